@@ -8,13 +8,13 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"expvar"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -29,10 +29,19 @@ import (
 	"github.com/companyzero/bisonrelay/client"
 	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
+	"github.com/companyzero/bisonrelay/client/plugins"
 	"github.com/companyzero/bisonrelay/client/resources"
+	"github.com/companyzero/bisonrelay/client/resources/booking"
+	"github.com/companyzero/bisonrelay/client/resources/donations"
 	"github.com/companyzero/bisonrelay/client/resources/simplestore"
+	"github.com/companyzero/bisonrelay/client/resources/tickets"
 	"github.com/companyzero/bisonrelay/client/rpcserver"
+	"github.com/companyzero/bisonrelay/client/supervisor"
+	"github.com/companyzero/bisonrelay/client/tracing"
+	"github.com/companyzero/bisonrelay/client/updates"
 	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/internal/imgreenc"
 	"github.com/companyzero/bisonrelay/internal/mdembeds"
 	"github.com/companyzero/bisonrelay/internal/strescape"
 	"github.com/companyzero/bisonrelay/internal/tlsconn"
@@ -209,11 +218,21 @@ type appState struct {
 
 	inviteFundsAccount string
 
+	// imageReencode, if not nil, configures re-encoding of embedded
+	// images.
+	imageReencode *imgreenc.Config
+
 	extenalEditorForComments bool
 
 	payReqStatuses *xsync.MapOf[chainhash.Hash, lnrpc.Payment_PaymentStatus]
 
 	sstore       *simplestore.Store
+	donations    *donations.Provider
+	tickets      *tickets.Provider
+	booking      *booking.Provider
+	plugins      *plugins.Manager
+	supervisor   *supervisor.Supervisor
+	updates      *updates.Checker
 	ssPayType    simpleStorePayType
 	ssAcct       string
 	ssShipCharge float64
@@ -310,11 +329,24 @@ func (as *appState) run() error {
 		}()
 	}
 
+	// Fetch exchange rates.
+	as.wg.Add(1)
+	go func() {
+		err := as.supervisor.Run(as.ctx, "rates", func(ctx context.Context) error {
+			as.rates.Run(ctx)
+			return nil
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			as.log.Errorf("Error running rates fetcher: %v", err)
+		}
+		as.wg.Done()
+	}()
+
 	// Run the simple store if set.
 	if as.sstore != nil {
 		as.wg.Add(1)
 		go func() {
-			err := as.sstore.Run(as.ctx)
+			err := as.supervisor.Run(as.ctx, "simplestore", as.sstore.Run)
 			if err != nil && !errors.Is(err, context.Canceled) {
 				as.log.Errorf("Error running simple store: %v", err)
 			}
@@ -322,6 +354,66 @@ func (as *appState) run() error {
 		}()
 	}
 
+	// Run the donation page if set.
+	if as.donations != nil {
+		as.wg.Add(1)
+		go func() {
+			err := as.supervisor.Run(as.ctx, "donations", as.donations.Run)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				as.log.Errorf("Error running donation page: %v", err)
+			}
+			as.wg.Done()
+		}()
+	}
+
+	// Run the ticket sales if set.
+	if as.tickets != nil {
+		as.wg.Add(1)
+		go func() {
+			err := as.supervisor.Run(as.ctx, "tickets", as.tickets.Run)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				as.log.Errorf("Error running ticket sales: %v", err)
+			}
+			as.wg.Done()
+		}()
+	}
+
+	// Run the appointment booking if set.
+	if as.booking != nil {
+		as.wg.Add(1)
+		go func() {
+			err := as.supervisor.Run(as.ctx, "booking", as.booking.Run)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				as.log.Errorf("Error running appointment booking: %v", err)
+			}
+			as.wg.Done()
+		}()
+	}
+
+	// Run the plugins if set.
+	if as.plugins != nil {
+		as.wg.Add(1)
+		go func() {
+			err := as.plugins.Run(as.ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				as.log.Errorf("Error running plugins: %v", err)
+			}
+			as.wg.Done()
+		}()
+	}
+
+	// Run the update checker if enabled.
+	if as.updates != nil {
+		as.wg.Add(1)
+		go func() {
+			err := as.updates.Run(as.ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				as.log.Errorf("Error running update checker: %v", err)
+			}
+			as.wg.Done()
+		}()
+	}
+
 	as.wg.Wait()
 	if as.cmdHistoryFile != nil {
 		as.cmdHistoryFile.Close()
@@ -644,6 +736,39 @@ func (as *appState) lastLndLogLines(nbLines int) string {
 	return log
 }
 
+// newReleaseAvailable is called by the update checker when a new release is
+// found.
+func (as *appState) newReleaseAvailable(release *updates.Release, stagedPath string) {
+	as.manyDiagMsgsCb(func(pf printf) {
+		pf("")
+		pf("New %s release %s available (current version %s)", appName,
+			release.Version, version.Version)
+		if release.Notes != "" {
+			pf("Release notes: %s", release.Notes)
+		}
+		if stagedPath != "" {
+			pf("The new binary was downloaded and verified at %s. "+
+				"Replace the current binary with it and restart "+
+				"to upgrade.", stagedPath)
+		}
+	})
+}
+
+// subsystemIncident is called by the supervisor when a subsystem panics.
+func (as *appState) subsystemIncident(inc supervisor.Incident) {
+	as.manyDiagMsgsCb(func(pf printf) {
+		pf("")
+		pf(as.styles.err.Render(fmt.Sprintf("Subsystem %s crashed: %v",
+			inc.Subsystem, inc.Value)))
+		if inc.RestartIn > 0 {
+			pf("Restarting it in %s (restart #%d). See the log "+
+				"for details.", inc.RestartIn, inc.Restarts)
+		} else {
+			pf("See the log for details.")
+		}
+	})
+}
+
 // errorLogMsg is called by the log backend when an error msg is received.
 func (as *appState) errorLogMsg(msg string) {
 	as.diagMsg(as.styles.err.Render(msg))
@@ -1305,8 +1430,12 @@ func (as *appState) handleRcvdText(s string, nick string) string {
 }
 
 // writeInvite writes a new invite to the given filename. This blocks until the
-// invite is written.
-func (as *appState) writeInvite(filename string, gcID zkidentity.ShortID, funds *rpc.InviteFunds) {
+// invite is written. If referral is not empty, the invite is tracked as a
+// referral with that label. If importedContact is not empty, the invite is
+// tracked as the invite of the imported contact with that ID.
+func (as *appState) writeInvite(filename string, gcID zkidentity.ShortID,
+	funds *rpc.InviteFunds, referral, importedContact string) {
+
 	as.cwHelpMsg("Attempting to create and subscribe to new invite")
 	w := new(bytes.Buffer)
 	pii, inviteKey, err := as.c.CreatePrepaidInvite(w, funds)
@@ -1327,6 +1456,22 @@ func (as *appState) writeInvite(filename string, gcID zkidentity.ShortID, funds
 		}
 		as.cwHelpMsg("Will invite to GC %s after KX", gcID)
 	}
+	if referral != "" {
+		err = as.c.TrackInviteReferral(pii.InitialRendezvous, referral)
+		if err != nil {
+			as.cwHelpMsg("Unable to track referral: %v", err)
+			return
+		}
+		as.cwHelpMsg("Tracking invite as referral %q", referral)
+	}
+	if importedContact != "" {
+		err = as.c.TrackImportedContactInvite(importedContact, pii.InitialRendezvous)
+		if err != nil {
+			as.cwHelpMsg("Unable to track invite of imported contact: %v", err)
+			return
+		}
+		as.cwHelpMsg("Tracking invite of imported contact %s", importedContact)
+	}
 
 	encodedKey, err := inviteKey.Encode()
 	if err != nil {
@@ -1387,11 +1532,11 @@ func (as *appState) pm(cw *chatWindow, msg string) {
 
 // payTip sends a tip to the user of the given window. This blocks until the
 // tip has been paid.
-func (as *appState) payTip(cw *chatWindow, dcrAmount float64) {
+func (as *appState) payTip(cw *chatWindow, dcrAmount float64, opts client.TipOptions) {
 	const maxAttempts = 1
 	m := cw.newInternalMsg(fmt.Sprintf("Attempting to send %.8f DCR as tip", dcrAmount))
 	as.repaintIfActive(cw)
-	err := as.c.TipUser(cw.uid, dcrAmount, maxAttempts)
+	err := as.c.TipUserWithOptions(cw.uid, dcrAmount, maxAttempts, opts)
 	if err != nil {
 		as.cwHelpMsg("Unable to tip user %q: %v",
 			cw.alias, err)
@@ -1406,13 +1551,21 @@ func (as *appState) payPayReq(cw *chatWindow, invoice string, payReq *zpay32.Inv
 		return
 	}
 
+	// Do not pay invoices of pages with an order summary that failed
+	// verification, as the invoice may have been swapped.
+	if cw.page != nil && cw.page.OrderSummaryStatus == clientdb.ResourceSignatureInvalid {
+		as.diagMsg(as.styles.err.Render("Refusing to pay invoice of " +
+			"page with an invalid order summary"))
+		return
+	}
+
 	_, loaded := as.payReqStatuses.LoadOrStore(*payReq.PaymentHash, lnrpc.Payment_IN_FLIGHT)
 	if loaded {
 		// Already attempting to pay.
 		return
 	}
 
-	fees, err := as.lnPC.PayInvoice(as.ctx, invoice)
+	fees, err := as.c.PayInvoice(clientintf.PaymentCategoryStore, invoice)
 	if err != nil {
 		as.diagMsg(as.styles.err.Render(fmt.Sprintf("Unable to pay invoice: %v", err)))
 		as.payReqStatuses.Store(*payReq.PaymentHash, lnrpc.Payment_FAILED)
@@ -1421,6 +1574,19 @@ func (as *appState) payPayReq(cw *chatWindow, invoice string, payReq *zpay32.Inv
 
 	as.payReqStatuses.Store(*payReq.PaymentHash, lnrpc.Payment_SUCCEEDED)
 	as.diagMsg(fmt.Sprintf("Paid %s invoice (%d milliatoms as fees)", payReqStrAmount(payReq), fees))
+
+	// Send the proof of payment of the order to the store.
+	page := cw.page
+	if page == nil || page.OrderSummary == nil ||
+		page.OrderSummaryStatus != clientdb.ResourceSignatureValid ||
+		page.OrderSummary.Invoice != invoice {
+		return
+	}
+	_, err = as.c.SendOrderPaymentProof(page.UID, page.OrderSummary)
+	if err != nil {
+		as.diagMsg(as.styles.err.Render(fmt.Sprintf("Unable to send "+
+			"payment proof of order %s: %v", page.OrderSummary.OrderID, err)))
+	}
 }
 
 // block blocks a user.
@@ -2365,11 +2531,15 @@ func (as *appState) fetchPage(uid clientintf.UserID, pagePath string, session,
 
 	// Serialize form data.
 	var data json.RawMessage
+	var meta map[string]string
 	if form != nil {
 		data, err = form.toJson()
 		if err != nil {
 			return err
 		}
+		meta = map[string]string{
+			rpc.ResourceMetaContentType: rpc.ResourceContentTypeForm,
+		}
 	}
 
 	// If it's for a local page, fetch it directly.
@@ -2383,7 +2553,7 @@ func (as *appState) fetchPage(uid clientintf.UserID, pagePath string, session,
 		return err
 	}
 
-	tag, err := as.c.FetchResource(uid, path, nil, session, parent, data)
+	tag, err := as.c.FetchResource(uid, path, meta, session, parent, data)
 	if err != nil {
 		return err
 	}
@@ -2448,6 +2618,23 @@ func (as *appState) modifyGCAdmins(gcID zkidentity.ShortID, add, del clientintf.
 	return nil
 }
 
+func (as *appState) setGCReadOnly(gcID zkidentity.ShortID, readOnly bool, posters []clientintf.UserID) error {
+	cw := as.findOrNewGCWindow(gcID)
+	if err := as.c.SetGCReadOnly(gcID, readOnly, posters); err != nil {
+		return err
+	}
+	if !readOnly {
+		cw.newHelpMsg("GC made writable by all members")
+	} else if len(posters) == 0 {
+		cw.newHelpMsg("GC made read-only (only admins may post)")
+	} else {
+		cw.newHelpMsg("GC made read-only (only admins and %d posters may post)",
+			len(posters))
+	}
+	as.repaintIfActive(cw)
+	return nil
+}
+
 // handleCmd executes the given (already parsed) command line.
 func (as *appState) handleCmd(rawText string, args []string) {
 	if len(args) == 0 {
@@ -2540,6 +2727,80 @@ func (as *appState) handleCmd(rawText string, args []string) {
 	}
 }
 
+// monitorLNHealth warns when the connection to the LN wallet is lost and when
+// it is re-established.
+func (as *appState) monitorLNHealth(ctx context.Context, lnPC *client.DcrlnPaymentClient) {
+	const checkInterval = time.Minute
+	first := true
+	lnPC.MonitorHealth(ctx, checkInterval, func(h client.DcrlnHealth) {
+		switch {
+		case !h.Online:
+			as.diagMsg("Connection to LN wallet lost: %s", h.Error)
+		case !first:
+			as.diagMsg("Connection to LN wallet re-established "+
+				"(synced to chain: %v)", h.SyncedToChain)
+		}
+		first = false
+	})
+}
+
+// runWatchtowers registers the configured watchtowers with the LN wallet and
+// monitors their status, warning when a watchtower stops being usable.
+func (as *appState) runWatchtowers(ctx context.Context, lnPC *client.DcrlnPaymentClient,
+	watchtowers []string) {
+
+	for _, uri := range watchtowers {
+		if err := lnPC.AddWatchtower(ctx, uri); err != nil {
+			as.diagMsg("Unable to add watchtower %s: %v", uri, err)
+		}
+	}
+
+	// Only warn about changes in the status of the towers to avoid
+	// repeating the same messages.
+	const checkInterval = 10 * time.Minute
+	var lastErr error
+	inactive := make(map[string]bool)
+	lnPC.MonitorWatchtowers(ctx, checkInterval, func(towers []client.WatchtowerStatus, err error) {
+		if err != nil {
+			if lastErr == nil {
+				as.diagMsg("Unable to check watchtowers: %v", err)
+			}
+			lastErr = err
+			return
+		}
+		lastErr = nil
+
+		for _, t := range towers {
+			wasInactive := inactive[t.Pubkey]
+			switch {
+			case !t.Active && !wasInactive:
+				as.diagMsg("Watchtower %s is not active", t.Pubkey)
+			case t.Active && wasInactive:
+				as.diagMsg("Watchtower %s is active again", t.Pubkey)
+			}
+			inactive[t.Pubkey] = !t.Active
+		}
+	})
+}
+
+// runSCBBackups keeps copies of the LN static channel backup in the
+// configured backup dirs, warning when the copies could not be written.
+func (as *appState) runSCBBackups(ctx context.Context, lnPC *client.DcrlnPaymentClient,
+	dirs []string) {
+
+	var lastErr error
+	lnPC.MonitorChannelBackups(ctx, dirs, func(err error) {
+		switch {
+		case err != nil && lastErr == nil:
+			as.diagMsg("Unable to copy channel backup: %v", err)
+		case err == nil && lastErr != nil:
+			as.diagMsg("Channel backup copied to %s",
+				strings.Join(dirs, ", "))
+		}
+		lastErr = err
+	})
+}
+
 // newAppState initializes the main app state.
 func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 	isRestore bool, args *config) (*appState, error) {
@@ -2564,6 +2825,15 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 	rpc.SetLog(logBknd.logger("RRPC"))
 	internalLog = logBknd.logger("INTR")
 
+	// Initialize metrics.
+	var promExporter *metrics.PrometheusExporter
+	var appMetrics *metrics.Metrics
+	if args.MetricsListen != "" {
+		promExporter = metrics.NewPrometheusExporter(nil)
+		appMetrics = metrics.New(promExporter,
+			metrics.NewExpvarSink(appName))
+	}
+
 	// Initialize DB.
 	db, err := clientdb.New(clientdb.Config{
 		Root:          args.DBRoot,
@@ -2571,6 +2841,9 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		DownloadsRoot: args.DownloadsRoot,
 		Logger:        logBknd.logger("FDDB"),
 		ChunkSize:     rpc.MaxChunkSize,
+		Metrics:       appMetrics,
+
+		DownloadsNaming: args.DownloadsNaming,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize DB: %v", err)
@@ -2589,6 +2862,8 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 			MacaroonPath: args.LNMacaroonPath,
 			Address:      args.LNRPCHost,
 			Log:          logBknd.logger("LNPY"),
+
+			WarnMacaroonScope: args.WalletType == "external",
 		}
 		lnPC, err = client.NewDcrlndPaymentClient(ctx, pcCfg)
 		if err != nil {
@@ -2843,6 +3118,68 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		})
 	}))
 
+	ntfns.Register(client.OnCompatWarningNtfn(func(user *client.RemoteUser, warn client.CompatWarning) {
+		var msg string
+		if user == nil {
+			msg = fmt.Sprintf("Server compatibility warning: %s", warn)
+		} else {
+			msg = fmt.Sprintf("Compatibility warning for user %s: %s",
+				strescape.Nick(user.Nick()), warn)
+		}
+		as.diagMsg("%s", as.styles.err.Render(msg))
+	}))
+
+	ntfns.Register(client.OnClockSkewWarningNtfn(func(user *client.RemoteUser, warn client.ClockSkewWarning) {
+		var msg string
+		if user == nil {
+			msg = fmt.Sprintf("Clock skew warning: %s", warn)
+		} else {
+			msg = fmt.Sprintf("Clock skew warning for user %s: %s",
+				strescape.Nick(user.Nick()), warn)
+		}
+		as.diagMsg("%s", as.styles.err.Render(msg))
+	}))
+
+	ntfns.Register(client.OnRatchetHealthAlertNtfn(func(alert client.RatchetHealthAlert) {
+		msg := fmt.Sprintf("Ratchet health alert (%s): %s", alert.Type,
+			alert.Detail)
+		as.diagMsg("%s", as.styles.err.Render(msg))
+		as.diagMsg("Suggested action: %s", alert.Remediation)
+	}))
+
+	var compactionMtx sync.Mutex
+	var lastCompactionStage clientdb.CompactionStage
+	ntfns.Register(client.OnCompactionProgressNtfn(func(p client.CompactionProgress) {
+		// Only report the start of each stage.
+		compactionMtx.Lock()
+		newStage := p.Stage != lastCompactionStage
+		lastCompactionStage = p.Stage
+		compactionMtx.Unlock()
+		if !newStage {
+			return
+		}
+		as.diagMsg("Compacting storage (%s): %d/%d targets, %s reclaimed",
+			p.Stage, p.Done, p.Total, hbytes(p.Reclaimed))
+	}))
+
+	ntfns.Register(client.OnContentSummarizedNtfn(func(ev client.ContentSummaryEvent) {
+		switch ev.Kind {
+		case client.SummaryKindGCBacklog:
+			cw := as.findOrNewGCWindow(ev.GC)
+			cw.manyHelpMsgs(func(pf printf) {
+				pf("Summary of the last %d messages:", ev.Messages)
+				for _, line := range strings.Split(ev.Summary.Summary, "\n") {
+					pf("%s", strescape.Content(line))
+				}
+			})
+			as.repaintIfActive(cw)
+		case client.SummaryKindPost:
+			as.diagMsg("Summarized post %s (%d bytes). Type /summary "+
+				"post %s %s to show it.", ev.Post, ev.Summary.SourceLen,
+				ev.UID, ev.Post)
+		}
+	}))
+
 	ntfns.Register(client.OnInvitedToGCNtfn(func(user *client.RemoteUser, iid uint64, invite rpc.RMGroupInvite) {
 		gcName := strescape.Nick(invite.Name)
 		as.gcInvitesMtx.Lock()
@@ -2958,11 +3295,51 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		as.repaintIfActive(cw)
 	}))
 
+	ntfns.Register(client.OnGCReadOnlyChangedNtfn(func(ru *client.RemoteUser, gc rpc.RMGroupList) {
+		srcNick := strescape.Nick(ru.Nick())
+
+		cw := as.findOrNewGCWindow(gc.ID)
+		cw.manyHelpMsgs(func(pf printf) {
+			if !gc.ReadOnly {
+				pf("GC made writable by all members by %s", srcNick)
+				return
+			}
+			pf("GC made read-only by %s", srcNick)
+			if !gc.CanPost(as.c.PublicID()) {
+				pf("Local client cannot send messages to this GC")
+			}
+			for _, uid := range gc.Posters {
+				nick, _ := as.c.UserNick(uid)
+				pf("%q (%s) may post", strescape.Nick(nick), uid)
+			}
+		})
+		as.repaintIfActive(cw)
+	}))
+
 	ntfns.Register(client.OnKXSearchCompleted(func(ru *client.RemoteUser) {
 		as.diagMsg("Completed KX search of %s", ru)
 		as.sendMsg(kxSearchCompleted{uid: ru.ID()})
 	}))
 
+	ntfns.Register(client.OnPaymentFeeLimitExceededNtfn(func(category clientintf.PaymentCategory,
+		amountMAtoms, estimatedFee, maxFee int64) {
+		as.diagMsg("Skipped %s payment of %.8f DCR: estimated fee %.8f DCR "+
+			"exceeds max fee %.8f DCR", category, float64(amountMAtoms)/1e11,
+			float64(estimatedFee)/1e11, float64(maxFee)/1e11)
+	}))
+
+	ntfns.Register(client.OnPaymentApprovalRequestedNtfn(func(approval client.PaymentApproval) {
+		dest := ""
+		if approval.UID != nil {
+			nick, _ := as.c.UserNick(*approval.UID)
+			dest = " to " + strescape.Nick(nick)
+		}
+		as.diagMsg(as.styles.err.Render(fmt.Sprintf("Payment %d (%s "+
+			"payment of %.8f DCR%s) requires approval. Type /payapproval "+
+			"approve %[1]d or /payapproval deny %[1]d", approval.ID,
+			approval.Category, float64(approval.MAtoms)/1e11, dest)))
+	}))
+
 	ntfns.Register(client.OnTipAttemptProgressNtfn(func(ru *client.RemoteUser, amtMAtoms int64, completed bool, attempt int, attemptErr error, willRetry bool) {
 		// Ignore non-final attempts (user can check logs).
 		if willRetry {
@@ -3052,6 +3429,19 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 			return
 		}
 
+		if fr.OrderSummaryStatus == clientdb.ResourceSignatureInvalid {
+			as.diagMsg("WARNING: resource %s/%s has an invalid "+
+				"order summary. Do NOT pay the invoices or "+
+				"addresses in it, as they may have been swapped.",
+				nick, strescape.ResourcesPath(fr.Request.Path))
+		}
+		if fr.SignatureStatus == clientdb.ResourceSignatureInvalid {
+			as.diagMsg("WARNING: resource %s/%s has an invalid "+
+				"signature. Its contents (including prices and "+
+				"payment details) may have been altered.", nick,
+				strescape.ResourcesPath(fr.Request.Path))
+		}
+
 		cw := as.findOrNewPagesChatWindow(fr.SessionID)
 		cw.replacePage(fr)
 		sendMsg(msgPageFetched{
@@ -3062,6 +3452,14 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		})
 	}))
 
+	ntfns.Register(client.OnResourceFetchProgressNtfn(func(user *client.RemoteUser,
+		tag rpc.ResourceTag, chunks, totalChunks int, size int64) {
+
+		as.diagMsg("Fetching resource %s from %s: received %d/%d chunks (%s)",
+			tag, strescape.Nick(user.Nick()), chunks, totalChunks,
+			hbytes(size))
+	}))
+
 	ntfns.Register(client.OnHandshakeStageNtfn(func(ru *client.RemoteUser, msgtype string) {
 		nick := strescape.Nick(ru.Nick())
 		switch msgtype {
@@ -3157,9 +3555,33 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		as.repaintIfActive(cw)
 	}))
 
+	// Isolate panics of non-critical subsystems, so that they are
+	// restarted instead of taking down the client.
+	sup := supervisor.New(supervisor.Config{
+		Log: logBknd.logger("SUPV"),
+		OnIncident: func(inc supervisor.Incident) {
+			as.subsystemIncident(inc)
+		},
+	})
+
 	// Initialize resources router.
 	var sstore *simplestore.Store
+	var donationsProvider *donations.Provider
+	var ticketsProvider *tickets.Provider
+	var bookingProvider *booking.Provider
 	resRouter := resources.NewRouter()
+	resRouter.Use(resources.RecoverPanics(sup))
+	resRateLimits := args.ResourcesRateLimits
+	resAbuseLog := logBknd.logger("RGRD")
+	resRateLimits.OnAbuse = func(ev client.ResourceAbuseEvent) {
+		// Log the first rejection of a streak and then periodically, to
+		// avoid flooding the log.
+		if ev.Count == 1 || ev.Count%100 == 0 {
+			resAbuseLog.Warnf("Rejected resource request for %s from %s "+
+				"(%s, %d in a row)", strescape.ResourcesPath(ev.Path),
+				ev.UID, ev.Reason, ev.Count)
+		}
+	}
 
 	// Initialize client config.
 	cfg := client.Config{
@@ -3173,6 +3595,16 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		Notifications:     ntfns,
 		ResourcesProvider: resRouter,
 		NoLoadChatHistory: args.NoLoadChatHistory,
+		FeePolicies:       args.FeePolicies,
+
+		PaymentApprovalThreshold: args.ApprovalThreshold,
+
+		ResourceRateLimits:  resRateLimits,
+		ResourceConcurrency: args.ResourcesConcurrency,
+		TrustTiers:          args.TrustTiers,
+		ImageReencode:       args.ImageReencode,
+
+		TipUserKeysendFallback: args.TipKeysend,
 
 		AutoHandshakeInterval:       args.AutoHandshakeInterval,
 		AutoRemoveIdleUsersInterval: args.AutoRemoveIdleUsersInterval,
@@ -3485,19 +3917,33 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 	}
 
 	// Parse bell command.
-	var bellCmd []string
-	if args.BellCmd != "" {
-		r := regexp.MustCompile(`[^\s"]+|"([^"]*)"`)
-		bellCmd = r.FindAllString(args.BellCmd, -1)
-		// Remove "".
-		for i, s := range bellCmd {
-			if len(s) < 2 {
-				continue
-			}
-			if s[0] == '"' && s[len(s)-1] == '"' {
-				bellCmd[i] = s[1 : len(s)-1]
-			}
-		}
+	bellCmd := splitCmdLine(args.BellCmd)
+
+	if args.SummarizeCmd != "" {
+		cfg.Summarizer = &cmdSummarizer{cmd: splitCmdLine(args.SummarizeCmd)}
+		cfg.SummarizePostsMinLen = args.SummarizePostsMinLen
+		cfg.SummarizeGCBacklogMsgs = args.SummarizeGCMsgs
+	}
+
+	if args.TracingURL != "" {
+		cfg.Tracer = tracing.New(tracing.Config{
+			Exporter: tracing.NewOTLPExporter(args.TracingURL, appName),
+			Log:      logBknd.logger("TRCE"),
+		})
+	}
+
+	if args.MetricsListen != "" {
+		cfg.Metrics = appMetrics
+		resRouter.Use(resources.Instrument(cfg.Metrics))
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promExporter)
+		mux.Handle("/debug/vars", expvar.Handler())
+		metricsLog := logBknd.logger("MTRC")
+		go func() {
+			err := http.ListenAndServe(args.MetricsListen, mux)
+			metricsLog.Errorf("Unable to serve metrics: %v", err)
+		}()
 	}
 
 	// Initialize client.
@@ -3528,6 +3974,7 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		rpcServer = rpcserver.New(rpcserver.Config{
 			JSONRPCListeners: jsonListeners,
 			Log:              rpcsLog,
+			RESTGateway:      args.RPCRESTGateway,
 		})
 		rpcServer.InitVersionService(appName, version.Version)
 		chatRPCServerCfg := rpcserver.ChatServerCfg{
@@ -3601,6 +4048,110 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		}
 	}
 
+	// Bind the donation page before the upstream provider, so that it
+	// takes precedence over it.
+	if args.Donations != nil {
+		dcfg := *args.Donations
+		dcfg.Log = logBknd.logger("DONA")
+		dcfg.Client = c
+		dcfg.LNPayClient = lnPC
+		dcfg.DonationReceived = func(d *donations.Donation) {
+			handleDonationReceived(as, d)
+		}
+		donationsProvider, err = donations.New(dcfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize donation page: %v", err)
+		}
+		resRouter.BindPrefixPath([]string{"donate"}, donationsProvider)
+	}
+	if args.TicketsRoot != "" {
+		ticketsProvider, err = tickets.New(tickets.Config{
+			Root:        args.TicketsRoot,
+			Log:         logBknd.logger("TCKT"),
+			Client:      c,
+			LNPayClient: lnPC,
+			TicketsIssued: func(p *tickets.Purchase) {
+				handleTicketsIssued(as, p)
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize ticket sales: %v", err)
+		}
+		resRouter.BindPrefixPath([]string{"tickets"}, ticketsProvider)
+	}
+	if args.BookingRoot != "" {
+		bookingProvider, err = booking.New(booking.Config{
+			Root:         args.BookingRoot,
+			Log:          logBknd.logger("BOOK"),
+			Client:       c,
+			LNPayClient:  lnPC,
+			ReminderLead: args.BookingReminderLead,
+			BookingConfirmed: func(b *booking.Booking) {
+				handleBookingConfirmed(as, b)
+			},
+			Reminder: func(b *booking.Booking) {
+				handleBookingReminder(as, b)
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize appointment booking: %v", err)
+		}
+		resRouter.BindPrefixPath([]string{"booking"}, bookingProvider)
+	}
+
+	// Setup the plugins if configured. Their resource providers are bound
+	// before the upstream provider, which handles every other path.
+	var pluginsMgr *plugins.Manager
+	if args.PluginsConfig != "" {
+		pluginCfgs, err := plugins.LoadConfigFile(args.PluginsConfig)
+		if err != nil {
+			return nil, err
+		}
+		pluginsMgr, err = plugins.NewManager(plugins.ManagerConfig{
+			Host:    c,
+			Plugins: pluginCfgs,
+			Logger:  logBknd.logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize plugins: %v", err)
+		}
+		pluginsMgr.RegisterNotifications(ntfns)
+		for prefix, p := range pluginsMgr.ResourcesProviders() {
+			path := strings.FieldsFunc(prefix, func(r rune) bool { return r == '/' })
+			resRouter.BindPrefixPath(path, p)
+		}
+	}
+
+	// Setup the checker of new releases if enabled.
+	var updatesChecker *updates.Checker
+	if args.Updates != nil {
+		var fetcher updates.Fetcher
+		if args.Updates.ManifestUser != nil {
+			fetcher = &updates.BRFetcher{
+				Client: c,
+				UID:    *args.Updates.ManifestUser,
+				Path:   args.Updates.ManifestPath,
+			}
+		} else {
+			fetcher = &updates.HTTPSFetcher{URL: args.Updates.ManifestURL}
+		}
+		updatesChecker, err = updates.New(updates.Config{
+			Fetcher:        fetcher,
+			CurrentVersion: version.Version,
+			TrustedKeys:    args.Updates.Keys,
+			MinSignatures:  args.Updates.MinSigs,
+			Interval:       args.Updates.Interval,
+			StageDir:       args.Updates.StageDir,
+			NewRelease: func(release *updates.Release, stagedPath string) {
+				as.newReleaseAvailable(release, stagedPath)
+			},
+			Log: logBknd.logger("UPDT"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize update checker: %v", err)
+		}
+	}
+
 	// Bind the selected upstream resource provider.
 	switch {
 	case strings.HasPrefix(args.ResourcesUpstream, "http://"),
@@ -3616,6 +4167,14 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 				" template: %v", err)
 		}
 
+		var ssBackend simplestore.StoreBackend
+		if args.SimpleStoreDBFile != "" {
+			ssBackend, err = simplestore.NewBoltBackend(args.SimpleStoreDBFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		scfg := simplestore.Config{
 			Root:        path,
 			Log:         logBknd.logger("SSTR"),
@@ -3623,13 +4182,29 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 			Client:      c,
 			PayType:     simplestore.PayType(args.SimpleStorePayType),
 			Account:     args.SimpleStoreAccount,
+			XPub:        args.SimpleStoreXPub,
 			ShipCharge:  args.SimpleStoreShipCharge,
 			LNPayClient: lnPC,
 
-			ExchangeRateProvider: func() float64 {
-				dcrPrice, _ := as.rates.Get()
-				return dcrPrice
-			},
+			AdminRouting:         args.SimpleStoreAdmins,
+			AdminRoles:           args.SimpleStoreAdminRoles,
+			Ledger:               args.SimpleStoreLedger,
+			OnChainConfirmations: args.SimpleStoreOnChainConfs,
+			CoHost:               args.SimpleStoreCoHost,
+
+			Currency: args.SimpleStoreCurrency,
+			Backend:  ssBackend,
+			Webhook:  args.SimpleStoreWebhook,
+
+			InventorySync: args.SimpleStoreInventorySync,
+			Reminders:     args.SimpleStoreReminders,
+			Tax:           args.SimpleStoreTax,
+
+			RateFailurePolicy: args.SimpleStoreRateFailure,
+			RateMaxStaleness:  args.SimpleStoreRateStaleness,
+			RateProvider: simplestore.RateProviderFunc(func(currency string) (float64, error) {
+				return as.rates.DCRPrice(currency)
+			}),
 
 			OrderPlaced: func(order *simplestore.Order, msg string) {
 				handleCompletedSimpleStoreOrder(as, order, msg)
@@ -3638,16 +4213,46 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 			StatusChanged: func(order *simplestore.Order, msg string) {
 				handleSimpleStoreOrderStatusChanged(as, order, msg)
 			},
+
+			OrderPaid: func(order *simplestore.Order, msg string) {
+				handleSimpleStoreOrderPaid(as, order, msg)
+			},
+
+			QuoteRequested: func(quote *simplestore.QuoteRequest, msg string) {
+				handleSimpleStoreQuoteRequested(as, quote, msg)
+			},
+
+			LowStock: func(products []*simplestore.Product, msg string) {
+				as.diagMsg("Simple store: %s", msg)
+			},
 		}
 		sstore, err = simplestore.New(scfg)
 		if err != nil {
 			return nil, fmt.Errorf("unable to initialize simple store: %v", err)
 		}
 		resRouter.BindPrefixPath([]string{}, sstore)
+		c.RegisterQueryRunner(client.SavedQueryOrders, sstore)
 	case strings.HasPrefix(args.ResourcesUpstream, "pages:"):
 		path := args.ResourcesUpstream[len("pages:"):]
 		p := resources.NewFilesystemResource(path, logBknd.logger("PAGE"))
 		resRouter.BindPrefixPath([]string{}, p)
+	case strings.HasPrefix(args.ResourcesUpstream, "site:"):
+		path := args.ResourcesUpstream[len("site:"):]
+		p := resources.ServeDirectory(path)
+		p.SetLogger(logBknd.logger("SITE"))
+		resRouter.BindPrefixPath([]string{}, p)
+	}
+
+	// Allow managing the simplestore through clientrpc.
+	if rpcServer != nil && sstore != nil {
+		err := rpcServer.InitStoreService(rpcserver.StoreServerCfg{
+			Log:               logBknd.logger("RPCS"),
+			Store:             sstore,
+			RootReplayMsgLogs: filepath.Join(args.DBRoot, "replaymsglog"),
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	httpClient := http.Client{
@@ -3664,8 +4269,8 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 	r := rates.New(rates.Config{
 		HTTPClient: &httpClient,
 		Log:        logBknd.logger("RATE"),
+		Currencies: []string{args.SimpleStoreCurrency},
 	})
-	go r.Run(ctx)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	as = &appState{
@@ -3715,6 +4320,7 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		mimeMap:            args.MimeMap,
 		bellCmd:            bellCmd,
 		inviteFundsAccount: args.InviteFundsAccount,
+		imageReencode:      args.ImageReencode,
 
 		collator: collate.New(language.Und),
 
@@ -3729,6 +4335,12 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 		payReqStatuses: xsync.NewTypedMapOf[chainhash.Hash, lnrpc.Payment_PaymentStatus](chainHashMapHashHasher),
 
 		sstore:       sstore,
+		donations:    donationsProvider,
+		tickets:      ticketsProvider,
+		booking:      bookingProvider,
+		plugins:      pluginsMgr,
+		supervisor:   sup,
+		updates:      updatesChecker,
 		ssPayType:    args.SimpleStorePayType,
 		ssAcct:       args.SimpleStoreAccount,
 		ssShipCharge: args.SimpleStoreShipCharge,
@@ -3736,5 +4348,21 @@ func newAppState(sendMsg func(tea.Msg), lndLogLines *sloglinesbuffer.Buffer,
 
 	as.diagMsg("%s version %s", appName, version.String())
 
+	if lnPC != nil && len(args.Watchtowers) > 0 {
+		go as.runWatchtowers(ctx, lnPC, args.Watchtowers)
+	}
+	if lnPC != nil && len(args.SCBBackupDirs) > 0 {
+		go as.runSCBBackups(ctx, lnPC, args.SCBBackupDirs)
+	}
+	if lnPC != nil && args.WalletType == "external" {
+		for _, w := range lnPC.MacaroonWarnings() {
+			as.diagMsg("%s LN macaroon %s",
+				as.styles.err.Render("WARNING:"), w)
+		}
+	}
+	if lnPC != nil {
+		go as.monitorLNHealth(ctx, lnPC)
+	}
+
 	return as, nil
 }
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/companyzero/bisonrelay/client/resources/booking"
+)
+
+func handleBookingConfirmed(as *appState, b *booking.Booking) {
+	ru, err := as.c.UserByID(b.User)
+	if err != nil {
+		as.diagMsg("Booking #%d by unknown user %s confirmed for %s",
+			b.ID, b.User, b.Start.Format(ISO8601DateTime))
+		return
+	}
+
+	cw := as.findOrNewChatWindow(ru.ID(), ru.Nick())
+	cw.newInternalMsg("Booking #%d confirmed for %s (%s)", b.ID,
+		b.Start.Format(ISO8601DateTime), b.Amount)
+	as.repaintIfActive(cw)
+}
+
+func handleBookingReminder(as *appState, b *booking.Booking) {
+	nick, _ := as.c.UserNick(b.User)
+	if nick == "" {
+		nick = b.User.String()
+	}
+	as.diagMsg("Reminder: booking #%d with %s starts at %s", b.ID, nick,
+		b.Start.Format(ISO8601DateTime))
+}
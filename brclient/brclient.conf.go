@@ -40,6 +40,21 @@ root = {{ .Root }}
 # Show a desktop notification.
 # bellcmd = notify-send -i mail-unread "[$src]> $msg"
 
+# Summarize Command: summarizes long posts and GC backlogs (for example, with a
+# local LLM). The content is written to the stdin of the command and the
+# summary is read from its stdout. In arguments, '$kind' is replaced with the
+# kind of content ("post" or "gcbacklog"), '$title' with the post title or GC
+# name and '$maxlen' with the max length of the summary.
+# summarizecmd = llm-summarize --max-chars $maxlen
+#
+# Min length of received posts that are automatically summarized with
+# summarizecmd. 0 only summarizes posts with the /summary command.
+# summarizepostsminlen = 0
+#
+# Nb of messages received in a GC after which its backlog is automatically
+# summarized with summarizecmd. 0 only summarizes GCs with the /summary command.
+# summarizegcmsgs = 0
+
 # Set externaleditorforcomments to true to launch $EDITOR to write new comments
 # in the posts window.
 # externaleditorforcomments = false
@@ -47,6 +62,14 @@ root = {{ .Root }}
 # Set whether to read chat logs to build chat history
 # noloadchathistory = false
 
+# The naming scheme of the dirs (inside the downloads dir) where the files
+# received in each conversation are stored. One of "nick" (the nick of the
+# user), "uid" (the id of the user, which does not change when the user is
+# renamed), "nickuid" (nick and short id of the user) or "flat" (all files
+# directly in the downloads dir). Files identical to ones already received in
+# the same conversation are not saved again.
+# downloadsnaming = nick
+
 # Whether to enable or disable bbolt's syncfreelist option. Setting to false
 # improves running performance while worsening startup performance.
 # syncfreelist = true
@@ -88,6 +111,18 @@ savehistory = false
 # Whether to log pings.
 # pings = false
 
+# URL of the traces endpoint of an OpenTelemetry collector, using the OTLP/HTTP
+# protocol. When set, spans for the stages of sending each message (compose,
+# encrypt, pay, push and ack) are exported to the collector, to diagnose slow
+# message delivery. The default endpoint of a local collector is
+# http://127.0.0.1:4318/v1/traces.
+# tracing =
+
+# metrics is the ip:port of where to serve metrics of the client (messages sent
+# and received, resource fetch latencies, store orders, etc). Metrics are
+# served in the Prometheus format at /metrics and as expvars at /debug/vars.
+# metrics = 127.0.0.1:9464
+
 # Valid ui colors: na, black, red, green, yellow, blue, magenta, cyan and white
 # Valid attributes are: none, underline and bold
 # format is: attribute:foreground:background
@@ -140,6 +175,13 @@ lnmacaroonpath = {{ .LNMacaroonPath }}
 # lnmacaroonpath = ~/.dcrlnd/data/chain/decred/mainnet/admin.macaroon
 {{ end }}
 
+# Name of a dcrlnd connection profile to use instead of the lnrpchost,
+# lntlscert and lnmacaroonpath options. Profiles are read from lnprofilesfile
+# (by default, lnprofiles.json in the root dir), which is a JSON list of
+# objects with "name", "address", "tls_cert_path" and "macaroon_path" fields.
+# lnprofile = shared-node
+# lnprofilesfile = ~/.brclient/lnprofiles.json
+
 # Log Level of the internal dcrlnd
 # lndebuglevel = info
 
@@ -152,6 +194,24 @@ minimumwalletbalance = 1.0
 minimumrecvbalance = 0.01
 minimumsendbalance = 0.01
 
+# Max fees to pay for outgoing payments of each category (tips, file downloads
+# and invoices of remote stores). The max fee may be specified as an absolute
+# value (in DCR) and as a percentage of the amount being paid. When both are
+# specified, the lowest one is used. Payments where the estimated route fee
+# exceeds the limit are skipped. Zero means no limit.
+# tipmaxfee = 0.0001
+# tipmaxfeepct = 1
+# downloadmaxfee = 0.0001
+# downloadmaxfeepct = 5
+# storemaxfee = 0.001
+# storemaxfeepct = 1
+
+# Audit mode of outgoing payments, for shared or bot-operated clients. When
+# set, every outgoing payment (other than server fees) is recorded in the
+# payment audit log and payments of at least this amount (in DCR) are held
+# until approved with /payapproval approve (or denied) or through clientrpc.
+# approvalthreshold = 0.01
+
 # LN RPC listen addresses. Only used with internal dcrlnd instance. Comma
 # separated. If specified, the first address MUST be a locally accessible one
 # (such as 127.0.0.1:10009).
@@ -161,6 +221,29 @@ minimumsendbalance = 0.01
 # users on-chain inside invites.
 # invitefundsaccount = non-default-account
 
+# Comma separated list of watchtowers (in the format <pubkey>@<host>:<port>)
+# used to back up the channel states of the internal dcrlnd instance. This
+# protects the channels when the client is offline for long periods of time.
+# Only used with internal dcrlnd instance.
+# watchtowers = <pubkey>@<host>:<port>
+
+# Send tips via keysend (spontaneous payments that do not require an invoice)
+# when a request for an invoice is not answered by the remote user (for example,
+# because they are offline). A memo signed by the local identity is attached
+# to the payment so that the receiver knows who sent the tip. This also enables
+# receiving tips via keysend in the internal dcrlnd instance. External dcrlnd
+# instances must be started with --accept-keysend to receive keysend tips.
+# tipkeysend = 0
+
+# Comma separated list of dirs where copies of the static channel backup (SCB)
+# are kept. A new copy is written whenever channels are opened or closed. Use
+# dirs located in a different device (such as a removable drive or a network
+# mount) so that channel funds can be recovered if this device is lost.
+{{ if .SCBBackupDirs -}}
+scbbackupdirs = {{ range $i, $d := .SCBBackupDirs }}{{ if $i }},{{ end }}{{ $d }}{{ end }}
+{{ else -}}
+# scbbackupdirs = /mnt/backup/brclient
+{{ end }}
 [clientrpc]
 # Enable the JSON-RPC clientrpc protocol on the comma-separated list of addresses.
 # jsonrpclisten = 127.0.0.1:7676
@@ -178,19 +261,114 @@ minimumsendbalance = 0.01
 # for generating the client CA, and cert files.
 # rpcissueclientcert = true
 
+# If set to true, the clientrpc services may also be called as REST endpoints
+# (/v1/<Service>/<Method>) in the jsonrpclisten addresses, with requests and
+# replies encoded as JSON and streams sent as server-sent events.
+# restgateway = false
+
 [resources]
 # Use an upstream processor for handling resource requests. Options:
 # "pages:<path>" offers static pages stored in the local <path>.
+# "site:<path>" offers the site in the local <path>, with markdown and template
+#   pages with front matter and generated indexes (see doc/site.md).
 # "simplestore:<path>" uses the internal 'simplestore' subsystem; if <path> does
 #   not exist, then it will be created and fill with a sample, minimal store.
 # "clientrpc": sends request events and waits for responses via clientrpc.
 # "http://...": sends request events and waits for the responses to an HTTP(S)
 #   server.
 # upstream = pages:/path/to/static/pages
+# upstream = site:/path/to/site
 # upstream = smplestore:/path/to/simple/store
 # upstream = clientrpc
 # upstream = https://example.com
 
+# ratelimit is the max number of resource requests per second accepted from
+# each remote user. Requests above the limit are replied with a "too many
+# requests" error. Zero means requests are not limited.
+# ratelimit = 0
+
+# ratelimitburst is the max number of resource requests accepted at once from
+# each remote user, when ratelimit is set.
+# ratelimitburst = 10
+
+# ratelimitpaths is a comma delimited list of rate limits that override the
+# default one for requests with paths that start with a given prefix. Each
+# entry is in the format "<path prefix>=<rate>[:<burst>]". A rate of zero
+# means requests with the prefix are not limited.
+# ratelimitpaths = static=0,admin=0.5:5
+
+# workers is the max number of resource requests fulfilled at once. Requests
+# are fulfilled separately from other received messages, so slow resources do
+# not delay chats.
+# workers = 8
+
+# maxqueued is the max number of resource requests waiting to be fulfilled.
+# Requests above the limit are replied with a "too many requests" error.
+# maxqueued = 100
+
+# concurrencypaths is a comma delimited list of limits of the number of
+# requests fulfilled at once for requests with paths that start with a given
+# prefix, in the format "<path prefix>=<limit>". Requests waiting on the limit
+# of a prefix do not hold up requests for other paths.
+# concurrencypaths = store=2,donate=1
+
+# allowusers is a comma delimited list of ids of users whose resource requests
+# are not rate limited (for example, the admins of a store).
+# allowusers =
+
+# denyusers is a comma delimited list of ids of users not allowed to fetch
+# resources. Rejected requests are logged.
+# denyusers =
+
+[trusttiers]
+# Trust tiers gate the defaults of features that could be abused by remote
+# users. Each contact is in one of the tiers "new", "known" or "trusted". New
+# contacts are automatically promoted to the "known" tier after knownafter has
+# elapsed since the first KX with them. The tier of a contact may also be set
+# explicitly with the /trust command.
+#
+# Set enable to true to enable trust tiers. When disabled, all contacts are
+# treated the same.
+# enable = false
+
+# knownafter is how long after the first KX contacts are promoted from the
+# "new" to the "known" tier. Set to zero to disable automatic promotion.
+# knownafter = 30d
+
+# The policy of each tier is a comma delimited list of <key>=<value> entries
+# that override the default policy of the tier. The keys are:
+#
+# autodownload: max size (in bytes) of free files downloaded without
+#   confirmation.
+# sentfiles: max size (in bytes) of files the contact may send without them
+#   being requested. Zero rejects all sent files.
+# ratelimit: resource requests rate limit in the format <rate>[:<burst>],
+#   overriding the default [resources] rate limit.
+# mediate: whether to automatically accept requests to mediate KX with
+#   other contacts.
+# resources: whether the contact may fetch resources (pages, store, etc).
+#
+# new = autodownload=0,sentfiles=0,ratelimit=0.2:5,mediate=false,resources=true
+# known = autodownload=1048576,sentfiles=10485760,mediate=true,resources=true
+# trusted = autodownload=10485760,sentfiles=1073741824,mediate=true,resources=true
+
+[images]
+# Images sent to contacts (embedded in messages and posts, or sent with /send)
+# may be downscaled and re-encoded before sending, to reduce the fees paid to
+# relay them. Images larger than maxwidth x maxheight pixels are downscaled to
+# fit, keeping their aspect ratio, and images larger than maxsize bytes are
+# re-encoded. Zero disables each limit.
+# maxwidth = 0
+# maxheight = 0
+# maxsize = 0
+
+# Quality (1-100) of re-encoded JPEG images.
+# jpegquality = 85
+
+# Set stripmetadata to true to remove metadata (which may include the location
+# where a photo was taken and the device used) from sent images.
+# stripmetadata = false
+
 [simplestore]
 # paytype defines how to charge for purchases done in the simplestore.  The
 # options are "ln" (use lightning network), "onchain" (generates an on-chain address),
@@ -203,8 +381,239 @@ minimumsendbalance = 0.01
 # If empty, the default account is used.
 # account =
 
-# simplestoreshipcharge is a surcharge (in USD) added to simplestore orders to
-# cover shipping and handling.
+# xpub is the extended public key of an account of an external wallet where the
+# on-chain payments of the store are received, to keep the funds of the store
+# apart from the funds of the local wallet. It is imported in the LN wallet as
+# a watch-only account (named after account, or "simplestore"), which generates
+# the payment addresses and detects the payments. The received funds may only
+# be spent from the external wallet. LN payments (and tips) are received in the
+# channels of the LN wallet and are not affected.
+# xpub =
+
+# simplestoreshipcharge is a surcharge (in the currency of the store) added to
+# simplestore orders to cover shipping and handling. It is only charged when
+# no shipping methods are defined in the shipping.toml file of the store root.
 # shipcharge = 0.0
+
+# currency is the fiat currency of the prices of the store. Supported
+# currencies are USD, EUR, GBP and BRL. Orders are quoted in DCR using the
+# latest exchange rate of the currency, and are not placed when the rate is
+# stale.
+# currency = USD
+
+# ratefailurepolicy is how orders are placed when the exchange rate of the
+# currency is not available. By default, orders are placed without payment
+# details and the buyer must be contacted with them. With "cached", orders are
+# quoted with the last exchange rate, as long as it is not older than
+# ratemaxstaleness (by default, 1h). With "retry", orders are placed pending a
+# quote and the buyer is sent the payment details once the rate is available.
+# With "reject", orders are rejected and the buyer is asked to try again later.
+# ratefailurepolicy =
+# ratemaxstaleness = 1h
+
+# onchainconfs is the number of confirmations an on-chain payment needs before
+# the order is considered paid.
+# onchainconfs = 1
+
+# admins is a comma delimited list of ids of remote users that may access the
+# admin section of the store. Placed orders are assigned to the admins in turn
+# (or to the admin on duty, according to adminshifts) and the assigned admin is
+# notified via PM.
+# admins =
+
+# adminroles is a comma delimited list of roles of admins, in the format
+# <id>:<role>. Admins with a role may only access the pages of the admin section
+# allowed by it. The roles are: "owner" (full access), "catalog" (products and
+# stock levels), "orders" (orders, quotes, subscriptions and customers) and
+# "accountant" (read-only access to orders, sales, customers and stock). Users
+# listed here do not need to be listed in admins, unless they should also be
+# assigned orders. Admins listed in admins without a role may only view orders
+# and acknowledge the orders assigned to them.
+# adminroles =
+
+# adminshifts is a comma delimited list of the weekly shifts of the admins, in
+# the format <id>:<weekday>:<HH:MM>-<HH:MM> (e.g. <id>:mon:09:00-17:00).
+# adminshifts =
+
+# adminacktimeout is how long an admin has to acknowledge an order assigned to
+# them before the order is reassigned to the next admin. If empty, orders are
+# never reassigned.
+# adminacktimeout = 30m
+
+# ledgerfile is a file where paid orders and refunds are appended as
+# double-entry accounting entries. ledgerformat is either "ledger" (for
+# ledger-cli) or "beancount". The account names of the entries may be
+# customized.
+# ledgerfile = ~/accounting/store.ledger
+# ledgerformat = ledger
+# ledgerlnaccount = Assets:Store:LN
+# ledgeronchainaccount = Assets:Store:OnChain
+# ledgersalesaccount = Income:Store:Sales
+# ledgershippingaccount = Income:Store:Shipping
+# ledgertaxaccount = Liabilities:Store:Taxes
+# ledgerrefundsaccount = Expenses:Store:Refunds
+
+# cohosts is a comma delimited list of ids of trusted remote users that may
+# co-host the store: they mirror its catalog and accept orders while the local
+# client is offline, syncing the orders back once it is reachable.
+# cohosts =
+
+# cohostprimary is the id of the primary store to co-host. When set, the local
+# product files are ignored and the catalog is mirrored from the primary store
+# every cohostsyncinterval.
+# cohostprimary =
+# cohostsyncinterval = 5m
+
+# dbfile is a bbolt database file where the store state (carts, orders, stock
+# levels, etc) is kept. If empty, the state is kept in JSON files under the
+# store root. The existing state is not migrated between the two.
+# dbfile =
+
+# webhookurl is the URL of an external (fulfillment, ERP, etc) system the
+# order events (placed, paid and canceled) are POSTed to as JSON. Each request
+# is signed with an HMAC-SHA256 of its body keyed by webhooksecret, in the
+# X-Simplestore-Signature header.
+# webhookurl =
+# webhooksecret =
+
+# invsyncaddr is the loopback address (e.g. 127.0.0.1:9871) of an HTTP endpoint
+# for an external inventory system to push stock and price updates and pull
+# the inventory and order events. Requests must send invsynctoken as a bearer
+# token. invsyncconflicts is the policy for external updates that conflict with
+# changes made by the store admins: external (external updates win), local
+# (local changes win) or newest (the most recent change wins).
+# invsyncaddr =
+# invsynctoken =
+# invsyncconflicts = external
+
+# lowstockthreshold is the stock level below which the admins (and the local
+# client) are warned about a product. Zero disables the warnings.
+# lowstockthreshold = 0
+
+# cartreminderidle is how long a cart must be left unchanged before its user
+# is sent cartremindermsg (or a default message) as a reminder. Empty disables
+# the reminders.
+# cartreminderidle = 24h
+# cartremindermsg =
+
+# orderreminders is a comma delimited list of delays after an order is placed
+# at which its user is reminded to pay it while it is unpaid. Unpaid orders are
+# canceled cancelunpaidafter the final reminder (empty disables cancellation).
+# Users may opt out of the reminders in the /reminders/off page of the store.
+# orderreminders = 1h,12h
+# cancelunpaidafter = 12h
+
+# taxrate is the rate (as a percentage) of the tax charged on orders, itemized
+# as taxname in the receipts. taxregions is a comma delimited list of rates per
+# region of the shipping address, in the format <region>:<rate>, where the
+# region is a country code (e.g. US) or a country code and state (e.g. US-CA).
+# Orders shipped to other regions (or without shipping) are charged taxrate.
+# When taxshipping is set, the shipping charge is also taxed.
+# taxname = Tax
+# taxrate = 0
+# taxregions =
+# taxshipping = false
+
+[donations]
+# root is the dir where the donations received in the donation page are kept.
+# When set, remote users may fetch the donation page at /donate, choose a preset
+# or custom amount and pay the generated LN invoice. Donors are thanked with a
+# PM once their donation is received.
+# root = ~/.brclient/donations
+
+# title and description are shown at the top of the donation page.
+# title = Donate
+# description =
+
+# presets is a comma delimited list of suggested donation amounts, in DCR.
+# presets = 0.1,0.5,1
+
+# minamount is the min amount, in DCR, of custom donations.
+# minamount = 0
+
+# publiclist lists the donors who chose to share a name, along with the total
+# amount received, in the donation page.
+# publiclist = false
+
+[tickets]
+# root is the dir with the events file (events.toml) of the events to sell
+# tickets for. When set, remote users may buy tickets at /tickets. Each paid
+# ticket gets a unique code, signed with a key kept in the root dir, that is
+# sent to the buyer. Verify and redeem codes at the door with the
+# /pages tickets verify|redeem commands. Example events.toml:
+#
+#   [[events]]
+#   id = "launch-party"
+#   title = "Launch Party"
+#   venue = "Main Hall"
+#   date = 2024-06-01T20:00:00Z
+#   price = 0.5 # DCR per ticket
+#   capacity = 100
+#
+# root = ~/.brclient/tickets
+
+[booking]
+# root is the dir with the slots file (slots.toml) of the time slots open for
+# booking. When set, remote users may book appointments at /booking. Paid slots
+# are confirmed once their LN invoice is paid and slots that overlap a booked
+# one can no longer be booked. Example slots.toml:
+#
+#   [[slots]]
+#   title = "Consultation"
+#   start = 2024-06-01T10:00:00Z
+#   duration = "30m"
+#   price = 0.1 # DCR
+#
+# root = ~/.brclient/booking
+
+# reminderlead is how long before the start of a booking both parties are
+# reminded of it.
+# reminderlead = 1h
+
+[plugins]
+# config is the path to a JSON file with the list of plugins to run. Plugins
+# are external programs that talk to the client through their stdin and
+# stdout (see doc/plugins.md). Each plugin only gets the permissions listed in
+# its config: messages.read, messages.send, commands, resources,
+# payments.read and payments.send. Example:
+#
+#   [{
+#     "name": "echo",
+#     "command": "/usr/local/bin/br-echo-plugin",
+#     "args": ["-v"],
+#     "dir": "/var/lib/br-echo",
+#     "env": ["ECHO_PREFIX=bot"],
+#     "permissions": ["messages.read", "messages.send", "commands", "resources"],
+#     "resources_prefix": "echo",
+#     "max_tip_dcr": 0,
+#     "max_daily_tips_dcr": 0,
+#     "disabled": false
+#   }]
+#
+# config = ~/.brclient/plugins.json
+
+[updates]
+# check enables periodically checking for new releases. Releases are described
+# in a manifest that must be signed by the trusted release keys. The manifest
+# is fetched either over HTTPS (manifesturl) or as a page served by a user of
+# the BR network (manifestuser and manifestpath). Only one of them may be set.
+# check = false
+# manifesturl = https://example.com/brclient/manifest.json
+# manifestuser =
+# manifestpath = releases/manifest.json
+
+# keys is the comma separated list of hex encoded ed25519 keys trusted to sign
+# release manifests and minsigs is the number of them that must sign each
+# manifest.
+# keys =
+# minsigs = 1
+
+# interval is the interval between checks for new releases.
+# interval = 24h
+
+# stagedir is the dir where the binary of new releases is downloaded to (after
+# verifying its hash). The running binary is never replaced: staged binaries
+# must be manually swapped. If empty, new releases are only notified.
+# stagedir = ~/.brclient/updates
 `
 )
@@ -17,6 +17,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources"
 	"github.com/companyzero/bisonrelay/internal/mdembeds"
 	"github.com/companyzero/bisonrelay/internal/strescape"
 	"github.com/companyzero/bisonrelay/rpc"
@@ -118,34 +119,19 @@ func (ff *formField) view() string {
 	return b.String()
 }
 
-var formFieldPattern = regexp.MustCompile(`([\w]+)="([^"]*)"`)
-
 func parseFormField(line string) *formField {
-	matches := formFieldPattern.FindAllStringSubmatch(line, -1)
-
-	ff := &formField{}
-	var value string
-	hasValue := false
-	for _, m := range matches {
-		if len(m) < 3 {
-			continue
-		}
-		switch m[1] {
-		case "type":
-			ff.typ = m[2]
-		case "name":
-			ff.name = m[2]
-		case "value":
-			value = m[2]
-			hasValue = true
-		case "label":
-			ff.label = m[2]
-		}
+	field, ok := resources.ParseFormField(line)
+	if !ok {
+		return nil
 	}
 
-	if ff.typ == "" {
-		return nil
+	ff := &formField{
+		typ:   field.Type,
+		name:  field.Name,
+		label: field.Label,
 	}
+	value := field.Value
+	hasValue := strings.Contains(line, "value=\"")
 
 	switch ff.typ {
 	case "txtinput":
@@ -710,6 +696,13 @@ func (cw *chatWindow) renderMsgElements(winW int, as *appState, elements []*chat
 				}
 
 				switch {
+				case args.QuoteExpired(time.Now()) && args.Refresh != "":
+					s += "[Quote expired - Ctrl+V to refresh]"
+				case args.QuoteExpired(time.Now()):
+					s += "[Quote expired]"
+				case args.IsQuote():
+					s += fmt.Sprintf("[Quote valid until %s]",
+						time.Unix(args.ExpiresTS, 0).Format("Mon, 02 Jan 15:04"))
 				case args.Download.IsEmpty() && (len(args.Data) == 0):
 					s += "[Empty link and data]"
 				case args.Download.IsEmpty() && args.Typ == "":
@@ -836,6 +829,20 @@ func (cw *chatWindow) renderPage(winW int, as *appState, b *strings.Builder) {
 		nick, _ := as.c.UserNick(cw.page.UID)
 		fmt.Fprintf(b, "Source : %s (%s)\n", strescape.Nick(nick), cw.page.UID)
 		fmt.Fprintf(b, "Path %s: %s\n", loadingTxt, strescape.Nick(strings.Join(cw.page.Request.Path, "/")))
+		switch cw.page.SignatureStatus {
+		case clientdb.ResourceSignatureValid:
+			b.WriteString("Signed : ✓ signed by the source\n")
+		case clientdb.ResourceSignatureInvalid:
+			b.WriteString("Signed : ✗ INVALID SIGNATURE - the page may have been altered\n")
+		}
+		if sum := cw.page.OrderSummary; sum != nil {
+			fmt.Fprintf(b, "Order  : ✓ #%s, %s via %s, payment details verified\n",
+				strescape.Content(sum.OrderID),
+				dcrutil.Amount(sum.TotalAtoms),
+				strescape.Content(sum.PayType))
+		} else if cw.page.OrderSummaryStatus == clientdb.ResourceSignatureInvalid {
+			b.WriteString("Order  : ✗ INVALID ORDER SUMMARY - do not pay this order\n")
+		}
 		fmt.Fprintf(b, strings.Repeat("―", winW))
 		b.WriteRune('\n')
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,7 +17,12 @@ import (
 	"github.com/companyzero/bisonrelay/client"
 	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/contactimport"
+	"github.com/companyzero/bisonrelay/client/plugins"
+	"github.com/companyzero/bisonrelay/client/resources/simplestore"
+	"github.com/companyzero/bisonrelay/client/resources/tickets"
 	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rates"
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrlnd/lnrpc"
@@ -406,6 +412,41 @@ var listCommands = []tuicmd{
 			})
 			return nil
 		},
+	}, {
+		cmd:           "attachments",
+		usableOffline: true,
+		usage:         "<nick or user id>",
+		descr:         "List the files downloaded from a user",
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "nick or user id must be specified"}
+			}
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+			atts, err := as.c.ListUserAttachments(uid)
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Files downloaded from %s", strescape.Nick(args[0]))
+				for _, att := range atts {
+					pf("%s %q (%s) - %s",
+						att.Received.Format(ISO8601DateTime),
+						att.Filename, hbytes(int64(att.Size)),
+						att.DiskPath)
+				}
+			})
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return nickCompleter(arg, as)
+			}
+			return nil
+		},
 	}, {
 		cmd:           "paystats",
 		usableOffline: true,
@@ -465,6 +506,32 @@ var listCommands = []tuicmd{
 
 			return nil
 		},
+	}, {
+		cmd:   "tipstats",
+		usage: "[sub]",
+		descr: "Received tips leaderboard and thank-you commands",
+		sub:   tipStatsCommands,
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return cmdCompleter(tipStatsCommands, arg, false)
+			}
+			return nil
+		},
+		handler: subcmdNeededHandler,
+	}, {
+		cmd:   "summary",
+		usage: "[sub]",
+		descr: "Summarize long posts and GC backlogs",
+		long: []string{"Summaries are produced by the command set in the summarizecmd config option.",
+			"Received posts longer than summarizepostsminlen and GCs after summarizegcmsgs new messages are summarized automatically."},
+		sub: summaryCommands,
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return cmdCompleter(summaryCommands, arg, false)
+			}
+			return nil
+		},
+		handler: subcmdNeededHandler,
 	}, {
 		cmd:     "svrrates",
 		aliases: []string{"serverrates"},
@@ -549,6 +616,57 @@ var listCommands = []tuicmd{
 			})
 			return nil
 		},
+	}, {
+		cmd:           "ratchethealth",
+		descr:         "Check ratchets and KXs for anomalies and list the active alerts",
+		usableOffline: true,
+		handler: func(args []string, as *appState) error {
+			alerts, err := as.c.CheckRatchetHealth()
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				if len(alerts) == 0 {
+					pf("No ratchet health alerts")
+				}
+				for _, alert := range alerts {
+					pf("%s - %s - %s",
+						alert.Timestamp.Format(ISO8601DateTime),
+						alert.Type, alert.Detail)
+					pf("  %s", alert.Remediation)
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "peerscores",
+		descr:         "List the reply latency and reliability scores of the remote users",
+		usableOffline: true,
+		handler: func(args []string, as *appState) error {
+			scores, err := as.c.PeerScores()
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				if len(scores) == 0 {
+					pf("No peer scores")
+				}
+				for _, score := range scores {
+					nick, _ := as.c.UserNick(score.UID)
+					nick = strescape.Nick(nick)
+					abandoned := ""
+					if score.Abandoned {
+						abandoned = " - abandoned client?"
+					}
+					pf("%s - latency %s - reliability %.0f%% - "+
+						"%d replied, %d missed, %d pending%s", nick,
+						score.Latency.Round(time.Second),
+						score.Reliability*100, score.Replies,
+						score.Missed, score.Pending, abandoned)
+				}
+			})
+			return nil
+		},
 	},
 }
 
@@ -806,6 +924,9 @@ var gcCommands = []tuicmd{
 				} else if slices.Contains(gc.ExtraAdmins, myID) {
 					pf("Local client is admin of this GC")
 				}
+				if gc.ReadOnly {
+					pf("Read-only GC (only admins and posters may post)")
+				}
 				pf("Members (%d + local client)", len(members))
 				firstUknown := true
 				for _, uid := range members {
@@ -814,6 +935,8 @@ var gcCommands = []tuicmd{
 						ignored += " (owner)"
 					} else if slices.Contains(gc.ExtraAdmins, uid) {
 						ignored += " (admin)"
+					} else if gc.ReadOnly && slices.Contains(gc.Posters, uid) {
+						ignored += " (poster)"
 					}
 					if gcbl.IsBlocked(uid) {
 						ignored += " (in GC blocklist)"
@@ -1223,6 +1346,57 @@ var gcCommands = []tuicmd{
 			}
 			return nil
 		},
+	}, {
+		cmd:   "readonly",
+		usage: "<gc> on|off [<poster>...]",
+		descr: "Make a GC read-only, where only admins and the posters may send messages",
+		long: []string{
+			"Read-only GCs are suitable for announcements. Messages from members that are not admins or posters are dropped by the other members.",
+			"Members running clients without support for read-only GCs can still send messages, but the messages are dropped by the members that support it.",
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "GC cannot be empty"}
+			}
+			if len(args) < 2 || (args[1] != "on" && args[1] != "off") {
+				return usageError{msg: "Specify either on or off"}
+			}
+
+			gcID, err := as.c.GCIDByName(args[0])
+			if err != nil {
+				return err
+			}
+
+			readOnly := args[1] == "on"
+			var posters []clientintf.UserID
+			if readOnly {
+				for _, nick := range args[2:] {
+					uid, err := as.c.UIDByNick(nick)
+					if err != nil {
+						return err
+					}
+					posters = append(posters, uid)
+				}
+			}
+
+			return as.setGCReadOnly(gcID, readOnly, posters)
+		},
+
+		completer: func(args []string, arg string, as *appState) []string {
+			switch len(args) {
+			case 0:
+				return gcCompleter(arg, as)
+			case 1:
+				var res []string
+				for _, t := range []string{"on", "off"} {
+					if strings.HasPrefix(t, arg) {
+						res = append(res, t)
+					}
+				}
+				return res
+			}
+			return nickCompleter(arg, as)
+		},
 	},
 }
 
@@ -1545,6 +1719,163 @@ var postCommands = []tuicmd{
 			}()
 			return nil
 		},
+	}, {
+		cmd:           "export",
+		usableOffline: true,
+		usage:         "<filename>",
+		descr:         "Export the local posts to an archive file",
+		long:          []string{"Writes a zip archive with all posts published by the local client (as markdown files) and the comments received on them (as JSON files). The archive may be imported in another client with /post import."},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return fileCompleter(arg)
+			}
+			return nil
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "filename must be specified"}
+			}
+			filename, err := homedir.Expand(args[0])
+			if err != nil {
+				return err
+			}
+			f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+			if err != nil {
+				return err
+			}
+			n, err := as.c.ExportPosts(f)
+			if err != nil {
+				f.Close()
+				os.Remove(filename)
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Exported %d posts to %s", n, filename)
+			return nil
+		},
+	}, {
+		cmd:   "import",
+		usage: "<filename>",
+		descr: "Republish the posts of an archive file",
+		long:  []string{"Republishes the posts of an archive created with /post export as new posts of the local client, which are sent to the current subscribers. Comments in the archive are not republished."},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return fileCompleter(arg)
+			}
+			return nil
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "filename must be specified"}
+			}
+			filename, err := homedir.Expand(args[0])
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(filename)
+			if err != nil {
+				return err
+			}
+			finfo, err := f.Stat()
+			if err != nil {
+				f.Close()
+				return err
+			}
+			go func() {
+				defer f.Close()
+				summs, err := as.c.ImportPosts(f, finfo.Size())
+				if err != nil {
+					as.cwHelpMsg("Unable to import posts: %v", err)
+				}
+				if len(summs) > 0 {
+					as.cwHelpMsg("Republished %d posts from %s",
+						len(summs), filename)
+				}
+			}()
+			return nil
+		},
+	}, {
+		cmd:           "announcegcs",
+		usableOffline: true,
+		usage:         "[none | <gc>...]",
+		descr:         "Set the GCs where new posts are announced",
+		long:          []string{"Sets the GCs where new local posts are automatically announced with their title, a teaser and their ID. Members already subscribed to the local posts do not receive the announcements, and posts published less than an hour after the last announcement in a GC are announced together at the end of the hour.", "Without arguments, lists the GCs where posts are announced. Specify 'none' to disable the announcements."},
+		completer: func(args []string, arg string, as *appState) []string {
+			return gcCompleter(arg, as)
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) == 0 {
+				gcs, err := as.c.PostAnnounceGCs()
+				if err != nil {
+					return err
+				}
+				if len(gcs) == 0 {
+					as.cwHelpMsg("New posts are not announced in any GCs")
+					return nil
+				}
+				as.cwHelpMsgs(func(pf printf) {
+					pf("GCs where new posts are announced:")
+					for _, gcID := range gcs {
+						name, _ := as.c.GetGCAlias(gcID)
+						pf("  %s %s", gcID, name)
+					}
+				})
+				return nil
+			}
+
+			var gcs []zkidentity.ShortID
+			if !(len(args) == 1 && args[0] == "none") {
+				for _, arg := range args {
+					gcID, err := as.c.GCIDByName(arg)
+					if err != nil {
+						return err
+					}
+					gcs = append(gcs, gcID)
+				}
+			}
+			if err := as.c.SetPostAnnounceGCs(gcs); err != nil {
+				return err
+			}
+			as.cwHelpMsg("New posts will be announced in %d GCs", len(gcs))
+			return nil
+		},
+	}, {
+		cmd:   "announce",
+		usage: "<post id> <gc>...",
+		descr: "Announce a local post in the given GCs",
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) > 0 {
+				return gcCompleter(arg, as)
+			}
+			return nil
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "post id cannot be empty"}
+			}
+			if len(args) < 2 {
+				return usageError{msg: "at least one GC must be specified"}
+			}
+			var pid clientintf.PostID
+			if err := pid.FromString(args[0]); err != nil {
+				return err
+			}
+			gcs := make([]zkidentity.ShortID, 0, len(args)-1)
+			for _, arg := range args[1:] {
+				gcID, err := as.c.GCIDByName(arg)
+				if err != nil {
+					return err
+				}
+				gcs = append(gcs, gcID)
+			}
+			if err := as.c.AnnouncePost(pid, gcs); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Announcing post %s in %d GCs", pid, len(gcs))
+			return nil
+		},
 	}, {
 		cmd:     "subscribe",
 		aliases: []string{"sub"},
@@ -1871,53 +2202,152 @@ var lnCommands = []tuicmd{
 		},
 	},
 	{
-		cmd:           "openchannel",
+		cmd:           "exportmultiscb",
 		usableOffline: true,
-		aliases:       []string{"openchan", "opench"},
-		descr:         "Open a channel funded by the local node",
+		descr:         "Export the multipacked SCB to a file",
+		long: []string{"The static channel backup (SCB) allows recovering the funds of channels after restoring the wallet from its seed.",
+			"The file should be kept in a different device. Use the 'scbbackupdirs' config option to keep automatically updated copies."},
+		usage: "<filename>",
+		completer: func(args []string, arg string, as *appState) []string {
+			return fileCompleter(arg)
+		},
 		handler: func(args []string, as *appState) error {
-			if as.lnRPC == nil {
+			if as.lnPC == nil {
 				return fmt.Errorf("LN client not configured")
 			}
-			as.sendMsg(msgLNOpenChannel{})
+			if len(args) < 1 {
+				return usageError{msg: "filename cannot be empty"}
+			}
+			if err := as.lnPC.SaveChannelBackup(as.ctx, args[0]); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Exported SCB file to %s", args[0])
 			return nil
 		},
 	},
 	{
-		cmd:           "closechannel",
+		cmd:           "verifymultiscb",
 		usableOffline: true,
-		aliases:       []string{"closechan"},
-		usage:         "<channel-point> [\"force\"]",
-		descr:         "Close a local channel",
-		long:          []string{"If the remote counterparty is offline, the channel can be force-closed by specifying \"force\" as the second parameter."},
+		descr:         "Verify a multipacked SCB file can be used to restore channels",
+		usage:         "<filename>",
+		completer: func(args []string, arg string, as *appState) []string {
+			return fileCompleter(arg)
+		},
 		handler: func(args []string, as *appState) error {
+			if as.lnPC == nil {
+				return fmt.Errorf("LN client not configured")
+			}
 			if len(args) < 1 {
-				return usageError{msg: "channel point cannot be empty"}
+				return usageError{msg: "filename cannot be empty"}
 			}
-			var force bool
-			if len(args) > 1 {
-				if args[1] != "force" {
-					return usageError{msg: "second argument must " +
-						"be 'force' or remain empty"}
-				}
-				force = true
+			packedMulti, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to read multi packed "+
+					"backup: %v", err)
 			}
-
-			var chanPoint *lnrpc.ChannelPoint
-			if len(args) < 64 {
-				// Try to find by the prefix of the channel point.
-				chans, err := as.lnRPC.ListChannels(as.ctx,
-					&lnrpc.ListChannelsRequest{})
-				if err != nil {
-					return err
-				}
-				for _, c := range chans.Channels {
-					if strings.HasPrefix(c.ChannelPoint, args[0]) {
-						cp, err := strToChanPoint(c.ChannelPoint)
-						if err != nil {
-							return err
-						} else if chanPoint != nil {
-							return fmt.Errorf("channel prefix %q "+
+			if err := as.lnPC.VerifyChannelBackup(as.ctx, packedMulti); err != nil {
+				return fmt.Errorf("invalid SCB file: %v", err)
+			}
+			as.cwHelpMsg("SCB file %s is valid", args[0])
+			return nil
+		},
+	},
+	{
+		cmd:           "paymentproofs",
+		usableOffline: true,
+		descr:         "List and verify the proofs of payments made to or received from a user",
+		usage:         "<nick>",
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return nickCompleter(arg, as)
+			}
+			return nil
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "nick cannot be empty"}
+			}
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+			proofs, err := as.c.ListPaymentProofs(uid)
+			if err != nil {
+				return err
+			}
+			if len(proofs) == 0 {
+				as.cwHelpMsg("No payment proofs with %s", args[0])
+				return nil
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Payment proofs with %s", args[0])
+				for _, proof := range proofs {
+					dir := "Paid"
+					if proof.Payee == as.c.PublicID() {
+						dir = "Received"
+					}
+					status := "valid"
+					if err := as.c.VerifyPaymentProof(proof); err != nil {
+						status = err.Error()
+					}
+					pf("%s %s %.8f DCR (%s) hash %x: %s",
+						time.Unix(proof.Timestamp, 0).Format(ISO8601DateTime),
+						dir, float64(proof.MilliAtoms)/1e11,
+						proof.Context, proof.PaymentHash, status)
+				}
+			})
+			return nil
+		},
+	},
+	{
+		cmd:           "openchannel",
+		usableOffline: true,
+		aliases:       []string{"openchan", "opench"},
+		descr:         "Open a channel funded by the local node",
+		handler: func(args []string, as *appState) error {
+			if as.lnRPC == nil {
+				return fmt.Errorf("LN client not configured")
+			}
+			as.sendMsg(msgLNOpenChannel{})
+			return nil
+		},
+	},
+	{
+		cmd:           "closechannel",
+		usableOffline: true,
+		aliases:       []string{"closechan"},
+		usage:         "<channel-point> [\"force\"]",
+		descr:         "Close a local channel",
+		long:          []string{"If the remote counterparty is offline, the channel can be force-closed by specifying \"force\" as the second parameter."},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "channel point cannot be empty"}
+			}
+			var force bool
+			if len(args) > 1 {
+				if args[1] != "force" {
+					return usageError{msg: "second argument must " +
+						"be 'force' or remain empty"}
+				}
+				force = true
+			}
+
+			var chanPoint *lnrpc.ChannelPoint
+			if len(args) < 64 {
+				// Try to find by the prefix of the channel point.
+				chans, err := as.lnRPC.ListChannels(as.ctx,
+					&lnrpc.ListChannelsRequest{})
+				if err != nil {
+					return err
+				}
+				for _, c := range chans.Channels {
+					if strings.HasPrefix(c.ChannelPoint, args[0]) {
+						cp, err := strToChanPoint(c.ChannelPoint)
+						if err != nil {
+							return err
+						} else if chanPoint != nil {
+							return fmt.Errorf("channel prefix %q "+
 								"matches multiple channels", args[0])
 						}
 						chanPoint = cp
@@ -2329,6 +2759,19 @@ var lnCommands = []tuicmd{
 
 			as.cwHelpMsg("Attempting to pay invoice")
 			go func() {
+				decReq := &lnrpc.PayReqString{PayReq: payreq}
+				invoice, err := as.lnRPC.DecodePayReq(as.ctx, decReq)
+				if err != nil {
+					as.cwHelpMsg("Unable to decode invoice: %v", err)
+					return
+				}
+				err = as.c.WaitPaymentApproval(clientintf.PaymentCategoryManual,
+					payreq, invoice.NumMAtoms)
+				if err != nil {
+					as.cwHelpMsg("Not paying invoice: %v", err)
+					return
+				}
+
 				pc, err := as.lnRPC.SendPayment(as.ctx)
 				if err != nil {
 					as.cwHelpMsg("PC: %v", err)
@@ -2413,6 +2856,13 @@ var lnCommands = []tuicmd{
 			addr := args[1]
 			as.cwHelpMsg("Sending %s DCR to %s", amount, addr)
 			go func() {
+				err := as.c.WaitPaymentApproval(clientintf.PaymentCategoryOnChain,
+					"", int64(amount)*1000)
+				if err != nil {
+					as.cwHelpMsg("Not sending coins on-chain: %v", err)
+					return
+				}
+
 				req := &lnrpc.SendCoinsRequest{
 					Addr:    addr,
 					Amount:  int64(amount),
@@ -2490,6 +2940,70 @@ var lnCommands = []tuicmd{
 			as.cwHelpMsg("Created account %s", name)
 			return nil
 		},
+	}, {
+		cmd:           "watchtowers",
+		aliases:       []string{"wt"},
+		usableOffline: true,
+		descr:         "List the watchtowers used to back up channel states",
+		long: []string{"Watchtowers monitor the chain for attempts to close channels with revoked states while the client is offline.",
+			"The watchtower client must be enabled by setting the 'watchtowers' config option."},
+		sub: []tuicmd{{
+			cmd:           "add",
+			usableOffline: true,
+			usage:         "<pubkey>@<host>:<port>",
+			descr:         "Add a watchtower",
+			handler: func(args []string, as *appState) error {
+				if len(args) < 1 {
+					return usageError{msg: "watchtower URI cannot be empty"}
+				}
+				if as.lnPC == nil {
+					return fmt.Errorf("LN client not configured")
+				}
+				if err := as.lnPC.AddWatchtower(as.ctx, args[0]); err != nil {
+					return err
+				}
+				as.cwHelpMsg("Added watchtower %s", args[0])
+				return nil
+			},
+		}, {
+			cmd:           "remove",
+			aliases:       []string{"rm"},
+			usableOffline: true,
+			usage:         "<pubkey>",
+			descr:         "Remove a watchtower",
+			handler: func(args []string, as *appState) error {
+				if len(args) < 1 {
+					return usageError{msg: "watchtower pubkey cannot be empty"}
+				}
+				if as.lnPC == nil {
+					return fmt.Errorf("LN client not configured")
+				}
+				if err := as.lnPC.RemoveWatchtower(as.ctx, args[0]); err != nil {
+					return err
+				}
+				as.cwHelpMsg("Removed watchtower %s", args[0])
+				return nil
+			},
+		}},
+		handler: func(args []string, as *appState) error {
+			if as.lnPC == nil {
+				return fmt.Errorf("LN client not configured")
+			}
+			towers, err := as.lnPC.ListWatchtowers(as.ctx)
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Watchtowers (%d)", len(towers))
+				for _, t := range towers {
+					pf("%s - active: %v - sessions: %d - %s",
+						t.Pubkey, t.Active, t.NumSessions,
+						strings.Join(t.Addresses, ", "))
+				}
+			})
+			return nil
+		},
 	},
 }
 
@@ -2539,112 +3053,770 @@ var pagesCommands = []tuicmd{
 			nextSess := clientintf.PagesSessionID(0)
 			return as.fetchPage(as.c.PublicID(), pagePath, nextSess, 0, nil)
 		},
-	},
-}
-
-var filterCommands = []tuicmd{
-	{
-		cmd:           "list",
+	}, {
+		cmd:           "theme",
 		usableOffline: true,
-		aliases:       []string{"ls"},
-		descr:         "Lists content-based filters",
-		handler: func(args []string, as *appState) error {
-			filters := as.c.ListContentFilters()
-			if len(filters) == 0 {
-				as.cwHelpMsg("Client has no content-based filters")
+		descr:         "List the installed simplestore themes",
+		long: []string{"Themes are bundles (zip files or dirs) with a theme.toml manifest, a templates dir with the store templates and an assets dir with files embedded in the templates.",
+			"Templates not defined in the active theme are loaded from the store dir."},
+		sub: []tuicmd{{
+			cmd:           "install",
+			usableOffline: true,
+			usage:         "<path/to/bundle>",
+			descr:         "Install a theme bundle",
+			handler: func(args []string, as *appState) error {
+				if len(args) < 1 {
+					return usageError{msg: "theme bundle path cannot be empty"}
+				}
+				if as.sstore == nil {
+					return fmt.Errorf("simplestore not configured")
+				}
+				manifest, err := as.sstore.InstallTheme(args[0])
+				if err != nil {
+					return err
+				}
+				as.cwHelpMsg("Installed theme %q version %q",
+					manifest.Name, manifest.Version)
+				return nil
+			},
+		}, {
+			cmd:           "activate",
+			usableOffline: true,
+			usage:         "<name>",
+			descr:         "Activate an installed theme",
+			handler: func(args []string, as *appState) error {
+				if len(args) < 1 {
+					return usageError{msg: "theme name cannot be empty"}
+				}
+				if as.sstore == nil {
+					return fmt.Errorf("simplestore not configured")
+				}
+				if err := as.sstore.ActivateTheme(args[0]); err != nil {
+					return err
+				}
+				as.cwHelpMsg("Activated theme %q", args[0])
+				return nil
+			},
+		}, {
+			cmd:           "deactivate",
+			usableOffline: true,
+			descr:         "Deactivate the active theme",
+			handler: func(args []string, as *appState) error {
+				if as.sstore == nil {
+					return fmt.Errorf("simplestore not configured")
+				}
+				if err := as.sstore.ActivateTheme(""); err != nil {
+					return err
+				}
+				as.cwHelpMsg("Deactivated store theme")
+				return nil
+			},
+		}, {
+			cmd:           "remove",
+			aliases:       []string{"rm"},
+			usableOffline: true,
+			usage:         "<name>",
+			descr:         "Remove an installed theme",
+			handler: func(args []string, as *appState) error {
+				if len(args) < 1 {
+					return usageError{msg: "theme name cannot be empty"}
+				}
+				if as.sstore == nil {
+					return fmt.Errorf("simplestore not configured")
+				}
+				if err := as.sstore.RemoveTheme(args[0]); err != nil {
+					return err
+				}
+				as.cwHelpMsg("Removed theme %q", args[0])
 				return nil
+			},
+		}},
+		handler: func(args []string, as *appState) error {
+			if as.sstore == nil {
+				return fmt.Errorf("simplestore not configured")
+			}
+			themes, err := as.sstore.ListThemes()
+			if err != nil {
+				return err
 			}
-
 			as.cwHelpMsgs(func(pf printf) {
 				pf("")
-				pf("Content filters (%d total)", len(filters))
-				for _, cf := range filters {
-					var s string
-					if !cf.SkipPMs {
-						s += "PMs, "
+				pf("Store themes (%d)", len(themes))
+				for _, t := range themes {
+					active := ""
+					if t.Active {
+						active = " (active)"
 					}
-					if !cf.SkipGCMs {
-						s += "GCMs"
-						if cf.GC != nil && !cf.GC.IsEmpty() {
-							gc, _ := as.c.GetGCAlias(*cf.GC)
-							s += fmt.Sprintf(" gc=%s", strescape.Nick(gc))
-						}
-						s += ", "
-
-					}
-					if !cf.SkipPosts {
-						s += "posts, "
-					}
-					if !cf.SkipPostComments {
-						s += "post comments, "
-					}
-					if cf.UID != nil {
-						nick, _ := as.c.UserNick(*cf.UID)
-						s += fmt.Sprintf("user=%s, ", strescape.Nick(nick))
-					}
-
-					s += fmt.Sprintf("regexp=\"%s\"", cf.Regexp)
-					pf("%08d - %s", cf.ID, s)
+					pf("%s %s%s - %s - %s", t.Name, t.Version,
+						active, t.Author, t.Description)
 				}
 			})
-
 			return nil
 		},
 	}, {
-		cmd:           "add",
+		cmd:           "storelog",
 		usableOffline: true,
-		descr:         "Add a simple, case-insensitive content filter",
-		usage:         "[filter]",
-		long: []string{
-			"Adding a filter through this command filters content from ",
-			"all users on all contexts. Use the 'addrule' command for ",
-			"specifying more complex rules.",
-		},
-		rawHandler: func(rawCmd string, args []string, as *appState) error {
-			_, expr := popNArgs(rawCmd, 2) // cmd+subcmd
-			if len(expr) == 0 {
-				return usageError{"filter expression cannot be empty"}
+		usage:         "[<count>]",
+		descr:         "List the last entries of the simplestore event log",
+		long:          []string{"The event log records every change to the store (products, stock, carts, orders, order status changes and refunds) in a hash-chained log."},
+		sub: []tuicmd{{
+			cmd:           "verify",
+			usableOffline: true,
+			descr:         "Verify the integrity of the simplestore event log",
+			handler: func(args []string, as *appState) error {
+				if as.sstore == nil {
+					return fmt.Errorf("simplestore not configured")
+				}
+				n, err := as.sstore.VerifyEventLog()
+				if err != nil {
+					return fmt.Errorf("event log verification failed "+
+						"after %d entries: %v", n, err)
+				}
+				as.cwHelpMsg("Verified %d entries of the store event log", n)
+				return nil
+			},
+		}, {
+			cmd:           "order",
+			usableOffline: true,
+			usage:         "<user> <order id>",
+			descr:         "List the events of an order, including the changes to the cart that preceded it",
+			handler: func(args []string, as *appState) error {
+				if as.sstore == nil {
+					return fmt.Errorf("simplestore not configured")
+				}
+				if len(args) < 2 {
+					return usageError{msg: "user and order id must be specified"}
+				}
+				uid, err := as.c.UIDByNick(args[0])
+				if err != nil {
+					return err
+				}
+				var oid simplestore.OrderID
+				if err := oid.FromString(args[1]); err != nil {
+					return usageError{msg: fmt.Sprintf("invalid order id: %v", err)}
+				}
+				entries, err := as.sstore.OrderAuditTrail(uid, oid)
+				if err != nil {
+					return err
+				}
+				as.cwHelpMsgs(func(pf printf) {
+					pf("")
+					pf("Events of order %s/%s", args[0], oid)
+					for _, e := range entries {
+						actor := "store"
+						if e.Actor != nil {
+							actor = e.Actor.ShortLogID()
+							if nick, err := as.c.UserNick(*e.Actor); err == nil {
+								actor = strescape.Nick(nick)
+							}
+						}
+						pf("%d %s %s %s %v", e.Seq,
+							e.Timestamp.Format(ISO8601DateTime),
+							e.Type, actor, e.Details)
+					}
+				})
+				return nil
+			},
+		}},
+		handler: func(args []string, as *appState) error {
+			if as.sstore == nil {
+				return fmt.Errorf("simplestore not configured")
 			}
-
-			// Add the case-insensitive flag.
-			expr = "(?i)" + regexp.QuoteMeta(expr)
-
-			cf := &clientdb.ContentFilter{Regexp: expr}
-			err := as.c.StoreContentFilter(cf)
+			count := 20
+			if len(args) > 0 {
+				var err error
+				if count, err = strconv.Atoi(args[0]); err != nil || count < 1 {
+					return usageError{msg: "count must be a positive number"}
+				}
+			}
+			entries, err := as.sstore.EventLogTail(count)
 			if err != nil {
 				return err
 			}
-			as.cwHelpMsg("Added content filter rule %d", cf.ID)
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Store events (last %d)", len(entries))
+				for _, e := range entries {
+					pf("%d %s %s %v", e.Seq,
+						e.Timestamp.Format(ISO8601DateTime),
+						e.Type, e.Details)
+				}
+			})
 			return nil
 		},
 	}, {
-		cmd:           "addrule",
+		cmd:           "tickets",
 		usableOffline: true,
-		descr:         "Add a content-based filter",
-		usage:         "[user=<user>] [gc=<gc> | noGC] [noPost] [noPC] [noPM] [--] [regexp]",
-		long: []string{
-			"Content-based filters drop received messages before they are ",
-			"presented to the user.",
-			"",
-			"If an incoming message matches any of the existing filters, ",
-			"the message is dropped.",
-			"",
-			"By default, filters apply to all messages, received from any ",
-			"user and on all contexts (PMs, GCMs, posts and post comments). ",
-			"Some attributes allow refining when the filter applies: ",
-			"",
-			"  - user=<user>: only test filter if the message is from a specific user",
-			"  - gc=<gc>: only test filter if GCM is in a specific GC",
-			"  - noGC: do not apply filter for messages in GCs",
-			"  - noPost: do not apply filter for post conent",
-			"  - noPC: do not apply filter for post comments",
-			"  - noPM: do not apply filter for PMs",
-			"",
-			"Everything after the last valid option or after a literal '--'",
-			"is considered part of the regexp.",
-			"",
-			"The regexp follows Go's regexp package rules. As a reminder, ",
-			"it is case-sensitive by default, unless it is started with ",
+		descr:         "List the events with tickets on sale",
+		sub: []tuicmd{{
+			cmd:           "verify",
+			usableOffline: true,
+			usage:         "<code>",
+			descr:         "Verify a ticket code without redeeming it",
+			handler: func(args []string, as *appState) error {
+				if len(args) < 1 {
+					return usageError{msg: "ticket code must be specified"}
+				}
+				if as.tickets == nil {
+					return fmt.Errorf("tickets not configured")
+				}
+				info, err := as.tickets.VerifyTicket(args[0])
+				if err != nil {
+					return err
+				}
+				printTicketInfo(as, "Valid ticket", info)
+				return nil
+			},
+		}, {
+			cmd:           "redeem",
+			usableOffline: true,
+			usage:         "<code>",
+			descr:         "Verify a ticket code and mark it as redeemed",
+			long:          []string{"Redeeming a ticket fails if the ticket was already redeemed."},
+			handler: func(args []string, as *appState) error {
+				if len(args) < 1 {
+					return usageError{msg: "ticket code must be specified"}
+				}
+				if as.tickets == nil {
+					return fmt.Errorf("tickets not configured")
+				}
+				info, err := as.tickets.RedeemTicket(args[0])
+				if errors.Is(err, tickets.ErrTicketRedeemed) {
+					printTicketInfo(as, "Ticket already redeemed", info)
+					return err
+				}
+				if err != nil {
+					return err
+				}
+				printTicketInfo(as, "Redeemed ticket", info)
+				return nil
+			},
+		}},
+		handler: func(args []string, as *appState) error {
+			if as.tickets == nil {
+				return fmt.Errorf("tickets not configured")
+			}
+			events := as.tickets.Events()
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Events (%d)", len(events))
+				for _, e := range events {
+					pf("%s %s %q: %d of %d tickets available",
+						e.ID, e.Date.Format(ISO8601DateTime),
+						e.Title, e.Available, e.Capacity)
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "bookings",
+		usableOffline: true,
+		descr:         "List the upcoming confirmed bookings of appointments",
+		handler: func(args []string, as *appState) error {
+			if as.booking == nil {
+				return fmt.Errorf("appointment booking not configured")
+			}
+			bookings := as.booking.Bookings()
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Upcoming bookings (%d)", len(bookings))
+				for _, b := range bookings {
+					nick, _ := as.c.UserNick(b.User)
+					pf("#%d %s - %s %q by %s: %s", b.ID,
+						b.Start.Format(ISO8601DateTime),
+						b.End.Format("15:04"), b.Title,
+						strescape.Nick(nick),
+						strescape.Content(b.Note))
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:   "publish",
+		descr: "Publish a page to the server, to be served to other users while offline",
+		usage: "<path/to/page> <filename>",
+		long: []string{"The page is signed by the local client, so users that fetch it from the server can verify it was published by the local client.",
+			"Only servers that advertise support for hosting published pages accept them."},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 2 {
+				return usageError{msg: "page path and filename must be specified"}
+			}
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				return err
+			}
+			path := strings.Split(strings.Trim(args[0], "/"), "/")
+			if err := as.c.PublishResource(as.ctx, path, data); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Published page %q (%s)", args[0],
+				hbytes(int64(len(data))))
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 1 {
+				return fileCompleter(arg)
+			}
+			return nil
+		},
+	}, {
+		cmd:   "unpublish",
+		descr: "Remove a page previously published to the server",
+		usage: "<path/to/page>",
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "page path must be specified"}
+			}
+			path := strings.Split(strings.Trim(args[0], "/"), "/")
+			if err := as.c.UnpublishResource(as.ctx, path); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Removed published page %q", args[0])
+			return nil
+		},
+	}, {
+		cmd:   "fetchpublished",
+		descr: "Fetch a page published by a user to the server",
+		usage: "<nick> <path/to/page>",
+		handler: func(args []string, as *appState) error {
+			if len(args) < 2 {
+				return usageError{msg: "nick and page path must be specified"}
+			}
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+			path := strings.Split(strings.Trim(args[1], "/"), "/")
+			res, err := as.c.FetchPublishedResource(as.ctx, uid, path)
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Page %q published by %s at %s (signature verified)",
+					args[1], strescape.Nick(args[0]),
+					time.Unix(res.Timestamp, 0).Format(ISO8601DateTime))
+				for _, line := range strings.Split(string(res.Data), "\n") {
+					pf("%s", strescape.Content(line))
+				}
+			})
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return nickCompleter(arg, as)
+			}
+			return nil
+		},
+	},
+}
+
+// errPluginsNotConfigured is returned by plugin commands when no plugins
+// are configured.
+var errPluginsNotConfigured = errors.New("plugins are not configured (set plugins.config)")
+
+// pluginCmdHandler returns a handler of a plugin command that takes the name
+// of the plugin as its only argument.
+func pluginCmdHandler(f func(m *plugins.Manager, name string) error, done string) func(args []string, as *appState) error {
+	return func(args []string, as *appState) error {
+		if as.plugins == nil {
+			return errPluginsNotConfigured
+		}
+		if len(args) < 1 {
+			return usageError{"plugin name cannot be empty"}
+		}
+		if err := f(as.plugins, args[0]); err != nil {
+			return err
+		}
+		as.cwHelpMsg("%s plugin %s", done, args[0])
+		return nil
+	}
+}
+
+var pluginCommands = []tuicmd{
+	{
+		cmd:           "list",
+		usableOffline: true,
+		aliases:       []string{"ls"},
+		descr:         "List the plugins and their state",
+		handler: func(args []string, as *appState) error {
+			if as.plugins == nil {
+				return errPluginsNotConfigured
+			}
+			statuses := as.plugins.Plugins()
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Plugins (%d total)", len(statuses))
+				for _, st := range statuses {
+					pf("%s - %s (version %q, %d restarts)", st.Name,
+						st.State, strescape.Content(st.Version),
+						st.Restarts)
+					pf("  Permissions: %v", st.Permissions)
+					if st.LastErr != nil {
+						pf("  Last error: %v", st.LastErr)
+					}
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "commands",
+		usableOffline: true,
+		descr:         "List the commands registered by the running plugins",
+		handler: func(args []string, as *appState) error {
+			if as.plugins == nil {
+				return errPluginsNotConfigured
+			}
+			cmds := as.plugins.Commands()
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Plugin commands (%d total)", len(cmds))
+				for _, cmd := range cmds {
+					pf("%s %s - %s", cmd.Plugin,
+						strescape.Content(cmd.Name),
+						strescape.Content(cmd.Descr))
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:   "run",
+		descr: "Run a command registered by a plugin",
+		usage: "<plugin> <command> [args...]",
+		handler: func(args []string, as *appState) error {
+			if as.plugins == nil {
+				return errPluginsNotConfigured
+			}
+			if len(args) < 2 {
+				return usageError{"plugin and command cannot be empty"}
+			}
+			go func() {
+				out, err := as.plugins.RunCommand(as.ctx, args[0],
+					args[1], args[2:])
+				if err != nil {
+					as.cwHelpMsg("Plugin %s command %s failed: %v",
+						args[0], args[1], err)
+					return
+				}
+				as.cwHelpMsgs(func(pf printf) {
+					for _, line := range strings.Split(out, "\n") {
+						pf("%s", strescape.Content(line))
+					}
+				})
+			}()
+			return nil
+		},
+	}, {
+		cmd:           "start",
+		usableOffline: true,
+		descr:         "Start a stopped plugin",
+		usage:         "<plugin>",
+		handler: pluginCmdHandler(func(m *plugins.Manager, name string) error {
+			return m.Start(name)
+		}, "Starting"),
+	}, {
+		cmd:           "stop",
+		usableOffline: true,
+		descr:         "Stop a plugin",
+		usage:         "<plugin>",
+		handler: pluginCmdHandler(func(m *plugins.Manager, name string) error {
+			return m.Stop(name)
+		}, "Stopping"),
+	}, {
+		cmd:           "restart",
+		usableOffline: true,
+		descr:         "Restart a plugin",
+		usage:         "<plugin>",
+		handler: pluginCmdHandler(func(m *plugins.Manager, name string) error {
+			return m.Restart(name)
+		}, "Restarting"),
+	},
+}
+
+var contactCommands = []tuicmd{
+	{
+		cmd:           "import",
+		usableOffline: true,
+		usage:         "<source> <filename>",
+		descr:         "Import contacts exported from another platform",
+		long: []string{
+			"Imports the contacts of a CSV file exported from another messenger or address book as placeholders of users to invite to BR. The source is a label of the platform the contacts were exported from (e.g. signal, telegram, google).",
+			"The columns with the names and identifiers (phone numbers, email addresses, usernames, etc) of the contacts are found from the header of the file. Contacts already imported from the same source are skipped.",
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 1 {
+				return fileCompleter(arg)
+			}
+			return nil
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 || args[0] == "" {
+				return usageError{msg: "source must be specified"}
+			}
+			if len(args) < 2 {
+				return usageError{msg: "filename must be specified"}
+			}
+			filename, err := homedir.Expand(args[1])
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(filename)
+			if err != nil {
+				return err
+			}
+			contacts, err := contactimport.ParseCSV(f)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			added, err := as.c.ImportContacts(args[0], contacts)
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsg("Imported %d new contacts (%d skipped)", added,
+				len(contacts)-added)
+			return nil
+		},
+	}, {
+		cmd:           "list",
+		aliases:       []string{"ls"},
+		usableOffline: true,
+		usage:         "[pending|invited|joined]",
+		descr:         "List the imported contacts",
+		handler: func(args []string, as *appState) error {
+			var filter string
+			if len(args) > 0 {
+				filter = args[0]
+			}
+			switch filter {
+			case "", "pending", "invited", "joined":
+			default:
+				return usageError{msg: fmt.Sprintf("unknown filter %q", filter)}
+			}
+
+			contacts, err := as.c.ListImportedContacts()
+			if err != nil {
+				return err
+			}
+			var invited, joined int
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Imported contacts")
+				for _, ic := range contacts {
+					status := "pending"
+					switch {
+					case ic.Joined != nil:
+						status = "joined"
+						joined++
+						invited++
+					case ic.InviteRV != nil:
+						status = "invited"
+						invited++
+					}
+					if filter != "" && filter != status {
+						continue
+					}
+
+					ids := strings.Join(ic.Identifiers, ", ")
+					switch status {
+					case "joined":
+						nick, _ := as.c.UserNick(*ic.Joined)
+						pf("%s %q (%s) %s - joined by %s (%s) at %s",
+							ic.ID, ic.Name, ic.Source, ids,
+							strescape.Nick(nick), ic.Joined,
+							ic.JoinedTS.Format(ISO8601DateTime))
+					case "invited":
+						pf("%s %q (%s) %s - invited at %s", ic.ID,
+							ic.Name, ic.Source, ids,
+							ic.InvitedTS.Format(ISO8601DateTime))
+					default:
+						pf("%s %q (%s) %s", ic.ID, ic.Name,
+							ic.Source, ids)
+					}
+				}
+				pf("%d contacts, %d invited, %d joined", len(contacts),
+					invited, joined)
+			})
+			return nil
+		},
+	}, {
+		cmd:   "invite",
+		usage: "<id> <filename> [<gcname>]",
+		descr: "Create an invitation file for an imported contact",
+		long: []string{
+			"Creates an invitation file like /invite, to be sent to the imported contact with the given id through the platform the contact was imported from. The user that accepts the invite is recorded as the user of the contact.",
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 1 {
+				return fileCompleter(arg)
+			}
+			if len(args) == 2 {
+				return gcCompleter(arg, as)
+			}
+			return nil
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 || args[0] == "" {
+				return usageError{msg: "contact id must be specified"}
+			}
+			if len(args) < 2 {
+				return usageError{msg: "filename must be specified"}
+			}
+			contacts, err := as.c.ListImportedContacts()
+			if err != nil {
+				return err
+			}
+			i := slices.IndexFunc(contacts, func(ic clientdb.ImportedContact) bool {
+				return ic.ID == args[0]
+			})
+			if i < 0 {
+				return fmt.Errorf("imported contact %q not found", args[0])
+			}
+			if contacts[i].Joined != nil {
+				return fmt.Errorf("imported contact %q already joined",
+					args[0])
+			}
+
+			filename, err := homedir.Expand(args[1])
+			if err != nil {
+				return err
+			}
+			var gcID zkidentity.ShortID
+			if len(args) > 2 && len(args[2]) > 0 {
+				gcID, err = as.c.GCIDByName(args[2])
+				if err != nil {
+					return err
+				}
+				if _, err := as.c.GetGC(gcID); err != nil {
+					return err
+				}
+			}
+
+			go as.writeInvite(filename, gcID, nil, "", args[0])
+			return nil
+		},
+	}, {
+		cmd:           "remove",
+		aliases:       []string{"rm"},
+		usableOffline: true,
+		usage:         "<id>",
+		descr:         "Remove an imported contact",
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 || args[0] == "" {
+				return usageError{msg: "contact id must be specified"}
+			}
+			if err := as.c.RemoveImportedContact(args[0]); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Removed imported contact %s", args[0])
+			return nil
+		},
+	},
+}
+
+var filterCommands = []tuicmd{
+	{
+		cmd:           "list",
+		usableOffline: true,
+		aliases:       []string{"ls"},
+		descr:         "Lists content-based filters",
+		handler: func(args []string, as *appState) error {
+			filters := as.c.ListContentFilters()
+			if len(filters) == 0 {
+				as.cwHelpMsg("Client has no content-based filters")
+				return nil
+			}
+
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Content filters (%d total)", len(filters))
+				for _, cf := range filters {
+					var s string
+					if !cf.SkipPMs {
+						s += "PMs, "
+					}
+					if !cf.SkipGCMs {
+						s += "GCMs"
+						if cf.GC != nil && !cf.GC.IsEmpty() {
+							gc, _ := as.c.GetGCAlias(*cf.GC)
+							s += fmt.Sprintf(" gc=%s", strescape.Nick(gc))
+						}
+						s += ", "
+
+					}
+					if !cf.SkipPosts {
+						s += "posts, "
+					}
+					if !cf.SkipPostComments {
+						s += "post comments, "
+					}
+					if cf.UID != nil {
+						nick, _ := as.c.UserNick(*cf.UID)
+						s += fmt.Sprintf("user=%s, ", strescape.Nick(nick))
+					}
+
+					s += fmt.Sprintf("regexp=\"%s\"", cf.Regexp)
+					pf("%08d - %s", cf.ID, s)
+				}
+			})
+
+			return nil
+		},
+	}, {
+		cmd:           "add",
+		usableOffline: true,
+		descr:         "Add a simple, case-insensitive content filter",
+		usage:         "[filter]",
+		long: []string{
+			"Adding a filter through this command filters content from ",
+			"all users on all contexts. Use the 'addrule' command for ",
+			"specifying more complex rules.",
+		},
+		rawHandler: func(rawCmd string, args []string, as *appState) error {
+			_, expr := popNArgs(rawCmd, 2) // cmd+subcmd
+			if len(expr) == 0 {
+				return usageError{"filter expression cannot be empty"}
+			}
+
+			// Add the case-insensitive flag.
+			expr = "(?i)" + regexp.QuoteMeta(expr)
+
+			cf := &clientdb.ContentFilter{Regexp: expr}
+			err := as.c.StoreContentFilter(cf)
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsg("Added content filter rule %d", cf.ID)
+			return nil
+		},
+	}, {
+		cmd:           "addrule",
+		usableOffline: true,
+		descr:         "Add a content-based filter",
+		usage:         "[user=<user>] [gc=<gc> | noGC] [noPost] [noPC] [noPM] [--] [regexp]",
+		long: []string{
+			"Content-based filters drop received messages before they are ",
+			"presented to the user.",
+			"",
+			"If an incoming message matches any of the existing filters, ",
+			"the message is dropped.",
+			"",
+			"By default, filters apply to all messages, received from any ",
+			"user and on all contexts (PMs, GCMs, posts and post comments). ",
+			"Some attributes allow refining when the filter applies: ",
+			"",
+			"  - user=<user>: only test filter if the message is from a specific user",
+			"  - gc=<gc>: only test filter if GCM is in a specific GC",
+			"  - noGC: do not apply filter for messages in GCs",
+			"  - noPost: do not apply filter for post conent",
+			"  - noPC: do not apply filter for post comments",
+			"  - noPM: do not apply filter for PMs",
+			"",
+			"Everything after the last valid option or after a literal '--'",
+			"is considered part of the regexp.",
+			"",
+			"The regexp follows Go's regexp package rules. As a reminder, ",
+			"it is case-sensitive by default, unless it is started with ",
 			"the '(?i)' flag.",
 			"",
 			"Examples of filters:",
@@ -2665,210 +3837,937 @@ var filterCommands = []tuicmd{
 			"Use the /filter test* commands to test if the setup filters work as ",
 			"needed.",
 		},
-		rawHandler: func(rawCmd string, args []string, as *appState) error {
+		rawHandler: func(rawCmd string, args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "filter cannot be empty"}
+			}
+
+			var cf clientdb.ContentFilter
+			nargs := 2 // cmd+subcmd
+		loopArgs:
+			for _, arg := range args {
+				switch {
+				case strings.HasPrefix(arg, "user="):
+					nick := arg[5:]
+					uid, err := as.c.UIDByNick(nick)
+					if err != nil {
+						return err
+					}
+					cf.UID = &uid
+					nargs += 1
+
+				case strings.HasPrefix(arg, "gc="):
+					name := arg[3:]
+					gc, err := as.c.GCIDByName(name)
+					if err != nil {
+						return err
+					}
+					cf.GC = &gc
+					nargs += 1
+
+				case strings.EqualFold(arg, "noPost"):
+					cf.SkipPosts = true
+					nargs += 1
+
+				case strings.EqualFold(arg, "noPC"):
+					cf.SkipPostComments = true
+					nargs += 1
+
+				case strings.EqualFold(arg, "noGC"):
+					cf.SkipGCMs = true
+					nargs += 1
+
+				case strings.EqualFold(arg, "noPM"):
+					cf.SkipPMs = true
+					nargs += 1
+
+				case arg == "--":
+					nargs += 1
+					break loopArgs
+
+				default:
+					break loopArgs
+				}
+			}
+
+			_, cf.Regexp = popNArgs(rawCmd, nargs)
+			if cf.Regexp == "" {
+				return usageError{msg: "regexp cannot be empty"}
+			}
+
+			err := as.c.StoreContentFilter(&cf)
+			if err != nil {
+				return err
+			}
+
+			as.cwHelpMsg("Added content filter rule %d", cf.ID)
+			return nil
+		},
+	}, {
+		cmd:     "del",
+		aliases: []string{"delete", "remove", "rem"},
+		descr:   "Remove a filter rule",
+		usage:   "[id]",
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "rule number cannot be empty"}
+			}
+
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			if err := as.c.RemoveContentFilter(id); err != nil {
+				return fmt.Errorf("unable to remove rule %d: %v", id, err)
+			}
+
+			as.cwHelpMsg("Removed content filter rule %d", id)
+			return nil
+		},
+	}, {
+		cmd:           "testpm",
+		usableOffline: true,
+		descr:         "Test if a PM would be filtered",
+		usage:         "<user> <pm>",
+		rawHandler: func(rawCmd string, args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "user cannot be empty"}
+			}
+
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+
+			_, pm := popNArgs(rawCmd, 3) // cmd+subcmd+user
+			filter, id := as.c.FilterPM(uid, pm)
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Test message: %q", pm)
+				if !filter {
+					pf("Message would NOT be filtered")
+				} else {
+					pf("Message would be filtered by rule %d", id)
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "testgcm",
+		usableOffline: true,
+		descr:         "Test if a GC message would be filtered",
+		usage:         "<user> <gc> <pm>",
+		rawHandler: func(rawCmd string, args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "user cannot be empty"}
+			}
+			if len(args) < 2 {
+				return usageError{msg: "gc cannot be empty"}
+			}
+
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+			gc, err := as.c.GCIDByName(args[1])
+			if err != nil {
+				return err
+			}
+
+			_, gcm := popNArgs(rawCmd, 4) // cmd+subcmd+user+gc
+			filter, id := as.c.FilterGCM(uid, gc, gcm)
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Test message: %q", gcm)
+				if !filter {
+					pf("Message would NOT be filtered")
+				} else {
+					pf("Message would be filtered by rule %d", id)
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "testpost",
+		usableOffline: true,
+		descr:         "Test if a post would be filtered",
+		usage:         "<user> <post>",
+		rawHandler: func(rawCmd string, args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "user cannot be empty"}
+			}
+
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+
+			_, post := popNArgs(rawCmd, 3) // cmd+subcmd+user
+			filter, id := as.c.FilterPost(uid, clientintf.PostID{}, post)
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Test post: %q", post)
+				if !filter {
+					pf("Post would NOT be filtered")
+				} else {
+					pf("Post would be filtered by rule %d", id)
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "testpostcomment",
+		usableOffline: true,
+		descr:         "Test if a post comment would be filtered",
+		usage:         "<user> <post>",
+		rawHandler: func(rawCmd string, args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "user cannot be empty"}
+			}
+
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+
+			_, comment := popNArgs(rawCmd, 3) // cmd+subcmd+user
+			postFrom, pid := clientintf.UserID{0: 0x01}, clientintf.UserID{0: 0x02}
+			filter, id := as.c.FilterPostComment(uid, postFrom, pid, comment)
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Test comment: %q", comment)
+				if !filter {
+					pf("Comment would NOT be filtered")
+				} else {
+					pf("Comment would be filtered by rule %d", id)
+				}
+			})
+			return nil
+		},
+	},
+}
+
+// tipStatsPeriod parses the optional number of days of the period of the tip
+// stats commands.
+func tipStatsPeriod(args []string) (time.Time, error) {
+	days := 7
+	if len(args) > 0 {
+		var err error
+		if days, err = strconv.Atoi(args[0]); err != nil || days < 1 {
+			return time.Time{}, usageError{msg: "invalid number of days"}
+		}
+	}
+	return time.Now().Add(-time.Duration(days) * 24 * time.Hour), nil
+}
+
+var dedupCommands = []tuicmd{
+	{
+		cmd:           "list",
+		usableOffline: true,
+		descr:         "List the contacts that are likely duplicates",
+		long:          []string{"Lists the sets of contacts that have the same signature key, the same name and nick or nicks that only differ by case or by a '_<n>' suffix."},
+		handler: func(args []string, as *appState) error {
+			dups := as.c.FindDuplicateContacts()
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				if len(dups) == 0 {
+					pf("No duplicate contacts found")
+					return
+				}
+				pf("Duplicate contacts")
+				for _, dup := range dups {
+					nicks := make([]string, len(dup.UIDs))
+					for i, uid := range dup.UIDs {
+						nick, _ := as.c.UserNick(uid)
+						nicks[i] = fmt.Sprintf("%s (%s)",
+							strescape.Nick(nick), uid.ShortLogID())
+					}
+					pf("%s: %s", dup.Reason, strings.Join(nicks, ", "))
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "merge",
+		usableOffline: true,
+		usage:         "<canonical nick> <dup nick>... [gcs] [remove]",
+		descr:         "Merge duplicate contacts into one contact",
+		long: []string{"Merges the message history of the duplicates into the history of the canonical contact, makes the nicks of the duplicates aliases of the canonical contact and updates the GC block lists that reference the duplicates.",
+			"With 'gcs', the canonical contact is invited to the GCs administered by the local client where a duplicate is a member and the duplicate is removed from them.",
+			"With 'remove', the duplicates are removed from the address book (without blocking them). Otherwise, they are ignored."},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "canonical nick cannot be empty"}
+			}
+			canonical, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+			var opts client.MergeContactsOpts
+			var dups []clientintf.UserID
+			for _, arg := range args[1:] {
+				switch arg {
+				case "gcs":
+					opts.UpdateGCs = true
+				case "remove":
+					opts.Remove = true
+				default:
+					uid, err := as.c.UIDByNick(arg)
+					if err != nil {
+						return err
+					}
+					dups = append(dups, uid)
+				}
+			}
+			if len(dups) == 0 {
+				return usageError{msg: "duplicate nicks cannot be empty"}
+			}
+
+			go func() {
+				err := as.c.MergeContacts(canonical, dups, opts)
+				if err != nil {
+					as.cwHelpMsg("Unable to merge contacts: %v", err)
+					return
+				}
+				as.cwHelpMsg("Merged %d contacts into %s", len(dups),
+					strescape.Nick(args[0]))
+			}()
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			return nickCompleter(arg, as)
+		},
+	},
+}
+
+var guestViewCommands = []tuicmd{
+	{
+		cmd:   "create",
+		usage: "<duration> [<path/to/page>...]",
+		descr: "Publish pages for guests that did not KX with the local client",
+		long: []string{"Publishes the pages (and the pages they link to) of the local client to the server, encrypted with a new guest view token. Users that receive the token may view the pages with '/guestview open', without KXing with the local client, until the token expires (for example, after '24h').",
+			"If no pages are specified, the index page is published. Pages are rendered for an anonymous user, so they do not include admin sections.",
+			"Only servers that advertise support for hosting published pages accept them."},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "duration cannot be empty"}
+			}
+			lifetime, err := time.ParseDuration(args[0])
+			if err != nil {
+				return usageError{msg: fmt.Sprintf("invalid duration: %v", err)}
+			}
+			var pages [][]string
+			for _, arg := range args[1:] {
+				pages = append(pages, strings.Split(strings.Trim(arg, "/"), "/"))
+			}
+			go func() {
+				token, gv, err := as.c.CreateGuestView(as.ctx, lifetime, pages)
+				if err != nil {
+					as.cwHelpMsg("Unable to create guest view: %v", err)
+					return
+				}
+				as.cwHelpMsgs(func(pf printf) {
+					pf("")
+					pf("Created guest view %s with %d pages, valid until %s",
+						token.ID, len(gv.Pages),
+						token.Expires.Format(ISO8601DateTime))
+					pf("Share the following token with guests:")
+					pf("%s", token)
+				})
+			}()
+			return nil
+		},
+	}, {
+		cmd:           "list",
+		usableOffline: true,
+		descr:         "List the guest views created by the local client",
+		handler: func(args []string, as *appState) error {
+			views, err := as.c.ListGuestViews()
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Guest views (%d)", len(views))
+				for _, gv := range views {
+					pages := make([]string, len(gv.Pages))
+					for i, page := range gv.Pages {
+						pages[i] = strings.Join(page, "/")
+					}
+					expired := ""
+					if gv.Token.Expired() {
+						expired = " (expired)"
+					}
+					pf("%s - until %s%s - %s", gv.Token.ID,
+						gv.Token.Expires.Format(ISO8601DateTime),
+						expired, strescape.Content(strings.Join(pages, ", ")))
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:   "revoke",
+		usage: "<id>",
+		descr: "Remove the pages of a guest view from the server",
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "guest view id cannot be empty"}
+			}
+			var id clientintf.GuestViewID
+			if err := id.FromString(args[0]); err != nil {
+				return err
+			}
+			go func() {
+				if err := as.c.RevokeGuestView(as.ctx, id); err != nil {
+					as.cwHelpMsg("Unable to revoke guest view: %v", err)
+					return
+				}
+				as.cwHelpMsg("Revoked guest view %s", id)
+			}()
+			return nil
+		},
+	}, {
+		cmd:   "open",
+		usage: "<token> [<path/to/page>]",
+		descr: "View a page of a guest view shared by another user",
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "token cannot be empty"}
+			}
+			token, err := clientintf.DecodeGuestViewToken(args[0])
+			if err != nil {
+				return err
+			}
+			var page []string
+			if len(args) > 1 {
+				page = strings.Split(strings.Trim(args[1], "/"), "/")
+			}
+			go func() {
+				data, err := as.c.FetchGuestView(as.ctx, token, page)
+				if err != nil {
+					as.cwHelpMsg("Unable to fetch guest view page: %v", err)
+					return
+				}
+				as.cwHelpMsgs(func(pf printf) {
+					pf("")
+					pf("Guest view page of user %s (signature verified)",
+						token.Owner)
+					for _, line := range strings.Split(string(data), "\n") {
+						pf("%s", strescape.Content(line))
+					}
+				})
+			}()
+			return nil
+		},
+	},
+}
+
+var summaryCommands = []tuicmd{
+	{
+		cmd:           "post",
+		usableOffline: true,
+		usage:         "<nick> <post id> [refresh]",
+		descr:         "Show the summary of a post",
+		long:          []string{"Shows the stored summary of the post received from the user, summarizing the post if it was not summarized yet or if 'refresh' is specified."},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "nick cannot be empty"}
+			}
+			if len(args) < 2 {
+				return usageError{msg: "post id cannot be empty"}
+			}
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+			var pid clientintf.PostID
+			if err := pid.FromString(args[1]); err != nil {
+				return err
+			}
+			refresh := len(args) > 2 && args[2] == "refresh"
+
+			go func() {
+				summ, err := as.c.PostSummary(uid, pid)
+				if refresh || errors.Is(err, clientdb.ErrNotFound) {
+					as.cwHelpMsg("Summarizing post %s", pid)
+					summ, err = as.c.SummarizePost(uid, pid)
+				}
+				if err != nil {
+					as.cwHelpMsg("Unable to summarize post: %v", err)
+					return
+				}
+				as.cwHelpMsgs(func(pf printf) {
+					pf("")
+					pf("Summary of post %s (by %s at %s)", pid,
+						summ.Summarizer,
+						summ.Created.Format(ISO8601DateTime))
+					for _, line := range strings.Split(summ.Summary, "\n") {
+						pf("%s", strescape.Content(line))
+					}
+				})
+			}()
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return nickCompleter(arg, as)
+			}
+			return nil
+		},
+	}, {
+		cmd:           "gc",
+		usableOffline: true,
+		usage:         "<gc> [<nb msgs>]",
+		descr:         "Summarize the latest messages of a GC",
+		long:          []string{"Summarizes the latest messages (by default, up to 200) of the GC. The summary is shown in the GC window."},
+		handler: func(args []string, as *appState) error {
 			if len(args) < 1 {
-				return usageError{msg: "filter cannot be empty"}
+				return usageError{msg: "gc cannot be empty"}
+			}
+			gcID, err := as.c.GCIDByName(args[0])
+			if err != nil {
+				return err
+			}
+			var nbMsgs int
+			if len(args) > 1 {
+				nbMsgs, err = strconv.Atoi(args[1])
+				if err != nil {
+					return usageError{msg: fmt.Sprintf("invalid nb of messages: %v", err)}
+				}
 			}
 
-			var cf clientdb.ContentFilter
-			nargs := 2 // cmd+subcmd
-		loopArgs:
-			for _, arg := range args {
-				switch {
-				case strings.HasPrefix(arg, "user="):
-					nick := arg[5:]
-					uid, err := as.c.UIDByNick(nick)
-					if err != nil {
-						return err
-					}
-					cf.UID = &uid
-					nargs += 1
+			as.cwHelpMsg("Summarizing messages of GC %s", args[0])
+			go func() {
+				// The summary is shown when the summarized ntfn
+				// is received.
+				_, err := as.c.SummarizeGCBacklog(gcID, nbMsgs)
+				if err != nil {
+					as.cwHelpMsg("Unable to summarize GC: %v", err)
+				}
+			}()
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return gcCompleter(arg, as)
+			}
+			return nil
+		},
+	},
+}
 
-				case strings.HasPrefix(arg, "gc="):
-					name := arg[3:]
-					gc, err := as.c.GCIDByName(name)
-					if err != nil {
-						return err
+var tipStatsCommands = []tuicmd{
+	{
+		cmd:           "enable",
+		usableOffline: true,
+		descr:         "Start recording the tips received",
+		handler: func(args []string, as *appState) error {
+			cfg, err := as.c.TipStatsConfig()
+			if err != nil {
+				return err
+			}
+			cfg.Enabled = true
+			if err := as.c.SetTipStatsConfig(*cfg); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Recording tips received")
+			return nil
+		},
+	}, {
+		cmd:           "disable",
+		usableOffline: true,
+		descr:         "Stop recording the tips received",
+		handler: func(args []string, as *appState) error {
+			cfg, err := as.c.TipStatsConfig()
+			if err != nil {
+				return err
+			}
+			cfg.Enabled = false
+			if err := as.c.SetTipStatsConfig(*cfg); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Stopped recording tips received")
+			return nil
+		},
+	}, {
+		cmd:           "leaderboard",
+		usableOffline: true,
+		usage:         "[<days>]",
+		descr:         "List the tippers and tipped posts of the last days",
+		long:          []string{"Lists the users that sent tips and the posts that received tips in the last days (by default, 7). Only tips received while recording tips is enabled are listed."},
+		handler: func(args []string, as *appState) error {
+			since, err := tipStatsPeriod(args)
+			if err != nil {
+				return err
+			}
+			lb, err := as.c.TipLeaderboard(since, time.Time{})
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Tips received since %s: %d tips, %.8f DCR",
+					since.Format(ISO8601DateTime), lb.Count,
+					float64(lb.MilliAtoms)/1e11)
+				for i, tl := range lb.Tippers {
+					public := ""
+					if tl.Public {
+						public = " (public)"
 					}
-					cf.GC = &gc
-					nargs += 1
-
-				case strings.EqualFold(arg, "noPost"):
-					cf.SkipPosts = true
-					nargs += 1
-
-				case strings.EqualFold(arg, "noPC"):
-					cf.SkipPostComments = true
-					nargs += 1
-
-				case strings.EqualFold(arg, "noGC"):
-					cf.SkipGCMs = true
-					nargs += 1
-
-				case strings.EqualFold(arg, "noPM"):
-					cf.SkipPMs = true
-					nargs += 1
-
-				case arg == "--":
-					nargs += 1
-					break loopArgs
-
-				default:
-					break loopArgs
+					pf("%3d. %12.8f %3d tips - %s %s%s", i+1,
+						float64(tl.MilliAtoms)/1e11, tl.Count,
+						strescape.Nick(tl.Nick),
+						as.styles.help.Render(tl.UID.String()),
+						public)
+				}
+				if len(lb.Posts) > 0 {
+					pf("Tipped posts")
+					for _, pt := range lb.Posts {
+						pf("     %12.8f %3d tips - %s %s",
+							float64(pt.MilliAtoms)/1e11,
+							pt.Count, strescape.Content(pt.Title),
+							as.styles.help.Render(pt.Post.String()))
+					}
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "thanks",
+		usableOffline: true,
+		usage:         "[<days>] [send]",
+		descr:         "Show or send a thank-you summary of the tips of the last days",
+		long:          []string{"Shows the thank-you summary of the tips received in the last days (by default, 7). Specify 'send' to publish it as configured with /tipstats autothanks (as a post and/or in GCs).", "Only tippers that consented to it are named, and only when named summaries are enabled with /tipstats autothanks."},
+		handler: func(args []string, as *appState) error {
+			send := len(args) > 0 && args[len(args)-1] == "send"
+			if send {
+				args = args[:len(args)-1]
+			}
+			since, err := tipStatsPeriod(args)
+			if err != nil {
+				return err
+			}
+			if send {
+				msg, err := as.c.SendTipThankYou(since, time.Now())
+				if err != nil {
+					return err
+				}
+				if msg == "" {
+					as.cwHelpMsg("No tips received in the period")
+				} else {
+					as.cwHelpMsg("Sent thank-you summary of tips")
 				}
+				return nil
 			}
 
-			_, cf.Regexp = popNArgs(rawCmd, nargs)
-			if cf.Regexp == "" {
-				return usageError{msg: "regexp cannot be empty"}
+			cfg, err := as.c.TipStatsConfig()
+			if err != nil {
+				return err
 			}
-
-			err := as.c.StoreContentFilter(&cf)
+			lb, err := as.c.TipLeaderboard(since, time.Time{})
 			if err != nil {
 				return err
 			}
-
-			as.cwHelpMsg("Added content filter rule %d", cf.ID)
+			summary := lb.ThankYouSummary(cfg.AckNamed)
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				for _, line := range strings.Split(summary, "\n") {
+					pf("%s", line)
+				}
+			})
 			return nil
 		},
 	}, {
-		cmd:     "del",
-		aliases: []string{"delete", "remove", "rem"},
-		descr:   "Remove a filter rule",
-		usage:   "[id]",
+		cmd:           "autothanks",
+		usableOffline: true,
+		usage:         "<interval | off> [post] [named] [<gc>...]",
+		descr:         "Periodically send thank-you summaries of tips",
+		long:          []string{"Sends a thank-you summary of the tips received every interval (for example, 168h for weekly summaries). Specify 'post' to publish the summaries as posts and the GCs where to send them. Specify 'named' to name the tippers that consented to it (others are always anonymized).", "Specify 'off' to disable the summaries."},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) > 0 {
+				return gcCompleter(arg, as)
+			}
+			return nil
+		},
 		handler: func(args []string, as *appState) error {
 			if len(args) < 1 {
-				return usageError{msg: "rule number cannot be empty"}
+				return usageError{msg: "interval cannot be empty"}
 			}
-
-			id, err := strconv.ParseUint(args[0], 10, 64)
+			cfg, err := as.c.TipStatsConfig()
 			if err != nil {
 				return err
 			}
+			if args[0] == "off" {
+				cfg.AckInterval = 0
+				if err := as.c.SetTipStatsConfig(*cfg); err != nil {
+					return err
+				}
+				as.cwHelpMsg("Disabled periodic thank-you summaries of tips")
+				return nil
+			}
 
-			if err := as.c.RemoveContentFilter(id); err != nil {
-				return fmt.Errorf("unable to remove rule %d: %v", id, err)
+			interval, err := time.ParseDuration(args[0])
+			if err != nil || interval <= 0 {
+				return usageError{msg: "invalid interval"}
+			}
+			cfg.AckInterval = interval
+			cfg.AckPost, cfg.AckNamed, cfg.AckGCs = false, false, nil
+			for _, arg := range args[1:] {
+				switch arg {
+				case "post":
+					cfg.AckPost = true
+				case "named":
+					cfg.AckNamed = true
+				default:
+					gcID, err := as.c.GCIDByName(arg)
+					if err != nil {
+						return err
+					}
+					cfg.AckGCs = append(cfg.AckGCs, gcID)
+				}
+			}
+			if !cfg.AckPost && len(cfg.AckGCs) == 0 {
+				return usageError{msg: "specify 'post' and/or the GCs where to send the summaries"}
+			}
+			cfg.Enabled = true
+			if err := as.c.SetTipStatsConfig(*cfg); err != nil {
+				return err
 			}
+			as.cwHelpMsg("Sending thank-you summaries of tips every %s",
+				interval)
+			return nil
+		},
+	},
+}
 
-			as.cwHelpMsg("Removed content filter rule %d", id)
+var queryCommands = []tuicmd{
+	{
+		cmd:           "save",
+		usableOffline: true,
+		usage:         "<name> <kind> <field=value>...",
+		descr:         "Save a named query over the local messages or store orders",
+		long: []string{
+			"Saves a query over the logged messages (kind 'messages') or the orders of the simple store (kind 'orders'), replacing any query with the same name.",
+			"Messages are queried with the fields from=<nick>, gc=<gc>, contains=<text> and days=<nb days>.",
+			"Orders are queried with the fields status=<status,...>, from=<date>, to=<date>, days=<nb days>, user=<id>, sku=<sku>, currency=<code> and mintotal=<amount> (in the currency of the store, unless currency is specified).",
+			"Example: /query save invoices messages from=alice contains=invoice days=30",
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "name cannot be empty"}
+			}
+			if len(args) < 2 {
+				return usageError{msg: "kind cannot be empty"}
+			}
+			if err := as.c.SaveQuery(args[0], args[1], args[2:]); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Saved query %q", args[0])
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 1 {
+				return cmdCompleter([]tuicmd{{cmd: client.SavedQueryMessages},
+					{cmd: client.SavedQueryOrders}}, arg, false)
+			}
 			return nil
 		},
 	}, {
-		cmd:           "testpm",
+		cmd:           "list",
+		aliases:       []string{"ls"},
 		usableOffline: true,
-		descr:         "Test if a PM would be filtered",
-		usage:         "<user> <pm>",
-		rawHandler: func(rawCmd string, args []string, as *appState) error {
+		descr:         "List the saved queries",
+		handler: func(args []string, as *appState) error {
+			queries, err := as.c.SavedQueries()
+			if err != nil {
+				return err
+			}
+			as.cwHelpMsgs(func(pf printf) {
+				if len(queries) == 0 {
+					pf("No saved queries")
+				}
+				for _, q := range queries {
+					pf("%s - %s - %s", q.Name, q.Kind,
+						strescape.Content(strings.Join(q.Terms, " ")))
+				}
+			})
+			return nil
+		},
+	}, {
+		cmd:           "run",
+		usableOffline: true,
+		usage:         "<name>",
+		descr:         "Run a saved query",
+		handler: func(args []string, as *appState) error {
 			if len(args) < 1 {
-				return usageError{msg: "user cannot be empty"}
+				return usageError{msg: "name cannot be empty"}
 			}
-
-			uid, err := as.c.UIDByNick(args[0])
+			res, err := as.c.RunSavedQuery(args[0])
 			if err != nil {
 				return err
 			}
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("%d results of query %q", len(res), args[0])
+				for _, r := range res {
+					pf("%s - %s - %s",
+						r.Timestamp.Format(ISO8601DateTime),
+						strescape.Content(r.Source),
+						strescape.Content(r.Text))
+				}
+			})
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return savedQueryCompleter(arg, as)
+			}
+			return nil
+		},
+	}, {
+		cmd:           "del",
+		aliases:       []string{"rm"},
+		usableOffline: true,
+		usage:         "<name>",
+		descr:         "Remove a saved query",
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "name cannot be empty"}
+			}
+			if err := as.c.RemoveSavedQuery(args[0]); err != nil {
+				return err
+			}
+			as.cwHelpMsg("Removed query %q", args[0])
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return savedQueryCompleter(arg, as)
+			}
+			return nil
+		},
+	},
+}
+
+// savedQueryCompleter completes the names of the saved queries.
+func savedQueryCompleter(arg string, as *appState) []string {
+	queries, err := as.c.SavedQueries()
+	if err != nil {
+		return nil
+	}
+	var res []string
+	for _, q := range queries {
+		if strings.HasPrefix(q.Name, arg) {
+			res = append(res, q.Name)
+		}
+	}
+	return res
+}
 
-			_, pm := popNArgs(rawCmd, 3) // cmd+subcmd+user
-			filter, id := as.c.FilterPM(uid, pm)
+var payApprovalCommands = []tuicmd{
+	{
+		cmd:           "list",
+		aliases:       []string{"ls"},
+		usableOffline: true,
+		descr:         "List the outgoing payments waiting for approval",
+		handler: func(args []string, as *appState) error {
+			approvals := as.c.PendingPaymentApprovals()
 			as.cwHelpMsgs(func(pf printf) {
-				pf("")
-				pf("Test message: %q", pm)
-				if !filter {
-					pf("Message would NOT be filtered")
-				} else {
-					pf("Message would be filtered by rule %d", id)
+				if len(approvals) == 0 {
+					pf("No payments waiting for approval")
+				}
+				for _, a := range approvals {
+					dest := ""
+					if a.UID != nil {
+						nick, _ := as.c.UserNick(*a.UID)
+						dest = " to " + strescape.Nick(nick)
+					}
+					pf("%d - %s - %s payment of %.8f DCR%s",
+						a.ID, a.Requested.Format(ISO8601DateTime),
+						a.Category, float64(a.MAtoms)/1e11, dest)
 				}
 			})
 			return nil
 		},
 	}, {
-		cmd:           "testgcm",
+		cmd:           "approve",
 		usableOffline: true,
-		descr:         "Test if a GC message would be filtered",
-		usage:         "<user> <gc> <pm>",
-		rawHandler: func(rawCmd string, args []string, as *appState) error {
+		usage:         "<id>",
+		descr:         "Approve an outgoing payment waiting for approval",
+		handler: func(args []string, as *appState) error {
 			if len(args) < 1 {
-				return usageError{msg: "user cannot be empty"}
-			}
-			if len(args) < 2 {
-				return usageError{msg: "gc cannot be empty"}
+				return usageError{msg: "id cannot be empty"}
 			}
-
-			uid, err := as.c.UIDByNick(args[0])
+			id, err := strconv.ParseUint(args[0], 10, 64)
 			if err != nil {
-				return err
+				return usageError{msg: fmt.Sprintf("invalid id: %v", err)}
 			}
-			gc, err := as.c.GCIDByName(args[1])
-			if err != nil {
+			if err := as.c.ApprovePayment(id); err != nil {
 				return err
 			}
-
-			_, gcm := popNArgs(rawCmd, 4) // cmd+subcmd+user+gc
-			filter, id := as.c.FilterGCM(uid, gc, gcm)
-			as.cwHelpMsgs(func(pf printf) {
-				pf("")
-				pf("Test message: %q", gcm)
-				if !filter {
-					pf("Message would NOT be filtered")
-				} else {
-					pf("Message would be filtered by rule %d", id)
-				}
-			})
+			as.cwHelpMsg("Approved payment %d", id)
 			return nil
 		},
 	}, {
-		cmd:           "testpost",
+		cmd:           "deny",
 		usableOffline: true,
-		descr:         "Test if a post would be filtered",
-		usage:         "<user> <post>",
-		rawHandler: func(rawCmd string, args []string, as *appState) error {
+		usage:         "<id> [<reason>]",
+		descr:         "Deny an outgoing payment waiting for approval",
+		handler: func(args []string, as *appState) error {
 			if len(args) < 1 {
-				return usageError{msg: "user cannot be empty"}
+				return usageError{msg: "id cannot be empty"}
 			}
-
-			uid, err := as.c.UIDByNick(args[0])
+			id, err := strconv.ParseUint(args[0], 10, 64)
 			if err != nil {
+				return usageError{msg: fmt.Sprintf("invalid id: %v", err)}
+			}
+			reason := strings.Join(args[1:], " ")
+			if err := as.c.DenyPayment(id, reason); err != nil {
 				return err
 			}
-
-			_, post := popNArgs(rawCmd, 3) // cmd+subcmd+user
-			filter, id := as.c.FilterPost(uid, clientintf.PostID{}, post)
-			as.cwHelpMsgs(func(pf printf) {
-				pf("")
-				pf("Test post: %q", post)
-				if !filter {
-					pf("Post would NOT be filtered")
-				} else {
-					pf("Post would be filtered by rule %d", id)
-				}
-			})
+			as.cwHelpMsg("Denied payment %d", id)
 			return nil
 		},
 	}, {
-		cmd:           "testpostcomment",
+		cmd:           "audit",
 		usableOffline: true,
-		descr:         "Test if a post comment would be filtered",
-		usage:         "<user> <post>",
-		rawHandler: func(rawCmd string, args []string, as *appState) error {
-			if len(args) < 1 {
-				return usageError{msg: "user cannot be empty"}
+		usage:         "[<nb entries>]",
+		descr:         "Show the latest entries (by default, 20) of the audit log of outgoing payments",
+		handler: func(args []string, as *appState) error {
+			nb := 20
+			if len(args) > 0 {
+				var err error
+				nb, err = strconv.Atoi(args[0])
+				if err != nil {
+					return usageError{msg: fmt.Sprintf("invalid nb of entries: %v", err)}
+				}
 			}
-
-			uid, err := as.c.UIDByNick(args[0])
+			entries, err := as.c.PaymentAuditLog()
 			if err != nil {
 				return err
 			}
-
-			_, comment := popNArgs(rawCmd, 3) // cmd+subcmd+user
-			postFrom, pid := clientintf.UserID{0: 0x01}, clientintf.UserID{0: 0x02}
-			filter, id := as.c.FilterPostComment(uid, postFrom, pid, comment)
+			if nb >= 0 && len(entries) > nb {
+				entries = entries[len(entries)-nb:]
+			}
 			as.cwHelpMsgs(func(pf printf) {
-				pf("")
-				pf("Test comment: %q", comment)
-				if !filter {
-					pf("Comment would NOT be filtered")
-				} else {
-					pf("Comment would be filtered by rule %d", id)
+				if len(entries) == 0 {
+					pf("No entries in the payment audit log")
+				}
+				for _, e := range entries {
+					dest := ""
+					if e.UID != nil {
+						nick, _ := as.c.UserNick(*e.UID)
+						dest = " to " + strescape.Nick(nick)
+					}
+					reason := ""
+					if e.Reason != "" {
+						reason = " (" + strescape.Content(e.Reason) + ")"
+					}
+					pf("%s - %s payment of %.8f DCR%s - %s%s",
+						e.Decided.Format(ISO8601DateTime), e.Category,
+						float64(e.MAtoms)/1e11, dest, e.Decision,
+						reason)
 				}
 			})
 			return nil
@@ -2895,6 +4794,37 @@ var commands = []tuicmd{
 			as.log.Infof("Successfully backed up to %v", backupFile)
 			return nil
 		},
+	}, {
+		cmd:           "compactdb",
+		usableOffline: true,
+		descr:         "Compact the client storage and report the reclaimed space",
+		long: []string{"Drops the redundant entries of the message logs, prunes the chunks of completed or canceled downloads and rewrites the append-only JSON stores without their corrupted entries.",
+			"The client may keep running while the storage is compacted."},
+		handler: func(args []string, as *appState) error {
+			go func() {
+				res, err := as.c.CompactStorage(as.ctx)
+				if err != nil {
+					as.diagMsg("Unable to compact storage: %v", err)
+					return
+				}
+				for _, stage := range []clientdb.CompactionStage{
+					clientdb.CompactionStageLogs,
+					clientdb.CompactionStageChunks,
+					clientdb.CompactionStageJSON,
+				} {
+					as.diagMsg("Compacted %d %s targets, reclaimed %s",
+						res.Compacted[stage], stage,
+						hbytes(res.Reclaimed[stage]))
+				}
+				if res.Failed > 0 {
+					as.diagMsg("Failed to compact %d targets (see logs)",
+						res.Failed)
+				}
+				as.diagMsg("Storage compaction done: reclaimed %s",
+					hbytes(res.TotalReclaimed()))
+			}()
+			return nil
+		},
 	}, {
 		cmd:           "online",
 		usableOffline: true,
@@ -2943,6 +4873,56 @@ var commands = []tuicmd{
 			as.log.Infof("Modified log level to: %q", args[0])
 			return nil
 		},
+	}, {
+		cmd:   "guestview",
+		usage: "[sub]",
+		descr: "Share read-only views of the local pages with users that did not KX",
+		sub:   guestViewCommands,
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return cmdCompleter(guestViewCommands, arg, false)
+			}
+			return nil
+		},
+		handler: subcmdNeededHandler,
+	}, {
+		cmd:   "dedup",
+		usage: "[sub]",
+		descr: "Find and merge duplicate contacts",
+		sub:   dedupCommands,
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return cmdCompleter(dedupCommands, arg, false)
+			}
+			return nil
+		},
+		handler: subcmdNeededHandler,
+	}, {
+		cmd:           "query",
+		usableOffline: true,
+		usage:         "[sub]",
+		descr:         "Manage and run named queries over the local messages and store orders",
+		sub:           queryCommands,
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return cmdCompleter(queryCommands, arg, false)
+			}
+			return nil
+		},
+		handler: subcmdNeededHandler,
+	}, {
+		cmd:           "payapproval",
+		usableOffline: true,
+		usage:         "[sub]",
+		descr:         "Approve or deny outgoing payments held in the payment audit mode",
+		sub:           payApprovalCommands,
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return cmdCompleter(payApprovalCommands, arg, false)
+			}
+			return nil
+		},
+		handler: subcmdNeededHandler,
 	}, {
 		cmd:           "ignore",
 		usableOffline: true,
@@ -2986,6 +4966,61 @@ var commands = []tuicmd{
 			}
 			return nil
 		},
+	}, {
+		cmd:           "trust",
+		usableOffline: true,
+		usage:         "<nick> [new | known | trusted | auto]",
+		descr:         "View or set the trust tier of a user",
+		long: []string{
+			"The trust tier gates the defaults of features such as automatic downloads, resource requests rate limits, KX mediation and access to pages and store.",
+			"Setting the tier to 'auto' makes the tier be determined automatically by how long ago the user was first KX'd with.",
+			"Tiers only have an effect when enabled in the [trusttiers] section of the config file.",
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "nick cannot be empty"}
+			}
+			uid, err := as.c.UIDByNick(args[0])
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 1 {
+				tier := client.TrustTier(args[1])
+				if tier == "auto" {
+					tier = ""
+				}
+				if err := as.c.SetUserTrustTier(uid, tier); err != nil {
+					return err
+				}
+			}
+
+			tier, explicit, err := as.c.UserTrustTier(uid)
+			if err != nil {
+				return err
+			}
+			if explicit {
+				as.cwHelpMsg("Trust tier of %s: %s", args[0], tier)
+			} else {
+				as.cwHelpMsg("Trust tier of %s: %s (automatic)", args[0], tier)
+			}
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			switch len(args) {
+			case 0:
+				return nickCompleter(arg, as)
+			case 1:
+				var res []string
+				for _, t := range []string{"new", "known", "trusted", "auto"} {
+					if strings.HasPrefix(t, arg) {
+						res = append(res, t)
+					}
+				}
+				return res
+			}
+			return nil
+		},
 	}, {
 		cmd:   "block",
 		usage: "<nick>",
@@ -3070,7 +5105,7 @@ var commands = []tuicmd{
 				}
 			}
 
-			go as.writeInvite(filename, gcID, nil)
+			go as.writeInvite(filename, gcID, nil, "", "")
 			return nil
 		},
 		completer: func(args []string, arg string, as *appState) []string {
@@ -3131,6 +5166,11 @@ var commands = []tuicmd{
 				}
 			}
 
+			if amount < client.MinOnboardFundsAmount {
+				as.cwHelpMsg("Warning: invitees without funds need at least %s "+
+					"in the invite to onboard", client.MinOnboardFundsAmount)
+			}
+
 			funds, err := as.lnPC.CreateInviteFunds(as.ctx, amount, as.inviteFundsAccount)
 			if err != nil {
 				return err
@@ -3138,7 +5178,7 @@ var commands = []tuicmd{
 			as.cwHelpMsg("%s available for invitee after tx %s confirms",
 				amount, funds.Tx)
 
-			go as.writeInvite(filename, gcID, funds)
+			go as.writeInvite(filename, gcID, funds, "", "")
 			return nil
 		},
 		completer: func(args []string, arg string, as *appState) []string {
@@ -3150,6 +5190,84 @@ var commands = []tuicmd{
 			}
 			return nil
 		},
+	}, {
+		cmd:   "referralinvite",
+		usage: "<filename> <label> [<gcname>]",
+		descr: "Create invitation file tracked as a referral",
+		long: []string{
+			"Creates an invitation file like /invite, but records the user that accepts it as having joined through the referral with the given label.",
+			"Referral labels are local only and are not included in the invite file. Use /referrals to list the tracked referrals.",
+		},
+		handler: func(args []string, as *appState) error {
+			if len(args) < 1 {
+				return usageError{msg: "filename must be specified"}
+			}
+			if len(args) < 2 || args[1] == "" {
+				return usageError{msg: "label must be specified"}
+			}
+
+			filename, err := homedir.Expand(args[0])
+			if err != nil {
+				return err
+			}
+
+			var gcID zkidentity.ShortID
+			if len(args) > 2 && len(args[2]) > 0 {
+				gcID, err = as.c.GCIDByName(args[2])
+				if err != nil {
+					return err
+				}
+				if _, err := as.c.GetGC(gcID); err != nil {
+					return err
+				}
+			}
+
+			go as.writeInvite(filename, gcID, nil, args[1], "")
+			return nil
+		},
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return fileCompleter(arg)
+			}
+			if len(args) == 2 {
+				return gcCompleter(arg, as)
+			}
+			return nil
+		},
+	}, {
+		cmd:   "referrals",
+		descr: "List the invites tracked as referrals and the users that joined through them",
+		handler: func(args []string, as *appState) error {
+			referrals, err := as.c.ListReferrals()
+			if err != nil {
+				return err
+			}
+			if len(referrals) == 0 {
+				as.cwHelpMsg("No tracked referrals")
+				return nil
+			}
+
+			var joined int
+			as.cwHelpMsgs(func(pf printf) {
+				pf("")
+				pf("Tracked referrals")
+				for _, r := range referrals {
+					if r.Joined == nil {
+						pf("%s %q - not accepted yet",
+							r.Created.Format(ISO8601DateTime), r.Label)
+						continue
+					}
+					joined++
+					nick, _ := as.c.UserNick(*r.Joined)
+					pf("%s %q - joined by %s (%s) at %s",
+						r.Created.Format(ISO8601DateTime), r.Label,
+						strescape.Nick(nick), r.Joined,
+						r.JoinedTS.Format(ISO8601DateTime))
+				}
+				pf("%d of %d referrals joined", joined, len(referrals))
+			})
+			return nil
+		},
 	}, {
 		cmd:   "add",
 		usage: "<filename>",
@@ -3187,6 +5305,9 @@ var commands = []tuicmd{
 					pf("Invitation from peer includes funds")
 					pf("Nick: %q", pii.Public.Nick)
 					pf("UTXO: %s:%d", pii.Funds.Tx, pii.Funds.Index)
+					if pii.Funds.Amount > 0 {
+						pf("Amount: %s", dcrutil.Amount(pii.Funds.Amount))
+					}
 					pf("Type '/add %s ignorefunds' to add the invite anyway",
 						args[0])
 					pf("or '/redeeminvitefunds %s' to redeem the funds on-chain",
@@ -3476,9 +5597,10 @@ var commands = []tuicmd{
 		handler: subcmdNeededHandler,
 	}, {
 		cmd:   "paytip",
-		usage: "<nick or id> <dcr amount>",
+		usage: "<nick or id> <dcr amount> [post <post id>] [public]",
 		descr: "Send a tip with the given dcr amount to the user",
 		long: []string{
+			"Specify 'post' followed by the ID of a post of the user to tip that post. Specify 'public' to consent to being named by the user in their acknowledgments of tips.",
 			"Note: the tip is sent via LN, so the other peer only receives the tip if it is also online an connected to LN.",
 		},
 		handler: func(args []string, as *appState) error {
@@ -3499,7 +5621,24 @@ var commands = []tuicmd{
 				return err
 			}
 
-			go as.payTip(cw, dcrAmount)
+			var opts client.TipOptions
+			for i := 2; i < len(args); i++ {
+				switch {
+				case args[i] == "public":
+					opts.Public = true
+				case args[i] == "post" && i+1 < len(args):
+					var pid clientintf.PostID
+					if err := pid.FromString(args[i+1]); err != nil {
+						return fmt.Errorf("invalid post id: %v", err)
+					}
+					opts.Post = &pid
+					i += 1
+				default:
+					return usageError{msg: fmt.Sprintf("unknown argument %q", args[i])}
+				}
+			}
+
+			go as.payTip(cw, dcrAmount, opts)
 			return nil
 		},
 		completer: func(args []string, arg string, as *appState) []string {
@@ -3545,6 +5684,19 @@ var commands = []tuicmd{
 			return nil
 		},
 		handler: subcmdNeededHandler,
+	}, {
+		cmd:           "contacts",
+		usableOffline: true,
+		usage:         "[sub]",
+		descr:         "Import and invite contacts from other platforms",
+		sub:           contactCommands,
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return cmdCompleter(contactCommands, arg, false)
+			}
+			return nil
+		},
+		handler: subcmdNeededHandler,
 	}, {
 		cmd:           "filters",
 		usableOffline: true,
@@ -3558,6 +5710,20 @@ var commands = []tuicmd{
 			return nil
 		},
 		handler: subcmdNeededHandler,
+	}, {
+		cmd:           "plugins",
+		usableOffline: true,
+		aliases:       []string{"plugin"},
+		usage:         "[sub]",
+		descr:         "Plugin commands",
+		sub:           pluginCommands,
+		completer: func(args []string, arg string, as *appState) []string {
+			if len(args) == 0 {
+				return cmdCompleter(pluginCommands, arg, false)
+			}
+			return nil
+		},
+		handler: subcmdNeededHandler,
 	}, {
 		cmd:     "rreset",
 		aliases: []string{"rr", "ratchetreset"},
@@ -3873,8 +6039,8 @@ var commands = []tuicmd{
 		cmd:           "setexchangerate",
 		aliases:       []string{"setxchange"},
 		usableOffline: true,
-		usage:         "<USD/DCR> <USD/BTC>",
-		descr:         "Manually set the exchange rate of USD/DCR and USD/BTC",
+		usage:         "<USD/DCR> <USD/BTC> [<currency>]",
+		descr:         "Manually set the exchange rate of USD/DCR and USD/BTC (or of another fiat currency)",
 		handler: func(args []string, as *appState) error {
 			if len(args) < 1 {
 				return usageError{msg: "USD/DCR rate cannot be empty"}
@@ -3890,9 +6056,16 @@ var commands = []tuicmd{
 			if err != nil {
 				return fmt.Errorf("invalid USD/BTC rate: %v", err)
 			}
-			as.cwHelpMsg("Setting manual exchange rate: DCR:%0.2f BTC:%0.2f",
-				dcrPrice, btcPrice)
-			as.rates.Set(dcrPrice, btcPrice)
+			currency := rates.USD
+			if len(args) > 2 {
+				currency = strings.ToUpper(args[2])
+				if !rates.IsSupportedCurrency(currency) {
+					return fmt.Errorf("unsupported currency %q", args[2])
+				}
+			}
+			as.cwHelpMsg("Setting manual exchange rate: DCR:%0.2f BTC:%0.2f %s",
+				dcrPrice, btcPrice, currency)
+			as.rates.SetCurrency(currency, dcrPrice, btcPrice)
 			return nil
 		},
 	}, {
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"flag"
 	"fmt"
@@ -14,11 +15,19 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/companyzero/bisonrelay/brclient/internal/version"
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources/donations"
+	"github.com/companyzero/bisonrelay/client/resources/simplestore"
+	"github.com/companyzero/bisonrelay/client/updates"
+	"github.com/companyzero/bisonrelay/internal/imgreenc"
+	"github.com/companyzero/bisonrelay/rates"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/go-socks/socks"
 	"github.com/jrick/flagfile"
@@ -67,39 +76,99 @@ func (sspt simpleStorePayType) isValid() bool {
 		(sspt == ssPayTypeOnChain)
 }
 
+// parseResourceRateLimit parses a per-path resource rate limit in the format
+// "<path prefix>=<rate>[:<burst>]".
+func parseResourceRateLimit(s string) (string, client.ResourceRateLimit, error) {
+	var limit client.ResourceRateLimit
+	prefix, spec, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", limit, fmt.Errorf("resource rate limit %q not in the "+
+			"format <path prefix>=<rate>[:<burst>]", s)
+	}
+	limit, err := parseRateBurst(spec)
+	if err != nil {
+		return "", limit, fmt.Errorf("invalid resource rate limit %q: %v", s, err)
+	}
+	return strings.TrimSpace(prefix), limit, nil
+}
+
+// parseRateBurst parses a rate limit in the format "<rate>[:<burst>]". The
+// burst defaults to 1.
+func parseRateBurst(s string) (client.ResourceRateLimit, error) {
+	var limit client.ResourceRateLimit
+	rateStr, burstStr, hasBurst := strings.Cut(s, ":")
+	var err error
+	limit.Rate, err = strconv.ParseFloat(rateStr, 64)
+	if err != nil || limit.Rate < 0 {
+		return limit, errors.New("invalid rate")
+	}
+	limit.Burst = 1
+	if hasBurst {
+		limit.Burst, err = strconv.Atoi(burstStr)
+		if err != nil || limit.Burst < 1 {
+			return limit, errors.New("invalid burst")
+		}
+	}
+	return limit, nil
+}
+
+// parseUserIDList parses a comma delimited list of user ids.
+func parseUserIDList(s string) ([]clientintf.UserID, error) {
+	var uids []clientintf.UserID
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		var uid clientintf.UserID
+		if err := uid.FromString(v); err != nil {
+			return nil, fmt.Errorf("invalid user id %q: %v", v, err)
+		}
+		uids = append(uids, uid)
+	}
+	return uids, nil
+}
+
 type config struct {
-	ServerAddr        string
-	Root              string
-	DBRoot            string
-	MsgRoot           string
-	DownloadsRoot     string
-	LNRPCHost         string
-	LNTLSCertPath     string
-	LNMacaroonPath    string
-	LNDebugLevel      string
-	LNMaxLogFiles     int
-	LNRPCListen       []string
-	LogFile           string
-	MaxLogFiles       int
-	DebugLevel        string
-	WalletType        string
-	CompressLevel     int
-	CmdHistoryPath    string
-	NickColor         string
-	GCOtherColor      string
-	PMOtherColor      string
-	BlinkCursor       bool
-	BellCmd           string
-	Network           string
-	CPUProfile        string
-	CPUProfileHz      int
-	MemProfile        string
-	LogPings          bool
-	NoLoadChatHistory bool
+	ServerAddr     string
+	Root           string
+	DBRoot         string
+	MsgRoot        string
+	DownloadsRoot  string
+	LNRPCHost      string
+	LNTLSCertPath  string
+	LNMacaroonPath string
+	LNDebugLevel   string
+	LNMaxLogFiles  int
+	LNRPCListen    []string
+	LogFile        string
+	MaxLogFiles    int
+	DebugLevel     string
+	TracingURL     string
+	MetricsListen  string
+	WalletType     string
+	CompressLevel  int
+	CmdHistoryPath string
+	NickColor      string
+	GCOtherColor   string
+	PMOtherColor   string
+	BlinkCursor    bool
+	BellCmd        string
+
+	SummarizeCmd         string
+	SummarizePostsMinLen int
+	SummarizeGCMsgs      int
+	Network              string
+	CPUProfile           string
+	CPUProfileHz         int
+	MemProfile           string
+	LogPings             bool
+	NoLoadChatHistory    bool
 
 	AutoHandshakeInterval       time.Duration
 	AutoRemoveIdleUsersInterval time.Duration
 
+	DownloadsNaming clientdb.DownloadsNaming
+
 	SyncFreeList bool
 
 	ProxyAddr    string
@@ -114,23 +183,64 @@ type config struct {
 	WinPin             []string
 	MimeMap            map[string]string
 	InviteFundsAccount string
+	Watchtowers        []string
+	SCBBackupDirs      []string
+	FeePolicies        map[clientintf.PaymentCategory]clientintf.FeePolicy
+	TipKeysend         bool
+	ApprovalThreshold  int64
 
 	JSONRPCListen      []string
 	RPCCertPath        string
 	RPCKeyPath         string
 	RPCClientCAPath    string
 	RPCIssueClientCert bool
+	RPCRESTGateway     bool
 
 	ExtenalEditorForComments bool
 
-	ResourcesUpstream     string
-	SimpleStorePayType    simpleStorePayType
-	SimpleStoreAccount    string
-	SimpleStoreShipCharge float64
+	ResourcesUpstream        string
+	ResourcesRateLimits      client.ResourceRateLimits
+	ResourcesConcurrency     client.ResourceConcurrency
+	TrustTiers               *client.TrustTiersConfig
+	ImageReencode            *imgreenc.Config
+	SimpleStorePayType       simpleStorePayType
+	SimpleStoreAccount       string
+	SimpleStoreXPub          string
+	SimpleStoreShipCharge    float64
+	SimpleStoreCurrency      string
+	SimpleStoreOnChainConfs  uint32
+	SimpleStoreAdmins        simplestore.AdminRouting
+	SimpleStoreAdminRoles    map[clientintf.UserID]simplestore.AdminRole
+	SimpleStoreLedger        simplestore.LedgerConfig
+	SimpleStoreCoHost        simplestore.CoHostConfig
+	SimpleStoreDBFile        string
+	SimpleStoreWebhook       simplestore.WebhookConfig
+	SimpleStoreInventorySync simplestore.InventorySyncConfig
+	SimpleStoreReminders     simplestore.RemindersConfig
+	SimpleStoreTax           simplestore.TaxConfig
+	SimpleStoreRateFailure   simplestore.RateFailurePolicy
+	SimpleStoreRateStaleness time.Duration
+	Donations                *donations.Config
+	TicketsRoot              string
+	BookingRoot              string
+	BookingReminderLead      time.Duration
+	PluginsConfig            string
+	Updates                  *updatesConfig
 
 	dialFunc func(context.Context, string, string) (net.Conn, error)
 }
 
+// updatesConfig is the config of the checker of new releases.
+type updatesConfig struct {
+	ManifestURL  string
+	ManifestUser *clientintf.UserID
+	ManifestPath []string
+	Keys         []ed25519.PublicKey
+	MinSigs      int
+	Interval     time.Duration
+	StageDir     string
+}
+
 func defaultAppDataDir(homeDir string) string {
 	switch runtime.GOOS {
 	// Attempt to use the LOCALAPPDATA or APPDATA environment variable on
@@ -267,10 +377,14 @@ func loadConfig() (*config, error) {
 	fs.Var(&mimetypes, "mimetype", "List of mimetypes with viewer")
 
 	flagBellCmd := fs.String("bellcmd", "", "Bell command on new msgs")
+	flagSummarizeCmd := fs.String("summarizecmd", "", "Command that summarizes long posts and GC backlogs")
+	flagSummarizePostsMinLen := fs.Int("summarizepostsminlen", 0, "Min length of received posts that are automatically summarized")
+	flagSummarizeGCMsgs := fs.Int("summarizegcmsgs", 0, "Nb of GC messages after which the GC backlog is automatically summarized")
 	flagSyncFreeList := fs.Bool("syncfreelist", true, "")
 
 	flagExternalEditorForComments := fs.Bool("externaleditorforcomments", false, "")
 	flagNoLoadChatHistory := fs.Bool("noloadchathistory", false, "Whether to read chat logs to build chat history")
+	flagDownloadsNaming := fs.String("downloadsnaming", "nick", "Naming scheme of the per-conversation downloads dirs")
 
 	flagAutoHandshake := fs.String("autohandshakeinterval", "21d", "")
 	flagAutoRemove := fs.String("autoremoveidleusersinterval", "60d", "")
@@ -282,6 +396,8 @@ func loadConfig() (*config, error) {
 	flagDebugLevel := fs.String("log.debuglevel", defaultDebugLevel, "Debug Level")
 	flagSaveHistory := fs.Bool("log.savehistory", false, "Whether to save history to a file")
 	flagLogPings := fs.Bool("log.pings", false, "Whether to log pings")
+	flagTracingURL := fs.String("log.tracing", "", "URL of the OTLP/HTTP traces endpoint of a collector")
+	flagMetricsListen := fs.String("log.metrics", "", "ip:port of where to serve the client metrics")
 
 	// theme
 	flagNickColor := fs.String("theme.nickcolor", "bold:white:na", "color of the nick")
@@ -295,6 +411,8 @@ func loadConfig() (*config, error) {
 	flagLNHost := fs.String("payment.lnrpchost", "127.0.0.1:10009", "dcrlnd network address")
 	flagLNTLSCert := fs.String("payment.lntlscert", "~/.dcrlnd/tls.cert", "path to dcrlnd tls.cert")
 	flagLNMacaroonPath := fs.String("payment.lnmacaroonpath", "", "path do dcrlnd admin.macaroon")
+	flagLNProfile := fs.String("payment.lnprofile", "", "name of the dcrlnd connection profile to use")
+	flagLNProfilesFile := fs.String("payment.lnprofilesfile", "", "path to the dcrlnd connection profiles file")
 	flagLNDebugLevel := fs.String("payment.lndebuglevel", "info", "LN log level")
 	flagLNMaxLogFiles := fs.Int("payment.lnmaxlogfiles", 3, "LN Max Log Files")
 	flagMinWalletBal := fs.Float64("payment.minimumwalletbalance", 1.0, "Minimum wallet balance before warn")
@@ -302,6 +420,16 @@ func loadConfig() (*config, error) {
 	flagMinSendBal := fs.Float64("payment.minimumsendbalance", 0.01, "Minimum send balance before warn")
 	flagLNRPCListen := fs.String("payment.lnrpclisten", "", "list of addrs for the embedded ln to listen on")
 	flagInviteFundsAccount := fs.String("payment.invitefundsaccount", "", "")
+	flagWatchtowers := fs.String("payment.watchtowers", "", "Comma delimited list of watchtower URIs")
+	flagTipMaxFee := fs.Float64("payment.tipmaxfee", 0, "Max fee (in DCR) to pay when sending tips")
+	flagTipMaxFeePct := fs.Float64("payment.tipmaxfeepct", 0, "Max fee (as a percentage of the amount) to pay when sending tips")
+	flagDownloadMaxFee := fs.Float64("payment.downloadmaxfee", 0, "Max fee (in DCR) to pay when paying for downloads")
+	flagDownloadMaxFeePct := fs.Float64("payment.downloadmaxfeepct", 0, "Max fee (as a percentage of the amount) to pay when paying for downloads")
+	flagStoreMaxFee := fs.Float64("payment.storemaxfee", 0, "Max fee (in DCR) to pay when paying store invoices")
+	flagStoreMaxFeePct := fs.Float64("payment.storemaxfeepct", 0, "Max fee (as a percentage of the amount) to pay when paying store invoices")
+	flagTipKeysend := fs.Bool("payment.tipkeysend", false, "Send and receive tips via keysend when invoice requests are not answered")
+	flagApprovalThreshold := fs.Float64("payment.approvalthreshold", 0, "Min amount (in DCR) of outgoing payments that require approval")
+	flagSCBBackupDirs := fs.String("payment.scbbackupdirs", "", "Comma delimited list of dirs to keep copies of the channel backup")
 
 	// clientrpc
 	flagJSONRPCListen := fs.String("clientrpc.jsonrpclisten", "", "Comma delimited list of JSON-RPC server binding addresses")
@@ -309,14 +437,100 @@ func loadConfig() (*config, error) {
 	flagRPCKeyPath := fs.String("clientrpc.rpckeypath", defaultRPCKeyPath, "")
 	flagRPCClientCAPath := fs.String("clientrpc.rpcclientcapath", defaultRPCClientCA, "")
 	flagRPCIssueClientCert := fs.Bool("clientrpc.rpcissueclientcert", true, "")
+	flagRPCRESTGateway := fs.Bool("clientrpc.restgateway", false, "Enable the REST mapping of the clientrpc services")
 
 	// resources
 	flagResourcesUpstream := fs.String("resources.upstream", "", "Upstream processor of resource requests")
+	flagResourcesRateLimit := fs.Float64("resources.ratelimit", 0, "Max number of resource requests per second per remote user")
+	flagResourcesRateLimitBurst := fs.Int("resources.ratelimitburst", 10, "Max number of resource requests at once per remote user")
+	flagResourcesRateLimitPaths := fs.String("resources.ratelimitpaths", "", "Comma delimited list of per-path resource rate limits")
+	flagResourcesWorkers := fs.Int("resources.workers", 8, "Max number of resource requests fulfilled at once")
+	flagResourcesMaxQueued := fs.Int("resources.maxqueued", 100, "Max number of resource requests waiting to be fulfilled")
+	flagResourcesConcurrencyPaths := fs.String("resources.concurrencypaths", "", "Comma delimited list of per-path limits of resource requests fulfilled at once")
+	flagResourcesAllowUsers := fs.String("resources.allowusers", "", "Comma delimited list of users whose resource requests are not rate limited")
+	flagResourcesDenyUsers := fs.String("resources.denyusers", "", "Comma delimited list of users not allowed to fetch resources")
+
+	// trusttiers
+	flagTrustTiersEnable := fs.Bool("trusttiers.enable", false, "Gate feature defaults by the trust tier of remote users")
+	flagTrustTiersKnownAfter := fs.String("trusttiers.knownafter", "30d", "How long after the first KX users are promoted to the known tier")
+	flagTrustTiersNew := fs.String("trusttiers.new", "", "Policy of users in the new tier")
+	flagTrustTiersKnown := fs.String("trusttiers.known", "", "Policy of users in the known tier")
+	flagTrustTiersTrusted := fs.String("trusttiers.trusted", "", "Policy of users in the trusted tier")
+
+	// images
+	flagImagesMaxWidth := fs.Int("images.maxwidth", 0, "Max width of sent images")
+	flagImagesMaxHeight := fs.Int("images.maxheight", 0, "Max height of sent images")
+	flagImagesMaxSize := fs.Int("images.maxsize", 0, "Size above which sent images are re-encoded")
+	flagImagesJPEGQuality := fs.Int("images.jpegquality", imgreenc.DefaultJPEGQuality, "Quality of re-encoded JPEG images")
+	flagImagesStripMetadata := fs.Bool("images.stripmetadata", false, "Whether to strip metadata from sent images")
 
 	// simplestore
 	flagSimpleStorePayType := fs.String("simplestore.paytype", "", "How to charge for paystore purchases")
 	flagSimpleStoreAccount := fs.String("simplestore.account", "", "Account to use for on-chain adresses")
+	flagSimpleStoreXPub := fs.String("simplestore.xpub", "", "Extended public key of an external wallet account where on-chain payments are received")
 	flagSimpleStoreShipCharge := fs.Float64("simplestore.shipcharge", 0, "How much to charge for s&h")
+	flagSimpleStoreCurrency := fs.String("simplestore.currency", "USD", "Fiat currency of the prices of the store")
+	flagSimpleStoreOnChainConfs := fs.Uint("simplestore.onchainconfs", 1, "Number of confirmations of on-chain payments")
+	flagSimpleStoreAdmins := fs.String("simplestore.admins", "", "Comma delimited list of ids of remote users that are store admins")
+	flagSimpleStoreAdminRoles := fs.String("simplestore.adminroles", "", "Comma delimited list of roles of the store admins, in the format <id>:<role>")
+	flagSimpleStoreAdminShifts := fs.String("simplestore.adminshifts", "", "Comma delimited list of shifts of the store admins")
+	flagSimpleStoreAdminAckTimeout := fs.String("simplestore.adminacktimeout", "", "How long an admin has to acknowledge an order before it is reassigned")
+	flagSimpleStoreLedgerFile := fs.String("simplestore.ledgerfile", "", "File to export paid orders and refunds as accounting entries")
+	flagSimpleStoreLedgerFormat := fs.String("simplestore.ledgerformat", "ledger", "Format of the accounting entries (ledger or beancount)")
+	flagSimpleStoreLedgerLNAccount := fs.String("simplestore.ledgerlnaccount", "", "Account of LN payments")
+	flagSimpleStoreLedgerOnChainAccount := fs.String("simplestore.ledgeronchainaccount", "", "Account of on-chain payments")
+	flagSimpleStoreLedgerSalesAccount := fs.String("simplestore.ledgersalesaccount", "", "Account of sales")
+	flagSimpleStoreLedgerShippingAccount := fs.String("simplestore.ledgershippingaccount", "", "Account of shipping charges")
+	flagSimpleStoreLedgerTaxAccount := fs.String("simplestore.ledgertaxaccount", "", "Account of taxes charged on orders")
+	flagSimpleStoreLedgerRefundsAccount := fs.String("simplestore.ledgerrefundsaccount", "", "Account of refunds")
+	flagSimpleStoreCoHostPrimary := fs.String("simplestore.cohostprimary", "", "Id of the primary store to co-host")
+	flagSimpleStoreCoHosts := fs.String("simplestore.cohosts", "", "Comma delimited list of ids of the trusted co-hosts of the store")
+	flagSimpleStoreCoHostSyncInterval := fs.String("simplestore.cohostsyncinterval", "", "Interval between syncs of a co-host with the primary store")
+	flagSimpleStoreDBFile := fs.String("simplestore.dbfile", "", "bbolt database file to store carts and orders instead of JSON files")
+	flagSimpleStoreWebhookURL := fs.String("simplestore.webhookurl", "", "URL to POST order events to")
+	flagSimpleStoreWebhookSecret := fs.String("simplestore.webhooksecret", "", "Secret used to sign the order events POSTed to the webhook")
+	flagSimpleStoreInvSyncAddr := fs.String("simplestore.invsyncaddr", "", "Loopback address of the inventory sync endpoint")
+	flagSimpleStoreInvSyncToken := fs.String("simplestore.invsynctoken", "", "Bearer token of the inventory sync endpoint")
+	flagSimpleStoreInvSyncConflicts := fs.String("simplestore.invsyncconflicts", "external", "Policy for external inventory updates that conflict with local changes")
+	flagSimpleStoreLowStockThreshold := fs.Int("simplestore.lowstockthreshold", 0, "Stock level below which the admins are warned about a product")
+	flagSimpleStoreCartReminderIdle := fs.String("simplestore.cartreminderidle", "", "How long a cart must be idle before its user is reminded of it")
+	flagSimpleStoreCartReminderMsg := fs.String("simplestore.cartremindermsg", "", "Message sent to users with idle carts")
+	flagSimpleStoreOrderReminders := fs.String("simplestore.orderreminders", "", "Comma delimited list of delays after an order is placed at which its user is reminded to pay it")
+	flagSimpleStoreCancelUnpaidAfter := fs.String("simplestore.cancelunpaidafter", "", "How long after the final order reminder unpaid orders are canceled")
+	flagSimpleStoreTaxName := fs.String("simplestore.taxname", "", "Name of the tax charged on orders")
+	flagSimpleStoreTaxRate := fs.Float64("simplestore.taxrate", 0, "Flat rate (as a percentage) of the tax charged on orders")
+	flagSimpleStoreTaxRegions := fs.String("simplestore.taxregions", "", "Comma delimited list of tax rates per region, in the format <region>:<rate>")
+	flagSimpleStoreTaxShipping := fs.Bool("simplestore.taxshipping", false, "Whether to also tax the shipping charge")
+	flagSimpleStoreRateFailurePolicy := fs.String("simplestore.ratefailurepolicy", "", "How orders are placed when the exchange rate is not available (cached, retry or reject)")
+	flagSimpleStoreRateMaxStaleness := fs.String("simplestore.ratemaxstaleness", "", "Max age of the last exchange rate used with the cached rate failure policy")
+
+	// donations
+	flagDonationsRoot := fs.String("donations.root", "", "Dir of the donations received in the donation page")
+	flagDonationsTitle := fs.String("donations.title", "Donate", "Title of the donation page")
+	flagDonationsDescription := fs.String("donations.description", "", "Description shown in the donation page")
+	flagDonationsPresets := fs.String("donations.presets", "", "Comma delimited list of suggested donation amounts in DCR")
+	flagDonationsMinAmount := fs.Float64("donations.minamount", 0, "Min donation amount in DCR")
+	flagDonationsPublicList := fs.Bool("donations.publiclist", false, "Whether to list donors in the donation page")
+
+	// tickets
+	flagTicketsRoot := fs.String("tickets.root", "", "Dir with the events to sell tickets for and their purchases")
+
+	// booking
+	flagBookingRoot := fs.String("booking.root", "", "Dir with the slots open for booking and the bookings")
+	flagBookingReminderLead := fs.String("booking.reminderlead", "1h", "How long before the start of bookings the reminders are sent")
+
+	// plugins
+	flagPluginsConfig := fs.String("plugins.config", "", "Path to the JSON file with the configs of the plugins to run")
+
+	// updates
+	flagUpdatesCheck := fs.Bool("updates.check", false, "Whether to periodically check for new releases")
+	flagUpdatesManifestURL := fs.String("updates.manifesturl", "", "HTTPS URL of the signed release manifest")
+	flagUpdatesManifestUser := fs.String("updates.manifestuser", "", "ID of the user that serves the signed release manifest")
+	flagUpdatesManifestPath := fs.String("updates.manifestpath", "releases/manifest.json", "Path of the release manifest resource served by manifestuser")
+	flagUpdatesKeys := fs.String("updates.keys", "", "Comma separated list of hex encoded ed25519 keys trusted to sign release manifests")
+	flagUpdatesMinSigs := fs.Int("updates.minsigs", 1, "Min number of trusted keys that must sign a release manifest")
+	flagUpdatesInterval := fs.String("updates.interval", "24h", "Interval between checks for new releases")
+	flagUpdatesStageDir := fs.String("updates.stagedir", "", "Dir where the binaries of new releases are downloaded to")
 
 	// Load config from file.
 	parser := flagfile.Parser{
@@ -358,6 +572,10 @@ func loadConfig() (*config, error) {
 		path := (*flagResourcesUpstream)[len("simplestore:"):]
 		path = expandPath(homeDir, path)
 		*flagResourcesUpstream = "simplestore:" + path
+	case strings.HasPrefix(*flagResourcesUpstream, "site:"):
+		path := (*flagResourcesUpstream)[len("site:"):]
+		path = expandPath(homeDir, path)
+		*flagResourcesUpstream = "site:" + path
 	default:
 		return nil, fmt.Errorf("unknown resources upstream provider %q", *flagResourcesUpstream)
 
@@ -375,6 +593,22 @@ func loadConfig() (*config, error) {
 	*flagLogFile = expandPath(homeDir, *flagLogFile)
 	*flagLNTLSCert = expandPath(homeDir, *flagLNTLSCert)
 	*flagLNMacaroonPath = expandPath(homeDir, *flagLNMacaroonPath)
+
+	// Override the external dcrlnd connection params with the ones from
+	// the selected connection profile.
+	if *flagLNProfile != "" {
+		if *flagLNProfilesFile == "" {
+			*flagLNProfilesFile = filepath.Join(*flagRootDir, "lnprofiles.json")
+		}
+		*flagLNProfilesFile = expandPath(homeDir, *flagLNProfilesFile)
+		profile, err := client.FindDcrlnConnProfile(*flagLNProfilesFile, *flagLNProfile)
+		if err != nil {
+			return nil, err
+		}
+		*flagLNHost = profile.Address
+		*flagLNTLSCert = expandPath(homeDir, profile.TLSCertPath)
+		*flagLNMacaroonPath = expandPath(homeDir, profile.MacaroonPath)
+	}
 	*flagMsgRoot = expandPath(homeDir, *flagMsgRoot)
 	*flagRPCKeyPath = expandPath(homeDir, *flagRPCKeyPath)
 	*flagRPCCertPath = expandPath(homeDir, *flagRPCCertPath)
@@ -397,6 +631,42 @@ func loadConfig() (*config, error) {
 	if err != nil || minSendBal < 0 {
 		return nil, fmt.Errorf("invalid minimum send balance")
 	}
+	downloadsNaming, err := clientdb.ParseDownloadsNaming(*flagDownloadsNaming)
+	if err != nil {
+		return nil, err
+	}
+
+	feePolicies := make(map[clientintf.PaymentCategory]clientintf.FeePolicy)
+	for _, fp := range []struct {
+		cat    clientintf.PaymentCategory
+		maxFee float64
+		maxPct float64
+	}{
+		{clientintf.PaymentCategoryTip, *flagTipMaxFee, *flagTipMaxFeePct},
+		{clientintf.PaymentCategoryDownload, *flagDownloadMaxFee, *flagDownloadMaxFeePct},
+		{clientintf.PaymentCategoryStore, *flagStoreMaxFee, *flagStoreMaxFeePct},
+	} {
+		maxFee, err := dcrutil.NewAmount(fp.maxFee)
+		if err != nil || maxFee < 0 {
+			return nil, fmt.Errorf("invalid %s max fee", fp.cat)
+		}
+		if fp.maxPct < 0 || fp.maxPct > 100 {
+			return nil, fmt.Errorf("invalid %s max fee percentage", fp.cat)
+		}
+		policy := clientintf.FeePolicy{
+			MaxFeeMAtoms: int64(maxFee) * 1000,
+			MaxFeeRate:   fp.maxPct / 100,
+		}
+		if !policy.IsZero() {
+			feePolicies[fp.cat] = policy
+		}
+	}
+
+	approvalThreshold, err := dcrutil.NewAmount(*flagApprovalThreshold)
+	if err != nil || approvalThreshold < 0 {
+		return nil, fmt.Errorf("invalid payment approval threshold")
+	}
+
 	var winpin []string
 	if *flagWinPin != "" {
 		winpin = strings.Split(*flagWinPin, ",")
@@ -429,12 +699,349 @@ func loadConfig() (*config, error) {
 		}
 	}
 
+	var watchtowers []string
+	for _, v := range strings.Split(*flagWatchtowers, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			watchtowers = append(watchtowers, v)
+		}
+	}
+
+	var scbBackupDirs []string
+	for _, v := range strings.Split(*flagSCBBackupDirs, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			scbBackupDirs = append(scbBackupDirs, expandPath(homeDir, v))
+		}
+	}
+
+	resRateLimits := client.ResourceRateLimits{
+		Default: client.ResourceRateLimit{
+			Rate:  *flagResourcesRateLimit,
+			Burst: *flagResourcesRateLimitBurst,
+		},
+	}
+	for _, v := range strings.Split(*flagResourcesRateLimitPaths, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		prefix, limit, err := parseResourceRateLimit(v)
+		if err != nil {
+			return nil, err
+		}
+		if resRateLimits.Paths == nil {
+			resRateLimits.Paths = make(map[string]client.ResourceRateLimit)
+		}
+		resRateLimits.Paths[prefix] = limit
+	}
+
+	resConcurrency := client.ResourceConcurrency{
+		Workers:   *flagResourcesWorkers,
+		MaxQueued: *flagResourcesMaxQueued,
+	}
+	for _, v := range strings.Split(*flagResourcesConcurrencyPaths, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		prefix, limitStr, ok := strings.Cut(v, "=")
+		limit, err := strconv.Atoi(limitStr)
+		if !ok || err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid resource concurrency limit %q", v)
+		}
+		if resConcurrency.Paths == nil {
+			resConcurrency.Paths = make(map[string]int)
+		}
+		resConcurrency.Paths[strings.TrimSpace(prefix)] = limit
+	}
+
+	if resRateLimits.Allow, err = parseUserIDList(*flagResourcesAllowUsers); err != nil {
+		return nil, fmt.Errorf("invalid value for flag 'resources.allowusers': %v", err)
+	}
+	if resRateLimits.Deny, err = parseUserIDList(*flagResourcesDenyUsers); err != nil {
+		return nil, fmt.Errorf("invalid value for flag 'resources.denyusers': %v", err)
+	}
+
+	var imageReencode *imgreenc.Config
+	if *flagImagesJPEGQuality < 1 || *flagImagesJPEGQuality > 100 {
+		return nil, errors.New("invalid value for flag 'images.jpegquality': " +
+			"must be between 1 and 100")
+	}
+	imgCfg := imgreenc.Config{
+		MaxWidth:      *flagImagesMaxWidth,
+		MaxHeight:     *flagImagesMaxHeight,
+		MaxSize:       *flagImagesMaxSize,
+		JPEGQuality:   *flagImagesJPEGQuality,
+		StripMetadata: *flagImagesStripMetadata,
+	}
+	if !imgCfg.IsZero() {
+		imageReencode = &imgCfg
+	}
+
+	var trustTiers *client.TrustTiersConfig
+	if *flagTrustTiersEnable {
+		knownAfter, err := strduration.ParseDuration(*flagTrustTiersKnownAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag "+
+				"'trusttiers.knownafter': %v", err)
+		}
+		trustTiers = &client.TrustTiersConfig{
+			KnownAfter: knownAfter,
+			Policies:   make(map[client.TrustTier]client.TrustTierPolicy),
+		}
+		tierPolicies := map[client.TrustTier]string{
+			client.TrustTierNew:     *flagTrustTiersNew,
+			client.TrustTierKnown:   *flagTrustTiersKnown,
+			client.TrustTierTrusted: *flagTrustTiersTrusted,
+		}
+		for tier, spec := range tierPolicies {
+			base := client.DefaultTrustTierPolicies[tier]
+			policy, err := client.ParseTrustTierPolicy(spec, base)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for flag "+
+					"'trusttiers.%s': %v", tier, err)
+			}
+			trustTiers.Policies[tier] = policy
+		}
+	}
+
 	ssPayType := simpleStorePayType(*flagSimpleStorePayType)
 	if !ssPayType.isValid() {
 		return nil, fmt.Errorf("invalid simple store payment type %q",
 			ssPayType)
 	}
 
+	ssCurrency := strings.ToUpper(*flagSimpleStoreCurrency)
+	if !rates.IsSupportedCurrency(ssCurrency) {
+		return nil, fmt.Errorf("unsupported simple store currency %q", ssCurrency)
+	}
+
+	var ssAdmins simplestore.AdminRouting
+	for _, v := range strings.Split(*flagSimpleStoreAdmins, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		var uid clientintf.UserID
+		if err := uid.FromString(v); err != nil {
+			return nil, fmt.Errorf("invalid simple store admin id %q: %v", v, err)
+		}
+		ssAdmins.Admins = append(ssAdmins.Admins, uid)
+	}
+	for _, v := range strings.Split(*flagSimpleStoreAdminShifts, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		shift, err := simplestore.ParseAdminShift(v)
+		if err != nil {
+			return nil, err
+		}
+		ssAdmins.Shifts = append(ssAdmins.Shifts, shift)
+	}
+	var ssAdminRoles map[clientintf.UserID]simplestore.AdminRole
+	for _, v := range strings.Split(*flagSimpleStoreAdminRoles, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		uid, role, err := simplestore.ParseAdminRole(v)
+		if err != nil {
+			return nil, err
+		}
+		if ssAdminRoles == nil {
+			ssAdminRoles = make(map[clientintf.UserID]simplestore.AdminRole)
+		}
+		ssAdminRoles[uid] = role
+	}
+	ssLedgerFormat := simplestore.LedgerFormat(*flagSimpleStoreLedgerFormat)
+	switch ssLedgerFormat {
+	case simplestore.LedgerFormatLedger, simplestore.LedgerFormatBeancount:
+	default:
+		return nil, fmt.Errorf("invalid simple store ledger format %q",
+			ssLedgerFormat)
+	}
+	var ssDBFile string
+	if *flagSimpleStoreDBFile != "" {
+		ssDBFile = cleanAndExpandPath(*flagSimpleStoreDBFile)
+	}
+	ssLedger := simplestore.LedgerConfig{
+		Filename:        *flagSimpleStoreLedgerFile,
+		Format:          ssLedgerFormat,
+		LNAccount:       *flagSimpleStoreLedgerLNAccount,
+		OnChainAccount:  *flagSimpleStoreLedgerOnChainAccount,
+		SalesAccount:    *flagSimpleStoreLedgerSalesAccount,
+		ShippingAccount: *flagSimpleStoreLedgerShippingAccount,
+		TaxAccount:      *flagSimpleStoreLedgerTaxAccount,
+		RefundsAccount:  *flagSimpleStoreLedgerRefundsAccount,
+	}
+	if ssLedger.Filename != "" {
+		ssLedger.Filename = expandPath(homeDir, ssLedger.Filename)
+	}
+
+	if *flagSimpleStoreAdminAckTimeout != "" {
+		ssAdmins.AckTimeout, err = strduration.ParseDuration(*flagSimpleStoreAdminAckTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag 'adminacktimeout': %v", err)
+		}
+	}
+
+	var ssCoHost simplestore.CoHostConfig
+	if *flagSimpleStoreCoHostPrimary != "" {
+		var uid clientintf.UserID
+		if err := uid.FromString(*flagSimpleStoreCoHostPrimary); err != nil {
+			return nil, fmt.Errorf("invalid simple store co-host primary id: %v", err)
+		}
+		ssCoHost.Primary = &uid
+	}
+	for _, v := range strings.Split(*flagSimpleStoreCoHosts, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		var uid clientintf.UserID
+		if err := uid.FromString(v); err != nil {
+			return nil, fmt.Errorf("invalid simple store co-host id %q: %v", v, err)
+		}
+		ssCoHost.CoHosts = append(ssCoHost.CoHosts, uid)
+	}
+	if *flagSimpleStoreCoHostSyncInterval != "" {
+		ssCoHost.SyncInterval, err = strduration.ParseDuration(*flagSimpleStoreCoHostSyncInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag 'cohostsyncinterval': %v", err)
+		}
+	}
+
+	ssReminders := simplestore.RemindersConfig{
+		LowStockThreshold: int64(*flagSimpleStoreLowStockThreshold),
+		CartReminderMsg:   *flagSimpleStoreCartReminderMsg,
+	}
+	if *flagSimpleStoreCartReminderIdle != "" {
+		ssReminders.CartIdleTime, err = strduration.ParseDuration(*flagSimpleStoreCartReminderIdle)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag 'cartreminderidle': %v", err)
+		}
+	}
+	for _, v := range strings.Split(*flagSimpleStoreOrderReminders, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		delay, err := strduration.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag 'orderreminders': %v", err)
+		}
+		ssReminders.OrderReminders = append(ssReminders.OrderReminders, delay)
+	}
+	if *flagSimpleStoreCancelUnpaidAfter != "" {
+		ssReminders.CancelUnpaidAfter, err = strduration.ParseDuration(*flagSimpleStoreCancelUnpaidAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag 'cancelunpaidafter': %v", err)
+		}
+	}
+	ssTax := simplestore.TaxConfig{
+		Name:        *flagSimpleStoreTaxName,
+		Rate:        *flagSimpleStoreTaxRate,
+		TaxShipping: *flagSimpleStoreTaxShipping,
+	}
+	for _, v := range strings.Split(*flagSimpleStoreTaxRegions, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		region, rateStr, ok := strings.Cut(v, ":")
+		if !ok {
+			return nil, fmt.Errorf("tax region %q not in the format "+
+				"<region>:<rate>", v)
+		}
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate of tax region %q: %v", region, err)
+		}
+		if ssTax.Regions == nil {
+			ssTax.Regions = make(map[string]float64)
+		}
+		ssTax.Regions[region] = rate
+	}
+
+	var ssRateMaxStaleness time.Duration
+	if *flagSimpleStoreRateMaxStaleness != "" {
+		ssRateMaxStaleness, err = strduration.ParseDuration(*flagSimpleStoreRateMaxStaleness)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag 'ratemaxstaleness': %v", err)
+		}
+	}
+
+	var donationsCfg *donations.Config
+	if *flagDonationsRoot != "" {
+		donationsCfg = &donations.Config{
+			Root:        cleanAndExpandPath(*flagDonationsRoot),
+			Title:       *flagDonationsTitle,
+			Description: *flagDonationsDescription,
+			MinAmount:   *flagDonationsMinAmount,
+			PublicList:  *flagDonationsPublicList,
+		}
+		for _, v := range strings.Split(*flagDonationsPresets, ",") {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+			amount, err := strconv.ParseFloat(v, 64)
+			if err != nil || amount <= 0 {
+				return nil, fmt.Errorf("invalid donation preset amount %q", v)
+			}
+			donationsCfg.Presets = append(donationsCfg.Presets, amount)
+		}
+	}
+
+	var ticketsRoot string
+	if *flagTicketsRoot != "" {
+		ticketsRoot = cleanAndExpandPath(*flagTicketsRoot)
+	}
+
+	var bookingRoot string
+	if *flagBookingRoot != "" {
+		bookingRoot = cleanAndExpandPath(*flagBookingRoot)
+	}
+	var pluginsConfig string
+	if *flagPluginsConfig != "" {
+		pluginsConfig = cleanAndExpandPath(*flagPluginsConfig)
+	}
+
+	var updatesCfg *updatesConfig
+	if *flagUpdatesCheck {
+		updatesCfg = &updatesConfig{
+			ManifestURL: *flagUpdatesManifestURL,
+			MinSigs:     *flagUpdatesMinSigs,
+		}
+		if *flagUpdatesManifestUser != "" {
+			updatesCfg.ManifestUser = new(clientintf.UserID)
+			if err := updatesCfg.ManifestUser.FromString(*flagUpdatesManifestUser); err != nil {
+				return nil, fmt.Errorf("invalid value for flag 'manifestuser': %v", err)
+			}
+			updatesCfg.ManifestPath = strings.FieldsFunc(*flagUpdatesManifestPath,
+				func(r rune) bool { return r == '/' })
+		}
+		if (updatesCfg.ManifestURL == "") == (updatesCfg.ManifestUser == nil) {
+			return nil, fmt.Errorf("exactly one of 'manifesturl' or " +
+				"'manifestuser' must be set to check for updates")
+		}
+		for _, s := range strings.Split(*flagUpdatesKeys, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			key, err := updates.ParsePubKey(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %q in flag 'keys': %v", s, err)
+			}
+			updatesCfg.Keys = append(updatesCfg.Keys, key)
+		}
+		updatesCfg.Interval, err = strduration.ParseDuration(*flagUpdatesInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for flag 'interval': %v", err)
+		}
+		if *flagUpdatesStageDir != "" {
+			updatesCfg.StageDir = cleanAndExpandPath(*flagUpdatesStageDir)
+		}
+	}
+
+	bookingReminderLead, err := strduration.ParseDuration(*flagBookingReminderLead)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for flag 'reminderlead': %v", err)
+	}
+
 	var d net.Dialer
 	dialFunc := d.DialContext
 	if *flagProxyAddr != "" {
@@ -455,60 +1062,107 @@ func loadConfig() (*config, error) {
 
 	// Return the final cfg object.
 	return &config{
-		ServerAddr:         *flagServerAddr,
-		Root:               *flagRootDir,
-		DBRoot:             filepath.Join(*flagRootDir, "db"),
-		DownloadsRoot:      filepath.Join(*flagRootDir, "downloads"),
-		WalletType:         *flagWalletType,
-		MsgRoot:            *flagMsgRoot,
-		LNRPCHost:          *flagLNHost,
-		LNTLSCertPath:      *flagLNTLSCert,
-		LNMacaroonPath:     *flagLNMacaroonPath,
-		LNDebugLevel:       *flagLNDebugLevel,
-		LNMaxLogFiles:      *flagLNMaxLogFiles,
-		LNRPCListen:        lnRPCListen,
-		LogFile:            *flagLogFile,
-		MaxLogFiles:        *flagMaxLogFiles,
-		DebugLevel:         *flagDebugLevel,
-		CompressLevel:      *flagCompressLevel,
-		CmdHistoryPath:     cmdHistoryPath,
-		NickColor:          *flagNickColor,
-		GCOtherColor:       *flagGCOtherColor,
-		PMOtherColor:       *flagPMOtherColor,
-		BlinkCursor:        *flagBlinkCursor,
-		BellCmd:            strings.TrimSpace(*flagBellCmd),
-		Network:            *flagNetwork,
-		CPUProfile:         *flagCPUProfile,
-		CPUProfileHz:       *flagCPUProfileHz,
-		MemProfile:         *flagMemProfile,
-		LogPings:           *flagLogPings,
-		NoLoadChatHistory:  *flagNoLoadChatHistory,
-		ProxyAddr:          *flagProxyAddr,
-		ProxyUser:          *flagProxyUser,
-		ProxyPass:          *flagProxyPass,
-		TorIsolation:       *flagTorIsolation,
-		MinWalletBal:       minWalletBal,
-		MinRecvBal:         minRecvBal,
-		MinSendBal:         minSendBal,
-		WinPin:             winpin,
-		MimeMap:            mimeMap,
-		JSONRPCListen:      jrpcListen,
-		RPCCertPath:        *flagRPCCertPath,
-		RPCKeyPath:         *flagRPCKeyPath,
-		RPCClientCAPath:    *flagRPCClientCAPath,
-		RPCIssueClientCert: *flagRPCIssueClientCert,
-		InviteFundsAccount: *flagInviteFundsAccount,
-		ResourcesUpstream:  *flagResourcesUpstream,
+		ServerAddr:           *flagServerAddr,
+		Root:                 *flagRootDir,
+		DBRoot:               filepath.Join(*flagRootDir, "db"),
+		DownloadsRoot:        filepath.Join(*flagRootDir, "downloads"),
+		WalletType:           *flagWalletType,
+		MsgRoot:              *flagMsgRoot,
+		LNRPCHost:            *flagLNHost,
+		LNTLSCertPath:        *flagLNTLSCert,
+		LNMacaroonPath:       *flagLNMacaroonPath,
+		LNDebugLevel:         *flagLNDebugLevel,
+		LNMaxLogFiles:        *flagLNMaxLogFiles,
+		LNRPCListen:          lnRPCListen,
+		LogFile:              *flagLogFile,
+		MaxLogFiles:          *flagMaxLogFiles,
+		DebugLevel:           *flagDebugLevel,
+		TracingURL:           *flagTracingURL,
+		MetricsListen:        *flagMetricsListen,
+		CompressLevel:        *flagCompressLevel,
+		CmdHistoryPath:       cmdHistoryPath,
+		NickColor:            *flagNickColor,
+		GCOtherColor:         *flagGCOtherColor,
+		PMOtherColor:         *flagPMOtherColor,
+		BlinkCursor:          *flagBlinkCursor,
+		BellCmd:              strings.TrimSpace(*flagBellCmd),
+		SummarizeCmd:         strings.TrimSpace(*flagSummarizeCmd),
+		SummarizePostsMinLen: *flagSummarizePostsMinLen,
+		SummarizeGCMsgs:      *flagSummarizeGCMsgs,
+		Network:              *flagNetwork,
+		CPUProfile:           *flagCPUProfile,
+		CPUProfileHz:         *flagCPUProfileHz,
+		MemProfile:           *flagMemProfile,
+		LogPings:             *flagLogPings,
+		NoLoadChatHistory:    *flagNoLoadChatHistory,
+		ProxyAddr:            *flagProxyAddr,
+		ProxyUser:            *flagProxyUser,
+		ProxyPass:            *flagProxyPass,
+		TorIsolation:         *flagTorIsolation,
+		MinWalletBal:         minWalletBal,
+		MinRecvBal:           minRecvBal,
+		MinSendBal:           minSendBal,
+		WinPin:               winpin,
+		MimeMap:              mimeMap,
+		JSONRPCListen:        jrpcListen,
+		RPCCertPath:          *flagRPCCertPath,
+		RPCKeyPath:           *flagRPCKeyPath,
+		RPCClientCAPath:      *flagRPCClientCAPath,
+		RPCIssueClientCert:   *flagRPCIssueClientCert,
+		RPCRESTGateway:       *flagRPCRESTGateway,
+		InviteFundsAccount:   *flagInviteFundsAccount,
+		Watchtowers:          watchtowers,
+		SCBBackupDirs:        scbBackupDirs,
+		FeePolicies:          feePolicies,
+		TipKeysend:           *flagTipKeysend,
+		ApprovalThreshold:    int64(approvalThreshold) * 1000,
+		ResourcesUpstream:    *flagResourcesUpstream,
+
+		ResourcesRateLimits:  resRateLimits,
+		ResourcesConcurrency: resConcurrency,
+		TrustTiers:           trustTiers,
+		ImageReencode:        imageReencode,
 
 		AutoHandshakeInterval:       autoHandshakeInterval,
 		AutoRemoveIdleUsersInterval: autoRemoveInterval,
 
+		DownloadsNaming: downloadsNaming,
+
 		SyncFreeList:             *flagSyncFreeList,
 		ExtenalEditorForComments: *flagExternalEditorForComments,
 
-		SimpleStorePayType:    ssPayType,
-		SimpleStoreAccount:    *flagSimpleStoreAccount,
-		SimpleStoreShipCharge: *flagSimpleStoreShipCharge,
+		SimpleStorePayType:      ssPayType,
+		SimpleStoreAccount:      *flagSimpleStoreAccount,
+		SimpleStoreXPub:         *flagSimpleStoreXPub,
+		SimpleStoreShipCharge:   *flagSimpleStoreShipCharge,
+		SimpleStoreCurrency:     ssCurrency,
+		SimpleStoreOnChainConfs: uint32(*flagSimpleStoreOnChainConfs),
+		SimpleStoreAdmins:       ssAdmins,
+		SimpleStoreAdminRoles:   ssAdminRoles,
+		SimpleStoreLedger:       ssLedger,
+		SimpleStoreCoHost:       ssCoHost,
+		SimpleStoreDBFile:       ssDBFile,
+		SimpleStoreReminders:    ssReminders,
+		SimpleStoreTax:          ssTax,
+
+		SimpleStoreRateFailure:   simplestore.RateFailurePolicy(*flagSimpleStoreRateFailurePolicy),
+		SimpleStoreRateStaleness: ssRateMaxStaleness,
+
+		SimpleStoreWebhook: simplestore.WebhookConfig{
+			URL:    *flagSimpleStoreWebhookURL,
+			Secret: *flagSimpleStoreWebhookSecret,
+		},
+		SimpleStoreInventorySync: simplestore.InventorySyncConfig{
+			Conflicts:  simplestore.InventoryConflictPolicy(*flagSimpleStoreInvSyncConflicts),
+			ListenAddr: *flagSimpleStoreInvSyncAddr,
+			Token:      *flagSimpleStoreInvSyncToken,
+		},
+		Donations:           donationsCfg,
+		TicketsRoot:         ticketsRoot,
+		BookingRoot:         bookingRoot,
+		BookingReminderLead: bookingReminderLead,
+		PluginsConfig:       pluginsConfig,
+		Updates:             updatesCfg,
 
 		dialFunc: dialFunc,
 	}, nil
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/companyzero/bisonrelay/client/resources/donations"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+)
+
+func handleDonationReceived(as *appState, d *donations.Donation) {
+	ru, err := as.c.UserByID(d.User)
+	if err != nil {
+		as.diagMsg("Received donation #%d of %s from unknown user %s",
+			d.ID, d.PaidAmount, d.User)
+		return
+	}
+
+	cw := as.findOrNewChatWindow(ru.ID(), ru.Nick())
+	if d.Message != "" {
+		cw.newInternalMsg("Received donation #%d of %s: %s", d.ID,
+			d.PaidAmount, strescape.Content(d.Message))
+	} else {
+		cw.newInternalMsg("Received donation #%d of %s", d.ID, d.PaidAmount)
+	}
+	as.repaintIfActive(cw)
+}
@@ -10,6 +10,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/internal/imgreenc"
 	"github.com/companyzero/bisonrelay/internal/mdembeds"
 	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -100,11 +101,20 @@ func (ew *embedWidget) tryEmbed() error {
 			return err
 		}
 
+		args.Typ = mime.TypeByExtension(filepath.Ext(filename))
+		if cfg := ew.as.imageReencode; cfg != nil {
+			reenc, mimeType, changed, err := imgreenc.Reencode(data, *cfg)
+			if err != nil {
+				return err
+			}
+			if changed {
+				data, args.Typ = reenc, mimeType
+			}
+		}
+
 		if uint64(len(data)) > rpc.MaxChunkSize {
 			return fmt.Errorf("file too big to embed")
 		}
-
-		args.Typ = mime.TypeByExtension(filepath.Ext(filename))
 		id = chainhash.HashH(data).String()[:8]
 		pseudoData := fmt.Sprintf("[content %s]", id)
 		args.Data = []byte(pseudoData)
@@ -384,6 +384,28 @@ func (mws mainWindowState) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				mws.resetFormInput()
 			}
 
+		case cw != nil && cw.selEl != nil && cw.selEl.embed != nil && cw.selEl.embed.IsQuote() && msg.Type == tea.KeyCtrlV:
+			// Refresh the selected quote.
+			embedded := *cw.selEl.embed
+			uid := embedded.Uid
+			if uid == nil && cw.page != nil {
+				uid = &cw.page.UID
+			}
+			switch {
+			case !embedded.QuoteExpired(time.Now()):
+				cw.newHelpMsg("Quote is still valid")
+			case embedded.Refresh == "" || uid == nil:
+				cw.newHelpMsg("Quote cannot be refreshed")
+			default:
+				err := mws.as.fetchPage(*uid, embedded.Refresh, 0, 0, nil)
+				if err != nil {
+					cw.newHelpMsg("Unable to refresh quote: %v", err)
+				} else {
+					cw.newHelpMsg("Requesting new quote")
+				}
+			}
+			mws.updateViewportContent()
+
 		case cw != nil && cw.selEl != nil && cw.selEl.embed != nil && msg.Type == tea.KeyCtrlV:
 			// View selected embed.
 			embedded := *cw.selEl.embed
@@ -420,8 +442,9 @@ func (mws mainWindowState) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case cw != nil && cw.selEl != nil && cw.selEl.url != nil && cw.selEl.payReq != nil && msg.Type == tea.KeyCtrlV:
-			// Pay invoice.
-			mws.as.payPayReq(cw, *cw.selEl.url, cw.selEl.payReq)
+			// Pay invoice. This is done in a goroutine, as the
+			// payment may be held until approved.
+			go mws.as.payPayReq(cw, *cw.selEl.url, cw.selEl.payReq)
 
 		case msg.Type == tea.KeyCtrlD:
 			if cw != nil && cw.selEl != nil && cw.selEl.embed != nil {
@@ -63,6 +63,8 @@ type setupWizardScreen struct {
 	seedWords      []string
 	seed           []byte
 	mcbBytes       []byte
+	mcbPath        string
+	scbBackupDir   string
 }
 
 func (sws setupWizardScreen) Init() tea.Cmd {
@@ -155,6 +157,20 @@ func (sws *setupWizardScreen) initInputsServer() tea.Cmd {
 
 	sws.inputs = []textinput.Model{txtServer}
 
+	// Internal wallets may keep automatic copies of the channel backup.
+	// Default to the dir of the backup used to restore the wallet.
+	if sws.walletType == "internal" {
+		txtSCBDir := textinput.New()
+		txtSCBDir.Placeholder = ""
+		txtSCBDir.Prompt = "Dir to keep channel backup copies (optional): "
+		txtSCBDir.Width = sws.winW
+		txtSCBDir.SetCursorMode(textinput.CursorBlink)
+		if sws.mcbPath != "" {
+			txtSCBDir.SetValue(filepath.Dir(sws.mcbPath))
+		}
+		sws.inputs = append(sws.inputs, txtSCBDir)
+	}
+
 	return batchCmds(sws.setFocus(0))
 }
 
@@ -228,6 +244,9 @@ func (sws *setupWizardScreen) generateConfig() error {
 		LNMacaroonPath: sws.lnMacaroonPath,
 		ServerAddr:     sws.serverAddr,
 	}
+	if sws.scbBackupDir != "" {
+		cfg.SCBBackupDirs = []string{sws.scbBackupDir}
+	}
 
 	return saveNewConfig(sws.cfgFilePath, cfg)
 }
@@ -433,9 +452,18 @@ func (sws setupWizardScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return sws, cmd
 		}
 
+		if sws.focusIndex == 0 && len(sws.inputs) > 1 {
+			sws.setFocus(1)
+			return sws, nil
+		}
+		if len(sws.inputs) > 1 {
+			sws.scbBackupDir = strings.TrimSpace(sws.inputs[1].Value())
+		}
+
 		val := strings.TrimSpace(sws.inputs[0].Value())
 		if val == "" {
 			sws.validationErr = "Server address cannot be empty"
+			sws.setFocus(0)
 		} else {
 			// TODO: verify if it's a valid server address before
 			// accepting.
@@ -513,7 +541,13 @@ func (sws setupWizardScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				sws.setFocus(1)
 				return sws, nil
 			}
+			if len(mcbBytes) == 0 {
+				sws.validationErr = fmt.Sprintf("channel backup %v is empty", mcbPath)
+				sws.setFocus(1)
+				return sws, nil
+			}
 			sws.mcbBytes = mcbBytes
+			sws.mcbPath = mcbPath
 		}
 		sws.seedWords = seedWords
 		sws.validationErr = ""
@@ -565,6 +599,13 @@ func (sws setupWizardScreen) innerView() string {
 			b.WriteString("your channel's counterparties force-close them.\n")
 			b.WriteString("Execute '/ln restoremultiscb <scb-file>' if you wish\n")
 			b.WriteString("to do it at a later time.\n")
+		case swsStageServer:
+			if len(sws.inputs) > 1 {
+				b.WriteString("\n\n")
+				b.WriteString("Copies of the channel backup are written to the\n")
+				b.WriteString("backup dir whenever channels change. Use a dir in a\n")
+				b.WriteString("different device to be able to recover channel funds.\n")
+			}
 		}
 
 		return b.String()
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -20,11 +21,18 @@ type shutdownState struct {
 	cancel func()
 	err    error
 	wg     *sync.WaitGroup
+
+	// checkpoint is called before canceling the app to perform a graceful
+	// shutdown. It is nil when shutting down due to a crash.
+	checkpoint func()
 }
 
 type shutdownDone struct{}
 
 func (ss shutdownState) waitShutdown() tea.Msg {
+	if ss.checkpoint != nil {
+		ss.checkpoint()
+	}
 	ss.cancel()
 	ss.wg.Wait()
 	return shutdownDone{}
@@ -58,12 +66,35 @@ func maybeShutdown(as *appState, msg tea.Msg) (tea.Model, tea.Cmd) {
 			cancel: as.cancel,
 			err:    err,
 		}
+		if !crash {
+			ss.checkpoint = as.gracefulShutdown
+		}
 		return ss, ss.waitShutdown
 	}
 
 	return nil, nil
 }
 
+// shutdownGracePeriod is the max amount of time to wait for outbound messages
+// to be sent during a graceful shutdown.
+const shutdownGracePeriod = 5 * time.Second
+
+// gracefulShutdown checkpoints the state of the app subsystems and waits (up
+// to shutdownGracePeriod) for the outbound message queues to be flushed.
+func (as *appState) gracefulShutdown() {
+	if as.sstore != nil {
+		if err := as.sstore.Checkpoint(); err != nil {
+			as.log.Errorf("Unable to checkpoint simple store: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(as.ctx, shutdownGracePeriod)
+	defer cancel()
+	if err := as.c.Shutdown(ctx); err != nil {
+		as.log.Errorf("Unable to gracefully shutdown client: %v", err)
+	}
+}
+
 // listenToCrashSignals blocks until an abort signal is received or programDone
 // is closed. If an abort signal is received, a crashApp{} message is sent to
 // the program and after a few seconds, the program is forcefully terminated.
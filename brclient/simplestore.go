@@ -34,13 +34,50 @@ func handleCompletedSimpleStoreOrder(as *appState, order *simplestore.Order, msg
 func handleSimpleStoreOrderStatusChanged(as *appState, order *simplestore.Order, msg string) {
 	ru, err := as.c.UserByID(order.User)
 	if err != nil {
-		as.diagMsg("Order #%d placed by unknown user %s",
-			order.ID, order.User)
+		as.diagMsg("Order #%d placed by unknown user %s changed to "+
+			"status %s", order.ID, order.User, order.Status)
 		return
 	}
 
+	// The receipt was already sent by the store, so only record it in
+	// the chat window.
 	cw := as.findOrNewChatWindow(ru.ID(), ru.Nick())
-	as.pm(cw, msg)
+	cw.newInternalMsg("Sent order receipt: %s", msg)
+	as.repaintIfActive(cw)
+}
+
+func handleSimpleStoreOrderPaid(as *appState, order *simplestore.Order, msg string) {
+	ru, err := as.c.UserByID(order.User)
+	if err != nil {
+		as.diagMsg("Order #%d placed by unknown user %s paid (%s)",
+			order.ID, order.User, order.PaidAmount)
+		return
+	}
+
+	// The payment confirmation was already sent by the store, so only
+	// record it in the chat window.
+	cw := as.findOrNewChatWindow(ru.ID(), ru.Nick())
+	cw.newInternalMsg("Sent payment confirmation: %s", msg)
+	as.repaintIfActive(cw)
+}
+
+func handleSimpleStoreQuoteRequested(as *appState, quote *simplestore.QuoteRequest, msg string) {
+	if quote.User == as.c.PublicID() {
+		as.diagMsg("Quote request #%d placed by the local client", quote.ID)
+		as.diagMsg(msg)
+		return
+	}
+
+	ru, err := as.c.UserByID(quote.User)
+	if err != nil {
+		as.diagMsg("Quote request #%d placed by unknown user %s",
+			quote.ID, quote.User)
+		return
+	}
+
+	cw := as.findOrNewChatWindow(ru.ID(), ru.Nick())
+	cw.newInternalMsg("%s", msg)
+	as.repaintIfActive(cw)
 }
 
 func handleNewTransaction(as *appState, tx *lnrpc.Transaction) error {
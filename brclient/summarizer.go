@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/client"
+)
+
+// splitCmdLine splits a command line into its arguments. Arguments with
+// spaces may be enclosed in double quotes.
+func splitCmdLine(s string) []string {
+	if s == "" {
+		return nil
+	}
+	r := regexp.MustCompile(`[^\s"]+|"([^"]*)"`)
+	args := r.FindAllString(s, -1)
+	// Remove "".
+	for i, s := range args {
+		if len(s) < 2 {
+			continue
+		}
+		if s[0] == '"' && s[len(s)-1] == '"' {
+			args[i] = s[1 : len(s)-1]
+		}
+	}
+	return args
+}
+
+// cmdSummarizer is a client.Summarizer that runs an external command (for
+// example, one that calls a local LLM) to summarize content. The content is
+// written to the stdin of the command and the summary is read from its
+// stdout.
+type cmdSummarizer struct {
+	cmd []string
+}
+
+func (s *cmdSummarizer) Name() string {
+	return "summarizecmd"
+}
+
+func (s *cmdSummarizer) Summarize(ctx context.Context, req client.SummaryRequest) (string, error) {
+	if len(s.cmd) == 0 {
+		return "", errors.New("empty summarizecmd")
+	}
+
+	// Replace $kind, $title and $maxlen in command line args.
+	args := append([]string{}, s.cmd[1:]...)
+	for i := range args {
+		args[i] = strings.Replace(args[i], "$kind", string(req.Kind), -1)
+		args[i] = strings.Replace(args[i], "$title", req.Title, -1)
+		args[i] = strings.Replace(args[i], "$maxlen", strconv.Itoa(req.MaxLen), -1)
+	}
+
+	var stdout, stderr bytes.Buffer
+	c := exec.CommandContext(ctx, s.cmd[0], args...)
+	c.Stdin = strings.NewReader(req.Content)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%v: %s", err, msg)
+		}
+		return "", err
+	}
+
+	summ := strings.TrimSpace(stdout.String())
+	if req.MaxLen > 0 && len(summ) > req.MaxLen {
+		summ = strings.ToValidUTF8(summ[:req.MaxLen], "")
+	}
+	return summ, nil
+}
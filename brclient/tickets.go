@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/companyzero/bisonrelay/client/resources/tickets"
+)
+
+func handleTicketsIssued(as *appState, p *tickets.Purchase) {
+	ru, err := as.c.UserByID(p.User)
+	if err != nil {
+		as.diagMsg("Issued %d tickets of event %s to unknown user %s",
+			p.Quantity, p.Event, p.User)
+		return
+	}
+
+	cw := as.findOrNewChatWindow(ru.ID(), ru.Nick())
+	cw.newInternalMsg("Sold %d tickets of event %s (purchase #%d, %s)",
+		p.Quantity, p.Event, p.ID, p.Amount)
+	as.repaintIfActive(cw)
+}
+
+func printTicketInfo(as *appState, title string, info *tickets.TicketInfo) {
+	nick, _ := as.c.UserNick(info.User)
+	if nick == "" {
+		nick = info.User.String()
+	}
+	as.cwHelpMsgs(func(pf printf) {
+		pf("")
+		pf("%s", title)
+		pf("Code: %s", info.Code)
+		if info.Event != nil {
+			pf("Event: %s (%s)", info.Event.Title,
+				info.Event.Date.Format(ISO8601DateTime))
+		}
+		pf("Ticket number: %d", info.Number)
+		pf("Buyer: %s (purchase #%d)", nick, info.PurchaseID)
+		if info.RedeemedTS != nil {
+			pf("Redeemed at: %s", info.RedeemedTS.Format(ISO8601DateTime))
+		}
+	})
+}
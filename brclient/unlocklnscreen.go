@@ -50,6 +50,9 @@ func (ulns unlockLNScreen) Init() tea.Cmd {
 			TorAddr:      ulns.cfg.ProxyAddr,
 			TorIsolation: ulns.cfg.TorIsolation,
 			SyncFreeList: ulns.cfg.SyncFreeList,
+
+			WatchtowerClient: len(ulns.cfg.Watchtowers) > 0,
+			AcceptKeysend:    ulns.cfg.TipKeysend,
 		}
 
 		cmd := func() tea.Msg {
@@ -83,6 +83,7 @@ class Config {
   late final bool syncFreeList;
   late final int autoHandshakeInterval;
   late final int autoRemoveIdleUsersInterval;
+  late final double paymentApprovalThreshold;
 
   Config();
   Config.filled(
@@ -111,7 +112,8 @@ class Config {
       this.noLoadChatHistory: true,
       this.syncFreeList: true,
       this.autoHandshakeInterval: 21 * 24 * 60 * 60,
-      this.autoRemoveIdleUsersInterval: 60 * 24 * 60 * 60});
+      this.autoRemoveIdleUsersInterval: 60 * 24 * 60 * 60,
+      this.paymentApprovalThreshold: 0});
   factory Config.newWithRPCHost(
           Config cfg, String rpcHost, String tlsCert, String macaroonPath) =>
       Config.filled(
@@ -141,6 +143,7 @@ class Config {
         syncFreeList: cfg.syncFreeList,
         autoHandshakeInterval: cfg.autoHandshakeInterval,
         autoRemoveIdleUsersInterval: cfg.autoRemoveIdleUsersInterval,
+        paymentApprovalThreshold: cfg.paymentApprovalThreshold,
       );
 
   Future<void> saveConfig(String filepath) async {
@@ -288,6 +291,9 @@ Future<Config> loadConfig(String filepath) async {
   c.simpleStoreShipCharge =
       double.tryParse(f.get("resources", "simplestoreshipcharge") ?? "0") ?? 0;
 
+  c.paymentApprovalThreshold =
+      double.tryParse(f.get("payment", "approvalthreshold") ?? "0") ?? 0;
+
   return c;
 }
 
@@ -39,6 +39,7 @@ import 'package:bruig/screens/verify_server.dart';
 import 'package:flutter/material.dart';
 import 'package:golib_plugin/definitions.dart';
 import 'package:golib_plugin/golib_plugin.dart';
+import 'package:golib_plugin/util.dart';
 import 'package:provider/provider.dart';
 import 'package:window_manager/window_manager.dart';
 import './screens/app_start.dart';
@@ -167,7 +168,8 @@ class _AppState extends State<App> with WindowListener {
           cfg.circuitLimit,
           cfg.noLoadChatHistory,
           cfg.autoHandshakeInterval,
-          cfg.autoRemoveIdleUsersInterval);
+          cfg.autoRemoveIdleUsersInterval,
+          dcrToAtoms(cfg.paymentApprovalThreshold) * 1000);
       await Golib.initClient(initArgs);
 
       navkey.currentState!.pushReplacementNamed(OverviewScreen.routeName);
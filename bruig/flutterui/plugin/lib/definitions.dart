@@ -59,6 +59,8 @@ class InitClient {
   final int autoHandshakeInterval;
   @JsonKey(name: 'auto_remove_idle_users_interval')
   final int autoRemoveIdleUsersInterval;
+  @JsonKey(name: 'payment_approval_threshold')
+  final int paymentApprovalThreshold;
 
   InitClient(
       this.dbRoot,
@@ -82,7 +84,8 @@ class InitClient {
       this.circuitLimit,
       this.noLoadChatHistory,
       this.autoHandshakeInterval,
-      this.autoRemoveIdleUsersInterval);
+      this.autoRemoveIdleUsersInterval,
+      this.paymentApprovalThreshold);
 
   Map<String, dynamic> toJson() => _$InitClientToJson(this);
 }
@@ -1891,8 +1894,10 @@ class SSOrder {
 class SSPlacedOrder {
   final SSOrder order;
   final String msg;
+  @JsonKey(name: "payment_uri", defaultValue: "")
+  final String paymentURI;
 
-  SSPlacedOrder(this.order, this.msg);
+  SSPlacedOrder(this.order, this.msg, this.paymentURI);
   factory SSPlacedOrder.fromJson(Map<String, dynamic> json) =>
       _$SSPlacedOrderFromJson(json);
 }
@@ -1930,6 +1935,30 @@ class HandshakeStage extends ChatEvent {
       _$HandshakeStageFromJson(json);
 }
 
+@JsonSerializable()
+class PaymentApproval {
+  final int id;
+  final String category;
+  final String? uid;
+  final String invoice;
+  final int matoms;
+  final int requested;
+
+  PaymentApproval(this.id, this.category, this.uid, this.invoice, this.matoms,
+      this.requested);
+  factory PaymentApproval.fromJson(Map<String, dynamic> json) =>
+      _$PaymentApprovalFromJson(json);
+}
+
+@JsonSerializable()
+class DenyPayment {
+  final int id;
+  final String reason;
+
+  DenyPayment(this.id, this.reason);
+  Map<String, dynamic> toJson() => _$DenyPaymentToJson(this);
+}
+
 mixin NtfStreams {
   StreamController<RemoteUser> ntfAcceptedInvites =
       StreamController<RemoteUser>();
@@ -1985,6 +2014,10 @@ mixin NtfStreams {
       StreamController<SSPlacedOrder>();
   Stream<SSPlacedOrder> simpleStoreOrders() => ntfSimpleStoreOrders.stream;
 
+  StreamController<PaymentApproval> ntfPaymentApprovals =
+      StreamController<PaymentApproval>();
+  Stream<PaymentApproval> paymentApprovals() => ntfPaymentApprovals.stream;
+
   handleNotifications(int cmd, bool isError, String jsonPayload) {
     dynamic payload;
     if (jsonPayload != "") {
@@ -2185,6 +2218,10 @@ mixin NtfStreams {
         }
         break;
 
+      case NTPaymentApprovalReq:
+        ntfPaymentApprovals.add(PaymentApproval.fromJson(payload));
+        break;
+
       default:
         print("Received unknown notification ${cmd.toRadixString(16)}");
     }
@@ -2388,6 +2425,22 @@ abstract class PluginPlatform {
     await asyncCall(CTResetAllOldKX, age);
   }
 
+  Future<List<PaymentApproval>> listPaymentApprovals() async {
+    var res = await asyncCall(CTListPaymentApprovals, null);
+    if (res == null) {
+      return [];
+    }
+    return (res as List)
+        .map<PaymentApproval>((v) => PaymentApproval.fromJson(v))
+        .toList();
+  }
+
+  Future<void> approvePayment(int id) async =>
+      await asyncCall(CTApprovePayment, id);
+
+  Future<void> denyPayment(int id, String reason) async =>
+      await asyncCall(CTDenyPayment, DenyPayment(id, reason));
+
   Future<List<PostSummary>> listPosts() async {
     var res = await asyncCall(CTListPosts, null);
     if (res == null) {
@@ -2892,6 +2945,9 @@ const int CTHandshake = 0x77;
 const int CTLoadUserHistory = 0x78;
 const int CTAddressBookEntry = 0x79;
 const int CTResetAllOldKX = 0x80;
+const int CTListPaymentApprovals = 0x81;
+const int CTApprovePayment = 0x82;
+const int CTDenyPayment = 0x83;
 
 const int notificationsStartID = 0x1000;
 
@@ -2935,3 +2991,5 @@ const int NTOnboardStateChanged = 0x1025;
 const int NTResourceFetched = 0x1026;
 const int NTSimpleStoreOrderPlaced = 0x1027;
 const int NTHandshakeStage = 0x1028;
+const int NTLNHealthChanged = 0x1029;
+const int NTPaymentApprovalReq = 0x102a;
@@ -29,6 +29,7 @@ InitClient _$InitClientFromJson(Map<String, dynamic> json) => InitClient(
       json['no_load_chat_history'] as bool,
       json['auto_handshake_interval'] as int,
       json['auto_remove_idle_users_interval'] as int,
+      json['payment_approval_threshold'] as int,
     );
 
 Map<String, dynamic> _$InitClientToJson(InitClient instance) =>
@@ -55,6 +56,7 @@ Map<String, dynamic> _$InitClientToJson(InitClient instance) =>
       'no_load_chat_history': instance.noLoadChatHistory,
       'auto_handshake_interval': instance.autoHandshakeInterval,
       'auto_remove_idle_users_interval': instance.autoRemoveIdleUsersInterval,
+      'payment_approval_threshold': instance.paymentApprovalThreshold,
     };
 
 IDInit _$IDInitFromJson(Map<String, dynamic> json) => IDInit(
@@ -1925,12 +1927,14 @@ SSPlacedOrder _$SSPlacedOrderFromJson(Map<String, dynamic> json) =>
     SSPlacedOrder(
       SSOrder.fromJson(json['order'] as Map<String, dynamic>),
       json['msg'] as String,
+      json['payment_uri'] as String? ?? '',
     );
 
 Map<String, dynamic> _$SSPlacedOrderToJson(SSPlacedOrder instance) =>
     <String, dynamic>{
       'order': instance.order,
       'msg': instance.msg,
+      'payment_uri': instance.paymentURI,
     };
 
 FetchedResource _$FetchedResourceFromJson(Map<String, dynamic> json) =>
@@ -1968,3 +1972,34 @@ Map<String, dynamic> _$HandshakeStageToJson(HandshakeStage instance) =>
       'uid': instance.uid,
       'stage': instance.stage,
     };
+
+PaymentApproval _$PaymentApprovalFromJson(Map<String, dynamic> json) =>
+    PaymentApproval(
+      json['id'] as int,
+      json['category'] as String,
+      json['uid'] as String?,
+      json['invoice'] as String,
+      json['matoms'] as int,
+      json['requested'] as int,
+    );
+
+Map<String, dynamic> _$PaymentApprovalToJson(PaymentApproval instance) =>
+    <String, dynamic>{
+      'id': instance.id,
+      'category': instance.category,
+      'uid': instance.uid,
+      'invoice': instance.invoice,
+      'matoms': instance.matoms,
+      'requested': instance.requested,
+    };
+
+DenyPayment _$DenyPaymentFromJson(Map<String, dynamic> json) => DenyPayment(
+      json['id'] as int,
+      json['reason'] as String,
+    );
+
+Map<String, dynamic> _$DenyPaymentToJson(DenyPayment instance) =>
+    <String, dynamic>{
+      'id': instance.id,
+      'reason': instance.reason,
+    };
@@ -119,11 +119,19 @@ func handleInitClient(handle uint32, args initClient) error {
 	var pc clientintf.PaymentClient = clientintf.FreePaymentClient{}
 	var lnpc *client.DcrlnPaymentClient
 	if args.LNRPCHost != "" && args.LNTLSCertPath != "" && args.LNMacaroonPath != "" {
+		// The embedded wallet uses the admin macaroon, so only warn
+		// about the macaroon scope of external wallets.
+		currentLndcMtx.Lock()
+		embedded := currentLndc != nil && currentLndc.RPCAddr() == args.LNRPCHost
+		currentLndcMtx.Unlock()
+
 		pcCfg := client.DcrlnPaymentClientCfg{
 			TLSCertPath:  args.LNTLSCertPath,
 			MacaroonPath: args.LNMacaroonPath,
 			Address:      args.LNRPCHost,
 			Log:          logBknd.logger("LNPY"),
+
+			WarnMacaroonScope: !embedded,
 		}
 		lnpc, err = client.NewDcrlndPaymentClient(context.Background(), pcCfg)
 		if err != nil {
@@ -349,6 +357,10 @@ func handleInitClient(handle uint32, args initClient) error {
 		notify(NTTipReceived, v, nil)
 	}))
 
+	ntfns.Register(client.OnPaymentApprovalRequestedNtfn(func(approval client.PaymentApproval) {
+		notify(NTPaymentApprovalReq, toPaymentApproval(approval), nil)
+	}))
+
 	ntfns.Register(client.OnPostsListReceived(func(user *client.RemoteUser, postList rpc.RMListPostsReply) {
 		v := userPostList{
 			UID:   user.ID(),
@@ -393,6 +405,8 @@ func handleInitClient(handle uint32, args initClient) error {
 		AutoHandshakeInterval:       time.Duration(args.AutoHandshakeInterval) * time.Second,
 		AutoRemoveIdleUsersInterval: time.Duration(args.AutoRemoveIdleUsersInterval) * time.Second,
 
+		PaymentApprovalThreshold: args.PaymentApprovalThreshold,
+
 		CertConfirmer: func(ctx context.Context, cs *tls.ConnectionState,
 			svrID *zkidentity.PublicIdentity) error {
 
@@ -591,16 +605,27 @@ func handleInitClient(handle uint32, args initClient) error {
 
 			OrderPlaced: func(order *simplestore.Order, msg string) {
 				event := simpleStoreOrder{
-					Order: *order,
-					Msg:   msg,
+					Order:      *order,
+					Msg:        msg,
+					PaymentURI: order.PaymentURI(),
 				}
 				notify(NTSimpleStoreOrderPlaced, event, nil)
 			},
 
 			StatusChanged: func(order *simplestore.Order, msg string) {
 				event := simpleStoreOrder{
-					Order: *order,
-					Msg:   msg,
+					Order:      *order,
+					Msg:        msg,
+					PaymentURI: order.PaymentURI(),
+				}
+				notify(NTSimpleStoreOrderPlaced, event, nil)
+			},
+
+			OrderPaid: func(order *simplestore.Order, msg string) {
+				event := simpleStoreOrder{
+					Order:      *order,
+					Msg:        msg,
+					PaymentURI: order.PaymentURI(),
 				}
 				notify(NTSimpleStoreOrderPlaced, event, nil)
 			},
@@ -658,6 +683,12 @@ func handleInitClient(handle uint32, args initClient) error {
 		go sstore.Run(ctx)
 	}
 
+	if lnpc != nil {
+		go lnpc.MonitorHealth(ctx, time.Minute, func(h client.DcrlnHealth) {
+			notify(NTLNHealthChanged, h, nil)
+		})
+	}
+
 	go func() {
 		err := c.Run(ctx)
 		if errors.Is(err, context.Canceled) {
@@ -1141,6 +1172,21 @@ func handleClientCmd(cc *clientCtx, cmd *cmd) (interface{}, error) {
 		}
 
 		ctx := context.Background()
+		amount := args.Amount * 1000
+		if amount == 0 {
+			payReq := &lnrpc.PayReqString{PayReq: args.PaymentRequest}
+			decoded, err := lnc.DecodePayReq(ctx, payReq)
+			if err != nil {
+				return nil, err
+			}
+			amount = decoded.NumMAtoms
+		}
+		err := c.WaitPaymentApproval(clientintf.PaymentCategoryManual,
+			args.PaymentRequest, amount)
+		if err != nil {
+			return nil, err
+		}
+
 		pc, err := lnc.SendPayment(ctx)
 		if err != nil {
 			return nil, err
@@ -1439,6 +1485,12 @@ func handleClientCmd(cc *clientCtx, cmd *cmd) (interface{}, error) {
 		return clientintf.EstimatePostSize(args, "")
 
 	case CTStopClient:
+		// Wait for outbound msgs to be sent before stopping.
+		ctx, cancel := context.WithTimeout(cc.ctx, 5*time.Second)
+		if err := c.Shutdown(ctx); err != nil {
+			cc.log.Errorf("Unable to gracefully shutdown client: %v", err)
+		}
+		cancel()
 		cc.cancel()
 		return nil, nil
 
@@ -1641,6 +1693,11 @@ func handleClientCmd(cc *clientCtx, cmd *cmd) (interface{}, error) {
 		if err := cmd.decode(&args); err != nil {
 			return nil, err
 		}
+		err := c.WaitPaymentApproval(clientintf.PaymentCategoryOnChain,
+			"", int64(args.Amount)*1000)
+		if err != nil {
+			return nil, err
+		}
 		req := &lnrpc.SendCoinsRequest{
 			Addr:    args.Addr,
 			Amount:  int64(args.Amount),
@@ -1801,6 +1858,28 @@ func handleClientCmd(cc *clientCtx, cmd *cmd) (interface{}, error) {
 			return nil, err
 		}
 		return res, nil
+
+	case CTListPaymentApprovals:
+		approvals := c.PendingPaymentApprovals()
+		res := make([]paymentApproval, len(approvals))
+		for i := range approvals {
+			res[i] = toPaymentApproval(approvals[i])
+		}
+		return res, nil
+
+	case CTApprovePayment:
+		var id uint64
+		if err := cmd.decode(&id); err != nil {
+			return nil, err
+		}
+		return nil, c.ApprovePayment(id)
+
+	case CTDenyPayment:
+		var args denyPayment
+		if err := cmd.decode(&args); err != nil {
+			return nil, err
+		}
+		return nil, c.DenyPayment(args.ID, args.Reason)
 	}
 	return nil, nil
 
@@ -1812,6 +1891,8 @@ func handleLNTryExternalDcrlnd(args lnTryExternalDcrlnd) (*lnrpc.GetInfoResponse
 		TLSCertPath:  args.TLSCertPath,
 		MacaroonPath: args.MacaroonPath,
 		Address:      args.RPCHost,
+
+		WarnMacaroonScope: true,
 	}
 	lnpc, err := client.NewDcrlndPaymentClient(ctx, pcCfg)
 	if err != nil {
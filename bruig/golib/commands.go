@@ -123,6 +123,9 @@ const (
 	CTLoadUserHistory                 = 0x78
 	CTAddressBookEntry                = 0x79
 	CTResetAllOldKX                   = 0x80
+	CTListPaymentApprovals            = 0x81
+	CTApprovePayment                  = 0x82
+	CTDenyPayment                     = 0x83
 
 	NTInviteReceived         = 0x1001
 	NTInviteAccepted         = 0x1002
@@ -164,6 +167,8 @@ const (
 	NTResourceFetched        = 0x1026
 	NTSimpleStoreOrderPlaced = 0x1027
 	NTHandshakeStage         = 0x1028
+	NTLNHealthChanged        = 0x1029
+	NTPaymentApprovalReq     = 0x102a
 )
 
 type cmd struct {
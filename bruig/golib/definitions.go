@@ -40,6 +40,8 @@ type initClient struct {
 
 	AutoHandshakeInterval       int64 `json:"auto_handshake_interval"`
 	AutoRemoveIdleUsersInterval int64 `json:"auto_remove_idle_users_interval"`
+
+	PaymentApprovalThreshold int64 `json:"payment_approval_threshold"`
 }
 
 type iDInit struct {
@@ -399,8 +401,9 @@ type fetchResourceArgs struct {
 }
 
 type simpleStoreOrder struct {
-	Order simplestore.Order `json:"order"`
-	Msg   string            `json:"msg"`
+	Order      simplestore.Order `json:"order"`
+	Msg        string            `json:"msg"`
+	PaymentURI string            `json:"payment_uri"`
 }
 
 type handshakeStage struct {
@@ -408,6 +411,20 @@ type handshakeStage struct {
 	Stage string            `json:"stage"`
 }
 
+type paymentApproval struct {
+	ID        uint64             `json:"id"`
+	Category  string             `json:"category"`
+	UID       *clientintf.UserID `json:"uid,omitempty"`
+	Invoice   string             `json:"invoice"`
+	MAtoms    int64              `json:"matoms"`
+	Requested int64              `json:"requested"`
+}
+
+type denyPayment struct {
+	ID     uint64 `json:"id"`
+	Reason string `json:"reason"`
+}
+
 type loadUserHistory struct {
 	UID     clientintf.UserID `json:"uid"`
 	GcName  string            `json:"gc_name"`
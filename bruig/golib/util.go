@@ -4,6 +4,8 @@ import (
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/hex"
+
+	"github.com/companyzero/bisonrelay/client"
 )
 
 func fingerprintDER(c *x509.Certificate) string {
@@ -12,3 +14,14 @@ func fingerprintDER(c *x509.Certificate) string {
 	digest := d.Sum(nil)
 	return hex.EncodeToString(digest)
 }
+
+func toPaymentApproval(approval client.PaymentApproval) paymentApproval {
+	return paymentApproval{
+		ID:        approval.ID,
+		Category:  string(approval.Category),
+		UID:       approval.UID,
+		Invoice:   approval.Invoice,
+		MAtoms:    approval.MAtoms,
+		Requested: approval.Requested.Unix(),
+	}
+}
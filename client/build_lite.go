@@ -0,0 +1,11 @@
+//go:build brlite
+// +build brlite
+
+package client
+
+// LiteBuild is true when the client is built with the brlite build tag. Lite
+// builds exclude the dcrlnd payment client and the onboarding of new users
+// (which depends on it), and are suitable for embedding the messaging
+// functions of the client in other applications. Lite clients must be
+// configured with a PaymentClient other than DcrlnPaymentClient.
+const LiteBuild = true
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"os"
 	"regexp"
 	"sync"
@@ -14,11 +15,15 @@ import (
 	"github.com/companyzero/bisonrelay/client/internal/gcmcacher"
 	"github.com/companyzero/bisonrelay/client/internal/lowlevel"
 	"github.com/companyzero/bisonrelay/client/internal/singlesetmap"
+	"github.com/companyzero/bisonrelay/client/metrics"
 	"github.com/companyzero/bisonrelay/client/resources"
 	"github.com/companyzero/bisonrelay/client/timestats"
+	"github.com/companyzero/bisonrelay/client/tracing"
+	"github.com/companyzero/bisonrelay/internal/imgreenc"
 	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/decred/slog"
+	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -138,6 +143,13 @@ type Config struct {
 	// If unspecified, a default value of 12 seconds (1/5 minute) is used.
 	TipUserPayRetryDelayFactor time.Duration
 
+	// TipUserKeysendFallback enables sending tips via keysend when a
+	// prior request for an invoice was not answered by the remote user
+	// (for example, because they are offline). This is only done when the
+	// LN node of the remote user is known (from prior invoices) and the
+	// payment client supports keysend payments.
+	TipUserKeysendFallback bool
+
 	// GCMQMaxLifetime is how long to wait for a message from an user,
 	// after which the GCMQ considers no other messages from this user
 	// will be received.
@@ -149,6 +161,43 @@ type Config struct {
 	// requests.
 	ResourcesProvider resources.Provider
 
+	// ResourceRateLimits are the rate limits applied to the fetch
+	// resource requests of each remote user. Requests above the limits are
+	// replied with a "too many requests" status.
+	ResourceRateLimits ResourceRateLimits
+
+	// ResourceConcurrency configures how many fetch resource requests are
+	// fulfilled at once, globally and for each path prefix.
+	ResourceConcurrency ResourceConcurrency
+
+	// TrustTiers configures the trust tiers of remote users, which gate
+	// the defaults of features such as automatic downloads and access to
+	// resources. If nil, trust tiers are disabled and all users are
+	// treated the same.
+	TrustTiers *TrustTiersConfig
+
+	// FeatureExtensions are optional extensions (along with their
+	// versions) supported by the local client, which are advertised to
+	// remote users with the rest of the client features.
+	FeatureExtensions map[string]uint64
+
+	// ImageReencode, if not nil, configures the downscaling and
+	// re-encoding of images sent to remote users with SendFile.
+	ImageReencode *imgreenc.Config
+
+	// PreSendFileHooks are called (in order) before files are sent to
+	// remote users with SendFile, and may replace the sent file (e.g. by a
+	// transcoded version of it).
+	PreSendFileHooks []PreSendFileHook
+
+	// PostReceiveFileHooks are called (in order) after file downloads
+	// complete.
+	PostReceiveFileHooks []PostReceiveFileHook
+
+	// FileHookTimeout is the max time each file hook may run. Defaults to
+	// DefaultFileHookTimeout.
+	FileHookTimeout time.Duration
+
 	// GCMQUpdtDelay is how often to check for GCMQ rules to emit messages.
 	//
 	// If unspecified, a default value of 1 second is used.
@@ -189,6 +238,121 @@ type Config struct {
 	// automatically removed from GCs the local client admins and will be
 	// automatically unsubscribed from posts.
 	AutoRemoveIdleUsersInterval time.Duration
+
+	// MaxAutoFetchRMSize is the max size of RMs that the server should
+	// automatically push to the client. Larger RMs are deferred until
+	// they are requested with FetchDeferredRMs. Zero means no limit. This
+	// is only respected by servers that support subscription filters.
+	MaxAutoFetchRMSize int
+
+	// LiteSync enables the lite sync startup mode. In this mode, only
+	// recent, small messages are received when the client first connects
+	// to the server, while older messages and large messages (such as
+	// file chunks) are fetched afterwards, in stages. Progress is reported
+	// through OnSyncProgressNtfn. This is only respected by servers that
+	// support subscription filters.
+	LiteSync bool
+
+	// MaxBacklogFetchPerMinute and MaxBacklogFeesPerMinute throttle how
+	// fast the backlog of deferred messages is fetched during lite sync,
+	// to avoid a sudden burst of payments for the subscriptions needed to
+	// receive a large backlog after reconnecting to the server. The fees
+	// are specified in milliatoms. Zero means no limit.
+	MaxBacklogFetchPerMinute int
+	MaxBacklogFeesPerMinute  int64
+
+	// PushWakeupGateway and PushWakeupToken, when specified, are
+	// registered with the server after connecting, such that the server
+	// notifies the gateway (passing only the opaque token) when new RMs
+	// are received while the client is offline. The registration is only
+	// done if the server advertises support for the gateway.
+	PushWakeupGateway string
+	PushWakeupToken   string
+
+	// FeePolicies are the max fee policies applied to outgoing payments of
+	// each category. Before paying, the route fee is estimated and the
+	// payment is skipped (with an OnPaymentFeeLimitExceededNtfn
+	// notification) if the estimated fee exceeds the category's limit.
+	// Categories without a policy are not limited.
+	FeePolicies map[clientintf.PaymentCategory]clientintf.FeePolicy
+
+	// PaymentApprovalThreshold enables the audit mode of outgoing
+	// payments when positive. In this mode, every outgoing payment (other
+	// than the payments of server fees) is recorded in the payment audit
+	// log, and payments of at least this amount (in milliatoms) are held
+	// in a queue of pending approvals until they are approved or denied
+	// with ApprovePayment or DenyPayment.
+	PaymentApprovalThreshold int64
+
+	// RatchetHealthCheckInterval is the interval between checks of the
+	// ratchets and KXs for anomalies, which are reported through
+	// OnRatchetHealthAlertNtfn. Defaults to one hour. A negative value
+	// disables the periodic checks.
+	RatchetHealthCheckInterval time.Duration
+
+	// DecryptFailuresAlertThreshold is the number of consecutive messages
+	// from a user that must fail to be decrypted before an alert is
+	// raised. Defaults to 10.
+	DecryptFailuresAlertThreshold int
+
+	// StalledKXAlertThreshold is the time after which a KX that has not
+	// completed raises an alert. Defaults to 3 days.
+	StalledKXAlertThreshold time.Duration
+
+	// PostAnnounceInterval is the min interval between announcements of
+	// new posts in a single GC. Posts published before the interval
+	// elapses are announced together at its end.
+	//
+	// If unspecified, a default value of 1 hour is used.
+	PostAnnounceInterval time.Duration
+
+	// TipThankYouCheckInterval is the interval between checks for whether
+	// to send the periodic thank-you summary of received tips (see
+	// SetTipStatsConfig). If negative, the summaries are not sent
+	// automatically.
+	//
+	// If unspecified, a default value of 10 minutes is used.
+	TipThankYouCheckInterval time.Duration
+
+	// ReceivedMsgIDsCleanupInterval is the interval between cleanups of
+	// the ids of received messages that already expired in the server.
+	// If negative, the ids are only cleaned up when the expiration of the
+	// server changes.
+	//
+	// If unspecified, a default value of 6 hours is used.
+	ReceivedMsgIDsCleanupInterval time.Duration
+
+	// Summarizer, if not nil, is the provider used to produce short
+	// summaries of long posts and of busy GC backlogs.
+	Summarizer Summarizer
+
+	// SummarizePostsMinLen is the min length (in bytes) of the content of
+	// received posts that are automatically summarized. If zero, posts are
+	// only summarized when requested with SummarizePost.
+	SummarizePostsMinLen int
+
+	// SummarizeGCBacklogMsgs is the number of GC messages received in a
+	// GC after which its backlog is automatically summarized. If zero, GC
+	// backlogs are only summarized when requested with
+	// SummarizeGCBacklog.
+	SummarizeGCBacklogMsgs int
+
+	// SummaryMaxLen is the max length (in bytes) requested of summaries.
+	// Defaults to DefaultSummaryMaxLen.
+	SummaryMaxLen int
+
+	// SummaryTimeout is the max time the summarizer may take to produce
+	// each summary. Defaults to DefaultSummaryTimeout.
+	SummaryTimeout time.Duration
+
+	// Tracer, when specified, records spans for the stages of sending
+	// messages to remote users (compose, encrypt, pay, push and ack), to
+	// diagnose slow message delivery.
+	Tracer *tracing.Tracer
+
+	// Metrics, when specified, records metrics of the work done by the
+	// client, such as RMs sent and received and resource fetch latencies.
+	Metrics *metrics.Metrics
 }
 
 // logger creates a logger for the given subsystem in the configured backend.
@@ -240,6 +404,31 @@ func (cfg *Config) setDefaults() {
 	if cfg.GCMQInitialDelay == 0 {
 		cfg.GCMQInitialDelay = time.Second * 10
 	}
+
+	if cfg.RatchetHealthCheckInterval == 0 {
+		cfg.RatchetHealthCheckInterval = time.Hour
+	}
+	if cfg.DecryptFailuresAlertThreshold == 0 {
+		cfg.DecryptFailuresAlertThreshold = 10
+	}
+	if cfg.StalledKXAlertThreshold == 0 {
+		cfg.StalledKXAlertThreshold = time.Hour * 24 * 3
+	}
+	if cfg.PostAnnounceInterval == 0 {
+		cfg.PostAnnounceInterval = time.Hour
+	}
+	if cfg.TipThankYouCheckInterval == 0 {
+		cfg.TipThankYouCheckInterval = 10 * time.Minute
+	}
+	if cfg.ReceivedMsgIDsCleanupInterval == 0 {
+		cfg.ReceivedMsgIDsCleanupInterval = 6 * time.Hour
+	}
+	if cfg.SummaryMaxLen == 0 {
+		cfg.SummaryMaxLen = DefaultSummaryMaxLen
+	}
+	if cfg.SummaryTimeout == 0 {
+		cfg.SummaryTimeout = DefaultSummaryTimeout
+	}
 }
 
 // Client is the main state manager for a CR client connection. It attempts to
@@ -273,6 +462,10 @@ type Client struct {
 	// done after the client starts.
 	firstSubDone chan struct{}
 
+	// syncProgress is the last reported progress of lite sync.
+	syncProgressMtx sync.Mutex
+	syncProgress    SyncProgress
+
 	svrLnNodeMtx sync.Mutex
 	svrLnNode    string
 
@@ -286,6 +479,16 @@ type Client struct {
 	// incompatible version has been issued.
 	gcWarnedVersions *singlesetmap.Map[zkidentity.ShortID]
 
+	// compatWarned tracks the compatibility and clock skew warnings
+	// already notified in this session.
+	compatWarned *singlesetmap.Map[string]
+
+	// clockSkews tracks the skew of the server and remote user clocks.
+	clockSkews clockSkews
+
+	// ratchetHealth tracks the active ratchet health alerts.
+	ratchetHealth ratchetHealth
+
 	// unkxdWarnings tracks the time used to warn about unkxd remote clients
 	// (for example, because they are GC members).
 	unkxdWarningsMtx sync.Mutex
@@ -305,6 +508,57 @@ type Client struct {
 	filtersMtx     sync.Mutex
 	filters        []clientdb.ContentFilter
 	filtersRegexps map[uint64]*regexp.Regexp
+
+	resRateLimiter *resourceRateLimiter
+
+	// resWorkers fulfills the incoming resource requests.
+	resWorkers *resourceWorkers
+
+	// resChunks tracks the chunks of resource replies received so far.
+	resChunks *resourceReplyChunks
+
+	// trustTiers caches the trust tier of remote users.
+	trustTiersMtx sync.Mutex
+	trustTiers    map[UserID]TrustTier
+
+	// remoteFeatures caches the features advertised by remote users and
+	// featuresRequested tracks when the features were last requested.
+	featuresMtx       sync.Mutex
+	remoteFeatures    map[UserID]*clientdb.RemoteFeatures
+	featuresRequested map[UserID]time.Time
+
+	// queryRunners are the runners of the saved queries of each kind,
+	// other than the built-in kinds.
+	queryRunnersMtx sync.Mutex
+	queryRunners    map[string]SavedQueryRunner
+
+	// payApprovals are the outgoing payments waiting for approval.
+	payApprovalsMtx   sync.Mutex
+	payApprovals      map[uint64]*pendingPaymentApproval
+	nextPayApprovalID uint64
+
+	// postAnnounces tracks the announcements of posts in each GC.
+	postAnnouncesMtx sync.Mutex
+	postAnnounces    map[zkidentity.ShortID]*gcPostAnnounces
+
+	// gcBacklogs tracks the number of messages received in each GC since
+	// its backlog was last summarized.
+	gcBacklogsMtx sync.Mutex
+	gcBacklogs    map[zkidentity.ShortID]int
+
+	// cleanShutdown is set by Shutdown() to record a clean shutdown marker
+	// once Run() finishes.
+	shutdownMtx   sync.Mutex
+	cleanShutdown bool
+
+	// sess is the current server session, or nil if the client is not
+	// connected to the server.
+	sessMtx sync.Mutex
+	sess    clientintf.ServerSessionIntf
+
+	// lastShutdown is the marker recorded during the last clean shutdown.
+	// It is nil if the last shutdown was not clean.
+	lastShutdown *clientdb.CleanShutdownMarker
 }
 
 // New creates a new CR client with the given config.
@@ -330,6 +584,11 @@ func New(cfg Config) (*Client, error) {
 	rmgrLog := cfg.logger("RVMR")
 	rmgrdb := &rvManagerDBAdapter{}
 	rmgr := lowlevel.NewRVManager(rmgrLog, rmgrdb, subsDelayer, subsDoneCB)
+	if cfg.LiteSync {
+		rmgr.SetInitialSubscriptionFilter(liteSyncFilter(&cfg))
+	} else if cfg.MaxAutoFetchRMSize > 0 {
+		rmgr.SetMaxAutoFetchSize(cfg.MaxAutoFetchRMSize)
+	}
 
 	// Wrap cert confirmer to update DB on successful confirmation from UI.
 	certConfirmer := func(ctx context.Context, cs *tls.ConnectionState,
@@ -394,6 +653,7 @@ func New(cfg Config) (*Client, error) {
 		firstSubDone:     make(chan struct{}),
 		newUsersChan:     make(chan *RemoteUser),
 		gcWarnedVersions: &singlesetmap.Map[zkidentity.ShortID]{},
+		compatWarned:     &singlesetmap.Map[string]{},
 		unkxdWarnings:    make(map[clientintf.UserID]time.Time),
 
 		onboardCancelChan: make(chan struct{}, 1),
@@ -401,6 +661,17 @@ func New(cfg Config) (*Client, error) {
 		tipAttemptsChan:            make(chan *clientdb.TipUserAttempt),
 		listRunningTipAttemptsChan: make(chan chan []RunningTipUserAttempt),
 		tipAttemptsRunning:         make(chan struct{}),
+
+		resRateLimiter: newResourceRateLimiter(cfg.ResourceRateLimits),
+		resChunks:      newResourceReplyChunks(),
+		resWorkers:     newResourceWorkers(cfg.ResourceConcurrency),
+		trustTiers:     make(map[UserID]TrustTier),
+
+		remoteFeatures:    make(map[UserID]*clientdb.RemoteFeatures),
+		featuresRequested: make(map[UserID]time.Time),
+
+		postAnnounces: make(map[zkidentity.ShortID]*gcPostAnnounces),
+		gcBacklogs:    make(map[zkidentity.ShortID]int),
 	}
 
 	// Use the GC message cacher to collect gc messages for a few seconds
@@ -562,6 +833,11 @@ func (c *Client) queueUnackedUserRM(ctx context.Context, unacked clientdb.Unacke
 // usually be at most one RM (i.e. the one being sent just before the last time
 // the client was executed).
 func (c *Client) queueUnackedUserRMs(ctx context.Context) error {
+	if c.lastShutdown != nil && c.lastShutdown.UnsentRMs == 0 {
+		c.log.Debugf("Skipping scan for unsent RMs after clean shutdown")
+		return nil
+	}
+
 	var unsents []clientdb.UnackedRM
 	err := c.db.View(ctx, func(tx clientdb.ReadTx) error {
 		var err error
@@ -599,6 +875,9 @@ func (c *Client) loadInitialDBData(ctx context.Context) error {
 	if err := c.loadContentFilters(ctx); err != nil {
 		return err
 	}
+	if err := c.loadLastShutdown(ctx); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -658,6 +937,12 @@ func (c *Client) NotificationManager() *NotificationManager {
 	return c.ntfns
 }
 
+// Metrics returns the metrics recorder of the client. It may be nil, but its
+// methods are safe to call on a nil value.
+func (c *Client) Metrics() *metrics.Metrics {
+	return c.cfg.Metrics
+}
+
 // PublicID is the public local identity of this client.
 func (c *Client) PublicID() UserID {
 	return c.id.Public.Identity
@@ -702,6 +987,69 @@ func (c *Client) RVsUpToDate() bool {
 	return c.rmgr.IsUpToDate()
 }
 
+// DeferredRM is an RM that was not automatically pushed by the server due to
+// its size.
+type DeferredRM = lowlevel.DeferredRM
+
+// DeferredRMs returns the list of RMs that were not automatically pushed by
+// the server because they are larger than the configured MaxAutoFetchRMSize.
+func (c *Client) DeferredRMs() []DeferredRM {
+	return c.rmgr.DeferredRMs()
+}
+
+// FetchDeferredRMs requests the server to push all RMs that were previously
+// deferred because they were larger than the configured MaxAutoFetchRMSize.
+func (c *Client) FetchDeferredRMs(ctx context.Context) error {
+	return c.rmgr.FetchDeferredRMs(ctx)
+}
+
+// registerWakeup registers the configured push gateway wakeup with the passed
+// server session.
+func (c *Client) registerWakeup(sess clientintf.ServerSessionIntf) {
+	gateway := c.cfg.PushWakeupGateway
+	if !slices.Contains(sess.Policy().PushGateways, gateway) {
+		c.warnCompat(nil, CompatWarning{
+			Feature: "push-gateway",
+			Detail: fmt.Sprintf("Server does not support push gateway "+
+				"%q, so push wakeups are disabled", gateway),
+			Action: "Ask the server operator to enable the gateway",
+		})
+		return
+	}
+
+	msg := rpc.Message{Command: rpc.TaggedCmdRegisterWakeup}
+	payload := &rpc.RegisterWakeup{
+		Gateway: gateway,
+		Token:   c.cfg.PushWakeupToken,
+	}
+	replyChan := make(chan interface{})
+	if err := sess.SendPRPC(msg, payload, replyChan); err != nil {
+		c.log.Errorf("Unable to register push wakeup: %v", err)
+		return
+	}
+
+	var reply interface{}
+	select {
+	case reply = <-replyChan:
+	case <-sess.Context().Done():
+		return
+	}
+
+	switch reply := reply.(type) {
+	case *rpc.RegisterWakeupReply:
+		if reply.Error != "" {
+			c.log.Errorf("Server rejected push wakeup registration: %s",
+				reply.Error)
+			return
+		}
+		c.log.Debugf("Registered push wakeup with gateway %s", gateway)
+	case error:
+		c.log.Errorf("Unable to register push wakeup: %v", reply)
+	default:
+		c.log.Errorf("Unknown reply to push wakeup registration: %v", reply)
+	}
+}
+
 // RMQTimingStat returns the latest timing stats for the outbound RMQ.
 func (c *Client) RMQTimingStat() []timestats.Quantile {
 	return c.q.TimingStats()
@@ -757,6 +1105,15 @@ func (c *Client) UIDByNick(nick string) (UserID, error) {
 	<-c.abLoaded
 	ru, err := c.rul.byNick(nick)
 	if err != nil {
+		// Fallback to the nicks of contacts merged into other users.
+		var uid UserID
+		if dbErr := c.dbView(func(tx clientdb.ReadTx) error {
+			var err error
+			uid, err = c.db.FindMergedContactNick(tx, nick)
+			return err
+		}); dbErr == nil {
+			return uid, nil
+		}
 		return UserID{}, err
 	}
 	return ru.ID(), nil
@@ -983,6 +1340,7 @@ func (c *Client) Run(ctx context.Context) error {
 		// finished.
 		c.log.Tracef("Starting to wait for DB shutdown")
 		time.Sleep(300 * time.Millisecond)
+		c.recordCleanShutdown()
 		c.log.Tracef("Shutting down db context")
 		c.dbCtxCancel()
 		return nil
@@ -1087,10 +1445,13 @@ func (c *Client) Run(ctx context.Context) error {
 					c.log.Infof("Cleaning up expired RVs "+
 						"older than %d days", expDays)
 					c.cleanupPaidRVsDir(nextSess.ExpirationDays())
+					c.cleanupReceivedMsgIDs(expDays)
 					lastExpDays = expDays
 				}
 
 				c.cleanupPushPaymentAttempts(nextSess.Policy().PushPaymentLifetime)
+				c.checkServerCompat(nextSess)
+				c.checkServerClock(nextSess)
 			} else {
 				// c.gcmq.SessionChanged(true) is called after
 				// the initial batch of subscriptions is done
@@ -1100,6 +1461,12 @@ func (c *Client) Run(ctx context.Context) error {
 
 			c.rmgr.BindToSession(nextSess)
 			c.q.BindToSession(nextSess)
+			c.sessMtx.Lock()
+			c.sess = nextSess
+			c.sessMtx.Unlock()
+			if nextSess != nil && c.cfg.PushWakeupToken != "" {
+				go c.registerWakeup(nextSess)
+			}
 			connected := nextSess != nil
 			c.ntfns.notifyServerSessionChanged(connected, pushRate, subRate, uint64(expDays))
 			if canceled(gctx) {
@@ -1204,6 +1571,17 @@ func (c *Client) Run(ctx context.Context) error {
 	// Run tip user payments.
 	g.Go(func() error { return c.runTipAttempts(gctx) })
 
+	// Export trace spans.
+	if c.cfg.Tracer != nil {
+		g.Go(func() error { return c.cfg.Tracer.Run(gctx) })
+	}
+
+	// Periodically check the health of ratchets and KXs.
+	g.Go(func() error { return c.runRatchetHealthChecks(gctx) })
+
+	// Track tips received via keysend.
+	g.Go(func() error { return c.trackKeysendPayments(gctx) })
+
 	// Restart client onboarding.
 	g.Go(func() error { return c.restartOnboarding(gctx) })
 
@@ -1213,5 +1591,19 @@ func (c *Client) Run(ctx context.Context) error {
 	// Restart tracking tip receiving.
 	g.Go(func() error { return c.restartTrackGeneratedTipInvoices(gctx) })
 
+	// Send thank-you summaries of received tips.
+	g.Go(func() error { return c.runTipThankYous(gctx) })
+
+	// Cleanup the ids of received messages.
+	g.Go(func() error { return c.runReceivedMsgIDsCleanup(gctx) })
+
+	// Revoke expired guest views.
+	g.Go(func() error { return c.runGuestViewsExpiry(gctx) })
+
+	// Fetch deferred messages in stages when running in lite sync mode.
+	if c.cfg.LiteSync {
+		g.Go(func() error { return c.runLiteSync(gctx) })
+	}
+
 	return g.Wait()
 }
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/internal/mdembeds"
+)
+
+// ListUserAttachments lists the files downloaded from the user, in the order
+// they were received.
+func (c *Client) ListUserAttachments(uid UserID) ([]clientdb.Attachment, error) {
+	var res []clientdb.Attachment
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.ListAttachments(tx, uid)
+		return err
+	})
+	return res, err
+}
+
+// MessageAttachments returns the downloaded files linked in a message received
+// from the user. Files linked in the message that were not downloaded yet are
+// not returned.
+func (c *Client) MessageAttachments(uid UserID, msg string) ([]clientdb.Attachment, error) {
+	var fids []clientdb.FileID
+	mdembeds.ReplaceEmbeds(msg, func(args mdembeds.EmbeddedArgs) string {
+		if !args.Download.IsEmpty() {
+			fids = append(fids, args.Download)
+		}
+		return ""
+	})
+	if len(fids) == 0 {
+		return nil, nil
+	}
+
+	var res []clientdb.Attachment
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		for _, fid := range fids {
+			att, err := c.db.GetAttachment(tx, uid, fid)
+			if errors.Is(err, clientdb.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			res = append(res, *att)
+		}
+		return nil
+	})
+	return res, err
+}
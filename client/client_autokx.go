@@ -151,6 +151,11 @@ func (c *Client) handleMediateID(ru *RemoteUser, mi rpc.RMMediateIdentity) error
 			zkidentity.ShortID(mi.Identity))
 		return err
 	}
+	if !c.userTrustPolicy(ru).AutoMediateID {
+		ru.log.Infof("Ignoring request to mediate id to %s due to "+
+			"trust tier policy", target)
+		return nil
+	}
 	ru.log.Infof("Asked to mediate id to %s", target)
 
 	// Ask target to generate an identity invite.
@@ -0,0 +1,251 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	// serverClockSkewThreshold is the max offset between the local and
+	// server clocks before the local clock is considered skewed. Invoices
+	// are created and checked against the clocks of different hosts, so
+	// larger offsets may cause valid invoices to be considered expired.
+	serverClockSkewThreshold = 2 * time.Minute
+
+	// peerClockSkewThreshold is the max offset between the clock of a
+	// remote user and the local clock before the clock of the remote user
+	// is considered skewed. This is larger than serverClockSkewThreshold
+	// to account for the delay of RMs in transit.
+	peerClockSkewThreshold = 10 * time.Minute
+
+	// peerClockSkewSamples is the number of the most recent samples used
+	// to estimate the clock skew of a remote user.
+	peerClockSkewSamples = 16
+
+	// minPeerClockSkewSamples is the min number of samples needed to
+	// estimate the clock skew of a remote user.
+	minPeerClockSkewSamples = 3
+)
+
+// ClockSkewWarning is a warning about a clock that is skewed enough to break
+// the expiry of invoices and the timestamps of messages and orders.
+type ClockSkewWarning struct {
+	// Skew is the offset of the local clock relative to the server clock
+	// (for warnings about the local clock) or of the clock of the remote
+	// user relative to the local clock.
+	Skew time.Duration
+
+	// Detail describes the consequences of the skew.
+	Detail string
+
+	// Action is the action that may be taken to fix the skew.
+	Action string
+}
+
+// String returns the warning as a human readable message.
+func (w ClockSkewWarning) String() string {
+	return fmt.Sprintf("%s. %s", w.Detail, w.Action)
+}
+
+// describeSkew returns whether the skew is ahead or behind the reference
+// clock.
+func describeSkew(skew time.Duration) string {
+	if skew < 0 {
+		return fmt.Sprintf("%s behind", (-skew).Round(time.Second))
+	}
+	return fmt.Sprintf("%s ahead of", skew.Round(time.Second))
+}
+
+// peerClockSkew are the samples of the clock skew of a remote user.
+type peerClockSkew struct {
+	samples []time.Duration
+	next    int
+}
+
+// add adds a sample, replacing the oldest one when the max number of samples
+// is reached.
+func (p *peerClockSkew) add(skew time.Duration) {
+	if len(p.samples) < peerClockSkewSamples {
+		p.samples = append(p.samples, skew)
+		return
+	}
+	p.samples[p.next] = skew
+	p.next = (p.next + 1) % peerClockSkewSamples
+}
+
+// estimate returns the median of the samples. The median is used so that RMs
+// delayed in transit (for example, sent while offline) do not skew the
+// estimate.
+func (p *peerClockSkew) estimate() (time.Duration, bool) {
+	if len(p.samples) < minPeerClockSkewSamples {
+		return 0, false
+	}
+	sorted := append([]time.Duration(nil), p.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[len(sorted)/2], true
+}
+
+// clockSkews tracks the skew of the server clock and of the clocks of remote
+// users relative to the local clock.
+type clockSkews struct {
+	mtx         sync.Mutex
+	server      time.Duration
+	serverKnown bool
+	peers       map[clientintf.UserID]*peerClockSkew
+}
+
+// setServer sets the offset of the server clock.
+func (cs *clockSkews) setServer(skew time.Duration) {
+	cs.mtx.Lock()
+	cs.server = skew
+	cs.serverKnown = true
+	cs.mtx.Unlock()
+}
+
+// addPeerSample adds a sample of the skew of the clock of a remote user, given
+// the timestamp of an RM in the clock of the user and the time it was received
+// by the server in the server clock. It returns the updated estimate of the
+// skew.
+func (cs *clockSkews) addPeerSample(uid clientintf.UserID, sentTS, serverTS time.Time) (time.Duration, bool) {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+
+	// Without the offset of the server clock, the server time cannot be
+	// converted to the local clock.
+	if !cs.serverKnown {
+		return 0, false
+	}
+	if cs.peers == nil {
+		cs.peers = make(map[clientintf.UserID]*peerClockSkew)
+	}
+	p := cs.peers[uid]
+	if p == nil {
+		p = &peerClockSkew{}
+		cs.peers[uid] = p
+	}
+	p.add(sentTS.Sub(serverTS.Add(-cs.server)))
+	return p.estimate()
+}
+
+// toLocal converts a timestamp of the server clock to the local clock, if the
+// local clock is skewed relative to the server clock.
+func (cs *clockSkews) toLocal(ts time.Time) time.Time {
+	cs.mtx.Lock()
+	skew := cs.server
+	cs.mtx.Unlock()
+	if skew > -serverClockSkewThreshold && skew < serverClockSkewThreshold {
+		return ts
+	}
+	return ts.Add(-skew)
+}
+
+// peer returns the estimated skew of the clock of a remote user.
+func (cs *clockSkews) peer(uid clientintf.UserID) (time.Duration, bool) {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+	if p := cs.peers[uid]; p != nil {
+		return p.estimate()
+	}
+	return 0, false
+}
+
+// ServerClockSkew returns the offset of the server clock relative to the local
+// clock, measured when connecting to the server. Returns false if the client
+// has not connected to the server yet.
+func (c *Client) ServerClockSkew() (time.Duration, bool) {
+	c.clockSkews.mtx.Lock()
+	defer c.clockSkews.mtx.Unlock()
+	return c.clockSkews.server, c.clockSkews.serverKnown
+}
+
+// PeerClockSkew returns the estimated offset of the clock of the remote user
+// relative to the local clock, based on the timestamps of the RMs received
+// from the user. Returns false if not enough RMs were received from the user
+// to estimate it.
+func (c *Client) PeerClockSkew(uid clientintf.UserID) (time.Duration, bool) {
+	return c.clockSkews.peer(uid)
+}
+
+// NormalizeTimestamp converts a timestamp taken by the clock of the remote user
+// (for example, the timestamp of a GC message) to the local clock, so that
+// messages from users with skewed clocks are ordered correctly when
+// displayed. The timestamp is returned unchanged if the skew of the clock of
+// the user is not known or is within the expected delay of RMs.
+func (c *Client) NormalizeTimestamp(uid clientintf.UserID, ts time.Time) time.Time {
+	skew, ok := c.clockSkews.peer(uid)
+	if !ok || (skew > -peerClockSkewThreshold && skew < peerClockSkewThreshold) {
+		return ts
+	}
+	return ts.Add(-skew)
+}
+
+// checkServerClock records the offset of the server clock of the session and
+// warns if the local clock is skewed relative to it.
+func (c *Client) checkServerClock(sess clientintf.ServerSessionIntf) {
+	skew := sess.ClockSkew()
+	c.clockSkews.setServer(skew)
+	if skew > -serverClockSkewThreshold && skew < serverClockSkewThreshold {
+		return
+	}
+
+	detail := fmt.Sprintf("Local clock is %s the server clock, so "+
+		"invoices may be considered expired before (or after) they "+
+		"actually expire and the timestamps of sent messages and "+
+		"orders are wrong", describeSkew(-skew))
+	if lifetime := sess.Policy().PushPaymentLifetime; skew > lifetime || -skew > lifetime {
+		detail = fmt.Sprintf("Local clock is %s the server clock, "+
+			"which is more than the lifetime of the invoices of the "+
+			"server (%s), so paying for messages will fail",
+			describeSkew(-skew), lifetime)
+	}
+	c.warnClockSkew(nil, ClockSkewWarning{
+		Skew:   -skew,
+		Detail: detail,
+		Action: "Synchronize the local clock (for example, with NTP)",
+	})
+}
+
+// checkUserClock samples the skew of the clock of the remote user from the
+// timestamp of an RM received from them and warns if the clock of the user is
+// skewed.
+func (c *Client) checkUserClock(ru *RemoteUser, h *rpc.RMHeader, serverTS time.Time) {
+	if h.Timestamp == 0 || serverTS.IsZero() {
+		return
+	}
+	skew, ok := c.clockSkews.addPeerSample(ru.ID(), time.Unix(h.Timestamp, 0), serverTS)
+	if !ok || (skew > -peerClockSkewThreshold && skew < peerClockSkewThreshold) {
+		return
+	}
+	c.warnClockSkew(ru, ClockSkewWarning{
+		Skew: skew,
+		Detail: fmt.Sprintf("Clock of the remote client is %s the "+
+			"local clock, so the timestamps of their messages "+
+			"are wrong and invoices sent to them may be "+
+			"considered expired", describeSkew(skew)),
+		Action: "Ask them to synchronize their clock",
+	})
+}
+
+// warnClockSkew logs and notifies the clock skew warning, if it has not been
+// notified yet in this session. ru is nil for warnings about the local clock.
+func (c *Client) warnClockSkew(ru *RemoteUser, warn ClockSkewWarning) {
+	key := "server/clock-skew"
+	if ru != nil {
+		key = ru.ID().String() + "/clock-skew"
+	}
+	if c.compatWarned.Set(key) {
+		return
+	}
+
+	if ru != nil {
+		ru.log.Warnf("Clock skew warning: %s", warn.Detail)
+	} else {
+		c.log.Warnf("Clock skew warning: %s", warn.Detail)
+	}
+	c.ntfns.notifyClockSkewWarning(ru, warn)
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/internal/singlesetmap"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/slog"
+)
+
+// skewedServerSession is a server session with a skewed clock.
+type skewedServerSession struct {
+	skew time.Duration
+}
+
+func (s skewedServerSession) SendPRPC(rpc.Message, interface{}, chan<- interface{}) error {
+	return nil
+}
+func (s skewedServerSession) RequestClose(error)                  {}
+func (s skewedServerSession) PayClient() clientintf.PaymentClient { return nil }
+func (s skewedServerSession) PaymentRates() (uint64, uint64)      { return 0, 0 }
+func (s skewedServerSession) ExpirationDays() int                 { return 7 }
+func (s skewedServerSession) Context() context.Context            { return context.Background() }
+func (s skewedServerSession) ClockSkew() time.Duration            { return s.skew }
+func (s skewedServerSession) Policy() clientintf.ServerPolicy {
+	return clientintf.ServerPolicy{PushPaymentLifetime: time.Hour}
+}
+
+// TestClockSkew tests the detection of skewed local and remote user clocks and
+// the normalization of timestamps.
+func TestClockSkew(t *testing.T) {
+	t.Parallel()
+
+	rnd := testRand(t)
+	aliceID := testID(t, rnd, "alice")
+	bobID := testID(t, rnd, "bob")
+
+	ntfns := NewNotificationManager()
+	var localWarns, userWarns []ClockSkewWarning
+	ntfns.RegisterSync(OnClockSkewWarningNtfn(func(ru *RemoteUser, warn ClockSkewWarning) {
+		if ru == nil {
+			localWarns = append(localWarns, warn)
+		} else {
+			userWarns = append(userWarns, warn)
+		}
+	}))
+	c := &Client{
+		id:           aliceID,
+		ntfns:        ntfns,
+		log:          slog.Disabled,
+		compatWarned: &singlesetmap.Map[string]{},
+	}
+	ru := newRemoteUser(nil, nil, nil, &bobID.Public, aliceID, nil)
+
+	// The server clock is 5 minutes ahead of the local clock.
+	serverSkew := 5 * time.Minute
+	c.checkServerClock(skewedServerSession{skew: serverSkew})
+	c.checkServerClock(skewedServerSession{skew: serverSkew})
+	if len(localWarns) != 1 {
+		t.Fatalf("unexpected nb of local clock warnings: %d", len(localWarns))
+	}
+	if localWarns[0].Skew != -serverSkew {
+		t.Fatalf("unexpected local clock skew: got %s, want %s",
+			localWarns[0].Skew, -serverSkew)
+	}
+	if gotSkew, ok := c.ServerClockSkew(); !ok || gotSkew != serverSkew {
+		t.Fatalf("unexpected server clock skew: got %s %v", gotSkew, ok)
+	}
+
+	// Server timestamps are converted to the local clock.
+	now := time.Now()
+	if got := c.clockSkews.toLocal(now.Add(serverSkew)); !got.Equal(now) {
+		t.Fatalf("unexpected local timestamp: got %s, want %s", got, now)
+	}
+
+	// Bob's clock is one hour behind the local clock. One of the RMs was
+	// delayed in transit, which does not affect the estimate.
+	peerSkew := -time.Hour
+	delays := []time.Duration{time.Second, 2 * time.Hour, time.Second, 2 * time.Second}
+	for i, delay := range delays {
+		sentTS := now.Add(peerSkew)
+		serverTS := now.Add(delay).Add(serverSkew)
+		h := &rpc.RMHeader{Timestamp: sentTS.Unix()}
+		c.checkUserClock(ru, h, serverTS)
+
+		if i < minPeerClockSkewSamples-1 {
+			if _, ok := c.PeerClockSkew(bobID.Public.Identity); ok {
+				t.Fatalf("unexpected estimate after %d samples", i+1)
+			}
+			if len(userWarns) != 0 {
+				t.Fatalf("unexpected warning after %d samples", i+1)
+			}
+		}
+	}
+	gotSkew, ok := c.PeerClockSkew(bobID.Public.Identity)
+	if !ok {
+		t.Fatalf("peer clock skew not estimated")
+	}
+	if diff := gotSkew - peerSkew; diff < -5*time.Second || diff > 0 {
+		t.Fatalf("unexpected peer clock skew: got %s, want %s", gotSkew, peerSkew)
+	}
+	if len(userWarns) != 1 {
+		t.Fatalf("unexpected nb of user clock warnings: %d", len(userWarns))
+	}
+
+	// Timestamps of Bob's clock are normalized to the local clock.
+	peerTS := now.Add(peerSkew)
+	got := c.NormalizeTimestamp(bobID.Public.Identity, peerTS)
+	if diff := got.Sub(now); diff < 0 || diff > 5*time.Second {
+		t.Fatalf("unexpected normalized timestamp: got %s, want %s", got, now)
+	}
+
+	// Timestamps of users without enough samples are not changed.
+	if got := c.NormalizeTimestamp(aliceID.Public.Identity, peerTS); !got.Equal(peerTS) {
+		t.Fatalf("timestamp of unknown user was normalized")
+	}
+}
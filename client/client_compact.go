@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+)
+
+// CompactionProgress is the progress of the compaction of the client storage.
+type CompactionProgress struct {
+	// Stage is the stage of the last compacted target.
+	Stage clientdb.CompactionStage
+
+	// Done and Total are the number of targets compacted so far and the
+	// total number of targets to compact.
+	Done  int
+	Total int
+
+	// Reclaimed is the number of bytes reclaimed so far.
+	Reclaimed int64
+}
+
+// CompactionResult is the result of the compaction of the client storage.
+type CompactionResult struct {
+	// Compacted and Reclaimed are the number of targets that were
+	// compacted and the number of bytes reclaimed in each stage.
+	Compacted map[clientdb.CompactionStage]int
+	Reclaimed map[clientdb.CompactionStage]int64
+
+	// Failed is the number of targets that failed to be compacted.
+	Failed int
+}
+
+// TotalReclaimed returns the total number of bytes reclaimed.
+func (res *CompactionResult) TotalReclaimed() int64 {
+	var total int64
+	for _, n := range res.Reclaimed {
+		total += n
+	}
+	return total
+}
+
+// CompactStorage compacts the client storage: it drops the redundant entries
+// of the message logs, prunes the chunks of completed or canceled downloads
+// and rewrites the append-only JSON stores without their corrupted entries.
+//
+// The storage is compacted one file at a time, so the client may keep running
+// while it is compacted. The progress is notified through the
+// OnCompactionProgressNtfn notification.
+func (c *Client) CompactStorage(ctx context.Context) (*CompactionResult, error) {
+	var targets []clientdb.CompactionTarget
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		targets, err = c.db.CompactionTargets(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CompactionResult{
+		Compacted: make(map[clientdb.CompactionStage]int),
+		Reclaimed: make(map[clientdb.CompactionStage]int64),
+	}
+	var reclaimed int64
+	for i, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+
+		var n int64
+		err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+			var err error
+			n, err = c.db.CompactTarget(tx, target)
+			return err
+		})
+		if err != nil {
+			c.log.Warnf("Unable to compact %s: %v", target.Path, err)
+			res.Failed += 1
+		} else if n != 0 {
+			res.Compacted[target.Stage] += 1
+			res.Reclaimed[target.Stage] += n
+			reclaimed += n
+		}
+
+		c.ntfns.notifyCompactionProgress(CompactionProgress{
+			Stage:     target.Stage,
+			Done:      i + 1,
+			Total:     len(targets),
+			Reclaimed: reclaimed,
+		})
+	}
+
+	c.log.Infof("Compacted storage: %d targets, reclaimed %d bytes (%d "+
+		"failed)", len(targets), reclaimed, res.Failed)
+	return res, nil
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/decred/slog"
+)
+
+// TestCompactStorage tests that compacting the client storage drops the
+// redundant markers of message logs, prunes orphaned download chunks and
+// stale temp files and drops the corrupted entries of JSON stores.
+func TestCompactStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := testDBConfig(t, nil, nil)
+	root := cfg.Root
+	msgsRoot := filepath.Join(root, "logs")
+	cfg.MsgsRoot = msgsRoot
+	db, err := clientdb.New(cfg)
+	orFatal(t, err)
+	runTestDB(t, db)
+
+	writeFile := func(fname, data string) {
+		t.Helper()
+		orFatal(t, os.MkdirAll(filepath.Dir(fname), 0o700))
+		orFatal(t, os.WriteFile(fname, []byte(data), 0o600))
+	}
+
+	// Message log of a client restarted twice on the same day and once
+	// on the next day.
+	logFname := filepath.Join(msgsRoot, "alice.0000.log")
+	writeFile(logFname, strings.Join([]string{
+		"2024-01-01T10:00:00 * Conversation started 2024-01-01",
+		"2024-01-01T10:00:00 <alice> hello",
+		"2024-01-01T11:00:00 * Conversation started 2024-01-01",
+		"2024-01-01T11:00:00 <alice> again",
+		"multiline",
+		"2024-01-02T09:00:00 * Conversation started 2024-01-02",
+		"2024-01-02T09:00:00 * internal msg",
+		"",
+	}, "\n"))
+	wantLog := strings.Join([]string{
+		"2024-01-01T10:00:00 * Conversation started 2024-01-01",
+		"2024-01-01T10:00:00 <alice> hello",
+		"2024-01-01T11:00:00 <alice> again",
+		"multiline",
+		"2024-01-02T09:00:00 * Day Changed to 2024-01-02",
+		"2024-01-02T09:00:00 * internal msg",
+		"",
+	}, "\n")
+
+	// Chunks of a canceled download and a stale temp file.
+	chunkDir := filepath.Join(root, "downloading", "0102.chunks")
+	writeFile(filepath.Join(chunkDir, "aabb"), "chunk")
+	tempFname := filepath.Join(root, ".blockedusers.json.new")
+	writeFile(tempFname, "{")
+	old := time.Now().Add(-2 * time.Hour)
+	orFatal(t, os.Chtimes(tempFname, old, old))
+
+	// JSON store with an entry truncated by a crash.
+	proofsFname := filepath.Join(root, "inbound", "0000", "payment-proofs.json")
+	writeFile(proofsFname, "{\"a\":1}\n{\"b\":\n{\"c\":3}\n")
+
+	ntfns := NewNotificationManager()
+	var progress []CompactionProgress
+	ntfns.RegisterSync(OnCompactionProgressNtfn(func(p CompactionProgress) {
+		progress = append(progress, p)
+	}))
+	c := &Client{
+		db:    db,
+		dbCtx: ctx,
+		ntfns: ntfns,
+		log:   slog.Disabled,
+	}
+	res, err := c.CompactStorage(ctx)
+	orFatal(t, err)
+
+	gotLog, err := os.ReadFile(logFname)
+	orFatal(t, err)
+	if string(gotLog) != wantLog {
+		t.Fatalf("unexpected compacted log: got\n%s\nwant\n%s", gotLog, wantLog)
+	}
+	if _, err := os.Stat(chunkDir); !os.IsNotExist(err) {
+		t.Fatalf("orphaned chunks dir was not removed: %v", err)
+	}
+	if _, err := os.Stat(tempFname); !os.IsNotExist(err) {
+		t.Fatalf("stale temp file was not removed: %v", err)
+	}
+	gotProofs, err := os.ReadFile(proofsFname)
+	orFatal(t, err)
+	if want := "{\"a\":1}\n{\"c\":3}\n"; string(gotProofs) != want {
+		t.Fatalf("unexpected rewritten JSON store: got %q, want %q", gotProofs, want)
+	}
+
+	if res.Failed != 0 {
+		t.Fatalf("unexpected failed targets: %d", res.Failed)
+	}
+	wantCompacted := map[clientdb.CompactionStage]int{
+		clientdb.CompactionStageLogs:   1,
+		clientdb.CompactionStageChunks: 2,
+		clientdb.CompactionStageJSON:   1,
+	}
+	for stage, want := range wantCompacted {
+		if got := res.Compacted[stage]; got != want {
+			t.Fatalf("unexpected nb of compacted %s targets: got %d, want %d",
+				stage, got, want)
+		}
+	}
+	if len(progress) == 0 {
+		t.Fatalf("no progress notified")
+	}
+	last := progress[len(progress)-1]
+	if last.Done != last.Total || last.Reclaimed != res.TotalReclaimed() {
+		t.Fatalf("unexpected final progress %+v", last)
+	}
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// CompatWarning is a warning about a feature that is degraded or disabled
+// because a remote user or the server runs an older (or newer) version of the
+// protocol than the local client.
+type CompatWarning struct {
+	// Feature is the name of the affected feature.
+	Feature string
+
+	// Detail describes the incompatibility and the fallback behavior
+	// adopted by the local client.
+	Detail string
+
+	// Action is the action that may be taken to fix the incompatibility.
+	Action string
+}
+
+// String returns the warning as a human readable message.
+func (w CompatWarning) String() string {
+	if w.Action == "" {
+		return w.Detail
+	}
+	return fmt.Sprintf("%s. %s", w.Detail, w.Action)
+}
+
+// warnCompat logs and notifies the compatibility warning, if it has not been
+// notified yet in this session. ru is nil for warnings about the server.
+func (c *Client) warnCompat(ru *RemoteUser, warn CompatWarning) {
+	key := "server/" + warn.Feature
+	if ru != nil {
+		key = ru.ID().String() + "/" + warn.Feature
+	}
+	if c.compatWarned.Set(key) {
+		return
+	}
+
+	if ru != nil {
+		ru.log.Warnf("Compatibility warning for feature %q: %s",
+			warn.Feature, warn.Detail)
+	} else {
+		c.log.Warnf("Server compatibility warning for feature %q: %s",
+			warn.Feature, warn.Detail)
+	}
+	c.ntfns.notifyCompatWarning(ru, warn)
+}
+
+// checkUserCompat checks the capabilities advertised by the remote user in an
+// RM for incompatibilities with the local client.
+func (c *Client) checkUserCompat(ru *RemoteUser, h *rpc.RMHeader) {
+	if !h.Capabilities.Has(rpc.RMCapFeatures) {
+		c.warnCompat(ru, CompatWarning{
+			Feature: "features",
+			Detail: "Remote client does not support feature negotiation, " +
+				"so only the baseline messages will be sent to them",
+			Action: "Ask them to update their client",
+		})
+	}
+	if !h.Capabilities.Has(rpc.RMCapZstdCompression) {
+		c.warnCompat(ru, CompatWarning{
+			Feature: "zstd",
+			Detail: "Remote client does not support zstd compression, " +
+				"so messages to them are compressed with zlib",
+			Action: "Ask them to update their client",
+		})
+	}
+}
+
+// checkUserFeaturesCompat checks the features advertised by the remote user
+// for incompatibilities with the local client.
+func (c *Client) checkUserFeaturesCompat(ru *RemoteUser, f *rpc.RMFeatures) {
+	switch {
+	case f.Version < rpc.RMFeaturesVersion:
+		c.warnCompat(ru, CompatWarning{
+			Feature: "features-version",
+			Detail: fmt.Sprintf("Remote client advertised features "+
+				"version %d, older than the local version %d",
+				f.Version, rpc.RMFeaturesVersion),
+			Action: "Ask them to update their client",
+		})
+	case f.Version > rpc.RMFeaturesVersion:
+		c.warnCompat(ru, CompatWarning{
+			Feature: "features-version",
+			Detail: fmt.Sprintf("Remote client advertised features "+
+				"version %d, newer than the local version %d",
+				f.Version, rpc.RMFeaturesVersion),
+			Action: "Update the local client to use their newer features",
+		})
+	}
+
+	var missing []string
+	for _, cmd := range rpc.SupportedRMCommands() {
+		if !f.HasCommand(cmd) {
+			missing = append(missing, cmd)
+		}
+	}
+	if len(missing) > 0 {
+		c.warnCompat(ru, CompatWarning{
+			Feature: "commands",
+			Detail: fmt.Sprintf("Remote client does not support the "+
+				"messages %s, which will not be sent to them",
+				strings.Join(missing, ", ")),
+			Action: "Ask them to update their client",
+		})
+	}
+}
+
+// checkServerCompat checks the policy of the server session for missing
+// features, which are disabled while connected to the server.
+func (c *Client) checkServerCompat(sess clientintf.ServerSessionIntf) {
+	policy := sess.Policy()
+	if policy.MaxBatchedRMs < 2 {
+		c.warnCompat(nil, CompatWarning{
+			Feature: "batched-rms",
+			Detail: "Server does not support batched RM pushes, so " +
+				"messages are pushed one at a time",
+			Action: "Ask the server operator to update the server",
+		})
+	}
+	if !policy.SubFilters {
+		c.warnCompat(nil, CompatWarning{
+			Feature: "sub-filters",
+			Detail: "Server does not support subscription filters, so " +
+				"pushes of large messages cannot be deferred",
+			Action: "Ask the server operator to update the server",
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/contactimport"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// ImportContacts adds the contacts (as parsed by the contactimport package) to
+// the list of imported contacts, as placeholders of users to invite. source
+// identifies the platform the contacts were exported from. Contacts that were
+// already imported from the same source are skipped.
+//
+// Returns the number of newly imported contacts.
+func (c *Client) ImportContacts(source string, contacts []contactimport.Contact) (int, error) {
+	if source == "" {
+		return 0, errors.New("source of the contacts must be specified")
+	}
+
+	var added int
+	now := time.Now()
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		for _, contact := range contacts {
+			ic := &clientdb.ImportedContact{
+				Source:      source,
+				Name:        contact.Name,
+				Identifiers: contact.Identifiers,
+				Imported:    now,
+			}
+			isNew, err := c.db.AddImportedContact(tx, ic)
+			if err != nil {
+				return fmt.Errorf("unable to import contact %q: %w",
+					contact.Name, err)
+			}
+			if isNew {
+				added++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return added, err
+	}
+	c.log.Infof("Imported %d new contacts (of %d) from %s", added,
+		len(contacts), source)
+	return added, nil
+}
+
+// ListImportedContacts lists the imported contacts, including ones already
+// invited and ones that joined.
+func (c *Client) ListImportedContacts() ([]clientdb.ImportedContact, error) {
+	var res []clientdb.ImportedContact
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.ListImportedContacts(tx)
+		return err
+	})
+	return res, err
+}
+
+// TrackImportedContactInvite records that the imported contact with the given
+// ID was sent the invite with the given initial RV (as returned in the invite
+// created by WriteNewInvite or CreatePrepaidInvite). Once a user completes KX
+// through the invite, they are recorded as the user of the contact.
+func (c *Client) TrackImportedContactInvite(id string, initialRV zkidentity.ShortID) error {
+	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		_, err := c.db.SetImportedContactInvite(tx, id, initialRV)
+		return err
+	})
+}
+
+// RemoveImportedContact removes the imported contact with the given ID.
+func (c *Client) RemoveImportedContact(id string) error {
+	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.RemoveImportedContact(tx, id)
+	})
+}
+
+// markImportedContactJoined records the user as the user of the imported
+// contact invited with the invite with the given initial RV, if there is one.
+func (c *Client) markImportedContactJoined(tx clientdb.ReadWriteTx,
+	initialRV zkidentity.ShortID, uid UserID) {
+
+	ic, err := c.db.MarkImportedContactJoined(tx, initialRV, uid)
+	if errors.Is(err, clientdb.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		c.log.Warnf("Unable to mark imported contact invited at %s as "+
+			"joined: %v", initialRV, err)
+		return
+	}
+	c.log.Infof("Imported contact %q (from %s) joined as user %s", ic.Name,
+		ic.Source, uid)
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
+	"github.com/companyzero/bisonrelay/internal/imgreenc"
 	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/decred/slog"
@@ -390,7 +394,8 @@ func (c *Client) payFileChunkInvoice(ru *RemoteUser, fid clientdb.FileID,
 	}
 
 	// Attempt to pay invoice.
-	fees, invErr := c.pc.PayInvoice(c.ctx, invoice)
+	uid := ru.ID()
+	fees, invErr := c.payInvoice(clientintf.PaymentCategoryDownload, &uid, invoice, matoms)
 	if invErr == nil {
 		ru.log.Debugf("Paid for chunk %d of file download %s", chunkIdx, fid)
 	}
@@ -613,8 +618,11 @@ func (c *Client) handleFTGetReply(ru *RemoteUser, gr rpc.RMFTGetReply) error {
 	}
 
 	// Ask user for confirmation before downloading file (specially
-	// due to cost).
-	if c.cfg.FileDownloadConfirmer != nil {
+	// due to cost), unless the file is free and small enough to be
+	// automatically downloaded from this user.
+	autoDownload := gr.Metadata.Cost == 0 &&
+		gr.Metadata.Size <= c.userTrustPolicy(ru).MaxAutoDownloadSize
+	if c.cfg.FileDownloadConfirmer != nil && !autoDownload {
 		if !c.cfg.FileDownloadConfirmer(ru, gr.Metadata) {
 			// Canceled. Remove download.
 			ru.log.Infof("User canceled download of file %s", fid)
@@ -717,6 +725,7 @@ func (c *Client) genInvoiceForFTUpload(tx clientdb.ReadWriteTx,
 	var err error
 	inv, err = c.pc.GetInvoice(c.ctx, int64(amountMAtoms), cb)
 	if err != nil {
+		c.cfg.Metrics.Inc(metrics.InvoiceGenFailures)
 		return inv, err
 	}
 
@@ -927,6 +936,9 @@ func (c *Client) handleFTGetChunkReply(ru *RemoteUser, gcr rpc.RMFTGetChunkReply
 		baseName := filepath.Base(completedFname)
 		ru.log.Infof("Completed file download %q (%s, saved as %q",
 			fd.Metadata.Filename, fd.FID, baseName)
+		if len(c.cfg.PostReceiveFileHooks) > 0 {
+			go c.runPostReceiveFileHooks(ru, completedFname)
+		}
 		if c.cfg.FileDownloadCompleted != nil {
 			c.cfg.FileDownloadCompleted(ru, *fd.Metadata, completedFname)
 		}
@@ -936,13 +948,39 @@ func (c *Client) handleFTGetChunkReply(ru *RemoteUser, gcr rpc.RMFTGetChunkReply
 	return err
 }
 
-// ListDownloads lists all outstanding downloads.
+// ListDownloads lists all outstanding downloads, including the ones
+// checkpointed during the last clean shutdown.
 func (c *Client) ListDownloads() ([]clientdb.FileDownload, error) {
 	var fds []clientdb.FileDownload
 	err := c.dbView(func(tx clientdb.ReadTx) error {
 		var err error
 		fds, err = c.db.ListOutstandingDownloads(tx)
-		return err
+		if err != nil || c.lastShutdown == nil {
+			return err
+		}
+
+		// Also include the downloads checkpointed during the last
+		// clean shutdown that are not in the live list.
+		seen := make(map[clientdb.FileID]struct{}, len(fds))
+		for i := range fds {
+			seen[fds[i].FID] = struct{}{}
+		}
+		for _, ref := range c.lastShutdown.OutstandingDownloads {
+			if _, ok := seen[ref.FID]; ok {
+				continue
+			}
+			fd, err := c.db.ReadFileDownload(tx, ref.UID, ref.FID)
+			if err != nil {
+				c.log.Warnf("Unable to read checkpointed download "+
+					"%s: %v", ref.FID, err)
+				continue
+			}
+			seen[ref.FID] = struct{}{}
+			if fd.CompletedName == "" {
+				fds = append(fds, fd)
+			}
+		}
+		return nil
 	})
 	return fds, err
 }
@@ -954,6 +992,28 @@ func (c *Client) SendFile(uid UserID, filepath string) error {
 		return err
 	}
 
+	// Run the pre-send hooks (e.g. transcoders).
+	hookFname, hookTempDir, err := c.runPreSendFileHooks(uid, filepath)
+	if err != nil {
+		return err
+	}
+	if hookTempDir != "" {
+		defer os.RemoveAll(hookTempDir)
+	}
+	filepath = hookFname
+
+	// Downscale and re-encode images, if configured.
+	if cfg := c.cfg.ImageReencode; cfg != nil && !cfg.IsZero() {
+		reencFname, tempDir, err := c.reencodeImageFile(filepath)
+		if err != nil {
+			return err
+		}
+		if reencFname != "" {
+			defer os.RemoveAll(tempDir)
+			filepath = reencFname
+		}
+	}
+
 	// Share the file with the user.
 	sf, fm, err := c.ShareFile(filepath, &uid, 0, "")
 	if err != nil {
@@ -996,9 +1056,66 @@ func (c *Client) SendFile(uid UserID, filepath string) error {
 	return c.UnshareFile(sf.FID, &uid)
 }
 
+// maxReencodeImageSize is the max size of image files that are re-encoded
+// before being sent.
+const maxReencodeImageSize = 64 << 20
+
+// reencodeImageFile re-encodes the image file according to the client's image
+// re-encoding config. If the file was re-encoded, the re-encoded image is
+// written to a new temp dir and its filename and the temp dir are returned.
+// Otherwise, an empty filename is returned.
+func (c *Client) reencodeImageFile(fname string) (string, string, error) {
+	fi, err := os.Stat(fname)
+	if err != nil {
+		return "", "", err
+	}
+	if fi.Size() > maxReencodeImageSize {
+		return "", "", nil
+	}
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return "", "", err
+	}
+	reenc, mimeType, changed, err := imgreenc.Reencode(data, *c.cfg.ImageReencode)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to re-encode image: %v", err)
+	}
+	if !changed {
+		return "", "", nil
+	}
+
+	// Keep the base name, adjusting the extension when the image format
+	// changed.
+	base := filepath.Base(fname)
+	ext := filepath.Ext(base)
+	if mimeType == "image/jpeg" && !strings.EqualFold(ext, ".jpg") &&
+		!strings.EqualFold(ext, ".jpeg") {
+		base = strings.TrimSuffix(base, ext) + ".jpg"
+	}
+
+	dir, err := os.MkdirTemp("", "brreenc")
+	if err != nil {
+		return "", "", err
+	}
+	reencFname := filepath.Join(dir, base)
+	if err := os.WriteFile(reencFname, reenc, 0o600); err != nil {
+		os.RemoveAll(dir)
+		return "", "", err
+	}
+	c.log.Infof("Re-encoded image %q from %d to %d bytes", base, len(data),
+		len(reenc))
+	return reencFname, dir, nil
+}
+
 func (c *Client) handleFTSendFile(ru *RemoteUser, sf rpc.RMFTSendFile) error {
 	var fid clientdb.FileID = sf.Metadata.MetadataHash()
 
+	if maxSize := c.userTrustPolicy(ru).MaxSentFileSize; sf.Metadata.Size > maxSize {
+		return fmt.Errorf("sent file %s has size %d larger than max "+
+			"%d allowed by trust tier policy", fid, sf.Metadata.Size,
+			maxSize)
+	}
+
 	// Store that we'll receive this file.
 	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
 		fd, err := c.db.StartFileDownload(tx, ru.ID(), fid, true)
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/decred/slog"
+)
+
+// TestListDownloadsAfterCleanShutdown tests that the downloads started after
+// a clean shutdown are listed along with the ones checkpointed during the
+// shutdown.
+func TestListDownloadsAfterCleanShutdown(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rnd := testRand(t)
+	uid := testID(t, rnd, "bob").Public.Identity
+
+	db := testDB(t, nil, nil)
+	runTestDB(t, db)
+
+	c := &Client{
+		cfg:   &Config{},
+		ctx:   ctx,
+		db:    db,
+		dbCtx: ctx,
+		log:   slog.Disabled,
+	}
+
+	var fidCheckpointed, fidNew, fidMissing clientdb.FileID
+	fidCheckpointed[0], fidNew[0], fidMissing[0] = 0x01, 0x02, 0x03
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		for _, fid := range []clientdb.FileID{fidCheckpointed, fidNew} {
+			if _, err := c.db.StartFileDownload(tx, uid, fid, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	orFatal(t, err)
+
+	c.lastShutdown = &clientdb.CleanShutdownMarker{
+		OutstandingDownloads: []clientdb.DownloadRef{
+			{UID: uid, FID: fidCheckpointed},
+			{UID: uid, FID: fidMissing},
+		},
+	}
+
+	fds, err := c.ListDownloads()
+	orFatal(t, err)
+	got := make(map[clientdb.FileID]int, len(fds))
+	for _, fd := range fds {
+		got[fd.FID] += 1
+	}
+	want := map[clientdb.FileID]int{fidCheckpointed: 1, fidNew: 1}
+	if len(got) != len(want) || got[fidCheckpointed] != 1 || got[fidNew] != 1 {
+		t.Fatalf("unexpected downloads: got %v, want %v", got, want)
+	}
+}
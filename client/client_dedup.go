@@ -0,0 +1,240 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"golang.org/x/exp/slices"
+)
+
+// DuplicateReason is the reason why address book entries are considered
+// duplicates of each other.
+type DuplicateReason string
+
+const (
+	// DuplicateSameSigKey is the reason of entries with the same
+	// signature key (i.e. the same person that reset their identity).
+	DuplicateSameSigKey DuplicateReason = "samesigkey"
+
+	// DuplicateSameName is the reason of entries with the same (non
+	// empty) name and the same original nick.
+	DuplicateSameName DuplicateReason = "samename"
+
+	// DuplicateSameNick is the reason of entries with nicks that only
+	// differ by case or by the "_<n>" suffix added to disambiguate nicks.
+	DuplicateSameNick DuplicateReason = "samenick"
+)
+
+// DuplicateContacts is a set of address book entries that are likely the
+// same person (reached via different invites).
+type DuplicateContacts struct {
+	Reason DuplicateReason
+
+	// UIDs are the duplicate entries, sorted by the time they were first
+	// created (older first).
+	UIDs []UserID
+}
+
+// nickSuffixRegexp matches the suffix added to nicks to make them unique.
+var nickSuffixRegexp = regexp.MustCompile(`_\d+$`)
+
+// normalizedNick returns the nick without case and without the suffix added to
+// make nicks unique.
+func normalizedNick(nick string) string {
+	nick = strings.ToLower(strings.TrimSpace(nick))
+	return nickSuffixRegexp.ReplaceAllString(nick, "")
+}
+
+// FindDuplicateContacts returns the sets of address book entries that are
+// likely duplicates of each other. Each entry is returned in at most one set,
+// with the most specific reason found.
+func (c *Client) FindDuplicateContacts() []DuplicateContacts {
+	entries := c.AddressBook()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FirstCreated.Before(entries[j].FirstCreated)
+	})
+
+	seen := make(map[UserID]struct{}, len(entries))
+	var res []DuplicateContacts
+	group := func(reason DuplicateReason, key func(*clientdb.AddressBookEntry) string) {
+		groups := make(map[string][]UserID)
+		var keys []string
+		for _, entry := range entries {
+			if _, ok := seen[entry.ID.Identity]; ok {
+				continue
+			}
+			k := key(entry)
+			if k == "" {
+				continue
+			}
+			if _, ok := groups[k]; !ok {
+				keys = append(keys, k)
+			}
+			groups[k] = append(groups[k], entry.ID.Identity)
+		}
+		for _, k := range keys {
+			uids := groups[k]
+			if len(uids) < 2 {
+				continue
+			}
+			for _, uid := range uids {
+				seen[uid] = struct{}{}
+			}
+			res = append(res, DuplicateContacts{Reason: reason, UIDs: uids})
+		}
+	}
+
+	group(DuplicateSameSigKey, func(entry *clientdb.AddressBookEntry) string {
+		return fmt.Sprintf("%x", entry.ID.SigKey[:])
+	})
+	group(DuplicateSameName, func(entry *clientdb.AddressBookEntry) string {
+		name := strings.ToLower(strings.TrimSpace(entry.ID.Name))
+		if name == "" {
+			return ""
+		}
+		return name + "\x00" + normalizedNick(entry.ID.Nick)
+	})
+	group(DuplicateSameNick, func(entry *clientdb.AddressBookEntry) string {
+		return normalizedNick(entry.ID.Nick)
+	})
+	return res
+}
+
+// MergeContactsOpts are the options for merging duplicate contacts.
+type MergeContactsOpts struct {
+	// UpdateGCs invites the canonical user to the GCs administered by the
+	// local client where a duplicate is a member and removes the
+	// duplicate from them.
+	UpdateGCs bool
+
+	// Remove removes the duplicates from the address book (without
+	// blocking them). Otherwise, the duplicates are ignored.
+	Remove bool
+}
+
+// MergeContacts merges the duplicate address book entries into the canonical
+// entry: the logged messages exchanged with the duplicates are merged into
+// the log of the canonical user, the nicks of the duplicates become aliases of
+// the canonical user (see MergedContacts and UIDByNick) and the GC block lists
+// that reference the duplicates are updated to reference the canonical user.
+func (c *Client) MergeContacts(canonical UserID, dups []UserID, opts MergeContactsOpts) error {
+	<-c.abLoaded
+
+	canonRU, err := c.rul.byID(canonical)
+	if err != nil {
+		return err
+	}
+	for _, dup := range dups {
+		if dup == canonical {
+			return fmt.Errorf("cannot merge user %s into itself", dup)
+		}
+		if _, err := c.rul.byID(dup); err != nil {
+			return err
+		}
+	}
+
+	for _, dup := range dups {
+		ru, _ := c.rul.byID(dup)
+		var gcsToUpdate []zkidentity.ShortID
+		err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+			if err := c.db.MergePMLogs(tx, canonical, dup); err != nil {
+				return fmt.Errorf("unable to merge message logs: %v", err)
+			}
+
+			err := c.db.AddMergedContact(tx, canonical, clientdb.MergedContact{
+				UID:    dup,
+				Nick:   ru.Nick(),
+				Name:   ru.PublicIdentity().Name,
+				Merged: time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+
+			gcs, err := c.db.ListGCsWithMember(tx, dup)
+			if err != nil {
+				return err
+			}
+			for _, gcID := range gcs {
+				bl, err := c.db.GetGCBlockList(tx, gcID)
+				if err != nil {
+					return err
+				}
+				if bl.IsBlocked(dup) && !bl.IsBlocked(canonical) {
+					err := c.db.AddToGCBlockList(tx, gcID, canonical)
+					if err != nil {
+						return err
+					}
+				}
+				if bl.IsBlocked(dup) {
+					err := c.db.RemoveFromGCBlockList(tx, gcID, dup)
+					if err != nil {
+						return err
+					}
+				}
+
+				gc, err := c.db.GetGC(tx, gcID)
+				if err != nil {
+					return err
+				}
+				if c.uidHasGCPerm(gc, c.PublicID()) == nil {
+					gcsToUpdate = append(gcsToUpdate, gcID)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if opts.UpdateGCs {
+			reason := fmt.Sprintf("merged into %s", canonRU.Nick())
+			for _, gcID := range gcsToUpdate {
+				gc, err := c.GetGC(gcID)
+				if err != nil {
+					return err
+				}
+				if !slices.Contains(gc.Members, canonical) {
+					if err := c.InviteToGroupChat(gcID, canonical); err != nil {
+						return err
+					}
+				}
+				if err := c.GCKick(gcID, dup, reason); err != nil {
+					return err
+				}
+			}
+		}
+
+		if opts.Remove {
+			ru.log.Infof("Removing user merged into %s", canonRU)
+			c.rul.del(ru)
+			ru.stop()
+			err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+				return c.db.RemoveUser(tx, dup, false)
+			})
+		} else if !ru.IsIgnored() {
+			err = c.Ignore(dup, true)
+		}
+		if err != nil {
+			return err
+		}
+		ru.log.Infof("Merged user into %s", canonRU)
+	}
+	return nil
+}
+
+// MergedContacts lists the contacts merged into the given user.
+func (c *Client) MergedContacts(uid UserID) ([]clientdb.MergedContact, error) {
+	var res []clientdb.MergedContact
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.ListMergedContacts(tx, uid)
+		return err
+	})
+	return res, err
+}
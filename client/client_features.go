@@ -0,0 +1,167 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// featuresRefreshInterval is the interval after which the cached features of a
+// remote user are considered stale and are requested again.
+const featuresRefreshInterval = 7 * 24 * time.Hour
+
+// localFeatures returns the features supported by the local client.
+func (c *Client) localFeatures(wantReply bool) rpc.RMFeatures {
+	return rpc.RMFeatures{
+		Version:    rpc.RMFeaturesVersion,
+		Commands:   rpc.SupportedRMCommands(),
+		MaxMsgSize: rpc.MaxMsgSize,
+		Extensions: c.cfg.FeatureExtensions,
+		WantReply:  wantReply,
+	}
+}
+
+// cachedRemoteFeatures returns the cached features of the remote user or nil
+// if the user has not advertised their features.
+func (c *Client) cachedRemoteFeatures(uid UserID) (*clientdb.RemoteFeatures, error) {
+	c.featuresMtx.Lock()
+	rf, ok := c.remoteFeatures[uid]
+	c.featuresMtx.Unlock()
+	if ok {
+		return rf, nil
+	}
+
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		rf, err = c.db.GetRemoteFeatures(tx, uid)
+		return err
+	})
+	if errors.Is(err, clientdb.ErrNotFound) {
+		rf, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.featuresMtx.Lock()
+	c.remoteFeatures[uid] = rf
+	c.featuresMtx.Unlock()
+	return rf, nil
+}
+
+// maybeRequestFeatures requests the features of the remote user if they
+// support the features exchange and their cached features are missing or
+// stale. This is called whenever an RM is received from the user.
+func (c *Client) maybeRequestFeatures(ru *RemoteUser, h *rpc.RMHeader) {
+	if !h.Capabilities.Has(rpc.RMCapFeatures) || h.Command == rpc.RMCFeatures {
+		return
+	}
+
+	// Only request once per refresh interval, even if the remote user
+	// does not reply.
+	uid := ru.ID()
+	now := time.Now()
+	c.featuresMtx.Lock()
+	lastReq, requested := c.featuresRequested[uid]
+	if requested && now.Sub(lastReq) < featuresRefreshInterval {
+		c.featuresMtx.Unlock()
+		return
+	}
+	c.featuresRequested[uid] = now
+	c.featuresMtx.Unlock()
+
+	rf, err := c.cachedRemoteFeatures(uid)
+	if err != nil {
+		ru.log.Warnf("Unable to load cached features: %v", err)
+		return
+	}
+	if rf != nil && now.Sub(rf.Updated) < featuresRefreshInterval &&
+		rf.Features.Version >= rpc.RMFeaturesVersion {
+		return
+	}
+
+	ru.log.Debugf("Requesting remote user features")
+	err = c.sendWithSendQ("features", c.localFeatures(true), uid)
+	if err != nil {
+		ru.log.Warnf("Unable to request features: %v", err)
+	}
+}
+
+// handleFeatures handles a remote user advertising their features.
+func (c *Client) handleFeatures(ru *RemoteUser, f rpc.RMFeatures) error {
+	if f.Version == 0 {
+		return fmt.Errorf("invalid features version %d", f.Version)
+	}
+
+	wantReply := f.WantReply
+	f.WantReply = false
+	rf := &clientdb.RemoteFeatures{
+		Features: f,
+		Updated:  time.Now(),
+	}
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.UpdateRemoteFeatures(tx, ru.ID(), rf)
+	})
+	if err != nil {
+		return err
+	}
+	c.featuresMtx.Lock()
+	c.remoteFeatures[ru.ID()] = rf
+	c.featuresMtx.Unlock()
+
+	ru.log.Debugf("Received features version %d with %d commands and %d "+
+		"extensions", f.Version, len(f.Commands), len(f.Extensions))
+	c.checkUserFeaturesCompat(ru, &f)
+
+	if !wantReply {
+		return nil
+	}
+	return c.sendWithSendQ("features", c.localFeatures(false), ru.ID())
+}
+
+// RemoteFeatures returns the features advertised by the remote user. Returns
+// nil if the user has not advertised their features (for example, because
+// they run an older client).
+func (c *Client) RemoteFeatures(uid UserID) (*rpc.RMFeatures, error) {
+	rf, err := c.cachedRemoteFeatures(uid)
+	if err != nil || rf == nil {
+		return nil, err
+	}
+	f := rf.Features
+	return &f, nil
+}
+
+// UserSupportsCommand returns true if the remote user supports the given RM
+// command. Users that have not advertised their features are assumed to only
+// support the baseline commands (rpc.BaselineRMCommands).
+//
+// This should be used before sending RMs of newer commands, in order to
+// degrade gracefully when talking to older clients.
+func (c *Client) UserSupportsCommand(uid UserID, cmd string) bool {
+	f, err := c.RemoteFeatures(uid)
+	if err != nil {
+		c.log.Warnf("Unable to load features of user %s: %v", uid, err)
+	}
+	if f != nil {
+		return f.HasCommand(cmd)
+	}
+	baseline := rpc.RMFeatures{Commands: rpc.BaselineRMCommands}
+	return baseline.HasCommand(cmd)
+}
+
+// UserExtensionVersion returns the version of the given extension supported by
+// the remote user or zero if the user does not support it (or has not
+// advertised their features).
+func (c *Client) UserExtensionVersion(uid UserID, ext string) uint64 {
+	f, err := c.RemoteFeatures(uid)
+	if err != nil {
+		c.log.Warnf("Unable to load features of user %s: %v", uid, err)
+	}
+	if f == nil {
+		return 0
+	}
+	return f.ExtensionVersion(ext)
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultFileHookTimeout is the timeout for running each file hook when one
+// is not specified in the config.
+const DefaultFileHookTimeout = 5 * time.Minute
+
+// FileHookStage is the stage of the file transfer in which a file hook runs.
+type FileHookStage string
+
+const (
+	// FileHookStagePreSend is the stage before a file is sent to a remote
+	// user.
+	FileHookStagePreSend FileHookStage = "presend"
+
+	// FileHookStagePostReceive is the stage after a file was downloaded
+	// from a remote user.
+	FileHookStagePostReceive FileHookStage = "postreceive"
+)
+
+// FileHookProgressFunc is called by file hooks to report their progress, as a
+// fraction between 0 and 1.
+type FileHookProgressFunc func(progress float64)
+
+// PreSendFileHook is a hook called before a file is sent to a remote user with
+// SendFile. It may be used to transcode the file (e.g. compress a video).
+type PreSendFileHook interface {
+	// Name is the name of the hook, used in logs and notifications.
+	Name() string
+
+	// PreSendFile processes the file before it is sent to the remote user.
+	// If the hook produces a new file to be sent instead of fname, it must
+	// write it to tempDir and return its path. Returning an empty path
+	// sends fname unchanged.
+	PreSendFile(ctx context.Context, uid UserID, fname, tempDir string,
+		progress FileHookProgressFunc) (string, error)
+}
+
+// PostReceiveFileHook is a hook called after a file download from a remote
+// user completes. It may be used to post-process the file (e.g. generate a
+// preview thumbnail).
+type PostReceiveFileHook interface {
+	// Name is the name of the hook, used in logs and notifications.
+	Name() string
+
+	// PostReceiveFile processes the downloaded file.
+	PostReceiveFile(ctx context.Context, uid UserID, fname string,
+		progress FileHookProgressFunc) error
+}
+
+// FileHookEvent is an event about the progress of a file hook.
+type FileHookEvent struct {
+	Hook     string
+	Stage    FileHookStage
+	UID      UserID
+	Filename string
+
+	// Progress is the progress reported by the hook, between 0 and 1.
+	Progress float64
+
+	// Done is set when the hook finished running, in which case Err is
+	// the error returned by it (if any).
+	Done bool
+	Err  error
+}
+
+// fileHookTimeout returns the timeout for running each file hook.
+func (c *Client) fileHookTimeout() time.Duration {
+	if c.cfg.FileHookTimeout > 0 {
+		return c.cfg.FileHookTimeout
+	}
+	return DefaultFileHookTimeout
+}
+
+// runFileHook runs a file hook with the configured timeout, notifying its
+// progress.
+func (c *Client) runFileHook(stage FileHookStage, hook string, uid UserID,
+	fname string, run func(context.Context, FileHookProgressFunc) error) error {
+
+	ev := FileHookEvent{
+		Hook:     hook,
+		Stage:    stage,
+		UID:      uid,
+		Filename: fname,
+	}
+	progress := func(p float64) {
+		if p < 0 {
+			p = 0
+		} else if p > 1 {
+			p = 1
+		}
+		ev := ev
+		ev.Progress = p
+		c.ntfns.notifyFileHookProgress(ev)
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, c.fileHookTimeout())
+	defer cancel()
+	start := time.Now()
+	err := run(ctx, progress)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("file hook %s timed out after %s: %w", hook,
+			c.fileHookTimeout(), err)
+	}
+	c.log.Debugf("File hook %s (%s) on %q finished in %s (err: %v)", hook,
+		stage, fname, time.Since(start), err)
+
+	ev.Done = true
+	ev.Err = err
+	if err == nil {
+		ev.Progress = 1
+	}
+	c.ntfns.notifyFileHookProgress(ev)
+	return err
+}
+
+// runPreSendFileHooks runs the pre-send hooks on the file. The hooks are
+// chained: each one processes the file returned by the previous one. It
+// returns the file to send and a temp dir (to be removed after the file is
+// sent) when the hooks produced a new file.
+func (c *Client) runPreSendFileHooks(uid UserID, fname string) (string, string, error) {
+	if len(c.cfg.PreSendFileHooks) == 0 {
+		return fname, "", nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "brfilehook")
+	if err != nil {
+		return "", "", err
+	}
+	for _, hook := range c.cfg.PreSendFileHooks {
+		var newFname string
+		err := c.runFileHook(FileHookStagePreSend, hook.Name(), uid, fname,
+			func(ctx context.Context, progress FileHookProgressFunc) error {
+				var err error
+				newFname, err = hook.PreSendFile(ctx, uid, fname, tempDir, progress)
+				return err
+			})
+		if err != nil {
+			os.RemoveAll(tempDir)
+			return "", "", fmt.Errorf("pre-send file hook %s failed: %w",
+				hook.Name(), err)
+		}
+		if newFname != "" {
+			fname = newFname
+		}
+	}
+	return fname, tempDir, nil
+}
+
+// runPostReceiveFileHooks runs the post-receive hooks on the downloaded file.
+// Hook errors are logged but do not fail the download.
+func (c *Client) runPostReceiveFileHooks(ru *RemoteUser, fname string) {
+	for _, hook := range c.cfg.PostReceiveFileHooks {
+		hook := hook
+		err := c.runFileHook(FileHookStagePostReceive, hook.Name(), ru.ID(),
+			fname, func(ctx context.Context, progress FileHookProgressFunc) error {
+				return hook.PostReceiveFile(ctx, ru.ID(), fname, progress)
+			})
+		if err != nil {
+			ru.log.Warnf("Post-receive file hook %s failed on %q: %v",
+				hook.Name(), fname, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/slog"
+)
+
+// testFileHook is a file hook that runs a function on the file.
+type testFileHook struct {
+	name string
+	f    func(ctx context.Context, fname, tempDir string) (string, error)
+}
+
+func (h *testFileHook) Name() string { return h.name }
+
+func (h *testFileHook) PreSendFile(ctx context.Context, uid UserID, fname,
+	tempDir string, progress FileHookProgressFunc) (string, error) {
+	progress(0.5)
+	return h.f(ctx, fname, tempDir)
+}
+
+// TestPreSendFileHooks tests that pre-send file hooks are chained and that
+// their timeout is enforced.
+func TestPreSendFileHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ntfns := NewNotificationManager()
+	var events []FileHookEvent
+	ntfns.RegisterSync(OnFileHookProgressNtfn(func(ev FileHookEvent) {
+		events = append(events, ev)
+	}))
+	c := &Client{
+		cfg:   &Config{FileHookTimeout: 100 * time.Millisecond},
+		ctx:   ctx,
+		ntfns: ntfns,
+		log:   slog.Disabled,
+	}
+
+	srcFname := filepath.Join(t.TempDir(), "video.raw")
+	if err := os.WriteFile(srcFname, []byte("raw"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first hook transcodes the file, the second one only observes
+	// the transcoded file.
+	var observed string
+	c.cfg.PreSendFileHooks = []PreSendFileHook{
+		&testFileHook{name: "transcode", f: func(_ context.Context, fname, tempDir string) (string, error) {
+			newFname := filepath.Join(tempDir, "video.mp4")
+			return newFname, os.WriteFile(newFname, []byte("mp4"), 0o600)
+		}},
+		&testFileHook{name: "observe", f: func(_ context.Context, fname, _ string) (string, error) {
+			observed = fname
+			return "", nil
+		}},
+	}
+	fname, tempDir, err := c.runPreSendFileHooks(UserID{}, srcFname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(fname) != "video.mp4" || observed != fname {
+		t.Fatalf("unexpected hook output: got %q, observed %q", fname, observed)
+	}
+	if filepath.Dir(fname) != tempDir {
+		t.Fatalf("unexpected temp dir %q for file %q", tempDir, fname)
+	}
+	os.RemoveAll(tempDir)
+
+	// Each hook reports its progress and completion.
+	if len(events) != 4 {
+		t.Fatalf("unexpected nb of events: got %d, want 4", len(events))
+	}
+	if events[0].Hook != "transcode" || events[0].Progress != 0.5 || events[0].Done {
+		t.Fatalf("unexpected progress event: %+v", events[0])
+	}
+	if !events[1].Done || events[1].Err != nil || events[1].Progress != 1 {
+		t.Fatalf("unexpected done event: %+v", events[1])
+	}
+
+	// A hook that does not finish before the timeout fails the send.
+	c.cfg.PreSendFileHooks = []PreSendFileHook{
+		&testFileHook{name: "slow", f: func(ctx context.Context, _, _ string) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}},
+	}
+	_, _, err = c.runPreSendFileHooks(UserID{}, srcFname)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: got %v, want %v", err,
+			context.DeadlineExceeded)
+	}
+}
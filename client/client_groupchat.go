@@ -87,7 +87,7 @@ func (c *Client) GetGCAlias(gcID zkidentity.ShortID) (string, error) {
 	}
 	c.gcAliasMtx.Unlock()
 	if alias == "" {
-		return "", fmt.Errorf("gc %s not found", gcID)
+		return "", clientintf.KindErrorf(clientintf.ErrGCNotFound, "gc %s not found", gcID)
 	}
 	return alias, nil
 }
@@ -108,7 +108,7 @@ func (c *Client) GCIDByName(name string) (zkidentity.ShortID, error) {
 	c.gcAliasMtx.Unlock()
 
 	if !ok {
-		return id, fmt.Errorf("gc %q not found", name)
+		return id, clientintf.KindErrorf(clientintf.ErrGCNotFound, "gc %q not found", name)
 	}
 
 	return id, nil
@@ -650,6 +650,7 @@ func (c *Client) maybeUpdateGCFunc(ru *RemoteUser, gcid zkidentity.ShortID, f fu
 		newGC = oldGC
 		newGC.Members = slices.Clone(oldGC.Members)
 		newGC.ExtraAdmins = slices.Clone(oldGC.ExtraAdmins)
+		newGC.Posters = slices.Clone(oldGC.Posters)
 		if err := f(&newGC); err != nil {
 			return err
 		}
@@ -851,6 +852,12 @@ func (c *Client) notifyUpdatedGC(ru *RemoteUser, oldGC, newGC rpc.RMGroupList) {
 	if len(adminChanges.removed) > 0 || len(adminChanges.added) > 0 {
 		c.ntfns.notifyGCAdminsChanged(ru, newGC, adminChanges.added, adminChanges.removed)
 	}
+
+	posterChanges := sliceDiff(oldGC.Posters, newGC.Posters)
+	if oldGC.ReadOnly != newGC.ReadOnly || len(posterChanges.added) > 0 ||
+		len(posterChanges.removed) > 0 {
+		c.ntfns.notifyGCReadOnlyChanged(ru, newGC)
+	}
 }
 
 // saveJoinedGC is called when the local client receives the first RMGroupList
@@ -1006,6 +1013,7 @@ func (c *Client) handleDelayedGCMessages(msg clientintf.ReceivedGCMsg) {
 	}
 
 	c.ntfns.notifyOnGCM(user, msg.GCM, msg.TS)
+	c.trackGCBacklog(msg.GCM.ID)
 }
 
 // SendProgress is sent to track progress of messages that are sent to multiple
@@ -1032,6 +1040,9 @@ func (c *Client) GCMessage(gcID zkidentity.ShortID, msg string, mode rpc.Message
 		if gcBlockList, err = c.db.GetGCBlockList(tx, gcID); err != nil {
 			return err
 		}
+		if !gc.CanPost(c.PublicID()) {
+			return ErrGCReadOnly
+		}
 
 		gcAlias, err := c.GetGCAlias(gcID)
 		if err != nil {
@@ -1049,6 +1060,7 @@ func (c *Client) GCMessage(gcID zkidentity.ShortID, msg string, mode rpc.Message
 		Generation: gc.Generation,
 		Message:    msg,
 		Mode:       mode,
+		Nonce:      newMsgNonce(),
 	}
 	members := gcBlockList.FilterMembers(gc.Members)
 	if len(members) == 0 {
@@ -1060,18 +1072,24 @@ func (c *Client) GCMessage(gcID zkidentity.ShortID, msg string, mode rpc.Message
 
 func (c *Client) handleGCMessage(ru *RemoteUser, gcm rpc.RMGroupMessage, ts time.Time) error {
 	var gc rpc.RMGroupList
-	var found, isBlocked bool
+	var found, isBlocked, readOnly bool
 	var gcAlias string
 
 	// Create the local cached structure for a received GCM. The MsgID is
+	// the ID of the message when the sender sets one. Otherwise, it is
 	// just a random id used for caching purposes.
 	rgcm := clientintf.ReceivedGCMsg{
 		UID: ru.ID(),
 		GCM: gcm,
 		TS:  ts,
 	}
-	_, _ = rand.Read(rgcm.MsgID[:])
+	if gcm.Nonce != 0 {
+		rgcm.MsgID = gcm.MsgID(ru.ID())
+	} else {
+		_, _ = rand.Read(rgcm.MsgID[:])
+	}
 
+	var dup bool
 	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
 		// Ensure gc exists.
 		var err error
@@ -1089,6 +1107,14 @@ func (c *Client) handleGCMessage(ru *RemoteUser, gcm rpc.RMGroupMessage, ts time
 			return nil
 		}
 
+		// Members that are not allowed to post in read-only GCs
+		// cannot send messages, even if their clients do not enforce
+		// it.
+		readOnly = !gc.CanPost(ru.ID())
+		if readOnly {
+			return nil
+		}
+
 		gcBlockList, err := c.db.GetGCBlockList(tx, gcm.ID)
 		if err != nil {
 			return err
@@ -1098,6 +1124,15 @@ func (c *Client) handleGCMessage(ru *RemoteUser, gcm rpc.RMGroupMessage, ts time
 			return nil
 		}
 
+		// Copies of the message resent after a reconnection are
+		// ignored.
+		if gcm.Nonce != 0 {
+			dup, err = c.db.MarkMsgIDReceived(tx, rgcm.MsgID)
+			if err != nil || dup {
+				return err
+			}
+		}
+
 		return c.db.CacheReceivedGCM(tx, rgcm)
 	})
 	if errors.Is(err, clientdb.ErrNotFound) {
@@ -1129,6 +1164,18 @@ func (c *Client) handleGCMessage(ru *RemoteUser, gcm rpc.RMGroupMessage, ts time
 		return nil
 	}
 
+	if readOnly {
+		c.log.Warnf("Received message in read-only GC %q from member %s "+
+			"not allowed to post", gcAlias, ru)
+		return nil
+	}
+
+	if dup {
+		ru.log.Debugf("Ignoring duplicate message %s in GC %s",
+			rgcm.MsgID, gc.ID)
+		return nil
+	}
+
 	if filter, _ := c.FilterGCM(ru.ID(), gc.ID, gcm.Message); filter {
 		return nil
 	}
@@ -1148,6 +1195,9 @@ func (c *Client) GetGC(gcID zkidentity.ShortID) (rpc.RMGroupList, error) {
 		gc, err = c.db.GetGC(tx, gcID)
 		return err
 	})
+	if errors.Is(err, clientdb.ErrNotFound) {
+		err = clientintf.KindErrorf(clientintf.ErrGCNotFound, "%w", err)
+	}
 	return gc, err
 }
 
@@ -1608,6 +1658,37 @@ func (c *Client) ModifyGCAdmins(gcid zkidentity.ShortID, extraAdmins []zkidentit
 	return c.sendToGCMembers(gcid, newGC.Members, "modifyAdmins", rm, nil)
 }
 
+// SetGCReadOnly sets whether the GC is read-only and the members (other than
+// the admins) that may post in it. In read-only GCs, messages from other
+// members are dropped when received, which is suitable for announcement GCs.
+//
+// Members running clients without support for read-only GCs ignore this
+// setting.
+func (c *Client) SetGCReadOnly(gcid zkidentity.ShortID, readOnly bool, posters []zkidentity.ShortID) error {
+	cb := func(gc *rpc.RMGroupList) error {
+		for _, uid := range posters {
+			if !slices.Contains(gc.Members, uid) {
+				return fmt.Errorf("poster %s is not a member of the GC", uid)
+			}
+		}
+		gc.Timestamp = time.Now().Unix()
+		gc.Generation += 1
+		gc.ReadOnly = readOnly
+		gc.Posters = posters
+		return nil
+	}
+
+	_, newGC, err := c.maybeUpdateGCFunc(nil, gcid, cb)
+	if err != nil {
+		return err
+	}
+
+	c.log.Infof("Set GC %s read-only to %v (posters %v)", gcid,
+		readOnly, posters)
+
+	return c.sendToGCMembers(gcid, newGC.Members, "setReadOnly", newGC, nil)
+}
+
 func (c *Client) handleGCUpdateAdmins(ru *RemoteUser, gcup rpc.RMGroupUpdateAdmins) error {
 	oldGC, err := c.maybeUpdateGC(ru, gcup.NewGroupList)
 	if err != nil {
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	// maxGuestViewPages is the max number of pages published in a single
+	// guest view.
+	maxGuestViewPages = 16
+
+	// guestViewsExpiryInterval is the interval between checks for expired
+	// guest views.
+	guestViewsExpiryInterval = time.Hour
+)
+
+// guestViewLinkRegexp matches the markdown links to local pages.
+var guestViewLinkRegexp = regexp.MustCompile(`\]\(/([^)\s]+)\)`)
+
+// guestViewPagePath splits the path of a page of a guest view.
+func guestViewPagePath(s string) []string {
+	return strings.Split(strings.Trim(s, "/"), "/")
+}
+
+// guestViewLinks returns the paths of the local pages linked from the page.
+// Links to admin pages are skipped.
+func guestViewLinks(page []byte) [][]string {
+	var res [][]string
+	for _, m := range guestViewLinkRegexp.FindAllSubmatch(page, -1) {
+		path := guestViewPagePath(string(m[1]))
+		if len(path) == 0 || path[0] == "" || path[0] == "admin" {
+			continue
+		}
+		res = append(res, path)
+	}
+	return res
+}
+
+// CreateGuestView publishes the given pages of the local resources provider
+// to the server, encrypted with the key of a new guest view token, along with
+// the pages they link to (up to a max number of pages). Guests that hold the
+// token may fetch the pages with FetchGuestView without KXing with the local
+// client. The pages are rendered for an anonymous user (not the local client),
+// so they do not include content shown only to the local user (such as admin
+// sections).
+//
+// If no pages are specified, the index page ("index.md") is published.
+func (c *Client) CreateGuestView(ctx context.Context, lifetime time.Duration,
+	pages [][]string) (*clientintf.GuestViewToken, *clientdb.GuestView, error) {
+
+	if c.cfg.ResourcesProvider == nil {
+		return nil, nil, clientintf.KindErrorf(clientintf.ErrResourceNotFound,
+			"resources provider not configured")
+	}
+	if lifetime <= 0 {
+		return nil, nil, fmt.Errorf("guest view lifetime must be positive")
+	}
+	if len(pages) == 0 {
+		pages = [][]string{{"index.md"}}
+	}
+
+	token := clientintf.NewGuestViewToken(&c.id.Public, time.Now().Add(lifetime))
+
+	// Pages are fetched as an anonymous user, derived from the token id
+	// so that it is not a real user.
+	guestUID := UserID(sha256.Sum256(token.ID[:]))
+
+	gv := &clientdb.GuestView{Token: token, Created: time.Now()}
+	seen := make(map[string]struct{})
+	queue := append([][]string{}, pages...)
+	for len(queue) > 0 && len(gv.Pages) < maxGuestViewPages {
+		page := queue[0]
+		queue = queue[1:]
+		key := rpc.PublishedResourcePathKey(page)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		res, err := c.cfg.ResourcesProvider.Fulfill(ctx, guestUID,
+			&rpc.RMFetchResource{Path: page})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to render page %s: %v",
+				strescape.ResourcesPath(page), err)
+		}
+		if res.Status != rpc.ResourceStatusOk {
+			c.log.Debugf("Skipping page %s of guest view with status %d",
+				strescape.ResourcesPath(page), res.Status)
+			continue
+		}
+
+		box, err := token.Encrypt(res.Data)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = c.PublishResource(ctx, token.PublishedPath(page), box)
+		if err != nil {
+			// Unpublish what was already published.
+			c.unpublishGuestView(ctx, gv)
+			return nil, nil, fmt.Errorf("unable to publish page %s: %v",
+				strescape.ResourcesPath(page), err)
+		}
+		gv.Pages = append(gv.Pages, page)
+		queue = append(queue, guestViewLinks(res.Data)...)
+	}
+	if len(gv.Pages) == 0 {
+		return nil, nil, fmt.Errorf("no pages to publish in guest view")
+	}
+
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.StoreGuestView(tx, gv)
+	})
+	if err != nil {
+		c.unpublishGuestView(ctx, gv)
+		return nil, nil, err
+	}
+	c.log.Infof("Created guest view %s with %d pages (expires %s)", token.ID,
+		len(gv.Pages), token.Expires.Format(time.RFC3339))
+	return token, gv, nil
+}
+
+// unpublishGuestView removes the pages of the guest view from the server.
+func (c *Client) unpublishGuestView(ctx context.Context, gv *clientdb.GuestView) error {
+	var firstErr error
+	for _, page := range gv.Pages {
+		err := c.UnpublishResource(ctx, gv.Token.PublishedPath(page))
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ListGuestViews lists the guest views created by the local client.
+func (c *Client) ListGuestViews() ([]clientdb.GuestView, error) {
+	var res []clientdb.GuestView
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.ListGuestViews(tx)
+		return err
+	})
+	return res, err
+}
+
+// RevokeGuestView removes the pages of the guest view from the server, such
+// that guests cannot fetch them anymore.
+func (c *Client) RevokeGuestView(ctx context.Context, id clientintf.GuestViewID) error {
+	var gv *clientdb.GuestView
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		gv, err = c.db.GetGuestView(tx, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.unpublishGuestView(ctx, gv); err != nil {
+		return fmt.Errorf("unable to unpublish guest view pages: %w", err)
+	}
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.RemoveGuestView(tx, id)
+	})
+	if err != nil {
+		return err
+	}
+	c.log.Infof("Revoked guest view %s", id)
+	return nil
+}
+
+// FetchGuestView fetches a page of a guest view of another user, using the
+// guest view token shared by them. The page is verified to have been signed
+// by the user that created the token.
+func (c *Client) FetchGuestView(ctx context.Context, token *clientintf.GuestViewToken,
+	page []string) ([]byte, error) {
+
+	if token.Expired() {
+		return nil, fmt.Errorf("guest view token expired at %s",
+			token.Expires.Format(time.RFC3339))
+	}
+	if len(page) == 0 {
+		page = []string{"index.md"}
+	}
+	id := token.Identity()
+	res, err := c.fetchPublishedResource(ctx, &id, token.Owner.ShortLogID(),
+		token.PublishedPath(page))
+	if err != nil {
+		return nil, err
+	}
+	return token.Decrypt(res.Data)
+}
+
+// expireGuestViews revokes the expired guest views.
+func (c *Client) expireGuestViews(ctx context.Context) error {
+	views, err := c.ListGuestViews()
+	if err != nil {
+		return err
+	}
+	for _, gv := range views {
+		if !gv.Token.Expired() {
+			continue
+		}
+		err := c.RevokeGuestView(ctx, gv.Token.ID)
+		if errors.Is(err, errNotConnected) {
+			return nil
+		}
+		if err != nil {
+			c.log.Warnf("Unable to revoke expired guest view %s: %v",
+				gv.Token.ID, err)
+		}
+	}
+	return nil
+}
+
+// runGuestViewsExpiry periodically revokes expired guest views.
+func (c *Client) runGuestViewsExpiry(ctx context.Context) error {
+	for {
+		select {
+		case <-time.After(guestViewsExpiryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if err := c.expireGuestViews(ctx); err != nil {
+			c.log.Warnf("Unable to expire guest views: %v", err)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// Keysend tips are sent directly to the LN node of the remote user, without
+// first requesting an invoice through RMs. The LN node of a remote user is
+// learned from the invoices they send to the local client. A memo, signed by
+// the local client's identity, is attached to the payment in order for the
+// receiver to link the payment to the sender.
+
+// recordUserLNNode stores the LN node of the remote user, as learned from an
+// invoice received from them.
+func (c *Client) recordUserLNNode(ru *RemoteUser, node string) {
+	if node == "" {
+		return
+	}
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		ab, err := c.db.GetAddressBookEntry(tx, ru.ID())
+		if err != nil {
+			return err
+		}
+		if ab.LNNodeID == node {
+			return nil
+		}
+		ab.LNNodeID = node
+		return c.db.UpdateAddressBookEntry(tx, ab)
+	})
+	if err != nil {
+		ru.log.Warnf("Unable to record user LN node: %v", err)
+	}
+}
+
+// userLNNode returns the last known LN node of the remote user.
+func (c *Client) userLNNode(uid UserID) (string, error) {
+	var node string
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		ab, err := c.db.GetAddressBookEntry(tx, uid)
+		if err != nil {
+			return err
+		}
+		node = ab.LNNodeID
+		return nil
+	})
+	return node, err
+}
+
+// canTipViaKeysend returns the LN node to send a keysend tip to the remote
+// user, if keysend tips are possible.
+func (c *Client) canTipViaKeysend(uid UserID) (string, bool) {
+	if _, ok := c.pc.(clientintf.KeysendPaymentClient); !ok {
+		return "", false
+	}
+	node, err := c.userLNNode(uid)
+	if err != nil || node == "" {
+		return "", false
+	}
+	return node, true
+}
+
+// payTipKeysend pays the tip attempt via keysend, attaching a signed memo to
+// the payment.
+func (c *Client) payTipKeysend(ru *RemoteUser, node string, ta clientdb.TipUserAttempt) {
+	kpc := c.pc.(clientintf.KeysendPaymentClient)
+
+	memo := rpc.KeysendTipMemo{
+		From:       c.PublicID(),
+		To:         ru.ID(),
+		MilliAtoms: ta.MilliAtoms,
+		Timestamp:  time.Now().Unix(),
+	}
+	memo.Signature = c.id.SignMessage(memo.SignedHash())
+	rawMemo, err := json.Marshal(memo)
+	if err != nil {
+		c.handleTipUserPaymentResult(ru, ta.Tag, err, 0)
+		return
+	}
+	records := map[uint64][]byte{rpc.KeysendTipMemoRecordType: rawMemo}
+
+	uid := ru.ID()
+	amount := int64(ta.MilliAtoms)
+	err = c.waitPaymentApproval(clientintf.PaymentCategoryTip, &uid, "", amount)
+	if err != nil {
+		c.handleTipUserPaymentResult(ru, ta.Tag, err, 0)
+		return
+	}
+
+	// There is no invoice to estimate the fee of keysend payments, so the
+	// max fee of the tip fee policy is used as the fee limit of the
+	// payment.
+	maxFee := c.cfg.FeePolicies[clientintf.PaymentCategoryTip].MaxFee(amount)
+
+	ru.log.Debugf("Attempting to pay tip of %.8f DCR (tag %d) via keysend "+
+		"to node %s", float64(amount)/1e11, ta.Tag, node)
+	fees, err := kpc.PayKeysend(c.ctx, node, amount, maxFee, records)
+	c.handleTipUserPaymentResult(ru, ta.Tag, err, fees)
+}
+
+// handleKeysendPayment handles a keysend payment received by the local node.
+// If the payment carries a valid tip memo, the tip is recorded as received
+// from the remote user that signed the memo.
+func (c *Client) handleKeysendPayment(ks clientintf.ReceivedKeysend) error {
+	rawMemo, ok := ks.CustomRecords[rpc.KeysendTipMemoRecordType]
+	if !ok {
+		c.log.Debugf("Received keysend payment of %d MAtoms without "+
+			"tip memo", ks.MAtoms)
+		return nil
+	}
+
+	var memo rpc.KeysendTipMemo
+	if err := json.Unmarshal(rawMemo, &memo); err != nil {
+		return fmt.Errorf("unable to decode keysend tip memo: %v", err)
+	}
+	if memo.To != c.PublicID() {
+		return fmt.Errorf("keysend tip memo addressed to %s", memo.To)
+	}
+	ru, err := c.rul.byID(memo.From)
+	if err != nil {
+		return fmt.Errorf("keysend tip memo from unknown user %s: %v",
+			memo.From, err)
+	}
+	if !ru.id.VerifyMessage(memo.SignedHash(), memo.Signature) {
+		return fmt.Errorf("keysend tip memo from %s has invalid signature",
+			memo.From)
+	}
+	if ks.MAtoms < int64(memo.MilliAtoms) {
+		return fmt.Errorf("keysend tip from %s paid %d < memo amount %d",
+			memo.From, ks.MAtoms, memo.MilliAtoms)
+	}
+
+	ru.log.Infof("Received %f DCR as tip via keysend", float64(ks.MAtoms)/1e11)
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		if err := c.db.RecordUserPayEvent(tx, ru.ID(), "tip", ks.MAtoms, 0); err != nil {
+			return err
+		}
+		return c.recordReceivedTip(tx, ru.ID(), ks.MAtoms, nil, false)
+	})
+	if err != nil {
+		return err
+	}
+	c.ntfns.notifyTipReceived(ru, ks.MAtoms)
+	return nil
+}
+
+// trackKeysendPayments tracks keysend payments received by the local node,
+// in order to detect tips sent via keysend.
+func (c *Client) trackKeysendPayments(ctx context.Context) error {
+	kpc, ok := c.pc.(clientintf.KeysendPaymentClient)
+	if !ok {
+		return nil
+	}
+	<-c.abLoaded
+
+	const retryDelay = time.Minute
+	for {
+		err := kpc.TrackKeysendPayments(ctx, func(ks clientintf.ReceivedKeysend) {
+			if err := c.handleKeysendPayment(ks); err != nil {
+				c.log.Warnf("Unable to handle keysend payment: %v", err)
+			}
+		})
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil
+		}
+		c.log.Warnf("Unable to track keysend payments: %v", err)
+
+		select {
+		case <-time.After(retryDelay):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
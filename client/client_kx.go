@@ -128,6 +128,8 @@ func (c *Client) initRemoteUser(id *zkidentity.PublicIdentity, r *ratchet.Ratche
 	ru := newRemoteUser(c.q, c.rmgr, c.db, id, c.id, r)
 	ru.ignored = ignored
 	ru.compressLevel = c.cfg.CompressLevel
+	ru.tracer = c.cfg.Tracer
+	ru.metrics = c.cfg.Metrics
 	ru.log = c.cfg.logger(fmt.Sprintf("RUSR %x", id.Identity[:8]))
 	ru.logPayloads = c.cfg.logger(fmt.Sprintf("RMPL %x", id.Identity[:8]))
 	ru.rmHandler = c.handleUserRM
@@ -167,10 +169,13 @@ func (c *Client) initRemoteUser(id *zkidentity.PublicIdentity, r *ratchet.Ratche
 		var ignored bool
 		firstCreated := time.Now()
 		var lastHandshakeAttempt time.Time
+		var lnNodeID, trustTier string
 		if oldEntry != nil {
 			ignored = oldEntry.Ignored
 			firstCreated = oldEntry.FirstCreated
 			lastHandshakeAttempt = oldEntry.LastHandshakeAttempt
+			lnNodeID = oldEntry.LNNodeID
+			trustTier = oldEntry.TrustTier
 		}
 		if updateAB {
 			newEntry := &clientdb.AddressBookEntry{
@@ -180,6 +185,8 @@ func (c *Client) initRemoteUser(id *zkidentity.PublicIdentity, r *ratchet.Ratche
 				Ignored:              ignored,
 				FirstCreated:         firstCreated,
 				LastHandshakeAttempt: lastHandshakeAttempt,
+				LNNodeID:             lnNodeID,
+				TrustTier:            trustTier,
 			}
 			if err := c.db.UpdateAddressBookEntry(tx, newEntry); err != nil {
 				return err
@@ -199,6 +206,18 @@ func (c *Client) initRemoteUser(id *zkidentity.PublicIdentity, r *ratchet.Ratche
 			if err != nil {
 				return err
 			}
+
+			// Record the user as referred if the invite was
+			// tracked as a referral.
+			_, err = c.db.MarkReferralJoined(tx, initialRV, id.Identity)
+			if err != nil && !errors.Is(err, clientdb.ErrNotFound) {
+				c.log.Warnf("Unable to mark referral %s as joined: %v",
+					initialRV, err)
+			}
+
+			// Record the user as the user of the imported
+			// contact invited with the invite.
+			c.markImportedContactJoined(tx, initialRV, id.Identity)
 		}
 
 		// See if there are any actions to be taken after completing KX.
@@ -656,12 +675,18 @@ func (c *Client) handshakeIdleUsers(limitInterval time.Duration) error {
 		}
 
 		// Skip if we attempted a handshake with this user more recently
-		// than the limit date.
+		// than the limit date. Handshakes with users that take longer
+		// to reply, or that did not reply to the last requests, are
+		// retried less often.
 		ab, err := c.getAddressBookEntry(uid)
 		if err != nil {
 			continue
 		}
-		if ab.LastHandshakeAttempt.After(limitDate) {
+		retryDate := limitDate
+		if score, err := c.PeerScore(uid); err == nil {
+			retryDate = time.Now().Add(-peerRetryInterval(score, limitInterval))
+		}
+		if ab.LastHandshakeAttempt.After(retryDate) {
 			continue
 		}
 
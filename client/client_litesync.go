@@ -0,0 +1,318 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/internal/lowlevel"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	// liteSyncMaxPushSize is the max size of RMs automatically pushed by
+	// the server during the initial stage of lite sync. This is large
+	// enough for regular messages, but small enough that file chunks are
+	// deferred.
+	liteSyncMaxPushSize = 16 * 1024
+
+	// liteSyncRecentWindow is how far back RMs are considered recent
+	// during lite sync. Older RMs are deferred until the initial stage is
+	// done.
+	liteSyncRecentWindow = 3 * 24 * time.Hour
+
+	// liteSyncBatchSize is the number of deferred RMs requested from the
+	// server at a time during lite sync.
+	liteSyncBatchSize = 64
+
+	// liteSyncSettleInterval is the interval between checks for whether
+	// the server finished pushing the stored RMs after the initial
+	// subscription.
+	liteSyncSettleInterval = time.Second
+
+	// liteSyncMaxSettleTime is the max amount of time to wait for the
+	// server to finish pushing the stored RMs.
+	liteSyncMaxSettleTime = 30 * time.Second
+
+	// backlogThrottleWindow is the window of the backlog fetch limits.
+	backlogThrottleWindow = time.Minute
+)
+
+// SyncStage is a stage of the lite sync startup mode.
+type SyncStage int
+
+const (
+	// SyncStageRecent is the stage where recent, small messages are
+	// received.
+	SyncStageRecent SyncStage = iota
+
+	// SyncStageBacklog is the stage where older, small messages are
+	// fetched.
+	SyncStageBacklog
+
+	// SyncStageLarge is the stage where large messages (such as file
+	// chunks) are fetched.
+	SyncStageLarge
+
+	// SyncStageDone is the final stage, after which the client is fully
+	// synced.
+	SyncStageDone
+)
+
+func (s SyncStage) String() string {
+	switch s {
+	case SyncStageRecent:
+		return "recent"
+	case SyncStageBacklog:
+		return "backlog"
+	case SyncStageLarge:
+		return "large"
+	case SyncStageDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+// SyncProgress is the progress of the lite sync startup mode.
+type SyncProgress struct {
+	Stage SyncStage
+
+	// Fetched and Total are the number of messages requested so far and
+	// total number of messages to fetch in the current stage.
+	Fetched int
+	Total   int
+
+	// EstimatedFees is the estimated amount (in milliatoms) to be paid to
+	// the server for the subscriptions needed to receive the messages
+	// remaining in the current stage. This does not include LN routing
+	// fees.
+	EstimatedFees int64
+
+	// Throttled is true while fetching is paused due to the configured
+	// backlog fetch limits.
+	Throttled bool
+}
+
+// Remaining is the number of messages of the current stage that haven't been
+// fetched yet.
+func (p SyncProgress) Remaining() int {
+	return p.Total - p.Fetched
+}
+
+// backlogThrottle limits how many deferred RMs are fetched per window, by
+// number and by the fees of the subscriptions needed to receive them.
+type backlogThrottle struct {
+	maxRMs  int
+	maxFees int64
+	window  time.Duration
+
+	start time.Time
+	rms   int
+	fees  int64
+}
+
+// take returns how many of the next n RMs, each costing fee milliatoms to
+// receive, may be fetched at the given time and tracks them as fetched. When
+// none may be fetched, it returns how long to wait until the next window. At
+// least one RM is allowed per window, regardless of its cost.
+func (t *backlogThrottle) take(n int, fee int64, now time.Time) (int, time.Duration) {
+	if now.Sub(t.start) >= t.window {
+		t.start, t.rms, t.fees = now, 0, 0
+	}
+	allowed := n
+	if t.maxRMs > 0 && t.maxRMs-t.rms < allowed {
+		allowed = t.maxRMs - t.rms
+	}
+	if t.maxFees > 0 && fee > 0 && (t.maxFees-t.fees)/fee < int64(allowed) {
+		allowed = int((t.maxFees - t.fees) / fee)
+	}
+	if allowed <= 0 {
+		if t.rms > 0 {
+			return 0, t.start.Add(t.window).Sub(now)
+		}
+		allowed = 1
+	}
+	t.rms += allowed
+	t.fees += int64(allowed) * fee
+	return allowed, 0
+}
+
+// liteSyncFilter returns the subscription filter to use during the initial
+// stage of lite sync.
+func liteSyncFilter(cfg *Config) rpc.SubscriptionFilter {
+	maxSize := liteSyncMaxPushSize
+	if cfg.MaxAutoFetchRMSize > 0 && cfg.MaxAutoFetchRMSize < maxSize {
+		maxSize = cfg.MaxAutoFetchRMSize
+	}
+	return rpc.SubscriptionFilter{
+		MaxPushSize:  maxSize,
+		MinTimestamp: time.Now().Add(-liteSyncRecentWindow).Unix(),
+	}
+}
+
+// waitDeferredSettled waits until the list of deferred RMs stops changing,
+// which indicates the server finished pushing the RMs stored on the initial
+// subscription.
+func (c *Client) waitDeferredSettled(ctx context.Context) error {
+	lastCount := -1
+	deadline := time.After(liteSyncMaxSettleTime)
+	for {
+		count := len(c.rmgr.DeferredRMs())
+		if count == lastCount {
+			return nil
+		}
+		lastCount = count
+
+		select {
+		case <-time.After(liteSyncSettleInterval):
+		case <-deadline:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// reportSyncProgress records and notifies the progress of lite sync.
+func (c *Client) reportSyncProgress(progress SyncProgress) {
+	c.syncProgressMtx.Lock()
+	c.syncProgress = progress
+	c.syncProgressMtx.Unlock()
+	c.ntfns.notifySyncProgress(progress)
+}
+
+// SyncProgress returns the last reported progress of the lite sync startup
+// mode.
+func (c *Client) SyncProgress() SyncProgress {
+	c.syncProgressMtx.Lock()
+	defer c.syncProgressMtx.Unlock()
+	return c.syncProgress
+}
+
+// backlogRMFee returns the estimated fee (in milliatoms) to receive a deferred
+// RM, which is the cost of subscribing to the RV of the next message of the
+// same ratchet.
+func (c *Client) backlogRMFee() int64 {
+	c.sessMtx.Lock()
+	sess := c.sess
+	c.sessMtx.Unlock()
+	if sess == nil || sess.PayClient().PayScheme() == rpc.PaySchemeFree {
+		return 0
+	}
+	_, subRate := sess.PaymentRates()
+	return int64(subRate)
+}
+
+// liteSyncFetchStage fetches the specified deferred RMs in batches, sending
+// progress notifications after each batch. Fetching is paused whenever the
+// throttle limits are reached.
+func (c *Client) liteSyncFetchStage(ctx context.Context, stage SyncStage,
+	rms []lowlevel.DeferredRM, throttle *backlogThrottle) error {
+
+	// Fetch the most recent RMs first.
+	sort.Slice(rms, func(i, j int) bool {
+		return rms[i].ServerTS.After(rms[j].ServerTS)
+	})
+
+	c.log.Infof("Lite sync: fetching %d deferred messages (%s stage)",
+		len(rms), stage)
+	progress := func(fetched int, throttled bool) SyncProgress {
+		return SyncProgress{
+			Stage:         stage,
+			Fetched:       fetched,
+			Total:         len(rms),
+			EstimatedFees: int64(len(rms)-fetched) * c.backlogRMFee(),
+			Throttled:     throttled,
+		}
+	}
+	c.reportSyncProgress(progress(0, false))
+	rvs := make([]lowlevel.RVID, 0, liteSyncBatchSize)
+	for i := 0; i < len(rms); {
+		n := liteSyncBatchSize
+		if n > len(rms)-i {
+			n = len(rms) - i
+		}
+		n, wait := throttle.take(n, c.backlogRMFee(), time.Now())
+		if n == 0 {
+			c.log.Debugf("Lite sync: throttling fetching of deferred "+
+				"messages for %s", wait)
+			c.reportSyncProgress(progress(i, true))
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		end := i + n
+		rvs = rvs[:0]
+		for _, drm := range rms[i:end] {
+			rvs = append(rvs, drm.RV)
+		}
+		if err := c.rmgr.FetchDeferredRMsList(ctx, rvs); err != nil {
+			return err
+		}
+		i = end
+		c.reportSyncProgress(progress(end, false))
+	}
+	return nil
+}
+
+// runLiteSync runs the lite sync startup mode. The initial subscription is
+// done with a filter that only allows recent, small RMs to be pushed. After
+// those are received, the filter is relaxed and the older and larger RMs are
+// fetched in stages.
+func (c *Client) runLiteSync(ctx context.Context) error {
+	c.reportSyncProgress(SyncProgress{Stage: SyncStageRecent})
+
+	select {
+	case <-c.firstSubDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err := c.waitDeferredSettled(ctx); err != nil {
+		return err
+	}
+
+	// Restore the regular filter, so that new RMs are pushed as usual.
+	filter := rpc.SubscriptionFilter{MaxPushSize: c.cfg.MaxAutoFetchRMSize}
+	if err := c.rmgr.UpdateSubscriptionFilter(ctx, filter); err != nil {
+		return err
+	}
+
+	// Split deferred RMs into the old backlog of small RMs and the large
+	// ones. RMs larger than the configured max auto fetch size are left
+	// deferred, to be explicitly fetched by the user.
+	var backlog, large []lowlevel.DeferredRM
+	for _, drm := range c.rmgr.DeferredRMs() {
+		switch {
+		case c.cfg.MaxAutoFetchRMSize > 0 && drm.Size > c.cfg.MaxAutoFetchRMSize:
+		case drm.Size > liteSyncMaxPushSize:
+			large = append(large, drm)
+		default:
+			backlog = append(backlog, drm)
+		}
+	}
+
+	// Failures to fetch are not fatal to the client: the RMs remain
+	// deferred and will be pushed again on the next connection.
+	throttle := &backlogThrottle{
+		maxRMs:  c.cfg.MaxBacklogFetchPerMinute,
+		maxFees: c.cfg.MaxBacklogFeesPerMinute,
+		window:  backlogThrottleWindow,
+	}
+	if err := c.liteSyncFetchStage(ctx, SyncStageBacklog, backlog, throttle); err != nil {
+		c.log.Warnf("Lite sync: unable to fetch backlog: %v", err)
+	} else if err := c.liteSyncFetchStage(ctx, SyncStageLarge, large, throttle); err != nil {
+		c.log.Warnf("Lite sync: unable to fetch large messages: %v", err)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	c.log.Infof("Lite sync done")
+	c.reportSyncProgress(SyncProgress{Stage: SyncStageDone})
+	return nil
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBacklogThrottle tests that the backlog throttle limits the number of RMs
+// fetched per window by count and by fees.
+func TestBacklogThrottle(t *testing.T) {
+	t.Parallel()
+
+	type takeCall struct {
+		n        int
+		fee      int64
+		when     time.Duration
+		wantN    int
+		wantWait time.Duration
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name     string
+		throttle backlogThrottle
+		calls    []takeCall
+	}{{
+		name:     "unlimited",
+		throttle: backlogThrottle{window: time.Minute},
+		calls: []takeCall{
+			{64, 1000, 0, 64, 0},
+			{64, 1000, 0, 64, 0},
+		},
+	}, {
+		name:     "max rms",
+		throttle: backlogThrottle{maxRMs: 100, window: time.Minute},
+		calls: []takeCall{
+			{64, 1000, 0, 64, 0},
+			{64, 1000, 10 * time.Second, 36, 0},
+			{64, 1000, 20 * time.Second, 0, 40 * time.Second},
+			{64, 1000, time.Minute, 64, 0},
+		},
+	}, {
+		name:     "max fees",
+		throttle: backlogThrottle{maxFees: 10500, window: time.Minute},
+		calls: []takeCall{
+			{8, 1000, 0, 8, 0},
+			{8, 1000, 0, 2, 0},
+			{8, 1000, 30 * time.Second, 0, 30 * time.Second},
+			{8, 0, 30 * time.Second, 8, 0},
+			{8, 1000, time.Minute, 8, 0},
+		},
+	}, {
+		name:     "one rm above budget",
+		throttle: backlogThrottle{maxFees: 500, window: time.Minute},
+		calls: []takeCall{
+			{8, 1000, 0, 1, 0},
+			{8, 1000, 0, 0, time.Minute},
+			{8, 1000, time.Minute, 1, 0},
+		},
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			throttle := tc.throttle
+			for i, call := range tc.calls {
+				n, wait := throttle.take(call.n, call.fee, now.Add(call.when))
+				if n != call.wantN || wait != call.wantWait {
+					t.Fatalf("call %d: unexpected result: got (%d, %s), "+
+						"want (%d, %s)", i, n, wait, call.wantN,
+						call.wantWait)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// newMsgNonce returns a random, non-zero nonce for a sent PM or GC message.
+// The nonce makes the ID of the message unique, while the ID of resent copies
+// of the message stays the same.
+func newMsgNonce() uint64 {
+	var b [8]byte
+	for {
+		_, _ = rand.Read(b[:])
+		if nonce := binary.LittleEndian.Uint64(b[:]); nonce != 0 {
+			return nonce
+		}
+	}
+}
+
+// handlePM handles a received PM. PMs with a message ID (i.e. with a nonce)
+// are logged and notified only the first time they are received, so that
+// copies resent after a reconnection are ignored.
+func (c *Client) handlePM(ru *RemoteUser, pm rpc.RMPrivateMessage, ts time.Time) error {
+	if filter, _ := c.FilterPM(ru.ID(), pm.Message); filter {
+		return nil
+	}
+
+	var dup bool
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		if pm.Nonce != 0 {
+			var err error
+			dup, err = c.db.MarkMsgIDReceived(tx, pm.MsgID(ru.ID(), c.PublicID()))
+			if err != nil || dup {
+				return err
+			}
+		}
+		return c.db.LogPM(tx, ru.ID(), false, ru.Nick(), pm.Message, ts)
+	})
+	if err != nil {
+		return err
+	}
+	if dup {
+		ru.log.Debugf("Ignoring duplicate private message %s",
+			pm.MsgID(ru.ID(), c.PublicID()))
+		return nil
+	}
+	ru.log.Debugf("Received private message of length %d", len(pm.Message))
+
+	c.ntfns.notifyOnPM(ru, pm, ts)
+	return nil
+}
+
+// cleanupReceivedMsgIDs removes the IDs of the messages received before the
+// messages expire in the server, after which copies of the messages are not
+// expected to be received anymore.
+func (c *Client) cleanupReceivedMsgIDs(expirationDays int) {
+	limit := time.Now().Add(-24 * time.Hour * time.Duration(expirationDays+1))
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.CleanupReceivedMsgIDs(tx, limit)
+	})
+	if err != nil {
+		c.log.Warnf("Unable to cleanup received msg ids: %v", err)
+	}
+}
+
+// runReceivedMsgIDsCleanup periodically removes the IDs of the received
+// messages that already expired in the server, so that they do not accumulate
+// in clients that stay connected to the same server for a long time.
+func (c *Client) runReceivedMsgIDsCleanup(ctx context.Context) error {
+	if c.cfg.ReceivedMsgIDsCleanupInterval < 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(c.cfg.ReceivedMsgIDsCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		c.sessMtx.Lock()
+		sess := c.sess
+		c.sessMtx.Unlock()
+		if sess == nil {
+			continue
+		}
+		c.cleanupReceivedMsgIDs(sess.ExpirationDays())
+	}
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/decred/slog"
+)
+
+// TestReceiveDuplicatePM tests that copies of a received PM with the same
+// message ID are logged and notified only once, while PMs without a message ID
+// are always processed.
+func TestReceiveDuplicatePM(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rnd := testRand(t)
+	aliceID := testID(t, rnd, "alice")
+	bobID := testID(t, rnd, "bob")
+
+	cfg := testDBConfig(t, nil, nil)
+	cfg.MsgsRoot = filepath.Join(cfg.Root, "logs")
+	db, err := clientdb.New(cfg)
+	orFatal(t, err)
+	runTestDB(t, db)
+
+	ntfns := NewNotificationManager()
+	var gotPMs []string
+	ntfns.RegisterSync(OnPMNtfn(func(_ *RemoteUser, pm rpc.RMPrivateMessage, _ time.Time) {
+		gotPMs = append(gotPMs, pm.Message)
+	}))
+	c := &Client{
+		id:    aliceID,
+		db:    db,
+		dbCtx: ctx,
+		ntfns: ntfns,
+		log:   slog.Disabled,
+	}
+
+	// Bob is in Alice's address book.
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return db.UpdateAddressBookEntry(tx, &clientdb.AddressBookEntry{ID: &bobID.Public})
+	})
+	orFatal(t, err)
+	ru := newRemoteUser(nil, nil, db, &bobID.Public, aliceID, nil)
+
+	ts := time.Now()
+	pm := rpc.RMPrivateMessage{Message: "hello", Nonce: newMsgNonce()}
+	resent := pm
+	other := rpc.RMPrivateMessage{Message: "hello", Nonce: newMsgNonce()}
+	legacy := rpc.RMPrivateMessage{Message: "legacy"}
+	for _, pm := range []rpc.RMPrivateMessage{pm, resent, other, legacy, legacy} {
+		orFatal(t, c.handlePM(ru, pm, ts))
+	}
+
+	wantPMs := []string{"hello", "hello", "legacy", "legacy"}
+	if len(gotPMs) != len(wantPMs) {
+		t.Fatalf("unexpected notified PMs: got %q, want %q", gotPMs, wantPMs)
+	}
+	var logged []clientdb.PMLogEntry
+	err = c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		logged, err = db.ReadLogPM(tx, bobID.Public.Identity, 100, 0)
+		return err
+	})
+	orFatal(t, err)
+	if len(logged) != len(wantPMs) {
+		t.Fatalf("unexpected nb of logged PMs: got %d, want %d",
+			len(logged), len(wantPMs))
+	}
+
+	// After the received ids are cleaned up, the PM is processed again.
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return db.CleanupReceivedMsgIDs(tx, time.Now().Add(time.Hour))
+	})
+	orFatal(t, err)
+	orFatal(t, c.handlePM(ru, resent, ts))
+	if len(gotPMs) != len(wantPMs)+1 {
+		t.Fatalf("PM not notified after cleaning up the received ids")
+	}
+}
+
+// TestReceivedMsgIDsCleanupTimer tests that the ids of received messages that
+// expired in the server are periodically cleaned up.
+func TestReceivedMsgIDsCleanupTimer(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := testDBConfig(t, nil, nil)
+	db, err := clientdb.New(cfg)
+	orFatal(t, err)
+	runTestDB(t, db)
+	c := &Client{
+		cfg:   &Config{ReceivedMsgIDsCleanupInterval: 10 * time.Millisecond},
+		db:    db,
+		dbCtx: ctx,
+		log:   slog.Disabled,
+		sess:  skewedServerSession{},
+	}
+
+	// Mark a message received before it expired in the server (7 days)
+	// and a recent message.
+	var oldID, newID zkidentity.ShortID
+	oldID[0], newID[0] = 0x01, 0x02
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		for _, id := range []zkidentity.ShortID{oldID, newID} {
+			if _, err := db.MarkMsgIDReceived(tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	orFatal(t, err)
+	oldFname := filepath.Join(cfg.Root, "recvmsgids", oldID.String())
+	oldTS := time.Now().Add(-9 * 24 * time.Hour).Format(time.RFC3339)
+	orFatal(t, os.WriteFile(oldFname, []byte(`{"ts":"`+oldTS+`"}`), 0o600))
+
+	go c.runReceivedMsgIDsCleanup(ctx)
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(oldFname); os.IsNotExist(err) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expired msg id was not cleaned up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The recent id is kept.
+	newFname := filepath.Join(cfg.Root, "recvmsgids", newID.String())
+	if _, err := os.Stat(newFname); err != nil {
+		t.Fatalf("recent msg id was cleaned up: %v", err)
+	}
+}
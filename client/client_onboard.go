@@ -1,3 +1,6 @@
+//go:build !brlite
+// +build !brlite
+
 package client
 
 import (
@@ -139,6 +142,20 @@ func (c *Client) CancelOnboarding() error {
 	return nil
 }
 
+const (
+	// onboardChanOpenFee is the amount of the invite funds reserved to
+	// pay for the fees of opening the outbound channel.
+	onboardChanOpenFee = dcrutil.Amount(10800)
+
+	// onboardMinChanSize is the min size of channels accepted by dcrlnd.
+	onboardMinChanSize = dcrutil.Amount(20000)
+
+	// MinOnboardFundsAmount is the min amount of invite funds needed by
+	// the invitee to onboard (i.e. open an outbound channel to pay for the
+	// initial server fees).
+	MinOnboardFundsAmount = onboardChanOpenFee + onboardMinChanSize
+)
+
 // onboardRedeemOnchainFunds redeems the on-chain funds included in the invite.
 func (c *Client) onboardRedeemOnchainFunds(ctx context.Context, funds *rpc.InviteFunds) (dcrutil.Amount, chainhash.Hash, error) {
 	var amount dcrutil.Amount
@@ -217,7 +234,7 @@ func (c *Client) onboardOpenOutboundChan(ctx context.Context, onchainAmount dcru
 	// Determine how much to fund the outbound channel. This is a guess,
 	// based on the total amount received onchain and the fees that will
 	// be paid, capped at a maximum channel size of 5 DCR.
-	fundingAmt := onchainAmount - 10800
+	fundingAmt := onchainAmount - onboardChanOpenFee
 	if fundingAmt > 5e8 {
 		fundingAmt = 5e8
 	}
@@ -534,6 +551,17 @@ func (c *Client) runOnboarding(ctx context.Context, ostate clientintf.OnboardSta
 		case ostate.Stage == clientintf.StageInviteNoFunds:
 			runErr = clientintf.ErrOnboardNoFunds
 
+		case ostate.Stage == clientintf.StageRedeemingFunds &&
+			ostate.Invite.Funds.Amount > 0 &&
+			dcrutil.Amount(ostate.Invite.Funds.Amount) < MinOnboardFundsAmount:
+			// Fail early when the inviter advertised an amount
+			// that is not enough to open the outbound channel.
+			runErr = fmt.Errorf("%w: invite has %s but onboarding "+
+				"requires at least %s",
+				clientintf.ErrOnboardInsufficientFunds,
+				dcrutil.Amount(ostate.Invite.Funds.Amount),
+				MinOnboardFundsAmount)
+
 		case ostate.Stage == clientintf.StageRedeemingFunds:
 			// Haven't redeemed the invite funds yet, attempt to do
 			// so.
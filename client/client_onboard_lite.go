@@ -0,0 +1,50 @@
+//go:build brlite
+// +build brlite
+
+package client
+
+import (
+	"context"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// MinOnboardFundsAmount is the min amount of invite funds needed by the
+// invitee to onboard. Onboarding is not available in lite builds.
+const MinOnboardFundsAmount = dcrutil.Amount(30800)
+
+// ReadOnboard returns the existing onboard state. Onboarding is not available
+// in lite builds.
+func (c *Client) ReadOnboard() (*clientintf.OnboardState, error) {
+	return nil, ErrLiteBuild
+}
+
+// RetryOnboarding retries the onboarding at the current stage. Onboarding is
+// not available in lite builds.
+func (c *Client) RetryOnboarding() error {
+	return ErrLiteBuild
+}
+
+// SkipOnboardingStage skips the current onboarding stage to the next one.
+// Onboarding is not available in lite builds.
+func (c *Client) SkipOnboardingStage() error {
+	return ErrLiteBuild
+}
+
+// StartOnboarding starts a new onboarding procedure with the given key.
+// Onboarding is not available in lite builds.
+func (c *Client) StartOnboarding(key clientintf.PaidInviteKey) error {
+	return ErrLiteBuild
+}
+
+// CancelOnboarding stops the currently running onboarding and removes it from
+// the client. Onboarding is not available in lite builds.
+func (c *Client) CancelOnboarding() error {
+	return ErrLiteBuild
+}
+
+// restartOnboarding is a no-op in lite builds.
+func (c *Client) restartOnboarding(ctx context.Context) error {
+	return nil
+}
@@ -1,19 +1,28 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
 )
 
+// onchainAddrGenerator is implemented by payment clients that can generate
+// new on-chain receive addresses.
+type onchainAddrGenerator interface {
+	NewReceiveAddress(ctx context.Context, acct string) (stdaddr.Address, error)
+}
+
 // OnchainRecvAddrForUser returns the on-chain receive address of the local
 // wallet associated with the specified user. If acct is specified, addresses
-// are generated from that account.
+// are generated from that account. Addresses are tracked separately per
+// account, so that funds received in different accounts are not mixed.
 func (c *Client) OnchainRecvAddrForUser(uid UserID, acct string) (string, error) {
 	var addr string
 	err := c.dbView(func(tx clientdb.ReadTx) error {
 		var err error
-		addr, err = c.db.OnchainRecvAddrForUser(tx, uid)
+		addr, err = c.db.OnchainRecvAddrForUser(tx, uid, acct)
 		return err
 	})
 	if err != nil {
@@ -23,14 +32,14 @@ func (c *Client) OnchainRecvAddrForUser(uid UserID, acct string) (string, error)
 		return addr, nil
 	}
 
-	if pc, ok := c.cfg.PayClient.(*DcrlnPaymentClient); ok {
+	if pc, ok := c.cfg.PayClient.(onchainAddrGenerator); ok {
 		newAddr, err := pc.NewReceiveAddress(c.ctx, acct)
 		if err != nil {
 			return "", fmt.Errorf("unable to generate new on-chain address: %v", err)
 		}
 
 		err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
-			return c.db.UpdateOnchainRecvAddrForUser(tx, uid, newAddr.String())
+			return c.db.UpdateOnchainRecvAddrForUser(tx, uid, acct, newAddr.String())
 		})
 		if err != nil {
 			return "", err
@@ -43,11 +52,12 @@ func (c *Client) OnchainRecvAddrForUser(uid UserID, acct string) (string, error)
 }
 
 // UpdateOnchainRecvAddrForUser updates the on-chain receive address of the local
-// wallet associated with the specified user. If addr is nil, then the current
-// address is removed.
-func (c *Client) UpdateOnchainRecvAddrForUser(uid UserID, addr string) error {
+// wallet generated from the specified account (or the default account if acct
+// is empty) associated with the specified user. If addr is empty, then the
+// current address is removed.
+func (c *Client) UpdateOnchainRecvAddrForUser(uid UserID, acct, addr string) error {
 	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
-		return c.db.UpdateOnchainRecvAddrForUser(tx, uid, addr)
+		return c.db.UpdateOnchainRecvAddrForUser(tx, uid, acct, addr)
 	})
 }
 
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/slog"
+)
+
+// acctAddrPaymentClient is a payment client that generates a new address from
+// the given account on every call.
+type acctAddrPaymentClient struct {
+	clientintf.FreePaymentClient
+	generated map[string]int
+}
+
+func (pc *acctAddrPaymentClient) NewReceiveAddress(_ context.Context, acct string) (stdaddr.Address, error) {
+	pc.generated[acct] += 1
+	var hash [20]byte
+	copy(hash[:], fmt.Sprintf("%s/%d", acct, pc.generated[acct]))
+	return stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(hash[:], chaincfg.SimNetParams())
+}
+
+// TestOnchainRecvAddrPerAccount tests that the on-chain receive addresses of a
+// user are generated and kept separately for each account.
+func TestOnchainRecvAddrPerAccount(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rnd := testRand(t)
+	bobID := testID(t, rnd, "bob")
+	uid := bobID.Public.Identity
+
+	db := testDB(t, nil, nil)
+	runTestDB(t, db)
+
+	pc := &acctAddrPaymentClient{generated: make(map[string]int)}
+	c := &Client{
+		cfg:   &Config{PayClient: pc},
+		ctx:   ctx,
+		db:    db,
+		dbCtx: ctx,
+		log:   slog.Disabled,
+	}
+
+	personal, err := c.OnchainRecvAddrForUser(uid, "")
+	orFatal(t, err)
+	store, err := c.OnchainRecvAddrForUser(uid, "store")
+	orFatal(t, err)
+	if personal == store {
+		t.Fatalf("same address generated for different accounts")
+	}
+
+	// Addresses are reused for the same account.
+	for acct, want := range map[string]string{"": personal, "store": store} {
+		got, err := c.OnchainRecvAddrForUser(uid, acct)
+		orFatal(t, err)
+		if got != want {
+			t.Fatalf("unexpected address of account %q: got %s, want %s",
+				acct, got, want)
+		}
+		if pc.generated[acct] != 1 {
+			t.Fatalf("unexpected nb of addresses generated from "+
+				"account %q: %d", acct, pc.generated[acct])
+		}
+		if got := c.UserWithOnchainRecvAddr(want); got == nil || *got != uid {
+			t.Fatalf("user of address of account %q not found", acct)
+		}
+	}
+
+	// Removing the address of an account keeps the other one.
+	orFatal(t, c.UpdateOnchainRecvAddrForUser(uid, "store", ""))
+	if got := c.UserWithOnchainRecvAddr(store); got != nil {
+		t.Fatalf("removed address still associated with the user")
+	}
+	got, err := c.OnchainRecvAddrForUser(uid, "")
+	orFatal(t, err)
+	if got != personal {
+		t.Fatalf("address of the default account was changed")
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// PaymentApproval is an outgoing payment waiting for approval, in the audit
+// mode of outgoing payments.
+type PaymentApproval struct {
+	ID       uint64
+	Category clientintf.PaymentCategory
+
+	// UID is the remote user being paid, when known (for example, in tips
+	// and downloads).
+	UID *UserID
+
+	// Invoice is the invoice being paid. It is empty for payments without
+	// an invoice (keysend).
+	Invoice string
+	MAtoms  int64
+
+	Requested time.Time
+}
+
+// pendingPaymentApproval is a payment waiting for approval and the channel
+// where the decision is sent.
+type pendingPaymentApproval struct {
+	PaymentApproval
+	decision chan error
+}
+
+// recordPaymentAudit appends the decision about the payment to the payment
+// audit log.
+func (c *Client) recordPaymentAudit(approval *PaymentApproval,
+	decision clientdb.PaymentAuditDecision, reason string) {
+
+	entry := &clientdb.PaymentAuditEntry{
+		ApprovalID: approval.ID,
+		Category:   approval.Category,
+		UID:        approval.UID,
+		Invoice:    approval.Invoice,
+		MAtoms:     approval.MAtoms,
+		Requested:  approval.Requested,
+		Decided:    time.Now(),
+		Decision:   decision,
+		Reason:     reason,
+	}
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.AppendPaymentAudit(tx, entry)
+	})
+	if err != nil {
+		c.log.Errorf("Unable to record %s payment of %d milliatoms in "+
+			"payment audit log: %v", approval.Category, approval.MAtoms, err)
+	}
+}
+
+// waitPaymentApproval records the outgoing payment in the payment audit log
+// and, if its amount requires approval, waits until it is approved or denied.
+// Returns nil if the payment may proceed.
+func (c *Client) waitPaymentApproval(category clientintf.PaymentCategory,
+	uid *UserID, invoice string, amountMAtoms int64) error {
+
+	threshold := c.cfg.PaymentApprovalThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	approval := PaymentApproval{
+		Category:  category,
+		UID:       uid,
+		Invoice:   invoice,
+		MAtoms:    amountMAtoms,
+		Requested: time.Now(),
+	}
+	if amountMAtoms < threshold {
+		c.recordPaymentAudit(&approval, clientdb.PaymentAuditBelowThreshold, "")
+		return nil
+	}
+
+	pending := &pendingPaymentApproval{
+		PaymentApproval: approval,
+		decision:        make(chan error, 1),
+	}
+	c.payApprovalsMtx.Lock()
+	c.nextPayApprovalID++
+	pending.ID = c.nextPayApprovalID
+	if c.payApprovals == nil {
+		c.payApprovals = make(map[uint64]*pendingPaymentApproval)
+	}
+	c.payApprovals[pending.ID] = pending
+	c.payApprovalsMtx.Unlock()
+
+	c.log.Infof("Holding %s payment of %d milliatoms for approval (id %d)",
+		category, amountMAtoms, pending.ID)
+	c.ntfns.notifyPaymentApprovalRequested(pending.PaymentApproval)
+
+	select {
+	case err := <-pending.decision:
+		return err
+	case <-c.ctx.Done():
+		c.payApprovalsMtx.Lock()
+		_, stillPending := c.payApprovals[pending.ID]
+		delete(c.payApprovals, pending.ID)
+		c.payApprovalsMtx.Unlock()
+		if stillPending {
+			c.recordPaymentAudit(&pending.PaymentApproval,
+				clientdb.PaymentAuditCanceled, "client shutdown")
+		}
+		return c.ctx.Err()
+	}
+}
+
+// WaitPaymentApproval records an outgoing payment that is not made by the
+// client itself (for example, one made directly through the wallet) in the
+// payment audit log and, if its amount requires approval, blocks until it is
+// approved or denied. Returns nil if the payment may proceed.
+func (c *Client) WaitPaymentApproval(category clientintf.PaymentCategory,
+	invoice string, amountMAtoms int64) error {
+
+	return c.waitPaymentApproval(category, nil, invoice, amountMAtoms)
+}
+
+// decidePayment approves (when err is nil) or denies the payment waiting for
+// approval with the given id.
+func (c *Client) decidePayment(id uint64, decision clientdb.PaymentAuditDecision,
+	reason string, err error) error {
+
+	c.payApprovalsMtx.Lock()
+	pending, ok := c.payApprovals[id]
+	delete(c.payApprovals, id)
+	c.payApprovalsMtx.Unlock()
+	if !ok {
+		return fmt.Errorf("no payment waiting for approval with id %d", id)
+	}
+
+	c.recordPaymentAudit(&pending.PaymentApproval, decision, reason)
+	c.log.Infof("Payment %d (%s payment of %d milliatoms) %s", id,
+		pending.Category, pending.MAtoms, decision)
+	pending.decision <- err
+	return nil
+}
+
+// ApprovePayment approves the outgoing payment waiting for approval with the
+// given id, which then proceeds.
+func (c *Client) ApprovePayment(id uint64) error {
+	return c.decidePayment(id, clientdb.PaymentAuditApproved, "", nil)
+}
+
+// DenyPayment denies the outgoing payment waiting for approval with the given
+// id, which then fails with an error that wraps ErrPaymentNotApproved.
+func (c *Client) DenyPayment(id uint64, reason string) error {
+	err := clientintf.ErrPaymentNotApproved
+	if reason != "" {
+		err = fmt.Errorf("%w: %s", err, reason)
+	}
+	return c.decidePayment(id, clientdb.PaymentAuditDenied, reason, err)
+}
+
+// PendingPaymentApprovals returns the outgoing payments waiting for approval,
+// from the oldest one.
+func (c *Client) PendingPaymentApprovals() []PaymentApproval {
+	c.payApprovalsMtx.Lock()
+	res := make([]PaymentApproval, 0, len(c.payApprovals))
+	for _, pending := range c.payApprovals {
+		res = append(res, pending.PaymentApproval)
+	}
+	c.payApprovalsMtx.Unlock()
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res
+}
+
+// PaymentAuditLog returns the entries of the audit log of outgoing payments,
+// from the oldest one.
+func (c *Client) PaymentAuditLog() ([]clientdb.PaymentAuditEntry, error) {
+	var res []clientdb.PaymentAuditEntry
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.ListPaymentAudit(tx)
+		return err
+	})
+	return res, err
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/decred/slog"
+)
+
+// TestPaymentApprovals tests that outgoing payments above the approval
+// threshold are held until approved or denied and that every payment is
+// recorded in the payment audit log.
+func TestPaymentApprovals(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := testDB(t, nil, nil)
+	runTestDB(t, db)
+
+	ntfns := NewNotificationManager()
+	requested := make(chan PaymentApproval, 1)
+	ntfns.Register(OnPaymentApprovalRequestedNtfn(func(approval PaymentApproval) {
+		requested <- approval
+	}))
+	c := &Client{
+		cfg:   &Config{PaymentApprovalThreshold: 1000},
+		ctx:   ctx,
+		db:    db,
+		dbCtx: ctx,
+		ntfns: ntfns,
+		log:   slog.Disabled,
+	}
+
+	// Payments below the threshold proceed without approval.
+	err := c.waitPaymentApproval(clientintf.PaymentCategoryTip, nil, "inv0", 999)
+	assert.NilErr(t, err)
+
+	// Payments above the threshold are held until approved.
+	wait := func(invoice string) chan error {
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- c.waitPaymentApproval(clientintf.PaymentCategoryStore,
+				nil, invoice, 5000)
+		}()
+		return errChan
+	}
+	approvedErr := wait("inv1")
+	approval := assert.ChanWritten(t, requested)
+	assert.DeepEqual(t, approval.Invoice, "inv1")
+	assert.DeepEqual(t, c.PendingPaymentApprovals(), []PaymentApproval{approval})
+	assert.ChanNotWritten(t, approvedErr, 100*time.Millisecond)
+	assert.NilErr(t, c.ApprovePayment(approval.ID))
+	assert.NilErr(t, assert.ChanWritten(t, approvedErr))
+	assert.DeepEqual(t, len(c.PendingPaymentApprovals()), 0)
+
+	// Decided payments may not be decided again.
+	assert.NonNilErr(t, c.ApprovePayment(approval.ID))
+
+	// Denied payments fail. Payments made directly through the wallet
+	// are also held.
+	deniedErr := make(chan error, 1)
+	go func() {
+		deniedErr <- c.WaitPaymentApproval(clientintf.PaymentCategoryManual,
+			"inv2", 5000)
+	}()
+	approval = assert.ChanWritten(t, requested)
+	assert.DeepEqual(t, approval.Category, clientintf.PaymentCategoryManual)
+	assert.NilErr(t, c.DenyPayment(approval.ID, "too expensive"))
+	err = assert.ChanWritten(t, deniedErr)
+	if !errors.Is(err, clientintf.ErrPaymentNotApproved) {
+		t.Fatalf("unexpected error: got %v, want %v", err,
+			clientintf.ErrPaymentNotApproved)
+	}
+
+	// Payments still waiting for approval during shutdown are canceled.
+	canceledErr := wait("inv3")
+	assert.ChanWritten(t, requested)
+	cancel()
+	assert.NonNilErr(t, assert.ChanWritten(t, canceledErr))
+
+	// Every payment was recorded in the audit log.
+	entries, err := c.PaymentAuditLog()
+	orFatal(t, err)
+	var decisions []clientdb.PaymentAuditDecision
+	for _, e := range entries {
+		decisions = append(decisions, e.Decision)
+	}
+	assert.DeepEqual(t, decisions, []clientdb.PaymentAuditDecision{
+		clientdb.PaymentAuditBelowThreshold, clientdb.PaymentAuditApproved,
+		clientdb.PaymentAuditDenied, clientdb.PaymentAuditCanceled,
+	})
+	assert.DeepEqual(t, entries[2].Reason, "too expensive")
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
 	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/slog"
@@ -27,6 +28,16 @@ import (
 //   handleInvoice()
 //     (out-of-band payment)
 
+// TipOptions are optional settings of tips.
+type TipOptions struct {
+	// Post is the post (authored by the tipped user) being tipped.
+	Post *clientintf.PostID
+
+	// Public is set when the local client consents to being named in the
+	// acknowledgments of tips published by the tipped user.
+	Public bool
+}
+
 // TipUser starts an attempt to tip the user some amount of dcr. This dispatches
 // a request for an invoice to the remote user, which once received will be
 // paid.
@@ -35,6 +46,15 @@ import (
 // enough funds to pay for it, so multiple attempts will be made to fetch and
 // pay for an invoice.
 func (c *Client) TipUser(uid UserID, dcrAmount float64, maxAttempts int32) error {
+	return c.TipUserWithOptions(uid, dcrAmount, maxAttempts, TipOptions{})
+}
+
+// TipUserWithOptions is like TipUser, but allows specifying the post being
+// tipped and whether the local client consents to being named by the tipped
+// user in their acknowledgments of tips.
+func (c *Client) TipUserWithOptions(uid UserID, dcrAmount float64, maxAttempts int32,
+	opts TipOptions) error {
+
 	if dcrAmount <= 0 {
 		return fmt.Errorf("cannot pay user %f <= 0", dcrAmount)
 	}
@@ -73,6 +93,8 @@ func (c *Client) TipUser(uid UserID, dcrAmount float64, maxAttempts int32) error
 			Created:     time.Now(),
 			Attempts:    0,
 			MaxAttempts: maxAttempts,
+			Post:        opts.Post,
+			Public:      opts.Public,
 		}
 		return c.db.StoreTipUserAttempt(tx, ta)
 	})
@@ -99,8 +121,8 @@ func (c *Client) TipUser(uid UserID, dcrAmount float64, maxAttempts int32) error
 
 // trackGeneratedTipInvoice tracks an invoice generated by the local client for
 // a remote tip payment. This blocks until the invoice is paid or expires.
-func (c *Client) trackGeneratedTipInvoice(ctx context.Context, uid clientintf.UserID, invoice string,
-	wantMAtoms int64) {
+func (c *Client) trackGeneratedTipInvoice(ctx context.Context, inv clientdb.GeneratedInvoiceForTip) {
+	uid, invoice, wantMAtoms := inv.UID, inv.Invoice, int64(inv.MilliAtoms)
 
 	var err error
 	defer func() {
@@ -133,6 +155,9 @@ func (c *Client) trackGeneratedTipInvoice(ctx context.Context, uid clientintf.Us
 		if err := c.db.RecordUserPayEvent(tx, uid, "tip", receivedMAtoms, 0); err != nil {
 			return err
 		}
+		if err := c.recordReceivedTip(tx, uid, receivedMAtoms, inv.Post, inv.Public); err != nil {
+			return err
+		}
 		return c.db.MarkGeneratedTipInvoiceReceived(tx, uid, invoice, receivedMAtoms)
 	})
 	if err != nil {
@@ -169,6 +194,7 @@ func (c *Client) handleGetInvoice(ru *RemoteUser, getInvoice rpc.RMGetInvoice) e
 	dcrAmount := float64(amountMAtoms) / 1e11
 	inv, err := c.pc.GetInvoice(c.ctx, amountMAtoms, nil)
 	if err != nil {
+		c.cfg.Metrics.Inc(metrics.InvoiceGenFailures)
 		c.ntfns.notifyInvoiceGenFailed(ru, dcrAmount, err)
 		replyWithErr(rpc.ErrUnableToGenerateInvoice)
 		ru.log.Warnf("Unable to generate invoice for %.8f DCR: %v",
@@ -189,14 +215,21 @@ func (c *Client) handleGetInvoice(ru *RemoteUser, getInvoice rpc.RMGetInvoice) e
 	}
 
 	// Persist the generated invoice.
+	genInv := clientdb.GeneratedInvoiceForTip{
+		UID:        ru.ID(),
+		Invoice:    inv,
+		MilliAtoms: uint64(amountMAtoms),
+		Post:       getInvoice.Post,
+		Public:     getInvoice.Public,
+	}
 	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
-		return c.db.StoreGeneratedTipInvoice(tx, ru.ID(), inv, amountMAtoms)
+		return c.db.StoreGeneratedTipInvoice(tx, &genInv)
 	})
 	if err != nil {
 		return err
 	}
 
-	go c.trackGeneratedTipInvoice(c.ctx, ru.ID(), inv, amountMAtoms)
+	go c.trackGeneratedTipInvoice(c.ctx, genInv)
 	c.ntfns.notifyTipUserInvoiceGenerated(ru, getInvoice.Tag, inv)
 
 	// Send reply.
@@ -241,8 +274,10 @@ func (c *Client) handleTipUserPaymentResult(ru *RemoteUser, tag int32, payErr er
 			ta.Completed = &now
 			ta.LastInvoiceError = nil
 
-		case errors.Is(payErr, clientintf.ErrRetriablePayment):
-			// Will try the payment again after a delay.
+		case errors.Is(payErr, clientintf.ErrRetriablePayment) && ta.LastInvoice != "":
+			// Will try the payment again after a delay. Failed
+			// keysend payments (which have no invoice) are instead
+			// retried as a new attempt.
 			now := time.Now()
 			ta.PaymentAttempt = nil
 			ta.PaymentAttemptFailed = &now
@@ -253,6 +288,11 @@ func (c *Client) handleTipUserPaymentResult(ru *RemoteUser, tag int32, payErr er
 			ta.LastInvoiceError = &errMsg
 			ta.LastInvoice = ""
 			ta.PaymentAttempt = nil
+
+			// Denied payments are not attempted again.
+			if errors.Is(payErr, clientintf.ErrPaymentNotApproved) {
+				ta.Attempts = ta.MaxAttempts
+			}
 		}
 
 		return c.db.StoreTipUserAttempt(tx, ta)
@@ -286,10 +326,68 @@ func (c *Client) handleTipUserPaymentResult(ru *RemoteUser, tag int32, payErr er
 	}
 }
 
+// payInvoice pays the invoice after checking that the estimated fee to pay it
+// is within the fee policy of the payment category. amountMAtoms is the amount
+// being paid, used to determine the max fee of percentage based policies. uid
+// is the remote user being paid, if known.
+//
+// In the audit mode of outgoing payments, this blocks until the payment is
+// approved or denied, if it requires approval.
+func (c *Client) payInvoice(category clientintf.PaymentCategory, uid *UserID,
+	invoice string, amountMAtoms int64) (int64, error) {
+
+	if err := c.waitPaymentApproval(category, uid, invoice, amountMAtoms); err != nil {
+		return 0, err
+	}
+
+	policy := c.cfg.FeePolicies[category]
+	maxFee := policy.MaxFee(amountMAtoms)
+	if maxFee < 0 {
+		return c.pc.PayInvoice(c.ctx, invoice)
+	}
+
+	estFee, err := c.pc.EstimatePaymentFee(c.ctx, invoice, amountMAtoms)
+	if err != nil {
+		return 0, err
+	}
+	if estFee > maxFee {
+		c.log.Warnf("Skipping %s payment of %d milliatoms due to estimated "+
+			"fee %d > max fee %d", category, amountMAtoms, estFee, maxFee)
+		c.ntfns.notifyPaymentFeeLimitExceeded(category, amountMAtoms,
+			estFee, maxFee)
+		return 0, fmt.Errorf("%w (%d > %d milliatoms)",
+			clientintf.ErrFeeLimitExceeded, estFee, maxFee)
+	}
+
+	return c.pc.PayInvoice(c.ctx, invoice)
+}
+
+// PayInvoice pays an invoice (for example, one received from a remote store),
+// applying the fee policy of the specified payment category. Returns the fees
+// paid (in milliatoms).
+func (c *Client) PayInvoice(category clientintf.PaymentCategory, invoice string) (int64, error) {
+	decoded, err := c.pc.DecodeInvoice(c.ctx, invoice)
+	if err != nil {
+		return 0, err
+	}
+	return c.payInvoice(category, nil, invoice, decoded.MAtoms)
+}
+
 // payTipInvoice starts the payment process for a received invoice.
 func (c *Client) payTipInvoice(ru *RemoteUser, invoice string, amtMAtoms int64, tag int32) {
-	fees, payErr := c.pc.PayInvoice(c.ctx, invoice)
+	uid := ru.ID()
+	fees, payErr := c.payInvoice(clientintf.PaymentCategoryTip, &uid, invoice, amtMAtoms)
 	c.handleTipUserPaymentResult(ru, tag, payErr, fees)
+	if payErr != nil {
+		return
+	}
+	if _, ok := c.pc.(clientintf.PaymentPreimageClient); !ok {
+		return
+	}
+	_, err := c.sendPaymentProof(ru, invoice, rpc.PaymentProofContextTip)
+	if err != nil {
+		ru.log.Warnf("Unable to send payment proof for tip: %v", err)
+	}
 }
 
 // handleInvoice handles received RMInvoice calls.
@@ -297,6 +395,9 @@ func (c *Client) handleInvoice(ru *RemoteUser, invoice rpc.RMInvoice) error {
 
 	// Decode invoice to determine if it's valid.
 	decoded, decodedErr := c.pc.DecodeInvoice(c.ctx, invoice.Invoice)
+	if decodedErr == nil {
+		c.recordUserLNNode(ru, decoded.DestNode)
+	}
 
 	var ta clientdb.TipUserAttempt
 	var invoiceErr error
@@ -461,6 +562,13 @@ func (c *Client) takeTipAttemptAction(ctx context.Context, attempts *tipAttempts
 		return err
 	}
 
+	// Determine whether the tip may be sent via keysend before updating
+	// the DB, so that the keysend payment is recorded as in-flight.
+	var keysendNode string
+	if rta.nextAction == actionRequestInvoice && c.cfg.TipUserKeysendFallback {
+		keysendNode, _ = c.canTipViaKeysend(rta.uid)
+	}
+
 	// Update the DB with the status of the action being in progress.
 	var ta clientdb.TipUserAttempt
 	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) (runErr error) {
@@ -527,10 +635,19 @@ func (c *Client) takeTipAttemptAction(ctx context.Context, attempts *tipAttempts
 			now := time.Now()
 			ta.InvoiceRequested = &now
 			ta.LastInvoiceError = nil
+
+			// When a prior request for an invoice was not answered,
+			// the tip is sent via keysend. Record the payment
+			// attempt, so that invoices received while the keysend
+			// is in-flight are not paid.
+			paying := keysendNode != "" && ta.Attempts > 1
+			if paying {
+				ta.PaymentAttempt = &now
+			}
 			if err := c.db.StoreTipUserAttempt(tx, ta); err != nil {
 				return err
 			}
-			nextRTA := attempts.modifyTipAttempt(&ta, false)
+			nextRTA := attempts.modifyTipAttempt(&ta, paying)
 			ru.log.Tracef("Next tip attempt %d action %s at time %s",
 				ta.Tag, nextRTA.nextAction, nextRTA.nextActionTime)
 			return nil
@@ -603,11 +720,19 @@ func (c *Client) takeTipAttemptAction(ctx context.Context, attempts *tipAttempts
 			int(ta.Attempts), nil, false)
 
 	case actionRequestInvoice:
+		// Send the tip via keysend if the attempt was recorded as such.
+		if ta.PaymentAttempt != nil {
+			go c.payTipKeysend(ru, keysendNode, ta)
+			return nil
+		}
+
 		// Request a new invoice from remote user.
 		getInvoice := rpc.RMGetInvoice{
 			PayScheme:  c.pc.PayScheme(),
 			MilliAtoms: ta.MilliAtoms,
 			Tag:        uint32(ta.Tag),
+			Post:       ta.Post,
+			Public:     ta.Public,
 		}
 
 		ru.log.Debugf("Attempt %d/%d at requesting invoice for tip payment of "+
@@ -813,7 +938,7 @@ func (c *Client) restartTrackGeneratedTipInvoices(ctx context.Context) error {
 
 	// Check ones that are expired.
 	for _, inv := range invoices {
-		go c.trackGeneratedTipInvoice(ctx, inv.UID, inv.Invoice, int64(inv.MilliAtoms))
+		go c.trackGeneratedTipInvoice(ctx, inv)
 	}
 
 	return nil
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// Payment proofs link an LN payment to the identity of its payer. After paying
+// an invoice generated by a remote user, the local client signs a proof that
+// includes the preimage of the payment and its context (for example, a tip or
+// an order id), stores it and sends it to the payee, which stores it as well.
+// Either party may later use the proof to show the payment was made.
+
+// SendPaymentProof creates a payment proof for the specified (already paid)
+// invoice generated by the remote user. The proof is stored locally and sent
+// to the remote user.
+//
+// The context is a free-form string that identifies what the payment was made
+// for (e.g. "order:123").
+func (c *Client) SendPaymentProof(uid UserID, invoice, context string) (rpc.PaymentProof, error) {
+	ru, err := c.rul.byID(uid)
+	if err != nil {
+		return rpc.PaymentProof{}, err
+	}
+	return c.sendPaymentProof(ru, invoice, context)
+}
+
+// SendOrderPaymentProof creates and sends to the store of the remote user a
+// payment proof for the (already paid) invoice of the order in the summary.
+// The summary must be for an order placed by the local client.
+func (c *Client) SendOrderPaymentProof(uid UserID, summary *rpc.OrderSummary) (rpc.PaymentProof, error) {
+	if summary.Buyer != c.PublicID() {
+		return rpc.PaymentProof{}, fmt.Errorf("order %s was placed "+
+			"by %s", summary.OrderID, summary.Buyer)
+	}
+	if summary.Invoice == "" {
+		return rpc.PaymentProof{}, fmt.Errorf("order %s does not have "+
+			"an LN invoice", summary.OrderID)
+	}
+	ru, err := c.rul.byID(uid)
+	if err != nil {
+		return rpc.PaymentProof{}, err
+	}
+	context := rpc.PaymentProofOrderContext(summary.Buyer, summary.OrderID)
+	return c.sendPaymentProof(ru, summary.Invoice, context)
+}
+
+func (c *Client) sendPaymentProof(ru *RemoteUser, invoice, context string) (rpc.PaymentProof, error) {
+	ppc, ok := c.pc.(clientintf.PaymentPreimageClient)
+	if !ok {
+		return rpc.PaymentProof{}, fmt.Errorf("payment client does not " +
+			"support payment proofs")
+	}
+
+	decoded, err := c.pc.DecodeInvoice(c.ctx, invoice)
+	if err != nil {
+		return rpc.PaymentProof{}, err
+	}
+	preimage, err := ppc.PaymentPreimage(c.ctx, invoice)
+	if err != nil {
+		return rpc.PaymentProof{}, err
+	}
+
+	proof := rpc.PaymentProof{
+		Payer:       c.PublicID(),
+		Payee:       ru.ID(),
+		PaymentHash: decoded.ID,
+		Preimage:    preimage,
+		MilliAtoms:  uint64(decoded.MAtoms),
+		Context:     context,
+		Timestamp:   time.Now().Unix(),
+	}
+	proof.Signature = c.id.SignMessage(proof.SignedHash())
+	if !proof.VerifyPreimage() {
+		return rpc.PaymentProof{}, fmt.Errorf("preimage returned by " +
+			"payment client does not match invoice")
+	}
+
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.StorePaymentProof(tx, ru.ID(), proof)
+	})
+	if err != nil {
+		return rpc.PaymentProof{}, err
+	}
+
+	// Older clients do not support receiving payment proofs, so only
+	// store the proof locally in that case.
+	if !c.UserSupportsCommand(ru.ID(), rpc.RMCPaymentProof) {
+		ru.log.Debugf("Not sending payment proof for payment %x (%q) "+
+			"to user without support for payment proofs",
+			proof.PaymentHash, context)
+		return proof, nil
+	}
+
+	ru.log.Debugf("Sending payment proof for payment %x (%q)",
+		proof.PaymentHash, context)
+	rm := rpc.RMPaymentProof{Proof: proof}
+	return proof, c.sendWithSendQ("paymentproof", rm, ru.ID())
+}
+
+// VerifyPaymentProof verifies that the payment proof has a valid preimage and
+// was signed by its payer. The payer must be either the local client or a
+// known remote user.
+func (c *Client) VerifyPaymentProof(proof rpc.PaymentProof) error {
+	if !proof.VerifyPreimage() {
+		return fmt.Errorf("preimage does not match payment hash")
+	}
+
+	var payer *zkidentity.PublicIdentity
+	if proof.Payer == c.PublicID() {
+		payer = &c.id.Public
+	} else {
+		ru, err := c.rul.byID(proof.Payer)
+		if err != nil {
+			return fmt.Errorf("unknown payer: %v", err)
+		}
+		payer = ru.id
+	}
+	if !payer.VerifyMessage(proof.SignedHash(), proof.Signature) {
+		return fmt.Errorf("invalid payer signature")
+	}
+	return nil
+}
+
+// ListPaymentProofs lists the payment proofs of payments made between the local
+// client and the remote user.
+func (c *Client) ListPaymentProofs(uid UserID) ([]rpc.PaymentProof, error) {
+	var res []rpc.PaymentProof
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.ListPaymentProofs(tx, uid)
+		return err
+	})
+	return res, err
+}
+
+// handlePaymentProof handles a payment proof sent by a remote user that paid
+// an invoice generated by the local client.
+func (c *Client) handlePaymentProof(ru *RemoteUser, rm rpc.RMPaymentProof) error {
+	proof := rm.Proof
+	if proof.Payer != ru.ID() {
+		return fmt.Errorf("payment proof has payer %s", proof.Payer)
+	}
+	if proof.Payee != c.PublicID() {
+		return fmt.Errorf("payment proof has payee %s", proof.Payee)
+	}
+	if err := c.VerifyPaymentProof(proof); err != nil {
+		return fmt.Errorf("invalid payment proof: %v", err)
+	}
+
+	// Only store proofs of payments made to invoices generated by the
+	// local node.
+	ilc, ok := c.pc.(clientintf.InvoiceLookupClient)
+	if !ok {
+		return fmt.Errorf("payment client cannot verify payment proofs")
+	}
+	paid, err := ilc.SettledInvoiceMAtoms(c.ctx, proof.PaymentHash)
+	if err != nil {
+		return fmt.Errorf("payment proof for payment %x does not match "+
+			"a local invoice: %v", proof.PaymentHash, err)
+	}
+	if paid < int64(proof.MilliAtoms) {
+		return fmt.Errorf("payment proof for payment %x of %d milliatoms "+
+			"when the invoice was paid %d milliatoms", proof.PaymentHash,
+			proof.MilliAtoms, paid)
+	}
+
+	ru.log.Debugf("Received payment proof for payment %x (%q)",
+		proof.PaymentHash, proof.Context)
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.StorePaymentProof(tx, ru.ID(), proof)
+	})
+	if err != nil {
+		return err
+	}
+	c.ntfns.notifyPaymentProofReceived(ru, proof)
+	return nil
+}
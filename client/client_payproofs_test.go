@@ -0,0 +1,147 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/slog"
+)
+
+// settledInvoicesPaymentClient is a payment client that looks up the amount
+// paid to invoices in a fixed list of settled invoices.
+type settledInvoicesPaymentClient struct {
+	clientintf.FreePaymentClient
+	settled map[[32]byte]int64
+}
+
+func (pc *settledInvoicesPaymentClient) SettledInvoiceMAtoms(_ context.Context, paymentHash []byte) (int64, error) {
+	var hash [32]byte
+	copy(hash[:], paymentHash)
+	paid, ok := pc.settled[hash]
+	if !ok || len(paymentHash) != len(hash) {
+		return 0, errors.New("invoice not found")
+	}
+	return paid, nil
+}
+
+// TestHandlePaymentProof tests that received payment proofs are only stored
+// when they match a settled local invoice, that each payment has a single
+// proof and that only the most recent proofs are kept.
+func TestHandlePaymentProof(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rnd := testRand(t)
+	aliceID := testID(t, rnd, "alice")
+	bobID := testID(t, rnd, "bob")
+	charlieID := testID(t, rnd, "charlie")
+
+	cfg := testDBConfig(t, aliceID, nil)
+	cfg.MaxPaymentProofs = 2
+	db, err := clientdb.New(cfg)
+	orFatal(t, err)
+	runTestDB(t, db)
+
+	pc := &settledInvoicesPaymentClient{settled: make(map[[32]byte]int64)}
+	ntfns := NewNotificationManager()
+	var received []rpc.PaymentProof
+	ntfns.RegisterSync(OnPaymentProofReceivedNtfn(func(ru *RemoteUser, proof rpc.PaymentProof) {
+		received = append(received, proof)
+	}))
+	c := &Client{
+		id:    aliceID,
+		pc:    pc,
+		ntfns: ntfns,
+		ctx:   ctx,
+		db:    db,
+		dbCtx: ctx,
+		rul:   newRemoteUserList(),
+		log:   slog.Disabled,
+	}
+	ru := newRemoteUser(nil, nil, db, &bobID.Public, aliceID, nil)
+	_, err = c.rul.add(ru)
+	orFatal(t, err)
+
+	// newProof returns a proof signed by Bob of a payment to Alice. The
+	// payment is settled with the given amount, unless it is zero.
+	newProof := func(i byte, paid int64) rpc.PaymentProof {
+		preimage := bytes.Repeat([]byte{i}, 32)
+		hash := sha256.Sum256(preimage)
+		if paid > 0 {
+			pc.settled[hash] = paid
+		}
+		proof := rpc.PaymentProof{
+			Payer:       bobID.Public.Identity,
+			Payee:       aliceID.Public.Identity,
+			PaymentHash: hash[:],
+			Preimage:    preimage,
+			MilliAtoms:  1000,
+			Context:     rpc.PaymentProofContextTip,
+			Timestamp:   1700000000 + int64(i),
+		}
+		proof.Signature = bobID.SignMessage(proof.SignedHash())
+		return proof
+	}
+	handle := func(proof rpc.PaymentProof) error {
+		return c.handlePaymentProof(ru, rpc.RMPaymentProof{Proof: proof})
+	}
+	assertStored := func(want ...rpc.PaymentProof) {
+		t.Helper()
+		got, err := c.ListPaymentProofs(bobID.Public.Identity)
+		orFatal(t, err)
+		if len(got) != len(want) {
+			t.Fatalf("unexpected nb of stored proofs: got %d, want %d",
+				len(got), len(want))
+		}
+		for i := range want {
+			if !bytes.Equal(got[i].PaymentHash, want[i].PaymentHash) {
+				t.Fatalf("unexpected proof %d: got %x, want %x", i,
+					got[i].PaymentHash, want[i].PaymentHash)
+			}
+		}
+	}
+
+	// A proof of a settled invoice is stored once.
+	proof1 := newProof(1, 1000)
+	orFatal(t, handle(proof1))
+	orFatal(t, handle(proof1))
+	assertStored(proof1)
+	if len(received) != 2 {
+		t.Fatalf("unexpected nb of received proofs: got %d, want 2",
+			len(received))
+	}
+
+	// Proofs of unknown or underpaid invoices are rejected.
+	if err := handle(newProof(2, 0)); err == nil {
+		t.Fatal("proof of unknown invoice was accepted")
+	}
+	if err := handle(newProof(3, 999)); err == nil {
+		t.Fatal("proof of underpaid invoice was accepted")
+	}
+
+	// Proofs not signed by the payer are rejected.
+	forged := newProof(4, 1000)
+	forged.Signature = charlieID.SignMessage(forged.SignedHash())
+	if err := handle(forged); err == nil {
+		t.Fatal("forged proof was accepted")
+	}
+	assertStored(proof1)
+	if len(received) != 2 {
+		t.Fatalf("rejected proofs were notified (%d received proofs)",
+			len(received))
+	}
+
+	// Only the most recent proofs are kept.
+	proof5, proof6 := newProof(5, 1000), newProof(6, 2000)
+	orFatal(t, handle(proof5))
+	orFatal(t, handle(proof6))
+	assertStored(proof5, proof6)
+}
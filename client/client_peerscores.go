@@ -0,0 +1,318 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	// peerReplyTimeout is how long a request sent to a remote user may go
+	// unreplied before it is counted as missed.
+	peerReplyTimeout = 72 * time.Hour
+
+	// peerScoreAlpha is the weight of the most recent sample in the
+	// moving averages of the latency and reliability of remote users.
+	peerScoreAlpha = 0.2
+
+	// peerAbandonedMissedStreak is the number of consecutive requests a
+	// remote user may miss before they are considered to have abandoned
+	// their client (if no other messages were received from them in the
+	// meantime).
+	peerAbandonedMissedStreak = 3
+
+	// peerRetryLatencyFactor is the multiple of the latency of a remote
+	// user below which requests are not retried.
+	peerRetryLatencyFactor = 4
+
+	// peerMaxRetryBackoff is the max exponent of the backoff of the
+	// retries to remote users that miss consecutive requests.
+	peerMaxRetryBackoff = 3
+)
+
+// PeerScore is the score of the latency and reliability of the replies of a
+// remote user to the requests sent by the local client.
+type PeerScore struct {
+	UID UserID
+
+	// Latency is the moving average of the time the user takes to reply
+	// to requests.
+	Latency time.Duration
+
+	// Reliability is the moving average of the ratio of requests replied
+	// by the user, from 0 (never replies) to 1 (always replies). It is
+	// zero until a request is replied or missed.
+	Reliability float64
+
+	// Replies and Missed are the total number of requests replied and not
+	// replied in time. Pending is the number of requests still awaiting a
+	// reply.
+	Replies uint64
+	Missed  uint64
+	Pending int
+
+	// MissedStreak is the number of consecutive requests not replied in
+	// time.
+	MissedStreak int
+
+	// LastReply is when the last reply was received.
+	LastReply time.Time
+
+	// Abandoned is set when the user missed several consecutive requests
+	// and no message was received from them since, which indicates they
+	// are no longer running their client.
+	Abandoned bool
+}
+
+// peerRequestKey returns the key of a request sent to a remote user that
+// expects a reply, and whether the msg is such a request.
+func peerRequestKey(msg interface{}) (string, bool) {
+	switch msg := msg.(type) {
+	case rpc.RMHandshakeSYN:
+		return "syn", true
+	case rpc.RMGetInvoice:
+		return fmt.Sprintf("invoice/%d", msg.Tag), true
+	case rpc.RMFetchResource:
+		return fmt.Sprintf("resource/%d", msg.Tag), true
+	default:
+		return "", false
+	}
+}
+
+// peerReplyKey returns the key of the request replied by the msg, and whether
+// the msg is a reply to a request.
+func peerReplyKey(msg interface{}) (string, bool) {
+	switch msg := msg.(type) {
+	case rpc.RMHandshakeSYNACK:
+		return "syn", true
+	case rpc.RMInvoice:
+		return fmt.Sprintf("invoice/%d", msg.Tag), true
+	case rpc.RMFetchResourceReply:
+		return fmt.Sprintf("resource/%d", msg.Tag), true
+	default:
+		return "", false
+	}
+}
+
+// addPeerSample adds a sample to a moving average. The first sample
+// initializes the average.
+func addPeerSample(avg, sample float64, first bool) float64 {
+	if first {
+		return sample
+	}
+	return avg + peerScoreAlpha*(sample-avg)
+}
+
+// expirePeerRequests counts the pending requests sent before the reply timeout
+// as missed.
+func expirePeerRequests(ps *clientdb.PeerStats, now time.Time) {
+	limit := now.Add(-peerReplyTimeout)
+	expired := make([]string, 0, len(ps.Pending))
+	for key, sent := range ps.Pending {
+		if sent.Before(limit) {
+			expired = append(expired, key)
+		}
+	}
+
+	// Count the oldest requests first, so that the start of the streak of
+	// missed requests is correct.
+	sort.Slice(expired, func(i, j int) bool {
+		return ps.Pending[expired[i]].Before(ps.Pending[expired[j]])
+	})
+	for _, key := range expired {
+		first := ps.Replies+ps.Missed == 0
+		ps.Reliability = addPeerSample(ps.Reliability, 0, first)
+		ps.Missed++
+		if ps.MissedStreak == 0 {
+			ps.StreakStart = ps.Pending[key]
+		}
+		ps.MissedStreak++
+		delete(ps.Pending, key)
+	}
+}
+
+// addPeerRequest records a request sent to the remote user.
+func addPeerRequest(ps *clientdb.PeerStats, key string, sent time.Time) {
+	expirePeerRequests(ps, sent)
+	if ps.Pending == nil {
+		ps.Pending = make(map[string]time.Time)
+	}
+	ps.Pending[key] = sent
+}
+
+// addPeerReply records the reply to a request, received by the server at the
+// given time. Returns false if there is no pending request with the key.
+func addPeerReply(ps *clientdb.PeerStats, key string, replied time.Time) bool {
+	expirePeerRequests(ps, replied)
+	sent, ok := ps.Pending[key]
+	if !ok {
+		return false
+	}
+	delete(ps.Pending, key)
+
+	latency := replied.Sub(sent)
+	if latency < 0 {
+		latency = 0
+	}
+	first := ps.Replies+ps.Missed == 0
+	ps.Latency = time.Duration(addPeerSample(float64(ps.Latency),
+		float64(latency), ps.Replies == 0))
+	ps.Reliability = addPeerSample(ps.Reliability, 1, first)
+	ps.Replies++
+	ps.MissedStreak = 0
+	ps.StreakStart = time.Time{}
+	ps.LastReply = replied
+	return true
+}
+
+// peerScore returns the score of the remote user from their stats, as of now.
+// lastDecTime is when the last message from the user was decrypted.
+func peerScore(uid UserID, ps clientdb.PeerStats, lastDecTime, now time.Time) PeerScore {
+	// Copy the pending requests, so that expiring them does not modify
+	// the stats.
+	pending := make(map[string]time.Time, len(ps.Pending))
+	for key, sent := range ps.Pending {
+		pending[key] = sent
+	}
+	ps.Pending = pending
+	expirePeerRequests(&ps, now)
+
+	return PeerScore{
+		UID:          uid,
+		Latency:      ps.Latency,
+		Reliability:  ps.Reliability,
+		Replies:      ps.Replies,
+		Missed:       ps.Missed,
+		Pending:      len(ps.Pending),
+		MissedStreak: int(ps.MissedStreak),
+		LastReply:    ps.LastReply,
+		Abandoned: ps.MissedStreak >= peerAbandonedMissedStreak &&
+			!lastDecTime.After(ps.StreakStart),
+	}
+}
+
+// peerRetryInterval returns the interval after which requests to the remote
+// user with the given score are retried, given the base retry interval.
+// Requests to users that usually take longer to reply are retried later, and
+// retries to users that missed consecutive requests are backed off.
+func peerRetryInterval(score PeerScore, base time.Duration) time.Duration {
+	interval := base
+	if slow := score.Latency * peerRetryLatencyFactor; slow > interval {
+		interval = slow
+	}
+	backoff := score.MissedStreak
+	if backoff > peerMaxRetryBackoff {
+		backoff = peerMaxRetryBackoff
+	}
+	return interval << backoff
+}
+
+// updatePeerStats applies f to the stats of the remote user.
+func (c *Client) updatePeerStats(uid UserID, f func(ps *clientdb.PeerStats) bool) error {
+	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		ps, err := c.db.GetPeerStats(tx, uid)
+		if errors.Is(err, clientdb.ErrNotFound) {
+			ps, err = &clientdb.PeerStats{}, nil
+		}
+		if err != nil {
+			return err
+		}
+		if !f(ps) {
+			return nil
+		}
+		return c.db.UpdatePeerStats(tx, uid, ps)
+	})
+}
+
+// recordPeerRequest records that a request that expects a reply was sent to
+// the remote user.
+func (c *Client) recordPeerRequest(ru *RemoteUser, msg interface{}) {
+	key, ok := peerRequestKey(msg)
+	if !ok {
+		return
+	}
+	err := c.updatePeerStats(ru.ID(), func(ps *clientdb.PeerStats) bool {
+		addPeerRequest(ps, key, time.Now())
+		return true
+	})
+	if err != nil {
+		ru.log.Warnf("Unable to record request %s in peer stats: %v", key, err)
+	}
+}
+
+// recordPeerReply records the reply of the remote user to a request, received
+// by the server at ts.
+func (c *Client) recordPeerReply(ru *RemoteUser, msg interface{}, ts time.Time) {
+	key, ok := peerReplyKey(msg)
+	if !ok {
+		return
+	}
+	err := c.updatePeerStats(ru.ID(), func(ps *clientdb.PeerStats) bool {
+		return addPeerReply(ps, key, ts)
+	})
+	if err != nil {
+		ru.log.Warnf("Unable to record reply %s in peer stats: %v", key, err)
+	}
+}
+
+// PeerScore returns the score of the latency and reliability of the replies of
+// the remote user.
+func (c *Client) PeerScore(uid UserID) (PeerScore, error) {
+	ru, err := c.UserByID(uid)
+	if err != nil {
+		return PeerScore{}, err
+	}
+	var ps *clientdb.PeerStats
+	err = c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		ps, err = c.db.GetPeerStats(tx, uid)
+		return err
+	})
+	if errors.Is(err, clientdb.ErrNotFound) {
+		ps, err = &clientdb.PeerStats{}, nil
+	}
+	if err != nil {
+		return PeerScore{}, err
+	}
+	_, lastDecTime := ru.LastRatchetTimes()
+	return peerScore(uid, *ps, lastDecTime, time.Now()), nil
+}
+
+// PeerScores returns the scores of the remote users to which requests were
+// sent, sorted by reliability (least reliable first).
+func (c *Client) PeerScores() ([]PeerScore, error) {
+	now := time.Now()
+	uids := c.rul.userList()
+	res := make([]PeerScore, 0, len(uids))
+	for _, uid := range uids {
+		ru, err := c.rul.byID(uid)
+		if err != nil {
+			continue
+		}
+		var ps *clientdb.PeerStats
+		err = c.dbView(func(tx clientdb.ReadTx) error {
+			var err error
+			ps, err = c.db.GetPeerStats(tx, uid)
+			return err
+		})
+		if errors.Is(err, clientdb.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, lastDecTime := ru.LastRatchetTimes()
+		res = append(res, peerScore(uid, *ps, lastDecTime, now))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Reliability != res[j].Reliability {
+			return res[i].Reliability < res[j].Reliability
+		}
+		return res[i].UID.String() < res[j].UID.String()
+	})
+	return res, nil
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// TestPeerRequestKeys tests that replies are matched to the requests they
+// reply to.
+func TestPeerRequestKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		req   interface{}
+		reply interface{}
+	}{{
+		name:  "handshake",
+		req:   rpc.RMHandshakeSYN{},
+		reply: rpc.RMHandshakeSYNACK{},
+	}, {
+		name:  "invoice",
+		req:   rpc.RMGetInvoice{Tag: 10},
+		reply: rpc.RMInvoice{Tag: 10},
+	}, {
+		name:  "resource",
+		req:   rpc.RMFetchResource{Tag: 20},
+		reply: rpc.RMFetchResourceReply{Tag: 20},
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reqKey, ok := peerRequestKey(tc.req)
+			assert.BoolIs(t, ok, true)
+			replyKey, ok := peerReplyKey(tc.reply)
+			assert.BoolIs(t, ok, true)
+			assert.DeepEqual(t, replyKey, reqKey)
+		})
+	}
+
+	_, ok := peerRequestKey(rpc.RMPrivateMessage{})
+	assert.BoolIs(t, ok, false)
+	_, ok = peerReplyKey(rpc.RMHandshakeACK{})
+	assert.BoolIs(t, ok, false)
+	key1, _ := peerRequestKey(rpc.RMGetInvoice{Tag: 1})
+	key2, _ := peerReplyKey(rpc.RMInvoice{Tag: 2})
+	if key1 == key2 {
+		t.Fatalf("replies with different tags match the same request")
+	}
+}
+
+// TestPeerScore tests the scoring of the latency and reliability of the
+// replies of a remote user.
+func TestPeerScore(t *testing.T) {
+	t.Parallel()
+
+	var uid UserID
+	start := time.Now()
+	ps := &clientdb.PeerStats{}
+
+	// The first reply initializes the latency and reliability.
+	addPeerRequest(ps, "syn", start)
+	assert.BoolIs(t, addPeerReply(ps, "syn", start.Add(time.Minute)), true)
+	score := peerScore(uid, *ps, start.Add(time.Minute), start.Add(time.Minute))
+	assert.DeepEqual(t, score.Latency, time.Minute)
+	assert.DeepEqual(t, score.Reliability, 1.0)
+	assert.DeepEqual(t, score.Replies, uint64(1))
+
+	// Replies without pending requests (for example, duplicate replies)
+	// are ignored.
+	assert.BoolIs(t, addPeerReply(ps, "syn", start.Add(2*time.Minute)), false)
+	assert.BoolIs(t, addPeerReply(ps, "invoice/1", start.Add(2*time.Minute)), false)
+
+	// Slower replies increase the moving average of the latency.
+	addPeerRequest(ps, "invoice/1", start)
+	assert.BoolIs(t, addPeerReply(ps, "invoice/1", start.Add(11*time.Minute)), true)
+	assert.DeepEqual(t, ps.Latency, 3*time.Minute)
+
+	// Pending requests are not counted as missed until the reply timeout
+	// elapses.
+	sent := start.Add(time.Hour)
+	for i := 0; i < peerAbandonedMissedStreak; i++ {
+		key, _ := peerRequestKey(rpc.RMFetchResource{Tag: rpc.ResourceTag(i)})
+		addPeerRequest(ps, key, sent.Add(time.Duration(i)*time.Minute))
+	}
+	lastDec := start.Add(time.Minute)
+	score = peerScore(uid, *ps, lastDec, sent.Add(peerReplyTimeout/2))
+	assert.DeepEqual(t, score.Pending, peerAbandonedMissedStreak)
+	assert.DeepEqual(t, score.Missed, uint64(0))
+	assert.BoolIs(t, score.Abandoned, false)
+
+	// Once it elapses, the requests are counted as missed and the user is
+	// considered to have abandoned their client. Scoring does not modify
+	// the stats.
+	now := sent.Add(2 * peerReplyTimeout)
+	score = peerScore(uid, *ps, lastDec, now)
+	assert.DeepEqual(t, score.Pending, 0)
+	assert.DeepEqual(t, score.Missed, uint64(peerAbandonedMissedStreak))
+	assert.DeepEqual(t, score.MissedStreak, peerAbandonedMissedStreak)
+	assert.BoolIs(t, score.Abandoned, true)
+	if score.Reliability >= 0.6 {
+		t.Fatalf("unexpected reliability after missed requests: %f",
+			score.Reliability)
+	}
+	assert.DeepEqual(t, len(ps.Pending), peerAbandonedMissedStreak)
+
+	// Users that sent any message after the start of the streak are not
+	// considered to have abandoned their client.
+	score = peerScore(uid, *ps, sent.Add(time.Second), now)
+	assert.BoolIs(t, score.Abandoned, false)
+
+	// Retries to the user are backed off.
+	base := 24 * time.Hour
+	assert.DeepEqual(t, peerRetryInterval(score, base), base<<peerAbandonedMissedStreak)
+
+	// A new reply resets the streak.
+	addPeerRequest(ps, "syn", now)
+	assert.BoolIs(t, addPeerReply(ps, "syn", now.Add(time.Minute)), true)
+	score = peerScore(uid, *ps, now.Add(time.Minute), now.Add(time.Minute))
+	assert.DeepEqual(t, score.MissedStreak, 0)
+	assert.BoolIs(t, score.Abandoned, false)
+	assert.DeepEqual(t, peerRetryInterval(score, base), base)
+}
+
+// TestPeerRetryInterval tests the tuning of the retry interval by the score of
+// a remote user.
+func TestPeerRetryInterval(t *testing.T) {
+	t.Parallel()
+
+	base := time.Hour
+	tests := []struct {
+		name  string
+		score PeerScore
+		want  time.Duration
+	}{{
+		name:  "no score",
+		score: PeerScore{},
+		want:  base,
+	}, {
+		name:  "fast peer",
+		score: PeerScore{Latency: time.Minute},
+		want:  base,
+	}, {
+		name:  "slow peer",
+		score: PeerScore{Latency: time.Hour},
+		want:  peerRetryLatencyFactor * time.Hour,
+	}, {
+		name:  "missed requests",
+		score: PeerScore{MissedStreak: 2},
+		want:  4 * base,
+	}, {
+		name:  "max backoff",
+		score: PeerScore{MissedStreak: 10},
+		want:  base << peerMaxRetryBackoff,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := peerRetryInterval(tc.score, base)
+			assert.DeepEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"golang.org/x/exp/slices"
+)
+
+// maxPostTeaserLen is the max length (in runes) of the teaser of posts
+// included in announcements.
+const maxPostTeaserLen = 140
+
+// postAnnouncement is the announcement of a post in GCs.
+type postAnnouncement struct {
+	id     clientintf.PostID
+	title  string
+	teaser string
+}
+
+// gcPostAnnounces tracks the announcements of posts in a GC.
+type gcPostAnnounces struct {
+	last    time.Time
+	pending []postAnnouncement
+	timer   *time.Timer
+}
+
+// postTeaser returns the teaser of the post: its description or the start of
+// its body after the title.
+func postTeaser(pm *rpc.PostMetadata) string {
+	teaser := strings.TrimSpace(pm.Attributes[rpc.RMPDescription])
+	if teaser == "" {
+		body := strings.TrimSpace(strings.ReplaceAll(pm.Attributes[rpc.RMPMain], "\r", "\n"))
+		title := clientintf.PostTitle(pm)
+		if _, ok := pm.Attributes[rpc.RMPTitle]; !ok {
+			// The title is the first line of the body.
+			if i := strings.Index(body, "\n"); i > -1 {
+				body = body[i+1:]
+			} else {
+				body = ""
+			}
+		} else if strings.HasPrefix(body, title) {
+			body = body[len(title):]
+		}
+		teaser = strings.Join(strings.Fields(body), " ")
+	}
+	if runes := []rune(teaser); len(runes) > maxPostTeaserLen {
+		teaser = strings.TrimSpace(string(runes[:maxPostTeaserLen])) + "..."
+	}
+	return teaser
+}
+
+// newPostAnnouncement returns the announcement of the post.
+func newPostAnnouncement(pm *rpc.PostMetadata) (postAnnouncement, error) {
+	var pa postAnnouncement
+	if err := pa.id.FromString(pm.Attributes[rpc.RMPIdentifier]); err != nil {
+		return pa, fmt.Errorf("invalid post identifier: %v", err)
+	}
+	pa.title = clientintf.PostTitle(pm)
+	pa.teaser = postTeaser(pm)
+	return pa, nil
+}
+
+// postAnnouncementMsg returns the GC message that announces the posts.
+func (c *Client) postAnnouncementMsg(posts []postAnnouncement) string {
+	var b strings.Builder
+	if len(posts) == 1 {
+		b.WriteString("New post")
+	} else {
+		fmt.Fprintf(&b, "%d new posts", len(posts))
+	}
+	fmt.Fprintf(&b, " by %s (author %s)\n", c.LocalNick(), c.PublicID())
+	for _, p := range posts {
+		fmt.Fprintf(&b, "\n%s\n", p.title)
+		if p.teaser != "" {
+			fmt.Fprintf(&b, "%s\n", p.teaser)
+		}
+		fmt.Fprintf(&b, "Post ID: %s\n", p.id)
+	}
+	b.WriteString("\nSubscribe to the posts of the author to fetch them.")
+	return b.String()
+}
+
+// SetPostAnnounceGCs sets the GCs where all new posts published by the local
+// client are announced. The announcements include the title, a teaser and the
+// ID of the post (which allows members to fetch it after subscribing to the
+// posts of the local client). An empty list disables the announcements.
+func (c *Client) SetPostAnnounceGCs(gcs []zkidentity.ShortID) error {
+	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		for _, gcID := range gcs {
+			if _, err := c.db.GetGC(tx, gcID); err != nil {
+				return fmt.Errorf("unable to load GC %s: %w", gcID, err)
+			}
+		}
+		cfg := &clientdb.PostAnnounceConfig{GCs: gcs}
+		return c.db.SetPostAnnounceConfig(tx, cfg)
+	})
+}
+
+// PostAnnounceGCs returns the GCs where new posts are announced.
+func (c *Client) PostAnnounceGCs() ([]zkidentity.ShortID, error) {
+	var res []zkidentity.ShortID
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		cfg, err := c.db.GetPostAnnounceConfig(tx)
+		if err != nil {
+			return err
+		}
+		res = cfg.GCs
+		return nil
+	})
+	return res, err
+}
+
+// AnnouncePost announces the given post (published by the local client) in
+// the given GCs. Announcements respect the min interval between announcements
+// of each GC (see Config.PostAnnounceInterval).
+func (c *Client) AnnouncePost(pid clientintf.PostID, gcs []zkidentity.ShortID) error {
+	var pm rpc.PostMetadata
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		pm, err = c.db.ReadPost(tx, c.PublicID(), pid)
+		if err != nil {
+			return err
+		}
+		for _, gcID := range gcs {
+			if _, err := c.db.GetGC(tx, gcID); err != nil {
+				return fmt.Errorf("unable to load GC %s: %w", gcID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	pa, err := newPostAnnouncement(&pm)
+	if err != nil {
+		return err
+	}
+	for _, gcID := range gcs {
+		c.queuePostAnnouncement(gcID, pa)
+	}
+	return nil
+}
+
+// announceNewPost announces a post just published by the local client in the
+// GCs configured with SetPostAnnounceGCs.
+func (c *Client) announceNewPost(pm *rpc.PostMetadata) {
+	gcs, err := c.PostAnnounceGCs()
+	if err != nil {
+		c.log.Errorf("Unable to load GCs to announce posts: %v", err)
+		return
+	}
+	if len(gcs) == 0 {
+		return
+	}
+	pa, err := newPostAnnouncement(pm)
+	if err != nil {
+		c.log.Errorf("Unable to announce new post: %v", err)
+		return
+	}
+	for _, gcID := range gcs {
+		c.queuePostAnnouncement(gcID, pa)
+	}
+}
+
+// queuePostAnnouncement sends the announcement of the post to the GC or, if
+// a post was announced in the GC less than PostAnnounceInterval ago, queues
+// it to be sent (along with other posts published in the meantime) once the
+// interval elapses.
+func (c *Client) queuePostAnnouncement(gcID zkidentity.ShortID, pa postAnnouncement) {
+	c.postAnnouncesMtx.Lock()
+	gcpa := c.postAnnounces[gcID]
+	if gcpa == nil {
+		gcpa = &gcPostAnnounces{}
+		c.postAnnounces[gcID] = gcpa
+	}
+	if slices.IndexFunc(gcpa.pending, func(p postAnnouncement) bool { return p.id == pa.id }) > -1 {
+		// Already queued.
+		c.postAnnouncesMtx.Unlock()
+		return
+	}
+	gcpa.pending = append(gcpa.pending, pa)
+	wait := c.cfg.PostAnnounceInterval - time.Since(gcpa.last)
+	if wait > 0 {
+		if gcpa.timer == nil {
+			gcpa.timer = time.AfterFunc(wait, func() {
+				c.flushPostAnnouncements(gcID)
+			})
+		}
+		c.log.Debugf("Delaying announcement of post %s in GC %s for %s",
+			pa.id, gcID, wait)
+		c.postAnnouncesMtx.Unlock()
+		return
+	}
+	c.postAnnouncesMtx.Unlock()
+
+	go c.flushPostAnnouncements(gcID)
+}
+
+// flushPostAnnouncements sends the pending announcements of posts to the GC.
+func (c *Client) flushPostAnnouncements(gcID zkidentity.ShortID) {
+	c.postAnnouncesMtx.Lock()
+	gcpa := c.postAnnounces[gcID]
+	if gcpa == nil || len(gcpa.pending) == 0 {
+		c.postAnnouncesMtx.Unlock()
+		return
+	}
+	posts := gcpa.pending
+	gcpa.pending = nil
+	gcpa.timer = nil
+	gcpa.last = time.Now()
+	c.postAnnouncesMtx.Unlock()
+
+	if c.ctx != nil && c.ctx.Err() != nil {
+		// Client is shutting down.
+		return
+	}
+
+	if err := c.sendPostAnnouncement(gcID, posts); err != nil {
+		c.log.Errorf("Unable to announce %d posts in GC %s: %v",
+			len(posts), gcID, err)
+	}
+}
+
+// sendPostAnnouncement sends the announcement of the posts to the members of
+// the GC. Members blocked in the GC or ignored by the local client are
+// skipped, as are the subscribers of the posts of the local client (who
+// already received the posts).
+func (c *Client) sendPostAnnouncement(gcID zkidentity.ShortID, posts []postAnnouncement) error {
+	msg := c.postAnnouncementMsg(posts)
+
+	var gc rpc.RMGroupList
+	var gcBlockList clientdb.GCBlockList
+	var subs []clientintf.UserID
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		var err error
+		if gc, err = c.db.GetGC(tx, gcID); err != nil {
+			return err
+		}
+		if gcBlockList, err = c.db.GetGCBlockList(tx, gcID); err != nil {
+			return err
+		}
+		if subs, err = c.db.ListPostSubscribers(tx); err != nil {
+			return err
+		}
+
+		gcAlias, err := c.GetGCAlias(gcID)
+		if err != nil {
+			gcAlias = gc.Name
+		}
+		return c.db.LogGCMsg(tx, gcAlias, gcID, false, c.id.Public.Nick, msg, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	members := make([]zkidentity.ShortID, 0, len(gc.Members))
+	for _, uid := range gcBlockList.FilterMembers(gc.Members) {
+		if slices.Contains(subs, uid) {
+			continue
+		}
+		if ru, err := c.rul.byID(uid); err == nil && ru.IsIgnored() {
+			continue
+		}
+		members = append(members, uid)
+	}
+	if len(members) == 0 || (len(members) == 1 && members[0] == c.PublicID()) {
+		c.log.Debugf("No members to announce posts in GC %s", gcID)
+		return nil
+	}
+
+	p := rpc.RMGroupMessage{
+		ID:         gcID,
+		Generation: gc.Generation,
+		Message:    msg,
+		Mode:       rpc.MessageModeNormal,
+		Nonce:      newMsgNonce(),
+	}
+	c.log.Infof("Announcing %d posts in GC %s", len(posts), gcID)
+	return c.sendToGCMembers(gcID, members, "msg", p, nil)
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/postarchive"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// archivedPost converts a local post and its status updates to its archived
+// version.
+func archivedPost(pm *rpc.PostMetadata, created time.Time,
+	updates []rpc.PostMetadataStatus) (postarchive.Post, error) {
+
+	p := postarchive.Post{
+		ID:          pm.Attributes[rpc.RMPIdentifier],
+		Title:       clientintf.PostTitle(pm),
+		Description: pm.Attributes[rpc.RMPDescription],
+		Created:     created,
+		Body:        pm.Attributes[rpc.RMPMain],
+	}
+	if blob := pm.Attributes[rpc.RMPAttachment]; blob != "" {
+		var err error
+		p.Attachment, err = base64.StdEncoding.DecodeString(blob)
+		if err != nil {
+			return p, fmt.Errorf("invalid attachment in post %s: %v",
+				p.ID, err)
+		}
+	}
+
+	for i := range updates {
+		update := &updates[i]
+		comment := update.Attributes[rpc.RMPSComment]
+		if comment == "" {
+			continue
+		}
+		hash := update.Hash()
+		c := postarchive.Comment{
+			ID:      clientintf.ID(hash).String(),
+			Parent:  update.Attributes[rpc.RMPParent],
+			From:    update.From,
+			Nick:    update.Attributes[rpc.RMPFromNick],
+			Comment: comment,
+		}
+		if ts, err := strconv.ParseInt(update.Attributes[rpc.RMPTimestamp], 16, 64); err == nil {
+			c.Timestamp = time.Unix(ts, 0).UTC()
+		}
+		p.Comments = append(p.Comments, c)
+	}
+	return p, nil
+}
+
+// ExportPosts writes an archive (in the format of the postarchive package) of
+// all posts published by the local client, including the comments received on
+// them, to w. Relayed posts of other users are not included.
+//
+// Returns the number of exported posts.
+func (c *Client) ExportPosts(w io.Writer) (int, error) {
+	me := c.PublicID()
+	a := &postarchive.Archive{
+		Exported:   time.Now().UTC(),
+		AuthorID:   me.String(),
+		AuthorNick: c.LocalNick(),
+	}
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		summaries, err := c.db.ListPosts(tx)
+		if err != nil {
+			return err
+		}
+		for _, summ := range summaries {
+			if summ.From != me || summ.AuthorID != me {
+				continue
+			}
+			pm, err := c.db.ReadPost(tx, me, summ.ID)
+			if err != nil {
+				return err
+			}
+			updates, err := c.db.ListPostStatusUpdates(tx, me, summ.ID)
+			if err != nil {
+				return err
+			}
+			p, err := archivedPost(&pm, summ.Date.UTC(), updates)
+			if err != nil {
+				return err
+			}
+			a.Posts = append(a.Posts, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := postarchive.Write(w, a); err != nil {
+		return 0, fmt.Errorf("unable to write posts archive: %v", err)
+	}
+	c.log.Infof("Exported %d posts to archive", len(a.Posts))
+	return len(a.Posts), nil
+}
+
+// ImportPosts republishes the posts of an archive (in the format of the
+// postarchive package) as new posts of the local client, which are shared
+// with the current subscribers. This allows users to move their posts to a
+// new identity.
+//
+// The comments in the archive are not republished, because they were signed
+// by their authors for the original posts.
+func (c *Client) ImportPosts(r io.ReaderAt, size int64) ([]clientdb.PostSummary, error) {
+	a, err := postarchive.Read(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]clientdb.PostSummary, 0, len(a.Posts))
+	for i := range a.Posts {
+		p := &a.Posts[i]
+		extraAttrs := make(map[string]string, 2)
+		if len(p.Attachment) > 0 {
+			extraAttrs[rpc.RMPAttachment] = base64.StdEncoding.EncodeToString(p.Attachment)
+		}
+
+		// Keep explicit titles that differ from the one derived from
+		// the body of the post.
+		bodyTitle := clientintf.PostTitle(&rpc.PostMetadata{
+			Attributes: map[string]string{rpc.RMPMain: p.Body},
+		})
+		if p.Title != "" && p.Title != bodyTitle {
+			extraAttrs[rpc.RMPTitle] = p.Title
+		}
+
+		summ, err := c.createPost(p.Body, p.Description, extraAttrs)
+		if err != nil {
+			return res, fmt.Errorf("unable to import post %s: %w", p.ID, err)
+		}
+		res = append(res, summ)
+	}
+	c.log.Infof("Imported %d posts from archive of %s (%s)", len(res),
+		a.AuthorNick, a.AuthorID)
+	return res, nil
+}
@@ -326,6 +326,12 @@ func (c *Client) shareWithPostSubscribers(subs []clientintf.UserID,
 
 // CreatePost creates a new post and shares it with all current subscribers.
 func (c *Client) CreatePost(post, descr string) (clientdb.PostSummary, error) {
+	return c.createPost(post, descr, nil)
+}
+
+// createPost creates a new post with the given extra attributes and shares it
+// with all current subscribers.
+func (c *Client) createPost(post, descr string, extraAttrs map[string]string) (clientdb.PostSummary, error) {
 	// Filename for embedded data is not currently used, so it's disabled at
 	// the client API level.
 	const fname = ""
@@ -335,7 +341,7 @@ func (c *Client) CreatePost(post, descr string) (clientdb.PostSummary, error) {
 	var summ clientdb.PostSummary
 	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
 		var err error
-		summ, pm, err = c.db.CreatePost(tx, post, descr, fname, nil, c.id)
+		summ, pm, err = c.db.CreatePost(tx, post, descr, fname, extraAttrs, c.id)
 		if err != nil {
 			return err
 		}
@@ -352,6 +358,7 @@ func (c *Client) CreatePost(post, descr string) (clientdb.PostSummary, error) {
 	if err := c.shareWithPostSubscribers(subs, summ.ID, rm, "sharecreated"); err != nil {
 		return summ, err
 	}
+	c.announceNewPost(&pm)
 
 	return summ, nil
 }
@@ -600,6 +607,7 @@ func (c *Client) handlePostShare(ru *RemoteUser, ps rpc.RMPostShare) error {
 	if !isUpdate {
 		ru.log.Infof("Received post %s", pid)
 		c.ntfns.notifyOnPostRcvd(ru, summ, p)
+		c.maybeSummarizeReceivedPost(from, pid, &p)
 	} else {
 		ru.log.Infof("Received post update %s from %s", pid, statusFrom)
 		c.ntfns.notifyOnPostStatusRcvd(ru, pid, statusFrom, update)
@@ -860,6 +868,14 @@ func (c *Client) handlePostStatus(ru *RemoteUser, rmps rpc.RMPostStatus) error {
 	if err == nil {
 		err = c.addStatusToPost(ru.ID(), &pms)
 	}
+	if errors.Is(err, clientdb.ErrDuplicatePostStatus) {
+		// A copy of the status update resent after a reconnection.
+		// Reply as if it was added, so that the sender stops
+		// resending it.
+		ru.log.Debugf("Ignoring duplicate status update %s on post %q",
+			pms.MsgID(), rmps.Link)
+		err = nil
+	}
 
 	// Send reply to status sender.
 	var reply rpc.RMPostStatusReply
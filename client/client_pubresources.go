@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// serverSession returns the current server session.
+func (c *Client) serverSession() (clientintf.ServerSessionIntf, error) {
+	c.sessMtx.Lock()
+	sess := c.sess
+	c.sessMtx.Unlock()
+	if sess == nil {
+		return nil, errNotConnected
+	}
+	return sess, nil
+}
+
+// sendServerRPC sends the msg and payload to the server and waits for its
+// reply.
+func (c *Client) sendServerRPC(ctx context.Context, sess clientintf.ServerSessionIntf,
+	msg rpc.Message, payload interface{}) (interface{}, error) {
+
+	replyChan := make(chan interface{})
+	if err := sess.SendPRPC(msg, payload, replyChan); err != nil {
+		return nil, err
+	}
+
+	var reply interface{}
+	select {
+	case reply = <-replyChan:
+	case <-sess.Context().Done():
+		return nil, errNotConnected
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err, ok := reply.(error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// PublishResource publishes the given data as a public resource on the
+// server, such that the server serves it (with the local client's signature)
+// to remote users while the local client is offline. This is opt-in: only
+// resources explicitly published are hosted by the server, and only if the
+// server supports hosting published resources.
+//
+// Publishing empty data removes a previously published resource.
+func (c *Client) PublishResource(ctx context.Context, path []string, data []byte) error {
+	sess, err := c.serverSession()
+	if err != nil {
+		return err
+	}
+	maxSize := sess.Policy().MaxPublishedResourceSize
+	if maxSize <= 0 {
+		return fmt.Errorf("server does not host published resources")
+	}
+	if len(data) > maxSize {
+		return fmt.Errorf("resource size %d larger than max %d allowed by "+
+			"server", len(data), maxSize)
+	}
+
+	res := rpc.PublishedResource{
+		Owner:         c.PublicID(),
+		OwnerIdentity: c.id.Public,
+		SigKey:        c.id.Public.SigKey,
+		Path:          path,
+		Data:          data,
+		Timestamp:     time.Now().Unix(),
+	}
+	res.Signature = c.id.SignMessage(res.SignedHash())
+
+	msg := rpc.Message{Command: rpc.TaggedCmdPublishResource}
+	payload := &rpc.PublishResource{Resource: res}
+	reply, err := c.sendServerRPC(ctx, sess, msg, payload)
+	if err != nil {
+		return err
+	}
+	switch reply := reply.(type) {
+	case *rpc.PublishResourceReply:
+		if reply.Error != "" {
+			return fmt.Errorf("server rejected published resource: %s",
+				reply.Error)
+		}
+	default:
+		return fmt.Errorf("unknown reply to publish resource: %v", reply)
+	}
+
+	if len(data) == 0 {
+		c.log.Infof("Removed published resource %q", res.PathKey())
+	} else {
+		c.log.Infof("Published resource %q (%d bytes)", res.PathKey(),
+			len(data))
+	}
+	return nil
+}
+
+// UnpublishResource removes a resource previously published with
+// PublishResource.
+func (c *Client) UnpublishResource(ctx context.Context, path []string) error {
+	return c.PublishResource(ctx, path, nil)
+}
+
+// FetchPublishedResource fetches the resource published on the server by the
+// given remote user. The signature of the resource is verified against the
+// identity of the remote user, so the server cannot forge its contents.
+func (c *Client) FetchPublishedResource(ctx context.Context, uid UserID,
+	path []string) (*rpc.PublishedResource, error) {
+
+	ru, err := c.UserByID(uid)
+	if err != nil {
+		return nil, err
+	}
+	id := ru.PublicIdentity()
+	return c.fetchPublishedResource(ctx, &id, strescape.Nick(ru.Nick()), path)
+}
+
+// fetchPublishedResource fetches the resource published on the server by the
+// given identity and verifies it was signed by the identity. nick is the nick
+// of the identity used in errors.
+func (c *Client) fetchPublishedResource(ctx context.Context, id *zkidentity.PublicIdentity,
+	nick string, path []string) (*rpc.PublishedResource, error) {
+
+	uid := id.Identity
+	sess, err := c.serverSession()
+	if err != nil {
+		return nil, err
+	}
+
+	msg := rpc.Message{Command: rpc.TaggedCmdFetchPublishedResource}
+	payload := &rpc.FetchPublishedResource{Owner: uid, Path: path}
+	reply, err := c.sendServerRPC(ctx, sess, msg, payload)
+	if err != nil {
+		return nil, err
+	}
+	var res *rpc.PublishedResource
+	switch reply := reply.(type) {
+	case *rpc.FetchPublishedResourceReply:
+		if reply.Error != "" {
+			return nil, errors.New(reply.Error)
+		}
+		res = reply.Resource
+	default:
+		return nil, fmt.Errorf("unknown reply to fetch published "+
+			"resource: %v", reply)
+	}
+
+	// Verify the resource was signed by the remote user.
+	switch {
+	case res == nil:
+		return nil, fmt.Errorf("server did not send the resource")
+	case res.Owner != uid:
+		return nil, fmt.Errorf("resource owner %s is not the requested "+
+			"user", res.Owner)
+	case res.PathKey() != rpc.PublishedResourcePathKey(path):
+		return nil, fmt.Errorf("resource path %q is not the requested "+
+			"path", res.PathKey())
+	case res.SigKey != id.SigKey:
+		return nil, fmt.Errorf("resource not signed by the signing key "+
+			"of user %s", nick)
+	case !id.VerifyMessage(res.SignedHash(), res.Signature):
+		return nil, fmt.Errorf("invalid signature of resource published "+
+			"by user %s", nick)
+	}
+	return res, nil
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// TrackInviteReferral starts tracking the invite with the given initial RV
+// (as returned in the invite created by WriteNewInvite or CreatePrepaidInvite)
+// as a referral with the given label. Once a user completes KX through the
+// invite, they are recorded as having joined through it.
+//
+// Referral data is local only: neither the label nor the fact that the invite
+// is being tracked are included in the invite.
+func (c *Client) TrackInviteReferral(initialRV zkidentity.ShortID, label string) error {
+	r := &clientdb.Referral{
+		InitialRV: initialRV,
+		Label:     label,
+		Created:   time.Now(),
+	}
+	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.AddReferral(tx, r)
+	})
+}
+
+// ListReferrals lists the tracked invite referrals, including ones not yet
+// accepted by any user.
+func (c *Client) ListReferrals() ([]clientdb.Referral, error) {
+	var res []clientdb.Referral
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.ListReferrals(tx)
+		return err
+	})
+	return res, err
+}
+
+// UserReferral returns the referral through which the user joined. Returns nil
+// if the user did not join through a tracked invite.
+func (c *Client) UserReferral(uid UserID) (*clientdb.Referral, error) {
+	var res *clientdb.Referral
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.UserReferral(tx, uid)
+		if errors.Is(err, clientdb.ErrNotFound) {
+			err = nil
+		}
+		return err
+	})
+	return res, err
+}
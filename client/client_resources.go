@@ -1,20 +1,174 @@
 package client
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
 	"github.com/companyzero/bisonrelay/client/resources"
 	"github.com/companyzero/bisonrelay/internal/strescape"
 	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/decred/slog"
 )
 
+const (
+	// resourceReplyChunkSize is the max size of the data of each chunk of
+	// a resource reply that is split into multiple RMs.
+	resourceReplyChunkSize = rpc.MaxChunkSize
+
+	// maxResourceReplyChunks is the max number of chunks of a resource
+	// reply. This limits the total size of resource replies.
+	maxResourceReplyChunks = 64
+
+	// resourceReplyChunksTimeout is how long the chunks of an incomplete
+	// resource reply are kept, waiting for the remaining chunks.
+	resourceReplyChunksTimeout = time.Hour
+)
+
+// splitResourceReply splits the reply into chunks with at most chunkSize bytes
+// of data each. The meta of the reply is only sent in the first chunk. Replies
+// that fit in a single chunk are returned as is.
+func splitResourceReply(res *rpc.RMFetchResourceReply, chunkSize int) []rpc.RMFetchResourceReply {
+	if len(res.Data) <= chunkSize {
+		return []rpc.RMFetchResourceReply{*res}
+	}
+
+	count := (len(res.Data) + chunkSize - 1) / chunkSize
+	chunks := make([]rpc.RMFetchResourceReply, count)
+	for i := range chunks {
+		start, end := i*chunkSize, (i+1)*chunkSize
+		if end > len(res.Data) {
+			end = len(res.Data)
+		}
+		chunks[i] = rpc.RMFetchResourceReply{
+			Tag:    res.Tag,
+			Status: res.Status,
+			Data:   res.Data[start:end],
+			Index:  uint32(i),
+			Count:  uint32(count),
+		}
+	}
+	chunks[0].Meta = res.Meta
+	return chunks
+}
+
+type resourceReplyChunksKey struct {
+	uid clientintf.UserID
+	tag rpc.ResourceTag
+}
+
+// partialResourceReply is a resource reply for which only some of the chunks
+// have been received.
+type partialResourceReply struct {
+	started  time.Time
+	chunks   [][]byte
+	received int
+	size     int64
+	first    rpc.RMFetchResourceReply
+}
+
+// resourceReplyChunks reassembles resource replies received in multiple
+// chunks.
+type resourceReplyChunks struct {
+	mtx sync.Mutex
+	m   map[resourceReplyChunksKey]*partialResourceReply
+}
+
+func newResourceReplyChunks() *resourceReplyChunks {
+	return &resourceReplyChunks{
+		m: make(map[resourceReplyChunksKey]*partialResourceReply),
+	}
+}
+
+// isPending returns true if chunks of the reply with the given tag have
+// already been received from the user.
+func (rc *resourceReplyChunks) isPending(uid clientintf.UserID, tag rpc.ResourceTag) bool {
+	rc.mtx.Lock()
+	_, ok := rc.m[resourceReplyChunksKey{uid: uid, tag: tag}]
+	rc.mtx.Unlock()
+	return ok
+}
+
+// add adds a received chunk of a reply. It returns the reassembled reply once
+// all chunks have been received, along with the number of chunks and bytes
+// received so far.
+func (rc *resourceReplyChunks) add(uid clientintf.UserID, frr *rpc.RMFetchResourceReply,
+	now time.Time) (*rpc.RMFetchResourceReply, int, int64, error) {
+
+	if frr.Count > maxResourceReplyChunks {
+		return nil, 0, 0, fmt.Errorf("resource reply has too many "+
+			"chunks (%d > %d)", frr.Count, maxResourceReplyChunks)
+	}
+	if frr.Index >= frr.Count {
+		return nil, 0, 0, fmt.Errorf("resource reply chunk index %d "+
+			"is not lower than count %d", frr.Index, frr.Count)
+	}
+
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+
+	// Drop replies for which the remaining chunks were never received.
+	for k, p := range rc.m {
+		if now.Sub(p.started) > resourceReplyChunksTimeout {
+			delete(rc.m, k)
+		}
+	}
+
+	key := resourceReplyChunksKey{uid: uid, tag: frr.Tag}
+	p := rc.m[key]
+	if p == nil {
+		p = &partialResourceReply{
+			started: now,
+			chunks:  make([][]byte, frr.Count),
+		}
+		rc.m[key] = p
+	}
+	if int(frr.Count) != len(p.chunks) {
+		return nil, 0, 0, fmt.Errorf("resource reply chunk count %d "+
+			"differs from previous chunks count %d", frr.Count,
+			len(p.chunks))
+	}
+	if p.chunks[frr.Index] != nil {
+		// Duplicate chunk.
+		return nil, p.received, p.size, nil
+	}
+
+	data := frr.Data
+	if data == nil {
+		data = []byte{}
+	}
+	p.chunks[frr.Index] = data
+	p.received += 1
+	p.size += int64(len(data))
+	if frr.Index == 0 {
+		p.first = *frr
+	}
+	if p.received < len(p.chunks) {
+		return nil, p.received, p.size, nil
+	}
+
+	// All chunks received.
+	delete(rc.m, key)
+	res := p.first
+	res.Data = make([]byte, 0, p.size)
+	for _, chunk := range p.chunks {
+		res.Data = append(res.Data, chunk...)
+	}
+	res.Index, res.Count = 0, 0
+	return &res, p.received, p.size, nil
+}
+
 func (c *Client) NewPagesSession() (clientintf.PagesSessionID, error) {
 	var id clientintf.PagesSessionID
 	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
@@ -30,7 +184,8 @@ func (c *Client) NewPagesSession() (clientintf.PagesSessionID, error) {
 // ResourceFetched call.
 func (c *Client) FetchLocalResource(path []string, meta map[string]string, data json.RawMessage) error {
 	if c.cfg.ResourcesProvider == nil {
-		return fmt.Errorf("resources provider not configured")
+		return clientintf.KindErrorf(clientintf.ErrResourceNotFound,
+			"resources provider not configured")
 	}
 
 	rm := rpc.RMFetchResource{
@@ -42,7 +197,8 @@ func (c *Client) FetchLocalResource(path []string, meta map[string]string, data
 
 	res, err := c.cfg.ResourcesProvider.Fulfill(c.ctx, c.PublicID(), &rm)
 	if errors.Is(err, resources.ErrProviderNotFound) {
-		return fmt.Errorf("Provider not found for local request path %s",
+		return clientintf.KindErrorf(clientintf.ErrResourceNotFound,
+			"Provider not found for local request path %s",
 			strescape.ResourcesPath(path))
 	} else if err != nil {
 		return err
@@ -105,7 +261,8 @@ func (c *Client) FetchResource(uid UserID, path []string, meta map[string]string
 }
 
 // handleFetchResource handles receiving a request to send a resource to the
-// remote client.
+// remote client. Allowed requests are queued to be fulfilled by the resource
+// workers.
 func (c *Client) handleFetchResource(ru *RemoteUser, fr rpc.RMFetchResource) error {
 	// TODO: support chunked data request.
 	if fr.Index != 0 || fr.Count != 0 {
@@ -116,6 +273,76 @@ func (c *Client) handleFetchResource(ru *RemoteUser, fr rpc.RMFetchResource) err
 		return fmt.Errorf("resources provider not configured")
 	}
 
+	policy := c.userTrustPolicy(ru)
+	if !policy.ResourcesAccess {
+		ru.log.Debugf("Denying request tag %s for resource %s due to "+
+			"trust tier policy", fr.Tag, strescape.ResourcesPath(fr.Path))
+		res := rpc.RMFetchResourceReply{
+			Tag:    fr.Tag,
+			Status: rpc.ResourceStatusForbidden,
+			Data:   []byte("Access to resources is not allowed."),
+		}
+		payEvent := "resource." + strescape.ResourcesPath(fr.Path)
+		return c.sendWithSendQ(payEvent, res, ru.ID())
+	}
+
+	reason, retryAfter := c.resRateLimiter.check(ru.ID(), fr.Path,
+		policy.ResourceRateLimit, time.Now())
+	switch reason {
+	case ResourceAbuseDenied:
+		ru.log.Debugf("Denying request tag %s for resource %s due to "+
+			"deny list", fr.Tag, strescape.ResourcesPath(fr.Path))
+		res := rpc.RMFetchResourceReply{
+			Tag:    fr.Tag,
+			Status: rpc.ResourceStatusForbidden,
+			Data:   []byte("Access to resources is not allowed."),
+		}
+		payEvent := "resource." + strescape.ResourcesPath(fr.Path)
+		return c.sendWithSendQ(payEvent, res, ru.ID())
+
+	case ResourceAbuseRateLimited:
+		secs := int64(math.Ceil(retryAfter.Seconds()))
+		ru.log.Debugf("Rate limiting request tag %s for resource %s "+
+			"(retry after %ds)", fr.Tag, strescape.ResourcesPath(fr.Path),
+			secs)
+		res := rpc.RMFetchResourceReply{
+			Tag:    fr.Tag,
+			Status: rpc.ResourceStatusTooManyRequests,
+			Meta: map[string]string{
+				rpc.ResourceMetaRetryAfter: strconv.FormatInt(secs, 10),
+			},
+			Data: []byte(fmt.Sprintf("Too many requests. Try again "+
+				"in %d seconds.", secs)),
+		}
+		payEvent := "resource." + strescape.ResourcesPath(fr.Path)
+		return c.sendWithSendQ(payEvent, res, ru.ID())
+	}
+
+	queued := c.resWorkers.submit(fr.Path, func() {
+		if err := c.fulfillResource(ru, fr); err != nil {
+			ru.log.Errorf("Unable to fulfill request tag %s for "+
+				"resource %s: %v", fr.Tag,
+				strescape.ResourcesPath(fr.Path), err)
+		}
+	})
+	if !queued {
+		ru.log.Warnf("Resource request queue is full. Replying to "+
+			"request tag %s for resource %s with too many requests",
+			fr.Tag, strescape.ResourcesPath(fr.Path))
+		res := rpc.RMFetchResourceReply{
+			Tag:    fr.Tag,
+			Status: rpc.ResourceStatusTooManyRequests,
+			Data:   []byte("Too many requests. Try again later."),
+		}
+		payEvent := "resource." + strescape.ResourcesPath(fr.Path)
+		return c.sendWithSendQ(payEvent, res, ru.ID())
+	}
+	return nil
+}
+
+// fulfillResource fulfills the resource request with the resources provider
+// and sends the reply to the remote client.
+func (c *Client) fulfillResource(ru *RemoteUser, fr rpc.RMFetchResource) error {
 	if ru.log.Level() < slog.LevelInfo {
 		ru.log.Debugf("Fullfilling request %d/%d tag %s for resource %s data %d meta %s",
 			fr.Index, fr.Count, fr.Tag, strescape.ResourcesPath(fr.Path),
@@ -135,38 +362,242 @@ func (c *Client) handleFetchResource(ru *RemoteUser, fr rpc.RMFetchResource) err
 		return err
 	}
 	res.Tag = fr.Tag // Ensure response tag is same as request tag
+	res.Index, res.Count = 0, 0
 
-	if len(res.Data) > rpc.MaxChunkSize {
+	maxSize := resourceReplyChunkSize * maxResourceReplyChunks
+	if len(res.Data) > maxSize {
 		return fmt.Errorf("resource %s returned more data (%d) than "+
-			"max chunk size %d", strescape.ResourcesPath(fr.Path),
-			len(res.Data), rpc.MaxChunkSize)
+			"max reply size %d", strescape.ResourcesPath(fr.Path),
+			len(res.Data), maxSize)
 	}
 
-	ru.log.Debugf("Fulfilled request tag %s with status %s chunk %d/%d len %d",
-		res.Tag, res.Status, res.Index, res.Count, len(res.Data))
-
 	payEvent := "resource." + strescape.ResourcesPath(fr.Path)
-	return c.sendWithSendQ(payEvent, *res, ru.ID())
+	chunks := splitResourceReply(res, resourceReplyChunkSize)
+	if len(chunks) == 1 {
+		ru.log.Debugf("Fulfilled request tag %s with status %s len %d",
+			res.Tag, res.Status, len(res.Data))
+		return c.sendWithSendQ(payEvent, *res, ru.ID())
+	}
+
+	// Send all chunks as a batch, so that they are sent in order and
+	// either all or none of them are queued.
+	ru.log.Debugf("Fulfilled request tag %s with status %s len %d in %d chunks",
+		res.Tag, res.Status, len(res.Data), len(chunks))
+	batch := newSendBatch()
+	for i := range chunks {
+		batch.add(payEvent, chunks[i], ru.ID())
+	}
+	return c.sendBatchWithSendQ(batch)
+}
+
+// ResourceReplyError returns the error that corresponds to the status of a
+// resource reply, or nil if the reply was successful. Replies refused due to
+// rate limiting return an error of kind ErrRateLimited and replies for
+// missing resources an error of kind ErrResourceNotFound.
+func ResourceReplyError(res *rpc.RMFetchResourceReply) error {
+	switch res.Status {
+	case rpc.ResourceStatusOk:
+		return nil
+	case rpc.ResourceStatusNotFound:
+		return clientintf.KindErrorf(clientintf.ErrResourceNotFound,
+			"resource not found")
+	case rpc.ResourceStatusTooManyRequests:
+		if secs := res.Meta[rpc.ResourceMetaRetryAfter]; secs != "" {
+			return clientintf.KindErrorf(clientintf.ErrRateLimited,
+				"rate limited (retry after %s seconds)", secs)
+		}
+		return clientintf.ErrRateLimited
+	default:
+		return fmt.Errorf("resource request failed with status %s",
+			res.Status)
+	}
+}
+
+// SignResourceReply signs the reply to a request for the given path with the
+// identity key of the local client, so that the requesting user can verify the
+// reply was not altered after being created. The signature is set in the
+// reply meta, so this must be called after the data and content type of the
+// reply are final.
+func (c *Client) SignResourceReply(path []string, reply *rpc.RMFetchResourceReply) {
+	sig := c.id.SignMessage(rpc.ResourceReplySignMsg(path, reply))
+	if reply.Meta == nil {
+		reply.Meta = make(map[string]string, 1)
+	}
+	reply.Meta[rpc.ResourceMetaSignature] = hex.EncodeToString(sig[:])
+}
+
+// verifyResourceReply verifies the signature (if any) of a reply to a request
+// for the given path, sent by the user with the given identity.
+func verifyResourceReply(id *zkidentity.PublicIdentity, path []string,
+	reply *rpc.RMFetchResourceReply) clientdb.ResourceSignatureStatus {
+
+	sigHex, ok := reply.Meta[rpc.ResourceMetaSignature]
+	if !ok {
+		return clientdb.ResourceUnsigned
+	}
+	var sig [64]byte
+	if len(sigHex) != hex.EncodedLen(len(sig)) {
+		return clientdb.ResourceSignatureInvalid
+	}
+	if _, err := hex.Decode(sig[:], []byte(sigHex)); err != nil {
+		return clientdb.ResourceSignatureInvalid
+	}
+	if !id.VerifyMessage(rpc.ResourceReplySignMsg(path, reply), sig) {
+		return clientdb.ResourceSignatureInvalid
+	}
+	return clientdb.ResourceSignatureValid
+}
+
+// SignOrderSummary adds the summary of an order placed in a store to the
+// reply, signed with the identity key of the local client. This allows the
+// buyer to verify the payment details of the order shown in the reply were
+// created by the local client.
+func (c *Client) SignOrderSummary(summary *rpc.OrderSummary, reply *rpc.RMFetchResourceReply) error {
+	summaryJSON, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	sig := c.id.SignMessage(rpc.OrderSummarySignMsg(string(summaryJSON)))
+	if reply.Meta == nil {
+		reply.Meta = make(map[string]string, 2)
+	}
+	reply.Meta[rpc.ResourceMetaOrderSummary] = string(summaryJSON)
+	reply.Meta[rpc.ResourceMetaOrderSummarySig] = hex.EncodeToString(sig[:])
+	return nil
+}
+
+// verifyOrderSummary verifies the order summary (if any) included in a reply
+// sent by the user with the given identity. The summary is only valid if it
+// was signed by the user, is for an order of the local client and its invoice
+// is the one included in the reply data. The summary is returned only if it
+// is valid.
+func verifyOrderSummary(id *zkidentity.PublicIdentity, localID UserID,
+	reply *rpc.RMFetchResourceReply) (*rpc.OrderSummary, clientdb.ResourceSignatureStatus) {
+
+	summaryJSON, ok := reply.Meta[rpc.ResourceMetaOrderSummary]
+	if !ok {
+		return nil, clientdb.ResourceUnsigned
+	}
+	var sig [64]byte
+	sigHex := reply.Meta[rpc.ResourceMetaOrderSummarySig]
+	if len(sigHex) != hex.EncodedLen(len(sig)) {
+		return nil, clientdb.ResourceSignatureInvalid
+	}
+	if _, err := hex.Decode(sig[:], []byte(sigHex)); err != nil {
+		return nil, clientdb.ResourceSignatureInvalid
+	}
+	if !id.VerifyMessage(rpc.OrderSummarySignMsg(summaryJSON), sig) {
+		return nil, clientdb.ResourceSignatureInvalid
+	}
+
+	var summary rpc.OrderSummary
+	if err := json.Unmarshal([]byte(summaryJSON), &summary); err != nil {
+		return nil, clientdb.ResourceSignatureInvalid
+	}
+	if summary.Buyer != localID {
+		return nil, clientdb.ResourceSignatureInvalid
+	}
+	if summary.Invoice != "" && !bytes.Contains(reply.Data, []byte(summary.Invoice)) {
+		return nil, clientdb.ResourceSignatureInvalid
+	}
+
+	// Every LN invoice linked in the reply must be the one in the summary,
+	// otherwise a swapped invoice could be shown next to the right one.
+	for _, inv := range lnpayLinks(reply.Data) {
+		if inv != summary.Invoice {
+			return nil, clientdb.ResourceSignatureInvalid
+		}
+	}
+	return &summary, clientdb.ResourceSignatureValid
+}
+
+// lnpayLinks returns the LN invoices in the "lnpay://" links of the data.
+func lnpayLinks(data []byte) []string {
+	const prefix = "lnpay://"
+	var invoices []string
+	for {
+		i := bytes.Index(data, []byte(prefix))
+		if i < 0 {
+			return invoices
+		}
+		data = data[i+len(prefix):]
+		end := 0
+		for end < len(data) && ((data[end] >= 'a' && data[end] <= 'z') ||
+			(data[end] >= 'A' && data[end] <= 'Z') ||
+			(data[end] >= '0' && data[end] <= '9')) {
+			end += 1
+		}
+		invoices = append(invoices, string(data[:end]))
+		data = data[end:]
+	}
 }
 
 // handleFetchResourceReply handles the reply to a requested resource.
 func (c *Client) handleFetchResourceReply(ru *RemoteUser, frr rpc.RMFetchResourceReply) error {
-	// TODO: support chunked response.
-	if frr.Index != frr.Count || frr.Index != 0 {
-		return fmt.Errorf("chunked resource reply not implemented")
+	if frr.Count != 0 {
+		// Chunked reply. Only buffer chunks of replies to outstanding
+		// requests.
+		if !c.resChunks.isPending(ru.ID(), frr.Tag) {
+			var hasReq bool
+			err := c.dbView(func(tx clientdb.ReadTx) error {
+				var err error
+				hasReq, err = c.db.HasResourceRequest(tx, ru.ID(), frr.Tag)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if !hasReq {
+				return fmt.Errorf("received chunk of resource reply "+
+					"tag %s without outstanding request", frr.Tag)
+			}
+		}
+
+		res, chunks, size, err := c.resChunks.add(ru.ID(), &frr, time.Now())
+		if err != nil {
+			return err
+		}
+		ru.log.Debugf("Received resource reply tag %s chunk %d/%d "+
+			"(%d bytes so far)", frr.Tag, frr.Index+1, frr.Count, size)
+		c.ntfns.notifyResourceFetchProgress(ru, frr.Tag, chunks,
+			int(frr.Count), size)
+		if res == nil {
+			return nil
+		}
+		frr = *res
 	}
 
 	var req rpc.RMFetchResource
 	var fr clientdb.FetchedResource
 	var sess clientdb.PageSessionOverview
 	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
-		var err error
-		fr, sess, err = c.db.StoreFetchedResource(tx, ru.ID(), frr.Tag, frr)
+		rr, err := c.db.ReadResourceRequest(tx, ru.ID(), frr.Tag)
+		if err != nil {
+			return err
+		}
+		req = rr.Request
+		verif := clientdb.ResourceVerification{
+			SignatureStatus: verifyResourceReply(ru.id, req.Path, &frr),
+		}
+		verif.OrderSummary, verif.OrderSummaryStatus = verifyOrderSummary(
+			ru.id, c.PublicID(), &frr)
+		fr, sess, err = c.db.StoreFetchedResource(tx, ru.ID(), frr.Tag,
+			frr, verif)
 		return err
 	})
 	if err != nil {
 		return err
 	}
+	c.cfg.Metrics.ObserveDuration(metrics.ResourceFetchSeconds,
+		fr.ResponseTS.Sub(fr.RequestTS))
+	if fr.SignatureStatus == clientdb.ResourceSignatureInvalid {
+		ru.log.Warnf("Resource reply tag %s path %s has an invalid signature",
+			frr.Tag, strescape.ResourcesPath(req.Path))
+	}
+	if fr.OrderSummaryStatus == clientdb.ResourceSignatureInvalid {
+		ru.log.Warnf("Resource reply tag %s path %s has an invalid order "+
+			"summary", frr.Tag, strescape.ResourcesPath(req.Path))
+	}
 
 	ru.log.Infof("Received resource reply %d tag %s path %s chunk %d/%d %d bytes",
 		frr.Status, frr.Tag, strescape.ResourcesPath(req.Path),
@@ -0,0 +1,307 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// TestResourceReplyChunks tests splitting and reassembling resource replies.
+func TestResourceReplyChunks(t *testing.T) {
+	t.Parallel()
+
+	rnd := testRand(t)
+	data := make([]byte, 1000)
+	rnd.Read(data)
+	res := &rpc.RMFetchResourceReply{
+		Tag:    10,
+		Status: rpc.ResourceStatusOk,
+		Meta:   map[string]string{"k": "v"},
+		Data:   data,
+	}
+
+	// Small replies are not split.
+	if chunks := splitResourceReply(res, len(data)); len(chunks) != 1 {
+		t.Fatalf("unexpected nb of chunks: got %d, want 1", len(chunks))
+	}
+
+	chunks := splitResourceReply(res, 300)
+	if len(chunks) != 4 {
+		t.Fatalf("unexpected nb of chunks: got %d, want 4", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if chunk.Index != uint32(i) || chunk.Count != 4 {
+			t.Fatalf("unexpected index %d/%d for chunk %d",
+				chunk.Index, chunk.Count, i)
+		}
+		if (i == 0) != (chunk.Meta != nil) {
+			t.Fatalf("unexpected meta in chunk %d", i)
+		}
+	}
+
+	// Reassemble out of order, with a duplicate chunk.
+	var uid UserID
+	rc := newResourceReplyChunks()
+	now := time.Now()
+	for _, i := range []int{2, 0, 2, 3} {
+		got, _, _, err := rc.add(uid, &chunks[i], now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil {
+			t.Fatalf("unexpected complete reply after chunk %d", i)
+		}
+	}
+	if !rc.isPending(uid, res.Tag) {
+		t.Fatalf("reply is not pending")
+	}
+	got, received, size, err := rc.add(uid, &chunks[1], now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil {
+		t.Fatalf("reply not complete")
+	}
+	if received != 4 || size != int64(len(data)) {
+		t.Fatalf("unexpected progress %d chunks, %d bytes", received, size)
+	}
+	if !bytes.Equal(got.Data, data) || got.Meta["k"] != "v" ||
+		got.Index != 0 || got.Count != 0 {
+		t.Fatalf("unexpected reassembled reply")
+	}
+	if rc.isPending(uid, res.Tag) {
+		t.Fatalf("reply still pending after completion")
+	}
+
+	// Invalid chunks.
+	invalid := []rpc.RMFetchResourceReply{
+		{Tag: 11, Index: 0, Count: maxResourceReplyChunks + 1},
+		{Tag: 11, Index: 2, Count: 2},
+	}
+	for i := range invalid {
+		if _, _, _, err := rc.add(uid, &invalid[i], now); err == nil {
+			t.Fatalf("expected error for invalid chunk %d", i)
+		}
+	}
+	if _, _, _, err := rc.add(uid, &rpc.RMFetchResourceReply{Tag: 12, Count: 2}, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := rc.add(uid, &rpc.RMFetchResourceReply{Tag: 12, Index: 1, Count: 3}, now); err == nil {
+		t.Fatalf("expected error for mismatched chunk count")
+	}
+
+	// Incomplete replies are dropped after the timeout.
+	later := now.Add(resourceReplyChunksTimeout + time.Second)
+	if _, _, _, err := rc.add(uid, &rpc.RMFetchResourceReply{Tag: 13, Count: 2}, later); err != nil {
+		t.Fatal(err)
+	}
+	if rc.isPending(uid, 12) {
+		t.Fatalf("expired reply still pending")
+	}
+}
+
+// TestResourceReplySignature tests signing and verifying resource replies.
+func TestResourceReplySignature(t *testing.T) {
+	t.Parallel()
+
+	rnd := testRand(t)
+	alice := testID(t, rnd, "alice")
+	bob := testID(t, rnd, "bob")
+	c := &Client{id: alice}
+	path := []string{"product", "sku1"}
+
+	newReply := func() *rpc.RMFetchResourceReply {
+		res := &rpc.RMFetchResourceReply{
+			Tag:    10,
+			Status: rpc.ResourceStatusOk,
+			Data:   []byte("price: 1.00 DCR"),
+		}
+		c.SignResourceReply(path, res)
+		return res
+	}
+
+	tests := []struct {
+		name   string
+		id     *zkidentity.PublicIdentity
+		path   []string
+		alter  func(res *rpc.RMFetchResourceReply)
+		status clientdb.ResourceSignatureStatus
+	}{{
+		name:   "valid",
+		id:     &alice.Public,
+		path:   path,
+		status: clientdb.ResourceSignatureValid,
+	}, {
+		name: "unsigned",
+		id:   &alice.Public,
+		path: path,
+		alter: func(res *rpc.RMFetchResourceReply) {
+			delete(res.Meta, rpc.ResourceMetaSignature)
+		},
+		status: clientdb.ResourceUnsigned,
+	}, {
+		name:   "wrong signer",
+		id:     &bob.Public,
+		path:   path,
+		status: clientdb.ResourceSignatureInvalid,
+	}, {
+		name:   "different path",
+		id:     &alice.Public,
+		path:   []string{"product", "sku2"},
+		status: clientdb.ResourceSignatureInvalid,
+	}, {
+		name: "altered data",
+		id:   &alice.Public,
+		path: path,
+		alter: func(res *rpc.RMFetchResourceReply) {
+			res.Data = []byte("price: 9.00 DCR")
+		},
+		status: clientdb.ResourceSignatureInvalid,
+	}, {
+		name: "altered content type",
+		id:   &alice.Public,
+		path: path,
+		alter: func(res *rpc.RMFetchResourceReply) {
+			res.Meta[rpc.ResourceMetaContentType] = "text/plain"
+		},
+		status: clientdb.ResourceSignatureInvalid,
+	}, {
+		name: "malformed signature",
+		id:   &alice.Public,
+		path: path,
+		alter: func(res *rpc.RMFetchResourceReply) {
+			res.Meta[rpc.ResourceMetaSignature] = "xx"
+		},
+		status: clientdb.ResourceSignatureInvalid,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res := newReply()
+			if tc.alter != nil {
+				tc.alter(res)
+			}
+			got := verifyResourceReply(tc.id, tc.path, res)
+			if got != tc.status {
+				t.Fatalf("unexpected status: got %q, want %q",
+					got, tc.status)
+			}
+		})
+	}
+}
+
+// TestOrderSummarySignature tests signing and verifying order summaries.
+func TestOrderSummarySignature(t *testing.T) {
+	t.Parallel()
+
+	rnd := testRand(t)
+	store := testID(t, rnd, "store")
+	buyer := testID(t, rnd, "buyer")
+	other := testID(t, rnd, "other")
+	c := &Client{id: store}
+	const invoice = "lnsdcr1testinvoice"
+
+	newReply := func() *rpc.RMFetchResourceReply {
+		res := &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusOk,
+			Data:   []byte("Order 1\nLN Invoice: lnpay://" + invoice + "\n"),
+		}
+		summary := &rpc.OrderSummary{
+			Buyer:      buyer.Public.Identity,
+			OrderID:    "00000001",
+			TotalAtoms: 1e8,
+			PayType:    "ln",
+			Invoice:    invoice,
+		}
+		if err := c.SignOrderSummary(summary, res); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name    string
+		signer  *zkidentity.PublicIdentity
+		localID UserID
+		alter   func(res *rpc.RMFetchResourceReply)
+		status  clientdb.ResourceSignatureStatus
+	}{{
+		name:    "valid",
+		signer:  &store.Public,
+		localID: buyer.Public.Identity,
+		status:  clientdb.ResourceSignatureValid,
+	}, {
+		name:    "no summary",
+		signer:  &store.Public,
+		localID: buyer.Public.Identity,
+		alter: func(res *rpc.RMFetchResourceReply) {
+			delete(res.Meta, rpc.ResourceMetaOrderSummary)
+		},
+		status: clientdb.ResourceUnsigned,
+	}, {
+		name:    "wrong signer",
+		signer:  &other.Public,
+		localID: buyer.Public.Identity,
+		status:  clientdb.ResourceSignatureInvalid,
+	}, {
+		name:    "other buyer",
+		signer:  &store.Public,
+		localID: other.Public.Identity,
+		status:  clientdb.ResourceSignatureInvalid,
+	}, {
+		name:    "swapped invoice",
+		signer:  &store.Public,
+		localID: buyer.Public.Identity,
+		alter: func(res *rpc.RMFetchResourceReply) {
+			res.Data = []byte("LN Invoice: lnpay://lnsdcr1swapped\n")
+		},
+		status: clientdb.ResourceSignatureInvalid,
+	}, {
+		name:    "additional invoice",
+		signer:  &store.Public,
+		localID: buyer.Public.Identity,
+		alter: func(res *rpc.RMFetchResourceReply) {
+			res.Data = append(res.Data, []byte("Pay here: lnpay://lnsdcr1swapped\n")...)
+		},
+		status: clientdb.ResourceSignatureInvalid,
+	}, {
+		name:    "altered summary",
+		signer:  &store.Public,
+		localID: buyer.Public.Identity,
+		alter: func(res *rpc.RMFetchResourceReply) {
+			res.Meta[rpc.ResourceMetaOrderSummary] = strings.Replace(
+				res.Meta[rpc.ResourceMetaOrderSummary], invoice,
+				"lnsdcr1swapped", 1)
+			res.Data = []byte("LN Invoice: lnpay://lnsdcr1swapped\n")
+		},
+		status: clientdb.ResourceSignatureInvalid,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res := newReply()
+			if tc.alter != nil {
+				tc.alter(res)
+			}
+			summary, status := verifyOrderSummary(tc.signer, tc.localID, res)
+			if status != tc.status {
+				t.Fatalf("unexpected status: got %q, want %q",
+					status, tc.status)
+			}
+			if (summary != nil) != (status == clientdb.ResourceSignatureValid) {
+				t.Fatalf("unexpected summary %v for status %q",
+					summary, status)
+			}
+			if summary != nil && summary.Invoice != invoice {
+				t.Fatalf("unexpected invoice %q", summary.Invoice)
+			}
+		})
+	}
+}
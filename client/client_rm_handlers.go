@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
 	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/companyzero/bisonrelay/sw"
 	"github.com/companyzero/bisonrelay/zkidentity"
@@ -132,20 +132,7 @@ func (c *Client) innerHandleUserRM(ru *RemoteUser, h *rpc.RMHeader,
 			ru.log.Tracef("Ignoring received PM")
 			return nil
 		}
-
-		if filter, _ := c.FilterPM(ru.ID(), p.Message); filter {
-			return nil
-		}
-
-		err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
-			return c.db.LogPM(tx, ru.ID(), false, ru.Nick(), p.Message, ts)
-		})
-		if err != nil {
-			return err
-		}
-		ru.log.Debugf("Received private message of length %d", len(p.Message))
-
-		c.ntfns.notifyOnPM(ru, p, ts)
+		return c.handlePM(ru, p, ts)
 
 	case rpc.RMHandshakeSYN, rpc.RMHandshakeACK, rpc.RMHandshakeSYNACK:
 		return c.handleRMHandshake(ru, p)
@@ -265,25 +252,39 @@ func (c *Client) innerHandleUserRM(ru *RemoteUser, h *rpc.RMHeader,
 	case rpc.RMKXSuggestion:
 		return c.handleKXSuggestion(ru, p)
 
+	case rpc.RMPaymentProof:
+		return c.handlePaymentProof(ru, p)
+
 	case rpc.RMFetchResource:
 		return c.handleFetchResource(ru, p)
 
 	case rpc.RMFetchResourceReply:
 		return c.handleFetchResourceReply(ru, p)
 
+	case rpc.RMFeatures:
+		return c.handleFeatures(ru, p)
+
 	default:
 		return fmt.Errorf("Received unknown command %q payload %T",
 			h.Command, p)
 	}
-
-	return nil
 }
 
 // handleUserRM is the main handler for remote user RoutedMessages. It decides
 // what to do with the given RM from the given user.
 func (c *Client) handleUserRM(ru *RemoteUser, h *rpc.RMHeader, p interface{}, ts time.Time) {
 	ru.log.Tracef("Starting to handle %T", p)
+	c.cfg.Metrics.Inc(metrics.RMsReceived)
 	c.gcmq.RMReceived(ru.ID(), ts)
+	c.checkUserCompat(ru, h)
+	c.checkUserClock(ru, h, ts)
+
+	// Received RMs are timestamped with the server clock, while the RMs
+	// sent by the local client are timestamped with the local clock, so
+	// normalize the timestamp to keep them ordered when displayed.
+	ts = c.clockSkews.toLocal(ts)
+	c.maybeRequestFeatures(ru, h)
+	c.recordPeerReply(ru, p, ts)
 	err := c.innerHandleUserRM(ru, h, p, ts)
 	if err != nil {
 		if ru.log.Level() <= slog.LevelDebug {
@@ -0,0 +1,304 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+const (
+	// SavedQueryMessages is the kind of saved queries over the logged
+	// PMs and GC messages. See parseMessagesQuery for the fields of its
+	// terms.
+	SavedQueryMessages = "messages"
+
+	// SavedQueryOrders is the kind of saved queries over the orders of
+	// the simple store, which are run by the store once it is registered
+	// with RegisterQueryRunner.
+	SavedQueryOrders = "orders"
+)
+
+// SavedQuery is a named query over the local data of the client.
+type SavedQuery = clientdb.SavedQuery
+
+// SavedQueryResult is an item (message, order, etc) found by a saved query.
+type SavedQueryResult struct {
+	Timestamp time.Time
+
+	// Source identifies where the item was found (for example, the user
+	// or GC of a message or the ID of an order).
+	Source string
+
+	// Text is the text of the item.
+	Text string
+}
+
+// SavedQueryRunner runs saved queries of a kind.
+type SavedQueryRunner interface {
+	// ValidateQuery returns an error if the terms are not a valid query.
+	ValidateQuery(terms []string) error
+
+	// RunQuery returns the items that match the query, from the most
+	// recent one.
+	RunQuery(terms []string) ([]SavedQueryResult, error)
+}
+
+// RegisterQueryRunner registers the runner of the saved queries of the given
+// kind.
+func (c *Client) RegisterQueryRunner(kind string, runner SavedQueryRunner) {
+	c.queryRunnersMtx.Lock()
+	if c.queryRunners == nil {
+		c.queryRunners = make(map[string]SavedQueryRunner)
+	}
+	c.queryRunners[kind] = runner
+	c.queryRunnersMtx.Unlock()
+}
+
+// queryRunner returns the runner of the saved queries of the kind.
+func (c *Client) queryRunner(kind string) (SavedQueryRunner, error) {
+	if kind == SavedQueryMessages {
+		return messagesQueryRunner{c: c}, nil
+	}
+	c.queryRunnersMtx.Lock()
+	runner := c.queryRunners[kind]
+	c.queryRunnersMtx.Unlock()
+	if runner == nil {
+		return nil, fmt.Errorf("no runner for queries of kind %q", kind)
+	}
+	return runner, nil
+}
+
+// SaveQuery validates and saves a named query, replacing any existing query
+// with the same name.
+func (c *Client) SaveQuery(name, kind string, terms []string) error {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return fmt.Errorf("invalid query name %q", name)
+	}
+	runner, err := c.queryRunner(kind)
+	if err != nil {
+		return err
+	}
+	if err := runner.ValidateQuery(terms); err != nil {
+		return err
+	}
+	q := &SavedQuery{
+		Name:    name,
+		Kind:    kind,
+		Terms:   terms,
+		Created: time.Now(),
+	}
+	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.StoreSavedQuery(tx, q)
+	})
+}
+
+// SavedQueries returns the saved queries, sorted by name.
+func (c *Client) SavedQueries() ([]SavedQuery, error) {
+	var res []SavedQuery
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		res, err = c.db.ListSavedQueries(tx)
+		return err
+	})
+	return res, err
+}
+
+// RemoveSavedQuery removes the saved query with the given name.
+func (c *Client) RemoveSavedQuery(name string) error {
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.RemoveSavedQuery(tx, name)
+	})
+	if errors.Is(err, clientdb.ErrNotFound) {
+		return fmt.Errorf("saved query %q not found", name)
+	}
+	return err
+}
+
+// RunSavedQuery runs the saved query with the given name and returns the items
+// that match it, from the most recent one.
+func (c *Client) RunSavedQuery(name string) ([]SavedQueryResult, error) {
+	var q *SavedQuery
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		q, err = c.db.GetSavedQuery(tx, name)
+		return err
+	})
+	if errors.Is(err, clientdb.ErrNotFound) {
+		return nil, fmt.Errorf("saved query %q not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	runner, err := c.queryRunner(q.Kind)
+	if err != nil {
+		return nil, err
+	}
+	return runner.RunQuery(q.Terms)
+}
+
+// messagesQuery is a query over the logged messages.
+type messagesQuery struct {
+	from     string
+	gc       string
+	contains string
+	since    time.Time
+}
+
+// parseMessagesQuery parses the terms of a query over the logged messages.
+// The terms are in the <field>=<value> format, with the following fields:
+//
+//   - from: the nick or ID of the user that sent the messages.
+//   - gc: the name or ID of the GC where the messages were sent. Without it,
+//     the PMs are queried.
+//   - contains: text contained in the messages (case insensitive).
+//   - days: the number of days before the query is run when the messages
+//     were sent.
+func parseMessagesQuery(terms []string, now time.Time) (messagesQuery, error) {
+	var q messagesQuery
+	for _, term := range terms {
+		field, value, ok := strings.Cut(term, "=")
+		if !ok || value == "" {
+			return q, fmt.Errorf("term %q not in the format "+
+				"<field>=<value>", term)
+		}
+		switch field {
+		case "from":
+			q.from = value
+		case "gc":
+			q.gc = value
+		case "contains":
+			q.contains = strings.ToLower(value)
+		case "days":
+			days, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return q, fmt.Errorf("invalid days %q: %v", value, err)
+			}
+			q.since = now.AddDate(0, 0, -int(days))
+		default:
+			return q, fmt.Errorf("unknown query field %q", field)
+		}
+	}
+	return q, nil
+}
+
+// matches returns true if the logged message matches the query. fromNick is
+// the nick of the user of the from field, if the query has one.
+func (q *messagesQuery) matches(entry *clientdb.PMLogEntry, fromNick string) bool {
+	switch {
+	case entry.Internal:
+		return false
+	case fromNick != "" && !strings.EqualFold(entry.From, fromNick):
+		return false
+	case q.contains != "" && !strings.Contains(strings.ToLower(entry.Message), q.contains):
+		return false
+	case time.Unix(entry.Timestamp, 0).Before(q.since):
+		return false
+	}
+	return true
+}
+
+// messagesQueryRunner runs the saved queries over the logged messages.
+type messagesQueryRunner struct {
+	c *Client
+}
+
+func (r messagesQueryRunner) ValidateQuery(terms []string) error {
+	_, err := parseMessagesQuery(terms, time.Now())
+	return err
+}
+
+func (r messagesQueryRunner) RunQuery(terms []string) ([]SavedQueryResult, error) {
+	c := r.c
+	q, err := parseMessagesQuery(terms, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the user and GC of the query.
+	var fromNick string
+	var fromUID *UserID
+	if q.from != "" {
+		var uid UserID
+		if err := uid.FromString(q.from); err != nil {
+			uid, err = c.UIDByNick(q.from)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ru, err := c.UserByID(uid)
+		if err != nil {
+			return nil, err
+		}
+		fromNick, fromUID = ru.Nick(), &uid
+	}
+	var gcID zkidentity.ShortID
+	var gcAlias string
+	if q.gc != "" {
+		if gcID, err = c.GCIDByName(q.gc); err != nil {
+			return nil, err
+		}
+		if gcAlias, err = c.GetGCAlias(gcID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Read the logs to search. Without a GC, the PMs with the user (or
+	// with every user) are searched.
+	var res []SavedQueryResult
+	addMatches := func(source string, entries []clientdb.PMLogEntry) {
+		for i := range entries {
+			if !q.matches(&entries[i], fromNick) {
+				continue
+			}
+			res = append(res, SavedQueryResult{
+				Timestamp: time.Unix(entries[i].Timestamp, 0),
+				Source:    source,
+				Text:      fmt.Sprintf("<%s> %s", entries[i].From, entries[i].Message),
+			})
+		}
+	}
+	err = c.dbView(func(tx clientdb.ReadTx) error {
+		if q.gc != "" {
+			entries, err := c.db.ReadLogGCMsg(tx, gcAlias, gcID, math.MaxInt32, 0)
+			if err != nil {
+				return err
+			}
+			addMatches("gc "+gcAlias, entries)
+			return nil
+		}
+
+		uids := c.rul.userList()
+		if fromUID != nil {
+			uids = []UserID{*fromUID}
+		}
+		for _, uid := range uids {
+			entries, err := c.db.ReadLogPM(tx, uid, math.MaxInt32, 0)
+			if errors.Is(err, clientdb.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			nick, _ := c.UserNick(uid)
+			addMatches("pm "+nick, entries)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Timestamp.After(res[j].Timestamp)
+	})
+	return res, nil
+}
+
+var _ SavedQueryRunner = messagesQueryRunner{}
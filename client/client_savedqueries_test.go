@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/decred/slog"
+)
+
+// TestMessagesQuery tests parsing and matching queries over the logged
+// messages.
+func TestMessagesQuery(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	recent := now.Add(-24 * time.Hour).Unix()
+	old := now.AddDate(0, 0, -60).Unix()
+
+	tests := []struct {
+		name     string
+		terms    []string
+		fromNick string
+		entry    clientdb.PMLogEntry
+		wantErr  bool
+		want     bool
+	}{{
+		name:  "no terms",
+		entry: clientdb.PMLogEntry{Message: "hello", From: "bob", Timestamp: old},
+		want:  true,
+	}, {
+		name:  "contains",
+		terms: []string{"contains=invoice"},
+		entry: clientdb.PMLogEntry{Message: "Your Invoice is ready", From: "bob", Timestamp: recent},
+		want:  true,
+	}, {
+		name:  "does not contain",
+		terms: []string{"contains=invoice"},
+		entry: clientdb.PMLogEntry{Message: "hello", From: "bob", Timestamp: recent},
+		want:  false,
+	}, {
+		name:     "from other user",
+		terms:    []string{"from=bob"},
+		fromNick: "bob",
+		entry:    clientdb.PMLogEntry{Message: "hello", From: "alice", Timestamp: recent},
+		want:     false,
+	}, {
+		name:     "from user in recent days",
+		terms:    []string{"from=bob", "days=30"},
+		fromNick: "bob",
+		entry:    clientdb.PMLogEntry{Message: "hello", From: "bob", Timestamp: recent},
+		want:     true,
+	}, {
+		name:  "older than days",
+		terms: []string{"days=30"},
+		entry: clientdb.PMLogEntry{Message: "hello", From: "bob", Timestamp: old},
+		want:  false,
+	}, {
+		name:  "internal messages",
+		entry: clientdb.PMLogEntry{Message: "hello", From: "bob", Timestamp: recent, Internal: true},
+		want:  false,
+	}, {
+		name:    "unknown field",
+		terms:   []string{"subject=hello"},
+		wantErr: true,
+	}, {
+		name:    "missing value",
+		terms:   []string{"from="},
+		wantErr: true,
+	}, {
+		name:    "invalid days",
+		terms:   []string{"days=-1"},
+		wantErr: true,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := parseMessagesQuery(tc.terms, now)
+			if tc.wantErr {
+				assert.NonNilErr(t, err)
+				return
+			}
+			assert.NilErr(t, err)
+			assert.BoolIs(t, q.matches(&tc.entry, tc.fromNick), tc.want)
+		})
+	}
+}
+
+// testQueryRunner is a SavedQueryRunner that returns its terms as results.
+type testQueryRunner struct{}
+
+func (testQueryRunner) ValidateQuery(terms []string) error {
+	if len(terms) == 0 {
+		return errors.New("no terms")
+	}
+	return nil
+}
+
+func (testQueryRunner) RunQuery(terms []string) ([]SavedQueryResult, error) {
+	return []SavedQueryResult{{Text: strings.Join(terms, " ")}}, nil
+}
+
+// TestSavedQueries tests saving, running and removing saved queries.
+func TestSavedQueries(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := testDB(t, nil, nil)
+	runTestDB(t, db)
+	c := &Client{
+		db:    db,
+		dbCtx: ctx,
+		log:   slog.Disabled,
+	}
+
+	// Queries of kinds without a runner and invalid queries are not
+	// saved.
+	assert.NonNilErr(t, c.SaveQuery("unpaid", SavedQueryOrders, []string{"status=placed"}))
+	c.RegisterQueryRunner(SavedQueryOrders, testQueryRunner{})
+	assert.NonNilErr(t, c.SaveQuery("unpaid", SavedQueryOrders, nil))
+	assert.NonNilErr(t, c.SaveQuery("bad name", SavedQueryOrders, []string{"status=placed"}))
+	assert.NonNilErr(t, c.SaveQuery("invoices", SavedQueryMessages, []string{"subject=x"}))
+
+	orFatal(t, c.SaveQuery("unpaid", SavedQueryOrders, []string{"status=placed"}))
+	orFatal(t, c.SaveQuery("invoices", SavedQueryMessages, []string{"contains=invoice"}))
+	queries, err := c.SavedQueries()
+	orFatal(t, err)
+	assert.DeepEqual(t, len(queries), 2)
+	assert.DeepEqual(t, queries[0].Name, "invoices")
+	assert.DeepEqual(t, queries[1].Name, "unpaid")
+
+	// Saving a query with an existing name replaces it.
+	orFatal(t, c.SaveQuery("unpaid", SavedQueryOrders, []string{"status=placed", "days=30"}))
+	res, err := c.RunSavedQuery("unpaid")
+	orFatal(t, err)
+	assert.DeepEqual(t, res, []SavedQueryResult{{Text: "status=placed days=30"}})
+
+	orFatal(t, c.RemoveSavedQuery("unpaid"))
+	assert.NonNilErr(t, c.RemoveSavedQuery("unpaid"))
+	_, err = c.RunSavedQuery("unpaid")
+	assert.NonNilErr(t, err)
+	queries, err = c.SavedQueries()
+	orFatal(t, err)
+	assert.DeepEqual(t, len(queries), 1)
+}
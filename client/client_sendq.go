@@ -107,6 +107,7 @@ func (c *Client) sendWithSendQPriority(typ string, msg interface{}, priority uin
 				failed(err)
 			} else {
 				c.removeFromSendQ(sqid, uid)
+				c.recordPeerRequest(ru, msg)
 			}
 		}()
 	}
@@ -119,9 +120,159 @@ func (c *Client) sendWithSendQ(typ string, msg interface{}, dests ...clientintf.
 	return c.sendWithSendQPriority(typ, msg, priorityDefault, dests...)
 }
 
+// sendBatchMsg is a msg of a send batch.
+type sendBatchMsg struct {
+	typ   string
+	msg   interface{}
+	dests []clientintf.UserID
+}
+
+// sendBatch is a group of related msgs (for example, a post, the metadata of
+// its attachments and a notification to a GC) that are sent as a unit. The
+// msgs of a batch are added to the send queue atomically and are sent in
+// order to each destination. When one msg of the batch cannot be sent to a
+// destination, the remaining msgs of the batch are not sent to it, so that
+// the destination does not receive a partial batch out of order.
+type sendBatch struct {
+	priority uint
+	msgs     []sendBatchMsg
+}
+
+// newSendBatch creates a new, empty send batch with default priority.
+func newSendBatch() *sendBatch {
+	return &sendBatch{priority: priorityDefault}
+}
+
+// add adds a msg to be sent to the given destinations to the batch.
+func (b *sendBatch) add(typ string, msg interface{}, dests ...clientintf.UserID) {
+	b.msgs = append(b.msgs, sendBatchMsg{typ: typ, msg: msg, dests: dests})
+}
+
+// sendBatchWithSendQ adds the msgs of the batch to the DB send queue and sends
+// them to their destinations. Either all msgs of the batch are enqueued or an
+// error is returned and none of them are. As with sendWithSendQ, each sending
+// is done asynchronously.
+func (c *Client) sendBatchWithSendQ(batch *sendBatch) error {
+	if len(batch.msgs) == 0 {
+		return nil
+	}
+	if batch.priority > 4 {
+		return fmt.Errorf("priority must be max 4")
+	}
+
+	// Compose all msgs before storing any of them, so that an invalid
+	// msg fails the entire batch.
+	els := make([]clientdb.SendQueueElement, len(batch.msgs))
+	for i, bm := range batch.msgs {
+		blob, err := rpc.ComposeCompressedRM(c.id, bm.msg, c.cfg.CompressLevel)
+		if err != nil {
+			return fmt.Errorf("unable to compose batch msg %d (%T): %v",
+				i, bm.msg, err)
+		}
+		if rpc.EstimateRoutedRMWireSize(len(blob)) > rpc.MaxMsgSize {
+			return fmt.Errorf("cannot enqueue batch msg %d (%T) "+
+				"estimated as larger than max message size %d > %d: %w",
+				i, bm.msg, rpc.EstimateRoutedRMWireSize(len(blob)),
+				rpc.MaxMsgSize, errRMTooLarge)
+		}
+		els[i] = clientdb.SendQueueElement{
+			Type:     bm.typ,
+			Dests:    bm.dests,
+			Msg:      blob,
+			Priority: batch.priority,
+		}
+	}
+
+	var batchID clientdb.SendQID
+	var ids []clientdb.SendQID
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		var err error
+		batchID, ids, err = c.db.AddBatchToSendQueue(tx, els)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	c.log.Tracef("Added batch %s with %d msgs to sendq", batchID, len(ids))
+
+	// List the msgs of the batch to send to each destination, in order.
+	var dests []clientintf.UserID
+	destMsgs := make(map[clientintf.UserID][]int)
+	for i, bm := range batch.msgs {
+		for _, dest := range bm.dests {
+			if _, ok := destMsgs[dest]; !ok {
+				dests = append(dests, dest)
+			}
+			destMsgs[dest] = append(destMsgs[dest], i)
+		}
+	}
+
+	for _, dest := range dests {
+		uid := dest
+		msgIdxs := destMsgs[uid]
+
+		// abandon removes the destination from the msgs of the batch,
+		// starting at the given msg.
+		abandon := func(from int, err error) {
+			if errors.Is(err, clientintf.ErrSubsysExiting) {
+				// Keep the rest of the batch to send on restart.
+				return
+			}
+			c.log.Errorf("Unable to send batch %s msg %d to %s: %v",
+				batchID, msgIdxs[from], uid, err)
+			for _, idx := range msgIdxs[from:] {
+				c.removeFromSendQ(ids[idx], uid)
+			}
+		}
+
+		ru, err := c.UserByID(uid)
+		if err != nil {
+			abandon(0, err)
+			continue
+		}
+
+		// Queue synchronously to ensure outbound order.
+		replyChans := make([]chan error, 0, len(msgIdxs))
+		for j, idx := range msgIdxs {
+			bm := batch.msgs[idx]
+			replyChan := make(chan error)
+			err := ru.queueRMPriority(bm.msg, batch.priority, replyChan, bm.typ)
+			if err != nil {
+				abandon(j, err)
+				break
+			}
+			replyChans = append(replyChans, replyChan)
+		}
+
+		// Wait for replies asynchronously.
+		go func() {
+			failed := false
+			for j, replyChan := range replyChans {
+				err := <-replyChan
+				switch {
+				case failed:
+					// Already abandoned.
+				case err != nil:
+					failed = true
+					abandon(j, err)
+				default:
+					c.removeFromSendQ(ids[msgIdxs[j]], uid)
+				}
+			}
+		}()
+	}
+
+	return nil
+}
+
 // runSendQ sends outstanding msgs from the DB send queue.
 func (c *Client) runSendQ(ctx context.Context) error {
 	<-c.abLoaded
+	if c.lastShutdown != nil && c.lastShutdown.SendQueueLen == 0 {
+		c.log.Debugf("No queued messages to send after clean shutdown")
+		return nil
+	}
+
 	var sendq []clientdb.SendQueueElement
 	err := c.db.View(c.dbCtx, func(tx clientdb.ReadTx) error {
 		var err error
@@ -160,6 +311,44 @@ func (c *Client) runSendQ(ctx context.Context) error {
 		sendlist = sendlist[:len(sendlist)-1]
 	}
 
+	// isBatchMate returns true if the j'th element is part of the same
+	// batch and has the same destination as the i'th element.
+	isBatchMate := func(j int) bool {
+		a, b := sendlist[i], sendlist[j]
+		return a.msg.Batch != nil && b.msg.Batch != nil &&
+			*a.msg.Batch == *b.msg.Batch && *a.uid == *b.uid
+	}
+
+	// blockedByBatch returns true if the current element is part of a
+	// batch that has an earlier msg still pending for the same
+	// destination.
+	blockedByBatch := func() bool {
+		for j := range sendlist {
+			if j != i && isBatchMate(j) &&
+				sendlist[j].msg.BatchIndex < sendlist[i].msg.BatchIndex {
+				return true
+			}
+		}
+		return false
+	}
+
+	// removeBatchRest removes the later msgs of the batch of the current
+	// element for the same destination.
+	removeBatchRest := func() {
+		for j := len(sendlist) - 1; j >= 0; j-- {
+			if j == i || !isBatchMate(j) ||
+				sendlist[j].msg.BatchIndex < sendlist[i].msg.BatchIndex {
+				continue
+			}
+			c.removeFromSendQ(sendlist[j].msg.ID, *sendlist[j].uid)
+			copy(sendlist[j:], sendlist[j+1:])
+			sendlist = sendlist[:len(sendlist)-1]
+			if j < i {
+				i--
+			}
+		}
+	}
+
 	const maxTries = 5 // Max attempts at sending same msg.
 
 	c.log.Infof("Starting to send %d queued messages", len(sendlist))
@@ -168,6 +357,16 @@ func (c *Client) runSendQ(ctx context.Context) error {
 		if canceled(ctx) {
 			return ctx.Err()
 		}
+		if i >= len(sendlist) {
+			i = 0
+		}
+
+		// Msgs of a batch are sent in order, so skip this one if an
+		// earlier msg of its batch is still pending.
+		if blockedByBatch() {
+			i = (i + 1) % len(sendlist)
+			continue
+		}
 
 		// Attempt to send the next message.
 		el := sendlist[i]
@@ -193,6 +392,9 @@ func (c *Client) runSendQ(ctx context.Context) error {
 			ru.log.Errorf("Unable to send RM from sendq: %v", err)
 			sendlist[i].tries += 1
 			if sendlist[i].tries >= maxTries {
+				// The rest of the batch (if any) is not sent
+				// to this destination.
+				removeBatchRest()
 				removeCurrent()
 			} else {
 				i = (i + 1) % len(sendlist)
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+)
+
+// outboundQueuesLen returns the number of outbound messages that are still
+// queued to be sent, either in the RMQ or in the DB send queue.
+func (c *Client) outboundQueuesLen() (int, error) {
+	var sendqLen int
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		sendq, err := c.db.ListSendQueue(tx)
+		sendqLen = len(sendq)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	ql, sl := c.q.Len()
+	return ql + sl + sendqLen, nil
+}
+
+// Shutdown performs the first stage of a graceful shutdown of the client. It
+// waits until the outbound message queues are flushed (or until the passed
+// context is done) and flags the client such that, once Run() returns, its
+// state is checkpointed and a clean shutdown marker is recorded in the DB.
+//
+// The context passed to Run() should be canceled after Shutdown returns.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.log.Infof("Starting graceful shutdown")
+
+	const checkInterval = 250 * time.Millisecond
+	for {
+		n, err := c.outboundQueuesLen()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			c.log.Debugf("Outbound queues flushed")
+			break
+		}
+
+		c.log.Debugf("Waiting for %d outbound messages to be sent "+
+			"before shutting down", n)
+		select {
+		case <-time.After(checkInterval):
+		case <-ctx.Done():
+			c.log.Warnf("Shutting down with %d unsent outbound "+
+				"messages", n)
+			c.setCleanShutdown()
+			return nil
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		}
+	}
+
+	c.setCleanShutdown()
+	return nil
+}
+
+func (c *Client) setCleanShutdown() {
+	c.shutdownMtx.Lock()
+	c.cleanShutdown = true
+	c.shutdownMtx.Unlock()
+}
+
+// recordCleanShutdown checkpoints the state of the client and records the
+// clean shutdown marker if Shutdown() was called. This is called after all
+// client subsystems have stopped, but before the DB is closed.
+func (c *Client) recordCleanShutdown() {
+	c.shutdownMtx.Lock()
+	clean := c.cleanShutdown
+	c.shutdownMtx.Unlock()
+	if !clean {
+		return
+	}
+
+	marker := &clientdb.CleanShutdownMarker{Timestamp: time.Now()}
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		var err error
+		marker.UnsentRMs, err = c.db.CountUnackedUserRMs(tx)
+		if err != nil {
+			return err
+		}
+		sendq, err := c.db.ListSendQueue(tx)
+		if err != nil {
+			return err
+		}
+		marker.SendQueueLen = len(sendq)
+		fds, err := c.db.ListOutstandingDownloads(tx)
+		if err != nil {
+			return err
+		}
+		for _, fd := range fds {
+			marker.OutstandingDownloads = append(marker.OutstandingDownloads,
+				clientdb.DownloadRef{UID: fd.UID, FID: fd.FID})
+		}
+		return c.db.MarkCleanShutdown(tx, marker)
+	})
+	if err != nil {
+		c.log.Errorf("Unable to record clean shutdown: %v", err)
+		return
+	}
+	c.log.Infof("Recorded clean shutdown (%d unsent RMs, %d queued msgs, "+
+		"%d outstanding downloads)", marker.UnsentRMs, marker.SendQueueLen,
+		len(marker.OutstandingDownloads))
+}
+
+// loadLastShutdown loads the marker recorded in the last clean shutdown of
+// the client.
+func (c *Client) loadLastShutdown(ctx context.Context) error {
+	var marker *clientdb.CleanShutdownMarker
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		var err error
+		marker, err = c.db.TakeCleanShutdownMarker(tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if marker == nil {
+		c.log.Infof("No clean shutdown marker found; previous " +
+			"shutdown was not clean")
+	} else {
+		c.log.Debugf("Previous shutdown was clean at %s",
+			marker.Timestamp.Format(time.RFC3339))
+	}
+	c.lastShutdown = marker
+	return nil
+}
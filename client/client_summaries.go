@@ -0,0 +1,290 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+const (
+	// DefaultSummaryMaxLen is the max length of summaries when one is not
+	// specified in the config.
+	DefaultSummaryMaxLen = 500
+
+	// DefaultSummaryTimeout is the timeout for producing each summary when
+	// one is not specified in the config.
+	DefaultSummaryTimeout = 2 * time.Minute
+
+	// maxGCBacklogSummaryMsgs is the max number of GC messages summarized
+	// in a single summary of a GC backlog.
+	maxGCBacklogSummaryMsgs = 200
+)
+
+// errNoSummarizer is returned when attempting to summarize content without a
+// configured summarizer.
+var errNoSummarizer = errors.New("summarizer not configured")
+
+// SummaryKind is the kind of content being summarized.
+type SummaryKind string
+
+const (
+	// SummaryKindPost is the summary of a post.
+	SummaryKindPost SummaryKind = "post"
+
+	// SummaryKindGCBacklog is the summary of the latest messages of a GC.
+	SummaryKindGCBacklog SummaryKind = "gcbacklog"
+)
+
+// SummaryRequest is a request to summarize content.
+type SummaryRequest struct {
+	Kind SummaryKind
+
+	// Title is the title of the content (the post title or the GC name).
+	Title string
+
+	// Content is the content to summarize. GC backlogs are formatted as
+	// one "<nick> message" line per message.
+	Content string
+
+	// MaxLen is the max length of the summary, in bytes.
+	MaxLen int
+}
+
+// Summarizer is a provider of summaries of long content, such as a local LLM.
+type Summarizer interface {
+	// Name is the name of the provider, used in logs and stored with the
+	// summaries.
+	Name() string
+
+	// Summarize returns a short summary of the requested content.
+	Summarize(ctx context.Context, req SummaryRequest) (string, error)
+}
+
+// ContentSummaryEvent is the event of a summary of content being produced.
+type ContentSummaryEvent struct {
+	Kind    SummaryKind
+	Summary clientdb.ContentSummary
+
+	// UID and Post identify the post, when Kind is SummaryKindPost.
+	UID  UserID
+	Post clientintf.PostID
+
+	// GC and Messages identify the GC and the number of summarized
+	// messages, when Kind is SummaryKindGCBacklog.
+	GC       zkidentity.ShortID
+	Messages int
+}
+
+// summarize requests the configured summarizer to summarize the content.
+func (c *Client) summarize(kind SummaryKind, title, content string) (clientdb.ContentSummary, error) {
+	var summ clientdb.ContentSummary
+	if c.cfg.Summarizer == nil {
+		return summ, errNoSummarizer
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, c.cfg.SummaryTimeout)
+	defer cancel()
+	start := time.Now()
+	text, err := c.cfg.Summarizer.Summarize(ctx, SummaryRequest{
+		Kind:    kind,
+		Title:   title,
+		Content: content,
+		MaxLen:  c.cfg.SummaryMaxLen,
+	})
+	if err != nil {
+		return summ, fmt.Errorf("summarizer %s failed: %w",
+			c.cfg.Summarizer.Name(), err)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return summ, fmt.Errorf("summarizer %s returned an empty summary",
+			c.cfg.Summarizer.Name())
+	}
+	c.log.Debugf("Summarizer %s summarized %s (%d bytes) in %s",
+		c.cfg.Summarizer.Name(), kind, len(content), time.Since(start))
+
+	summ = clientdb.ContentSummary{
+		Summary:    text,
+		Summarizer: c.cfg.Summarizer.Name(),
+		Created:    time.Now(),
+		SourceLen:  len(content),
+	}
+	return summ, nil
+}
+
+// SummarizePost produces, stores and returns a summary of the given post,
+// replacing any existing summary of it.
+func (c *Client) SummarizePost(from UserID, pid clientintf.PostID) (*clientdb.ContentSummary, error) {
+	var pm rpc.PostMetadata
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		pm, err = c.db.ReadPost(tx, from, pid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summ, err := c.summarize(SummaryKindPost, clientintf.PostTitle(&pm),
+		pm.Attributes[rpc.RMPMain])
+	if err != nil {
+		return nil, err
+	}
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.StorePostSummary(tx, from, pid, &summ)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.ntfns.notifyContentSummarized(ContentSummaryEvent{
+		Kind:    SummaryKindPost,
+		Summary: summ,
+		UID:     from,
+		Post:    pid,
+	})
+	return &summ, nil
+}
+
+// PostSummary returns the stored summary of the given post.
+func (c *Client) PostSummary(from UserID, pid clientintf.PostID) (*clientdb.ContentSummary, error) {
+	var summ *clientdb.ContentSummary
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		summ, err = c.db.ReadPostSummary(tx, from, pid)
+		return err
+	})
+	return summ, err
+}
+
+// maybeSummarizeReceivedPost summarizes a received post if its content is
+// longer than the configured threshold.
+func (c *Client) maybeSummarizeReceivedPost(from UserID, pid clientintf.PostID,
+	pm *rpc.PostMetadata) {
+
+	if c.cfg.Summarizer == nil || c.cfg.SummarizePostsMinLen <= 0 {
+		return
+	}
+	if len(pm.Attributes[rpc.RMPMain]) < c.cfg.SummarizePostsMinLen {
+		return
+	}
+
+	go func() {
+		if _, err := c.SummarizePost(from, pid); err != nil {
+			c.log.Warnf("Unable to summarize post %s: %v", pid, err)
+		}
+	}()
+}
+
+// SummarizeGCBacklog produces, stores and returns a summary of the latest
+// messages (up to maxMsgs) logged in the given GC, replacing any previous
+// summary of the GC. If maxMsgs is <= 0, a default number of messages is
+// summarized.
+func (c *Client) SummarizeGCBacklog(gcID zkidentity.ShortID, maxMsgs int) (*clientdb.GCBacklogSummary, error) {
+	if maxMsgs <= 0 || maxMsgs > maxGCBacklogSummaryMsgs {
+		maxMsgs = maxGCBacklogSummaryMsgs
+	}
+
+	// The GC messages are logged under the local alias of the GC.
+	gcAlias, _ := c.GetGCAlias(gcID)
+	title := gcAlias
+	var msgs []clientdb.PMLogEntry
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		gc, err := c.db.GetGC(tx, gcID)
+		if err != nil {
+			return err
+		}
+		if title == "" {
+			title = gc.Name
+		}
+		msgs, err = c.db.ReadLogGCMsg(tx, gcAlias, gcID, maxMsgs, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("no logged messages in GC %s", gcID)
+	}
+
+	var b strings.Builder
+	for _, msg := range msgs {
+		fmt.Fprintf(&b, "<%s> %s\n", msg.From, msg.Message)
+	}
+	summ, err := c.summarize(SummaryKindGCBacklog, title, b.String())
+	if err != nil {
+		return nil, err
+	}
+	gcSumm := &clientdb.GCBacklogSummary{
+		ContentSummary: summ,
+		GC:             gcID,
+		Messages:       len(msgs),
+		Since:          time.Unix(msgs[0].Timestamp, 0),
+		Until:          time.Unix(msgs[len(msgs)-1].Timestamp, 0),
+	}
+	err = c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		return c.db.StoreGCBacklogSummary(tx, gcSumm)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.gcBacklogsMtx.Lock()
+	delete(c.gcBacklogs, gcID)
+	c.gcBacklogsMtx.Unlock()
+
+	c.ntfns.notifyContentSummarized(ContentSummaryEvent{
+		Kind:     SummaryKindGCBacklog,
+		Summary:  summ,
+		GC:       gcID,
+		Messages: len(msgs),
+	})
+	return gcSumm, nil
+}
+
+// GCBacklogSummary returns the latest summary of the backlog of the given GC.
+func (c *Client) GCBacklogSummary(gcID zkidentity.ShortID) (*clientdb.GCBacklogSummary, error) {
+	var summ *clientdb.GCBacklogSummary
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		summ, err = c.db.ReadGCBacklogSummary(tx, gcID)
+		return err
+	})
+	return summ, err
+}
+
+// trackGCBacklog tracks a message received in the given GC, summarizing the
+// GC backlog once the configured number of messages is received.
+func (c *Client) trackGCBacklog(gcID zkidentity.ShortID) {
+	if c.cfg.Summarizer == nil || c.cfg.SummarizeGCBacklogMsgs <= 0 {
+		return
+	}
+
+	c.gcBacklogsMtx.Lock()
+	c.gcBacklogs[gcID] += 1
+	n := c.gcBacklogs[gcID]
+	if n >= c.cfg.SummarizeGCBacklogMsgs {
+		// Reset the count, so that only one summary is attempted
+		// while the current one is produced.
+		c.gcBacklogs[gcID] = 0
+	}
+	c.gcBacklogsMtx.Unlock()
+	if n < c.cfg.SummarizeGCBacklogMsgs {
+		return
+	}
+
+	go func() {
+		_, err := c.SummarizeGCBacklog(gcID, n)
+		if err != nil {
+			c.log.Warnf("Unable to summarize backlog of GC %s: %v",
+				gcID, err)
+		}
+	}()
+}
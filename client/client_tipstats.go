@@ -0,0 +1,323 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// maxTipAckTippers and maxTipAckPosts are the max number of tippers and posts
+// listed in thank-you summaries of tips.
+const (
+	maxTipAckTippers = 10
+	maxTipAckPosts   = 5
+)
+
+// TipLeader is the aggregate of the tips received from a single user.
+type TipLeader struct {
+	UID  UserID
+	Nick string
+
+	// Public is set if the user consented to being named in
+	// acknowledgments of their tips (in any of the tips).
+	Public bool
+
+	MilliAtoms int64
+	Count      int
+}
+
+// PostTips is the aggregate of the tips received for a post.
+type PostTips struct {
+	Post       clientintf.PostID
+	Title      string
+	MilliAtoms int64
+	Count      int
+}
+
+// TipLeaderboard is the aggregate of the tips received in a period.
+type TipLeaderboard struct {
+	Since      time.Time
+	Until      time.Time
+	MilliAtoms int64
+	Count      int
+
+	// Tippers are the users that sent tips and Posts the posts that were
+	// tipped, sorted by total amount received.
+	Tippers []TipLeader
+	Posts   []PostTips
+}
+
+// recordReceivedTip records a tip received by the local client, if recording
+// tips is enabled.
+func (c *Client) recordReceivedTip(tx clientdb.ReadWriteTx, uid UserID,
+	mAtoms int64, post *clientintf.PostID, public bool) error {
+
+	cfg, err := c.db.GetTipStatsConfig(tx)
+	if err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+	tip := &clientdb.ReceivedTip{
+		UID:        uid,
+		MilliAtoms: mAtoms,
+		Timestamp:  time.Now(),
+		Post:       post,
+		Public:     public,
+	}
+	return c.db.RecordReceivedTip(tx, tip)
+}
+
+// SetTipStatsConfig sets the config for recording received tips and sending
+// thank-you summaries of them.
+func (c *Client) SetTipStatsConfig(cfg clientdb.TipStatsConfig) error {
+	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		for _, gcID := range cfg.AckGCs {
+			if _, err := c.db.GetGC(tx, gcID); err != nil {
+				return fmt.Errorf("unable to load GC %s: %w", gcID, err)
+			}
+		}
+		old, err := c.db.GetTipStatsConfig(tx)
+		if err != nil {
+			return err
+		}
+		if cfg.LastAck.IsZero() {
+			cfg.LastAck = old.LastAck
+		}
+		return c.db.SetTipStatsConfig(tx, &cfg)
+	})
+}
+
+// TipStatsConfig returns the config for recording received tips.
+func (c *Client) TipStatsConfig() (*clientdb.TipStatsConfig, error) {
+	var cfg *clientdb.TipStatsConfig
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		cfg, err = c.db.GetTipStatsConfig(tx)
+		return err
+	})
+	return cfg, err
+}
+
+// TipLeaderboard aggregates the recorded tips received in the [since, until)
+// period, per tipper and per tipped post. A zero until means until now.
+//
+// Only tips received while recording tips is enabled (see SetTipStatsConfig)
+// are included.
+func (c *Client) TipLeaderboard(since, until time.Time) (*TipLeaderboard, error) {
+	if until.IsZero() {
+		until = time.Now()
+	}
+	lb := &TipLeaderboard{Since: since, Until: until}
+	me := c.PublicID()
+	tippers := make(map[UserID]*TipLeader)
+	posts := make(map[clientintf.PostID]*PostTips)
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		tips, err := c.db.ListReceivedTips(tx, since, until)
+		if err != nil {
+			return err
+		}
+		for _, tip := range tips {
+			lb.MilliAtoms += tip.MilliAtoms
+			lb.Count += 1
+
+			tl := tippers[tip.UID]
+			if tl == nil {
+				tl = &TipLeader{UID: tip.UID}
+				tl.Nick, _ = c.UserNick(tip.UID)
+				tippers[tip.UID] = tl
+			}
+			tl.MilliAtoms += tip.MilliAtoms
+			tl.Count += 1
+			tl.Public = tl.Public || tip.Public
+
+			if tip.Post == nil {
+				continue
+			}
+			pt := posts[*tip.Post]
+			if pt == nil {
+				pm, err := c.db.ReadPost(tx, me, *tip.Post)
+				if err != nil {
+					// Not a post of the local client.
+					continue
+				}
+				pt = &PostTips{Post: *tip.Post, Title: clientintf.PostTitle(&pm)}
+				posts[*tip.Post] = pt
+			}
+			pt.MilliAtoms += tip.MilliAtoms
+			pt.Count += 1
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lb.Tippers = make([]TipLeader, 0, len(tippers))
+	for _, tl := range tippers {
+		lb.Tippers = append(lb.Tippers, *tl)
+	}
+	sort.Slice(lb.Tippers, func(i, j int) bool {
+		ti, tj := &lb.Tippers[i], &lb.Tippers[j]
+		if ti.MilliAtoms != tj.MilliAtoms {
+			return ti.MilliAtoms > tj.MilliAtoms
+		}
+		return ti.UID.String() < tj.UID.String()
+	})
+	lb.Posts = make([]PostTips, 0, len(posts))
+	for _, pt := range posts {
+		lb.Posts = append(lb.Posts, *pt)
+	}
+	sort.Slice(lb.Posts, func(i, j int) bool {
+		pi, pj := &lb.Posts[i], &lb.Posts[j]
+		if pi.MilliAtoms != pj.MilliAtoms {
+			return pi.MilliAtoms > pj.MilliAtoms
+		}
+		return pi.Post.String() < pj.Post.String()
+	})
+	return lb, nil
+}
+
+// tipsCount returns "1 tip" or "<n> tips".
+func tipsCount(n int) string {
+	if n == 1 {
+		return "1 tip"
+	}
+	return fmt.Sprintf("%d tips", n)
+}
+
+// ThankYouSummary returns a thank-you message for the tips in the
+// leaderboard. When named is true, tippers that consented to it are named in
+// the message. All other tippers are anonymized.
+func (lb *TipLeaderboard) ThankYouSummary(named bool) string {
+	amount := func(mAtoms int64) string {
+		return dcrutil.Amount(mAtoms / 1000).String()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Thank you for the tips received between %s and %s!\n\n",
+		lb.Since.Format("2006-01-02"), lb.Until.Format("2006-01-02"))
+	fmt.Fprintf(&b, "%s from %d tippers, totalling %s.\n", tipsCount(lb.Count),
+		len(lb.Tippers), amount(lb.MilliAtoms))
+
+	if len(lb.Tippers) > 0 {
+		b.WriteString("\nTop tippers:\n")
+		for i, tl := range lb.Tippers {
+			if i >= maxTipAckTippers {
+				break
+			}
+			name := "An anonymous tipper"
+			if named && tl.Public && tl.Nick != "" {
+				name = tl.Nick
+			}
+			fmt.Fprintf(&b, "%d. %s - %s (%s)\n", i+1, name,
+				amount(tl.MilliAtoms), tipsCount(tl.Count))
+		}
+	}
+
+	if len(lb.Posts) > 0 {
+		b.WriteString("\nMost tipped posts:\n")
+		for i, pt := range lb.Posts {
+			if i >= maxTipAckPosts {
+				break
+			}
+			fmt.Fprintf(&b, "- %s - %s (%s)\n", pt.Title,
+				amount(pt.MilliAtoms), tipsCount(pt.Count))
+		}
+	}
+	return b.String()
+}
+
+// SendTipThankYou sends a thank-you summary of the tips received in the
+// [since, until) period, as configured by SetTipStatsConfig: as a new post
+// and/or as a message in the configured GCs. Returns the summary, which is
+// empty (and not sent) if no tips were received in the period.
+func (c *Client) SendTipThankYou(since, until time.Time) (string, error) {
+	cfg, err := c.TipStatsConfig()
+	if err != nil {
+		return "", err
+	}
+	lb, err := c.TipLeaderboard(since, until)
+	if err != nil {
+		return "", err
+	}
+	if lb.Count == 0 {
+		return "", nil
+	}
+
+	msg := lb.ThankYouSummary(cfg.AckNamed)
+	if cfg.AckPost {
+		if _, err := c.createPost(msg, "", nil); err != nil {
+			return "", fmt.Errorf("unable to publish thank-you post: %v", err)
+		}
+	}
+	for _, gcID := range cfg.AckGCs {
+		err := c.GCMessage(gcID, msg, rpc.MessageModeNormal, nil)
+		if err != nil {
+			c.log.Errorf("Unable to send thank-you for tips to GC %s: %v",
+				gcID, err)
+		}
+	}
+	c.log.Infof("Sent thank-you summary of %d tips", lb.Count)
+	return msg, nil
+}
+
+// sendPeriodicTipThankYou sends the thank-you summary of the tips received
+// since the last one, if the configured interval elapsed.
+func (c *Client) sendPeriodicTipThankYou(now time.Time) error {
+	cfg, err := c.TipStatsConfig()
+	if err != nil {
+		return err
+	}
+	if !cfg.Enabled || cfg.AckInterval <= 0 || (!cfg.AckPost && len(cfg.AckGCs) == 0) {
+		return nil
+	}
+	if !cfg.LastAck.IsZero() && now.Sub(cfg.LastAck) < cfg.AckInterval {
+		return nil
+	}
+
+	if !cfg.LastAck.IsZero() {
+		if _, err := c.SendTipThankYou(cfg.LastAck, now); err != nil {
+			return err
+		}
+	}
+
+	// Start the next period.
+	return c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		cfg, err := c.db.GetTipStatsConfig(tx)
+		if err != nil {
+			return err
+		}
+		cfg.LastAck = now
+		return c.db.SetTipStatsConfig(tx, cfg)
+	})
+}
+
+// runTipThankYous periodically sends the thank-you summaries of received tips.
+func (c *Client) runTipThankYous(ctx context.Context) error {
+	if c.cfg.TipThankYouCheckInterval < 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(c.cfg.TipThankYouCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if err := c.sendPeriodicTipThankYou(time.Now()); err != nil {
+			c.log.Errorf("Unable to send thank-you for tips: %v", err)
+		}
+	}
+}
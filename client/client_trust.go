@@ -0,0 +1,252 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+)
+
+// TrustTier is the level of trust the local client has on a remote user. The
+// tier of a user gates the defaults of features that could be abused by remote
+// users.
+type TrustTier string
+
+const (
+	// TrustTierNew is the tier of users that were recently KX'd with.
+	TrustTierNew TrustTier = "new"
+
+	// TrustTierKnown is the tier of users that have been KX'd with for
+	// some time.
+	TrustTierKnown TrustTier = "known"
+
+	// TrustTierTrusted is the tier of users explicitly trusted by the
+	// local client.
+	TrustTierTrusted TrustTier = "trusted"
+)
+
+// IsValid returns true if the tier is one of the known tiers.
+func (tier TrustTier) IsValid() bool {
+	return tier == TrustTierNew || tier == TrustTierKnown || tier == TrustTierTrusted
+}
+
+// TrustTierPolicy are the feature defaults applied to users of a trust tier.
+type TrustTierPolicy struct {
+	// MaxAutoDownloadSize is the max size of free files that are
+	// downloaded from the user without calling the FileDownloadConfirmer.
+	MaxAutoDownloadSize uint64
+
+	// MaxSentFileSize is the max size of files the user may send
+	// (without being requested by the local client). Zero means files
+	// sent by the user are rejected.
+	MaxSentFileSize uint64
+
+	// ResourceRateLimit, if not nil, overrides the default rate limit of
+	// the resource requests of the user.
+	ResourceRateLimit *ResourceRateLimit
+
+	// AutoMediateID is whether requests from the user to mediate a KX
+	// with another user are automatically accepted.
+	AutoMediateID bool
+
+	// ResourcesAccess is whether the user may fetch resources (pages,
+	// store, etc) from the local client.
+	ResourcesAccess bool
+}
+
+// String returns the policy in the format accepted by ParseTrustTierPolicy.
+func (p TrustTierPolicy) String() string {
+	s := fmt.Sprintf("autodownload=%d,sentfiles=%d,mediate=%v,resources=%v",
+		p.MaxAutoDownloadSize, p.MaxSentFileSize, p.AutoMediateID,
+		p.ResourcesAccess)
+	if p.ResourceRateLimit != nil {
+		s += fmt.Sprintf(",ratelimit=%g:%d", p.ResourceRateLimit.Rate,
+			p.ResourceRateLimit.Burst)
+	}
+	return s
+}
+
+// ParseTrustTierPolicy parses a policy in the format of comma separated
+// <key>=<value> entries, starting from the base policy. Valid keys are
+// "autodownload" (bytes), "sentfiles" (bytes), "ratelimit"
+// (<rate>[:<burst>]), "mediate" (bool) and "resources" (bool).
+func ParseTrustTierPolicy(s string, base TrustTierPolicy) (TrustTierPolicy, error) {
+	p := base
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return p, fmt.Errorf("trust tier policy entry %q not "+
+				"in the format <key>=<value>", entry)
+		}
+		var err error
+		switch key {
+		case "autodownload":
+			p.MaxAutoDownloadSize, err = strconv.ParseUint(value, 10, 64)
+		case "sentfiles":
+			p.MaxSentFileSize, err = strconv.ParseUint(value, 10, 64)
+		case "mediate":
+			p.AutoMediateID, err = strconv.ParseBool(value)
+		case "resources":
+			p.ResourcesAccess, err = strconv.ParseBool(value)
+		case "ratelimit":
+			rateStr, burstStr, hasBurst := strings.Cut(value, ":")
+			limit := ResourceRateLimit{Burst: 1}
+			limit.Rate, err = strconv.ParseFloat(rateStr, 64)
+			if err == nil && hasBurst {
+				limit.Burst, err = strconv.Atoi(burstStr)
+			}
+			p.ResourceRateLimit = &limit
+		default:
+			return p, fmt.Errorf("unknown trust tier policy key %q", key)
+		}
+		if err != nil {
+			return p, fmt.Errorf("invalid value for trust tier policy "+
+				"key %q: %v", key, err)
+		}
+	}
+	return p, nil
+}
+
+// DefaultTrustTierPolicies are the default policies of each trust tier.
+var DefaultTrustTierPolicies = map[TrustTier]TrustTierPolicy{
+	TrustTierNew: {
+		ResourceRateLimit: &ResourceRateLimit{Rate: 0.2, Burst: 5},
+		ResourcesAccess:   true,
+	},
+	TrustTierKnown: {
+		MaxAutoDownloadSize: 1 << 20,
+		MaxSentFileSize:     10 << 20,
+		AutoMediateID:       true,
+		ResourcesAccess:     true,
+	},
+	TrustTierTrusted: {
+		MaxAutoDownloadSize: 10 << 20,
+		MaxSentFileSize:     1 << 30,
+		AutoMediateID:       true,
+		ResourcesAccess:     true,
+	},
+}
+
+// legacyTrustPolicy is the policy applied to all users when trust tiers are
+// not enabled. It matches the behavior of the client before trust tiers were
+// introduced.
+var legacyTrustPolicy = TrustTierPolicy{
+	MaxSentFileSize: ^uint64(0),
+	AutoMediateID:   true,
+	ResourcesAccess: true,
+}
+
+// TrustTiersConfig configures the trust tiers of remote users.
+type TrustTiersConfig struct {
+	// Policies are the policies of each tier. Tiers without a policy use
+	// the one in DefaultTrustTierPolicies.
+	Policies map[TrustTier]TrustTierPolicy
+
+	// KnownAfter is the amount of time after the first KX with a user
+	// that they are promoted from the new to the known tier, unless a tier
+	// was explicitly set for them. If zero, users are never automatically
+	// promoted.
+	KnownAfter time.Duration
+}
+
+// policy returns the policy of the given tier.
+func (cfg *TrustTiersConfig) policy(tier TrustTier) TrustTierPolicy {
+	if p, ok := cfg.Policies[tier]; ok {
+		return p
+	}
+	return DefaultTrustTierPolicies[tier]
+}
+
+// tierFor returns the tier of a user with the given address book entry.
+func (cfg *TrustTiersConfig) tierFor(ab *clientdb.AddressBookEntry) TrustTier {
+	if tier := TrustTier(ab.TrustTier); tier.IsValid() {
+		return tier
+	}
+	if cfg.KnownAfter > 0 && !ab.FirstCreated.IsZero() &&
+		time.Since(ab.FirstCreated) >= cfg.KnownAfter {
+		return TrustTierKnown
+	}
+	return TrustTierNew
+}
+
+// UserTrustTier returns the trust tier of the user and whether it was
+// explicitly set for the user.
+func (c *Client) UserTrustTier(uid UserID) (TrustTier, bool, error) {
+	var ab *clientdb.AddressBookEntry
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		ab, err = c.db.GetAddressBookEntry(tx, uid)
+		return err
+	})
+	if err != nil {
+		return "", false, err
+	}
+	cfg := c.cfg.TrustTiers
+	if cfg == nil {
+		cfg = &TrustTiersConfig{}
+	}
+	return cfg.tierFor(ab), TrustTier(ab.TrustTier).IsValid(), nil
+}
+
+// SetUserTrustTier sets the trust tier of the user. An empty tier removes the
+// explicitly set tier, such that the tier is determined automatically.
+func (c *Client) SetUserTrustTier(uid UserID, tier TrustTier) error {
+	if tier != "" && !tier.IsValid() {
+		return fmt.Errorf("invalid trust tier %q", tier)
+	}
+	err := c.dbUpdate(func(tx clientdb.ReadWriteTx) error {
+		ab, err := c.db.GetAddressBookEntry(tx, uid)
+		if err != nil {
+			return err
+		}
+		ab.TrustTier = string(tier)
+		return c.db.UpdateAddressBookEntry(tx, ab)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.trustTiersMtx.Lock()
+	delete(c.trustTiers, uid)
+	c.trustTiersMtx.Unlock()
+	return nil
+}
+
+// userTrustPolicy returns the policy that applies to the remote user.
+func (c *Client) userTrustPolicy(ru *RemoteUser) TrustTierPolicy {
+	cfg := c.cfg.TrustTiers
+	if cfg == nil {
+		return legacyTrustPolicy
+	}
+
+	uid := ru.ID()
+	c.trustTiersMtx.Lock()
+	tier, ok := c.trustTiers[uid]
+	c.trustTiersMtx.Unlock()
+	if !ok {
+		tier = TrustTierNew
+		err := c.dbView(func(tx clientdb.ReadTx) error {
+			ab, err := c.db.GetAddressBookEntry(tx, uid)
+			if err != nil {
+				return err
+			}
+			tier = cfg.tierFor(ab)
+			return nil
+		})
+		if err != nil {
+			ru.log.Warnf("Unable to determine trust tier: %v", err)
+		} else if tier != TrustTierNew {
+			// Users in the new tier are not cached, so that they
+			// may be automatically promoted.
+			c.trustTiersMtx.Lock()
+			c.trustTiers[uid] = tier
+			c.trustTiersMtx.Unlock()
+		}
+	}
+	return cfg.policy(tier)
+}
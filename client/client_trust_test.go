@@ -0,0 +1,112 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+)
+
+// TestParseTrustTierPolicy tests parsing trust tier policies.
+func TestParseTrustTierPolicy(t *testing.T) {
+	t.Parallel()
+
+	base := DefaultTrustTierPolicies[TrustTierKnown]
+	tests := []struct {
+		name    string
+		s       string
+		want    TrustTierPolicy
+		wantErr bool
+	}{{
+		name: "empty",
+		s:    "",
+		want: base,
+	}, {
+		name: "all keys",
+		s:    "autodownload=10, sentfiles=20,ratelimit=0.5:3,mediate=false,resources=false",
+		want: TrustTierPolicy{
+			MaxAutoDownloadSize: 10,
+			MaxSentFileSize:     20,
+			ResourceRateLimit:   &ResourceRateLimit{Rate: 0.5, Burst: 3},
+		},
+	}, {
+		name: "rate limit without burst",
+		s:    "ratelimit=2",
+		want: TrustTierPolicy{
+			MaxAutoDownloadSize: base.MaxAutoDownloadSize,
+			MaxSentFileSize:     base.MaxSentFileSize,
+			ResourceRateLimit:   &ResourceRateLimit{Rate: 2, Burst: 1},
+			AutoMediateID:       base.AutoMediateID,
+			ResourcesAccess:     base.ResourcesAccess,
+		},
+	}, {
+		name:    "unknown key",
+		s:       "foo=1",
+		wantErr: true,
+	}, {
+		name:    "missing value",
+		s:       "mediate",
+		wantErr: true,
+	}, {
+		name:    "invalid value",
+		s:       "autodownload=-1",
+		wantErr: true,
+	}}
+
+	for _, tc := range tests {
+		got, err := ParseTrustTierPolicy(tc.s, base)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error, got nil", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: unexpected policy: got %s, want %s", tc.name,
+				got, tc.want)
+		}
+
+		// The string representation is parseable back to the policy.
+		got2, err := ParseTrustTierPolicy(got.String(), TrustTierPolicy{})
+		if err != nil {
+			t.Fatalf("%s: unexpected error parsing %q: %v", tc.name,
+				got.String(), err)
+		}
+		if !reflect.DeepEqual(got, got2) {
+			t.Fatalf("%s: unexpected roundtrip policy: got %s, want %s",
+				tc.name, got2, got)
+		}
+	}
+}
+
+// TestTrustTierFor tests determining the trust tier of users.
+func TestTrustTierFor(t *testing.T) {
+	t.Parallel()
+
+	cfg := &TrustTiersConfig{KnownAfter: time.Hour}
+	now := time.Now()
+	tests := []struct {
+		name string
+		ab   clientdb.AddressBookEntry
+		want TrustTier
+	}{
+		{"recent", clientdb.AddressBookEntry{FirstCreated: now}, TrustTierNew},
+		{"old", clientdb.AddressBookEntry{FirstCreated: now.Add(-2 * time.Hour)}, TrustTierKnown},
+		{"unknown creation", clientdb.AddressBookEntry{}, TrustTierNew},
+		{"explicit trusted", clientdb.AddressBookEntry{FirstCreated: now, TrustTier: "trusted"}, TrustTierTrusted},
+		{"explicit new", clientdb.AddressBookEntry{FirstCreated: now.Add(-2 * time.Hour), TrustTier: "new"}, TrustTierNew},
+		{"invalid explicit", clientdb.AddressBookEntry{FirstCreated: now, TrustTier: "foo"}, TrustTierNew},
+	}
+
+	for _, tc := range tests {
+		got := cfg.tierFor(&tc.ab)
+		if got != tc.want {
+			t.Fatalf("%s: unexpected tier: got %s, want %s", tc.name,
+				got, tc.want)
+		}
+	}
+}
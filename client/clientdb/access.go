@@ -12,6 +12,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/companyzero/bisonrelay/client/internal/lrucache"
+	"github.com/companyzero/bisonrelay/client/metrics"
 	"github.com/companyzero/bisonrelay/inidb"
 	"github.com/companyzero/bisonrelay/lockfile"
 	"github.com/companyzero/bisonrelay/zkidentity"
@@ -52,8 +54,33 @@ type Config struct {
 
 	// DownloadsRoot is where to put final downloaded files.
 	DownloadsRoot string
+
+	// DownloadsNaming is the scheme used to name the per-conversation
+	// dirs of downloaded files. Defaults to DownloadsNamingNick.
+	DownloadsNaming DownloadsNaming
+
+	// FileCacheSize is the max size (in bytes) of the cache of frequently
+	// read files (address book entries and GC metadata). Zero means the
+	// default size of 8MiB. Negative values disable the cache.
+	FileCacheSize int64
+
+	// Metrics, when specified, records the hit rate and evictions of the
+	// file cache.
+	Metrics *metrics.Metrics
+
+	// MaxPaymentProofs is the max number of payment proofs stored for each
+	// remote user, after which the oldest ones are removed. Zero means the
+	// default of 1000.
+	MaxPaymentProofs int
 }
 
+// defaultFileCacheSize is the default max size of the file cache.
+const defaultFileCacheSize = 8 << 20 // 8MiB
+
+// defaultMaxPaymentProofs is the default max number of payment proofs stored
+// for each remote user.
+const defaultMaxPaymentProofs = 1000
+
 type DB struct {
 	cfg          Config
 	log          slog.Logger
@@ -63,6 +90,10 @@ type DB struct {
 	idb          *inidb.INIDB
 	invites      *inidb.INIDB
 
+	// files caches the contents of frequently read files. It is nil when
+	// the cache is disabled.
+	files *lrucache.Cache[string, []byte]
+
 	// Keep track of when the last msg of a given conversation was sent.
 	// This is used to emit "start-of-conversation", "day-changed" log
 	// messages.
@@ -157,6 +188,17 @@ func New(cfg Config) (*DB, error) {
 		blockedIDs:   blockedIDs,
 		payStats:     make(map[string]UserPayStats),
 	}
+	if cfg.FileCacheSize >= 0 {
+		cacheSize := cfg.FileCacheSize
+		if cacheSize == 0 {
+			cacheSize = defaultFileCacheSize
+		}
+		db.files = lrucache.New[string, []byte](lrucache.Config{
+			Name:     "db_files",
+			MaxBytes: cacheSize,
+			Metrics:  cfg.Metrics,
+		})
+	}
 
 	// Perform upgrades as needed.
 	if err := db.performUpgrades(); err != nil {
@@ -0,0 +1,140 @@
+package clientdb
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DownloadsNaming is the scheme used to name the dirs that organize the
+// downloaded files of each conversation.
+type DownloadsNaming string
+
+const (
+	// DownloadsNamingNick names the dirs after the nick of the user.
+	DownloadsNamingNick DownloadsNaming = "nick"
+
+	// DownloadsNamingUID names the dirs after the id of the user, which
+	// does not change when the user is renamed.
+	DownloadsNamingUID DownloadsNaming = "uid"
+
+	// DownloadsNamingNickUID names the dirs after the nick and the short
+	// id of the user.
+	DownloadsNamingNickUID DownloadsNaming = "nickuid"
+
+	// DownloadsNamingFlat puts all downloaded files directly in the
+	// downloads dir.
+	DownloadsNamingFlat DownloadsNaming = "flat"
+)
+
+// ParseDownloadsNaming parses a downloads naming scheme. An empty string is
+// parsed as DownloadsNamingNick.
+func ParseDownloadsNaming(s string) (DownloadsNaming, error) {
+	switch n := DownloadsNaming(strings.ToLower(s)); n {
+	case "":
+		return DownloadsNamingNick, nil
+	case DownloadsNamingNick, DownloadsNamingUID, DownloadsNamingNickUID,
+		DownloadsNamingFlat:
+		return n, nil
+	default:
+		return "", fmt.Errorf("unknown downloads naming scheme %q", s)
+	}
+}
+
+// conversationDownloadsDir returns the dir where files downloaded from the
+// given user are stored.
+func (db *DB) conversationDownloadsDir(nick string, uid UserID) string {
+	switch db.cfg.DownloadsNaming {
+	case DownloadsNamingUID:
+		return filepath.Join(db.downloadsDir, uid.String())
+	case DownloadsNamingNickUID:
+		return filepath.Join(db.downloadsDir,
+			escapeNickForFname(nick)+"-"+uid.ShortLogID())
+	case DownloadsNamingFlat:
+		return db.downloadsDir
+	default:
+		return filepath.Join(db.downloadsDir, escapeNickForFname(nick))
+	}
+}
+
+// Attachment is a file received from a remote user that was fully
+// downloaded.
+type Attachment struct {
+	FID      FileID    `json:"fid"`
+	Hash     string    `json:"hash"`
+	Filename string    `json:"filename"`
+	Size     uint64    `json:"size"`
+	DiskPath string    `json:"disk_path"`
+	Received time.Time `json:"received"`
+}
+
+// addAttachment adds the completed download to the index of attachments of
+// its user.
+func (db *DB) addAttachment(fd *FileDownload) error {
+	fname := filepath.Join(db.root, attachmentsDir, fd.UID.String())
+	var atts []Attachment
+	err := db.readJsonFile(fname, &atts)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	for _, att := range atts {
+		if att.FID == fd.FID {
+			return nil
+		}
+	}
+	atts = append(atts, Attachment{
+		FID:      fd.FID,
+		Hash:     fd.Metadata.Hash,
+		Filename: fd.Metadata.Filename,
+		Size:     fd.Metadata.Size,
+		DiskPath: fd.DiskPath,
+		Received: time.Now(),
+	})
+	return db.saveJsonFile(fname, atts)
+}
+
+// attachmentByHash returns the attachment received from the user with the
+// given file hash.
+func (db *DB) attachmentByHash(uid UserID, hash string) (*Attachment, error) {
+	fname := filepath.Join(db.root, attachmentsDir, uid.String())
+	var atts []Attachment
+	if err := db.readJsonFile(fname, &atts); err != nil {
+		return nil, err
+	}
+	for i := range atts {
+		if atts[i].Hash == hash {
+			return &atts[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ListAttachments lists the attachments received from the user, in the order
+// they were received.
+func (db *DB) ListAttachments(tx ReadTx, uid UserID) ([]Attachment, error) {
+	fname := filepath.Join(db.root, attachmentsDir, uid.String())
+	var atts []Attachment
+	err := db.readJsonFile(fname, &atts)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return atts, err
+}
+
+// GetAttachment returns the attachment with the given file id received from
+// the user. Returns ErrNotFound if the file has not been downloaded from the
+// user.
+func (db *DB) GetAttachment(tx ReadTx, uid UserID, fid FileID) (*Attachment, error) {
+	atts, err := db.ListAttachments(tx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range atts {
+		if atts[i].FID == fid {
+			return &atts[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
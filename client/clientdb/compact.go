@@ -0,0 +1,285 @@
+package clientdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CompactionStage is a stage of the compaction of the client storage.
+type CompactionStage string
+
+const (
+	// CompactionStageLogs compacts the message logs, dropping the
+	// redundant date markers written every time the client is restarted.
+	CompactionStageLogs CompactionStage = "logs"
+
+	// CompactionStageChunks prunes the chunks of completed or canceled
+	// downloads and the temp files left by interrupted writes.
+	CompactionStageChunks CompactionStage = "chunks"
+
+	// CompactionStageJSON rewrites the append-only JSON stores, dropping
+	// the entries truncated or corrupted by interrupted writes.
+	CompactionStageJSON CompactionStage = "json"
+)
+
+// staleTempFileAge is the min age of the temp files of interrupted writes
+// removed during compaction.
+const staleTempFileAge = time.Hour
+
+// logMarkerRegexp matches the date markers of message logs.
+var logMarkerRegexp = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}) \* (Conversation started|Day Changed to) (\d{4}-\d{2}-\d{2})$`)
+
+// jsonStreamFiles are the files of each user that are stored as a stream of
+// JSON entries, appended to as the entries are recorded.
+var jsonStreamFiles = []string{payStatsFile, paymentProofsFile,
+	genTipInvoicesFile, recvTipInvoicesFile, expiredTipInvoicesFile}
+
+// CompactionTarget is a file or dir of the client storage to be compacted.
+type CompactionTarget struct {
+	Stage CompactionStage
+	Path  string
+}
+
+// isTempFile returns true if the file is the temp file of a JSON file write.
+func isTempFile(fname string) bool {
+	base := filepath.Base(fname)
+	return strings.HasPrefix(base, ".") && strings.HasSuffix(base, ".new")
+}
+
+// isOrphanedChunkDir returns true if the chunks dir of a download may be
+// removed, because the download was completed or canceled.
+func (db *DB) isOrphanedChunkDir(dir string) bool {
+	metaFname := strings.TrimSuffix(dir, chunkDirSuffix) + contentMetaExt
+	var fd FileDownload
+	err := db.readJsonFile(metaFname, &fd)
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+	return err == nil && fd.CompletedName != ""
+}
+
+// CompactionTargets lists the files and dirs of the client storage that may
+// be compacted, in the order of the compaction stages.
+func (db *DB) CompactionTargets(tx ReadTx) ([]CompactionTarget, error) {
+	var res []CompactionTarget
+	add := func(stage CompactionStage, paths []string) {
+		for _, p := range paths {
+			res = append(res, CompactionTarget{Stage: stage, Path: p})
+		}
+	}
+
+	if db.cfg.MsgsRoot != "" {
+		logs, err := filepath.Glob(filepath.Join(db.cfg.MsgsRoot, "*.log"))
+		if err != nil {
+			return nil, err
+		}
+		add(CompactionStageLogs, logs)
+	}
+
+	chunkDirs, err := filepath.Glob(filepath.Join(db.root, downloadingDir, "*"+chunkDirSuffix))
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range chunkDirs {
+		if db.isOrphanedChunkDir(dir) {
+			add(CompactionStageChunks, []string{dir})
+		}
+	}
+	err = filepath.WalkDir(db.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && isTempFile(path) {
+			add(CompactionStageChunks, []string{path})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fname := range jsonStreamFiles {
+		files, err := filepath.Glob(filepath.Join(db.root, inboundDir, "*", fname))
+		if err != nil {
+			return nil, err
+		}
+		add(CompactionStageJSON, files)
+	}
+	add(CompactionStageJSON, []string{filepath.Join(db.root, tipStatsDir, receivedTipsFile)})
+
+	return res, nil
+}
+
+// CompactTarget compacts the target listed by CompactionTargets. It returns
+// the number of bytes reclaimed.
+func (db *DB) CompactTarget(tx ReadWriteTx, target CompactionTarget) (int64, error) {
+	switch target.Stage {
+	case CompactionStageLogs:
+		return db.compactMsgLog(target.Path)
+	case CompactionStageChunks:
+		return db.pruneOrphanedChunks(target.Path)
+	case CompactionStageJSON:
+		return db.rewriteJSONStream(target.Path)
+	default:
+		return 0, fmt.Errorf("unknown compaction stage %q", target.Stage)
+	}
+}
+
+// diskUsage returns the size of the file or the total size of the files in
+// the dir.
+func diskUsage(path string) int64 {
+	var total int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if fi, err := d.Info(); err == nil && !fi.IsDir() {
+			total += fi.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// replaceFile replaces the contents of the file with data, through a temp
+// file. It returns the number of bytes reclaimed.
+func replaceFile(fname string, data []byte) (int64, error) {
+	fi, err := os.Stat(fname)
+	if err != nil {
+		return 0, err
+	}
+	tempFname := fname + ".compact"
+	if err := os.WriteFile(tempFname, data, fi.Mode().Perm()); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tempFname, fname); err != nil {
+		os.Remove(tempFname)
+		return 0, err
+	}
+	return fi.Size() - int64(len(data)), nil
+}
+
+// compactMsgLog drops the redundant date markers of the message log (those on
+// the same date as the previous line), which are written every time the
+// client is restarted.
+func (db *DB) compactMsgLog(fname string) (int64, error) {
+	data, err := os.ReadFile(fname)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	var lastDay string
+	var dropped bool
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := logMarkerRegexp.FindStringSubmatch(line); m != nil {
+			day := m[3]
+			if day == lastDay {
+				dropped = true
+				continue
+			}
+			if m[2] == "Conversation started" && lastDay != "" {
+				// Only the first marker of the log is the start
+				// of the conversation.
+				line = fmt.Sprintf("%s * Day Changed to %s", m[1], day)
+				dropped = true
+			}
+			lastDay = day
+		} else if m := logLineRegexp.FindStringSubmatch(line); m != nil {
+			lastDay = m[1][:10]
+		}
+		out.WriteString(line)
+		out.WriteRune('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if !dropped {
+		return 0, nil
+	}
+	return replaceFile(fname, out.Bytes())
+}
+
+// pruneOrphanedChunks removes the chunks dir of a completed or canceled
+// download or a stale temp file of an interrupted write.
+func (db *DB) pruneOrphanedChunks(path string) (int64, error) {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Check again the target may be removed, as it may have changed
+	// since it was listed.
+	switch {
+	case fi.IsDir() && strings.HasSuffix(path, chunkDirSuffix):
+		if !db.isOrphanedChunkDir(path) {
+			return 0, nil
+		}
+	case !fi.IsDir() && isTempFile(path):
+		if time.Since(fi.ModTime()) < staleTempFileAge {
+			return 0, nil
+		}
+	default:
+		return 0, fmt.Errorf("%s is not a chunks dir or temp file", path)
+	}
+
+	size := diskUsage(path)
+	if err := os.RemoveAll(path); err != nil {
+		return 0, err
+	}
+	db.log.Debugf("Removed orphaned %s (%d bytes)", path, size)
+	return size, nil
+}
+
+// rewriteJSONStream rewrites the stream of JSON entries of the file, dropping
+// the entries that are not valid JSON (e.g. entries truncated by a crash
+// while being appended, which prevent reading the entries after them).
+func (db *DB) rewriteJSONStream(fname string) (int64, error) {
+	data, err := os.ReadFile(fname)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	var dropped int
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			dropped++
+			continue
+		}
+		out.Write(line)
+		out.WriteRune('\n')
+	}
+	if out.Len() == len(data) {
+		return 0, nil
+	}
+	if dropped > 0 {
+		db.log.Warnf("Dropped %d invalid entries from %s", dropped, fname)
+	}
+	return replaceFile(fname, out.Bytes())
+}
@@ -0,0 +1,196 @@
+package clientdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const mergedContactsDir = "mergedcontacts"
+
+// MergedContact is a duplicate address book entry that was merged into a
+// canonical entry.
+type MergedContact struct {
+	UID  UserID `json:"uid"`
+	Nick string `json:"nick"`
+	Name string `json:"name"`
+
+	// Merged is when the entry was merged.
+	Merged time.Time `json:"merged"`
+}
+
+// AddMergedContact records that the given contact was merged into the
+// canonical user.
+func (db *DB) AddMergedContact(tx ReadWriteTx, canonical UserID, mc MergedContact) error {
+	merged, err := db.ListMergedContacts(tx, canonical)
+	if err != nil {
+		return err
+	}
+	for _, old := range merged {
+		if old.UID == mc.UID {
+			return nil
+		}
+	}
+	merged = append(merged, mc)
+	fname := filepath.Join(db.root, mergedContactsDir, canonical.String())
+	return db.saveJsonFile(fname, merged)
+}
+
+// ListMergedContacts lists the contacts merged into the canonical user.
+func (db *DB) ListMergedContacts(tx ReadTx, canonical UserID) ([]MergedContact, error) {
+	fname := filepath.Join(db.root, mergedContactsDir, canonical.String())
+	var merged []MergedContact
+	err := db.readJsonFile(fname, &merged)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// FindMergedContactNick returns the canonical user into which a contact with
+// the given nick (or ID) was merged.
+func (db *DB) FindMergedContactNick(tx ReadTx, nick string) (UserID, error) {
+	dir := filepath.Join(db.root, mergedContactsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return UserID{}, err
+	}
+	for _, entry := range entries {
+		var canonical UserID
+		if err := canonical.FromString(entry.Name()); err != nil {
+			continue
+		}
+		merged, err := db.ListMergedContacts(tx, canonical)
+		if err != nil {
+			return UserID{}, err
+		}
+		for _, mc := range merged {
+			if strings.EqualFold(mc.Nick, nick) || mc.UID.String() == nick {
+				return canonical, nil
+			}
+		}
+	}
+	return UserID{}, fmt.Errorf("merged contact %q: %w", nick, ErrNotFound)
+}
+
+// logBlock is a logged message (with any continuation lines) of a message log.
+type logBlock struct {
+	ts    time.Time
+	lines string
+}
+
+// readLogBlocks reads the messages of a message log file.
+func readLogBlocks(filename string) ([]logBlock, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var blocks []logBlock
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+			var ts time.Time
+			matches := logLineRegexp.FindStringSubmatch(line)
+			if len(matches) == 3 {
+				ts, _ = time.ParseInLocation("2006-01-02T15:04:05",
+					matches[1], time.Local)
+			}
+			if ts.IsZero() && len(blocks) > 0 {
+				// Continuation of the previous message.
+				blocks[len(blocks)-1].lines += line
+			} else {
+				blocks = append(blocks, logBlock{ts: ts, lines: line})
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return blocks, nil
+}
+
+// MergePMLogs merges the log of messages exchanged with the from user into
+// the log of messages exchanged with the into user, in timestamp order. The
+// log of the from user is removed.
+func (db *DB) MergePMLogs(tx ReadWriteTx, into, from UserID) error {
+	if db.cfg.MsgsRoot == "" {
+		return nil
+	}
+
+	intoEntry, err := db.getBaseABEntry(into)
+	if err != nil {
+		return err
+	}
+	fromEntry, err := db.getBaseABEntry(from)
+	if err != nil {
+		return err
+	}
+	intoLogFname := fmt.Sprintf("%s.%s.log", escapeNickForFname(intoEntry.ID.Nick), into)
+	fromLogFname := fmt.Sprintf("%s.%s.log", escapeNickForFname(fromEntry.ID.Nick), from)
+	intoFname := filepath.Join(db.cfg.MsgsRoot, intoLogFname)
+	fromFname := filepath.Join(db.cfg.MsgsRoot, fromLogFname)
+
+	fromBlocks, err := readLogBlocks(fromFname)
+	if err != nil {
+		return err
+	}
+	if len(fromBlocks) == 0 {
+		return nil
+	}
+	intoBlocks, err := readLogBlocks(intoFname)
+	if err != nil {
+		return err
+	}
+
+	blocks := append(intoBlocks, fromBlocks...)
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].ts.Before(blocks[j].ts)
+	})
+
+	tmpFname := intoFname + ".tmp"
+	f, err := os.OpenFile(tmpFname, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, b := range blocks {
+		if _, err := w.WriteString(b.lines); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFname, intoFname); err != nil {
+		return err
+	}
+
+	delete(db.lastMsgTS, fromLogFname)
+	if last := blocks[len(blocks)-1].ts; !last.IsZero() {
+		db.lastMsgTS[intoLogFname] = last
+	}
+	return os.Remove(fromFname)
+}
@@ -850,8 +850,16 @@ func (db *DB) SaveFileDownloadChunk(tx ReadWriteTx, user string, fd *FileDownloa
 		return "", nil
 	}
 
+	// If an identical file was already received in this conversation,
+	// reuse it instead of assembling a duplicate.
+	if att, err := db.attachmentByHash(fd.UID, fd.Metadata.Hash); err == nil &&
+		fileExists(att.DiskPath) {
+
+		return db.completeFileDownload(fd, chunkDir, att.DiskPath)
+	}
+
 	// Assemble final file. First: figure out final name.
-	baseDestFileName := filepath.Join(db.downloadsDir, escapeNickForFname(user),
+	baseDestFileName := filepath.Join(db.conversationDownloadsDir(user, fd.UID),
 		strescape.PathElement(fd.Metadata.Filename))
 	destFileName := baseDestFileName
 	ext := filepath.Ext(baseDestFileName)
@@ -892,11 +900,22 @@ func (db *DB) SaveFileDownloadChunk(tx ReadWriteTx, user string, fd *FileDownloa
 		return "", fmt.Errorf("unexpected final file hash (got %s, want %s)",
 			hashStr, fd.Metadata.Hash)
 	}
+	return db.completeFileDownload(fd, chunkDir, destFileName)
+}
+
+// completeFileDownload records the download as completed, with its final file
+// at destFileName, and cleans up its chunks.
+func (db *DB) completeFileDownload(fd *FileDownload, chunkDir, destFileName string) (string, error) {
 	fd.CompletedName = filepath.Base(destFileName)
+	fd.DiskPath = destFileName
+	diskDir := filepath.Join(db.root, downloadingDir)
 	metaPath := filepath.Join(diskDir, fd.FID.String()+contentMetaExt)
 	if err := db.saveJsonFile(metaPath, fd); err != nil {
 		return "", err
 	}
+	if err := db.addAttachment(fd); err != nil {
+		return "", err
+	}
 
 	// Finally, clean up the chunks.
 	if err := os.RemoveAll(chunkDir); err != nil {
@@ -949,10 +968,12 @@ func (db *DB) HasDownloadedFile(tx ReadTx, fid zkidentity.ShortID) (string, erro
 		return "", fmt.Errorf("unable to load addressbook entry: %v", err)
 	}
 
-	fname := fd.CompletedName
-	if fname != "" {
+	fname := fd.DiskPath
+	if fname == "" && fd.CompletedName != "" {
 		fname = filepath.Join(db.downloadsDir,
 			escapeNickForFname(ab.ID.Nick), fd.CompletedName)
+	}
+	if fname != "" {
 		if !fileExists(fname) {
 			fname = ""
 		}
@@ -986,7 +1007,9 @@ func (db *DB) HasDownloadedFiles(tx ReadTx, user string, uid UserID, files []rpc
 			return nil, err
 		}
 		res[i].UID = fd.UID
-		if fd.CompletedName != "" {
+		if fd.DiskPath != "" {
+			res[i].DiskPath = fd.DiskPath
+		} else if fd.CompletedName != "" {
 			res[i].DiskPath = filepath.Join(db.downloadsDir,
 				escapeNickForFname(user), fd.CompletedName)
 		}
@@ -0,0 +1,33 @@
+package clientdb
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// RemoteFeatures are the features advertised by a remote user.
+type RemoteFeatures struct {
+	Features rpc.RMFeatures `json:"features"`
+
+	// Updated is when the features were received.
+	Updated time.Time `json:"updated"`
+}
+
+// GetRemoteFeatures returns the cached features of the remote user. Returns
+// ErrNotFound if the user has not advertised their features.
+func (db *DB) GetRemoteFeatures(tx ReadTx, uid UserID) (*RemoteFeatures, error) {
+	fname := filepath.Join(db.root, inboundDir, uid.String(), remoteFeaturesFile)
+	var rf RemoteFeatures
+	if err := db.readJsonFile(fname, &rf); err != nil {
+		return nil, err
+	}
+	return &rf, nil
+}
+
+// UpdateRemoteFeatures updates the cached features of the remote user.
+func (db *DB) UpdateRemoteFeatures(tx ReadWriteTx, uid UserID, rf *RemoteFeatures) error {
+	fname := filepath.Join(db.root, inboundDir, uid.String(), remoteFeaturesFile)
+	return db.saveJsonFile(fname, rf)
+}
@@ -40,12 +40,14 @@ const (
 	postsSubscribers    = "subscribers"
 	postsSubscriptions  = "subscriptns"
 	postsStatusExt      = ".status"
+	postsAnnounceFile   = "announce.json"
 	kxDir               = "kx"
 	transResetFile      = "transreset.json"
 	sendqDir            = "sendqueue"
 	blockedUsersFile    = "blockedusers.json"
 	paidRVsDir          = "paidrvs"
 	paidPushesDir       = "paidpushes"
+	recvMsgIDsDir       = "recvmsgids"
 	kxSearches          = "kxsearches"
 	miRequestsDir       = "mirequests"
 	postKXActionsDir    = "postkxactions"
@@ -54,18 +56,30 @@ const (
 	unackedRMsDir       = "unackedrms"
 	lastConnDateFile    = "lastconndate.json"
 	tipsDir             = "tips"
+	tipStatsDir         = "tipstats"
+	summariesDir        = "summaries"
+	guestViewsDir       = "guestviews"
 	onboardStateFile    = "onboard.json"
 	reqResourcesDir     = "reqresources"
 	recvAddrForUserFile = "onchainrecvaddr.json"
 	cachedGCMsDir       = "cachedgcms"
 	unkxdUsersDir       = "unkxd"
 	filtersDir          = "contentfilters"
+	referralsDir        = "referrals"
+	importedContactsDir = "importedcontacts"
+	attachmentsDir      = "attachments"
 
 	pageSessionsDir         = "pagesessions"
 	pageSessionOverviewFile = "overview.json"
 	genTipInvoicesFile      = "generated-tip-invoices.json"
 	recvTipInvoicesFile     = "received-tip-invoices.json"
 	expiredTipInvoicesFile  = "expired-tip-invoices.json"
+	paymentProofsFile       = "payment-proofs.json"
+	cleanShutdownFile       = "clean-shutdown.json"
+	remoteFeaturesFile      = "features.json"
+	peerStatsFile           = "peerstats.json"
+	savedQueriesFile        = "savedqueries.json"
+	paymentAuditFile        = "payment-audit.json"
 )
 
 var (
@@ -247,7 +261,7 @@ func (db *DB) AddressBookEntryExists(tx ReadTx, id UserID) bool {
 func (db *DB) getBaseABEntry(id UserID) (*AddressBookEntry, error) {
 	filename := filepath.Join(db.root, inboundDir, id.String(),
 		identityFilename)
-	blob, err := os.ReadFile(filename)
+	blob, err := db.readCachedFile(filename)
 	if os.IsNotExist(err) {
 		return nil, fmt.Errorf("identity file %s: %w", id.String(), ErrNotFound)
 	}
@@ -608,6 +622,7 @@ func (db *DB) RemoveUser(tx ReadWriteTx, id UserID, block bool) error {
 		}
 	}
 	dir := filepath.Join(db.root, inboundDir, id.String())
+	db.uncacheFile(filepath.Join(dir, identityFilename))
 	return os.RemoveAll(dir)
 }
 
@@ -664,6 +679,33 @@ func (db *DB) ReplaceLastConnDate(tx ReadWriteTx, date time.Time) (time.Time, er
 	return oldDate, err
 }
 
+// MarkCleanShutdown records the marker that indicates the client was cleanly
+// shutdown, along with the checkpointed state at shutdown time.
+func (db *DB) MarkCleanShutdown(tx ReadWriteTx, marker *CleanShutdownMarker) error {
+	fname := filepath.Join(db.root, cleanShutdownFile)
+	return db.saveJsonFile(fname, marker)
+}
+
+// TakeCleanShutdownMarker returns the marker recorded during the last clean
+// shutdown and removes it from the db, such that an unclean shutdown of the
+// current run is not mistaken for a clean one. Returns nil if the last
+// shutdown was not clean.
+func (db *DB) TakeCleanShutdownMarker(tx ReadWriteTx) (*CleanShutdownMarker, error) {
+	fname := filepath.Join(db.root, cleanShutdownFile)
+	var marker CleanShutdownMarker
+	err := db.readJsonFile(fname, &marker)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(fname); err != nil {
+		return nil, err
+	}
+	return &marker, nil
+}
+
 // Backup
 func (db *DB) Backup(_ ReadTx, rootDir, destPath string) (string, error) {
 	f, err := os.CreateTemp(destPath, "brclient-backup")
@@ -236,7 +236,7 @@ func (db *DB) FindAcceptedGCInvite(tx ReadTx, gcID, uid zkidentity.ShortID) (rpc
 
 // readGC reads the gc from the given filename into gl.
 func (db *DB) readGC(filename string, gc *rpc.RMGroupList) error {
-	gcJSON, err := os.ReadFile(filename)
+	gcJSON, err := db.readCachedFile(filename)
 	if err != nil && os.IsNotExist(err) {
 		return ErrNotFound
 	}
@@ -311,6 +311,7 @@ func (db *DB) SaveGC(tx ReadWriteTx, gc rpc.RMGroupList) error {
 func (db *DB) DeleteGC(tx ReadWriteTx, gcID zkidentity.ShortID) error {
 	gcDir := filepath.Join(db.root, groupchatDir)
 	filename := filepath.Join(gcDir, gcID.String())
+	db.uncacheFile(filename)
 	if err := os.Remove(filename); err != nil {
 		return err
 	}
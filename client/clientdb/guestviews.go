@@ -0,0 +1,73 @@
+package clientdb
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// GuestView is a set of pages published to the server for guests that hold
+// the view token.
+type GuestView struct {
+	Token   *clientintf.GuestViewToken `json:"token"`
+	Created time.Time                  `json:"created"`
+
+	// Pages are the paths of the published pages.
+	Pages [][]string `json:"pages"`
+}
+
+// StoreGuestView stores the guest view.
+func (db *DB) StoreGuestView(tx ReadWriteTx, gv *GuestView) error {
+	fname := filepath.Join(db.root, guestViewsDir, gv.Token.ID.String())
+	return db.saveJsonFile(fname, gv)
+}
+
+// GetGuestView returns the guest view with the given id.
+func (db *DB) GetGuestView(tx ReadTx, id clientintf.GuestViewID) (*GuestView, error) {
+	fname := filepath.Join(db.root, guestViewsDir, id.String())
+	var gv GuestView
+	if err := db.readJsonFile(fname, &gv); err != nil {
+		return nil, err
+	}
+	return &gv, nil
+}
+
+// ListGuestViews lists the guest views, sorted by creation time.
+func (db *DB) ListGuestViews(tx ReadTx) ([]GuestView, error) {
+	dir := filepath.Join(db.root, guestViewsDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]GuestView, 0, len(entries))
+	for _, e := range entries {
+		var gv GuestView
+		err := db.readJsonFile(filepath.Join(dir, e.Name()), &gv)
+		if err != nil {
+			db.log.Warnf("Unable to read guest view %s: %v", e.Name(), err)
+			continue
+		}
+		res = append(res, gv)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+	return res, nil
+}
+
+// RemoveGuestView removes the guest view with the given id.
+func (db *DB) RemoveGuestView(tx ReadWriteTx, id clientintf.GuestViewID) error {
+	fname := filepath.Join(db.root, guestViewsDir, id.String())
+	err := os.Remove(fname)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
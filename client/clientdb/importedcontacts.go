@@ -0,0 +1,177 @@
+package clientdb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// ImportedContact is a placeholder for a contact imported from the contact
+// list of another platform, to be invited to BR. Imported contacts are local
+// only data and are never sent to remote users.
+type ImportedContact struct {
+	ID          string    `json:"id"`
+	Source      string    `json:"source"`
+	Name        string    `json:"name"`
+	Identifiers []string  `json:"identifiers,omitempty"`
+	Imported    time.Time `json:"imported"`
+
+	// InviteRV is the initial RV of the invite created for the contact.
+	// It is nil while the contact has not been invited.
+	InviteRV  *zkidentity.ShortID `json:"invite_rv,omitempty"`
+	InvitedTS *time.Time          `json:"invited_ts,omitempty"`
+
+	// Joined is the user that completed KX through the invite of the
+	// contact. It is nil while the invite has not been accepted.
+	Joined   *UserID    `json:"joined,omitempty"`
+	JoinedTS *time.Time `json:"joined_ts,omitempty"`
+}
+
+// importedContactID returns the ID of an imported contact. The ID is derived
+// from the source, name and identifiers of the contact, such that importing
+// the same contact list twice does not duplicate its contacts.
+func importedContactID(c *ImportedContact) string {
+	h := sha256.New()
+	h.Write([]byte(strings.ToLower(c.Source)))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.ToLower(c.Name)))
+	for _, id := range c.Identifiers {
+		h.Write([]byte{0})
+		h.Write([]byte(strings.ToLower(id)))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// AddImportedContact adds the contact to the list of imported contacts. The ID
+// of the contact is filled by this function. Returns false if the contact was
+// already imported, in which case the existing contact is kept.
+func (db *DB) AddImportedContact(tx ReadWriteTx, c *ImportedContact) (bool, error) {
+	c.ID = importedContactID(c)
+	fname := filepath.Join(db.root, importedContactsDir, c.ID)
+	if fileExists(fname) {
+		return false, nil
+	}
+	if err := db.saveJsonFile(fname, c); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetImportedContact returns the imported contact with the given ID.
+func (db *DB) GetImportedContact(tx ReadTx, id string) (*ImportedContact, error) {
+	if !isHexID(id) {
+		return nil, fmt.Errorf("imported contact %q: %w", id, ErrNotFound)
+	}
+	fname := filepath.Join(db.root, importedContactsDir, id)
+	var c ImportedContact
+	if err := db.readJsonFile(fname, &c); err != nil {
+		return nil, fmt.Errorf("imported contact %q: %w", id, err)
+	}
+	return &c, nil
+}
+
+// SetImportedContactInvite records that the contact was invited with the
+// invite with the given initial RV.
+func (db *DB) SetImportedContactInvite(tx ReadWriteTx, id string,
+	initialRV zkidentity.ShortID) (*ImportedContact, error) {
+
+	c, err := db.GetImportedContact(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Joined != nil {
+		return nil, fmt.Errorf("imported contact %q already joined as %s",
+			id, c.Joined)
+	}
+	now := time.Now()
+	c.InviteRV, c.InvitedTS = &initialRV, &now
+	fname := filepath.Join(db.root, importedContactsDir, id)
+	if err := db.saveJsonFile(fname, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// MarkImportedContactJoined records that the given user completed KX through
+// the invite with the given initial RV. Returns ErrNotFound if no imported
+// contact was invited with the invite.
+func (db *DB) MarkImportedContactJoined(tx ReadWriteTx, initialRV zkidentity.ShortID,
+	uid UserID) (*ImportedContact, error) {
+
+	contacts, err := db.ListImportedContacts(tx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range contacts {
+		c := &contacts[i]
+		if c.InviteRV == nil || *c.InviteRV != initialRV || c.Joined != nil {
+			continue
+		}
+		now := time.Now()
+		c.Joined, c.JoinedTS = &uid, &now
+		fname := filepath.Join(db.root, importedContactsDir, c.ID)
+		if err := db.saveJsonFile(fname, c); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return nil, ErrNotFound
+}
+
+// RemoveImportedContact removes the imported contact with the given ID.
+func (db *DB) RemoveImportedContact(tx ReadWriteTx, id string) error {
+	if !isHexID(id) {
+		return fmt.Errorf("imported contact %q: %w", id, ErrNotFound)
+	}
+	fname := filepath.Join(db.root, importedContactsDir, id)
+	err := os.Remove(fname)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("imported contact %q: %w", id, ErrNotFound)
+	}
+	return err
+}
+
+// ListImportedContacts lists all imported contacts, sorted by import time and
+// name.
+func (db *DB) ListImportedContacts(tx ReadTx) ([]ImportedContact, error) {
+	dir := filepath.Join(db.root, importedContactsDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ImportedContact, 0, len(entries))
+	for _, e := range entries {
+		var c ImportedContact
+		err := db.readJsonFile(filepath.Join(dir, e.Name()), &c)
+		if err != nil {
+			db.log.Warnf("Unable to read imported contact %s: %v",
+				e.Name(), err)
+			continue
+		}
+		res = append(res, c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Imported.Equal(res[j].Imported) {
+			return res[i].Imported.Before(res[j].Imported)
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
+
+// isHexID returns true if the id is a valid ID of an imported contact.
+func isHexID(id string) bool {
+	b, err := hex.DecodeString(id)
+	return err == nil && len(b) == 8
+}
@@ -113,6 +113,14 @@ type AddressBookEntry struct {
 	// LastHandshake is the last time when the local client attempted
 	// to start a handshake with this remote user.
 	LastHandshakeAttempt time.Time `json:"last_handshake_attempt,omitempty"`
+
+	// LNNodeID is the last known LN node of the remote user, as learned
+	// from invoices received from them. It is used to send keysend tips.
+	LNNodeID string `json:"ln_node_id,omitempty"`
+
+	// TrustTier is the trust tier explicitly set by the local client for
+	// the remote user. Empty if the tier is determined automatically.
+	TrustTier string `json:"trust_tier,omitempty"`
 }
 
 // AddressBookAndRatchet stores both the address book entry and ratchet data of
@@ -175,6 +183,11 @@ type FileDownload struct {
 	ChunkStates      map[int]ChunkState `json:"chunkstates"`
 	ChunkUpdatedTime map[int]time.Time  `json:"chunkupdttimes"`
 	IsSentFile       bool               `json:"is_sent_file"`
+
+	// DiskPath is the full path to the completed file. Downloads completed
+	// before this was tracked only have CompletedName set, relative to
+	// the dir of the user's nick in the downloads dir.
+	DiskPath string `json:"disk_path,omitempty"`
 }
 
 func (fd *FileDownload) GetChunkState(chunkIdx int) ChunkState {
@@ -248,6 +261,12 @@ type SendQueueElement struct {
 	Dests    []UserID      `json:"dests"`
 	Msg      []byte        `json:"msg"`
 	Priority uint          `json:"priority"`
+
+	// Batch is the ID of the batch of messages the element is part of
+	// (if any). Messages of a batch are sent in order of their BatchIndex
+	// to each destination.
+	Batch      *SendQID `json:"batch,omitempty"`
+	BatchIndex int      `json:"batch_index,omitempty"`
 }
 
 // KXSeachQuery holds a specific target used while searching for a KX.
@@ -326,6 +345,8 @@ type TipUserAttempt struct {
 	PrevInvoices         []string   `json:"prev_invoices"`
 	LastInvoiceError     *string    `json:"last_invoice_error,omitempty"`
 	Completed            *time.Time `json:"completed,omitempty"`
+	Post                 *PostID    `json:"post,omitempty"`
+	Public               bool       `json:"public,omitempty"`
 }
 
 // ResourceRequest is a serialized request for a resource.
@@ -337,6 +358,40 @@ type ResourceRequest struct {
 	ParentPage clientintf.PagesSessionID `json:"parent_page"`
 }
 
+// ResourceSignatureStatus is the result of verifying the signature of a
+// fetched resource.
+type ResourceSignatureStatus string
+
+const (
+	// ResourceUnsigned is the status of resources fetched without a
+	// signature.
+	ResourceUnsigned ResourceSignatureStatus = ""
+
+	// ResourceSignatureValid is the status of resources signed by the
+	// identity of the user that replied to the request.
+	ResourceSignatureValid ResourceSignatureStatus = "valid"
+
+	// ResourceSignatureInvalid is the status of resources with a signature
+	// that does not match the resource. The resource may have been altered
+	// after being signed.
+	ResourceSignatureInvalid ResourceSignatureStatus = "invalid"
+)
+
+// ResourceVerification is the result of verifying the signatures of a fetched
+// resource.
+type ResourceVerification struct {
+	// SignatureStatus is the status of the signature of the resource.
+	SignatureStatus ResourceSignatureStatus `json:"signature_status,omitempty"`
+
+	// OrderSummaryStatus is the status of the signature of the order
+	// summary included in the resource (if any).
+	OrderSummaryStatus ResourceSignatureStatus `json:"order_summary_status,omitempty"`
+
+	// OrderSummary is the order summary included in the resource. It is
+	// only set when its signature is valid.
+	OrderSummary *rpc.OrderSummary `json:"order_summary,omitempty"`
+}
+
 // FetchedResource is the full information about a fetched resource from a
 // remote client.
 type FetchedResource struct {
@@ -348,6 +403,8 @@ type FetchedResource struct {
 	ResponseTS time.Time                 `json:"response_ts"`
 	Request    rpc.RMFetchResource       `json:"request"`
 	Response   rpc.RMFetchResourceReply  `json:"response"`
+
+	ResourceVerification
 }
 
 // PageSessionOverviewRequest is the overview of a fetch resource request.
@@ -408,6 +465,31 @@ type GeneratedInvoiceForTip struct {
 	Created    time.Time `json:"created"`
 	Invoice    string    `json:"invoice"`
 	MilliAtoms uint64    `json:"milli_atoms"`
+	Post       *PostID   `json:"post,omitempty"`
+	Public     bool      `json:"public,omitempty"`
+}
+
+// CleanShutdownMarker is recorded when the client is cleanly shutdown. It
+// checkpoints the state of outbound queues and downloads at shutdown time,
+// which allows skipping recovery scans on the next start.
+type CleanShutdownMarker struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// UnsentRMs is the number of RMs that were still in the outbound
+	// queue (and thus not acked by the server) during shutdown.
+	UnsentRMs int `json:"unsent_rms"`
+
+	// SendQueueLen is the number of elements in the send queue.
+	SendQueueLen int `json:"sendq_len"`
+
+	// OutstandingDownloads are the downloads that were in progress.
+	OutstandingDownloads []DownloadRef `json:"outstanding_downloads"`
+}
+
+// DownloadRef references a file download.
+type DownloadRef struct {
+	UID UserID `json:"uid"`
+	FID FileID `json:"fid"`
 }
 
 // ContentFilter stores filtering rules for content.
@@ -0,0 +1,67 @@
+package clientdb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+type receivedMsgID struct {
+	TS time.Time `json:"ts"`
+}
+
+// MarkMsgIDReceived records that the message with the given ID (see
+// rpc.RMPrivateMessage.MsgID and rpc.RMGroupMessage.MsgID) was received. It
+// returns true if the message was already received, in which case it should
+// not be processed again.
+func (db *DB) MarkMsgIDReceived(tx ReadWriteTx, id zkidentity.ShortID) (bool, error) {
+	filename := filepath.Join(db.root, recvMsgIDsDir, id.String())
+	var rmi receivedMsgID
+	err := db.readJsonFile(filename, &rmi)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	rmi.TS = time.Now()
+	return false, db.saveJsonFile(filename, &rmi)
+}
+
+// CleanupReceivedMsgIDs removes the IDs of messages received before the
+// limit.
+func (db *DB) CleanupReceivedMsgIDs(tx ReadWriteTx, limit time.Time) error {
+	dir := filepath.Join(db.root, recvMsgIDsDir)
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, f := range files {
+		var rmi receivedMsgID
+		filename := filepath.Join(dir, f.Name())
+		if err := db.readJsonFile(filename, &rmi); err != nil {
+			db.log.Debugf("Unable to read json file %s: %v", filename, err)
+			continue
+		}
+		if !rmi.TS.Before(limit) {
+			continue
+		}
+		if err := os.Remove(filename); err != nil {
+			db.log.Debugf("Unable to remove file %s: %v", filename, err)
+			continue
+		}
+		total += 1
+	}
+	if total > 0 {
+		db.log.Debugf("Cleaned up %d received msg ids", total)
+	}
+	return nil
+}
@@ -7,12 +7,39 @@ import (
 )
 
 type onchainAddr struct {
+	// Addr is the address generated from the default account.
 	Addr string `json:"addr"`
+
+	// AcctAddrs are the addresses generated from other accounts, keyed by
+	// account name.
+	AcctAddrs map[string]string `json:"acct_addrs,omitempty"`
+}
+
+// addr returns the address of the account.
+func (oa *onchainAddr) addr(acct string) string {
+	if acct == "" {
+		return oa.Addr
+	}
+	return oa.AcctAddrs[acct]
+}
+
+// hasAddr returns true if addr is the address of any of the accounts.
+func (oa *onchainAddr) hasAddr(addr string) bool {
+	if oa.Addr == addr {
+		return true
+	}
+	for _, v := range oa.AcctAddrs {
+		if v == addr {
+			return true
+		}
+	}
+	return false
 }
 
-// OnchainRecvAddrForUser returns the onchain address for an user or an empty
+// OnchainRecvAddrForUser returns the onchain address for an user generated from
+// the specified account (or the default account if acct is empty) or an empty
 // string if a valid address does not exist.
-func (db *DB) OnchainRecvAddrForUser(tx ReadTx, uid UserID) (string, error) {
+func (db *DB) OnchainRecvAddrForUser(tx ReadTx, uid UserID, acct string) (string, error) {
 	filename := filepath.Join(db.root, inboundDir, uid.String(), recvAddrForUserFile)
 	var jsonAddr onchainAddr
 	err := db.readJsonFile(filename, &jsonAddr)
@@ -22,26 +49,45 @@ func (db *DB) OnchainRecvAddrForUser(tx ReadTx, uid UserID) (string, error) {
 		return "", err
 	}
 
-	return jsonAddr.Addr, nil
+	return jsonAddr.addr(acct), nil
 }
 
 // UpdateOnchainRecvAddrForUser updates the on-chain address of the local node
-// for receiving payments for the specified user. If addr is an empty string,
-// then this removes the existing address.
-func (db *DB) UpdateOnchainRecvAddrForUser(tx ReadWriteTx, uid UserID, addr string) error {
+// generated from the specified account (or the default account if acct is
+// empty) for receiving payments for the specified user. If addr is an empty
+// string, then this removes the existing address.
+func (db *DB) UpdateOnchainRecvAddrForUser(tx ReadWriteTx, uid UserID, acct, addr string) error {
 	filename := filepath.Join(db.root, inboundDir, uid.String(), recvAddrForUserFile)
-	if addr == "" {
+	var jsonAddr onchainAddr
+	err := db.readJsonFile(filename, &jsonAddr)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	switch {
+	case acct == "":
+		jsonAddr.Addr = addr
+	case addr == "":
+		delete(jsonAddr.AcctAddrs, acct)
+	default:
+		if jsonAddr.AcctAddrs == nil {
+			jsonAddr.AcctAddrs = make(map[string]string)
+		}
+		jsonAddr.AcctAddrs[acct] = addr
+	}
+
+	if jsonAddr.Addr == "" && len(jsonAddr.AcctAddrs) == 0 {
 		err := os.Remove(filename)
 		if err == nil || os.IsNotExist(err) {
 			return nil
 		}
+		return err
 	}
-	jsonAddr := onchainAddr{Addr: addr}
 	return db.saveJsonFile(filename, jsonAddr)
 }
 
 // UserWithOnchainRecvAddr returns the user id associated with the given
-// receive address or nil if no such id exists.
+// receive address (generated from any account) or nil if no such id exists.
 func (db *DB) UserWithOnchainRecvAddr(tx ReadTx, addr string) *UserID {
 	fi, err := os.ReadDir(filepath.Join(db.root, inboundDir))
 	if err != nil {
@@ -65,7 +111,7 @@ func (db *DB) UserWithOnchainRecvAddr(tx ReadTx, addr string) *UserID {
 			continue
 		}
 
-		if jsonAddr.Addr == addr {
+		if jsonAddr.hasAddr(addr) {
 			return &uid
 		}
 	}
@@ -0,0 +1,79 @@
+package clientdb
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// PaymentAuditDecision is the decision recorded for an outgoing payment in the
+// payment audit log.
+type PaymentAuditDecision string
+
+const (
+	// PaymentAuditBelowThreshold is recorded for payments that did not
+	// require approval.
+	PaymentAuditBelowThreshold PaymentAuditDecision = "below-threshold"
+
+	PaymentAuditApproved PaymentAuditDecision = "approved"
+	PaymentAuditDenied   PaymentAuditDecision = "denied"
+
+	// PaymentAuditCanceled is recorded for payments that were waiting for
+	// approval when the client was shutdown.
+	PaymentAuditCanceled PaymentAuditDecision = "canceled"
+)
+
+// PaymentAuditEntry is an entry of the audit log of outgoing payments.
+type PaymentAuditEntry struct {
+	ApprovalID uint64                     `json:"approval_id,omitempty"`
+	Category   clientintf.PaymentCategory `json:"category"`
+	UID        *UserID                    `json:"uid,omitempty"`
+
+	// Invoice is empty for payments without an invoice (keysend).
+	Invoice string `json:"invoice,omitempty"`
+	MAtoms  int64  `json:"matoms"`
+
+	Requested time.Time            `json:"requested"`
+	Decided   time.Time            `json:"decided"`
+	Decision  PaymentAuditDecision `json:"decision"`
+	Reason    string               `json:"reason,omitempty"`
+}
+
+// AppendPaymentAudit appends the entry to the audit log of outgoing payments.
+func (db *DB) AppendPaymentAudit(tx ReadWriteTx, entry *PaymentAuditEntry) error {
+	fname := filepath.Join(db.root, paymentAuditFile)
+	return db.appendToJsonFile(fname, entry)
+}
+
+// ListPaymentAudit lists the entries of the audit log of outgoing payments,
+// from the oldest one.
+func (db *DB) ListPaymentAudit(tx ReadTx) ([]PaymentAuditEntry, error) {
+	fname := filepath.Join(db.root, paymentAuditFile)
+	f, err := os.Open(fname)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []PaymentAuditEntry
+	dec := json.NewDecoder(f)
+	for {
+		var entry PaymentAuditEntry
+		err := dec.Decode(&entry)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, entry)
+	}
+	return res, nil
+}
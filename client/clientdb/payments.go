@@ -1,13 +1,16 @@
 package clientdb
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 )
 
@@ -186,15 +189,81 @@ func (db *DB) ListOldestValidTipUserAttempts(tx ReadTx, maxLifetime time.Duratio
 	return res, nil
 }
 
+// StorePaymentProof stores the payment proof of a payment made between the
+// local client and the specified remote user (either as payer or payee).
+// Proofs for already stored payments are ignored. Once the max number of
+// proofs is reached, the oldest ones are removed.
+func (db *DB) StorePaymentProof(tx ReadWriteTx, uid UserID, proof rpc.PaymentProof) error {
+	proofs, err := db.ListPaymentProofs(tx, uid)
+	if err != nil {
+		return err
+	}
+	for i := range proofs {
+		if bytes.Equal(proofs[i].PaymentHash, proof.PaymentHash) {
+			return nil
+		}
+	}
+
+	fname := filepath.Join(db.root, inboundDir, uid.String(), paymentProofsFile)
+	maxProofs := db.cfg.MaxPaymentProofs
+	if maxProofs <= 0 {
+		maxProofs = defaultMaxPaymentProofs
+	}
+	if len(proofs) < maxProofs {
+		return db.appendToJsonFile(fname, proof)
+	}
+
+	// Rewrite the file without the oldest proofs.
+	proofs = append(proofs[len(proofs)-maxProofs+1:], proof)
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for i := range proofs {
+		if err := enc.Encode(proofs[i]); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// ListPaymentProofs lists the payment proofs of payments made between the local
+// client and the specified remote user.
+func (db *DB) ListPaymentProofs(tx ReadTx, uid UserID) ([]rpc.PaymentProof, error) {
+	fname := filepath.Join(db.root, inboundDir, uid.String(), paymentProofsFile)
+	f, err := os.Open(fname)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []rpc.PaymentProof
+	dec := json.NewDecoder(f)
+	for {
+		var proof rpc.PaymentProof
+		err := dec.Decode(&proof)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, proof)
+	}
+	return res, nil
+}
+
 // StoreGeneratedTipInvoice stores the specified invoice as one generated for
 // the remote client to pay the local client for a tip.
-func (db *DB) StoreGeneratedTipInvoice(tx ReadWriteTx, uid UserID, invoice string, amountMAtoms int64) error {
-	fname := filepath.Join(db.root, inboundDir, uid.String(), genTipInvoicesFile)
-	data := GeneratedInvoiceForTip{
-		UID:        uid,
-		Created:    time.Now(),
-		Invoice:    invoice,
-		MilliAtoms: uint64(amountMAtoms),
+func (db *DB) StoreGeneratedTipInvoice(tx ReadWriteTx, data *GeneratedInvoiceForTip) error {
+	fname := filepath.Join(db.root, inboundDir, data.UID.String(), genTipInvoicesFile)
+	if data.Created.IsZero() {
+		data.Created = time.Now()
 	}
 	return db.appendToJsonFile(fname, data)
 }
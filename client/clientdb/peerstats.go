@@ -0,0 +1,53 @@
+package clientdb
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// PeerStats are the stats of the replies of a remote user to the requests
+// sent by the local client (handshakes, invoice and resource requests), used
+// to score the latency and reliability of the user.
+type PeerStats struct {
+	// Pending are the times the requests still awaiting a reply were
+	// sent, keyed by request.
+	Pending map[string]time.Time `json:"pending,omitempty"`
+
+	// Replies and Missed are the number of requests that were replied
+	// and that were not replied in time.
+	Replies uint64 `json:"replies"`
+	Missed  uint64 `json:"missed"`
+
+	// MissedStreak is the number of consecutive requests that were not
+	// replied in time, the first of which was sent at StreakStart.
+	MissedStreak uint32    `json:"missed_streak,omitempty"`
+	StreakStart  time.Time `json:"streak_start,omitempty"`
+
+	// Latency is the moving average of the time between sending a
+	// request and the reply being received by the server.
+	Latency time.Duration `json:"latency"`
+
+	// Reliability is the moving average of the ratio of replied
+	// requests.
+	Reliability float64 `json:"reliability"`
+
+	// LastReply is when the last reply was received.
+	LastReply time.Time `json:"last_reply,omitempty"`
+}
+
+// GetPeerStats returns the reply stats of the remote user. Returns ErrNotFound
+// if no request was sent to the user yet.
+func (db *DB) GetPeerStats(tx ReadTx, uid UserID) (*PeerStats, error) {
+	fname := filepath.Join(db.root, inboundDir, uid.String(), peerStatsFile)
+	var ps PeerStats
+	if err := db.readJsonFile(fname, &ps); err != nil {
+		return nil, err
+	}
+	return &ps, nil
+}
+
+// UpdatePeerStats updates the reply stats of the remote user.
+func (db *DB) UpdatePeerStats(tx ReadWriteTx, uid UserID, ps *PeerStats) error {
+	fname := filepath.Join(db.root, inboundDir, uid.String(), peerStatsFile)
+	return db.saveJsonFile(fname, ps)
+}
@@ -0,0 +1,34 @@
+package clientdb
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// PostAnnounceConfig configures the automatic announcement of the posts
+// published by the local client in GCs.
+type PostAnnounceConfig struct {
+	// GCs are the GCs where all new posts are announced.
+	GCs []zkidentity.ShortID `json:"gcs,omitempty"`
+}
+
+// GetPostAnnounceConfig returns the config of the announcement of new posts in
+// GCs. Returns an empty config if none was set.
+func (db *DB) GetPostAnnounceConfig(tx ReadTx) (*PostAnnounceConfig, error) {
+	fname := filepath.Join(db.root, postsDir, postsAnnounceFile)
+	var cfg PostAnnounceConfig
+	err := db.readJsonFile(fname, &cfg)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// SetPostAnnounceConfig sets the config of the announcement of new posts in
+// GCs.
+func (db *DB) SetPostAnnounceConfig(tx ReadWriteTx, cfg *PostAnnounceConfig) error {
+	fname := filepath.Join(db.root, postsDir, postsAnnounceFile)
+	return db.saveJsonFile(fname, cfg)
+}
@@ -0,0 +1,97 @@
+package clientdb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// Referral tracks an invite created by the local client and the user that
+// joined (i.e. completed KX) through it. Referrals are local only data and are
+// never sent to remote users.
+type Referral struct {
+	InitialRV zkidentity.ShortID `json:"initial_rv"`
+	Label     string             `json:"label"`
+	Created   time.Time          `json:"created"`
+
+	// Joined is the user that completed KX through the invite. It is nil
+	// while the invite has not been accepted.
+	Joined   *UserID    `json:"joined,omitempty"`
+	JoinedTS *time.Time `json:"joined_ts,omitempty"`
+}
+
+// AddReferral starts tracking the referral of the invite with the given
+// initial RV.
+func (db *DB) AddReferral(tx ReadWriteTx, r *Referral) error {
+	fname := filepath.Join(db.root, referralsDir, r.InitialRV.String())
+	return db.saveJsonFile(fname, r)
+}
+
+// MarkReferralJoined records that the given user completed KX through the
+// invite with the given initial RV. Returns ErrNotFound if the invite is not
+// tracked as a referral.
+func (db *DB) MarkReferralJoined(tx ReadWriteTx, initialRV zkidentity.ShortID,
+	uid UserID) (*Referral, error) {
+
+	fname := filepath.Join(db.root, referralsDir, initialRV.String())
+	var r Referral
+	if err := db.readJsonFile(fname, &r); err != nil {
+		return nil, err
+	}
+	if r.Joined != nil {
+		return nil, fmt.Errorf("referral %s already joined by %s",
+			initialRV, r.Joined)
+	}
+	now := time.Now()
+	r.Joined, r.JoinedTS = &uid, &now
+	if err := db.saveJsonFile(fname, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListReferrals lists all tracked referrals, sorted by creation time.
+func (db *DB) ListReferrals(tx ReadTx) ([]Referral, error) {
+	dir := filepath.Join(db.root, referralsDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Referral, 0, len(entries))
+	for _, e := range entries {
+		var r Referral
+		err := db.readJsonFile(filepath.Join(dir, e.Name()), &r)
+		if err != nil {
+			db.log.Warnf("Unable to read referral %s: %v", e.Name(), err)
+			continue
+		}
+		res = append(res, r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+	return res, nil
+}
+
+// UserReferral returns the referral through which the given user joined.
+// Returns ErrNotFound if the user did not join through a tracked referral.
+func (db *DB) UserReferral(tx ReadTx, uid UserID) (*Referral, error) {
+	referrals, err := db.ListReferrals(tx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range referrals {
+		if referrals[i].Joined != nil && *referrals[i].Joined == uid {
+			return &referrals[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
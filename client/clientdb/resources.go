@@ -63,9 +63,9 @@ func (db *DB) StoreResourceRequest(tx ReadWriteTx, uid UserID,
 	return nil
 }
 
-// readResourceRequest returns the resource request corresponding to the
+// ReadResourceRequest returns the resource request corresponding to the
 // specified tag.
-func (db *DB) readResourceRequest(tx ReadTx, uid UserID,
+func (db *DB) ReadResourceRequest(tx ReadTx, uid UserID,
 	tag rpc.ResourceTag) (ResourceRequest, error) {
 
 	dir := filepath.Join(db.root, inboundDir, uid.String(), reqResourcesDir)
@@ -75,6 +75,16 @@ func (db *DB) readResourceRequest(tx ReadTx, uid UserID,
 	return res, err
 }
 
+// HasResourceRequest returns true if there is an outstanding request sent to
+// the user with the specified tag.
+func (db *DB) HasResourceRequest(tx ReadTx, uid UserID, tag rpc.ResourceTag) (bool, error) {
+	_, err := db.ReadResourceRequest(tx, uid, tag)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 // removeResourceRequest deletes the request with the corresponding tag.
 func (db *DB) removeResourceRequest(tx ReadWriteTx, uid UserID, tag rpc.ResourceTag) error {
 	dir := filepath.Join(db.root, inboundDir, uid.String(), reqResourcesDir)
@@ -102,15 +112,16 @@ func (db *DB) saveResourcesSessionOverview(sessID clientintf.PagesSessionID, ove
 }
 
 // StoreFetchedResource removes an existing request sent to the specified
-// user with the tag, and stores the resulting fetched response.
+// user with the tag, and stores the resulting fetched response along with the
+// result of verifying its signatures.
 func (db *DB) StoreFetchedResource(tx ReadWriteTx, uid UserID, tag rpc.ResourceTag,
-	reply rpc.RMFetchResourceReply) (FetchedResource, PageSessionOverview, error) {
+	reply rpc.RMFetchResourceReply, verif ResourceVerification) (FetchedResource, PageSessionOverview, error) {
 
 	var fr FetchedResource
 	var sess PageSessionOverview
 
 	// Double check request exists.
-	req, err := db.readResourceRequest(tx, uid, tag)
+	req, err := db.ReadResourceRequest(tx, uid, tag)
 	if err != nil {
 		return fr, sess, err
 	}
@@ -132,6 +143,8 @@ func (db *DB) StoreFetchedResource(tx ReadWriteTx, uid UserID, tag rpc.ResourceT
 		ResponseTS: time.Now(),
 		Request:    req.Request,
 		Response:   reply,
+
+		ResourceVerification: verif,
 	}
 
 	fname := filepath.Join(sessionDir, pageFnamePattern.FilenameFor(pageID))
@@ -0,0 +1,87 @@
+package clientdb
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// SavedQuery is a named query over the local data of the client (for
+// example, the logged messages or the orders of the store).
+type SavedQuery struct {
+	Name string `json:"name"`
+
+	// Kind is the kind of data queried, which determines the fields of
+	// the terms.
+	Kind string `json:"kind"`
+
+	// Terms are the terms of the query, in the <field>=<value> format.
+	Terms []string `json:"terms"`
+
+	Created time.Time `json:"created"`
+}
+
+// readSavedQueries reads the saved queries, keyed by name.
+func (db *DB) readSavedQueries() (map[string]SavedQuery, error) {
+	fname := filepath.Join(db.root, savedQueriesFile)
+	queries := make(map[string]SavedQuery)
+	err := db.readJsonFile(fname, &queries)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return queries, nil
+}
+
+// ListSavedQueries returns the saved queries, sorted by name.
+func (db *DB) ListSavedQueries(tx ReadTx) ([]SavedQuery, error) {
+	queries, err := db.readSavedQueries()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]SavedQuery, 0, len(queries))
+	for _, q := range queries {
+		res = append(res, q)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+// GetSavedQuery returns the saved query with the given name. Returns
+// ErrNotFound if there is no such query.
+func (db *DB) GetSavedQuery(tx ReadTx, name string) (*SavedQuery, error) {
+	queries, err := db.readSavedQueries()
+	if err != nil {
+		return nil, err
+	}
+	q, ok := queries[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &q, nil
+}
+
+// StoreSavedQuery stores the query, replacing any existing query with the
+// same name.
+func (db *DB) StoreSavedQuery(tx ReadWriteTx, q *SavedQuery) error {
+	queries, err := db.readSavedQueries()
+	if err != nil {
+		return err
+	}
+	queries[q.Name] = *q
+	return db.saveJsonFile(filepath.Join(db.root, savedQueriesFile), queries)
+}
+
+// RemoveSavedQuery removes the saved query with the given name. Returns
+// ErrNotFound if there is no such query.
+func (db *DB) RemoveSavedQuery(tx ReadWriteTx, name string) error {
+	queries, err := db.readSavedQueries()
+	if err != nil {
+		return err
+	}
+	if _, ok := queries[name]; !ok {
+		return ErrNotFound
+	}
+	delete(queries, name)
+	return db.saveJsonFile(filepath.Join(db.root, savedQueriesFile), queries)
+}
@@ -35,6 +35,52 @@ func (db *DB) AddToSendQueue(tx ReadWriteTx, typ string, dests []UserID,
 	return id, db.saveJsonFile(fname, el)
 }
 
+// AddBatchToSendQueue creates the send queue elements of a batch of msgs that
+// are sent as a unit. Either all elements are created or none of them are.
+// The ID of the batch is returned along with the ID of each element.
+func (db *DB) AddBatchToSendQueue(tx ReadWriteTx, els []SendQueueElement) (SendQID, []SendQID, error) {
+	dir := filepath.Join(db.root, sendqDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return SendQID{}, nil, err
+	}
+
+	ids := make([]SendQID, 0, len(els))
+	var batch SendQID
+	var err error
+	for i := range els {
+		var id SendQID
+		id, err = db.randomIDInDir(dir)
+		if err != nil {
+			break
+		}
+		if i == 0 {
+			batch = id
+		}
+
+		el := els[i]
+		el.ID = id
+		el.Batch = &batch
+		el.BatchIndex = i
+		fname := filepath.Join(dir, id.String())
+		if err = db.saveJsonFile(fname, el); err != nil {
+			break
+		}
+		ids = append(ids, id)
+	}
+	if err != nil {
+		// Remove the elements already created.
+		for _, id := range ids {
+			fname := filepath.Join(dir, id.String())
+			if rmErr := os.Remove(fname); rmErr != nil {
+				db.log.Warnf("Unable to remove sendq file %s of "+
+					"failed batch: %v", fname, rmErr)
+			}
+		}
+		return SendQID{}, nil, err
+	}
+	return batch, ids, nil
+}
+
 // RemoveFromSendQueue marks the given destination as sent on the specified
 // queue.  If the queue is now empty, it is removed from the db.
 func (db *DB) RemoveFromSendQueue(tx ReadWriteTx, id SendQID, dest UserID) error {
@@ -75,6 +121,29 @@ func (ssq *sortableSendQ) Swap(i, j int) {
 	ssq.times[i], ssq.times[j] = ssq.times[j], ssq.times[i]
 }
 
+// sortSendQBatches sorts the elements of each batch in q by their position in
+// the batch, keeping the batch at the position of its first element.
+func sortSendQBatches(q []SendQueueElement) {
+	batches := make(map[SendQID][]int)
+	for i := range q {
+		if q[i].Batch != nil {
+			batches[*q[i].Batch] = append(batches[*q[i].Batch], i)
+		}
+	}
+	for _, idxs := range batches {
+		els := make([]SendQueueElement, len(idxs))
+		for i, idx := range idxs {
+			els[i] = q[idx]
+		}
+		sort.SliceStable(els, func(i, j int) bool {
+			return els[i].BatchIndex < els[j].BatchIndex
+		})
+		for i, idx := range idxs {
+			q[idx] = els[i]
+		}
+	}
+}
+
 // ListSendQueue lists all send queues registered.
 func (db *DB) ListSendQueue(tx ReadTx) ([]SendQueueElement, error) {
 	dir := filepath.Join(db.root, sendqDir)
@@ -127,9 +196,11 @@ func (db *DB) ListSendQueue(tx ReadTx) ([]SendQueueElement, error) {
 		times = append(times, finfo.ModTime())
 	}
 
-	// Sort by mod time.
+	// Sort by mod time. Elements of a batch are sorted by their position
+	// in the batch.
 	ssq := &sortableSendQ{q: res, times: times}
 	sort.Sort(ssq)
+	sortSendQBatches(ssq.q)
 
 	return ssq.q, nil
 }
@@ -184,3 +255,11 @@ func (db *DB) ListUnackedUserRMs(tx ReadTx) ([]UnackedRM, error) {
 
 	return res, nil
 }
+
+// CountUnackedUserRMs returns the number of unacked RMs of all users, without
+// reading them.
+func (db *DB) CountUnackedUserRMs(tx ReadTx) (int, error) {
+	pattern := filepath.Join(db.root, inboundDir, "*", unackedRMsDir, "*")
+	matches, err := filepath.Glob(pattern)
+	return len(matches), err
+}
@@ -0,0 +1,89 @@
+package clientdb
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+const (
+	postSummariesDir = "posts"
+	gcSummariesDir   = "gcs"
+)
+
+// ContentSummary is a short summary of long content (a post or the backlog of
+// messages of a GC), produced by a summarization provider.
+type ContentSummary struct {
+	// Summary is the text of the summary.
+	Summary string `json:"summary"`
+
+	// Summarizer is the name of the provider that produced the summary.
+	Summarizer string `json:"summarizer"`
+
+	// Created is when the summary was produced.
+	Created time.Time `json:"created"`
+
+	// SourceLen is the length (in bytes) of the summarized content.
+	SourceLen int `json:"source_len"`
+}
+
+// GCBacklogSummary is a summary of the latest messages of a GC.
+type GCBacklogSummary struct {
+	ContentSummary
+
+	GC zkidentity.ShortID `json:"gc"`
+
+	// Messages is the number of summarized messages, sent in the [Since,
+	// Until] period.
+	Messages int       `json:"messages"`
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+}
+
+// StorePostSummary stores the summary of the given post.
+func (db *DB) StorePostSummary(tx ReadWriteTx, from UserID, pid PostID, summ *ContentSummary) error {
+	fname := filepath.Join(db.root, summariesDir, postSummariesDir,
+		from.String(), pid.String())
+	return db.saveJsonFile(fname, summ)
+}
+
+// ReadPostSummary returns the stored summary of the given post.
+func (db *DB) ReadPostSummary(tx ReadTx, from UserID, pid PostID) (*ContentSummary, error) {
+	fname := filepath.Join(db.root, summariesDir, postSummariesDir,
+		from.String(), pid.String())
+	var summ ContentSummary
+	err := db.readJsonFile(fname, &summ)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("summary of post %s: %w", pid, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summ, nil
+}
+
+// StoreGCBacklogSummary stores the summary of the backlog of a GC, replacing
+// any previous summary of the GC.
+func (db *DB) StoreGCBacklogSummary(tx ReadWriteTx, summ *GCBacklogSummary) error {
+	fname := filepath.Join(db.root, summariesDir, gcSummariesDir,
+		summ.GC.String())
+	return db.saveJsonFile(fname, summ)
+}
+
+// ReadGCBacklogSummary returns the latest summary of the backlog of a GC.
+func (db *DB) ReadGCBacklogSummary(tx ReadTx, gcID zkidentity.ShortID) (*GCBacklogSummary, error) {
+	fname := filepath.Join(db.root, summariesDir, gcSummariesDir,
+		gcID.String())
+	var summ GCBacklogSummary
+	err := db.readJsonFile(fname, &summ)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("summary of GC %s: %w", gcID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summ, nil
+}
@@ -0,0 +1,109 @@
+package clientdb
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+const (
+	tipStatsConfigFile = "config.json"
+	receivedTipsFile   = "received.json"
+)
+
+// TipStatsConfig configures the aggregation of the tips received by the local
+// client and the automatic acknowledgment of the tips.
+type TipStatsConfig struct {
+	// Enabled is set to record the tips received.
+	Enabled bool `json:"enabled"`
+
+	// AckInterval is the interval between automatic thank-you summaries of
+	// the received tips. Zero disables the automatic summaries.
+	AckInterval time.Duration `json:"ack_interval,omitempty"`
+
+	// AckPost is set to publish the automatic summaries as posts.
+	AckPost bool `json:"ack_post,omitempty"`
+
+	// AckGCs are the GCs where the automatic summaries are sent.
+	AckGCs []zkidentity.ShortID `json:"ack_gcs,omitempty"`
+
+	// AckNamed is set to name (instead of anonymizing) the tippers that
+	// consented to it in the summaries.
+	AckNamed bool `json:"ack_named,omitempty"`
+
+	// LastAck is the end of the period of the last automatic summary.
+	LastAck time.Time `json:"last_ack,omitempty"`
+}
+
+// ReceivedTip is a tip received by the local client.
+type ReceivedTip struct {
+	UID        UserID    `json:"uid"`
+	MilliAtoms int64     `json:"milli_atoms"`
+	Timestamp  time.Time `json:"timestamp"`
+
+	// Post is the post that was tipped, if any.
+	Post *PostID `json:"post,omitempty"`
+
+	// Public is set if the tipper consented to being named in the
+	// acknowledgments of tips.
+	Public bool `json:"public,omitempty"`
+}
+
+// GetTipStatsConfig returns the config of the tip stats. Returns an empty
+// (disabled) config if none was set.
+func (db *DB) GetTipStatsConfig(tx ReadTx) (*TipStatsConfig, error) {
+	fname := filepath.Join(db.root, tipStatsDir, tipStatsConfigFile)
+	var cfg TipStatsConfig
+	err := db.readJsonFile(fname, &cfg)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// SetTipStatsConfig sets the config of the tip stats.
+func (db *DB) SetTipStatsConfig(tx ReadWriteTx, cfg *TipStatsConfig) error {
+	fname := filepath.Join(db.root, tipStatsDir, tipStatsConfigFile)
+	return db.saveJsonFile(fname, cfg)
+}
+
+// RecordReceivedTip records a tip received by the local client.
+func (db *DB) RecordReceivedTip(tx ReadWriteTx, tip *ReceivedTip) error {
+	fname := filepath.Join(db.root, tipStatsDir, receivedTipsFile)
+	return db.appendToJsonFile(fname, tip)
+}
+
+// ListReceivedTips lists the recorded tips received in the [since, until)
+// interval. A zero until lists all tips received after since.
+func (db *DB) ListReceivedTips(tx ReadTx, since, until time.Time) ([]ReceivedTip, error) {
+	fname := filepath.Join(db.root, tipStatsDir, receivedTipsFile)
+	f, err := os.Open(fname)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []ReceivedTip
+	dec := json.NewDecoder(f)
+	for {
+		var tip ReceivedTip
+		if err := dec.Decode(&tip); err != nil {
+			break
+		}
+		if tip.Timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !tip.Timestamp.Before(until) {
+			continue
+		}
+		res = append(res, tip)
+	}
+	return res, nil
+}
@@ -116,9 +116,33 @@ func (db *DB) randomIDInDir(dir string) (clientintf.ID, error) {
 // saveJsonFile saves the data to a temp file, then renames the temp file to
 // the passed filename.
 func (db *DB) saveJsonFile(fname string, data interface{}) error {
+	db.uncacheFile(fname)
 	return jsonfile.Write(fname, data, db.log)
 }
 
+// readCachedFile reads the contents of the file, using the file cache when it
+// is enabled. Files read with this MUST only be modified through
+// saveJsonFile or be removed from the cache with uncacheFile.
+func (db *DB) readCachedFile(fname string) ([]byte, error) {
+	if db.files != nil {
+		if b, ok := db.files.Get(fname); ok {
+			return b, nil
+		}
+	}
+	b, err := os.ReadFile(fname)
+	if err == nil && db.files != nil {
+		db.files.Put(fname, b, int64(len(b)))
+	}
+	return b, err
+}
+
+// uncacheFile removes the file from the file cache.
+func (db *DB) uncacheFile(fname string) {
+	if db.files != nil {
+		db.files.Delete(fname)
+	}
+}
+
 // dirExistsEmpty returs true if the given dir exists and is empty.
 func dirExistsEmpty(dir string) bool {
 	f, err := os.Open(dir)
@@ -0,0 +1,101 @@
+package clientintf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind is the kind of an error returned by the client API. Errors
+// returned by client calls that fall in one of the kinds below match the
+// corresponding ErrorKind with errors.Is(), which allows callers to branch on
+// the errors without parsing their messages.
+type ErrorKind string
+
+func (kind ErrorKind) Error() string {
+	return string(kind)
+}
+
+const (
+	// ErrPeerOffline is the kind of errors of actions that could not be
+	// completed because the client is not connected to the server (through
+	// which all remote peers are reached).
+	ErrPeerOffline ErrorKind = "peer is offline"
+
+	// ErrInsufficientFunds is the kind of errors of actions that could not
+	// be completed due to a lack of funds.
+	ErrInsufficientFunds ErrorKind = "insufficient funds"
+
+	// ErrRateLimited is the kind of errors of requests that were refused
+	// because the requester exceeded its rate limit.
+	ErrRateLimited ErrorKind = "rate limited"
+
+	// ErrResourceNotFound is the kind of errors of requests for resources
+	// that do not exist or have no provider.
+	ErrResourceNotFound ErrorKind = "resource not found"
+
+	// ErrUserNotFound is the kind of errors of actions that reference an
+	// unknown remote user.
+	ErrUserNotFound ErrorKind = "user not found"
+
+	// ErrGCNotFound is the kind of errors of actions that reference an
+	// unknown group chat.
+	ErrGCNotFound ErrorKind = "group chat not found"
+
+	// ErrUserBlocked is the kind of errors of actions that were refused
+	// because the remote user is blocked.
+	ErrUserBlocked ErrorKind = "user is blocked"
+
+	// ErrAlreadyExists is the kind of errors of actions that would create
+	// an entity that already exists.
+	ErrAlreadyExists ErrorKind = "already exists"
+)
+
+// ErrorKinds are all the kinds of errors returned by the client API.
+var ErrorKinds = []ErrorKind{
+	ErrPeerOffline,
+	ErrInsufficientFunds,
+	ErrRateLimited,
+	ErrResourceNotFound,
+	ErrUserNotFound,
+	ErrGCNotFound,
+	ErrUserBlocked,
+	ErrAlreadyExists,
+}
+
+// kindError is an error of a specific kind.
+type kindError struct {
+	kind ErrorKind
+	err  error
+}
+
+func (err kindError) Error() string {
+	return err.err.Error()
+}
+
+func (err kindError) Unwrap() error {
+	return err.err
+}
+
+func (err kindError) Is(target error) bool {
+	return target == err.kind
+}
+
+// KindErrorf returns an error formatted as with fmt.Errorf() that matches the
+// given kind with errors.Is().
+func KindErrorf(kind ErrorKind, format string, args ...interface{}) error {
+	return kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// ErrorKindOf returns the kind of the error or an empty kind if the error is
+// not of any of the kinds in ErrorKinds.
+func ErrorKindOf(err error) ErrorKind {
+	if err == nil {
+		return ""
+	}
+	for _, kind := range ErrorKinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package clientintf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestErrorKinds tests that kind errors match their kinds.
+func TestErrorKinds(t *testing.T) {
+	baseErr := errors.New("base error")
+	err := KindErrorf(ErrPeerOffline, "unable to send: %w", baseErr)
+	if err.Error() != "unable to send: base error" {
+		t.Fatalf("unexpected error message %q", err.Error())
+	}
+	if !errors.Is(err, ErrPeerOffline) {
+		t.Fatalf("error does not match its kind")
+	}
+	if errors.Is(err, ErrRateLimited) {
+		t.Fatalf("error matches unrelated kind")
+	}
+	if !errors.Is(err, baseErr) {
+		t.Fatalf("error does not unwrap to base error")
+	}
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	if kind := ErrorKindOf(wrapped); kind != ErrPeerOffline {
+		t.Fatalf("unexpected kind: got %q, want %q", kind, ErrPeerOffline)
+	}
+	if kind := ErrorKindOf(ErrUserBlocked); kind != ErrUserBlocked {
+		t.Fatalf("unexpected kind: got %q, want %q", kind, ErrUserBlocked)
+	}
+	if kind := ErrorKindOf(baseErr); kind != "" {
+		t.Fatalf("unexpected kind of plain error: %q", kind)
+	}
+	if !errors.Is(ErrOnboardInsufficientFunds, ErrInsufficientFunds) {
+		t.Fatalf("onboarding error does not match insufficient funds")
+	}
+}
@@ -0,0 +1,193 @@
+package clientintf
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/sw"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/decred/dcrd/bech32"
+)
+
+const (
+	// guestViewTokenHRP is the human readable part of encoded guest view
+	// tokens.
+	guestViewTokenHRP = "brgv"
+
+	// GuestViewPathPrefix is the first element of the path of the pages
+	// published for guest views.
+	GuestViewPathPrefix = "guestview"
+
+	guestViewTokenLen = 32 + 32 + 16 + 32 + 8
+)
+
+// GuestViewID is the ID of a guest view.
+type GuestViewID [16]byte
+
+// String returns the hex encoding of the id.
+func (id GuestViewID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// FromString decodes the id from its hex encoding.
+func (id *GuestViewID) FromString(s string) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(id) {
+		return fmt.Errorf("invalid guest view id length %d", len(b))
+	}
+	copy(id[:], b)
+	return nil
+}
+
+// GuestViewToken is a shareable credential that grants read-only access to
+// pages of a user, without requiring the guest to KX with the user. The pages
+// are published to the server encrypted with the key of the token and signed
+// by the owner, so the server cannot read or forge them.
+type GuestViewToken struct {
+	// Owner and SigKey identify the user that published the pages.
+	Owner  UserID
+	SigKey zkidentity.FixedSizeEd25519PublicKey
+
+	ID      GuestViewID
+	Key     *[32]byte
+	Expires time.Time
+}
+
+// NewGuestViewToken generates a new token for pages of the given identity that
+// expires at the given time.
+func NewGuestViewToken(id *zkidentity.PublicIdentity, expires time.Time) *GuestViewToken {
+	token := &GuestViewToken{
+		Owner:   id.Identity,
+		SigKey:  id.SigKey,
+		Key:     new([32]byte),
+		Expires: expires,
+	}
+	if _, err := rand.Read(token.ID[:]); err != nil {
+		panic(fmt.Errorf("failed to generate random bytes: %v", err))
+	}
+	if _, err := rand.Read(token.Key[:]); err != nil {
+		panic(fmt.Errorf("failed to generate random bytes: %v", err))
+	}
+	return token
+}
+
+// Expired returns true if the token is expired.
+func (token *GuestViewToken) Expired() bool {
+	return time.Now().After(token.Expires)
+}
+
+// Identity returns a public identity of the owner that may be used to verify
+// the signature of the published pages.
+func (token *GuestViewToken) Identity() zkidentity.PublicIdentity {
+	return zkidentity.PublicIdentity{
+		Identity: token.Owner,
+		SigKey:   token.SigKey,
+	}
+}
+
+// PublishedPath returns the path where the given page is published.
+func (token *GuestViewToken) PublishedPath(page []string) []string {
+	path := make([]string, 0, len(page)+2)
+	path = append(path, GuestViewPathPrefix, token.ID.String())
+	return append(path, page...)
+}
+
+// Encrypt a page with the key of the token.
+func (token *GuestViewToken) Encrypt(page []byte) ([]byte, error) {
+	return sw.Seal(page, token.Key)
+}
+
+// Decrypt a page with the key of the token.
+func (token *GuestViewToken) Decrypt(box []byte) ([]byte, error) {
+	page, ok := sw.Open(box, token.Key)
+	if !ok {
+		return nil, fmt.Errorf("unable to decrypt page with guest view token")
+	}
+	return page, nil
+}
+
+// Encode the token as a string.
+func (token *GuestViewToken) Encode() (string, error) {
+	b := make([]byte, 0, guestViewTokenLen)
+	b = append(b, token.Owner[:]...)
+	b = append(b, token.SigKey[:]...)
+	b = append(b, token.ID[:]...)
+	b = append(b, token.Key[:]...)
+	var expires [8]byte
+	binary.BigEndian.PutUint64(expires[:], uint64(token.Expires.Unix()))
+	b = append(b, expires[:]...)
+	conv, err := bech32.ConvertBits(b, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(guestViewTokenHRP, conv)
+}
+
+// Decode the token from its string encoding.
+func (token *GuestViewToken) Decode(s string) error {
+	hrp, data, err := bech32.DecodeNoLimit(s)
+	if err != nil {
+		return fmt.Errorf("unable to decode guest view token: %v", err)
+	}
+	if hrp != guestViewTokenHRP {
+		return fmt.Errorf("hrp for string is not %s", guestViewTokenHRP)
+	}
+	b, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return fmt.Errorf("unable to decode guest view token: %v", err)
+	}
+	if len(b) != guestViewTokenLen {
+		return fmt.Errorf("incorrect length for decoded guest view "+
+			"token: %d != %d", len(b), guestViewTokenLen)
+	}
+	copy(token.Owner[:], b[0:32])
+	copy(token.SigKey[:], b[32:64])
+	copy(token.ID[:], b[64:80])
+	token.Key = new([32]byte)
+	copy(token.Key[:], b[80:112])
+	token.Expires = time.Unix(int64(binary.BigEndian.Uint64(b[112:])), 0)
+	return nil
+}
+
+// String returns the encoded token or an error string.
+func (token *GuestViewToken) String() string {
+	enc, err := token.Encode()
+	if err != nil {
+		return fmt.Sprintf("[invalid GuestViewToken: %v]", err)
+	}
+	return enc
+}
+
+// MarshalJSON marshals the token into a json string.
+func (token *GuestViewToken) MarshalJSON() ([]byte, error) {
+	s, err := token.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON unmarshals the json representation of a token.
+func (token *GuestViewToken) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return token.Decode(s)
+}
+
+// DecodeGuestViewToken decodes a given string as a GuestViewToken.
+func DecodeGuestViewToken(s string) (*GuestViewToken, error) {
+	token := new(GuestViewToken)
+	if err := token.Decode(s); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
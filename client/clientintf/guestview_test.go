@@ -0,0 +1,65 @@
+package clientintf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// TestGuestViewTokenEncoding tests that guest view tokens can be encoded and
+// decoded and that pages encrypted with a token can only be decrypted with
+// the same token.
+func TestGuestViewTokenEncoding(t *testing.T) {
+	id := &zkidentity.PublicIdentity{}
+	id.Identity[0] = 0x01
+	id.SigKey[0] = 0x02
+	expires := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	token := NewGuestViewToken(id, expires)
+
+	enc, err := token.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeGuestViewToken(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(token, decoded) {
+		t.Fatalf("unexpected decoded token: got %#v, want %#v", decoded, token)
+	}
+	if decoded.Expired() {
+		t.Fatalf("unexpected expired token")
+	}
+
+	page := []byte("# Index\n")
+	box, err := token.Encrypt(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decoded.Decrypt(box)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, page) {
+		t.Fatalf("unexpected decrypted page: got %q, want %q", got, page)
+	}
+
+	other := NewGuestViewToken(id, expires)
+	if _, err := other.Decrypt(box); err == nil {
+		t.Fatalf("unexpected decryption with a different token")
+	}
+
+	// Changing a char of the encoded token breaks its checksum.
+	bad := []byte(enc)
+	if bad[len(bad)-1] == 'q' {
+		bad[len(bad)-1] = 'p'
+	} else {
+		bad[len(bad)-1] = 'q'
+	}
+	if _, err := DecodeGuestViewToken(string(bad)); err == nil {
+		t.Fatalf("unexpected decoding of invalid token")
+	}
+}
@@ -55,6 +55,23 @@ type CertConfirmer func(context.Context, *tls.ConnectionState, *zkidentity.Publi
 type ServerPolicy struct {
 	PushPaymentLifetime time.Duration
 	MaxPushInvoices     int
+
+	// MaxBatchedRMs is the max number of RMs that may be pushed in a
+	// single batch. Batching is disabled when this is lower than 2.
+	MaxBatchedRMs int
+
+	// SubFilters is true if the server supports subscription filters and
+	// deferring pushes of large RMs.
+	SubFilters bool
+
+	// PushGateways are the push gateways the server may notify when new
+	// RMs are received while the client is offline.
+	PushGateways []string
+
+	// MaxPublishedResourceSize is the max size of resources that may be
+	// published to the server. Zero means the server does not host
+	// published resources.
+	MaxPublishedResourceSize int
 }
 
 // ServerSessionIntf is the interface available from serverSession to
@@ -67,6 +84,10 @@ type ServerSessionIntf interface {
 	ExpirationDays() int
 	Policy() ServerPolicy
 
+	// ClockSkew returns the offset of the server clock relative to the
+	// local clock, measured when the session was established.
+	ClockSkew() time.Duration
+
 	// Context returns a context that gets cancelled once this session stops
 	// running.
 	Context() context.Context
@@ -78,6 +99,10 @@ type DecodedInvoice struct {
 	ID         []byte
 	MAtoms     int64
 	ExpiryTime time.Time
+
+	// DestNode is the hex-encoded public key of the LN node that generated
+	// the invoice (if known).
+	DestNode string
 }
 
 // isExpired is similar to IsExpired, but with a parametrized nowFunc to allow
@@ -104,6 +129,91 @@ type PaymentClient interface {
 	IsInvoicePaid(context.Context, int64, string) error
 	TrackInvoice(context.Context, string, int64) (int64, error)
 	IsPaymentCompleted(context.Context, string) (int64, error)
+	EstimatePaymentFee(context.Context, string, int64) (int64, error)
+}
+
+// ReceivedKeysend is a spontaneous (keysend) payment received by the local
+// node.
+type ReceivedKeysend struct {
+	MAtoms        int64
+	CustomRecords map[uint64][]byte
+}
+
+// KeysendPaymentClient is implemented by payment clients that can send and
+// receive spontaneous (keysend) payments, which do not require the receiver to
+// generate an invoice. A negative maxFeeMAtoms means the default fee limit is
+// used.
+type KeysendPaymentClient interface {
+	PayKeysend(ctx context.Context, destNode string, amountMAtoms,
+		maxFeeMAtoms int64, records map[uint64][]byte) (int64, error)
+	TrackKeysendPayments(ctx context.Context, handler func(ReceivedKeysend)) error
+}
+
+// PaymentPreimageClient is implemented by payment clients that can return the
+// preimage of invoices paid by the local node, which is used as proof that the
+// payment was completed.
+type PaymentPreimageClient interface {
+	PaymentPreimage(ctx context.Context, invoice string) ([]byte, error)
+}
+
+// InvoiceLookupClient is implemented by payment clients that can look up the
+// invoices generated by the local node, which is used to verify the payment
+// proofs received from payers. SettledInvoiceMAtoms returns the amount paid to
+// the invoice with the given payment hash, or an error if there is no such
+// invoice or it was not settled.
+type InvoiceLookupClient interface {
+	SettledInvoiceMAtoms(ctx context.Context, paymentHash []byte) (int64, error)
+}
+
+// PaymentCategory is the category of an outgoing payment, used to select the
+// fee policy applied to it.
+type PaymentCategory string
+
+const (
+	PaymentCategoryTip      PaymentCategory = "tip"
+	PaymentCategoryDownload PaymentCategory = "download"
+	PaymentCategoryStore    PaymentCategory = "store"
+
+	// PaymentCategoryManual is the category of payments made directly
+	// through the LN wallet (for example, paying an invoice by hand).
+	PaymentCategoryManual PaymentCategory = "manual"
+
+	// PaymentCategoryOnChain is the category of on-chain payments made
+	// through the wallet.
+	PaymentCategoryOnChain PaymentCategory = "onchain"
+)
+
+// FeePolicy is the policy of max fees to pay when making a payment. When both
+// the absolute and the percentage limits are set, the lowest one is used.
+type FeePolicy struct {
+	// MaxFeeMAtoms is the max absolute fee (in milliatoms) to pay. Zero
+	// means no absolute limit.
+	MaxFeeMAtoms int64 `json:"max_fee_matoms"`
+
+	// MaxFeeRate is the max fee to pay, as a fraction of the amount being
+	// paid (for example, 0.01 is 1%). Zero means no percentage limit.
+	MaxFeeRate float64 `json:"max_fee_rate"`
+}
+
+// IsZero returns true if the policy does not set any limits.
+func (fp FeePolicy) IsZero() bool {
+	return fp.MaxFeeMAtoms <= 0 && fp.MaxFeeRate <= 0
+}
+
+// MaxFee returns the max fee to pay for a payment of the given amount. Returns
+// -1 if there is no limit.
+func (fp FeePolicy) MaxFee(amountMAtoms int64) int64 {
+	maxFee := int64(-1)
+	if fp.MaxFeeMAtoms > 0 {
+		maxFee = fp.MaxFeeMAtoms
+	}
+	if fp.MaxFeeRate > 0 {
+		rateFee := int64(float64(amountMAtoms) * fp.MaxFeeRate)
+		if maxFee < 0 || rateFee < maxFee {
+			maxFee = rateFee
+		}
+	}
+	return maxFee
 }
 
 // FreePaymentClient implements the PaymentClient interface for servers that
@@ -128,6 +238,9 @@ func (pc FreePaymentClient) IsPaymentCompleted(context.Context, string) (int64,
 func (pc FreePaymentClient) TrackInvoice(ctx context.Context, inv string, minMAtoms int64) (int64, error) {
 	return 0, nil
 }
+func (pc FreePaymentClient) EstimatePaymentFee(context.Context, string, int64) (int64, error) {
+	return 0, nil
+}
 
 // farFutureExpiryTime is a time far in the future for the expiration of free
 // invoices.
@@ -184,6 +297,9 @@ var (
 	ErrSubsysExiting             = errors.New("subsys exiting")
 	ErrInvoiceInsufficientlyPaid = errors.New("invoice insufficiently paid")
 	ErrInvoiceExpired            = errors.New("invoice expired")
-	ErrOnboardNoFunds            = errors.New("onboarding invite does not have any funds")
+	ErrOnboardNoFunds            = KindErrorf(ErrInsufficientFunds, "onboarding invite does not have any funds")
+	ErrOnboardInsufficientFunds  = KindErrorf(ErrInsufficientFunds, "onboarding invite does not have enough funds")
 	ErrRetriablePayment          = errors.New("retriable payment error")
+	ErrFeeLimitExceeded          = errors.New("estimated payment fee exceeds fee policy limit")
+	ErrPaymentNotApproved        = errors.New("payment not approved")
 )
@@ -69,3 +69,49 @@ func TestInvoiceIsExpired(t *testing.T) {
 		})
 	}
 }
+
+// TestFeePolicyMaxFee tests that the max fee of fee policies is correctly
+// determined.
+func TestFeePolicyMaxFee(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy FeePolicy
+		amount int64
+		want   int64
+	}{{
+		name:   "no limits",
+		amount: 1000,
+		want:   -1,
+	}, {
+		name:   "absolute limit",
+		policy: FeePolicy{MaxFeeMAtoms: 100},
+		amount: 1000,
+		want:   100,
+	}, {
+		name:   "percentage limit",
+		policy: FeePolicy{MaxFeeRate: 0.05},
+		amount: 1000,
+		want:   50,
+	}, {
+		name:   "absolute limit lower than percentage",
+		policy: FeePolicy{MaxFeeMAtoms: 20, MaxFeeRate: 0.05},
+		amount: 1000,
+		want:   20,
+	}, {
+		name:   "percentage limit lower than absolute",
+		policy: FeePolicy{MaxFeeMAtoms: 200, MaxFeeRate: 0.05},
+		amount: 1000,
+		want:   50,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.policy.MaxFee(tc.amount)
+			if got != tc.want {
+				t.Fatalf("unexpected max fee: got %d, want %d",
+					got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Package contactimport parses contact lists exported from other messengers
+// and address books, so that they can be imported as placeholders of users to
+// invite to BR.
+//
+// Contacts are read from CSV files. The first row is the header, which is used
+// to find the columns with the name and the identifiers (phone numbers, email
+// addresses, usernames, etc) of the contacts. The column names used by the
+// exports of the most common platforms (Google Contacts, Outlook, Signal,
+// Telegram, WhatsApp, etc) are recognized. Files without a recognized header
+// are read as having the name in the first column and identifiers in the
+// remaining ones.
+package contactimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// MaxContacts is the max number of contacts read from a single file.
+const MaxContacts = 100000
+
+// Contact is a contact read from an exported contact list.
+type Contact struct {
+	// Name is the name of the contact.
+	Name string
+
+	// Identifiers are the identifiers of the contact in the platform it
+	// was exported from (phone numbers, email addresses, usernames, etc).
+	Identifiers []string
+}
+
+// columnKind is the kind of data in a column of the CSV file.
+type columnKind int
+
+const (
+	colIgnored columnKind = iota
+	colName
+	colFirstName
+	colMiddleName
+	colLastName
+	colIdentifier
+)
+
+// nameColumns are the (normalized) names of the columns with the full name of
+// the contact.
+var nameColumns = map[string]columnKind{
+	"name":            colName,
+	"full name":       colName,
+	"display name":    colName,
+	"contact name":    colName,
+	"nickname":        colName,
+	"first name":      colFirstName,
+	"given name":      colFirstName,
+	"first_name":      colFirstName,
+	"middle name":     colMiddleName,
+	"additional name": colMiddleName,
+	"last name":       colLastName,
+	"family name":     colLastName,
+	"surname":         colLastName,
+	"last_name":       colLastName,
+}
+
+// identifierWords are words that, when present in the (normalized) name of a
+// column, mark it as a column with identifiers of the contact.
+var identifierWords = []string{"phone", "mobile", "e-mail", "email", "username",
+	"handle", "user id", "user_id", "jid", "number"}
+
+// classifyColumn returns the kind of data in the column with the given header.
+func classifyColumn(header string) columnKind {
+	h := strings.ToLower(strings.TrimSpace(header))
+	if kind, ok := nameColumns[h]; ok {
+		return kind
+	}
+
+	// Columns with the type or label of identifiers (e.g. "Phone 1 -
+	// Type" of Google Contacts) do not contain identifiers.
+	if strings.HasSuffix(h, "type") || strings.HasSuffix(h, "label") {
+		return colIgnored
+	}
+	for _, w := range identifierWords {
+		if strings.Contains(h, w) {
+			return colIdentifier
+		}
+	}
+	return colIgnored
+}
+
+// ParseCSV parses the contacts from a CSV file. Rows without a name and
+// identifiers are skipped. Fields with multiple values separated by " ::: "
+// (as exported by Google Contacts) are split into multiple identifiers.
+func ParseCSV(r io.Reader) ([]Contact, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.LazyQuotes = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read header: %v", err)
+	}
+
+	// Remove the BOM added by some exporters.
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	kinds := make([]columnKind, len(header))
+	var hasName, hasIdentifier bool
+	for i, h := range header {
+		kinds[i] = classifyColumn(h)
+		hasName = hasName || (kinds[i] != colIgnored && kinds[i] != colIdentifier)
+		hasIdentifier = hasIdentifier || kinds[i] == colIdentifier
+	}
+
+	var res []Contact
+	if !hasName && !hasIdentifier {
+		// No header. Parse the first row as a contact.
+		kinds = nil
+		if c, ok := parseRecord(header, nil); ok {
+			res = append(res, c)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read line %d: %v", line, err)
+		}
+		if c, ok := parseRecord(record, kinds); ok {
+			if len(res) >= MaxContacts {
+				return nil, fmt.Errorf("file has more than %d contacts",
+					MaxContacts)
+			}
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+// parseRecord parses a contact from a record with the given kinds of columns.
+// If kinds is nil, the first column is the name and the remaining ones are
+// identifiers.
+func parseRecord(record []string, kinds []columnKind) (Contact, bool) {
+	var c Contact
+	var first, middle, last string
+	for i, field := range record {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		kind := colIdentifier
+		switch {
+		case kinds == nil && i == 0:
+			kind = colName
+		case kinds == nil:
+		case i < len(kinds):
+			kind = kinds[i]
+		default:
+			kind = colIgnored
+		}
+
+		switch kind {
+		case colName:
+			if c.Name == "" {
+				c.Name = field
+			}
+		case colFirstName:
+			first = field
+		case colMiddleName:
+			middle = field
+		case colLastName:
+			last = field
+		case colIdentifier:
+			for _, id := range strings.Split(field, ":::") {
+				id = strings.TrimSpace(id)
+				if id != "" && !slices.Contains(c.Identifiers, id) {
+					c.Identifiers = append(c.Identifiers, id)
+				}
+			}
+		}
+	}
+	if c.Name == "" {
+		c.Name = strings.Join(nonEmpty(first, middle, last), " ")
+	}
+	if c.Name == "" && len(c.Identifiers) > 0 {
+		c.Name = c.Identifiers[0]
+	}
+	return c, c.Name != ""
+}
+
+func nonEmpty(ss ...string) []string {
+	res := make([]string, 0, len(ss))
+	for _, s := range ss {
+		if s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
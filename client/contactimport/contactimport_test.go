@@ -0,0 +1,62 @@
+package contactimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+)
+
+// TestParseCSV tests parsing contact lists in the formats of various
+// platforms.
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []Contact
+	}{{
+		name: "google contacts",
+		csv: "\ufeffName,Given Name,Family Name,E-mail 1 - Type,E-mail 1 - Value,Phone 1 - Type,Phone 1 - Value\n" +
+			"Alice Smith,Alice,Smith,* Home,alice@example.com,Mobile,+1 555 0100 ::: +1 555 0101\n" +
+			",Bob,Jones,,,Mobile,+1 555 0200\n",
+		want: []Contact{
+			{Name: "Alice Smith", Identifiers: []string{"alice@example.com", "+1 555 0100", "+1 555 0101"}},
+			{Name: "Bob Jones", Identifiers: []string{"+1 555 0200"}},
+		},
+	}, {
+		name: "telegram",
+		csv: "first_name,last_name,phone_number,date\n" +
+			"Carol,,+44 20 7946 0000,2023-01-01\n",
+		want: []Contact{
+			{Name: "Carol", Identifiers: []string{"+44 20 7946 0000"}},
+		},
+	}, {
+		name: "identifier only",
+		csv: "Username\n" +
+			"@dave\n" +
+			"\n",
+		want: []Contact{
+			{Name: "@dave", Identifiers: []string{"@dave"}},
+		},
+	}, {
+		name: "no header",
+		csv: "Erin,erin@example.com\n" +
+			"Frank\n",
+		want: []Contact{
+			{Name: "Erin", Identifiers: []string{"erin@example.com"}},
+			{Name: "Frank"},
+		},
+	}, {
+		name: "empty",
+		csv:  "",
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseCSV(strings.NewReader(tc.csv))
+			assert.NilErr(t, err)
+			assert.DeepEqual(t, got, tc.want)
+		})
+	}
+}
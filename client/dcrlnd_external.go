@@ -0,0 +1,217 @@
+//go:build !brlite
+// +build !brlite
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/decred/dcrlnd/lnrpc"
+	"github.com/decred/slog"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/macaroon.v2"
+)
+
+const (
+	// dcrlnKeepaliveTime is the interval of keepalive pings sent to the
+	// dcrlnd node.
+	dcrlnKeepaliveTime = 30 * time.Second
+
+	// dcrlnKeepaliveTimeout is how long to wait for a reply to a keepalive
+	// ping before considering the connection broken.
+	dcrlnKeepaliveTimeout = 10 * time.Second
+
+	// macaroonIDVersion is the version of the macaroon id encoding used by
+	// dcrlnd.
+	macaroonIDVersion = 3
+)
+
+// broadMacaroonPerms are the permissions that are not needed by the client and
+// that make a macaroon dangerous to use with a shared node.
+var broadMacaroonPerms = map[string]string{
+	"macaroon:generate": "allows baking new macaroons",
+	"macaroon:write":    "allows revoking macaroons",
+	"signer:generate":   "allows signing arbitrary data with the node keys",
+	"peers:write":       "allows changing the node's peers and channel policies",
+	"onchain:write":     "allows spending on-chain funds",
+}
+
+// MacaroonScopeWarnings returns warnings about permissions granted by the
+// macaroon that are broader than the client needs. Using a macaroon without
+// these warnings is advised when connecting to a node shared with other
+// applications.
+func MacaroonScopeWarnings(mac *macaroon.Macaroon) ([]string, error) {
+	rawID := mac.Id()
+	if len(rawID) == 0 || rawID[0] != macaroonIDVersion {
+		return nil, fmt.Errorf("unknown macaroon id version")
+	}
+	var id lnrpc.MacaroonId
+	if err := proto.Unmarshal(rawID[1:], &id); err != nil {
+		return nil, fmt.Errorf("unable to decode macaroon id: %v", err)
+	}
+
+	var warnings []string
+	for _, op := range id.Ops {
+		for _, action := range op.Actions {
+			perm := op.Entity + ":" + action
+			if descr, ok := broadMacaroonPerms[perm]; ok {
+				warnings = append(warnings, fmt.Sprintf("permission "+
+					"%s %s", perm, descr))
+			}
+		}
+	}
+	if len(mac.Caveats()) == 0 {
+		warnings = append(warnings, "macaroon has no caveats (such "+
+			"as a timeout or IP lock)")
+	}
+	return warnings, nil
+}
+
+// MacaroonWarnings returns the warnings about the scope of the macaroon used
+// to connect to dcrlnd.
+func (pc *DcrlnPaymentClient) MacaroonWarnings() []string {
+	return pc.macWarnings
+}
+
+// DcrlnHealth is the health status of the connection to dcrlnd.
+type DcrlnHealth struct {
+	// Online is true if the node is reachable and its RPC service is
+	// running.
+	Online bool `json:"online"`
+
+	// SyncedToChain is true if the node is synced to the chain.
+	SyncedToChain bool `json:"synced_to_chain"`
+
+	// Error is set when the node is not reachable.
+	Error string `json:"error,omitempty"`
+
+	// Time is the time the status was checked.
+	Time time.Time `json:"time"`
+}
+
+// checkHealth checks the current health of the connection to dcrlnd.
+func (pc *DcrlnPaymentClient) checkHealth(ctx context.Context, timeout time.Duration) DcrlnHealth {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	health := DcrlnHealth{Time: time.Now()}
+	info, err := pc.lnRpc.GetInfo(ctx, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.Online = true
+	health.SyncedToChain = info.SyncedToChain
+	return health
+}
+
+// MonitorHealth periodically checks the connection to dcrlnd and calls f
+// whenever its health status changes (including once, with the initial
+// status). When the node is unreachable, reconnection attempts are made
+// without waiting for the connection backoff to elapse.
+//
+// This returns when the context is canceled.
+func (pc *DcrlnPaymentClient) MonitorHealth(ctx context.Context,
+	interval time.Duration, f func(DcrlnHealth)) error {
+
+	var last *DcrlnHealth
+	for {
+		health := pc.checkHealth(ctx, interval)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !health.Online {
+			pc.conn.ResetConnectBackoff()
+		}
+
+		changed := last == nil || last.Online != health.Online ||
+			last.SyncedToChain != health.SyncedToChain
+		if changed {
+			if health.Online {
+				pc.log.Infof("dcrlnd connection online (synced "+
+					"to chain: %v)", health.SyncedToChain)
+			} else {
+				pc.log.Warnf("dcrlnd connection offline: %s",
+					health.Error)
+			}
+			f(health)
+		}
+		last = &health
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// DcrlnConnProfile is a named set of parameters to connect to a (usually
+// external) dcrlnd node.
+type DcrlnConnProfile struct {
+	Name         string `json:"name"`
+	Address      string `json:"address"`
+	TLSCertPath  string `json:"tls_cert_path"`
+	MacaroonPath string `json:"macaroon_path"`
+}
+
+// PaymentClientCfg returns the config to create a payment client that
+// connects to the node of this profile.
+func (p DcrlnConnProfile) PaymentClientCfg(log slog.Logger) DcrlnPaymentClientCfg {
+	return DcrlnPaymentClientCfg{
+		TLSCertPath:  p.TLSCertPath,
+		MacaroonPath: p.MacaroonPath,
+		Address:      p.Address,
+		Log:          log,
+	}
+}
+
+// ErrDcrlnProfileNotFound is returned when a dcrlnd connection profile does not
+// exist.
+var ErrDcrlnProfileNotFound = errors.New("dcrlnd connection profile not found")
+
+// LoadDcrlnConnProfiles loads the list of dcrlnd connection profiles from the
+// given file. Returns an empty list if the file does not exist.
+func LoadDcrlnConnProfiles(fname string) ([]DcrlnConnProfile, error) {
+	var profiles []DcrlnConnProfile
+	err := jsonfile.Read(fname, &profiles)
+	if errors.Is(err, jsonfile.ErrNotFound) {
+		return nil, nil
+	}
+	return profiles, err
+}
+
+// SaveDcrlnConnProfiles saves the list of dcrlnd connection profiles to the
+// given file.
+func SaveDcrlnConnProfiles(fname string, profiles []DcrlnConnProfile) error {
+	names := make(map[string]struct{}, len(profiles))
+	for _, p := range profiles {
+		if p.Name == "" {
+			return fmt.Errorf("dcrlnd connection profile name cannot be empty")
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("duplicate dcrlnd connection profile %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+	}
+	return jsonfile.Write(fname, profiles, slog.Disabled)
+}
+
+// FindDcrlnConnProfile returns the profile with the given name from the list
+// of profiles stored in fname.
+func FindDcrlnConnProfile(fname, name string) (DcrlnConnProfile, error) {
+	profiles, err := LoadDcrlnConnProfiles(fname)
+	if err != nil {
+		return DcrlnConnProfile{}, err
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return DcrlnConnProfile{}, fmt.Errorf("%w: %q", ErrDcrlnProfileNotFound, name)
+}
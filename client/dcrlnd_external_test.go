@@ -0,0 +1,73 @@
+//go:build !brlite
+// +build !brlite
+
+package client
+
+import (
+	"testing"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/decred/dcrlnd/lnrpc"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/macaroon.v2"
+)
+
+// TestMacaroonScopeWarnings asserts that overly broad macaroons generate
+// warnings.
+func TestMacaroonScopeWarnings(t *testing.T) {
+	newMac := func(ops []*lnrpc.Op, caveat string) *macaroon.Macaroon {
+		t.Helper()
+		rawID, err := proto.Marshal(&lnrpc.MacaroonId{Ops: ops})
+		assert.NilErr(t, err)
+		rawID = append([]byte{macaroonIDVersion}, rawID...)
+		mac, err := macaroon.New(make([]byte, 32), rawID, "lnd",
+			macaroon.LatestVersion)
+		assert.NilErr(t, err)
+		if caveat != "" {
+			err := mac.AddFirstPartyCaveat([]byte(caveat))
+			assert.NilErr(t, err)
+		}
+		return mac
+	}
+
+	tests := []struct {
+		name  string
+		ops   []*lnrpc.Op
+		cav   string
+		nbWrn int
+	}{{
+		name: "scoped macaroon",
+		ops: []*lnrpc.Op{
+			{Entity: "info", Actions: []string{"read"}},
+			{Entity: "offchain", Actions: []string{"read", "write"}},
+			{Entity: "invoices", Actions: []string{"read", "write"}},
+		},
+		cav:   "time-before 2030-01-01T00:00:00Z",
+		nbWrn: 0,
+	}, {
+		name: "scoped macaroon without caveats",
+		ops: []*lnrpc.Op{
+			{Entity: "offchain", Actions: []string{"read", "write"}},
+		},
+		nbWrn: 1,
+	}, {
+		name: "admin macaroon",
+		ops: []*lnrpc.Op{
+			{Entity: "macaroon", Actions: []string{"generate", "read", "write"}},
+			{Entity: "onchain", Actions: []string{"read", "write"}},
+			{Entity: "signer", Actions: []string{"generate", "read"}},
+		},
+		cav:   "time-before 2030-01-01T00:00:00Z",
+		nbWrn: 4,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mac := newMac(tc.ops, tc.cav)
+			warnings, err := MacaroonScopeWarnings(mac)
+			assert.NilErr(t, err)
+			assert.DeepEqual(t, len(warnings), tc.nbWrn)
+		})
+	}
+}
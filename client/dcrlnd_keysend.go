@@ -0,0 +1,114 @@
+//go:build !brlite
+// +build !brlite
+
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/decred/dcrlnd/lnrpc"
+	"github.com/decred/dcrlnd/lntypes"
+	"github.com/decred/dcrlnd/record"
+)
+
+// keysendFinalCltvDelta is the final CLTV delta used in keysend payments.
+const keysendFinalCltvDelta = 80
+
+// PayKeysend sends a spontaneous (keysend) payment to the given node, without
+// requiring an invoice. The custom records are sent along with the payment.
+// If maxFeeMAtoms is negative, the default fee limit is used. Returns the fees
+// paid (in milliatoms).
+func (pc *DcrlnPaymentClient) PayKeysend(ctx context.Context, destNode string,
+	amountMAtoms, maxFeeMAtoms int64, records map[uint64][]byte) (int64, error) {
+
+	dest, err := hex.DecodeString(destNode)
+	if err != nil {
+		return 0, fmt.Errorf("invalid destination node: %v", err)
+	}
+
+	var preimage lntypes.Preimage
+	if _, err := rand.Read(preimage[:]); err != nil {
+		return 0, err
+	}
+	hash := preimage.Hash()
+
+	destRecords := make(map[uint64][]byte, len(records)+1)
+	for k, v := range records {
+		destRecords[k] = v
+	}
+	destRecords[record.KeySendType] = preimage[:]
+
+	pc.log.Debugf("Attempting keysend payment of %d MAtoms to %s, hash %s",
+		amountMAtoms, destNode, hash)
+
+	feeLimit := PaymentFeeLimit(uint64(amountMAtoms))
+	if maxFeeMAtoms >= 0 {
+		feeLimit = &lnrpc.FeeLimit{
+			Limit: &lnrpc.FeeLimit_FixedMAtoms{FixedMAtoms: maxFeeMAtoms},
+		}
+	}
+
+	sendPayReq := &lnrpc.SendRequest{
+		Dest:                 dest,
+		AmtMAtoms:            amountMAtoms,
+		PaymentHash:          hash[:],
+		FinalCltvDelta:       keysendFinalCltvDelta,
+		FeeLimit:             feeLimit,
+		DestCustomRecords:    destRecords,
+		DestFeatures:         []lnrpc.FeatureBit{lnrpc.FeatureBit_TLV_ONION_REQ},
+		IgnoreMaxOutboundAmt: true,
+	}
+
+	start := time.Now()
+	sendPayRes, err := pc.lnRpc.SendPaymentSync(ctx, sendPayReq)
+	if err != nil {
+		return 0, fmt.Errorf("unable to complete keysend payment: %v", err)
+	}
+	if sendPayRes.PaymentError != "" {
+		return 0, fmt.Errorf("keysend payment error: %s", sendPayRes.PaymentError)
+	}
+	pc.payTiming.Add(time.Since(start))
+
+	fees := sendPayRes.PaymentRoute.TotalFeesMAtoms
+	pc.log.Debugf("Completed keysend payment of hash %s fees %d hops %d",
+		hash, fees, len(sendPayRes.PaymentRoute.Hops))
+	return fees, nil
+}
+
+// TrackKeysendPayments calls handler for every keysend payment received by the
+// node. This blocks until the context is canceled or the subscription to
+// invoices fails.
+func (pc *DcrlnPaymentClient) TrackKeysendPayments(ctx context.Context,
+	handler func(clientintf.ReceivedKeysend)) error {
+
+	stream, err := pc.lnRpc.SubscribeInvoices(ctx, &lnrpc.InvoiceSubscription{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		inv, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if !inv.IsKeysend || inv.State != lnrpc.Invoice_SETTLED {
+			continue
+		}
+
+		records := make(map[uint64][]byte)
+		for _, htlc := range inv.Htlcs {
+			for k, v := range htlc.CustomRecords {
+				records[k] = v
+			}
+		}
+		handler(clientintf.ReceivedKeysend{
+			MAtoms:        inv.AmtPaidMAtoms,
+			CustomRecords: records,
+		})
+	}
+}
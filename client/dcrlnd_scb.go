@@ -0,0 +1,162 @@
+//go:build !brlite
+// +build !brlite
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrlnd/lnrpc"
+)
+
+// ChannelBackupFilename is the name of the file where copies of the static
+// channel backup are written to in the backup dirs.
+const ChannelBackupFilename = "channels.backup"
+
+// ExportChannelBackup returns the packed multi-channel static channel backup
+// (SCB) of all channels of the node.
+func (pc *DcrlnPaymentClient) ExportChannelBackup(ctx context.Context) ([]byte, error) {
+	res, err := pc.lnRpc.ExportAllChannelBackups(ctx,
+		&lnrpc.ChanBackupExportRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if res.MultiChanBackup == nil {
+		return nil, fmt.Errorf("node did not return a multi-channel backup")
+	}
+	return res.MultiChanBackup.MultiChanBackup, nil
+}
+
+// VerifyChannelBackup verifies that the packed multi-channel backup is valid
+// and can be decrypted by the node.
+func (pc *DcrlnPaymentClient) VerifyChannelBackup(ctx context.Context, packedMulti []byte) error {
+	if len(packedMulti) == 0 {
+		return fmt.Errorf("channel backup is empty")
+	}
+	_, err := pc.lnRpc.VerifyChanBackup(ctx, &lnrpc.ChanBackupSnapshot{
+		MultiChanBackup: &lnrpc.MultiChanBackup{
+			MultiChanBackup: packedMulti,
+		},
+	})
+	return err
+}
+
+// RestoreChannelBackup restores the channels in the packed multi-channel
+// backup. This causes the remote counterparties of the channels to
+// force-close them.
+func (pc *DcrlnPaymentClient) RestoreChannelBackup(ctx context.Context, packedMulti []byte) error {
+	_, err := pc.lnRpc.RestoreChannelBackups(ctx,
+		&lnrpc.RestoreChanBackupRequest{
+			Backup: &lnrpc.RestoreChanBackupRequest_MultiChanBackup{
+				MultiChanBackup: packedMulti,
+			},
+		})
+	return err
+}
+
+// writeChannelBackup atomically writes the packed multi-channel backup to
+// fname.
+func writeChannelBackup(fname string, packedMulti []byte) error {
+	if err := os.MkdirAll(filepath.Dir(fname), 0o700); err != nil {
+		return err
+	}
+	tmpName := fname + ".tmp"
+	if err := os.WriteFile(tmpName, packedMulti, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, fname)
+}
+
+// SaveChannelBackup exports the current multi-channel backup, verifies it and
+// writes it to fname.
+func (pc *DcrlnPaymentClient) SaveChannelBackup(ctx context.Context, fname string) error {
+	packedMulti, err := pc.ExportChannelBackup(ctx)
+	if err != nil {
+		return err
+	}
+	if err := pc.VerifyChannelBackup(ctx, packedMulti); err != nil {
+		return fmt.Errorf("exported channel backup failed verification: %v", err)
+	}
+	return writeChannelBackup(fname, packedMulti)
+}
+
+// copyChannelBackup writes the packed multi-channel backup to every one of the
+// backup dirs. It returns an error listing the dirs that could not be written
+// to.
+func copyChannelBackup(dirs []string, packedMulti []byte) error {
+	var failed []string
+	for _, dir := range dirs {
+		fname := filepath.Join(dir, ChannelBackupFilename)
+		if err := writeChannelBackup(fname, packedMulti); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", dir, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to write channel backup to %s",
+			strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// MonitorChannelBackups keeps copies of the static channel backup in each of
+// the specified dirs (which are usually located in removable or network
+// mounted devices). A new copy is written whenever the set of channels of the
+// node changes. f is called after every attempt to write the copies, with a
+// nil error if all copies were successfully written.
+//
+// This returns when the context is canceled.
+func (pc *DcrlnPaymentClient) MonitorChannelBackups(ctx context.Context,
+	dirs []string, f func(err error)) error {
+
+	const retryDelay = time.Minute
+	for {
+		err := pc.monitorChannelBackups(ctx, dirs, f)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		pc.log.Warnf("Channel backup subscription failed: %v", err)
+
+		select {
+		case <-time.After(retryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (pc *DcrlnPaymentClient) monitorChannelBackups(ctx context.Context,
+	dirs []string, f func(err error)) error {
+
+	stream, err := pc.lnRpc.SubscribeChannelBackups(ctx,
+		&lnrpc.ChannelBackupSubscription{})
+	if err != nil {
+		return err
+	}
+
+	// Write the current backup before waiting for updates.
+	packedMulti, err := pc.ExportChannelBackup(ctx)
+	if err != nil {
+		return err
+	}
+	err = copyChannelBackup(dirs, packedMulti)
+	f(err)
+
+	for {
+		snapshot, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if snapshot.MultiChanBackup == nil {
+			continue
+		}
+		pc.log.Debugf("Channel backup updated with %d channels",
+			len(snapshot.MultiChanBackup.ChanPoints))
+		err = copyChannelBackup(dirs, snapshot.MultiChanBackup.MultiChanBackup)
+		f(err)
+	}
+}
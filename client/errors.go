@@ -11,9 +11,18 @@ import (
 var (
 	errRemoteUserExiting = fmt.Errorf("remote user: %w", clientintf.ErrSubsysExiting)
 	errClientExiting     = fmt.Errorf("client: %w", clientintf.ErrSubsysExiting)
-	errAlreadyExists     = fmt.Errorf("already exists")
-	errUserBlocked       = fmt.Errorf("user is blocked")
+	errAlreadyExists     = clientintf.ErrAlreadyExists
+	errUserBlocked       = clientintf.ErrUserBlocked
 	errRMTooLarge        = errors.New("RM is too large")
+	errNotConnected      = clientintf.KindErrorf(clientintf.ErrPeerOffline, "not connected to server")
+
+	// ErrLiteBuild is returned by the calls that depend on subsystems
+	// excluded from lite builds of the client (see LiteBuild).
+	ErrLiteBuild = errors.New("not available in lite builds of the client")
+
+	// ErrGCReadOnly is returned when attempting to send a message to a
+	// read-only GC without being one of its admins or posters.
+	ErrGCReadOnly = errors.New("GC is read-only")
 )
 
 type userNotFoundError struct {
@@ -25,6 +34,9 @@ func (err userNotFoundError) Error() string {
 }
 
 func (err userNotFoundError) Is(target error) bool {
+	if target == clientintf.ErrUserNotFound {
+		return true
+	}
 	_, ok := target.(userNotFoundError)
 	return ok
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/companyzero/bisonrelay/client/clientintf"
 	"github.com/companyzero/bisonrelay/zkidentity"
 )
 
@@ -26,3 +27,16 @@ func TestErrHasOngoingKX(t *testing.T) {
 			otherRV)
 	}
 }
+
+func TestUserNotFoundErrorKind(t *testing.T) {
+	err := fmt.Errorf("wrapped %w", userNotFoundError{id: "someone"})
+	if !errors.Is(err, clientintf.ErrUserNotFound) {
+		t.Fatalf("does not match ErrUserNotFound")
+	}
+	if !errors.Is(err, userNotFoundError{}) {
+		t.Fatalf("does not match userNotFoundError")
+	}
+	if !errors.Is(errNotConnected, clientintf.ErrPeerOffline) {
+		t.Fatalf("not connected error does not match ErrPeerOffline")
+	}
+}
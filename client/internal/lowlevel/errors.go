@@ -20,6 +20,7 @@ var (
 	// closed on request.
 	errSessRequestedClose = errors.New("requested session close")
 	errORMTooLarge        = errors.New("outbound RM encrypted len greater than max allowed msg size")
+	errNoServerConn       = errors.New("not connected to server")
 )
 
 // kxError is returned when the server KX stage fails.
@@ -1,5 +1,7 @@
 package lowlevel
 
+import "github.com/companyzero/bisonrelay/client/tracing"
+
 // OutboundRM is the interface for sending routed messages via the rmq.
 type OutboundRM interface {
 	EncryptedLen() uint32
@@ -7,3 +9,10 @@ type OutboundRM interface {
 	Priority() uint
 	PaidForRM(int64, int64)
 }
+
+// TracedOutboundRM is an OutboundRM that records the stages of its sending
+// (encryption, payment, push and server ack) as children of a span.
+type TracedOutboundRM interface {
+	OutboundRM
+	TraceSpan() *tracing.Span
+}
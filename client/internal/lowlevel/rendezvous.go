@@ -55,6 +55,21 @@ func (unsub rdzvUnsub) replyUnsubDone(err error, runDone chan struct{}) {
 	}
 }
 
+// DeferredRM is an RM that the server did not push due to the subscription
+// filter. It may be fetched with FetchDeferredRMs.
+type DeferredRM struct {
+	RV       RVID
+	Size     int
+	ServerTS time.Time
+}
+
+// deferredFetch is used to request the list of deferred RMs and the current
+// server session in order to fetch them.
+type deferredFetch struct {
+	rms  []DeferredRM
+	sess clientintf.ServerSessionIntf
+}
+
 // recvdPRM is used during processing of received pushed messages.
 type recvdPRM struct {
 	prm       *rpc.PushRoutedMessage
@@ -92,6 +107,16 @@ type RVManager struct {
 	nextInvoice string
 	subDoneCB   func()
 
+	// deferredChan is used to list the currently deferred RMs.
+	deferredChan chan chan deferredFetch
+
+	// subFilter is the filter sent to the server to limit which RMs are
+	// automatically pushed. Must only be set before Run() is called.
+	subFilter *rpc.SubscriptionFilter
+
+	// filterChan is used to change the subscription filter while running.
+	filterChan chan rpc.SubscriptionFilter
+
 	// subsDelayer is used to do some hysteresis around the full
 	// subscription set and avoid sending multiple subscription requests to
 	// the server in a very short time frame.
@@ -124,6 +149,140 @@ func NewRVManager(log slog.Logger, db RVManagerDB, subsDelayer func() <-chan tim
 		isUpToDate:  make(chan chan bool),
 		subsDelayer: subsDelayer,
 		subDoneCB:   subDoneCB,
+
+		deferredChan: make(chan chan deferredFetch),
+		filterChan:   make(chan rpc.SubscriptionFilter),
+	}
+}
+
+// SetMaxAutoFetchSize sets the max size of RMs that the server should
+// automatically push. Larger RMs are deferred until FetchDeferredRMs is called.
+// Zero means no limit. This is only respected by servers that support
+// subscription filters.
+//
+// This MUST be called before Run().
+func (rmgr *RVManager) SetMaxAutoFetchSize(size int) {
+	rmgr.subFilter = &rpc.SubscriptionFilter{MaxPushSize: size}
+}
+
+// SetInitialSubscriptionFilter sets the full subscription filter used when
+// subscribing to RVs.
+//
+// This MUST be called before Run().
+func (rmgr *RVManager) SetInitialSubscriptionFilter(filter rpc.SubscriptionFilter) {
+	rmgr.subFilter = &filter
+}
+
+// UpdateSubscriptionFilter changes the subscription filter sent to the server.
+// The new filter is sent to the server along with the next subscription update
+// (which is triggered by this call).
+func (rmgr *RVManager) UpdateSubscriptionFilter(ctx context.Context, filter rpc.SubscriptionFilter) error {
+	select {
+	case rmgr.filterChan <- filter:
+		return nil
+	case <-rmgr.runDone:
+		return errRdvzMgrExiting
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// listDeferred returns the list of currently deferred RMs and the current
+// server session.
+func (rmgr *RVManager) listDeferred(ctx context.Context) (deferredFetch, error) {
+	c := make(chan deferredFetch, 1)
+	select {
+	case rmgr.deferredChan <- c:
+	case <-rmgr.runDone:
+		return deferredFetch{}, errRdvzMgrExiting
+	case <-ctx.Done():
+		return deferredFetch{}, ctx.Err()
+	}
+
+	select {
+	case res := <-c:
+		return res, nil
+	case <-rmgr.runDone:
+		return deferredFetch{}, errRdvzMgrExiting
+	case <-ctx.Done():
+		return deferredFetch{}, ctx.Err()
+	}
+}
+
+// DeferredRMs returns the list of RMs that were not pushed by the server due
+// to the subscription filter and that haven't been fetched yet.
+func (rmgr *RVManager) DeferredRMs() []DeferredRM {
+	df, err := rmgr.listDeferred(context.Background())
+	if err != nil {
+		return nil
+	}
+	return df.rms
+}
+
+// FetchDeferredRMs requests the server to push all RMs that were previously
+// deferred due to the subscription filter.
+func (rmgr *RVManager) FetchDeferredRMs(ctx context.Context) error {
+	df, err := rmgr.listDeferred(ctx)
+	if err != nil {
+		return err
+	}
+	if len(df.rms) == 0 {
+		return nil
+	}
+
+	rvs := make([]RVID, len(df.rms))
+	for i := range df.rms {
+		rvs[i] = df.rms[i].RV
+	}
+	return rmgr.fetchDeferred(ctx, df.sess, rvs)
+}
+
+// FetchDeferredRMsList requests the server to push the specified subset of
+// deferred RMs.
+func (rmgr *RVManager) FetchDeferredRMsList(ctx context.Context, rvs []RVID) error {
+	if len(rvs) == 0 {
+		return nil
+	}
+	df, err := rmgr.listDeferred(ctx)
+	if err != nil {
+		return err
+	}
+	return rmgr.fetchDeferred(ctx, df.sess, rvs)
+}
+
+// fetchDeferred requests the server to push the specified deferred RMs.
+func (rmgr *RVManager) fetchDeferred(ctx context.Context,
+	sess clientintf.ServerSessionIntf, rvs []RVID) error {
+
+	if sess == nil {
+		return errNoServerConn
+	}
+
+	rmgr.log.Debugf("Requesting server to push %d deferred RMs", len(rvs))
+	msg := rpc.Message{Command: rpc.TaggedCmdFetchDeferredRoutedMessages}
+	payload := &rpc.FetchDeferredRoutedMessages{RVs: rvs}
+	replyChan := make(chan interface{})
+	if err := sess.SendPRPC(msg, payload, replyChan); err != nil {
+		return err
+	}
+
+	var reply interface{}
+	select {
+	case reply = <-replyChan:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	switch reply := reply.(type) {
+	case *rpc.FetchDeferredRoutedMessagesReply:
+		if reply.Error != "" {
+			return AckError{ErrorStr: reply.Error}
+		}
+		return nil
+	case error:
+		return reply
+	default:
+		return fmt.Errorf("unknown reply from server: %v", reply)
 	}
 }
 
@@ -279,7 +438,7 @@ func (rmgr *RVManager) HandlePushedRMs(prm *rpc.PushRoutedMessage) error {
 		return AckError{ErrorStr: prm.Error}
 	}
 
-	if len(prm.Payload) == 0 {
+	if len(prm.Payload) == 0 && !prm.Deferred {
 		rmgr.log.Tracef("Received empty pushed RM")
 		return nil
 	}
@@ -451,7 +610,8 @@ func (rmgr *RVManager) payForSubs(ctx context.Context, rlist []ratchet.RVPoint,
 // updatePayloadSubscriptions (re-)subscribes to all rendezvous points in subs on
 // the given server session.
 func (rmgr *RVManager) updatePayloadSubscriptions(ctx context.Context,
-	add, del, mark []ratchet.RVPoint, subs map[RVID]rdzvSub, sess clientintf.ServerSessionIntf) error {
+	add, del, mark []ratchet.RVPoint, subs map[RVID]rdzvSub,
+	filter *rpc.SubscriptionFilter, sess clientintf.ServerSessionIntf) error {
 
 	// Pay for the subs we haven't paid yet. This includes both
 	// subscriptions to add and to mark as paid in the server and excludes
@@ -471,6 +631,9 @@ func (rmgr *RVManager) updatePayloadSubscriptions(ctx context.Context,
 		DelRendezvous: del,
 		MarkPaid:      mark,
 	}
+	if sess.Policy().SubFilters {
+		payload.Filter = filter
+	}
 
 	replyChan := make(chan interface{})
 	err = sess.SendPRPC(msg, payload, replyChan)
@@ -570,11 +733,13 @@ func (rmgr *RVManager) handleInSub(rprm recvdPRM, sub rdzvSub, ok bool) {
 func (rmgr *RVManager) Run(ctx context.Context) error {
 
 	subs := make(map[RVID]rdzvSub)
+	deferred := make(map[RVID]DeferredRM)
 	var toAdd, toDel, toMark []RVID
 	var unsubs, requestedUnsubs []rdzvUnsub
 	var sess clientintf.ServerSessionIntf
 	var err error
 	var needsUpdate bool
+	filter := rmgr.subFilter
 
 	// updateResChan gets the result of the async call to
 	// updatePayloadSubscriptions().
@@ -664,6 +829,25 @@ loop:
 			continue loop
 
 		case rprm := <-rmgr.handlerChan:
+			if rprm.prm.Deferred {
+				// Track the deferred RM until it's fetched.
+				rmgr.log.Debugf("Server deferred push of %d bytes "+
+					"at RV %s", rprm.prm.Size, rprm.prm.RV)
+				deferred[rprm.prm.RV] = DeferredRM{
+					RV:       rprm.prm.RV,
+					Size:     rprm.prm.Size,
+					ServerTS: time.Unix(rprm.prm.Timestamp, 0),
+				}
+				go func() {
+					select {
+					case rprm.replyChan <- nil:
+					case <-rmgr.runDone:
+					}
+				}()
+				continue loop
+			}
+			delete(deferred, rprm.prm.RV)
+
 			// Handle received pushed RM. The handleInSub call will
 			// ack the result of processing the RV.
 			sub, ok := subs[rprm.prm.RV]
@@ -671,6 +855,21 @@ loop:
 
 			continue loop
 
+		case newFilter := <-rmgr.filterChan:
+			rmgr.log.Debugf("Changing subscription filter to max "+
+				"push size %d, min timestamp %d",
+				newFilter.MaxPushSize, newFilter.MinTimestamp)
+			filter = &newFilter
+			needsUpdate = true
+
+		case c := <-rmgr.deferredChan:
+			df := deferredFetch{sess: sess, rms: make([]DeferredRM, 0, len(deferred))}
+			for _, drm := range deferred {
+				df.rms = append(df.rms, drm)
+			}
+			c <- df
+			continue loop
+
 		case updateErr := <-updateResChan:
 			// Received reply to latest subscription attempt.
 			lastUpdateDone = true
@@ -754,12 +953,13 @@ loop:
 		unsubs = nil
 		delayChan = nil
 		needsUpdate = false
-		go func(add, del, mark []ratchet.RVPoint, sess clientintf.ServerSessionIntf) {
+		go func(add, del, mark []ratchet.RVPoint, filter *rpc.SubscriptionFilter,
+			sess clientintf.ServerSessionIntf) {
 			select {
-			case updateResChan <- rmgr.updatePayloadSubscriptions(ctx, add, del, mark, subs, sess):
+			case updateResChan <- rmgr.updatePayloadSubscriptions(ctx, add, del, mark, subs, filter, sess):
 			case <-ctx.Done():
 			}
-		}(toAdd, toDel, toMark, sess)
+		}(toAdd, toDel, toMark, filter, sess)
 		toAdd = nil
 		toDel = nil
 		toMark = nil
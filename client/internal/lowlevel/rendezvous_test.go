@@ -604,3 +604,68 @@ func TestRendezvousManagerFetchPrepaidWorks(t *testing.T) {
 	// Got the correct blob.
 	assert.NilErrFromChan(t, errChan)
 }
+
+// TestDeferredRMs asserts that RMs deferred by the server due to the
+// subscription filter are tracked until they are fetched.
+func TestDeferredRMs(t *testing.T) {
+	t.Parallel()
+
+	rmgr := NewRVManager(nil, &mockRvMgrDB{alwaysPaid: true}, nil, nil)
+	rmgr.SetMaxAutoFetchSize(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- rmgr.Run(ctx) }()
+
+	sess := newMockServerSession()
+	sess.policy.SubFilters = true
+	rmgr.BindToSession(sess)
+
+	// Subscribe to the RV.
+	id := rvidFromStr("rdzv-deferred")
+	blob := []byte("large blob of data")
+	handlerCalled := make(chan struct{}, 1)
+	handler := func(gotBlob RVBlob) error {
+		handlerCalled <- struct{}{}
+		return nil
+	}
+	subDoneChan := make(chan error, 1)
+	go func() { subDoneChan <- rmgr.Sub(id, handler, nil) }()
+
+	// The filter should be sent to the server.
+	payload := sess.replyNextPRPC(t, &rpc.SubscribeRoutedMessagesReply{})
+	subsMsg := payload.(*rpc.SubscribeRoutedMessages)
+	if subsMsg.Filter == nil || subsMsg.Filter.MaxPushSize != 10 {
+		t.Fatalf("unexpected filter: %v", subsMsg.Filter)
+	}
+	assert.NilErrFromChan(t, subDoneChan)
+
+	// Push a deferred RM. The handler should not be called and it should
+	// be listed as deferred.
+	prm := &rpc.PushRoutedMessage{RV: id, Deferred: true, Size: len(blob)}
+	assert.NilErr(t, rmgr.HandlePushedRMs(prm))
+	assert.ChanNotWritten(t, handlerCalled, 100*time.Millisecond)
+	deferred := rmgr.DeferredRMs()
+	if len(deferred) != 1 || deferred[0].RV != id || deferred[0].Size != len(blob) {
+		t.Fatalf("unexpected deferred RMs: %v", deferred)
+	}
+
+	// Fetch the deferred RMs.
+	fetchErr := make(chan error, 1)
+	go func() { fetchErr <- rmgr.FetchDeferredRMs(ctx) }()
+	payload = sess.replyNextPRPC(t, &rpc.FetchDeferredRoutedMessagesReply{})
+	fetchMsg := payload.(*rpc.FetchDeferredRoutedMessages)
+	if len(fetchMsg.RVs) != 1 || fetchMsg.RVs[0] != id {
+		t.Fatalf("unexpected fetch RVs: %v", fetchMsg.RVs)
+	}
+	assert.NilErrFromChan(t, fetchErr)
+
+	// Push the full RM. The handler should be called and it should no
+	// longer be listed as deferred.
+	prm = &rpc.PushRoutedMessage{RV: id, Payload: blob}
+	assert.NilErr(t, rmgr.HandlePushedRMs(prm))
+	assert.ChanWritten(t, handlerCalled)
+	if deferred := rmgr.DeferredRMs(); len(deferred) != 0 {
+		t.Fatalf("unexpected deferred RMs: %v", deferred)
+	}
+}
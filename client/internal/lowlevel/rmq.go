@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
@@ -11,12 +12,24 @@ import (
 	"github.com/companyzero/bisonrelay/client/clientintf"
 	"github.com/companyzero/bisonrelay/client/internal/multipriq"
 	"github.com/companyzero/bisonrelay/client/timestats"
+	"github.com/companyzero/bisonrelay/client/tracing"
 	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/decred/slog"
 	"golang.org/x/sync/errgroup"
 )
 
+const (
+	// maxBatchableRMSize is the max size of an encrypted RM that may be
+	// pushed as part of a batch of RMs. Larger RMs are always pushed
+	// individually.
+	maxBatchableRMSize = 8 * 1024
+
+	// maxBatchPayloadSize is the max total size of the RMs pushed in a
+	// single batch.
+	maxBatchPayloadSize = 64 * 1024
+)
+
 // rmmsg is the internal structure used to keep track of an outbound RM.
 type rmmsg struct {
 	orm       OutboundRM
@@ -24,10 +37,32 @@ type rmmsg struct {
 	rv        RVID
 	encrypted []byte
 
+	// span is the span of the rm, when it is traced. The stages of sending
+	// the rm are recorded as children of this span.
+	span *tracing.Span
+
 	mtx      sync.Mutex
 	paidHash []byte
 }
 
+// childSpans starts a child span for the stage of sending of each rm of the
+// batch.
+func childSpans(batch []*rmmsg, stage string) []*tracing.Span {
+	spans := make([]*tracing.Span, len(batch))
+	for i, rmm := range batch {
+		spans[i] = rmm.span.Child(stage)
+		spans[i].SetAttr("batch_size", strconv.Itoa(len(batch)))
+	}
+	return spans
+}
+
+// endSpans ends the spans with the given error.
+func endSpans(spans []*tracing.Span, err error) {
+	for _, span := range spans {
+		span.End(err)
+	}
+}
+
 func (r *rmmsg) sendReply(err error) {
 	if r.replyChan == nil {
 		return
@@ -126,6 +161,9 @@ func (q *RMQ) QueueRM(orm OutboundRM, replyChan chan error) error {
 		orm:       orm,
 		replyChan: replyChan,
 	}
+	if traced, ok := orm.(TracedOutboundRM); ok {
+		rmm.span = traced.TraceSpan()
+	}
 	select { // Enqueue.
 	case q.rmChan <- rmm:
 	case <-q.enqueueDone:
@@ -192,6 +230,45 @@ func (q *RMQ) processRMAck(reply interface{}) (string, error) {
 	return nextInvoice, err
 }
 
+// processRMBatchAck processes the ack'd reply from a previously sent batch of
+// RMs. It returns the new server invoice (if there is one), the individual
+// errors for each RM of the batch and an error that applies to the entire
+// batch.
+func (q *RMQ) processRMBatchAck(reply interface{}, batchLen int) (string, []error, error) {
+	q.log.Tracef("Processing RMBatchAck reply %T", reply)
+
+	var r *rpc.RouteMessageBatchReply
+	switch reply := reply.(type) {
+	case rpc.RouteMessageBatchReply:
+		r = &reply
+	case *rpc.RouteMessageBatchReply:
+		r = reply
+	case error:
+		return "", nil, reply
+	default:
+		return "", nil, fmt.Errorf("unknown reply of RMBatchAck: %v", reply)
+	}
+
+	if r.Error != "" {
+		if r.Error == rpc.ErrRMInvoicePayment.Error() {
+			return "", nil, rpc.ErrRMInvoicePayment
+		}
+		return "", nil, routeMessageReplyError{errorStr: r.Error}
+	}
+	if len(r.Errors) != batchLen {
+		return "", nil, fmt.Errorf("server replied with %d batch errors "+
+			"instead of %d", len(r.Errors), batchLen)
+	}
+
+	errs := make([]error, batchLen)
+	for i := range r.Errors {
+		if r.Errors[i] != "" {
+			errs[i] = routeMessageReplyError{errorStr: r.Errors[i]}
+		}
+	}
+	return r.NextInvoice, errs, nil
+}
+
 // fetchInvoice requests and returns an invoice for the server to pay for
 // pushing an RM.
 func (q *RMQ) fetchInvoice(ctx context.Context, sess clientintf.ServerSessionIntf) (string, error) {
@@ -390,7 +467,10 @@ func (q *RMQ) sendToSession(ctx context.Context, rmm *rmmsg, sess clientintf.Ser
 	invoice string, replyChan chan rmmsgReply) {
 
 	// Pay for the RM.
-	if err := q.payForRM(ctx, rmm, invoice, sess); err != nil {
+	paySpan := rmm.span.Child("pay")
+	err := q.payForRM(ctx, rmm, invoice, sess)
+	paySpan.End(err)
+	if err != nil {
 		q.log.Debugf("Unable to pay for RM %s: %v", rmm.orm, err)
 
 		// Request connection close so that we reconnect and try to
@@ -408,8 +488,10 @@ func (q *RMQ) sendToSession(ctx context.Context, rmm *rmmsg, sess clientintf.Ser
 
 	// Send it!
 	ackChan := make(chan interface{})
-	err := sess.SendPRPC(msg, payload, ackChan)
+	pushSpan := rmm.span.Child("push")
+	err = sess.SendPRPC(msg, payload, ackChan)
 	sendTime := time.Now()
+	pushSpan.End(err)
 	if err != nil {
 		// Connection will be dropped, try again with next connection.
 		q.log.Debugf("Error sending rm %s at RV %s: %v", rmm.orm, rmm.rv, err)
@@ -419,16 +501,19 @@ func (q *RMQ) sendToSession(ctx context.Context, rmm *rmmsg, sess clientintf.Ser
 	q.log.Debugf("Success sending rm %s at RV %s", rmm.orm, rmm.rv)
 
 	// Wait for server ack.
+	ackSpan := rmm.span.Child("ack")
 	var ackReply interface{}
 	select {
 	case ackReply = <-ackChan:
 	case <-ctx.Done():
 		// RMQ is quitting.
+		ackSpan.End(ctx.Err())
 		return
 	}
 
 	// Ack received from server. Process it.
 	nextInvoice, err := q.processRMAck(ackReply)
+	ackSpan.End(err)
 
 	// Ignore ErrSubsysExiting. This error happens when (a) the session was
 	// closed or (b) the user is quitting the client.  Either way, the
@@ -491,6 +576,238 @@ func (q *RMQ) sendToSession(ctx context.Context, rmm *rmmsg, sess clientintf.Ser
 	}
 }
 
+// payForRMBatch pays for pushing the given batch of rms on the server with a
+// single payment.
+func (q *RMQ) payForRMBatch(ctx context.Context, batch []*rmmsg, invoice string,
+	sess clientintf.ServerSessionIntf) error {
+
+	// Determine payment amount.
+	pc := sess.PayClient()
+	var payloadSize int64
+	for _, rmm := range batch {
+		payloadSize += int64(len(rmm.encrypted))
+	}
+	pushPayRate, _ := sess.PaymentRates()
+	amt := payloadSize * int64(pushPayRate)
+
+	// Enforce the minimum payment policy.
+	if amt < int64(rpc.MinRMPushPayment) {
+		amt = int64(rpc.MinRMPushPayment)
+	}
+
+	// Fetch invoice if needed.
+	var err error
+	var decoded clientintf.DecodedInvoice
+	needsInvoice := false
+	if invoice == "" {
+		needsInvoice = true
+	} else {
+		// Decode invoice, check if it's expired.
+		decoded, err = pc.DecodeInvoice(ctx, invoice)
+		if err != nil {
+			needsInvoice = true
+		} else if decoded.IsExpired(rpc.InvoiceExpiryAffordance) {
+			needsInvoice = true
+		}
+	}
+
+	if needsInvoice {
+		invoice, err = q.fetchInvoice(ctx, sess)
+		if err != nil {
+			return err
+		}
+		if decoded, err = pc.DecodeInvoice(ctx, invoice); err != nil {
+			return err
+		}
+	}
+
+	// Save that there's a payment attempt outbound for every RV of the
+	// batch so that, if the batch needs to be resent individually (e.g.
+	// after a reconnection), the payment may be reused.
+	now := time.Now()
+	for _, rmm := range batch {
+		if err := q.db.StoreRVPaymentAttempt(rmm.rv, invoice, now); err != nil {
+			return err
+		}
+	}
+
+	// Pay for it.
+	q.log.Tracef("Attempting to pay %d MAtoms to push batch of %d RMs",
+		amt, len(batch))
+	ctx, cancel := multiCtx(ctx, sess.Context())
+	fees, err := pc.PayInvoiceAmount(ctx, invoice, amt)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	q.log.Tracef("Payment to push batch of %d RMs completed successfully "+
+		"with ID %x", len(batch), decoded.ID)
+
+	// Split the amount and fees among the RMs of the batch, proportionally
+	// to their size. Any remainder is attributed to the first one.
+	var amtLeft, feesLeft = amt, fees
+	amts, feess := make([]int64, len(batch)), make([]int64, len(batch))
+	for i, rmm := range batch {
+		size := int64(len(rmm.encrypted))
+		if payloadSize > 0 {
+			amts[i] = amt * size / payloadSize
+			feess[i] = fees * size / payloadSize
+		}
+		amtLeft -= amts[i]
+		feesLeft -= feess[i]
+	}
+	amts[0] += amtLeft
+	feess[0] += feesLeft
+
+	for i, rmm := range batch {
+		rmm.mtx.Lock()
+		rmm.paidHash = decoded.ID
+		rmm.mtx.Unlock()
+		rmm.orm.PaidForRM(amts[i], feess[i])
+	}
+	return nil
+}
+
+// sendBatchToSession sends the given batch of rms to the given session, using
+// a single push command (and payment). It sends the result of the send
+// attempt of each individual rm in replyChan.
+//
+// Errors are handled in the same way as in sendToSession.
+func (q *RMQ) sendBatchToSession(ctx context.Context, batch []*rmmsg,
+	sess clientintf.ServerSessionIntf, invoice string, replyChan chan rmmsgReply) {
+
+	// Pay for the batch.
+	paySpans := childSpans(batch, "pay")
+	err := q.payForRMBatch(ctx, batch, invoice, sess)
+	endSpans(paySpans, err)
+	if err != nil {
+		q.log.Debugf("Unable to pay for batch of %d RMs: %v", len(batch), err)
+
+		// Request connection close so that we reconnect and try to
+		// pay again.
+		sess.RequestClose(err)
+		return
+	}
+
+	msg := rpc.Message{Command: rpc.TaggedCmdRouteMessageBatch}
+	payload := &rpc.RouteMessageBatch{
+		PaidInvoiceID: batch[0].paidHash,
+		Messages:      make([]rpc.BatchedRouteMessage, len(batch)),
+	}
+	for i, rmm := range batch {
+		payload.Messages[i] = rpc.BatchedRouteMessage{
+			Rendezvous: rmm.rv,
+			Message:    rmm.encrypted,
+		}
+	}
+
+	// Send it!
+	ackChan := make(chan interface{})
+	pushSpans := childSpans(batch, "push")
+	err = sess.SendPRPC(msg, payload, ackChan)
+	sendTime := time.Now()
+	endSpans(pushSpans, err)
+	if err != nil {
+		// Connection will be dropped, try again with next connection.
+		q.log.Debugf("Error sending batch of %d RMs: %v", len(batch), err)
+		return
+	}
+
+	q.log.Debugf("Success sending batch of %d RMs", len(batch))
+
+	// Wait for server ack.
+	ackSpans := childSpans(batch, "ack")
+	var ackReply interface{}
+	select {
+	case ackReply = <-ackChan:
+	case <-ctx.Done():
+		// RMQ is quitting.
+		endSpans(ackSpans, ctx.Err())
+		return
+	}
+
+	// Ack received from server. Process it.
+	nextInvoice, errs, err := q.processRMBatchAck(ackReply, len(batch))
+	for i, span := range ackSpans {
+		if err == nil && errs != nil {
+			span.End(errs[i])
+		} else {
+			span.End(err)
+		}
+	}
+
+	// Ignore ErrSubsysExiting (see sendToSession for rationale).
+	if errors.Is(err, clientintf.ErrSubsysExiting) {
+		return
+	}
+
+	// When we receive back an invoice payment error, clear the invoice
+	// used for payment and try again with a fresh invoice.
+	if errors.Is(err, rpc.ErrRMInvoicePayment) {
+		q.log.Warnf("Received ErrRMInvoicePayment when attempting to "+
+			"push batch of %d RMs with payment hash %x. Attempting "+
+			"again with new invoice.", len(batch), batch[0].paidHash)
+
+		for _, rmm := range batch {
+			rmm.mtx.Lock()
+			rmm.paidHash = nil
+			rmm.mtx.Unlock()
+
+			if err := q.db.DeleteRVPaymentAttempt(rmm.rv); err != nil {
+				q.log.Warnf("Unable to delete payment to push RV %s: %v",
+					rmm.rv, err)
+			}
+		}
+
+		q.sendBatchToSession(ctx, batch, sess, "", replyChan)
+		return
+	}
+
+	// Track how long it took to get the ack.
+	q.timingStat.Add(time.Since(sendTime))
+
+	// An error for the entire batch is handled as an ack error for an
+	// individual RM: disconnect and resend through the next connection.
+	if err != nil {
+		sess.RequestClose(fmt.Errorf("RM batch push ack error: %v", err))
+		return
+	}
+
+	// Reply for every RM that was successfully stored. Those that failed
+	// are kept pending in sendLoop and are resent after reconnecting.
+	var failedErr error
+	for i, rmm := range batch {
+		if errs[i] != nil {
+			q.log.Debugf("Server failed to store batched RM %s at RV %s: %v",
+				rmm.orm, rmm.rv, errs[i])
+			failedErr = errs[i]
+			continue
+		}
+
+		go rmm.sendReply(nil)
+
+		// Mark payment as used.
+		if err := q.db.DeleteRVPaymentAttempt(rmm.rv); err != nil {
+			q.log.Warnf("Unable to delete payment to push RV %s: %v",
+				rmm.rv, err)
+		}
+
+		// Reply sendLoop that the ack for this was received. Only the
+		// first reply carries the next invoice.
+		select {
+		case replyChan <- rmmsgReply{rmm: rmm, nextInvoice: nextInvoice}:
+			nextInvoice = ""
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	if failedErr != nil {
+		sess.RequestClose(fmt.Errorf("RM batch push ack error: %v", failedErr))
+	}
+}
+
 // Len returns the current number of outstanding messages in the RMQs enqueue
 // loop and send loop.
 func (q *RMQ) Len() (int, int) {
@@ -639,6 +956,42 @@ func (q *RMQ) sendLoop(ctx context.Context) error {
 	// and that can be used to pay for the next one.
 	invoices := &genericlist.List[string]{}
 
+	// nextInvoice returns an available invoice if we have one.
+	nextInvoice := func() string {
+		var invoice string
+		if invoices.Len() > 0 {
+			e := invoices.Front()
+			invoice = e.Value
+			invoices.Remove(e)
+		}
+		return invoice
+	}
+
+	// prepare the msg. This is done synchronously so that RMs sent to the
+	// same user are sent in ratchet sendcount order. Returns false if the
+	// RM cannot be sent.
+	prepare := func(rmm *rmmsg) bool {
+		if rmm.encrypted != nil {
+			return true
+		}
+
+		var err error
+		encSpan := rmm.span.Child("encrypt")
+		rmm.rv, rmm.encrypted, err = rmm.orm.EncryptedMsg()
+		encSpan.End(err)
+		if err != nil {
+			q.log.Debugf("Error encrypting RM %s: %v",
+				rmm.orm, err)
+			// This is a fatal error for this RM. We cannot send it
+			// anymore, so inform original caller of the error.
+			go rmm.sendReply(err)
+			return false
+		}
+		q.log.Tracef("Generated encrypted %T with %d bytes at RV %s", rmm.orm,
+			len(rmm.encrypted), rmm.rv)
+		return true
+	}
+
 loop:
 	for {
 		select {
@@ -690,27 +1043,45 @@ loop:
 			}
 
 		case rmm := <-sendChan:
-			// Prepare the msg. This is done synchronously so that
-			// RMs sent to the same user are sent in ratchet
-			// sendcount order.
-			if rmm.encrypted == nil {
-				var err error
-				rmm.rv, rmm.encrypted, err = rmm.orm.EncryptedMsg()
-				if err != nil {
-					q.log.Debugf("Error encrypting RM %s: %v",
-						rmm.orm, err)
-					// This is a fatal error for this RM.
-					// We cannot send it anymore, so inform
-					// original caller of the error.
-					go rmm.sendReply(err)
-					continue loop
-				}
-				q.log.Tracef("Generated encrypted %T with %d bytes at RV %s", rmm.orm,
-					len(rmm.encrypted), rmm.rv)
+			if !prepare(rmm) {
+				continue loop
 			}
 
 			// New item to send.
 			rmms[rmm] = struct{}{}
+
+			// Opportunistically gather other small RMs that are
+			// ready to be sent, to push them as a single batch
+			// (with a single payment).
+			batch := []*rmmsg{rmm}
+			maxBatch := sess.Policy().MaxBatchedRMs
+			batchSize := len(rmm.encrypted)
+			var nextRMM *rmmsg
+			if maxBatch > 1 && batchSize <= maxBatchableRMSize {
+			batchLoop:
+				for len(batch) < maxBatch && len(rmms) < maxPendingRMMs {
+					select {
+					case nextRMM = <-sendChan:
+					default:
+						break batchLoop
+					}
+					if !prepare(nextRMM) {
+						nextRMM = nil
+						continue batchLoop
+					}
+					rmms[nextRMM] = struct{}{}
+					nextSize := len(nextRMM.encrypted)
+					if nextSize > maxBatchableRMSize ||
+						batchSize+nextSize > maxBatchPayloadSize {
+						// Send this one individually.
+						break batchLoop
+					}
+					batch = append(batch, nextRMM)
+					batchSize += nextSize
+					nextRMM = nil
+				}
+			}
+
 			if len(rmms) >= maxPendingRMMs {
 				// Stop accepting new items to send while we
 				// have too many pending for confirmation.
@@ -720,16 +1091,15 @@ loop:
 					len(rmms), maxPendingRMMs)
 			}
 
-			// Use an available invoice if we have one.
-			var invoice string
-			if invoices.Len() > 0 {
-				e := invoices.Front()
-				invoice = e.Value
-				invoices.Remove(e)
-			}
-
 			// Attempt send.
-			go q.sendToSession(ctx, rmm, sess, invoice, replyChan)
+			if len(batch) > 1 {
+				go q.sendBatchToSession(ctx, batch, sess, nextInvoice(), replyChan)
+			} else {
+				go q.sendToSession(ctx, rmm, sess, nextInvoice(), replyChan)
+			}
+			if nextRMM != nil {
+				go q.sendToSession(ctx, nextRMM, sess, nextInvoice(), replyChan)
+			}
 
 		case reply := <-replyChan:
 			// Whatever we receive as reply, we consider this RM
@@ -291,6 +291,112 @@ func TestRMQMultipleRM(t *testing.T) {
 	}
 }
 
+// TestRMQBatchedRMs asserts that the RMQ correctly sends RMs when the server
+// supports pushing batches of RMs.
+func TestRMQBatchedRMs(t *testing.T) {
+	t.Parallel()
+
+	nb := 5
+	mockID := &zkidentity.FullIdentity{}
+	q := NewRMQ(nil, mockID, newMockRMQDB())
+	runErr := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { runErr <- q.Run(ctx) }()
+
+	// Queue the RMs before binding to the server, so that they may be
+	// batched.
+	rmErrChan := make(chan error, nb)
+	for i := 0; i < nb; i++ {
+		rm := mockRM(fmt.Sprintf("test %d", i))
+		err := q.QueueRM(rm, rmErrChan)
+		assert.NilErr(t, err)
+	}
+
+	// Bind to the server.
+	sess := newMockServerSession()
+	sess.policy.MaxPushInvoices = nb
+	sess.policy.MaxBatchedRMs = nb
+	q.BindToSession(sess)
+
+	// RMs may be sent individually or batched, depending on how fast
+	// they are dequeued, so handle both cases.
+	gotRVs := make(map[string]struct{}, nb)
+	for len(gotRVs) < nb {
+		var wm wireMsg
+		select {
+		case wm = <-sess.rpcChan:
+		case <-time.After(time.Second):
+			t.Fatal("timeout on receive")
+		}
+
+		var reply interface{}
+		switch p := wm.payload.(type) {
+		case *rpc.GetInvoice:
+			reply = &rpc.GetInvoiceReply{}
+		case *rpc.RouteMessage:
+			gotRVs[strFromRVID(p.Rendezvous)] = struct{}{}
+			reply = &rpc.RouteMessageReply{}
+		case *rpc.RouteMessageBatch:
+			for _, m := range p.Messages {
+				gotRVs[strFromRVID(m.Rendezvous)] = struct{}{}
+			}
+			reply = &rpc.RouteMessageBatchReply{
+				Errors: make([]string, len(p.Messages)),
+			}
+		default:
+			t.Fatalf("unexpected payload %T", wm.payload)
+		}
+
+		select {
+		case wm.replyChan <- reply:
+		case <-time.After(time.Second):
+			t.Fatal("timeout on reply")
+		}
+	}
+
+	// Ensure no errors occurred.
+	for i := 0; i < nb; i++ {
+		select {
+		case err := <-runErr:
+			t.Fatal(err)
+		case err := <-rmErrChan:
+			assert.NilErr(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
+
+// TestProcessRMBatchAck asserts that the replies to pushing a batch of RMs
+// are correctly processed.
+func TestProcessRMBatchAck(t *testing.T) {
+	t.Parallel()
+
+	q := NewRMQ(nil, &zkidentity.FullIdentity{}, newMockRMQDB())
+
+	// Success.
+	reply := &rpc.RouteMessageBatchReply{
+		Errors:      []string{"", "boo", ""},
+		NextInvoice: "next",
+	}
+	inv, errs, err := q.processRMBatchAck(reply, 3)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, inv, "next")
+	assert.NilErr(t, errs[0])
+	assert.ErrorIs(t, errs[1], routeMessageReplyError{})
+	assert.NilErr(t, errs[2])
+
+	// Wrong number of individual errors.
+	_, _, err = q.processRMBatchAck(reply, 2)
+	assert.NonNilErr(t, err)
+
+	// Invoice payment error.
+	reply = &rpc.RouteMessageBatchReply{Error: rpc.ErrRMInvoicePayment.Error()}
+	_, _, err = q.processRMBatchAck(reply, 3)
+	assert.ErrorIs(t, err, rpc.ErrRMInvoicePayment)
+}
+
 // TestCanceledRMQErrorsRM asserts that stopping the RMQ errors out the sending
 // rm.
 func TestCanceledRMQErrorsRM(t *testing.T) {
@@ -81,6 +81,10 @@ type serverSession struct {
 
 	policy clientintf.ServerPolicy
 
+	// clockSkew is the offset of the server clock relative to the local
+	// clock when the session was established.
+	clockSkew time.Duration
+
 	// Handler for pushed routed messages.
 	//
 	// If the handler returns an error that unwraps into an AckError
@@ -136,6 +140,10 @@ func (sess *serverSession) Policy() clientintf.ServerPolicy {
 	return sess.policy
 }
 
+func (sess *serverSession) ClockSkew() time.Duration {
+	return sess.clockSkew
+}
+
 // SendPRPC sends the given msg and payload to the server. This returns when
 // the msg has been sent with any errors generated during the send process.
 //
@@ -36,12 +36,22 @@ func payloadForCmd(cmd string) (interface{}, error) {
 		p = new(rpc.RouteMessage)
 	case rpc.TaggedCmdRouteMessageReply:
 		p = new(rpc.RouteMessageReply)
+	case rpc.TaggedCmdRouteMessageBatchReply:
+		p = new(rpc.RouteMessageBatchReply)
 	case rpc.TaggedCmdSubscribeRoutedMessagesReply:
 		p = new(rpc.SubscribeRoutedMessagesReply)
 	case rpc.TaggedCmdPushRoutedMessage:
 		p = new(rpc.PushRoutedMessage)
 	case rpc.TaggedCmdGetInvoiceReply:
 		p = new(rpc.GetInvoiceReply)
+	case rpc.TaggedCmdFetchDeferredRoutedMessagesReply:
+		p = new(rpc.FetchDeferredRoutedMessagesReply)
+	case rpc.TaggedCmdRegisterWakeupReply:
+		p = new(rpc.RegisterWakeupReply)
+	case rpc.TaggedCmdPublishResourceReply:
+		p = new(rpc.PublishResourceReply)
+	case rpc.TaggedCmdFetchPublishedResourceReply:
+		p = new(rpc.FetchPublishedResourceReply)
 	default:
 		return nil, errUnknownRPCCommand
 	}
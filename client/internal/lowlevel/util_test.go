@@ -386,6 +386,7 @@ func (m *mockServerSession) PaymentRates() (uint64, uint64)      { return 0, 0 }
 func (m *mockServerSession) ExpirationDays() int                 { return 7 }
 func (m *mockServerSession) Context() context.Context            { return context.Background() }
 func (m *mockServerSession) Policy() clientintf.ServerPolicy     { return m.policy }
+func (m *mockServerSession) ClockSkew() time.Duration            { return 0 }
 
 type mockRM string
 
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -157,10 +158,16 @@ func (ck *ConnKeeper) attemptWelcome(conn clientintf.Conn, kx msgReaderWriter) (
 		return nil, fmt.Errorf("unmarshal Welcome payload failed")
 	}
 
-	if wmsg.Version != rpc.ProtocolVersion {
+	if wmsg.Version < rpc.ProtocolVersion {
 		return nil, fmt.Errorf("protocol version mismatch: "+
-			"got %v wanted %v",
-			wmsg.Version,
+			"got %v wanted %v (the server runs an older version "+
+			"and needs to be updated)", wmsg.Version,
+			rpc.ProtocolVersion)
+	}
+	if wmsg.Version > rpc.ProtocolVersion {
+		return nil, fmt.Errorf("protocol version mismatch: "+
+			"got %v wanted %v (the server runs a newer version; "+
+			"update the client)", wmsg.Version,
 			rpc.ProtocolVersion)
 	}
 
@@ -186,6 +193,14 @@ func (ck *ConnKeeper) attemptWelcome(conn clientintf.Conn, kx msgReaderWriter) (
 
 		pushPaymentLifetime int64 = rpc.PropPushPaymentLifetimeDefault
 		maxPushInvoices     int64 = rpc.PropMaxPushInvoicesDefault
+
+		// Servers that do not advertise the max batched RMs prop do
+		// not support batched pushes.
+		maxBatchedRMs int64 = 0
+
+		subFilters    bool
+		pushGateways  []string
+		maxPubResSize int64
 	)
 
 	for _, v := range wmsg.Properties {
@@ -242,6 +257,29 @@ func (ck *ConnKeeper) attemptWelcome(conn clientintf.Conn, kx msgReaderWriter) (
 				return nil, fmt.Errorf("invalid max push invoices: %v", err)
 			}
 
+		case rpc.PropSubFilters:
+			subFilters = v.Value == rpc.PropSubFiltersDefault
+
+		case rpc.PropPushGateways:
+			for _, gw := range strings.Split(v.Value, ",") {
+				if gw = strings.TrimSpace(gw); gw != "" {
+					pushGateways = append(pushGateways, gw)
+				}
+			}
+
+		case rpc.PropMaxPublishedResourceSize:
+			maxPubResSize, err = strconv.ParseInt(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max published "+
+					"resource size: %v", err)
+			}
+
+		case rpc.PropMaxBatchedRMs:
+			maxBatchedRMs, err = strconv.ParseInt(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max batched RMs: %v", err)
+			}
+
 		default:
 			if v.Required {
 				errMsg := fmt.Sprintf("unhandled server property: %v", v.Key)
@@ -293,6 +331,7 @@ func (ck *ConnKeeper) attemptWelcome(conn clientintf.Conn, kx msgReaderWriter) (
 		return nil, fmt.Errorf("server did not provide time")
 	}
 	ck.log.Debugf("Server provided time %v", time.Unix(pt, 0).Format(time.RFC3339))
+	clockSkew := time.Until(time.Unix(pt, 0)).Round(time.Second)
 
 	// message size
 	if kx, ok := kx.(*session.KX); ok {
@@ -334,10 +373,16 @@ func (ck *ConnKeeper) attemptWelcome(conn clientintf.Conn, kx msgReaderWriter) (
 	sess.pingInterval = ck.cfg.PingInterval
 	sess.pushedRoutedMsgsHandler = ck.cfg.PushedRoutedMsgsHandler
 	sess.expirationDays = int(expd)
+	sess.clockSkew = clockSkew
 	sess.logPings = ck.cfg.LogPings
 	sess.policy = clientintf.ServerPolicy{
 		PushPaymentLifetime: time.Duration(pushPaymentLifetime) * time.Second,
 		MaxPushInvoices:     int(maxPushInvoices),
+		MaxBatchedRMs:       int(maxBatchedRMs),
+		SubFilters:          subFilters,
+		PushGateways:        pushGateways,
+
+		MaxPublishedResourceSize: int(maxPubResSize),
 	}
 
 	ck.log.Infof("Connected to server %s",
@@ -0,0 +1,173 @@
+// Package lrucache implements a size-bounded, least recently used cache that
+// records its hit rate and evictions as metrics.
+package lrucache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/companyzero/bisonrelay/client/metrics"
+)
+
+// Config is the configuration of a Cache.
+type Config struct {
+	// Name identifies the cache in the names of its metrics.
+	Name string
+
+	// MaxEntries is the max number of entries in the cache. Zero means no
+	// limit on the number of entries.
+	MaxEntries int
+
+	// MaxBytes is the max total size of the entries in the cache. Zero
+	// means no limit on the size of the entries.
+	MaxBytes int64
+
+	// Metrics records the hits, misses and evictions of the cache. May be
+	// nil.
+	Metrics *metrics.Metrics
+}
+
+// Stats are the statistics of a cache.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	Bytes     int64
+}
+
+// HitRate returns the ratio of lookups that were hits.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type entry[K comparable, V any] struct {
+	key  K
+	val  V
+	size int64
+}
+
+// Cache is a least recently used cache bounded by the number of entries and
+// their total size. It is safe for concurrent access.
+type Cache[K comparable, V any] struct {
+	cfg         Config
+	hitsName    string
+	missesName  string
+	evictedName string
+
+	mtx     sync.Mutex
+	ll      *list.List
+	entries map[K]*list.Element
+	bytes   int64
+	stats   Stats
+}
+
+// New creates a new cache.
+func New[K comparable, V any](cfg Config) *Cache[K, V] {
+	return &Cache[K, V]{
+		cfg:         cfg,
+		hitsName:    metrics.CacheHits(cfg.Name),
+		missesName:  metrics.CacheMisses(cfg.Name),
+		evictedName: metrics.CacheEvictions(cfg.Name),
+		ll:          list.New(),
+		entries:     make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value of the key and whether it was found in the cache.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mtx.Lock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses += 1
+		c.mtx.Unlock()
+		c.cfg.Metrics.Inc(c.missesName)
+		var v V
+		return v, false
+	}
+	c.ll.MoveToFront(el)
+	c.stats.Hits += 1
+	v := el.Value.(*entry[K, V]).val
+	c.mtx.Unlock()
+	c.cfg.Metrics.Inc(c.hitsName)
+	return v, true
+}
+
+// Put adds or replaces the value of the key in the cache. size is the size of
+// the value, which counts towards the MaxBytes limit. Values larger than
+// MaxBytes are not cached. The least recently used entries are evicted as
+// needed to respect the limits of the cache.
+func (c *Cache[K, V]) Put(key K, val V, size int64) {
+	c.mtx.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+	if c.cfg.MaxBytes > 0 && size > c.cfg.MaxBytes {
+		c.mtx.Unlock()
+		return
+	}
+	c.entries[key] = c.ll.PushFront(&entry[K, V]{key: key, val: val, size: size})
+	c.bytes += size
+
+	var evicted int
+	for c.overLimits() {
+		c.removeElement(c.ll.Back())
+		evicted += 1
+	}
+	c.stats.Evictions += uint64(evicted)
+	c.mtx.Unlock()
+
+	if evicted > 0 {
+		c.cfg.Metrics.Add(c.evictedName, float64(evicted))
+	}
+}
+
+// overLimits returns true if the cache has more entries or bytes than its
+// limits.
+//
+// This MUST be called with the mutex held.
+func (c *Cache[K, V]) overLimits() bool {
+	return (c.cfg.MaxEntries > 0 && c.ll.Len() > c.cfg.MaxEntries) ||
+		(c.cfg.MaxBytes > 0 && c.bytes > c.cfg.MaxBytes)
+}
+
+// removeElement removes the element from the cache.
+//
+// This MUST be called with the mutex held.
+func (c *Cache[K, V]) removeElement(el *list.Element) {
+	e := c.ll.Remove(el).(*entry[K, V])
+	delete(c.entries, e.key)
+	c.bytes -= e.size
+}
+
+// Delete removes the key from the cache.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mtx.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+	c.mtx.Unlock()
+}
+
+// Purge removes all entries from the cache.
+func (c *Cache[K, V]) Purge() {
+	c.mtx.Lock()
+	c.ll.Init()
+	c.entries = make(map[K]*list.Element)
+	c.bytes = 0
+	c.mtx.Unlock()
+}
+
+// Stats returns the current statistics of the cache.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mtx.Lock()
+	stats := c.stats
+	stats.Entries = c.ll.Len()
+	stats.Bytes = c.bytes
+	c.mtx.Unlock()
+	return stats
+}
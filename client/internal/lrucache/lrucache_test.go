@@ -0,0 +1,88 @@
+package lrucache
+
+import (
+	"testing"
+
+	"github.com/companyzero/bisonrelay/client/metrics"
+	"github.com/companyzero/bisonrelay/internal/assert"
+)
+
+// TestEvictsLeastRecentlyUsed tests that the least recently used entries are
+// evicted once the cache has more entries than its limit.
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](Config{MaxEntries: 2})
+	c.Put("a", 1, 0)
+	c.Put("b", 2, 0)
+
+	// Access "a" so that "b" becomes the least recently used entry.
+	v, ok := c.Get("a")
+	assert.BoolIs(t, ok, true)
+	assert.DeepEqual(t, v, 1)
+
+	c.Put("c", 3, 0)
+	_, ok = c.Get("b")
+	assert.BoolIs(t, ok, false)
+	_, ok = c.Get("a")
+	assert.BoolIs(t, ok, true)
+	_, ok = c.Get("c")
+	assert.BoolIs(t, ok, true)
+
+	stats := c.Stats()
+	assert.DeepEqual(t, stats.Hits, uint64(3))
+	assert.DeepEqual(t, stats.Misses, uint64(1))
+	assert.DeepEqual(t, stats.Evictions, uint64(1))
+	assert.DeepEqual(t, stats.Entries, 2)
+	assert.DeepEqual(t, stats.HitRate(), 0.75)
+}
+
+// TestMaxBytes tests that the cache respects its size limit.
+func TestMaxBytes(t *testing.T) {
+	c := New[string, []byte](Config{MaxBytes: 10})
+	c.Put("a", make([]byte, 4), 4)
+	c.Put("b", make([]byte, 4), 4)
+	assert.DeepEqual(t, c.Stats().Bytes, int64(8))
+
+	// Adding "c" requires evicting "a".
+	c.Put("c", make([]byte, 4), 4)
+	_, ok := c.Get("a")
+	assert.BoolIs(t, ok, false)
+	assert.DeepEqual(t, c.Stats().Bytes, int64(8))
+
+	// Replacing an entry updates the size.
+	c.Put("c", make([]byte, 2), 2)
+	assert.DeepEqual(t, c.Stats().Bytes, int64(6))
+	assert.DeepEqual(t, c.Stats().Entries, 2)
+
+	// Values larger than the limit are not cached and replace the
+	// existing value.
+	c.Put("b", make([]byte, 11), 11)
+	_, ok = c.Get("b")
+	assert.BoolIs(t, ok, false)
+	assert.DeepEqual(t, c.Stats().Bytes, int64(2))
+
+	c.Delete("c")
+	c.Put("d", nil, 0)
+	c.Purge()
+	stats := c.Stats()
+	assert.DeepEqual(t, stats.Entries, 0)
+	assert.DeepEqual(t, stats.Bytes, int64(0))
+}
+
+// TestMetrics tests that the cache records its metrics.
+func TestMetrics(t *testing.T) {
+	agg := metrics.NewAggregator(nil)
+	c := New[int, int](Config{
+		Name:       "test",
+		MaxEntries: 1,
+		Metrics:    metrics.New(agg),
+	})
+	c.Put(1, 1, 0)
+	c.Get(1)
+	c.Get(2)
+	c.Put(2, 2, 0)
+
+	snap := agg.Snapshot()
+	assert.DeepEqual(t, snap.Counters[metrics.CacheHits("test")], 1.0)
+	assert.DeepEqual(t, snap.Counters[metrics.CacheMisses("test")], 1.0)
+	assert.DeepEqual(t, snap.Counters[metrics.CacheEvictions("test")], 1.0)
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultBuckets are the default upper bounds of the buckets of histograms,
+// suited for latencies measured in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// HistogramSnapshot is the state of a histogram.
+type HistogramSnapshot struct {
+	// Buckets are the upper bounds of the buckets.
+	Buckets []float64
+
+	// Counts are the cumulative number of observations less than or
+	// equal to the upper bound of each bucket.
+	Counts []uint64
+
+	// Count is the total number of observations.
+	Count uint64
+
+	// Sum is the sum of all observations.
+	Sum float64
+}
+
+// Snapshot is the state of all metrics of an Aggregator.
+type Snapshot struct {
+	Counters   map[string]float64
+	Histograms map[string]HistogramSnapshot
+}
+
+// CounterNames returns the names of the counters, sorted.
+func (s *Snapshot) CounterNames() []string {
+	names := make([]string, 0, len(s.Counters))
+	for name := range s.Counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HistogramNames returns the names of the histograms, sorted.
+func (s *Snapshot) HistogramNames() []string {
+	names := make([]string, 0, len(s.Histograms))
+	for name := range s.Histograms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type histogram struct {
+	counts []uint64 // Not cumulative.
+	count  uint64
+	sum    float64
+}
+
+// Aggregator is a Sink that keeps the current value of the counters and
+// histograms in memory.
+type Aggregator struct {
+	buckets []float64
+
+	mtx        sync.Mutex
+	counters   map[string]float64
+	histograms map[string]*histogram
+}
+
+// NewAggregator creates a new aggregator, with histograms that use the given
+// bucket upper bounds. If buckets is empty, DefaultBuckets is used.
+func NewAggregator(buckets []float64) *Aggregator {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Aggregator{
+		buckets:    buckets,
+		counters:   make(map[string]float64),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+// AddCounter is part of the Sink interface.
+func (a *Aggregator) AddCounter(name string, delta float64) {
+	a.mtx.Lock()
+	a.counters[name] += delta
+	a.mtx.Unlock()
+}
+
+// ObserveHistogram is part of the Sink interface.
+func (a *Aggregator) ObserveHistogram(name string, value float64) {
+	i := sort.SearchFloat64s(a.buckets, value)
+	a.mtx.Lock()
+	h := a.histograms[name]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(a.buckets))}
+		a.histograms[name] = h
+	}
+	if i < len(h.counts) {
+		h.counts[i] += 1
+	}
+	h.count += 1
+	h.sum += value
+	a.mtx.Unlock()
+}
+
+// Snapshot returns the current state of the metrics.
+func (a *Aggregator) Snapshot() Snapshot {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	snap := Snapshot{
+		Counters:   make(map[string]float64, len(a.counters)),
+		Histograms: make(map[string]HistogramSnapshot, len(a.histograms)),
+	}
+	for name, v := range a.counters {
+		snap.Counters[name] = v
+	}
+	for name, h := range a.histograms {
+		hs := HistogramSnapshot{
+			Buckets: a.buckets,
+			Counts:  make([]uint64, len(h.counts)),
+			Count:   h.count,
+			Sum:     h.sum,
+		}
+		var cum uint64
+		for i, c := range h.counts {
+			cum += c
+			hs.Counts[i] = cum
+		}
+		snap.Histograms[name] = hs
+	}
+	return snap
+}
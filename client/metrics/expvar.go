@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"expvar"
+)
+
+// ExpvarSink is a Sink that publishes the metrics as expvar variables, which
+// are served (as JSON) by the handler of the expvar package. Histograms are
+// published as the count and sum of their observations.
+type ExpvarSink struct {
+	vars *expvar.Map
+}
+
+// NewExpvarSink creates a new sink that publishes the metrics in an expvar
+// map with the given name. If a map with that name was already published, it
+// is reused.
+func NewExpvarSink(name string) *ExpvarSink {
+	vars, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		vars = expvar.NewMap(name)
+	}
+	return &ExpvarSink{vars: vars}
+}
+
+// AddCounter is part of the Sink interface.
+func (es *ExpvarSink) AddCounter(name string, delta float64) {
+	es.vars.AddFloat(name, delta)
+}
+
+// ObserveHistogram is part of the Sink interface.
+func (es *ExpvarSink) ObserveHistogram(name string, value float64) {
+	es.vars.AddFloat(name+"_count", 1)
+	es.vars.AddFloat(name+"_sum", value)
+}
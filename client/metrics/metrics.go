@@ -0,0 +1,122 @@
+// Package metrics records counters and histograms of the work done by the
+// client (messages sent and received, resource fetch latencies, store orders,
+// etc) and forwards them to pluggable sinks, such as a Prometheus exporter or
+// expvar.
+//
+// All methods are safe to call on a nil Metrics value, in which case they do
+// nothing. This allows code to be instrumented unconditionally, with metrics
+// disabled by not creating a Metrics.
+package metrics
+
+import (
+	"time"
+)
+
+// Names of the metrics recorded by the client and its subsystems.
+const (
+	// RMsSent is the counter of RMs sent to remote users.
+	RMsSent = "br_rms_sent_total"
+
+	// RMSendErrors is the counter of RMs that failed to be sent.
+	RMSendErrors = "br_rm_send_errors_total"
+
+	// RMsReceived is the counter of RMs received from remote users.
+	RMsReceived = "br_rms_received_total"
+
+	// ResourceFetchSeconds is the histogram of the time between sending
+	// a resource request and receiving its reply.
+	ResourceFetchSeconds = "br_resource_fetch_seconds"
+
+	// ResourceRequestsServed is the counter of resource requests from
+	// remote users that were fulfilled.
+	ResourceRequestsServed = "br_resource_requests_served_total"
+
+	// ResourceFulfillSeconds is the histogram of the time taken to
+	// fulfill resource requests from remote users.
+	ResourceFulfillSeconds = "br_resource_fulfill_seconds"
+
+	// InvoiceGenFailures is the counter of failures to generate LN
+	// invoices requested by remote users or by the store.
+	InvoiceGenFailures = "br_invoice_generation_failures_total"
+
+	// StoreOrdersPlaced is the counter of orders placed in the store.
+	StoreOrdersPlaced = "br_store_orders_placed_total"
+)
+
+// CacheHits returns the name of the counter of lookups of the named cache that
+// were hits.
+func CacheHits(cache string) string {
+	return "br_cache_" + cache + "_hits_total"
+}
+
+// CacheMisses returns the name of the counter of lookups of the named cache
+// that were misses.
+func CacheMisses(cache string) string {
+	return "br_cache_" + cache + "_misses_total"
+}
+
+// CacheEvictions returns the name of the counter of entries evicted from the
+// named cache to respect its size limits.
+func CacheEvictions(cache string) string {
+	return "br_cache_" + cache + "_evictions_total"
+}
+
+// Sink receives the recorded metrics.
+//
+// Sinks must be safe for concurrent use.
+type Sink interface {
+	// AddCounter adds delta to the counter with the given name.
+	AddCounter(name string, delta float64)
+
+	// ObserveHistogram records an observation of the histogram with the
+	// given name.
+	ObserveHistogram(name string, value float64)
+}
+
+// Metrics records metrics and forwards them to its sinks.
+type Metrics struct {
+	sinks []Sink
+}
+
+// New creates a new Metrics that forwards the recorded metrics to the passed
+// sinks.
+func New(sinks ...Sink) *Metrics {
+	return &Metrics{sinks: sinks}
+}
+
+// Add adds delta to the counter with the given name.
+func (m *Metrics) Add(name string, delta float64) {
+	if m == nil {
+		return
+	}
+	for _, s := range m.sinks {
+		s.AddCounter(name, delta)
+	}
+}
+
+// Inc increments the counter with the given name.
+func (m *Metrics) Inc(name string) {
+	m.Add(name, 1)
+}
+
+// Observe records an observation of the histogram with the given name.
+func (m *Metrics) Observe(name string, value float64) {
+	if m == nil {
+		return
+	}
+	for _, s := range m.sinks {
+		s.ObserveHistogram(name, value)
+	}
+}
+
+// ObserveDuration records the duration (in seconds) as an observation of the
+// histogram with the given name.
+func (m *Metrics) ObserveDuration(name string, d time.Duration) {
+	m.Observe(name, d.Seconds())
+}
+
+// ObserveSince records the time elapsed since start (in seconds) as an
+// observation of the histogram with the given name.
+func (m *Metrics) ObserveSince(name string, start time.Time) {
+	m.ObserveDuration(name, time.Since(start))
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNilMetrics tests that a nil Metrics can be used.
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	m.Inc(RMsSent)
+	m.Add(RMsSent, 2)
+	m.Observe(ResourceFetchSeconds, 1)
+	m.ObserveSince(ResourceFetchSeconds, time.Now())
+}
+
+// TestAggregator tests the aggregation of counters and histograms.
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+
+	agg := NewAggregator([]float64{1, 0.1, 10})
+	m := New(agg)
+	m.Inc(RMsSent)
+	m.Add(RMsSent, 2)
+	m.Inc(RMsReceived)
+	for _, v := range []float64{0.05, 0.1, 0.5, 5, 50} {
+		m.Observe(ResourceFetchSeconds, v)
+	}
+
+	snap := agg.Snapshot()
+	if got := snap.Counters[RMsSent]; got != 3 {
+		t.Fatalf("unexpected sent counter: got %v, want 3", got)
+	}
+	if got := snap.Counters[RMsReceived]; got != 1 {
+		t.Fatalf("unexpected received counter: got %v, want 1", got)
+	}
+
+	h := snap.Histograms[ResourceFetchSeconds]
+	wantBuckets := []float64{0.1, 1, 10}
+	wantCounts := []uint64{2, 3, 4}
+	for i := range wantBuckets {
+		if h.Buckets[i] != wantBuckets[i] || h.Counts[i] != wantCounts[i] {
+			t.Fatalf("unexpected bucket %d: got %v=%d, want %v=%d",
+				i, h.Buckets[i], h.Counts[i], wantBuckets[i],
+				wantCounts[i])
+		}
+	}
+	if h.Count != 5 {
+		t.Fatalf("unexpected count: got %d, want 5", h.Count)
+	}
+	if h.Sum != 55.65 {
+		t.Fatalf("unexpected sum: got %v, want 55.65", h.Sum)
+	}
+}
+
+// TestPrometheusExporter tests the output of the prometheus exporter.
+func TestPrometheusExporter(t *testing.T) {
+	t.Parallel()
+
+	pe := NewPrometheusExporter([]float64{1})
+	m := New(pe)
+	m.Inc(StoreOrdersPlaced)
+	m.Observe(ResourceFulfillSeconds, 0.5)
+	m.Observe(ResourceFulfillSeconds, 2)
+
+	var b bytes.Buffer
+	if _, err := pe.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"# TYPE br_store_orders_placed_total counter",
+		"br_store_orders_placed_total 1",
+		"# TYPE br_resource_fulfill_seconds histogram",
+		`br_resource_fulfill_seconds_bucket{le="1"} 1`,
+		`br_resource_fulfill_seconds_bucket{le="+Inf"} 2`,
+		"br_resource_fulfill_seconds_sum 2.5",
+		"br_resource_fulfill_seconds_count 2",
+	}, "\n") + "\n"
+	if got := b.String(); got != want {
+		t.Fatalf("unexpected output: got\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestExpvarSink tests that metrics are published as expvars.
+func TestExpvarSink(t *testing.T) {
+	t.Parallel()
+
+	m := New(NewExpvarSink("brtestmetrics"))
+	m.Inc(InvoiceGenFailures)
+	m.Observe(ResourceFetchSeconds, 1.5)
+
+	// Creating a sink with the same name reuses the published map.
+	New(NewExpvarSink("brtestmetrics")).Inc(InvoiceGenFailures)
+
+	var vars map[string]float64
+	err := json.Unmarshal([]byte(expvar.Get("brtestmetrics").String()), &vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{
+		InvoiceGenFailures:              2,
+		ResourceFetchSeconds + "_count": 1,
+		ResourceFetchSeconds + "_sum":   1.5,
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Fatalf("unexpected value of %s: got %v, want %v", k,
+				vars[k], v)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// PrometheusExporter is a Sink that serves the metrics over HTTP in the
+// Prometheus text exposition format.
+type PrometheusExporter struct {
+	*Aggregator
+}
+
+// NewPrometheusExporter creates a new exporter with histograms that use the
+// given bucket upper bounds. If buckets is empty, DefaultBuckets is used.
+func NewPrometheusExporter(buckets []float64) *PrometheusExporter {
+	return &PrometheusExporter{Aggregator: NewAggregator(buckets)}
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WriteTo writes the current state of the metrics to w in the Prometheus text
+// exposition format.
+func (pe *PrometheusExporter) WriteTo(w io.Writer) (int64, error) {
+	snap := pe.Snapshot()
+	bw := bufio.NewWriter(w)
+	var n int64
+	write := func(ss ...string) {
+		for _, s := range ss {
+			nn, _ := bw.WriteString(s)
+			n += int64(nn)
+		}
+	}
+
+	for _, name := range snap.CounterNames() {
+		write("# TYPE ", name, " counter\n")
+		write(name, " ", formatFloat(snap.Counters[name]), "\n")
+	}
+	for _, name := range snap.HistogramNames() {
+		h := snap.Histograms[name]
+		write("# TYPE ", name, " histogram\n")
+		for i, le := range h.Buckets {
+			write(name, `_bucket{le="`, formatFloat(le), `"} `,
+				strconv.FormatUint(h.Counts[i], 10), "\n")
+		}
+		write(name, `_bucket{le="+Inf"} `, strconv.FormatUint(h.Count, 10), "\n")
+		write(name, "_sum ", formatFloat(h.Sum), "\n")
+		write(name, "_count ", strconv.FormatUint(h.Count, 10), "\n")
+	}
+	return n, bw.Flush()
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (pe *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	pe.WriteTo(w)
+}
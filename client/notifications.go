@@ -163,6 +163,14 @@ type OnGCAdminsChangedNtfn func(ru *RemoteUser, gc rpc.RMGroupList, added, remov
 
 func (_ OnGCAdminsChangedNtfn) typ() string { return onGCAdminsChangedNtfnType }
 
+const onGCReadOnlyChangedNtfnType = "onGCReadOnlyChanged"
+
+// OnGCReadOnlyChangedNtfn is called when a GC admin changes whether the GC is
+// read-only or the list of members that may post in it.
+type OnGCReadOnlyChangedNtfn func(ru *RemoteUser, gc rpc.RMGroupList)
+
+func (_ OnGCReadOnlyChangedNtfn) typ() string { return onGCReadOnlyChangedNtfnType }
+
 const onKXSearchCompletedNtfnType = "kxSearchCompleted"
 
 // OnKXSearchCompleted is a handler for completed KX search procedures.
@@ -194,6 +202,83 @@ type OnServerSessionChangedNtfn func(connected bool, pushRate, subRate, expirati
 
 func (_ OnServerSessionChangedNtfn) typ() string { return onServerSessionChangedNtfnType }
 
+const onCompatWarningNtfnType = "onCompatWarning"
+
+// OnCompatWarningNtfn is called when a feature is degraded or disabled due to
+// a remote user (or the server, in which case user is nil) running an
+// incompatible version. Each warning is only notified once per session.
+type OnCompatWarningNtfn func(user *RemoteUser, warn CompatWarning)
+
+func (_ OnCompatWarningNtfn) typ() string { return onCompatWarningNtfnType }
+
+const onClockSkewWarningNtfnType = "onClockSkewWarning"
+
+// OnClockSkewWarningNtfn is called when the clock of a remote user (or the
+// local clock relative to the server clock, in which case user is nil) is
+// skewed enough to break the expiry of invoices and the timestamps of messages
+// and orders. Each warning is only notified once per session.
+type OnClockSkewWarningNtfn func(user *RemoteUser, warn ClockSkewWarning)
+
+func (_ OnClockSkewWarningNtfn) typ() string { return onClockSkewWarningNtfnType }
+
+const onRatchetHealthAlertNtfnType = "onRatchetHealthAlert"
+
+// OnRatchetHealthAlertNtfn is called when the periodic ratchet health check
+// detects a new anomaly in the ratchets or KXs of the client. Each alert is
+// only notified once while it remains active.
+type OnRatchetHealthAlertNtfn func(alert RatchetHealthAlert)
+
+func (_ OnRatchetHealthAlertNtfn) typ() string { return onRatchetHealthAlertNtfnType }
+
+const onFileHookProgressNtfnType = "onFileHookProgress"
+
+// OnFileHookProgressNtfn is called when a pre-send or post-receive file hook
+// reports its progress and when it finishes running.
+type OnFileHookProgressNtfn func(ev FileHookEvent)
+
+func (_ OnFileHookProgressNtfn) typ() string { return onFileHookProgressNtfnType }
+
+const onContentSummarizedNtfnType = "onContentSummarized"
+
+// OnContentSummarizedNtfn is called when a summary of a post or of the
+// backlog of a GC is produced.
+type OnContentSummarizedNtfn func(ev ContentSummaryEvent)
+
+func (_ OnContentSummarizedNtfn) typ() string { return onContentSummarizedNtfnType }
+
+const onSyncProgressNtfnType = "onSyncProgress"
+
+// OnSyncProgressNtfn is called with the progress of the lite sync startup
+// mode.
+type OnSyncProgressNtfn func(progress SyncProgress)
+
+func (_ OnSyncProgressNtfn) typ() string { return onSyncProgressNtfnType }
+
+const onCompactionProgressNtfnType = "onCompactionProgress"
+
+// OnCompactionProgressNtfn is called with the progress of the compaction of
+// the client storage.
+type OnCompactionProgressNtfn func(progress CompactionProgress)
+
+func (_ OnCompactionProgressNtfn) typ() string { return onCompactionProgressNtfnType }
+
+const onPaymentFeeLimitExceededNtfnType = "onPaymentFeeLimitExceeded"
+
+// OnPaymentFeeLimitExceededNtfn is called when an outgoing payment is skipped
+// because its estimated fee exceeds the fee policy of its category.
+type OnPaymentFeeLimitExceededNtfn func(category clientintf.PaymentCategory,
+	amountMAtoms, estimatedFee, maxFee int64)
+
+func (_ OnPaymentFeeLimitExceededNtfn) typ() string { return onPaymentFeeLimitExceededNtfnType }
+
+const onPaymentApprovalRequestedNtfnType = "onPaymentApprovalRequested"
+
+// OnPaymentApprovalRequestedNtfn is called when an outgoing payment is held
+// until it is approved or denied, in the audit mode of outgoing payments.
+type OnPaymentApprovalRequestedNtfn func(approval PaymentApproval)
+
+func (_ OnPaymentApprovalRequestedNtfn) typ() string { return onPaymentApprovalRequestedNtfnType }
+
 const onOnboardStateChangedNtfnType = "onOnboardStateChanged"
 
 type OnOnboardStateChangedNtfn func(state clientintf.OnboardState, err error)
@@ -211,6 +296,16 @@ type OnResourceFetchedNtfn func(ru *RemoteUser, fr clientdb.FetchedResource, ses
 
 func (_ OnResourceFetchedNtfn) typ() string { return onResourceFetchedNtfnType }
 
+const onResourceFetchProgressNtfnType = "onResourceFetchProgress"
+
+// OnResourceFetchProgressNtfn is called when a chunk of a resource reply that
+// was split into multiple chunks is received. The OnResourceFetchedNtfn is
+// called once all chunks are received.
+type OnResourceFetchProgressNtfn func(ru *RemoteUser, tag rpc.ResourceTag,
+	chunks, totalChunks int, size int64)
+
+func (_ OnResourceFetchProgressNtfn) typ() string { return onResourceFetchProgressNtfnType }
+
 const onTipUserInvoiceGeneratedNtfnType = "onTipUserInvoiceGenerated"
 
 // OnTipUserInvoiceGeneratedNtfn is called when the local client generates an
@@ -244,6 +339,14 @@ type OnTipReceivedNtfn func(ru *RemoteUser, amountMAtoms int64)
 
 func (_ OnTipReceivedNtfn) typ() string { return onTipReceivedNtfnType }
 
+const onPaymentProofReceivedNtfnType = "onPaymentProofReceived"
+
+// OnPaymentProofReceivedNtfn is called when a remote user sends a verified
+// proof of the payment of an invoice generated by the local client.
+type OnPaymentProofReceivedNtfn func(ru *RemoteUser, proof rpc.PaymentProof)
+
+func (_ OnPaymentProofReceivedNtfn) typ() string { return onPaymentProofReceivedNtfnType }
+
 const onMessageContentFilteredNtfType = "onMsgContentFiltered"
 
 // MsgContentFilteredEvent is the data for a message content filter event.
@@ -310,15 +413,49 @@ type NotificationHandler interface {
 	typ() string
 }
 
+// NotificationFilter is a filter of the notifications of the handler type T.
+// Filters are called with the next handler in the chain and return the
+// handler that is called instead of it. Filters may suppress notifications
+// (by not calling next), modify their arguments or enrich them before calling
+// next.
+//
+// Filters are applied to each registered handler of the notification type,
+// in the order they were added, so they should not have side effects that
+// must happen only once per notification.
+type NotificationFilter[T NotificationHandler] func(next T) T
+
+// filteredTyp returns the type of the notifications filtered by the filter.
+func (_ NotificationFilter[T]) filteredTyp() string {
+	var h T
+	return h.typ()
+}
+
+// wrap wraps the handler h, which MUST be of type T.
+func (f NotificationFilter[T]) wrap(h interface{}) interface{} {
+	return f(h.(T))
+}
+
+// NotificationFilterer is the interface of NotificationFilter.
+type NotificationFilterer interface {
+	filteredTyp() string
+	wrap(h interface{}) interface{}
+}
+
 type handler[T any] struct {
 	handler T
 	async   bool
 }
 
+type filter struct {
+	id     uint
+	filter NotificationFilterer
+}
+
 type handlersFor[T any] struct {
 	mtx      sync.Mutex
 	next     uint
 	handlers map[uint]handler[T]
+	filters  []filter
 }
 
 func (hn *handlersFor[T]) register(h T, async bool) NotificationRegistration {
@@ -347,13 +484,49 @@ func (hn *handlersFor[T]) register(h T, async bool) NotificationRegistration {
 	}
 }
 
+func (hn *handlersFor[T]) AddFilter(f NotificationFilterer) NotificationRegistration {
+	var id uint
+
+	hn.mtx.Lock()
+	id, hn.next = hn.next, hn.next+1
+	hn.filters = append(hn.filters, filter{id: id, filter: f})
+	hn.mtx.Unlock()
+
+	return NotificationRegistration{
+		unreg: func() bool {
+			hn.mtx.Lock()
+			defer hn.mtx.Unlock()
+			for i := range hn.filters {
+				if hn.filters[i].id == id {
+					hn.filters = append(hn.filters[:i:i], hn.filters[i+1:]...)
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// chain returns the handler wrapped by the filters. This MUST be called with
+// the mutex held.
+func (hn *handlersFor[T]) chain(h T) T {
+	for i := len(hn.filters) - 1; i >= 0; i-- {
+		h = hn.filters[i].filter.wrap(h).(T)
+	}
+	return h
+}
+
 func (hn *handlersFor[T]) visit(f func(T)) {
 	hn.mtx.Lock()
 	for _, h := range hn.handlers {
+		handler := h.handler
+		if len(hn.filters) > 0 {
+			handler = hn.chain(handler)
+		}
 		if h.async {
-			go f(h.handler)
+			go f(handler)
 		} else {
-			f(h.handler)
+			f(handler)
 		}
 	}
 	hn.mtx.Unlock()
@@ -376,6 +549,7 @@ func (hn *handlersFor[T]) AnyRegistered() bool {
 
 type handlersRegistry interface {
 	Register(v interface{}, async bool) NotificationRegistration
+	AddFilter(f NotificationFilterer) NotificationRegistration
 	AnyRegistered() bool
 }
 
@@ -411,6 +585,31 @@ func (nmgr *NotificationManager) RegisterSync(handler NotificationHandler) Notif
 	return nmgr.register(handler, false)
 }
 
+// AddFilter adds a filter to the end of the chain of filters of the filtered
+// notification type. The filter is called before the registered handlers of
+// the notification and may suppress, modify or enrich it. Unregistering the
+// returned registration removes the filter from the chain.
+//
+// Example of a filter that suppresses PMs from a given user:
+//
+//	nmgr.AddFilter(client.NotificationFilter[client.OnPMNtfn](
+//		func(next client.OnPMNtfn) client.OnPMNtfn {
+//			return func(ru *client.RemoteUser, pm rpc.RMPrivateMessage, ts time.Time) {
+//				if ru.ID() != blocked {
+//					next(ru, pm, ts)
+//				}
+//			}
+//		}))
+func (nmgr *NotificationManager) AddFilter(filter NotificationFilterer) NotificationRegistration {
+	handlers := nmgr.handlers[filter.filteredTyp()]
+	if handlers == nil {
+		panic(fmt.Sprintf("forgot to init the handler type of filter %T "+
+			"in NewNotificationManager", filter))
+	}
+
+	return handlers.AddFilter(filter)
+}
+
 // AnyRegistered returns true if there are any handlers registered for the given
 // handler type.
 func (ngmr *NotificationManager) AnyRegistered(handler NotificationHandler) bool {
@@ -531,6 +730,11 @@ func (nmgr *NotificationManager) notifyGCAdminsChanged(ru *RemoteUser, gc rpc.RM
 		visit(func(h OnGCAdminsChangedNtfn) { h(ru, gc, added, removed) })
 }
 
+func (nmgr *NotificationManager) notifyGCReadOnlyChanged(ru *RemoteUser, gc rpc.RMGroupList) {
+	nmgr.handlers[onGCReadOnlyChangedNtfnType].(*handlersFor[OnGCReadOnlyChangedNtfn]).
+		visit(func(h OnGCReadOnlyChangedNtfn) { h(ru, gc) })
+}
+
 func (nmgr *NotificationManager) notifyTipAttemptProgress(ru *RemoteUser, amtMAtoms int64, completed bool, attempt int, attemptErr error, willRetry bool) {
 	nmgr.handlers[onTipAttemptProgressNtfnType].(*handlersFor[OnTipAttemptProgressNtfn]).
 		visit(func(h OnTipAttemptProgressNtfn) { h(ru, amtMAtoms, completed, attempt, attemptErr, willRetry) })
@@ -551,6 +755,31 @@ func (nmgr *NotificationManager) notifyServerSessionChanged(connected bool, push
 		visit(func(h OnServerSessionChangedNtfn) { h(connected, pushRate, subRate, expDays) })
 }
 
+func (nmgr *NotificationManager) notifyCompatWarning(ru *RemoteUser, warn CompatWarning) {
+	nmgr.handlers[onCompatWarningNtfnType].(*handlersFor[OnCompatWarningNtfn]).
+		visit(func(h OnCompatWarningNtfn) { h(ru, warn) })
+}
+
+func (nmgr *NotificationManager) notifyClockSkewWarning(ru *RemoteUser, warn ClockSkewWarning) {
+	nmgr.handlers[onClockSkewWarningNtfnType].(*handlersFor[OnClockSkewWarningNtfn]).
+		visit(func(h OnClockSkewWarningNtfn) { h(ru, warn) })
+}
+
+func (nmgr *NotificationManager) notifyRatchetHealthAlert(alert RatchetHealthAlert) {
+	nmgr.handlers[onRatchetHealthAlertNtfnType].(*handlersFor[OnRatchetHealthAlertNtfn]).
+		visit(func(h OnRatchetHealthAlertNtfn) { h(alert) })
+}
+
+func (nmgr *NotificationManager) notifyFileHookProgress(ev FileHookEvent) {
+	nmgr.handlers[onFileHookProgressNtfnType].(*handlersFor[OnFileHookProgressNtfn]).
+		visit(func(h OnFileHookProgressNtfn) { h(ev) })
+}
+
+func (nmgr *NotificationManager) notifyContentSummarized(ev ContentSummaryEvent) {
+	nmgr.handlers[onContentSummarizedNtfnType].(*handlersFor[OnContentSummarizedNtfn]).
+		visit(func(h OnContentSummarizedNtfn) { h(ev) })
+}
+
 func (nmgr *NotificationManager) notifyOnOnboardStateChanged(state clientintf.OnboardState, err error) {
 	nmgr.handlers[onOnboardStateChangedNtfnType].(*handlersFor[OnOnboardStateChangedNtfn]).
 		visit(func(h OnOnboardStateChangedNtfn) { h(state, err) })
@@ -562,6 +791,13 @@ func (nmgr *NotificationManager) notifyResourceFetched(ru *RemoteUser,
 		visit(func(h OnResourceFetchedNtfn) { h(ru, fr, sess) })
 }
 
+func (nmgr *NotificationManager) notifyResourceFetchProgress(ru *RemoteUser,
+	tag rpc.ResourceTag, chunks, totalChunks int, size int64) {
+
+	nmgr.handlers[onResourceFetchProgressNtfnType].(*handlersFor[OnResourceFetchProgressNtfn]).
+		visit(func(h OnResourceFetchProgressNtfn) { h(ru, tag, chunks, totalChunks, size) })
+}
+
 func (nmgr *NotificationManager) notifyHandshakeStage(ru *RemoteUser, msgtype string) {
 	nmgr.handlers[onHandshakeStageNtfnType].(*handlersFor[OnHandshakeStageNtfn]).
 		visit(func(h OnHandshakeStageNtfn) { h(ru, msgtype) })
@@ -580,6 +816,11 @@ func (nmgr *NotificationManager) notifyTipReceived(ru *RemoteUser, amountMAtoms
 		visit(func(h OnTipReceivedNtfn) { h(ru, amountMAtoms) })
 }
 
+func (nmgr *NotificationManager) notifyPaymentProofReceived(ru *RemoteUser, proof rpc.PaymentProof) {
+	nmgr.handlers[onPaymentProofReceivedNtfnType].(*handlersFor[OnPaymentProofReceivedNtfn]).
+		visit(func(h OnPaymentProofReceivedNtfn) { h(ru, proof) })
+}
+
 func (nmgr *NotificationManager) notifyMsgContentFiltered(e MsgContentFilteredEvent) {
 	nmgr.handlers[onMessageContentFilteredNtfType].(*handlersFor[OnMsgContentFilteredNtfn]).
 		visit(func(h OnMsgContentFilteredNtfn) {
@@ -597,6 +838,27 @@ func (nmgr *NotificationManager) notifyPostsListReceived(ru *RemoteUser, postLis
 		visit(func(h OnPostsListReceived) { h(ru, postList) })
 }
 
+func (nmgr *NotificationManager) notifySyncProgress(progress SyncProgress) {
+	nmgr.handlers[onSyncProgressNtfnType].(*handlersFor[OnSyncProgressNtfn]).
+		visit(func(h OnSyncProgressNtfn) { h(progress) })
+}
+
+func (nmgr *NotificationManager) notifyCompactionProgress(progress CompactionProgress) {
+	nmgr.handlers[onCompactionProgressNtfnType].(*handlersFor[OnCompactionProgressNtfn]).
+		visit(func(h OnCompactionProgressNtfn) { h(progress) })
+}
+
+func (nmgr *NotificationManager) notifyPaymentFeeLimitExceeded(category clientintf.PaymentCategory,
+	amountMAtoms, estimatedFee, maxFee int64) {
+	nmgr.handlers[onPaymentFeeLimitExceededNtfnType].(*handlersFor[OnPaymentFeeLimitExceededNtfn]).
+		visit(func(h OnPaymentFeeLimitExceededNtfn) { h(category, amountMAtoms, estimatedFee, maxFee) })
+}
+
+func (nmgr *NotificationManager) notifyPaymentApprovalRequested(approval PaymentApproval) {
+	nmgr.handlers[onPaymentApprovalRequestedNtfnType].(*handlersFor[OnPaymentApprovalRequestedNtfn]).
+		visit(func(h OnPaymentApprovalRequestedNtfn) { h(approval) })
+}
+
 func (nmgr *NotificationManager) notifyUnsubscribingIdleRemote(ru *RemoteUser, lastDecTime time.Time) {
 	nmgr.handlers[onUnsubscribingIdleRemoteClient].(*handlersFor[OnUnsubscribingIdleRemoteClient]).
 		visit(func(h OnUnsubscribingIdleRemoteClient) { h(ru, lastDecTime) })
@@ -629,6 +891,8 @@ func NewNotificationManager() *NotificationManager {
 			onGCKilledNtfnType:         &handlersFor[OnGCKilledNtfn]{},
 			onGCAdminsChangedNtfnType:  &handlersFor[OnGCAdminsChangedNtfn]{},
 
+			onGCReadOnlyChangedNtfnType: &handlersFor[OnGCReadOnlyChangedNtfn]{},
+
 			onKXSearchCompletedNtfnType:       &handlersFor[OnKXSearchCompleted]{},
 			onInvoiceGenFailedNtfnType:        &handlersFor[OnInvoiceGenFailedNtfn]{},
 			onRemoteSubscriptionChangedType:   &handlersFor[OnRemoteSubscriptionChangedNtfn]{},
@@ -639,9 +903,21 @@ func NewNotificationManager() *NotificationManager {
 			onServerSessionChangedNtfnType:    &handlersFor[OnServerSessionChangedNtfn]{},
 			onOnboardStateChangedNtfnType:     &handlersFor[OnOnboardStateChangedNtfn]{},
 			onResourceFetchedNtfnType:         &handlersFor[OnResourceFetchedNtfn]{},
+			onResourceFetchProgressNtfnType:   &handlersFor[OnResourceFetchProgressNtfn]{},
 			onGCWithUnkxdMemberNtfnType:       &handlersFor[OnGCWithUnkxdMemberNtfn]{},
 			onMessageContentFilteredNtfType:   &handlersFor[OnMsgContentFilteredNtfn]{},
 			onUnsubscribingIdleRemoteClient:   &handlersFor[OnUnsubscribingIdleRemoteClient]{},
+			onSyncProgressNtfnType:            &handlersFor[OnSyncProgressNtfn]{},
+			onCompactionProgressNtfnType:      &handlersFor[OnCompactionProgressNtfn]{},
+			onPaymentFeeLimitExceededNtfnType: &handlersFor[OnPaymentFeeLimitExceededNtfn]{},
+			onCompatWarningNtfnType:           &handlersFor[OnCompatWarningNtfn]{},
+			onClockSkewWarningNtfnType:        &handlersFor[OnClockSkewWarningNtfn]{},
+			onRatchetHealthAlertNtfnType:      &handlersFor[OnRatchetHealthAlertNtfn]{},
+			onFileHookProgressNtfnType:        &handlersFor[OnFileHookProgressNtfn]{},
+			onContentSummarizedNtfnType:       &handlersFor[OnContentSummarizedNtfn]{},
+
+			onPaymentApprovalRequestedNtfnType: &handlersFor[OnPaymentApprovalRequestedNtfn]{},
+			onPaymentProofReceivedNtfnType:     &handlersFor[OnPaymentProofReceivedNtfn]{},
 		},
 	}
 }
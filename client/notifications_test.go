@@ -84,3 +84,67 @@ func TestNotificationManager(t *testing.T) {
 	assertUnregister(regSync, false)
 	assertUnregister(regAsync, false)
 }
+
+// TestNotificationFilters tests that filters added to the NotificationManager
+// may suppress and modify notifications before their handlers are called.
+func TestNotificationFilters(t *testing.T) {
+	nmgr := NewNotificationManager()
+
+	gotChan := make(chan string, 1)
+	nmgr.RegisterSync(OnPMNtfn(func(_ *RemoteUser, pm rpc.RMPrivateMessage, _ time.Time) {
+		gotChan <- pm.Message
+	}))
+	assertGot := func(want string) {
+		t.Helper()
+		select {
+		case got := <-gotChan:
+			if got != want {
+				t.Fatalf("unexpected msg: got %q, want %q", got, want)
+			}
+		default:
+			if want != "" {
+				t.Fatalf("handler not called")
+			}
+		}
+	}
+	notify := func(msg string) {
+		nmgr.notifyOnPM(nil, rpc.RMPrivateMessage{Message: msg}, time.Now())
+	}
+
+	// Suppress "spam" messages.
+	regSuppress := nmgr.AddFilter(NotificationFilter[OnPMNtfn](func(next OnPMNtfn) OnPMNtfn {
+		return func(ru *RemoteUser, pm rpc.RMPrivateMessage, ts time.Time) {
+			if pm.Message != "spam" {
+				next(ru, pm, ts)
+			}
+		}
+	}))
+
+	// Modify messages. This runs after the first filter.
+	regModify := nmgr.AddFilter(NotificationFilter[OnPMNtfn](func(next OnPMNtfn) OnPMNtfn {
+		return func(ru *RemoteUser, pm rpc.RMPrivateMessage, ts time.Time) {
+			pm.Message = "[filtered] " + pm.Message
+			next(ru, pm, ts)
+		}
+	}))
+
+	notify("spam")
+	assertGot("")
+	notify("hello")
+	assertGot("[filtered] hello")
+
+	// Removing the filters restores the original notifications.
+	if !regSuppress.Unregister() {
+		t.Fatal("suppress filter not unregistered")
+	}
+	notify("spam")
+	assertGot("[filtered] spam")
+	if !regModify.Unregister() {
+		t.Fatal("modify filter not unregistered")
+	}
+	if regModify.Unregister() {
+		t.Fatal("modify filter unregistered twice")
+	}
+	notify("hello")
+	assertGot("hello")
+}
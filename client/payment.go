@@ -1,3 +1,6 @@
+//go:build !brlite
+// +build !brlite
+
 package client
 
 import (
@@ -23,11 +26,13 @@ import (
 	"github.com/decred/dcrlnd/lnrpc/invoicesrpc"
 	"github.com/decred/dcrlnd/lnrpc/routerrpc"
 	"github.com/decred/dcrlnd/lnrpc/walletrpc"
+	"github.com/decred/dcrlnd/lnrpc/wtclientrpc"
 	"github.com/decred/dcrlnd/macaroons"
 	"github.com/decred/slog"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"gopkg.in/macaroon.v2"
 )
 
@@ -36,6 +41,11 @@ type DcrlnPaymentClientCfg struct {
 	MacaroonPath string
 	Address      string
 	Log          slog.Logger
+
+	// WarnMacaroonScope enables the warnings about macaroons that grant
+	// more permissions than needed. This should only be set for external
+	// dcrlnd instances, given the embedded wallet uses the admin macaroon.
+	WarnMacaroonScope bool
 }
 
 // DcrlnPaymentClient implements the PaymentClient interface for servers that
@@ -47,9 +57,22 @@ type DcrlnPaymentClient struct {
 	lnRouter    routerrpc.RouterClient
 	lnWallet    walletrpc.WalletKitClient
 	lnChain     chainrpc.ChainNotifierClient
+	lnWtClient  wtclientrpc.WatchtowerClientClient
+	conn        *grpc.ClientConn
 	log         slog.Logger
 	payTiming   *timestats.Tracker
 	chainParams *chaincfg.Params
+	macWarnings []string
+}
+
+// lnPaymentError returns the error for a payment that failed with the given
+// payment error reported by dcrlnd.
+func lnPaymentError(payErr string) error {
+	if strings.Contains(payErr, "insufficient_balance") {
+		return clientintf.KindErrorf(clientintf.ErrInsufficientFunds,
+			"LN payment error: %s", payErr)
+	}
+	return fmt.Errorf("LN payment error: %s", payErr)
 }
 
 // NewDcrlndPaymentClient creates a new payment client that can send payments
@@ -72,10 +95,34 @@ func NewDcrlndPaymentClient(ctx context.Context, cfg DcrlnPaymentClientCfg) (*Dc
 		return nil, err
 	}
 
-	// Now we append the macaroon credentials to the dial options.
+	log := slog.Disabled
+	if cfg.Log != nil {
+		log = cfg.Log
+	}
+
+	// Warn about macaroons that grant more permissions than needed.
+	var macWarnings []string
+	if cfg.WarnMacaroonScope {
+		macWarnings, err = MacaroonScopeWarnings(mac)
+		if err != nil {
+			log.Warnf("Unable to check macaroon scope: %v", err)
+		}
+		for _, w := range macWarnings {
+			log.Warnf("Macaroon %s: %s", cfg.MacaroonPath, w)
+		}
+	}
+
+	// Now we append the macaroon credentials to the dial options. The
+	// keepalive params help detect broken connections to remote nodes, so
+	// that they are automatically re-established.
 	opts = append(
 		opts,
 		grpc.WithPerRPCCredentials(macaroons.NewMacaroonCredential(mac)),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                dcrlnKeepaliveTime,
+			Timeout:             dcrlnKeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
 	)
 
 	conn, err := grpc.Dial(cfg.Address, opts...)
@@ -90,11 +137,7 @@ func NewDcrlndPaymentClient(ctx context.Context, cfg DcrlnPaymentClientCfg) (*Dc
 	lnRouter := routerrpc.NewRouterClient(conn)
 	lnWallet := walletrpc.NewWalletKitClient(conn)
 	lnChain := chainrpc.NewChainNotifierClient(conn)
-
-	log := slog.Disabled
-	if cfg.Log != nil {
-		log = cfg.Log
-	}
+	lnWtClient := wtclientrpc.NewWatchtowerClientClient(conn)
 
 	return &DcrlnPaymentClient{
 		lnRpc:      lnRpc,
@@ -103,8 +146,12 @@ func NewDcrlndPaymentClient(ctx context.Context, cfg DcrlnPaymentClientCfg) (*Dc
 		lnRouter:   lnRouter,
 		lnWallet:   lnWallet,
 		lnChain:    lnChain,
+		lnWtClient: lnWtClient,
+		conn:       conn,
 		log:        log,
 		payTiming:  timestats.NewTracker(250),
+
+		macWarnings: macWarnings,
 	}, nil
 }
 
@@ -173,7 +220,7 @@ func (pc *DcrlnPaymentClient) PayInvoice(ctx context.Context, invoice string) (i
 			return 0, fmt.Errorf("LN %w: %s", clientintf.ErrRetriablePayment,
 				sendPayRes.PaymentError)
 		}
-		return 0, fmt.Errorf("LN payment error: %s", sendPayRes.PaymentError)
+		return 0, lnPaymentError(sendPayRes.PaymentError)
 	}
 	pc.payTiming.Add(time.Since(start))
 
@@ -186,6 +233,37 @@ func (pc *DcrlnPaymentClient) PayInvoice(ctx context.Context, invoice string) (i
 	return fees, nil
 }
 
+// EstimatePaymentFee returns the fees (in milliatoms) of the best route found
+// to pay the invoice. If the invoice does not specify an amount, then amount
+// is used.
+func (pc *DcrlnPaymentClient) EstimatePaymentFee(ctx context.Context, invoice string, amount int64) (int64, error) {
+	payReq, err := pc.lnRpc.DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: invoice})
+	if err != nil {
+		return 0, fmt.Errorf("unable to decode pay req")
+	}
+	if payReq.NumMAtoms > 0 {
+		amount = payReq.NumMAtoms
+	}
+
+	req := &lnrpc.QueryRoutesRequest{
+		PubKey:            payReq.Destination,
+		AmtMAtoms:         amount,
+		FinalCltvDelta:    int32(payReq.CltvExpiry),
+		FeeLimit:          PaymentFeeLimit(uint64(amount)),
+		RouteHints:        payReq.RouteHints,
+		UseMissionControl: true,
+	}
+	res, err := pc.lnRpc.QueryRoutes(ctx, req)
+	if err != nil {
+		return 0, fmt.Errorf("unable to query payment route: %v", err)
+	}
+	if len(res.Routes) == 0 {
+		return 0, fmt.Errorf("LN %w: no route found to %s",
+			clientintf.ErrRetriablePayment, payReq.Destination)
+	}
+	return res.Routes[0].TotalFeesMAtoms, nil
+}
+
 func (pc *DcrlnPaymentClient) PayInvoiceAmount(ctx context.Context, invoice string, amount int64) (int64, error) {
 	payReq, err := pc.lnRpc.DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: invoice})
 	if err != nil {
@@ -211,7 +289,7 @@ func (pc *DcrlnPaymentClient) PayInvoiceAmount(ctx context.Context, invoice stri
 	}
 
 	if sendPayRes.PaymentError != "" {
-		return 0, fmt.Errorf("LN payment error: %s", sendPayRes.PaymentError)
+		return 0, lnPaymentError(sendPayRes.PaymentError)
 	}
 
 	pc.payTiming.Add(time.Since(start))
@@ -279,6 +357,22 @@ func (pc *DcrlnPaymentClient) GetInvoice(ctx context.Context, mat int64, cb func
 	return addInvoiceRes.PaymentRequest, nil
 }
 
+// CancelInvoice cancels an open invoice generated by the wallet, so that it
+// can no longer be paid.
+func (pc *DcrlnPaymentClient) CancelInvoice(ctx context.Context, invoice string) error {
+	payReq, err := pc.lnRpc.DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: invoice})
+	if err != nil {
+		return fmt.Errorf("unable to decode pay req: %v", err)
+	}
+	hash, err := hex.DecodeString(payReq.PaymentHash)
+	if err != nil {
+		return fmt.Errorf("unable to decode payment hash: %v", err)
+	}
+	req := &invoicesrpc.CancelInvoiceMsg{PaymentHash: hash}
+	_, err = pc.lnInvoices.CancelInvoice(ctx, req)
+	return err
+}
+
 func (pc *DcrlnPaymentClient) IsInvoicePaid(ctx context.Context, minMatAmt int64, invoice string) error {
 
 	payReq, err := pc.lnRpc.DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: invoice})
@@ -333,6 +427,7 @@ func (pc *DcrlnPaymentClient) DecodeInvoice(ctx context.Context, invoice string)
 		ID:         id,
 		MAtoms:     payReq.NumMAtoms,
 		ExpiryTime: time.Unix(expiryTS, 0),
+		DestNode:   payReq.Destination,
 	}, nil
 }
 
@@ -364,6 +459,10 @@ func (pc *DcrlnPaymentClient) IsPaymentCompleted(ctx context.Context, invoice st
 		case lnrpc.Payment_SUCCEEDED:
 			return event.FeeMAtoms, nil
 		case lnrpc.Payment_FAILED:
+			if event.FailureReason == lnrpc.PaymentFailureReason_FAILURE_REASON_INSUFFICIENT_BALANCE {
+				return 0, clientintf.KindErrorf(clientintf.ErrInsufficientFunds,
+					"payment failed due to %s", event.FailureReason.String())
+			}
 			return 0, fmt.Errorf("payment failed due to %s", event.FailureReason.String())
 		case lnrpc.Payment_UNKNOWN:
 			return 0, fmt.Errorf("payment status is unknown")
@@ -375,6 +474,62 @@ func (pc *DcrlnPaymentClient) IsPaymentCompleted(ctx context.Context, invoice st
 	}
 }
 
+// PaymentPreimage returns the preimage of the invoice, which must have been
+// successfully paid by the local node.
+func (pc *DcrlnPaymentClient) PaymentPreimage(ctx context.Context, invoice string) ([]byte, error) {
+	payReq, err := pc.lnRpc.DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: invoice})
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode pay req")
+	}
+
+	payHash, err := hex.DecodeString(payReq.PaymentHash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode payment hash: %v", err)
+	}
+
+	rctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req := &routerrpc.TrackPaymentRequest{
+		PaymentHash:       payHash,
+		NoInflightUpdates: true,
+	}
+	stream, err := pc.lnRouter.TrackPaymentV2(rctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create payment tracking stream: %v", err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("error reading from payment tracking stream: %v", err)
+		}
+
+		switch event.Status {
+		case lnrpc.Payment_SUCCEEDED:
+			return hex.DecodeString(event.PaymentPreimage)
+		case lnrpc.Payment_IN_FLIGHT:
+			continue
+		default:
+			return nil, fmt.Errorf("payment was not completed (status %s)",
+				event.Status)
+		}
+	}
+}
+
+// SettledInvoiceMAtoms returns the amount paid to the settled invoice with the
+// given payment hash, which must have been generated by the local node.
+func (pc *DcrlnPaymentClient) SettledInvoiceMAtoms(ctx context.Context, paymentHash []byte) (int64, error) {
+	req := &lnrpc.PaymentHash{RHash: paymentHash}
+	inv, err := pc.lnRpc.LookupInvoice(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if inv.State != lnrpc.Invoice_SETTLED {
+		return 0, fmt.Errorf("invoice not settled (state %s)", inv.State)
+	}
+	return inv.AmtPaidMAtoms, nil
+}
+
 func (pc *DcrlnPaymentClient) TrackInvoice(ctx context.Context, invoice string, minMAtoms int64) (int64, error) {
 	payReq, err := pc.lnRpc.DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: invoice})
 	if err != nil {
@@ -522,6 +677,7 @@ func (pc *DcrlnPaymentClient) CreateInviteFunds(ctx context.Context, amount dcru
 		PrivateKey: pk.Wif,
 		HeightHint: info.BlockHeight - 6,
 		Address:    addr.Addr,
+		Amount:     int64(amount),
 	}
 
 	pc.log.Infof("Stored %s as invite funds from account %q on tx %s",
@@ -678,6 +834,41 @@ func (pc *DcrlnPaymentClient) NewReceiveAddress(ctx context.Context, acct string
 	return stdaddr.DecodeAddress(addrRes.Address, pc.chainParams)
 }
 
+// ImportXPubAccount imports the extended public key of an account of an
+// external wallet as a watch-only account of the underlying wallet with the
+// given name. Addresses of the account may then be generated with
+// NewReceiveAddress and the payments to them are detected by the wallet, while
+// the received funds may only be spent by the external wallet. This is a no-op
+// if the account was already imported with the same key.
+func (pc *DcrlnPaymentClient) ImportXPubAccount(ctx context.Context, name, xpub string) error {
+	if name == "" {
+		return fmt.Errorf("name of the imported account is empty")
+	}
+	res, err := pc.lnWallet.ListAccounts(ctx, &walletrpc.ListAccountsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, acct := range res.Accounts {
+		if acct.Name != name {
+			continue
+		}
+		if acct.ExtendedPublicKey != xpub {
+			return fmt.Errorf("account %q already exists with a "+
+				"different extended public key", name)
+		}
+		return nil
+	}
+
+	req := &walletrpc.ImportAccountRequest{
+		Name:              name,
+		ExtendedPublicKey: xpub,
+	}
+	if _, err := pc.lnWallet.ImportAccount(ctx, req); err != nil {
+		return fmt.Errorf("unable to import account %q: %v", name, err)
+	}
+	return nil
+}
+
 // WatchTransactions watches transactions until the given context is closed.
 func (pc *DcrlnPaymentClient) WatchTransactions(ctx context.Context, handler func(tx *lnrpc.Transaction)) {
 	ctxCanceled := func() bool {
@@ -933,3 +1124,100 @@ func chanPointToStr(cp *lnrpc.ChannelPoint) string {
 	}
 	return fmt.Sprintf("%s:%d", tx, cp.OutputIndex)
 }
+
+// WatchtowerStatus is the status of a watchtower registered with the LN
+// wallet.
+type WatchtowerStatus struct {
+	Pubkey      string
+	Addresses   []string
+	Active      bool
+	NumSessions uint32
+}
+
+// parseWatchtowerURI parses a watchtower URI in the form <pubkey>@<address>.
+func parseWatchtowerURI(uri string) ([]byte, string, error) {
+	pubHex, addr, ok := strings.Cut(uri, "@")
+	if !ok || addr == "" {
+		return nil, "", fmt.Errorf("watchtower URI must be in the " +
+			"format <pubkey>@<address>")
+	}
+	pubkey, err := hex.DecodeString(pubHex)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid watchtower pubkey: %v", err)
+	}
+	if len(pubkey) != 33 {
+		return nil, "", fmt.Errorf("invalid watchtower pubkey length %d",
+			len(pubkey))
+	}
+	return pubkey, addr, nil
+}
+
+// AddWatchtower registers the watchtower at the given URI (in the format
+// <pubkey>@<address>) to back up the channel states of the LN wallet. This
+// requires the watchtower client to be active in the wallet.
+func (pc *DcrlnPaymentClient) AddWatchtower(ctx context.Context, uri string) error {
+	pubkey, addr, err := parseWatchtowerURI(uri)
+	if err != nil {
+		return err
+	}
+	req := &wtclientrpc.AddTowerRequest{Pubkey: pubkey, Address: addr}
+	if _, err := pc.lnWtClient.AddTower(ctx, req); err != nil {
+		return err
+	}
+	pc.log.Infof("Added watchtower %x@%s", pubkey, addr)
+	return nil
+}
+
+// RemoveWatchtower removes the watchtower with the given (hex-encoded) pubkey
+// from the list of watchtowers used by the LN wallet.
+func (pc *DcrlnPaymentClient) RemoveWatchtower(ctx context.Context, pubkeyHex string) error {
+	pubkey, err := hex.DecodeString(pubkeyHex)
+	if err != nil {
+		return fmt.Errorf("invalid watchtower pubkey: %v", err)
+	}
+	req := &wtclientrpc.RemoveTowerRequest{Pubkey: pubkey}
+	if _, err := pc.lnWtClient.RemoveTower(ctx, req); err != nil {
+		return err
+	}
+	pc.log.Infof("Removed watchtower %x", pubkey)
+	return nil
+}
+
+// ListWatchtowers lists the watchtowers registered in the LN wallet.
+func (pc *DcrlnPaymentClient) ListWatchtowers(ctx context.Context) ([]WatchtowerStatus, error) {
+	res, err := pc.lnWtClient.ListTowers(ctx, &wtclientrpc.ListTowersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	towers := make([]WatchtowerStatus, len(res.Towers))
+	for i, t := range res.Towers {
+		towers[i] = WatchtowerStatus{
+			Pubkey:      hex.EncodeToString(t.Pubkey),
+			Addresses:   t.Addresses,
+			Active:      t.ActiveSessionCandidate,
+			NumSessions: t.NumSessions,
+		}
+	}
+	return towers, nil
+}
+
+// MonitorWatchtowers periodically checks the status of the watchtowers
+// registered in the LN wallet and calls f with the result. It returns when the
+// context is canceled.
+func (pc *DcrlnPaymentClient) MonitorWatchtowers(ctx context.Context,
+	interval time.Duration, f func([]WatchtowerStatus, error)) error {
+
+	for {
+		towers, err := pc.ListWatchtowers(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		f(towers, err)
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
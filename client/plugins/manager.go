@@ -0,0 +1,339 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/slog"
+	"golang.org/x/sync/errgroup"
+)
+
+// ManagerConfig is the configuration of a Manager.
+type ManagerConfig struct {
+	// Host fulfills the requests of plugins.
+	Host Host
+
+	// Plugins are the configs of the managed plugins.
+	Plugins []Config
+
+	// Logger returns the logger for the given subsystem. The logs of each
+	// plugin (including what it writes to stderr) use a subsystem named
+	// after the plugin.
+	Logger func(subsys string) slog.Logger
+
+	// CallTimeout is the max time the client waits for replies of calls
+	// made to plugins. Defaults to 30 seconds.
+	CallTimeout time.Duration
+
+	// StartTimeout is the max time a plugin takes to reply to the init
+	// call after being started. Defaults to 10 seconds.
+	StartTimeout time.Duration
+
+	// MaxBackoff is the max time to wait before restarting a plugin that
+	// exited unexpectedly. Defaults to 1 minute.
+	MaxBackoff time.Duration
+}
+
+func (cfg *ManagerConfig) logger(subsys string) slog.Logger {
+	if cfg.Logger == nil {
+		return slog.Disabled
+	}
+	return cfg.Logger(subsys)
+}
+
+// Manager manages the lifecycle of plugins and routes the events of the client
+// to them.
+type Manager struct {
+	cfg     ManagerConfig
+	plugins map[string]*plugin
+	order   []string
+}
+
+// NewManager creates a new plugin manager. Run must be called for the plugins
+// to be started.
+func NewManager(cfg ManagerConfig) (*Manager, error) {
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = 30 * time.Second
+	}
+	if cfg.StartTimeout <= 0 {
+		cfg.StartTimeout = 10 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = time.Minute
+	}
+
+	m := &Manager{
+		cfg:     cfg,
+		plugins: make(map[string]*plugin, len(cfg.Plugins)),
+	}
+	for i := range cfg.Plugins {
+		pcfg := cfg.Plugins[i]
+		if err := pcfg.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := m.plugins[pcfg.Name]; ok {
+			return nil, fmt.Errorf("duplicated plugin %s", pcfg.Name)
+		}
+		m.plugins[pcfg.Name] = newPlugin(m, pcfg)
+		m.order = append(m.order, pcfg.Name)
+	}
+	return m, nil
+}
+
+func (m *Manager) plugin(name string) (*plugin, error) {
+	p, ok := m.plugins[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, name)
+	}
+	return p, nil
+}
+
+// Plugins returns the status of the plugins.
+func (m *Manager) Plugins() []Status {
+	res := make([]Status, 0, len(m.order))
+	for _, name := range m.order {
+		res = append(res, m.plugins[name].status())
+	}
+	return res
+}
+
+// Start starts a stopped plugin.
+func (m *Manager) Start(name string) error {
+	p, err := m.plugin(name)
+	if err != nil {
+		return err
+	}
+	p.control(ctrlStart)
+	return nil
+}
+
+// Stop stops a plugin. It is not restarted until Start is called.
+func (m *Manager) Stop(name string) error {
+	p, err := m.plugin(name)
+	if err != nil {
+		return err
+	}
+	p.control(ctrlStop)
+	return nil
+}
+
+// Restart restarts a plugin, starting it if it was stopped.
+func (m *Manager) Restart(name string) error {
+	p, err := m.plugin(name)
+	if err != nil {
+		return err
+	}
+	p.control(ctrlRestart)
+	return nil
+}
+
+// PluginCommand is a command registered by a plugin.
+type PluginCommand struct {
+	Plugin string
+	CommandInfo
+}
+
+// Commands returns the commands registered by the running plugins.
+func (m *Manager) Commands() []PluginCommand {
+	var res []PluginCommand
+	for _, name := range m.order {
+		for _, cmd := range m.plugins[name].status().Commands {
+			res = append(res, PluginCommand{Plugin: name, CommandInfo: cmd})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Plugin != res[j].Plugin {
+			return res[i].Plugin < res[j].Plugin
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// RunCommand runs a command registered by the plugin and returns its output.
+func (m *Manager) RunCommand(ctx context.Context, name, cmd string, args []string) (string, error) {
+	p, err := m.plugin(name)
+	if err != nil {
+		return "", err
+	}
+	if !p.cfg.hasPermission(PermCommands) {
+		return "", fmt.Errorf("plugin %s: %w to run commands", name,
+			ErrPermissionDenied)
+	}
+	var found bool
+	for _, c := range p.status().Commands {
+		found = found || c.Name == cmd
+	}
+	if !found {
+		return "", fmt.Errorf("plugin %s does not have command %q", name, cmd)
+	}
+
+	var res CommandResult
+	err = p.call(ctx, MethodCommand, CommandParams{Name: cmd, Args: args}, &res)
+	return res.Output, err
+}
+
+// ResourcesProvider returns a provider that fulfills resource requests with
+// the plugin. The provider should be bound to the ResourcesPrefix of the
+// plugin.
+func (m *Manager) ResourcesProvider(name string) (*ResourcesProvider, error) {
+	p, err := m.plugin(name)
+	if err != nil {
+		return nil, err
+	}
+	if !p.cfg.hasPermission(PermResources) {
+		return nil, fmt.Errorf("plugin %s: %w to serve resources", name,
+			ErrPermissionDenied)
+	}
+	return &ResourcesProvider{p: p}, nil
+}
+
+// ResourcesProviders returns the providers of the plugins granted permission
+// to serve resources, keyed by their resources prefix.
+func (m *Manager) ResourcesProviders() map[string]*ResourcesProvider {
+	res := make(map[string]*ResourcesProvider)
+	for _, name := range m.order {
+		p := m.plugins[name]
+		if p.cfg.hasPermission(PermResources) {
+			res[p.cfg.ResourcesPrefix] = &ResourcesProvider{p: p}
+		}
+	}
+	return res
+}
+
+// notifyAll sends a notification to the running plugins with the permission.
+func (m *Manager) notifyAll(perm Permission, method string, params interface{}) {
+	for _, name := range m.order {
+		p := m.plugins[name]
+		if !p.cfg.hasPermission(perm) {
+			continue
+		}
+		if err := p.notify(method, params); err != nil && err != ErrPluginNotRunning {
+			p.log.Debugf("Unable to notify %s: %v", method, err)
+		}
+	}
+}
+
+// PMReceived notifies the plugins that a PM was received.
+func (m *Manager) PMReceived(uid clientintf.UserID, nick, msg string, ts time.Time) {
+	m.notifyAll(PermReadMessages, MethodPMReceived, PMReceivedParams{
+		UID:       uid,
+		Nick:      nick,
+		Message:   msg,
+		Timestamp: ts.Unix(),
+	})
+}
+
+// TipReceived notifies the plugins that a tip was received.
+func (m *Manager) TipReceived(uid clientintf.UserID, amountMAtoms int64) {
+	m.notifyAll(PermReadPayments, MethodTipReceived, TipReceivedParams{
+		UID:          uid,
+		AmountMAtoms: amountMAtoms,
+	})
+}
+
+// RegisterNotifications registers handlers in the notification manager of a
+// client that forward its events to the plugins.
+func (m *Manager) RegisterNotifications(ntfns *client.NotificationManager) {
+	ntfns.Register(client.OnPMNtfn(func(ru *client.RemoteUser,
+		pm rpc.RMPrivateMessage, ts time.Time) {
+		m.PMReceived(ru.ID(), ru.Nick(), pm.Message, ts)
+	}))
+	ntfns.Register(client.OnTipReceivedNtfn(func(ru *client.RemoteUser,
+		amountMAtoms int64) {
+		m.TipReceived(ru.ID(), amountMAtoms)
+	}))
+}
+
+// handleRequest handles a request made by a plugin.
+func (m *Manager) handleRequest(ctx context.Context, p *plugin, method string,
+	params json.RawMessage) (interface{}, error) {
+
+	switch method {
+	case MethodLog:
+		var lp LogParams
+		if err := json.Unmarshal(params, &lp); err != nil {
+			return nil, err
+		}
+		switch lp.Level {
+		case "error":
+			p.log.Error(lp.Message)
+		case "warn":
+			p.log.Warn(lp.Message)
+		case "debug":
+			p.log.Debug(lp.Message)
+		default:
+			p.log.Info(lp.Message)
+		}
+		return nil, nil
+
+	case MethodSendPM:
+		if !p.cfg.hasPermission(PermSendMessages) {
+			return nil, fmt.Errorf("%w to send messages", ErrPermissionDenied)
+		}
+		var sp SendPMParams
+		if err := json.Unmarshal(params, &sp); err != nil {
+			return nil, err
+		}
+		if m.cfg.Host == nil {
+			return nil, fmt.Errorf("client not available")
+		}
+		return nil, m.cfg.Host.PM(sp.UID, sp.Message)
+
+	case MethodTipUser:
+		if !p.cfg.hasPermission(PermSendPayments) || p.cfg.MaxTipDCR <= 0 {
+			return nil, fmt.Errorf("%w to send tips", ErrPermissionDenied)
+		}
+		var tp TipUserParams
+		if err := json.Unmarshal(params, &tp); err != nil {
+			return nil, err
+		}
+		if tp.DCRAmount <= 0 || tp.DCRAmount > p.cfg.MaxTipDCR {
+			return nil, fmt.Errorf("%w to tip %.8f DCR (max %.8f DCR)",
+				ErrPermissionDenied, tp.DCRAmount, p.cfg.MaxTipDCR)
+		}
+		if m.cfg.Host == nil {
+			return nil, fmt.Errorf("client not available")
+		}
+		release, err := p.reserveTip(tp.DCRAmount, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		p.log.Infof("Tipping user %s %.8f DCR", tp.UID, tp.DCRAmount)
+		if err := m.cfg.Host.TipUser(tp.UID, tp.DCRAmount, 1); err != nil {
+			release()
+			return nil, err
+		}
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unknown method %q", method)
+	}
+}
+
+// Run starts the plugins and manages them until the context is done, at
+// which point they are stopped.
+func (m *Manager) Run(ctx context.Context) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for _, name := range m.order {
+		p := m.plugins[name]
+		g.Go(func() error { return p.run(gctx) })
+	}
+	return g.Wait()
+}
+
+// minimalEnv returns the environment passed to plugins.
+func minimalEnv(cfg *Config) []string {
+	env := []string{"BR_PLUGIN_NAME=" + cfg.Name}
+	if path, ok := os.LookupEnv("PATH"); ok {
+		env = append(env, "PATH="+path)
+	}
+	return append(env, cfg.Env...)
+}
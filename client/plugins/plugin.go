@@ -0,0 +1,375 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/slog"
+)
+
+// ctrlCmd is a command to change the state of a plugin.
+type ctrlCmd int
+
+const (
+	ctrlStart ctrlCmd = iota
+	ctrlStop
+	ctrlRestart
+)
+
+// errStopped is the error of runs of plugins that were stopped on request.
+var errStopped = errors.New("plugin stopped")
+
+// tipsWindow is the window of time in which the tips sent by a plugin are
+// limited by its MaxDailyTipsDCR.
+const tipsWindow = 24 * time.Hour
+
+// sentTip is a tip sent by a plugin.
+type sentTip struct {
+	ts     time.Time
+	amount dcrutil.Amount
+}
+
+// plugin supervises the process of a single plugin.
+type plugin struct {
+	m    *Manager
+	cfg  Config
+	log  slog.Logger
+	ctrl chan ctrlCmd
+
+	mtx       sync.Mutex
+	state     State
+	conn      *conn
+	version   string
+	commands  []CommandInfo
+	restarts  int
+	lastErr   error
+	startedAt time.Time
+
+	// tips are the tips sent by the plugin in the last tipsWindow. They
+	// are kept across restarts of the plugin.
+	tips []sentTip
+}
+
+func newPlugin(m *Manager, cfg Config) *plugin {
+	return &plugin{
+		m:     m,
+		cfg:   cfg,
+		log:   m.cfg.logger(cfg.Name),
+		ctrl:  make(chan ctrlCmd, 1),
+		state: StateStopped,
+	}
+}
+
+func (p *plugin) status() Status {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return Status{
+		Name:        p.cfg.Name,
+		State:       p.state,
+		Version:     p.version,
+		Permissions: p.cfg.Permissions,
+		Commands:    p.commands,
+		Restarts:    p.restarts,
+		LastErr:     p.lastErr,
+		StartedAt:   p.startedAt,
+	}
+}
+
+// reserveTip records a tip about to be sent by the plugin, if it does not
+// make the total amount of its tips in the last tipsWindow exceed the max.
+// The returned func removes the tip, to be called if it is not sent.
+func (p *plugin) reserveTip(dcrAmount float64, now time.Time) (func(), error) {
+	amount, err := dcrutil.NewAmount(dcrAmount)
+	if err != nil {
+		return nil, err
+	}
+	maxTotal, err := dcrutil.NewAmount(p.cfg.maxDailyTipsDCR())
+	if err != nil {
+		return nil, err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	var total dcrutil.Amount
+	var i int
+	for _, tip := range p.tips {
+		if now.Sub(tip.ts) >= tipsWindow {
+			continue
+		}
+		total += tip.amount
+		p.tips[i] = tip
+		i++
+	}
+	p.tips = p.tips[:i]
+	if total+amount > maxTotal {
+		return nil, fmt.Errorf("%w to tip %s (already sent %s of max %s "+
+			"in the last %s)", ErrPermissionDenied, amount, total,
+			maxTotal, tipsWindow)
+	}
+
+	tip := sentTip{ts: now, amount: amount}
+	p.tips = append(p.tips, tip)
+	release := func() {
+		p.mtx.Lock()
+		for i := range p.tips {
+			if p.tips[i] == tip {
+				p.tips = append(p.tips[:i], p.tips[i+1:]...)
+				break
+			}
+		}
+		p.mtx.Unlock()
+	}
+	return release, nil
+}
+
+// control sends a command to the supervisor of the plugin. Only the latest
+// pending command is kept.
+func (p *plugin) control(cmd ctrlCmd) {
+	for {
+		select {
+		case p.ctrl <- cmd:
+			return
+		default:
+		}
+		select {
+		case <-p.ctrl:
+		default:
+		}
+	}
+}
+
+func (p *plugin) setState(state State) {
+	p.mtx.Lock()
+	p.state = state
+	p.mtx.Unlock()
+}
+
+// runningConn returns the connection to the plugin, if it is running.
+func (p *plugin) runningConn() (*conn, error) {
+	p.mtx.Lock()
+	c := p.conn
+	p.mtx.Unlock()
+	if c == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPluginNotRunning, p.cfg.Name)
+	}
+	return c, nil
+}
+
+// call makes a call to the plugin, if it is running.
+func (p *plugin) call(ctx context.Context, method string, params, result interface{}) error {
+	c, err := p.runningConn()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.m.cfg.CallTimeout)
+	defer cancel()
+	return c.call(ctx, method, params, result)
+}
+
+// notify sends a notification to the plugin, if it is running.
+func (p *plugin) notify(method string, params interface{}) error {
+	c, err := p.runningConn()
+	if err != nil {
+		return ErrPluginNotRunning
+	}
+	return c.notify(method, params)
+}
+
+// logStderr logs the lines written by the plugin to stderr.
+func (p *plugin) logStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		p.log.Infof("stderr: %s", scanner.Text())
+	}
+}
+
+// runOnce starts the plugin process and returns once it exits.
+func (p *plugin) runOnce(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.cfg.Command, p.cfg.Args...)
+	cmd.Dir = p.cfg.Dir
+	cmd.Env = minimalEnv(&p.cfg)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go p.logStderr(stderr)
+
+	c := newConn(stdin, func(ctx context.Context, method string,
+		params json.RawMessage) (interface{}, error) {
+		return p.m.handleRequest(ctx, p, method, params)
+	})
+	connErr := make(chan error, 1)
+	go func() { connErr <- c.run(ctx, stdout) }()
+
+	// Initialize the plugin.
+	initCtx, initCancel := context.WithTimeout(ctx, p.m.cfg.StartTimeout)
+	var initRes InitResult
+	err = c.call(initCtx, MethodInit, InitParams{
+		Name:        p.cfg.Name,
+		Permissions: p.cfg.Permissions,
+	}, &initRes)
+	initCancel()
+	if err != nil {
+		cancel()
+		cmd.Wait()
+		return fmt.Errorf("unable to initialize plugin: %v", err)
+	}
+	if !p.cfg.hasPermission(PermCommands) {
+		initRes.Commands = nil
+	}
+
+	p.mtx.Lock()
+	p.conn = c
+	p.state = StateRunning
+	p.version = initRes.Version
+	p.commands = initRes.Commands
+	p.startedAt = time.Now()
+	p.mtx.Unlock()
+	p.log.Infof("Started plugin %s (version %q, %d commands)", p.cfg.Name,
+		initRes.Version, len(initRes.Commands))
+
+	// Wait until the plugin exits or closes its stdout.
+	select {
+	case err = <-connErr:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	cancel()
+	waitErr := cmd.Wait()
+
+	p.mtx.Lock()
+	p.conn = nil
+	p.commands = nil
+	p.mtx.Unlock()
+
+	if waitErr != nil && ctx.Err() == nil {
+		return waitErr
+	}
+	if errors.Is(err, io.EOF) {
+		return errors.New("plugin exited")
+	}
+	return err
+}
+
+// run supervises the plugin until the context is done, restarting it with an
+// exponential backoff when it exits unexpectedly.
+func (p *plugin) run(ctx context.Context) error {
+	stopped := p.cfg.Disabled
+	backoff := time.Second
+	for {
+		// Wait until the plugin is started.
+		for stopped {
+			p.setState(StateStopped)
+			select {
+			case cmd := <-p.ctrl:
+				stopped = cmd == ctrlStop
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		p.setState(StateStarting)
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() { done <- p.runOnce(runCtx) }()
+
+		var err error
+		select {
+		case err = <-done:
+		case cmd := <-p.ctrl:
+			cancel()
+			<-done
+			err = errStopped
+			stopped = cmd == ctrlStop
+			backoff = time.Second
+		case <-ctx.Done():
+			cancel()
+			<-done
+			p.setState(StateStopped)
+			return nil
+		}
+		cancel()
+
+		if err == errStopped {
+			p.log.Infof("Stopped plugin %s", p.cfg.Name)
+			continue
+		}
+
+		// The plugin exited unexpectedly. Reset the backoff if it ran
+		// for a while.
+		p.mtx.Lock()
+		if !p.startedAt.IsZero() && time.Since(p.startedAt) > p.m.cfg.MaxBackoff {
+			backoff = time.Second
+		}
+		p.restarts += 1
+		p.lastErr = err
+		p.state = StateBackoff
+		p.mtx.Unlock()
+		p.log.Errorf("Plugin %s exited: %v. Restarting in %s", p.cfg.Name,
+			err, backoff)
+
+		select {
+		case <-time.After(backoff):
+		case cmd := <-p.ctrl:
+			stopped = cmd == ctrlStop
+		case <-ctx.Done():
+			p.setState(StateStopped)
+			return nil
+		}
+		backoff *= 2
+		if backoff > p.m.cfg.MaxBackoff {
+			backoff = p.m.cfg.MaxBackoff
+		}
+	}
+}
+
+// ResourcesProvider fulfills resource requests with a plugin.
+type ResourcesProvider struct {
+	p *plugin
+}
+
+// Fulfill is part of the resources.Provider interface.
+func (rp *ResourcesProvider) Fulfill(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var res FetchResourceResult
+	err := rp.p.call(ctx, MethodFetchResource, FetchResourceParams{
+		UID:  uid,
+		Path: request.Path,
+		Meta: request.Meta,
+		Data: request.Data,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.RMFetchResourceReply{
+		Tag:    request.Tag,
+		Status: rpc.ResourceStatus(res.Status),
+		Meta:   res.Meta,
+		Data:   res.Data,
+	}, nil
+}
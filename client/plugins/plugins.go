@@ -0,0 +1,201 @@
+// Package plugins runs client plugins: external programs that extend the
+// client with new features without requiring it to be forked.
+//
+// Each plugin runs as a separate process, with a minimal environment and in
+// its own working dir, and communicates with the client by exchanging JSON
+// messages (one per line) through its stdin and stdout. Plugins may register
+// commands, serve resources, receive messages and tips and send messages and
+// tips, but only when granted the corresponding permission in their config.
+//
+// The Manager starts the plugins, restarts the ones that exit unexpectedly and
+// allows them to be stopped and started on demand.
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// Permission is a capability granted to a plugin.
+type Permission string
+
+const (
+	// PermReadMessages allows the plugin to receive the PMs received by
+	// the client.
+	PermReadMessages Permission = "messages.read"
+
+	// PermSendMessages allows the plugin to send PMs.
+	PermSendMessages Permission = "messages.send"
+
+	// PermCommands allows the plugin to register commands.
+	PermCommands Permission = "commands"
+
+	// PermResources allows the plugin to serve resources to remote users.
+	PermResources Permission = "resources"
+
+	// PermReadPayments allows the plugin to receive the tips received by
+	// the client.
+	PermReadPayments Permission = "payments.read"
+
+	// PermSendPayments allows the plugin to send tips (of up to the
+	// MaxTipDCR and MaxDailyTipsDCR amounts in its config).
+	PermSendPayments Permission = "payments.send"
+)
+
+var validPermissions = map[Permission]struct{}{
+	PermReadMessages: {}, PermSendMessages: {}, PermCommands: {},
+	PermResources: {}, PermReadPayments: {}, PermSendPayments: {},
+}
+
+var validPluginName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Config is the configuration of a plugin.
+type Config struct {
+	// Name identifies the plugin. It may only contain letters, numbers,
+	// dashes and underscores.
+	Name string `json:"name"`
+
+	// Command is the path to the program of the plugin.
+	Command string `json:"command"`
+
+	// Args are the arguments passed to the program.
+	Args []string `json:"args,omitempty"`
+
+	// Dir is the working dir of the plugin.
+	Dir string `json:"dir,omitempty"`
+
+	// Env are extra environment variables (in the "key=value" format)
+	// set for the plugin. Plugins do not inherit the environment of the
+	// client, other than the PATH.
+	Env []string `json:"env,omitempty"`
+
+	// Permissions are the capabilities granted to the plugin.
+	Permissions []Permission `json:"permissions,omitempty"`
+
+	// ResourcesPrefix is the (slash separated) path prefix of resource
+	// requests served by the plugin, when it is granted PermResources.
+	ResourcesPrefix string `json:"resources_prefix,omitempty"`
+
+	// MaxTipDCR is the max amount of each tip sent by the plugin. Tips
+	// are only allowed when this is set and the plugin is granted
+	// PermSendPayments.
+	MaxTipDCR float64 `json:"max_tip_dcr,omitempty"`
+
+	// MaxDailyTipsDCR is the max total amount of the tips sent by the
+	// plugin in the last 24 hours. When unset, it is the same as
+	// MaxTipDCR.
+	MaxDailyTipsDCR float64 `json:"max_daily_tips_dcr,omitempty"`
+
+	// Disabled plugins are not started with the manager.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// validate returns an error if the config is invalid.
+func (cfg *Config) validate() error {
+	if !validPluginName.MatchString(cfg.Name) {
+		return fmt.Errorf("invalid plugin name %q", cfg.Name)
+	}
+	if cfg.Command == "" {
+		return fmt.Errorf("plugin %s does not have a command", cfg.Name)
+	}
+	for _, perm := range cfg.Permissions {
+		if _, ok := validPermissions[perm]; !ok {
+			return fmt.Errorf("plugin %s has unknown permission %q",
+				cfg.Name, perm)
+		}
+	}
+	if cfg.hasPermission(PermResources) && strings.Trim(cfg.ResourcesPrefix, "/") == "" {
+		return fmt.Errorf("plugin %s serves resources without a "+
+			"resources prefix", cfg.Name)
+	}
+	if cfg.MaxTipDCR < 0 {
+		return fmt.Errorf("plugin %s has a negative max tip", cfg.Name)
+	}
+	if cfg.MaxDailyTipsDCR < 0 {
+		return fmt.Errorf("plugin %s has a negative max of daily tips", cfg.Name)
+	}
+	return nil
+}
+
+// maxDailyTipsDCR returns the max total amount of the tips sent by the plugin
+// in the last 24 hours.
+func (cfg *Config) maxDailyTipsDCR() float64 {
+	if cfg.MaxDailyTipsDCR == 0 {
+		return cfg.MaxTipDCR
+	}
+	return cfg.MaxDailyTipsDCR
+}
+
+// hasPermission returns true if the plugin was granted the permission.
+func (cfg *Config) hasPermission(perm Permission) bool {
+	for _, p := range cfg.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadConfigFile loads the configs of plugins from a JSON file with a list of
+// Config objects.
+func LoadConfigFile(fname string) ([]Config, error) {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	var cfgs []Config
+	if err := json.Unmarshal(b, &cfgs); err != nil {
+		return nil, fmt.Errorf("unable to decode plugins config file: %v", err)
+	}
+	return cfgs, nil
+}
+
+// Host is the interface to the client used to fulfill the requests of
+// plugins.
+type Host interface {
+	PM(uid clientintf.UserID, msg string) error
+	TipUser(uid clientintf.UserID, dcrAmount float64, maxAttempts int32) error
+}
+
+// State is the state of a plugin.
+type State string
+
+const (
+	StateStopped  State = "stopped"
+	StateStarting State = "starting"
+	StateRunning  State = "running"
+
+	// StateBackoff is the state of plugins that exited unexpectedly and
+	// are waiting to be restarted.
+	StateBackoff State = "backoff"
+)
+
+// Status is the status of a plugin.
+type Status struct {
+	Name        string
+	State       State
+	Version     string
+	Permissions []Permission
+	Commands    []CommandInfo
+	Restarts    int
+	LastErr     error
+	StartedAt   time.Time
+}
+
+// ErrPluginNotFound is returned when a plugin does not exist.
+var ErrPluginNotFound = errors.New("plugin not found")
+
+// ErrPluginNotRunning is returned when making a call to a plugin that is not
+// running.
+var ErrPluginNotRunning = errors.New("plugin not running")
+
+// ErrPermissionDenied is returned when a plugin attempts to do something it
+// was not granted permission to do.
+var ErrPermissionDenied = errors.New("permission denied")
@@ -0,0 +1,416 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// helperEnv is the environment variable that makes the test binary run as a
+// test plugin.
+const helperEnv = "BR_PLUGIN_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) == "1" {
+		runTestPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runTestPlugin runs a plugin that echoes commands, resources and PMs.
+func runTestPlugin() {
+	ctx := context.Background()
+	var c *conn
+	c = newConn(os.Stdout, func(ctx context.Context, method string,
+		params json.RawMessage) (interface{}, error) {
+
+		switch method {
+		case MethodInit:
+			return InitResult{
+				Version: "1.0",
+				Commands: []CommandInfo{{Name: "echo"}, {Name: "tip"},
+					{Name: "crash"}},
+			}, nil
+
+		case MethodCommand:
+			var cp CommandParams
+			if err := json.Unmarshal(params, &cp); err != nil {
+				return nil, err
+			}
+			switch cp.Name {
+			case "echo":
+				return CommandResult{Output: strings.Join(cp.Args, " ")}, nil
+			case "tip":
+				amount, _ := strconv.ParseFloat(cp.Args[0], 64)
+				err := c.call(ctx, MethodTipUser, TipUserParams{
+					DCRAmount: amount,
+				}, nil)
+				if err != nil {
+					return CommandResult{Output: err.Error()}, nil
+				}
+				return CommandResult{Output: "ok"}, nil
+			case "crash":
+				os.Exit(1)
+			}
+			return nil, fmt.Errorf("unknown command")
+
+		case MethodFetchResource:
+			var fp FetchResourceParams
+			if err := json.Unmarshal(params, &fp); err != nil {
+				return nil, err
+			}
+			return FetchResourceResult{
+				Status: rpc.ResourceStatusOk,
+				Data:   []byte("hello " + strings.Join(fp.Path, "/")),
+			}, nil
+
+		case MethodPMReceived:
+			var pp PMReceivedParams
+			if err := json.Unmarshal(params, &pp); err != nil {
+				return nil, err
+			}
+			return nil, c.call(ctx, MethodSendPM, SendPMParams{
+				UID:     pp.UID,
+				Message: "echo: " + pp.Message,
+			}, nil)
+		}
+		return nil, fmt.Errorf("unknown method")
+	})
+	c.run(ctx, os.Stdin)
+}
+
+type testHost struct {
+	pms  chan string
+	tips chan float64
+}
+
+func (h *testHost) PM(uid clientintf.UserID, msg string) error {
+	h.pms <- msg
+	return nil
+}
+
+func (h *testHost) TipUser(uid clientintf.UserID, dcrAmount float64, maxAttempts int32) error {
+	h.tips <- dcrAmount
+	return nil
+}
+
+func testPluginConfig(name string, perms ...Permission) Config {
+	return Config{
+		Name:            name,
+		Command:         os.Args[0],
+		Env:             []string{helperEnv + "=1"},
+		Permissions:     perms,
+		ResourcesPrefix: name,
+		MaxTipDCR:       1,
+	}
+}
+
+// waitState waits until the plugin is in the given state.
+func waitState(t *testing.T, m *Manager, name string, state State) Status {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, st := range m.Plugins() {
+			if st.Name == name && st.State == state {
+				return st
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("plugin %s did not reach state %s", name, state)
+	return Status{}
+}
+
+// TestPlugins tests the hook points and permissions of plugins.
+func TestPlugins(t *testing.T) {
+	host := &testHost{pms: make(chan string, 10), tips: make(chan float64, 10)}
+	m, err := NewManager(ManagerConfig{
+		Host: host,
+		Plugins: []Config{
+			testPluginConfig("full", PermCommands, PermResources,
+				PermReadMessages, PermSendMessages, PermSendPayments),
+			testPluginConfig("limited", PermReadMessages),
+		},
+		MaxBackoff: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- m.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-runErr; err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	st := waitState(t, m, "full", StateRunning)
+	if st.Version != "1.0" || len(st.Commands) != 3 {
+		t.Fatalf("unexpected status: %v", st)
+	}
+	st = waitState(t, m, "limited", StateRunning)
+	if len(st.Commands) != 0 {
+		t.Fatalf("plugin without permission registered commands")
+	}
+
+	// Commands.
+	out, err := m.RunCommand(ctx, "full", "echo", []string{"a", "b"})
+	if err != nil || out != "a b" {
+		t.Fatalf("unexpected command result: %q %v", out, err)
+	}
+	_, err = m.RunCommand(ctx, "limited", "echo", nil)
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Resources.
+	if _, err := m.ResourcesProvider("limited"); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rp, err := m.ResourcesProvider("full")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := rp.Fulfill(ctx, clientintf.UserID{}, &rpc.RMFetchResource{
+		Path: []string{"full", "page"},
+		Tag:  10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != rpc.ResourceStatusOk || string(res.Data) != "hello full/page" || res.Tag != 10 {
+		t.Fatalf("unexpected reply: %v", res)
+	}
+
+	// Messages: only the plugin with permission to send PMs replies.
+	m.PMReceived(clientintf.UserID{}, "user", "hi", time.Now())
+	select {
+	case pm := <-host.pms:
+		if pm != "echo: hi" {
+			t.Fatalf("unexpected pm %q", pm)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for pm")
+	}
+	select {
+	case pm := <-host.pms:
+		t.Fatalf("unexpected pm %q", pm)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Payments are limited by the max tip.
+	out, err = m.RunCommand(ctx, "full", "tip", []string{"2"})
+	if err != nil || !strings.Contains(out, ErrPermissionDenied.Error()) {
+		t.Fatalf("unexpected result of tip above max: %q %v", out, err)
+	}
+	out, err = m.RunCommand(ctx, "full", "tip", []string{"0.5"})
+	if err != nil || out != "ok" {
+		t.Fatalf("unexpected result of tip: %q %v", out, err)
+	}
+	if tip := <-host.tips; tip != 0.5 {
+		t.Fatalf("unexpected tip %v", tip)
+	}
+
+	// Payments are limited by the max daily total of tips (which
+	// defaults to the max tip).
+	out, err = m.RunCommand(ctx, "full", "tip", []string{"0.6"})
+	if err != nil || !strings.Contains(out, ErrPermissionDenied.Error()) {
+		t.Fatalf("unexpected result of tip above daily max: %q %v", out, err)
+	}
+	out, err = m.RunCommand(ctx, "full", "tip", []string{"0.5"})
+	if err != nil || out != "ok" {
+		t.Fatalf("unexpected result of tip: %q %v", out, err)
+	}
+	if tip := <-host.tips; tip != 0.5 {
+		t.Fatalf("unexpected tip %v", tip)
+	}
+
+	// Crashed plugins are restarted.
+	m.RunCommand(ctx, "full", "crash", nil)
+	waitState(t, m, "full", StateBackoff)
+	st = waitState(t, m, "full", StateRunning)
+	if st.Restarts != 1 || st.LastErr == nil {
+		t.Fatalf("unexpected status after restart: %v", st)
+	}
+
+	// Stopped plugins are not restarted until started.
+	if err := m.Stop("full"); err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, m, "full", StateStopped)
+	if _, err := m.RunCommand(ctx, "full", "echo", nil); err == nil {
+		t.Fatal("command of stopped plugin did not fail")
+	}
+	if err := m.Start("full"); err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, m, "full", StateRunning)
+}
+
+// TestReserveTip tests that the total amount of the tips sent by a plugin is
+// limited in the tips window.
+func TestReserveTip(t *testing.T) {
+	p := &plugin{cfg: Config{MaxTipDCR: 1, MaxDailyTipsDCR: 2}}
+	now := time.Now()
+	assertReserve := func(dcrAmount float64, ts time.Time, wantOk bool) func() {
+		t.Helper()
+		release, err := p.reserveTip(dcrAmount, ts)
+		if wantOk && err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !wantOk && !errors.Is(err, ErrPermissionDenied) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return release
+	}
+
+	assertReserve(1, now, true)
+	release := assertReserve(0.7, now.Add(time.Hour), true)
+	assertReserve(0.5, now.Add(time.Hour), false)
+
+	// Tips that were not sent do not count towards the limit.
+	release()
+	assertReserve(0.5, now.Add(time.Hour), true)
+	assertReserve(0.5, now.Add(time.Hour), true)
+	assertReserve(0.1, now.Add(time.Hour), false)
+
+	// Tips older than the window do not count towards the limit.
+	assertReserve(1, now.Add(tipsWindow), true)
+	assertReserve(0.1, now.Add(tipsWindow), false)
+}
+
+// TestConnMaxConcurrentRequests tests that requests received while the max
+// number of requests are being handled are rejected.
+func TestConnMaxConcurrentRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{}, maxConcurrentRequests)
+	unblock := make(chan struct{})
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := newConn(outW, func(ctx context.Context, method string,
+		params json.RawMessage) (interface{}, error) {
+		started <- struct{}{}
+		<-unblock
+		return "done", nil
+	})
+	go c.run(ctx, inR)
+	defer inW.Close()
+
+	replies := make(chan *message, maxConcurrentRequests+2)
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		for scanner.Scan() {
+			msg := new(message)
+			if err := json.Unmarshal(scanner.Bytes(), msg); err != nil {
+				panic(err)
+			}
+			replies <- msg
+		}
+	}()
+	sendRequest := func(id uint64) {
+		t.Helper()
+		b, err := json.Marshal(&message{ID: id, Method: "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := inW.Write(append(b, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitReply := func() *message {
+		t.Helper()
+		select {
+		case msg := <-replies:
+			return msg
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for reply")
+			return nil
+		}
+	}
+
+	// Fill up the handlers.
+	for i := 1; i <= maxConcurrentRequests; i++ {
+		sendRequest(uint64(i))
+	}
+	for i := 0; i < maxConcurrentRequests; i++ {
+		select {
+		case <-started:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for handler to start")
+		}
+	}
+
+	// The next request is rejected.
+	sendRequest(maxConcurrentRequests + 1)
+	reply := waitReply()
+	if reply.ID != maxConcurrentRequests+1 || reply.Error != errTooManyRequests.Error() {
+		t.Fatalf("unexpected reply: %v", reply)
+	}
+
+	// Once the handlers finish, new requests are handled again.
+	close(unblock)
+	for i := 0; i < maxConcurrentRequests; i++ {
+		if reply := waitReply(); reply.Error != "" {
+			t.Fatalf("unexpected reply: %v", reply)
+		}
+	}
+	sendRequest(maxConcurrentRequests + 2)
+	reply = waitReply()
+	if reply.ID != maxConcurrentRequests+2 || reply.Error != "" {
+		t.Fatalf("unexpected reply: %v", reply)
+	}
+}
+
+// TestConfigValidation tests the validation of plugin configs.
+func TestConfigValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{{
+		name: "valid",
+		cfg:  Config{Name: "p1", Command: "p1", Permissions: []Permission{PermCommands}},
+		ok:   true,
+	}, {
+		name: "invalid name",
+		cfg:  Config{Name: "../p1", Command: "p1"},
+	}, {
+		name: "no command",
+		cfg:  Config{Name: "p1"},
+	}, {
+		name: "resources without prefix",
+		cfg:  Config{Name: "p1", Command: "p1", Permissions: []Permission{PermResources}},
+	}, {
+		name: "negative max daily tips",
+		cfg:  Config{Name: "p1", Command: "p1", MaxDailyTipsDCR: -1},
+	}, {
+		name: "unknown permission",
+		cfg:  Config{Name: "p1", Command: "p1", Permissions: []Permission{"all"}},
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewManager(ManagerConfig{Plugins: []Config{tc.cfg}})
+			if (err == nil) != tc.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// maxMessageSize is the max size of a single message exchanged with a
+// plugin.
+const maxMessageSize = 16 * 1024 * 1024
+
+// maxConcurrentRequests is the max number of requests received through a
+// connection that are handled concurrently.
+const maxConcurrentRequests = 32
+
+// errConnClosed is returned by calls made after the connection to the plugin
+// was closed.
+var errConnClosed = errors.New("plugin connection closed")
+
+// errTooManyRequests is the error replied to requests received while the max
+// number of requests are already being handled.
+var errTooManyRequests = errors.New("too many concurrent requests")
+
+// Methods called by the client on plugins.
+const (
+	// MethodInit is the first call made to a plugin after it starts. Its
+	// params are an InitParams and its result an InitResult.
+	MethodInit = "init"
+
+	// MethodCommand runs a command registered by the plugin. Its params
+	// are a CommandParams and its result a CommandResult.
+	MethodCommand = "command"
+
+	// MethodFetchResource fulfills a resource request from a remote user.
+	// Its params are a FetchResourceParams and its result a
+	// FetchResourceResult.
+	MethodFetchResource = "fetch_resource"
+
+	// MethodPMReceived is a notification of a PM received from a remote
+	// user. Its params are a PMReceivedParams.
+	MethodPMReceived = "pm_received"
+
+	// MethodTipReceived is a notification of a tip received from a remote
+	// user. Its params are a TipReceivedParams.
+	MethodTipReceived = "tip_received"
+)
+
+// Methods called by plugins on the client.
+const (
+	// MethodSendPM sends a PM to a remote user. Its params are a
+	// SendPMParams.
+	MethodSendPM = "send_pm"
+
+	// MethodTipUser sends a tip to a remote user. Its params are a
+	// TipUserParams.
+	MethodTipUser = "tip_user"
+
+	// MethodLog is a notification with a message to log. Its params are a
+	// LogParams.
+	MethodLog = "log"
+)
+
+// message is a message exchanged with a plugin. Messages are encoded as JSON
+// objects, one per line. Messages with a method are requests (or
+// notifications, if they do not have an id) and messages without a method are
+// replies to the request with the same id.
+type message struct {
+	ID     uint64          `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// handlerFunc handles a request received through a connection. The returned
+// result is sent as the reply of requests that have an id.
+type handlerFunc func(ctx context.Context, method string, params json.RawMessage) (interface{}, error)
+
+// conn is a bidirectional connection with a plugin (or with the client, from
+// the point of view of a plugin), where either side may make requests to the
+// other.
+type conn struct {
+	handler handlerFunc
+	sem     chan struct{}
+
+	wmtx sync.Mutex
+	w    io.Writer
+
+	mtx     sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *message
+	closed  bool
+	done    chan struct{}
+}
+
+// newConn creates a new connection that writes messages to w. Requests
+// received by run are handled by handler.
+func newConn(w io.Writer, handler handlerFunc) *conn {
+	return &conn{
+		handler: handler,
+		sem:     make(chan struct{}, maxConcurrentRequests),
+		w:       w,
+		pending: make(map[uint64]chan *message),
+		done:    make(chan struct{}),
+	}
+}
+
+func (c *conn) write(msg *message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	c.wmtx.Lock()
+	_, err = c.w.Write(b)
+	c.wmtx.Unlock()
+	return err
+}
+
+// call makes a request and waits for its reply, which is decoded into result
+// (if not nil).
+func (c *conn) call(ctx context.Context, method string, params, result interface{}) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	if c.closed {
+		c.mtx.Unlock()
+		return errConnClosed
+	}
+	c.nextID += 1
+	id := c.nextID
+	replyChan := make(chan *message, 1)
+	c.pending[id] = replyChan
+	c.mtx.Unlock()
+
+	defer func() {
+		c.mtx.Lock()
+		delete(c.pending, id)
+		c.mtx.Unlock()
+	}()
+
+	err = c.write(&message{ID: id, Method: method, Params: rawParams})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case reply := <-replyChan:
+		if reply.Error != "" {
+			return errors.New(reply.Error)
+		}
+		if result == nil || len(reply.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(reply.Result, result)
+	case <-c.done:
+		return errConnClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notify sends a request that does not have a reply.
+func (c *conn) notify(method string, params interface{}) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(&message{Method: method, Params: rawParams})
+}
+
+// handle handles a request and sends its reply. The slot of the request in
+// sem is released once it is handled.
+func (c *conn) handle(ctx context.Context, msg *message) {
+	res, err := c.handler(ctx, msg.Method, msg.Params)
+	<-c.sem
+	if msg.ID == 0 {
+		return
+	}
+	reply := &message{ID: msg.ID}
+	if err != nil {
+		reply.Error = err.Error()
+	} else if res != nil {
+		reply.Result, err = json.Marshal(res)
+		if err != nil {
+			reply.Error = fmt.Sprintf("unable to encode result: %v", err)
+		}
+	}
+	c.write(reply)
+}
+
+// run reads messages from r until it is closed or has an invalid message.
+// Requests are handled concurrently, in new goroutines, up to
+// maxConcurrentRequests at a time. Requests received over that limit are
+// replied with errTooManyRequests (and notifications are dropped), instead of
+// blocking reads, so that replies to pending calls are still received.
+func (c *conn) run(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	var err error
+	for scanner.Scan() {
+		msg := new(message)
+		if err = json.Unmarshal(scanner.Bytes(), msg); err != nil {
+			err = fmt.Errorf("invalid message: %v", err)
+			break
+		}
+		if msg.Method != "" {
+			select {
+			case c.sem <- struct{}{}:
+				go c.handle(ctx, msg)
+			default:
+				if msg.ID != 0 {
+					c.write(&message{ID: msg.ID, Error: errTooManyRequests.Error()})
+				}
+			}
+			continue
+		}
+
+		c.mtx.Lock()
+		replyChan := c.pending[msg.ID]
+		c.mtx.Unlock()
+		if replyChan != nil {
+			// Ignore duplicated replies.
+			select {
+			case replyChan <- msg:
+			default:
+			}
+		}
+	}
+	if err == nil {
+		err = scanner.Err()
+	}
+	if err == nil {
+		err = io.EOF
+	}
+
+	c.mtx.Lock()
+	c.closed = true
+	c.mtx.Unlock()
+	close(c.done)
+	return err
+}
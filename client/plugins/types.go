@@ -0,0 +1,81 @@
+package plugins
+
+import (
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// InitParams are the params of the MethodInit call.
+type InitParams struct {
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// CommandInfo describes a command registered by a plugin.
+type CommandInfo struct {
+	Name  string `json:"name"`
+	Descr string `json:"descr"`
+}
+
+// InitResult is the result of the MethodInit call.
+type InitResult struct {
+	Version  string        `json:"version"`
+	Commands []CommandInfo `json:"commands"`
+}
+
+// CommandParams are the params of the MethodCommand call.
+type CommandParams struct {
+	Name string   `json:"name"`
+	Args []string `json:"args"`
+}
+
+// CommandResult is the result of the MethodCommand call.
+type CommandResult struct {
+	Output string `json:"output"`
+}
+
+// FetchResourceParams are the params of the MethodFetchResource call.
+type FetchResourceParams struct {
+	UID  clientintf.UserID `json:"uid"`
+	Path []string          `json:"path"`
+	Meta map[string]string `json:"meta,omitempty"`
+	Data []byte            `json:"data,omitempty"`
+}
+
+// FetchResourceResult is the result of the MethodFetchResource call.
+type FetchResourceResult struct {
+	Status int               `json:"status"`
+	Meta   map[string]string `json:"meta,omitempty"`
+	Data   []byte            `json:"data,omitempty"`
+}
+
+// PMReceivedParams are the params of the MethodPMReceived notification.
+type PMReceivedParams struct {
+	UID       clientintf.UserID `json:"uid"`
+	Nick      string            `json:"nick"`
+	Message   string            `json:"message"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// TipReceivedParams are the params of the MethodTipReceived notification.
+type TipReceivedParams struct {
+	UID          clientintf.UserID `json:"uid"`
+	AmountMAtoms int64             `json:"amount_matoms"`
+}
+
+// SendPMParams are the params of the MethodSendPM call.
+type SendPMParams struct {
+	UID     clientintf.UserID `json:"uid"`
+	Message string            `json:"message"`
+}
+
+// TipUserParams are the params of the MethodTipUser call.
+type TipUserParams struct {
+	UID       clientintf.UserID `json:"uid"`
+	DCRAmount float64           `json:"dcr_amount"`
+}
+
+// LogParams are the params of the MethodLog notification.
+type LogParams struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,214 @@
+// Package postarchive reads and writes portable archives of posts, which allow
+// users to keep a copy of their posts outside of BR and to republish them from
+// another identity.
+//
+// An archive is a zip file with the following layout:
+//
+//	manifest.json             - version, export date, author and post IDs
+//	posts/<id>/post.md        - the body of the post (markdown)
+//	posts/<id>/post.json      - metadata and comments of the post
+//	posts/<id>/attachment     - the embedded file of the post (if any)
+//
+// The format does not depend on the internal representation of posts in the
+// client, so archives may be read by other tools.
+package postarchive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+// Version is the version of the archive format written by this package.
+const Version = 1
+
+// MaxFileSize is the max size of a single file read from an archive.
+const MaxFileSize = 64 * 1024 * 1024
+
+const (
+	manifestFile   = "manifest.json"
+	postsDir       = "posts"
+	postBodyFile   = "post.md"
+	postMetaFile   = "post.json"
+	attachmentFile = "attachment"
+)
+
+// Comment is a comment made on a post.
+type Comment struct {
+	// ID is the (hex encoded) ID of the comment and Parent the ID of the
+	// comment it replies to, if any.
+	ID     string `json:"id"`
+	Parent string `json:"parent,omitempty"`
+
+	From      string    `json:"from"`
+	Nick      string    `json:"nick,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Comment   string    `json:"comment"`
+}
+
+// Post is a post in an archive.
+type Post struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Created     time.Time `json:"created"`
+	Comments    []Comment `json:"comments,omitempty"`
+
+	// Body is the content of the post (stored in post.md) and Attachment
+	// the embedded file of the post (stored in attachment).
+	Body       string `json:"-"`
+	Attachment []byte `json:"-"`
+}
+
+// Archive is an archive of the posts of a user.
+type Archive struct {
+	Version    int       `json:"version"`
+	Exported   time.Time `json:"exported"`
+	AuthorID   string    `json:"author_id"`
+	AuthorNick string    `json:"author_nick,omitempty"`
+
+	// PostIDs lists the IDs of the posts in the archive, in the same order
+	// as Posts.
+	PostIDs []string `json:"posts"`
+	Posts   []Post   `json:"-"`
+}
+
+// Write writes the archive as a zip file to w. The Version and PostIDs fields
+// of the archive are filled by this function.
+func Write(w io.Writer, a *Archive) error {
+	a.Version = Version
+	a.PostIDs = make([]string, len(a.Posts))
+	for i := range a.Posts {
+		if a.Posts[i].ID == "" {
+			return fmt.Errorf("post %d does not have an ID", i)
+		}
+		a.PostIDs[i] = a.Posts[i].ID
+	}
+
+	zw := zip.NewWriter(w)
+	writeFile := func(name string, data []byte) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	writeJSON := func(name string, v interface{}) error {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFile(name, b)
+	}
+
+	if err := writeJSON(manifestFile, a); err != nil {
+		return err
+	}
+	for i := range a.Posts {
+		p := &a.Posts[i]
+		dir := path.Join(postsDir, p.ID)
+		if err := writeFile(path.Join(dir, postBodyFile), []byte(p.Body)); err != nil {
+			return err
+		}
+		if err := writeJSON(path.Join(dir, postMetaFile), p); err != nil {
+			return err
+		}
+		if len(p.Attachment) > 0 {
+			err := writeFile(path.Join(dir, attachmentFile), p.Attachment)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
+
+// readZipFile reads the named file of the zip archive. Returns nil data if the
+// file does not exist.
+func readZipFile(files map[string]*zip.File, name string) ([]byte, error) {
+	f := files[name]
+	if f == nil {
+		return nil, nil
+	}
+	if f.UncompressedSize64 > MaxFileSize {
+		return nil, fmt.Errorf("file %s is larger than the max file size", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", name, err)
+	}
+	if len(data) > MaxFileSize {
+		return nil, fmt.Errorf("file %s is larger than the max file size", name)
+	}
+	return data, nil
+}
+
+// Read reads an archive from the zip file in r.
+func Read(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a posts archive: %v", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	data, err := readZipFile(files, manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errors.New("not a posts archive: manifest not found")
+	}
+	var a Archive
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+	if a.Version < 1 || a.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d", a.Version)
+	}
+
+	a.Posts = make([]Post, 0, len(a.PostIDs))
+	for _, id := range a.PostIDs {
+		dir := path.Join(postsDir, id)
+		data, err := readZipFile(files, path.Join(dir, postMetaFile))
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			return nil, fmt.Errorf("metadata of post %s not found", id)
+		}
+		var p Post
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("invalid metadata of post %s: %v", id, err)
+		}
+		p.ID = id
+
+		body, err := readZipFile(files, path.Join(dir, postBodyFile))
+		if err != nil {
+			return nil, err
+		}
+		if body == nil {
+			return nil, fmt.Errorf("body of post %s not found", id)
+		}
+		p.Body = string(body)
+
+		p.Attachment, err = readZipFile(files, path.Join(dir, attachmentFile))
+		if err != nil {
+			return nil, err
+		}
+		a.Posts = append(a.Posts, p)
+	}
+	return &a, nil
+}
@@ -0,0 +1,70 @@
+package postarchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+)
+
+// TestWriteReadArchive tests that archives are read back as written.
+func TestWriteReadArchive(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	a := &Archive{
+		Exported:   ts,
+		AuthorID:   "0102",
+		AuthorNick: "alice",
+		Posts: []Post{{
+			ID:          "aa",
+			Title:       "First post",
+			Description: "the first",
+			Created:     ts,
+			Body:        "# First post\n\nHello.\n",
+			Comments: []Comment{{
+				ID:        "c1",
+				From:      "0304",
+				Nick:      "bob",
+				Timestamp: ts,
+				Comment:   "nice",
+			}, {
+				ID:      "c2",
+				Parent:  "c1",
+				From:    "0102",
+				Comment: "thanks",
+			}},
+		}, {
+			ID:         "bb",
+			Title:      "Second post",
+			Created:    ts.Add(time.Hour),
+			Body:       "Second",
+			Attachment: []byte{0, 1, 2, 3},
+		}},
+	}
+
+	var b bytes.Buffer
+	assert.NilErr(t, Write(&b, a))
+	got, err := Read(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, got, a)
+}
+
+// TestReadInvalidArchive tests that invalid archives are rejected.
+func TestReadInvalidArchive(t *testing.T) {
+	// Not a zip file.
+	data := []byte("not a zip file")
+	_, err := Read(bytes.NewReader(data), int64(len(data)))
+	assert.NonNilErr(t, err)
+
+	// Missing post files.
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	f, err := zw.Create(manifestFile)
+	assert.NilErr(t, err)
+	_, err = f.Write([]byte(`{"version":1,"author_id":"01","posts":["aa"]}`))
+	assert.NilErr(t, err)
+	assert.NilErr(t, zw.Close())
+	_, err = Read(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	assert.NonNilErr(t, err)
+}
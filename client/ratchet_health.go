@@ -0,0 +1,328 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// RatchetAlertType is the type of an anomaly detected in the ratchets and KXs
+// of the client.
+type RatchetAlertType string
+
+const (
+	// RatchetAlertDecryptFailures is raised when repeated messages from
+	// a user fail to be decrypted, without any successful decryption
+	// in between.
+	RatchetAlertDecryptFailures RatchetAlertType = "decrypt-failures"
+
+	// RatchetAlertRatchetError is raised when the ratchet with a user
+	// failed to encrypt and is no longer used.
+	RatchetAlertRatchetError RatchetAlertType = "ratchet-error"
+
+	// RatchetAlertReusedKey is raised when the identity keys of a user
+	// are also used by a different user or by the local client.
+	RatchetAlertReusedKey RatchetAlertType = "reused-key"
+
+	// RatchetAlertStalledKX is raised when a KX has not progressed for
+	// longer than the configured threshold.
+	RatchetAlertStalledKX RatchetAlertType = "stalled-kx"
+
+	// RatchetAlertAbandonedClient is raised when a user stopped replying
+	// to requests and no messages were received from them since, which
+	// indicates they abandoned their client.
+	RatchetAlertAbandonedClient RatchetAlertType = "abandoned-client"
+)
+
+// RatchetHealthAlert is an alert about an anomaly detected in the ratchets and
+// KXs of the client.
+type RatchetHealthAlert struct {
+	Type RatchetAlertType
+
+	// UID is the user the alert refers to. It is nil for alerts about
+	// KXs with users whose identity is not yet known.
+	UID *UserID
+
+	// Key identifies the alert among the alerts of the same type, for
+	// alerts that are not about a single user.
+	Key string
+
+	// Detail describes the anomaly.
+	Detail string
+
+	// Remediation is the suggested action to fix the anomaly.
+	Remediation string
+
+	// Timestamp is when the anomaly was first detected.
+	Timestamp time.Time
+}
+
+// id identifies the alert across checks.
+func (a *RatchetHealthAlert) id() string {
+	if a.UID != nil {
+		return fmt.Sprintf("%s/%s/%s", a.Type, a.UID, a.Key)
+	}
+	return fmt.Sprintf("%s//%s", a.Type, a.Key)
+}
+
+// String returns the alert as a human readable message.
+func (a RatchetHealthAlert) String() string {
+	if a.Remediation == "" {
+		return a.Detail
+	}
+	return fmt.Sprintf("%s. %s", a.Detail, a.Remediation)
+}
+
+// ratchetHealth tracks the active ratchet health alerts.
+type ratchetHealth struct {
+	mtx    sync.Mutex
+	active map[string]RatchetHealthAlert
+}
+
+// update replaces the active alerts with the ones found in the last check.
+// It returns the alerts that were not active before. Alerts that were already
+// active keep the time they were first detected.
+func (rh *ratchetHealth) update(found []RatchetHealthAlert) []RatchetHealthAlert {
+	rh.mtx.Lock()
+	defer rh.mtx.Unlock()
+
+	var added []RatchetHealthAlert
+	active := make(map[string]RatchetHealthAlert, len(found))
+	for _, a := range found {
+		id := a.id()
+		if old, ok := rh.active[id]; ok {
+			a.Timestamp = old.Timestamp
+		} else {
+			added = append(added, a)
+		}
+		active[id] = a
+	}
+	rh.active = active
+	return added
+}
+
+// alerts returns the active alerts, oldest first.
+func (rh *ratchetHealth) alerts() []RatchetHealthAlert {
+	rh.mtx.Lock()
+	res := make([]RatchetHealthAlert, 0, len(rh.active))
+	for _, a := range rh.active {
+		res = append(res, a)
+	}
+	rh.mtx.Unlock()
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Timestamp.Equal(res[j].Timestamp) {
+			return res[i].Timestamp.Before(res[j].Timestamp)
+		}
+		return res[i].id() < res[j].id()
+	})
+	return res
+}
+
+// findReusedKeys returns alerts for the remote identities that use the same
+// signing or encryption key as another remote identity or the local identity.
+func findReusedKeys(local *zkidentity.PublicIdentity, remotes []*zkidentity.PublicIdentity,
+	now time.Time) []RatchetHealthAlert {
+
+	sigKeys := map[zkidentity.FixedSizeEd25519PublicKey]*zkidentity.PublicIdentity{
+		local.SigKey: local,
+	}
+	encKeys := map[zkidentity.FixedSizeSntrupPublicKey]*zkidentity.PublicIdentity{
+		local.Key: local,
+	}
+
+	// Sort the identities so that the alerts are raised for the same
+	// user in every check.
+	sorted := append([]*zkidentity.PublicIdentity(nil), remotes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Identity.String() < sorted[j].Identity.String()
+	})
+
+	var res []RatchetHealthAlert
+	for _, id := range sorted {
+		uid := id.Identity
+		var other *zkidentity.PublicIdentity
+		var keyType string
+		if o, ok := sigKeys[id.SigKey]; ok {
+			other, keyType = o, "signing"
+		} else if o, ok := encKeys[id.Key]; ok {
+			other, keyType = o, "encryption"
+		} else {
+			sigKeys[id.SigKey] = id
+			encKeys[id.Key] = id
+			continue
+		}
+
+		detail := fmt.Sprintf("User %q uses the same %s key as user %q",
+			id.Nick, keyType, other.Nick)
+		if other == local {
+			detail = fmt.Sprintf("User %q uses the same %s key as the "+
+				"local client", id.Nick, keyType)
+		}
+		res = append(res, RatchetHealthAlert{
+			Type:   RatchetAlertReusedKey,
+			UID:    &uid,
+			Key:    keyType,
+			Detail: detail,
+			Remediation: "This may indicate an impersonation attempt. " +
+				"Verify the identity of the user out of band before " +
+				"trusting them and remove the user if it cannot be " +
+				"verified",
+			Timestamp: now,
+		})
+	}
+	return res
+}
+
+// checkRatchetHealth looks for anomalies in the ratchets and KXs of the
+// client.
+func (c *Client) checkRatchetHealth() ([]RatchetHealthAlert, error) {
+	now := time.Now()
+	var res []RatchetHealthAlert
+
+	uids := c.rul.userList()
+	remotes := make([]*zkidentity.PublicIdentity, 0, len(uids))
+	for _, uid := range uids {
+		ru, err := c.rul.byID(uid)
+		if err != nil {
+			continue
+		}
+		uid := uid
+		id := ru.PublicIdentity()
+		remotes = append(remotes, &id)
+
+		failures, lastFailure := ru.decryptFailures()
+		if failures >= c.cfg.DecryptFailuresAlertThreshold {
+			res = append(res, RatchetHealthAlert{
+				Type: RatchetAlertDecryptFailures,
+				UID:  &uid,
+				Detail: fmt.Sprintf("%d messages from user %q failed "+
+					"to be decrypted (last one at %s)", failures,
+					ru.Nick(), lastFailure.Format(time.RFC3339)),
+				Remediation: "The ratchet may be out of sync. Reset " +
+					"the ratchet with the user (for example, with " +
+					"the /reset command of brclient)",
+				Timestamp: now,
+			})
+		}
+
+		if err := ru.ratchetError(); err != nil {
+			res = append(res, RatchetHealthAlert{
+				Type: RatchetAlertRatchetError,
+				UID:  &uid,
+				Detail: fmt.Sprintf("The ratchet with user %q failed "+
+					"and is not being used: %v", ru.Nick(), err),
+				Remediation: "Reset the ratchet with the user (for " +
+					"example, with the /reset command of brclient)",
+				Timestamp: now,
+			})
+		}
+
+		if score, err := c.PeerScore(uid); err == nil && score.Abandoned {
+			res = append(res, RatchetHealthAlert{
+				Type: RatchetAlertAbandonedClient,
+				UID:  &uid,
+				Detail: fmt.Sprintf("User %q did not reply to the last "+
+					"%d requests and no messages were received "+
+					"from them since", ru.Nick(), score.MissedStreak),
+				Remediation: "The user may have abandoned their client. " +
+					"Contact them out of band to check whether they " +
+					"moved to a new client",
+				Timestamp: now,
+			})
+		}
+	}
+	res = append(res, findReusedKeys(&c.id.Public, remotes, now)...)
+
+	var kxs []clientdb.KXData
+	err := c.dbView(func(tx clientdb.ReadTx) error {
+		var err error
+		kxs, err = c.db.ListKXs(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, kx := range kxs {
+		age := now.Sub(kx.Timestamp)
+		if age < c.cfg.StalledKXAlertThreshold {
+			continue
+		}
+		alert := RatchetHealthAlert{
+			Type: RatchetAlertStalledKX,
+			Key:  kx.InitialRV.String(),
+			Remediation: "Ask the remote user to accept the invite again " +
+				"or send them a new invite",
+			Timestamp: now,
+		}
+		days := int(age.Hours() / 24)
+		if !kx.Public.Identity.IsEmpty() {
+			uid := kx.Public.Identity
+			alert.UID = &uid
+			alert.Detail = fmt.Sprintf("KX with user %q stalled at "+
+				"stage %s for %d days", kx.Public.Nick, kx.Stage, days)
+		} else if kx.Invitee != nil {
+			uid := kx.Invitee.Identity
+			alert.UID = &uid
+			alert.Detail = fmt.Sprintf("KX with invitee %q stalled at "+
+				"stage %s for %d days", kx.Invitee.Nick, kx.Stage, days)
+		} else {
+			alert.Detail = fmt.Sprintf("KX %s stalled at stage %s for "+
+				"%d days", kx.InitialRV, kx.Stage, days)
+		}
+		res = append(res, alert)
+	}
+
+	return res, nil
+}
+
+// CheckRatchetHealth checks the ratchets and KXs of the client for anomalies
+// and returns the active alerts. New alerts are also notified through
+// OnRatchetHealthAlertNtfn.
+func (c *Client) CheckRatchetHealth() ([]RatchetHealthAlert, error) {
+	found, err := c.checkRatchetHealth()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range c.ratchetHealth.update(found) {
+		if a.UID != nil {
+			c.log.Warnf("Ratchet health alert (%s) for user %s: %s",
+				a.Type, a.UID, a.Detail)
+		} else {
+			c.log.Warnf("Ratchet health alert (%s): %s", a.Type, a.Detail)
+		}
+		c.ntfns.notifyRatchetHealthAlert(a)
+	}
+	return c.ratchetHealth.alerts(), nil
+}
+
+// RatchetHealthAlerts returns the alerts found in the last ratchet health
+// check that are still active.
+func (c *Client) RatchetHealthAlerts() []RatchetHealthAlert {
+	return c.ratchetHealth.alerts()
+}
+
+// runRatchetHealthChecks periodically checks the health of the ratchets and
+// KXs until the context is done.
+func (c *Client) runRatchetHealthChecks(ctx context.Context) error {
+	if c.cfg.RatchetHealthCheckInterval < 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(c.cfg.RatchetHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if _, err := c.CheckRatchetHealth(); err != nil {
+			c.log.Errorf("Unable to check ratchet health: %v", err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// TestFindReusedKeys tests that remote identities that reuse keys of other
+// identities are detected.
+func TestFindReusedKeys(t *testing.T) {
+	t.Parallel()
+
+	rnd := testRand(t)
+	local := testID(t, rnd, "local")
+	alice := testID(t, rnd, "alice")
+	bob := testID(t, rnd, "bob")
+
+	// Mallory reuses the signing key of alice and eve reuses the
+	// encryption key of the local client.
+	mallory := testID(t, rnd, "mallory")
+	mallory.Public.SigKey = alice.Public.SigKey
+	eve := testID(t, rnd, "eve")
+	eve.Public.Key = local.Public.Key
+
+	now := time.Now()
+	remotes := []*zkidentity.PublicIdentity{&alice.Public, &bob.Public}
+	if got := findReusedKeys(&local.Public, remotes, now); len(got) != 0 {
+		t.Fatalf("unexpected alerts: %v", got)
+	}
+
+	remotes = append(remotes, &mallory.Public, &eve.Public)
+	got := findReusedKeys(&local.Public, remotes, now)
+	if len(got) != 2 {
+		t.Fatalf("unexpected nb of alerts: got %d, want 2", len(got))
+	}
+	// Identities are checked in a stable order, so either alice or
+	// mallory is flagged for the reused signing key.
+	wantKeys := map[zkidentity.ShortID]string{
+		alice.Public.Identity:   "signing",
+		mallory.Public.Identity: "signing",
+		eve.Public.Identity:     "encryption",
+	}
+	for _, a := range got {
+		if a.Type != RatchetAlertReusedKey {
+			t.Fatalf("unexpected alert type %s", a.Type)
+		}
+		if a.UID == nil || wantKeys[*a.UID] != a.Key {
+			t.Fatalf("unexpected alert %v", a)
+		}
+		if a.Remediation == "" {
+			t.Fatalf("alert without remediation")
+		}
+	}
+}
+
+// TestRatchetHealthUpdate tests that only new alerts are returned when the
+// active alerts are updated.
+func TestRatchetHealthUpdate(t *testing.T) {
+	t.Parallel()
+
+	var uid1, uid2 UserID
+	uid1[0], uid2[0] = 1, 2
+	t1 := time.Now()
+	t2 := t1.Add(time.Hour)
+
+	var rh ratchetHealth
+	added := rh.update([]RatchetHealthAlert{
+		{Type: RatchetAlertDecryptFailures, UID: &uid1, Timestamp: t1},
+		{Type: RatchetAlertStalledKX, Key: "kx1", Timestamp: t1},
+	})
+	if len(added) != 2 {
+		t.Fatalf("unexpected nb of new alerts: got %d, want 2", len(added))
+	}
+
+	// Alerts still active are not returned again and keep their original
+	// timestamp.
+	added = rh.update([]RatchetHealthAlert{
+		{Type: RatchetAlertDecryptFailures, UID: &uid1, Timestamp: t2},
+		{Type: RatchetAlertDecryptFailures, UID: &uid2, Timestamp: t2},
+	})
+	if len(added) != 1 || *added[0].UID != uid2 {
+		t.Fatalf("unexpected new alerts: %v", added)
+	}
+	alerts := rh.alerts()
+	if len(alerts) != 2 {
+		t.Fatalf("unexpected nb of active alerts: got %d, want 2", len(alerts))
+	}
+	if *alerts[0].UID != uid1 || !alerts[0].Timestamp.Equal(t1) {
+		t.Fatalf("unexpected first alert: %v", alerts[0])
+	}
+
+	// An alert that cleared is notified again if it reappears.
+	rh.update(nil)
+	added = rh.update([]RatchetHealthAlert{
+		{Type: RatchetAlertDecryptFailures, UID: &uid1, Timestamp: t2},
+	})
+	if len(added) != 1 {
+		t.Fatalf("unexpected nb of new alerts: got %d, want 1", len(added))
+	}
+}
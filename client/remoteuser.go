@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,8 @@ import (
 	"github.com/companyzero/bisonrelay/client/clientintf"
 	"github.com/companyzero/bisonrelay/client/internal/lowlevel"
 	"github.com/companyzero/bisonrelay/client/internal/waitingq"
+	"github.com/companyzero/bisonrelay/client/metrics"
+	"github.com/companyzero/bisonrelay/client/tracing"
 	"github.com/companyzero/bisonrelay/ratchet"
 	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/companyzero/bisonrelay/sw"
@@ -73,6 +76,8 @@ type RemoteUser struct {
 	decryptedRMChan chan error
 	sentRMChan      chan error
 	compressLevel   int
+	tracer          *tracing.Tracer
+	metrics         *metrics.Metrics
 	myResetRV       clientdb.RawRVID
 	theirResetRV    clientdb.RawRVID
 
@@ -80,6 +85,15 @@ type RemoteUser struct {
 	mtx     sync.Mutex
 	ignored bool
 
+	// remoteCaps are the RM capabilities advertised by the remote user in
+	// the last received RM.
+	remoteCaps rpc.RMCapabilities
+
+	// decryptFails is the number of received messages that failed to be
+	// decrypted since the last successfully decrypted one.
+	decryptFails    int
+	lastDecryptFail time.Time
+
 	// rmHandler is called whenever we receive a RM from this user. This is
 	// called as a goroutine.
 	rmHandler func(ru *RemoteUser, h *rpc.RMHeader, c interface{}, ts time.Time)
@@ -88,6 +102,12 @@ type RemoteUser struct {
 	// before run() returns.
 	rmHandlerWG sync.WaitGroup
 
+	// lastRMDispatched is closed once the handler of the last decrypted RM
+	// is allowed to be followed by the handler of the next RM. This keeps
+	// handlers starting in the same order the RMs were decrypted.
+	// Protected by rLock.
+	lastRMDispatched chan struct{}
+
 	// wq* keeps track of inflight calls to this user that need to be done
 	// one at a time, because there's no way to demux replies.
 	wqSubPosts *waitingq.WaitingReplyQueue
@@ -98,6 +118,8 @@ type RemoteUser struct {
 }
 
 func newRemoteUser(q rmqIntf, rmgr rdzvManagerIntf, db *clientdb.DB, remoteID *zkidentity.PublicIdentity, localID *zkidentity.FullIdentity, r *ratchet.Ratchet) *RemoteUser {
+	lastRMDispatched := make(chan struct{})
+	close(lastRMDispatched)
 	return &RemoteUser{
 		q:               q,
 		rmgr:            rmgr,
@@ -114,6 +136,8 @@ func newRemoteUser(q rmqIntf, rmgr rdzvManagerIntf, db *clientdb.DB, remoteID *z
 		ratchetChan:     make(chan *ratchet.Ratchet),
 		decryptedRMChan: make(chan error),
 		sentRMChan:      make(chan error),
+
+		lastRMDispatched: lastRMDispatched,
 	}
 }
 
@@ -176,6 +200,23 @@ func (ru *RemoteUser) IsIgnored() bool {
 	return res
 }
 
+// decryptFailures returns the number of received messages that failed to be
+// decrypted since the last successfully decrypted one and the time of the last
+// failure.
+func (ru *RemoteUser) decryptFailures() (int, time.Time) {
+	ru.mtx.Lock()
+	defer ru.mtx.Unlock()
+	return ru.decryptFails, ru.lastDecryptFail
+}
+
+// ratchetError returns the error that caused the ratchet with the user to
+// stop being used, if any.
+func (ru *RemoteUser) ratchetError() error {
+	ru.rLock.Lock()
+	defer ru.rLock.Unlock()
+	return ru.rError
+}
+
 func (ru *RemoteUser) SetIgnored(ignored bool) {
 	ru.mtx.Lock()
 	ru.ignored = ignored
@@ -234,6 +275,21 @@ func removeUnackedRMDueToErr(err error) bool {
 	return true
 }
 
+// composeOpts returns the options to use when composing an RM to be sent to
+// this user. Large RMs are compressed with zstd once the remote user has
+// advertised support for it.
+func (ru *RemoteUser) composeOpts() rpc.RMComposeOpts {
+	ru.mtx.Lock()
+	remoteCaps := ru.remoteCaps
+	ru.mtx.Unlock()
+
+	return rpc.RMComposeOpts{
+		ZlibLevel:    ru.compressLevel,
+		Zstd:         remoteCaps.Has(rpc.RMCapZstdCompression),
+		Capabilities: rpc.SupportedRMCapabilities,
+	}
+}
+
 // queueRMPriority queues the given payload in the underlying RMQ and returns
 // once it has been queued.
 //
@@ -245,16 +301,27 @@ func (ru *RemoteUser) queueRMPriority(payload interface{}, priority uint,
 		return fmt.Errorf("priority must be max 4")
 	}
 
-	me, err := rpc.ComposeCompressedRM(ru.localID, payload, ru.compressLevel)
+	span := ru.tracer.Start("rm.send")
+	span.SetAttr("payload", fmt.Sprintf("%T", payload))
+	span.SetAttr("user", ru.id.Identity.String())
+	span.SetAttr("priority", strconv.Itoa(int(priority)))
+
+	composeSpan := span.Child("compose")
+	me, err := rpc.ComposeRMWithOpts(ru.localID, payload, ru.composeOpts())
+	composeSpan.End(err)
 	if err != nil {
+		span.End(err)
 		return err
 	}
+	span.SetAttr("size", strconv.Itoa(len(me)))
 
 	if rpc.EstimateRoutedRMWireSize(len(me)) > rpc.MaxMsgSize {
-		return fmt.Errorf("message %T estimated as larger than "+
+		err := fmt.Errorf("message %T estimated as larger than "+
 			"max message size %d > %d: %w", payload,
 			rpc.EstimateRoutedRMWireSize(len(me)),
 			rpc.MaxMsgSize, errRMTooLarge)
+		span.End(err)
+		return err
 	}
 
 	if ru.logPayloads.Level() <= slog.LevelTrace {
@@ -270,6 +337,7 @@ func (ru *RemoteUser) queueRMPriority(payload interface{}, priority uint,
 		ru:       ru,
 		payloadT: fmt.Sprintf("%T", payload),
 		payEvent: payEvent,
+		span:     span,
 	}
 
 	// This inner channel is needed in order to alert run() of completed
@@ -278,6 +346,7 @@ func (ru *RemoteUser) queueRMPriority(payload interface{}, priority uint,
 
 	ru.log.Tracef("Queuing to RMQ %T", payload)
 	if err := ru.q.QueueRM(orm, innerReplyChan); err != nil {
+		span.End(err)
 		return err
 	}
 
@@ -301,6 +370,12 @@ func (ru *RemoteUser) queueRMPriority(payload interface{}, priority uint,
 		case <-ru.runDone:
 			err = errRemoteUserExiting
 		}
+		span.End(err)
+		if err != nil {
+			ru.metrics.Inc(metrics.RMSendErrors)
+		} else {
+			ru.metrics.Inc(metrics.RMsSent)
+		}
 
 		if replyChan != nil {
 			replyChan <- err
@@ -363,6 +438,7 @@ func (ru *RemoteUser) sendPM(msg string) error {
 	return ru.sendRMPriority(rpc.RMPrivateMessage{
 		Mode:    rpc.RMPrivateMessageModeNormal,
 		Message: msg,
+		Nonce:   newMsgNonce(),
 	}, "pm", priorityPM)
 }
 
@@ -506,6 +582,9 @@ func (ru *RemoteUser) handleReceivedEncrypted(recvBlob lowlevel.RVBlob) error {
 	ru.rLock.Lock()
 	cleartext, decodeErr := ru.r.Decrypt(recvBlob.Decoded)
 
+	// Track the order of decrypted RMs, so that their handlers are
+	// dispatched in the same order.
+	var prevDispatched, dispatched chan struct{}
 	if decodeErr == nil {
 		err := ru.saveRatchet(nil, nil, "")
 		if err != nil {
@@ -514,6 +593,8 @@ func (ru *RemoteUser) handleReceivedEncrypted(recvBlob lowlevel.RVBlob) error {
 				"This might cause a busted ratchet")
 			return err
 		}
+		prevDispatched, dispatched = ru.lastRMDispatched, make(chan struct{})
+		ru.lastRMDispatched = dispatched
 	}
 	ru.rLock.Unlock()
 
@@ -529,8 +610,26 @@ func (ru *RemoteUser) handleReceivedEncrypted(recvBlob lowlevel.RVBlob) error {
 		// ratchet state was _not_ updated and _not_ saved do the DB.
 		ru.log.Warnf("Error decrypting ratchet msg at RV %s: %v",
 			recvBlob.ID, decodeErr)
+		ru.mtx.Lock()
+		ru.decryptFails += 1
+		ru.lastDecryptFail = time.Now()
+		ru.mtx.Unlock()
 		return nil
 	}
+	ru.mtx.Lock()
+	ru.decryptFails = 0
+	ru.mtx.Unlock()
+
+	// Release the next RM if this one does not end up being handled.
+	handling := false
+	defer func() {
+		if !handling {
+			go func() {
+				<-prevDispatched
+				close(dispatched)
+			}()
+		}
+	}()
 
 	// Successfully decrypted using the ratchet. Let Run() know.
 	select {
@@ -546,6 +645,15 @@ func (ru *RemoteUser) handleReceivedEncrypted(recvBlob lowlevel.RVBlob) error {
 		return fmt.Errorf("could not decode remote command: %v", err)
 	}
 
+	// Track the capabilities of the remote user.
+	ru.mtx.Lock()
+	if ru.remoteCaps != h.Capabilities {
+		ru.log.Debugf("Remote user capabilities changed from %d to %d",
+			ru.remoteCaps, h.Capabilities)
+		ru.remoteCaps = h.Capabilities
+	}
+	ru.mtx.Unlock()
+
 	if ru.logPayloads.Level() <= slog.LevelTrace {
 		ru.logPayloads.Tracef("Received RM %q via RV %s: %s", h.Command,
 			recvBlob.ID, spew.Sdump(c))
@@ -553,11 +661,22 @@ func (ru *RemoteUser) handleReceivedEncrypted(recvBlob lowlevel.RVBlob) error {
 		ru.logPayloads.Debugf("Received RM %q via RV %s", h.Command, recvBlob.ID)
 	}
 
-	// Handle every received msg in a different goroutine.
+	// Handle every received msg in a different goroutine. Handlers start
+	// in the order the msgs were decrypted and msgs that depend on the
+	// previous ones are handled one at a time.
 	if ru.rmHandler != nil {
+		handling = true
+		inOrder := rmHandledInOrder(h.Command)
 		ru.rmHandlerWG.Add(1)
 		go func() {
+			<-prevDispatched
+			if !inOrder {
+				close(dispatched)
+			}
 			ru.rmHandler(ru, h, c, recvBlob.ServerTS)
+			if inOrder {
+				close(dispatched)
+			}
 			ru.rmHandlerWG.Done()
 		}()
 	}
@@ -565,6 +684,19 @@ func (ru *RemoteUser) handleReceivedEncrypted(recvBlob lowlevel.RVBlob) error {
 	return nil
 }
 
+// rmHandledInOrder returns true if RMs of the given command must be fully
+// handled before the next RM received from the same user. This is the case of
+// posts, where the status updates of a post are sent right after the post and
+// would be dropped if handled before it.
+func rmHandledInOrder(cmd string) bool {
+	switch cmd {
+	case rpc.RMCPostShare, rpc.RMCPostStatus:
+		return true
+	default:
+		return false
+	}
+}
+
 // maybeUpdateRVs updates the RVs we listen on related to this user in the
 // server.
 func (ru *RemoteUser) maybeUpdateRVs(lastRecvRV, lastDrainRV ratchet.RVPoint, handler lowlevel.RVHandler) (ratchet.RVPoint, ratchet.RVPoint, error) {
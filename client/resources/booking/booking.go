@@ -0,0 +1,716 @@
+// Package booking is a resource provider that lets remote users book
+// appointments in time slots defined by the owner, paying for them with LN
+// invoices.
+package booking
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrlnd/lnrpc"
+	"github.com/decred/slog"
+	"github.com/pelletier/go-toml"
+)
+
+//go:embed template
+var defaultTemplates embed.FS
+
+const (
+	indexTmplFile   = "index.tmpl"
+	slotTmplFile    = "slot.tmpl"
+	bookingTmplFile = "booking.tmpl"
+	mineTmplFile    = "mine.tmpl"
+
+	// slotsFile is the file, in the root dir, with the slots open for
+	// booking.
+	slotsFile = "slots.toml"
+
+	bookingsDir = "bookings"
+
+	// invoiceValidity is how long the invoices of bookings are valid for.
+	// Slots of bookings that are not paid in this time are released.
+	invoiceValidity = time.Hour
+
+	// maxNoteLen is the max length of the note of a booking.
+	maxNoteLen = 500
+
+	// slotIDFormat is the format of the start time in slot ids.
+	slotIDFormat = "20060102T1504"
+)
+
+var bookingFnamePattern = jsonfile.MakeDecimalFilePattern("booking-", ".json", false)
+
+// Slot is a time slot open for booking.
+type Slot struct {
+	Title string    `toml:"title"`
+	Start time.Time `toml:"start"`
+
+	// Duration is the duration of the slot (e.g. "30m" or "1h").
+	Duration string `toml:"duration"`
+
+	// Price is the price of the slot, in DCR. Slots without a price are
+	// confirmed as soon as they are booked.
+	Price float64 `toml:"price"`
+
+	// ID and End are filled when the slots are loaded.
+	ID  string    `toml:"-"`
+	End time.Time `toml:"-"`
+
+	// Available is filled when the slot is rendered.
+	Available bool `toml:"-"`
+}
+
+type slotsFileData struct {
+	Slots []*Slot `toml:"slots"`
+}
+
+// Status is the status of a booking.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusConfirmed Status = "confirmed"
+	StatusCanceled  Status = "canceled"
+)
+
+// Booking is the booking of a slot by a remote user.
+type Booking struct {
+	ID          uint64            `json:"id"`
+	User        clientintf.UserID `json:"user"`
+	SlotID      string            `json:"slot_id"`
+	Title       string            `json:"title"`
+	Start       time.Time         `json:"start"`
+	End         time.Time         `json:"end"`
+	Note        string            `json:"note,omitempty"`
+	Amount      dcrutil.Amount    `json:"amount"`
+	Invoice     string            `json:"invoice,omitempty"`
+	Status      Status            `json:"status"`
+	CreatedTS   time.Time         `json:"created_ts"`
+	ConfirmedTS *time.Time        `json:"confirmed_ts,omitempty"`
+	RemindedTS  *time.Time        `json:"reminded_ts,omitempty"`
+}
+
+// IsExpired returns true if the invoice of the booking expired before it was
+// paid.
+func (b *Booking) IsExpired() bool {
+	return b.Status == StatusPending && time.Since(b.CreatedTS) > invoiceValidity
+}
+
+// holdsSlot returns true if the booking prevents other bookings in its time.
+func (b *Booking) holdsSlot() bool {
+	return b.Status == StatusConfirmed || (b.Status == StatusPending && !b.IsExpired())
+}
+
+// Config is the configuration of a booking provider.
+type Config struct {
+	// Root is the dir with the slots file and the bookings.
+	Root string
+
+	Log         slog.Logger
+	Client      *client.Client
+	LNPayClient *client.DcrlnPaymentClient
+
+	// Prefix is the path where the provider is bound in the resources
+	// router. Defaults to "booking".
+	Prefix string
+
+	// ReminderLead is how long before the start of a booking the
+	// reminders are sent. Defaults to 1 hour.
+	ReminderLead time.Duration
+
+	// RenderEngine, if set, is used to render the pages instead of the
+	// default templates. Templates that are not defined in the engine are
+	// rendered with the default templates.
+	RenderEngine resources.RenderEngine
+
+	// BookingConfirmed is called when a booking is confirmed.
+	BookingConfirmed func(b *Booking)
+
+	// Reminder is called when the reminder of a booking is sent to the
+	// remote user, to remind the owner as well.
+	Reminder func(b *Booking)
+}
+
+// Provider is the resource provider of the booking pages.
+type Provider struct {
+	cfg    Config
+	log    slog.Logger
+	render resources.RenderEngine
+
+	mtx      sync.Mutex
+	slots    map[string]*Slot
+	bookings map[uint64]*Booking
+}
+
+// loadSlots loads the slots file.
+func loadSlots(fname string) (map[string]*Slot, error) {
+	var data slotsFileData
+	b, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*Slot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := toml.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode slots file: %v", err)
+	}
+	slots := make(map[string]*Slot, len(data.Slots))
+	for _, s := range data.Slots {
+		d, err := time.ParseDuration(s.Duration)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("slot at %s has an invalid duration %q",
+				s.Start, s.Duration)
+		}
+		if s.Price < 0 {
+			return nil, fmt.Errorf("slot at %s has a negative price", s.Start)
+		}
+		s.End = s.Start.Add(d)
+		s.ID = fmt.Sprintf("%s-%d", s.Start.UTC().Format(slotIDFormat),
+			int64(d/time.Minute))
+		if slots[s.ID] != nil {
+			return nil, fmt.Errorf("duplicate slot at %s", s.Start)
+		}
+		slots[s.ID] = s
+	}
+	return slots, nil
+}
+
+// New creates a new booking provider.
+func New(cfg Config) (*Provider, error) {
+	log := slog.Disabled
+	if cfg.Log != nil {
+		log = cfg.Log
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "booking"
+	}
+	if cfg.ReminderLead <= 0 {
+		cfg.ReminderLead = time.Hour
+	}
+	if err := os.MkdirAll(filepath.Join(cfg.Root, bookingsDir), 0o700); err != nil {
+		return nil, err
+	}
+
+	var render resources.RenderEngine
+	render, err := resources.ParseTextTemplatesFS(defaultTemplates, nil, "template/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RenderEngine != nil {
+		render = resources.FallbackEngine{cfg.RenderEngine, render}
+	}
+
+	slots, err := loadSlots(filepath.Join(cfg.Root, slotsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		cfg:      cfg,
+		log:      log,
+		render:   render,
+		slots:    slots,
+		bookings: make(map[uint64]*Booking),
+	}
+	if err := p.loadBookings(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) bookingFname(id uint64) string {
+	return filepath.Join(p.cfg.Root, bookingsDir, bookingFnamePattern.FilenameFor(id))
+}
+
+// loadBookings loads all bookings.
+func (p *Provider) loadBookings() error {
+	dir := filepath.Join(p.cfg.Root, bookingsDir)
+	files, err := bookingFnamePattern.MatchFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		b := new(Booking)
+		if err := jsonfile.Read(filepath.Join(dir, f.Filename), b); err != nil {
+			p.log.Warnf("Unable to read booking %s: %v", f.Filename, err)
+			continue
+		}
+		p.bookings[b.ID] = b
+	}
+	return nil
+}
+
+// conflict returns the booking (other than except) that prevents booking the
+// [start, end) interval, if there is one.
+//
+// This MUST be called with the mutex held.
+func (p *Provider) conflict(start, end time.Time, except *Booking) *Booking {
+	for _, b := range p.bookings {
+		if b == except {
+			continue
+		}
+		if b.holdsSlot() && b.Start.Before(end) && start.Before(b.End) {
+			return b
+		}
+	}
+	return nil
+}
+
+// isAvailable returns true if the slot may be booked.
+//
+// This MUST be called with the mutex held.
+func (p *Provider) isAvailable(s *Slot) bool {
+	return s.Start.After(time.Now()) && p.conflict(s.Start, s.End, nil) == nil
+}
+
+// Slots returns the future slots, sorted by start time.
+func (p *Provider) Slots() []Slot {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	now := time.Now()
+	res := make([]Slot, 0, len(p.slots))
+	for _, s := range p.slots {
+		if !s.Start.After(now) {
+			continue
+		}
+		slot := *s
+		slot.Available = p.isAvailable(s)
+		res = append(res, slot)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Start.Equal(res[j].Start) {
+			return res[i].Start.Before(res[j].Start)
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res
+}
+
+// Bookings returns the confirmed bookings that have not ended, sorted by start
+// time.
+func (p *Provider) Bookings() []Booking {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	now := time.Now()
+	var res []Booking
+	for _, b := range p.bookings {
+		if b.Status == StatusConfirmed && b.End.After(now) {
+			res = append(res, *b)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Start.Before(res[j].Start) })
+	return res
+}
+
+// saveBooking saves the updated booking and replaces the current one with it.
+//
+// This MUST be called with the mutex held.
+func (p *Provider) saveBooking(cur, upd *Booking) error {
+	if err := jsonfile.Write(p.bookingFname(upd.ID), upd, p.log); err != nil {
+		return err
+	}
+	*cur = *upd
+	return nil
+}
+
+// book books the slot for the user. Bookings of paid slots remain pending
+// until their invoice is paid.
+func (p *Provider) book(ctx context.Context, uid clientintf.UserID,
+	slotID, note string) (*Booking, string, error) {
+
+	p.mtx.Lock()
+	s := p.slots[slotID]
+	if s == nil {
+		p.mtx.Unlock()
+		return nil, "Slot not found", nil
+	}
+	if !p.isAvailable(s) {
+		p.mtx.Unlock()
+		return nil, "The slot is no longer available", nil
+	}
+	price, err := dcrutil.NewAmount(s.Price)
+	p.mtx.Unlock()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var invoice string
+	if price > 0 {
+		if p.cfg.LNPayClient == nil {
+			return nil, "", fmt.Errorf("LN not setup")
+		}
+		invoice, err = p.cfg.LNPayClient.GetInvoice(ctx, int64(price)*1000, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to generate invoice: %v", err)
+		}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	// Check again, as the slot may have been booked while the invoice was
+	// generated.
+	if !p.isAvailable(s) {
+		return nil, "The slot is no longer available", nil
+	}
+
+	fname, id, err := bookingFnamePattern.NextFilename(filepath.Join(p.cfg.Root, bookingsDir))
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	b := &Booking{
+		ID:        id,
+		User:      uid,
+		SlotID:    s.ID,
+		Title:     s.Title,
+		Start:     s.Start,
+		End:       s.End,
+		Note:      note,
+		Amount:    price,
+		Invoice:   invoice,
+		Status:    StatusPending,
+		CreatedTS: now,
+	}
+	if price == 0 {
+		b.Status = StatusConfirmed
+		b.ConfirmedTS = &now
+	}
+	if err := jsonfile.Write(fname, b, p.log); err != nil {
+		return nil, "", err
+	}
+	p.bookings[id] = b
+	p.log.Infof("User %s booked slot %s (booking %d, %s)", uid.ShortLogID(),
+		s.ID, id, b.Status)
+	if b.Status == StatusConfirmed {
+		go p.notifyConfirmed(*b)
+	}
+	return b, "", nil
+}
+
+// notifyConfirmed notifies both parties of a confirmed booking.
+func (p *Provider) notifyConfirmed(b Booking) {
+	if p.cfg.Client != nil {
+		msg := fmt.Sprintf("Your booking #%d (%s) on %s is confirmed.",
+			b.ID, b.Title, b.Start.Format("2006-01-02 15:04 MST"))
+		if err := p.cfg.Client.PM(b.User, msg); err != nil {
+			p.log.Warnf("Unable to send confirmation of booking %d to "+
+				"user %s: %v", b.ID, b.User.ShortLogID(), err)
+		}
+	}
+	if p.cfg.BookingConfirmed != nil {
+		p.cfg.BookingConfirmed(&b)
+	}
+}
+
+// bookingPaid confirms the booking with the invoice.
+func (p *Provider) bookingPaid(invoice string) {
+	p.mtx.Lock()
+	var b *Booking
+	for _, v := range p.bookings {
+		if v.Invoice == invoice && v.Status == StatusPending {
+			b = v
+			break
+		}
+	}
+	if b == nil {
+		p.mtx.Unlock()
+		return
+	}
+
+	// A booking paid after its invoice expired is only confirmed if the
+	// slot was not booked by someone else in the meantime.
+	upd := *b
+	if b.IsExpired() {
+		if p.conflict(b.Start, b.End, b) != nil {
+			upd.Status = StatusCanceled
+			err := p.saveBooking(b, &upd)
+			p.mtx.Unlock()
+			if err != nil {
+				p.log.Errorf("Unable to save booking %d: %v", b.ID, err)
+			}
+			p.log.Warnf("Booking %d was paid after its slot was booked "+
+				"by another user and needs to be refunded", upd.ID)
+			if p.cfg.Client != nil {
+				msg := fmt.Sprintf("Your payment for booking #%d was "+
+					"received after the slot was booked by "+
+					"someone else. Please contact us for a refund.",
+					upd.ID)
+				if err := p.cfg.Client.PM(upd.User, msg); err != nil {
+					p.log.Warnf("Unable to notify user %s: %v",
+						upd.User.ShortLogID(), err)
+				}
+			}
+			return
+		}
+	}
+
+	now := time.Now()
+	upd.Status = StatusConfirmed
+	upd.ConfirmedTS = &now
+	err := p.saveBooking(b, &upd)
+	p.mtx.Unlock()
+	if err != nil {
+		p.log.Errorf("Unable to save booking %d: %v", upd.ID, err)
+		return
+	}
+	p.log.Infof("Booking %d of user %s confirmed", upd.ID, upd.User.ShortLogID())
+	p.notifyConfirmed(upd)
+}
+
+// sendReminders sends the reminders of bookings that start soon.
+func (p *Provider) sendReminders() {
+	now := time.Now()
+	var due []Booking
+	p.mtx.Lock()
+	for _, b := range p.bookings {
+		if b.Status != StatusConfirmed || b.RemindedTS != nil {
+			continue
+		}
+		if !b.Start.After(now) || b.Start.Sub(now) > p.cfg.ReminderLead {
+			continue
+		}
+		upd := *b
+		upd.RemindedTS = &now
+		if err := p.saveBooking(b, &upd); err != nil {
+			p.log.Errorf("Unable to save booking %d: %v", b.ID, err)
+			continue
+		}
+		due = append(due, upd)
+	}
+	p.mtx.Unlock()
+
+	for _, b := range due {
+		p.log.Infof("Sending reminder of booking %d", b.ID)
+		if p.cfg.Client != nil {
+			msg := fmt.Sprintf("Reminder: your booking #%d (%s) starts "+
+				"on %s.", b.ID, b.Title,
+				b.Start.Format("2006-01-02 15:04 MST"))
+			if err := p.cfg.Client.PM(b.User, msg); err != nil {
+				p.log.Warnf("Unable to send reminder of booking %d "+
+					"to user %s: %v", b.ID, b.User.ShortLogID(), err)
+			}
+		}
+		if p.cfg.Reminder != nil {
+			p.cfg.Reminder(&b)
+		}
+	}
+}
+
+// runReminders sends the reminders of bookings until the context is done.
+func (p *Provider) runReminders(ctx context.Context) error {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		p.sendReminders()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// runInvoiceWatcher confirms the bookings as their invoices are paid.
+func (p *Provider) runInvoiceWatcher(ctx context.Context) error {
+	if p.cfg.LNPayClient == nil {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	stream, err := p.cfg.LNPayClient.LNRPC().SubscribeInvoices(ctx, &lnrpc.InvoiceSubscription{})
+	if err != nil {
+		return err
+	}
+
+	// Confirm the bookings paid while the provider was not running.
+	p.mtx.Lock()
+	var invoices []string
+	for _, b := range p.bookings {
+		if b.Status == StatusPending {
+			invoices = append(invoices, b.Invoice)
+		}
+	}
+	p.mtx.Unlock()
+	for _, inv := range invoices {
+		if err := p.cfg.LNPayClient.IsInvoicePaid(ctx, 0, inv); err == nil {
+			p.bookingPaid(inv)
+		}
+	}
+
+	for {
+		inv, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if inv.State == lnrpc.Invoice_SETTLED {
+			p.bookingPaid(inv.PaymentRequest)
+		}
+	}
+}
+
+// Run watches the invoices of the bookings and sends the reminders until the
+// context is done.
+func (p *Provider) Run(ctx context.Context) error {
+	errChan := make(chan error, 2)
+	go func() { errChan <- p.runInvoiceWatcher(ctx) }()
+	go func() { errChan <- p.runReminders(ctx) }()
+	err := <-errChan
+	if !errors.Is(err, context.Canceled) {
+		p.log.Errorf("Booking provider failed: %v", err)
+	}
+	<-ctx.Done()
+	return err
+}
+
+type indexContext struct {
+	Prefix string
+	Slots  []Slot
+}
+
+type slotContext struct {
+	Slot
+	Prefix string
+}
+
+type bookingContext struct {
+	Booking
+	Prefix string
+}
+
+type mineContext struct {
+	Prefix   string
+	Bookings []Booking
+}
+
+func (p *Provider) renderPage(tmplFile string, tmplCtx interface{}) (*rpc.RMFetchResourceReply, error) {
+	w := &bytes.Buffer{}
+	if err := p.render.Render(w, tmplFile, tmplCtx); err != nil {
+		return nil, fmt.Errorf("unable to execute booking template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+func badRequest(msg string) *rpc.RMFetchResourceReply {
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusBadRequest,
+		Data:   []byte(msg),
+	}
+}
+
+func notFound() *rpc.RMFetchResourceReply {
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusNotFound,
+	}
+}
+
+func (p *Provider) handleSlot(slotID string) (*rpc.RMFetchResourceReply, error) {
+	for _, s := range p.Slots() {
+		if s.ID == slotID {
+			return p.renderPage(slotTmplFile, &slotContext{Slot: s, Prefix: p.cfg.Prefix})
+		}
+	}
+	return notFound(), nil
+}
+
+func (p *Provider) handleBook(ctx context.Context, uid clientintf.UserID,
+	data []byte) (*rpc.RMFetchResourceReply, error) {
+
+	fd, err := resources.ParseFormData(data)
+	if err != nil {
+		return badRequest("request data not valid json"), nil
+	}
+	note := strescape.Content(strings.TrimSpace(fd.String("note")))
+	if len(note) > maxNoteLen {
+		return badRequest(fmt.Sprintf("The note is limited to %d "+
+			"characters", maxNoteLen)), nil
+	}
+
+	b, msg, err := p.book(ctx, uid, fd.String("slot"), note)
+	if err != nil {
+		return nil, err
+	}
+	if msg != "" {
+		return badRequest(msg), nil
+	}
+	return p.renderPage(bookingTmplFile, &bookingContext{Booking: *b, Prefix: p.cfg.Prefix})
+}
+
+func (p *Provider) handleBooking(uid clientintf.UserID, idStr string) (*rpc.RMFetchResourceReply, error) {
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return badRequest("invalid booking id"), nil
+	}
+	p.mtx.Lock()
+	b := p.bookings[id]
+	if b == nil || b.User != uid {
+		p.mtx.Unlock()
+		return notFound(), nil
+	}
+	tmplCtx := &bookingContext{Booking: *b, Prefix: p.cfg.Prefix}
+	p.mtx.Unlock()
+	return p.renderPage(bookingTmplFile, tmplCtx)
+}
+
+func (p *Provider) handleMine(uid clientintf.UserID) (*rpc.RMFetchResourceReply, error) {
+	tmplCtx := &mineContext{Prefix: p.cfg.Prefix}
+	p.mtx.Lock()
+	for _, b := range p.bookings {
+		if b.User == uid && b.Status == StatusConfirmed {
+			tmplCtx.Bookings = append(tmplCtx.Bookings, *b)
+		}
+	}
+	p.mtx.Unlock()
+	sort.Slice(tmplCtx.Bookings, func(i, j int) bool {
+		return tmplCtx.Bookings[i].Start.After(tmplCtx.Bookings[j].Start)
+	})
+	return p.renderPage(mineTmplFile, tmplCtx)
+}
+
+// Fulfill is part of the resources.Provider interface.
+func (p *Provider) Fulfill(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	path := request.Path
+	if len(path) > 0 && path[0] == p.cfg.Prefix {
+		path = path[1:]
+	}
+	switch {
+	case len(path) == 0:
+		return p.renderPage(indexTmplFile, &indexContext{
+			Prefix: p.cfg.Prefix,
+			Slots:  p.Slots(),
+		})
+	case path[0] == "slot" && len(path) == 2:
+		return p.handleSlot(path[1])
+	case path[0] == "book" && len(path) == 1:
+		return p.handleBook(ctx, uid, request.Data)
+	case path[0] == "booking" && len(path) == 2:
+		return p.handleBooking(uid, path[1])
+	case path[0] == "mine" && len(path) == 1:
+		return p.handleMine(uid)
+	default:
+		return notFound(), nil
+	}
+}
@@ -0,0 +1,333 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/internal/testutils"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// testSlots returns the test slots file. The first two slots overlap and the
+// third starts after both of them end.
+func testSlots(start time.Time) string {
+	f := func(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+	return fmt.Sprintf(`
+[[slots]]
+title = "Morning"
+start = %s
+duration = "1h"
+
+[[slots]]
+title = "Consult"
+start = %s
+duration = "1h"
+price = 0.5
+
+[[slots]]
+start = %s
+duration = "30m"
+`, f(start), f(start.Add(30*time.Minute)), f(start.Add(3*time.Hour)))
+}
+
+// newTestProvider returns a provider with the test slots, starting at start,
+// in a new root dir.
+func newTestProvider(t testing.TB, start time.Time) *Provider {
+	t.Helper()
+	root := testutils.TempTestDir(t, "booking-")
+	err := os.WriteFile(filepath.Join(root, slotsFile), []byte(testSlots(start)), 0o600)
+	assert.NilErr(t, err)
+	p, err := New(Config{Root: root})
+	assert.NilErr(t, err)
+	return p
+}
+
+// testSlotIDs returns the ids of the slots of the provider, sorted by start
+// time.
+func testSlotIDs(p *Provider) []string {
+	var ids []string
+	for _, s := range p.Slots() {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+// addBooking adds the booking to the provider as if it had been made by book.
+func addBooking(t testing.TB, p *Provider, b *Booking) {
+	t.Helper()
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	s := p.slots[b.SlotID]
+	b.ID = uint64(len(p.bookings) + 1)
+	b.Title, b.Start, b.End = s.Title, s.Start, s.End
+	assert.NilErr(t, jsonfile.Write(p.bookingFname(b.ID), b, p.log))
+	p.bookings[b.ID] = b
+}
+
+// fetch fetches the path from the provider.
+func fetch(t testing.TB, p *Provider, uid clientintf.UserID, path string,
+	data string) *rpc.RMFetchResourceReply {
+	t.Helper()
+	req := &rpc.RMFetchResource{
+		Path: strings.Split(path, "/"),
+		Data: []byte(data),
+	}
+	res, err := p.Fulfill(context.Background(), uid, req)
+	assert.NilErr(t, err)
+	return res
+}
+
+// TestLoadSlots tests the validation of the slots file.
+func TestLoadSlots(t *testing.T) {
+	start := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	fname := filepath.Join(testutils.TempTestDir(t, "booking-"), slotsFile)
+
+	// A missing file has no slots.
+	slots, err := loadSlots(fname)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(slots), 0)
+
+	assert.NilErr(t, os.WriteFile(fname, []byte(testSlots(start)), 0o600))
+	slots, err = loadSlots(fname)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(slots), 3)
+	s := slots["20300102T1000-60"]
+	if s == nil {
+		t.Fatalf("slot not found in %v", slots)
+	}
+	assert.DeepEqual(t, s.Title, "Morning")
+	assert.DeepEqual(t, s.End, start.Add(time.Hour))
+	if slots["20300102T1300-30"] == nil {
+		t.Fatalf("slot not found in %v", slots)
+	}
+
+	tests := []struct {
+		name  string
+		slots string
+	}{{
+		name:  "invalid duration",
+		slots: "[[slots]]\nstart = 2030-01-02T10:00:00Z\nduration = \"soon\"",
+	}, {
+		name:  "zero duration",
+		slots: "[[slots]]\nstart = 2030-01-02T10:00:00Z\nduration = \"0s\"",
+	}, {
+		name:  "negative price",
+		slots: "[[slots]]\nstart = 2030-01-02T10:00:00Z\nduration = \"1h\"\nprice = -1.0",
+	}, {
+		name: "duplicate",
+		slots: "[[slots]]\nstart = 2030-01-02T10:00:00Z\nduration = \"1h\"\n" +
+			"[[slots]]\nstart = 2030-01-02T10:00:00Z\nduration = \"1h\"",
+	}, {
+		name:  "invalid toml",
+		slots: "[[slots",
+	}}
+	for _, tc := range tests {
+		assert.NilErr(t, os.WriteFile(fname, []byte(tc.slots), 0o600))
+		if _, err := loadSlots(fname); err == nil {
+			t.Fatalf("%s: expected error loading slots", tc.name)
+		}
+	}
+}
+
+// TestBookFreeSlot tests booking free slots and that booked slots, and the
+// slots that overlap them, can no longer be booked.
+func TestBookFreeSlot(t *testing.T) {
+	p := newTestProvider(t, time.Now().Add(24*time.Hour))
+	confirmed := make(chan *Booking, 5)
+	p.cfg.BookingConfirmed = func(b *Booking) { confirmed <- b }
+	ids := testSlotIDs(p)
+	alice, bob := clientintf.UserID{1: 1}, clientintf.UserID{1: 2}
+
+	res := fetch(t, p, alice, "booking/book",
+		fmt.Sprintf(`{"slot":%q,"note":"  first visit "}`, ids[0]))
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	if !strings.Contains(string(res.Data), "**Confirmed.**") {
+		t.Fatalf("booking not confirmed: %s", res.Data)
+	}
+	b := assert.ChanWritten(t, confirmed)
+	assert.DeepEqual(t, b.ID, uint64(1))
+	assert.DeepEqual(t, b.User, alice)
+	assert.DeepEqual(t, b.SlotID, ids[0])
+	assert.DeepEqual(t, b.Note, "first visit")
+	assert.DeepEqual(t, b.Status, StatusConfirmed)
+
+	// The booked slot and the one that overlaps it are no longer
+	// available.
+	for _, s := range p.Slots() {
+		assert.DeepEqual(t, s.Available, s.ID == ids[2])
+	}
+	for _, id := range ids[:2] {
+		res = fetch(t, p, bob, "booking/book", fmt.Sprintf(`{"slot":%q}`, id))
+		assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+		assert.DeepEqual(t, string(res.Data), "The slot is no longer available")
+	}
+	assert.ChanNotWritten(t, confirmed, 50*time.Millisecond)
+
+	// Invalid requests are rejected.
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "unknown slot", data: `{"slot":"none"}`, want: "Slot not found"},
+		{name: "invalid data", data: `[1]`, want: "request data not valid json"},
+		{
+			name: "long note",
+			data: fmt.Sprintf(`{"slot":%q,"note":%q}`, ids[2], strings.Repeat("x", maxNoteLen+1)),
+			want: fmt.Sprintf("The note is limited to %d characters", maxNoteLen),
+		},
+	}
+	for _, tc := range tests {
+		res = fetch(t, p, bob, "booking/book", tc.data)
+		assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+		if string(res.Data) != tc.want {
+			t.Fatalf("%s: unexpected reply %q", tc.name, res.Data)
+		}
+	}
+
+	// Bob books the remaining slot.
+	res = fetch(t, p, bob, "booking/book", fmt.Sprintf(`{"slot":%q}`, ids[2]))
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	assert.DeepEqual(t, assert.ChanWritten(t, confirmed).User, bob)
+
+	// Bookings are only visible to the user that made them.
+	res = fetch(t, p, alice, "booking/booking/1", "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	if !strings.Contains(string(res.Data), "Note: first visit") {
+		t.Fatalf("unexpected booking page: %s", res.Data)
+	}
+	res = fetch(t, p, bob, "booking/booking/1", "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusNotFound)
+	res = fetch(t, p, alice, "booking/booking/x", "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+
+	res = fetch(t, p, alice, "booking/mine", "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	if !strings.Contains(string(res.Data), "(/booking/booking/1)") ||
+		strings.Contains(string(res.Data), "(/booking/booking/2)") {
+		t.Fatalf("unexpected bookings of alice: %s", res.Data)
+	}
+
+	// The bookings persist after the provider is reloaded.
+	p2, err := New(Config{Root: p.cfg.Root})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(p2.Bookings()), 2)
+	for _, s := range p2.Slots() {
+		assert.DeepEqual(t, s.Available, false)
+	}
+}
+
+// TestBookingPaid tests that pending bookings are confirmed when paid, unless
+// their invoice expired and the slot was booked by someone else.
+func TestBookingPaid(t *testing.T) {
+	p := newTestProvider(t, time.Now().Add(24*time.Hour))
+	confirmed := make(chan *Booking, 5)
+	p.cfg.BookingConfirmed = func(b *Booking) { confirmed <- b }
+	ids := testSlotIDs(p)
+	alice, bob := clientintf.UserID{1: 1}, clientintf.UserID{1: 2}
+
+	// A pending booking holds its slot until its invoice expires.
+	pending := &Booking{
+		User:      alice,
+		SlotID:    ids[1],
+		Invoice:   "invoice-1",
+		Status:    StatusPending,
+		CreatedTS: time.Now(),
+	}
+	addBooking(t, p, pending)
+	expired := &Booking{
+		User:      bob,
+		SlotID:    ids[0],
+		Invoice:   "invoice-2",
+		Status:    StatusPending,
+		CreatedTS: time.Now().Add(-2 * invoiceValidity),
+	}
+	addBooking(t, p, expired)
+	expiredFree := &Booking{
+		User:      bob,
+		SlotID:    ids[2],
+		Invoice:   "invoice-3",
+		Status:    StatusPending,
+		CreatedTS: time.Now().Add(-2 * invoiceValidity),
+	}
+	addBooking(t, p, expiredFree)
+	for _, s := range p.Slots() {
+		assert.DeepEqual(t, s.Available, s.ID == ids[2])
+	}
+
+	p.bookingPaid("invoice-1")
+	b := assert.ChanWritten(t, confirmed)
+	assert.DeepEqual(t, b.ID, pending.ID)
+	assert.DeepEqual(t, b.Status, StatusConfirmed)
+	if b.ConfirmedTS == nil {
+		t.Fatal("confirmed booking without confirmed time")
+	}
+
+	// Bookings are only confirmed once and unknown invoices are ignored.
+	p.bookingPaid("invoice-1")
+	p.bookingPaid("invoice-4")
+	assert.ChanNotWritten(t, confirmed, 50*time.Millisecond)
+
+	// The expired booking overlaps the confirmed one, so it is canceled.
+	p.bookingPaid("invoice-2")
+	assert.ChanNotWritten(t, confirmed, 50*time.Millisecond)
+	p.mtx.Lock()
+	assert.DeepEqual(t, expired.Status, StatusCanceled)
+	p.mtx.Unlock()
+
+	// The slot of the other expired booking is still free, so it is
+	// confirmed.
+	p.bookingPaid("invoice-3")
+	b = assert.ChanWritten(t, confirmed)
+	assert.DeepEqual(t, b.ID, expiredFree.ID)
+	assert.DeepEqual(t, b.Status, StatusConfirmed)
+
+	// The statuses persist after the provider is reloaded.
+	p2, err := New(Config{Root: p.cfg.Root})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, p2.bookings[pending.ID].Status, StatusConfirmed)
+	assert.DeepEqual(t, p2.bookings[expired.ID].Status, StatusCanceled)
+	assert.DeepEqual(t, p2.bookings[expiredFree.ID].Status, StatusConfirmed)
+}
+
+// TestSendReminders tests that reminders are sent once for confirmed bookings
+// that start within the reminder lead.
+func TestSendReminders(t *testing.T) {
+	p := newTestProvider(t, time.Now().Add(30*time.Minute))
+	reminded := make(chan *Booking, 5)
+	p.cfg.Reminder = func(b *Booking) { reminded <- b }
+	ids := testSlotIDs(p)
+	uid := clientintf.UserID{1: 1}
+
+	// Only the first booking is confirmed and starts within the reminder
+	// lead.
+	soon := &Booking{User: uid, SlotID: ids[0], Status: StatusConfirmed}
+	addBooking(t, p, soon)
+	addBooking(t, p, &Booking{User: uid, SlotID: ids[1], Status: StatusPending,
+		CreatedTS: time.Now()})
+	addBooking(t, p, &Booking{User: uid, SlotID: ids[2], Status: StatusConfirmed})
+
+	p.sendReminders()
+	b := assert.ChanWritten(t, reminded)
+	assert.DeepEqual(t, b.ID, soon.ID)
+	if b.RemindedTS == nil {
+		t.Fatal("reminded booking without reminded time")
+	}
+	assert.ChanNotWritten(t, reminded, 50*time.Millisecond)
+
+	// Reminders are not sent again, even after the provider is reloaded.
+	p.sendReminders()
+	assert.ChanNotWritten(t, reminded, 50*time.Millisecond)
+	p2, err := New(Config{Root: p.cfg.Root, Reminder: p.cfg.Reminder})
+	assert.NilErr(t, err)
+	p2.sendReminders()
+	assert.ChanNotWritten(t, reminded, 50*time.Millisecond)
+}
@@ -0,0 +1,478 @@
+// Package donations is a resource provider that renders a donation page and
+// accepts donations paid with LN invoices.
+package donations
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrlnd/lnrpc"
+	"github.com/decred/slog"
+)
+
+//go:embed template
+var defaultTemplates embed.FS
+
+const (
+	indexTmplFile    = "index.tmpl"
+	donationTmplFile = "donation.tmpl"
+
+	// invoiceValidity is how long the invoices of donations are valid for
+	// (the default expiry of LN invoices).
+	invoiceValidity = time.Hour
+
+	// maxMessageLen is the max length of the public name and message of
+	// a donation.
+	maxMessageLen = 280
+
+	// maxPublicDonations is the max number of donations in the public
+	// thank-you list.
+	maxPublicDonations = 50
+)
+
+var donationFnamePattern = jsonfile.MakeDecimalFilePattern("donation-", ".json", false)
+
+// Status is the status of a donation.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSettled Status = "settled"
+)
+
+// Donation is a donation from a remote user.
+type Donation struct {
+	ID        uint64            `json:"id"`
+	User      clientintf.UserID `json:"user"`
+	Amount    dcrutil.Amount    `json:"amount"`
+	Invoice   string            `json:"invoice"`
+	Status    Status            `json:"status"`
+	CreatedTS time.Time         `json:"created_ts"`
+
+	// Name and Message are shown in the public thank-you list, if the
+	// donor chose to be listed.
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message,omitempty"`
+	Public  bool   `json:"public,omitempty"`
+
+	PaidAmount dcrutil.Amount `json:"paid_amount,omitempty"`
+	SettledTS  *time.Time     `json:"settled_ts,omitempty"`
+}
+
+// IsExpired returns true if the invoice of the donation expired before it was
+// paid.
+func (d *Donation) IsExpired() bool {
+	return d.Status == StatusPending && time.Since(d.CreatedTS) > invoiceValidity
+}
+
+// Config is the configuration of a donations provider.
+type Config struct {
+	// Root is the dir where the donations are stored.
+	Root string
+
+	Log         slog.Logger
+	Client      *client.Client
+	LNPayClient *client.DcrlnPaymentClient
+
+	// Prefix is the path where the provider is bound in the resources
+	// router. Defaults to "donate".
+	Prefix string
+
+	// Title and Description are shown at the top of the donation page.
+	Title       string
+	Description string
+
+	// Presets are the suggested donation amounts, in DCR. Donors may also
+	// choose a custom amount, which must be at least MinAmount.
+	Presets   []float64
+	MinAmount float64
+
+	// PublicList enables the public thank-you list of the donations whose
+	// donors chose to be listed.
+	PublicList bool
+
+	// RenderEngine, if set, is used to render the pages instead of the
+	// default templates. Templates that are not defined in the engine are
+	// rendered with the default templates.
+	RenderEngine resources.RenderEngine
+
+	// DonationReceived is called when a donation is settled.
+	DonationReceived func(d *Donation)
+}
+
+// Provider is the resource provider of the donation page.
+type Provider struct {
+	cfg    Config
+	log    slog.Logger
+	render resources.RenderEngine
+
+	mtx sync.Mutex
+
+	// pending maps the invoices of pending donations to their ids.
+	pending map[string]uint64
+}
+
+// New creates a new donations provider.
+func New(cfg Config) (*Provider, error) {
+	log := slog.Disabled
+	if cfg.Log != nil {
+		log = cfg.Log
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "donate"
+	}
+	if err := os.MkdirAll(cfg.Root, 0o700); err != nil {
+		return nil, err
+	}
+
+	var render resources.RenderEngine
+	render, err := resources.ParseTextTemplatesFS(defaultTemplates, nil, "template/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RenderEngine != nil {
+		render = resources.FallbackEngine{cfg.RenderEngine, render}
+	}
+
+	p := &Provider{
+		cfg:     cfg,
+		log:     log,
+		render:  render,
+		pending: make(map[string]uint64),
+	}
+	donations, err := p.loadDonations()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range donations {
+		if d.Status == StatusPending && !d.IsExpired() {
+			p.pending[d.Invoice] = d.ID
+		}
+	}
+	return p, nil
+}
+
+func (p *Provider) donationFname(id uint64) string {
+	return filepath.Join(p.cfg.Root, donationFnamePattern.FilenameFor(id))
+}
+
+// loadDonations loads all donations, most recent first.
+//
+// This MUST be called with the mutex held or before the provider is running.
+func (p *Provider) loadDonations() ([]*Donation, error) {
+	files, err := donationFnamePattern.MatchFiles(p.cfg.Root)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Donation, 0, len(files))
+	for _, f := range files {
+		d := new(Donation)
+		if err := jsonfile.Read(filepath.Join(p.cfg.Root, f.Filename), d); err != nil {
+			p.log.Warnf("Unable to read donation %s: %v", f.Filename, err)
+			continue
+		}
+		res = append(res, d)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID > res[j].ID })
+	return res, nil
+}
+
+// Donations returns all donations received, most recent first.
+func (p *Provider) Donations() ([]*Donation, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.loadDonations()
+}
+
+// minAmount returns the min amount of donations.
+func (p *Provider) minAmount() dcrutil.Amount {
+	min, _ := dcrutil.NewAmount(p.cfg.MinAmount)
+	if min < 1 {
+		min = 1
+	}
+	return min
+}
+
+// newDonation creates a donation and its invoice.
+func (p *Provider) newDonation(ctx context.Context, uid clientintf.UserID,
+	amount dcrutil.Amount, name, msg string) (*Donation, error) {
+
+	if p.cfg.LNPayClient == nil {
+		return nil, fmt.Errorf("LN not setup")
+	}
+	invoice, err := p.cfg.LNPayClient.GetInvoice(ctx, int64(amount)*1000, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate invoice: %v", err)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	fname, id, err := donationFnamePattern.NextFilename(p.cfg.Root)
+	if err != nil {
+		return nil, err
+	}
+	d := &Donation{
+		ID:        id,
+		User:      uid,
+		Amount:    amount,
+		Invoice:   invoice,
+		Status:    StatusPending,
+		CreatedTS: time.Now(),
+		Name:      name,
+		Message:   msg,
+		Public:    name != "",
+	}
+	if err := jsonfile.Write(fname, d, p.log); err != nil {
+		return nil, err
+	}
+	p.pending[invoice] = id
+	p.log.Infof("Created donation %d of %s from user %s", id, amount,
+		uid.ShortLogID())
+	return d, nil
+}
+
+// settle marks the donation with the invoice as settled.
+func (p *Provider) settle(invoice string, paid dcrutil.Amount) {
+	p.mtx.Lock()
+	id, ok := p.pending[invoice]
+	if !ok {
+		p.mtx.Unlock()
+		return
+	}
+	delete(p.pending, invoice)
+	fname := p.donationFname(id)
+	d := new(Donation)
+	if err := jsonfile.Read(fname, d); err != nil {
+		p.mtx.Unlock()
+		p.log.Errorf("Unable to read donation %d: %v", id, err)
+		return
+	}
+	now := time.Now()
+	d.Status = StatusSettled
+	d.PaidAmount = paid
+	d.SettledTS = &now
+	err := jsonfile.Write(fname, d, p.log)
+	p.mtx.Unlock()
+	if err != nil {
+		p.log.Errorf("Unable to save donation %d: %v", id, err)
+		return
+	}
+
+	p.log.Infof("Received donation %d of %s from user %s", id, paid,
+		d.User.ShortLogID())
+	if p.cfg.Client != nil {
+		msg := fmt.Sprintf("Thank you for your donation of %s!", paid)
+		if err := p.cfg.Client.PM(d.User, msg); err != nil {
+			p.log.Warnf("Unable to thank user %s for donation %d: %v",
+				d.User.ShortLogID(), id, err)
+		}
+	}
+	if p.cfg.DonationReceived != nil {
+		p.cfg.DonationReceived(d)
+	}
+}
+
+// Run watches the invoices of the donations until the context is done.
+func (p *Provider) Run(ctx context.Context) error {
+	if p.cfg.LNPayClient == nil {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	stream, err := p.cfg.LNPayClient.LNRPC().SubscribeInvoices(ctx, &lnrpc.InvoiceSubscription{})
+	if err != nil {
+		return err
+	}
+
+	// Settle donations paid while the provider was not running.
+	p.mtx.Lock()
+	invoices := make([]string, 0, len(p.pending))
+	for inv := range p.pending {
+		invoices = append(invoices, inv)
+	}
+	p.mtx.Unlock()
+	for _, inv := range invoices {
+		if err := p.cfg.LNPayClient.IsInvoicePaid(ctx, 0, inv); err == nil {
+			p.settle(inv, 0)
+		}
+	}
+
+	for {
+		inv, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if inv.State == lnrpc.Invoice_SETTLED {
+			p.settle(inv.PaymentRequest, dcrutil.Amount(inv.AmtPaidMAtoms/1000))
+		}
+	}
+}
+
+// indexContext is the data passed to the index template.
+type indexContext struct {
+	Title       string
+	Description string
+	Prefix      string
+	Presets     []dcrutil.Amount
+	MinAmount   dcrutil.Amount
+	PublicList  bool
+
+	// Donations are the public settled donations, most recent first.
+	Donations []*Donation
+	Total     dcrutil.Amount
+	Count     int
+}
+
+// donationContext is the data passed to the donation template.
+type donationContext struct {
+	*Donation
+	Prefix string
+}
+
+func (p *Provider) renderPage(tmplFile string, tmplCtx interface{}) (*rpc.RMFetchResourceReply, error) {
+	w := &bytes.Buffer{}
+	if err := p.render.Render(w, tmplFile, tmplCtx); err != nil {
+		return nil, fmt.Errorf("unable to execute donations template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+func badRequest(msg string) *rpc.RMFetchResourceReply {
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusBadRequest,
+		Data:   []byte(msg),
+	}
+}
+
+func (p *Provider) handleIndex() (*rpc.RMFetchResourceReply, error) {
+	tmplCtx := &indexContext{
+		Title:       p.cfg.Title,
+		Description: p.cfg.Description,
+		Prefix:      p.cfg.Prefix,
+		MinAmount:   p.minAmount(),
+		PublicList:  p.cfg.PublicList,
+	}
+	for _, v := range p.cfg.Presets {
+		if amount, err := dcrutil.NewAmount(v); err == nil && amount > 0 {
+			tmplCtx.Presets = append(tmplCtx.Presets, amount)
+		}
+	}
+
+	if p.cfg.PublicList {
+		donations, err := p.Donations()
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range donations {
+			if d.Status != StatusSettled {
+				continue
+			}
+			tmplCtx.Count++
+			tmplCtx.Total += d.PaidAmount
+			if d.Public && len(tmplCtx.Donations) < maxPublicDonations {
+				tmplCtx.Donations = append(tmplCtx.Donations, d)
+			}
+		}
+	}
+	return p.renderPage(indexTmplFile, tmplCtx)
+}
+
+// handleGive creates a donation. The amount is either in the path
+// (/<prefix>/give/<amount>) or in the form data, along with the optional
+// public name and message of the donor.
+func (p *Provider) handleGive(ctx context.Context, uid clientintf.UserID,
+	path []string, data []byte) (*rpc.RMFetchResourceReply, error) {
+
+	fd, err := resources.ParseFormData(data)
+	if err != nil {
+		return badRequest("request data not valid json"), nil
+	}
+	amountStr := fd.String("amount")
+	if len(path) > 1 {
+		amountStr = path[1]
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(amountStr), 64)
+	if err != nil {
+		return badRequest(fmt.Sprintf("invalid amount %q", amountStr)), nil
+	}
+	amount, err := dcrutil.NewAmount(v)
+	if err != nil || amount < p.minAmount() {
+		return badRequest(fmt.Sprintf("The minimum donation is %s",
+			p.minAmount())), nil
+	}
+	name := strescape.Nick(strings.TrimSpace(fd.String("name")))
+	msg := strescape.Content(strings.TrimSpace(fd.String("message")))
+	if len(name) > maxMessageLen || len(msg) > maxMessageLen {
+		return badRequest(fmt.Sprintf("The name and message are limited "+
+			"to %d characters", maxMessageLen)), nil
+	}
+
+	d, err := p.newDonation(ctx, uid, amount, name, msg)
+	if err != nil {
+		return nil, err
+	}
+	return p.renderPage(donationTmplFile, &donationContext{Donation: d, Prefix: p.cfg.Prefix})
+}
+
+// handleDonation shows a donation of the user.
+func (p *Provider) handleDonation(uid clientintf.UserID, path []string) (*rpc.RMFetchResourceReply, error) {
+	id, err := strconv.ParseUint(path[1], 10, 64)
+	if err != nil {
+		return badRequest("invalid donation id"), nil
+	}
+	d := new(Donation)
+	p.mtx.Lock()
+	err = jsonfile.Read(p.donationFname(id), d)
+	p.mtx.Unlock()
+	if errors.Is(err, jsonfile.ErrNotFound) || (err == nil && d.User != uid) {
+		return badRequest("donation not found"), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.renderPage(donationTmplFile, &donationContext{Donation: d, Prefix: p.cfg.Prefix})
+}
+
+// Fulfill is part of the resources.Provider interface.
+func (p *Provider) Fulfill(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	path := request.Path
+	if len(path) > 0 && path[0] == p.cfg.Prefix {
+		path = path[1:]
+	}
+	switch {
+	case len(path) == 0:
+		return p.handleIndex()
+	case path[0] == "give" && len(path) <= 2:
+		return p.handleGive(ctx, uid, path, request.Data)
+	case path[0] == "donation" && len(path) == 2:
+		return p.handleDonation(uid, path)
+	default:
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusNotFound,
+		}, nil
+	}
+}
@@ -0,0 +1,225 @@
+package donations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/internal/testutils"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// newTestProvider returns a provider with a public list in a new root dir.
+func newTestProvider(t testing.TB) *Provider {
+	t.Helper()
+	p, err := New(Config{
+		Root:       testutils.TempTestDir(t, "donations-"),
+		Title:      "Support Us",
+		Presets:    []float64{0.1, 0, 1},
+		MinAmount:  0.01,
+		PublicList: true,
+	})
+	assert.NilErr(t, err)
+	return p
+}
+
+// addDonation adds the pending donation to the provider as if it had been
+// created by newDonation.
+func addDonation(t testing.TB, p *Provider, d *Donation) {
+	t.Helper()
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	fname, id, err := donationFnamePattern.NextFilename(p.cfg.Root)
+	assert.NilErr(t, err)
+	d.ID = id
+	d.Invoice = fmt.Sprintf("invoice-%d", id)
+	d.Status = StatusPending
+	d.Public = d.Name != ""
+	if d.CreatedTS.IsZero() {
+		d.CreatedTS = time.Now()
+	}
+	assert.NilErr(t, jsonfile.Write(fname, d, p.log))
+	p.pending[d.Invoice] = id
+}
+
+// fetch fetches the path from the provider.
+func fetch(t testing.TB, p *Provider, uid clientintf.UserID, path string,
+	data string) *rpc.RMFetchResourceReply {
+	t.Helper()
+	req := &rpc.RMFetchResource{
+		Path: strings.Split(path, "/"),
+		Data: []byte(data),
+	}
+	res, err := p.Fulfill(context.Background(), uid, req)
+	assert.NilErr(t, err)
+	return res
+}
+
+// TestGiveValidation tests that donations with invalid amounts, names or
+// messages are rejected.
+func TestGiveValidation(t *testing.T) {
+	p := newTestProvider(t)
+	uid := clientintf.UserID{1: 1}
+
+	long := strings.Repeat("x", maxMessageLen+1)
+	tests := []struct {
+		name string
+		path string
+		data string
+		want string
+	}{{
+		name: "invalid json",
+		path: "donate/give",
+		data: "[1]",
+		want: "request data not valid json",
+	}, {
+		name: "missing amount",
+		path: "donate/give",
+		data: `{}`,
+		want: `invalid amount ""`,
+	}, {
+		name: "invalid amount in path",
+		path: "donate/give/lots",
+		want: `invalid amount "lots"`,
+	}, {
+		name: "below min amount",
+		path: "donate/give",
+		data: `{"amount":"0.001"}`,
+		want: "The minimum donation is 0.01 DCR",
+	}, {
+		name: "negative amount in path",
+		path: "donate/give/-1",
+		want: "The minimum donation is 0.01 DCR",
+	}, {
+		name: "long name",
+		path: "donate/give",
+		data: fmt.Sprintf(`{"amount":"1","name":%q}`, long),
+		want: fmt.Sprintf("The name and message are limited to %d characters", maxMessageLen),
+	}, {
+		name: "long message",
+		path: "donate/give/1",
+		data: fmt.Sprintf(`{"message":%q}`, long),
+		want: fmt.Sprintf("The name and message are limited to %d characters", maxMessageLen),
+	}}
+	for _, tc := range tests {
+		res := fetch(t, p, uid, tc.path, tc.data)
+		assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+		if string(res.Data) != tc.want {
+			t.Fatalf("%s: unexpected reply %q", tc.name, res.Data)
+		}
+	}
+
+	// Valid donations need LN to create their invoices.
+	req := &rpc.RMFetchResource{Path: []string{"donate", "give", "0.5"}}
+	_, err := p.Fulfill(context.Background(), uid, req)
+	assert.NonNilErr(t, err)
+
+	res := fetch(t, p, uid, "donate/other", "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusNotFound)
+}
+
+// TestSettleDonations tests that pending donations are settled once when paid
+// and that only the settled donations of donors that chose to be listed are
+// shown publicly.
+func TestSettleDonations(t *testing.T) {
+	p := newTestProvider(t)
+	received := make(chan *Donation, 5)
+	p.cfg.DonationReceived = func(d *Donation) { received <- d }
+	alice, bob := clientintf.UserID{1: 1}, clientintf.UserID{1: 2}
+
+	d1 := &Donation{User: alice, Amount: 1e8, Name: "alice", Message: "keep it up"}
+	addDonation(t, p, d1)
+	d2 := &Donation{User: bob, Amount: 5e7}
+	addDonation(t, p, d2)
+	d3 := &Donation{User: bob, Amount: 2e7, Name: "bob"}
+	addDonation(t, p, d3)
+
+	p.settle(d1.Invoice, 1e8)
+	d := assert.ChanWritten(t, received)
+	assert.DeepEqual(t, d.ID, d1.ID)
+	assert.DeepEqual(t, d.Status, StatusSettled)
+	assert.DeepEqual(t, d.PaidAmount, dcrutil.Amount(1e8))
+	if d.SettledTS == nil {
+		t.Fatal("settled donation without settled time")
+	}
+	p.settle(d2.Invoice, 5e7)
+	assert.ChanWritten(t, received)
+
+	// Donations are only settled once and unknown invoices are ignored.
+	p.settle(d1.Invoice, 1e8)
+	p.settle("invoice-10", 1e8)
+	assert.ChanNotWritten(t, received, 50*time.Millisecond)
+
+	// The public list has the totals of all settled donations, but only
+	// lists the named ones.
+	res := fetch(t, p, bob, "donate", "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	page := string(res.Data)
+	for _, s := range []string{
+		"# Support Us",
+		"[Donate 0.1 DCR](/donate/give/0.1)",
+		"[Donate 1 DCR](/donate/give/1)",
+		"2 donations received, totalling 1.5 DCR.",
+		"- **alice** (1 DCR): keep it up",
+	} {
+		if !strings.Contains(page, s) {
+			t.Fatalf("index page does not contain %q: %s", s, page)
+		}
+	}
+	if strings.Contains(page, "**bob**") || strings.Contains(page, "Donate 0 DCR") {
+		t.Fatalf("unexpected index page: %s", page)
+	}
+
+	// Donations are only visible to their donors.
+	res = fetch(t, p, alice, fmt.Sprintf("donate/donation/%d", d1.ID), "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	if !strings.Contains(string(res.Data), "Thank you!") {
+		t.Fatalf("unexpected donation page: %s", res.Data)
+	}
+	res = fetch(t, p, bob, fmt.Sprintf("donate/donation/%d", d1.ID), "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+	res = fetch(t, p, bob, fmt.Sprintf("donate/donation/%d", d3.ID), "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	if !strings.Contains(string(res.Data), "lnpay://"+d3.Invoice) {
+		t.Fatalf("unexpected donation page: %s", res.Data)
+	}
+	res = fetch(t, p, bob, "donate/donation/99", "")
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+}
+
+// TestPendingDonationsReload tests that only the pending donations whose
+// invoices did not expire are settled after the provider is reloaded.
+func TestPendingDonationsReload(t *testing.T) {
+	p := newTestProvider(t)
+	uid := clientintf.UserID{1: 1}
+	pending := &Donation{User: uid, Amount: 1e8}
+	addDonation(t, p, pending)
+	expired := &Donation{User: uid, Amount: 1e8,
+		CreatedTS: time.Now().Add(-2 * invoiceValidity)}
+	addDonation(t, p, expired)
+
+	received := make(chan *Donation, 5)
+	p2, err := New(Config{
+		Root:             p.cfg.Root,
+		DonationReceived: func(d *Donation) { received <- d },
+	})
+	assert.NilErr(t, err)
+	p2.settle(expired.Invoice, 1e8)
+	assert.ChanNotWritten(t, received, 50*time.Millisecond)
+	p2.settle(pending.Invoice, 1e8)
+	assert.DeepEqual(t, assert.ChanWritten(t, received).ID, pending.ID)
+
+	// Donations are listed most recent first.
+	donations, err := p2.Donations()
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(donations), 2)
+	assert.DeepEqual(t, donations[0].ID, expired.ID)
+	assert.DeepEqual(t, donations[0].Status, StatusPending)
+	assert.DeepEqual(t, donations[1].Status, StatusSettled)
+}
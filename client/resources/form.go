@@ -0,0 +1,266 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// Types of the fields of forms in pages.
+const (
+	// FormFieldAction is the field with the path the form is submitted
+	// to.
+	FormFieldAction = "action"
+
+	FormFieldHidden   = "hidden"
+	FormFieldTxtInput = "txtinput"
+	FormFieldIntInput = "intinput"
+	FormFieldSubmit   = "submit"
+)
+
+const (
+	formStartMarker = "--form--"
+	formEndMarker   = "--/form--"
+)
+
+// FormField is a field of a form in a page.
+type FormField struct {
+	Type  string
+	Name  string
+	Label string
+	Value string
+}
+
+// formAttrValue cleans a value to be used as an attribute of a form field,
+// which cannot have quotes or line breaks.
+func formAttrValue(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '"':
+			return '\''
+		case '\n', '\r':
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// String returns the field in the format of form fields in pages.
+func (ff FormField) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "type=\"%s\"", formAttrValue(ff.Type))
+	if ff.Label != "" {
+		fmt.Fprintf(&b, " label=\"%s\"", formAttrValue(ff.Label))
+	}
+	if ff.Name != "" {
+		fmt.Fprintf(&b, " name=\"%s\"", formAttrValue(ff.Name))
+	}
+	if ff.Value != "" || ff.Type != FormFieldSubmit {
+		fmt.Fprintf(&b, " value=\"%s\"", formAttrValue(ff.Value))
+	}
+	return b.String()
+}
+
+var formFieldPattern = regexp.MustCompile(`([\w]+)="([^"]*)"`)
+
+// ParseFormField parses a line with a form field. It returns false if the
+// line does not have the type of the field.
+func ParseFormField(line string) (FormField, bool) {
+	var ff FormField
+	for _, m := range formFieldPattern.FindAllStringSubmatch(line, -1) {
+		switch m[1] {
+		case "type":
+			ff.Type = m[2]
+		case "name":
+			ff.Name = m[2]
+		case "value":
+			ff.Value = m[2]
+		case "label":
+			ff.Label = m[2]
+		}
+	}
+	return ff, ff.Type != ""
+}
+
+// Form is a form that may be declared in pages, to be filled and submitted
+// by the users that fetch the pages.
+type Form struct {
+	Fields []FormField
+}
+
+// NewForm starts a new form, submitted to the passed path.
+func NewForm(action string) *Form {
+	return &Form{Fields: []FormField{{Type: FormFieldAction, Value: action}}}
+}
+
+// Hidden adds a hidden field to the form.
+func (f *Form) Hidden(name, value string) *Form {
+	f.Fields = append(f.Fields, FormField{Type: FormFieldHidden, Name: name, Value: value})
+	return f
+}
+
+// TextInput adds a text input field to the form.
+func (f *Form) TextInput(name, label, value string) *Form {
+	f.Fields = append(f.Fields, FormField{Type: FormFieldTxtInput,
+		Name: name, Label: label, Value: value})
+	return f
+}
+
+// IntInput adds an integer input field to the form.
+func (f *Form) IntInput(name, label string, value int64) *Form {
+	f.Fields = append(f.Fields, FormField{Type: FormFieldIntInput,
+		Name: name, Label: label, Value: strconv.FormatInt(value, 10)})
+	return f
+}
+
+// Submit adds the submit button to the form.
+func (f *Form) Submit(label string) *Form {
+	f.Fields = append(f.Fields, FormField{Type: FormFieldSubmit, Label: label})
+	return f
+}
+
+// Action returns the path the form is submitted to.
+func (f *Form) Action() string {
+	for _, ff := range f.Fields {
+		if ff.Type == FormFieldAction {
+			return ff.Value
+		}
+	}
+	return ""
+}
+
+// String returns the form in the format used to declare forms in pages.
+func (f *Form) String() string {
+	var b strings.Builder
+	b.WriteString(formStartMarker)
+	b.WriteRune('\n')
+	for _, ff := range f.Fields {
+		b.WriteString(ff.String())
+		b.WriteRune('\n')
+	}
+	b.WriteString(formEndMarker)
+	b.WriteRune('\n')
+	return b.String()
+}
+
+// Data returns the data sent when the form is submitted with the current
+// values of its fields. The values of integer fields are sent as numbers.
+func (f *Form) Data() (json.RawMessage, error) {
+	m := make(map[string]interface{}, len(f.Fields))
+	for _, ff := range f.Fields {
+		if ff.Name == "" {
+			continue
+		}
+		if ff.Type == FormFieldIntInput {
+			i, err := strconv.ParseInt(ff.Value, 10, 64)
+			if err != nil && ff.Value != "" {
+				return nil, fmt.Errorf("field %q is not an integer", ff.Name)
+			}
+			m[ff.Name] = i
+			continue
+		}
+		m[ff.Name] = ff.Value
+	}
+	return json.Marshal(m)
+}
+
+// ParseForms returns the forms declared in the page.
+func ParseForms(page string) []*Form {
+	var res []*Form
+	var form *Form
+	for _, line := range strings.Split(page, "\n") {
+		switch {
+		case line == formStartMarker:
+			form = &Form{}
+		case line == formEndMarker && form != nil:
+			res = append(res, form)
+			form = nil
+		case form != nil:
+			if ff, ok := ParseFormField(line); ok {
+				form.Fields = append(form.Fields, ff)
+			}
+		}
+	}
+	return res
+}
+
+// FormData is the data of a submitted form.
+type FormData map[string]json.RawMessage
+
+// ParseFormData parses the data of a submitted form.
+func ParseFormData(data []byte) (FormData, error) {
+	fd := make(FormData)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return fd, nil
+	}
+	if err := json.Unmarshal(data, &fd); err != nil {
+		return nil, fmt.Errorf("form data is not a JSON object: %v", err)
+	}
+	return fd, nil
+}
+
+// IsFormSubmission returns true if the request is the submission of a form.
+// Clients that do not mark form submissions via the request meta are detected
+// by their data being a JSON object.
+func IsFormSubmission(req *rpc.RMFetchResource) bool {
+	if req.Meta[rpc.ResourceMetaContentType] == rpc.ResourceContentTypeForm {
+		return true
+	}
+	data := bytes.TrimSpace(req.Data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// Has returns true if the form data has the field.
+func (fd FormData) Has(name string) bool {
+	_, ok := fd[name]
+	return ok
+}
+
+// String returns the value of the field as a string, or an empty string if
+// the field is not set. Numbers are returned in their decimal form.
+func (fd FormData) String(name string) string {
+	raw, ok := fd[name]
+	if !ok {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
+
+// Int returns the value of the field as an integer. Fields sent as strings
+// are parsed as decimal integers.
+func (fd FormData) Int(name string) (int64, error) {
+	if !fd.Has(name) {
+		return 0, fmt.Errorf("field %q not set", name)
+	}
+	i, err := strconv.ParseInt(strings.TrimSpace(fd.String(name)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("field %q is not an integer", name)
+	}
+	return i, nil
+}
+
+// Float returns the value of the field as a float. Fields sent as strings are
+// parsed as decimal numbers.
+func (fd FormData) Float(name string) (float64, error) {
+	if !fd.Has(name) {
+		return 0, fmt.Errorf("field %q not set", name)
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(fd.String(name)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("field %q is not a number", name)
+	}
+	return f, nil
+}
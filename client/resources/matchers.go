@@ -1,6 +1,12 @@
 package resources
 
-import "github.com/companyzero/bisonrelay/rpc"
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/rpc"
+)
 
 // exactPathMatcher is a matcher that matches only the exact path.
 func exactPathMatcher(path []string) routeMatcher {
@@ -40,3 +46,88 @@ func prefixPathMatcher(prefixPath []string) routeMatcher {
 		return true
 	}
 }
+
+// pathPattern is a parsed pattern of paths bound with Router.Handle.
+type pathPattern struct {
+	elems []string
+
+	// rest is true if the pattern ends with a "*" element.
+	rest bool
+}
+
+func parsePathPattern(pattern string) (*pathPattern, error) {
+	pattern = strings.Trim(pattern, "/")
+	pp := &pathPattern{}
+	if pattern == "" {
+		return pp, nil
+	}
+	elems := SplitPath(pattern)
+	for i, e := range elems {
+		switch {
+		case e == "*" && i == len(elems)-1:
+			pp.rest = true
+			continue
+		case e == "*":
+			return nil, fmt.Errorf("pattern %q has a \"*\" element "+
+				"that is not the last one", pattern)
+		case e == "", e == ":":
+			return nil, fmt.Errorf("pattern %q has an empty element",
+				pattern)
+		}
+		pp.elems = append(pp.elems, e)
+	}
+	return pp, nil
+}
+
+// matcher returns a matcher of the paths that match the pattern.
+func (pp *pathPattern) matcher() routeMatcher {
+	return func(req *rpc.RMFetchResource) bool {
+		if req == nil {
+			return false
+		}
+		if len(req.Path) < len(pp.elems) {
+			return false
+		}
+		if !pp.rest && len(req.Path) != len(pp.elems) {
+			return false
+		}
+		for i, e := range pp.elems {
+			if strings.HasPrefix(e, ":") {
+				continue
+			}
+			if req.Path[i] != e {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// params returns the values of the params of the pattern in the path. The
+// rest of the path matched by a final "*" is returned in the "*" param.
+func (pp *pathPattern) params(path []string) map[string]string {
+	params := make(map[string]string)
+	for i, e := range pp.elems {
+		if strings.HasPrefix(e, ":") && i < len(path) {
+			params[e[1:]] = path[i]
+		}
+	}
+	if pp.rest && len(path) >= len(pp.elems) {
+		params["*"] = strings.Join(path[len(pp.elems):], "/")
+	}
+	return params
+}
+
+type pathParamsCtxKey struct{}
+
+func withPathParams(ctx context.Context, params map[string]string) context.Context {
+	return context.WithValue(ctx, pathParamsCtxKey{}, params)
+}
+
+// PathParam returns the value of the named param of the path of a request
+// routed with Router.Handle. The rest of the path matched by a final "*"
+// element is returned for the "*" param.
+func PathParam(ctx context.Context, name string) string {
+	params, _ := ctx.Value(pathParamsCtxKey{}).(map[string]string)
+	return params[name]
+}
@@ -0,0 +1,179 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
+	"github.com/companyzero/bisonrelay/client/supervisor"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/slog"
+	"golang.org/x/time/rate"
+)
+
+// Middleware wraps a provider to run code before and after requests are
+// fulfilled by it. A middleware may also fulfill the request itself (for
+// example, to deny it) without calling the wrapped provider.
+type Middleware func(next Provider) Provider
+
+// Chain wraps the provider with the passed middlewares. The first middleware
+// is the outermost one, so it is the first one called for each request.
+func Chain(p Provider, mws ...Middleware) Provider {
+	for i := len(mws) - 1; i >= 0; i-- {
+		p = mws[i](p)
+	}
+	return p
+}
+
+// LogRequests is a middleware that logs the requests, along with the status
+// of their replies and how long they took to be fulfilled.
+func LogRequests(log slog.Logger) Middleware {
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, uid clientintf.UserID,
+			req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+			start := time.Now()
+			res, err := next.Fulfill(ctx, uid, req)
+			path := strescape.ResourcesPath(req.Path)
+			elapsed := time.Since(start).Truncate(time.Millisecond)
+			if err != nil {
+				log.Warnf("Request for %s from %s failed after %s: %v",
+					path, uid.ShortLogID(), elapsed, err)
+			} else if res != nil {
+				log.Infof("Request for %s from %s: status %s in %s",
+					path, uid.ShortLogID(), res.Status, elapsed)
+			}
+			return res, err
+		})
+	}
+}
+
+// Instrument is a middleware that records the number of requests fulfilled
+// and how long they took to be fulfilled in the passed metrics.
+func Instrument(m *metrics.Metrics) Middleware {
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, uid clientintf.UserID,
+			req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+			start := time.Now()
+			res, err := next.Fulfill(ctx, uid, req)
+			m.ObserveSince(metrics.ResourceFulfillSeconds, start)
+			m.Inc(metrics.ResourceRequestsServed)
+			return res, err
+		})
+	}
+}
+
+// RecoverPanics is a middleware that isolates panics of the wrapped provider
+// with the supervisor. Requests that cause the provider to panic fail with an
+// error and the panic is reported as an incident of the "resources"
+// subsystem.
+func RecoverPanics(sup *supervisor.Supervisor) Middleware {
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, uid clientintf.UserID,
+			req *rpc.RMFetchResource) (res *rpc.RMFetchResourceReply, err error) {
+
+			perr := sup.Protect("resources", func() error {
+				res, err = next.Fulfill(ctx, uid, req)
+				return nil
+			})
+			if perr != nil {
+				return nil, fmt.Errorf("request for %s: %w",
+					strescape.ResourcesPath(req.Path), perr)
+			}
+			return res, err
+		})
+	}
+}
+
+// RequireUser is a middleware that only allows requests from the users for
+// which allowed returns true. Requests from other users are replied with a
+// forbidden status.
+func RequireUser(allowed func(uid clientintf.UserID) bool) Middleware {
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, uid clientintf.UserID,
+			req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+			if !allowed(uid) {
+				return &rpc.RMFetchResourceReply{
+					Status: rpc.ResourceStatusForbidden,
+					Data:   []byte("Access to this resource is not allowed."),
+				}, nil
+			}
+			return next.Fulfill(ctx, uid, req)
+		})
+	}
+}
+
+// RequireUsers is a middleware that only allows requests from the passed
+// users.
+func RequireUsers(uids ...clientintf.UserID) Middleware {
+	allowed := make(map[clientintf.UserID]struct{}, len(uids))
+	for _, uid := range uids {
+		allowed[uid] = struct{}{}
+	}
+	return RequireUser(func(uid clientintf.UserID) bool {
+		_, ok := allowed[uid]
+		return ok
+	})
+}
+
+// maxRateLimiters is the number of users tracked by RateLimit above which the
+// limiters of idle users are dropped.
+const maxRateLimiters = 4096
+
+// RateLimit is a middleware that limits the rate of requests of each remote
+// user to r requests per second, with bursts of up to burst requests.
+// Requests above the limit are replied with a too many requests status.
+func RateLimit(r float64, burst int) Middleware {
+	if burst < 1 {
+		burst = 1
+	}
+	var mtx sync.Mutex
+	limiters := make(map[clientintf.UserID]*rate.Limiter)
+
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, uid clientintf.UserID,
+			req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+			now := time.Now()
+			mtx.Lock()
+			lim := limiters[uid]
+			if lim == nil {
+				// Limiters with their full burst available
+				// behave the same as new limiters.
+				if len(limiters) >= maxRateLimiters {
+					for id, l := range limiters {
+						if l.TokensAt(now) >= float64(burst) {
+							delete(limiters, id)
+						}
+					}
+				}
+				lim = rate.NewLimiter(rate.Limit(r), burst)
+				limiters[uid] = lim
+			}
+			mtx.Unlock()
+
+			rsv := lim.ReserveN(now, 1)
+			if delay := rsv.DelayFrom(now); delay > 0 {
+				rsv.CancelAt(now)
+				secs := int64(math.Ceil(delay.Seconds()))
+				return &rpc.RMFetchResourceReply{
+					Status: rpc.ResourceStatusTooManyRequests,
+					Meta: map[string]string{
+						rpc.ResourceMetaRetryAfter: strconv.FormatInt(secs, 10),
+					},
+					Data: []byte(fmt.Sprintf("Too many requests. Try "+
+						"again in %d seconds.", secs)),
+				}, nil
+			}
+			return next.Fulfill(ctx, uid, req)
+		})
+	}
+}
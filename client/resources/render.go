@@ -0,0 +1,100 @@
+package resources
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"text/template"
+)
+
+// RenderEngine is the interface for template engines used by resource
+// providers to render their pages. This allows providers to use alternative
+// template engines or precompiled template sets.
+type RenderEngine interface {
+	// Render renders the template with the given name using the passed
+	// data.
+	Render(w io.Writer, name string, data interface{}) error
+
+	// Has returns true if the engine has a template with the given name.
+	Has(name string) bool
+}
+
+// TextTemplateEngine is a RenderEngine that renders templates using the
+// text/template package.
+type TextTemplateEngine struct {
+	tmpl *template.Template
+}
+
+// NewTextTemplateEngine returns a render engine that renders the templates
+// defined in tmpl.
+func NewTextTemplateEngine(tmpl *template.Template) *TextTemplateEngine {
+	return &TextTemplateEngine{tmpl: tmpl}
+}
+
+// Render is part of the RenderEngine interface.
+func (e *TextTemplateEngine) Render(w io.Writer, name string, data interface{}) error {
+	return e.tmpl.ExecuteTemplate(w, name, data)
+}
+
+// Has is part of the RenderEngine interface.
+func (e *TextTemplateEngine) Has(name string) bool {
+	return e.tmpl.Lookup(name) != nil
+}
+
+// Template returns the underlying template set.
+func (e *TextTemplateEngine) Template() *template.Template {
+	return e.tmpl
+}
+
+// ParseTextTemplatesFS parses the files of fsys that match any of the patterns
+// (as defined by fs.Glob) as text templates. Each template is named after the
+// base name of its file. This can be used to load a precompiled set of
+// templates from an embedded FS.
+func ParseTextTemplatesFS(fsys fs.FS, funcs template.FuncMap,
+	patterns ...string) (*TextTemplateEngine, error) {
+
+	tmpl := template.New("*root").Funcs(funcs)
+	for _, pattern := range patterns {
+		fnames, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, fname := range fnames {
+			data, err := fs.ReadFile(fsys, fname)
+			if err != nil {
+				return nil, err
+			}
+			_, err = tmpl.New(path.Base(fname)).Parse(string(data))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse template %s: %v",
+					fname, err)
+			}
+		}
+	}
+	return &TextTemplateEngine{tmpl: tmpl}, nil
+}
+
+// FallbackEngine is a RenderEngine that renders each template with the first
+// of its engines that has it.
+type FallbackEngine []RenderEngine
+
+// Render is part of the RenderEngine interface.
+func (engines FallbackEngine) Render(w io.Writer, name string, data interface{}) error {
+	for _, e := range engines {
+		if e.Has(name) {
+			return e.Render(w, name, data)
+		}
+	}
+	return fmt.Errorf("template %q not defined", name)
+}
+
+// Has is part of the RenderEngine interface.
+func (engines FallbackEngine) Has(name string) bool {
+	for _, e := range engines {
+		if e.Has(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -15,53 +15,117 @@ type routeMatcher func(req *rpc.RMFetchResource) bool
 type matcherProvider struct {
 	matcher  routeMatcher
 	provider Provider
+
+	// pattern is set for routes bound with Handle, to extract the path
+	// params of matched requests.
+	pattern *pathPattern
 }
 
 // Router is a Provider that matches requests to sub-providers using specific
 // rules.
+//
+// Routes are matched in the order they were bound, so more specific routes
+// should be bound before the ones with a less specific prefix. Binding routes
+// and middleware is not safe to do concurrently with requests being fulfilled.
 type Router struct {
-	matchers []*matcherProvider
+	matchers    []*matcherProvider
+	middlewares []Middleware
 }
 
-func (r *Router) bind(m routeMatcher, p Provider) {
+func (r *Router) bind(m routeMatcher, p Provider, mws []Middleware) *matcherProvider {
 	mp := &matcherProvider{
 		matcher:  m,
-		provider: p,
+		provider: Chain(p, mws...),
 	}
 	r.matchers = append(r.matchers, mp)
+	return mp
 }
 
 // BindExactPath binds the passed provider to be called whenever a request
-// has an exact path.
-func (r *Router) BindExactPath(path []string, p Provider) {
-	r.bind(exactPathMatcher(path), p)
+// has an exact path. The middlewares (if any) are only applied to requests
+// for this path.
+func (r *Router) BindExactPath(path []string, p Provider, mws ...Middleware) {
+	r.bind(exactPathMatcher(path), p, mws)
 }
 
 // BindPrefixPath binds the passed provider to be called whenever a request
-// has a path with the passed prefix.
-func (r *Router) BindPrefixPath(prefixPath []string, p Provider) {
-	r.bind(prefixPathMatcher(prefixPath), p)
+// has a path with the passed prefix. The middlewares (if any) are only
+// applied to requests for paths with this prefix.
+func (r *Router) BindPrefixPath(prefixPath []string, p Provider, mws ...Middleware) {
+	r.bind(prefixPathMatcher(prefixPath), p, mws)
 }
 
-// FindProvider attempts to find a provider to match the request.
-func (r *Router) FindProvider(req *rpc.RMFetchResource) Provider {
+// Handle binds the passed provider to be called whenever a request has a
+// path that matches the pattern. The pattern is a slash separated list of
+// path elements, where an element in the form ":name" matches any single
+// element of the path and a final "*" element matches the rest of the path
+// (including an empty one). For example, "orders/:id" and "files/*".
+//
+// The provider may obtain the value of the params with PathParam. The
+// middlewares (if any) are only applied to requests that match the pattern.
+func (r *Router) Handle(pattern string, p Provider, mws ...Middleware) error {
+	pp, err := parsePathPattern(pattern)
+	if err != nil {
+		return err
+	}
+	mp := r.bind(pp.matcher(), p, mws)
+	mp.pattern = pp
+	return nil
+}
+
+// HandleFunc is like Handle, but binds the passed function as the provider.
+func (r *Router) HandleFunc(pattern string,
+	f func(ctx context.Context, uid clientintf.UserID, request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error),
+	mws ...Middleware) error {
+
+	return r.Handle(pattern, ProviderFunc(f), mws...)
+}
+
+// Use adds middlewares that are applied to all requests fulfilled by the
+// router, including the ones that do not match any route. Middlewares are
+// called in the order they were added.
+func (r *Router) Use(mws ...Middleware) {
+	r.middlewares = append(r.middlewares, mws...)
+}
+
+// findRoute returns the first route that matches the request.
+func (r *Router) findRoute(req *rpc.RMFetchResource) *matcherProvider {
 	for _, mp := range r.matchers {
 		if mp.matcher(req) {
-			return mp.provider
+			return mp
 		}
 	}
 
 	return nil
 }
 
-// Fulfill attempts to find a sub-provider to match and fulfill the request.
-func (r *Router) Fulfill(ctx context.Context, uid clientintf.UserID, req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
-	p := r.FindProvider(req)
-	if p == nil {
+// FindProvider attempts to find a provider to match the request.
+func (r *Router) FindProvider(req *rpc.RMFetchResource) Provider {
+	if mp := r.findRoute(req); mp != nil {
+		return mp.provider
+	}
+	return nil
+}
+
+// dispatch fulfills the request with the provider of the matching route.
+func (r *Router) dispatch(ctx context.Context, uid clientintf.UserID, req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+	mp := r.findRoute(req)
+	if mp == nil {
 		return nil, ErrProviderNotFound
 	}
+	if mp.pattern != nil {
+		ctx = withPathParams(ctx, mp.pattern.params(req.Path))
+	}
+
+	return mp.provider.Fulfill(ctx, uid, req)
+}
 
-	return p.Fulfill(ctx, uid, req)
+// Fulfill attempts to find a sub-provider to match and fulfill the request.
+func (r *Router) Fulfill(ctx context.Context, uid clientintf.UserID, req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+	if len(r.middlewares) == 0 {
+		return r.dispatch(ctx, uid, req)
+	}
+	return Chain(ProviderFunc(r.dispatch), r.middlewares...).Fulfill(ctx, uid, req)
 }
 
 // NewRouter initializes a new, empty router.
@@ -0,0 +1,336 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/slog"
+)
+
+// DefaultSessionTTL is the default duration of sessions since their last use.
+const DefaultSessionTTL = 30 * time.Minute
+
+// ErrSessionExpired is returned when modifying a session that expired or was
+// removed from its store.
+var ErrSessionExpired = errors.New("session expired")
+
+// SessionStoreConfig is the configuration of a SessionStore.
+type SessionStoreConfig struct {
+	// TTL is how long sessions are kept since they were last used.
+	// Defaults to DefaultSessionTTL.
+	TTL time.Duration
+
+	// Dir, if set, is the dir where sessions are persisted, such that they
+	// survive restarts. If empty, sessions are only kept in memory.
+	Dir string
+
+	Log slog.Logger
+}
+
+// sessionData is the persisted data of a session.
+type sessionData struct {
+	UID     clientintf.UserID          `json:"uid"`
+	Expires time.Time                  `json:"expires"`
+	Values  map[string]json.RawMessage `json:"values"`
+}
+
+// Session is the state kept for a remote user across their resource requests.
+// Values are stored encoded as JSON, so they should be of types that round
+// trip through JSON.
+type Session struct {
+	ss *SessionStore
+
+	mtx  sync.Mutex
+	data sessionData
+}
+
+// UserID is the id of the user that owns the session.
+func (s *Session) UserID() clientintf.UserID {
+	return s.data.UID
+}
+
+// Get decodes the value of the key into v. It returns false if the key is not
+// set in the session.
+func (s *Session) Get(key string, v interface{}) (bool, error) {
+	s.mtx.Lock()
+	raw, ok := s.data.Values[key]
+	s.mtx.Unlock()
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, v)
+}
+
+// update calls f to modify the values of the session and persists the session
+// if f returns true. It fails with ErrSessionExpired if the session expired or
+// was removed from its store, so that removed sessions are not written back.
+func (s *Session) update(f func() bool) error {
+	now := time.Now()
+	s.ss.mtx.Lock()
+	defer s.ss.mtx.Unlock()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.ss.sessions[s.data.UID] != s || s.data.Expires.Before(now) {
+		return ErrSessionExpired
+	}
+	if !f() {
+		return nil
+	}
+	return s.ss.persist(s)
+}
+
+// Set sets the value of the key in the session.
+func (s *Session) Set(key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.update(func() bool {
+		s.data.Values[key] = raw
+		return true
+	})
+}
+
+// Delete removes the key from the session.
+func (s *Session) Delete(key string) error {
+	return s.update(func() bool {
+		if _, ok := s.data.Values[key]; !ok {
+			return false
+		}
+		delete(s.data.Values, key)
+		return true
+	})
+}
+
+// Clear removes all keys from the session.
+func (s *Session) Clear() error {
+	return s.update(func() bool {
+		s.data.Values = make(map[string]json.RawMessage)
+		return true
+	})
+}
+
+// SessionValue returns the value of the key in the session, decoded as a T. It
+// returns false if the key is not set or its value is not a T.
+func SessionValue[T any](s *Session, key string) (T, bool) {
+	var v T
+	ok, err := s.Get(key, &v)
+	if !ok || err != nil {
+		var zero T
+		return zero, false
+	}
+	return v, true
+}
+
+// SessionStore keeps the sessions of remote users. Sessions expire after not
+// being used for the configured TTL.
+type SessionStore struct {
+	cfg SessionStoreConfig
+	log slog.Logger
+
+	mtx      sync.Mutex
+	sessions map[clientintf.UserID]*Session
+}
+
+// NewSessionStore creates a new session store. If the config has a dir set,
+// the sessions persisted in it that have not expired are loaded.
+func NewSessionStore(cfg SessionStoreConfig) (*SessionStore, error) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultSessionTTL
+	}
+	log := slog.Disabled
+	if cfg.Log != nil {
+		log = cfg.Log
+	}
+	ss := &SessionStore{
+		cfg:      cfg,
+		log:      log,
+		sessions: make(map[clientintf.UserID]*Session),
+	}
+	if cfg.Dir == "" {
+		return ss, nil
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		fname := filepath.Join(cfg.Dir, e.Name())
+		s := &Session{ss: ss}
+		if err := jsonfile.Read(fname, &s.data); err != nil {
+			log.Warnf("Unable to read session file %s: %v", fname, err)
+			continue
+		}
+		if s.data.Expires.Before(now) {
+			if err := os.Remove(fname); err != nil {
+				log.Warnf("Unable to remove expired session %s: %v",
+					fname, err)
+			}
+			continue
+		}
+		if s.data.Values == nil {
+			s.data.Values = make(map[string]json.RawMessage)
+		}
+		ss.sessions[s.data.UID] = s
+	}
+	return ss, nil
+}
+
+func (ss *SessionStore) sessionFname(uid clientintf.UserID) string {
+	return filepath.Join(ss.cfg.Dir, uid.String()+".json")
+}
+
+// persist saves the session, if the store persists sessions.
+//
+// This MUST be called with the store and session mutexes held.
+func (ss *SessionStore) persist(s *Session) error {
+	if ss.cfg.Dir == "" {
+		return nil
+	}
+	return jsonfile.Write(ss.sessionFname(s.data.UID), &s.data, ss.log)
+}
+
+// Get returns the session of the user, creating a new one if the user has no
+// session or their session expired. Getting the session extends its expiry by
+// the TTL of the store. The extended expiry is persisted, so that sessions in
+// use survive restarts.
+func (ss *SessionStore) Get(uid clientintf.UserID) *Session {
+	now := time.Now()
+	ss.mtx.Lock()
+	s := ss.sessions[uid]
+	if s != nil {
+		s.mtx.Lock()
+		expired := s.data.Expires.Before(now)
+		s.mtx.Unlock()
+		if expired {
+			// Replace the expired session, so that it can no
+			// longer be modified by holders of it.
+			if err := ss.delete(uid); err != nil {
+				ss.log.Warnf("Unable to remove expired session "+
+					"of %s: %v", uid.ShortLogID(), err)
+			}
+			s = nil
+		}
+	}
+	if s != nil {
+		s.mtx.Lock()
+		s.data.Expires = now.Add(ss.cfg.TTL)
+		if len(s.data.Values) > 0 {
+			if err := ss.persist(s); err != nil {
+				ss.log.Warnf("Unable to persist session of %s: %v",
+					uid.ShortLogID(), err)
+			}
+		}
+		s.mtx.Unlock()
+	} else {
+		s = &Session{
+			ss: ss,
+			data: sessionData{
+				UID:     uid,
+				Expires: now.Add(ss.cfg.TTL),
+				Values:  make(map[string]json.RawMessage),
+			},
+		}
+		ss.sessions[uid] = s
+	}
+	ss.mtx.Unlock()
+	return s
+}
+
+// delete removes the session of the user.
+//
+// This MUST be called with the store mutex held.
+func (ss *SessionStore) delete(uid clientintf.UserID) error {
+	delete(ss.sessions, uid)
+	if ss.cfg.Dir == "" {
+		return nil
+	}
+	err := os.Remove(ss.sessionFname(uid))
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return err
+}
+
+// Delete removes the session of the user.
+func (ss *SessionStore) Delete(uid clientintf.UserID) error {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+	return ss.delete(uid)
+}
+
+// removeExpired removes the sessions that have expired.
+func (ss *SessionStore) removeExpired() {
+	now := time.Now()
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+	for uid, s := range ss.sessions {
+		s.mtx.Lock()
+		expired := s.data.Expires.Before(now)
+		s.mtx.Unlock()
+		if !expired {
+			continue
+		}
+		if err := ss.delete(uid); err != nil {
+			ss.log.Warnf("Unable to remove expired session of %s: %v",
+				uid.ShortLogID(), err)
+		}
+	}
+}
+
+// Run periodically removes the expired sessions until the context is done.
+func (ss *SessionStore) Run(ctx context.Context) error {
+	interval := ss.cfg.TTL / 2
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ss.removeExpired()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+type sessionCtxKey struct{}
+
+// WithSessions is a middleware that makes the session of the requesting user
+// available to providers via SessionFromContext.
+func WithSessions(ss *SessionStore) Middleware {
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, uid clientintf.UserID,
+			req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+			ctx = context.WithValue(ctx, sessionCtxKey{}, ss.Get(uid))
+			return next.Fulfill(ctx, uid, req)
+		})
+	}
+}
+
+// SessionFromContext returns the session of the requesting user, if the
+// request was routed through the WithSessions middleware.
+func SessionFromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionCtxKey{}).(*Session)
+	return s
+}
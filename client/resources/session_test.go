@@ -0,0 +1,128 @@
+package resources
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/assert"
+)
+
+// TestSessionTTL tests that sessions expire after not being used for the TTL
+// of the store and that expired sessions can no longer be modified.
+func TestSessionTTL(t *testing.T) {
+	t.Parallel()
+
+	ttl := 200 * time.Millisecond
+	ss, err := NewSessionStore(SessionStoreConfig{TTL: ttl})
+	assert.NilErr(t, err)
+
+	uid := clientintf.UserID{1: 1}
+	s := ss.Get(uid)
+	assert.NilErr(t, s.Set("key", "value"))
+
+	// Using the session extends its expiry.
+	for i := 0; i < 3; i++ {
+		time.Sleep(ttl / 2)
+		s = ss.Get(uid)
+		v, ok := SessionValue[string](s, "key")
+		assert.DeepEqual(t, ok, true)
+		assert.DeepEqual(t, v, "value")
+	}
+
+	// After the TTL, the user gets a new session and the old one can no
+	// longer be modified.
+	time.Sleep(ttl + ttl/2)
+	newS := ss.Get(uid)
+	if newS == s {
+		t.Fatal("expired session was returned")
+	}
+	_, ok := SessionValue[string](newS, "key")
+	assert.DeepEqual(t, ok, false)
+	if err := s.Set("key", "other"); !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("unexpected error setting expired session: %v", err)
+	}
+}
+
+// TestSessionPersistence tests that persisted sessions are loaded with their
+// renewed expiry and that sessions removed from the store are not written back.
+func TestSessionPersistence(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ttl := 500 * time.Millisecond
+	ss, err := NewSessionStore(SessionStoreConfig{TTL: ttl, Dir: dir})
+	assert.NilErr(t, err)
+
+	uid1, uid2 := clientintf.UserID{1: 1}, clientintf.UserID{1: 2}
+	assert.NilErr(t, ss.Get(uid1).Set("cart", []string{"a", "b"}))
+	s2 := ss.Get(uid2)
+	assert.NilErr(t, s2.Set("key", 10))
+
+	// Only the session of uid1 is used after being created.
+	time.Sleep(300 * time.Millisecond)
+	ss.Get(uid1)
+	time.Sleep(300 * time.Millisecond)
+
+	// The session of uid1 is loaded with its renewed expiry, while the
+	// session of uid2 expired.
+	ss2, err := NewSessionStore(SessionStoreConfig{TTL: ttl, Dir: dir})
+	assert.NilErr(t, err)
+	cart, ok := SessionValue[[]string](ss2.Get(uid1), "cart")
+	assert.DeepEqual(t, ok, true)
+	assert.DeepEqual(t, cart, []string{"a", "b"})
+	_, ok = SessionValue[int](ss2.Get(uid2), "key")
+	assert.DeepEqual(t, ok, false)
+	if _, err := os.Stat(ss.sessionFname(uid2)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("unexpected error checking expired session file: %v", err)
+	}
+
+	// A session removed from the store while held is not written back.
+	ss.removeExpired()
+	if err := s2.Set("key", 20); !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("unexpected error setting removed session: %v", err)
+	}
+	if _, err := os.Stat(ss.sessionFname(uid2)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("removed session was written back: %v", err)
+	}
+}
+
+// TestSessionValues tests getting and setting typed values in sessions.
+func TestSessionValues(t *testing.T) {
+	t.Parallel()
+
+	ss, err := NewSessionStore(SessionStoreConfig{})
+	assert.NilErr(t, err)
+	s := ss.Get(clientintf.UserID{1: 1})
+
+	type cart struct {
+		Items map[string]int `json:"items"`
+		Note  string         `json:"note"`
+	}
+	want := cart{Items: map[string]int{"book01": 2}, Note: "gift"}
+	assert.NilErr(t, s.Set("cart", want))
+	assert.NilErr(t, s.Set("count", 3))
+
+	got, ok := SessionValue[cart](s, "cart")
+	assert.DeepEqual(t, ok, true)
+	assert.DeepEqual(t, got, want)
+	count, ok := SessionValue[int](s, "count")
+	assert.DeepEqual(t, ok, true)
+	assert.DeepEqual(t, count, 3)
+
+	// Values of other types or missing keys are not returned.
+	_, ok = SessionValue[int](s, "cart")
+	assert.DeepEqual(t, ok, false)
+	_, ok = SessionValue[string](s, "missing")
+	assert.DeepEqual(t, ok, false)
+
+	// Deleted and cleared keys are no longer set.
+	assert.NilErr(t, s.Delete("count"))
+	_, ok = SessionValue[int](s, "count")
+	assert.DeepEqual(t, ok, false)
+	assert.NilErr(t, s.Clear())
+	_, ok = SessionValue[cart](s, "cart")
+	assert.DeepEqual(t, ok, false)
+}
@@ -0,0 +1,141 @@
+package simplestore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/decred/slog"
+)
+
+// ActivityType is the type of an activity event of the store.
+type ActivityType string
+
+const (
+	ActivityCartUpdated   ActivityType = "cart.updated"
+	ActivityOrderPlaced   ActivityType = "order.placed"
+	ActivityOrderPaid     ActivityType = "order.paid"
+	ActivityStatusChanged ActivityType = "order.status"
+)
+
+// activityQueueSize is the max number of activity events waiting to be
+// dispatched to handlers. Events generated while the queue is full are
+// dropped.
+const activityQueueSize = 1000
+
+// Activity is an event about the activity of the store, generated as users
+// interact with it.
+type Activity struct {
+	Type      ActivityType
+	Timestamp time.Time
+	User      clientintf.UserID
+
+	// Cart is the updated cart of the user in ActivityCartUpdated events.
+	// An empty cart means the cart was cleared.
+	Cart *Cart
+
+	// Order is the order of the order events.
+	Order *Order
+
+	// OldStatus is the status of the order before it changed, in
+	// ActivityStatusChanged events.
+	OldStatus OrderStatus
+}
+
+// activityDispatcher dispatches activity events to the registered handlers,
+// in the order they were generated.
+type activityDispatcher struct {
+	log   slog.Logger
+	queue chan *Activity
+
+	mtx      sync.Mutex
+	handlers []func(*Activity)
+}
+
+func newActivityDispatcher(log slog.Logger) *activityDispatcher {
+	return &activityDispatcher{
+		log:   log,
+		queue: make(chan *Activity, activityQueueSize),
+	}
+}
+
+// hasHandlers returns true if there are registered handlers.
+func (ad *activityDispatcher) hasHandlers() bool {
+	ad.mtx.Lock()
+	res := len(ad.handlers) > 0
+	ad.mtx.Unlock()
+	return res
+}
+
+// enqueue queues the event for dispatching. It does not block.
+func (ad *activityDispatcher) enqueue(act *Activity) {
+	select {
+	case ad.queue <- act:
+	default:
+		ad.log.Warnf("Activity queue is full. Dropping %s event of user %s",
+			act.Type, act.User.ShortLogID())
+	}
+}
+
+// run dispatches the queued events until the context is done.
+func (ad *activityDispatcher) run(ctx context.Context) error {
+	for {
+		select {
+		case act := <-ad.queue:
+			ad.mtx.Lock()
+			handlers := ad.handlers
+			ad.mtx.Unlock()
+			for _, h := range handlers {
+				h(act)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RegisterActivityHandler registers a handler that is called with the activity
+// events of the store (carts updated, orders placed, paid or with their status
+// changed). Handlers are called sequentially, in the order the events were
+// generated, while the store is running.
+func (s *Store) RegisterActivityHandler(handler func(*Activity)) {
+	ad := s.activity
+	ad.mtx.Lock()
+	ad.handlers = append(ad.handlers[:len(ad.handlers):len(ad.handlers)], handler)
+	ad.mtx.Unlock()
+}
+
+// emitCartActivity generates an event about the updated cart of the user.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) emitCartActivity(uid clientintf.UserID, cart *Cart) {
+	if !s.activity.hasHandlers() {
+		return
+	}
+	cartCopy := *cart
+	cartCopy.Items = append([]*CartItem(nil), cart.Items...)
+	s.activity.enqueue(&Activity{
+		Type:      ActivityCartUpdated,
+		Timestamp: time.Now(),
+		User:      uid,
+		Cart:      &cartCopy,
+	})
+}
+
+// emitOrderActivity generates an event about the order.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) emitOrderActivity(typ ActivityType, order *Order, oldStatus OrderStatus) {
+	if !s.activity.hasHandlers() {
+		return
+	}
+	orderCopy := *order
+	s.activity.enqueue(&Activity{
+		Type:      typ,
+		Timestamp: time.Now(),
+		User:      order.User,
+		Order:     &orderCopy,
+		OldStatus: oldStatus,
+	})
+}
@@ -4,73 +4,76 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"path/filepath"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/companyzero/bisonrelay/client/clientintf"
-	"github.com/companyzero/bisonrelay/internal/jsonfile"
 	"github.com/companyzero/bisonrelay/internal/strescape"
 	"github.com/companyzero/bisonrelay/rpc"
 )
 
-func (s *Store) handleAdminIndex(ctx context.Context, uid clientintf.UserID,
-	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+// allOrderStatuses are the statuses of orders, in the order they are listed
+// in the admin pages.
+var allOrderStatuses = []OrderStatus{StatusQuotePending, StatusPlaced,
+	StatusConfirmed, StatusPaid, StatusBackordered, StatusShipped,
+	StatusCompleted, StatusCanceled, StatusExpired}
 
-	w := &bytes.Buffer{}
-	w.WriteString("# Admin Section\n\n")
-	w.WriteString("[Recent Orders](/admin/orders)\n\n")
-	w.WriteString("[Back to Index](/)\n\n")
-	return &rpc.RMFetchResourceReply{
-		Data:   w.Bytes(),
-		Status: rpc.ResourceStatusOk,
-	}, nil
+// adminOrderSummary returns the summary of the order listed in the admin
+// pages.
+func (s *Store) adminOrderSummary(order *Order) adminOrderSummary {
+	nick, _ := s.c.UserNick(order.User)
+	nick = strescape.Nick(nick)
+
+	return adminOrderSummary{
+		ID:       order.ID,
+		User:     order.User,
+		UserNick: nick,
+		Status:   order.Status,
+		PlacedTS: order.PlacedTS,
+		NeedsAck: order.needsAck(),
+		Referral: order.Referral,
+		Escrow:   order.Escrow,
+	}
 }
 
 func (s *Store) handleAdminOrders(ctx context.Context, uid clientintf.UserID,
 	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
-	s.mtx.Lock()
-	defer s.mtx.Unlock()
 
-	// List all orders for all users.
-	pattern := filepath.Join(s.root, ordersDir, "*", "*.json")
-	files, err := filepath.Glob(pattern)
+	// The listing may be filtered by status (/admin/orders/<status>).
+	var filter OrderStatus
+	if len(request.Path) > 2 {
+		filter = OrderStatus(request.Path[2])
+		if !filter.IsValid() {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data:   []byte(fmt.Sprintf("unknown order status %q", filter)),
+			}, nil
+		}
+	}
+
+	var query OrderFilter
+	if filter != "" {
+		query.Statuses = []OrderStatus{filter}
+	}
+	orders, err := s.QueryOrders(query)
 	if err != nil {
 		return nil, err
 	}
 
 	tctx := adminOrdersContext{
-		Orders: make([]adminOrderSummary, 0, len(files)),
+		Orders:   make([]adminOrderSummary, 0, len(orders)),
+		Filter:   filter,
+		Statuses: allOrderStatuses,
 	}
-
-	for _, f := range files {
-		var order Order
-		if err := jsonfile.Read(f, &order); err != nil {
-			s.log.Warnf("Unable to decode order file %s: %v", f, err)
-			continue
-		}
-
-		nick, _ := s.c.UserNick(order.User)
-		nick = strescape.Nick(nick)
-
-		summ := adminOrderSummary{
-			ID:       order.ID,
-			User:     order.User,
-			UserNick: nick,
-			Status:   order.Status,
-			PlacedTS: order.PlacedTS,
-		}
-		tctx.Orders = append(tctx.Orders, summ)
+	for _, order := range orders {
+		tctx.Orders = append(tctx.Orders, s.adminOrderSummary(order))
 	}
 
-	sort.Slice(tctx.Orders, func(i, j int) bool {
-		return tctx.Orders[i].PlacedTS.After(tctx.Orders[j].PlacedTS)
-	})
-
 	// Generate template.
 	w := &bytes.Buffer{}
-	err = s.tmpl.ExecuteTemplate(w, adminOrdersTmplFile, &tctx)
+	err = s.render.Render(w, adminOrdersTmplFile, &tctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute product template: %v", err)
 	}
@@ -99,10 +102,13 @@ func (s *Store) handleAdminViewOrder(ctx context.Context, _ clientintf.UserID,
 		return nil, err
 	}
 
-	orderDir := filepath.Join(s.root, ordersDir, uid.String())
-	orderFname := filepath.Join(orderDir, orderFnamePattern.FilenameFor(uint64(oid)))
+	orderFname := orderKey(uid, oid)
 	var order Order
-	if err := jsonfile.Read(orderFname, &order); err != nil {
+	if err := s.backend.Read(orderFname, &order); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadOrderShipAddr(&order); err != nil {
 		return nil, err
 	}
 
@@ -113,10 +119,17 @@ func (s *Store) handleAdminViewOrder(ctx context.Context, _ clientintf.UserID,
 		Order:    order,
 		UserNick: nick,
 	}
+	if ttp, ok := order.TimeToPayment(); ok {
+		tctx.TimeToPay = ttp.Round(time.Second)
+	}
+	if order.AssignedAdmin != nil {
+		tctx.AssignedNick, _ = s.c.UserNick(*order.AssignedAdmin)
+		tctx.AssignedNick = strescape.Nick(tctx.AssignedNick)
+	}
 
 	// Generate template.
 	w := &bytes.Buffer{}
-	err := s.tmpl.ExecuteTemplate(w, adminOrderTmplFile, tctx)
+	err := s.render.Render(w, adminOrderTmplFile, tctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute product template: %v", err)
 	}
@@ -153,10 +166,9 @@ func (s *Store) handleAdminAddOrderComment(ctx context.Context, _ clientintf.Use
 	if err := oid.FromString(request.Path[3]); err != nil {
 		return nil, err
 	}
-	orderDir := filepath.Join(s.root, ordersDir, uid.String())
-	orderFname := filepath.Join(orderDir, orderFnamePattern.FilenameFor(uint64(oid)))
+	orderFname := orderKey(uid, oid)
 	var order Order
-	if err := jsonfile.Read(orderFname, &order); err != nil {
+	if err := s.backend.Read(orderFname, &order); err != nil {
 		return nil, err
 	}
 
@@ -168,7 +180,7 @@ func (s *Store) handleAdminAddOrderComment(ctx context.Context, _ clientintf.Use
 	})
 
 	// Save order.
-	if err := jsonfile.Write(orderFname, &order, s.log); err != nil {
+	if err := s.writeDoc(orderFname, &order); err != nil {
 		return nil, err
 	}
 
@@ -185,7 +197,7 @@ func (s *Store) handleAdminAddOrderComment(ctx context.Context, _ clientintf.Use
 
 }
 
-func (s *Store) handleAdminUpdateOrderStatus(ctx context.Context, _ clientintf.UserID,
+func (s *Store) handleAdminUpdateOrderStatus(ctx context.Context, admin clientintf.UserID,
 	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
 
 	s.mtx.Lock()
@@ -205,33 +217,101 @@ func (s *Store) handleAdminUpdateOrderStatus(ctx context.Context, _ clientintf.U
 	if err := oid.FromString(request.Path[3]); err != nil {
 		return nil, err
 	}
-	orderDir := filepath.Join(s.root, ordersDir, uid.String())
-	orderFname := filepath.Join(orderDir, orderFnamePattern.FilenameFor(uint64(oid)))
-	var order Order
-	if err := jsonfile.Read(orderFname, &order); err != nil {
-		return nil, err
-	}
 
-	// Modify Status.
-	order.Status = OrderStatus(request.Path[4])
+	status := OrderStatus(request.Path[4])
 
-	// Save order.
-	if err := jsonfile.Write(orderFname, &order, s.log); err != nil {
-		return nil, err
+	// Shipped orders may include the tracking number of the shipment.
+	var formData struct {
+		Tracking string `json:"tracking"`
+	}
+	if len(request.Data) > 0 {
+		if err := json.Unmarshal(request.Data, &formData); err != nil {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data:   []byte(err.Error()),
+			}, nil
+		}
+	}
+	var update func(*Order)
+	if tracking := strings.TrimSpace(formData.Tracking); tracking != "" && status == StatusShipped {
+		update = func(order *Order) { order.TrackingNumber = tracking }
 	}
 
-	if s.cfg.StatusChanged != nil {
-		msg := fmt.Sprintf("Your order %s/%s changed to status %s",
-			order.User.ShortLogID(), order.ID, order.Status)
-		s.cfg.StatusChanged(&order, msg)
+	// Modify Status.
+	order, err := s.updateOrderStatusWith(uid, oid, status, &admin, update)
+	if errors.Is(err, ErrInvalidStatusTransition) {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(err.Error()),
+		}, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	s.notifyStatusChanged(order)
 
 	// Generate template.
 	w := &bytes.Buffer{}
 	w.WriteString("# Order Status Updated\n\n")
+	w.WriteString(fmt.Sprintf("Order status changed to %s\n\n", order.Status))
 	w.WriteString(fmt.Sprintf("[Back to Order](/admin/order/%s/%s)\n\n", uid, oid))
 	return &rpc.RMFetchResourceReply{
 		Data:   w.Bytes(),
 		Status: rpc.ResourceStatusOk,
 	}, nil
 }
+
+func (s *Store) handleAdminAckOrder(ctx context.Context, admin clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if len(request.Path) < 4 {
+		return nil, fmt.Errorf("path has < 4 elements")
+	}
+
+	// Load order.
+	var uid clientintf.UserID
+	if err := uid.FromString(request.Path[2]); err != nil {
+		return nil, err
+	}
+	var oid OrderID
+	if err := oid.FromString(request.Path[3]); err != nil {
+		return nil, err
+	}
+
+	orderFname := orderKey(uid, oid)
+	var order Order
+	if err := s.backend.Read(orderFname, &order); err != nil {
+		return nil, err
+	}
+
+	// Routing admins may only acknowledge the orders assigned to them.
+	if s.adminRole(admin) == RoleRouting &&
+		(order.AssignedAdmin == nil || *order.AssignedAdmin != admin) {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusForbidden,
+			Data:   []byte("Order is not assigned to you"),
+		}, nil
+	}
+
+	if order.AckedTS == nil {
+		now := time.Now()
+		order.AckedBy = &admin
+		order.AckedTS = &now
+		if err := s.writeDoc(orderFname, &order); err != nil {
+			return nil, err
+		}
+		s.log.Infof("Order %s/%s acknowledged by admin %s",
+			uid.ShortLogID(), order.ID, admin.ShortLogID())
+	}
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Order acknowledged\n\n")
+	w.WriteString(fmt.Sprintf("[Back to Order](/admin/order/%s/%s)\n\n",
+		uid, order.ID))
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
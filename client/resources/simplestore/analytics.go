@@ -0,0 +1,466 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+const (
+	// defaultAnalyticsDays is the number of days covered by the analytics
+	// page when no period is specified.
+	defaultAnalyticsDays = 30
+
+	// maxAnalyticsDays is the max number of days that may be requested in
+	// the analytics page.
+	maxAnalyticsDays = 366
+
+	// defaultTopSKUs is the number of products listed in the top SKUs of
+	// the analytics page.
+	defaultTopSKUs = 10
+)
+
+// paymentAmountBuckets are the upper bounds (in units of the store currency)
+// of the ranges of order totals of the payment statistics. The last range has
+// no upper bound.
+var paymentAmountBuckets = []Money{1000, 5000, 10000, 50000}
+
+// paymentDelayBuckets are the upper bounds of the ranges of the time to pay of
+// the payment statistics. The last range has no upper bound.
+var paymentDelayBuckets = []time.Duration{
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	24 * time.Hour,
+}
+
+// DaySales are the sales of a single (UTC) day.
+type DaySales struct {
+	// Day is the start of the day.
+	Day time.Time
+
+	// Orders is the number of orders counted as sales placed on the day.
+	Orders int
+
+	// Totals are the total amounts of the sales in each currency and DCR
+	// is their total amount in DCR.
+	Totals map[string]Money
+	DCR    dcrutil.Amount
+}
+
+// SKUSales are the sales of a single product.
+type SKUSales struct {
+	SKU   string
+	Title string
+
+	// Quantity is the number of units sold and Orders the number of orders
+	// that included the product.
+	Quantity uint64
+	Orders   int
+
+	// Totals are the total amounts of the sales of the product in each
+	// currency and DCR is their total amount in DCR (converted with the
+	// exchange rate of each order).
+	Totals map[string]Money
+	DCR    dcrutil.Amount
+}
+
+// PaymentStats are the statistics of the payment of a set of orders.
+type PaymentStats struct {
+	// Paid is the number of paid orders, Abandoned the number of orders
+	// that expired or were canceled without being paid and Pending the
+	// number of orders still waiting for payment.
+	Paid      int
+	Abandoned int
+	Pending   int
+
+	// MedianTimeToPay, MeanTimeToPay and P90TimeToPay are the median,
+	// mean and 90th percentile of the time between placing and paying the
+	// paid orders.
+	MedianTimeToPay time.Duration
+	MeanTimeToPay   time.Duration
+	P90TimeToPay    time.Duration
+}
+
+// AbandonmentRate returns the ratio of abandoned orders to the resolved (paid
+// or abandoned) orders, or zero if no orders were resolved.
+func (ps *PaymentStats) AbandonmentRate() float64 {
+	if ps.Paid+ps.Abandoned == 0 {
+		return 0
+	}
+	return float64(ps.Abandoned) / float64(ps.Paid+ps.Abandoned)
+}
+
+// AmountPaymentStats are the payment statistics of the orders with a total in
+// the [Min, Max) range. A zero Max means no upper bound.
+type AmountPaymentStats struct {
+	Min Money
+	Max Money
+	PaymentStats
+}
+
+// DelayPayments is the number of orders paid within the [Min, Max) time after
+// being placed. A zero Max means no upper bound.
+type DelayPayments struct {
+	Min    time.Duration
+	Max    time.Duration
+	Orders int
+}
+
+// paymentStatsBuilder accumulates the payment statistics of orders.
+type paymentStatsBuilder struct {
+	stats  PaymentStats
+	delays []time.Duration
+}
+
+// add accounts for the order in the statistics.
+func (b *paymentStatsBuilder) add(order *Order) {
+	if ttp, ok := order.TimeToPayment(); ok {
+		b.stats.Paid++
+		b.delays = append(b.delays, ttp)
+		return
+	}
+	switch {
+	case order.Status == StatusExpired, order.Status == StatusCanceled:
+		b.stats.Abandoned++
+	case order.CanCancel():
+		b.stats.Pending++
+	}
+}
+
+// build returns the accumulated statistics.
+func (b *paymentStatsBuilder) build() PaymentStats {
+	res := b.stats
+	if len(b.delays) == 0 {
+		return res
+	}
+	sort.Slice(b.delays, func(i, j int) bool { return b.delays[i] < b.delays[j] })
+	var sum time.Duration
+	for _, d := range b.delays {
+		sum += d
+	}
+	n := len(b.delays)
+	res.MeanTimeToPay = sum / time.Duration(n)
+	if n%2 == 1 {
+		res.MedianTimeToPay = b.delays[n/2]
+	} else {
+		res.MedianTimeToPay = (b.delays[n/2-1] + b.delays[n/2]) / 2
+	}
+	res.P90TimeToPay = b.delays[(n*9+9)/10-1]
+	return res
+}
+
+// TimeToPayment returns the time between placing and paying the order. It
+// returns false if the order was not paid.
+func (order *Order) TimeToPayment() (time.Duration, bool) {
+	if order.PaidTS == nil {
+		return 0, false
+	}
+	ttp := order.PaidTS.Sub(order.PlacedTS)
+	if ttp < 0 {
+		ttp = 0
+	}
+	return ttp, true
+}
+
+// Analytics are the aggregated statistics of the orders of the store placed
+// in a period.
+type Analytics struct {
+	Since time.Time
+	Until time.Time
+
+	// CartsCreated is the number of carts created (i.e. first items added
+	// to an empty cart) and OrdersPlaced the number of orders placed in
+	// the period, in any status.
+	CartsCreated int
+	OrdersPlaced int
+
+	// Sales is the number of orders counted as sales (i.e. orders that were
+	// paid and not canceled).
+	Sales int
+
+	// Totals are the total amounts of the sales in each currency and DCR
+	// is their total amount in DCR.
+	Totals map[string]Money
+	DCR    dcrutil.Amount
+
+	// Days are the sales of each day of the period with at least one sale,
+	// in chronological order.
+	Days []DaySales
+
+	// TopSKUs are the best selling products, sorted by the number of units
+	// sold.
+	TopSKUs []SKUSales
+
+	// Payments are the payment statistics of the orders placed in the
+	// period, excluding orders paid with tips (which are paid in parts).
+	Payments PaymentStats
+
+	// PaymentsByAmount are the payment statistics of the orders in the
+	// store currency, split by their total amount.
+	PaymentsByAmount []AmountPaymentStats
+
+	// PaymentDelays is the distribution of the time to pay of the paid
+	// orders.
+	PaymentDelays []DelayPayments
+}
+
+// Conversion returns the ratio of orders placed to carts created in the
+// period, or zero if no carts were created.
+func (a *Analytics) Conversion() float64 {
+	if a.CartsCreated == 0 {
+		return 0
+	}
+	return float64(a.OrdersPlaced) / float64(a.CartsCreated)
+}
+
+// orderDCR returns the amount in DCR of the order. This is the paid amount for
+// paid orders or the quoted amount otherwise.
+func orderDCR(order *Order) dcrutil.Amount {
+	if order.PaidAmount > 0 {
+		return order.PaidAmount
+	}
+	return order.TotalDCR()
+}
+
+// toDCR converts the amount to DCR using the exchange rate of the order.
+func (order *Order) toDCR(v Money) dcrutil.Amount {
+	amount, _ := v.ToDCR(order.ExchangeRate)
+	return amount
+}
+
+// countCartsCreated returns the number of carts created in the [since, until)
+// interval, as recorded in the event log.
+func (s *Store) countCartsCreated(since, until time.Time) (int, error) {
+	s.events.mtx.Lock()
+	defer s.events.mtx.Unlock()
+
+	var n int
+	_, err := readEventLog(s.events.fname, func(e *EventLogEntry) error {
+		if e.Type == EventCartCreated && !e.Timestamp.Before(since) &&
+			e.Timestamp.Before(until) {
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Analytics aggregates the orders placed in the [since, until) interval into
+// the sales per day, the topN best selling products (or all sold products if
+// topN <= 0), the conversion of carts into orders and the revenue in the store
+// currency and in DCR. If until is the zero time, orders up to the current
+// time are included.
+func (s *Store) Analytics(since, until time.Time, topN int) (*Analytics, error) {
+	if until.IsZero() {
+		until = time.Now()
+	}
+	if !until.After(since) {
+		return nil, fmt.Errorf("end of period %s is not after its start %s",
+			until, since)
+	}
+
+	s.mtx.Lock()
+	orders, err := s.loadAllOrders()
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Analytics{
+		Since:  since,
+		Until:  until,
+		Totals: make(salesTotals),
+	}
+	res.CartsCreated, err = s.countCartsCreated(since, until)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read event log: %v", err)
+	}
+
+	currency := s.currency()
+	var payments paymentStatsBuilder
+	amountPayments := make([]paymentStatsBuilder, len(paymentAmountBuckets)+1)
+	res.PaymentDelays = make([]DelayPayments, len(paymentDelayBuckets)+1)
+	for i := range res.PaymentDelays {
+		if i > 0 {
+			res.PaymentDelays[i].Min = paymentDelayBuckets[i-1]
+		}
+		if i < len(paymentDelayBuckets) {
+			res.PaymentDelays[i].Max = paymentDelayBuckets[i]
+		}
+	}
+
+	days := make(map[time.Time]*DaySales)
+	skus := make(map[string]*SKUSales)
+	for _, order := range orders {
+		if order.PlacedTS.Before(since) || !order.PlacedTS.Before(until) {
+			continue
+		}
+		res.OrdersPlaced++
+
+		if order.PayType != PayTypeTip {
+			payments.add(order)
+			if order.CurrencyCode() == currency {
+				total := order.Total()
+				i := sort.Search(len(paymentAmountBuckets), func(i int) bool {
+					return total < paymentAmountBuckets[i]
+				})
+				amountPayments[i].add(order)
+			}
+			if ttp, ok := order.TimeToPayment(); ok {
+				i := sort.Search(len(paymentDelayBuckets), func(i int) bool {
+					return ttp < paymentDelayBuckets[i]
+				})
+				res.PaymentDelays[i].Orders++
+			}
+		}
+		if !order.Status.isSale() {
+			continue
+		}
+
+		dcr := orderDCR(order)
+		res.Sales++
+		salesTotals(res.Totals).add(order.Currency, order.Total())
+		res.DCR += dcr
+
+		placed := order.PlacedTS.UTC()
+		day := time.Date(placed.Year(), placed.Month(), placed.Day(), 0, 0, 0, 0, time.UTC)
+		ds := days[day]
+		if ds == nil {
+			ds = &DaySales{Day: day, Totals: make(salesTotals)}
+			days[day] = ds
+		}
+		ds.Orders++
+		salesTotals(ds.Totals).add(order.Currency, order.Total())
+		ds.DCR += dcr
+
+		for _, item := range order.Cart.Items {
+			ss := skus[item.Product.SKU]
+			if ss == nil {
+				ss = &SKUSales{
+					SKU:    item.Product.SKU,
+					Title:  item.Product.Title,
+					Totals: make(salesTotals),
+				}
+				skus[item.Product.SKU] = ss
+			}
+			total := item.Total()
+			ss.Quantity += uint64(item.Quantity)
+			ss.Orders++
+			salesTotals(ss.Totals).add(order.Currency, total)
+			ss.DCR += order.toDCR(total)
+		}
+	}
+
+	res.Days = make([]DaySales, 0, len(days))
+	for _, ds := range days {
+		res.Days = append(res.Days, *ds)
+	}
+	sort.Slice(res.Days, func(i, j int) bool {
+		return res.Days[i].Day.Before(res.Days[j].Day)
+	})
+
+	res.TopSKUs = make([]SKUSales, 0, len(skus))
+	for _, ss := range skus {
+		res.TopSKUs = append(res.TopSKUs, *ss)
+	}
+	sort.Slice(res.TopSKUs, func(i, j int) bool {
+		a, b := &res.TopSKUs[i], &res.TopSKUs[j]
+		if a.Quantity != b.Quantity {
+			return a.Quantity > b.Quantity
+		}
+		return a.SKU < b.SKU
+	})
+	if topN > 0 && len(res.TopSKUs) > topN {
+		res.TopSKUs = res.TopSKUs[:topN]
+	}
+
+	res.Payments = payments.build()
+	res.PaymentsByAmount = make([]AmountPaymentStats, len(amountPayments))
+	for i := range amountPayments {
+		aps := &res.PaymentsByAmount[i]
+		if i > 0 {
+			aps.Min = paymentAmountBuckets[i-1]
+		}
+		if i < len(paymentAmountBuckets) {
+			aps.Max = paymentAmountBuckets[i]
+		}
+		aps.PaymentStats = amountPayments[i].build()
+	}
+	return res, nil
+}
+
+type adminAnalyticsContext struct {
+	*Analytics
+
+	// Period is the number of days of the period and Periods are the
+	// alternative periods (in days) linked in the page.
+	Period  int
+	Periods []int
+}
+
+// FormatTotals formats the totals in their currencies, sorted by currency.
+func (ctx *adminAnalyticsContext) FormatTotals(totals map[string]Money) []string {
+	return salesTotals(totals).List()
+}
+
+// Pct formats the ratio as a percentage.
+func (ctx *adminAnalyticsContext) Pct(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
+
+// Duration formats the duration rounded to seconds.
+func (ctx *adminAnalyticsContext) Duration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// ConversionPct returns the conversion of carts into orders as a percentage.
+func (ctx *adminAnalyticsContext) ConversionPct() string {
+	return fmt.Sprintf("%.1f%%", ctx.Conversion()*100)
+}
+
+func (s *Store) handleAdminAnalytics(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	days := defaultAnalyticsDays
+	if len(request.Path) > 2 {
+		var err error
+		days, err = strconv.Atoi(request.Path[2])
+		if err != nil || days < 1 || days > maxAnalyticsDays {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data: []byte(fmt.Sprintf("period must be between 1 and %d days",
+					maxAnalyticsDays)),
+			}, nil
+		}
+	}
+
+	until := time.Now()
+	since := until.Add(-time.Duration(days) * 24 * time.Hour)
+	analytics, err := s.Analytics(since, until, defaultTopSKUs)
+	if err != nil {
+		return nil, err
+	}
+
+	tctx := &adminAnalyticsContext{
+		Analytics: analytics,
+		Period:    days,
+		Periods:   []int{7, 30, 90, 365},
+	}
+	w := &bytes.Buffer{}
+	err = s.render.Render(w, adminAnalyticsTmplFile, tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute admin analytics template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
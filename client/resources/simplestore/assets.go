@@ -0,0 +1,119 @@
+package simplestore
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/mdembeds"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	// assetsDir is the dir, relative to the store root, with the static
+	// files (product photos, etc) served by the store.
+	assetsDir = "assets"
+
+	// maxAssetSize is the max size of assets served by the store and
+	// embedded in its pages.
+	maxAssetSize = 4 * 1024 * 1024
+
+	// defaultAssetContentType is the content type of assets with an
+	// unknown extension.
+	defaultAssetContentType = "application/octet-stream"
+)
+
+// assetContentType returns the content type of the asset file, based on its
+// extension.
+func assetContentType(fname string) string {
+	ext := strings.ToLower(path.Ext(fname))
+	if ext == ".md" {
+		return "text/markdown"
+	}
+	typ := mime.TypeByExtension(ext)
+	if typ == "" {
+		return defaultAssetContentType
+	}
+	if i := strings.IndexByte(typ, ';'); i > -1 {
+		typ = typ[:i]
+	}
+	return typ
+}
+
+// assetPath returns the path in the filesystem of the asset with the given
+// name (a slash separated path relative to the assets dir). It fails if the
+// name does not refer to a file inside the assets dir.
+func (s *Store) assetPath(name string) (string, error) {
+	name = strings.TrimPrefix(name, "/")
+	if name == "" || strings.Contains(name, "\\") {
+		return "", fmt.Errorf("invalid asset name %q", name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || part == "." || part == ".." || strings.HasPrefix(part, ".") {
+			return "", fmt.Errorf("invalid asset name %q", name)
+		}
+	}
+	return filepath.Join(s.root, assetsDir, filepath.FromSlash(name)), nil
+}
+
+// readAsset reads the asset with the given name.
+func (s *Store) readAsset(name string) ([]byte, error) {
+	fname, err := s.assetPath(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(fname)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, fmt.Errorf("asset %q is not a regular file", name)
+	}
+	if fi.Size() > maxAssetSize {
+		return nil, fmt.Errorf("asset %q is larger than the max asset size %d",
+			name, maxAssetSize)
+	}
+	return os.ReadFile(fname)
+}
+
+// assetEmbed returns the embed of the asset with the given name, to include in
+// the store pages, or an empty string if the asset cannot be read.
+func (s *Store) assetEmbed(name, alt string) string {
+	data, err := s.readAsset(name)
+	if err != nil {
+		s.log.Warnf("Unable to read asset %q: %v", name, err)
+		return ""
+	}
+	return mdembeds.EmbeddedArgs{
+		Typ:  assetContentType(name),
+		Data: data,
+		Alt:  url.PathEscape(alt),
+	}.String()
+}
+
+// handleAsset serves the files of the assets dir as /assets/<name>. The
+// content type of the file is sent in the meta of the reply.
+func (s *Store) handleAsset(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	name := strings.Join(request.Path[1:], "/")
+	data, err := s.readAsset(name)
+	if err != nil {
+		s.log.Debugf("Unable to serve asset %q to %s: %v", name, uid, err)
+		return s.handleNotFound(ctx, uid, request)
+	}
+
+	return &rpc.RMFetchResourceReply{
+		Data:   data,
+		Status: rpc.ResourceStatusOk,
+		Meta: map[string]string{
+			rpc.ResourceMetaContentType: assetContentType(name),
+		},
+	}, nil
+}
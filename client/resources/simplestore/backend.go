@@ -0,0 +1,209 @@
+package simplestore
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+)
+
+// ErrNotFound is returned by store backends when reading a document that does
+// not exist.
+var ErrNotFound = jsonfile.ErrNotFound
+
+// StoreBackend is the storage of the state of a store (carts, orders, stock
+// levels, etc). The state is stored as JSON encoded documents identified by
+// slash separated keys (for example, "orders/<uid>/order-00000001.json").
+//
+// The default backend stores each document as a JSON file under the store
+// root, with the key as its relative path.
+type StoreBackend interface {
+	// Read decodes the document with the given key into data. It returns
+	// an error that matches ErrNotFound if the document does not exist.
+	Read(key string, data interface{}) error
+
+	// List returns the keys of the documents that match the pattern (as
+	// defined by path.Match), sorted by key.
+	List(pattern string) ([]string, error)
+
+	// NewBatch starts a new batch of writes and removals that are
+	// committed atomically.
+	NewBatch() StoreBatch
+
+	// NextOrderID atomically allocates the ID of the next order of the
+	// user.
+	NextOrderID(uid clientintf.UserID) (OrderID, error)
+
+	// LockUser locks the state of the user (cart and orders) and returns
+	// the function that unlocks it.
+	LockUser(uid clientintf.UserID) func()
+}
+
+// StoreBatch is a set of document writes and removals that are committed
+// atomically: after a crash, either all or none of them are applied.
+type StoreBatch interface {
+	// Write adds the writing of data as the document with the given key.
+	Write(key string, data interface{})
+
+	// Remove adds the removal of the document with the given key. It is
+	// not an error if the document does not exist.
+	Remove(key string)
+
+	// Commit commits the batch.
+	Commit() error
+}
+
+// userLocks is a set of per-user mutexes.
+type userLocks struct {
+	mtx   sync.Mutex
+	locks map[clientintf.UserID]*sync.Mutex
+}
+
+// lock locks the mutex of the user and returns the function that unlocks it.
+func (ul *userLocks) lock(uid clientintf.UserID) func() {
+	ul.mtx.Lock()
+	if ul.locks == nil {
+		ul.locks = make(map[clientintf.UserID]*sync.Mutex)
+	}
+	l := ul.locks[uid]
+	if l == nil {
+		l = new(sync.Mutex)
+		ul.locks[uid] = l
+	}
+	ul.mtx.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// jsonBackend is the default store backend, which stores documents as JSON
+// files under the store root, written through the store journal.
+type jsonBackend struct {
+	root    string
+	journal *jsonfile.Journal
+	users   userLocks
+
+	// orderIDsMtx guards the allocation of order IDs.
+	orderIDsMtx sync.Mutex
+	lastIDs     map[clientintf.UserID]OrderID
+}
+
+func newJSONBackend(root string, journal *jsonfile.Journal) *jsonBackend {
+	return &jsonBackend{
+		root:    root,
+		journal: journal,
+		lastIDs: make(map[clientintf.UserID]OrderID),
+	}
+}
+
+func (jb *jsonBackend) fname(key string) string {
+	return filepath.Join(jb.root, filepath.FromSlash(key))
+}
+
+// Read is part of the StoreBackend interface.
+func (jb *jsonBackend) Read(key string, data interface{}) error {
+	return jsonfile.Read(jb.fname(key), data)
+}
+
+// List is part of the StoreBackend interface.
+func (jb *jsonBackend) List(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(jb.fname(pattern))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(matches))
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		rel, err := filepath.Rel(jb.root, m)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, filepath.ToSlash(rel))
+	}
+	return keys, nil
+}
+
+// NewBatch is part of the StoreBackend interface.
+func (jb *jsonBackend) NewBatch() StoreBatch {
+	return &jsonBatch{jb: jb, batch: jb.journal.NewBatch()}
+}
+
+// NextOrderID is part of the StoreBackend interface.
+func (jb *jsonBackend) NextOrderID(uid clientintf.UserID) (OrderID, error) {
+	jb.orderIDsMtx.Lock()
+	defer jb.orderIDsMtx.Unlock()
+
+	// The last allocated ID is tracked in memory, so that IDs allocated
+	// for orders not yet written are not allocated again.
+	dir := filepath.Join(jb.root, ordersDir, uid.String())
+	last, err := orderFnamePattern.Last(dir)
+	if err != nil {
+		return 0, err
+	}
+	id := OrderID(last.ID)
+	if id < jb.lastIDs[uid] {
+		id = jb.lastIDs[uid]
+	}
+	id++
+	jb.lastIDs[uid] = id
+	return id, nil
+}
+
+// LockUser is part of the StoreBackend interface.
+func (jb *jsonBackend) LockUser(uid clientintf.UserID) func() {
+	return jb.users.lock(uid)
+}
+
+type jsonBatch struct {
+	jb    *jsonBackend
+	batch *jsonfile.Batch
+}
+
+func (b *jsonBatch) Write(key string, data interface{}) {
+	b.batch.Write(b.jb.fname(key), data)
+}
+
+func (b *jsonBatch) Remove(key string) {
+	b.batch.Remove(b.jb.fname(key))
+}
+
+func (b *jsonBatch) Commit() error {
+	return b.batch.Commit()
+}
+
+// Keys of the documents of the store state.
+
+func cartKey(uid clientintf.UserID) string {
+	return path.Join(cartsDir, uid.String())
+}
+
+func userOrdersPattern(uid clientintf.UserID) string {
+	return path.Join(ordersDir, uid.String(), "*.json")
+}
+
+func orderKey(uid clientintf.UserID, id OrderID) string {
+	return path.Join(ordersDir, uid.String(), orderFnamePattern.FilenameFor(uint64(id)))
+}
+
+// allOrdersPattern matches the orders of all users.
+var allOrdersPattern = path.Join(ordersDir, "*", "*.json")
+
+// writeDoc writes a single document of the store state.
+func (s *Store) writeDoc(key string, data interface{}) error {
+	b := s.backend.NewBatch()
+	b.Write(key, data)
+	return b.Commit()
+}
+
+// removeDoc removes a single document of the store state.
+func (s *Store) removeDoc(key string) error {
+	b := s.backend.NewBatch()
+	b.Remove(key)
+	return b.Commit()
+}
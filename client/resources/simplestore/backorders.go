@@ -0,0 +1,181 @@
+package simplestore
+
+import (
+	"fmt"
+	"sort"
+)
+
+// OnBackorder returns true if the product is out of stock but may still be
+// ordered, to be shipped once it is back in stock.
+func (prod *Product) OnBackorder() bool {
+	return prod.Backorder && prod.Stock != nil && *prod.Stock < 1
+}
+
+// covers returns true if the tracked stock levels have enough units for all
+// the given units of stock.
+func (levels stockLevels) covers(units []stockUnit) bool {
+	for _, u := range units {
+		if n, ok := levels[u.sku]; ok && n < u.units {
+			return false
+		}
+	}
+	return true
+}
+
+// take takes the given units of stock from the tracked stock levels. It
+// returns true if any of the levels changed.
+func (levels stockLevels) take(units []stockUnit) bool {
+	var changed bool
+	for _, u := range units {
+		if n, ok := levels[u.sku]; ok {
+			levels[u.sku] = n - u.units
+			changed = true
+		}
+	}
+	return changed
+}
+
+// splitBackorderable splits the items between the ones of products that may
+// be backordered and the others.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) splitBackorderable(items []*CartItem) (stocked, backorderable []*CartItem) {
+	for _, item := range items {
+		if prod, ok := s.product(item.Product.SKU); ok && prod.Backorder {
+			backorderable = append(backorderable, item)
+		} else {
+			stocked = append(stocked, item)
+		}
+	}
+	return stocked, backorderable
+}
+
+// takeOrderStock takes the units of the items of the order from a copy of
+// the stock levels. Items of products that may be backordered and do not have
+// enough units in stock are recorded as backordered in the order instead. It
+// returns the new stock levels and whether they changed, or the SKU without
+// enough units in stock for the other items.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) takeOrderStock(order *Order) (stockLevels, bool, string) {
+	order.Backordered = nil
+	stocked, backorderable := s.splitBackorderable(order.Cart.Items)
+	if sku := s.stock.shortage(stocked); sku != "" {
+		return nil, false, sku
+	}
+
+	// Items that cannot be backordered take precedence over the ones that
+	// can.
+	levels := s.stock.clone()
+	var changed bool
+	for _, item := range stocked {
+		changed = levels.take(item.stockUnits()) || changed
+	}
+	for _, item := range backorderable {
+		units := item.stockUnits()
+		if levels.covers(units) {
+			changed = levels.take(units) || changed
+		} else {
+			order.backorder(units)
+		}
+	}
+	return levels, changed, ""
+}
+
+// IsBackordered returns true if some of the items of the order are waiting
+// for stock.
+func (order *Order) IsBackordered() bool {
+	return len(order.Backordered) > 0
+}
+
+// backorder records the units of stock as owed to the order.
+func (order *Order) backorder(units []stockUnit) {
+	if order.Backordered == nil {
+		order.Backordered = make(map[string]int64, len(units))
+	}
+	for _, u := range units {
+		order.Backordered[u.sku] += u.units
+	}
+}
+
+// backorderedUnits returns the units of stock owed to the order, sorted by
+// SKU.
+func (order *Order) backorderedUnits() []stockUnit {
+	res := make([]stockUnit, 0, len(order.Backordered))
+	for sku, n := range order.Backordered {
+		res = append(res, stockUnit{sku, n})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].sku < res[j].sku })
+	return res
+}
+
+// fillBackorders takes the units owed to backordered orders from the stock,
+// in the order the orders were placed, for the orders that can be completely
+// filled by the current stock levels. Backordered orders that were already
+// paid go back to the paid status, and the buyers are notified that their
+// items are in stock.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) fillBackorders() {
+	orders, err := s.loadAllOrders()
+	if err != nil {
+		s.log.Warnf("Unable to load orders to fill backorders: %v", err)
+		return
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].PlacedTS.Before(orders[j].PlacedTS)
+	})
+
+	for _, order := range orders {
+		if !order.IsBackordered() || order.Status.IsFinal() ||
+			order.Status == StatusExpired {
+			continue
+		}
+		units := order.backorderedUnits()
+		if !s.stock.covers(units) {
+			continue
+		}
+
+		levels := s.stock.clone()
+		stockChanged := levels.take(units)
+		order.Backordered = nil
+		if order.Status == StatusBackordered {
+			if err := order.setStatus(StatusPaid, nil); err != nil {
+				s.log.Warnf("Unable to change status of backordered "+
+					"order %s/%s: %v", order.User.ShortLogID(),
+					order.ID, err)
+				continue
+			}
+		}
+
+		batch := s.backend.NewBatch()
+		batch.Write(orderKey(order.User, order.ID), order)
+		if stockChanged {
+			batch.Write(stockFile, levels)
+		}
+		if err := batch.Commit(); err != nil {
+			s.log.Warnf("Unable to fill backordered order %s/%s: %v",
+				order.User.ShortLogID(), order.ID, err)
+			continue
+		}
+		if stockChanged {
+			s.stock = levels
+			s.refreshStock()
+		}
+		s.log.Infof("Filled backordered order %s/%s", order.User.ShortLogID(),
+			order.ID)
+		if order.Status == StatusPaid {
+			s.logOrderEvent(EventOrderStatus, nil, order, map[string]string{
+				"from": string(StatusBackordered),
+				"to":   string(StatusPaid),
+			})
+		}
+
+		msg := fmt.Sprintf("The backordered items of your order %s/%s are "+
+			"now in stock", order.User.ShortLogID(), order.ID)
+		receipt := s.sendOrderReceipt(order, msg)
+		if s.cfg.StatusChanged != nil {
+			s.cfg.StatusChanged(order, receipt)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package simplestore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// boltDocsBucket is the bucket with the documents of the store state,
+	// keyed by document key.
+	boltDocsBucket = []byte("docs")
+
+	// boltOrderIDsBucket is the bucket with the last order ID allocated
+	// to each user, keyed by user ID.
+	boltOrderIDsBucket = []byte("orderids")
+)
+
+// BoltBackend is a store backend that stores the state of the store in a
+// bbolt database.
+type BoltBackend struct {
+	db    *bolt.DB
+	users userLocks
+}
+
+// NewBoltBackend opens (creating if needed) the bbolt database file fname as
+// a store backend. The returned backend must be closed after the store is
+// done with it.
+func NewBoltBackend(fname string) (*BoltBackend, error) {
+	if err := os.MkdirAll(filepath.Dir(fname), 0o700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(fname, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open store db %s: %v", fname, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltDocsBucket, boltOrderIDsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltBackend{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (bb *BoltBackend) Close() error {
+	return bb.db.Close()
+}
+
+// Read is part of the StoreBackend interface.
+func (bb *BoltBackend) Read(key string, data interface{}) error {
+	return bb.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(boltDocsBucket).Get([]byte(key))
+		if v == nil {
+			return fmt.Errorf("%s: %w", key, ErrNotFound)
+		}
+		if err := json.Unmarshal(v, data); err != nil {
+			return fmt.Errorf("unable to decode %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// List is part of the StoreBackend interface.
+func (bb *BoltBackend) List(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	// Only the keys with the literal prefix of the pattern need to be
+	// checked.
+	prefix := pattern
+	if i := strings.IndexAny(pattern, `*?[\`); i > -1 {
+		prefix = pattern[:i]
+	}
+
+	var keys []string
+	err := bb.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltDocsBucket).Cursor()
+		p := []byte(prefix)
+		for k, _ := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, _ = c.Next() {
+			if ok, _ := path.Match(pattern, string(k)); ok {
+				keys = append(keys, string(k))
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// NewBatch is part of the StoreBackend interface.
+func (bb *BoltBackend) NewBatch() StoreBatch {
+	return &boltBatch{bb: bb}
+}
+
+// NextOrderID is part of the StoreBackend interface.
+func (bb *BoltBackend) NextOrderID(uid clientintf.UserID) (OrderID, error) {
+	var id OrderID
+	err := bb.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltOrderIDsBucket)
+		if v := bucket.Get(uid[:]); len(v) == 8 {
+			id = OrderID(binary.BigEndian.Uint64(v))
+		}
+		id++
+		var v [8]byte
+		binary.BigEndian.PutUint64(v[:], uint64(id))
+		return bucket.Put(uid[:], v[:])
+	})
+	return id, err
+}
+
+// LockUser is part of the StoreBackend interface.
+func (bb *BoltBackend) LockUser(uid clientintf.UserID) func() {
+	return bb.users.lock(uid)
+}
+
+type boltBatchOp struct {
+	key  string
+	data []byte // nil for removals
+	err  error
+}
+
+// boltBatch is a batch committed as a single bbolt transaction.
+type boltBatch struct {
+	bb  *BoltBackend
+	ops []boltBatchOp
+}
+
+func (b *boltBatch) Write(key string, data interface{}) {
+	v, err := json.Marshal(data)
+	if err != nil {
+		err = fmt.Errorf("unable to encode %s: %v", key, err)
+	}
+	b.ops = append(b.ops, boltBatchOp{key: key, data: v, err: err})
+}
+
+func (b *boltBatch) Remove(key string) {
+	b.ops = append(b.ops, boltBatchOp{key: key})
+}
+
+func (b *boltBatch) Commit() error {
+	for _, op := range b.ops {
+		if op.err != nil {
+			return op.err
+		}
+	}
+	return b.bb.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltDocsBucket)
+		for _, op := range b.ops {
+			var err error
+			if op.data == nil {
+				err = bucket.Delete([]byte(op.key))
+			} else {
+				err = bucket.Put([]byte(op.key), op.data)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,200 @@
+package simplestore
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/internal/testutils"
+	bolt "go.etcd.io/bbolt"
+)
+
+type boltTestData struct {
+	V int `json:"v"`
+}
+
+// TestBoltBackendRoundTrip tests writing, reading, listing and removing
+// documents and allocating order IDs, including after the database is
+// reopened.
+func TestBoltBackendRoundTrip(t *testing.T) {
+	fname := filepath.Join(testutils.TempTestDir(t, "boltbackend-"), "store.db")
+	bb, err := NewBoltBackend(fname)
+	assert.NilErr(t, err)
+
+	keyA := "carts/a"
+	keyB := "orders/u1/order-00000002.json"
+	keyC := "orders/u1/order-00000001.json"
+	keyD := "orders/u2/order-00000001.json"
+
+	b := bb.NewBatch()
+	b.Write(keyA, boltTestData{V: 1})
+	b.Write(keyB, boltTestData{V: 2})
+	b.Write(keyC, boltTestData{V: 3})
+	b.Write(keyD, boltTestData{V: 4})
+	assert.NilErr(t, b.Commit())
+
+	var got boltTestData
+	assert.NilErr(t, bb.Read(keyA, &got))
+	assert.DeepEqual(t, got.V, 1)
+	assert.NilErr(t, bb.Read(keyB, &got))
+	assert.DeepEqual(t, got.V, 2)
+	assert.ErrorIs(t, bb.Read("carts/b", &got), ErrNotFound)
+
+	// Listed keys are sorted and match the pattern.
+	keys, err := bb.List(allOrdersPattern)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, keys, []string{keyC, keyB, keyD})
+	keys, err = bb.List("orders/u1/*.json")
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, keys, []string{keyC, keyB})
+	keys, err = bb.List("carts/*")
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, keys, []string{keyA})
+	keys, err = bb.List("products/*")
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(keys), 0)
+	_, err = bb.List("[")
+	assert.NonNilErr(t, err)
+
+	// Overwrite and remove in the same batch. Removing a document that
+	// does not exist is not an error.
+	b = bb.NewBatch()
+	b.Write(keyA, boltTestData{V: 10})
+	b.Remove(keyB)
+	b.Remove("carts/b")
+	assert.NilErr(t, b.Commit())
+	assert.NilErr(t, bb.Read(keyA, &got))
+	assert.DeepEqual(t, got.V, 10)
+	assert.ErrorIs(t, bb.Read(keyB, &got), ErrNotFound)
+
+	// Order IDs are allocated per user.
+	var uid1, uid2 clientintf.UserID
+	uid1[0], uid2[0] = 0x01, 0x02
+	for want := OrderID(1); want <= 3; want++ {
+		id, err := bb.NextOrderID(uid1)
+		assert.NilErr(t, err)
+		assert.DeepEqual(t, id, want)
+	}
+	id, err := bb.NextOrderID(uid2)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, id, OrderID(1))
+
+	// The documents and order IDs persist after reopening the database.
+	assert.NilErr(t, bb.Close())
+	bb, err = NewBoltBackend(fname)
+	assert.NilErr(t, err)
+	defer bb.Close()
+	assert.NilErr(t, bb.Read(keyA, &got))
+	assert.DeepEqual(t, got.V, 10)
+	keys, err = bb.List(allOrdersPattern)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, keys, []string{keyC, keyD})
+	id, err = bb.NextOrderID(uid1)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, id, OrderID(4))
+}
+
+// TestBoltBackendBatchAtomic tests that a batch with a document that can't be
+// encoded is not applied at all.
+func TestBoltBackendBatchAtomic(t *testing.T) {
+	fname := filepath.Join(testutils.TempTestDir(t, "boltbackend-"), "store.db")
+	bb, err := NewBoltBackend(fname)
+	assert.NilErr(t, err)
+	defer bb.Close()
+
+	b := bb.NewBatch()
+	b.Write("carts/a", boltTestData{V: 1})
+	assert.NilErr(t, b.Commit())
+
+	b = bb.NewBatch()
+	b.Write("carts/b", boltTestData{V: 2})
+	b.Remove("carts/a")
+	b.Write("carts/c", make(chan int))
+	assert.NonNilErr(t, b.Commit())
+
+	var got boltTestData
+	assert.NilErr(t, bb.Read("carts/a", &got))
+	assert.DeepEqual(t, got.V, 1)
+	assert.ErrorIs(t, bb.Read("carts/b", &got), ErrNotFound)
+}
+
+// putBoltDoc stores raw data as the document with the given key.
+func putBoltDoc(t testing.TB, bb *BoltBackend, key string, data string) {
+	t.Helper()
+	err := bb.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltDocsBucket).Put([]byte(key), []byte(data))
+	})
+	assert.NilErr(t, err)
+}
+
+// getBoltDoc returns the raw data of the document with the given key.
+func getBoltDoc(t testing.TB, bb *BoltBackend, key string) string {
+	t.Helper()
+	var data string
+	err := bb.db.View(func(tx *bolt.Tx) error {
+		data = string(tx.Bucket(boltDocsBucket).Get([]byte(key)))
+		return nil
+	})
+	assert.NilErr(t, err)
+	return data
+}
+
+// TestBoltBackendMigration tests that documents saved in a bbolt backend with
+// older schema versions are migrated when the store is created and that a
+// store with documents saved by a newer version is not loaded.
+func TestBoltBackendMigration(t *testing.T) {
+	root := testutils.TempTestDir(t, "boltbackend-")
+	assert.NilErr(t, os.Mkdir(filepath.Join(root, "products"), 0o700))
+	bb, err := NewBoltBackend(filepath.Join(root, "store.db"))
+	assert.NilErr(t, err)
+	defer bb.Close()
+
+	// A cart saved before schema versions and Money amounts.
+	var uid clientintf.UserID
+	uid[0] = 0x01
+	cartKey := cartsDir + "/" + uid.String()
+	legacyCart := `{"items":[{"product":{"title":"Test Book","sku":"book01",` +
+		`"price":10},"quantity":2}],"discount_cents":150}`
+	putBoltDoc(t, bb, cartKey, legacyCart)
+
+	_, err = New(Config{Root: root, Backend: bb})
+	assert.NilErr(t, err)
+
+	// The cart and the schema were saved again with the current versions.
+	raw := getBoltDoc(t, bb, cartKey)
+	if !strings.Contains(raw, `"schema_version":1`) {
+		t.Fatalf("cart not saved with the current schema: %s", raw)
+	}
+	var cart Cart
+	assert.NilErr(t, bb.Read(cartKey, &cart))
+	assert.DeepEqual(t, cart.Discount, MoneyFromFloat(1.5))
+	assert.DeepEqual(t, cart.Total(), MoneyFromFloat(18.5))
+	var schema storeSchema
+	assert.NilErr(t, bb.Read(schemaFile, &schema))
+	assert.DeepEqual(t, schema, currentSchema())
+
+	// Documents saved by a newer version are detected when decoded, so
+	// the migration fails instead of skipping them.
+	uid[0] = 0x02
+	newerKey := cartsDir + "/" + uid.String()
+	putBoltDoc(t, bb, newerKey, `{"items":[],"schema_version":99}`)
+	assert.ErrorIs(t, bb.Read(newerKey, &cart), ErrNewerSchema)
+	b := bb.NewBatch()
+	b.Remove(schemaFile)
+	assert.NilErr(t, b.Commit())
+	_, err = New(Config{Root: root, Backend: bb})
+	assert.ErrorIs(t, err, ErrNewerSchema)
+
+	// Stores with documents saved by a newer version are not loaded.
+	newerSchema := currentSchema()
+	newerSchema.Carts = 99
+	data, err := json.Marshal(newerSchema)
+	assert.NilErr(t, err)
+	putBoltDoc(t, bb, schemaFile, string(data))
+	_, err = New(Config{Root: root, Backend: bb})
+	assert.ErrorIs(t, err, ErrNewerSchema)
+}
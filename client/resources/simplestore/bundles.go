@@ -0,0 +1,169 @@
+package simplestore
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// BundleItem is one of the products that compose a bundle.
+type BundleItem struct {
+	// SKU is the SKU of the product (or variant) included in the bundle.
+	SKU string `json:"sku"`
+
+	// Quantity is the number of units of the product included in the
+	// bundle.
+	Quantity uint32 `json:"quantity"`
+}
+
+// IsBundle returns true if the product is a bundle of other products.
+func (prod *Product) IsBundle() bool {
+	return len(prod.Bundle) > 0
+}
+
+// validateBundles ensures the components of the bundles among the products
+// exist and are not themselves bundles or products with variants.
+func validateBundles(products, variants map[string]*Product) error {
+	for _, prod := range products {
+		if !prod.IsBundle() {
+			continue
+		}
+		if prod.HasVariants() {
+			return fmt.Errorf("bundle %s cannot have variants", prod.SKU)
+		}
+		seen := make(map[string]bool, len(prod.Bundle))
+		for _, item := range prod.Bundle {
+			comp, ok := products[item.SKU]
+			if !ok {
+				comp, ok = variants[item.SKU]
+			}
+			switch {
+			case !ok:
+				return fmt.Errorf("bundle %s includes unknown "+
+					"SKU %q", prod.SKU, item.SKU)
+			case comp.IsBundle():
+				return fmt.Errorf("bundle %s includes bundle %s",
+					prod.SKU, item.SKU)
+			case comp.HasVariants():
+				return fmt.Errorf("bundle %s includes product %s "+
+					"which has variants (include a variant SKU "+
+					"instead)", prod.SKU, item.SKU)
+			case item.Quantity == 0:
+				return fmt.Errorf("bundle %s includes zero units "+
+					"of SKU %s", prod.SKU, item.SKU)
+			case seen[item.SKU]:
+				return fmt.Errorf("bundle %s includes SKU %s more "+
+					"than once", prod.SKU, item.SKU)
+			}
+			seen[item.SKU] = true
+		}
+	}
+	return nil
+}
+
+// stockUnit is a number of units of stock of a SKU.
+type stockUnit struct {
+	sku   string
+	units int64
+}
+
+// stockUnits returns the units of stock consumed by the item. Bundles consume
+// the stock of each of their components, while other products consume their
+// own stock.
+func (item *CartItem) stockUnits() []stockUnit {
+	if !item.Product.IsBundle() {
+		return []stockUnit{{item.Product.SKU, int64(item.Quantity)}}
+	}
+	res := make([]stockUnit, len(item.Product.Bundle))
+	for i, comp := range item.Product.Bundle {
+		res[i] = stockUnit{comp.SKU, int64(comp.Quantity) * int64(item.Quantity)}
+	}
+	return res
+}
+
+// shortage returns the first SKU (in alphabetical order) with a tracked stock
+// level lower than the total units consumed by the items, or an empty string
+// if all tracked SKUs have enough units in stock.
+func (levels stockLevels) shortage(items []*CartItem) string {
+	needed := make(map[string]int64)
+	for _, item := range items {
+		for _, u := range item.stockUnits() {
+			needed[u.sku] += u.units
+		}
+	}
+	skus := make([]string, 0, len(needed))
+	for sku := range needed {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+	for _, sku := range skus {
+		if n, ok := levels[sku]; ok && n < needed[sku] {
+			return sku
+		}
+	}
+	return ""
+}
+
+// skuOutOfStockReply returns the reply to a request for more units of the
+// SKU than are in stock.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) skuOutOfStockReply(sku string) *rpc.RMFetchResourceReply {
+	prod, ok := s.product(sku)
+	if !ok {
+		prod = &Product{SKU: sku, Title: sku}
+	}
+	return outOfStockReply(prod)
+}
+
+// applyBundleStock sets the stock of the bundles to the number of complete
+// bundles that may be assembled from the stock of their components. Bundles
+// with no components with limited stock have unlimited stock.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) applyBundleStock() {
+	for _, prod := range s.products {
+		if !prod.IsBundle() {
+			continue
+		}
+		prod.Stock = nil
+		for _, item := range prod.Bundle {
+			n, ok := s.stock[item.SKU]
+			if !ok {
+				continue
+			}
+			n /= int64(item.Quantity)
+			if n < 0 {
+				n = 0
+			}
+			if prod.Stock == nil || n < *prod.Stock {
+				prod.Stock = &n
+			}
+		}
+	}
+}
+
+// bundleComponent is a component of a bundle, as shown in its page.
+type bundleComponent struct {
+	*Product
+	Quantity uint32
+}
+
+// bundleComponents returns the components of the bundle and the sum of
+// their prices.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) bundleComponents(prod *Product) ([]bundleComponent, Money) {
+	var value Money
+	res := make([]bundleComponent, 0, len(prod.Bundle))
+	for _, item := range prod.Bundle {
+		comp, ok := s.product(item.SKU)
+		if !ok {
+			continue
+		}
+		res = append(res, bundleComponent{Product: comp, Quantity: item.Quantity})
+		value += comp.Price.Mul(int(item.Quantity))
+	}
+	return res, value
+}
@@ -0,0 +1,316 @@
+package simplestore
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"golang.org/x/exp/slices"
+)
+
+// categoryMetaFile is the name of the file, inside each dir of the products
+// dir, with the metadata of the category.
+const categoryMetaFile = "category.toml"
+
+// Category is a category of products. Categories are loaded from the dir tree
+// of the products dir: each dir is a category, with the products defined in
+// the product files of the dir and the subcategories defined by its subdirs.
+type Category struct {
+	// Path is the slash separated path of the category's dir, relative to
+	// the products dir. The root category has an empty path.
+	Path        string
+	Title       string
+	Description string
+
+	Products      []*Product
+	Subcategories []*Category
+}
+
+// Name returns the name of the category's dir.
+func (cat *Category) Name() string {
+	return path.Base(cat.Path)
+}
+
+type categoryMeta struct {
+	Title       string `toml:"title"`
+	Description string `toml:"description"`
+}
+
+// catalogDir are the contents loaded from a single dir of the products tree.
+type catalogDir struct {
+	meta     categoryMeta
+	products []*Product
+}
+
+// loadCatalogDir loads the category metadata and product files of a single
+// dir of the products tree. relPath is the slash separated path of the dir
+// relative to the products dir.
+func loadCatalogDir(dir, relPath string) (*catalogDir, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list product files: %v", err)
+	}
+
+	cd := &catalogDir{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".toml" {
+			continue
+		}
+		fname := filepath.Join(dir, entry.Name())
+		f, err := os.Open(fname)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load product file %s: %v",
+				fname, err)
+		}
+		dec := toml.NewDecoder(f)
+		if entry.Name() == categoryMetaFile {
+			err = dec.Decode(&cd.meta)
+		} else {
+			var prods productsFile
+			err = dec.Decode(&prods)
+			for _, prod := range prods.Products {
+				if prod.Disabled {
+					continue
+				}
+				prod.Category = relPath
+				cd.products = append(cd.products, prod)
+			}
+		}
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode product file %s: %v",
+				fname, err)
+		}
+	}
+	return cd, nil
+}
+
+// loadCatalogTree loads all dirs of the products tree starting at relPath
+// into dirs.
+func loadCatalogTree(prodDir, relPath string, dirs map[string]*catalogDir) error {
+	root := filepath.Join(prodDir, filepath.FromSlash(relPath))
+	return filepath.WalkDir(root, func(dir string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(prodDir, dir)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			rel = ""
+		}
+		cd, err := loadCatalogDir(dir, rel)
+		if err != nil {
+			return err
+		}
+		dirs[rel] = cd
+		return nil
+	})
+}
+
+// buildCatalog builds the product index and category tree from the loaded
+// dirs of the products tree.
+func buildCatalog(dirs map[string]*catalogDir) (map[string]*Product, *Category, error) {
+	relPaths := make([]string, 0, len(dirs))
+	for rel := range dirs {
+		relPaths = append(relPaths, rel)
+	}
+	sort.Strings(relPaths)
+
+	products := make(map[string]*Product)
+	categories := make(map[string]*Category, len(dirs))
+	root := &Category{}
+	categories[""] = root
+	for _, rel := range relPaths {
+		cd := dirs[rel]
+		cat := categories[rel]
+		if cat == nil {
+			cat = &Category{Path: rel}
+			categories[rel] = cat
+
+			parentPath := path.Dir(rel)
+			if parentPath == "." {
+				parentPath = ""
+			}
+			parent := categories[parentPath]
+			if parent == nil {
+				return nil, nil, fmt.Errorf("category %s loaded "+
+					"without parent", rel)
+			}
+			parent.Subcategories = append(parent.Subcategories, cat)
+		}
+		cat.Title = cd.meta.Title
+		cat.Description = cd.meta.Description
+		if cat.Title == "" && rel != "" {
+			cat.Title = path.Base(rel)
+		}
+
+		for _, prod := range cd.products {
+			if other, ok := products[prod.SKU]; ok {
+				return nil, nil, fmt.Errorf("product with duplicated "+
+					"SKU %s in categories %q and %q", prod.SKU,
+					other.Category, rel)
+			}
+			products[prod.SKU] = prod
+			cat.Products = append(cat.Products, prod)
+		}
+	}
+
+	// List the products in their additional categories.
+	for _, rel := range relPaths {
+		for _, prod := range dirs[rel].products {
+			for _, catPath := range prod.Categories {
+				catPath = strings.Trim(path.Clean("/"+catPath), "/")
+				if catPath == prod.Category {
+					continue
+				}
+				cat := ensureCategory(categories, catPath)
+				if !slices.Contains(cat.Products, prod) {
+					cat.Products = append(cat.Products, prod)
+				}
+			}
+		}
+	}
+
+	return products, root, nil
+}
+
+// ensureCategory returns the category with the given path, creating it (and
+// its parents) if it does not exist.
+func ensureCategory(categories map[string]*Category, catPath string) *Category {
+	if cat := categories[catPath]; cat != nil {
+		return cat
+	}
+	parentPath := path.Dir(catPath)
+	if parentPath == "." {
+		parentPath = ""
+	}
+	parent := ensureCategory(categories, parentPath)
+	cat := &Category{Path: catPath, Title: path.Base(catPath)}
+	categories[catPath] = cat
+	parent.Subcategories = append(parent.Subcategories, cat)
+	return cat
+}
+
+// reloadCatalogDirs reloads the given dirs (slash separated and relative to
+// the products dir) of the products tree, without reloading the rest of the
+// store.
+func (s *Store) reloadCatalogDirs(relPaths []string) error {
+	// Co-hosts ignore the local product files.
+	if s.isCoHost() {
+		return nil
+	}
+	prodDir := filepath.Join(s.root, productsDir)
+
+	s.mtx.Lock()
+	dirs := make(map[string]*catalogDir, len(s.catalogDirs))
+	for rel, cd := range s.catalogDirs {
+		dirs[rel] = cd
+	}
+	s.mtx.Unlock()
+
+	for _, rel := range relPaths {
+		// Remove the previously loaded subtree, as subdirs may have
+		// been removed or renamed.
+		for other := range dirs {
+			if other == rel || rel == "" || strings.HasPrefix(other, rel+"/") {
+				delete(dirs, other)
+			}
+		}
+
+		dir := filepath.Join(prodDir, filepath.FromSlash(rel))
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		if err := loadCatalogTree(prodDir, rel, dirs); err != nil {
+			return err
+		}
+	}
+
+	products, catalog, err := buildCatalog(dirs)
+	if err != nil {
+		return err
+	}
+	variants, err := buildVariants(products)
+	if err != nil {
+		return err
+	}
+	if err := validateBundles(products, variants); err != nil {
+		return err
+	}
+	if err := validateSubscriptions(products); err != nil {
+		return err
+	}
+	if err := validatePayWhatYouWant(products); err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	s.catalogDirs = dirs
+	s.products = products
+	s.variants = variants
+	s.catalog = catalog
+	s.search = buildSearchIndex(products)
+	s.refreshStock()
+	s.mtx.Unlock()
+	return nil
+}
+
+// product returns the product or product variant with the given SKU.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) product(sku string) (*Product, bool) {
+	if prod, ok := s.products[sku]; ok {
+		return prod, true
+	}
+	prod, ok := s.variants[sku]
+	return prod, ok
+}
+
+// productVariants returns the products of the variants of the product, in
+// the order they are listed in the product.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) productVariants(prod *Product) []*Product {
+	res := make([]*Product, 0, len(prod.Variants))
+	for _, v := range prod.Variants {
+		if vp, ok := s.variants[prod.SKU+v.SKUSuffix]; ok {
+			res = append(res, vp)
+		}
+	}
+	return res
+}
+
+// findCategory returns the category with the given path.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) findCategory(relPath string) *Category {
+	cat := s.catalog
+	if relPath == "" {
+		return cat
+	}
+	for _, name := range strings.Split(relPath, "/") {
+		var next *Category
+		for _, sub := range cat.Subcategories {
+			if sub.Name() == name {
+				next = sub
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		cat = next
+	}
+	return cat
+}
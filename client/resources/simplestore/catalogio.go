@@ -0,0 +1,569 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// CatalogFormat is the format of imported and exported product catalogs.
+type CatalogFormat string
+
+const (
+	// CatalogFormatCSV is a CSV file with a header. Besides the columns
+	// of catalogCSVHeader, the columns of Shopify product CSV exports
+	// (Handle, Title, Body (HTML), Tags, Type, Variant SKU, Variant
+	// Price, Variant Inventory Qty and Variant Requires Shipping) are
+	// recognized.
+	CatalogFormatCSV CatalogFormat = "csv"
+
+	// CatalogFormatJSON is the JSON layout of common e-commerce platforms
+	// (e.g. Shopify): {"products": [{"title", "body_html", "tags",
+	// "product_type", "variants": [{"sku", "price", ...}]}]}.
+	CatalogFormatJSON CatalogFormat = "json"
+)
+
+// maxImportedProducts is the max number of products in an imported catalog.
+const maxImportedProducts = 10000
+
+// contentType returns the content type of catalogs exported in the format.
+func (f CatalogFormat) contentType() string {
+	if f == CatalogFormatJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// catalogCSVHeader is the header of catalogs exported as CSV.
+var catalogCSVHeader = []string{"sku", "title", "description", "price",
+	"tags", "category", "shipping", "stock", "digital_file"}
+
+// catalogCSVAliases maps the names of columns of Shopify CSV exports to the
+// columns of catalogCSVHeader.
+var catalogCSVAliases = map[string]string{
+	"variant sku":               "sku",
+	"body (html)":               "body_html",
+	"type":                      "category",
+	"product_type":              "category",
+	"variant price":             "price",
+	"variant inventory qty":     "stock",
+	"variant requires shipping": "shipping",
+	"option1 value":             "option",
+}
+
+// htmlTagRegexp matches the HTML tags stripped from imported descriptions.
+var htmlTagRegexp = regexp.MustCompile(`<[^>]*>`)
+
+// htmlToText converts an HTML description to plain text.
+func htmlToText(s string) string {
+	s = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n",
+		"</p>", "\n\n").Replace(s)
+	s = html.UnescapeString(htmlTagRegexp.ReplaceAllString(s, ""))
+	return strings.TrimSpace(s)
+}
+
+// ImportIssue is a problem with a product of an imported catalog, which was
+// not imported.
+type ImportIssue struct {
+	// Record is the number of the record (CSV line after the header or
+	// product variant of the JSON catalog) of the product, starting at 1.
+	Record int
+	SKU    string
+	Msg    string
+}
+
+// String returns a description of the issue.
+func (issue ImportIssue) String() string {
+	if issue.SKU == "" {
+		return fmt.Sprintf("record %d: %s", issue.Record, issue.Msg)
+	}
+	return fmt.Sprintf("record %d (SKU %s): %s", issue.Record, issue.SKU, issue.Msg)
+}
+
+// ImportResult is the result of importing a catalog.
+type ImportResult struct {
+	// DryRun is set when the catalog was only validated.
+	DryRun bool
+
+	// Created and Updated are the SKUs of the products created and
+	// updated (or that would be, in dry runs).
+	Created []string
+	Updated []string
+
+	// Issues are the products that were not imported.
+	Issues []ImportIssue
+}
+
+// importedProduct is a product of an imported catalog.
+type importedProduct struct {
+	record int
+	upd    ProductUpdate
+}
+
+// catalogRecord is a product of an imported catalog, with its fields as
+// strings.
+type catalogRecord struct {
+	record                       int
+	sku, title, description      string
+	price, tags, category        string
+	shipping, stock, digitalFile string
+}
+
+// product validates the record and converts it to a product update.
+func (r *catalogRecord) product() (*importedProduct, error) {
+	upd := ProductUpdate{
+		SKU:         strings.TrimSpace(r.sku),
+		Title:       strings.TrimSpace(r.title),
+		Description: strings.TrimSpace(r.description),
+		DigitalFile: strings.TrimSpace(r.digitalFile),
+	}
+	switch {
+	case upd.SKU == "":
+		return nil, errors.New("SKU is empty")
+	case !skuRegexp.MatchString(upd.SKU):
+		return nil, fmt.Errorf("invalid SKU %q", upd.SKU)
+	case upd.Title == "":
+		return nil, errors.New("title is empty")
+	}
+
+	var err error
+	if upd.Price, err = ParseMoney(r.price); err != nil {
+		return nil, err
+	}
+	if upd.Price < 0 {
+		return nil, fmt.Errorf("invalid price %s", upd.Price)
+	}
+	if upd.Category, err = cleanCategoryPath(r.category); err != nil {
+		return nil, err
+	}
+	for _, tag := range strings.Split(r.tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			upd.Tags = append(upd.Tags, tag)
+		}
+	}
+	if v := strings.TrimSpace(r.shipping); v != "" {
+		if upd.Shipping, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid shipping %q", v)
+		}
+	}
+	if v := strings.TrimSpace(r.stock); v != "" {
+		stock, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || stock < 0 {
+			return nil, fmt.Errorf("invalid stock %q", v)
+		}
+		upd.Stock = &stock
+	}
+	return &importedProduct{record: r.record, upd: upd}, nil
+}
+
+// readCatalogCSV reads the records of a CSV catalog.
+func readCatalogCSV(r io.Reader) ([]*catalogRecord, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CSV header: %v", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := catalogCSVAliases[name]; ok {
+			name = alias
+		}
+		if _, ok := cols[name]; !ok {
+			cols[name] = i
+		}
+	}
+	if _, ok := cols["sku"]; !ok {
+		if _, ok := cols["handle"]; !ok {
+			return nil, errors.New("CSV header does not have a sku column")
+		}
+	}
+
+	var res []*catalogRecord
+	var first *catalogRecord
+	var firstHandle, baseTitle string
+	for n := 1; ; n++ {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(res) >= maxImportedProducts {
+			return nil, fmt.Errorf("catalog has more than %d products",
+				maxImportedProducts)
+		}
+		get := func(col string) string {
+			if i, ok := cols[col]; ok && i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+
+		rec := &catalogRecord{
+			record:      n,
+			sku:         get("sku"),
+			title:       get("title"),
+			description: get("description"),
+			price:       get("price"),
+			tags:        get("tags"),
+			category:    get("category"),
+			shipping:    get("shipping"),
+			stock:       get("stock"),
+			digitalFile: get("digital_file"),
+		}
+		if rec.description == "" {
+			rec.description = htmlToText(get("body_html"))
+		}
+
+		// In Shopify exports, the rows of the variants of a product
+		// after the first one only have the handle and the variant
+		// fields.
+		handle := get("handle")
+		if handle != "" && handle == firstHandle {
+			if rec.title == "" {
+				rec.title = baseTitle
+			}
+			if rec.description == "" {
+				rec.description = first.description
+			}
+			if rec.tags == "" {
+				rec.tags = first.tags
+			}
+			if rec.category == "" {
+				rec.category = first.category
+			}
+		} else {
+			first, firstHandle, baseTitle = rec, handle, rec.title
+		}
+		if rec.sku == "" {
+			rec.sku = handle
+		}
+		if opt := strings.TrimSpace(get("option")); opt != "" &&
+			!strings.EqualFold(opt, "Default Title") {
+			rec.title = fmt.Sprintf("%s - %s", rec.title, opt)
+		}
+		res = append(res, rec)
+	}
+}
+
+// jsonCatalog is the layout of catalogs in the JSON format.
+type jsonCatalog struct {
+	Products []jsonCatalogProduct `json:"products"`
+}
+
+type jsonCatalogProduct struct {
+	Handle      string               `json:"handle,omitempty"`
+	Title       string               `json:"title"`
+	BodyHTML    string               `json:"body_html,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        string               `json:"tags,omitempty"`
+	ProductType string               `json:"product_type,omitempty"`
+	DigitalFile string               `json:"digital_file,omitempty"`
+	Variants    []jsonCatalogVariant `json:"variants"`
+}
+
+type jsonCatalogVariant struct {
+	SKU               string      `json:"sku"`
+	Title             string      `json:"title,omitempty"`
+	Price             json.Number `json:"price"`
+	InventoryQuantity *int64      `json:"inventory_quantity,omitempty"`
+	RequiresShipping  bool        `json:"requires_shipping"`
+}
+
+// UnmarshalJSON decodes the variant, accepting prices encoded as strings
+// (e.g. "19.99") or numbers.
+func (v *jsonCatalogVariant) UnmarshalJSON(b []byte) error {
+	type variant jsonCatalogVariant
+	var raw struct {
+		variant
+		Price json.RawMessage `json:"price"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*v = jsonCatalogVariant(raw.variant)
+	price := strings.Trim(string(raw.Price), `"`)
+	if price == "null" {
+		price = ""
+	}
+	v.Price = json.Number(price)
+	return nil
+}
+
+// readCatalogJSON reads the records of a JSON catalog.
+func readCatalogJSON(r io.Reader) ([]*catalogRecord, error) {
+	var cat jsonCatalog
+	if err := json.NewDecoder(r).Decode(&cat); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON catalog: %v", err)
+	}
+
+	var res []*catalogRecord
+	for _, p := range cat.Products {
+		desc := p.Description
+		if desc == "" {
+			desc = htmlToText(p.BodyHTML)
+		}
+		variants := p.Variants
+		if len(variants) == 0 {
+			variants = []jsonCatalogVariant{{}}
+		}
+		for _, v := range variants {
+			if len(res) >= maxImportedProducts {
+				return nil, fmt.Errorf("catalog has more than %d products",
+					maxImportedProducts)
+			}
+			rec := &catalogRecord{
+				record:      len(res) + 1,
+				sku:         v.SKU,
+				title:       p.Title,
+				description: desc,
+				price:       v.Price.String(),
+				tags:        p.Tags,
+				category:    p.ProductType,
+				shipping:    strconv.FormatBool(v.RequiresShipping),
+				digitalFile: p.DigitalFile,
+			}
+			if rec.sku == "" && len(variants) == 1 {
+				rec.sku = p.Handle
+			}
+			if len(variants) > 1 && v.Title != "" &&
+				!strings.EqualFold(v.Title, "Default Title") {
+				rec.title = fmt.Sprintf("%s - %s", p.Title, v.Title)
+			}
+			if v.InventoryQuantity != nil {
+				rec.stock = strconv.FormatInt(*v.InventoryQuantity, 10)
+			}
+			res = append(res, rec)
+		}
+	}
+	return res, nil
+}
+
+// ImportProducts imports the products of a catalog in the given format,
+// creating the products with new SKUs and updating the products with existing
+// SKUs (the stock of existing products is not changed and their category is
+// kept if the imported product does not have one). Invalid products and
+// products with a SKU repeated in the catalog are not imported and are
+// returned as issues of the result.
+//
+// If dryRun is true, the catalog is only validated and the result lists the
+// products that would be created and updated.
+func (s *Store) ImportProducts(r io.Reader, format CatalogFormat, dryRun bool) (*ImportResult, error) {
+	var records []*catalogRecord
+	var err error
+	switch format {
+	case CatalogFormatCSV:
+		records, err = readCatalogCSV(r)
+	case CatalogFormatJSON:
+		records, err = readCatalogJSON(r)
+	default:
+		return nil, fmt.Errorf("unknown catalog format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ImportResult{DryRun: dryRun}
+	issue := func(rec int, sku, msg string, args ...interface{}) {
+		res.Issues = append(res.Issues, ImportIssue{
+			Record: rec,
+			SKU:    sku,
+			Msg:    fmt.Sprintf(msg, args...),
+		})
+	}
+
+	// Validate the products and drop the repeated SKUs (the first product
+	// with a SKU is imported).
+	products := make([]*importedProduct, 0, len(records))
+	seen := make(map[string]int, len(records))
+	for _, rec := range records {
+		prod, err := rec.product()
+		if err != nil {
+			issue(rec.record, strings.TrimSpace(rec.sku), "%v", err)
+			continue
+		}
+		if first, ok := seen[prod.upd.SKU]; ok {
+			issue(rec.record, prod.upd.SKU, "SKU repeated from record %d", first)
+			continue
+		}
+		seen[prod.upd.SKU] = rec.record
+		products = append(products, prod)
+	}
+
+	for _, prod := range products {
+		// Only products listed in the store are updated. Other
+		// products in the product files (variants and archived
+		// products) keep their SKUs.
+		s.mtx.Lock()
+		existing, exists := s.products[prod.upd.SKU]
+		var taken bool
+		if exists {
+			if prod.upd.Category == "" {
+				prod.upd.Category = existing.Category
+			}
+		} else if _, taken = s.variants[prod.upd.SKU]; !taken {
+			taken, err = s.skuExists(prod.upd.SKU)
+		}
+		s.mtx.Unlock()
+		if err != nil {
+			return res, err
+		}
+		if taken {
+			issue(prod.record, prod.upd.SKU, "SKU is the SKU of a "+
+				"product variant or archived product")
+			continue
+		}
+
+		if !dryRun {
+			_, err := s.saveProduct(nil, !exists, &prod.upd, "")
+			if errors.Is(err, ErrInvalidProduct) {
+				issue(prod.record, prod.upd.SKU, "%v", err)
+				continue
+			}
+			if err != nil {
+				return res, err
+			}
+		}
+		if exists {
+			res.Updated = append(res.Updated, prod.upd.SKU)
+		} else {
+			res.Created = append(res.Created, prod.upd.SKU)
+		}
+	}
+
+	if !dryRun {
+		s.log.Infof("Imported catalog: %d products created, %d updated, "+
+			"%d not imported", len(res.Created), len(res.Updated),
+			len(res.Issues))
+	}
+	return res, nil
+}
+
+// ExportProducts exports the products of the store in the given format, such
+// that they may be imported with ImportProducts. Archived products are only
+// exported if includeArchived is true. Products with variants are exported
+// with one record for each variant.
+func (s *Store) ExportProducts(format CatalogFormat, includeArchived bool) ([]byte, error) {
+	if format != CatalogFormatCSV && format != CatalogFormatJSON {
+		return nil, fmt.Errorf("unknown catalog format %q", format)
+	}
+	products, err := s.Products(includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mtx.Lock()
+	var exported []*Product
+	for _, prod := range products {
+		if !prod.HasVariants() {
+			exported = append(exported, prod)
+			continue
+		}
+		exported = append(exported, s.productVariants(prod)...)
+	}
+	s.mtx.Unlock()
+	sort.SliceStable(exported, func(i, j int) bool {
+		return exported[i].SKU < exported[j].SKU
+	})
+
+	w := &bytes.Buffer{}
+	if format == CatalogFormatJSON {
+		cat := jsonCatalog{Products: make([]jsonCatalogProduct, 0, len(exported))}
+		for _, prod := range exported {
+			cat.Products = append(cat.Products, jsonCatalogProduct{
+				Handle:      prod.SKU,
+				Title:       prod.Title,
+				Description: prod.Description,
+				Tags:        strings.Join(prod.Tags, ", "),
+				ProductType: prod.Category,
+				DigitalFile: prod.DigitalFile,
+				Variants: []jsonCatalogVariant{{
+					SKU:               prod.SKU,
+					Price:             json.Number(prod.Price.String()),
+					InventoryQuantity: prod.Stock,
+					RequiresShipping:  prod.Shipping,
+				}},
+			})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(&cat); err != nil {
+			return nil, err
+		}
+		return w.Bytes(), nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(catalogCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, prod := range exported {
+		var stock string
+		if prod.Stock != nil {
+			stock = strconv.FormatInt(*prod.Stock, 10)
+		}
+		record := []string{
+			prod.SKU,
+			prod.Title,
+			prod.Description,
+			prod.Price.String(),
+			strings.Join(prod.Tags, ", "),
+			prod.Category,
+			strconv.FormatBool(prod.Shipping),
+			stock,
+			prod.DigitalFile,
+		}
+		if err := cw.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// handleAdminExportProducts exports the catalog as
+// /admin/exportproducts/<format>.
+func (s *Store) handleAdminExportProducts(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	format := CatalogFormatCSV
+	if len(request.Path) > 2 {
+		format = CatalogFormat(request.Path[2])
+	}
+	if format != CatalogFormatCSV && format != CatalogFormatJSON {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(fmt.Sprintf("unknown catalog format %q", format)),
+		}, nil
+	}
+
+	data, err := s.ExportProducts(format, false)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   data,
+		Status: rpc.ResourceStatusOk,
+		Meta: map[string]string{
+			rpc.ResourceMetaContentType: format.contentType(),
+		},
+	}, nil
+}
@@ -0,0 +1,179 @@
+package simplestore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// checkoutRulesFile is the name of the file, in the store root, with
+	// the checkout rules of the store.
+	checkoutRulesFile = "checkout.toml"
+
+	checkoutErrorTmplFile = "checkout_error.tmpl"
+)
+
+// ExclusiveProducts is a group of products that cannot be ordered together.
+type ExclusiveProducts struct {
+	// SKUs are the SKUs of the products of the group. The SKU of a product
+	// with variants applies to all of its variants.
+	SKUs []string
+
+	// Message is the error shown to buyers that try to order more than
+	// one of the products. If empty, a message listing the products is
+	// shown.
+	Message string
+}
+
+// CheckoutRules are the rules that orders must follow to be placed.
+type CheckoutRules struct {
+	// MinTotal is the min total of the items of an order (after discounts
+	// and before shipping), in the currency of the store.
+	MinTotal Money
+
+	// MaxWeight is the max total weight of the items of an order, in the
+	// unit of the weight of the products.
+	MaxWeight float64
+
+	// AllowedRegions, if not empty, are the only regions orders may be
+	// shipped to, while orders may never be shipped to BlockedRegions.
+	// Regions are either country codes ("US") or country codes followed
+	// by a state ("US-CA").
+	AllowedRegions []string
+	BlockedRegions []string
+
+	// Exclusive are the groups of products that cannot be ordered
+	// together.
+	Exclusive []ExclusiveProducts
+}
+
+// loadCheckoutRules loads the checkout rules of the file. A missing file
+// means no rules.
+func loadCheckoutRules(fname string) (*CheckoutRules, error) {
+	rules := new(CheckoutRules)
+	data, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := toml.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("unable to decode checkout rules %s: %v", fname, err)
+	}
+	if rules.MinTotal < 0 || rules.MaxWeight < 0 {
+		return nil, fmt.Errorf("checkout rules %s: min total and max weight "+
+			"cannot be negative", fname)
+	}
+	for _, group := range rules.Exclusive {
+		if len(group.SKUs) < 2 {
+			return nil, fmt.Errorf("checkout rules %s: group of exclusive "+
+				"products needs at least two SKUs", fname)
+		}
+	}
+	return rules, nil
+}
+
+// matchesRegion returns true if the address is in one of the regions.
+func (addr *ShippingAddress) matchesRegion(regions []string) bool {
+	country := strings.ToUpper(strings.TrimSpace(addr.CountryCode))
+	state := strings.ToUpper(strings.TrimSpace(addr.State))
+	for _, region := range regions {
+		region = strings.ToUpper(region)
+		if region == country || region == country+"-"+state {
+			return true
+		}
+	}
+	return false
+}
+
+// checkoutViolations returns the checkout rules violated by an order for the
+// items of the cart shipped to the address (if any).
+//
+// This MUST be called with the store mutex held.
+func (s *Store) checkoutViolations(cart *Cart, shipAddr *ShippingAddress) []string {
+	rules := s.checkoutRules
+	if rules == nil {
+		return nil
+	}
+
+	currency := s.currency()
+	var res []string
+	if total := cart.Total(); total < rules.MinTotal {
+		res = append(res, fmt.Sprintf("The minimum order total is %s "+
+			"(the items of the order total %s)",
+			formatAmount(rules.MinTotal, currency),
+			formatAmount(total, currency)))
+	}
+
+	if rules.MaxWeight > 0 {
+		if weight := s.cartWeight(cart); weight > rules.MaxWeight {
+			res = append(res, fmt.Sprintf("The total weight of the "+
+				"order (%g) is over the max weight of %g",
+				weight, rules.MaxWeight))
+		}
+	}
+
+	for _, group := range rules.Exclusive {
+		var titles []string
+		for _, item := range cart.Items {
+			for _, sku := range group.SKUs {
+				if item.Product.SKU == sku || item.Product.BaseSKU == sku {
+					titles = append(titles, item.Product.Title)
+					break
+				}
+			}
+		}
+		if len(titles) < 2 {
+			continue
+		}
+		msg := group.Message
+		if msg == "" {
+			msg = fmt.Sprintf("The following products cannot be "+
+				"ordered together: %s", strings.Join(titles, ", "))
+		}
+		res = append(res, msg)
+	}
+
+	if shipAddr != nil {
+		region := strings.ToUpper(strings.TrimSpace(shipAddr.CountryCode))
+		if state := strings.TrimSpace(shipAddr.State); state != "" {
+			region += "-" + strings.ToUpper(state)
+		}
+		switch {
+		case len(rules.AllowedRegions) > 0 && shipAddr.CountryCode == "":
+			res = append(res, "The country code of the shipping "+
+				"address is required")
+		case len(rules.AllowedRegions) > 0 && !shipAddr.matchesRegion(rules.AllowedRegions),
+			shipAddr.matchesRegion(rules.BlockedRegions):
+			res = append(res, fmt.Sprintf("The store does not ship "+
+				"to %s", region))
+		}
+	}
+
+	return res
+}
+
+type checkoutErrorContext struct {
+	Errors []string
+}
+
+// checkoutErrorReply renders the page with the checkout rules violated by an
+// order.
+func (s *Store) checkoutErrorReply(violations []string) (*rpc.RMFetchResourceReply, error) {
+	w := &bytes.Buffer{}
+	err := s.render.Render(w, checkoutErrorTmplFile, &checkoutErrorContext{Errors: violations})
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute checkout error template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package simplestore
+
+import (
+	"errors"
+	"time"
+)
+
+// stateFile is the file where the in-memory state of the store is checkpointed.
+const stateFile = "state.json"
+
+// storeState is the in-memory state of the store that is checkpointed during
+// shutdown.
+type storeState struct {
+	CheckpointTS time.Time `json:"checkpoint_ts"`
+	NextAdminIdx int       `json:"next_admin_idx"`
+}
+
+// loadState loads the state checkpointed in the last shutdown.
+func (s *Store) loadState() error {
+	var state storeState
+	err := s.backend.Read(stateFile, &state)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	s.nextAdminIdx = state.NextAdminIdx
+	s.mtx.Unlock()
+	return nil
+}
+
+// Checkpoint saves the in-memory state of the store, such that it can be
+// restored when the store is restarted. This waits for any in-progress
+// request to finish processing.
+func (s *Store) Checkpoint() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	state := storeState{
+		CheckpointTS: time.Now(),
+		NextAdminIdx: s.nextAdminIdx,
+	}
+	if err := s.writeDoc(stateFile, &state); err != nil {
+		return err
+	}
+	s.log.Debugf("Checkpointed store state")
+	return nil
+}
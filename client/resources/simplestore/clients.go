@@ -0,0 +1,60 @@
+package simplestore
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrlnd/lnrpc"
+)
+
+// Client is the client the store uses to interact with its buyers and admins.
+// It is implemented by *client.Client.
+type Client interface {
+	PublicID() clientintf.UserID
+	UserNick(uid clientintf.UserID) (string, error)
+	UIDByNick(nick string) (clientintf.UserID, error)
+	PM(uid clientintf.UserID, msg string) error
+	SendFile(uid clientintf.UserID, filepath string) error
+	TipUser(uid clientintf.UserID, dcrAmount float64, maxAttempts int32) error
+	UserReferral(uid clientintf.UserID) (*clientdb.Referral, error)
+	OnchainRecvAddrForUser(uid clientintf.UserID, acct string) (string, error)
+	FetchResource(uid clientintf.UserID, path []string, meta map[string]string,
+		sess, parentPage clientintf.PagesSessionID, data json.RawMessage) (rpc.ResourceTag, error)
+	SignResourceReply(path []string, reply *rpc.RMFetchResourceReply)
+	SignOrderSummary(summary *rpc.OrderSummary, reply *rpc.RMFetchResourceReply) error
+	NotificationManager() *client.NotificationManager
+	Metrics() *metrics.Metrics
+}
+
+// LNPayClient is the LN wallet the store uses to generate invoices and
+// on-chain addresses and to watch for their payments. It is implemented by
+// *client.DcrlnPaymentClient.
+type LNPayClient interface {
+	ChainParams(ctx context.Context) (*chaincfg.Params, error)
+	GetInvoice(ctx context.Context, mat int64, cb func(int64)) (string, error)
+	CancelInvoice(ctx context.Context, invoice string) error
+	ImportXPubAccount(ctx context.Context, name, xpub string) error
+	LNRPC() lnrpc.LightningClient
+}
+
+var (
+	_ Client      = (*client.Client)(nil)
+	_ LNPayClient = (*client.DcrlnPaymentClient)(nil)
+)
+
+// normalizeClients clears the clients of the config that are nil pointers
+// wrapped in the interfaces, so that they are correctly detected as not set.
+func (cfg *Config) normalizeClients() {
+	if c, ok := cfg.Client.(*client.Client); ok && c == nil {
+		cfg.Client = nil
+	}
+	if pc, ok := cfg.LNPayClient.(*client.DcrlnPaymentClient); ok && pc == nil {
+		cfg.LNPayClient = nil
+	}
+}
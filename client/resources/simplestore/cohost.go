@@ -0,0 +1,622 @@
+package simplestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// coHostDir is the dir where a co-host keeps the catalog mirrored
+	// from the primary store.
+	coHostDir         = "cohost"
+	coHostCatalogFile = "catalog.json"
+
+	// coHostOrdersFile is the file where the primary store indexes the
+	// orders imported from its co-hosts.
+	coHostOrdersFile = "cohostorders.json"
+
+	// defaultCoHostSyncInterval is the interval between syncs with the
+	// primary store when one is not specified in the config.
+	defaultCoHostSyncInterval = 5 * time.Minute
+
+	// coHostRequestTimeout is how long a co-host waits for the reply of
+	// the primary store to a sync request.
+	coHostRequestTimeout = 2 * time.Minute
+
+	// coHostSyncBatchSize is the max number of orders sent in each sync
+	// request.
+	coHostSyncBatchSize = 10
+)
+
+// CoHostConfig configures the co-hosting of a store. A co-host is a trusted
+// client that mirrors the catalog of the primary store and accepts orders
+// while the primary is offline, syncing them back to the primary once it is
+// reachable again.
+type CoHostConfig struct {
+	// Primary is the id of the primary store. When set, the local store
+	// runs as a co-host of it: the local product files are ignored and
+	// the catalog is mirrored from the primary instead.
+	Primary *clientintf.UserID
+
+	// CoHosts are the trusted clients allowed to mirror the catalog of
+	// the local store and to sync the orders placed with them.
+	CoHosts []clientintf.UserID
+
+	// SyncInterval is the interval between syncs of a co-host with the
+	// primary store. Defaults to 5 minutes.
+	SyncInterval time.Duration
+}
+
+// CoHostOrigin records the origin of an order imported from a co-host.
+type CoHostOrigin struct {
+	Host       clientintf.UserID `json:"host"`
+	ID         OrderID           `json:"id"`
+	ImportedTS time.Time         `json:"imported_ts"`
+
+	// Conflict describes the last conflict between the co-host and the
+	// primary store copies of the order, which needs to be resolved by
+	// an admin.
+	Conflict string `json:"conflict,omitempty"`
+}
+
+// OrderSync records the sync of an order placed with a co-host to the
+// primary store.
+type OrderSync struct {
+	PrimaryID OrderID     `json:"primary_id"`
+	Status    OrderStatus `json:"status"`
+	TS        time.Time   `json:"ts"`
+
+	// PrimaryStatus is the status of the order in the primary store after
+	// the sync. The status of the primary store prevails in conflicts.
+	PrimaryStatus OrderStatus `json:"primary_status"`
+	Conflict      string      `json:"conflict,omitempty"`
+}
+
+// coHostCatalogDir is a dir of the products tree sent to co-hosts.
+type coHostCatalogDir struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Products    []*Product `json:"products"`
+}
+
+// coHostCatalog is the catalog of the primary store mirrored by co-hosts.
+type coHostCatalog struct {
+	Dirs      map[string]coHostCatalogDir `json:"dirs"`
+	Stock     stockLevels                 `json:"stock"`
+	FetchedTS time.Time                   `json:"fetched_ts"`
+}
+
+type coHostSyncRequest struct {
+	Orders []*Order `json:"orders"`
+}
+
+type coHostSyncResult struct {
+	User      clientintf.UserID `json:"user"`
+	ID        OrderID           `json:"id"`
+	PrimaryID OrderID           `json:"primary_id"`
+	Status    OrderStatus       `json:"status"`
+	Conflict  string            `json:"conflict,omitempty"`
+}
+
+type coHostSyncReply struct {
+	Results []coHostSyncResult `json:"results"`
+	Stock   stockLevels        `json:"stock"`
+}
+
+// isCoHost returns true if the local store runs as a co-host of a primary
+// store.
+func (s *Store) isCoHost() bool {
+	return s.cfg.CoHost.Primary != nil
+}
+
+// isTrustedCoHost returns true if the user is a co-host of the local store.
+func (s *Store) isTrustedCoHost(uid clientintf.UserID) bool {
+	return slices.Contains(s.cfg.CoHost.CoHosts, uid)
+}
+
+// statusPath returns the shortest sequence of transitions that changes an
+// order from one status to another, or nil if there is none.
+func statusPath(from, to OrderStatus) []OrderStatus {
+	prev := map[OrderStatus]OrderStatus{from: from}
+	queue := []OrderStatus{from}
+	for len(queue) > 0 {
+		status := queue[0]
+		queue = queue[1:]
+		if status == to {
+			var path []OrderStatus
+			for ; status != from; status = prev[status] {
+				path = append([]OrderStatus{status}, path...)
+			}
+			return path
+		}
+		for _, next := range orderTransitions[status] {
+			if _, ok := prev[next]; !ok {
+				prev[next] = status
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// loadMirroredCatalog loads the catalog mirrored from the primary store in
+// the format of the products tree.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadMirroredCatalog() (map[string]*catalogDir, error) {
+	var mirror coHostCatalog
+	err := s.backend.Read(path.Join(coHostDir, coHostCatalogFile), &mirror)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("unable to load mirrored catalog: %v", err)
+	}
+	dirs := map[string]*catalogDir{"": {}}
+	for rel, dir := range mirror.Dirs {
+		for _, prod := range dir.Products {
+			prod.Category = rel
+		}
+		dirs[rel] = &catalogDir{
+			meta: categoryMeta{
+				Title:       dir.Title,
+				Description: dir.Description,
+			},
+			products: dir.Products,
+		}
+	}
+	return dirs, nil
+}
+
+// unsyncedOrders returns the orders placed with the local co-host that were
+// not yet synced to the primary store or that changed since they were synced.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) unsyncedOrders() ([]*Order, error) {
+	files, err := s.backend.List(allOrdersPattern)
+	if err != nil {
+		return nil, err
+	}
+	var orders []*Order
+	for _, f := range files {
+		order := new(Order)
+		if err := s.backend.Read(f, order); err != nil {
+			s.log.Warnf("Unable to decode order file %s: %v", f, err)
+			continue
+		}
+		if order.Synced != nil && order.Synced.Status == order.Status {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// applyPrimaryStock sets the local stock levels to the stock levels of the
+// primary store, minus the units of the orders not yet synced to it.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) applyPrimaryStock(levels stockLevels) error {
+	orders, err := s.unsyncedOrders()
+	if err != nil {
+		return err
+	}
+	levels = levels.clone()
+	for _, order := range orders {
+		if order.Synced != nil || order.Status == StatusCanceled ||
+			order.Status == StatusExpired {
+			continue
+		}
+		for _, item := range order.Cart.Items {
+			for _, u := range item.stockUnits() {
+				if n, ok := levels[u.sku]; ok {
+					levels[u.sku] = n - u.units
+				}
+			}
+		}
+	}
+	if err := s.writeDoc(stockFile, levels); err != nil {
+		return err
+	}
+	s.stock = levels
+	s.refreshStock()
+	return nil
+}
+
+// handleCoHostCatalog sends the catalog and stock levels of the store to a
+// co-host.
+func (s *Store) handleCoHostCatalog(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	catalog := coHostCatalog{
+		Dirs:      make(map[string]coHostCatalogDir, len(s.catalogDirs)),
+		Stock:     s.stock.clone(),
+		FetchedTS: time.Now(),
+	}
+	for rel, dir := range s.catalogDirs {
+		catalog.Dirs[rel] = coHostCatalogDir{
+			Title:       dir.meta.Title,
+			Description: dir.meta.Description,
+			Products:    dir.products,
+		}
+	}
+	data, err := json.Marshal(catalog)
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Debugf("Sending catalog to co-host %s", uid)
+	return &rpc.RMFetchResourceReply{
+		Data:   data,
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// importCoHostOrder imports an order placed with a co-host. Orders that were
+// already imported have their status and payment merged instead.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) importCoHostOrder(host clientintf.UserID, remote *Order,
+	index map[string]OrderID, batch StoreBatch, levels stockLevels) (coHostSyncResult, error) {
+
+	res := coHostSyncResult{User: remote.User, ID: remote.ID}
+	key := fmt.Sprintf("%s/%s/%s", host, remote.User, remote.ID)
+
+	primaryID, imported := index[key]
+	if !imported {
+		var err error
+		if primaryID, err = s.backend.NextOrderID(remote.User); err != nil {
+			return res, err
+		}
+
+		order := *remote
+		order.ID = primaryID
+		order.Synced = nil
+		order.CoHost = &CoHostOrigin{
+			Host:       host,
+			ID:         remote.ID,
+			ImportedTS: time.Now(),
+		}
+		if order.ShipAddr != nil {
+			encAddr, err := s.encryptShipAddr(order.ShipAddr)
+			if err != nil {
+				return res, err
+			}
+			order.ShipAddr = nil
+			order.EncShipAddr = encAddr
+		}
+
+		// Stock sold by the co-host while the primary was offline may
+		// have been sold by the primary as well. The order is kept, but
+		// flagged for the admins.
+		if order.Status != StatusCanceled && order.Status != StatusExpired {
+			for _, item := range order.Cart.Items {
+				for _, u := range item.stockUnits() {
+					n, ok := levels[u.sku]
+					if !ok {
+						continue
+					}
+					if n < u.units {
+						order.CoHost.Conflict = fmt.Sprintf("oversold "+
+							"%d units of SKU %s", u.units-n,
+							u.sku)
+						n = u.units
+					}
+					levels[u.sku] = n - u.units
+				}
+			}
+		}
+
+		s.assignOrder(&order)
+		batch.Write(orderKey(remote.User, primaryID), &order)
+		index[key] = primaryID
+		res.PrimaryID = primaryID
+		res.Status = order.Status
+		res.Conflict = order.CoHost.Conflict
+		s.log.Infof("Imported order %s/%s from co-host %s as order %s",
+			remote.User.ShortLogID(), remote.ID, host.ShortLogID(),
+			primaryID)
+		return res, nil
+	}
+
+	orderFname := orderKey(remote.User, primaryID)
+	order := new(Order)
+	if err := s.backend.Read(orderFname, order); err != nil {
+		return res, err
+	}
+	res.PrimaryID = primaryID
+
+	if remote.PaidTS != nil && order.PaidTS == nil {
+		order.PaidTS = remote.PaidTS
+		order.PaidAmount = remote.PaidAmount
+		order.PaidTxID = remote.PaidTxID
+		order.PaidPreimage = remote.PaidPreimage
+	}
+
+	// The status of the primary prevails when the co-host status is not
+	// reachable from it.
+	oldStatus := order.Status
+	if path := statusPath(order.Status, remote.Status); path != nil {
+		for _, status := range path {
+			if err := order.setStatus(status, &host); err != nil {
+				return res, err
+			}
+		}
+	} else if order.Status != remote.Status {
+		order.CoHost.Conflict = fmt.Sprintf("status %s in co-host "+
+			"conflicts with status %s", remote.Status, order.Status)
+	}
+	if (order.Status == StatusCanceled || order.Status == StatusExpired) &&
+		oldStatus != StatusCanceled && oldStatus != StatusExpired {
+		for _, item := range order.Cart.Items {
+			for _, u := range item.stockUnits() {
+				if _, ok := levels[u.sku]; ok {
+					levels[u.sku] += u.units
+				}
+			}
+		}
+	}
+	batch.Write(orderFname, order)
+
+	res.Status = order.Status
+	res.Conflict = order.CoHost.Conflict
+	s.log.Infof("Merged order %s/%s from co-host %s into order %s "+
+		"(status %s)", remote.User.ShortLogID(), remote.ID,
+		host.ShortLogID(), primaryID, order.Status)
+	return res, nil
+}
+
+// handleCoHostSyncOrders imports the orders sent by a co-host.
+func (s *Store) handleCoHostSyncOrders(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var req coHostSyncRequest
+	if err := json.Unmarshal(request.Data, &req); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	indexFname := coHostOrdersFile
+	index := make(map[string]OrderID)
+	err := s.backend.Read(indexFname, &index)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	levels := s.stock.clone()
+	batch := s.backend.NewBatch()
+	reply := coHostSyncReply{Results: make([]coHostSyncResult, 0, len(req.Orders))}
+	for _, order := range req.Orders {
+		if !order.Status.IsValid() {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data: []byte(fmt.Sprintf("order %s/%s has invalid "+
+					"status %q", order.User, order.ID, order.Status)),
+			}, nil
+		}
+		res, err := s.importCoHostOrder(uid, order, index, batch,
+			levels)
+		if err != nil {
+			return nil, fmt.Errorf("unable to import order %s/%s "+
+				"from co-host %s: %v", order.User, order.ID, uid, err)
+		}
+		reply.Results = append(reply.Results, res)
+	}
+	batch.Write(indexFname, index)
+	batch.Write(stockFile, levels)
+	if err := batch.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to save co-host orders: %v", err)
+	}
+	s.stock = levels
+	s.refreshStock()
+
+	reply.Stock = levels.clone()
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   data,
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// coHostRequest sends a request to the primary store and waits for its
+// reply.
+func (s *Store) coHostRequest(ctx context.Context, replies chan rpc.RMFetchResourceReply,
+	path []string, req, res interface{}) error {
+
+	var data json.RawMessage
+	if req != nil {
+		var err error
+		if data, err = json.Marshal(req); err != nil {
+			return err
+		}
+	}
+
+	tag, err := s.c.FetchResource(*s.cfg.CoHost.Primary, path, nil, 0, 0, data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, coHostRequestTimeout)
+	defer cancel()
+	for {
+		select {
+		case reply := <-replies:
+			if reply.Tag != tag {
+				// Reply to an earlier request that timed out.
+				continue
+			}
+			if reply.Status != rpc.ResourceStatusOk {
+				return fmt.Errorf("primary store replied with "+
+					"status %s: %s", reply.Status, reply.Data)
+			}
+			return json.Unmarshal(reply.Data, res)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// coHostSync mirrors the catalog of the primary store and syncs the orders
+// placed with the local co-host to it.
+func (s *Store) coHostSync(ctx context.Context, replies chan rpc.RMFetchResourceReply) error {
+	var catalog coHostCatalog
+	err := s.coHostRequest(ctx, replies, []string{"cohost", "catalog"}, nil, &catalog)
+	if err != nil {
+		return fmt.Errorf("unable to fetch catalog: %v", err)
+	}
+
+	s.mtx.Lock()
+	err = s.writeDoc(path.Join(coHostDir, coHostCatalogFile), &catalog)
+	if err == nil {
+		err = s.applyPrimaryStock(catalog.Stock)
+	}
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := s.reloadStore(); err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	orders, err := s.unsyncedOrders()
+	if err == nil {
+		for _, order := range orders {
+			err = s.loadOrderShipAddr(order)
+			if err != nil {
+				break
+			}
+		}
+	}
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for len(orders) > 0 {
+		n := len(orders)
+		if n > coHostSyncBatchSize {
+			n = coHostSyncBatchSize
+		}
+		batch := orders[:n]
+		orders = orders[n:]
+
+		var reply coHostSyncReply
+		err := s.coHostRequest(ctx, replies, []string{"cohost", "syncorders"},
+			&coHostSyncRequest{Orders: batch}, &reply)
+		if err != nil {
+			return fmt.Errorf("unable to sync orders: %v", err)
+		}
+
+		s.mtx.Lock()
+		err = s.recordOrdersSync(batch, reply.Results)
+		if err == nil {
+			err = s.applyPrimaryStock(reply.Stock)
+		}
+		s.mtx.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordOrdersSync records the results of syncing the orders to the primary
+// store.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) recordOrdersSync(sent []*Order, results []coHostSyncResult) error {
+	now := time.Now()
+	batch := s.backend.NewBatch()
+	for _, res := range results {
+		i := slices.IndexFunc(sent, func(o *Order) bool {
+			return o.User == res.User && o.ID == res.ID
+		})
+		if i < 0 {
+			continue
+		}
+
+		// Reload the order, without the decrypted shipping address and
+		// with any changes done while it was being synced.
+		fname := orderKey(res.User, res.ID)
+		order := new(Order)
+		if err := s.backend.Read(fname, order); err != nil {
+			return err
+		}
+		order.Synced = &OrderSync{
+			PrimaryID:     res.PrimaryID,
+			Status:        sent[i].Status,
+			TS:            now,
+			PrimaryStatus: res.Status,
+			Conflict:      res.Conflict,
+		}
+		if res.Conflict != "" {
+			s.log.Warnf("Conflict syncing order %s/%s to primary: %s",
+				res.User.ShortLogID(), res.ID, res.Conflict)
+		}
+		batch.Write(fname, order)
+	}
+	return batch.Commit()
+}
+
+// runCoHostSync periodically syncs the local co-host with the primary store.
+func (s *Store) runCoHostSync(ctx context.Context) error {
+	replies := make(chan rpc.RMFetchResourceReply, 10)
+	primary := *s.cfg.CoHost.Primary
+	reg := s.c.NotificationManager().Register(client.OnResourceFetchedNtfn(
+		func(ru *client.RemoteUser, fr clientdb.FetchedResource,
+			_ clientdb.PageSessionOverview) {
+
+			if ru == nil || ru.ID() != primary || len(fr.Request.Path) == 0 ||
+				fr.Request.Path[0] != "cohost" {
+				return
+			}
+			select {
+			case replies <- fr.Response:
+			default:
+			}
+		}))
+	defer reg.Unregister()
+
+	interval := s.cfg.CoHost.SyncInterval
+	if interval <= 0 {
+		interval = defaultCoHostSyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := s.coHostSync(ctx, replies)
+		switch {
+		case errors.Is(err, context.Canceled):
+			return err
+		case err != nil:
+			s.log.Warnf("Unable to sync with primary store %s: %v",
+				primary, err)
+		default:
+			s.log.Debugf("Synced with primary store %s", primary)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
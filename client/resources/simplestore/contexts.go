@@ -8,16 +8,70 @@ import (
 
 type indexContext struct {
 	Products map[string]*Product
+	Catalog  *Category
 	IsAdmin  bool
 }
 
+type categoryContext struct {
+	Category *Category
+	IsAdmin  bool
+}
+
+type productContext struct {
+	*Product
+
+	// Variants are the products of the variants of the product, if any.
+	Variants []*Product
+
+	// Currency is the currency of the prices of the store.
+	Currency string
+
+	// ImageEmbed is the embed of the image of the product, if it has one.
+	ImageEmbed string
+
+	// Gallery are the embeds of the additional images of the product.
+	Gallery []string
+
+	// Components are the products included in the product, when it is a
+	// bundle, and BundleValue is the sum of their prices.
+	Components  []bundleComponent
+	BundleValue Money
+}
+
+// FormatPrice formats a price in the currency of the store.
+func (ctx *productContext) FormatPrice(v Money) string {
+	return formatAmount(v, ctx.Currency)
+}
+
 type addToCartContext struct {
 	Product *Product
-	Cart    *Cart
+
+	// Cart is the context of the cart template, which is included in the
+	// add to cart template.
+	Cart *cartContext
+}
+
+type cartContext struct {
+	*Cart
+
+	// Message is a status message about the last update to the cart.
+	Message string
+}
+
+type checkoutContext struct {
+	Cart     *Cart
+	ShipAddr *ShippingAddress
+
+	// ShippingOptions are the shipping methods available for the order.
+	ShippingOptions []ShippingOption
 }
 
 type orderContext struct {
 	Order
+
+	// CanPayOnChain is true if the user may switch the payment of the
+	// order from LN to on-chain.
+	CanPayOnChain bool
 }
 
 type ordersContext struct {
@@ -30,13 +84,30 @@ type adminOrderSummary struct {
 	UserNick string
 	Status   OrderStatus
 	PlacedTS time.Time
+	NeedsAck bool
+	Referral string
+	Escrow   *OrderEscrow
 }
 
 type adminOrdersContext struct {
-	Orders []adminOrderSummary
+	Orders   []adminOrderSummary
+	Filter   OrderStatus
+	Statuses []OrderStatus
+
+	// Query is the filter of the orders listed by an order query.
+	Query string
 }
 
 type adminOrderContext struct {
-	Order    Order
-	UserNick string
+	Order        Order
+	UserNick     string
+	AssignedNick string
+
+	// TimeToPay is the time between placing and paying the order, if it
+	// was paid.
+	TimeToPay time.Duration
+}
+
+type packingSlipContext struct {
+	Orders []*Order
 }
@@ -0,0 +1,72 @@
+package simplestore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/rates"
+)
+
+// RateProvider provides the exchange rate of DCR in fiat currencies.
+type RateProvider interface {
+	// DCRPrice returns the current price of one DCR in the given
+	// currency. It fails if the rate is unknown or stale.
+	DCRPrice(currency string) (float64, error)
+}
+
+// RateProviderFunc is a function that implements RateProvider.
+type RateProviderFunc func(currency string) (float64, error)
+
+// DCRPrice is part of the RateProvider interface.
+func (f RateProviderFunc) DCRPrice(currency string) (float64, error) {
+	return f(currency)
+}
+
+// currencyOrDefault returns the currency or USD if it is empty (as in orders
+// placed before stores could be configured with other currencies).
+func currencyOrDefault(currency string) string {
+	if currency == "" {
+		return rates.USD
+	}
+	return currency
+}
+
+// formatAmount formats an amount in the given currency.
+func formatAmount(v Money, currency string) string {
+	return v.Format(currency)
+}
+
+// currency returns the currency of the prices of the store.
+func (s *Store) currency() string {
+	return currencyOrDefault(strings.ToUpper(s.cfg.Currency))
+}
+
+// exchangeRate returns the current price of one DCR in the currency of the
+// store.
+func (s *Store) exchangeRate() (float64, error) {
+	currency := s.currency()
+	switch {
+	case s.cfg.RateProvider != nil:
+		return s.cfg.RateProvider.DCRPrice(currency)
+	case s.cfg.ExchangeRateProvider != nil && currency == rates.USD:
+		return s.cfg.ExchangeRateProvider(), nil
+	default:
+		return 0, fmt.Errorf("no exchange rate provider for currency %s",
+			currency)
+	}
+}
+
+// FormatAmount formats an amount in the currency of the cart.
+func (cart *Cart) FormatAmount(v Money) string {
+	return formatAmount(v, cart.Currency)
+}
+
+// CurrencyCode returns the currency of the order.
+func (order *Order) CurrencyCode() string {
+	return currencyOrDefault(order.Currency)
+}
+
+// FormatAmount formats an amount in the currency of the order.
+func (order *Order) FormatAmount(v Money) string {
+	return formatAmount(v, order.Currency)
+}
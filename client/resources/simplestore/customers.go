@@ -0,0 +1,268 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+const (
+	customersDir = "customers"
+
+	adminCustomerTmplFile = "admin_customer.tmpl"
+)
+
+func customerKey(uid clientintf.UserID) string {
+	return path.Join(customersDir, uid.String())
+}
+
+// CustomerNote is a note about a customer, recorded by an admin of the store.
+type CustomerNote struct {
+	Timestamp time.Time         `json:"ts"`
+	Admin     clientintf.UserID `json:"admin"`
+	Note      string            `json:"note"`
+}
+
+// customerRecord is the data the store keeps about a customer, besides their
+// orders.
+type customerRecord struct {
+	Notes []CustomerNote `json:"notes"`
+
+	// NoReminders is set when the customer opted out of the reminders of
+	// the store.
+	NoReminders bool `json:"no_reminders,omitempty"`
+}
+
+// OrderRefund is a refund of an order.
+type OrderRefund struct {
+	Timestamp time.Time      `json:"ts"`
+	Amount    dcrutil.Amount `json:"amount"`
+	Note      string         `json:"note,omitempty"`
+
+	// By is the admin that issued the refund or nil if it was recorded
+	// by the local client.
+	By *clientintf.UserID `json:"by,omitempty"`
+
+	// Pushed is true if the refund was sent to the buyer as a payment
+	// through the client. Otherwise, the refund was performed out of band.
+	Pushed bool `json:"pushed,omitempty"`
+}
+
+// CustomerRefund is a refund of one of the orders of a customer.
+type CustomerRefund struct {
+	OrderID OrderID
+	OrderRefund
+}
+
+// CustomerHistory is the lifetime purchase history of a customer.
+type CustomerHistory struct {
+	User clientintf.UserID
+
+	// Orders are the orders placed by the customer, most recent first.
+	Orders []*Order
+
+	// Paid is the number of orders that were paid and Spent their total
+	// amount.
+	Paid  int
+	Spent salesTotals
+
+	// Refunds are the refunds of the orders of the customer, most recent
+	// first, and Refunded their total amount.
+	Refunds  []CustomerRefund
+	Refunded dcrutil.Amount
+
+	// Notes are the notes recorded by admins about the customer, oldest
+	// first.
+	Notes []CustomerNote
+
+	FirstOrderTS time.Time
+	LastOrderTS  time.Time
+}
+
+// loadCustomerRecord loads the record of the customer.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadCustomerRecord(uid clientintf.UserID) (*customerRecord, error) {
+	rec := new(customerRecord)
+	err := s.backend.Read(customerKey(uid), rec)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// customerHistory returns the purchase history of the customer.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) customerHistory(uid clientintf.UserID) (*CustomerHistory, error) {
+	files, err := s.backend.List(userOrdersPattern(uid))
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.loadCustomerRecord(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &CustomerHistory{
+		User:  uid,
+		Spent: make(salesTotals),
+		Notes: rec.Notes,
+	}
+	for _, fname := range files {
+		order := new(Order)
+		if err := s.backend.Read(fname, order); err != nil {
+			s.log.Warnf("Unable to decode order file %s: %v", fname, err)
+			continue
+		}
+		h.Orders = append(h.Orders, order)
+		if h.FirstOrderTS.IsZero() || order.PlacedTS.Before(h.FirstOrderTS) {
+			h.FirstOrderTS = order.PlacedTS
+		}
+		if order.PlacedTS.After(h.LastOrderTS) {
+			h.LastOrderTS = order.PlacedTS
+		}
+		if order.Status.isSale() {
+			h.Paid++
+			h.Spent.add(order.Currency, order.Total())
+		}
+		for _, refund := range order.Refunds {
+			h.Refunds = append(h.Refunds, CustomerRefund{
+				OrderID:     order.ID,
+				OrderRefund: refund,
+			})
+			h.Refunded += refund.Amount
+		}
+	}
+
+	sort.Slice(h.Orders, func(i, j int) bool {
+		return h.Orders[i].PlacedTS.After(h.Orders[j].PlacedTS)
+	})
+	sort.Slice(h.Refunds, func(i, j int) bool {
+		return h.Refunds[i].Timestamp.After(h.Refunds[j].Timestamp)
+	})
+	return h, nil
+}
+
+// CustomerHistory returns the lifetime purchase history of the customer: their
+// orders, totals, refunds and the notes recorded about them.
+func (s *Store) CustomerHistory(uid clientintf.UserID) (*CustomerHistory, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.customerHistory(uid)
+}
+
+// AddCustomerNote records a note from an admin about a customer.
+func (s *Store) AddCustomerNote(uid, admin clientintf.UserID, note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return fmt.Errorf("note is empty")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	rec, err := s.loadCustomerRecord(uid)
+	if err != nil {
+		return err
+	}
+	rec.Notes = append(rec.Notes, CustomerNote{
+		Timestamp: time.Now(),
+		Admin:     admin,
+		Note:      note,
+	})
+	return s.writeDoc(customerKey(uid), rec)
+}
+
+type adminCustomerContext struct {
+	*CustomerHistory
+	UserNick string
+}
+
+func (s *Store) handleAdminCustomer(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if len(request.Path) < 3 {
+		return nil, fmt.Errorf("path has < 3 elements")
+	}
+	var uid clientintf.UserID
+	if err := uid.FromString(request.Path[2]); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("invalid user id"),
+		}, nil
+	}
+
+	h, err := s.CustomerHistory(uid)
+	if err != nil {
+		return nil, err
+	}
+	nick, _ := s.c.UserNick(uid)
+	tctx := &adminCustomerContext{
+		CustomerHistory: h,
+		UserNick:        strescape.Nick(nick),
+	}
+
+	w := &bytes.Buffer{}
+	err = s.render.Render(w, adminCustomerTmplFile, tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute admin customer template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+func (s *Store) handleAdminAddCustomerNote(ctx context.Context, admin clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if len(request.Path) < 3 {
+		return nil, fmt.Errorf("path has < 3 elements")
+	}
+	var uid clientintf.UserID
+	if err := uid.FromString(request.Path[2]); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("invalid user id"),
+		}, nil
+	}
+
+	var formData struct {
+		Note string `json:"note"`
+	}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+	if strings.TrimSpace(formData.Note) == "" {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("note is empty"),
+		}, nil
+	}
+	if err := s.AddCustomerNote(uid, admin, formData.Note); err != nil {
+		return nil, err
+	}
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Note added\n\n")
+	w.WriteString(fmt.Sprintf("[Back to Customer](/admin/customer/%s)\n\n", uid))
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
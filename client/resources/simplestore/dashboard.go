@@ -0,0 +1,343 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// lowStockLevel is the stock level at or below which products are listed as
+// low in stock in the admin dashboard.
+const lowStockLevel = 5
+
+// isSale returns true if orders in this status count towards the sales of the
+// store.
+func (status OrderStatus) isSale() bool {
+	switch status {
+	case StatusPaid, StatusBackordered, StatusShipped, StatusCompleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// salesTotals tracks the total amount of sales in each currency.
+type salesTotals map[string]Money
+
+// add adds the amount to the total of the currency.
+func (totals salesTotals) add(currency string, v Money) {
+	totals[currencyOrDefault(currency)] += v
+}
+
+// List returns the totals formatted in their currencies, sorted by currency.
+func (totals salesTotals) List() []string {
+	currencies := make([]string, 0, len(totals))
+	for cur := range totals {
+		currencies = append(currencies, cur)
+	}
+	sort.Strings(currencies)
+	res := make([]string, len(currencies))
+	for i, cur := range currencies {
+		res[i] = formatAmount(totals[cur], cur)
+	}
+	return res
+}
+
+// loadAllOrders loads the orders of all users.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadAllOrders() ([]*Order, error) {
+	files, err := s.backend.List(allOrdersPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*Order, 0, len(files))
+	for _, f := range files {
+		order := new(Order)
+		if err := s.backend.Read(f, order); err != nil {
+			s.log.Warnf("Unable to decode order file %s: %v", f, err)
+			continue
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// stockedProducts returns the products that track stock individually (i.e.
+// the products without variants and the variants), sorted by SKU.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) stockedProducts() []*Product {
+	products := make([]*Product, 0, len(s.products)+len(s.variants))
+	for _, prod := range s.products {
+		if !prod.HasVariants() && !prod.IsBundle() {
+			products = append(products, prod)
+		}
+	}
+	for _, prod := range s.variants {
+		products = append(products, prod)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].SKU < products[j].SKU
+	})
+	return products
+}
+
+type adminStatusCount struct {
+	Status OrderStatus
+	Count  int
+}
+
+type adminIndexContext struct {
+	// Queues are the number of orders in each status that still needs
+	// handling by the admins.
+	Queues []adminStatusCount
+
+	// NeedsAck is the number of orders assigned to an admin that were
+	// not yet acknowledged.
+	NeedsAck int
+
+	// Sales are the all time sales and RecentSales are the sales of
+	// orders placed in the last 30 days.
+	Sales       salesTotals
+	RecentSales salesTotals
+	SalesCount  int
+
+	// LowStock are the products with low or no stock.
+	LowStock []*Product
+
+	// Role is the role of the admin viewing the page.
+	Role AdminRole
+}
+
+func (s *Store) handleAdminIndex(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	orders, err := s.loadAllOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	tctx := &adminIndexContext{
+		Sales:       make(salesTotals),
+		RecentSales: make(salesTotals),
+		Role:        s.adminRole(uid),
+	}
+	counts := make(map[OrderStatus]int)
+	recent := time.Now().Add(-30 * 24 * time.Hour)
+	for _, order := range orders {
+		counts[order.Status]++
+		if order.needsAck() {
+			tctx.NeedsAck++
+		}
+		if !order.Status.isSale() {
+			continue
+		}
+		tctx.SalesCount++
+		tctx.Sales.add(order.Currency, order.Total())
+		if order.PlacedTS.After(recent) {
+			tctx.RecentSales.add(order.Currency, order.Total())
+		}
+	}
+	for _, status := range []OrderStatus{StatusPlaced, StatusConfirmed,
+		StatusPaid, StatusBackordered, StatusShipped} {
+		tctx.Queues = append(tctx.Queues, adminStatusCount{
+			Status: status,
+			Count:  counts[status],
+		})
+	}
+
+	for _, prod := range s.stockedProducts() {
+		if prod.Stock != nil && *prod.Stock <= lowStockLevel {
+			tctx.LowStock = append(tctx.LowStock, prod)
+		}
+	}
+
+	w := &bytes.Buffer{}
+	err = s.render.Render(w, adminIndexTmplFile, tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute admin index template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+type adminProductSummary struct {
+	*Product
+
+	// Sold is the number of units sold in paid orders and Pending the
+	// number of units in orders not yet paid.
+	Sold    uint64
+	Pending uint64
+
+	// Sales are the total sales of the product.
+	Sales salesTotals
+}
+
+// StockLevel returns the stock level of the product as a string.
+func (summ *adminProductSummary) StockLevel() string {
+	if summ.Stock == nil {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d", *summ.Stock)
+}
+
+type adminProductsContext struct {
+	Products []*adminProductSummary
+	Currency string
+
+	// Archived are the products disabled in the product files.
+	Archived []*Product
+
+	// CanEdit is false when the products are managed by the primary
+	// store of a co-hosted store.
+	CanEdit bool
+}
+
+// FormatPrice formats a price in the currency of the store.
+func (ctx *adminProductsContext) FormatPrice(v Money) string {
+	return formatAmount(v, ctx.Currency)
+}
+
+func (s *Store) handleAdminProducts(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	orders, err := s.loadAllOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	products := s.stockedProducts()
+	tctx := &adminProductsContext{
+		Products: make([]*adminProductSummary, len(products)),
+		Currency: s.currency(),
+		CanEdit:  !s.isCoHost(),
+	}
+	if tctx.CanEdit {
+		tctx.Archived, err = s.archivedProducts()
+		if err != nil {
+			return nil, err
+		}
+	}
+	bySKU := make(map[string]*adminProductSummary, len(products))
+	for i, prod := range products {
+		summ := &adminProductSummary{Product: prod, Sales: make(salesTotals)}
+		tctx.Products[i] = summ
+		bySKU[prod.SKU] = summ
+	}
+
+	for _, order := range orders {
+		if order.Status == StatusCanceled || order.Status == StatusExpired {
+			continue
+		}
+		for _, item := range order.Cart.Items {
+			summ := bySKU[item.Product.SKU]
+			if summ == nil {
+				// Product no longer in the catalog.
+				continue
+			}
+			if !order.Status.isSale() {
+				summ.Pending += uint64(item.Quantity)
+				continue
+			}
+			summ.Sold += uint64(item.Quantity)
+			summ.Sales.add(order.Currency, item.Total())
+		}
+	}
+
+	w := &bytes.Buffer{}
+	err = s.render.Render(w, adminProductsTmplFile, tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute admin products template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+type adminCustomerSummary struct {
+	User     clientintf.UserID
+	UserNick string
+
+	// Orders is the number of orders placed by the customer and Paid the
+	// number of those that were paid.
+	Orders int
+	Paid   int
+
+	// Spent is the total amount of the paid orders of the customer.
+	Spent salesTotals
+
+	LastOrderID OrderID
+	LastOrderTS time.Time
+}
+
+type adminCustomersContext struct {
+	Customers []*adminCustomerSummary
+}
+
+func (s *Store) handleAdminCustomers(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	orders, err := s.loadAllOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[clientintf.UserID]*adminCustomerSummary)
+	tctx := &adminCustomersContext{}
+	for _, order := range orders {
+		summ := byUser[order.User]
+		if summ == nil {
+			nick, _ := s.c.UserNick(order.User)
+			summ = &adminCustomerSummary{
+				User:     order.User,
+				UserNick: strescape.Nick(nick),
+				Spent:    make(salesTotals),
+			}
+			byUser[order.User] = summ
+			tctx.Customers = append(tctx.Customers, summ)
+		}
+		summ.Orders++
+		if order.PlacedTS.After(summ.LastOrderTS) {
+			summ.LastOrderID = order.ID
+			summ.LastOrderTS = order.PlacedTS
+		}
+		if order.Status.isSale() {
+			summ.Paid++
+			summ.Spent.add(order.Currency, order.Total())
+		}
+	}
+
+	sort.Slice(tctx.Customers, func(i, j int) bool {
+		return tctx.Customers[i].LastOrderTS.After(tctx.Customers[j].LastOrderTS)
+	})
+
+	w := &bytes.Buffer{}
+	err = s.render.Render(w, adminCustomersTmplFile, tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute admin customers template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package simplestore
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// FileDelivery records the delivery of the digital file of an order item to
+// the buyer.
+type FileDelivery struct {
+	SKU      string     `json:"sku"`
+	Filename string     `json:"filename"`
+	Attempts int        `json:"attempts"`
+	SentTS   *time.Time `json:"sent_ts,omitempty"`
+
+	// Error is the error of the last failed attempt to send the file.
+	Error string `json:"error,omitempty"`
+}
+
+// digitalFilePath returns the path to the digital file of the product, or an
+// empty string if the product does not have one. Relative paths are relative
+// to the root of the store.
+func (s *Store) digitalFilePath(prod *Product) string {
+	fname := prod.DigitalFile
+	if fname == "" {
+		fname = prod.SendFilename
+	}
+	if fname == "" {
+		return ""
+	}
+	if !filepath.IsAbs(fname) {
+		fname = filepath.Join(s.root, fname)
+	}
+	return fname
+}
+
+// hasDigitalFiles returns true if any item of the order has a digital file.
+func (s *Store) hasDigitalFiles(order *Order) bool {
+	for _, item := range order.Cart.Items {
+		if s.digitalFilePath(item.Product) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// delivered returns true if the digital file of the product with the given
+// SKU was already delivered to the buyer.
+func (order *Order) delivered(sku string) bool {
+	for _, d := range order.Deliveries {
+		if d.SKU == sku && d.SentTS != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// deliverDigitalFiles sends the digital files of the items of the paid order
+// (that were not yet delivered) to the buyer, or to the recipient of gift
+// orders. The files are sent asynchronously and their delivery is recorded in
+// the order.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) deliverDigitalFiles(order *Order) {
+	for _, item := range order.Cart.Items {
+		fname := s.digitalFilePath(item.Product)
+		if fname == "" || order.delivered(item.Product.SKU) {
+			continue
+		}
+
+		uid, id, sku := order.User, order.ID, item.Product.SKU
+		to := order.DeliverTo()
+		go func() {
+			err := s.c.SendFile(to, fname)
+			if err != nil {
+				s.log.Errorf("Unable to send file %s to user %s due "+
+					"to order %s/%s: %v", fname, to,
+					uid.ShortLogID(), id, err)
+			} else {
+				s.log.Infof("Delivered file %s of order %s/%s",
+					filepath.Base(fname), uid.ShortLogID(), id)
+			}
+			s.recordDelivery(uid, id, sku, fname, err)
+		}()
+	}
+}
+
+// recordDelivery records the outcome of an attempt to deliver the digital file
+// of an order item.
+func (s *Store) recordDelivery(uid clientintf.UserID, id OrderID, sku, fname string,
+	sendErr error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	orderFname := orderKey(uid, id)
+	order := new(Order)
+	if err := s.backend.Read(orderFname, order); err != nil {
+		s.log.Warnf("Unable to read order %s/%s to record delivery: %v",
+			uid.ShortLogID(), id, err)
+		return
+	}
+
+	var d *FileDelivery
+	for i := range order.Deliveries {
+		if order.Deliveries[i].SKU == sku {
+			d = &order.Deliveries[i]
+			break
+		}
+	}
+	if d == nil {
+		order.Deliveries = append(order.Deliveries, FileDelivery{SKU: sku})
+		d = &order.Deliveries[len(order.Deliveries)-1]
+	}
+	d.Filename = filepath.Base(fname)
+	d.Attempts += 1
+	if sendErr != nil {
+		d.Error = sendErr.Error()
+	} else {
+		now := time.Now()
+		d.SentTS = &now
+		d.Error = ""
+	}
+	if err := s.writeDoc(orderFname, order); err != nil {
+		s.log.Warnf("Unable to record delivery of order %s/%s: %v",
+			uid.ShortLogID(), id, err)
+	}
+}
@@ -0,0 +1,491 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// ErrInvalidEscrowAction is returned when attempting to release or dispute the
+// escrow of an order that is not held in escrow or that is not in a state that
+// allows the action.
+var ErrInvalidEscrowAction = errors.New("invalid escrow action")
+
+// EscrowConfig configures the escrow payment mode of high-value orders. The
+// payment of escrow orders is held until both the buyer (after receiving the
+// order) and an admin of the store release it. Only then the order may be
+// completed.
+type EscrowConfig struct {
+	// MinTotal is the min total amount (in the store currency) of orders
+	// paid in escrow. Zero disables escrow payments.
+	MinTotal float64
+
+	// Account is the wallet account where the on-chain payment addresses
+	// of escrow orders are generated, to keep the held funds apart from
+	// other payments. If empty, the account of the store is used.
+	Account string
+}
+
+// OrderEscrow is the state of the escrow of an order paid in escrow.
+type OrderEscrow struct {
+	// BuyerReleasedTS is when the buyer confirmed the receipt of the order
+	// and released the escrow.
+	BuyerReleasedTS *time.Time `json:"buyer_released_ts,omitempty"`
+
+	// SellerReleasedTS is when an admin of the store released the escrow.
+	SellerReleasedTS *time.Time         `json:"seller_released_ts,omitempty"`
+	SellerReleasedBy *clientintf.UserID `json:"seller_released_by,omitempty"`
+
+	// Disputed is set while the buyer disputes the order. Disputed
+	// escrows cannot be released until an admin resolves the dispute.
+	Disputed      bool       `json:"disputed,omitempty"`
+	DisputeReason string     `json:"dispute_reason,omitempty"`
+	DisputeTS     *time.Time `json:"dispute_ts,omitempty"`
+
+	// DisputeResolvedTS is when an admin resolved the last dispute.
+	DisputeResolvedTS *time.Time         `json:"dispute_resolved_ts,omitempty"`
+	DisputeResolvedBy *clientintf.UserID `json:"dispute_resolved_by,omitempty"`
+}
+
+// Released returns true if both parties released the escrow.
+func (e *OrderEscrow) Released() bool {
+	return e.BuyerReleasedTS != nil && e.SellerReleasedTS != nil && !e.Disputed
+}
+
+// IsEscrow returns true if the order is paid in escrow.
+func (order *Order) IsEscrow() bool {
+	return order.Escrow != nil
+}
+
+// escrowReleasable returns an error if the escrow of the order cannot be
+// released or disputed: the order must be held in escrow and paid, but not
+// yet final.
+func (order *Order) escrowReleasable() error {
+	switch {
+	case order.Escrow == nil:
+		return fmt.Errorf("%w: order %s is not paid in escrow",
+			ErrInvalidEscrowAction, order.ID)
+	case order.PaidTS == nil:
+		return fmt.Errorf("%w: order %s was not paid", ErrInvalidEscrowAction,
+			order.ID)
+	case order.Status.IsFinal():
+		return fmt.Errorf("%w: order %s is %s", ErrInvalidEscrowAction,
+			order.ID, order.Status)
+	}
+	return nil
+}
+
+// CanReleaseEscrow returns true if the buyer may release the escrow of the
+// order.
+func (order *Order) CanReleaseEscrow() bool {
+	return order.escrowReleasable() == nil && !order.Escrow.Disputed &&
+		order.Escrow.BuyerReleasedTS == nil
+}
+
+// CanDisputeEscrow returns true if the buyer may dispute the order.
+func (order *Order) CanDisputeEscrow() bool {
+	return order.escrowReleasable() == nil && !order.Escrow.Disputed &&
+		!order.Escrow.Released()
+}
+
+// needsEscrow returns true if the order should be paid in escrow.
+func (s *Store) needsEscrow(order *Order) bool {
+	minTotal := MoneyFromFloat(s.cfg.Escrow.MinTotal)
+	return minTotal > 0 && order.Total() >= minTotal
+}
+
+// orderAccount returns the wallet account where the on-chain payment address
+// of the order is generated.
+func (s *Store) orderAccount(order *Order) string {
+	if order.Escrow != nil && s.cfg.Escrow.Account != "" {
+		return s.cfg.Escrow.Account
+	}
+	return s.cfg.Account
+}
+
+// notifyOrderAdmins notifies the remote admins of the store and the local
+// client about an escrow event of an order.
+func (s *Store) notifyOrderAdmins(order *Order, msg string) {
+	for _, admin := range s.cfg.AdminRouting.Admins {
+		admin := admin
+		go func() {
+			if err := s.c.PM(admin, msg); err != nil {
+				s.log.Warnf("Unable to notify admin %s about order "+
+					"%s/%s: %v", admin.ShortLogID(),
+					order.User.ShortLogID(), order.ID, err)
+			}
+		}()
+	}
+	if s.cfg.EscrowChanged != nil {
+		s.cfg.EscrowChanged(order, msg)
+	}
+}
+
+// updateOrderEscrow loads the order, calls update to modify its escrow and
+// saves it. If both parties released the escrow, the order is completed.
+// Returns true if the order was completed.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) updateOrderEscrow(uid clientintf.UserID, id OrderID,
+	by *clientintf.UserID, update func(*Order) error) (*Order, bool, error) {
+
+	key := orderKey(uid, id)
+	order := new(Order)
+	if err := s.backend.Read(key, order); err != nil {
+		return nil, false, err
+	}
+	if err := update(order); err != nil {
+		return nil, false, err
+	}
+	if err := s.writeDoc(key, order); err != nil {
+		return nil, false, err
+	}
+
+	if !order.Escrow.Released() || !order.Status.CanTransitionTo(StatusCompleted) {
+		return order, false, nil
+	}
+	completed, err := s.updateOrderStatus(uid, id, StatusCompleted, by)
+	if err != nil {
+		return nil, false, err
+	}
+	return completed, true, nil
+}
+
+// escrowEvent records an escrow event of the order in the event log.
+func (s *Store) escrowEvent(order *Order, by *clientintf.UserID, action, note string) {
+	details := map[string]string{"escrow": action}
+	if note != "" {
+		details["note"] = note
+	}
+	s.logOrderEvent(EventOrderEscrow, by, order, details)
+	s.log.Infof("Escrow of order %s/%s: %s", order.User.ShortLogID(),
+		order.ID, action)
+}
+
+// ReleaseEscrow releases the escrow of the order on behalf of the seller (the
+// local client). The order is completed if the buyer already released it.
+func (s *Store) ReleaseEscrow(uid clientintf.UserID, id OrderID) (*Order, error) {
+	s.mtx.Lock()
+	order, completed, err := s.sellerReleaseEscrow(uid, id, nil)
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if completed {
+		s.notifyStatusChanged(order)
+	}
+	return order, nil
+}
+
+// sellerReleaseEscrow releases the escrow of the order on behalf of the
+// seller. by is the admin that released it or nil if it was released by the
+// local client.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) sellerReleaseEscrow(uid clientintf.UserID, id OrderID,
+	by *clientintf.UserID) (*Order, bool, error) {
+
+	order, completed, err := s.updateOrderEscrow(uid, id, by, func(order *Order) error {
+		if err := order.escrowReleasable(); err != nil {
+			return err
+		}
+		if order.Escrow.Disputed {
+			return fmt.Errorf("%w: order %s is disputed",
+				ErrInvalidEscrowAction, order.ID)
+		}
+		if order.Escrow.SellerReleasedTS != nil {
+			return fmt.Errorf("%w: escrow of order %s already "+
+				"released", ErrInvalidEscrowAction, order.ID)
+		}
+		now := time.Now()
+		order.Escrow.SellerReleasedTS = &now
+		order.Escrow.SellerReleasedBy = by
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	s.escrowEvent(order, by, "seller_released", "")
+	return order, completed, nil
+}
+
+// ResolveEscrowDispute clears the dispute of the order (for example, after
+// the admins and the buyer reach an agreement), allowing its escrow to be
+// released again.
+func (s *Store) ResolveEscrowDispute(uid clientintf.UserID, id OrderID, note string) (*Order, error) {
+	s.mtx.Lock()
+	order, err := s.resolveEscrowDispute(uid, id, note, nil)
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.notifyDisputeResolved(order, note)
+	return order, nil
+}
+
+// resolveEscrowDispute clears the dispute of the order.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) resolveEscrowDispute(uid clientintf.UserID, id OrderID,
+	note string, by *clientintf.UserID) (*Order, error) {
+
+	order, _, err := s.updateOrderEscrow(uid, id, by, func(order *Order) error {
+		if order.Escrow == nil || !order.Escrow.Disputed {
+			return fmt.Errorf("%w: order %s is not disputed",
+				ErrInvalidEscrowAction, id)
+		}
+		now := time.Now()
+		order.Escrow.Disputed = false
+		order.Escrow.DisputeResolvedTS = &now
+		order.Escrow.DisputeResolvedBy = by
+		if note != "" {
+			order.Comments = append(order.Comments, OrderComment{
+				Timestamp: now,
+				FromAdmin: true,
+				Comment:   "Dispute resolved: " + note,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.escrowEvent(order, by, "dispute_resolved", note)
+	return order, nil
+}
+
+// notifyDisputeResolved sends the receipt of the resolution of the dispute of
+// the order to the buyer.
+func (s *Store) notifyDisputeResolved(order *Order, note string) {
+	msg := fmt.Sprintf("The dispute of your order %s/%s was resolved",
+		order.User.ShortLogID(), order.ID)
+	if note != "" {
+		msg += fmt.Sprintf(" (%s)", note)
+	}
+	if order.Status == StatusCompleted {
+		msg += " and the order is completed"
+	}
+	s.sendOrderReceipt(order, msg)
+}
+
+// escrowActionReply returns the reply of an escrow action of the buyer or of
+// an admin.
+func escrowActionReply(title, msg, backLink string) *rpc.RMFetchResourceReply {
+	w := &bytes.Buffer{}
+	w.WriteString(fmt.Sprintf("# %s\n\n", title))
+	w.WriteString(msg + "\n\n")
+	w.WriteString(fmt.Sprintf("[Back to Order](%s)\n", backLink))
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}
+}
+
+func (s *Store) handleOrderEscrowRelease(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var id OrderID
+	if err := id.FromString(request.Path[1]); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("invalid order id"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	s.mtx.Lock()
+	order, completed, err := s.updateOrderEscrow(uid, id, &uid, func(order *Order) error {
+		if !order.CanReleaseEscrow() {
+			if err := order.escrowReleasable(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: escrow of order %s cannot be "+
+				"released", ErrInvalidEscrowAction, order.ID)
+		}
+		now := time.Now()
+		order.Escrow.BuyerReleasedTS = &now
+		return nil
+	})
+	if err == nil {
+		s.escrowEvent(order, &uid, "buyer_released", "")
+	}
+	s.mtx.Unlock()
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("order not found"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	case errors.Is(err, ErrInvalidEscrowAction):
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte(err.Error()),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	s.notifyOrderAdmins(order, fmt.Sprintf("The buyer confirmed the receipt "+
+		"of order %s/%s and released its escrow", uid.ShortLogID(), id))
+	msg := "You confirmed the receipt of the order. The payment will be " +
+		"released once the store also releases it."
+	if completed {
+		s.notifyStatusChanged(order)
+		msg = "You confirmed the receipt of the order. The order is completed."
+	}
+	return escrowActionReply("Escrow Released", msg,
+		fmt.Sprintf("/order/%s", id)), nil
+}
+
+func (s *Store) handleOrderEscrowDispute(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var id OrderID
+	if err := id.FromString(request.Path[1]); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("invalid order id"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+	var formData struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("request data not valid json"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+	reason := strings.TrimSpace(formData.Reason)
+	if reason == "" {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("the reason of the dispute is required"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	s.mtx.Lock()
+	order, _, err := s.updateOrderEscrow(uid, id, &uid, func(order *Order) error {
+		if !order.CanDisputeEscrow() {
+			if err := order.escrowReleasable(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: order %s cannot be disputed",
+				ErrInvalidEscrowAction, order.ID)
+		}
+		now := time.Now()
+		order.Escrow.Disputed = true
+		order.Escrow.DisputeReason = reason
+		order.Escrow.DisputeTS = &now
+		order.Comments = append(order.Comments, OrderComment{
+			Timestamp: now,
+			Comment:   "Dispute: " + reason,
+		})
+		return nil
+	})
+	if err == nil {
+		s.escrowEvent(order, &uid, "disputed", reason)
+	}
+	s.mtx.Unlock()
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("order not found"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	case errors.Is(err, ErrInvalidEscrowAction):
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte(err.Error()),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	s.notifyOrderAdmins(order, fmt.Sprintf("The buyer disputed order %s/%s: %s",
+		uid.ShortLogID(), id, reason))
+	return escrowActionReply("Order Disputed", "The store admins were "+
+		"notified of the dispute. The payment remains held until the "+
+		"dispute is resolved.", fmt.Sprintf("/order/%s", id)), nil
+}
+
+// handleAdminOrderEscrow handles the escrow actions of admins:
+// /admin/orderescrow/<uid>/<id>/release and
+// /admin/orderescrow/<uid>/<id>/resolve.
+func (s *Store) handleAdminOrderEscrow(ctx context.Context, admin clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if len(request.Path) < 5 {
+		return nil, fmt.Errorf("path has < 5 elements")
+	}
+	var uid clientintf.UserID
+	if err := uid.FromString(request.Path[2]); err != nil {
+		return nil, err
+	}
+	var oid OrderID
+	if err := oid.FromString(request.Path[3]); err != nil {
+		return nil, err
+	}
+
+	var formData struct {
+		Note string `json:"note"`
+	}
+	if len(request.Data) > 0 {
+		if err := json.Unmarshal(request.Data, &formData); err != nil {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data:   []byte(err.Error()),
+			}, nil
+		}
+	}
+	note := strings.TrimSpace(formData.Note)
+
+	var order *Order
+	var completed bool
+	var err error
+	var title, msg string
+	s.mtx.Lock()
+	switch request.Path[4] {
+	case "release":
+		order, completed, err = s.sellerReleaseEscrow(uid, oid, &admin)
+		title = "Escrow Released"
+		msg = "The escrow was released. The order will be completed once " +
+			"the buyer confirms its receipt."
+		if completed {
+			msg = "The escrow was released and the order is completed."
+		}
+	case "resolve":
+		order, err = s.resolveEscrowDispute(uid, oid, note, &admin)
+		title = "Dispute Resolved"
+		msg = "The dispute was resolved."
+	default:
+		err = fmt.Errorf("%w: unknown action %q", ErrInvalidEscrowAction,
+			request.Path[4])
+	}
+	s.mtx.Unlock()
+	if errors.Is(err, ErrInvalidEscrowAction) {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(err.Error()),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case request.Path[4] == "resolve":
+		s.notifyDisputeResolved(order, note)
+	case completed:
+		s.notifyStatusChanged(order)
+	}
+	return escrowActionReply(title, msg, path.Join("/admin/order",
+		uid.String(), oid.String())), nil
+}
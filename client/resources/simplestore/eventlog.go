@@ -0,0 +1,383 @@
+package simplestore
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// eventLogFile is the name of the file, in the store root, with the log of
+// store events.
+const eventLogFile = "events.log"
+
+// Types of the events recorded in the event log.
+const (
+	EventProductCreated  = "product.created"
+	EventProductUpdated  = "product.updated"
+	EventProductArchived = "product.archived"
+	EventProductRestored = "product.restored"
+	EventProductDeleted  = "product.deleted"
+	EventStockSet        = "stock.set"
+	EventCartCreated     = "cart.created"
+	EventCartItemAdded   = "cart.add"
+	EventCartItemUpdated = "cart.update"
+	EventCartCleared     = "cart.cleared"
+	EventCartCoupon      = "cart.coupon"
+	EventCartGift        = "cart.gift"
+	EventOrderPlaced     = "order.placed"
+	EventOrderStatus     = "order.status"
+	EventOrderRefund     = "order.refund"
+	EventOrderEscrow     = "order.escrow"
+
+	EventInventorySync     = "inventory.sync"
+	EventInventoryConflict = "inventory.conflict"
+
+	EventSubscriptionStarted  = "subscription.started"
+	EventSubscriptionRenewed  = "subscription.renewed"
+	EventSubscriptionCanceled = "subscription.canceled"
+	EventSubscriptionLapsed   = "subscription.lapsed"
+)
+
+// EventLogEntry is an entry of the store event log. Each entry includes the
+// hash of the previous entry, such that changing or removing any entry breaks
+// the hash chain from that entry on.
+type EventLogEntry struct {
+	Seq       uint64             `json:"seq"`
+	Timestamp time.Time          `json:"ts"`
+	Type      string             `json:"type"`
+	Actor     *clientintf.UserID `json:"actor,omitempty"`
+	Details   map[string]string  `json:"details,omitempty"`
+	PrevHash  string             `json:"prev_hash"`
+	Hash      string             `json:"hash"`
+}
+
+// computeHash returns the hash of the entry, which covers all of its fields
+// except the hash itself.
+func (e *EventLogEntry) computeHash() (string, error) {
+	c := *e
+	c.Hash = ""
+	b, err := json.Marshal(&c)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// eventLog is the append-only, hash-chained log of store events.
+type eventLog struct {
+	mtx      sync.Mutex
+	fname    string
+	lastSeq  uint64
+	lastHash string
+}
+
+// readEventLog calls fn for each complete entry of the event log file. It
+// returns the offset of the end of the last complete entry.
+func readEventLog(fname string, fn func(e *EventLogEntry) error) (int64, error) {
+	f, err := os.Open(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// A last line without a newline is an entry torn by
+			// a crash while it was written.
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+		var e EventLogEntry
+		if err := json.Unmarshal(bytes.TrimSpace(line), &e); err != nil {
+			return offset, fmt.Errorf("unable to decode entry after "+
+				"offset %d: %v", offset, err)
+		}
+		if err := fn(&e); err != nil {
+			return offset, err
+		}
+		offset += int64(len(line))
+	}
+}
+
+// openEventLog opens the event log file, to append new entries after its last
+// entry.
+func openEventLog(fname string) (*eventLog, error) {
+	el := &eventLog{fname: fname}
+	end, err := readEventLog(fname, func(e *EventLogEntry) error {
+		el.lastSeq, el.lastHash = e.Seq, e.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read event log %s: %v", fname, err)
+	}
+
+	// Drop an entry torn by a crash, so that new entries start on a new
+	// line.
+	if fi, err := os.Stat(fname); err == nil && fi.Size() > end {
+		if err := os.Truncate(fname, end); err != nil {
+			return nil, err
+		}
+	}
+	return el, nil
+}
+
+// append appends a new entry to the log.
+func (el *eventLog) append(typ string, actor *clientintf.UserID, details map[string]string) error {
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	e := &EventLogEntry{
+		Seq:       el.lastSeq + 1,
+		Timestamp: time.Now().UTC(),
+		Type:      typ,
+		Actor:     actor,
+		Details:   details,
+		PrevHash:  el.lastHash,
+	}
+	var err error
+	if e.Hash, err = e.computeHash(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	f, err := os.OpenFile(el.fname, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	el.lastSeq, el.lastHash = e.Seq, e.Hash
+	return nil
+}
+
+// VerifyEventLog verifies the hash chain of the event log file. It returns the
+// number of entries verified, or an error describing the first entry that
+// does not match the chain.
+func VerifyEventLog(fname string) (int, error) {
+	var n int
+	var prevHash string
+	_, err := readEventLog(fname, func(e *EventLogEntry) error {
+		if e.Seq != uint64(n)+1 {
+			return fmt.Errorf("entry %d has sequence number %d", n+1, e.Seq)
+		}
+		if e.PrevHash != prevHash {
+			return fmt.Errorf("entry %d does not follow the previous "+
+				"entry", e.Seq)
+		}
+		hash, err := e.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != e.Hash {
+			return fmt.Errorf("entry %d does not match its hash", e.Seq)
+		}
+		prevHash = e.Hash
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// VerifyEventLog verifies the hash chain of the event log of the store.
+func (s *Store) VerifyEventLog() (int, error) {
+	s.events.mtx.Lock()
+	defer s.events.mtx.Unlock()
+	return VerifyEventLog(s.events.fname)
+}
+
+// EventLogTail returns the last n entries of the event log of the store.
+func (s *Store) EventLogTail(n int) ([]EventLogEntry, error) {
+	s.events.mtx.Lock()
+	defer s.events.mtx.Unlock()
+
+	var res []EventLogEntry
+	_, err := readEventLog(s.events.fname, func(e *EventLogEntry) error {
+		res = append(res, *e)
+		if len(res) > n {
+			res = res[1:]
+		}
+		return nil
+	})
+	return res, err
+}
+
+// hasTypePrefix returns true if the type of the entry has one of the
+// prefixes, or if no prefixes are specified.
+func (e *EventLogEntry) hasTypePrefix(prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(e.Type, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventLogSince returns up to max entries of the event log of the store after
+// the entry with sequence number seq. If prefixes are specified, only entries
+// with a type that has one of the prefixes are returned.
+func (s *Store) EventLogSince(seq uint64, max int, prefixes ...string) ([]EventLogEntry, error) {
+	s.events.mtx.Lock()
+	defer s.events.mtx.Unlock()
+
+	errDone := errors.New("done")
+	var res []EventLogEntry
+	_, err := readEventLog(s.events.fname, func(e *EventLogEntry) error {
+		if e.Seq <= seq || !e.hasTypePrefix(prefixes) {
+			return nil
+		}
+		res = append(res, *e)
+		if len(res) >= max {
+			return errDone
+		}
+		return nil
+	})
+	if errors.Is(err, errDone) {
+		err = nil
+	}
+	return res, err
+}
+
+// EventLogFilter filters the entries returned by ReadEventLog. Zero fields do
+// not filter the entries.
+type EventLogFilter struct {
+	// From and To are the range [From, To) of the time of the entries.
+	From time.Time
+	To   time.Time
+
+	// Prefixes are the prefixes of the types of the entries.
+	Prefixes []string
+
+	// Actor is the user that performed the events.
+	Actor *clientintf.UserID
+
+	// Order is the order ("<uid>/<id>") the events are about.
+	Order string
+
+	// SKU is the SKU of the product the events are about.
+	SKU string
+}
+
+// matches returns true if the entry matches the filter.
+func (f *EventLogFilter) matches(e *EventLogEntry) bool {
+	switch {
+	case e.Timestamp.Before(f.From):
+		return false
+	case !f.To.IsZero() && !e.Timestamp.Before(f.To):
+		return false
+	case !e.hasTypePrefix(f.Prefixes):
+		return false
+	case f.Actor != nil && (e.Actor == nil || *e.Actor != *f.Actor):
+		return false
+	case f.Order != "" && e.Details["order"] != f.Order:
+		return false
+	case f.SKU != "" && e.Details["sku"] != f.SKU:
+		return false
+	}
+	return true
+}
+
+// ReadEventLog returns the entries of the event log file that match the
+// filter, in the order they were recorded.
+func ReadEventLog(fname string, filter EventLogFilter) ([]EventLogEntry, error) {
+	var res []EventLogEntry
+	_, err := readEventLog(fname, func(e *EventLogEntry) error {
+		if filter.matches(e) {
+			res = append(res, *e)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// ReadEventLog returns the entries of the event log of the store that match
+// the filter.
+func (s *Store) ReadEventLog(filter EventLogFilter) ([]EventLogEntry, error) {
+	s.events.mtx.Lock()
+	defer s.events.mtx.Unlock()
+	return ReadEventLog(s.events.fname, filter)
+}
+
+// OrderAuditTrail returns the entries of the event log about the order: the
+// changes to the cart of the user since the cart was created until the order
+// was placed, followed by the placement of the order and its later changes
+// (status changes, refunds, etc).
+func (s *Store) OrderAuditTrail(uid clientintf.UserID, id OrderID) ([]EventLogEntry, error) {
+	s.events.mtx.Lock()
+	defer s.events.mtx.Unlock()
+
+	order := fmt.Sprintf("%s/%s", uid, id)
+	var res, cart []EventLogEntry
+	placed := false
+	_, err := readEventLog(s.events.fname, func(e *EventLogEntry) error {
+		switch {
+		case e.Details["order"] == order:
+			if e.Type == EventOrderPlaced && !placed {
+				res = append(res, cart...)
+				placed = true
+			}
+			res = append(res, *e)
+		case placed:
+		case e.Type == EventCartCreated && e.Actor != nil && *e.Actor == uid:
+			cart = append(cart[:0], *e)
+		case strings.HasPrefix(e.Type, "cart.") && e.Actor != nil && *e.Actor == uid:
+			cart = append(cart, *e)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// logEvent records an event in the event log of the store. Failures are
+// logged but do not fail the action that generated the event.
+func (s *Store) logEvent(typ string, actor *clientintf.UserID, details map[string]string) {
+	if err := s.events.append(typ, actor, details); err != nil {
+		s.log.Errorf("Unable to record %s event in the event log: %v", typ, err)
+	}
+}
+
+// logOrderEvent records an event about the order in the event log.
+func (s *Store) logOrderEvent(typ string, actor *clientintf.UserID, order *Order,
+	details map[string]string) {
+
+	if details == nil {
+		details = make(map[string]string, 2)
+	}
+	details["order"] = fmt.Sprintf("%s/%s", order.User, order.ID)
+	s.logEvent(typ, actor, details)
+}
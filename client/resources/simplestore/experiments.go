@@ -0,0 +1,348 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// experimentsFile is the name of the file, in the store root, with the
+	// A/B experiments of the store.
+	experimentsFile = "experiments.toml"
+
+	// experimentsDir tracks the viewers of each experiment.
+	experimentsDir = "experiments"
+
+	adminExperimentsTmplFile = "admin_experiments.tmpl"
+)
+
+// experimentTemplates are the templates of the pages browsed by buyers, which
+// may be the subject of experiments.
+var experimentTemplates = map[string]struct{}{
+	indexTmplFile:     {},
+	prodTmplFile:      {},
+	categoryTmplFile:  {},
+	searchTmplFile:    {},
+	addToCartTmplFile: {},
+	checkoutTmplFile:  {},
+}
+
+// Experiment is an A/B test of the layout of a page of the store. Every
+// requester is served one of the variants of the template of the page,
+// selected by a stable hash of their ID, and the orders they place are
+// attributed to the variant they were served.
+type Experiment struct {
+	// Name identifies the experiment in the orders and stats. It must be
+	// unique.
+	Name string
+
+	// Template is the template of the page (for example, "product.tmpl").
+	Template string
+
+	// Variants are the templates served instead of Template, which may be
+	// one of them (as the control variant).
+	Variants []string
+}
+
+// variant returns the variant of the experiment served to the user.
+func (e *Experiment) variant(uid clientintf.UserID) string {
+	h := fnv.New32a()
+	h.Write([]byte(e.Name))
+	h.Write(uid[:])
+	return e.Variants[h.Sum32()%uint32(len(e.Variants))]
+}
+
+// validateExperiments returns an error if the experiments are invalid.
+func validateExperiments(exps []Experiment) error {
+	names := make(map[string]struct{}, len(exps))
+	tmpls := make(map[string]struct{}, len(exps))
+	for _, e := range exps {
+		if e.Name == "" {
+			return errors.New("experiment has no name")
+		}
+		if strings.ContainsAny(e.Name, `/\.`) {
+			return fmt.Errorf("experiment name %q has invalid chars", e.Name)
+		}
+		if _, ok := experimentTemplates[e.Template]; !ok {
+			return fmt.Errorf("experiment %q: template %q does not "+
+				"support experiments", e.Name, e.Template)
+		}
+		if len(e.Variants) < 2 {
+			return fmt.Errorf("experiment %q needs at least two variants", e.Name)
+		}
+		if _, ok := names[e.Name]; ok {
+			return fmt.Errorf("duplicate experiment %q", e.Name)
+		}
+		if _, ok := tmpls[e.Template]; ok {
+			return fmt.Errorf("experiment %q: template %q is already "+
+				"the subject of another experiment", e.Name, e.Template)
+		}
+		names[e.Name] = struct{}{}
+		tmpls[e.Template] = struct{}{}
+	}
+	return nil
+}
+
+// loadExperiments loads the experiments of the file. A missing file means no
+// experiments.
+func loadExperiments(fname string) ([]Experiment, error) {
+	data, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Experiments []Experiment
+	}
+	if err := toml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unable to decode experiments %s: %v", fname, err)
+	}
+	if err := validateExperiments(file.Experiments); err != nil {
+		return nil, fmt.Errorf("experiments %s: %v", fname, err)
+	}
+	return file.Experiments, nil
+}
+
+// experiments returns the experiments of the store. The experiments of the
+// experiments file take precedence over the ones in the config.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) experiments() []Experiment {
+	if len(s.exps) > 0 {
+		return s.exps
+	}
+	return s.cfg.Experiments
+}
+
+// experimentViewers are the users that were served a variant of an
+// experiment, keyed by user ID, with the variant they were served.
+type experimentViewers struct {
+	Viewers map[string]string `json:"viewers"`
+}
+
+func experimentViewersKey(name string) string {
+	return path.Join(experimentsDir, name)
+}
+
+// loadExperimentViewers returns the viewers of the experiment.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadExperimentViewers(name string) (*experimentViewers, error) {
+	if v := s.expViewers[name]; v != nil {
+		return v, nil
+	}
+	v := &experimentViewers{}
+	err := s.backend.Read(experimentViewersKey(name), v)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if v.Viewers == nil {
+		v.Viewers = make(map[string]string)
+	}
+	if s.expViewers == nil {
+		s.expViewers = make(map[string]*experimentViewers)
+	}
+	s.expViewers[name] = v
+	return v, nil
+}
+
+// templateVariant returns the template to render for the user instead of the
+// given template, which is the variant served to the user when the template is
+// the subject of an experiment. The first time the user is served a variant,
+// they are recorded as a viewer of the variant.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) templateVariant(uid clientintf.UserID, tmplFile string) string {
+	for i := range s.experiments() {
+		e := &s.experiments()[i]
+		if e.Template != tmplFile {
+			continue
+		}
+		variant := e.variant(uid)
+		if !s.render.Has(variant) {
+			s.log.Warnf("Template %s of variant of experiment %q "+
+				"not found", variant, e.Name)
+			return tmplFile
+		}
+		if !s.isAdmin(uid) {
+			s.recordExperimentViewer(e, uid, variant)
+		}
+		return variant
+	}
+	return tmplFile
+}
+
+// recordExperimentViewer records the user as a viewer of the variant of the
+// experiment, if they were not recorded before.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) recordExperimentViewer(e *Experiment, uid clientintf.UserID, variant string) {
+	viewers, err := s.loadExperimentViewers(e.Name)
+	if err != nil {
+		s.log.Warnf("Unable to load viewers of experiment %q: %v", e.Name, err)
+		return
+	}
+	if viewers.Viewers[uid.String()] == variant {
+		return
+	}
+	viewers.Viewers[uid.String()] = variant
+	if err := s.writeDoc(experimentViewersKey(e.Name), viewers); err != nil {
+		s.log.Warnf("Unable to save viewers of experiment %q: %v", e.Name, err)
+	}
+}
+
+// orderExperiments returns the variants of the experiments served to the
+// user, keyed by experiment name, which are recorded in the orders they place.
+// Experiments the user was not served a variant of are not included.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) orderExperiments(uid clientintf.UserID) map[string]string {
+	var res map[string]string
+	for _, e := range s.experiments() {
+		viewers, err := s.loadExperimentViewers(e.Name)
+		if err != nil {
+			s.log.Warnf("Unable to load viewers of experiment %q: %v", e.Name, err)
+			continue
+		}
+		variant, ok := viewers.Viewers[uid.String()]
+		if !ok {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]string)
+		}
+		res[e.Name] = variant
+	}
+	return res
+}
+
+// ExperimentVariantStats are the conversion stats of a variant of an
+// experiment.
+type ExperimentVariantStats struct {
+	Variant string
+
+	// Viewers is the number of users (other than admins) served the
+	// variant.
+	Viewers int
+
+	// Orders is the number of orders placed by users served the variant
+	// and Sales the number of them counted as sales (paid and not
+	// canceled).
+	Orders int
+	Sales  int
+
+	// Totals are the total amounts of the sales in each currency.
+	Totals salesTotals
+}
+
+// Conversion returns the ratio of sales to viewers of the variant, or zero if
+// the variant has no viewers.
+func (v *ExperimentVariantStats) Conversion() float64 {
+	if v.Viewers == 0 {
+		return 0
+	}
+	return float64(v.Sales) / float64(v.Viewers)
+}
+
+// ExperimentStats are the conversion stats of the variants of an experiment.
+type ExperimentStats struct {
+	Name     string
+	Template string
+	Variants []ExperimentVariantStats
+}
+
+// ExperimentStats returns the conversion stats of the variants of the current
+// experiments of the store.
+func (s *Store) ExperimentStats() ([]ExperimentStats, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	exps := s.experiments()
+	if len(exps) == 0 {
+		return nil, nil
+	}
+	orders, err := s.loadAllOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ExperimentStats, 0, len(exps))
+	for _, e := range exps {
+		stats := ExperimentStats{Name: e.Name, Template: e.Template}
+		idx := make(map[string]int, len(e.Variants))
+		for i, variant := range e.Variants {
+			idx[variant] = i
+			stats.Variants = append(stats.Variants, ExperimentVariantStats{
+				Variant: variant,
+				Totals:  make(salesTotals),
+			})
+		}
+
+		viewers, err := s.loadExperimentViewers(e.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, variant := range viewers.Viewers {
+			if i, ok := idx[variant]; ok {
+				stats.Variants[i].Viewers++
+			}
+		}
+
+		for _, order := range orders {
+			i, ok := idx[order.Experiments[e.Name]]
+			if !ok {
+				continue
+			}
+			v := &stats.Variants[i]
+			v.Orders++
+			if order.Status.isSale() {
+				v.Sales++
+				v.Totals.add(order.Currency, order.Total())
+			}
+		}
+		res = append(res, stats)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+type adminExperimentsContext struct {
+	Experiments []ExperimentStats
+}
+
+// ConversionPct returns the conversion of the variant as a percentage.
+func (ctx *adminExperimentsContext) ConversionPct(v ExperimentVariantStats) string {
+	return fmt.Sprintf("%.1f%%", v.Conversion()*100)
+}
+
+func (s *Store) handleAdminExperiments(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	stats, err := s.ExperimentStats()
+	if err != nil {
+		return nil, err
+	}
+	tctx := &adminExperimentsContext{Experiments: stats}
+	w := &bytes.Buffer{}
+	err = s.render.Render(w, adminExperimentsTmplFile, tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute admin experiments template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
@@ -0,0 +1,244 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// ExportFormat is the format of exported orders.
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatJSON ExportFormat = "json"
+)
+
+// exportDateLayout is the layout of the dates of the range of exported orders
+// in the export admin page.
+const exportDateLayout = "2006-01-02"
+
+// contentType returns the content type of orders exported in the format.
+func (f ExportFormat) contentType() string {
+	if f == ExportFormatJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// Payment status of exported orders.
+const (
+	exportPaymentPaid    = "paid"
+	exportPaymentPending = "pending"
+	exportPaymentUnpaid  = "unpaid"
+)
+
+// ExportedOrder is an order as exported for bookkeeping. Fiat amounts are in
+// the currency of the order, while DCR amounts are converted using the
+// exchange rate quoted in the order.
+type ExportedOrder struct {
+	User          clientintf.UserID `json:"user"`
+	ID            OrderID           `json:"id"`
+	Status        OrderStatus       `json:"status"`
+	PaymentStatus string            `json:"payment_status"`
+	PayType       PayType           `json:"pay_type"`
+	PlacedTS      time.Time         `json:"placed_ts"`
+	PaidTS        *time.Time        `json:"paid_ts,omitempty"`
+	Currency      string            `json:"currency"`
+	Subtotal      Money             `json:"subtotal"`
+	Discount      Money             `json:"discount"`
+	ShipCharge    Money             `json:"ship_charge"`
+	ShipMethod    string            `json:"ship_method,omitempty"`
+	Taxes         Money             `json:"taxes"`
+	Total         Money             `json:"total"`
+	ExchangeRate  float64           `json:"exchange_rate"`
+	TotalDCR      float64           `json:"total_dcr"`
+	PaidDCR       float64           `json:"paid_dcr"`
+	PaidTxID      string            `json:"paid_txid,omitempty"`
+	Coupon        string            `json:"coupon,omitempty"`
+	Referral      string            `json:"referral,omitempty"`
+}
+
+// exportedOrder returns the order as exported for bookkeeping.
+func exportedOrder(order *Order) *ExportedOrder {
+	payStatus := exportPaymentUnpaid
+	switch {
+	case order.PaidTS != nil:
+		payStatus = exportPaymentPaid
+	case order.AwaitingPayment():
+		payStatus = exportPaymentPending
+	}
+	return &ExportedOrder{
+		User:          order.User,
+		ID:            order.ID,
+		Status:        order.Status,
+		PaymentStatus: payStatus,
+		PayType:       order.PayType,
+		PlacedTS:      order.PlacedTS,
+		PaidTS:        order.PaidTS,
+		Currency:      order.CurrencyCode(),
+		Subtotal:      order.Cart.Subtotal(),
+		Discount:      order.Cart.Discount,
+		ShipCharge:    order.ShipCharge,
+		ShipMethod:    order.ShipMethod,
+		Taxes:         order.Taxes.Total(),
+		Total:         order.Total(),
+		ExchangeRate:  order.ExchangeRate,
+		TotalDCR:      order.TotalDCR().ToCoin(),
+		PaidDCR:       order.PaidAmount.ToCoin(),
+		PaidTxID:      order.PaidTxID,
+		Coupon:        order.Cart.Coupon,
+		Referral:      order.Referral,
+	}
+}
+
+// exportCSVHeader is the header of orders exported as CSV.
+var exportCSVHeader = []string{"user", "id", "status", "payment_status",
+	"pay_type", "placed_ts", "paid_ts", "currency", "subtotal", "discount",
+	"ship_charge", "ship_method", "taxes", "total", "exchange_rate",
+	"total_dcr", "paid_dcr", "paid_txid", "coupon", "referral"}
+
+// csvRecord returns the exported order as a CSV record.
+func (eo *ExportedOrder) csvRecord() []string {
+	fmtFloat := func(v float64, prec int) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+	var paidTS string
+	if eo.PaidTS != nil {
+		paidTS = eo.PaidTS.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		eo.User.String(),
+		eo.ID.String(),
+		string(eo.Status),
+		eo.PaymentStatus,
+		string(eo.PayType),
+		eo.PlacedTS.UTC().Format(time.RFC3339),
+		paidTS,
+		eo.Currency,
+		eo.Subtotal.String(),
+		eo.Discount.String(),
+		eo.ShipCharge.String(),
+		eo.ShipMethod,
+		eo.Taxes.String(),
+		eo.Total.String(),
+		fmtFloat(eo.ExchangeRate, -1),
+		fmtFloat(eo.TotalDCR, 8),
+		fmtFloat(eo.PaidDCR, 8),
+		eo.PaidTxID,
+		eo.Coupon,
+		eo.Referral,
+	}
+}
+
+// ExportOrders exports the orders placed in the range [from, to), sorted by
+// placement time, in the given format. A zero to exports all orders placed
+// after from.
+func (s *Store) ExportOrders(from, to time.Time, format ExportFormat) ([]byte, error) {
+	if format != ExportFormatCSV && format != ExportFormatJSON {
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	s.mtx.Lock()
+	orders, err := s.loadAllOrders()
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	exported := make([]*ExportedOrder, 0, len(orders))
+	for _, order := range orders {
+		if order.PlacedTS.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !order.PlacedTS.Before(to) {
+			continue
+		}
+		exported = append(exported, exportedOrder(order))
+	}
+	sort.SliceStable(exported, func(i, j int) bool {
+		return exported[i].PlacedTS.Before(exported[j].PlacedTS)
+	})
+
+	w := &bytes.Buffer{}
+	if format == ExportFormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(exported); err != nil {
+			return nil, err
+		}
+		return w.Bytes(), nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, eo := range exported {
+		if err := cw.Write(eo.csvRecord()); err != nil {
+			return nil, err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// handleAdminExportOrders exports the orders as
+// /admin/exportorders/<format>[/<from>[/<to>]], where from and to are dates
+// in the YYYY-MM-DD format (to is exclusive).
+func (s *Store) handleAdminExportOrders(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	badRequest := func(msg string, args ...interface{}) *rpc.RMFetchResourceReply {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(fmt.Sprintf(msg, args...)),
+		}
+	}
+
+	format := ExportFormatCSV
+	if len(request.Path) > 2 {
+		format = ExportFormat(request.Path[2])
+	}
+	if format != ExportFormatCSV && format != ExportFormatJSON {
+		return badRequest("unknown export format %q", format), nil
+	}
+
+	var from, to time.Time
+	var err error
+	if len(request.Path) > 3 {
+		from, err = time.ParseInLocation(exportDateLayout, request.Path[3], time.Local)
+		if err != nil {
+			return badRequest("invalid start date %q", request.Path[3]), nil
+		}
+	}
+	if len(request.Path) > 4 {
+		to, err = time.ParseInLocation(exportDateLayout, request.Path[4], time.Local)
+		if err != nil {
+			return badRequest("invalid end date %q", request.Path[4]), nil
+		}
+	}
+
+	data, err := s.ExportOrders(from, to, format)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   data,
+		Status: rpc.ResourceStatusOk,
+		Meta: map[string]string{
+			rpc.ResourceMetaContentType: format.contentType(),
+		},
+	}, nil
+}
@@ -0,0 +1,185 @@
+package simplestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// maxGiftMessageLen is the max length of the message sent along with a gift.
+const maxGiftMessageLen = 500
+
+// OrderGift records the recipient of an order placed on behalf of another
+// user. The digital files of gift orders are delivered to the recipient and
+// the recipient is notified when the order is paid, shipped and completed,
+// while the payer (the user that placed the order) still pays for the order
+// and receives its receipts.
+type OrderGift struct {
+	Recipient clientintf.UserID `json:"recipient"`
+
+	// RecipientNick is the nick of the recipient when the order was
+	// placed.
+	RecipientNick string `json:"recipient_nick"`
+
+	// Message is the message of the payer sent to the recipient along
+	// with the gift.
+	Message string `json:"message,omitempty"`
+}
+
+// IsGift returns true if the order was placed on behalf of another user.
+func (order *Order) IsGift() bool {
+	return order.Gift != nil
+}
+
+// DeliverTo returns the user that receives the digital files of the order:
+// the recipient of gift orders or the user that placed the order otherwise.
+func (order *Order) DeliverTo() clientintf.UserID {
+	if order.Gift != nil {
+		return order.Gift.Recipient
+	}
+	return order.User
+}
+
+// handleSetGiftRecipient sets (or clears, when empty) the recipient of the
+// order to be placed with the items of the cart of the user.
+func (s *Store) handleSetGiftRecipient(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if request.Data == nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data is empty"),
+		}, nil
+	}
+
+	formData := struct {
+		Recipient string `json:"recipient"`
+		Message   string `json:"message"`
+	}{}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+	recipient := strings.TrimSpace(formData.Recipient)
+	message := strings.TrimSpace(formData.Message)
+	if len(message) > maxGiftMessageLen {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("gift message is longer than %d "+
+				"characters", maxGiftMessageLen)),
+		}, nil
+	}
+
+	defer s.backend.LockUser(uid)()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	fname := cartKey(uid)
+	var cart Cart
+	err := s.backend.Read(fname, &cart)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("No items in the cart"),
+		}, nil
+	}
+
+	var msg string
+	if recipient == "" {
+		cart.Gift = nil
+		msg = "The order is no longer a gift"
+	} else {
+		// The recipient must be a user the store has KXd with, so
+		// that the store can deliver the gift.
+		recipientID, err := s.c.UIDByNick(recipient)
+		if err != nil {
+			return s.renderCart(&cart, fmt.Sprintf("Unable to gift the "+
+				"order: user %q not found", strescape.Nick(recipient)))
+		}
+		recipientNick, _ := s.c.UserNick(recipientID)
+		if recipientID == uid {
+			return s.renderCart(&cart, "Unable to gift the order: the "+
+				"recipient cannot be yourself")
+		}
+		cart.Gift = &OrderGift{
+			Recipient:     recipientID,
+			RecipientNick: recipientNick,
+			Message:       message,
+		}
+		msg = fmt.Sprintf("The order will be gifted to %s",
+			strescape.Nick(recipientNick))
+	}
+	cart.Updated = time.Now()
+
+	if err := s.writeDoc(fname, &cart); err != nil {
+		return nil, err
+	}
+	details := map[string]string{}
+	if cart.Gift != nil {
+		details["recipient"] = cart.Gift.Recipient.String()
+	}
+	s.logEvent(EventCartGift, &uid, details)
+	return s.renderCart(&cart, msg)
+}
+
+// notifyGiftRecipient notifies the recipient of the gift order of the change
+// of its status to paid, shipped or completed.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) notifyGiftRecipient(order *Order) {
+	gift := order.Gift
+	if gift == nil || gift.Recipient == s.c.PublicID() {
+		return
+	}
+
+	nick, _ := s.c.UserNick(order.User)
+	var b strings.Builder
+	switch order.Status {
+	case StatusPaid, StatusBackordered:
+		fmt.Fprintf(&b, "%s sent you a gift from the store (order %s/%s)",
+			strescape.Nick(nick), order.User.ShortLogID(), order.ID)
+		if gift.Message != "" {
+			fmt.Fprintf(&b, " with the message: %s", gift.Message)
+		}
+		b.WriteString("\nThe gift includes:\n")
+		for _, item := range order.Cart.Items {
+			fmt.Fprintf(&b, "  %d x %s\n", item.Quantity, item.Product.Title)
+		}
+		if s.hasDigitalFiles(order) {
+			b.WriteString("Its files are being sent to you")
+		}
+	case StatusShipped:
+		fmt.Fprintf(&b, "Your gift from %s (order %s/%s) was shipped",
+			strescape.Nick(nick), order.User.ShortLogID(), order.ID)
+		if order.TrackingNumber != "" {
+			fmt.Fprintf(&b, " with tracking number %s", order.TrackingNumber)
+		}
+	case StatusCompleted:
+		fmt.Fprintf(&b, "Your gift from %s (order %s/%s) was completed",
+			strescape.Nick(nick), order.User.ShortLogID(), order.ID)
+	default:
+		return
+	}
+
+	msg := strings.TrimSpace(b.String())
+	recipient, uid, id := gift.Recipient, order.User, order.ID
+	go func() {
+		if err := s.c.PM(recipient, msg); err != nil {
+			s.log.Warnf("Unable to notify recipient %s of gift order "+
+				"%s/%s: %v", recipient.ShortLogID(),
+				uid.ShortLogID(), id, err)
+		}
+	}()
+}
@@ -6,16 +6,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
 	"github.com/companyzero/bisonrelay/internal/jsonfile"
 	"github.com/companyzero/bisonrelay/internal/strescape"
 	"github.com/companyzero/bisonrelay/rpc"
+	"golang.org/x/exp/slices"
 )
 
 var orderFnamePattern = jsonfile.MakeDecimalFilePattern("order-", ".json", false)
@@ -32,12 +34,19 @@ func (s *Store) handleIndex(ctx context.Context, uid clientintf.UserID,
 	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
 
 	s.mtx.Lock()
+	products := make(map[string]*Product, len(s.products))
+	for sku, prod := range s.products {
+		if prod.Available() {
+			products[sku] = prod
+		}
+	}
 	tmplCtx := &indexContext{
-		Products: s.products,
-		IsAdmin:  uid == s.c.PublicID(),
+		Products: products,
+		Catalog:  s.catalog,
+		IsAdmin:  s.isAdmin(uid),
 	}
 	w := &bytes.Buffer{}
-	err := s.tmpl.ExecuteTemplate(w, indexTmplFile, tmplCtx)
+	err := s.render.Render(w, s.templateVariant(uid, indexTmplFile), tmplCtx)
 	s.mtx.Unlock()
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute index template: %v", err)
@@ -54,17 +63,80 @@ func (s *Store) handleProduct(ctx context.Context, uid clientintf.UserID,
 
 	s.mtx.Lock()
 	prod := s.products[request.Path[1]]
+	if prod == nil || !prod.Available() {
+		s.mtx.Unlock()
+		return s.handleNotFound(ctx, uid, request)
+	}
+	// Variants of the product page of experiments are cached apart from
+	// the default page.
+	tmpl := s.templateVariant(uid, prodTmplFile)
+	pageKey := prod.SKU
+	if tmpl != prodTmplFile {
+		pageKey += "/" + tmpl
+	}
+	page, pageGen, cached := s.cachedPage(pageKey)
+	var variants []*Product
+	var components []bundleComponent
+	var bundleValue Money
+	if !cached {
+		variants = s.productVariants(prod)
+		components, bundleValue = s.bundleComponents(prod)
+	}
 	s.mtx.Unlock()
 
-	if prod == nil {
-		return s.handleNotFound(ctx, uid, request)
+	if cached {
+		return &rpc.RMFetchResourceReply{
+			Data:   page,
+			Status: rpc.ResourceStatusOk,
+		}, nil
 	}
 
+	tmplCtx := &productContext{
+		Product:    prod,
+		Variants:   variants,
+		Currency:   s.currency(),
+		ImageEmbed: s.productImageEmbed(prod),
+
+		Components:  components,
+		BundleValue: bundleValue,
+	}
+	for _, img := range prod.Images {
+		if embed := s.assetEmbed(img, prod.Title); embed != "" {
+			tmplCtx.Gallery = append(tmplCtx.Gallery, embed)
+		}
+	}
 	w := &bytes.Buffer{}
-	err := s.tmpl.ExecuteTemplate(w, prodTmplFile, prod)
+	err := s.render.Render(w, tmpl, tmplCtx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute product template: %v", err)
 	}
+	s.cachePage(pageKey, pageGen, w.Bytes())
+
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+func (s *Store) handleCategory(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	cat := s.findCategory(strings.Join(request.Path[1:], "/"))
+	if cat == nil {
+		return s.handleNotFound(ctx, uid, request)
+	}
+
+	tmplCtx := &categoryContext{
+		Category: cat,
+		IsAdmin:  s.isAdmin(uid),
+	}
+	w := &bytes.Buffer{}
+	err := s.render.Render(w, s.templateVariant(uid, categoryTmplFile), tmplCtx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute category template: %v", err)
+	}
 
 	return &rpc.RMFetchResourceReply{
 		Data:   w.Bytes(),
@@ -82,9 +154,13 @@ func (s *Store) handleAddToCart(ctx context.Context, uid clientintf.UserID,
 		}, nil
 	}
 
+	// Variants are selected either by their full SKU or by the SKU of the
+	// product and the SKU suffix of the variant.
 	formData := struct {
-		SKU string `json:"sku"`
-		Qty uint32 `json:"qty"`
+		SKU     string `json:"sku"`
+		Variant string `json:"variant"`
+		Qty     uint32 `json:"qty"`
+		Amount  string `json:"amount"`
 	}{}
 
 	if err := json.Unmarshal(request.Data, &formData); err != nil {
@@ -93,51 +169,109 @@ func (s *Store) handleAddToCart(ctx context.Context, uid clientintf.UserID,
 			Data:   []byte("request data not valid json"),
 		}, nil
 	}
-	fname := filepath.Join(s.root, cartsDir, uid.String())
+	fname := cartKey(uid)
 	var cart Cart
 
+	defer s.backend.LockUser(uid)()
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	prod, ok := s.products[formData.SKU]
+	prod, ok := s.product(formData.SKU + formData.Variant)
 	if !ok {
 		return nil, fmt.Errorf("product does not exist")
 	}
+	if prod.HasVariants() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("Select a variant of product %q",
+				prod.Title)),
+		}, nil
+	}
+	if !prod.Available() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(fmt.Sprintf("Product %q is not available", prod.Title)),
+		}, nil
+	}
+	if prod.CustomQuote {
+		return customQuoteReply(prod), nil
+	}
+	if prod.PayWhatYouWant {
+		// The cart holds the product at the price chosen by the
+		// buyer.
+		amount, _ := ParseMoney(formData.Amount)
+		if msg := checkPayWhatYouWantAmount(prod, amount, s.currency()); msg != "" {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data:   []byte(msg),
+			}, nil
+		}
+		chosen := *prod
+		chosen.Price = amount
+		prod = &chosen
+	}
 
-	err := jsonfile.Read(fname, &cart)
-	if err != nil && !errors.Is(err, jsonfile.ErrNotFound) {
+	err := s.backend.Read(fname, &cart)
+	if err != nil && !errors.Is(err, ErrNotFound) {
 		return nil, err
 	}
 
-	hasItem := false
+	var cartItem *CartItem
 	for _, item := range cart.Items {
 		if item.Product.SKU == prod.SKU {
-			item.Quantity += formData.Qty
-			hasItem = true
+			cartItem = item
 			break
 		}
 	}
 
-	if !hasItem {
+	// Reject adding more units than are in stock.
+	qty := formData.Qty
+	if cartItem != nil {
+		qty += cartItem.Quantity
+	}
+	if !prod.InStock(qty) {
+		return outOfStockReply(prod), nil
+	}
+
+	if cartItem != nil {
+		cartItem.Product = prod
+		cartItem.Quantity = qty
+	} else {
 		newItem := &CartItem{
 			Product:  prod,
 			Quantity: formData.Qty,
 		}
 		cart.Items = append(cart.Items, newItem)
 	}
+	newCart := len(cart.Items) == 1 && cartItem == nil
 	cart.Updated = time.Now()
+	if newCart {
+		cart.Created = cart.Updated
+	}
+	cart.Currency = s.currency()
+	s.updateCartDiscount(&cart)
 
-	err = jsonfile.Write(fname, &cart, s.log)
+	err = s.writeDoc(fname, &cart)
 	if err != nil {
 		return nil, err
 	}
+	s.emitCartActivity(uid, &cart)
+	if newCart {
+		s.logEvent(EventCartCreated, &uid, nil)
+	}
+	s.logEvent(EventCartItemAdded, &uid, map[string]string{
+		"sku":      prod.SKU,
+		"qty":      strconv.FormatUint(uint64(formData.Qty), 10),
+		"quantity": strconv.FormatUint(uint64(qty), 10),
+		"price":    prod.Price.String(),
+	})
 
 	tmplCtx := addToCartContext{
 		Product: prod,
-		Cart:    &cart,
+		Cart:    &cartContext{Cart: &cart},
 	}
 	w := &bytes.Buffer{}
-	err = s.tmpl.ExecuteTemplate(w, addToCartTmplFile, tmplCtx)
+	err = s.render.Render(w, s.templateVariant(uid, addToCartTmplFile), tmplCtx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute product template: %v", err)
 	}
@@ -149,18 +283,33 @@ func (s *Store) handleAddToCart(ctx context.Context, uid clientintf.UserID,
 }
 
 func (s *Store) handleClearCart(ctx context.Context, uid clientintf.UserID) (*rpc.RMFetchResourceReply, error) {
-	fname := filepath.Join(s.root, cartsDir, uid.String())
-
-	err := os.Remove(fname)
-	if err != nil && !os.IsNotExist(err) {
+	unlock := s.backend.LockUser(uid)
+	s.mtx.Lock()
+	err := s.removeDoc(cartKey(uid))
+	if err == nil {
+		s.emitCartActivity(uid, &Cart{})
+		s.logEvent(EventCartCleared, &uid, nil)
+	}
+	s.mtx.Unlock()
+	unlock()
+	if err != nil {
 		return nil, err
 	}
 
-	var cart Cart
+	return s.renderCart(&Cart{}, "Cart cleared")
+}
+
+// renderCart renders the cart template with the given cart and status message.
+func (s *Store) renderCart(cart *Cart, msg string) (*rpc.RMFetchResourceReply, error) {
+	cart.Currency = s.currency()
+	tmplCtx := &cartContext{
+		Cart:    cart,
+		Message: msg,
+	}
 	w := &bytes.Buffer{}
-	err = s.tmpl.ExecuteTemplate(w, cartTmplFile, &cart)
+	err := s.render.Render(w, cartTmplFile, tmplCtx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to execute product template: %v", err)
+		return nil, fmt.Errorf("unable to execute cart template: %v", err)
 	}
 
 	return &rpc.RMFetchResourceReply{
@@ -169,43 +318,205 @@ func (s *Store) handleClearCart(ctx context.Context, uid clientintf.UserID) (*rp
 	}, nil
 }
 
+// updateCartItem sets the quantity of the product with the given SKU in the
+// cart of the user. A zero quantity removes the product from the cart.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) updateCartItem(uid clientintf.UserID, sku string, qty uint32) (*Cart, *rpc.RMFetchResourceReply, error) {
+	fname := cartKey(uid)
+	var cart Cart
+	err := s.backend.Read(fname, &cart)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, nil, err
+	}
+
+	i := slices.IndexFunc(cart.Items, func(item *CartItem) bool {
+		return item.Product.SKU == sku
+	})
+	if i < 0 {
+		return nil, &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(fmt.Sprintf("SKU %q is not in the cart", sku)),
+		}, nil
+	}
+
+	if qty == 0 {
+		cart.Items = slices.Delete(cart.Items, i, i+1)
+	} else {
+		// Products removed from the store or out of stock may only
+		// be removed from the cart.
+		prod, ok := s.product(sku)
+		if !ok || !prod.Available() || prod.HasVariants() {
+			return nil, &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data: []byte(fmt.Sprintf("Product %q is not available",
+					cart.Items[i].Product.Title)),
+			}, nil
+		}
+		if qty > cart.Items[i].Quantity && !prod.InStock(qty) {
+			return nil, outOfStockReply(prod), nil
+		}
+		if prod.PayWhatYouWant {
+			// Keep the price chosen by the buyer.
+			chosen := *prod
+			chosen.Price = cart.Items[i].Product.Price
+			prod = &chosen
+		}
+		cart.Items[i].Product = prod
+		cart.Items[i].Quantity = qty
+	}
+	cart.Updated = time.Now()
+	cart.Currency = s.currency()
+	s.updateCartDiscount(&cart)
+
+	if err := s.writeDoc(fname, &cart); err != nil {
+		return nil, nil, err
+	}
+	s.emitCartActivity(uid, &cart)
+	s.logEvent(EventCartItemUpdated, &uid, map[string]string{
+		"sku":      sku,
+		"quantity": strconv.FormatUint(uint64(qty), 10),
+	})
+	return &cart, nil, nil
+}
+
+// handleRemoveFromCart removes units of a product from the cart. The product
+// and number of units are specified either in the path
+// (/removeFromCart/<sku>[/<qty>]) or as form data. When the number of units is
+// not specified or is greater than the number of units in the cart, the
+// product is removed from the cart.
+func (s *Store) handleRemoveFromCart(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	formData := struct {
+		SKU string `json:"sku"`
+		Qty uint32 `json:"qty"`
+	}{}
+	switch {
+	case len(request.Path) > 1:
+		formData.SKU = request.Path[1]
+		if len(request.Path) > 2 {
+			qty, err := strconv.ParseUint(request.Path[2], 10, 32)
+			if err != nil {
+				return &rpc.RMFetchResourceReply{
+					Status: rpc.ResourceStatusBadRequest,
+					Data:   []byte("invalid quantity"),
+				}, nil
+			}
+			formData.Qty = uint32(qty)
+		}
+	case request.Data != nil:
+		if err := json.Unmarshal(request.Data, &formData); err != nil {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data:   []byte("request data not valid json"),
+			}, nil
+		}
+	default:
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("product to remove not specified"),
+		}, nil
+	}
+
+	defer s.backend.LockUser(uid)()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	// Determine the remaining units of the product.
+	fname := cartKey(uid)
+	var oldCart Cart
+	err := s.backend.Read(fname, &oldCart)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	var qty uint32
+	for _, item := range oldCart.Items {
+		if item.Product.SKU == formData.SKU && formData.Qty > 0 &&
+			formData.Qty < item.Quantity {
+			qty = item.Quantity - formData.Qty
+		}
+	}
+
+	cart, reply, err := s.updateCartItem(uid, formData.SKU, qty)
+	if reply != nil || err != nil {
+		return reply, err
+	}
+	msg := fmt.Sprintf("Removed %s from the cart", formData.SKU)
+	if qty > 0 {
+		msg = fmt.Sprintf("Removed %d units of %s from the cart",
+			formData.Qty, formData.SKU)
+	}
+	return s.renderCart(cart, msg)
+}
+
+// handleSetCartQuantity sets the number of units of a product in the cart. A
+// zero quantity removes the product from the cart.
+func (s *Store) handleSetCartQuantity(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if request.Data == nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data is empty"),
+		}, nil
+	}
+
+	formData := struct {
+		SKU string `json:"sku"`
+		Qty uint32 `json:"qty"`
+	}{}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+
+	defer s.backend.LockUser(uid)()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	cart, reply, err := s.updateCartItem(uid, formData.SKU, formData.Qty)
+	if reply != nil || err != nil {
+		return reply, err
+	}
+	msg := fmt.Sprintf("Set quantity of %s to %d", formData.SKU, formData.Qty)
+	if formData.Qty == 0 {
+		msg = fmt.Sprintf("Removed %s from the cart", formData.SKU)
+	}
+	return s.renderCart(cart, msg)
+}
+
 func (s *Store) handleCart(ctx context.Context, uid clientintf.UserID,
 	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
 
-	fname := filepath.Join(s.root, cartsDir, uid.String())
+	fname := cartKey(uid)
 	var cart Cart
 
 	s.mtx.Lock()
-	err := jsonfile.Read(fname, &cart)
+	err := s.backend.Read(fname, &cart)
 	s.mtx.Unlock()
 
-	if err != nil && !errors.Is(err, jsonfile.ErrNotFound) {
+	if err != nil && !errors.Is(err, ErrNotFound) {
 		return nil, err
 	}
 
-	w := &bytes.Buffer{}
-	err = s.tmpl.ExecuteTemplate(w, cartTmplFile, &cart)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute product template: %v", err)
-	}
-
-	return &rpc.RMFetchResourceReply{
-		Data:   w.Bytes(),
-		Status: rpc.ResourceStatusOk,
-	}, nil
+	return s.renderCart(&cart, "")
 }
 
 func (s *Store) handlePlaceOrder(ctx context.Context, uid clientintf.UserID,
 	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
 
-	cartFname := filepath.Join(s.root, cartsDir, uid.String())
+	cartFname := cartKey(uid)
 	var cart Cart
 
+	defer s.backend.LockUser(uid)()
 	s.mtx.Lock()
-	defer s.mtx.Unlock()
+	defer s.unlockAndNotify()
 
-	err := jsonfile.Read(cartFname, &cart)
-	if err != nil && !errors.Is(err, jsonfile.ErrNotFound) {
+	err := s.backend.Read(cartFname, &cart)
+	if err != nil && !errors.Is(err, ErrNotFound) {
 		return nil, err
 	}
 
@@ -216,60 +527,210 @@ func (s *Store) handlePlaceOrder(ctx context.Context, uid clientintf.UserID,
 		}, nil
 	}
 
-	var shipAddr *ShippingAddress
+	return s.placeOrder(ctx, uid, &cart, cartFname, nil, request)
+}
+
+// placeOrder places an order for the items of the cart. When the cart is the
+// saved cart of the user, cartFname is its key and the cart is cleared once
+// the order is placed. When the order is placed by accepting a quote request,
+// quote is the accepted quote, saved along with the order.
+//
+// This MUST be called with the store mutex and the user lock held.
+func (s *Store) placeOrder(ctx context.Context, uid clientintf.UserID,
+	cart *Cart, cartFname string, quote *QuoteRequest,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var err error
+	var needsShipping, payWithTip bool
 	// Verify the items
 	for _, item := range cart.Items {
-		prod, ok := s.products[item.Product.SKU]
+		prod, ok := s.product(item.Product.SKU)
 		if !ok {
 			return &rpc.RMFetchResourceReply{
 				Status: rpc.ResourceStatusBadRequest,
 				Data:   []byte(fmt.Sprintf("SKU %q does not exist", item.Product.SKU)),
 			}, nil
 		}
-		// If a product requires shipping, ensure a shipping address
-		// was sent.
-		if shipAddr == nil && prod.Shipping {
-			// Process form data.
-			var formData ShippingAddress
-			if err := json.Unmarshal(request.Data, &formData); err != nil {
+		if !prod.Available() || prod.HasVariants() {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data: []byte(fmt.Sprintf("Product %q is no longer "+
+					"available", prod.Title)),
+			}, nil
+		}
+		if cart.Gift != nil && prod.Subscription != "" {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data: []byte(fmt.Sprintf("Subscription %q cannot be "+
+					"gifted", prod.Title)),
+			}, nil
+		}
+		if prod.CustomQuote && quote == nil {
+			return customQuoteReply(prod), nil
+		}
+		if !prod.InStock(item.Quantity) {
+			return outOfStockReply(prod), nil
+		}
+		if prod.PayWhatYouWant {
+			msg := checkPayWhatYouWantAmount(prod, item.Product.Price, s.currency())
+			if msg != "" {
 				return &rpc.RMFetchResourceReply{
 					Status: rpc.ResourceStatusBadRequest,
-					Data:   []byte("request data not valid json"),
+					Data:   []byte(msg),
 				}, nil
 			}
+			payWithTip = true
+		}
+		needsShipping = needsShipping || prod.Shipping
+	}
 
-			if formData.Name == "" || formData.Address1 == "" ||
-				formData.City == "" || formData.State == "" ||
-				formData.PostalCode == "" {
+	// Bundles share the stock of their components with other items of
+	// the cart, so check the total units taken from each SKU.
+	stocked, _ := s.splitBackorderable(cart.Items)
+	if sku := s.stock.shortage(stocked); sku != "" {
+		return s.skuOutOfStockReply(sku), nil
+	}
+
+	// Ensure the coupon applied to the cart is still valid.
+	if cart.Coupon != "" {
+		discount, err := s.checkPromotion(cart.Coupon, cart)
+		if err != nil {
+			cart.Coupon = ""
+			cart.Discount = 0
+			if cartFname != "" {
+				if err := s.writeDoc(cartFname, cart); err != nil {
+					return nil, err
+				}
+			}
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data: []byte(fmt.Sprintf("%v. The coupon was "+
+					"removed from the cart.", err)),
+			}, nil
+		}
+		cart.Discount = discount
+	}
+
+	// If a product requires shipping, ensure a shipping address was sent,
+	// either with this request or in the checkout step.
+	var shipAddr *ShippingAddress
+	var shipMethodID string
+	if needsShipping {
+		if len(request.Data) > 0 {
+			var formData ShippingAddress
+			if err := json.Unmarshal(request.Data, &formData); err != nil {
 				return &rpc.RMFetchResourceReply{
 					Status: rpc.ResourceStatusBadRequest,
-					Data:   []byte("incomplete shipping address"),
+					Data:   []byte("request data not valid json"),
 				}, nil
 			}
-
-			// TODO: proper address validation, optional phone
-			// number validation.
 			shipAddr = &formData
+		} else if shipAddr, shipMethodID, err = s.pendingShipAddr(uid); err != nil {
+			return nil, fmt.Errorf("unable to load shipping address: %v", err)
+		}
+		if shipAddr == nil {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data:   []byte("shipping address not provided"),
+			}, nil
+		}
+
+		// TODO: proper address validation, optional phone number
+		// validation.
+		if err := shipAddr.validate(); err != nil {
+			return &rpc.RMFetchResourceReply{
+				Status: rpc.ResourceStatusBadRequest,
+				Data:   []byte(err.Error()),
+			}, nil
 		}
 	}
 
+	// Enforce the checkout rules of the store.
+	if violations := s.checkoutViolations(cart, shipAddr); len(violations) > 0 {
+		return s.checkoutErrorReply(violations)
+	}
+
+	// Charge the selected shipping method for orders that need shipping.
+	// Without shipping methods, the flat shipping charge is charged on
+	// every order.
+	shipCharge := MoneyFromFloat(s.cfg.ShipCharge)
+	var shipMethod string
+	switch {
+	case needsShipping:
+		opt, ok := s.selectedShipping(cart, shipAddr, shipMethodID)
+		if !ok {
+			return s.checkoutErrorReply([]string{"No shipping " +
+				"method ships this order to the shipping address"})
+		}
+		shipCharge = opt.Price
+		if s.hasShippingMethods() {
+			shipMethod = opt.Name
+		}
+	case s.hasShippingMethods():
+		shipCharge = 0
+	}
+
+	// Quote the order in DCR. Depending on the rate failure policy, orders
+	// are rejected when the exchange rate is not available.
+	rate, rateErr := s.quoteRate()
+	if rateErr != nil && s.cfg.RateFailurePolicy == RateFailureReject {
+		s.log.Warnf("Rejecting order of user %s: %v", uid.ShortLogID(), rateErr)
+		return s.checkoutErrorReply([]string{"The store is unable to " +
+			"quote orders in DCR right now. Please try placing the " +
+			"order again later"})
+	}
+
 	// Create the order.
-	orderDir := filepath.Join(s.root, ordersDir, uid.String())
-	lastID, err := orderFnamePattern.Last(orderDir)
+	id, err := s.backend.NextOrderID(uid)
 	if err != nil {
 		return nil, err
 	}
 
-	id := lastID.ID + 1
+	cart.Currency = s.currency()
 	order := &Order{
+		Currency:   cart.Currency,
 		User:       uid,
-		Cart:       cart,
-		ID:         OrderID(id),
+		Cart:       *cart,
+		ID:         id,
 		Status:     StatusPlaced,
 		PlacedTS:   time.Now(),
-		ShipCharge: s.cfg.ShipCharge,
+		ShipCharge: shipCharge,
+		ShipMethod: shipMethod,
 		ShipAddr:   shipAddr,
-		ExpiresTS:  time.Now().Add(time.Hour),
+		ExpiresTS:  time.Now().Add(s.quoteValidity()),
+	}
+	if quote != nil {
+		order.QuoteID = quote.ID
+	}
+	if cart.Gift != nil {
+		order.Gift = cart.Gift
+		order.Cart.Gift = nil
+	}
+	if ref, err := s.c.UserReferral(uid); err != nil {
+		s.log.Warnf("Unable to load referral of user %s: %v", uid, err)
+	} else if ref != nil {
+		order.Referral = ref.Label
+	}
+	order.Taxes = s.orderTaxes(order)
+	order.Experiments = s.orderExperiments(uid)
+
+	// Enforce the purchase limits and the custom acceptance logic of the
+	// store.
+	violations, err := s.checkOrderAcceptance(order)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		return s.checkoutErrorReply(violations)
+	}
+
+	// Reserve the stock of the ordered products before generating the
+	// invoice and assigning the order, so that orders rejected for lack
+	// of stock leave no invoice or admin notification behind. The new
+	// stock is saved along with the order.
+	newStock, stockChanged, shortSKU := s.takeOrderStock(order)
+	if shortSKU != "" {
+		return s.skuOutOfStockReply(shortSKU), nil
 	}
 
 	// Build the message to send to the remote user, and present it to the
@@ -279,18 +740,21 @@ func (s *Store) handlePlaceOrder(ctx context.Context, uid clientintf.UserID,
 		b.WriteString(fmt.Sprintf(f, args...))
 	}
 
-	var userNick string
-	ru, err := s.c.UserByID(order.User)
+	userNick, err := s.c.UserNick(order.User)
 	if err != nil && order.User == s.c.PublicID() {
 		userNick = "(local client)"
 	} else if err != nil {
 		return nil, fmt.Errorf("Order #%d placed by unknown user %s",
 			order.ID, order.User)
 	} else {
-		userNick = strescape.Nick(ru.Nick())
+		userNick = strescape.Nick(userNick)
 	}
 
 	wpm("Thank you for placing your order #%d\n", order.ID)
+	if order.Gift != nil {
+		wpm("This order is a gift to %s, who will be notified once "+
+			"it is paid\n", strescape.Nick(order.Gift.RecipientNick))
+	}
 	if order.ShipAddr != nil {
 		shipAddr := order.ShipAddr
 		wpm("Shipping address:\n")
@@ -306,126 +770,184 @@ func (s *Store) handlePlaceOrder(ctx context.Context, uid clientintf.UserID,
 	}
 	wpm("The following were the items in your order:\n")
 	for _, item := range order.Cart.Items {
-		totalItemUSDCents := int64(item.Quantity) * int64(item.Product.Price*100)
-		wpm("  SKU %s - %s - %d units - $%.2f/item - $%.2f\n",
+		wpm("  SKU %s - %s - %d units - %s/item - %s\n",
 			item.Product.SKU, item.Product.Title,
-			item.Quantity, item.Product.Price,
-			float64(totalItemUSDCents)/100)
+			item.Quantity, order.FormatAmount(item.Product.Price),
+			order.FormatAmount(item.Total()))
 	}
 
-	if order.Cart.HasCharges() && s.cfg.ShipCharge > 0 {
-		wpm("Total item amount: $%.2f USD\n", order.Cart.Total())
-		wpm("Shipping and handling charge: $%.2f USD\n", s.cfg.ShipCharge)
-		wpm("Total amount: $%.2f USD\n", order.Total())
+	if order.Cart.Coupon != "" {
+		wpm("Coupon %s discount: -%s\n", order.Cart.Coupon,
+			order.FormatAmount(order.Cart.Discount))
+	}
+
+	if order.Cart.HasCharges() && (order.ShipCharge > 0 || len(order.Taxes) > 0) {
+		wpm("Total item amount: %s\n", order.FormatAmount(order.Cart.Total()))
+		if order.ShipCharge > 0 && order.ShipMethod != "" {
+			wpm("Shipping and handling charge (%s): %s\n", order.ShipMethod,
+				order.FormatAmount(order.ShipCharge))
+		} else if order.ShipCharge > 0 {
+			wpm("Shipping and handling charge: %s\n", order.FormatAmount(order.ShipCharge))
+		}
+		for _, tax := range order.Taxes {
+			wpm("%s: %s\n", tax.Label(), order.FormatAmount(tax.Amount))
+		}
+		wpm("Total amount: %s\n", order.FormatAmount(order.Total()))
 	} else {
-		wpm("Total amount: $%.2f USD\n", order.Total())
+		wpm("Total amount: %s\n", order.FormatAmount(order.Total()))
 	}
 
-	if s.cfg.ExchangeRateProvider != nil {
-		order.ExchangeRate = s.cfg.ExchangeRateProvider()
+	if rateErr == nil {
+		order.ExchangeRate = rate
 	}
 
 	totalDCR := order.TotalDCR()
 	if totalDCR > 0 {
-		wpm("Using the current exchange rate of %.2f USD/DCR, your order is "+
-			"%s, valid for the next hour (expires %s)\n",
-			order.ExchangeRate, totalDCR, order.ExpiresTS.Format("Mon, 02 Jan 2006 15:04 MST"))
+		wpm("Using the current exchange rate of %.2f %s/DCR, your order is "+
+			"%s, valid until %s\n", order.ExchangeRate,
+			order.CurrencyCode(), totalDCR,
+			order.ExpiresTS.Format("Mon, 02 Jan 2006 15:04 MST"))
 	}
 
+	// High-value orders are paid in escrow, to an on-chain address of the
+	// escrow account.
 	pt := s.cfg.PayType
+	if !payWithTip && s.needsEscrow(order) {
+		order.Escrow = &OrderEscrow{}
+		pt = PayTypeOnChain
+		wpm("This order is paid in escrow: the payment is held until you " +
+			"confirm the receipt of the order and the store releases it\n")
+	}
 	switch {
-	case s.cfg.ExchangeRateProvider == nil:
-		s.log.Warnf("No exchange rate provider setup in simplestore config")
+	case rateErr != nil && s.cfg.RateFailurePolicy == RateFailureRetry:
+		s.log.Warnf("Placing order of user %s pending quote: %v", userNick, rateErr)
+		order.Status = StatusQuotePending
+		order.PayType = pt
+		if payWithTip {
+			order.PayType = PayTypeTip
+		}
+		wpm("\nThe exchange rate is not available right now. Your order " +
+			"will be quoted and you will be sent its payment details " +
+			"once it is\n")
+	case rateErr != nil:
+		s.log.Warnf("Unable to quote order of user %s: %v", userNick, rateErr)
 	case order.ExchangeRate <= 0:
 		s.log.Warnf("Invalid exchange rate to charge user %s for order %s",
 			userNick, order.ID)
 	case totalDCR == 0:
 		s.log.Warnf("Order has zero total dcr amount")
-	case pt == PayTypeOnChain:
-		addr, err := s.c.OnchainRecvAddrForUser(order.User, s.cfg.Account)
-		if err != nil {
-			s.log.Errorf("Unable to generate on-chain addr for user %s: %v",
-				userNick, err)
-		} else {
-			wpm("On-chain Payment Address: %s\n", addr)
-			order.PayType = PayTypeOnChain
-			order.Invoice = addr
-		}
-
-	case pt == PayTypeLN:
-		if s.lnpc == nil {
-			s.log.Warnf("Unable to generate LN invoice for user %s "+
-				"for order %s: LN not setup", userNick,
-				order.ID)
-		} else {
-			invoice, err := s.lnpc.GetInvoice(ctx, int64(totalDCR*1000), nil)
-			if err != nil {
-				s.log.Errorf("Unable to generate LN invoice for user %s "+
-					"order %s: %v", userNick,
-					order.ID, err)
-
-				// Fallback to generating an onchain payment address.
-				addr, err := s.c.OnchainRecvAddrForUser(order.User, s.cfg.Account)
-				if err != nil {
-					s.log.Errorf("Unable to generate on-chain addr for user %s: %v",
-						userNick, err)
-				} else {
-					wpm("On-chain Payment Address: %s\n", addr)
-					order.PayType = PayTypeOnChain
-					order.Invoice = addr
-				}
-			} else {
-				urlInvoice := "lnpay://" + invoice
-				wpm("LN Invoice for payment: %s\n", urlInvoice)
-				order.PayType = PayTypeLN
-				order.Invoice = invoice
-			}
+	case payWithTip:
+		order.PayType = PayTypeTip
+		wpm("%s\n", tipPaymentMsg(order))
+	case pt == PayTypeOnChain, pt == PayTypeLN:
+		order.PayType, order.Invoice = s.newOrderInvoice(ctx, order, pt, userNick)
+		switch order.PayType {
+		case PayTypeOnChain:
+			wpm("On-chain Payment Address: %s\n", order.Invoice)
+		case PayTypeLN:
+			urlInvoice := "lnpay://" + order.Invoice
+			wpm("LN Invoice for payment: %s\n", urlInvoice)
 		}
 
 	default:
 		wpm("\nYou will be contacted with payment details shortly")
 	}
+	if uri := order.PaymentURI(); uri != "" {
+		wpm("Payment URI (for QR codes): %s\n", uri)
+	}
 
-	// Track pending invoice or onchain addr for payment.
-	if order.Invoice != "" {
-		pendingFname := filepath.Join(s.root, pendingInvoicesDir, fmt.Sprintf("%s-%s", uid, order.ID))
-		if err := jsonfile.Write(pendingFname, "", s.log); err != nil {
-			return nil, fmt.Errorf("unable to write pending invoice file: %v", err)
-		}
-		select {
-		case s.invoiceCreatedChan <- order:
-		case <-s.runCtx.Done():
-		case <-ctx.Done():
-			return nil, ctx.Err()
+	s.assignOrder(order)
+
+	if order.IsBackordered() {
+		wpm("\nSome items of your order are backordered and will be " +
+			"shipped once they are back in stock\n")
+	}
+
+	// The shipping address is only saved encrypted. The order passed to
+	// the OrderPlaced callback still has it in plain text.
+	savedOrder := order
+	if order.ShipAddr != nil {
+		encAddr, err := s.encryptShipAddr(order.ShipAddr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encrypt shipping address: %v", err)
 		}
+		orderCopy := *order
+		orderCopy.ShipAddr = nil
+		orderCopy.EncShipAddr = encAddr
+		savedOrder = &orderCopy
 	}
 
-	if s.cfg.OrderPlaced != nil {
-		s.cfg.OrderPlaced(order, b.String())
+	// Atomically save the order, track its pending invoice or onchain addr
+	// for payment, decrement the stock and clear the cart and the pending
+	// shipping address.
+	batch := s.backend.NewBatch()
+	batch.Write(orderKey(uid, id), savedOrder)
+	if order.Invoice != "" {
+		pendingFname := path.Join(pendingInvoicesDir, fmt.Sprintf("%s-%s", uid, order.ID))
+		batch.Write(pendingFname, "")
+	}
+	if order.Status == StatusQuotePending {
+		batch.Write(pendingQuoteKey(uid, order.ID), "")
+	}
+	if order.AwaitingTip() {
+		batch.Write(pendingTipKey(uid, order.ID), "")
+	}
+	if stockChanged {
+		batch.Write(stockFile, newStock)
+	}
+	if cart.Coupon != "" {
+		_, uses, err := s.loadPromotions()
+		if err != nil {
+			return nil, err
+		}
+		uses[cart.Coupon]++
+		batch.Write(promotionUsesFile, uses)
+	}
+	if cartFname != "" {
+		batch.Remove(cartFname)
+	}
+	if quote != nil {
+		quote.Status = QuoteStatusAccepted
+		quote.OrderID = order.ID
+		batch.Write(quoteKey(uid, quote.ID), quote)
+	}
+	if needsShipping {
+		batch.Remove(path.Join(pendingShippingDir, uid.String()))
+	}
+	if err := batch.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to save order: %v", err)
+	}
+	if stockChanged {
+		s.stock = newStock
+		s.refreshStock()
 	}
+	s.logOrderEvent(EventOrderPlaced, &uid, order, map[string]string{
+		"total": order.FormatAmount(order.Total()),
+	})
+	s.c.Metrics().Inc(metrics.StoreOrdersPlaced)
 
-	// Save order.
-	orderFname := filepath.Join(orderDir, orderFnamePattern.FilenameFor(id))
-	err = jsonfile.Write(orderFname, order, s.log)
-	if err != nil {
-		return nil, err
+	if order.Invoice != "" {
+		s.trackInvoice(order)
 	}
 
-	// Clear cart.
-	if err := jsonfile.RemoveIfExists(cartFname); err != nil {
-		return nil, err
+	if s.cfg.OrderPlaced != nil {
+		s.cfg.OrderPlaced(order, b.String())
 	}
+	s.emitWebhook(WebhookOrderPlaced, order)
+	s.emitOrderActivity(ActivityOrderPlaced, order, "")
 
 	// Render result.
 	w := &bytes.Buffer{}
-	err = s.tmpl.ExecuteTemplate(w, orderPlacedTmplFile, &order)
+	err = s.render.Render(w, orderPlacedTmplFile, &order)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute product template: %v", err)
 	}
-	return &rpc.RMFetchResourceReply{
+	res := &rpc.RMFetchResourceReply{
 		Data:   w.Bytes(),
 		Status: rpc.ResourceStatusOk,
-	}, nil
+	}
+	s.signOrderSummary(order, res)
+	return res, nil
 }
 
 func (s *Store) handleOrders(ctx context.Context, uid clientintf.UserID,
@@ -434,17 +956,15 @@ func (s *Store) handleOrders(ctx context.Context, uid clientintf.UserID,
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	dir := filepath.Join(s.root, ordersDir, uid.String())
-	files, err := os.ReadDir(dir)
-	if err != nil && !os.IsNotExist(err) {
+	files, err := s.backend.List(userOrdersPattern(uid))
+	if err != nil {
 		return nil, err
 	}
 
 	var orders []*Order
-	for _, file := range files {
+	for _, fname := range files {
 		order := &Order{}
-		fname := filepath.Join(dir, file.Name())
-		err := jsonfile.Read(fname, order)
+		err := s.backend.Read(fname, order)
 		if err != nil {
 			s.log.Warnf("Unable to read order %s: %v",
 				fname, err)
@@ -453,12 +973,17 @@ func (s *Store) handleOrders(ctx context.Context, uid clientintf.UserID,
 		orders = append(orders, order)
 	}
 
+	// Show the most recent orders first.
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].PlacedTS.After(orders[j].PlacedTS)
+	})
+
 	tmplCtx := &ordersContext{
 		Orders: orders,
 	}
 
 	w := &bytes.Buffer{}
-	err = s.tmpl.ExecuteTemplate(w, ordersTmplFile, tmplCtx)
+	err = s.render.Render(w, ordersTmplFile, tmplCtx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute product template: %v", err)
 	}
@@ -483,12 +1008,12 @@ func (s *Store) handleOrderStatus(ctx context.Context, uid clientintf.UserID,
 		}, nil
 	}
 
-	fname := filepath.Join(s.root, ordersDir, uid.String(), orderFnamePattern.FilenameFor(id))
+	fname := orderKey(uid, OrderID(id))
 
 	var order Order
-	err = jsonfile.Read(fname, &order)
+	err = s.backend.Read(fname, &order)
 	if err != nil {
-		if errors.Is(err, jsonfile.ErrNotFound) {
+		if errors.Is(err, ErrNotFound) {
 			return &rpc.RMFetchResourceReply{
 				Data:   []byte("order not found"),
 				Status: rpc.ResourceStatusBadRequest,
@@ -498,20 +1023,26 @@ func (s *Store) handleOrderStatus(ctx context.Context, uid clientintf.UserID,
 			fname, err)
 	}
 
+	if err := s.loadOrderShipAddr(&order); err != nil {
+		return nil, err
+	}
 	tmplCtx := &orderContext{
-		Order: order,
+		Order:         order,
+		CanPayOnChain: s.canPayOnChain(&order),
 	}
 
 	w := &bytes.Buffer{}
-	err = s.tmpl.ExecuteTemplate(w, orderTmplFile, tmplCtx)
+	err = s.render.Render(w, orderTmplFile, tmplCtx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute order template: %v", err)
 	}
 
-	return &rpc.RMFetchResourceReply{
+	res := &rpc.RMFetchResourceReply{
 		Data:   w.Bytes(),
 		Status: rpc.ResourceStatusOk,
-	}, nil
+	}
+	s.signOrderSummary(&order, res)
+	return res, nil
 }
 
 func (s *Store) handleOrderAddComment(ctx context.Context, uid clientintf.UserID,
@@ -535,12 +1066,12 @@ func (s *Store) handleOrderAddComment(ctx context.Context, uid clientintf.UserID
 		}, nil
 	}
 
-	fname := filepath.Join(s.root, ordersDir, uid.String(), orderFnamePattern.FilenameFor(id))
+	fname := orderKey(uid, OrderID(id))
 
 	var order Order
-	err = jsonfile.Read(fname, &order)
+	err = s.backend.Read(fname, &order)
 	if err != nil {
-		if errors.Is(err, jsonfile.ErrNotFound) {
+		if errors.Is(err, ErrNotFound) {
 			return &rpc.RMFetchResourceReply{
 				Data:   []byte("order not found"),
 				Status: rpc.ResourceStatusBadRequest,
@@ -558,7 +1089,7 @@ func (s *Store) handleOrderAddComment(ctx context.Context, uid clientintf.UserID
 	})
 
 	// Save order.
-	if err := jsonfile.Write(fname, &order, s.log); err != nil {
+	if err := s.writeDoc(fname, &order); err != nil {
 		return nil, err
 	}
 
@@ -572,3 +1103,254 @@ func (s *Store) handleOrderAddComment(ctx context.Context, uid clientintf.UserID
 	}, nil
 
 }
+
+// canPayOnChain returns true if the payment of the order may be switched from
+// LN to an on-chain payment (for users that pay from an external wallet
+// without LN support).
+func (s *Store) canPayOnChain(order *Order) bool {
+	return s.lnpc != nil && order.PayType == PayTypeLN && order.AwaitingPayment()
+}
+
+// handleOrderPayOnChain switches the payment of an order from an LN invoice to
+// an on-chain address. The payment to the new address is detected and linked
+// to the order by the invoice watcher, as for any other on-chain order.
+func (s *Store) handleOrderPayOnChain(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	id, err := strconv.ParseUint(request.Path[1], 10, 64)
+	if err != nil {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("invalid order id"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	fname := orderKey(uid, OrderID(id))
+
+	s.mtx.Lock()
+	defer s.unlockAndNotify()
+
+	var order Order
+	err = s.backend.Read(fname, &order)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return &rpc.RMFetchResourceReply{
+				Data:   []byte("order not found"),
+				Status: rpc.ResourceStatusBadRequest,
+			}, nil
+		}
+		return nil, fmt.Errorf("Unable to read order %s: %v",
+			fname, err)
+	}
+
+	if !s.canPayOnChain(&order) {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("order cannot be paid on-chain"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	addr, err := s.c.OnchainRecvAddrForUser(order.User, s.orderAccount(&order))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate on-chain addr: %v", err)
+	}
+
+	// Cancel the LN invoice, so that it can't be paid after the order is
+	// switched to on-chain. If it can't be canceled (e.g. because it was
+	// just paid), the order is kept as is.
+	if err := s.lnpc.CancelInvoice(ctx, order.Invoice); err != nil {
+		s.log.Warnf("Unable to cancel invoice of order %s/%s: %v",
+			uid.ShortLogID(), order.ID, err)
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("unable to cancel the LN invoice of the order"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	// Stop tracking the LN invoice and start tracking the on-chain
+	// address.
+	oldInvoice := order.invoiceDiscriminator()
+	order.PayType = PayTypeOnChain
+	order.Invoice = addr
+	if err := s.writeDoc(fname, &order); err != nil {
+		return nil, err
+	}
+	s.untrackInvoice(oldInvoice)
+	s.trackInvoice(&order)
+
+	s.log.Infof("User %s switched order %s to on-chain payment to %s",
+		uid.ShortLogID(), order.ID, addr)
+
+	w := &bytes.Buffer{}
+	w.WriteString("# On-Chain Payment\n\n")
+	w.WriteString(fmt.Sprintf("Send %s to the address %s\n\n",
+		order.TotalDCR(), addr))
+	w.WriteString(fmt.Sprintf("Payment URI (for QR codes): %s\n\n",
+		order.PaymentURI()))
+	w.WriteString("The payment will be automatically detected and linked " +
+		"to your order.\n\n")
+	w.WriteString(fmt.Sprintf("[Back to Order](/order/%d)\n\n", id))
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// quoteValidity returns how long the quotes of orders are valid for.
+func (s *Store) quoteValidity() time.Duration {
+	if s.cfg.QuoteValidity > 0 {
+		return s.cfg.QuoteValidity
+	}
+	return time.Hour
+}
+
+// newOrderInvoice generates the invoice (LN invoice or on-chain address) for
+// paying the order with the given pay type. If an LN invoice cannot be
+// generated, this falls back to an on-chain address. Orders paid in escrow are
+// always paid on-chain. Returns an empty invoice if one could not be
+// generated.
+func (s *Store) newOrderInvoice(ctx context.Context, order *Order, pt PayType,
+	userNick string) (PayType, string) {
+
+	if pt == PayTypeLN && order.Escrow == nil {
+		if s.lnpc == nil {
+			s.log.Warnf("Unable to generate LN invoice for user %s "+
+				"for order %s: LN not setup", userNick,
+				order.ID)
+			return "", ""
+		}
+		invoice, err := s.lnpc.GetInvoice(ctx, int64(order.TotalDCR()*1000), nil)
+		if err == nil {
+			return PayTypeLN, invoice
+		}
+		s.c.Metrics().Inc(metrics.InvoiceGenFailures)
+		s.log.Errorf("Unable to generate LN invoice for user %s "+
+			"order %s: %v", userNick, order.ID, err)
+
+		// Fallback to generating an onchain payment address.
+	}
+
+	addr, err := s.c.OnchainRecvAddrForUser(order.User, s.orderAccount(order))
+	if err != nil {
+		s.log.Errorf("Unable to generate on-chain addr for user %s: %v",
+			userNick, err)
+		return "", ""
+	}
+	return PayTypeOnChain, addr
+}
+
+// handleOrderRequote generates a new quote (exchange rate and invoice) for an
+// order that expired before being paid.
+func (s *Store) handleOrderRequote(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.unlockAndNotify()
+
+	id, err := strconv.ParseUint(request.Path[1], 10, 64)
+	if err != nil {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("invalid order id"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	fname := orderKey(uid, OrderID(id))
+
+	var order Order
+	err = s.backend.Read(fname, &order)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return &rpc.RMFetchResourceReply{
+				Data:   []byte("order not found"),
+				Status: rpc.ResourceStatusBadRequest,
+			}, nil
+		}
+		return nil, fmt.Errorf("Unable to read order %s: %v",
+			fname, err)
+	}
+
+	if order.Status != StatusExpired {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("only expired orders may be requoted"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	// The items of the expired order were returned to the stock, so take
+	// them again.
+	newStock, stockChanged, shortSKU := s.takeOrderStock(&order)
+	if shortSKU != "" {
+		return s.skuOutOfStockReply(shortSKU), nil
+	}
+
+	// Orders are requoted in their original currency.
+	order.ExchangeRate = 0
+	if order.CurrencyCode() != s.currency() {
+		s.log.Warnf("Unable to requote order %s/%s placed in %s: store "+
+			"currency is %s", uid.ShortLogID(), order.ID,
+			order.CurrencyCode(), s.currency())
+	} else if rate, err := s.quoteRate(); err != nil {
+		s.log.Warnf("Unable to requote order %s/%s: %v", uid.ShortLogID(),
+			order.ID, err)
+	} else {
+		order.ExchangeRate = rate
+	}
+	if order.ExchangeRate <= 0 || order.TotalDCR() == 0 {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("unable to requote order: no exchange rate"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	pt := order.PayType
+	if pt == "" {
+		pt = s.cfg.PayType
+	}
+	order.PayType, order.Invoice = s.newOrderInvoice(ctx, &order, pt, uid.ShortLogID())
+	if order.Invoice == "" {
+		return nil, fmt.Errorf("unable to generate invoice for order %s", order.ID)
+	}
+	order.ExpiresTS = time.Now().Add(s.quoteValidity())
+	if err := order.setStatus(StatusPlaced, nil); err != nil {
+		return nil, err
+	}
+
+	// Atomically save the order, track its new invoice and take the items
+	// from the stock.
+	batch := s.backend.NewBatch()
+	batch.Write(fname, &order)
+	pendingFname := path.Join(pendingInvoicesDir, fmt.Sprintf("%s-%s", uid, order.ID))
+	batch.Write(pendingFname, "")
+	if stockChanged {
+		batch.Write(stockFile, newStock)
+	}
+	if err := batch.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to save order: %v", err)
+	}
+	if stockChanged {
+		s.stock = newStock
+		s.refreshStock()
+	}
+
+	s.trackInvoice(&order)
+
+	s.log.Infof("User %s requoted order %s at exchange rate %.2f",
+		uid.ShortLogID(), order.ID, order.ExchangeRate)
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Order Requoted\n\n")
+	w.WriteString(fmt.Sprintf("Using the current exchange rate of %.2f %s/DCR, "+
+		"your order is %s, valid until %s\n\n", order.ExchangeRate,
+		order.CurrencyCode(), order.TotalDCR(), order.ExpiresTS.Format("Mon, 02 Jan 2006 15:04 MST")))
+	w.WriteString(fmt.Sprintf("Payment URI (for QR codes): %s\n\n",
+		order.PaymentURI()))
+	if embed := quoteEmbed(&order); embed != "" {
+		w.WriteString(embed + "\n\n")
+	}
+	w.WriteString(fmt.Sprintf("[Back to Order](/order/%d)\n\n", id))
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
@@ -0,0 +1,251 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// stockFile is the file that tracks the stock levels of the products. The
+// stock set in the product files is only used as the initial stock level of a
+// product: after that, the level is tracked in this file and decremented as
+// orders are placed.
+const stockFile = "stock.json"
+
+// stockLevels maps product SKUs to their number of units in stock.
+type stockLevels map[string]int64
+
+// clone returns a copy of the stock levels.
+func (levels stockLevels) clone() stockLevels {
+	res := make(stockLevels, len(levels))
+	for sku, n := range levels {
+		res[sku] = n
+	}
+	return res
+}
+
+// loadStock loads the tracked stock levels.
+func (s *Store) loadStock() error {
+	levels := make(stockLevels)
+	err := s.backend.Read(stockFile, &levels)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("unable to load stock levels: %v", err)
+	}
+	s.mtx.Lock()
+	s.stock = levels
+	s.mtx.Unlock()
+	return nil
+}
+
+// applyStock sets the stock of the products to their tracked stock level.
+// Products with a stock set in their product file that are not yet tracked
+// start being tracked.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) applyStock(products map[string]*Product) {
+	for sku, prod := range products {
+		if prod.IsBundle() {
+			continue
+		}
+		if n, ok := s.stock[sku]; ok {
+			prod.Stock = &n
+		} else if prod.Stock != nil {
+			s.stock[sku] = *prod.Stock
+		}
+	}
+}
+
+// refreshStock sets the stock of the loaded products and variants to their
+// tracked stock level and the stock of bundles to the stock of their
+// components.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) refreshStock() {
+	s.purgePageCache()
+	s.applyStock(s.products)
+	s.applyStock(s.variants)
+	s.applyBundleStock()
+}
+
+// setStock sets the tracked stock level of the product. A negative level
+// removes the product from stock tracking, such that the stock set in the
+// product file (if any) is used.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) setStock(prod *Product, n int64) error {
+	levels := s.stock.clone()
+	if n < 0 {
+		delete(levels, prod.SKU)
+	} else {
+		levels[prod.SKU] = n
+	}
+	if err := s.writeDoc(stockFile, levels); err != nil {
+		return err
+	}
+	s.stock = levels
+	s.purgePageCache()
+	if n < 0 {
+		prod.Stock = nil
+	} else {
+		prod.Stock = &n
+	}
+	s.applyBundleStock()
+	if n > 0 {
+		s.fillBackorders()
+	}
+	return nil
+}
+
+// outOfStockReply returns the reply to a request for more units of a product
+// than are in stock.
+func outOfStockReply(prod *Product) *rpc.RMFetchResourceReply {
+	msg := fmt.Sprintf("Product %q is out of stock", prod.Title)
+	if prod.Stock != nil && *prod.Stock > 0 {
+		msg = fmt.Sprintf("Only %d units of product %q are in stock",
+			*prod.Stock, prod.Title)
+	}
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusBadRequest,
+		Data:   []byte(msg),
+	}
+}
+
+// restockOrder returns the units of the items of a canceled order to the
+// stock.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) restockOrder(order *Order) error {
+	// The units still owed to the order were never taken from the
+	// stock.
+	owed := make(map[string]int64, len(order.Backordered))
+	for sku, n := range order.Backordered {
+		owed[sku] = n
+	}
+	levels := s.stock.clone()
+	var changed bool
+	for _, item := range order.Cart.Items {
+		for _, u := range item.stockUnits() {
+			units := u.units
+			if owed[u.sku] > 0 {
+				n := owed[u.sku]
+				if n > units {
+					n = units
+				}
+				owed[u.sku] -= n
+				units -= n
+			}
+			if _, ok := levels[u.sku]; ok && units > 0 {
+				levels[u.sku] += units
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := s.writeDoc(stockFile, levels); err != nil {
+		return err
+	}
+	s.stock = levels
+	s.refreshStock()
+	return nil
+}
+
+func (s *Store) handleAdminStock(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	products := s.stockedProducts()
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Stock Levels\n\n")
+	for _, prod := range products {
+		stock := "unlimited"
+		if prod.Stock != nil {
+			stock = fmt.Sprintf("%d", *prod.Stock)
+		}
+		w.WriteString(fmt.Sprintf("## %s (SKU %s)\n\n", prod.Title, prod.SKU))
+		w.WriteString(fmt.Sprintf("In stock: %s\n\n", stock))
+		form := resources.NewForm("/admin/setstock").
+			Hidden("sku", prod.SKU).
+			IntInput("stock", "Stock (negative for unlimited)", 0).
+			Submit("Set Stock")
+		w.WriteString(form.String())
+		w.WriteString("\n")
+	}
+	s.mtx.Unlock()
+	w.WriteString("[Back to Admin](/admin)\n\n")
+
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+func (s *Store) handleAdminSetStock(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	fd, err := resources.ParseFormData(request.Data)
+	if err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+	sku := fd.String("sku")
+	stock, err := fd.Int("stock")
+	if err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(err.Error()),
+		}, nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	prod, ok := s.product(sku)
+	if !ok {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(fmt.Sprintf("SKU %q does not exist", sku)),
+		}, nil
+	}
+	if prod.IsBundle() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("The stock of bundle %q is the "+
+				"stock of its components", prod.Title)),
+		}, nil
+	}
+	if err := s.setStock(prod, stock); err != nil {
+		return nil, fmt.Errorf("unable to save stock levels: %v", err)
+	}
+	s.markLocalInventoryChange(prod.SKU)
+	s.log.Infof("Admin %s set stock of product %s to %d", uid.ShortLogID(),
+		prod.SKU, stock)
+	s.logEvent(EventStockSet, &uid, map[string]string{
+		"sku":   prod.SKU,
+		"stock": strconv.FormatInt(stock, 10),
+	})
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Stock Updated\n\n")
+	if prod.Stock == nil {
+		w.WriteString(fmt.Sprintf("Product %q has unlimited stock\n\n", prod.Title))
+	} else {
+		w.WriteString(fmt.Sprintf("Product %q has %d units in stock\n\n",
+			prod.Title, *prod.Stock))
+	}
+	w.WriteString("[Back to Stock Levels](/admin/stock)\n\n")
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
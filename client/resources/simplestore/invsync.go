@@ -0,0 +1,547 @@
+package simplestore
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inventorySyncFile is the file that tracks the state of the synchronization
+// of each SKU with the external inventory system.
+const inventorySyncFile = "inventorysync.json"
+
+const (
+	// maxInventorySyncBody is the max size of the body of requests to the
+	// inventory sync HTTP endpoint.
+	maxInventorySyncBody = 4 * 1024 * 1024
+
+	// defaultInventorySyncSource is the source of external changes
+	// recorded in the event log when one is not configured.
+	defaultInventorySyncSource = "external"
+
+	// maxInventorySyncEvents is the max number of events returned by a
+	// single request to the events endpoint.
+	maxInventorySyncEvents = 1000
+)
+
+// InventoryConflictPolicy determines how updates from the external inventory
+// system that conflict with changes made locally (by admins of the store) are
+// handled.
+type InventoryConflictPolicy string
+
+const (
+	// InventoryConflictExternalWins applies all external updates,
+	// overwriting local changes. This is the default policy.
+	InventoryConflictExternalWins InventoryConflictPolicy = "external"
+
+	// InventoryConflictLocalWins rejects external updates to SKUs changed
+	// locally since the last external update applied to them.
+	InventoryConflictLocalWins InventoryConflictPolicy = "local"
+
+	// InventoryConflictNewest rejects external updates with a timestamp
+	// before the last local change to their SKU.
+	InventoryConflictNewest InventoryConflictPolicy = "newest"
+)
+
+// InventorySyncConfig configures the synchronization of the stock and prices
+// of the store with an external inventory system.
+type InventorySyncConfig struct {
+	// Source is the name of the external system, recorded in the event
+	// log entries of its changes. Defaults to "external".
+	Source string
+
+	// Conflicts is the policy for external updates that conflict with
+	// local changes.
+	Conflicts InventoryConflictPolicy
+
+	// ListenAddr, if set, is the loopback address (for example,
+	// "127.0.0.1:9871") of an HTTP endpoint for the external system to
+	// push updates and pull the inventory and order events.
+	ListenAddr string
+
+	// Token is the bearer token that requests to the HTTP endpoint must
+	// send in their Authorization header. Required when ListenAddr is set.
+	Token string
+}
+
+// validate returns an error if the config is invalid.
+func (cfg *InventorySyncConfig) validate() error {
+	switch cfg.Conflicts {
+	case "", InventoryConflictExternalWins, InventoryConflictLocalWins,
+		InventoryConflictNewest:
+	default:
+		return fmt.Errorf("unknown inventory conflict policy %q", cfg.Conflicts)
+	}
+	if cfg.ListenAddr == "" {
+		return nil
+	}
+	if cfg.Token == "" {
+		return fmt.Errorf("inventory sync endpoint requires a token")
+	}
+	host, _, err := net.SplitHostPort(cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("invalid inventory sync address: %v", err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("inventory sync endpoint must listen on a " +
+			"loopback address")
+	}
+	return nil
+}
+
+// source returns the source recorded in the event log.
+func (cfg *InventorySyncConfig) source() string {
+	if cfg.Source == "" {
+		return defaultInventorySyncSource
+	}
+	return cfg.Source
+}
+
+// InventoryUpdate is an update of the stock and/or price of a product pushed
+// by the external inventory system.
+type InventoryUpdate struct {
+	SKU string `json:"sku"`
+
+	// Stock, if set, is the new stock level of the product. Negative
+	// levels make the stock of the product unlimited.
+	Stock *int64 `json:"stock,omitempty"`
+
+	// StockDelta is added to the stock level of the product (for example,
+	// when a shipment is received). Updates with only a StockDelta never
+	// conflict with local changes. It is ignored if Stock is set.
+	StockDelta int64 `json:"stock_delta,omitempty"`
+
+	// Price, if set, is the new price of the product.
+	Price *Money `json:"price,omitempty"`
+
+	// Version, if not zero, is the version of the product in the external
+	// system. Updates with a version that is not greater than the last
+	// version applied to the product are rejected as stale.
+	Version uint64 `json:"version,omitempty"`
+
+	// Timestamp is when the product changed in the external system, used
+	// by the InventoryConflictNewest policy. Defaults to the time the
+	// update is applied.
+	Timestamp time.Time `json:"timestamp,omitempty"`
+
+	// Force applies the update even if it conflicts with local changes.
+	Force bool `json:"force,omitempty"`
+}
+
+// onlyDelta returns true if the update only changes the stock by a delta.
+func (upd *InventoryUpdate) onlyDelta() bool {
+	return upd.Stock == nil && upd.Price == nil && upd.StockDelta != 0
+}
+
+// InventoryUpdateResult is the result of applying an InventoryUpdate.
+type InventoryUpdateResult struct {
+	SKU     string `json:"sku"`
+	Applied bool   `json:"applied"`
+
+	// Conflict is the reason an update was not applied.
+	Conflict string `json:"conflict,omitempty"`
+}
+
+// InventoryItem is the inventory of a product, as pulled by the external
+// inventory system.
+type InventoryItem struct {
+	SKU      string `json:"sku"`
+	Title    string `json:"title"`
+	Price    Money  `json:"price"`
+	Currency string `json:"currency"`
+
+	// Stock is the stock level of the product, or nil if its stock is
+	// unlimited.
+	Stock *int64 `json:"stock,omitempty"`
+
+	// Version is the last external version applied to the product.
+	Version uint64 `json:"version,omitempty"`
+
+	// LocalTS is the time of the last local change to the product, when
+	// tracked.
+	LocalTS *time.Time `json:"local_ts,omitempty"`
+}
+
+// inventorySyncState is the state of the synchronization of a SKU.
+type inventorySyncState struct {
+	Version    uint64     `json:"version,omitempty"`
+	ExternalTS *time.Time `json:"external_ts,omitempty"`
+	LocalTS    *time.Time `json:"local_ts,omitempty"`
+}
+
+// conflict returns the reason the update conflicts with the state of its SKU,
+// or an empty string if it can be applied.
+func (st *inventorySyncState) conflict(policy InventoryConflictPolicy,
+	upd *InventoryUpdate, now time.Time) string {
+
+	if upd.Version > 0 && upd.Version <= st.Version {
+		return fmt.Sprintf("stale update: version %d is not after the "+
+			"last applied version %d", upd.Version, st.Version)
+	}
+	if upd.Force || upd.onlyDelta() || st.LocalTS == nil {
+		return ""
+	}
+	switch policy {
+	case InventoryConflictLocalWins:
+		if st.ExternalTS == nil || st.LocalTS.After(*st.ExternalTS) {
+			return fmt.Sprintf("product changed locally at %s",
+				st.LocalTS.Format(time.RFC3339))
+		}
+	case InventoryConflictNewest:
+		ts := upd.Timestamp
+		if ts.IsZero() {
+			ts = now
+		}
+		if ts.Before(*st.LocalTS) {
+			return fmt.Sprintf("update is older than the local change "+
+				"at %s", st.LocalTS.Format(time.RFC3339))
+		}
+	}
+	return ""
+}
+
+// loadInventorySyncState loads the sync state of the SKUs.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadInventorySyncState() (map[string]*inventorySyncState, error) {
+	state := make(map[string]*inventorySyncState)
+	err := s.backend.Read(inventorySyncFile, &state)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("unable to load inventory sync state: %v", err)
+	}
+	return state, nil
+}
+
+// markLocalInventoryChange records a local change to the stock or price of the
+// product, used to detect conflicts with external updates.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) markLocalInventoryChange(sku string) {
+	policy := s.cfg.InventorySync.Conflicts
+	if policy == "" || policy == InventoryConflictExternalWins {
+		return
+	}
+	state, err := s.loadInventorySyncState()
+	if err != nil {
+		s.log.Warnf("Unable to record local change of %s: %v", sku, err)
+		return
+	}
+	st := state[sku]
+	if st == nil {
+		st = &inventorySyncState{}
+		state[sku] = st
+	}
+	now := time.Now()
+	st.LocalTS = &now
+	if err := s.writeDoc(inventorySyncFile, state); err != nil {
+		s.log.Warnf("Unable to record local change of %s: %v", sku, err)
+	}
+}
+
+// InventorySnapshot returns the inventory of the products with stock (i.e.
+// excluding bundles and products with variants, whose variants are returned
+// instead).
+func (s *Store) InventorySnapshot() ([]InventoryItem, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	state, err := s.loadInventorySyncState()
+	if err != nil {
+		return nil, err
+	}
+	currency := s.currency()
+	products := s.stockedProducts()
+	res := make([]InventoryItem, 0, len(products))
+	for _, prod := range products {
+		item := InventoryItem{
+			SKU:      prod.SKU,
+			Title:    prod.Title,
+			Price:    prod.Price,
+			Currency: currency,
+		}
+		if prod.Stock != nil {
+			stock := *prod.Stock
+			item.Stock = &stock
+		}
+		if st := state[prod.SKU]; st != nil {
+			item.Version = st.Version
+			item.LocalTS = st.LocalTS
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// ApplyInventoryUpdates applies the updates pushed by the external inventory
+// system, according to the conflict policy of the store. Updates that cannot
+// be applied (because they conflict with local changes or are invalid) are
+// returned with the reason in the Conflict field of their result. Every
+// external change (and rejected update) is recorded in the event log.
+func (s *Store) ApplyInventoryUpdates(updates []InventoryUpdate) ([]InventoryUpdateResult, error) {
+	if s.isCoHost() {
+		return nil, fmt.Errorf("the inventory of co-hosted stores is " +
+			"managed by the primary store")
+	}
+	res := make([]InventoryUpdateResult, 0, len(updates))
+	for i := range updates {
+		r, err := s.applyInventoryUpdate(&updates[i])
+		if err != nil {
+			return res, fmt.Errorf("unable to apply update of %s: %v",
+				updates[i].SKU, err)
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// applyInventoryUpdate applies a single update of the external inventory
+// system.
+func (s *Store) applyInventoryUpdate(upd *InventoryUpdate) (InventoryUpdateResult, error) {
+	res := InventoryUpdateResult{SKU: upd.SKU}
+	source := s.cfg.InventorySync.source()
+	reject := func(msg string, args ...interface{}) (InventoryUpdateResult, error) {
+		res.Conflict = fmt.Sprintf(msg, args...)
+		s.log.Infof("Rejected inventory update of %s from %s: %s",
+			upd.SKU, source, res.Conflict)
+		s.logEvent(EventInventoryConflict, nil, map[string]string{
+			"source":   source,
+			"sku":      upd.SKU,
+			"conflict": res.Conflict,
+		})
+		return res, nil
+	}
+
+	s.mtx.Lock()
+	prod, ok := s.product(upd.SKU)
+	if !ok {
+		s.mtx.Unlock()
+		return reject("SKU %q does not exist", upd.SKU)
+	}
+	changesStock := upd.Stock != nil || upd.StockDelta != 0
+	switch {
+	case changesStock && prod.IsBundle():
+		s.mtx.Unlock()
+		return reject("the stock of bundle %q is the stock of its "+
+			"components", upd.SKU)
+	case upd.Stock == nil && upd.StockDelta != 0 && prod.Stock == nil:
+		s.mtx.Unlock()
+		return reject("the stock of %q is unlimited", upd.SKU)
+	case upd.Price != nil && *upd.Price < 0:
+		s.mtx.Unlock()
+		return reject("invalid price %s", upd.Price)
+	}
+
+	// Variants are not defined in their own entry of a product file, so
+	// their price cannot be changed.
+	var priceFile *productFile
+	var priceIdx int
+	if upd.Price != nil && *upd.Price != prod.Price {
+		var err error
+		priceFile, priceIdx, err = s.findProductFile(upd.SKU)
+		if err != nil {
+			s.mtx.Unlock()
+			return res, err
+		}
+		if priceFile == nil {
+			s.mtx.Unlock()
+			return reject("the price of variant %q cannot be changed", upd.SKU)
+		}
+	}
+
+	state, err := s.loadInventorySyncState()
+	if err != nil {
+		s.mtx.Unlock()
+		return res, err
+	}
+	st := state[upd.SKU]
+	if st == nil {
+		st = &inventorySyncState{}
+		state[upd.SKU] = st
+	}
+	now := time.Now()
+	if conflict := st.conflict(s.cfg.InventorySync.Conflicts, upd, now); conflict != "" {
+		s.mtx.Unlock()
+		return reject("%s", conflict)
+	}
+
+	details := map[string]string{
+		"source": source,
+		"sku":    upd.SKU,
+	}
+	if upd.Version > 0 {
+		details["version"] = strconv.FormatUint(upd.Version, 10)
+	}
+	if changesStock {
+		prev := int64(-1)
+		if prod.Stock != nil {
+			prev = *prod.Stock
+		}
+		var n int64
+		if upd.Stock != nil {
+			n = *upd.Stock
+			if n < 0 {
+				n = -1
+			}
+		} else {
+			n = prev + upd.StockDelta
+			if n < 0 {
+				n = 0
+			}
+		}
+		if err := s.setStock(prod, n); err != nil {
+			s.mtx.Unlock()
+			return res, fmt.Errorf("unable to save stock levels: %v", err)
+		}
+		details["prev_stock"] = strconv.FormatInt(prev, 10)
+		details["stock"] = strconv.FormatInt(n, 10)
+	}
+	if priceFile != nil {
+		details["prev_price"] = prod.Price.String()
+		details["price"] = upd.Price.String()
+		priceFile.pf.Products[priceIdx].Price = *upd.Price
+		if err := writeProductFile(priceFile); err != nil {
+			s.mtx.Unlock()
+			return res, fmt.Errorf("unable to write product file: %v", err)
+		}
+	}
+
+	if upd.Version > 0 {
+		st.Version = upd.Version
+	}
+	st.ExternalTS = &now
+	if err := s.writeDoc(inventorySyncFile, state); err != nil {
+		s.mtx.Unlock()
+		return res, err
+	}
+	var reloadDir string
+	if priceFile != nil {
+		reloadDir = s.catalogReloadDir(priceFile.relDir)
+	}
+	s.mtx.Unlock()
+
+	if priceFile != nil {
+		if err := s.reloadCatalogDirs([]string{reloadDir}); err != nil {
+			return res, fmt.Errorf("unable to reload products: %v", err)
+		}
+	}
+	s.logEvent(EventInventorySync, nil, details)
+	s.log.Infof("Applied inventory update of %s from %s", upd.SKU, source)
+	res.Applied = true
+	return res, nil
+}
+
+// inventorySyncHandler returns the handler of the inventory sync HTTP
+// endpoint:
+//
+//   - GET /inventory returns the InventorySnapshot.
+//   - POST /inventory applies the list of InventoryUpdate in the body and
+//     returns their results.
+//   - GET /events?since=<seq>&types=<prefix>,... returns the entries of the
+//     event log after seq with the given type prefixes (by default, "order.").
+func (s *Store) inventorySyncHandler() http.Handler {
+	token := []byte("Bearer " + s.cfg.InventorySync.Token)
+	reply := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			s.log.Debugf("Unable to write inventory sync reply: %v", err)
+		}
+	}
+	replyErr := func(w http.ResponseWriter, status int, err error) {
+		reply(w, status, map[string]string{"error": err.Error()})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/inventory", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			items, err := s.InventorySnapshot()
+			if err != nil {
+				replyErr(w, http.StatusInternalServerError, err)
+				return
+			}
+			reply(w, http.StatusOK, items)
+
+		case http.MethodPost:
+			var updates []InventoryUpdate
+			body := io.LimitReader(r.Body, maxInventorySyncBody)
+			if err := json.NewDecoder(body).Decode(&updates); err != nil {
+				replyErr(w, http.StatusBadRequest, err)
+				return
+			}
+			res, err := s.ApplyInventoryUpdates(updates)
+			if err != nil {
+				replyErr(w, http.StatusInternalServerError, err)
+				return
+			}
+			reply(w, http.StatusOK, res)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var since uint64
+		if v := r.URL.Query().Get("since"); v != "" {
+			var err error
+			if since, err = strconv.ParseUint(v, 10, 64); err != nil {
+				replyErr(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		types := []string{"order."}
+		if v := r.URL.Query().Get("types"); v != "" {
+			types = strings.Split(v, ",")
+		}
+		entries, err := s.EventLogSince(since, maxInventorySyncEvents, types...)
+		if err != nil {
+			replyErr(w, http.StatusInternalServerError, err)
+			return
+		}
+		reply(w, http.StatusOK, entries)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(auth, token) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// runInventorySyncServer serves the inventory sync HTTP endpoint until the
+// context is done.
+func (s *Store) runInventorySyncServer(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.InventorySync.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("unable to listen for inventory sync: %v", err)
+	}
+	srv := &http.Server{
+		Handler:           s.inventorySyncHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	s.log.Infof("Listening for inventory sync on %s", ln.Addr())
+	err = srv.Serve(ln)
+	if errors.Is(err, http.ErrServerClosed) {
+		return ctx.Err()
+	}
+	return err
+}
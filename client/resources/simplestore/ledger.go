@@ -0,0 +1,217 @@
+package simplestore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// LedgerFormat is the format of the plain text accounting entries written by
+// the store.
+type LedgerFormat string
+
+const (
+	LedgerFormatLedger    LedgerFormat = "ledger"
+	LedgerFormatBeancount LedgerFormat = "beancount"
+)
+
+// LedgerConfig configures the export of paid orders and refunds as
+// double-entry accounting entries to a plain text accounting file (as used by
+// ledger-cli or beancount).
+type LedgerConfig struct {
+	// Filename is the file where the entries are appended to. If empty,
+	// entries are not exported.
+	Filename string
+
+	// Format is the format of the entries. Defaults to ledger-cli.
+	Format LedgerFormat
+
+	// Commodity is the name of the commodity of amounts. Defaults to
+	// "DCR".
+	Commodity string
+
+	// Account names. Empty names are replaced by the default names.
+	LNAccount       string
+	OnChainAccount  string
+	SalesAccount    string
+	ShippingAccount string
+	TaxAccount      string
+	RefundsAccount  string
+}
+
+// withDefaults returns the config with the default values for the unset
+// fields.
+func (cfg LedgerConfig) withDefaults() LedgerConfig {
+	setDefault := func(v *string, def string) {
+		if *v == "" {
+			*v = def
+		}
+	}
+	if cfg.Format == "" {
+		cfg.Format = LedgerFormatLedger
+	}
+	setDefault(&cfg.Commodity, "DCR")
+	setDefault(&cfg.LNAccount, "Assets:Store:LN")
+	setDefault(&cfg.OnChainAccount, "Assets:Store:OnChain")
+	setDefault(&cfg.SalesAccount, "Income:Store:Sales")
+	setDefault(&cfg.ShippingAccount, "Income:Store:Shipping")
+	setDefault(&cfg.TaxAccount, "Liabilities:Store:Taxes")
+	setDefault(&cfg.RefundsAccount, "Expenses:Store:Refunds")
+	return cfg
+}
+
+// ledgerPosting is a single posting of a ledger transaction.
+type ledgerPosting struct {
+	account string
+	amount  dcrutil.Amount
+}
+
+// ledgerTx is a balanced ledger transaction.
+type ledgerTx struct {
+	date     time.Time
+	payee    string
+	meta     [][2]string
+	postings []ledgerPosting
+}
+
+// formatAmount formats the amount with the full precision of the commodity.
+func (cfg *LedgerConfig) formatAmount(amount dcrutil.Amount) string {
+	return strconv.FormatFloat(amount.ToCoin(), 'f', 8, 64) + " " + cfg.Commodity
+}
+
+// format formats the transaction according to the config.
+func (cfg *LedgerConfig) format(tx *ledgerTx) string {
+	var b strings.Builder
+	switch cfg.Format {
+	case LedgerFormatBeancount:
+		b.WriteString(fmt.Sprintf("%s * %q\n", tx.date.Format("2006-01-02"),
+			tx.payee))
+		for _, m := range tx.meta {
+			b.WriteString(fmt.Sprintf("  %s: %q\n", m[0], m[1]))
+		}
+	default:
+		b.WriteString(fmt.Sprintf("%s * %s\n", tx.date.Format("2006/01/02"),
+			tx.payee))
+		for _, m := range tx.meta {
+			b.WriteString(fmt.Sprintf("    ; %s: %s\n", m[0], m[1]))
+		}
+	}
+	for _, p := range tx.postings {
+		b.WriteString(fmt.Sprintf("    %-40s %s\n", p.account,
+			cfg.formatAmount(p.amount)))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// orderPaidTx returns the transaction that records the payment of the order.
+// The amount received is recorded, which may differ from the quoted total of
+// the order (e.g. when it was overpaid). The difference is recorded as sales.
+func (cfg *LedgerConfig) orderPaidTx(order *Order, date time.Time) *ledgerTx {
+	total := orderDCR(order)
+	var shipping dcrutil.Amount
+	if order.ShipCharge > 0 {
+		shipping, _ = order.ShipCharge.ToDCR(order.ExchangeRate)
+	}
+	var taxes dcrutil.Amount
+	if taxTotal := order.Taxes.Total(); taxTotal > 0 {
+		taxes, _ = taxTotal.ToDCR(order.ExchangeRate)
+	}
+	sales := total - shipping - taxes
+
+	assetsAccount := cfg.LNAccount
+	if order.PayType == PayTypeOnChain {
+		assetsAccount = cfg.OnChainAccount
+	}
+
+	tx := &ledgerTx{
+		date:  date,
+		payee: fmt.Sprintf("Order %s/%s paid", order.User.ShortLogID(), order.ID),
+		meta: [][2]string{
+			{"user", order.User.String()},
+			{strings.ToLower(order.CurrencyCode()) + "_total",
+				order.Total().String()},
+		},
+		postings: []ledgerPosting{
+			{account: assetsAccount, amount: total},
+			{account: cfg.SalesAccount, amount: -sales},
+		},
+	}
+	if shipping > 0 {
+		tx.postings = append(tx.postings, ledgerPosting{
+			account: cfg.ShippingAccount,
+			amount:  -shipping,
+		})
+	}
+	if taxes > 0 {
+		tx.postings = append(tx.postings, ledgerPosting{
+			account: cfg.TaxAccount,
+			amount:  -taxes,
+		})
+	}
+	return tx
+}
+
+// refundTx returns the transaction that records a refund of the order.
+func (cfg *LedgerConfig) refundTx(order *Order, amount dcrutil.Amount,
+	note string, date time.Time) *ledgerTx {
+
+	assetsAccount := cfg.LNAccount
+	if order.PayType == PayTypeOnChain {
+		assetsAccount = cfg.OnChainAccount
+	}
+	tx := &ledgerTx{
+		date:  date,
+		payee: fmt.Sprintf("Order %s/%s refund", order.User.ShortLogID(), order.ID),
+		meta: [][2]string{
+			{"user", order.User.String()},
+		},
+		postings: []ledgerPosting{
+			{account: cfg.RefundsAccount, amount: amount},
+			{account: assetsAccount, amount: -amount},
+		},
+	}
+	if note != "" {
+		tx.meta = append(tx.meta, [2]string{"note", note})
+	}
+	return tx
+}
+
+// appendLedgerTx appends the transaction to the ledger file, if one is
+// configured.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) appendLedgerTx(tx *ledgerTx) error {
+	cfg := s.cfg.Ledger.withDefaults()
+	if cfg.Filename == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Filename), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(cfg.Filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(cfg.format(tx)); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportOrderPaid exports the ledger entry of a paid order.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) exportOrderPaid(order *Order) {
+	cfg := s.cfg.Ledger.withDefaults()
+	if err := s.appendLedgerTx(cfg.orderPaidTx(order, time.Now())); err != nil {
+		s.log.Errorf("Unable to export ledger entry of order %s/%s: %v",
+			order.User.ShortLogID(), order.ID, err)
+	}
+}
@@ -0,0 +1,184 @@
+package simplestore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/internal/testutils"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/slog"
+)
+
+// testLedgerOrder returns an order of $27.50 ($20 of items, $5 of shipping and
+// $2.50 of taxes) quoted at 10 USD/DCR.
+func testLedgerOrder() *Order {
+	var order Order
+	order.User[0] = 0x01
+	order.ID = 1
+	order.ExchangeRate = 10
+	order.PayType = PayTypeLN
+	order.Cart.Items = []*CartItem{{
+		Product:  &Product{SKU: "book01", Price: MoneyFromFloat(10)},
+		Quantity: 2,
+	}}
+	order.ShipCharge = MoneyFromFloat(5)
+	order.Taxes = TaxLines{{Name: "VAT", Amount: MoneyFromFloat(2.5)}}
+	return &order
+}
+
+// assertLedgerPostings asserts the transaction has the given postings and that
+// they are balanced.
+func assertLedgerPostings(t testing.TB, tx *ledgerTx, want map[string]dcrutil.Amount) {
+	t.Helper()
+	got := make(map[string]dcrutil.Amount, len(tx.postings))
+	var sum dcrutil.Amount
+	for _, p := range tx.postings {
+		got[p.account] += p.amount
+		sum += p.amount
+	}
+	assert.DeepEqual(t, got, want)
+	assert.DeepEqual(t, sum, dcrutil.Amount(0))
+}
+
+// TestLedgerOrderPaidTx tests the postings of the transactions of paid orders.
+func TestLedgerOrderPaidTx(t *testing.T) {
+	t.Parallel()
+
+	cfg := LedgerConfig{}.withDefaults()
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		payType PayType
+		paid    float64
+		want    map[string]dcrutil.Amount
+	}{{
+		name:    "paid the quoted total",
+		payType: PayTypeLN,
+		paid:    2.75,
+		want: map[string]dcrutil.Amount{
+			cfg.LNAccount:       2.75e8,
+			cfg.SalesAccount:    -2e8,
+			cfg.ShippingAccount: -0.5e8,
+			cfg.TaxAccount:      -0.25e8,
+		},
+	}, {
+		name:    "overpaid",
+		payType: PayTypeOnChain,
+		paid:    3,
+		want: map[string]dcrutil.Amount{
+			cfg.OnChainAccount:  3e8,
+			cfg.SalesAccount:    -2.25e8,
+			cfg.ShippingAccount: -0.5e8,
+			cfg.TaxAccount:      -0.25e8,
+		},
+	}, {
+		name:    "underpaid",
+		payType: PayTypeLN,
+		paid:    2.7,
+		want: map[string]dcrutil.Amount{
+			cfg.LNAccount:       2.7e8,
+			cfg.SalesAccount:    -1.95e8,
+			cfg.ShippingAccount: -0.5e8,
+			cfg.TaxAccount:      -0.25e8,
+		},
+	}, {
+		name:    "paid amount not recorded",
+		payType: PayTypeLN,
+		want: map[string]dcrutil.Amount{
+			cfg.LNAccount:       2.75e8,
+			cfg.SalesAccount:    -2e8,
+			cfg.ShippingAccount: -0.5e8,
+			cfg.TaxAccount:      -0.25e8,
+		},
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			order := testLedgerOrder()
+			order.PayType = tc.payType
+			var err error
+			order.PaidAmount, err = dcrutil.NewAmount(tc.paid)
+			assert.NilErr(t, err)
+			tx := cfg.orderPaidTx(order, date)
+			assertLedgerPostings(t, tx, tc.want)
+			assert.DeepEqual(t, tx.date, date)
+		})
+	}
+}
+
+// TestLedgerRefundTx tests the postings of the transactions of refunds.
+func TestLedgerRefundTx(t *testing.T) {
+	t.Parallel()
+
+	cfg := LedgerConfig{RefundsAccount: "Expenses:Refunds"}.withDefaults()
+	order := testLedgerOrder()
+	order.PayType = PayTypeOnChain
+	tx := cfg.refundTx(order, 1e8, "damaged", time.Now())
+	assertLedgerPostings(t, tx, map[string]dcrutil.Amount{
+		"Expenses:Refunds": 1e8,
+		cfg.OnChainAccount: -1e8,
+	})
+	assert.DeepEqual(t, tx.meta[len(tx.meta)-1], [2]string{"note", "damaged"})
+}
+
+// TestLedgerFormat tests the formatting of transactions in the supported
+// formats.
+func TestLedgerFormat(t *testing.T) {
+	t.Parallel()
+
+	tx := &ledgerTx{
+		date:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		payee: "Order 1 paid",
+		meta:  [][2]string{{"user", "bob"}},
+		postings: []ledgerPosting{
+			{account: "Assets:LN", amount: 150000000},
+			{account: "Income:Sales", amount: -150000000},
+		},
+	}
+
+	cfg := LedgerConfig{}.withDefaults()
+	want := "2024/03/01 * Order 1 paid\n" +
+		"    ; user: bob\n" +
+		"    Assets:LN                                1.50000000 DCR\n" +
+		"    Income:Sales                             -1.50000000 DCR\n\n"
+	assert.DeepEqual(t, cfg.format(tx), want)
+
+	cfg = LedgerConfig{Format: LedgerFormatBeancount, Commodity: "XDCR"}.withDefaults()
+	want = "2024-03-01 * \"Order 1 paid\"\n" +
+		"  user: \"bob\"\n" +
+		"    Assets:LN                                1.50000000 XDCR\n" +
+		"    Income:Sales                             -1.50000000 XDCR\n\n"
+	assert.DeepEqual(t, cfg.format(tx), want)
+}
+
+// TestExportOrderPaid tests that the entries of paid orders are appended to
+// the configured ledger file.
+func TestExportOrderPaid(t *testing.T) {
+	t.Parallel()
+
+	fname := filepath.Join(testutils.TempTestDir(t, "ledger"), "store.ledger")
+	s := &Store{
+		cfg: Config{Ledger: LedgerConfig{Filename: fname}},
+		log: slog.Disabled,
+	}
+	order := testLedgerOrder()
+	order.PaidAmount = 3e8
+	s.exportOrderPaid(order)
+	order.ID = 2
+	s.exportOrderPaid(order)
+
+	data, err := os.ReadFile(fname)
+	assert.NilErr(t, err)
+	got := string(data)
+	assert.DeepEqual(t, strings.Count(got, "paid\n"), 2)
+	if !strings.Contains(got, "Assets:Store:LN                          3.00000000 DCR") {
+		t.Fatalf("ledger does not record the paid amount: %s", got)
+	}
+}
@@ -0,0 +1,146 @@
+package simplestore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// defaultPurchaseLimitsWindow is the window of the purchase limits when one is
+// not specified.
+const defaultPurchaseLimitsWindow = 24 * time.Hour
+
+// PurchaseLimits are the limits on the purchases of each user. These limit the
+// exposure of the store to fraudulent buyers without identifying them. Zero
+// values disable the corresponding limit. Admins of the store are not subject
+// to the limits.
+type PurchaseLimits struct {
+	// MaxOrderTotal is the max total of a single order, in the store
+	// currency.
+	MaxOrderTotal float64
+
+	// Window is the time window of MaxWindowOrders and MaxWindowTotal.
+	// Defaults to 24 hours.
+	Window time.Duration
+
+	// MaxWindowOrders is the max number of orders a user may place in the
+	// window. Canceled orders and orders that expired unpaid are not
+	// counted.
+	MaxWindowOrders int
+
+	// MaxWindowTotal is the max total of the orders a user may place in
+	// the window, in the store currency.
+	MaxWindowTotal float64
+
+	// MaxUnpaidOrders is the max number of unpaid orders (placed,
+	// confirmed or expired) a user may have before placing a new one.
+	MaxUnpaidOrders int
+
+	// Exempt are the users that are not subject to the limits (for
+	// example, trusted customers).
+	Exempt []clientintf.UserID
+}
+
+// exempt returns true if the user is not subject to the limits.
+func (limits *PurchaseLimits) exempt(uid clientintf.UserID) bool {
+	for _, e := range limits.Exempt {
+		if e == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// purchaseLimitViolations returns the purchase limits violated by the order.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) purchaseLimitViolations(order *Order) ([]string, error) {
+	limits := &s.cfg.PurchaseLimits
+	if limits.exempt(order.User) || s.isAdmin(order.User) {
+		return nil, nil
+	}
+
+	currency := s.currency()
+	total := order.Total()
+	var res []string
+	if max := MoneyFromFloat(limits.MaxOrderTotal); max > 0 && total > max {
+		res = append(res, fmt.Sprintf("The maximum order total is %s "+
+			"(the order totals %s)", formatAmount(max, currency),
+			formatAmount(total, currency)))
+	}
+
+	maxWindowTotal := MoneyFromFloat(limits.MaxWindowTotal)
+	if limits.MaxWindowOrders <= 0 && maxWindowTotal <= 0 && limits.MaxUnpaidOrders <= 0 {
+		return res, nil
+	}
+
+	window := limits.Window
+	if window <= 0 {
+		window = defaultPurchaseLimitsWindow
+	}
+	files, err := s.backend.List(userOrdersPattern(order.User))
+	if err != nil {
+		return nil, err
+	}
+	windowStart := time.Now().Add(-window)
+	var windowOrders, unpaidOrders int
+	var windowTotal Money
+	for _, fname := range files {
+		var prev Order
+		if err := s.backend.Read(fname, &prev); err != nil {
+			s.log.Warnf("Unable to decode order file %s: %v", fname, err)
+			continue
+		}
+		if prev.CanCancel() {
+			unpaidOrders++
+		}
+		if prev.Status == StatusCanceled || prev.PlacedTS.Before(windowStart) ||
+			(prev.Status == StatusExpired && prev.PaidTS == nil) {
+			continue
+		}
+		windowOrders++
+		if prev.CurrencyCode() == currency {
+			windowTotal += prev.Total()
+		}
+	}
+
+	if limits.MaxUnpaidOrders > 0 && unpaidOrders >= limits.MaxUnpaidOrders {
+		res = append(res, fmt.Sprintf("You have %d unpaid orders. Pay or "+
+			"cancel them before placing a new order", unpaidOrders))
+	}
+	if limits.MaxWindowOrders > 0 && windowOrders >= limits.MaxWindowOrders {
+		res = append(res, fmt.Sprintf("The maximum number of orders "+
+			"in %s is %d", window, limits.MaxWindowOrders))
+	}
+	if maxWindowTotal > 0 && windowTotal+total > maxWindowTotal {
+		res = append(res, fmt.Sprintf("The maximum total of the orders "+
+			"in %s is %s (your orders in this period total %s)", window,
+			formatAmount(maxWindowTotal, currency),
+			formatAmount(windowTotal+total, currency)))
+	}
+	return res, nil
+}
+
+// checkOrderAcceptance checks the order against the purchase limits and the
+// OrderFilter of the store. Returns the reasons for rejecting the order, if
+// any.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) checkOrderAcceptance(order *Order) ([]string, error) {
+	violations, err := s.purchaseLimitViolations(order)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) == 0 && s.cfg.OrderFilter != nil {
+		if err := s.cfg.OrderFilter(order); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+	if len(violations) > 0 {
+		s.log.Infof("Rejected order of user %s with total %s: %v",
+			order.User.ShortLogID(), order.FormatAmount(order.Total()),
+			violations)
+	}
+	return violations, nil
+}
@@ -0,0 +1,127 @@
+package simplestore
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrInvalidProduct is returned when a product cannot be saved due to an
+// invalid definition.
+var ErrInvalidProduct = errors.New("invalid product")
+
+// ProductUpdate is the definition of a product created with AddProduct or
+// updated with UpdateProduct.
+type ProductUpdate struct {
+	SKU         string
+	Title       string
+	Description string
+	Tags        []string
+	Price       Money
+	Shipping    bool
+	DigitalFile string
+
+	// Category is the slash separated path of the category of the product.
+	// Products moved to another category are moved to a new product file.
+	Category string
+
+	// Stock is the initial stock of new products. If nil, the product has
+	// unlimited stock. It is ignored when updating products.
+	Stock *int64
+}
+
+// Products returns the products of the store, sorted by SKU. Archived products
+// are only returned if includeArchived is true.
+func (s *Store) Products(includeArchived bool) ([]*Product, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	res := make([]*Product, 0, len(s.products))
+	for _, prod := range s.products {
+		res = append(res, prod)
+	}
+	if includeArchived {
+		archived, err := s.archivedProducts()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, archived...)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].SKU < res[j].SKU })
+	return res, nil
+}
+
+// AddProduct creates a new product in its own product file, under the dir of
+// its category.
+func (s *Store) AddProduct(upd *ProductUpdate) (*Product, error) {
+	prod, err := s.saveProduct(nil, true, upd, "")
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infof("Created product %s", prod.SKU)
+	return prod, nil
+}
+
+// UpdateProduct updates the existing product with the SKU of upd.
+func (s *Store) UpdateProduct(upd *ProductUpdate) (*Product, error) {
+	prod, err := s.saveProduct(nil, false, upd, "")
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infof("Updated product %s", prod.SKU)
+	return prod, nil
+}
+
+// Orders returns the orders of all users, sorted by the time they were placed.
+// If status is not empty, only the orders in that status are returned.
+func (s *Store) Orders(status OrderStatus) ([]*Order, error) {
+	s.mtx.Lock()
+	orders, err := s.loadAllOrders()
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	res := orders[:0]
+	for _, order := range orders {
+		if status == "" || order.Status == status {
+			res = append(res, order)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].PlacedTS.Before(res[j].PlacedTS)
+	})
+	return res, nil
+}
+
+// Sales are the totals of the sales of the store.
+type Sales struct {
+	// Count is the number of orders counted as sales (i.e. orders that
+	// were paid and not canceled).
+	Count int
+
+	// Totals are the total amounts of the sales in each currency.
+	Totals map[string]Money
+}
+
+// Sales returns the totals of the sales of orders placed after since. If since
+// is the zero time, the totals of all sales are returned.
+func (s *Store) Sales(since time.Time) (*Sales, error) {
+	s.mtx.Lock()
+	orders, err := s.loadAllOrders()
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(salesTotals)
+	res := &Sales{Totals: totals}
+	for _, order := range orders {
+		if !order.Status.isSale() || order.PlacedTS.Before(since) {
+			continue
+		}
+		res.Count++
+		totals.add(order.Currency, order.Total())
+	}
+	return res, nil
+}
@@ -0,0 +1,166 @@
+package simplestore
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/rates"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// Money is an amount in the currency of the store (or of a cart or order), in
+// cents.
+//
+// Amounts are encoded in JSON and TOML files as decimal numbers of currency
+// units (e.g. 19.99), as prices were before the type was introduced. Decimal
+// numbers in JSON are parsed exactly, without going through float64. Amounts
+// with more than 2 decimal places are rounded to the nearest cent.
+type Money int64
+
+// MoneyFromFloat converts an amount in currency units to Money, rounding to
+// the nearest cent.
+func MoneyFromFloat(v float64) Money {
+	return Money(math.Round(v * 100))
+}
+
+// ParseMoney parses a decimal amount in currency units (e.g. "19.99"), rounding
+// to the nearest cent.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	if strings.ContainsAny(digits, "eE") {
+		// Exponent notation. Fallback to parsing as a float.
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.Abs(f*100) >= math.MaxInt64 {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+		return MoneyFromFloat(f), nil
+	}
+
+	intPart, fracPart := digits, ""
+	if i := strings.IndexByte(digits, '.'); i > -1 {
+		intPart, fracPart = digits[:i], digits[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	for _, c := range intPart + fracPart {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	var cents int64
+	for i := 0; i < 2; i++ {
+		cents *= 10
+		if i < len(fracPart) {
+			cents += int64(fracPart[i] - '0')
+		}
+	}
+	if len(fracPart) > 2 && fracPart[2] >= '5' {
+		cents += 1
+	}
+
+	m := Money(units*100 + cents)
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+// Float returns the amount in currency units.
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// Mul returns the amount multiplied by n.
+func (m Money) Mul(n int) Money {
+	return m * Money(n)
+}
+
+// Percent returns pct percent of the amount, rounded to the nearest cent.
+func (m Money) Percent(pct float64) Money {
+	return Money(math.Round(float64(m) * pct / 100))
+}
+
+// String returns the amount in currency units, with 2 decimal places.
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign, m = "-", -m
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, int64(m/100), int64(m%100))
+}
+
+// Format returns the amount formatted in the given currency.
+func (m Money) Format(currency string) string {
+	currency = currencyOrDefault(currency)
+	return fmt.Sprintf("%s%s %s", rates.CurrencySymbol(currency), m, currency)
+}
+
+// ToDCR converts the amount to DCR, given the price of one DCR in the currency
+// of the amount. Returns zero if the rate is not positive.
+func (m Money) ToDCR(rate float64) (dcrutil.Amount, error) {
+	if rate <= 0 {
+		return 0, nil
+	}
+	return dcrutil.NewAmount(m.Float() / rate)
+}
+
+// MoneyFromDCR converts an amount in DCR to Money, given the price of one DCR
+// in the currency of the amount.
+func MoneyFromDCR(amount dcrutil.Amount, rate float64) Money {
+	return MoneyFromFloat(amount.ToCoin() * rate)
+}
+
+// MarshalJSON encodes the amount as a decimal number of currency units.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a decimal number of currency units.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// MarshalTOML encodes the amount as a decimal number of currency units.
+func (m Money) MarshalTOML() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalTOML decodes an amount of currency units.
+func (m *Money) UnmarshalTOML(v interface{}) error {
+	switch v := v.(type) {
+	case float64:
+		*m = MoneyFromFloat(v)
+	case int64:
+		*m = Money(v * 100)
+	case string:
+		var err error
+		*m, err = ParseMoney(v)
+		return err
+	default:
+		return fmt.Errorf("invalid amount %v", v)
+	}
+	return nil
+}
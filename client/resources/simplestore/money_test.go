@@ -0,0 +1,132 @@
+package simplestore
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+)
+
+// TestParseMoney tests parsing decimal amounts.
+func TestParseMoney(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s       string
+		want    Money
+		wantErr bool
+	}{
+		{s: "0", want: 0},
+		{s: "19.99", want: 1999},
+		{s: " 19.99 ", want: 1999},
+		{s: "+19.99", want: 1999},
+		{s: "20", want: 2000},
+		{s: "1.", want: 100},
+		{s: ".5", want: 50},
+		{s: "-.5", want: -50},
+		{s: "0.05", want: 5},
+		{s: "-19.99", want: -1999},
+		{s: "-0.01", want: -1},
+		{s: "1.234", want: 123},
+		{s: "1.235", want: 124},
+		{s: "1.2349999", want: 123},
+		{s: "-1.235", want: -124},
+		{s: "99.995", want: 10000},
+		{s: "1.5e1", want: 1500},
+		{s: "1E-2", want: 1},
+		{s: "92233720368547757", want: 9223372036854775700},
+		{s: "92233720368547758", wantErr: true},
+		{s: "-92233720368547758", wantErr: true},
+		{s: "99999999999999999999", wantErr: true},
+		{s: "1e30", wantErr: true},
+		{s: "-1e30", wantErr: true},
+		{s: "1e400", wantErr: true},
+		{s: "", wantErr: true},
+		{s: ".", wantErr: true},
+		{s: "-", wantErr: true},
+		{s: "--1", wantErr: true},
+		{s: "1.2.3", wantErr: true},
+		{s: "1,5", wantErr: true},
+		{s: "$5", wantErr: true},
+		{s: "abc", wantErr: true},
+		{s: "NaN", wantErr: true},
+		{s: "Inf", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.s, func(t *testing.T) {
+			got, err := ParseMoney(tc.s)
+			if tc.wantErr {
+				assert.NonNilErr(t, err)
+				return
+			}
+			assert.NilErr(t, err)
+			assert.DeepEqual(t, got, tc.want)
+		})
+	}
+}
+
+// TestMoneyString tests formatting amounts.
+func TestMoneyString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{m: 0, want: "0.00"},
+		{m: 5, want: "0.05"},
+		{m: 1999, want: "19.99"},
+		{m: -1, want: "-0.01"},
+		{m: -1999, want: "-19.99"},
+		{m: math.MaxInt64, want: "92233720368547758.07"},
+	}
+	for _, tc := range tests {
+		assert.DeepEqual(t, tc.m.String(), tc.want)
+	}
+}
+
+// TestMoneyJSON tests that amounts are encoded in JSON as decimal numbers and
+// that encoded amounts are decoded back to the same amount.
+func TestMoneyJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range []Money{0, 1, 1999, -1999, math.MaxInt64 / 100 * 99} {
+		b, err := json.Marshal(m)
+		assert.NilErr(t, err)
+		var got Money
+		assert.NilErr(t, json.Unmarshal(b, &got))
+		assert.DeepEqual(t, got, m)
+	}
+
+	// Amounts saved as strings and null amounts are also decoded.
+	var v struct {
+		Price Money `json:"price"`
+	}
+	assert.NilErr(t, json.Unmarshal([]byte(`{"price":"4.50"}`), &v))
+	assert.DeepEqual(t, v.Price, Money(450))
+	assert.NilErr(t, json.Unmarshal([]byte(`{"price":null}`), &v))
+	assert.DeepEqual(t, v.Price, Money(450))
+	assert.NonNilErr(t, json.Unmarshal([]byte(`{"price":"4,50"}`), &v))
+}
+
+// TestMoneyConversions tests converting amounts to and from floats and DCR.
+func TestMoneyConversions(t *testing.T) {
+	t.Parallel()
+
+	assert.DeepEqual(t, MoneyFromFloat(19.99), Money(1999))
+	assert.DeepEqual(t, MoneyFromFloat(0.005), Money(1))
+	assert.DeepEqual(t, MoneyFromFloat(-0.005), Money(-1))
+	assert.DeepEqual(t, Money(1999).Float(), 19.99)
+	assert.DeepEqual(t, Money(1000).Percent(7.5), Money(75))
+
+	amount, err := Money(2000).ToDCR(10)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, amount.ToCoin(), 2.0)
+	amount, err = Money(2000).ToDCR(0)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, int64(amount), int64(0))
+	assert.DeepEqual(t, MoneyFromDCR(2e8, 10), Money(2000))
+}
@@ -0,0 +1,407 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/slog"
+	"golang.org/x/exp/slices"
+)
+
+// OrderFilter filters the orders returned by QueryOrders. Zero fields do not
+// filter the orders.
+type OrderFilter struct {
+	// Statuses are the statuses of the returned orders.
+	Statuses []OrderStatus
+
+	// From and To are the range [From, To) of the placement time of the
+	// returned orders.
+	From time.Time
+	To   time.Time
+
+	// User is the user that placed the returned orders.
+	User *clientintf.UserID
+
+	// SKU is the SKU of a product ordered (directly or as a component of
+	// a bundle) in the returned orders.
+	SKU string
+
+	// Currency is the currency of the returned orders. When MinTotal is
+	// set and Currency is empty, it defaults to the currency of the store,
+	// so that amounts in different currencies are never compared.
+	Currency string
+
+	// MinTotal is the minimum total amount of the returned orders, in
+	// Currency.
+	MinTotal Money
+}
+
+// orderIndexEntry is the indexed data of an order.
+type orderIndexEntry struct {
+	user     clientintf.UserID
+	id       OrderID
+	status   OrderStatus
+	placedTS time.Time
+	skus     []string
+	currency string
+	total    Money
+}
+
+// newOrderIndexEntry returns the index entry of the order.
+func newOrderIndexEntry(order *Order) orderIndexEntry {
+	e := orderIndexEntry{
+		user:     order.User,
+		id:       order.ID,
+		status:   order.Status,
+		placedTS: order.PlacedTS,
+		currency: order.CurrencyCode(),
+		total:    order.Total(),
+	}
+	for _, item := range order.Cart.Items {
+		if item.Product == nil {
+			continue
+		}
+		e.skus = append(e.skus, item.Product.SKU)
+		for _, u := range item.stockUnits() {
+			e.skus = append(e.skus, u.sku)
+		}
+	}
+	return e
+}
+
+// matches returns true if the indexed order matches the filter.
+func (e *orderIndexEntry) matches(filter *OrderFilter) bool {
+	switch {
+	case len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, e.status):
+		return false
+	case e.placedTS.Before(filter.From):
+		return false
+	case !filter.To.IsZero() && !e.placedTS.Before(filter.To):
+		return false
+	case filter.User != nil && *filter.User != e.user:
+		return false
+	case filter.SKU != "" && !slices.Contains(e.skus, filter.SKU):
+		return false
+	case filter.Currency != "" && filter.Currency != e.currency:
+		return false
+	case e.total < filter.MinTotal:
+		return false
+	}
+	return true
+}
+
+// orderIndex is an in-memory index of the orders of the store, keyed by the
+// key of the order document. It is loaded on the first query and then kept
+// up to date as orders are written through the store backend.
+type orderIndex struct {
+	log slog.Logger
+
+	mtx     sync.Mutex
+	loaded  bool
+	entries map[string]orderIndexEntry
+}
+
+// load loads the index from the orders stored in the backend, if it was not
+// loaded yet.
+//
+// This MUST be called with the index mutex held.
+func (idx *orderIndex) load(backend StoreBackend) error {
+	if idx.loaded {
+		return nil
+	}
+	keys, err := backend.List(allOrdersPattern)
+	if err != nil {
+		return err
+	}
+	entries := make(map[string]orderIndexEntry, len(keys))
+	for _, key := range keys {
+		var order Order
+		if err := backend.Read(key, &order); err != nil {
+			idx.log.Warnf("Unable to decode order file %s: %v", key, err)
+			continue
+		}
+		entries[key] = newOrderIndexEntry(&order)
+	}
+	idx.entries = entries
+	idx.loaded = true
+	return nil
+}
+
+// apply applies the committed writes and removals of orders to the index.
+func (idx *orderIndex) apply(written map[string]orderIndexEntry, removed []string) {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	// Orders are indexed when loading the index.
+	if !idx.loaded {
+		return
+	}
+	for _, key := range removed {
+		delete(idx.entries, key)
+	}
+	for key, e := range written {
+		idx.entries[key] = e
+	}
+}
+
+// query returns the keys of the orders that match the filter, sorted from the
+// most recently placed.
+func (idx *orderIndex) query(backend StoreBackend, filter *OrderFilter) ([]string, error) {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	if err := idx.load(backend); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for key, e := range idx.entries {
+		if e.matches(filter) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ei, ej := idx.entries[keys[i]], idx.entries[keys[j]]
+		if !ei.placedTS.Equal(ej.placedTS) {
+			return ei.placedTS.After(ej.placedTS)
+		}
+		return keys[i] < keys[j]
+	})
+	return keys, nil
+}
+
+// isOrderKey returns true if the key is the key of an order document.
+func isOrderKey(key string) bool {
+	ok, _ := path.Match(allOrdersPattern, key)
+	return ok
+}
+
+// indexingBackend is a store backend that keeps the order index up to date
+// with the orders written through it.
+type indexingBackend struct {
+	StoreBackend
+	idx *orderIndex
+}
+
+// NewBatch is part of the StoreBackend interface.
+func (ib *indexingBackend) NewBatch() StoreBatch {
+	return &indexingBatch{StoreBatch: ib.StoreBackend.NewBatch(), idx: ib.idx}
+}
+
+// indexingBatch is a batch that applies its writes and removals of orders to
+// the order index once committed.
+type indexingBatch struct {
+	StoreBatch
+	idx     *orderIndex
+	written map[string]orderIndexEntry
+	removed []string
+}
+
+func (b *indexingBatch) Write(key string, data interface{}) {
+	b.StoreBatch.Write(key, data)
+	if !isOrderKey(key) {
+		return
+	}
+
+	// The entry is created now, as the order may be modified by the
+	// caller before the batch is committed.
+	var e orderIndexEntry
+	switch order := data.(type) {
+	case *Order:
+		e = newOrderIndexEntry(order)
+	case Order:
+		e = newOrderIndexEntry(&order)
+	default:
+		return
+	}
+	if b.written == nil {
+		b.written = make(map[string]orderIndexEntry)
+	}
+	b.written[key] = e
+}
+
+func (b *indexingBatch) Remove(key string) {
+	b.StoreBatch.Remove(key)
+	if isOrderKey(key) {
+		delete(b.written, key)
+		b.removed = append(b.removed, key)
+	}
+}
+
+func (b *indexingBatch) Commit() error {
+	if err := b.StoreBatch.Commit(); err != nil {
+		return err
+	}
+	if len(b.written) > 0 || len(b.removed) > 0 {
+		b.idx.apply(b.written, b.removed)
+	}
+	return nil
+}
+
+// QueryOrders returns the orders that match the filter, sorted from the most
+// recently placed. The orders are selected through an index of the orders,
+// so only the matching orders are read from the store backend.
+func (s *Store) QueryOrders(filter OrderFilter) ([]*Order, error) {
+	filter.Currency = strings.ToUpper(filter.Currency)
+	if filter.MinTotal != 0 && filter.Currency == "" {
+		filter.Currency = s.currency()
+	}
+	keys, err := s.orders.query(s.backend, &filter)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]*Order, 0, len(keys))
+	for _, key := range keys {
+		order := new(Order)
+		if err := s.backend.Read(key, order); err != nil {
+			s.log.Warnf("Unable to decode order file %s: %v", key, err)
+			continue
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// parseOrderFilter parses the filter of an order query from the elements of
+// a path in the <field>=<value> format. The status field is a comma
+// separated list of statuses, the from and to fields are dates in the
+// YYYY-MM-DD format (to is exclusive), the days field is the number of days
+// before now when the orders were placed, the currency field is the currency
+// of the orders and the mintotal field is an amount in that currency (by
+// default, the currency of the store).
+func parseOrderFilter(elems []string, now time.Time) (OrderFilter, error) {
+	var filter OrderFilter
+	for _, elem := range elems {
+		field, value, ok := strings.Cut(elem, "=")
+		if !ok || value == "" {
+			return filter, fmt.Errorf("filter %q not in the format "+
+				"<field>=<value>", elem)
+		}
+		var err error
+		switch field {
+		case "status":
+			for _, v := range strings.Split(value, ",") {
+				status := OrderStatus(v)
+				if !status.IsValid() {
+					return filter, fmt.Errorf("unknown order status %q", v)
+				}
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		case "from":
+			filter.From, err = time.ParseInLocation(exportDateLayout, value, time.Local)
+		case "to":
+			filter.To, err = time.ParseInLocation(exportDateLayout, value, time.Local)
+		case "user":
+			var uid clientintf.UserID
+			err = uid.FromString(value)
+			filter.User = &uid
+		case "sku":
+			filter.SKU = value
+		case "days":
+			var days uint64
+			days, err = strconv.ParseUint(value, 10, 16)
+			filter.From = now.AddDate(0, 0, -int(days))
+		case "currency":
+			filter.Currency = strings.ToUpper(value)
+		case "mintotal":
+			filter.MinTotal, err = ParseMoney(value)
+		default:
+			return filter, fmt.Errorf("unknown filter field %q", field)
+		}
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s filter %q: %v", field,
+				value, err)
+		}
+	}
+	return filter, nil
+}
+
+// handleAdminQueryOrders lists the orders that match the filter given as
+// /admin/queryorders[/<field>=<value>...]. See parseOrderFilter for the
+// filter fields.
+func (s *Store) handleAdminQueryOrders(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	filter, err := parseOrderFilter(request.Path[2:], time.Now())
+	if err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(err.Error()),
+		}, nil
+	}
+
+	orders, err := s.QueryOrders(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tctx := adminOrdersContext{
+		Orders:   make([]adminOrderSummary, 0, len(orders)),
+		Query:    strings.Join(request.Path[2:], " "),
+		Statuses: allOrderStatuses,
+	}
+	for _, order := range orders {
+		tctx.Orders = append(tctx.Orders, s.adminOrderSummary(order))
+	}
+
+	w := &bytes.Buffer{}
+	err = s.render.Render(w, adminOrdersTmplFile, &tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute orders template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// ValidateQuery validates the terms of a saved query over the orders of the
+// store. See parseOrderFilter for the fields of the terms.
+//
+// This is part of the client.SavedQueryRunner interface.
+func (s *Store) ValidateQuery(terms []string) error {
+	_, err := parseOrderFilter(terms, time.Now())
+	return err
+}
+
+// RunQuery returns the orders that match the terms of a saved query, from the
+// most recently placed.
+//
+// This is part of the client.SavedQueryRunner interface.
+func (s *Store) RunQuery(terms []string) ([]client.SavedQueryResult, error) {
+	filter, err := parseOrderFilter(terms, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.QueryOrders(filter)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]client.SavedQueryResult, 0, len(orders))
+	for _, order := range orders {
+		nick := order.User.ShortLogID()
+		if s.c != nil {
+			if n, err := s.c.UserNick(order.User); err == nil {
+				nick = n
+			}
+		}
+		res = append(res, client.SavedQueryResult{
+			Timestamp: order.PlacedTS,
+			Source:    "order " + order.ID.String(),
+			Text: fmt.Sprintf("%s from %s: %s", order.Status, nick,
+				order.Total().Format(order.Currency)),
+		})
+	}
+	return res, nil
+}
+
+var _ client.SavedQueryRunner = (*Store)(nil)
@@ -0,0 +1,77 @@
+package simplestore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+)
+
+// TestOrderIndexMinTotal tests that the minimum total of the order filter is
+// only compared to orders in the same currency.
+func TestOrderIndexMinTotal(t *testing.T) {
+	t.Parallel()
+
+	usd := orderIndexEntry{currency: "USD", total: MoneyFromFloat(100)}
+	eur := orderIndexEntry{currency: "EUR", total: MoneyFromFloat(100)}
+
+	tests := []struct {
+		name    string
+		filter  OrderFilter
+		wantUSD bool
+		wantEUR bool
+	}{
+		{name: "no filter", wantUSD: true, wantEUR: true},
+		{
+			name:    "same currency",
+			filter:  OrderFilter{Currency: "USD", MinTotal: MoneyFromFloat(50)},
+			wantUSD: true,
+		},
+		{
+			name:   "total too low",
+			filter: OrderFilter{Currency: "USD", MinTotal: MoneyFromFloat(150)},
+		},
+		{
+			name:    "other currency",
+			filter:  OrderFilter{Currency: "EUR", MinTotal: MoneyFromFloat(100)},
+			wantEUR: true,
+		},
+		{
+			name:    "currency only",
+			filter:  OrderFilter{Currency: "EUR"},
+			wantEUR: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.DeepEqual(t, usd.matches(&tc.filter), tc.wantUSD)
+			assert.DeepEqual(t, eur.matches(&tc.filter), tc.wantEUR)
+		})
+	}
+}
+
+// TestParseOrderFilter tests parsing the fields of order filters.
+func TestParseOrderFilter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	filter, err := parseOrderFilter([]string{"status=placed,paid",
+		"currency=eur", "mintotal=20.5", "days=7", "sku=book01"}, now)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, filter.Statuses, []OrderStatus{StatusPlaced, StatusPaid})
+	assert.DeepEqual(t, filter.Currency, "EUR")
+	assert.DeepEqual(t, filter.MinTotal, MoneyFromFloat(20.5))
+	assert.DeepEqual(t, filter.From, now.AddDate(0, 0, -7))
+	assert.DeepEqual(t, filter.SKU, "book01")
+
+	for _, terms := range [][]string{
+		{"mintotal=abc"},
+		{"status=unknown"},
+		{"currency"},
+		{"color=red"},
+	} {
+		_, err := parseOrderFilter(terms, now)
+		assert.NonNilErr(t, err)
+	}
+}
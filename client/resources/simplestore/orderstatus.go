@@ -0,0 +1,201 @@
+package simplestore
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// ErrInvalidStatusTransition is returned when attempting to change the status
+// of an order to a status that is not reachable from its current one.
+var ErrInvalidStatusTransition = errors.New("invalid order status transition")
+
+// orderTransitions are the valid transitions between order statuses. Orders
+// start as placed and end as completed or canceled. Expired orders may be
+// requoted, which places them again. Paid orders with backordered items are
+// backordered until their items are in stock. Orders placed while the exchange
+// rate is not available are quote pending until they are quoted.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	StatusQuotePending: {StatusPlaced, StatusCanceled, StatusExpired},
+	StatusPlaced:       {StatusConfirmed, StatusPaid, StatusCanceled, StatusExpired},
+	StatusConfirmed:    {StatusPaid, StatusCanceled, StatusExpired},
+	StatusPaid:         {StatusBackordered, StatusShipped, StatusCompleted, StatusCanceled},
+	StatusBackordered:  {StatusPaid, StatusCanceled},
+	StatusShipped:      {StatusCompleted, StatusCanceled},
+	StatusExpired:      {StatusPlaced, StatusCanceled},
+}
+
+// IsValid returns true if the status is one of the known order statuses.
+func (status OrderStatus) IsValid() bool {
+	switch status {
+	case StatusPlaced, StatusConfirmed, StatusPaid, StatusBackordered,
+		StatusShipped, StatusCompleted, StatusCanceled, StatusExpired,
+		StatusQuotePending:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal returns true if orders in this status cannot be changed anymore.
+func (status OrderStatus) IsFinal() bool {
+	return status.IsValid() && len(orderTransitions[status]) == 0
+}
+
+// NextStatuses returns the statuses an order may be changed to from this
+// status.
+func (status OrderStatus) NextStatuses() []OrderStatus {
+	return orderTransitions[status]
+}
+
+// CanTransitionTo returns true if an order in this status may be changed to
+// the target status.
+func (status OrderStatus) CanTransitionTo(target OrderStatus) bool {
+	for _, next := range orderTransitions[status] {
+		if next == target {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderStatusChange records a change in the status of an order.
+type OrderStatusChange struct {
+	From      OrderStatus        `json:"from"`
+	To        OrderStatus        `json:"to"`
+	Timestamp time.Time          `json:"ts"`
+	By        *clientintf.UserID `json:"by,omitempty"`
+}
+
+// setStatus changes the status of the order, recording the change in its
+// history. by is the admin that changed the status or nil if the change was
+// done automatically by the store.
+func (order *Order) setStatus(status OrderStatus, by *clientintf.UserID) error {
+	if !order.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w from %q to %q", ErrInvalidStatusTransition,
+			order.Status, status)
+	}
+	if status == StatusCompleted && order.Escrow != nil && !order.Escrow.Released() {
+		return fmt.Errorf("%w: the escrow of the order was not released "+
+			"by both the buyer and the store", ErrInvalidStatusTransition)
+	}
+	now := time.Now()
+	order.StatusHistory = append(order.StatusHistory, OrderStatusChange{
+		From:      order.Status,
+		To:        status,
+		Timestamp: now,
+		By:        by,
+	})
+	order.Status = status
+	if status.IsFinal() {
+		order.ResolvedTS = &now
+	} else {
+		order.ResolvedTS = nil
+	}
+	return nil
+}
+
+// updateOrderStatus loads the order, changes its status and saves it.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) updateOrderStatus(uid clientintf.UserID, id OrderID,
+	status OrderStatus, by *clientintf.UserID) (*Order, error) {
+
+	return s.updateOrderStatusWith(uid, id, status, by, nil)
+}
+
+// updateOrderStatusWith is like updateOrderStatus, but calls update (if not
+// nil) to modify the order before it is saved with the new status.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) updateOrderStatusWith(uid clientintf.UserID, id OrderID,
+	status OrderStatus, by *clientintf.UserID, update func(*Order)) (*Order, error) {
+
+	orderFname := orderKey(uid, id)
+	order := new(Order)
+	if err := s.backend.Read(orderFname, order); err != nil {
+		return nil, err
+	}
+	oldStatus := order.Status
+	if err := order.setStatus(status, by); err != nil {
+		return nil, err
+	}
+	if update != nil {
+		update(order)
+	}
+
+	// Paid orders wait in the backordered status for their backordered
+	// items.
+	if order.Status == StatusPaid && order.IsBackordered() {
+		if err := order.setStatus(StatusBackordered, nil); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.writeDoc(orderFname, order); err != nil {
+		return nil, err
+	}
+
+	// Return the items of canceled and expired orders to the stock. The
+	// items of expired orders were already returned.
+	if (status == StatusCanceled || status == StatusExpired) &&
+		oldStatus != StatusExpired {
+		if err := s.restockOrder(order); err != nil {
+			s.log.Warnf("Unable to restock items of order %s/%s: %v",
+				uid.ShortLogID(), order.ID, err)
+		} else {
+			s.fillBackorders()
+		}
+	}
+
+	// Deliver the digital items of paid orders and start or renew their
+	// subscriptions.
+	if status == StatusPaid {
+		s.deliverDigitalFiles(order)
+		s.subscriptionOrderPaid(order)
+	}
+	if order.IsGift() && order.Status != oldStatus {
+		s.notifyGiftRecipient(order)
+	}
+
+	s.logOrderEvent(EventOrderStatus, by, order, map[string]string{
+		"from": string(oldStatus),
+		"to":   string(order.Status),
+	})
+
+	switch status {
+	case StatusPaid:
+		s.emitWebhook(WebhookOrderPaid, order)
+	case StatusCanceled:
+		s.emitWebhook(WebhookOrderCanceled, order)
+	}
+	if status == StatusPaid {
+		s.emitOrderActivity(ActivityOrderPaid, order, oldStatus)
+	}
+	s.emitOrderActivity(ActivityStatusChanged, order, oldStatus)
+
+	s.log.Infof("Order %s/%s changed to status %s", uid.ShortLogID(),
+		order.ID, order.Status)
+	return order, nil
+}
+
+// UpdateOrderStatus changes the status of the order placed by the given user.
+// Only transitions allowed by the order lifecycle (placed -> confirmed ->
+// paid -> shipped -> completed, with orders being canceled or expired along
+// the way) are accepted. The user is sent a receipt for the new status and
+// the change is notified through the StatusChanged callback.
+func (s *Store) UpdateOrderStatus(uid clientintf.UserID, id OrderID, status OrderStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("unknown order status %q", status)
+	}
+
+	s.mtx.Lock()
+	order, err := s.updateOrderStatus(uid, id, status, nil)
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	s.notifyStatusChanged(order)
+	return nil
+}
@@ -0,0 +1,134 @@
+package simplestore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/companyzero/bisonrelay/client/resources"
+	"github.com/decred/slog"
+)
+
+// templatesDir is the dir, in the store root, with the templates (*.tmpl)
+// that override the templates of the active theme, of the store root and of
+// the default store template.
+//
+// Overrides that fail to parse or to render are ignored (after logging the
+// error) and the template they override is used instead, so that mistakes
+// when editing them do not take the store offline.
+const templatesDir = "templates"
+
+// overridesEngine is a RenderEngine that renders templates with the template
+// overrides and falls back to the base templates when rendering an override
+// fails.
+type overridesEngine struct {
+	overrides *resources.TextTemplateEngine
+	names     map[string]struct{}
+	base      resources.RenderEngine
+	log       slog.Logger
+}
+
+// Render is part of the RenderEngine interface.
+func (e *overridesEngine) Render(w io.Writer, name string, data interface{}) error {
+	if _, ok := e.names[name]; !ok {
+		return e.base.Render(w, name, data)
+	}
+
+	// Render to a buffer, so that a failure does not write a partially
+	// rendered page.
+	var b bytes.Buffer
+	err := e.overrides.Render(&b, name, data)
+	if err == nil {
+		_, err = w.Write(b.Bytes())
+		return err
+	}
+	e.log.Errorf("Unable to render template override %s (using the base "+
+		"template instead): %v", name, err)
+	if !e.base.Has(name) {
+		return err
+	}
+	return e.base.Render(w, name, data)
+}
+
+// Has is part of the RenderEngine interface.
+func (e *overridesEngine) Has(name string) bool {
+	if _, ok := e.names[name]; ok {
+		return true
+	}
+	return e.base.Has(name)
+}
+
+// loadTemplateOverrides returns an engine that renders the templates in the
+// templates dir of the store root, using the templates of base for the ones
+// not overridden. Overrides that fail to parse are logged and skipped. If
+// there are no valid overrides, base is returned.
+func (s *Store) loadTemplateOverrides(base *template.Template) (resources.RenderEngine, error) {
+	baseEngine := resources.NewTextTemplateEngine(base)
+	dir := filepath.Join(s.root, templatesDir)
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(filenames) == 0 {
+		return baseEngine, nil
+	}
+	sort.Strings(filenames)
+
+	tmpl, err := base.Clone()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{}, len(filenames))
+	for _, filename := range filenames {
+		name := filepath.Base(filename)
+		rawBytes, err := os.ReadFile(filename)
+		if err != nil {
+			s.log.Errorf("Unable to read template override %s: %v",
+				filename, err)
+			continue
+		}
+		data := resources.ProcessEmbeds(string(rawBytes), dir, s.log)
+
+		// Check the template parses before adding it to the set, to
+		// avoid replacing the base template with a broken one.
+		if _, err := template.New(name).Funcs(s.templateFuncs()).Parse(data); err != nil {
+			s.log.Errorf("Unable to parse template override %s (using "+
+				"the base template instead): %v", filename, err)
+			continue
+		}
+		if _, err := tmpl.New(name).Parse(data); err != nil {
+			return nil, fmt.Errorf("unable to parse template %s: %v",
+				filename, err)
+		}
+		names[name] = struct{}{}
+	}
+	if len(names) == 0 {
+		return baseEngine, nil
+	}
+	s.log.Debugf("Loaded %d template overrides", len(names))
+
+	return &overridesEngine{
+		overrides: resources.NewTextTemplateEngine(tmpl),
+		names:     names,
+		base:      baseEngine,
+		log:       s.log,
+	}, nil
+}
+
+// reloadTemplates reloads the templates of the store without reloading the
+// product catalog.
+func (s *Store) reloadTemplates() error {
+	render, err := s.loadRenderEngine()
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	s.render = render
+	s.purgePageCache()
+	s.mtx.Unlock()
+	return nil
+}
@@ -0,0 +1,126 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// renderPackingSlips renders the packing slips of the given orders.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) renderPackingSlips(orders []*Order) ([]byte, error) {
+	w := &bytes.Buffer{}
+	tctx := &packingSlipContext{Orders: orders}
+	err := s.render.Render(w, packingSlipTmplFile, tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute packing slip template: %v", err)
+	}
+	return w.Bytes(), nil
+}
+
+// PackingSlip renders the packing slip of the given order. The packing slip
+// only includes information relevant to shipping the order (shipping address
+// and items to ship).
+func (s *Store) PackingSlip(uid clientintf.UserID, id OrderID) ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	orderFname := orderKey(uid, id)
+	var order Order
+	if err := s.backend.Read(orderFname, &order); err != nil {
+		return nil, err
+	}
+	if !order.NeedsShipping() {
+		return nil, fmt.Errorf("order %s/%s does not need shipping",
+			uid.ShortLogID(), id)
+	}
+	if err := s.loadOrderShipAddr(&order); err != nil {
+		return nil, err
+	}
+	return s.renderPackingSlips([]*Order{&order})
+}
+
+// PackingSlips renders the packing slips of all orders with the given status
+// that need shipping, sorted by placement time.
+func (s *Store) PackingSlips(status OrderStatus) ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	files, err := s.backend.List(allOrdersPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []*Order
+	for _, f := range files {
+		order := new(Order)
+		if err := s.backend.Read(f, order); err != nil {
+			s.log.Warnf("Unable to decode order file %s: %v", f, err)
+			continue
+		}
+		if order.Status != status || !order.NeedsShipping() {
+			continue
+		}
+		if err := s.loadOrderShipAddr(order); err != nil {
+			s.log.Warnf("Unable to load shipping address: %v", err)
+			continue
+		}
+		orders = append(orders, order)
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].PlacedTS.Before(orders[j].PlacedTS)
+	})
+	return s.renderPackingSlips(orders)
+}
+
+func (s *Store) handleAdminPackingSlip(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if len(request.Path) < 4 {
+		return nil, fmt.Errorf("path has < 4 elements")
+	}
+
+	var uid clientintf.UserID
+	if err := uid.FromString(request.Path[2]); err != nil {
+		return nil, err
+	}
+	var oid OrderID
+	if err := oid.FromString(request.Path[3]); err != nil {
+		return nil, err
+	}
+
+	data, err := s.PackingSlip(uid, oid)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   data,
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+func (s *Store) handleAdminPackingSlips(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	// By default, list the packing slips of paid orders, which are the
+	// ones ready to be shipped.
+	status := StatusPaid
+	if len(request.Path) > 2 {
+		status = OrderStatus(request.Path[2])
+	}
+
+	data, err := s.PackingSlips(status)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   data,
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
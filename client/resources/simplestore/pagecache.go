@@ -0,0 +1,66 @@
+package simplestore
+
+import (
+	"github.com/companyzero/bisonrelay/client/internal/lrucache"
+	"github.com/companyzero/bisonrelay/client/metrics"
+)
+
+// defaultPageCacheSize is the default max size of the cache of rendered
+// product pages.
+const defaultPageCacheSize = 16 << 20 // 16MiB
+
+// newPageCache creates the cache of rendered product pages, according to the
+// config. Returns nil if the cache is disabled.
+func newPageCache(cfg Config) *lrucache.Cache[string, []byte] {
+	if cfg.PageCacheSize < 0 {
+		return nil
+	}
+	size := cfg.PageCacheSize
+	if size == 0 {
+		size = defaultPageCacheSize
+	}
+	var m *metrics.Metrics
+	if cfg.Client != nil {
+		m = cfg.Client.Metrics()
+	}
+	return lrucache.New[string, []byte](lrucache.Config{
+		Name:     "store_pages",
+		MaxBytes: size,
+		Metrics:  m,
+	})
+}
+
+// cachedPage returns the cached rendered page with the given key (if any) and
+// the current generation of the page cache, which must be passed to cachePage
+// after rendering the page.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) cachedPage(key string) ([]byte, uint64, bool) {
+	if s.pages == nil {
+		return nil, s.pagesGen, false
+	}
+	page, ok := s.pages.Get(key)
+	return page, s.pagesGen, ok
+}
+
+// cachePage adds the page rendered at the given generation of the page cache
+// to the cache. The page is not cached if the cache was purged after it
+// started being rendered, because it may have been rendered from stale data.
+func (s *Store) cachePage(key string, gen uint64, page []byte) {
+	s.mtx.Lock()
+	if s.pages != nil && gen == s.pagesGen {
+		s.pages.Put(key, page, int64(len(page)))
+	}
+	s.mtx.Unlock()
+}
+
+// purgePageCache removes all pages from the page cache. This must be called
+// after every change to the products or their stock.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) purgePageCache() {
+	s.pagesGen += 1
+	if s.pages != nil {
+		s.pages.Purge()
+	}
+}
@@ -0,0 +1,93 @@
+package simplestore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrlnd/lnrpc"
+)
+
+// paymentProofReceived records in the order the payment proof sent by the
+// buyer after paying the LN invoice of the order. The proof is only recorded
+// if it is for the settled invoice of the order.
+func (s *Store) paymentProofReceived(ctx context.Context, proof rpc.PaymentProof) error {
+	buyer, sid, ok := rpc.ParsePaymentProofOrderContext(proof.Context)
+	if !ok {
+		// Not a proof for the payment of an order.
+		return nil
+	}
+	if buyer != proof.Payer {
+		return fmt.Errorf("payment proof of order %s/%s sent by %s",
+			buyer, sid, proof.Payer)
+	}
+	var id OrderID
+	if err := id.FromString(sid); err != nil {
+		return fmt.Errorf("invalid order id %q: %v", sid, err)
+	}
+
+	key := orderKey(buyer, id)
+	var order Order
+	s.mtx.Lock()
+	err := s.backend.Read(key, &order)
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	if order.PayType != PayTypeLN || order.Invoice == "" {
+		return fmt.Errorf("order %s/%s not paid with an LN invoice",
+			buyer, id)
+	}
+
+	// The proof must be for the settled invoice of the order.
+	inv, err := s.lnpc.LNRPC().LookupInvoice(ctx,
+		&lnrpc.PaymentHash{RHash: proof.PaymentHash})
+	if err != nil {
+		return fmt.Errorf("unable to lookup invoice of payment %x: %v",
+			proof.PaymentHash, err)
+	}
+	if inv.PaymentRequest != order.Invoice {
+		return fmt.Errorf("payment %x is not for the invoice of "+
+			"order %s/%s", proof.PaymentHash, buyer, id)
+	}
+	if inv.State != lnrpc.Invoice_SETTLED {
+		return fmt.Errorf("invoice of order %s/%s is not settled",
+			buyer, id)
+	}
+	if uint64(inv.AmtPaidMAtoms) < proof.MilliAtoms {
+		return fmt.Errorf("payment proof of order %s/%s for %d "+
+			"milliatoms when the invoice was paid %d milliatoms",
+			buyer, id, proof.MilliAtoms, inv.AmtPaidMAtoms)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if err := s.backend.Read(key, &order); err != nil {
+		return err
+	}
+	order.PaymentProof = &proof
+	if err := s.writeDoc(key, &order); err != nil {
+		return err
+	}
+	s.log.Infof("Recorded payment proof %x of order %s/%s",
+		proof.PaymentHash, buyer.ShortLogID(), id)
+	return nil
+}
+
+// runPaymentProofWatcher records the payment proofs of orders sent by buyers.
+func (s *Store) runPaymentProofWatcher(ctx context.Context) error {
+	reg := s.c.NotificationManager().Register(client.OnPaymentProofReceivedNtfn(
+		func(ru *client.RemoteUser, proof rpc.PaymentProof) {
+			go func() {
+				err := s.paymentProofReceived(ctx, proof)
+				if err != nil {
+					s.log.Warnf("Unable to record payment "+
+						"proof from %s: %v", ru, err)
+				}
+			}()
+		}))
+	defer reg.Unregister()
+	<-ctx.Done()
+	return ctx.Err()
+}
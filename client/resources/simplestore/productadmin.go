@@ -0,0 +1,703 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/mdembeds"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/pelletier/go-toml"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// productImagesDir is the dir, relative to the store root, where the
+	// images of products uploaded by admins are stored. It is outside the
+	// products dir, so that it is not loaded as a category.
+	productImagesDir = "productimages"
+
+	// maxProductImageSize is the max size of uploaded product images.
+	maxProductImageSize = 1024 * 1024
+)
+
+var (
+	// errStopWalk is returned by the callbacks of walkProductFiles to
+	// stop the walk.
+	errStopWalk = errors.New("stop walk")
+
+	// skuRegexp is the format of the SKUs of products created by admins.
+	// SKUs are used as file names, so they are restricted to safe chars.
+	skuRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+	// categoryNameRegexp is the format of each dir of the category of
+	// products created by admins.
+	categoryNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	// productImageExts are the supported types of product images.
+	productImageExts = map[string]string{
+		"image/png":  ".png",
+		"image/jpeg": ".jpg",
+		"image/gif":  ".gif",
+		"image/webp": ".webp",
+	}
+)
+
+// productFile is a product file of the products tree.
+type productFile struct {
+	fname  string
+	relDir string
+	pf     productsFile
+}
+
+// walkProductFiles calls fn for every product file of the products tree,
+// including the files with only disabled products. If fn returns
+// errStopWalk, the walk is stopped without an error.
+func (s *Store) walkProductFiles(fn func(f *productFile) error) error {
+	prodDir := filepath.Join(s.root, productsDir)
+	err := filepath.WalkDir(prodDir, func(fname string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(fname) != ".toml" || d.Name() == categoryMetaFile {
+			return nil
+		}
+		data, err := os.ReadFile(fname)
+		if err != nil {
+			return err
+		}
+		f := &productFile{fname: fname}
+		if err := toml.Unmarshal(data, &f.pf); err != nil {
+			return fmt.Errorf("unable to decode product file %s: %v",
+				fname, err)
+		}
+		rel, err := filepath.Rel(prodDir, filepath.Dir(fname))
+		if err != nil {
+			return err
+		}
+		if f.relDir = filepath.ToSlash(rel); f.relDir == "." {
+			f.relDir = ""
+		}
+		return fn(f)
+	})
+	if errors.Is(err, errStopWalk) {
+		err = nil
+	}
+	return err
+}
+
+// findProductFile returns the product file that defines the product with the
+// given SKU and the index of the product in the file. It returns a nil file if
+// no product file defines the product.
+func (s *Store) findProductFile(sku string) (*productFile, int, error) {
+	var res *productFile
+	var resIdx int
+	err := s.walkProductFiles(func(f *productFile) error {
+		for i, prod := range f.pf.Products {
+			if prod.SKU == sku {
+				res, resIdx = f, i
+				return errStopWalk
+			}
+		}
+		return nil
+	})
+	return res, resIdx, err
+}
+
+// skuExists returns true if a product file defines a product or variant with
+// the given SKU.
+func (s *Store) skuExists(sku string) (bool, error) {
+	var exists bool
+	err := s.walkProductFiles(func(f *productFile) error {
+		for _, prod := range f.pf.Products {
+			if prod.SKU == sku {
+				exists = true
+				return errStopWalk
+			}
+			for _, v := range prod.Variants {
+				if prod.SKU+v.SKUSuffix == sku {
+					exists = true
+					return errStopWalk
+				}
+			}
+		}
+		return nil
+	})
+	return exists, err
+}
+
+// writeProductFile writes the product file. The file is removed if it no
+// longer has any products.
+func writeProductFile(f *productFile) error {
+	if len(f.pf.Products) == 0 {
+		return os.Remove(f.fname)
+	}
+
+	var b bytes.Buffer
+	enc := toml.NewEncoder(&b).Order(toml.OrderPreserve)
+	if err := enc.Encode(&f.pf); err != nil {
+		return fmt.Errorf("unable to encode product file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.fname), 0o700); err != nil {
+		return err
+	}
+	tmpFname := f.fname + ".tmp"
+	if err := os.WriteFile(tmpFname, b.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFname, f.fname)
+}
+
+// cleanCategoryPath validates and returns the cleaned, slash separated path of
+// a category.
+func cleanCategoryPath(category string) (string, error) {
+	category = strings.Trim(strings.TrimSpace(category), "/")
+	if category == "" {
+		return "", nil
+	}
+	for _, name := range strings.Split(category, "/") {
+		if !categoryNameRegexp.MatchString(name) {
+			return "", fmt.Errorf("invalid category name %q", name)
+		}
+	}
+	return category, nil
+}
+
+// saveProductImage saves the image embedded in embedStr as the image of the
+// product. It returns the path of the image file, relative to the store root.
+func (s *Store) saveProductImage(sku, embedStr string) (string, error) {
+	loc := mdembeds.FindAllStringIndex(embedStr)
+	if len(loc) == 0 {
+		return "", fmt.Errorf("image is not an embedded file")
+	}
+	args := mdembeds.ParseEmbedArgs(embedStr[loc[0][0]:loc[0][1]])
+	ext, ok := productImageExts[args.Typ]
+	switch {
+	case len(args.Data) == 0:
+		return "", fmt.Errorf("embedded image has no data")
+	case !ok:
+		return "", fmt.Errorf("unsupported image type %q", args.Typ)
+	case len(args.Data) > maxProductImageSize:
+		return "", fmt.Errorf("image is larger than %d bytes",
+			maxProductImageSize)
+	}
+
+	dir := filepath.Join(s.root, productImagesDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	s.removeProductImages(sku)
+	fname := sku + ext
+	if err := os.WriteFile(filepath.Join(dir, fname), args.Data, 0o600); err != nil {
+		return "", err
+	}
+	return path.Join(productImagesDir, fname), nil
+}
+
+// removeProductImages removes the images uploaded for the product.
+func (s *Store) removeProductImages(sku string) {
+	for _, ext := range productImageExts {
+		fname := filepath.Join(s.root, productImagesDir, sku+ext)
+		if err := os.Remove(fname); err != nil && !os.IsNotExist(err) {
+			s.log.Warnf("Unable to remove product image %s: %v", fname, err)
+		}
+	}
+}
+
+// productImageEmbed returns the embed of the image of the product, to include
+// in its page, or an empty string if the product has no image.
+func (s *Store) productImageEmbed(prod *Product) string {
+	if prod.Image == "" {
+		return ""
+	}
+	fname := filepath.Join(s.root, filepath.FromSlash(prod.Image))
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		s.log.Warnf("Unable to read image of product %s: %v", prod.SKU, err)
+		return ""
+	}
+	typ := "image/png"
+	ext := strings.ToLower(filepath.Ext(fname))
+	for t, e := range productImageExts {
+		if e == ext || (ext == ".jpeg" && e == ".jpg") {
+			typ = t
+			break
+		}
+	}
+	alt := url.PathEscape(prod.Title)
+	return mdembeds.EmbeddedArgs{Typ: typ, Data: data, Alt: alt}.String()
+}
+
+// untrackStock removes the products from stock tracking.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) untrackStock(skus []string) error {
+	levels := s.stock.clone()
+	var changed bool
+	for _, sku := range skus {
+		if _, ok := levels[sku]; ok {
+			delete(levels, sku)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := s.writeDoc(stockFile, levels); err != nil {
+		return err
+	}
+	s.stock = levels
+	return nil
+}
+
+// catalogReloadDir returns the dir of the products tree to reload after
+// changes to the dir relDir. This is the topmost ancestor of relDir that is not
+// loaded in the catalog (for newly created categories) or relDir itself.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) catalogReloadDir(relDir string) string {
+	res := relDir
+	for dir := relDir; dir != ""; {
+		if _, ok := s.catalogDirs[dir]; ok {
+			break
+		}
+		res = dir
+		if dir = path.Dir(dir); dir == "." {
+			dir = ""
+		}
+	}
+	return res
+}
+
+// productSKUs returns the SKUs of the product and its variants.
+func productSKUs(prod *Product) []string {
+	skus := []string{prod.SKU}
+	for _, v := range prod.Variants {
+		skus = append(skus, prod.SKU+v.SKUSuffix)
+	}
+	return skus
+}
+
+// adminProductResult returns the reply to a successful change to a product.
+func adminProductResult(title, msg, sku string) *rpc.RMFetchResourceReply {
+	w := &bytes.Buffer{}
+	w.WriteString(fmt.Sprintf("# %s\n\n", title))
+	w.WriteString(msg + "\n\n")
+	if sku != "" {
+		w.WriteString(fmt.Sprintf("[Edit Product](/admin/editproduct/%s)\n\n", sku))
+	}
+	w.WriteString("[Back to Products](/admin/products)\n\n")
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}
+}
+
+type adminProductFormContext struct {
+	// New is true when creating a new product.
+	New      bool
+	Product  *Product
+	Category string
+	Tags     string
+	Shipping string
+}
+
+// FormValue escapes a value to be used in a single line form field. Line
+// breaks are escaped as "\n" (and unescaped when the form is saved).
+func (ctx *adminProductFormContext) FormValue(v string) string {
+	v = strings.ReplaceAll(v, `"`, "'")
+	return strings.ReplaceAll(v, "\n", `\n`)
+}
+
+// handleAdminProductForm renders the form to create (/admin/newproduct) or
+// edit (/admin/editproduct/<sku>) a product.
+func (s *Store) handleAdminProductForm(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	tctx := &adminProductFormContext{
+		New:      len(request.Path) < 3,
+		Product:  &Product{},
+		Shipping: "no",
+	}
+	if !tctx.New {
+		f, idx, err := s.findProductFile(request.Path[2])
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			return s.handleNotFound(ctx, uid, request)
+		}
+		tctx.Product = f.pf.Products[idx]
+		tctx.Category = f.relDir
+		tctx.Tags = strings.Join(tctx.Product.Tags, ", ")
+		if tctx.Product.Shipping {
+			tctx.Shipping = "yes"
+		}
+	}
+
+	w := &bytes.Buffer{}
+	err := s.render.Render(w, adminProductFormTmplFile, tctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute product form template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// handleAdminSaveProduct creates (/admin/saveproduct) or updates
+// (/admin/saveproduct/<sku>) a product with the data of the product form.
+func (s *Store) handleAdminSaveProduct(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	badRequest := func(msg string, args ...interface{}) (*rpc.RMFetchResourceReply, error) {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(fmt.Sprintf(msg, args...)),
+		}, nil
+	}
+
+	if s.isCoHost() {
+		return badRequest("products of co-hosted stores are managed by the primary store")
+	}
+
+	formData := struct {
+		SKU         string `json:"sku"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Tags        string `json:"tags"`
+		Price       string `json:"price"`
+		Category    string `json:"category"`
+		Shipping    string `json:"shipping"`
+		DigitalFile string `json:"digital_file"`
+		Image       string `json:"image"`
+		Stock       *int64 `json:"stock"`
+	}{}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return badRequest("request data not valid json")
+	}
+
+	isNew := len(request.Path) < 3
+	upd := &ProductUpdate{
+		SKU:         formData.SKU,
+		Title:       formData.Title,
+		Description: strings.ReplaceAll(formData.Description, `\n`, "\n"),
+		Tags:        strings.Split(formData.Tags, ","),
+		Category:    formData.Category,
+		Shipping:    strings.EqualFold(strings.TrimSpace(formData.Shipping), "yes"),
+		DigitalFile: formData.DigitalFile,
+	}
+	if !isNew {
+		upd.SKU = request.Path[2]
+	}
+	price, err := ParseMoney(formData.Price)
+	if err != nil || price < 0 {
+		return badRequest("invalid price %q", formData.Price)
+	}
+	upd.Price = price
+	if formData.Stock != nil && *formData.Stock >= 0 {
+		upd.Stock = formData.Stock
+	}
+
+	prod, err := s.saveProduct(&uid, isNew, upd, formData.Image)
+	if errors.Is(err, ErrInvalidProduct) {
+		return badRequest("%v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isNew {
+		s.log.Infof("Admin %s created product %s", uid.ShortLogID(), prod.SKU)
+		return adminProductResult("Product Created",
+			fmt.Sprintf("Created product %q", prod.Title), prod.SKU), nil
+	}
+	s.log.Infof("Admin %s updated product %s", uid.ShortLogID(), prod.SKU)
+	return adminProductResult("Product Updated",
+		fmt.Sprintf("Updated product %q", prod.Title), prod.SKU), nil
+}
+
+// saveProduct creates (when isNew is true) or updates a product and reloads
+// it in the catalog. image, if not empty, is either the embed of the new image
+// of the product or "none" to remove its image. Errors due to an invalid
+// product definition wrap ErrInvalidProduct.
+func (s *Store) saveProduct(by *clientintf.UserID, isNew bool, upd *ProductUpdate,
+	image string) (*Product, error) {
+
+	invalid := func(msg string, args ...interface{}) (*Product, error) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProduct,
+			fmt.Sprintf(msg, args...))
+	}
+
+	if s.isCoHost() {
+		return invalid("products of co-hosted stores are managed by the primary store")
+	}
+
+	sku := strings.TrimSpace(upd.SKU)
+	title := strings.TrimSpace(upd.Title)
+	category, err := cleanCategoryPath(upd.Category)
+	if err != nil {
+		return invalid("%v", err)
+	}
+	switch {
+	case !skuRegexp.MatchString(sku):
+		return invalid("invalid SKU %q", sku)
+	case title == "":
+		return invalid("product title is empty")
+	case upd.Price < 0:
+		return invalid("invalid price %v", upd.Price)
+	}
+	var tags []string
+	for _, tag := range upd.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	s.mtx.Lock()
+
+	// Load the existing product (when editing). f is the file the
+	// product is written to.
+	var f, oldFile *productFile
+	prod := &Product{SKU: sku}
+	if isNew {
+		exists, err := s.skuExists(sku)
+		if err != nil {
+			s.mtx.Unlock()
+			return nil, err
+		}
+		if exists {
+			s.mtx.Unlock()
+			return invalid("product with SKU %q already exists", sku)
+		}
+		if upd.Stock != nil && *upd.Stock >= 0 {
+			stock := *upd.Stock
+			prod.Stock = &stock
+		}
+	} else {
+		var idx int
+		f, idx, err = s.findProductFile(sku)
+		if err != nil {
+			s.mtx.Unlock()
+			return nil, err
+		}
+		if f == nil {
+			s.mtx.Unlock()
+			return invalid("product with SKU %q does not exist", sku)
+		}
+		prod = f.pf.Products[idx]
+
+		// Products moved to another category are removed from their
+		// previous file.
+		if f.relDir != category {
+			oldFile = f
+			oldFile.pf.Products = slices.Delete(oldFile.pf.Products, idx, idx+1)
+			f = nil
+		}
+	}
+
+	prod.Title = title
+	prod.Description = upd.Description
+	prod.Tags = tags
+	prod.Price = upd.Price
+	prod.Shipping = upd.Shipping
+	prod.DigitalFile = strings.TrimSpace(upd.DigitalFile)
+	prod.Category = category
+	switch image := strings.TrimSpace(image); {
+	case image == "":
+	case strings.EqualFold(image, "none"):
+		s.removeProductImages(sku)
+		prod.Image = ""
+	default:
+		prod.Image, err = s.saveProductImage(sku, image)
+		if err != nil {
+			s.mtx.Unlock()
+			return invalid("unable to save product image: %v", err)
+		}
+	}
+
+	// New (and moved) products are written to their own file.
+	if f == nil {
+		fname := filepath.Join(s.root, productsDir,
+			filepath.FromSlash(category), sku+".toml")
+		if _, err := os.Stat(fname); err == nil {
+			s.mtx.Unlock()
+			return invalid("product file %s already exists", fname)
+		}
+		f = &productFile{fname: fname, relDir: category}
+		f.pf.Products = []*Product{prod}
+	}
+	if err := writeProductFile(f); err != nil {
+		s.mtx.Unlock()
+		return nil, fmt.Errorf("unable to write product file: %v", err)
+	}
+	dirs := []string{s.catalogReloadDir(f.relDir)}
+	if oldFile != nil {
+		if err := writeProductFile(oldFile); err != nil {
+			s.mtx.Unlock()
+			return nil, fmt.Errorf("unable to write product file: %v", err)
+		}
+		dirs = append(dirs, oldFile.relDir)
+	}
+	if !isNew {
+		s.markLocalInventoryChange(sku)
+	}
+	s.mtx.Unlock()
+
+	if err := s.reloadCatalogDirs(dirs); err != nil {
+		return nil, fmt.Errorf("unable to reload products: %v", err)
+	}
+
+	event := EventProductUpdated
+	if isNew {
+		event = EventProductCreated
+	}
+	s.logEvent(event, by, map[string]string{
+		"sku":   sku,
+		"title": title,
+		"price": upd.Price.String(),
+	})
+	return prod, nil
+}
+
+// handleAdminArchiveProduct archives (/admin/archiveproduct/<sku>) or restores
+// (/admin/unarchiveproduct/<sku>) a product. Archived products are disabled in
+// their product file and not shown in the store.
+func (s *Store) handleAdminArchiveProduct(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if s.isCoHost() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("products of co-hosted stores are managed by the primary store"),
+		}, nil
+	}
+
+	archive := request.Path[1] == "archiveproduct"
+	sku := request.Path[2]
+
+	s.mtx.Lock()
+	f, idx, err := s.findProductFile(sku)
+	if err != nil || f == nil {
+		s.mtx.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return s.handleNotFound(ctx, uid, request)
+	}
+	prod := f.pf.Products[idx]
+	prod.Disabled = archive
+	if err := writeProductFile(f); err != nil {
+		s.mtx.Unlock()
+		return nil, fmt.Errorf("unable to write product file: %v", err)
+	}
+	s.mtx.Unlock()
+
+	if err := s.reloadCatalogDirs([]string{f.relDir}); err != nil {
+		return nil, fmt.Errorf("unable to reload products: %v", err)
+	}
+
+	event := EventProductRestored
+	if archive {
+		event = EventProductArchived
+	}
+	s.logEvent(event, &uid, map[string]string{"sku": sku})
+
+	if archive {
+		s.log.Infof("Admin %s archived product %s", uid.ShortLogID(), sku)
+		return adminProductResult("Product Archived",
+			fmt.Sprintf("Archived product %q", prod.Title), sku), nil
+	}
+	s.log.Infof("Admin %s restored product %s", uid.ShortLogID(), sku)
+	return adminProductResult("Product Restored",
+		fmt.Sprintf("Restored product %q", prod.Title), sku), nil
+}
+
+// handleAdminDeleteProduct deletes the product (/admin/deleteproduct/<sku>)
+// from its product file. The SKU of the product must be sent in the form data
+// to confirm the deletion.
+func (s *Store) handleAdminDeleteProduct(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if s.isCoHost() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("products of co-hosted stores are managed by the primary store"),
+		}, nil
+	}
+
+	sku := request.Path[2]
+	formData := struct {
+		Confirm string `json:"confirm"`
+	}{}
+	if err := json.Unmarshal(request.Data, &formData); err != nil ||
+		strings.TrimSpace(formData.Confirm) != sku {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("type the SKU of the product to confirm its deletion"),
+		}, nil
+	}
+
+	s.mtx.Lock()
+	f, idx, err := s.findProductFile(sku)
+	if err != nil || f == nil {
+		s.mtx.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return s.handleNotFound(ctx, uid, request)
+	}
+	prod := f.pf.Products[idx]
+	f.pf.Products = slices.Delete(f.pf.Products, idx, idx+1)
+	if err := writeProductFile(f); err != nil {
+		s.mtx.Unlock()
+		return nil, fmt.Errorf("unable to write product file: %v", err)
+	}
+	s.removeProductImages(sku)
+	if err := s.untrackStock(productSKUs(prod)); err != nil {
+		s.log.Warnf("Unable to remove stock of deleted product %s: %v", sku, err)
+	}
+	s.mtx.Unlock()
+
+	if err := s.reloadCatalogDirs([]string{f.relDir}); err != nil {
+		return nil, fmt.Errorf("unable to reload products: %v", err)
+	}
+
+	s.log.Infof("Admin %s deleted product %s", uid.ShortLogID(), sku)
+	s.logEvent(EventProductDeleted, &uid, map[string]string{"sku": sku})
+	return adminProductResult("Product Deleted",
+		fmt.Sprintf("Deleted product %q", prod.Title), ""), nil
+}
+
+// archivedProducts returns the products that are disabled in the product
+// files, sorted by SKU.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) archivedProducts() ([]*Product, error) {
+	var res []*Product
+	err := s.walkProductFiles(func(f *productFile) error {
+		for _, prod := range f.pf.Products {
+			if prod.Disabled {
+				res = append(res, prod)
+			}
+		}
+		return nil
+	})
+	sort.Slice(res, func(i, j int) bool { return res[i].SKU < res[j].SKU })
+	return res, err
+}
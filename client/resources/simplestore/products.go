@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
 	"github.com/decred/dcrd/dcrutil/v4"
 )
 
@@ -14,10 +15,192 @@ type Product struct {
 	SKU          string   `json:"sku"`
 	Description  string   `json:"description"`
 	Tags         []string `json:"tags"`
-	Price        float64  `json:"price"`
-	Disabled     bool     `json:"disabled,omitempty"`
-	Shipping     bool     `json:"shipping"`
-	SendFilename string   `json:"send_filename"`
+	Price        Money    `json:"price"`
+	Disabled     bool     `json:"disabled,omitempty" toml:",omitempty"`
+	Shipping     bool     `json:"shipping" toml:",omitempty"`
+	SendFilename string   `json:"send_filename" toml:",omitempty"`
+
+	// DigitalFile is the file delivered to buyers once their order is
+	// paid. Relative paths are relative to the root of the store.
+	// SendFilename is an older name for this field.
+	DigitalFile string `json:"digital_file,omitempty" toml:",omitempty"`
+
+	// Image is the image of the product shown in its page, relative to
+	// the root of the store.
+	Image string `json:"image,omitempty" toml:",omitempty"`
+
+	// Images are additional photos of the product, shown in its page.
+	// These are names of files in the assets dir of the store.
+	Images []string `json:"images,omitempty" toml:",omitempty"`
+
+	// AvailableFrom and AvailableUntil optionally restrict the period
+	// during which the product can be bought.
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
+
+	// Stock is the number of units of the product available for sale. If
+	// nil, the product has unlimited stock. The stock set in the product
+	// file is only the initial stock: after that, the stock level is
+	// tracked by the store.
+	Stock *int64 `json:"stock,omitempty"`
+
+	// Weight is the weight of a unit of the product, used to enforce the
+	// max weight of orders.
+	Weight float64 `json:"weight,omitempty" toml:",omitempty"`
+
+	// Backorder allows the product to be ordered when it is out of stock
+	// (as a pre-order or backorder). The units are taken from the stock
+	// once it is restocked, in the order the orders were placed.
+	// ExpectedDate is the date the product is expected to be in stock.
+	Backorder    bool       `json:"backorder,omitempty" toml:",omitempty"`
+	ExpectedDate *time.Time `json:"expected_date,omitempty" toml:",omitempty"`
+
+	// Variants are the variants (sizes, colors, editions, etc) of the
+	// product. When the product has variants, buyers must select one of
+	// them when adding the product to their cart.
+	Variants []*ProductVariant `json:"variants,omitempty" toml:",omitempty"`
+
+	// Bundle are the products that compose the product when it is a
+	// bundle. Bundles are sold at their own (usually discounted) price and
+	// their stock is the stock of their components: ordering a bundle
+	// takes units of each of its components from the stock.
+	Bundle []BundleItem `json:"bundle,omitempty" toml:",omitempty"`
+
+	// CustomQuote marks the product as custom work priced per order.
+	// Instead of adding the product to their cart, buyers request a quote
+	// describing their requirements and the admin responds with a price,
+	// which buyers may accept to place the order.
+	CustomQuote bool `json:"custom_quote,omitempty" toml:",omitempty"`
+
+	// Subscription, when set, sells the product as a subscription billed
+	// at this interval: paying for an order of the product starts a
+	// subscription that is renewed with a new order at the end of every
+	// period, until it is canceled.
+	Subscription SubscriptionInterval `json:"subscription,omitempty" toml:",omitempty"`
+
+	// PayWhatYouWant lets buyers choose the amount they pay for the
+	// product, with Price being the minimum amount. Orders with these
+	// products are paid by tipping the store, instead of with an invoice.
+	PayWhatYouWant bool `json:"pay_what_you_want,omitempty" toml:",omitempty"`
+
+	// BaseSKU and Variant are set in the products of variants (see
+	// variantProduct) to the SKU of the base product and the name of the
+	// variant.
+	BaseSKU string `json:"base_sku,omitempty" toml:"-"`
+	Variant string `json:"variant,omitempty" toml:"-"`
+
+	// Categories are the paths of additional categories the product is
+	// listed in, besides the category of the dir of its product file.
+	// Categories without a dir are created as needed.
+	Categories []string `json:"categories,omitempty" toml:",omitempty"`
+
+	// Category is the path of the category of the product, filled when
+	// the product is loaded.
+	Category string `json:"category,omitempty" toml:"-"`
+}
+
+// isAvailableAt returns true if the product can be bought at the given time.
+func (prod *Product) isAvailableAt(t time.Time) bool {
+	if prod.AvailableFrom != nil && t.Before(*prod.AvailableFrom) {
+		return false
+	}
+	if prod.AvailableUntil != nil && !t.Before(*prod.AvailableUntil) {
+		return false
+	}
+	return true
+}
+
+// Available returns true if the product can currently be bought, according to
+// its availability dates.
+func (prod *Product) Available() bool {
+	return prod.isAvailableAt(time.Now())
+}
+
+// InStock returns true if qty units of the product are in stock or if the
+// product may be backordered.
+func (prod *Product) InStock(qty uint32) bool {
+	return prod.Stock == nil || *prod.Stock >= int64(qty) || prod.Backorder
+}
+
+// OutOfStock returns true if the product has limited stock and no units are
+// left in stock.
+func (prod *Product) OutOfStock() bool {
+	return !prod.InStock(1)
+}
+
+// ProductVariant is a variant of a product. Each variant is sold as its own
+// product, with its own SKU, price and stock.
+type ProductVariant struct {
+	// Name is the name of the variant shown to buyers (e.g. "Large").
+	Name string `json:"name"`
+
+	// SKUSuffix is appended to the SKU of the product to form the SKU of
+	// the variant.
+	SKUSuffix string `json:"sku_suffix"`
+
+	// PriceDelta is added to the price of the product to form the price of
+	// the variant.
+	PriceDelta Money `json:"price_delta"`
+
+	// Stock is the initial stock of the variant. If nil, the variant has
+	// unlimited stock.
+	Stock *int64 `json:"stock,omitempty"`
+}
+
+// HasVariants returns true if the product has variants.
+func (prod *Product) HasVariants() bool {
+	return len(prod.Variants) > 0
+}
+
+// variantProduct returns the product that is sold for the given variant of
+// the product.
+func (prod *Product) variantProduct(v *ProductVariant) *Product {
+	vp := *prod
+	vp.SKU = prod.SKU + v.SKUSuffix
+	vp.Title = fmt.Sprintf("%s (%s)", prod.Title, v.Name)
+	vp.Price = prod.Price + v.PriceDelta
+	vp.Variants = nil
+	vp.BaseSKU = prod.SKU
+	vp.Variant = v.Name
+	vp.Tags = append([]string(nil), prod.Tags...)
+	if v.Stock != nil {
+		stock := *v.Stock
+		vp.Stock = &stock
+	} else {
+		vp.Stock = nil
+	}
+	return &vp
+}
+
+// buildVariants returns the products of the variants of the given products,
+// indexed by their SKU.
+func buildVariants(products map[string]*Product) (map[string]*Product, error) {
+	variants := make(map[string]*Product)
+	for _, prod := range products {
+		for _, v := range prod.Variants {
+			if v.SKUSuffix == "" {
+				return nil, fmt.Errorf("variant %q of product %s "+
+					"has an empty SKU suffix", v.Name, prod.SKU)
+			}
+			if v.Name == "" {
+				return nil, fmt.Errorf("variant %s of product %s "+
+					"has an empty name", v.SKUSuffix, prod.SKU)
+			}
+			vp := prod.variantProduct(v)
+			if _, ok := products[vp.SKU]; ok {
+				return nil, fmt.Errorf("SKU %s of variant %q of "+
+					"product %s is duplicated", vp.SKU, v.Name,
+					prod.SKU)
+			}
+			if _, ok := variants[vp.SKU]; ok {
+				return nil, fmt.Errorf("SKU %s of variant %q of "+
+					"product %s is duplicated", vp.SKU, v.Name,
+					prod.SKU)
+			}
+			variants[vp.SKU] = vp
+		}
+	}
+	return variants, nil
 }
 
 type productsFile struct {
@@ -32,6 +215,30 @@ type CartItem struct {
 type Cart struct {
 	Items   []*CartItem `json:"items"`
 	Updated time.Time   `json:"updated"`
+
+	// Created is when the first item was added to the (empty) cart.
+	Created time.Time `json:"created,omitempty"`
+
+	// Coupon is the code of the promotion applied to the cart (if any)
+	// and Discount is its discount on the items of the cart.
+	Coupon   string `json:"coupon,omitempty"`
+	Discount Money  `json:"discount,omitempty"`
+
+	// LegacyDiscountCents is the discount of carts saved before amounts
+	// were stored as Money. It is moved to Discount when decoding the
+	// cart.
+	LegacyDiscountCents int64 `json:"discount_cents,omitempty"`
+
+	// Currency is the currency of the prices of the cart.
+	Currency string `json:"currency,omitempty"`
+
+	// Gift is the recipient of the order to be placed with the cart, when
+	// it is placed on behalf of another user.
+	Gift *OrderGift `json:"gift,omitempty"`
+
+	// RemindedTS is when the user was last reminded of the cart after
+	// leaving it idle.
+	RemindedTS *time.Time `json:"reminded_ts,omitempty"`
 }
 
 // HasCharges returns true if at least one item has a positive charge amount.
@@ -45,19 +252,27 @@ func (cart *Cart) HasCharges() bool {
 	return false
 }
 
-// Total returns the total amount, with 2 decimal places accuracy.
-func (cart *Cart) TotalCents() int64 {
-	var totalUSDCents int64
+// Total returns the amount of all units of the item.
+func (item *CartItem) Total() Money {
+	return item.Product.Price.Mul(int(item.Quantity))
+}
+
+// Subtotal returns the amount of the items before the discount.
+func (cart *Cart) Subtotal() Money {
+	var total Money
 	for _, item := range cart.Items {
-		totalItemUSDCents := int64(item.Quantity) * int64(item.Product.Price*100)
-		totalUSDCents += totalItemUSDCents
+		total += item.Total()
 	}
-	return totalUSDCents
+	return total
 }
 
-// Total returns the total cart amount in USD.
-func (cart *Cart) Total() float64 {
-	return float64(cart.TotalCents()) / 100
+// Total returns the total cart amount.
+func (cart *Cart) Total() Money {
+	total := cart.Subtotal() - cart.Discount
+	if total < 0 {
+		total = 0
+	}
+	return total
 }
 
 type OrderID uint32
@@ -78,11 +293,19 @@ func (id *OrderID) FromString(s string) error {
 type OrderStatus string
 
 const (
-	StatusPlaced    OrderStatus = "placed"
-	StatusPaid      OrderStatus = "paid"
-	StatusShipped   OrderStatus = "shipped"
-	StatusCompleted OrderStatus = "completed"
-	StatusCanceled  OrderStatus = "canceled"
+	StatusPlaced      OrderStatus = "placed"
+	StatusConfirmed   OrderStatus = "confirmed"
+	StatusPaid        OrderStatus = "paid"
+	StatusBackordered OrderStatus = "backordered"
+	StatusShipped     OrderStatus = "shipped"
+	StatusCompleted   OrderStatus = "completed"
+	StatusCanceled    OrderStatus = "canceled"
+	StatusExpired     OrderStatus = "expired"
+
+	// StatusQuotePending is the status of orders placed while the
+	// exchange rate was not available, which are quoted once it is (see
+	// RateFailureRetry).
+	StatusQuotePending OrderStatus = "quotepending"
 )
 
 type ShippingAddress struct {
@@ -109,40 +332,152 @@ type Order struct {
 	Status       OrderStatus       `json:"status"`
 	PlacedTS     time.Time         `json:"placed_ts"`
 	ResolvedTS   *time.Time        `json:"resolved_ts"`
-	ShipCharge   float64           `json:"ship_charge"`
+	ShipCharge   Money             `json:"ship_charge"`
+	ShipMethod   string            `json:"ship_method,omitempty"`
 	ExchangeRate float64           `json:"exchange_rate"`
+	Currency     string            `json:"currency,omitempty"`
 	PayType      PayType           `json:"pay_type"`
 	Invoice      string            `json:"invoice"`
-	ShipAddr     *ShippingAddress  `json:"shipping"`
+	ShipAddr     *ShippingAddress  `json:"shipping,omitempty"`
 	Comments     []OrderComment    `json:"comments"`
 	ExpiresTS    time.Time         `json:"expires_ts"`
+
+	StatusHistory []OrderStatusChange `json:"status_history,omitempty"`
+
+	// PaidAmount is the amount received when the payment of the order was
+	// detected. For orders paid with tips, this is the amount tipped so
+	// far.
+	PaidAmount dcrutil.Amount `json:"paid_amount,omitempty"`
+	PaidTS     *time.Time     `json:"paid_ts,omitempty"`
+
+	// PaidTxID is the id of the tx that paid for on-chain orders.
+	PaidTxID string `json:"paid_txid,omitempty"`
+
+	// PaidPreimage is the hex encoded preimage of the settled invoice of
+	// orders paid with LN. It proves the invoice of the order was paid.
+	PaidPreimage string `json:"paid_preimage,omitempty"`
+
+	// PaymentProof is the proof of payment of the LN invoice of the order
+	// sent by the buyer, once verified against the settled invoice.
+	PaymentProof *rpc.PaymentProof `json:"payment_proof,omitempty"`
+
+	// EncShipAddr is the encrypted shipping address of the order. Orders
+	// placed before addresses were encrypted have it in ShipAddr instead.
+	EncShipAddr []byte `json:"enc_shipping,omitempty"`
+
+	// Deliveries records the delivery of the digital files of the items
+	// of the order.
+	Deliveries []FileDelivery `json:"deliveries,omitempty"`
+
+	// Referral is the label of the local invite referral through which the
+	// buyer joined, if any. It is only shown to admins.
+	Referral string `json:"referral,omitempty"`
+
+	// CoHost is set in orders imported from a co-host of the store.
+	CoHost *CoHostOrigin `json:"cohost,omitempty"`
+
+	// Synced is set in orders placed with the local store running as a
+	// co-host, once they are synced to the primary store.
+	Synced *OrderSync `json:"synced,omitempty"`
+
+	// TrackingNumber is the tracking number of the shipment of the
+	// order, set by the admin that marked the order as shipped.
+	TrackingNumber string `json:"tracking_number,omitempty"`
+
+	// Backordered are the units of stock of each SKU still owed to the
+	// order, for items ordered while out of stock.
+	Backordered map[string]int64 `json:"backordered,omitempty"`
+
+	// QuoteID is the ID of the quote request of the user that was
+	// accepted to place the order, if any.
+	QuoteID uint64 `json:"quote_id,omitempty"`
+
+	// SubscriptionID is the ID of the subscription of the user renewed by
+	// the order, if it is a renewal order.
+	SubscriptionID uint64 `json:"subscription_id,omitempty"`
+
+	// Refunds are the refunds of the order recorded with RefundOrder or
+	// RecordRefund.
+	Refunds []OrderRefund `json:"refunds,omitempty"`
+
+	AssignedAdmin *clientintf.UserID `json:"assigned_admin,omitempty"`
+	AssignedTS    *time.Time         `json:"assigned_ts,omitempty"`
+	AckedBy       *clientintf.UserID `json:"acked_by,omitempty"`
+	AckedTS       *time.Time         `json:"acked_ts,omitempty"`
+
+	// NotifiedAdmins are the admins that were already notified that the
+	// order was assigned to them.
+	NotifiedAdmins []clientintf.UserID `json:"notified_admins,omitempty"`
+
+	// Escrow is set in orders paid in escrow (see EscrowConfig).
+	Escrow *OrderEscrow `json:"escrow,omitempty"`
+
+	// Gift is set in orders placed on behalf of another user, who
+	// receives the order instead of User (the payer).
+	Gift *OrderGift `json:"gift,omitempty"`
+
+	// Reminders are the reminders to pay the order sent to the user while
+	// it was unpaid.
+	Reminders []OrderReminder `json:"reminders,omitempty"`
+
+	// Taxes are the taxes charged on the order, calculated when it was
+	// placed.
+	Taxes TaxLines `json:"taxes,omitempty"`
+
+	// Experiments are the variants of the experiments of the store served
+	// to the user when the order was placed, keyed by experiment name.
+	Experiments map[string]string `json:"experiments,omitempty"`
 }
 
-// Total returns the total amount, with 2 decimal places accuracy.
-func (order *Order) TotalCents() int64 {
-	totalUSDCents := order.Cart.TotalCents()
-	if order.ShipCharge > 0 {
-		totalUSDCents += int64(order.ShipCharge * 100)
-	}
-	return totalUSDCents
+// NeedsShipping returns true if the order has a shipping address.
+func (order *Order) NeedsShipping() bool {
+	return order.ShipAddr != nil || len(order.EncShipAddr) > 0
 }
 
-// Total returns the total amount as a float USD.
-func (order *Order) Total() float64 {
-	return float64(order.TotalCents()) / 100
+// Total returns the total amount, in the currency of the order.
+func (order *Order) Total() Money {
+	total := order.Cart.Total()
+	if order.ShipCharge > 0 {
+		total += order.ShipCharge
+	}
+	return total + order.Taxes.Total()
 }
 
 // TotalDCR returns the total order amount in DCR, given the configured exchange
 // rate.
 func (order *Order) TotalDCR() dcrutil.Amount {
-	if order.ExchangeRate == 0 {
-		return 0
-	}
-	totalDCR := order.Total() / order.ExchangeRate
-	amount, _ := dcrutil.NewAmount(totalDCR)
+	amount, _ := order.Total().ToDCR(order.ExchangeRate)
 	return amount
 }
 
+// AwaitingPayment returns true if the order has payment details (LN invoice or
+// on-chain address) that have not yet expired and the order has not yet been
+// paid.
+func (order *Order) AwaitingPayment() bool {
+	return order.Status == StatusPlaced && order.Invoice != "" &&
+		time.Now().Before(order.ExpiresTS)
+}
+
+// PaymentURI returns a URI suitable for encoding as a QR code that can be
+// scanned by wallets to pay for this order. For LN payments, this is a
+// "lightning:" URI with the invoice. For on-chain payments, this is a
+// "decred:" URI with the payment address and amount. Returns an empty string
+// if the order does not have payment details.
+func (order *Order) PaymentURI() string {
+	if order.Invoice == "" {
+		return ""
+	}
+	switch order.PayType {
+	case PayTypeLN:
+		return "lightning:" + order.Invoice
+	case PayTypeOnChain:
+		amount := strconv.FormatFloat(order.TotalDCR().ToCoin(), 'f', -1, 64)
+		return fmt.Sprintf("decred:%s?amount=%s", order.Invoice, amount)
+	default:
+		return ""
+	}
+}
+
 // onChainInvoiceDiscriminator returns the unique(ish) order discriminator for
 // onchain payments.
 func onChainInvoiceDiscriminator(addr string, amount dcrutil.Amount) string {
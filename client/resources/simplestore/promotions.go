@@ -0,0 +1,263 @@
+package simplestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	// promotionsFile is the file, defined by the admin, with the
+	// promotions of the store.
+	promotionsFile = "promotions.json"
+
+	// promotionUsesFile is the file that tracks the number of orders
+	// placed with each promotion.
+	promotionUsesFile = "promotionuses.json"
+)
+
+// PromotionType is the type of discount of a promotion.
+type PromotionType string
+
+const (
+	// PromotionPercent promotions discount a percentage of the price of
+	// the eligible items.
+	PromotionPercent PromotionType = "percent"
+
+	// PromotionFixed promotions discount a fixed amount (in the currency
+	// of the store) from the price of the eligible items.
+	PromotionFixed PromotionType = "fixed"
+)
+
+// Promotion is a discount applied to carts with its coupon code.
+type Promotion struct {
+	// Code is the coupon code of the promotion. Codes are case
+	// insensitive.
+	Code string `json:"code"`
+
+	// Type is the type of discount and Amount is either the percentage
+	// (0-100) or the amount of the discount.
+	Type   PromotionType `json:"type"`
+	Amount float64       `json:"amount"`
+
+	// SKUs are the SKUs of the products the promotion applies to. The SKU
+	// of a product with variants applies to all its variants. If empty,
+	// the promotion applies to the whole cart.
+	SKUs []string `json:"skus,omitempty"`
+
+	// MaxUses is the max number of orders that may be placed with the
+	// promotion. Zero means no limit.
+	MaxUses int `json:"max_uses,omitempty"`
+
+	// Expires is the time after which the promotion can no longer be
+	// used.
+	Expires *time.Time `json:"expires,omitempty"`
+
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// appliesTo returns true if the promotion applies to the product.
+func (promo *Promotion) appliesTo(prod *Product) bool {
+	if len(promo.SKUs) == 0 {
+		return true
+	}
+	for _, sku := range promo.SKUs {
+		if sku == prod.SKU || (prod.BaseSKU != "" && sku == prod.BaseSKU) {
+			return true
+		}
+	}
+	return false
+}
+
+// discount returns the discount of the promotion on the cart.
+func (promo *Promotion) discount(cart *Cart) Money {
+	var eligible Money
+	for _, item := range cart.Items {
+		if promo.appliesTo(item.Product) {
+			eligible += item.Total()
+		}
+	}
+
+	var discount Money
+	switch promo.Type {
+	case PromotionPercent:
+		discount = eligible.Percent(promo.Amount)
+	case PromotionFixed:
+		discount = MoneyFromFloat(promo.Amount)
+	}
+	if discount > eligible {
+		discount = eligible
+	}
+	if discount < 0 {
+		discount = 0
+	}
+	return discount
+}
+
+// promotionUses maps the (normalized) coupon codes of promotions to the
+// number of orders placed with them.
+type promotionUses map[string]int
+
+// normalizeCouponCode returns the normalized form of the coupon code.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// loadPromotions loads the promotions of the store, indexed by their
+// normalized code, and the number of uses of each one.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadPromotions() (map[string]*Promotion, promotionUses, error) {
+	var list []*Promotion
+	err := jsonfile.Read(filepath.Join(s.root, promotionsFile), &list)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, nil, fmt.Errorf("unable to load promotions: %v", err)
+	}
+	promos := make(map[string]*Promotion, len(list))
+	for _, promo := range list {
+		code := normalizeCouponCode(promo.Code)
+		if code == "" {
+			continue
+		}
+		if _, ok := promos[code]; ok {
+			s.log.Warnf("Duplicate promotion with code %q", promo.Code)
+			continue
+		}
+		promos[code] = promo
+	}
+
+	uses := make(promotionUses)
+	err = s.backend.Read(promotionUsesFile, &uses)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, nil, fmt.Errorf("unable to load promotion uses: %v", err)
+	}
+	return promos, uses, nil
+}
+
+// checkPromotion checks whether the promotion with the given code may be
+// applied to the cart. It returns the discount of the promotion on the cart.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) checkPromotion(code string, cart *Cart) (Money, error) {
+	promos, uses, err := s.loadPromotions()
+	if err != nil {
+		return 0, err
+	}
+
+	code = normalizeCouponCode(code)
+	promo, ok := promos[code]
+	switch {
+	case !ok || promo.Disabled:
+		return 0, fmt.Errorf("coupon %q does not exist", code)
+	case promo.Expires != nil && !time.Now().Before(*promo.Expires):
+		return 0, fmt.Errorf("coupon %q has expired", code)
+	case promo.MaxUses > 0 && uses[code] >= promo.MaxUses:
+		return 0, fmt.Errorf("coupon %q is no longer available", code)
+	}
+
+	discount := promo.discount(cart)
+	if discount == 0 {
+		return 0, fmt.Errorf("coupon %q does not apply to any item "+
+			"in the cart", code)
+	}
+	return discount, nil
+}
+
+// updateCartDiscount recalculates the discount of the coupon applied to the
+// cart, after its items changed. The coupon is removed from the cart if it
+// no longer applies to it.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) updateCartDiscount(cart *Cart) {
+	if cart.Coupon == "" {
+		cart.Discount = 0
+		return
+	}
+	discount, err := s.checkPromotion(cart.Coupon, cart)
+	if err != nil {
+		s.log.Debugf("Removing coupon %q from cart: %v", cart.Coupon, err)
+		cart.Coupon = ""
+		discount = 0
+	}
+	cart.Discount = discount
+}
+
+// handleApplyCoupon applies the promotion with the coupon code sent in the
+// form data to the cart of the user. An empty code removes the coupon applied
+// to the cart.
+func (s *Store) handleApplyCoupon(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if request.Data == nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data is empty"),
+		}, nil
+	}
+
+	formData := struct {
+		Code string `json:"code"`
+	}{}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+
+	defer s.backend.LockUser(uid)()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	fname := cartKey(uid)
+	var cart Cart
+	err := s.backend.Read(fname, &cart)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("No items in the cart"),
+		}, nil
+	}
+
+	code := normalizeCouponCode(formData.Code)
+	var msg string
+	if code == "" {
+		cart.Coupon = ""
+		cart.Discount = 0
+		msg = "Removed coupon from the cart"
+	} else {
+		discount, err := s.checkPromotion(code, &cart)
+		if err != nil {
+			return s.renderCart(&cart, fmt.Sprintf("Unable to apply "+
+				"coupon: %v", err))
+		}
+		cart.Coupon = code
+		cart.Discount = discount
+		cart.Currency = s.currency()
+		msg = fmt.Sprintf("Applied coupon %q (-%s)", code,
+			cart.FormatAmount(cart.Discount))
+	}
+	cart.Updated = time.Now()
+
+	if err := s.writeDoc(fname, &cart); err != nil {
+		return nil, err
+	}
+	s.emitCartActivity(uid, &cart)
+	s.logEvent(EventCartCoupon, &uid, map[string]string{
+		"coupon":   code,
+		"discount": cart.Discount.String(),
+	})
+	s.log.Debugf("User %s set cart coupon to %q", uid, code)
+	return s.renderCart(&cart, msg)
+}
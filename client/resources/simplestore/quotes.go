@@ -0,0 +1,554 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	quotesDir = "quotes"
+
+	quoteTmplFile       = "quote.tmpl"
+	quotesTmplFile      = "quotes.tmpl"
+	adminQuoteTmplFile  = "admin_quote.tmpl"
+	adminQuotesTmplFile = "admin_quotes.tmpl"
+
+	// maxQuoteRequirementsLen is the max length of the requirements of a
+	// quote request.
+	maxQuoteRequirementsLen = 4096
+)
+
+var quoteFnamePattern = jsonfile.MakeDecimalFilePattern("quote-", ".json", false)
+
+// QuoteStatus is the status of a quote request.
+type QuoteStatus string
+
+const (
+	// QuoteStatusRequested is the status of quote requests waiting for
+	// the admin to respond with a price.
+	QuoteStatusRequested QuoteStatus = "requested"
+
+	// QuoteStatusOffered is the status of quote requests the admin priced,
+	// waiting for the buyer to accept or decline the offer.
+	QuoteStatusOffered QuoteStatus = "offered"
+
+	// QuoteStatusAccepted is the status of quote requests accepted by the
+	// buyer, which were converted into orders.
+	QuoteStatusAccepted QuoteStatus = "accepted"
+
+	// QuoteStatusDeclined is the status of quote requests declined by the
+	// buyer or the admin.
+	QuoteStatusDeclined QuoteStatus = "declined"
+)
+
+// QuoteRequest is a request by a buyer for a quote of custom work on a
+// product sold by quote.
+type QuoteRequest struct {
+	User         clientintf.UserID `json:"user"`
+	ID           uint64            `json:"id"`
+	SKU          string            `json:"sku"`
+	Title        string            `json:"title"`
+	Requirements string            `json:"requirements"`
+	Status       QuoteStatus       `json:"status"`
+	RequestedTS  time.Time         `json:"requested_ts"`
+
+	// Shipping is true if the product requires shipping, in which case
+	// the shipping address is sent when accepting the offer.
+	Shipping bool `json:"shipping,omitempty"`
+
+	// Price is the custom price offered by the admin, in Currency, and
+	// Note is an optional note from the admin about the offer.
+	Price     Money      `json:"price,omitempty"`
+	Currency  string     `json:"currency,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	OfferedTS *time.Time `json:"offered_ts,omitempty"`
+
+	// OrderID is the ID of the order placed when the offer was accepted.
+	OrderID OrderID `json:"order_id,omitempty"`
+}
+
+// FormatPrice formats the offered price in the currency of the quote.
+func (q *QuoteRequest) FormatPrice() string {
+	return formatAmount(q.Price, q.Currency)
+}
+
+// IsOpen returns true if the quote request may still be priced, accepted or
+// declined.
+func (q *QuoteRequest) IsOpen() bool {
+	return q.Status == QuoteStatusRequested || q.Status == QuoteStatusOffered
+}
+
+func quoteKey(uid clientintf.UserID, id uint64) string {
+	return path.Join(quotesDir, uid.String(), quoteFnamePattern.FilenameFor(id))
+}
+
+func userQuotesPattern(uid clientintf.UserID) string {
+	return path.Join(quotesDir, uid.String(), "*.json")
+}
+
+// allQuotesPattern matches the quote requests of all users.
+var allQuotesPattern = path.Join(quotesDir, "*", "*.json")
+
+// customQuoteReply returns the reply to an attempt to add a product sold by
+// quote to a cart.
+func customQuoteReply(prod *Product) *rpc.RMFetchResourceReply {
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusBadRequest,
+		Data: []byte(fmt.Sprintf("Product %q is sold by quote. Request "+
+			"a quote in its page instead.", prod.Title)),
+	}
+}
+
+// loadQuotes loads the quote requests that match the pattern, sorted by
+// request time.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadQuotes(pattern string) ([]*QuoteRequest, error) {
+	keys, err := s.backend.List(pattern)
+	if err != nil {
+		return nil, err
+	}
+	quotes := make([]*QuoteRequest, 0, len(keys))
+	for _, key := range keys {
+		q := new(QuoteRequest)
+		if err := s.backend.Read(key, q); err != nil {
+			s.log.Warnf("Unable to decode quote request %s: %v", key, err)
+			continue
+		}
+		quotes = append(quotes, q)
+	}
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].RequestedTS.Before(quotes[j].RequestedTS)
+	})
+	return quotes, nil
+}
+
+// loadQuote loads a quote request of the user.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadQuote(uid clientintf.UserID, id uint64) (*QuoteRequest, error) {
+	q := new(QuoteRequest)
+	if err := s.backend.Read(quoteKey(uid, id), q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// quoteFromPath loads the quote request of the user with the ID in the given
+// path element. It returns a reply if the quote does not exist.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) quoteFromPath(uid clientintf.UserID, elem string) (*QuoteRequest, *rpc.RMFetchResourceReply, error) {
+	id, err := strconv.ParseUint(elem, 10, 64)
+	if err != nil {
+		return nil, &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("invalid quote id"),
+		}, nil
+	}
+	q, err := s.loadQuote(uid, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("quote request not found"),
+		}, nil
+	}
+	return q, nil, err
+}
+
+// notifyQuoteAdmins notifies the remote admins of the store and the local
+// client about a quote request.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) notifyQuoteAdmins(q *QuoteRequest, msg string) {
+	for _, admin := range s.cfg.AdminRouting.Admins {
+		admin := admin
+		go func() {
+			if err := s.c.PM(admin, msg); err != nil {
+				s.log.Warnf("Unable to notify admin %s of quote "+
+					"request %s/%d: %v", admin.ShortLogID(),
+					q.User.ShortLogID(), q.ID, err)
+			}
+		}()
+	}
+	if s.cfg.QuoteRequested != nil {
+		s.cfg.QuoteRequested(q, msg)
+	}
+}
+
+func (s *Store) renderQuotePage(tmplFile string, tmplCtx interface{}) (*rpc.RMFetchResourceReply, error) {
+	w := &bytes.Buffer{}
+	if err := s.render.Render(w, tmplFile, tmplCtx); err != nil {
+		return nil, fmt.Errorf("unable to execute quote template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// handleRequestQuote creates a quote request for a product sold by quote, with
+// the requirements sent by the buyer.
+func (s *Store) handleRequestQuote(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var formData struct {
+		SKU          string `json:"sku"`
+		Requirements string `json:"requirements"`
+	}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+	requirements := strings.TrimSpace(formData.Requirements)
+	if requirements == "" {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("Describe your requirements to request a quote"),
+		}, nil
+	}
+	if len(requirements) > maxQuoteRequirementsLen {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("Requirements are limited to %d "+
+				"characters", maxQuoteRequirementsLen)),
+		}, nil
+	}
+
+	defer s.backend.LockUser(uid)()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	prod, ok := s.product(formData.SKU)
+	if !ok || !prod.CustomQuote || !prod.Available() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("SKU %q is not available for "+
+				"quotes", formData.SKU)),
+		}, nil
+	}
+
+	// Quote requests are numbered sequentially for each user.
+	existing, err := s.loadQuotes(userQuotesPattern(uid))
+	if err != nil {
+		return nil, err
+	}
+	var id uint64
+	for _, q := range existing {
+		if q.ID > id {
+			id = q.ID
+		}
+	}
+
+	q := &QuoteRequest{
+		User:         uid,
+		ID:           id + 1,
+		SKU:          prod.SKU,
+		Title:        prod.Title,
+		Requirements: requirements,
+		Status:       QuoteStatusRequested,
+		RequestedTS:  time.Now(),
+		Shipping:     prod.Shipping,
+	}
+	if err := s.writeDoc(quoteKey(uid, q.ID), q); err != nil {
+		return nil, err
+	}
+
+	nick, _ := s.c.UserNick(uid)
+	s.log.Infof("User %s requested quote %d for %s", strescape.Nick(nick),
+		q.ID, q.SKU)
+	msg := fmt.Sprintf("Quote request %s/%d for %q by %s. Respond with a "+
+		"price in the store's admin page /admin/quote/%s/%d",
+		uid.ShortLogID(), q.ID, q.Title, strescape.Nick(nick), uid, q.ID)
+	s.notifyQuoteAdmins(q, msg)
+
+	return s.renderQuotePage(quoteTmplFile, q)
+}
+
+// handleQuotes lists the quote requests of the user.
+func (s *Store) handleQuotes(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	quotes, err := s.loadQuotes(userQuotesPattern(uid))
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return s.renderQuotePage(quotesTmplFile, quotes)
+}
+
+// handleQuote shows a quote request of the user.
+func (s *Store) handleQuote(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	q, reply, err := s.quoteFromPath(uid, request.Path[1])
+	if q == nil {
+		return reply, err
+	}
+	return s.renderQuotePage(quoteTmplFile, q)
+}
+
+// handleAcceptQuote accepts the price offered for a quote request, placing an
+// order for the product at the offered price.
+func (s *Store) handleAcceptQuote(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	defer s.backend.LockUser(uid)()
+	s.mtx.Lock()
+	defer s.unlockAndNotify()
+
+	q, reply, err := s.quoteFromPath(uid, request.Path[1])
+	if q == nil {
+		return reply, err
+	}
+	if q.Status != QuoteStatusOffered {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("Quote request %d cannot be "+
+				"accepted in status %s", q.ID, q.Status)),
+		}, nil
+	}
+	if q.Currency != s.currency() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("The currency of the store changed "+
+				"since quote request %d was priced. Request a new "+
+				"quote.", q.ID)),
+		}, nil
+	}
+	prod, ok := s.product(q.SKU)
+	if !ok {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(fmt.Sprintf("SKU %q does not exist", q.SKU)),
+		}, nil
+	}
+
+	// The order is for a single unit of the product at the offered price.
+	quoted := *prod
+	quoted.Price = q.Price
+	quoted.Title = fmt.Sprintf("%s (quote %d)", prod.Title, q.ID)
+	cart := &Cart{
+		Items:    []*CartItem{{Product: &quoted, Quantity: 1}},
+		Updated:  time.Now(),
+		Currency: q.Currency,
+	}
+	return s.placeOrder(ctx, uid, cart, "", q, request)
+}
+
+// handleDeclineQuote declines a quote request of the user.
+func (s *Store) handleDeclineQuote(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	q, reply, err := s.quoteFromPath(uid, request.Path[1])
+	if q == nil {
+		return reply, err
+	}
+	if !q.IsOpen() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("Quote request %d cannot be "+
+				"declined in status %s", q.ID, q.Status)),
+		}, nil
+	}
+	q.Status = QuoteStatusDeclined
+	if err := s.writeDoc(quoteKey(uid, q.ID), q); err != nil {
+		return nil, err
+	}
+
+	nick, _ := s.c.UserNick(uid)
+	msg := fmt.Sprintf("Quote request %s/%d for %q was declined by %s",
+		uid.ShortLogID(), q.ID, q.Title, strescape.Nick(nick))
+	s.notifyQuoteAdmins(q, msg)
+
+	return s.renderQuotePage(quoteTmplFile, q)
+}
+
+type adminQuoteContext struct {
+	*QuoteRequest
+	UserNick string
+}
+
+// handleAdminQuotes lists the quote requests of all users, the open ones
+// first.
+func (s *Store) handleAdminQuotes(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	quotes, err := s.loadQuotes(allQuotesPattern)
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(quotes, func(i, j int) bool {
+		return quotes[i].IsOpen() && !quotes[j].IsOpen()
+	})
+
+	tmplCtx := make([]adminQuoteContext, len(quotes))
+	for i, q := range quotes {
+		nick, _ := s.c.UserNick(q.User)
+		tmplCtx[i] = adminQuoteContext{QuoteRequest: q, UserNick: strescape.Nick(nick)}
+	}
+	return s.renderQuotePage(adminQuotesTmplFile, tmplCtx)
+}
+
+// adminQuoteFromPath loads the quote request in the
+// /admin/<action>/<uid>/<id> path.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) adminQuoteFromPath(path []string) (*QuoteRequest, *rpc.RMFetchResourceReply, error) {
+	if len(path) < 4 {
+		return nil, nil, fmt.Errorf("path has < 4 elements")
+	}
+	var uid clientintf.UserID
+	if err := uid.FromString(path[2]); err != nil {
+		return nil, nil, err
+	}
+	return s.quoteFromPath(uid, path[3])
+}
+
+// handleAdminQuote shows a quote request, with the form to price it.
+func (s *Store) handleAdminQuote(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	q, reply, err := s.adminQuoteFromPath(request.Path)
+	if q == nil {
+		return reply, err
+	}
+	nick, _ := s.c.UserNick(q.User)
+	tmplCtx := &adminQuoteContext{QuoteRequest: q, UserNick: strescape.Nick(nick)}
+	return s.renderQuotePage(adminQuoteTmplFile, tmplCtx)
+}
+
+// handleAdminOfferQuote sets the price of a quote request and sends the offer
+// to the buyer.
+func (s *Store) handleAdminOfferQuote(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var formData struct {
+		Price string `json:"price"`
+		Note  string `json:"note"`
+	}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+	price, err := ParseMoney(formData.Price)
+	if err != nil || price <= 0 {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(fmt.Sprintf("invalid price %q", formData.Price)),
+		}, nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	q, reply, err := s.adminQuoteFromPath(request.Path)
+	if q == nil {
+		return reply, err
+	}
+	if !q.IsOpen() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("Quote request %d cannot be "+
+				"priced in status %s", q.ID, q.Status)),
+		}, nil
+	}
+
+	now := time.Now()
+	q.Status = QuoteStatusOffered
+	q.Price = price
+	q.Currency = s.currency()
+	q.Note = strings.TrimSpace(formData.Note)
+	q.OfferedTS = &now
+	if err := s.writeDoc(quoteKey(q.User, q.ID), q); err != nil {
+		return nil, err
+	}
+
+	msg := fmt.Sprintf("Your quote request %d for %q was priced at %s. "+
+		"Accept it to place the order at /quote/%d", q.ID, q.Title,
+		q.FormatPrice(), q.ID)
+	if q.Note != "" {
+		msg += "\nNote: " + q.Note
+	}
+	if err := s.c.PM(q.User, msg); err != nil {
+		s.log.Warnf("Unable to send quote %d to user %s: %v", q.ID,
+			q.User.ShortLogID(), err)
+	}
+	s.log.Infof("Offered quote %s/%d at %s", q.User.ShortLogID(), q.ID,
+		q.FormatPrice())
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Quote sent\n\n")
+	w.WriteString(fmt.Sprintf("[Back to Quote](/admin/quote/%s/%d)\n\n", q.User, q.ID))
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// handleAdminDeclineQuote declines a quote request on behalf of the store.
+func (s *Store) handleAdminDeclineQuote(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	q, reply, err := s.adminQuoteFromPath(request.Path)
+	if q == nil {
+		return reply, err
+	}
+	if !q.IsOpen() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("Quote request %d cannot be "+
+				"declined in status %s", q.ID, q.Status)),
+		}, nil
+	}
+	q.Status = QuoteStatusDeclined
+	if err := s.writeDoc(quoteKey(q.User, q.ID), q); err != nil {
+		return nil, err
+	}
+
+	msg := fmt.Sprintf("Your quote request %d for %q was declined", q.ID, q.Title)
+	if err := s.c.PM(q.User, msg); err != nil {
+		s.log.Warnf("Unable to notify user %s of declined quote %d: %v",
+			q.User.ShortLogID(), q.ID, err)
+	}
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Quote declined\n\n")
+	w.WriteString("[Back to Quotes](/admin/quotes)\n\n")
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
@@ -0,0 +1,293 @@
+package simplestore
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+)
+
+// RateFailurePolicy is how the store places orders when the exchange rate
+// provider fails.
+type RateFailurePolicy string
+
+const (
+	// RateFailureNoQuote places orders without a payable amount, so that
+	// the buyer must be contacted with the payment details (the default).
+	RateFailureNoQuote RateFailurePolicy = ""
+
+	// RateFailureCached quotes orders with the last exchange rate, as long
+	// as it is not older than the max staleness. Orders are placed
+	// without a payable amount otherwise.
+	RateFailureCached RateFailurePolicy = "cached"
+
+	// RateFailureRetry places orders in the quote pending status, which
+	// are quoted (and sent their payment details) once the exchange rate
+	// is available again. Orders not quoted before they expire are
+	// expired.
+	RateFailureRetry RateFailurePolicy = "retry"
+
+	// RateFailureReject rejects orders, asking the buyer to try again
+	// later.
+	RateFailureReject RateFailurePolicy = "reject"
+)
+
+const (
+	// pendingQuotesDir tracks the orders in the quote pending status.
+	pendingQuotesDir = "pendingquotes"
+
+	// defaultRateMaxStaleness is the default max age of the last exchange
+	// rate used with RateFailureCached.
+	defaultRateMaxStaleness = time.Hour
+
+	// rateRetryInterval is the interval between attempts to quote the
+	// orders in the quote pending status.
+	rateRetryInterval = time.Minute
+)
+
+// validate returns an error if the policy is unknown.
+func (p RateFailurePolicy) validate() error {
+	switch p {
+	case RateFailureNoQuote, RateFailureCached, RateFailureRetry, RateFailureReject:
+		return nil
+	default:
+		return fmt.Errorf("unknown rate failure policy %q", string(p))
+	}
+}
+
+// cachedRate is the last exchange rate returned by the rate provider.
+type cachedRate struct {
+	mtx  sync.Mutex
+	rate float64
+	ts   time.Time
+}
+
+func (c *cachedRate) set(rate float64) {
+	c.mtx.Lock()
+	c.rate, c.ts = rate, time.Now()
+	c.mtx.Unlock()
+}
+
+func (c *cachedRate) get() (float64, time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.rate, c.ts
+}
+
+// rateMaxStaleness returns the max age of the last exchange rate used to quote
+// orders when the rate provider fails.
+func (s *Store) rateMaxStaleness() time.Duration {
+	if s.cfg.RateMaxStaleness > 0 {
+		return s.cfg.RateMaxStaleness
+	}
+	return defaultRateMaxStaleness
+}
+
+// quoteRate returns the exchange rate used to quote orders. When the rate
+// provider fails, the last rate is returned with RateFailureCached, if it is
+// not stale.
+func (s *Store) quoteRate() (float64, error) {
+	rate, err := s.exchangeRate()
+	if err == nil && rate <= 0 {
+		err = fmt.Errorf("invalid exchange rate %v", rate)
+	}
+	if err == nil {
+		s.lastRate.set(rate)
+		return rate, nil
+	}
+	if s.cfg.RateFailurePolicy != RateFailureCached {
+		return 0, err
+	}
+
+	cached, ts := s.lastRate.get()
+	maxStaleness := s.rateMaxStaleness()
+	if cached <= 0 || time.Since(ts) > maxStaleness {
+		return 0, fmt.Errorf("%v (and no exchange rate fetched in the "+
+			"last %s)", err, maxStaleness)
+	}
+	s.log.Warnf("Using exchange rate %.2f fetched at %s: %v", cached,
+		ts.Format(time.RFC3339), err)
+	return cached, nil
+}
+
+// pendingQuoteKey is the key of the entry that tracks an order in the quote
+// pending status.
+func pendingQuoteKey(uid clientintf.UserID, id OrderID) string {
+	return path.Join(pendingQuotesDir, fmt.Sprintf("%s-%s", uid, id))
+}
+
+// quotePendingOrders quotes the orders in the quote pending status if the
+// exchange rate is available, sending their payment details to the buyers.
+// Pending orders that were not quoted before they expire are expired.
+func (s *Store) quotePendingOrders(ctx context.Context) error {
+	s.mtx.Lock()
+	defer s.unlockAndNotify()
+
+	entries, err := s.backend.List(path.Join(pendingQuotesDir, "*"))
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	rate, rateErr := s.quoteRate()
+	if rateErr != nil {
+		s.log.Debugf("Unable to quote %d pending orders: %v",
+			len(entries), rateErr)
+	}
+
+	nameRegexp := regexp.MustCompile(`([0-9a-fA-F]{64})-([0-9]*)`)
+	for _, entry := range entries {
+		matches := nameRegexp.FindStringSubmatch(path.Base(entry))
+		if len(matches) != 3 {
+			continue
+		}
+		var uid clientintf.UserID
+		if err := uid.FromString(matches[1]); err != nil {
+			continue
+		}
+		var oid OrderID
+		if err := oid.FromString(matches[2]); err != nil {
+			continue
+		}
+		var order Order
+		if err := s.backend.Read(orderKey(uid, oid), &order); err != nil {
+			s.log.Warnf("Unable to load pending order %s/%s: %v",
+				uid.ShortLogID(), oid, err)
+			continue
+		}
+
+		switch {
+		case order.Status != StatusQuotePending:
+			// Canceled by the buyer or an admin.
+		case time.Now().After(order.ExpiresTS):
+			s.expirePendingQuote(&order)
+		case rateErr != nil:
+			continue
+		default:
+			if err := s.quotePendingOrder(ctx, &order, rate); err != nil {
+				s.log.Errorf("Unable to quote pending order "+
+					"%s/%s: %v", uid.ShortLogID(), oid, err)
+				continue
+			}
+		}
+		if err := s.removeDoc(pendingQuoteKey(uid, oid)); err != nil {
+			s.log.Warnf("Unable to remove pending quote %s: %v",
+				entry, err)
+		}
+	}
+	return nil
+}
+
+// expirePendingQuote expires an order that could not be quoted before its
+// expiration.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) expirePendingQuote(order *Order) {
+	order, err := s.updateOrderStatus(order.User, order.ID, StatusExpired, nil)
+	if err != nil {
+		s.log.Warnf("Unable to mark pending order as expired: %v", err)
+		return
+	}
+	s.log.Infof("Expired order %s/%s that could not be quoted",
+		order.User.ShortLogID(), order.ID)
+	s.sendOrderReceipt(order, fmt.Sprintf("Your order %s/%s expired "+
+		"because the store was unable to quote it. You may request a "+
+		"new quote for the order.", order.User.ShortLogID(), order.ID))
+}
+
+// quotePendingOrder quotes an order in the quote pending status with the
+// exchange rate, and sends its payment details to the buyer.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) quotePendingOrder(ctx context.Context, order *Order, rate float64) error {
+	if order.CurrencyCode() != s.currency() {
+		return fmt.Errorf("order placed in %s while the store currency "+
+			"is %s", order.CurrencyCode(), s.currency())
+	}
+
+	userNick, _ := s.c.UserNick(order.User)
+	userNick = strescape.Nick(userNick)
+	quoted := *order
+	quoted.ExchangeRate = rate
+	if quoted.TotalDCR() == 0 {
+		return fmt.Errorf("order has zero total dcr amount")
+	}
+	pt := order.PayType
+	if pt != PayTypeTip {
+		pt, quoted.Invoice = s.newOrderInvoice(ctx, &quoted, pt, userNick)
+		if quoted.Invoice == "" {
+			return fmt.Errorf("unable to generate invoice")
+		}
+	}
+
+	expires := time.Now().Add(s.quoteValidity())
+	order, err := s.updateOrderStatusWith(order.User, order.ID, StatusPlaced, nil,
+		func(o *Order) {
+			o.ExchangeRate = rate
+			o.PayType = pt
+			o.Invoice = quoted.Invoice
+			o.ExpiresTS = expires
+		})
+	if err != nil {
+		return err
+	}
+	if order.Invoice != "" {
+		pendingFname := path.Join(pendingInvoicesDir, fmt.Sprintf("%s-%s",
+			order.User, order.ID))
+		if err := s.writeDoc(pendingFname, ""); err != nil {
+			return err
+		}
+	}
+	if order.AwaitingTip() {
+		if err := s.writeDoc(pendingTipKey(order.User, order.ID), ""); err != nil {
+			return err
+		}
+	}
+
+	s.log.Infof("Quoted pending order %s/%s of user %s at exchange rate %.2f",
+		order.User.ShortLogID(), order.ID, userNick, rate)
+
+	msg := fmt.Sprintf("Using the current exchange rate of %.2f %s/DCR, "+
+		"your order %s/%s is %s, valid until %s\n", rate,
+		order.CurrencyCode(), order.User.ShortLogID(), order.ID,
+		order.TotalDCR(), order.ExpiresTS.Format("Mon, 02 Jan 2006 15:04 MST"))
+	switch order.PayType {
+	case PayTypeTip:
+		msg += tipPaymentMsg(order)
+	case PayTypeOnChain:
+		msg += fmt.Sprintf("On-chain Payment Address: %s\n", order.Invoice)
+	case PayTypeLN:
+		msg += fmt.Sprintf("LN Invoice for payment: lnpay://%s\n", order.Invoice)
+	}
+	if uri := order.PaymentURI(); uri != "" {
+		msg += fmt.Sprintf("Payment URI (for QR codes): %s\n", uri)
+	}
+	s.sendOrderReceipt(order, msg)
+
+	if order.Invoice != "" {
+		s.trackInvoice(order)
+	}
+	return nil
+}
+
+// runPendingQuotes periodically attempts to quote the orders in the quote
+// pending status.
+func (s *Store) runPendingQuotes(ctx context.Context) error {
+	ticker := time.NewTicker(rateRetryInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.quotePendingOrders(ctx); err != nil {
+			s.log.Errorf("Unable to quote pending orders: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
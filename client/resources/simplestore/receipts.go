@@ -0,0 +1,99 @@
+package simplestore
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/internal/mdembeds"
+)
+
+const (
+	// receiptTmplFile is the template of the receipts sent to buyers
+	// after the status of their orders changes.
+	receiptTmplFile = "receipt.tmpl"
+
+	// receiptTmplPrefix is the prefix of the templates of the receipts
+	// sent after the status of an order changes to a specific status
+	// (for example, receipt_shipped.tmpl). These take precedence over
+	// receiptTmplFile.
+	receiptTmplPrefix = "receipt_"
+)
+
+type receiptContext struct {
+	*Order
+
+	// Message is a summary of the change in the status of the order.
+	Message string
+}
+
+// quoteEmbed returns the embed with the validity of the quote of an order that
+// is awaiting payment or expired, which clients use to mark expired quotes and
+// to offer requoting the order. It is empty for other orders.
+func quoteEmbed(order *Order) string {
+	switch order.Status {
+	case StatusPlaced, StatusConfirmed, StatusExpired:
+	default:
+		return ""
+	}
+	if order.ExpiresTS.IsZero() || order.TotalDCR() == 0 {
+		return ""
+	}
+	args := mdembeds.EmbeddedArgs{
+		Alt:       fmt.Sprintf("Quote of order %s", order.ID),
+		ExpiresTS: order.ExpiresTS.Unix(),
+		Refresh:   fmt.Sprintf("/order/%s/requote", order.ID),
+	}
+	return args.String()
+}
+
+// orderReceipt returns the receipt for the current status of the order. msg
+// is a summary of the last change to the order, which is returned as the
+// receipt if no receipt template is defined.
+func (s *Store) orderReceipt(order *Order, msg string) string {
+	tmplFile := receiptTmplPrefix + string(order.Status) + ".tmpl"
+	if !s.render.Has(tmplFile) {
+		tmplFile = receiptTmplFile
+	}
+	if !s.render.Has(tmplFile) {
+		return msg
+	}
+
+	w := &bytes.Buffer{}
+	tctx := &receiptContext{Order: order, Message: msg}
+	if err := s.render.Render(w, tmplFile, tctx); err != nil {
+		s.log.Warnf("Unable to execute receipt template %s: %v",
+			tmplFile, err)
+		return msg
+	}
+	return strings.TrimSpace(w.String())
+}
+
+// sendOrderReceipt sends the receipt for the current status of the order to
+// the buyer via PM, along with the validity of its quote (if any). It returns
+// the sent receipt.
+func (s *Store) sendOrderReceipt(order *Order, msg string) string {
+	receipt := s.orderReceipt(order, msg)
+	if embed := quoteEmbed(order); embed != "" {
+		receipt += "\n\n" + embed
+	}
+	if order.User == s.c.PublicID() {
+		return receipt
+	}
+	if err := s.c.PM(order.User, receipt); err != nil {
+		s.log.Warnf("Unable to send receipt of order %s/%s: %v",
+			order.User.ShortLogID(), order.ID, err)
+	}
+	return receipt
+}
+
+// notifyStatusChanged sends the receipt for the current status of the order
+// to the buyer and notifies the change through the StatusChanged callback.
+func (s *Store) notifyStatusChanged(order *Order) {
+	msg := fmt.Sprintf("Your order %s/%s changed to status %s",
+		order.User.ShortLogID(), order.ID, order.Status)
+	receipt := s.sendOrderReceipt(order, msg)
+	if s.cfg.StatusChanged != nil {
+		s.cfg.StatusChanged(order, receipt)
+	}
+}
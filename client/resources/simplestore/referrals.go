@@ -0,0 +1,89 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// referralStats are the stats of the orders placed by users that joined
+// through a referral.
+type referralStats struct {
+	label  string
+	buyers map[clientintf.UserID]struct{}
+	orders int
+	paid   int
+	amount dcrutil.Amount
+}
+
+func (s *Store) handleAdminReferrals(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	files, err := s.backend.List(allOrdersPattern)
+	if err != nil {
+		s.mtx.Unlock()
+		return nil, err
+	}
+
+	stats := make(map[string]*referralStats)
+	var total int
+	for _, f := range files {
+		var order Order
+		if err := s.backend.Read(f, &order); err != nil {
+			s.log.Warnf("Unable to decode order file %s: %v", f, err)
+			continue
+		}
+		total++
+		if order.Referral == "" {
+			continue
+		}
+		st, ok := stats[order.Referral]
+		if !ok {
+			st = &referralStats{
+				label:  order.Referral,
+				buyers: make(map[clientintf.UserID]struct{}),
+			}
+			stats[order.Referral] = st
+		}
+		st.buyers[order.User] = struct{}{}
+		st.orders++
+		if order.PaidTS != nil {
+			st.paid++
+			st.amount += order.PaidAmount
+		}
+	}
+	s.mtx.Unlock()
+
+	sorted := make([]*referralStats, 0, len(stats))
+	var referred int
+	for _, st := range stats {
+		sorted = append(sorted, st)
+		referred += st.orders
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].label < sorted[j].label
+	})
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Referred Orders\n\n")
+	w.WriteString(fmt.Sprintf("%d of %d orders were placed by users that "+
+		"joined through a referral invite\n\n", referred, total))
+	for _, st := range sorted {
+		w.WriteString(fmt.Sprintf("## %s\n\n", st.label))
+		w.WriteString(fmt.Sprintf("Buyers: %d  \n", len(st.buyers)))
+		w.WriteString(fmt.Sprintf("Orders: %d  \n", st.orders))
+		w.WriteString(fmt.Sprintf("Paid  : %d (%s)\n\n", st.paid, st.amount))
+	}
+	w.WriteString("[Back to Admin](/admin)\n\n")
+
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
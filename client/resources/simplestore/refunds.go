@@ -0,0 +1,341 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// refundPushAttempts is the max number of attempts to send a refund to the
+// buyer through the client.
+const refundPushAttempts = 3
+
+var (
+	// ErrOrderNotCancelable is returned when attempting to cancel an order
+	// that was (fully or partially) paid or that is already final.
+	ErrOrderNotCancelable = errors.New("order cannot be canceled")
+
+	// ErrInvalidRefund is returned when attempting to refund an order that
+	// was not paid or to refund more than the unrefunded paid amount of
+	// an order.
+	ErrInvalidRefund = errors.New("invalid refund")
+)
+
+// CanCancel returns true if the order may be canceled by the buyer: unpaid
+// orders that are quote pending, placed, confirmed or expired.
+func (order *Order) CanCancel() bool {
+	switch order.Status {
+	case StatusQuotePending, StatusPlaced, StatusConfirmed, StatusExpired:
+		return order.PaidTS == nil && order.PaidAmount == 0
+	default:
+		return false
+	}
+}
+
+// Refunded returns the total amount refunded to the buyer.
+func (order *Order) Refunded() dcrutil.Amount {
+	var total dcrutil.Amount
+	for _, refund := range order.Refunds {
+		total += refund.Amount
+	}
+	return total
+}
+
+// Refundable returns the amount paid for the order that was not yet refunded.
+func (order *Order) Refundable() dcrutil.Amount {
+	if order.PaidTS == nil {
+		return 0
+	}
+	if res := order.PaidAmount - order.Refunded(); res > 0 {
+		return res
+	}
+	return 0
+}
+
+// stopTrackingInvoice stops tracking the invoice of the order, such that the
+// order is not marked as paid or expired anymore.
+func (s *Store) stopTrackingInvoice(ctx context.Context, order *Order) error {
+	if order.Invoice == "" {
+		return nil
+	}
+	select {
+	case s.invoiceCanceledChan <- order.invoiceDiscriminator():
+		return nil
+	case <-s.runCtx.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelUnpaidOrder cancels the unpaid order, returning its items to the stock
+// and no longer tracking its invoice. by is the user that canceled the order
+// or nil if it was canceled by the local client.
+func (s *Store) cancelUnpaidOrder(ctx context.Context, uid clientintf.UserID,
+	id OrderID, by *clientintf.UserID) (*Order, error) {
+
+	s.mtx.Lock()
+	var order Order
+	if err := s.backend.Read(orderKey(uid, id), &order); err != nil {
+		s.mtx.Unlock()
+		return nil, err
+	}
+	if !order.CanCancel() {
+		s.mtx.Unlock()
+		return nil, fmt.Errorf("%w: order %s/%s is %s", ErrOrderNotCancelable,
+			uid.ShortLogID(), id, order.Status)
+	}
+	if order.Invoice != "" {
+		s.removePendingInvoice(&order)
+	}
+	canceled, err := s.updateOrderStatus(uid, id, StatusCanceled, by)
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The invoice watcher is notified after releasing the mutex, because
+	// the watcher may be waiting for it during startup.
+	if err := s.stopTrackingInvoice(ctx, &order); err != nil {
+		return nil, err
+	}
+	s.log.Infof("Order %s/%s canceled before being paid", uid.ShortLogID(), id)
+	s.notifyStatusChanged(canceled)
+	return canceled, nil
+}
+
+// CancelOrder cancels an unpaid order (placed, confirmed or expired), returning
+// its items to the stock. The buyer is sent the receipt of the cancellation.
+func (s *Store) CancelOrder(ctx context.Context, uid clientintf.UserID, id OrderID) error {
+	_, err := s.cancelUnpaidOrder(ctx, uid, id, nil)
+	return err
+}
+
+// refundOrder records a refund of the given amount in the order and exports
+// its ledger entry. If push is true, the refund is sent to the buyer as a
+// payment through the client before being recorded. by is the admin that
+// issued the refund or nil if it was issued by the local client.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) refundOrder(uid clientintf.UserID, id OrderID, amount dcrutil.Amount,
+	note string, push bool, by *clientintf.UserID) (*Order, error) {
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: refund amount must be positive", ErrInvalidRefund)
+	}
+
+	// Reload the order, to record the refund in its latest version.
+	key := orderKey(uid, id)
+	order := new(Order)
+	if err := s.backend.Read(key, order); err != nil {
+		return nil, err
+	}
+	if order.PaidTS == nil {
+		return nil, fmt.Errorf("%w: order %s/%s was not paid", ErrInvalidRefund,
+			uid.ShortLogID(), id)
+	}
+	if refundable := order.Refundable(); amount > refundable {
+		return nil, fmt.Errorf("%w: refund of %s is larger than the "+
+			"refundable amount %s", ErrInvalidRefund, amount, refundable)
+	}
+
+	if push {
+		if uid == s.c.PublicID() {
+			return nil, fmt.Errorf("%w: cannot send refund to the local "+
+				"client", ErrInvalidRefund)
+		}
+		err := s.c.TipUser(uid, amount.ToCoin(), refundPushAttempts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to send refund: %v", err)
+		}
+	}
+
+	order.Refunds = append(order.Refunds, OrderRefund{
+		Timestamp: time.Now(),
+		Amount:    amount,
+		Note:      note,
+		By:        by,
+		Pushed:    push,
+	})
+	if err := s.writeDoc(key, order); err != nil {
+		return nil, err
+	}
+	s.logOrderEvent(EventOrderRefund, by, order, map[string]string{
+		"amount": amount.String(),
+		"note":   note,
+		"pushed": strconv.FormatBool(push),
+	})
+	s.log.Infof("Refunded %s of order %s/%s (pushed: %v)", amount,
+		uid.ShortLogID(), id, push)
+
+	cfg := s.cfg.Ledger.withDefaults()
+	if err := s.appendLedgerTx(cfg.refundTx(order, amount, note, time.Now())); err != nil {
+		s.log.Errorf("Unable to export ledger entry of refund of order "+
+			"%s/%s: %v", uid.ShortLogID(), id, err)
+	}
+	return order, nil
+}
+
+// notifyRefund sends the receipt of the last refund of the order to the buyer.
+func (s *Store) notifyRefund(order *Order) {
+	refund := order.Refunds[len(order.Refunds)-1]
+	msg := fmt.Sprintf("A refund of %s was issued for your order %s/%s",
+		refund.Amount, order.User.ShortLogID(), order.ID)
+	if refund.Pushed {
+		msg += " and is being sent to you as a payment"
+	}
+	if refund.Note != "" {
+		msg += fmt.Sprintf(" (%s)", refund.Note)
+	}
+	s.sendOrderReceipt(order, msg)
+}
+
+// RefundOrder records a full or partial refund of a paid order. The amount may
+// not be larger than the paid amount of the order that was not yet refunded.
+// If push is true, the refund is sent to the buyer as a payment (requested
+// with the tip flow of the client). Otherwise, the refund is assumed to have
+// been performed out of band. The buyer is sent the receipt of the refund.
+func (s *Store) RefundOrder(uid clientintf.UserID, id OrderID, amount dcrutil.Amount,
+	note string, push bool) (*Order, error) {
+
+	s.mtx.Lock()
+	order, err := s.refundOrder(uid, id, amount, note, push, nil)
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.notifyRefund(order)
+	return order, nil
+}
+
+// RecordRefund records a refund (performed out of band) of the given amount in
+// the order and exports its ledger entry.
+func (s *Store) RecordRefund(order *Order, amount dcrutil.Amount, note string) error {
+	s.mtx.Lock()
+	saved, err := s.refundOrder(order.User, order.ID, amount, note, false, nil)
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	order.Refunds = saved.Refunds
+	return nil
+}
+
+func (s *Store) handleOrderCancel(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var id OrderID
+	if err := id.FromString(request.Path[1]); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("invalid order id"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	}
+
+	order, err := s.cancelUnpaidOrder(ctx, uid, id, &uid)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("order not found"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	case errors.Is(err, ErrOrderNotCancelable):
+		return &rpc.RMFetchResourceReply{
+			Data:   []byte("only unpaid orders may be canceled"),
+			Status: rpc.ResourceStatusBadRequest,
+		}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Order Canceled\n\n")
+	w.WriteString(fmt.Sprintf("Order %s was canceled. Do not pay its invoice.\n\n",
+		order.ID))
+	w.WriteString(fmt.Sprintf("[Back to Order](/order/%s)\n", order.ID))
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+func (s *Store) handleAdminRefundOrder(ctx context.Context, admin clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if len(request.Path) < 4 {
+		return nil, fmt.Errorf("path has < 4 elements")
+	}
+	var uid clientintf.UserID
+	if err := uid.FromString(request.Path[2]); err != nil {
+		return nil, err
+	}
+	var oid OrderID
+	if err := oid.FromString(request.Path[3]); err != nil {
+		return nil, err
+	}
+
+	badRequest := func(msg string) (*rpc.RMFetchResourceReply, error) {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(msg),
+		}, nil
+	}
+
+	var formData struct {
+		Amount string `json:"amount"`
+		Note   string `json:"note"`
+		Push   string `json:"push"`
+	}
+	if err := json.Unmarshal(request.Data, &formData); err != nil {
+		return badRequest(err.Error())
+	}
+	dcr, err := strconv.ParseFloat(strings.TrimSpace(formData.Amount), 64)
+	if err != nil {
+		return badRequest(fmt.Sprintf("invalid amount %q", formData.Amount))
+	}
+	amount, err := dcrutil.NewAmount(dcr)
+	if err != nil {
+		return badRequest(err.Error())
+	}
+	var push bool
+	switch strings.ToLower(strings.TrimSpace(formData.Push)) {
+	case "", "no", "n", "false":
+	case "yes", "y", "true":
+		push = true
+	default:
+		return badRequest(fmt.Sprintf("invalid push option %q", formData.Push))
+	}
+
+	s.mtx.Lock()
+	order, err := s.refundOrder(uid, oid, amount,
+		strings.TrimSpace(formData.Note), push, &admin)
+	s.mtx.Unlock()
+	if errors.Is(err, ErrInvalidRefund) {
+		return badRequest(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.notifyRefund(order)
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Order Refunded\n\n")
+	w.WriteString(fmt.Sprintf("Refunded %s of the order (%s refunded in total)\n\n",
+		amount, order.Refunded()))
+	w.WriteString(fmt.Sprintf("[Back to Order](%s)\n",
+		path.Join("/admin/order", uid.String(), oid.String())))
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
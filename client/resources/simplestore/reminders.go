@@ -0,0 +1,453 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	// remindersFile tracks the products the admins were warned about.
+	remindersFile = "reminders.json"
+
+	// defaultRemindersInterval is the default interval between checks for
+	// low stock and idle carts.
+	defaultRemindersInterval = 15 * time.Minute
+
+	// defaultCartReminderMsg is the default message sent to users with
+	// idle carts.
+	defaultCartReminderMsg = "You left items in your cart at the store. " +
+		"Visit /cart to complete your order."
+
+	// remindersOptOutMsg is appended to the reminders sent to users.
+	remindersOptOutMsg = "Visit /reminders/off to stop receiving reminders " +
+		"from the store."
+)
+
+// RemindersConfig configures the reminders sent by the store in the
+// background. Each reminder is disabled when its config is zero.
+type RemindersConfig struct {
+	// LowStockThreshold is the stock level below which the admins of the
+	// store are warned about a product. Products are warned about once,
+	// until their stock is raised back to the threshold.
+	LowStockThreshold int64
+
+	// CartIdleTime is how long a cart must be left unchanged before its
+	// user is reminded of it. Users are reminded once for each change of
+	// their carts.
+	CartIdleTime time.Duration
+
+	// CartReminderMsg is the message sent to users with idle carts. If
+	// empty, a default message is sent.
+	CartReminderMsg string
+
+	// OrderReminders are the delays after an order is placed at which
+	// its user is reminded to pay it, while it is unpaid (e.g. 1h and
+	// 12h).
+	OrderReminders []time.Duration
+
+	// CancelUnpaidAfter is how long after the final order reminder unpaid
+	// orders are canceled. Zero disables the cancellation.
+	CancelUnpaidAfter time.Duration
+
+	// Interval is the interval between checks for reminders to send. If
+	// zero, a default interval is used.
+	Interval time.Duration
+}
+
+// enabled returns true if any reminder is enabled.
+func (cfg *RemindersConfig) enabled() bool {
+	return cfg.LowStockThreshold > 0 || cfg.CartIdleTime > 0 ||
+		len(cfg.OrderReminders) > 0
+}
+
+// validate returns an error if the config is invalid.
+func (cfg *RemindersConfig) validate() error {
+	for i, delay := range cfg.OrderReminders {
+		if delay <= 0 {
+			return fmt.Errorf("order reminder delay %s is not positive", delay)
+		}
+		if i > 0 && delay <= cfg.OrderReminders[i-1] {
+			return fmt.Errorf("order reminder delays are not increasing")
+		}
+	}
+	if cfg.CancelUnpaidAfter < 0 {
+		return fmt.Errorf("delay to cancel unpaid orders is negative")
+	}
+	return nil
+}
+
+// OrderReminder is a reminder to pay an order sent to its user.
+type OrderReminder struct {
+	Timestamp time.Time `json:"ts"`
+	Msg       string    `json:"msg"`
+
+	// Canceled is set in the notice of the cancellation of the order after
+	// the final reminder.
+	Canceled bool `json:"canceled,omitempty"`
+}
+
+// remindersState is the state of the reminders saved in remindersFile.
+type remindersState struct {
+	// LowStock are the stock levels of the products the admins were
+	// warned about.
+	LowStock map[string]int64 `json:"low_stock"`
+}
+
+// checkLowStock warns the admins of the products whose stock fell below the
+// low stock threshold since the last check.
+func (s *Store) checkLowStock() error {
+	threshold := s.cfg.Reminders.LowStockThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var state remindersState
+	err := s.backend.Read(remindersFile, &state)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("unable to load reminders state: %v", err)
+	}
+	if state.LowStock == nil {
+		state.LowStock = make(map[string]int64)
+	}
+
+	var low []*Product
+	changed := false
+	lowSKUs := make(map[string]struct{})
+	for _, prod := range s.stockedProducts() {
+		if prod.Stock == nil || *prod.Stock >= threshold {
+			continue
+		}
+		lowSKUs[prod.SKU] = struct{}{}
+		if _, ok := state.LowStock[prod.SKU]; !ok {
+			low = append(low, prod)
+			changed = true
+		}
+		state.LowStock[prod.SKU] = *prod.Stock
+	}
+
+	// Products restocked (or removed) may be warned about again.
+	for sku := range state.LowStock {
+		if _, ok := lowSKUs[sku]; !ok {
+			delete(state.LowStock, sku)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := s.writeDoc(remindersFile, &state); err != nil {
+		return err
+	}
+	if len(low) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Products with stock below %d:\n", threshold)
+	for _, prod := range low {
+		fmt.Fprintf(&b, "  %s (%s): %d in stock\n", prod.Title, prod.SKU,
+			*prod.Stock)
+	}
+	b.WriteString("Restock them in the store's admin page /admin/stock")
+	msg := b.String()
+	s.log.Infof("Warning admins of %d products low in stock", len(low))
+
+	for _, admin := range s.cfg.AdminRouting.Admins {
+		admin := admin
+		go func() {
+			if err := s.c.PM(admin, msg); err != nil {
+				s.log.Warnf("Unable to warn admin %s of low stock: %v",
+					admin.ShortLogID(), err)
+			}
+		}()
+	}
+	if s.cfg.LowStock != nil {
+		s.cfg.LowStock(low, msg)
+	}
+	return nil
+}
+
+// remindIdleCart reminds the user of the cart if it was left unchanged for
+// longer than the cart idle time and they were not reminded of it yet.
+func (s *Store) remindIdleCart(uid clientintf.UserID) error {
+	unlock := s.backend.LockUser(uid)
+	s.mtx.Lock()
+	fname := cartKey(uid)
+	var cart Cart
+	err := s.backend.Read(fname, &cart)
+	if err == nil {
+		idleSince := time.Since(cart.Updated)
+		if len(cart.Items) == 0 || idleSince < s.cfg.Reminders.CartIdleTime ||
+			(cart.RemindedTS != nil && !cart.RemindedTS.Before(cart.Updated)) ||
+			s.remindersOptedOut(uid) {
+			s.mtx.Unlock()
+			unlock()
+			return nil
+		}
+		now := time.Now()
+		cart.RemindedTS = &now
+		err = s.writeDoc(fname, &cart)
+	}
+	s.mtx.Unlock()
+	unlock()
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	msg := s.cfg.Reminders.CartReminderMsg
+	if msg == "" {
+		msg = defaultCartReminderMsg
+	}
+	msg += "\n" + remindersOptOutMsg
+	s.log.Debugf("Reminding user %s of idle cart", uid.ShortLogID())
+	return s.c.PM(uid, msg)
+}
+
+// remindIdleCarts reminds the users of their idle carts.
+func (s *Store) remindIdleCarts(ctx context.Context) error {
+	if s.cfg.Reminders.CartIdleTime <= 0 {
+		return nil
+	}
+
+	carts, err := s.backend.List(path.Join(cartsDir, "*"))
+	if err != nil {
+		return err
+	}
+	for _, key := range carts {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var uid clientintf.UserID
+		if err := uid.FromString(path.Base(key)); err != nil {
+			continue
+		}
+		if uid == s.c.PublicID() {
+			continue
+		}
+		if err := s.remindIdleCart(uid); err != nil {
+			s.log.Warnf("Unable to remind user %s of idle cart: %v",
+				uid.ShortLogID(), err)
+		}
+	}
+	return nil
+}
+
+// remindersOptedOut returns true if the user opted out of the reminders of
+// the store.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) remindersOptedOut(uid clientintf.UserID) bool {
+	rec, err := s.loadCustomerRecord(uid)
+	if err != nil {
+		s.log.Warnf("Unable to load customer record of %s: %v",
+			uid.ShortLogID(), err)
+		return false
+	}
+	return rec.NoReminders
+}
+
+// unpaidOrderDue returns the next reminder (or cancellation) due for the
+// unpaid order. It returns false if none is due.
+func (s *Store) unpaidOrderDue(order *Order, now time.Time) (reminder OrderReminder, due bool) {
+	delays := s.cfg.Reminders.OrderReminders
+	if len(delays) == 0 || order.PaidTS != nil || order.CoHost != nil ||
+		(order.Status != StatusPlaced && order.Status != StatusConfirmed) {
+		return reminder, false
+	}
+	sent := len(order.Reminders)
+
+	// The reminders of users that opted out are skipped, but their
+	// orders are still canceled after the final reminder is due.
+	if sent < len(delays) {
+		if now.Before(order.PlacedTS.Add(delays[sent])) {
+			return reminder, false
+		}
+		return OrderReminder{
+			Timestamp: now,
+			Msg: fmt.Sprintf("Your order %s/%s of %s is awaiting "+
+				"payment. Pay it at /order/%s", order.User.ShortLogID(),
+				order.ID, order.FormatAmount(order.Total()), order.ID),
+		}, true
+	}
+
+	cancelAfter := s.cfg.Reminders.CancelUnpaidAfter
+	cancelTS := order.PlacedTS.Add(delays[len(delays)-1] + cancelAfter)
+	if cancelAfter <= 0 || now.Before(cancelTS) {
+		return reminder, false
+	}
+	return OrderReminder{
+		Timestamp: now,
+		Msg: fmt.Sprintf("Your order %s/%s was canceled because it "+
+			"was not paid", order.User.ShortLogID(), order.ID),
+		Canceled: true,
+	}, true
+}
+
+// remindUnpaidOrder sends the next reminder due for the unpaid order (if any)
+// and records it in the order. Orders still unpaid after the final reminder
+// are canceled.
+func (s *Store) remindUnpaidOrder(ctx context.Context, key string) error {
+	s.mtx.Lock()
+	order := new(Order)
+	if err := s.backend.Read(key, order); err != nil {
+		s.mtx.Unlock()
+		return err
+	}
+	reminder, due := s.unpaidOrderDue(order, time.Now())
+	if !due {
+		s.mtx.Unlock()
+		return nil
+	}
+	uid, id := order.User, order.ID
+	if reminder.Canceled {
+		// The cancellation is recorded after the order is canceled
+		// (which sends its receipt to the user), so that it is retried
+		// if it fails.
+		s.mtx.Unlock()
+		s.log.Infof("Canceling order %s/%s unpaid after the final reminder",
+			uid.ShortLogID(), id)
+		if _, err := s.cancelUnpaidOrder(ctx, uid, id, nil); err != nil {
+			return err
+		}
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		if err := s.backend.Read(key, order); err != nil {
+			return err
+		}
+		order.Reminders = append(order.Reminders, reminder)
+		return s.writeDoc(key, order)
+	}
+
+	optedOut := s.remindersOptedOut(uid)
+	if optedOut {
+		// Record the skipped reminder, so that the next one is
+		// scheduled.
+		reminder.Msg = "Not sent (user opted out of reminders)"
+	}
+	order.Reminders = append(order.Reminders, reminder)
+	err := s.writeDoc(key, order)
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	if optedOut || uid == s.c.PublicID() {
+		return nil
+	}
+	s.log.Debugf("Reminding user %s of unpaid order %s", uid.ShortLogID(), id)
+	return s.c.PM(uid, reminder.Msg+"\n"+remindersOptOutMsg)
+}
+
+// remindUnpaidOrders sends the reminders due for the unpaid orders.
+func (s *Store) remindUnpaidOrders(ctx context.Context) error {
+	if len(s.cfg.Reminders.OrderReminders) == 0 {
+		return nil
+	}
+
+	s.mtx.Lock()
+	keys, err := s.backend.List(allOrdersPattern)
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.remindUnpaidOrder(ctx, key); err != nil {
+			s.log.Warnf("Unable to remind unpaid order %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// handleSetReminders opts the user in (/reminders/on) or out (/reminders/off)
+// of the reminders of the store.
+func (s *Store) handleSetReminders(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	var optOut bool
+	switch request.Path[1] {
+	case "on":
+	case "off":
+		optOut = true
+	default:
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("reminders may only be turned on or off"),
+		}, nil
+	}
+
+	s.mtx.Lock()
+	rec, err := s.loadCustomerRecord(uid)
+	if err == nil && rec.NoReminders != optOut {
+		rec.NoReminders = optOut
+		err = s.writeDoc(customerKey(uid), rec)
+	}
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Reminders\n\n")
+	if optOut {
+		w.WriteString("You will no longer receive reminders of your cart " +
+			"and unpaid orders. Unpaid orders may still be canceled.\n\n")
+		w.WriteString("[Turn reminders back on](/reminders/on)\n")
+	} else {
+		w.WriteString("You will receive reminders of your cart and " +
+			"unpaid orders.\n\n")
+		w.WriteString("[Turn reminders off](/reminders/off)\n")
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// runReminders periodically sends the reminders of the store.
+func (s *Store) runReminders(ctx context.Context) error {
+	interval := s.cfg.Reminders.Interval
+	if interval <= 0 {
+		interval = defaultRemindersInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.checkLowStock(); err != nil {
+			s.log.Errorf("Unable to check low stock: %v", err)
+		}
+		if err := s.remindIdleCarts(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.log.Errorf("Unable to remind idle carts: %v", err)
+		}
+		if err := s.remindUnpaidOrders(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.log.Errorf("Unable to remind unpaid orders: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
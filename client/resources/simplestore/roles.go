@@ -0,0 +1,207 @@
+package simplestore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"golang.org/x/exp/slices"
+)
+
+// AdminRole is the role of an admin of the store. The role determines which
+// pages of the admin section the admin may access.
+type AdminRole string
+
+const (
+	// RoleOwner has full access to the admin section.
+	RoleOwner AdminRole = "owner"
+
+	// RoleCatalogEditor may view and change products and stock levels.
+	RoleCatalogEditor AdminRole = "catalog"
+
+	// RoleOrderManager may view and handle orders, quotes, subscriptions
+	// and customers.
+	RoleOrderManager AdminRole = "orders"
+
+	// RoleAccountant has read-only access to orders, sales, customers and
+	// stock levels.
+	RoleAccountant AdminRole = "accountant"
+
+	// RoleRouting may view orders and acknowledge the orders assigned to
+	// it. This is the role of the admins listed in the admin routing config
+	// without an explicit role.
+	RoleRouting AdminRole = "routing"
+)
+
+// IsValid returns true if the role is one of the known roles.
+func (r AdminRole) IsValid() bool {
+	switch r {
+	case RoleOwner, RoleCatalogEditor, RoleOrderManager, RoleAccountant,
+		RoleRouting:
+		return true
+	}
+	return false
+}
+
+// CanViewSales returns true if the role may view orders, sales and customers.
+func (r AdminRole) CanViewSales() bool {
+	return r == RoleOwner || r == RoleOrderManager || r == RoleAccountant
+}
+
+// CanViewOrders returns true if the role may view orders.
+func (r AdminRole) CanViewOrders() bool {
+	return r.CanViewSales() || r == RoleRouting
+}
+
+// CanViewCatalog returns true if the role may view products and stock levels.
+func (r AdminRole) CanViewCatalog() bool {
+	return r.IsValid() && r != RoleRouting
+}
+
+// CanEditOrders returns true if the role may change orders, quotes,
+// subscriptions and customer notes.
+func (r AdminRole) CanEditOrders() bool {
+	return r == RoleOwner || r == RoleOrderManager
+}
+
+// CanEditCatalog returns true if the role may change products and stock
+// levels.
+func (r AdminRole) CanEditCatalog() bool {
+	return r == RoleOwner || r == RoleCatalogEditor
+}
+
+// ParseAdminRole parses an admin role in the format "<uid>:<role>".
+func ParseAdminRole(s string) (clientintf.UserID, AdminRole, error) {
+	var uid clientintf.UserID
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return uid, "", fmt.Errorf("admin role %q not in the format "+
+			"<uid>:<role>", s)
+	}
+	if err := uid.FromString(parts[0]); err != nil {
+		return uid, "", fmt.Errorf("invalid admin id in role %q: %v", s, err)
+	}
+	role := AdminRole(strings.ToLower(parts[1]))
+	if !role.IsValid() {
+		return uid, "", fmt.Errorf("unknown admin role %q", parts[1])
+	}
+	return uid, role, nil
+}
+
+// adminAccess is the kind of access required by a page of the admin section.
+type adminAccess int
+
+const (
+	accessIndex adminAccess = iota
+	accessViewSales
+	accessViewOrders
+	accessViewCatalog
+	accessEditOrders
+	accessEditCatalog
+	accessAckOrders
+)
+
+// adminPageAccess is the access required by each page of the admin section,
+// keyed by the second element of the path of the page.
+var adminPageAccess = map[string]adminAccess{
+	"orders":             accessViewOrders,
+	"order":              accessViewOrders,
+	"packingslip":        accessViewSales,
+	"packingslips":       accessViewSales,
+	"quotes":             accessViewSales,
+	"quote":              accessViewSales,
+	"subscriptions":      accessViewSales,
+	"exportorders":       accessViewSales,
+	"queryorders":        accessViewSales,
+	"referrals":          accessViewSales,
+	"customers":          accessViewSales,
+	"analytics":          accessViewSales,
+	"experiments":        accessViewSales,
+	"customer":           accessViewSales,
+	"stock":              accessViewCatalog,
+	"products":           accessViewCatalog,
+	"exportproducts":     accessViewCatalog,
+	"orderaddcomment":    accessEditOrders,
+	"orderstatusto":      accessEditOrders,
+	"orderrefund":        accessEditOrders,
+	"orderescrow":        accessEditOrders,
+	"orderack":           accessAckOrders,
+	"offerquote":         accessEditOrders,
+	"declinequote":       accessEditOrders,
+	"cancelsubscription": accessEditOrders,
+	"customernote":       accessEditOrders,
+	"setstock":           accessEditCatalog,
+	"newproduct":         accessEditCatalog,
+	"editproduct":        accessEditCatalog,
+	"saveproduct":        accessEditCatalog,
+	"archiveproduct":     accessEditCatalog,
+	"unarchiveproduct":   accessEditCatalog,
+	"deleteproduct":      accessEditCatalog,
+}
+
+// allows returns true if the role grants the access.
+func (r AdminRole) allows(access adminAccess) bool {
+	switch access {
+	case accessIndex:
+		return r.IsValid()
+	case accessViewCatalog:
+		return r.CanViewCatalog()
+	case accessViewSales:
+		return r.CanViewSales()
+	case accessViewOrders:
+		return r.CanViewOrders()
+	case accessAckOrders:
+		return r.CanEditOrders() || r == RoleRouting
+	case accessEditOrders:
+		return r.CanEditOrders()
+	case accessEditCatalog:
+		return r.CanEditCatalog()
+	}
+	return false
+}
+
+// adminRole returns the role of the user in the admin section of the store.
+// The local client is an owner and the admins listed in the admin routing
+// config without an explicit role have the routing role. Users that are not
+// admins have an empty role.
+func (s *Store) adminRole(uid clientintf.UserID) AdminRole {
+	if s.c != nil && uid == s.c.PublicID() {
+		return RoleOwner
+	}
+	if role, ok := s.cfg.AdminRoles[uid]; ok {
+		return role
+	}
+	if slices.Contains(s.cfg.AdminRouting.Admins, uid) {
+		return RoleRouting
+	}
+	return ""
+}
+
+// isAdmin returns true if the user may access the admin section of the store.
+func (s *Store) isAdmin(uid clientintf.UserID) bool {
+	return s.adminRole(uid).IsValid()
+}
+
+// checkAdminAccess returns a forbidden reply if the user's role does not
+// grant access to the admin page at the given path.
+func (s *Store) checkAdminAccess(uid clientintf.UserID, path []string) *rpc.RMFetchResourceReply {
+	access := accessIndex
+	if len(path) > 1 {
+		var ok bool
+		if access, ok = adminPageAccess[path[1]]; !ok {
+			// Unknown pages are handled as not found.
+			return nil
+		}
+	}
+	role := s.adminRole(uid)
+	if role.allows(access) {
+		return nil
+	}
+	s.log.Warnf("Admin %s with role %q denied access to %s", uid.ShortLogID(),
+		role, strings.Join(path, "/"))
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusForbidden,
+		Data:   []byte(fmt.Sprintf("The %q role does not grant access to this page", role)),
+	}
+}
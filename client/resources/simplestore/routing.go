@@ -0,0 +1,240 @@
+package simplestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"golang.org/x/exp/slices"
+)
+
+// AdminShift is a weekly period during which an admin is on duty to handle
+// orders.
+type AdminShift struct {
+	Admin   clientintf.UserID
+	Weekday time.Weekday
+
+	// Start and End are offsets from the start of the day (in local time)
+	// that delimit the shift.
+	Start time.Duration
+	End   time.Duration
+}
+
+// onDuty returns true if the shift includes the given time.
+func (shift *AdminShift) onDuty(t time.Time) bool {
+	if t.Weekday() != shift.Weekday {
+		return false
+	}
+	y, m, d := t.Date()
+	dayStart := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	offset := t.Sub(dayStart)
+	return offset >= shift.Start && offset < shift.End
+}
+
+// ParseAdminShift parses an admin shift in the format
+// "<uid>:<weekday>:<HH:MM>-<HH:MM>", where weekday is the three letter
+// abbreviation of the day (e.g. "mon").
+func ParseAdminShift(s string) (AdminShift, error) {
+	var shift AdminShift
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return shift, fmt.Errorf("admin shift %q not in the format "+
+			"<uid>:<weekday>:<HH:MM>-<HH:MM>", s)
+	}
+	if err := shift.Admin.FromString(parts[0]); err != nil {
+		return shift, fmt.Errorf("invalid admin id in shift %q: %v", s, err)
+	}
+
+	weekday := -1
+	for i := time.Sunday; i <= time.Saturday; i++ {
+		if strings.EqualFold(i.String()[:3], parts[1]) {
+			weekday = int(i)
+		}
+	}
+	if weekday < 0 {
+		return shift, fmt.Errorf("invalid weekday in shift %q", s)
+	}
+	shift.Weekday = time.Weekday(weekday)
+
+	times := strings.Split(parts[2], "-")
+	if len(times) != 2 {
+		return shift, fmt.Errorf("invalid time range in shift %q", s)
+	}
+	parseOffset := func(v string) (time.Duration, error) {
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(t.Hour())*time.Hour +
+			time.Duration(t.Minute())*time.Minute, nil
+	}
+	var err error
+	if shift.Start, err = parseOffset(times[0]); err != nil {
+		return shift, fmt.Errorf("invalid start time in shift %q: %v", s, err)
+	}
+	if shift.End, err = parseOffset(times[1]); err != nil {
+		return shift, fmt.Errorf("invalid end time in shift %q: %v", s, err)
+	}
+	if shift.End == 0 {
+		shift.End = 24 * time.Hour
+	}
+	if shift.End <= shift.Start {
+		return shift, fmt.Errorf("shift %q ends before it starts", s)
+	}
+	return shift, nil
+}
+
+// AdminRouting configures how the notifications of placed orders are routed
+// to the admins of a store.
+type AdminRouting struct {
+	// Admins is the list of remote users that may administer the store.
+	// Placed orders are assigned to the admin on duty (according to
+	// Shifts) or, if no admin is on duty, to each admin in turn.
+	Admins []clientintf.UserID
+
+	// Shifts is the schedule of the admins.
+	Shifts []AdminShift
+
+	// AckTimeout is how long an assigned admin has to acknowledge an
+	// order, before it is reassigned to the next admin. If zero, orders
+	// are never reassigned.
+	AckTimeout time.Duration
+}
+
+// pickAdmin returns the admin to assign a new order to. If possible, an admin
+// other than exclude is returned.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) pickAdmin(now time.Time, exclude *clientintf.UserID) (clientintf.UserID, bool) {
+	routing := &s.cfg.AdminRouting
+	if len(routing.Admins) == 0 {
+		return clientintf.UserID{}, false
+	}
+
+	var candidates []clientintf.UserID
+	for i := range routing.Shifts {
+		shift := &routing.Shifts[i]
+		if shift.onDuty(now) && !slices.Contains(candidates, shift.Admin) {
+			candidates = append(candidates, shift.Admin)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = routing.Admins
+	}
+	if exclude != nil && len(candidates) > 1 {
+		i := slices.Index(candidates, *exclude)
+		if i > -1 {
+			candidates = slices.Delete(slices.Clone(candidates), i, i+1)
+		}
+	}
+
+	admin := candidates[s.nextAdminIdx%len(candidates)]
+	s.nextAdminIdx += 1
+	return admin, true
+}
+
+// assignOrder assigns the order to an admin and notifies the admin about it.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) assignOrder(order *Order) {
+	now := time.Now()
+	admin, ok := s.pickAdmin(now, order.AssignedAdmin)
+	if !ok {
+		return
+	}
+	order.AssignedAdmin = &admin
+	order.AssignedTS = &now
+
+	// Admins are only notified the first time an order is assigned to
+	// them.
+	if slices.Contains(order.NotifiedAdmins, admin) {
+		s.log.Debugf("Reassigning order %s/%s to already notified "+
+			"admin %s", order.User.ShortLogID(), order.ID,
+			admin.ShortLogID())
+		return
+	}
+	order.NotifiedAdmins = append(order.NotifiedAdmins, admin)
+
+	nick, _ := s.c.UserNick(order.User)
+	msg := fmt.Sprintf("Order %s/%s placed by %s was assigned to you. "+
+		"Acknowledge it in the store's admin page /admin/order/%s/%s",
+		order.User.ShortLogID(), order.ID, strescape.Nick(nick),
+		order.User, order.ID)
+	s.log.Infof("Assigning order %s/%s to admin %s", order.User.ShortLogID(),
+		order.ID, admin.ShortLogID())
+	go func() {
+		if err := s.c.PM(admin, msg); err != nil {
+			s.log.Warnf("Unable to notify admin %s of order %s/%s: %v",
+				admin.ShortLogID(), order.User.ShortLogID(),
+				order.ID, err)
+		}
+	}()
+}
+
+// needsAck returns true if the order was assigned to an admin and it still
+// needs to be acknowledged.
+func (order *Order) needsAck() bool {
+	return order.AssignedAdmin != nil && order.AckedTS == nil &&
+		(order.Status == StatusPlaced || order.Status == StatusConfirmed ||
+			order.Status == StatusPaid)
+}
+
+// reassignUnackedOrders reassigns orders that have not been acknowledged by
+// their assigned admin after the ack timeout.
+func (s *Store) reassignUnackedOrders() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	files, err := s.backend.List(allOrdersPattern)
+	if err != nil {
+		s.log.Warnf("Unable to list orders: %v", err)
+		return
+	}
+
+	timeout := s.cfg.AdminRouting.AckTimeout
+	for _, fname := range files {
+		var order Order
+		if err := s.backend.Read(fname, &order); err != nil {
+			s.log.Warnf("Unable to decode order file %s: %v", fname, err)
+			continue
+		}
+		if !order.needsAck() || time.Since(*order.AssignedTS) < timeout {
+			continue
+		}
+
+		s.log.Warnf("Order %s/%s not acknowledged by admin %s",
+			order.User.ShortLogID(), order.ID,
+			order.AssignedAdmin.ShortLogID())
+		s.assignOrder(&order)
+		if err := s.writeDoc(fname, &order); err != nil {
+			s.log.Warnf("Unable to write order %s: %v", fname, err)
+		}
+	}
+}
+
+// runAdminAckWatcher periodically reassigns orders that were not acknowledged
+// by their assigned admins.
+func (s *Store) runAdminAckWatcher(ctx context.Context) error {
+	timeout := s.cfg.AdminRouting.AckTimeout
+	if timeout <= 0 || len(s.cfg.AdminRouting.Admins) == 0 {
+		return nil
+	}
+
+	interval := time.Minute
+	if timeout < interval {
+		interval = timeout
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.reassignUnackedOrders()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package simplestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+)
+
+// schemaFile is the document with the schema versions of the documents of the
+// store state.
+const schemaFile = "schema.json"
+
+// ErrNewerSchema is returned when decoding documents (or loading a store)
+// saved by a newer version of the store, which may have fields this version
+// would misread or drop when saving the documents again.
+var ErrNewerSchema = errors.New("saved by a newer version of the store")
+
+// The migrations of each kind of document. Migration i migrates a decoded
+// document from version i to version i+1, so the current version of each kind
+// of document is the number of its migrations. Future field additions that
+// change how existing fields are interpreted must append a migration.
+var (
+	cartMigrations = []func(cart *Cart){
+		// 0 -> 1: amounts stored as Money.
+		func(cart *Cart) {
+			if cart.LegacyDiscountCents != 0 {
+				cart.Discount = Money(cart.LegacyDiscountCents)
+				cart.LegacyDiscountCents = 0
+			}
+		},
+	}
+
+	orderMigrations = []func(order *Order){
+		// 0 -> 1: schema version introduced.
+		func(order *Order) {},
+	}
+
+	productMigrations = []func(prod *Product){
+		// 0 -> 1: schema version introduced.
+		func(prod *Product) {},
+	}
+)
+
+// storeSchema are the schema versions of the documents of the store state.
+type storeSchema struct {
+	Carts    int `json:"carts"`
+	Orders   int `json:"orders"`
+	Products int `json:"products"`
+}
+
+// currentSchema returns the schema versions of the documents saved by this
+// version of the store.
+func currentSchema() storeSchema {
+	return storeSchema{
+		Carts:    len(cartMigrations),
+		Orders:   len(orderMigrations),
+		Products: len(productMigrations),
+	}
+}
+
+// checkSchemaVersion returns an error if the version of the document of the
+// given kind is invalid or newer than the current version. Documents may be
+// received from remote users (e.g. co-hosted products and orders), so the
+// version must be checked before being used to select the migrations.
+func checkSchemaVersion(kind string, version, current int) error {
+	if version < 0 {
+		return fmt.Errorf("invalid %s schema version %d", kind, version)
+	}
+	if version > current {
+		return fmt.Errorf("%s schema version %d > supported version %d: %w",
+			kind, version, current, ErrNewerSchema)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the cart with its schema version.
+func (cart Cart) MarshalJSON() ([]byte, error) {
+	type jsonCart Cart
+	return json.Marshal(struct {
+		jsonCart
+		SchemaVersion int `json:"schema_version"`
+	}{jsonCart(cart), len(cartMigrations)})
+}
+
+// UnmarshalJSON decodes the cart, migrating carts saved with older schema
+// versions.
+func (cart *Cart) UnmarshalJSON(b []byte) error {
+	type jsonCart Cart
+	doc := struct {
+		*jsonCart
+		SchemaVersion int `json:"schema_version"`
+	}{jsonCart: (*jsonCart)(cart)}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	if err := checkSchemaVersion("cart", doc.SchemaVersion, len(cartMigrations)); err != nil {
+		return err
+	}
+	for _, migrate := range cartMigrations[doc.SchemaVersion:] {
+		migrate(cart)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the order with its schema version.
+func (order Order) MarshalJSON() ([]byte, error) {
+	type jsonOrder Order
+	return json.Marshal(struct {
+		jsonOrder
+		SchemaVersion int `json:"schema_version"`
+	}{jsonOrder(order), len(orderMigrations)})
+}
+
+// UnmarshalJSON decodes the order, migrating orders saved with older schema
+// versions.
+func (order *Order) UnmarshalJSON(b []byte) error {
+	type jsonOrder Order
+	doc := struct {
+		*jsonOrder
+		SchemaVersion int `json:"schema_version"`
+	}{jsonOrder: (*jsonOrder)(order)}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	if err := checkSchemaVersion("order", doc.SchemaVersion, len(orderMigrations)); err != nil {
+		return err
+	}
+	for _, migrate := range orderMigrations[doc.SchemaVersion:] {
+		migrate(order)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the product with its schema version.
+func (prod Product) MarshalJSON() ([]byte, error) {
+	type jsonProduct Product
+	return json.Marshal(struct {
+		jsonProduct
+		SchemaVersion int `json:"schema_version"`
+	}{jsonProduct(prod), len(productMigrations)})
+}
+
+// UnmarshalJSON decodes the product, migrating products saved with older
+// schema versions.
+func (prod *Product) UnmarshalJSON(b []byte) error {
+	type jsonProduct Product
+	doc := struct {
+		*jsonProduct
+		SchemaVersion int `json:"schema_version"`
+	}{jsonProduct: (*jsonProduct)(prod)}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	if err := checkSchemaVersion("product", doc.SchemaVersion, len(productMigrations)); err != nil {
+		return err
+	}
+	for _, migrate := range productMigrations[doc.SchemaVersion:] {
+		migrate(prod)
+	}
+	return nil
+}
+
+// migrateSchema migrates the carts and orders (and the products in them) saved
+// with older schema versions, by saving them again with the current versions,
+// and records the current versions. It returns an error that wraps
+// ErrNewerSchema if the store state was saved by a newer version of the store,
+// so that this version does not misread or corrupt it.
+func (s *Store) migrateSchema() error {
+	var saved storeSchema
+	err := s.backend.Read(schemaFile, &saved)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	current := currentSchema()
+	for _, v := range []struct {
+		kind           string
+		saved, current int
+	}{
+		{"cart", saved.Carts, current.Carts},
+		{"order", saved.Orders, current.Orders},
+		{"product", saved.Products, current.Products},
+	} {
+		if err := checkSchemaVersion(v.kind, v.saved, v.current); err != nil {
+			return fmt.Errorf("store state: %w", err)
+		}
+	}
+	if saved == current {
+		return nil
+	}
+
+	carts, err := s.backend.List(path.Join(cartsDir, "*"))
+	if err != nil {
+		return err
+	}
+	orders, err := s.backend.List(allOrdersPattern)
+	if err != nil {
+		return err
+	}
+
+	// The documents are migrated when decoded, so decoding and saving them
+	// again saves them with the current versions.
+	b := s.backend.NewBatch()
+	var n int
+	for _, key := range carts {
+		var cart Cart
+		if err := s.backend.Read(key, &cart); err != nil {
+			if errors.Is(err, ErrNewerSchema) {
+				return fmt.Errorf("cart %s: %w", key, err)
+			}
+			s.log.Warnf("Unable to decode cart %s: %v", key, err)
+			continue
+		}
+		b.Write(key, &cart)
+		n += 1
+	}
+	for _, key := range orders {
+		var order Order
+		if err := s.backend.Read(key, &order); err != nil {
+			if errors.Is(err, ErrNewerSchema) {
+				return fmt.Errorf("order %s: %w", key, err)
+			}
+			s.log.Warnf("Unable to decode order %s: %v", key, err)
+			continue
+		}
+		b.Write(key, &order)
+		n += 1
+	}
+	b.Write(schemaFile, &current)
+	if err := b.Commit(); err != nil {
+		return err
+	}
+	s.log.Infof("Migrated %d carts and orders from schema %+v to %+v", n,
+		saved, current)
+	return nil
+}
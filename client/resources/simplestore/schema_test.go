@@ -0,0 +1,66 @@
+package simplestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+)
+
+// TestDecodeSchemaVersions tests that documents with invalid or newer schema
+// versions are rejected when decoded.
+func TestDecodeSchemaVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version int
+		wantErr bool
+		newer   bool
+	}{
+		{name: "unversioned", version: 0},
+		{name: "current", version: 1},
+		{name: "negative", version: -1, wantErr: true},
+		{name: "very negative", version: -1 << 40, wantErr: true},
+		{name: "newer", version: 2, wantErr: true, newer: true},
+	}
+
+	docs := []struct {
+		kind   string
+		decode func([]byte) error
+	}{
+		{"cart", func(b []byte) error { return json.Unmarshal(b, new(Cart)) }},
+		{"order", func(b []byte) error { return json.Unmarshal(b, new(Order)) }},
+		{"product", func(b []byte) error { return json.Unmarshal(b, new(Product)) }},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		for _, doc := range docs {
+			doc := doc
+			t.Run(doc.kind+"/"+tc.name, func(t *testing.T) {
+				b := []byte(fmt.Sprintf(`{"schema_version":%d}`, tc.version))
+				err := doc.decode(b)
+				if !tc.wantErr {
+					assert.NilErr(t, err)
+					return
+				}
+				assert.NonNilErr(t, err)
+				if tc.newer {
+					assert.ErrorIs(t, err, ErrNewerSchema)
+				}
+			})
+		}
+	}
+}
+
+// TestCheckSchemaVersion tests the validation of the schema versions.
+func TestCheckSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	assert.NilErr(t, checkSchemaVersion("cart", 0, 1))
+	assert.NilErr(t, checkSchemaVersion("cart", 1, 1))
+	assert.NonNilErr(t, checkSchemaVersion("cart", -1, 1))
+	assert.ErrorIs(t, checkSchemaVersion("cart", 2, 1), ErrNewerSchema)
+}
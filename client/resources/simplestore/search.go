@@ -0,0 +1,196 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	searchTmplFile = "search.tmpl"
+
+	// minSearchTermLen is the min length of the terms indexed and searched
+	// for.
+	minSearchTermLen = 2
+
+	// maxSearchResults is the max number of products listed in the search
+	// results page.
+	maxSearchResults = 50
+)
+
+// searchTerms splits the text into lowercase terms, ignoring punctuation and
+// terms shorter than minSearchTermLen.
+func searchTerms(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if len(f) >= minSearchTermLen {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// searchIndex is an inverted index of the terms of the products of the store.
+type searchIndex struct {
+	// skus maps each indexed term to the SKUs of the products that have
+	// it.
+	skus map[string]map[string]struct{}
+
+	// terms are the indexed terms, sorted, to look up terms by prefix.
+	terms []string
+}
+
+// buildSearchIndex indexes the title, SKU, description, tags, categories and
+// variant names of the products.
+func buildSearchIndex(products map[string]*Product) *searchIndex {
+	idx := &searchIndex{skus: make(map[string]map[string]struct{})}
+	add := func(sku, text string) {
+		for _, term := range searchTerms(text) {
+			set := idx.skus[term]
+			if set == nil {
+				set = make(map[string]struct{})
+				idx.skus[term] = set
+			}
+			set[sku] = struct{}{}
+		}
+	}
+	for sku, prod := range products {
+		add(sku, prod.Title)
+		add(sku, prod.SKU)
+		add(sku, prod.Description)
+		add(sku, strings.Join(prod.Tags, " "))
+		add(sku, prod.Category)
+		add(sku, strings.Join(prod.Categories, " "))
+		for _, v := range prod.Variants {
+			add(sku, v.Name)
+		}
+	}
+	idx.terms = make([]string, 0, len(idx.skus))
+	for term := range idx.skus {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+	return idx
+}
+
+// matchPrefix returns the SKUs of the products with terms starting with the
+// given prefix.
+func (idx *searchIndex) matchPrefix(prefix string) map[string]struct{} {
+	res := make(map[string]struct{})
+	i := sort.SearchStrings(idx.terms, prefix)
+	for ; i < len(idx.terms) && strings.HasPrefix(idx.terms[i], prefix); i++ {
+		for sku := range idx.skus[idx.terms[i]] {
+			res[sku] = struct{}{}
+		}
+	}
+	return res
+}
+
+// search returns the SKUs of the products that match all terms of the query
+// (as prefixes of their indexed terms), sorted.
+func (idx *searchIndex) search(query string) []string {
+	terms := searchTerms(query)
+	if idx == nil || len(terms) == 0 {
+		return nil
+	}
+
+	matches := idx.matchPrefix(terms[0])
+	for _, term := range terms[1:] {
+		if len(matches) == 0 {
+			break
+		}
+		other := idx.matchPrefix(term)
+		for sku := range matches {
+			if _, ok := other[sku]; !ok {
+				delete(matches, sku)
+			}
+		}
+	}
+
+	res := make([]string, 0, len(matches))
+	for sku := range matches {
+		res = append(res, sku)
+	}
+	sort.Strings(res)
+	return res
+}
+
+type searchContext struct {
+	Query    string
+	Products []*Product
+	More     bool
+}
+
+// searchQuery returns the query of a search request: either the "q" field of
+// the form data, the "q" parameter of a "search?q=<query>" path or the rest of
+// a "/search/<query>" path.
+func searchQuery(request *rpc.RMFetchResource) string {
+	if len(request.Data) > 0 {
+		var formData struct {
+			Q string `json:"q"`
+		}
+		if err := json.Unmarshal(request.Data, &formData); err == nil &&
+			formData.Q != "" {
+			return formData.Q
+		}
+	}
+	if i := strings.IndexByte(request.Path[0], '?'); i > -1 {
+		values, err := url.ParseQuery(request.Path[0][i+1:])
+		if err == nil {
+			return values.Get("q")
+		}
+	}
+	return strings.Join(request.Path[1:], " ")
+}
+
+// isSearchPath returns true if the path is a path of the search page.
+func isSearchPath(path []string) bool {
+	return len(path) > 0 && (path[0] == "search" || strings.HasPrefix(path[0], "search?"))
+}
+
+func (s *Store) handleSearch(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	// Quotes are not searched for and would break the search form.
+	query := strings.TrimSpace(strings.ReplaceAll(searchQuery(request), `"`, ""))
+	tmplCtx := &searchContext{Query: query}
+
+	s.mtx.Lock()
+	for _, sku := range s.search.search(query) {
+		if prod := s.products[sku]; prod != nil && prod.Available() {
+			tmplCtx.Products = append(tmplCtx.Products, prod)
+		}
+	}
+	tmpl := s.templateVariant(uid, searchTmplFile)
+	s.mtx.Unlock()
+
+	sort.SliceStable(tmplCtx.Products, func(i, j int) bool {
+		return tmplCtx.Products[i].Title < tmplCtx.Products[j].Title
+	})
+	if len(tmplCtx.Products) > maxSearchResults {
+		tmplCtx.Products = tmplCtx.Products[:maxSearchResults]
+		tmplCtx.More = true
+	}
+
+	w := &bytes.Buffer{}
+	err := s.render.Render(w, tmpl, tmplCtx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute search template: %v", err)
+	}
+
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
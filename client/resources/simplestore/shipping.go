@@ -0,0 +1,289 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/rpc"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// Shipping addresses are stored encrypted with a key local to the store, so
+// that they are not readable from the order files (e.g. in backups).
+const (
+	shippingKeyFile    = "shipping.key"
+	pendingShippingDir = "shipping"
+)
+
+// pendingShipping is the shipping address submitted by a user during checkout,
+// which is used when the user places the order.
+type pendingShipping struct {
+	EncAddr []byte `json:"enc_addr"`
+
+	// Method is the id of the shipping method selected by the user.
+	Method string `json:"method,omitempty"`
+}
+
+// validate returns an error if any of the required fields of the address is
+// empty.
+func (addr *ShippingAddress) validate() error {
+	var missing []string
+	for _, f := range []struct {
+		name, value string
+	}{
+		{"name", addr.Name},
+		{"address", addr.Address1},
+		{"city", addr.City},
+		{"state", addr.State},
+		{"postal code", addr.PostalCode},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("incomplete shipping address: missing %s",
+			strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// shippingKey returns the key used to encrypt shipping addresses, creating it
+// if needed.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) shippingKey() (*[32]byte, error) {
+	if s.shipKey != nil {
+		return s.shipKey, nil
+	}
+
+	fname := filepath.Join(s.root, shippingKeyFile)
+	key := new([32]byte)
+	data, err := os.ReadFile(fname)
+	switch {
+	case err == nil && len(data) == len(key):
+		copy(key[:], data)
+	case err == nil:
+		return nil, fmt.Errorf("shipping key file %s has wrong size", fname)
+	case errors.Is(err, os.ErrNotExist):
+		if _, err := io.ReadFull(rand.Reader, key[:]); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(fname, key[:], 0o600); err != nil {
+			return nil, fmt.Errorf("unable to write shipping key: %v", err)
+		}
+	default:
+		return nil, err
+	}
+	s.shipKey = key
+	return key, nil
+}
+
+// encryptShipAddr encrypts the shipping address.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) encryptShipAddr(addr *ShippingAddress) ([]byte, error) {
+	key, err := s.shippingKey()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := json.Marshal(addr)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, err
+	}
+	return secretbox.Seal(nonce[:], plain, &nonce, key), nil
+}
+
+// decryptShipAddr decrypts a shipping address encrypted with encryptShipAddr.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) decryptShipAddr(enc []byte) (*ShippingAddress, error) {
+	key, err := s.shippingKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(enc) < 24 {
+		return nil, fmt.Errorf("encrypted shipping address too short")
+	}
+	var nonce [24]byte
+	copy(nonce[:], enc)
+	plain, ok := secretbox.Open(nil, enc[24:], &nonce, key)
+	if !ok {
+		return nil, fmt.Errorf("unable to decrypt shipping address")
+	}
+	var addr ShippingAddress
+	if err := json.Unmarshal(plain, &addr); err != nil {
+		return nil, err
+	}
+	return &addr, nil
+}
+
+// loadOrderShipAddr decrypts the shipping address of the order into its
+// ShipAddr field. Orders loaded this way MUST NOT be written back, otherwise
+// the address would be stored in plain text.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadOrderShipAddr(order *Order) error {
+	if order.ShipAddr != nil || len(order.EncShipAddr) == 0 {
+		return nil
+	}
+	addr, err := s.decryptShipAddr(order.EncShipAddr)
+	if err != nil {
+		return fmt.Errorf("order %s/%s: %v", order.User, order.ID, err)
+	}
+	order.ShipAddr = addr
+	return nil
+}
+
+// pendingShipAddr returns the shipping address submitted by the user during
+// checkout, if any, and the id of the shipping method selected by the user.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) pendingShipAddr(uid clientintf.UserID) (*ShippingAddress, string, error) {
+	fname := path.Join(pendingShippingDir, uid.String())
+	var pending pendingShipping
+	err := s.backend.Read(fname, &pending)
+	if errors.Is(err, ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	addr, err := s.decryptShipAddr(pending.EncAddr)
+	if err != nil {
+		return nil, "", err
+	}
+	return addr, pending.Method, nil
+}
+
+// handleShippingInfo handles the shipping address submitted by the user
+// during checkout. The address is stored (encrypted) until the user places
+// the order.
+func (s *Store) handleShippingInfo(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if request.Data == nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data is empty"),
+		}, nil
+	}
+	var addr ShippingAddress
+	if err := json.Unmarshal(request.Data, &addr); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("request data not valid json"),
+		}, nil
+	}
+	if err := addr.validate(); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte(err.Error()),
+		}, nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var cart Cart
+	err := s.backend.Read(cartKey(uid), &cart)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	enc, err := s.encryptShipAddr(&addr)
+	if err != nil {
+		return nil, err
+	}
+	fname := path.Join(pendingShippingDir, uid.String())
+	if err := s.writeDoc(fname, &pendingShipping{EncAddr: enc}); err != nil {
+		return nil, err
+	}
+
+	return s.checkoutReply(uid, &cart, &addr, "")
+}
+
+// handleShippingMethod handles the selection of the shipping method by the
+// user during checkout.
+func (s *Store) handleShippingMethod(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	methodID := request.Path[1]
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	fname := path.Join(pendingShippingDir, uid.String())
+	var pending pendingShipping
+	err := s.backend.Read(fname, &pending)
+	if errors.Is(err, ErrNotFound) {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("shipping address not provided"),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	addr, err := s.decryptShipAddr(pending.EncAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	var cart Cart
+	err = s.backend.Read(cartKey(uid), &cart)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if opt, ok := s.selectedShipping(&cart, addr, methodID); !ok || opt.ID != methodID {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("shipping method %q not "+
+				"available for this order", methodID)),
+		}, nil
+	}
+	pending.Method = methodID
+	if err := s.writeDoc(fname, &pending); err != nil {
+		return nil, err
+	}
+
+	return s.checkoutReply(uid, &cart, addr, methodID)
+}
+
+// checkoutReply renders the checkout page of the cart shipped to the address
+// with the selected shipping method.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) checkoutReply(uid clientintf.UserID, cart *Cart, addr *ShippingAddress,
+	method string) (*rpc.RMFetchResourceReply, error) {
+
+	cart.Currency = s.currency()
+	tmplCtx := &checkoutContext{
+		Cart:            cart,
+		ShipAddr:        addr,
+		ShippingOptions: s.shippingOptions(cart, addr, method),
+	}
+	w := &bytes.Buffer{}
+	if err := s.render.Render(w, s.templateVariant(uid, checkoutTmplFile), tmplCtx); err != nil {
+		return nil, fmt.Errorf("unable to execute checkout template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
@@ -0,0 +1,235 @@
+package simplestore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// shippingMethodsFile is the name of the file, in the store root, with
+	// the shipping methods of the store.
+	shippingMethodsFile = "shipping.toml"
+
+	// flatShippingMethodID is the id of the shipping method used when no
+	// shipping methods are defined, which charges the flat ShipCharge of
+	// the config.
+	flatShippingMethodID = "standard"
+)
+
+// ShippingTier is a price of a shipping method for orders up to a max weight.
+type ShippingTier struct {
+	// MaxWeight is the max total weight of the items of the order, in the
+	// unit of the weight of the products.
+	MaxWeight float64
+
+	Price Money
+}
+
+// ShippingMethod is a shipping method buyers may select during checkout.
+type ShippingMethod struct {
+	// ID identifies the method in the checkout links. It must be unique
+	// and may not contain slashes.
+	ID string
+
+	// Name is the name of the method shown to buyers.
+	Name string
+
+	// Price is the price of the method when it has no tiers.
+	Price Money
+
+	// Regions, if not empty, are the only regions the method ships to.
+	// Regions are either country codes ("US") or country codes followed
+	// by a state ("US-CA").
+	Regions []string
+
+	// Tiers, if not empty, are the prices of the method by the total
+	// weight of the order. The price of the tier with the lowest max
+	// weight that is not less than the weight of the order is charged.
+	// Orders heavier than the max weight of all tiers cannot be shipped
+	// with the method.
+	Tiers []ShippingTier
+
+	// FreeOver, if set, is the total of the items of the order (after
+	// discounts) from which shipping with the method is free.
+	FreeOver Money
+}
+
+// quote returns the price of shipping an order with the total weight and items
+// total to the address. Returns false if the method does not ship the order
+// to the address.
+func (m *ShippingMethod) quote(weight float64, itemsTotal Money, addr *ShippingAddress) (Money, bool) {
+	if len(m.Regions) > 0 && (addr == nil || !addr.matchesRegion(m.Regions)) {
+		return 0, false
+	}
+
+	price := m.Price
+	if len(m.Tiers) > 0 {
+		i := sort.Search(len(m.Tiers), func(i int) bool {
+			return m.Tiers[i].MaxWeight >= weight
+		})
+		if i == len(m.Tiers) {
+			return 0, false
+		}
+		price = m.Tiers[i].Price
+	}
+	if m.FreeOver > 0 && itemsTotal >= m.FreeOver {
+		price = 0
+	}
+	return price, true
+}
+
+// validateShippingMethods returns an error if the shipping methods are
+// invalid. The tiers of the methods are sorted by max weight.
+func validateShippingMethods(methods []ShippingMethod) error {
+	ids := make(map[string]struct{}, len(methods))
+	for i := range methods {
+		m := &methods[i]
+		switch {
+		case m.ID == "" || strings.ContainsAny(m.ID, "/ "):
+			return fmt.Errorf("shipping method %q has an invalid id", m.ID)
+		case m.Name == "":
+			return fmt.Errorf("shipping method %q has no name", m.ID)
+		case m.Price < 0 || m.FreeOver < 0:
+			return fmt.Errorf("shipping method %q: price and free "+
+				"threshold cannot be negative", m.ID)
+		}
+		if _, ok := ids[m.ID]; ok {
+			return fmt.Errorf("duplicate shipping method %q", m.ID)
+		}
+		ids[m.ID] = struct{}{}
+
+		for _, tier := range m.Tiers {
+			if tier.MaxWeight <= 0 || tier.Price < 0 {
+				return fmt.Errorf("shipping method %q: tiers need "+
+					"a positive max weight and a non-negative "+
+					"price", m.ID)
+			}
+		}
+		sort.Slice(m.Tiers, func(i, j int) bool {
+			return m.Tiers[i].MaxWeight < m.Tiers[j].MaxWeight
+		})
+	}
+	return nil
+}
+
+// loadShippingMethods loads the shipping methods of the file. A missing file
+// means no methods.
+func loadShippingMethods(fname string) ([]ShippingMethod, error) {
+	data, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Methods []ShippingMethod
+	}
+	if err := toml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unable to decode shipping methods %s: %v", fname, err)
+	}
+	if err := validateShippingMethods(file.Methods); err != nil {
+		return nil, fmt.Errorf("shipping methods %s: %v", fname, err)
+	}
+	return file.Methods, nil
+}
+
+// ShippingOption is a shipping method available for an order during checkout.
+type ShippingOption struct {
+	ID       string
+	Name     string
+	Price    Money
+	Selected bool
+}
+
+// shippingMethods returns the shipping methods of the store. The methods of
+// the shipping methods file take precedence over the ones in the config. When
+// neither defines any methods, a single method charges the flat ShipCharge of
+// the config.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) shippingMethods() []ShippingMethod {
+	switch {
+	case len(s.shipMethods) > 0:
+		return s.shipMethods
+	case len(s.cfg.ShippingMethods) > 0:
+		return s.cfg.ShippingMethods
+	default:
+		return []ShippingMethod{{
+			ID:    flatShippingMethodID,
+			Name:  "Standard",
+			Price: MoneyFromFloat(s.cfg.ShipCharge),
+		}}
+	}
+}
+
+// hasShippingMethods returns true if the store defines shipping methods
+// (instead of charging the flat ShipCharge of the config).
+//
+// This MUST be called with the store mutex held.
+func (s *Store) hasShippingMethods() bool {
+	return len(s.shipMethods) > 0 || len(s.cfg.ShippingMethods) > 0
+}
+
+// cartWeight returns the total weight of the items of the cart.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) cartWeight(cart *Cart) float64 {
+	var weight float64
+	for _, item := range cart.Items {
+		prod, ok := s.product(item.Product.SKU)
+		if !ok {
+			prod = item.Product
+		}
+		weight += prod.Weight * float64(item.Quantity)
+	}
+	return weight
+}
+
+// shippingOptions returns the shipping methods that ship the items of the cart
+// to the address, marking the selected one. The first available method is
+// selected if selected is not available.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) shippingOptions(cart *Cart, addr *ShippingAddress, selected string) []ShippingOption {
+	weight := s.cartWeight(cart)
+	total := cart.Total()
+	var res []ShippingOption
+	selIdx := -1
+	for _, m := range s.shippingMethods() {
+		price, ok := m.quote(weight, total, addr)
+		if !ok {
+			continue
+		}
+		if m.ID == selected {
+			selIdx = len(res)
+		}
+		res = append(res, ShippingOption{ID: m.ID, Name: m.Name, Price: price})
+	}
+	if selIdx == -1 && len(res) > 0 {
+		selIdx = 0
+	}
+	if selIdx > -1 {
+		res[selIdx].Selected = true
+	}
+	return res
+}
+
+// selectedShipping returns the selected (or default) shipping option for
+// shipping the items of the cart to the address. Returns false if no
+// shipping method ships the order to the address.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) selectedShipping(cart *Cart, addr *ShippingAddress, selected string) (ShippingOption, bool) {
+	for _, opt := range s.shippingOptions(cart, addr, selected) {
+		if opt.Selected {
+			return opt, true
+		}
+	}
+	return ShippingOption{}, false
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -14,8 +15,8 @@ import (
 	"text/template"
 	"time"
 
-	"github.com/companyzero/bisonrelay/client"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/internal/lrucache"
 	"github.com/companyzero/bisonrelay/client/resources"
 	"github.com/companyzero/bisonrelay/internal/jsonfile"
 	"github.com/companyzero/bisonrelay/internal/strescape"
@@ -27,7 +28,6 @@ import (
 	"github.com/decred/dcrlnd/lnrpc"
 	"github.com/decred/slog"
 	"github.com/fsnotify/fsnotify"
-	"github.com/pelletier/go-toml"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -36,15 +36,25 @@ const (
 	cartsDir            = "carts"
 	ordersDir           = "orders"
 	pendingInvoicesDir  = "pendinginvoices"
+	journalDir          = "journal"
 	indexTmplFile       = "index.tmpl"
 	prodTmplFile        = "product.tmpl"
+	categoryTmplFile    = "category.tmpl"
 	addToCartTmplFile   = "addtocart.tmpl"
 	cartTmplFile        = "cart.tmpl"
+	checkoutTmplFile    = "checkout.tmpl"
 	orderTmplFile       = "order.tmpl"
 	ordersTmplFile      = "orders.tmpl"
 	orderPlacedTmplFile = "orderplaced.tmpl"
 	adminOrdersTmplFile = "admin_orders.tmpl"
 	adminOrderTmplFile  = "admin_order.tmpl"
+	packingSlipTmplFile = "packingslip.tmpl"
+
+	adminIndexTmplFile       = "admin_index.tmpl"
+	adminProductsTmplFile    = "admin_products.tmpl"
+	adminCustomersTmplFile   = "admin_customers.tmpl"
+	adminProductFormTmplFile = "admin_productform.tmpl"
+	adminAnalyticsTmplFile   = "admin_analytics.tmpl"
 )
 
 type PayType string
@@ -52,6 +62,10 @@ type PayType string
 const (
 	PayTypeOnChain PayType = "onchain"
 	PayTypeLN      PayType = "ln"
+
+	// PayTypeTip is the pay type of orders with pay what you want
+	// products, which are paid by tipping the store.
+	PayTypeTip PayType = "tip"
 )
 
 // Config holds the configuration for a simple store.
@@ -64,31 +78,214 @@ type Config struct {
 	PayType       PayType
 	Account       string
 	ShipCharge    float64
-	Client        *client.Client
-	LNPayClient   *client.DcrlnPaymentClient
+	Client        Client
+	LNPayClient   LNPayClient
+
+	// Currency is the fiat currency of the prices of the products.
+	// Defaults to USD.
+	Currency string
+
+	// RateProvider provides the exchange rate used to quote orders in
+	// DCR.
+	RateProvider RateProvider
 
+	// ExchangeRateProvider provides the USD/DCR exchange rate when
+	// RateProvider is not set.
+	//
+	// Deprecated: use RateProvider.
 	ExchangeRateProvider func() float64
+
+	// RateFailurePolicy is how orders are placed when the exchange rate
+	// provider fails. Defaults to placing orders without a payable
+	// amount.
+	RateFailurePolicy RateFailurePolicy
+
+	// RateMaxStaleness is the max age of the last exchange rate used to
+	// quote orders with RateFailureCached. Defaults to one hour.
+	RateMaxStaleness time.Duration
+
+	// OrderPaid is called after the payment of an order is detected and
+	// the buyer was sent the payment confirmation msg.
+	OrderPaid func(order *Order, msg string)
+
+	// QuoteRequested is called when a buyer requests (or declines) a
+	// quote for a product sold by quote, with the msg sent to the admins.
+	QuoteRequested func(quote *QuoteRequest, msg string)
+
+	// QuoteValidity is how long the quote (exchange rate and invoice) of
+	// placed orders is valid for. After that, unpaid orders expire and
+	// must be requoted. If zero, quotes are valid for one hour.
+	QuoteValidity time.Duration
+
+	// OnChainConfirmations is the number of confirmations an on-chain
+	// payment needs before the order is considered paid. If zero, a
+	// single confirmation is required.
+	OnChainConfirmations uint32
+
+	// AdminRouting configures how placed orders are assigned to the
+	// (remote) admins of the store.
+	AdminRouting AdminRouting
+
+	// AdminRoles are the roles of additional admins of the store, which
+	// restrict the pages of the admin section they may access. Admins in
+	// AdminRouting without a role here (and the local client) are owners,
+	// with full access.
+	AdminRoles map[clientintf.UserID]AdminRole
+
+	// Ledger configures the export of paid orders and refunds to a plain
+	// text accounting file.
+	Ledger LedgerConfig
+
+	// CoHost configures the co-hosting of the store by trusted clients.
+	CoHost CoHostConfig
+
+	// RenderEngine, if set, is used to render the store pages instead of
+	// the templates in the root dir. Templates that are not defined in
+	// the engine are rendered with the default store templates.
+	RenderEngine resources.RenderEngine
+
+	// Webhook configures the delivery of order events to an external
+	// system via HTTP.
+	Webhook WebhookConfig
+
+	// Backend is the storage of the store state (carts, orders, stock,
+	// etc). If nil, the state is stored in JSON files under the store
+	// root.
+	Backend StoreBackend
+
+	// PageCacheSize is the max size (in bytes) of the cache of rendered
+	// product pages. Zero means the default size of 16MiB. Negative
+	// values disable the cache.
+	PageCacheSize int64
+
+	// Escrow configures the escrow payment of high-value orders.
+	Escrow EscrowConfig
+
+	// EscrowChanged is called when the buyer releases or disputes the
+	// escrow of an order, with the msg sent to the admins.
+	EscrowChanged func(order *Order, msg string)
+
+	// PurchaseLimits are the limits on the purchases of each user.
+	PurchaseLimits PurchaseLimits
+
+	// OrderFilter, if set, is called before an order is placed (after
+	// the purchase limits are checked). Orders for which it returns an
+	// error are rejected, with the error shown to the buyer. It is called
+	// with the store locked, so it must not call methods of the store.
+	OrderFilter func(order *Order) error
+
+	// InventorySync configures the synchronization of the stock and
+	// prices with an external inventory system.
+	InventorySync InventorySyncConfig
+
+	// Reminders configures the low stock warnings sent to the admins and
+	// the reminders sent to users with idle carts and unpaid orders.
+	Reminders RemindersConfig
+
+	// LowStock is called when products fall below the low stock threshold,
+	// with the msg sent to the admins.
+	LowStock func(products []*Product, msg string)
+
+	// Tax configures the taxes charged on placed orders.
+	Tax TaxConfig
+
+	// ShippingMethods are the shipping methods buyers may select during
+	// checkout. Methods defined in the shipping.toml file of the store
+	// root take precedence over these. When no methods are defined,
+	// ShipCharge is charged on every order.
+	ShippingMethods []ShippingMethod
+
+	// XPub is the extended public key of an account of an external wallet
+	// where the on-chain payments of the store are received, to keep the
+	// funds of the store apart from the funds of the local wallet. It is
+	// imported in the LN wallet as a watch-only account named Account (or
+	// "simplestore", if Account is empty), which generates the payment
+	// addresses and detects the payments, while the received funds may
+	// only be spent by the external wallet.
+	XPub string
+
+	// Experiments are the A/B experiments of the layout of the pages of
+	// the store. Experiments defined in the experiments.toml file of the
+	// store root take precedence over these.
+	Experiments []Experiment
 }
 
+// invoiceCreatedBuffer is the number of created invoices buffered until the
+// invoice watcher handles them. Orders are placed (and their invoices sent to
+// the watcher) with the store mutex held, which the watcher needs in order to
+// start, so orders placed while the store is starting must not block.
+const invoiceCreatedBuffer = 16
+
+// defaultXPubAccount is the name of the watch-only account imported from the
+// XPub of the store when no account name is configured.
+const defaultXPubAccount = "simplestore"
+
 // Store is a simple store instance. A simple store can render a front page
 // (index) and individual product pages.
 type Store struct {
 	cfg         Config
-	c           *client.Client
+	c           Client
 	log         slog.Logger
 	root        string
-	lnpc        *client.DcrlnPaymentClient
+	lnpc        LNPayClient
+	journal     *jsonfile.Journal
+	backend     StoreBackend
+	orders      *orderIndex
+	events      *eventLog
 	runCtx      context.Context
 	runCancel   func()
 	chainParams *chaincfg.Params
 
-	mtx      sync.Mutex
-	products map[string]*Product
-	tmpl     *template.Template
+	mtx         sync.Mutex
+	products    map[string]*Product
+	variants    map[string]*Product
+	catalog     *Category
+	catalogDirs map[string]*catalogDir
+	search      *searchIndex
+	render      resources.RenderEngine
+	stock       stockLevels
+	shipKey     *[32]byte
+
+	// checkoutRules are the checkout rules loaded from the store root.
+	checkoutRules *CheckoutRules
 
-	invoiceSettledChan  chan string
+	// shipMethods are the shipping methods loaded from the store root.
+	shipMethods []ShippingMethod
+
+	// exps are the experiments loaded from the store root and expViewers
+	// the cached viewers of each experiment.
+	exps       []Experiment
+	expViewers map[string]*experimentViewers
+
+	// lastRate is the last exchange rate used to quote orders.
+	lastRate cachedRate
+
+	// webhooks is the dispatcher of events to the webhook, if one is
+	// configured.
+	webhooks *webhookDispatcher
+
+	// activity dispatches the activity events of the store to the
+	// registered handlers.
+	activity *activityDispatcher
+
+	// pages caches the rendered product pages. pagesGen is incremented
+	// every time the cache is purged.
+	pages    *lrucache.Cache[string, []byte]
+	pagesGen uint64
+
+	invoiceSettledChan  chan settledInvoice
 	invoiceCanceledChan chan string
 	invoiceCreatedChan  chan *Order
+
+	// nextAdminIdx is used to select the admin to assign orders to in a
+	// round-robin fashion.
+	nextAdminIdx int
+
+	// createdInvoices and canceledInvoices are the changes to the invoices
+	// of orders made while holding the mutex, that are sent to the invoice
+	// watcher once the mutex is released (see unlockAndNotify).
+	createdInvoices  []*Order
+	canceledInvoices []string
 }
 
 // New creates a new simple store.
@@ -97,6 +294,45 @@ func New(cfg Config) (*Store, error) {
 	if cfg.Log != nil {
 		log = cfg.Log
 	}
+	if err := cfg.InventorySync.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Reminders.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Tax.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateShippingMethods(cfg.ShippingMethods); err != nil {
+		return nil, err
+	}
+	if err := validateExperiments(cfg.Experiments); err != nil {
+		return nil, err
+	}
+	if err := cfg.RateFailurePolicy.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.XPub != "" && cfg.Account == "" {
+		cfg.Account = defaultXPubAccount
+	}
+	cfg.normalizeClients()
+
+	// Recover any order writes interrupted by a crash before loading the
+	// store.
+	journal, err := jsonfile.OpenJournal(filepath.Join(cfg.Root, journalDir), log)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open store journal: %v", err)
+	}
+	backend := cfg.Backend
+	if backend == nil {
+		backend = newJSONBackend(cfg.Root, journal)
+	}
+	orders := &orderIndex{log: log}
+	backend = &indexingBackend{StoreBackend: backend, idx: orders}
+	events, err := openEventLog(filepath.Join(cfg.Root, eventLogFile))
+	if err != nil {
+		return nil, err
+	}
 	runCtx, runCancel := context.WithCancel(context.Background())
 
 	s := &Store{
@@ -105,111 +341,193 @@ func New(cfg Config) (*Store, error) {
 		log:       log,
 		root:      cfg.Root,
 		products:  make(map[string]*Product),
-		tmpl:      template.New("*root"),
+		stock:     make(stockLevels),
+		catalog:   &Category{},
+		render:    resources.FallbackEngine{},
 		lnpc:      cfg.LNPayClient,
+		journal:   journal,
+		backend:   backend,
+		orders:    orders,
+		events:    events,
 		runCtx:    runCtx,
 		runCancel: runCancel,
+		activity:  newActivityDispatcher(log),
+		pages:     newPageCache(cfg),
 
-		invoiceSettledChan:  make(chan string),
+		invoiceSettledChan:  make(chan settledInvoice),
 		invoiceCanceledChan: make(chan string),
-		invoiceCreatedChan:  make(chan *Order),
+		invoiceCreatedChan:  make(chan *Order, invoiceCreatedBuffer),
+	}
+	if cfg.Webhook.URL != "" {
+		s.webhooks = newWebhookDispatcher(cfg.Webhook, log)
 	}
 
+	if err := s.loadStock(); err != nil {
+		return nil, err
+	}
+	if err := s.migrateSchema(); err != nil {
+		return nil, fmt.Errorf("unable to migrate store data: %w", err)
+	}
 	if err := s.reloadStore(); err != nil {
 		return nil, err
 	}
+	if err := s.loadState(); err != nil {
+		return nil, fmt.Errorf("unable to load store state: %v", err)
+	}
 	return s, nil
 }
 
-func (s *Store) reloadStore() error {
-	// Reset.
-	products := make(map[string]*Product, len(s.products))
-	tmpl := template.New("*root")
-
-	// Parse templates.
-	filenames, err := filepath.Glob(filepath.Join(s.root, "*.tmpl"))
-	if err != nil {
-		return err
-	}
-	for _, filename := range filenames {
-		if filepath.Ext(filename) != ".tmpl" {
-			continue
-		}
-		rawBytes, err := os.ReadFile(filename)
+// loadRenderEngine loads the engine used to render the store pages.
+func (s *Store) loadRenderEngine() (resources.RenderEngine, error) {
+	if s.cfg.RenderEngine != nil {
+		defaults, err := resources.ParseTextTemplatesFS(storeTemplate,
+			s.templateFuncs(), "template/*.tmpl")
 		if err != nil {
-			return err
+			return nil, err
 		}
-		data := string(rawBytes)
-		data = resources.ProcessEmbeds(data,
-			s.root, s.log)
+		return resources.FallbackEngine{s.cfg.RenderEngine, defaults}, nil
+	}
 
-		t := tmpl.New(filepath.Base(filename))
-		_, err = t.Parse(data)
+	// Parse templates, giving precedence to the templates of the active
+	// theme.
+	tmpl := template.New("*root").Funcs(s.templateFuncs())
+	theme, err := s.activeThemeName()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load active theme: %v", err)
+	}
+	if theme != "" {
+		themeDir := filepath.Join(s.root, themesDir, theme)
+		err := s.parseTemplatesDir(tmpl, themeDir,
+			filepath.Join(themeTemplatesDir, "*.tmpl"))
 		if err != nil {
-			return fmt.Errorf("unable to parse template %s: %v",
-				filename, err)
+			return nil, fmt.Errorf("unable to load theme %q: %v",
+				theme, err)
 		}
 	}
+	if err := s.parseTemplatesDir(tmpl, s.root, "*.tmpl"); err != nil {
+		return nil, err
+	}
+	if err := parseDefaultTemplates(tmpl); err != nil {
+		return nil, err
+	}
+	return s.loadTemplateOverrides(tmpl)
+}
 
-	// Load Products.
-	prodDir := filepath.Join(s.root, productsDir)
-	prodFiles, err := os.ReadDir(prodDir)
+func (s *Store) reloadStore() error {
+	render, err := s.loadRenderEngine()
 	if err != nil {
-		return fmt.Errorf("unable to list product files: %v", err)
+		return err
 	}
 
-	for _, prodFile := range prodFiles {
-		if filepath.Ext(prodFile.Name()) != ".toml" {
-			continue
-		}
-		fname := filepath.Join(prodDir, prodFile.Name())
-		var prods productsFile
-		f, err := os.Open(fname)
-		if err != nil {
-			return fmt.Errorf("unable to load product file %s: %v",
-				fname, err)
-		}
-		dec := toml.NewDecoder(f)
-		err = dec.Decode(&prods)
-		_ = f.Close()
+	// Load the product catalog. Co-hosts use the catalog mirrored from
+	// the primary store.
+	dirs := make(map[string]*catalogDir)
+	if s.isCoHost() {
+		s.mtx.Lock()
+		dirs, err = s.loadMirroredCatalog()
+		s.mtx.Unlock()
 		if err != nil {
-			return fmt.Errorf("unable to decode product file %s: %v",
-				fname, err)
-		}
-		for _, prod := range prods.Products {
-			if prod.Disabled {
-				continue
-			}
-
-			if _, ok := products[prod.SKU]; ok {
-				return fmt.Errorf("product with duplicated SKU %s in %s",
-					prod.SKU, fname)
-			}
-
-			products[prod.SKU] = prod
+			return err
 		}
+	} else if err := loadCatalogTree(filepath.Join(s.root, productsDir), "", dirs); err != nil {
+		return err
+	}
+	products, catalog, err := buildCatalog(dirs)
+	if err != nil {
+		return err
+	}
+	variants, err := buildVariants(products)
+	if err != nil {
+		return err
+	}
+	if err := validateBundles(products, variants); err != nil {
+		return err
+	}
+	if err := validateSubscriptions(products); err != nil {
+		return err
+	}
+	if err := validatePayWhatYouWant(products); err != nil {
+		return err
+	}
+	checkoutRules, err := loadCheckoutRules(filepath.Join(s.root, checkoutRulesFile))
+	if err != nil {
+		return err
+	}
+	shipMethods, err := loadShippingMethods(filepath.Join(s.root, shippingMethodsFile))
+	if err != nil {
+		return err
+	}
+	exps, err := loadExperiments(filepath.Join(s.root, experimentsFile))
+	if err != nil {
+		return err
 	}
 
 	s.mtx.Lock()
 	s.products = products
-	s.tmpl = tmpl
+	s.variants = variants
+	s.refreshStock()
+	s.catalog = catalog
+	s.catalogDirs = dirs
+	s.search = buildSearchIndex(products)
+	s.render = render
+	s.checkoutRules = checkoutRules
+	s.shipMethods = shipMethods
+	s.exps = exps
 	s.mtx.Unlock()
 
 	return nil
 }
 
+// Fulfill fulfills the request. Replies are signed with the identity key of
+// the client, so that buyers can verify the prices and payment details shown
+// to them were not altered.
 func (s *Store) Fulfill(ctx context.Context, uid clientintf.UserID,
 	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
 
+	res, err := s.fulfill(ctx, uid, request)
+	if err == nil && res != nil && s.c != nil {
+		s.c.SignResourceReply(request.Path, res)
+	}
+	return res, err
+}
+
+// signOrderSummary adds the signed summary of the order to a reply that shows
+// its payment details, so that the buyer can verify the invoice or address in
+// the reply was created by the store for their order.
+func (s *Store) signOrderSummary(order *Order, res *rpc.RMFetchResourceReply) {
+	if s.c == nil || !order.AwaitingPayment() {
+		return
+	}
+	summary := &rpc.OrderSummary{
+		Buyer:      order.User,
+		OrderID:    order.ID.String(),
+		TotalAtoms: int64(order.TotalDCR()),
+		PayType:    string(order.PayType),
+		Invoice:    order.Invoice,
+		ExpiresTS:  order.ExpiresTS.Unix(),
+	}
+	if err := s.c.SignOrderSummary(summary, res); err != nil {
+		s.log.Warnf("Unable to sign summary of order %s/%s: %v",
+			order.User.ShortLogID(), order.ID, err)
+	}
+}
+
+func (s *Store) fulfill(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
 	// Admin handlers.
 	if len(request.Path) > 0 && request.Path[0] == "admin" {
-		if uid != s.c.PublicID() {
+		if !s.isAdmin(uid) {
 			return s.handleNotFound(ctx, uid, request)
 		}
+		if res := s.checkAdminAccess(uid, request.Path); res != nil {
+			return res, nil
+		}
 		switch {
 		case pathEquals(request.Path, "admin"):
 			return s.handleAdminIndex(ctx, uid, request)
-		case pathEquals(request.Path, "admin", "orders"):
+		case pathEquals(request.Path, "admin", "orders"),
+			len(request.Path) == 3 && pathHasPrefix(request.Path, "admin", "orders"):
 			return s.handleAdminOrders(ctx, uid, request)
 		case pathHasPrefix(request.Path, "admin", "order"):
 			return s.handleAdminViewOrder(ctx, uid, request)
@@ -217,6 +535,78 @@ func (s *Store) Fulfill(ctx context.Context, uid clientintf.UserID,
 			return s.handleAdminAddOrderComment(ctx, uid, request)
 		case pathHasPrefix(request.Path, "admin", "orderstatusto"):
 			return s.handleAdminUpdateOrderStatus(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "orderrefund"):
+			return s.handleAdminRefundOrder(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "orderescrow"):
+			return s.handleAdminOrderEscrow(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "packingslip"):
+			return s.handleAdminPackingSlip(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "packingslips"):
+			return s.handleAdminPackingSlips(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "quotes"):
+			return s.handleAdminQuotes(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "quote"):
+			return s.handleAdminQuote(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "offerquote"):
+			return s.handleAdminOfferQuote(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "declinequote"):
+			return s.handleAdminDeclineQuote(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "subscriptions"):
+			return s.handleAdminSubscriptions(ctx, uid, request)
+		case len(request.Path) == 4 && pathHasPrefix(request.Path, "admin", "cancelsubscription"):
+			return s.handleAdminCancelSubscription(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "exportproducts"):
+			return s.handleAdminExportProducts(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "queryorders"):
+			return s.handleAdminQueryOrders(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "exportorders"):
+			return s.handleAdminExportOrders(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "orderack"):
+			return s.handleAdminAckOrder(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "stock"):
+			return s.handleAdminStock(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "setstock"):
+			return s.handleAdminSetStock(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "referrals"):
+			return s.handleAdminReferrals(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "products"):
+			return s.handleAdminProducts(ctx, uid, request)
+		case len(request.Path) == 3 && pathHasPrefix(request.Path, "admin", "customer"):
+			return s.handleAdminCustomer(ctx, uid, request)
+		case len(request.Path) == 3 && pathHasPrefix(request.Path, "admin", "customernote"):
+			return s.handleAdminAddCustomerNote(ctx, uid, request)
+		case pathHasPrefix(request.Path, "admin", "analytics"):
+			return s.handleAdminAnalytics(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "experiments"):
+			return s.handleAdminExperiments(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "customers"):
+			return s.handleAdminCustomers(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "newproduct"),
+			len(request.Path) == 3 && pathHasPrefix(request.Path, "admin", "editproduct"):
+			return s.handleAdminProductForm(ctx, uid, request)
+		case pathEquals(request.Path, "admin", "saveproduct"),
+			len(request.Path) == 3 && pathHasPrefix(request.Path, "admin", "saveproduct"):
+			return s.handleAdminSaveProduct(ctx, uid, request)
+		case len(request.Path) == 3 && (pathHasPrefix(request.Path, "admin", "archiveproduct") ||
+			pathHasPrefix(request.Path, "admin", "unarchiveproduct")):
+			return s.handleAdminArchiveProduct(ctx, uid, request)
+		case len(request.Path) == 3 && pathHasPrefix(request.Path, "admin", "deleteproduct"):
+			return s.handleAdminDeleteProduct(ctx, uid, request)
+		default:
+			return s.handleNotFound(ctx, uid, request)
+		}
+	}
+
+	// Co-host handlers.
+	if len(request.Path) > 0 && request.Path[0] == "cohost" {
+		if !s.isTrustedCoHost(uid) {
+			return s.handleNotFound(ctx, uid, request)
+		}
+		switch {
+		case pathEquals(request.Path, "cohost", "catalog"):
+			return s.handleCoHostCatalog(ctx, uid, request)
+		case pathEquals(request.Path, "cohost", "syncorders"):
+			return s.handleCoHostSyncOrders(ctx, uid, request)
 		default:
 			return s.handleNotFound(ctx, uid, request)
 		}
@@ -225,12 +615,30 @@ func (s *Store) Fulfill(ctx context.Context, uid clientintf.UserID,
 	switch {
 	case len(request.Path) == 0 || request.Path[0] == "index.md":
 		return s.handleIndex(ctx, uid, request)
+	case len(request.Path) > 1 && request.Path[0] == assetsDir:
+		return s.handleAsset(ctx, uid, request)
 	case len(request.Path) == 2 && request.Path[0] == "product":
 		return s.handleProduct(ctx, uid, request)
+	case len(request.Path) > 0 && request.Path[0] == "category":
+		return s.handleCategory(ctx, uid, request)
+	case isSearchPath(request.Path):
+		return s.handleSearch(ctx, uid, request)
 	case pathEquals(request.Path, "addToCart"):
 		return s.handleAddToCart(ctx, uid, request)
 	case len(request.Path) == 1 && request.Path[0] == "clearCart":
 		return s.handleClearCart(ctx, uid)
+	case pathHasPrefix(request.Path, "removeFromCart"):
+		return s.handleRemoveFromCart(ctx, uid, request)
+	case pathEquals(request.Path, "shippingInfo"):
+		return s.handleShippingInfo(ctx, uid, request)
+	case len(request.Path) == 2 && request.Path[0] == "shippingMethod":
+		return s.handleShippingMethod(ctx, uid, request)
+	case pathEquals(request.Path, "giftRecipient"):
+		return s.handleSetGiftRecipient(ctx, uid, request)
+	case pathEquals(request.Path, "applyCoupon"):
+		return s.handleApplyCoupon(ctx, uid, request)
+	case pathEquals(request.Path, "setCartQuantity"):
+		return s.handleSetCartQuantity(ctx, uid, request)
 	case len(request.Path) == 1 && request.Path[0] == "cart":
 		return s.handleCart(ctx, uid, request)
 	case len(request.Path) == 1 && request.Path[0] == "placeOrder":
@@ -239,8 +647,36 @@ func (s *Store) Fulfill(ctx context.Context, uid clientintf.UserID,
 		return s.handleOrders(ctx, uid, request)
 	case len(request.Path) == 2 && request.Path[0] == "order":
 		return s.handleOrderStatus(ctx, uid, request)
+	case len(request.Path) == 3 && request.Path[0] == "order" && request.Path[2] == "requote":
+		return s.handleOrderRequote(ctx, uid, request)
+	case len(request.Path) == 3 && request.Path[0] == "order" && request.Path[2] == "cancel":
+		return s.handleOrderCancel(ctx, uid, request)
+	case len(request.Path) == 3 && request.Path[0] == "order" && request.Path[2] == "escrowrelease":
+		return s.handleOrderEscrowRelease(ctx, uid, request)
+	case len(request.Path) == 3 && request.Path[0] == "order" && request.Path[2] == "escrowdispute":
+		return s.handleOrderEscrowDispute(ctx, uid, request)
 	case len(request.Path) == 2 && request.Path[0] == "orderaddcomment":
 		return s.handleOrderAddComment(ctx, uid, request)
+	case pathEquals(request.Path, "requestQuote"):
+		return s.handleRequestQuote(ctx, uid, request)
+	case len(request.Path) == 1 && request.Path[0] == "quotes":
+		return s.handleQuotes(ctx, uid, request)
+	case len(request.Path) == 2 && request.Path[0] == "quote":
+		return s.handleQuote(ctx, uid, request)
+	case len(request.Path) == 3 && request.Path[0] == "quote" && request.Path[2] == "accept":
+		return s.handleAcceptQuote(ctx, uid, request)
+	case len(request.Path) == 3 && request.Path[0] == "quote" && request.Path[2] == "decline":
+		return s.handleDeclineQuote(ctx, uid, request)
+	case len(request.Path) == 1 && request.Path[0] == "subscriptions":
+		return s.handleSubscriptions(ctx, uid, request)
+	case len(request.Path) == 2 && request.Path[0] == "subscription":
+		return s.handleSubscription(ctx, uid, request)
+	case len(request.Path) == 3 && request.Path[0] == "subscription" && request.Path[2] == "cancel":
+		return s.handleCancelSubscription(ctx, uid, request)
+	case len(request.Path) == 2 && request.Path[0] == "orderpayonchain":
+		return s.handleOrderPayOnChain(ctx, uid, request)
+	case len(request.Path) == 2 && request.Path[0] == "reminders":
+		return s.handleSetReminders(ctx, uid, request)
 	default:
 		return s.handleNotFound(ctx, uid, request)
 	}
@@ -259,13 +695,34 @@ func (s *Store) reloadFSWatchers(watcher *fsnotify.Watcher) {
 		}
 	}
 
-	if err := watcher.Add(filepath.Join(s.root, productsDir)); err != nil {
-		s.log.Warnf("Unable to watch products dir: %v", err)
+	// Watch every dir of the products tree.
+	s.mtx.Lock()
+	prodDirs := make([]string, 0, len(s.catalogDirs))
+	for rel := range s.catalogDirs {
+		prodDirs = append(prodDirs, rel)
+	}
+	s.mtx.Unlock()
+	if len(prodDirs) == 0 {
+		prodDirs = append(prodDirs, "")
+	}
+	for _, rel := range prodDirs {
+		dir := filepath.Join(s.root, productsDir, filepath.FromSlash(rel))
+		if err := watcher.Add(dir); err != nil {
+			s.log.Warnf("Unable to watch products dir %s: %v", dir, err)
+		}
 	}
 
 	if err := watcher.Add(filepath.Join(s.root)); err != nil {
 		s.log.Warnf("Unable to watch root dir: %v", err)
 	}
+
+	// The template overrides dir is optional.
+	tmplDir := filepath.Join(s.root, templatesDir)
+	if _, err := os.Stat(tmplDir); err == nil {
+		if err := watcher.Add(tmplDir); err != nil {
+			s.log.Warnf("Unable to watch templates dir: %v", err)
+		}
+	}
 }
 
 func (s *Store) runFSWatcher(ctx context.Context, watcher *fsnotify.Watcher) {
@@ -275,6 +732,15 @@ func (s *Store) runFSWatcher(ctx context.Context, watcher *fsnotify.Watcher) {
 	// once when multiple events happen in sequence.
 	var chanReload <-chan time.Time
 
+	// Changes restricted to the products tree only cause the affected
+	// dirs of the catalog to be reloaded and changes restricted to the
+	// template overrides only cause the templates to be reloaded. Other
+	// changes cause a full reload of the store.
+	var fullReload, tmplReload bool
+	changedDirs := make(map[string]struct{})
+	prodDir := filepath.Join(s.root, productsDir)
+	tmplDir := filepath.Join(s.root, templatesDir)
+
 	s.log.Debugf("Starting FS watcher")
 	for {
 		select {
@@ -283,12 +749,30 @@ func (s *Store) runFSWatcher(ctx context.Context, watcher *fsnotify.Watcher) {
 
 		case <-chanReload:
 			chanReload = nil
-			err := s.reloadStore()
+			var err error
+			if fullReload {
+				err = s.reloadStore()
+			} else if tmplReload && len(changedDirs) == 0 {
+				err = s.reloadTemplates()
+			} else {
+				relPaths := make([]string, 0, len(changedDirs))
+				for rel := range changedDirs {
+					relPaths = append(relPaths, rel)
+				}
+				err = s.reloadCatalogDirs(relPaths)
+			}
 			if err != nil {
 				s.log.Errorf("Unable to reload store: %v", err)
-			} else {
+			} else if fullReload {
 				s.log.Infof("Reloaded store")
+			} else if tmplReload && len(changedDirs) == 0 {
+				s.log.Infof("Reloaded templates")
+			} else {
+				s.log.Infof("Reloaded %d product catalog dirs",
+					len(changedDirs))
 			}
+			fullReload, tmplReload = false, false
+			changedDirs = make(map[string]struct{})
 			s.reloadFSWatchers(watcher)
 
 		case event, ok := <-watcher.Events:
@@ -297,6 +781,16 @@ func (s *Store) runFSWatcher(ctx context.Context, watcher *fsnotify.Watcher) {
 				return
 			}
 			s.log.Debugf("Watcher event: %s", event)
+			rel, err := filepath.Rel(prodDir, filepath.Dir(event.Name))
+			if filepath.Dir(event.Name) == tmplDir {
+				tmplReload = true
+			} else if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				fullReload = true
+			} else if rel = filepath.ToSlash(rel); rel == "." {
+				changedDirs[""] = struct{}{}
+			} else {
+				changedDirs[rel] = struct{}{}
+			}
 			chanReload = time.After(time.Millisecond * 100)
 
 		case err, ok := <-watcher.Errors:
@@ -324,8 +818,13 @@ func (s *Store) runLNInvoiceWatcher(ctx context.Context) error {
 		}
 
 		if inv.State == lnrpc.Invoice_SETTLED {
+			settled := settledInvoice{
+				discriminator: inv.PaymentRequest,
+				amount:        dcrutil.Amount(inv.AmtPaidMAtoms / 1000),
+				preimage:      hex.EncodeToString(inv.RPreimage),
+			}
 			select {
-			case s.invoiceSettledChan <- inv.PaymentRequest:
+			case s.invoiceSettledChan <- settled:
 			case <-ctx.Done():
 				return ctx.Err()
 			}
@@ -339,8 +838,43 @@ func (s *Store) runLNInvoiceWatcher(ctx context.Context) error {
 	}
 }
 
+// onChainPendingTx is an on-chain tx that pays to the wallet and is waiting to
+// reach the required number of confirmations.
+type onChainPendingTx struct {
+	blockHeight int32
+	outputs     []settledInvoice
+	seen        time.Time
+}
+
+// onChainTxOutputs returns the outputs of the tx that may pay for orders.
+func (s *Store) onChainTxOutputs(tx *lnrpc.Transaction) ([]settledInvoice, error) {
+	msgTx := wire.NewMsgTx()
+	if err := msgTx.Deserialize(hex.NewDecoder(bytes.NewBuffer([]byte(tx.RawTxHex)))); err != nil {
+		return nil, err
+	}
+
+	var outputs []settledInvoice
+	for _, out := range msgTx.TxOut {
+		_, addrs := stdscript.ExtractAddrs(out.Version, out.PkScript, s.chainParams)
+		if len(addrs) != 1 {
+			// All addressses we create here are standard
+			// P2PKH, so skip any that are not that.
+			continue
+		}
+
+		amount := dcrutil.Amount(out.Value)
+		outputs = append(outputs, settledInvoice{
+			discriminator: onChainInvoiceDiscriminator(addrs[0].String(), amount),
+			amount:        amount,
+			txid:          tx.TxHash,
+		})
+	}
+	return outputs, nil
+}
+
 // runOnChainInvoiceWatcher watches for on-chain transactions that may complete
-// orders.
+// orders. Transactions are only considered to have paid for orders after they
+// reach the configured number of confirmations.
 func (s *Store) runOnChainInvoiceWatcher(ctx context.Context) error {
 	// TODO: have some way to look for transactions upon restart.
 
@@ -348,120 +882,197 @@ func (s *Store) runOnChainInvoiceWatcher(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	for {
-		tx, err := stream.Recv()
-		if err != nil {
-			return err
-		}
 
-		// TODO: use different number of confirmations based on the
-		// the amount.
-		if tx.NumConfirmations < 1 {
-			continue
+	txChan := make(chan *lnrpc.Transaction)
+	errChan := make(chan error, 1)
+	go func() {
+		for {
+			tx, err := stream.Recv()
+			if err != nil {
+				errChan <- err
+				return
+			}
+			select {
+			case txChan <- tx:
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
 
-		msgTx := wire.NewMsgTx()
-		if err := msgTx.Deserialize(hex.NewDecoder(bytes.NewBuffer([]byte(tx.RawTxHex)))); err != nil {
-			s.log.Warnf("Unable to deserialize raw tx %s", tx.TxHash)
-			continue
-		}
+	minConfs := int32(s.cfg.OnChainConfirmations)
+	if minConfs < 1 {
+		minConfs = 1
+	}
 
-		for _, out := range msgTx.TxOut {
-			_, addrs := stdscript.ExtractAddrs(out.Version, out.PkScript, s.chainParams)
-			if len(addrs) != 1 {
-				// All addressses we create here are standard
-				// P2PKH, so skip any that are not that.
-				continue
-			}
+	// Txs that were seen but have not yet reached the required number of
+	// confirmations. The subscription only notifies about the first
+	// confirmation of txs, so the number of confirmations of these are
+	// checked periodically.
+	pending := make(map[string]*onChainPendingTx)
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
 
-			discriminator := onChainInvoiceDiscriminator(addrs[0].String(), dcrutil.Amount(out.Value))
+	settle := func(ptx *onChainPendingTx) error {
+		for _, out := range ptx.outputs {
 			select {
-			case s.invoiceSettledChan <- discriminator:
+			case s.invoiceSettledChan <- out:
 			case <-ctx.Done():
 				return ctx.Err()
 			}
 		}
+		return nil
+	}
+
+	for {
+		select {
+		case tx := <-txChan:
+			if tx.NumConfirmations >= minConfs {
+				delete(pending, tx.TxHash)
+			} else if ptx := pending[tx.TxHash]; ptx != nil {
+				ptx.blockHeight = tx.BlockHeight
+				continue
+			}
+
+			outputs, err := s.onChainTxOutputs(tx)
+			if err != nil {
+				s.log.Warnf("Unable to deserialize raw tx %s", tx.TxHash)
+				continue
+			}
+			ptx := &onChainPendingTx{
+				blockHeight: tx.BlockHeight,
+				outputs:     outputs,
+				seen:        time.Now(),
+			}
+			if tx.NumConfirmations < minConfs {
+				s.log.Debugf("Tx %s has %d/%d confirmations",
+					tx.TxHash, tx.NumConfirmations, minConfs)
+				pending[tx.TxHash] = ptx
+				continue
+			}
+			if err := settle(ptx); err != nil {
+				return err
+			}
+
+		case <-ticker.C:
+			if len(pending) == 0 {
+				continue
+			}
+			info, err := s.lnpc.LNRPC().GetInfo(ctx, &lnrpc.GetInfoRequest{})
+			if err != nil {
+				s.log.Warnf("Unable to fetch current block height: %v", err)
+				continue
+			}
+			height := int32(info.BlockHeight)
+			for txh, ptx := range pending {
+				if ptx.blockHeight == 0 {
+					// Drop txs that are never mined.
+					if time.Since(ptx.seen) > 24*time.Hour {
+						delete(pending, txh)
+					}
+					continue
+				}
+				confs := height - ptx.blockHeight + 1
+				if confs < minConfs {
+					continue
+				}
+				delete(pending, txh)
+				if err := settle(ptx); err != nil {
+					return err
+				}
+			}
+
+		case err := <-errChan:
+			return err
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 }
 
 // removePendingInvoice removes an order from the list of orders with pending
 // invoice.
 func (s *Store) removePendingInvoice(order *Order) {
-	dir := filepath.Join(s.root, pendingInvoicesDir)
-	fname := filepath.Join(dir, fmt.Sprintf("%s-%s", order.User, order.ID))
-	err := jsonfile.RemoveIfExists(fname)
+	fname := path.Join(pendingInvoicesDir, fmt.Sprintf("%s-%s", order.User, order.ID))
+	err := s.removeDoc(fname)
 	if err != nil {
 		s.log.Warnf("Unable to remove pending order %s: %v",
 			fname, err)
 	}
 }
 
+// settledInvoice is an invoice (LN or on-chain) that was paid.
+type settledInvoice struct {
+	discriminator string
+	amount        dcrutil.Amount
+
+	// txid is the id of the paying tx of on-chain payments.
+	txid string
+
+	// preimage is the hex encoded preimage of settled LN invoices.
+	preimage string
+}
+
 // invoiceSettled is called when an invoice for a given order was settled (paid)
 // by the user.
-func (s *Store) invoiceSettled(ctx context.Context, order *Order) {
+func (s *Store) invoiceSettled(ctx context.Context, order *Order, inv settledInvoice) {
+	amount := inv.amount
+
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	// Remove pending invoice if exists.
-	s.removePendingInvoice(order)
-
-	// Mark order as paid. First, reload the full order from disk.
-	orderDir := filepath.Join(s.root, ordersDir, order.User.String())
-	orderFname := filepath.Join(orderDir, orderFnamePattern.FilenameFor(uint64(order.ID)))
-	order = new(Order)
-	if err := jsonfile.Read(orderFname, order); err != nil {
-		s.log.Warnf("Unable to read order %s: %v", orderFname, err)
-		return
+	// Remove pending invoice if exists. Orders paid with tips do not
+	// have one.
+	if order.Invoice != "" {
+		s.removePendingInvoice(order)
 	}
 
-	// Now update status.
-	order.Status = StatusPaid
-	if err := jsonfile.Write(orderFname, order, s.log); err != nil {
-		s.log.Warnf("Unable to write order %s: %v", orderFname, err)
+	// Mark order as paid, recording the payment.
+	now := time.Now()
+	order, err := s.updateOrderStatusWith(order.User, order.ID, StatusPaid, nil,
+		func(order *Order) {
+			order.PaidAmount = amount
+			order.PaidTS = &now
+			order.PaidTxID = inv.txid
+			order.PaidPreimage = inv.preimage
+		})
+	if err != nil {
+		s.log.Warnf("Unable to mark order as paid: %v", err)
 		return
 	}
+	s.exportOrderPaid(order)
 
-	ru, err := s.c.UserByID(order.User)
+	userNick, err := s.c.UserNick(order.User)
 	if err != nil {
 		s.log.Warnf("Order #%d placed by unknown user %s",
 			order.ID, order.User)
 		return
 	}
 
-	s.log.Infof("Detected order %s/%s from user %s as paid",
-		order.User.ShortLogID(), order.ID, strescape.Nick(ru.Nick()))
+	s.log.Infof("Detected order %s/%s from user %s as paid (%s)",
+		order.User.ShortLogID(), order.ID, strescape.Nick(userNick), amount)
 
 	// Finally, send a message to user acknowledging payment.
 	var b strings.Builder
 	wpm := func(f string, args ...interface{}) {
 		b.WriteString(fmt.Sprintf(f, args...))
 	}
-	wpm("Your order %s/%s has been identified as paid",
-		order.User.ShortLogID(), order.ID)
+	wpm("Your order %s/%s has been identified as paid (received %s)",
+		order.User.ShortLogID(), order.ID, amount)
 
-	// If the order has files attached to it, send them to the user.
+	// The files of digital items are delivered when the order is marked
+	// as paid.
 	for _, item := range order.Cart.Items {
-		fname := item.Product.SendFilename
-		if item.Product.SendFilename == "" {
-			continue
-		}
-
-		// Relative paths are set to be from the root of the simplestore.
-		if !filepath.IsAbs(fname) {
-			fname = filepath.Join(s.root, fname)
+		if fname := s.digitalFilePath(item.Product); fname != "" {
+			wpm("\nSending you the file %s included in your order",
+				filepath.Base(fname))
 		}
-		wpm("\nSending you the file %s included in your order",
-			filepath.Base(fname))
-		go func() {
-			err := s.c.SendFile(order.User, fname)
-			s.log.Errorf("Unable to send file %s to user %s due to order %s/%s: %v",
-				fname, strescape.Nick(ru.Nick()),
-				order.User.ShortLogID(), order.ID, err)
-		}()
 	}
 
-	if s.cfg.StatusChanged != nil {
-		s.cfg.StatusChanged(order, b.String())
+	msg := s.sendOrderReceipt(order, b.String())
+	if s.cfg.OrderPaid != nil {
+		s.cfg.OrderPaid(order, msg)
 	}
 }
 
@@ -473,23 +1084,14 @@ func (s *Store) invoiceExpired(ctx context.Context, order *Order) {
 	// Remove pending invoice if exists.
 	s.removePendingInvoice(order)
 
-	// Mark order as paid. First, reload the full order from disk.
-	orderDir := filepath.Join(s.root, ordersDir, order.User.String())
-	orderFname := filepath.Join(orderDir, orderFnamePattern.FilenameFor(uint64(order.ID)))
-	order = new(Order)
-	if err := jsonfile.Read(orderFname, order); err != nil {
-		s.log.Warnf("Unable to read order %s: %v", orderFname, err)
-		return
-	}
-
-	// Now update status.
-	order.Status = StatusPaid
-	if err := jsonfile.Write(orderFname, order, s.log); err != nil {
-		s.log.Warnf("Unable to write order %s: %v", orderFname, err)
+	// Mark order as expired.
+	order, err := s.updateOrderStatus(order.User, order.ID, StatusExpired, nil)
+	if err != nil {
+		s.log.Warnf("Unable to mark order as expired: %v", err)
 		return
 	}
 
-	ru, err := s.c.UserByID(order.User)
+	userNick, err := s.c.UserNick(order.User)
 	if err != nil {
 		s.log.Warnf("Order #%d placed by unknown user %s",
 			order.ID, order.User)
@@ -497,18 +1099,55 @@ func (s *Store) invoiceExpired(ctx context.Context, order *Order) {
 	}
 
 	s.log.Infof("Detected order %s/%s from user %s as expired",
-		order.User.ShortLogID(), order.ID, strescape.Nick(ru.Nick()))
+		order.User.ShortLogID(), order.ID, strescape.Nick(userNick))
 
-	// Finally, send a message to user noting the expiration.
-	var b strings.Builder
-	wpm := func(f string, args ...interface{}) {
-		b.WriteString(fmt.Sprintf(f, args...))
-	}
-	wpm("Your order %s/%s has been identified as expired",
+	// Finally, send a receipt to user noting the expiration.
+	msg := fmt.Sprintf("Your order %s/%s has been identified as expired",
 		order.User.ShortLogID(), order.ID)
-
+	msg = s.sendOrderReceipt(order, msg)
 	if s.cfg.StatusChanged != nil {
-		s.cfg.StatusChanged(order, b.String())
+		s.cfg.StatusChanged(order, msg)
+	}
+}
+
+// trackInvoice queues the invoice of the order to be tracked by the invoice
+// watcher once the store mutex is released by unlockAndNotify.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) trackInvoice(order *Order) {
+	s.createdInvoices = append(s.createdInvoices, order)
+}
+
+// untrackInvoice queues the invoice with the given discriminator to no longer
+// be tracked by the invoice watcher once the store mutex is released by
+// unlockAndNotify.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) untrackInvoice(discriminator string) {
+	s.canceledInvoices = append(s.canceledInvoices, discriminator)
+}
+
+// unlockAndNotify releases the store mutex and sends the invoices queued by
+// trackInvoice and untrackInvoice to the invoice watcher. The watcher is only
+// notified after the mutex is released because it needs the mutex to start.
+func (s *Store) unlockAndNotify() {
+	created, canceled := s.createdInvoices, s.canceledInvoices
+	s.createdInvoices, s.canceledInvoices = nil, nil
+	s.mtx.Unlock()
+
+	for _, inv := range canceled {
+		select {
+		case s.invoiceCanceledChan <- inv:
+		case <-s.runCtx.Done():
+			return
+		}
+	}
+	for _, order := range created {
+		select {
+		case s.invoiceCreatedChan <- order:
+		case <-s.runCtx.Done():
+			return
+		}
 	}
 }
 
@@ -517,9 +1156,8 @@ func (s *Store) invoiceExpired(ctx context.Context, order *Order) {
 func (s *Store) runInvoiceWatcher(ctx context.Context) error {
 	// List orders with pending invoices.
 	s.mtx.Lock()
-	dirPending := filepath.Join(s.root, pendingInvoicesDir)
-	entries, err := os.ReadDir(dirPending)
-	if err != nil && !os.IsNotExist(err) {
+	entries, err := s.backend.List(path.Join(pendingInvoicesDir, "*"))
+	if err != nil {
 		s.mtx.Unlock()
 		return err
 	}
@@ -531,7 +1169,7 @@ func (s *Store) runInvoiceWatcher(ctx context.Context) error {
 	// dir is "<uid>-<order_id>".
 	nameRegexp := regexp.MustCompile(`([0-9a-fA-F]{64})-([0-9]*)`)
 	for _, entry := range entries {
-		name := entry.Name()
+		name := path.Base(entry)
 		matches := nameRegexp.FindStringSubmatch(name)
 		if len(matches) != 3 {
 			continue
@@ -545,9 +1183,8 @@ func (s *Store) runInvoiceWatcher(ctx context.Context) error {
 			continue
 		}
 		order := new(Order)
-		fname := filepath.Join(s.root, ordersDir, uid.String(),
-			orderFnamePattern.FilenameFor(uint64(oid)))
-		if err := jsonfile.Read(fname, order); err != nil {
+		fname := orderKey(uid, oid)
+		if err := s.backend.Read(fname, order); err != nil {
 			s.log.Warnf("Unable to load order %s: %v", fname, err)
 			continue
 		}
@@ -586,11 +1223,12 @@ func (s *Store) runInvoiceWatcher(ctx context.Context) error {
 		select {
 		case order := <-s.invoiceCreatedChan:
 			invoices[order.invoiceDiscriminator()] = order
+			resetNextExpiresTimer()
 
 		case inv := <-s.invoiceSettledChan:
-			if order := invoices[inv]; order != nil {
-				delete(invoices, inv)
-				go s.invoiceSettled(ctx, order)
+			if order := invoices[inv.discriminator]; order != nil {
+				delete(invoices, inv.discriminator)
+				go s.invoiceSettled(ctx, order, inv)
 			}
 
 		case inv := <-s.invoiceCanceledChan:
@@ -621,6 +1259,17 @@ func (s *Store) Run(ctx context.Context) error {
 	}
 	s.chainParams = chainParams
 
+	// Import the account of the external wallet before generating any
+	// payment address from it.
+	if s.cfg.XPub != "" {
+		err := s.lnpc.ImportXPubAccount(ctx, s.cfg.Account, s.cfg.XPub)
+		if err != nil {
+			return fmt.Errorf("unable to import store xpub: %v", err)
+		}
+		s.log.Infof("Receiving on-chain payments in the external "+
+			"account %q", s.cfg.Account)
+	}
+
 	g, gctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
@@ -644,6 +1293,26 @@ func (s *Store) Run(ctx context.Context) error {
 	g.Go(func() error { return s.runLNInvoiceWatcher(ctx) })
 	g.Go(func() error { return s.runOnChainInvoiceWatcher(ctx) })
 	g.Go(func() error { return s.runInvoiceWatcher(ctx) })
+	g.Go(func() error { return s.runAdminAckWatcher(ctx) })
+	g.Go(func() error { return s.runSubscriptionRenewals(gctx) })
+	g.Go(func() error { return s.runTipWatcher(gctx) })
+	g.Go(func() error { return s.runPaymentProofWatcher(gctx) })
+	if s.cfg.RateFailurePolicy == RateFailureRetry {
+		g.Go(func() error { return s.runPendingQuotes(gctx) })
+	}
+	if s.isCoHost() {
+		g.Go(func() error { return s.runCoHostSync(gctx) })
+	}
+	if s.webhooks != nil {
+		g.Go(func() error { return s.webhooks.run(gctx) })
+	}
+	if s.cfg.InventorySync.ListenAddr != "" {
+		g.Go(func() error { return s.runInventorySyncServer(gctx) })
+	}
+	if s.cfg.Reminders.enabled() {
+		g.Go(func() error { return s.runReminders(gctx) })
+	}
+	g.Go(func() error { return s.activity.run(gctx) })
 
 	return g.Wait()
 }
@@ -0,0 +1,236 @@
+package storetest
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
+	"github.com/companyzero/bisonrelay/client/resources/simplestore"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+)
+
+// ErrUnknownUser is returned by the fake client for users that were not added
+// to it.
+var ErrUnknownUser = errors.New("unknown user")
+
+// PM is a private message sent by the store.
+type PM struct {
+	To  clientintf.UserID
+	Msg string
+}
+
+// Tip is a tip sent by the store (e.g. a refund).
+type Tip struct {
+	To        clientintf.UserID
+	DCRAmount float64
+}
+
+// Client is a fake client for simple stores. It knows the users added to it
+// and records the messages, files and tips sent by the store instead of
+// sending them.
+type Client struct {
+	id    clientintf.UserID
+	ntfns *client.NotificationManager
+
+	mtx       sync.Mutex
+	nicks     map[clientintf.UserID]string
+	referrals map[clientintf.UserID]*clientdb.Referral
+	files     []PM
+	tips      []Tip
+	nextAddr  uint32
+	addrAccts map[string]string
+
+	pms chan PM
+}
+
+var _ simplestore.Client = (*Client)(nil)
+
+// NewClient creates a new fake client with a random ID.
+func NewClient() *Client {
+	return &Client{
+		id:        randomUserID(),
+		ntfns:     client.NewNotificationManager(),
+		nicks:     make(map[clientintf.UserID]string),
+		referrals: make(map[clientintf.UserID]*clientdb.Referral),
+		addrAccts: make(map[string]string),
+		pms:       make(chan PM, 100),
+	}
+}
+
+func randomUserID() clientintf.UserID {
+	var id clientintf.UserID
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// AddUser adds a user with the given nick, as if the client had KX'd with it.
+func (c *Client) AddUser(nick string) clientintf.UserID {
+	uid := randomUserID()
+	c.mtx.Lock()
+	c.nicks[uid] = nick
+	c.mtx.Unlock()
+	return uid
+}
+
+// SetReferral sets the referral through which the user was introduced to the
+// client.
+func (c *Client) SetReferral(uid clientintf.UserID, ref *clientdb.Referral) {
+	c.mtx.Lock()
+	c.referrals[uid] = ref
+	c.mtx.Unlock()
+}
+
+// PMs is the channel of the private messages sent by the store.
+func (c *Client) PMs() <-chan PM {
+	return c.pms
+}
+
+// SentFiles returns the files sent by the store. The Msg of the entries is the
+// path of the file.
+func (c *Client) SentFiles() []PM {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return append([]PM(nil), c.files...)
+}
+
+// Tips returns the tips sent by the store.
+func (c *Client) Tips() []Tip {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return append([]Tip(nil), c.tips...)
+}
+
+// PublicID is part of the simplestore.Client interface.
+func (c *Client) PublicID() clientintf.UserID {
+	return c.id
+}
+
+// UserNick is part of the simplestore.Client interface.
+func (c *Client) UserNick(uid clientintf.UserID) (string, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	nick, ok := c.nicks[uid]
+	if !ok {
+		return "", ErrUnknownUser
+	}
+	return nick, nil
+}
+
+// UIDByNick is part of the simplestore.Client interface.
+func (c *Client) UIDByNick(nick string) (clientintf.UserID, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for uid, n := range c.nicks {
+		if n == nick {
+			return uid, nil
+		}
+	}
+	return clientintf.UserID{}, ErrUnknownUser
+}
+
+// PM is part of the simplestore.Client interface.
+func (c *Client) PM(uid clientintf.UserID, msg string) error {
+	if _, err := c.UserNick(uid); err != nil {
+		return err
+	}
+	select {
+	case c.pms <- PM{To: uid, Msg: msg}:
+		return nil
+	default:
+		return fmt.Errorf("too many unread PMs")
+	}
+}
+
+// SendFile is part of the simplestore.Client interface.
+func (c *Client) SendFile(uid clientintf.UserID, filepath string) error {
+	if _, err := c.UserNick(uid); err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	c.files = append(c.files, PM{To: uid, Msg: filepath})
+	c.mtx.Unlock()
+	return nil
+}
+
+// TipUser is part of the simplestore.Client interface.
+func (c *Client) TipUser(uid clientintf.UserID, dcrAmount float64, maxAttempts int32) error {
+	if _, err := c.UserNick(uid); err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	c.tips = append(c.tips, Tip{To: uid, DCRAmount: dcrAmount})
+	c.mtx.Unlock()
+	return nil
+}
+
+// UserReferral is part of the simplestore.Client interface.
+func (c *Client) UserReferral(uid clientintf.UserID) (*clientdb.Referral, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.referrals[uid], nil
+}
+
+// OnchainRecvAddrForUser is part of the simplestore.Client interface. The
+// fake client returns a new simnet P2PKH address, which may be paid with
+// LNClient.PayOnChain.
+func (c *Client) OnchainRecvAddrForUser(uid clientintf.UserID, acct string) (string, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.nextAddr++
+	var hash [20]byte
+	binary.BigEndian.PutUint32(hash[:], c.nextAddr)
+	addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(hash[:],
+		chaincfg.SimNetParams())
+	if err != nil {
+		return "", err
+	}
+	c.addrAccts[addr.String()] = acct
+	return addr.String(), nil
+}
+
+// AddrAccount returns the account of the wallet for which the address was
+// generated by OnchainRecvAddrForUser.
+func (c *Client) AddrAccount(addr string) (string, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	acct, ok := c.addrAccts[addr]
+	return acct, ok
+}
+
+// FetchResource is part of the simplestore.Client interface. The fake client
+// is not connected to other clients, so it always fails.
+func (c *Client) FetchResource(uid clientintf.UserID, path []string, meta map[string]string,
+	sess, parentPage clientintf.PagesSessionID, data json.RawMessage) (rpc.ResourceTag, error) {
+	return 0, errors.New("fetching resources is not supported by the fake client")
+}
+
+// SignResourceReply is part of the simplestore.Client interface. Replies are
+// not signed by the fake client.
+func (c *Client) SignResourceReply(path []string, reply *rpc.RMFetchResourceReply) {}
+
+// SignOrderSummary is part of the simplestore.Client interface. Summaries are
+// not signed by the fake client.
+func (c *Client) SignOrderSummary(summary *rpc.OrderSummary, reply *rpc.RMFetchResourceReply) error {
+	return nil
+}
+
+// NotificationManager is part of the simplestore.Client interface.
+func (c *Client) NotificationManager() *client.NotificationManager {
+	return c.ntfns
+}
+
+// Metrics is part of the simplestore.Client interface.
+func (c *Client) Metrics() *metrics.Metrics {
+	return nil
+}
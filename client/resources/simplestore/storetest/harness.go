@@ -0,0 +1,314 @@
+// Package storetest provides a harness to test simple stores end to end,
+// without a running client, server or LN wallet.
+//
+// The harness runs a store on a temporary root with a fake client (which
+// records the messages sent by the store), a fake LN wallet (whose invoices
+// are paid with PayInvoice and on-chain addresses with PayOnChain) and a fixed
+// exchange rate.
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources/simplestore"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// DefaultRate is the exchange rate (in USD/DCR) of the stores created by New
+// without a rate provider.
+const DefaultRate = 20.0
+
+// waitTimeout is how long the harness waits for async events of the store.
+const waitTimeout = 5 * time.Second
+
+// FixedRate returns a rate provider that always returns the given rate.
+func FixedRate(rate float64) simplestore.RateProvider {
+	return simplestore.RateProviderFunc(func(string) (float64, error) {
+		return rate, nil
+	})
+}
+
+// Harness is a running simple store with fake dependencies.
+type Harness struct {
+	t testing.TB
+
+	Store  *simplestore.Store
+	Client *Client
+	LN     *LNClient
+	Root   string
+
+	cfg    simplestore.Config
+	cancel func()
+	runErr chan error
+}
+
+// New creates and runs a simple store with the given config. The root of the
+// store, the client and the LN client of the config are replaced by the fakes
+// of the harness. The store is quoted at DefaultRate and paid with LN, unless
+// the config specifies otherwise.
+//
+// The store is stopped when the test finishes.
+func New(t testing.TB, cfg simplestore.Config) *Harness {
+	t.Helper()
+	return NewWithClient(t, cfg, NewClient())
+}
+
+// NewWithClient is like New, but uses the given fake client. This allows users
+// (e.g. the admins of the store) to be added before the store is created.
+func NewWithClient(t testing.TB, cfg simplestore.Config, c *Client) *Harness {
+	t.Helper()
+	return NewWithProducts(t, cfg, c, "")
+}
+
+// NewWithProducts is like NewWithClient, but the store is created with the
+// products of the given product file (in the TOML format of the product files
+// of stores). This allows creating products with attributes not supported by
+// AddProduct.
+func NewWithProducts(t testing.TB, cfg simplestore.Config, c *Client, products string) *Harness {
+	t.Helper()
+
+	h := &Harness{
+		t:      t,
+		Client: c,
+		LN:     NewLNClient(),
+		Root:   t.TempDir(),
+	}
+	prodDir := filepath.Join(h.Root, "products")
+	if err := os.Mkdir(prodDir, 0o700); err != nil {
+		t.Fatalf("unable to create products dir: %v", err)
+	}
+	if products != "" {
+		fname := filepath.Join(prodDir, "products.toml")
+		if err := os.WriteFile(fname, []byte(products), 0o600); err != nil {
+			t.Fatalf("unable to write products file: %v", err)
+		}
+	}
+	cfg.Root = h.Root
+	cfg.Client = h.Client
+	cfg.LNPayClient = h.LN
+	if cfg.RateProvider == nil && cfg.ExchangeRateProvider == nil {
+		cfg.RateProvider = FixedRate(DefaultRate)
+	}
+	if cfg.PayType == "" {
+		cfg.PayType = simplestore.PayTypeLN
+	}
+
+	h.cfg = cfg
+	h.start()
+	t.Cleanup(h.stop)
+	return h
+}
+
+// start creates and runs the store.
+func (h *Harness) start() {
+	h.t.Helper()
+	s, err := simplestore.New(h.cfg)
+	if err != nil {
+		h.t.Fatalf("unable to create store: %v", err)
+	}
+	h.Store = s
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- s.Run(ctx) }()
+	h.cancel, h.runErr = cancel, runErr
+}
+
+// stop stops the running store, if there is one.
+func (h *Harness) stop() {
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	h.cancel = nil
+	err := <-h.runErr
+	if err != nil && !errors.Is(err, context.Canceled) {
+		h.t.Errorf("store run error: %v", err)
+	}
+}
+
+// Restart stops the store and runs a new one on the same root, as if the
+// client had been restarted. The new store performs its startup tasks (e.g.
+// renewing subscriptions) with the state left by the previous one.
+func (h *Harness) Restart() {
+	h.t.Helper()
+	h.stop()
+	h.start()
+}
+
+// AddProduct adds a product to the store.
+func (h *Harness) AddProduct(upd *simplestore.ProductUpdate) *simplestore.Product {
+	h.t.Helper()
+	prod, err := h.Store.AddProduct(upd)
+	if err != nil {
+		h.t.Fatalf("unable to add product %s: %v", upd.SKU, err)
+	}
+	return prod
+}
+
+// Product returns the product of the store with the given SKU.
+func (h *Harness) Product(sku string) *simplestore.Product {
+	h.t.Helper()
+	products, err := h.Store.Products(false)
+	if err != nil {
+		h.t.Fatalf("unable to list products: %v", err)
+	}
+	for _, prod := range products {
+		if prod.SKU == sku {
+			return prod
+		}
+	}
+	h.t.Fatalf("product %s not found", sku)
+	return nil
+}
+
+// Fetch fetches the page at the slash separated path on behalf of the user,
+// with data (if not nil) encoded as JSON, as sent by forms.
+func (h *Harness) Fetch(uid clientintf.UserID, path string, data interface{}) *rpc.RMFetchResourceReply {
+	h.t.Helper()
+	req := &rpc.RMFetchResource{
+		Path: strings.Split(strings.TrimPrefix(path, "/"), "/"),
+	}
+	if data != nil {
+		var err error
+		if req.Data, err = json.Marshal(data); err != nil {
+			h.t.Fatalf("unable to encode request data: %v", err)
+		}
+	}
+	res, err := h.Store.Fulfill(context.Background(), uid, req)
+	if err != nil {
+		h.t.Fatalf("unable to fetch %s: %v", path, err)
+	}
+	if res == nil {
+		h.t.Fatalf("empty reply fetching %s", path)
+	}
+	return res
+}
+
+// FetchPage fetches the page at path and fails the test if its status is not
+// ok. Returns the contents of the page.
+func (h *Harness) FetchPage(uid clientintf.UserID, path string, data interface{}) string {
+	h.t.Helper()
+	res := h.Fetch(uid, path, data)
+	if res.Status != rpc.ResourceStatusOk {
+		h.t.Fatalf("unexpected status %d fetching %s: %s", res.Status,
+			path, res.Data)
+	}
+	return string(res.Data)
+}
+
+// AddToCart adds qty units of the product to the cart of the user.
+func (h *Harness) AddToCart(uid clientintf.UserID, sku string, qty uint32) string {
+	h.t.Helper()
+	data := struct {
+		SKU string `json:"sku"`
+		Qty uint32 `json:"qty"`
+	}{SKU: sku, Qty: qty}
+	return h.FetchPage(uid, "addToCart", data)
+}
+
+// PlaceOrder places an order for the items of the cart of the user and returns
+// the placed order.
+func (h *Harness) PlaceOrder(uid clientintf.UserID) *simplestore.Order {
+	h.t.Helper()
+	before := make(map[simplestore.OrderID]bool)
+	for _, order := range h.Orders(uid) {
+		before[order.ID] = true
+	}
+	h.FetchPage(uid, "placeOrder", nil)
+	var placed []*simplestore.Order
+	for _, order := range h.Orders(uid) {
+		if !before[order.ID] {
+			placed = append(placed, order)
+		}
+	}
+	if len(placed) != 1 {
+		h.t.Fatalf("placing an order resulted in %d new orders", len(placed))
+	}
+	return placed[0]
+}
+
+// Orders returns the orders of the user, from the most recently placed.
+func (h *Harness) Orders(uid clientintf.UserID) []*simplestore.Order {
+	h.t.Helper()
+	orders, err := h.Store.QueryOrders(simplestore.OrderFilter{User: &uid})
+	if err != nil {
+		h.t.Fatalf("unable to query orders: %v", err)
+	}
+	return orders
+}
+
+// Order returns the order of the user with the given id.
+func (h *Harness) Order(uid clientintf.UserID, id simplestore.OrderID) *simplestore.Order {
+	h.t.Helper()
+	for _, order := range h.Orders(uid) {
+		if order.ID == id {
+			return order
+		}
+	}
+	h.t.Fatalf("order %s/%s not found", uid.ShortLogID(), id)
+	return nil
+}
+
+// PayOrder pays the LN invoice or the on-chain address of the order.
+func (h *Harness) PayOrder(order *simplestore.Order) {
+	h.t.Helper()
+	if order.Invoice == "" {
+		h.t.Fatalf("order %s/%s has no invoice", order.User.ShortLogID(), order.ID)
+	}
+	var err error
+	if order.PayType == simplestore.PayTypeOnChain {
+		err = h.LN.PayOnChain(order.Invoice, order.TotalDCR())
+	} else {
+		err = h.LN.PayInvoice(order.Invoice)
+	}
+	if err != nil {
+		h.t.Fatalf("unable to pay order: %v", err)
+	}
+}
+
+// WaitOrderStatus waits until the order of the user is in the given status
+// and returns it.
+func (h *Harness) WaitOrderStatus(uid clientintf.UserID, id simplestore.OrderID,
+	status simplestore.OrderStatus) *simplestore.Order {
+
+	h.t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		order := h.Order(uid, id)
+		if order.Status == status {
+			return order
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("order %s/%s is in status %s instead of %s",
+				uid.ShortLogID(), id, order.Status, status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// WaitPM waits for the next private message sent by the store to the user.
+// Messages sent to other users are discarded.
+func (h *Harness) WaitPM(uid clientintf.UserID) string {
+	h.t.Helper()
+	timeout := time.After(waitTimeout)
+	for {
+		select {
+		case pm := <-h.Client.PMs():
+			if pm.To == uid {
+				return pm.Msg
+			}
+		case <-timeout:
+			h.t.Fatalf("timeout waiting for PM to %s", uid.ShortLogID())
+			return ""
+		}
+	}
+}
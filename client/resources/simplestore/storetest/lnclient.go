@@ -0,0 +1,254 @@
+package storetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/companyzero/bisonrelay/client/resources/simplestore"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/dcrlnd/lnrpc"
+	"google.golang.org/grpc"
+)
+
+// LNClient is a fake LN wallet for simple stores. It generates fake invoices
+// and notifies the store of their payment when they are paid with PayInvoice.
+// On-chain payments to the addresses generated by the fake client are sent to
+// the store with PayOnChain.
+type LNClient struct {
+	mtx      sync.Mutex
+	nextID   uint64
+	invoices map[string]*lnrpc.Invoice
+
+	updates chan *lnrpc.Invoice
+	txs     chan *lnrpc.Transaction
+}
+
+var _ simplestore.LNPayClient = (*LNClient)(nil)
+
+// NewLNClient creates a new fake LN wallet.
+func NewLNClient() *LNClient {
+	return &LNClient{
+		invoices: make(map[string]*lnrpc.Invoice),
+		updates:  make(chan *lnrpc.Invoice, 100),
+		txs:      make(chan *lnrpc.Transaction, 100),
+	}
+}
+
+// ChainParams is part of the simplestore.LNPayClient interface. The fake
+// wallet is on simnet.
+func (c *LNClient) ChainParams(ctx context.Context) (*chaincfg.Params, error) {
+	return chaincfg.SimNetParams(), nil
+}
+
+// GetInvoice is part of the simplestore.LNPayClient interface.
+func (c *LNClient) GetInvoice(ctx context.Context, mat int64, cb func(int64)) (string, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.nextID++
+	payReq := fmt.Sprintf("lnsb%dfake%d", mat, c.nextID)
+	c.invoices[payReq] = &lnrpc.Invoice{
+		PaymentRequest: payReq,
+		RHash:          PaymentHash(payReq),
+		ValueMAtoms:    mat,
+		State:          lnrpc.Invoice_OPEN,
+	}
+	return payReq, nil
+}
+
+// ImportXPubAccount is part of the simplestore.LNPayClient interface.
+func (c *LNClient) ImportXPubAccount(ctx context.Context, name, xpub string) error {
+	return nil
+}
+
+// LNRPC is part of the simplestore.LNPayClient interface. Only the calls used
+// by simple stores are implemented by the returned client.
+func (c *LNClient) LNRPC() lnrpc.LightningClient {
+	return &lightningClient{ln: c}
+}
+
+// update applies f to an open invoice of the wallet and notifies the store of
+// the updated invoice.
+func (c *LNClient) update(payReq string, f func(inv *lnrpc.Invoice)) error {
+	c.mtx.Lock()
+	inv, ok := c.invoices[payReq]
+	if !ok {
+		c.mtx.Unlock()
+		return fmt.Errorf("invoice %q not found", payReq)
+	}
+	if inv.State != lnrpc.Invoice_OPEN {
+		c.mtx.Unlock()
+		return fmt.Errorf("invoice %q is not open (%s)", payReq, inv.State)
+	}
+	f(inv)
+	upd := &lnrpc.Invoice{
+		PaymentRequest: inv.PaymentRequest,
+		RHash:          inv.RHash,
+		ValueMAtoms:    inv.ValueMAtoms,
+		State:          inv.State,
+		AmtPaidMAtoms:  inv.AmtPaidMAtoms,
+		RPreimage:      inv.RPreimage,
+	}
+	c.mtx.Unlock()
+
+	select {
+	case c.updates <- upd:
+		return nil
+	default:
+		return errors.New("too many pending invoice updates")
+	}
+}
+
+// paymentPreimage returns the preimage of a fake invoice.
+func paymentPreimage(payReq string) []byte {
+	preimage := sha256.Sum256([]byte(payReq))
+	return preimage[:]
+}
+
+// PaymentHash returns the payment hash of a fake invoice generated by the
+// wallet.
+func PaymentHash(payReq string) []byte {
+	hash := sha256.Sum256(paymentPreimage(payReq))
+	return hash[:]
+}
+
+// PayInvoice pays the full amount of an invoice generated by the wallet.
+func (c *LNClient) PayInvoice(payReq string) error {
+	return c.update(payReq, func(inv *lnrpc.Invoice) {
+		inv.State = lnrpc.Invoice_SETTLED
+		inv.AmtPaidMAtoms = inv.ValueMAtoms
+		inv.RPreimage = paymentPreimage(payReq)
+	})
+}
+
+// PayOnChain notifies the store of a confirmed on-chain tx that pays amount to
+// the simnet address.
+func (c *LNClient) PayOnChain(addr string, amount dcrutil.Amount) error {
+	decoded, err := stdaddr.DecodeAddress(addr, chaincfg.SimNetParams())
+	if err != nil {
+		return err
+	}
+	version, script := decoded.PaymentScript()
+	msgTx := wire.NewMsgTx()
+	msgTx.AddTxOut(wire.NewTxOut(int64(amount), script))
+	msgTx.TxOut[0].Version = version
+	var b bytes.Buffer
+	if err := msgTx.Serialize(&b); err != nil {
+		return err
+	}
+
+	tx := &lnrpc.Transaction{
+		TxHash:           msgTx.TxHash().String(),
+		Amount:           int64(amount),
+		NumConfirmations: 1,
+		RawTxHex:         hex.EncodeToString(b.Bytes()),
+	}
+	select {
+	case c.txs <- tx:
+		return nil
+	default:
+		return errors.New("too many pending transactions")
+	}
+}
+
+// CancelInvoice is part of the simplestore.LNPayClient interface. It may also
+// be called by tests to cancel an invoice generated by the wallet.
+func (c *LNClient) CancelInvoice(ctx context.Context, payReq string) error {
+	return c.update(payReq, func(inv *lnrpc.Invoice) {
+		inv.State = lnrpc.Invoice_CANCELED
+	})
+}
+
+// lightningClient implements the calls of lnrpc.LightningClient used by simple
+// stores. Calling any other method panics.
+type lightningClient struct {
+	lnrpc.LightningClient
+	ln *LNClient
+}
+
+func (lc *lightningClient) SubscribeInvoices(ctx context.Context, in *lnrpc.InvoiceSubscription,
+	opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeInvoicesClient, error) {
+	return &invoicesStream{ctx: ctx, updates: lc.ln.updates}, nil
+}
+
+func (lc *lightningClient) SubscribeTransactions(ctx context.Context, in *lnrpc.GetTransactionsRequest,
+	opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeTransactionsClient, error) {
+	return &txsStream{ctx: ctx, txs: lc.ln.txs}, nil
+}
+
+func (lc *lightningClient) LookupInvoice(ctx context.Context, in *lnrpc.PaymentHash,
+	opts ...grpc.CallOption) (*lnrpc.Invoice, error) {
+	lc.ln.mtx.Lock()
+	defer lc.ln.mtx.Unlock()
+	for _, inv := range lc.ln.invoices {
+		if bytes.Equal(inv.RHash, in.RHash) {
+			return &lnrpc.Invoice{
+				PaymentRequest: inv.PaymentRequest,
+				RHash:          inv.RHash,
+				ValueMAtoms:    inv.ValueMAtoms,
+				State:          inv.State,
+				AmtPaidMAtoms:  inv.AmtPaidMAtoms,
+				RPreimage:      inv.RPreimage,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("invoice with hash %x not found", in.RHash)
+}
+
+func (lc *lightningClient) DecodePayReq(ctx context.Context, in *lnrpc.PayReqString,
+	opts ...grpc.CallOption) (*lnrpc.PayReq, error) {
+	lc.ln.mtx.Lock()
+	defer lc.ln.mtx.Unlock()
+	inv, ok := lc.ln.invoices[in.PayReq]
+	if !ok {
+		return nil, fmt.Errorf("invoice %q not found", in.PayReq)
+	}
+	return &lnrpc.PayReq{
+		PaymentHash: hex.EncodeToString(inv.RHash),
+		NumMAtoms:   inv.ValueMAtoms,
+	}, nil
+}
+
+func (lc *lightningClient) GetInfo(ctx context.Context, in *lnrpc.GetInfoRequest,
+	opts ...grpc.CallOption) (*lnrpc.GetInfoResponse, error) {
+	return &lnrpc.GetInfoResponse{}, nil
+}
+
+// invoicesStream streams the updates of the invoices of the fake wallet.
+type invoicesStream struct {
+	grpc.ClientStream
+	ctx     context.Context
+	updates chan *lnrpc.Invoice
+}
+
+func (s *invoicesStream) Recv() (*lnrpc.Invoice, error) {
+	select {
+	case inv := <-s.updates:
+		return inv, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+// txsStream streams the on-chain transactions of the fake wallet.
+type txsStream struct {
+	grpc.ClientStream
+	ctx context.Context
+	txs chan *lnrpc.Transaction
+}
+
+func (s *txsStream) Recv() (*lnrpc.Transaction, error) {
+	select {
+	case tx := <-s.txs:
+		return tx, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
@@ -0,0 +1,773 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/metrics"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+const (
+	subscriptionsDir = "subscriptions"
+
+	subscriptionTmplFile       = "subscription.tmpl"
+	subscriptionsTmplFile      = "subscriptions.tmpl"
+	adminSubscriptionsTmplFile = "admin_subscriptions.tmpl"
+
+	// subscriptionRenewalLead is how long before the end of the paid
+	// period of a subscription its renewal order is placed.
+	subscriptionRenewalLead = 24 * time.Hour
+
+	// subscriptionGracePeriod is how long after the end of the paid period
+	// the renewal order of a subscription may still be paid, after which
+	// the subscription lapses.
+	subscriptionGracePeriod = 7 * 24 * time.Hour
+
+	// subscriptionCheckInterval is the interval between checks for
+	// subscriptions to renew.
+	subscriptionCheckInterval = time.Hour
+)
+
+var subscriptionFnamePattern = jsonfile.MakeDecimalFilePattern("sub-", ".json", false)
+
+// SubscriptionInterval is the billing interval of a subscription product.
+type SubscriptionInterval string
+
+const (
+	SubscriptionWeekly  SubscriptionInterval = "weekly"
+	SubscriptionMonthly SubscriptionInterval = "monthly"
+)
+
+// IsValid returns true if the interval is one of the known intervals.
+func (interval SubscriptionInterval) IsValid() bool {
+	return interval == SubscriptionWeekly || interval == SubscriptionMonthly
+}
+
+// next returns the end of a period of the interval that starts at t.
+func (interval SubscriptionInterval) next(t time.Time) time.Time {
+	if interval == SubscriptionWeekly {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 1, 0)
+}
+
+// IsSubscription returns true if the product is sold as a subscription.
+func (prod *Product) IsSubscription() bool {
+	return prod.Subscription != ""
+}
+
+// validateSubscriptions ensures subscription products have a valid interval
+// and are not sold in ways that do not apply to recurring orders.
+func validateSubscriptions(products map[string]*Product) error {
+	for _, prod := range products {
+		if !prod.IsSubscription() {
+			continue
+		}
+		switch {
+		case !prod.Subscription.IsValid():
+			return fmt.Errorf("product %s has invalid subscription "+
+				"interval %q", prod.SKU, prod.Subscription)
+		case prod.HasVariants(), prod.IsBundle(), prod.CustomQuote:
+			return fmt.Errorf("subscription product %s cannot have "+
+				"variants, be a bundle or be sold by quote", prod.SKU)
+		case prod.Stock != nil, prod.Backorder:
+			return fmt.Errorf("subscription product %s cannot have "+
+				"limited stock", prod.SKU)
+		}
+	}
+	return nil
+}
+
+// SubscriptionStatus is the status of a subscription.
+type SubscriptionStatus string
+
+const (
+	// SubscriptionStatusActive is the status of subscriptions that are
+	// renewed at the end of their paid period.
+	SubscriptionStatusActive SubscriptionStatus = "active"
+
+	// SubscriptionStatusCanceled is the status of subscriptions canceled
+	// by the buyer or an admin. They are not renewed, but remain valid
+	// until the end of their paid period.
+	SubscriptionStatusCanceled SubscriptionStatus = "canceled"
+
+	// SubscriptionStatusLapsed is the status of subscriptions whose
+	// renewal order was not paid by the end of the grace period.
+	SubscriptionStatusLapsed SubscriptionStatus = "lapsed"
+)
+
+// Subscription is the subscription of a user to a subscription product,
+// started when an order for the product is paid.
+type Subscription struct {
+	User     clientintf.UserID    `json:"user"`
+	ID       uint64               `json:"id"`
+	SKU      string               `json:"sku"`
+	Title    string               `json:"title"`
+	Interval SubscriptionInterval `json:"interval"`
+	Quantity uint32               `json:"quantity"`
+	Status   SubscriptionStatus   `json:"status"`
+	StartTS  time.Time            `json:"start_ts"`
+
+	// Price is the price of a unit of the product in Currency, charged on
+	// every renewal.
+	Price    Money  `json:"price"`
+	Currency string `json:"currency"`
+
+	// PaidUntil is the end of the period paid for by the subscriber.
+	PaidUntil time.Time `json:"paid_until"`
+
+	// Orders are the paid orders of the subscription, starting with the
+	// order that started it.
+	Orders []OrderID `json:"orders"`
+
+	// RenewalOrderID is the ID of the placed renewal order, while it is
+	// not paid.
+	RenewalOrderID OrderID `json:"renewal_order_id,omitempty"`
+
+	// EncShipAddr is the encrypted shipping address of the order that
+	// started the subscription, used in renewal orders.
+	EncShipAddr []byte `json:"enc_shipping,omitempty"`
+
+	CanceledTS *time.Time `json:"canceled_ts,omitempty"`
+}
+
+// FormatPrice formats the price charged on every renewal.
+func (sub *Subscription) FormatPrice() string {
+	return formatAmount(sub.Price.Mul(int(sub.Quantity)), sub.Currency)
+}
+
+// IsValidAt returns true if the subscriber has access to the subscription at
+// the given time.
+func (sub *Subscription) IsValidAt(t time.Time) bool {
+	return sub.Status != SubscriptionStatusLapsed && t.Before(sub.PaidUntil)
+}
+
+// IsActive returns true if the subscription is renewed at the end of its paid
+// period.
+func (sub *Subscription) IsActive() bool {
+	return sub.Status == SubscriptionStatusActive
+}
+
+func subscriptionKey(uid clientintf.UserID, id uint64) string {
+	return path.Join(subscriptionsDir, uid.String(), subscriptionFnamePattern.FilenameFor(id))
+}
+
+func userSubscriptionsPattern(uid clientintf.UserID) string {
+	return path.Join(subscriptionsDir, uid.String(), "*.json")
+}
+
+// allSubscriptionsPattern matches the subscriptions of all users.
+var allSubscriptionsPattern = path.Join(subscriptionsDir, "*", "*.json")
+
+// loadSubscriptions loads the subscriptions that match the pattern, sorted by
+// start time.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadSubscriptions(pattern string) ([]*Subscription, error) {
+	keys, err := s.backend.List(pattern)
+	if err != nil {
+		return nil, err
+	}
+	subs := make([]*Subscription, 0, len(keys))
+	for _, key := range keys {
+		sub := new(Subscription)
+		if err := s.backend.Read(key, sub); err != nil {
+			s.log.Warnf("Unable to decode subscription %s: %v", key, err)
+			continue
+		}
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].StartTS.Before(subs[j].StartTS)
+	})
+	return subs, nil
+}
+
+// loadSubscription loads a subscription of the user.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) loadSubscription(uid clientintf.UserID, id uint64) (*Subscription, error) {
+	sub := new(Subscription)
+	if err := s.backend.Read(subscriptionKey(uid, id), sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// logSubscriptionEvent records an event about the subscription in the event
+// log.
+func (s *Store) logSubscriptionEvent(typ string, actor *clientintf.UserID, sub *Subscription) {
+	s.logEvent(typ, actor, map[string]string{
+		"subscription": fmt.Sprintf("%s/%d", sub.User, sub.ID),
+		"sku":          sub.SKU,
+	})
+}
+
+// subscriptionOrderPaid starts the subscriptions of the subscription products
+// of a paid order or, if the order is a renewal order, extends the paid period
+// of its subscription.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) subscriptionOrderPaid(order *Order) {
+	now := time.Now()
+	if order.SubscriptionID != 0 {
+		sub, err := s.loadSubscription(order.User, order.SubscriptionID)
+		if err != nil {
+			s.log.Errorf("Unable to load subscription %s/%d renewed by "+
+				"order %s: %v", order.User.ShortLogID(),
+				order.SubscriptionID, order.ID, err)
+			return
+		}
+		start := sub.PaidUntil
+		if start.Before(now) {
+			start = now
+		}
+		sub.PaidUntil = sub.Interval.next(start)
+		sub.Orders = append(sub.Orders, order.ID)
+		if sub.RenewalOrderID == order.ID {
+			sub.RenewalOrderID = 0
+		}
+		if sub.Status == SubscriptionStatusLapsed {
+			sub.Status = SubscriptionStatusActive
+		}
+		if err := s.writeDoc(subscriptionKey(sub.User, sub.ID), sub); err != nil {
+			s.log.Errorf("Unable to save subscription %s/%d: %v",
+				sub.User.ShortLogID(), sub.ID, err)
+			return
+		}
+		s.logSubscriptionEvent(EventSubscriptionRenewed, nil, sub)
+		s.log.Infof("Renewed subscription %s/%d until %s",
+			sub.User.ShortLogID(), sub.ID, sub.PaidUntil.Format(time.RFC3339))
+		return
+	}
+
+	var nextID uint64
+	for _, item := range order.Cart.Items {
+		if !item.Product.IsSubscription() {
+			continue
+		}
+
+		// Subscriptions are numbered sequentially for each user.
+		if nextID == 0 {
+			existing, err := s.loadSubscriptions(userSubscriptionsPattern(order.User))
+			if err != nil {
+				s.log.Errorf("Unable to load subscriptions of user %s: %v",
+					order.User.ShortLogID(), err)
+				return
+			}
+			for _, sub := range existing {
+				if sub.ID > nextID {
+					nextID = sub.ID
+				}
+			}
+		}
+		nextID++
+
+		sub := &Subscription{
+			User:        order.User,
+			ID:          nextID,
+			SKU:         item.Product.SKU,
+			Title:       item.Product.Title,
+			Interval:    item.Product.Subscription,
+			Quantity:    item.Quantity,
+			Status:      SubscriptionStatusActive,
+			StartTS:     now,
+			Price:       item.Product.Price,
+			Currency:    order.CurrencyCode(),
+			PaidUntil:   item.Product.Subscription.next(now),
+			Orders:      []OrderID{order.ID},
+			EncShipAddr: order.EncShipAddr,
+		}
+		if err := s.writeDoc(subscriptionKey(sub.User, sub.ID), sub); err != nil {
+			s.log.Errorf("Unable to save subscription %s/%d: %v",
+				sub.User.ShortLogID(), sub.ID, err)
+			continue
+		}
+		s.logSubscriptionEvent(EventSubscriptionStarted, nil, sub)
+		s.log.Infof("Started %s subscription %s/%d to %s",
+			sub.Interval, sub.User.ShortLogID(), sub.ID, sub.SKU)
+	}
+}
+
+// cancelRenewalOrder cancels the unpaid renewal order of the subscription, if
+// it has one.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) cancelRenewalOrder(sub *Subscription, by *clientintf.UserID) error {
+	if sub.RenewalOrderID == 0 {
+		return nil
+	}
+	order, err := s.updateOrderStatus(sub.User, sub.RenewalOrderID, StatusCanceled, by)
+	switch {
+	case errors.Is(err, ErrInvalidStatusTransition):
+		// Already resolved.
+	case err != nil:
+		return err
+	default:
+		s.removePendingInvoice(order)
+	}
+	sub.RenewalOrderID = 0
+	return nil
+}
+
+// cancelSubscription cancels the subscription and its unpaid renewal order.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) cancelSubscription(sub *Subscription, by *clientintf.UserID) error {
+	if !sub.IsActive() {
+		return fmt.Errorf("subscription %d is %s", sub.ID, sub.Status)
+	}
+	if err := s.cancelRenewalOrder(sub, by); err != nil {
+		return err
+	}
+	now := time.Now()
+	sub.Status = SubscriptionStatusCanceled
+	sub.CanceledTS = &now
+	if err := s.writeDoc(subscriptionKey(sub.User, sub.ID), sub); err != nil {
+		return err
+	}
+	s.logSubscriptionEvent(EventSubscriptionCanceled, by, sub)
+	s.log.Infof("Canceled subscription %s/%d", sub.User.ShortLogID(), sub.ID)
+	return nil
+}
+
+// Subscriptions returns the subscriptions of the user.
+func (s *Store) Subscriptions(uid clientintf.UserID) ([]*Subscription, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.loadSubscriptions(userSubscriptionsPattern(uid))
+}
+
+// HasSubscription returns true if the user has a paid subscription to the
+// product with the given SKU. This may be used to restrict access to the
+// content of memberships and paid newsletters.
+func (s *Store) HasSubscription(uid clientintf.UserID, sku string) (bool, error) {
+	subs, err := s.Subscriptions(uid)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for _, sub := range subs {
+		if sub.SKU == sku && sub.IsValidAt(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CancelSubscription cancels a subscription of the user. The subscription is
+// not renewed anymore, but remains valid until the end of its paid period.
+func (s *Store) CancelSubscription(uid clientintf.UserID, id uint64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	sub, err := s.loadSubscription(uid, id)
+	if err != nil {
+		return err
+	}
+	return s.cancelSubscription(sub, nil)
+}
+
+// placeRenewalOrder places the order that renews the subscription for another
+// period and sends its LN invoice to the subscriber. It returns the placed
+// order, if any.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) placeRenewalOrder(ctx context.Context, sub *Subscription) (*Order, error) {
+	prod, ok := s.product(sub.SKU)
+	if !ok || !prod.IsSubscription() || sub.Currency != s.currency() {
+		// The product cannot be renewed anymore.
+		msg := fmt.Sprintf("Your subscription %d to %q cannot be "+
+			"renewed because the product is no longer sold. It "+
+			"remains valid until %s.", sub.ID, sub.Title,
+			sub.PaidUntil.Format("2006-01-02"))
+		if err := s.cancelSubscription(sub, nil); err != nil {
+			return nil, err
+		}
+		if err := s.c.PM(sub.User, msg); err != nil {
+			s.log.Warnf("Unable to notify user %s of canceled "+
+				"subscription %d: %v", sub.User.ShortLogID(), sub.ID, err)
+		}
+		return nil, nil
+	}
+
+	id, err := s.backend.NextOrderID(sub.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// Renewals are charged at the price of the subscription.
+	renewed := *prod
+	renewed.Price = sub.Price
+	renewed.Title = fmt.Sprintf("%s (renewal of subscription %d)", prod.Title, sub.ID)
+	now := time.Now()
+	order := &Order{
+		Currency: sub.Currency,
+		User:     sub.User,
+		Cart: Cart{
+			Items:    []*CartItem{{Product: &renewed, Quantity: sub.Quantity}},
+			Updated:  now,
+			Currency: sub.Currency,
+		},
+		ID:             id,
+		Status:         StatusPlaced,
+		PlacedTS:       now,
+		EncShipAddr:    sub.EncShipAddr,
+		ExpiresTS:      now.Add(s.quoteValidity()),
+		SubscriptionID: sub.ID,
+	}
+	if len(sub.EncShipAddr) > 0 {
+		// Renewals are shipped with the default shipping method for
+		// the address of the subscriber.
+		addr, err := s.decryptShipAddr(sub.EncShipAddr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt shipping address "+
+				"of subscription %d: %v", sub.ID, err)
+		}
+		opt, ok := s.selectedShipping(&order.Cart, addr, "")
+		if !ok {
+			return nil, fmt.Errorf("no shipping method ships the "+
+				"renewal of subscription %d", sub.ID)
+		}
+		order.ShipCharge = opt.Price
+		if s.hasShippingMethods() {
+			order.ShipMethod = opt.Name
+		}
+	}
+	order.Taxes = s.orderTaxes(order)
+	rate, err := s.quoteRate()
+	if err != nil {
+		return nil, fmt.Errorf("unable to quote renewal order: %v", err)
+	}
+	order.ExchangeRate = rate
+	if order.TotalDCR() == 0 {
+		return nil, fmt.Errorf("renewal order has zero total dcr amount")
+	}
+	order.PayType, order.Invoice = s.newOrderInvoice(ctx, order,
+		PayTypeLN, sub.User.ShortLogID())
+	if order.Invoice == "" {
+		return nil, fmt.Errorf("unable to generate invoice for renewal order")
+	}
+
+	sub.RenewalOrderID = order.ID
+	batch := s.backend.NewBatch()
+	batch.Write(orderKey(sub.User, order.ID), order)
+	pendingFname := path.Join(pendingInvoicesDir, fmt.Sprintf("%s-%s", sub.User, order.ID))
+	batch.Write(pendingFname, "")
+	batch.Write(subscriptionKey(sub.User, sub.ID), sub)
+	if err := batch.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to save renewal order: %v", err)
+	}
+	s.logOrderEvent(EventOrderPlaced, nil, order, map[string]string{
+		"total":        order.FormatAmount(order.Total()),
+		"subscription": strconv.FormatUint(sub.ID, 10),
+	})
+	s.c.Metrics().Inc(metrics.StoreOrdersPlaced)
+
+	msg := fmt.Sprintf("Your subscription %d to %q renews on %s. Renewal "+
+		"order %s totals %s (%s, valid until %s).", sub.ID, sub.Title,
+		sub.PaidUntil.Format("2006-01-02"), order.ID,
+		order.FormatAmount(order.Total()), order.TotalDCR(),
+		order.ExpiresTS.Format("Mon, 02 Jan 2006 15:04 MST"))
+	if order.PayType == PayTypeLN {
+		msg += "\nLN Invoice for payment: lnpay://" + order.Invoice
+	} else {
+		msg += "\nOn-chain Payment Address: " + order.Invoice
+	}
+	msg += fmt.Sprintf("\nIf the invoice expires, requote the order in "+
+		"/order/%d", order.ID)
+	if embed := quoteEmbed(order); embed != "" {
+		msg += "\n" + embed
+	}
+	if err := s.c.PM(sub.User, msg); err != nil {
+		s.log.Warnf("Unable to send renewal invoice of subscription "+
+			"%s/%d: %v", sub.User.ShortLogID(), sub.ID, err)
+	}
+
+	if s.cfg.OrderPlaced != nil {
+		s.cfg.OrderPlaced(order, msg)
+	}
+	s.emitWebhook(WebhookOrderPlaced, order)
+	s.emitOrderActivity(ActivityOrderPlaced, order, "")
+	s.log.Infof("Placed renewal order %s/%s of subscription %d",
+		sub.User.ShortLogID(), order.ID, sub.ID)
+	return order, nil
+}
+
+// lapseSubscription marks the subscription as lapsed after its renewal order
+// was not paid by the end of the grace period.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) lapseSubscription(sub *Subscription) error {
+	if err := s.cancelRenewalOrder(sub, nil); err != nil {
+		return err
+	}
+	sub.Status = SubscriptionStatusLapsed
+	if err := s.writeDoc(subscriptionKey(sub.User, sub.ID), sub); err != nil {
+		return err
+	}
+	s.logSubscriptionEvent(EventSubscriptionLapsed, nil, sub)
+	s.log.Infof("Subscription %s/%d lapsed", sub.User.ShortLogID(), sub.ID)
+
+	msg := fmt.Sprintf("Your subscription %d to %q lapsed because its "+
+		"renewal was not paid", sub.ID, sub.Title)
+	if err := s.c.PM(sub.User, msg); err != nil {
+		s.log.Warnf("Unable to notify user %s of lapsed subscription "+
+			"%d: %v", sub.User.ShortLogID(), sub.ID, err)
+	}
+	return nil
+}
+
+// renewSubscriptions places the renewal orders of the active subscriptions
+// that are close to the end of their paid period and lapses the ones whose
+// renewal was not paid by the end of the grace period.
+func (s *Store) renewSubscriptions(ctx context.Context) error {
+	s.mtx.Lock()
+	orders, err := s.renewDueSubscriptions(ctx)
+	s.mtx.Unlock()
+
+	// The invoice watcher is only told about the new invoices after the
+	// store mutex is released, because it needs the mutex to start.
+	for _, order := range orders {
+		select {
+		case s.invoiceCreatedChan <- order:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// renewDueSubscriptions renews the subscriptions (see renewSubscriptions). It
+// returns the placed renewal orders.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) renewDueSubscriptions(ctx context.Context) ([]*Order, error) {
+	subs, err := s.loadSubscriptions(allSubscriptionsPattern)
+	if err != nil {
+		return nil, err
+	}
+	var orders []*Order
+	now := time.Now()
+	for _, sub := range subs {
+		if !sub.IsActive() {
+			continue
+		}
+
+		if now.After(sub.PaidUntil.Add(subscriptionGracePeriod)) {
+			if err := s.lapseSubscription(sub); err != nil {
+				s.log.Errorf("Unable to lapse subscription %s/%d: %v",
+					sub.User.ShortLogID(), sub.ID, err)
+			}
+			continue
+		}
+
+		// Unpaid renewal orders may be requoted by the subscriber
+		// until the end of the grace period.
+		if sub.RenewalOrderID != 0 || sub.PaidUntil.Sub(now) > subscriptionRenewalLead {
+			continue
+		}
+		order, err := s.placeRenewalOrder(ctx, sub)
+		if err != nil {
+			if ctx.Err() != nil {
+				return orders, ctx.Err()
+			}
+			s.log.Errorf("Unable to renew subscription %s/%d: %v",
+				sub.User.ShortLogID(), sub.ID, err)
+		} else if order != nil {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+// runSubscriptionRenewals periodically renews the subscriptions of the store.
+func (s *Store) runSubscriptionRenewals(ctx context.Context) error {
+	ticker := time.NewTicker(subscriptionCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.renewSubscriptions(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.log.Errorf("Unable to renew subscriptions: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Store) renderSubscriptionPage(tmplFile string, tmplCtx interface{}) (*rpc.RMFetchResourceReply, error) {
+	w := &bytes.Buffer{}
+	if err := s.render.Render(w, tmplFile, tmplCtx); err != nil {
+		return nil, fmt.Errorf("unable to execute subscription template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+// subscriptionFromPath loads the subscription of the user with the ID in the
+// given path element. It returns a reply if the subscription does not exist.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) subscriptionFromPath(uid clientintf.UserID, elem string) (*Subscription, *rpc.RMFetchResourceReply, error) {
+	id, err := strconv.ParseUint(elem, 10, 64)
+	if err != nil {
+		return nil, &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("invalid subscription id"),
+		}, nil
+	}
+	sub, err := s.loadSubscription(uid, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("subscription not found"),
+		}, nil
+	}
+	return sub, nil, err
+}
+
+// handleSubscriptions lists the subscriptions of the user.
+func (s *Store) handleSubscriptions(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	subs, err := s.Subscriptions(uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderSubscriptionPage(subscriptionsTmplFile, subs)
+}
+
+// handleSubscription shows a subscription of the user.
+func (s *Store) handleSubscription(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	sub, reply, err := s.subscriptionFromPath(uid, request.Path[1])
+	if sub == nil {
+		return reply, err
+	}
+	return s.renderSubscriptionPage(subscriptionTmplFile, sub)
+}
+
+// handleCancelSubscription cancels a subscription of the user.
+func (s *Store) handleCancelSubscription(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	sub, reply, err := s.subscriptionFromPath(uid, request.Path[1])
+	if sub == nil {
+		return reply, err
+	}
+	if !sub.IsActive() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("Subscription %d cannot be "+
+				"canceled in status %s", sub.ID, sub.Status)),
+		}, nil
+	}
+	if err := s.cancelSubscription(sub, &uid); err != nil {
+		return nil, err
+	}
+	return s.renderSubscriptionPage(subscriptionTmplFile, sub)
+}
+
+type adminSubscriptionContext struct {
+	*Subscription
+	UserNick string
+}
+
+// handleAdminSubscriptions lists the subscriptions of all users, the active
+// ones first.
+func (s *Store) handleAdminSubscriptions(ctx context.Context, _ clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	s.mtx.Lock()
+	subs, err := s.loadSubscriptions(allSubscriptionsPattern)
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(subs, func(i, j int) bool {
+		return subs[i].IsActive() && !subs[j].IsActive()
+	})
+
+	tmplCtx := make([]adminSubscriptionContext, len(subs))
+	for i, sub := range subs {
+		nick, _ := s.c.UserNick(sub.User)
+		tmplCtx[i] = adminSubscriptionContext{Subscription: sub, UserNick: strescape.Nick(nick)}
+	}
+	return s.renderSubscriptionPage(adminSubscriptionsTmplFile, tmplCtx)
+}
+
+// handleAdminCancelSubscription cancels the subscription in the
+// /admin/cancelsubscription/<uid>/<id> path on behalf of the store.
+func (s *Store) handleAdminCancelSubscription(ctx context.Context, admin clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	if len(request.Path) < 4 {
+		return nil, fmt.Errorf("path has < 4 elements")
+	}
+	var uid clientintf.UserID
+	if err := uid.FromString(request.Path[2]); err != nil {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data:   []byte("invalid user id"),
+		}, nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	sub, reply, err := s.subscriptionFromPath(uid, request.Path[3])
+	if sub == nil {
+		return reply, err
+	}
+	if !sub.IsActive() {
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusBadRequest,
+			Data: []byte(fmt.Sprintf("Subscription %d cannot be "+
+				"canceled in status %s", sub.ID, sub.Status)),
+		}, nil
+	}
+	if err := s.cancelSubscription(sub, &admin); err != nil {
+		return nil, err
+	}
+
+	msg := fmt.Sprintf("Your subscription %d to %q was canceled by the "+
+		"store. It remains valid until %s.", sub.ID, sub.Title,
+		sub.PaidUntil.Format("2006-01-02"))
+	if err := s.c.PM(sub.User, msg); err != nil {
+		s.log.Warnf("Unable to notify user %s of canceled subscription "+
+			"%d: %v", sub.User.ShortLogID(), sub.ID, err)
+	}
+
+	w := &bytes.Buffer{}
+	w.WriteString("# Subscription canceled\n\n")
+	w.WriteString("[Back to Subscriptions](/admin/subscriptions)\n\n")
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
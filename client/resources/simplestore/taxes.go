@@ -0,0 +1,157 @@
+package simplestore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TaxLine is a tax charged on an order, itemized in the receipts of the order.
+type TaxLine struct {
+	// Name is the name of the tax shown to the buyer (e.g. "VAT").
+	Name string `json:"name"`
+
+	// Rate is the rate of the tax, as a percentage of the taxed amount.
+	// It is zero for taxes with custom amounts.
+	Rate float64 `json:"rate,omitempty"`
+
+	Amount Money `json:"amount"`
+}
+
+// Label returns the name and rate of the tax line.
+func (line TaxLine) Label() string {
+	if line.Rate == 0 {
+		return line.Name
+	}
+	return fmt.Sprintf("%s (%s%%)", line.Name,
+		strconv.FormatFloat(line.Rate, 'f', -1, 64))
+}
+
+// TaxLines are the taxes charged on an order.
+type TaxLines []TaxLine
+
+// Total returns the total amount of the taxes.
+func (lines TaxLines) Total() Money {
+	var total Money
+	for _, line := range lines {
+		total += line.Amount
+	}
+	return total
+}
+
+// TaxConfig configures the taxes charged on the orders placed in the store.
+// The taxes of an order are calculated with Calc, if set. Otherwise, the rate
+// of the region of the shipping address of the order in Regions is used,
+// falling back to the flat Rate for orders without shipping or shipped to
+// other regions. Zero config values do not charge taxes.
+type TaxConfig struct {
+	// Name is the name of the tax shown to buyers. Defaults to "Tax".
+	Name string
+
+	// Rate is the flat rate of the tax, as a percentage of the order
+	// amount.
+	Rate float64
+
+	// Regions are the rates of the tax (as a percentage of the order
+	// amount) per region of the shipping address. Regions are either a
+	// country code (e.g. "US") or a country code and state (e.g. "US-CA"),
+	// which takes precedence over the country rate. Regions are case
+	// insensitive.
+	Regions map[string]float64
+
+	// TaxShipping is set when the shipping charge is also taxed.
+	TaxShipping bool
+
+	// Calc, if set, calculates the taxes of orders, instead of the rates
+	// above. It is called with the store locked, so it must not call
+	// methods of the store.
+	Calc func(order *Order) TaxLines
+}
+
+// validate returns an error if the tax rates are invalid.
+func (cfg *TaxConfig) validate() error {
+	if cfg.Rate < 0 || cfg.Rate >= 100 {
+		return fmt.Errorf("tax rate %v is not in the range [0, 100)", cfg.Rate)
+	}
+	for region, rate := range cfg.Regions {
+		if region == "" {
+			return fmt.Errorf("tax region is empty")
+		}
+		if rate < 0 || rate >= 100 {
+			return fmt.Errorf("tax rate %v of region %q is not in the "+
+				"range [0, 100)", rate, region)
+		}
+	}
+	return nil
+}
+
+// regionRate returns the tax rate of the region of the shipping address.
+func (cfg *TaxConfig) regionRate(addr *ShippingAddress) float64 {
+	if addr == nil || len(cfg.Regions) == 0 {
+		return cfg.Rate
+	}
+	country := strings.TrimSpace(addr.CountryCode)
+	state := strings.TrimSpace(addr.State)
+	var countryRate, stateRate *float64
+	for region, rate := range cfg.Regions {
+		rate := rate
+		c, st, hasState := strings.Cut(region, "-")
+		switch {
+		case !strings.EqualFold(c, country):
+		case !hasState:
+			countryRate = &rate
+		case strings.EqualFold(st, state):
+			stateRate = &rate
+		}
+	}
+	switch {
+	case stateRate != nil:
+		return *stateRate
+	case countryRate != nil:
+		return *countryRate
+	default:
+		return cfg.Rate
+	}
+}
+
+// orderTaxes returns the taxes of the order.
+func (cfg *TaxConfig) orderTaxes(order *Order) TaxLines {
+	if cfg.Calc != nil {
+		return cfg.Calc(order)
+	}
+
+	rate := cfg.regionRate(order.ShipAddr)
+	if rate == 0 {
+		return nil
+	}
+	taxed := order.Cart.Total()
+	if cfg.TaxShipping && order.ShipCharge > 0 {
+		taxed += order.ShipCharge
+	}
+	if taxed <= 0 {
+		return nil
+	}
+	name := cfg.Name
+	if name == "" {
+		name = "Tax"
+	}
+	return TaxLines{{Name: name, Rate: rate, Amount: taxed.Percent(rate)}}
+}
+
+// orderTaxes returns the taxes of the order, calculated with the (decrypted)
+// shipping address of the order.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) orderTaxes(order *Order) TaxLines {
+	if len(order.EncShipAddr) > 0 && order.ShipAddr == nil {
+		// Calculate with a copy of the order, so that the address is
+		// not written in plain text.
+		withAddr := *order
+		if err := s.loadOrderShipAddr(&withAddr); err != nil {
+			s.log.Warnf("Unable to load shipping address to "+
+				"calculate taxes: %v", err)
+		}
+		order = &withAddr
+	}
+	return s.cfg.Tax.orderTaxes(order)
+}
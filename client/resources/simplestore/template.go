@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"text/template"
 )
 
 //go:embed template
@@ -56,3 +57,31 @@ func WriteTemplate(rootDestPath string) error {
 
 	return fs.WalkDir(storeTemplate, "template", walkDirFunc)
 }
+
+// parseDefaultTemplates parses the templates of the default store template
+// that are not defined in tmpl. This allows stores created with older versions
+// of the template to use newer features.
+func parseDefaultTemplates(tmpl *template.Template) error {
+	entries, err := storeTemplate.ReadDir("template")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || filepath.Ext(name) != ".tmpl" {
+			continue
+		}
+		if tmpl.Lookup(name) != nil {
+			continue
+		}
+		data, err := storeTemplate.ReadFile("template/" + name)
+		if err != nil {
+			return err
+		}
+		if _, err := tmpl.New(name).Parse(string(data)); err != nil {
+			return fmt.Errorf("unable to parse default template %s: %v",
+				name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package simplestore
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// defaultDateLayout is the layout used by the formatDate template function
+// when one is not specified.
+const defaultDateLayout = "2006-01-02 15:04"
+
+// tmplNumber converts a numeric template argument to a float64.
+func tmplNumber(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case dcrutil.Amount:
+		return v.ToCoin(), nil
+	case Money:
+		return v.Float(), nil
+	default:
+		return 0, fmt.Errorf("%T is not a number", v)
+	}
+}
+
+// tmplTime converts a time template argument (time.Time or *time.Time) to a
+// time. Returns false if the argument is a nil or zero time.
+func tmplTime(v interface{}) (time.Time, bool, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v == nil {
+			return t, false, nil
+		}
+		t = *v
+	default:
+		return t, false, fmt.Errorf("%T is not a time", v)
+	}
+	return t, !t.IsZero(), nil
+}
+
+// tmplMoney converts a template argument with an amount (either Money or a
+// number of currency units) to Money.
+func tmplMoney(v interface{}) (Money, error) {
+	if m, ok := v.(Money); ok {
+		return m, nil
+	}
+	f, err := tmplNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	return MoneyFromFloat(f), nil
+}
+
+// mdEscaper escapes the chars that have a meaning in markdown.
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`, `--`, `\-\-`,
+)
+
+// timeAgo returns a description of how long ago t happened.
+func timeAgo(t time.Time) string {
+	d := time.Since(t)
+	if d < 0 {
+		return "in the future"
+	}
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int64(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return plural(int64(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		return plural(int64(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		return plural(int64(d/(30*24*time.Hour)), "month")
+	default:
+		return plural(int64(d/(365*24*time.Hour)), "year")
+	}
+}
+
+// templateFuncs returns the functions available to the store templates (see
+// the Template Functions section of the store docs). The functions do not
+// access anything outside the store: files may only be read from the assets
+// dir.
+func (s *Store) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// Money.
+		"money": func(v interface{}, currency ...string) (string, error) {
+			m, err := tmplMoney(v)
+			if err != nil {
+				return "", err
+			}
+			c := s.currency()
+			if len(currency) > 0 && currency[0] != "" {
+				c = strings.ToUpper(currency[0])
+			}
+			return formatAmount(m, c), nil
+		},
+		"amount": func(v interface{}) (string, error) {
+			m, err := tmplMoney(v)
+			if err != nil {
+				return "", err
+			}
+			return m.String(), nil
+		},
+		"cents": func(v interface{}) (int64, error) {
+			m, err := tmplMoney(v)
+			if err != nil {
+				return 0, err
+			}
+			return int64(m), nil
+		},
+		"fromCents": func(v interface{}) (Money, error) {
+			f, err := tmplNumber(v)
+			if err != nil {
+				return 0, err
+			}
+			return Money(math.Round(f)), nil
+		},
+		"mulPrice": func(price, qty interface{}) (Money, error) {
+			p, err := tmplMoney(price)
+			if err != nil {
+				return 0, err
+			}
+			q, err := tmplNumber(qty)
+			if err != nil {
+				return 0, err
+			}
+			return Money(math.Round(float64(p) * q)), nil
+		},
+		"addPrice": func(vs ...interface{}) (Money, error) {
+			var total Money
+			for _, v := range vs {
+				m, err := tmplMoney(v)
+				if err != nil {
+					return 0, err
+				}
+				total += m
+			}
+			return total, nil
+		},
+		"dcr": func(v interface{}) (string, error) {
+			switch v := v.(type) {
+			case dcrutil.Amount:
+				return v.String(), nil
+			case int64:
+				return dcrutil.Amount(v).String(), nil
+			}
+			f, err := tmplNumber(v)
+			if err != nil {
+				return "", err
+			}
+			amt, err := dcrutil.NewAmount(f)
+			if err != nil {
+				return "", err
+			}
+			return amt.String(), nil
+		},
+
+		// Dates.
+		"formatDate": func(v interface{}, layout ...string) (string, error) {
+			t, ok, err := tmplTime(v)
+			if !ok || err != nil {
+				return "", err
+			}
+			l := defaultDateLayout
+			if len(layout) > 0 && layout[0] != "" {
+				l = layout[0]
+			}
+			return t.Format(l), nil
+		},
+		"timeAgo": func(v interface{}) (string, error) {
+			t, ok, err := tmplTime(v)
+			if !ok || err != nil {
+				return "", err
+			}
+			return timeAgo(t), nil
+		},
+
+		// Markdown.
+		"mdEscape": func(s string) string {
+			return mdEscaper.Replace(s)
+		},
+		"mdQuote": func(s string) string {
+			s = strings.TrimRight(strings.ReplaceAll(s, "\r", ""), "\n")
+			return "> " + strings.ReplaceAll(s, "\n", "\n> ")
+		},
+		"truncate": func(n int, s string) string {
+			if runes := []rune(s); len(runes) > n {
+				return strings.TrimSpace(string(runes[:n])) + "..."
+			}
+			return s
+		},
+
+		// Images.
+		"embedImage": func(name string, alt ...string) string {
+			var a string
+			if len(alt) > 0 {
+				a = alt[0]
+			}
+			return s.assetEmbed(name, a)
+		},
+		"quoteEmbed": quoteEmbed,
+
+		// Admin blocks.
+		"isAdmin": func(data interface{}) bool {
+			v := reflect.Indirect(reflect.ValueOf(data))
+			if v.Kind() != reflect.Struct {
+				return false
+			}
+			f := v.FieldByName("IsAdmin")
+			return f.IsValid() && f.Kind() == reflect.Bool && f.Bool()
+		},
+	}
+}
@@ -0,0 +1,331 @@
+package simplestore
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/companyzero/bisonrelay/client/resources"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/pelletier/go-toml"
+)
+
+// Theme bundles are zip files (or dirs) with the following structure:
+//
+//	theme.toml      Manifest of the theme (see ThemeManifest).
+//	templates/      Templates (*.tmpl) that override the store templates.
+//	assets/         Files (images, etc) that may be embedded in the
+//	                templates with paths relative to the theme root.
+//
+// Installed themes are stored in the themes dir of the store root. Templates
+// not defined in the active theme are loaded from the store root and then
+// from the default store template.
+const (
+	themesDir          = "themes"
+	themeManifestFile  = "theme.toml"
+	themeTemplatesDir  = "templates"
+	activeThemeFile    = "active.json"
+	maxThemeBundleSize = 64 * 1024 * 1024
+)
+
+var themeNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ThemeManifest is the manifest of a theme bundle.
+type ThemeManifest struct {
+	Name        string `toml:"name" json:"name"`
+	Version     string `toml:"version" json:"version"`
+	Author      string `toml:"author" json:"author"`
+	Description string `toml:"description" json:"description"`
+}
+
+// Theme is an installed theme.
+type Theme struct {
+	ThemeManifest
+	Active bool
+}
+
+type activeTheme struct {
+	Name string `json:"name"`
+}
+
+func decodeThemeManifest(data []byte) (*ThemeManifest, error) {
+	var m ThemeManifest
+	if err := toml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("unable to decode theme manifest: %v", err)
+	}
+	if !themeNameRegexp.MatchString(m.Name) {
+		return nil, fmt.Errorf("invalid theme name %q", m.Name)
+	}
+	return &m, nil
+}
+
+// parseTemplatesDir parses the templates in dir that match pattern and are not
+// yet defined in tmpl.
+func (s *Store) parseTemplatesDir(tmpl *template.Template, dir, pattern string) error {
+	filenames, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return err
+	}
+	for _, filename := range filenames {
+		name := filepath.Base(filename)
+		if filepath.Ext(name) != ".tmpl" || tmpl.Lookup(name) != nil {
+			continue
+		}
+		rawBytes, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		data := resources.ProcessEmbeds(string(rawBytes), dir, s.log)
+		if _, err := tmpl.New(name).Parse(data); err != nil {
+			return fmt.Errorf("unable to parse template %s: %v",
+				filename, err)
+		}
+	}
+	return nil
+}
+
+// activeThemeName returns the name of the active theme or an empty string if
+// no theme is active.
+func (s *Store) activeThemeName() (string, error) {
+	var active activeTheme
+	err := jsonfile.Read(filepath.Join(s.root, themesDir, activeThemeFile), &active)
+	if errors.Is(err, jsonfile.ErrNotFound) {
+		return "", nil
+	}
+	return active.Name, err
+}
+
+// extractThemeFile extracts a single file of a theme bundle into destDir.
+func extractThemeFile(f *zip.File, destDir string) error {
+	name := path.Clean(f.Name)
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("invalid file name %q in theme bundle", f.Name)
+	}
+	dest := filepath.Join(destDir, filepath.FromSlash(name))
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(dest, 0o700)
+	}
+	if !f.Mode().IsRegular() {
+		return fmt.Errorf("file %q in theme bundle is not a regular file",
+			f.Name)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o700); err != nil {
+		return err
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, io.LimitReader(r, maxThemeBundleSize))
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// extractThemeBundle extracts the zip theme bundle into destDir.
+func extractThemeBundle(bundle, destDir string) error {
+	zr, err := zip.OpenReader(bundle)
+	if err != nil {
+		return fmt.Errorf("unable to open theme bundle: %v", err)
+	}
+	defer zr.Close()
+
+	var total uint64
+	for _, f := range zr.File {
+		total += f.UncompressedSize64
+		if total > maxThemeBundleSize {
+			return fmt.Errorf("theme bundle is larger than %d bytes",
+				maxThemeBundleSize)
+		}
+		if err := extractThemeFile(f, destDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyThemeDir copies an unpacked theme bundle dir into destDir.
+func copyThemeDir(srcDir, destDir string) error {
+	return filepath.Walk(srcDir, func(src string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, src)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(destDir, rel)
+		if info.IsDir() {
+			return os.MkdirAll(dest, 0o700)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dest, data, 0o600)
+	})
+}
+
+// InstallTheme installs the theme bundle (either a zip file or a dir) into the
+// store. If a theme with the same name is already installed, it is replaced.
+// The installed theme is not activated.
+func (s *Store) InstallTheme(bundle string) (*ThemeManifest, error) {
+	fi, err := os.Stat(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unpack into a temp dir to validate the bundle before installing it.
+	dir := filepath.Join(s.root, themesDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	tempDir, err := os.MkdirTemp(dir, ".install-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if fi.IsDir() {
+		err = copyThemeDir(bundle, tempDir)
+	} else {
+		err = extractThemeBundle(bundle, tempDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, themeManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read theme manifest: %v", err)
+	}
+	manifest, err := decodeThemeManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := template.New("*root").Funcs(s.templateFuncs())
+	if err := s.parseTemplatesDir(tmpl, tempDir, filepath.Join(themeTemplatesDir, "*.tmpl")); err != nil {
+		return nil, err
+	}
+	if len(tmpl.Templates()) == 0 {
+		return nil, fmt.Errorf("theme bundle has no templates")
+	}
+
+	s.mtx.Lock()
+	themeDir := filepath.Join(dir, manifest.Name)
+	err = os.RemoveAll(themeDir)
+	if err == nil {
+		err = os.Rename(tempDir, themeDir)
+	}
+	s.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infof("Installed theme %q version %q", manifest.Name, manifest.Version)
+
+	// Reload the store in case the replaced theme was active.
+	if active, _ := s.activeThemeName(); active == manifest.Name {
+		if err := s.reloadStore(); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+// ActivateTheme activates the installed theme with the given name. An empty
+// name deactivates the current theme.
+func (s *Store) ActivateTheme(name string) error {
+	if name != "" {
+		fname := filepath.Join(s.root, themesDir, name, themeManifestFile)
+		if !themeNameRegexp.MatchString(name) || !jsonfile.Exists(fname) {
+			return fmt.Errorf("theme %q is not installed", name)
+		}
+	}
+
+	s.mtx.Lock()
+	fname := filepath.Join(s.root, themesDir, activeThemeFile)
+	err := s.journal.Write(fname, &activeTheme{Name: name})
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.reloadStore()
+}
+
+// RemoveTheme removes an installed theme. The active theme cannot be removed.
+func (s *Store) RemoveTheme(name string) error {
+	if !themeNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid theme name %q", name)
+	}
+	active, err := s.activeThemeName()
+	if err != nil {
+		return err
+	}
+	if active == name {
+		return fmt.Errorf("cannot remove active theme %q", name)
+	}
+
+	themeDir := filepath.Join(s.root, themesDir, name)
+	if _, err := os.Stat(themeDir); err != nil {
+		return fmt.Errorf("theme %q is not installed", name)
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return os.RemoveAll(themeDir)
+}
+
+// ListThemes lists the installed themes.
+func (s *Store) ListThemes() ([]Theme, error) {
+	active, err := s.activeThemeName()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(s.root, themesDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Theme
+	for _, e := range entries {
+		if !e.IsDir() || !themeNameRegexp.MatchString(e.Name()) {
+			continue
+		}
+		fname := filepath.Join(s.root, themesDir, e.Name(), themeManifestFile)
+		data, err := os.ReadFile(fname)
+		if err != nil {
+			s.log.Warnf("Unable to read theme manifest %s: %v", fname, err)
+			continue
+		}
+		manifest, err := decodeThemeManifest(data)
+		if err != nil {
+			s.log.Warnf("Invalid theme manifest %s: %v", fname, err)
+			continue
+		}
+		res = append(res, Theme{
+			ThemeManifest: *manifest,
+			Active:        manifest.Name == active,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
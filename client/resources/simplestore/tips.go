@@ -0,0 +1,255 @@
+package simplestore
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+const (
+	// pendingTipsDir tracks the orders waiting to be paid with tips, so
+	// that they are expired when not paid in time.
+	pendingTipsDir = "pendingtips"
+
+	// tipExpiryInterval is the max interval between checks for expired
+	// orders waiting to be paid with tips.
+	tipExpiryInterval = time.Minute
+)
+
+// pendingTipKey is the key that tracks an order waiting to be paid with tips.
+func pendingTipKey(uid clientintf.UserID, id OrderID) string {
+	return path.Join(pendingTipsDir, fmt.Sprintf("%s-%s", uid, id))
+}
+
+// validatePayWhatYouWant ensures pay what you want products are not sold in
+// ways that need a fixed price.
+func validatePayWhatYouWant(products map[string]*Product) error {
+	for _, prod := range products {
+		if !prod.PayWhatYouWant {
+			continue
+		}
+		if prod.HasVariants() || prod.IsBundle() || prod.CustomQuote ||
+			prod.IsSubscription() {
+			return fmt.Errorf("pay what you want product %s cannot "+
+				"have variants, be a bundle, be sold by quote or be "+
+				"a subscription", prod.SKU)
+		}
+		if prod.Price < 0 {
+			return fmt.Errorf("pay what you want product %s has a "+
+				"negative minimum price", prod.SKU)
+		}
+	}
+	return nil
+}
+
+// AwaitingTip returns true if the order is waiting to be paid with tips.
+func (order *Order) AwaitingTip() bool {
+	return order.Status == StatusPlaced && order.PayType == PayTypeTip
+}
+
+// TipDue returns the amount that remains to be tipped to pay for the order.
+func (order *Order) TipDue() dcrutil.Amount {
+	due := order.TotalDCR() - order.PaidAmount
+	if due < 0 {
+		return 0
+	}
+	return due
+}
+
+// oldestTipOrder returns the oldest order of the user waiting to be paid with
+// tips, if there is one.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) oldestTipOrder(uid clientintf.UserID) (*Order, error) {
+	files, err := s.backend.List(userOrdersPattern(uid))
+	if err != nil {
+		return nil, err
+	}
+	var orders []*Order
+	for _, fname := range files {
+		order := new(Order)
+		if err := s.backend.Read(fname, order); err != nil {
+			s.log.Warnf("Unable to read order %s: %v", fname, err)
+			continue
+		}
+		if order.AwaitingTip() {
+			orders = append(orders, order)
+		}
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].PlacedTS.Before(orders[j].PlacedTS)
+	})
+	return orders[0], nil
+}
+
+// tipReceived applies a tip received from a user to the oldest of their orders
+// waiting to be paid with tips. Tips from users without such orders are
+// ignored by the store.
+func (s *Store) tipReceived(ctx context.Context, uid clientintf.UserID, amount dcrutil.Amount) {
+	defer s.backend.LockUser(uid)()
+	s.mtx.Lock()
+	order, err := s.oldestTipOrder(uid)
+	if err != nil || order == nil {
+		s.mtx.Unlock()
+		if err != nil {
+			s.log.Errorf("Unable to load orders of user %s paid with "+
+				"tips: %v", uid.ShortLogID(), err)
+		}
+		return
+	}
+
+	paid := order.PaidAmount + amount
+	if paid >= order.TotalDCR() {
+		s.mtx.Unlock()
+		s.invoiceSettled(ctx, order, settledInvoice{amount: paid})
+		return
+	}
+
+	// Partial payment: record the amount tipped so far.
+	defer s.mtx.Unlock()
+	order.PaidAmount = paid
+	if err := s.writeDoc(orderKey(uid, order.ID), order); err != nil {
+		s.log.Errorf("Unable to record tip for order %s/%s: %v",
+			uid.ShortLogID(), order.ID, err)
+		return
+	}
+	s.log.Infof("Received tip of %s for order %s/%s (%s due)", amount,
+		uid.ShortLogID(), order.ID, order.TipDue())
+	msg := fmt.Sprintf("Received tip of %s for order %s. Tip %s more to "+
+		"complete the payment of the order.", amount, order.ID,
+		order.TipDue())
+	if err := s.c.PM(uid, msg); err != nil {
+		s.log.Warnf("Unable to notify user %s of partial payment of "+
+			"order %s: %v", uid.ShortLogID(), order.ID, err)
+	}
+}
+
+// expireTipOrders expires the orders waiting to be paid with tips that were
+// not paid before their quote expired, returning their items to the stock.
+// Orders that were partially paid are not expired, so that the tips already
+// sent by the buyer are not lost.
+func (s *Store) expireTipOrders(ctx context.Context) error {
+	s.mtx.Lock()
+	entries, err := s.backend.List(path.Join(pendingTipsDir, "*"))
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	nameRegexp := regexp.MustCompile(`([0-9a-fA-F]{64})-([0-9]*)`)
+	for _, entry := range entries {
+		matches := nameRegexp.FindStringSubmatch(path.Base(entry))
+		if len(matches) != 3 {
+			continue
+		}
+		var uid clientintf.UserID
+		if err := uid.FromString(matches[1]); err != nil {
+			continue
+		}
+		var oid OrderID
+		if err := oid.FromString(matches[2]); err != nil {
+			continue
+		}
+		s.expireTipOrder(ctx, uid, oid, now)
+	}
+	return nil
+}
+
+// expireTipOrder expires the order waiting to be paid with tips if its quote
+// expired (see expireTipOrders).
+func (s *Store) expireTipOrder(ctx context.Context, uid clientintf.UserID,
+	oid OrderID, now time.Time) {
+
+	// The user lock serializes the expiration with the tips received
+	// from the user.
+	defer s.backend.LockUser(uid)()
+	s.mtx.Lock()
+	key := pendingTipKey(uid, oid)
+	var order Order
+	if err := s.backend.Read(orderKey(uid, oid), &order); err != nil {
+		s.mtx.Unlock()
+		s.log.Warnf("Unable to load order %s/%s paid with tips: %v",
+			uid.ShortLogID(), oid, err)
+		return
+	}
+	switch {
+	case !order.AwaitingTip():
+		// Paid, canceled or switched to another payment method.
+	case order.PaidAmount > 0, now.Before(order.ExpiresTS):
+		s.mtx.Unlock()
+		return
+	default:
+		s.mtx.Unlock()
+		s.invoiceExpired(ctx, &order)
+		s.mtx.Lock()
+	}
+	if err := s.removeDoc(key); err != nil {
+		s.log.Warnf("Unable to remove pending tip order %s: %v", key, err)
+	}
+	s.mtx.Unlock()
+}
+
+// runTipWatcher applies the tips received by the client to the orders paid
+// with tips and expires the ones that are not paid in time.
+func (s *Store) runTipWatcher(ctx context.Context) error {
+	reg := s.c.NotificationManager().Register(client.OnTipReceivedNtfn(
+		func(ru *client.RemoteUser, amountMAtoms int64) {
+			amount := dcrutil.Amount(amountMAtoms / 1000)
+			go s.tipReceived(ctx, ru.ID(), amount)
+		}))
+	defer reg.Unregister()
+
+	// Expired orders are checked at least once during the validity of
+	// their quotes.
+	interval := tipExpiryInterval
+	if v := s.quoteValidity(); v < interval {
+		interval = v
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.expireTipOrders(ctx); err != nil {
+			s.log.Errorf("Unable to expire orders paid with tips: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkPayWhatYouWantAmount checks the amount chosen by the buyer for a pay
+// what you want product. It returns an error message for the buyer if the
+// amount is not valid.
+func checkPayWhatYouWantAmount(prod *Product, amount Money, currency string) string {
+	if amount <= 0 || amount < prod.Price {
+		if prod.Price > 0 {
+			return fmt.Sprintf("The minimum amount for %q is %s",
+				prod.Title, formatAmount(prod.Price, currency))
+		}
+		return fmt.Sprintf("Choose an amount to pay for %q", prod.Title)
+	}
+	return ""
+}
+
+// tipPaymentMsg is the message sent to buyers with the instructions to pay for
+// an order with tips.
+func tipPaymentMsg(order *Order) string {
+	return fmt.Sprintf("Pay for the order by sending a tip of %s to the "+
+		"store (for example, with the /paytip command of brclient). "+
+		"Tips are applied to your oldest unpaid order paid with tips.",
+		order.TipDue())
+}
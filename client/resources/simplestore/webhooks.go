@@ -0,0 +1,236 @@
+package simplestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/decred/slog"
+	"golang.org/x/exp/slices"
+)
+
+// WebhookEvent is the type of a store event sent to the webhook.
+type WebhookEvent string
+
+const (
+	WebhookOrderPlaced   WebhookEvent = "order.placed"
+	WebhookOrderPaid     WebhookEvent = "order.paid"
+	WebhookOrderCanceled WebhookEvent = "order.canceled"
+)
+
+const (
+	// WebhookSignatureHeader is the header of webhook requests with the
+	// hex encoded HMAC-SHA256 of the request body, keyed by the webhook
+	// secret.
+	WebhookSignatureHeader = "X-Simplestore-Signature"
+
+	// webhookQueueSize is the max number of events waiting to be
+	// delivered. Events generated while the queue is full are dropped.
+	webhookQueueSize = 1000
+)
+
+// WebhookConfig configures the delivery of store events to an external
+// system (for example, a fulfillment or ERP system) via HTTP.
+type WebhookConfig struct {
+	// URL is the URL the events are POSTed to. If empty, events are not
+	// sent.
+	URL string
+
+	// Secret is the key used to sign the events.
+	Secret string
+
+	// Events are the events to send. If empty, all events are sent.
+	Events []WebhookEvent
+
+	// MaxAttempts is the max number of attempts to deliver an event,
+	// after which it is dropped. Defaults to 8.
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff are the min and max delays between
+	// attempts to deliver an event. The delay doubles after every failed
+	// attempt. Default to 1 second and 5 minutes. MaxBackoff is never
+	// lower than MinBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// HTTPClient is the client used to send the events. Defaults to a
+	// client with a 30 second timeout.
+	HTTPClient *http.Client
+}
+
+// withDefaults returns the config with the defaults of unset fields filled.
+func (cfg WebhookConfig) withDefaults() WebhookConfig {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = cfg.MinBackoff
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return cfg
+}
+
+// WebhookPayload is the JSON body of the requests sent to the webhook.
+type WebhookPayload struct {
+	// ID uniquely identifies the event, so that receivers can ignore
+	// events delivered more than once.
+	ID        string       `json:"id"`
+	Event     WebhookEvent `json:"event"`
+	Timestamp time.Time    `json:"timestamp"`
+	Order     *Order       `json:"order"`
+}
+
+// SignWebhookPayload returns the signature of the body of a webhook request,
+// as sent in the WebhookSignatureHeader.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type webhookDelivery struct {
+	event WebhookEvent
+	body  []byte
+}
+
+// webhookDispatcher delivers store events to the webhook, in the order they
+// were generated.
+type webhookDispatcher struct {
+	cfg   WebhookConfig
+	log   slog.Logger
+	queue chan webhookDelivery
+}
+
+func newWebhookDispatcher(cfg WebhookConfig, log slog.Logger) *webhookDispatcher {
+	return &webhookDispatcher{
+		cfg:   cfg.withDefaults(),
+		log:   log,
+		queue: make(chan webhookDelivery, webhookQueueSize),
+	}
+}
+
+// wants returns true if the event should be sent to the webhook.
+func (wd *webhookDispatcher) wants(event WebhookEvent) bool {
+	return len(wd.cfg.Events) == 0 || slices.Contains(wd.cfg.Events, event)
+}
+
+// enqueue queues the event for delivery. It does not block.
+func (wd *webhookDispatcher) enqueue(event WebhookEvent, order *Order) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		wd.log.Errorf("Unable to generate webhook event id: %v", err)
+		return
+	}
+	body, err := json.Marshal(&WebhookPayload{
+		ID:        hex.EncodeToString(id[:]),
+		Event:     event,
+		Timestamp: time.Now(),
+		Order:     order,
+	})
+	if err != nil {
+		wd.log.Errorf("Unable to encode webhook event: %v", err)
+		return
+	}
+
+	select {
+	case wd.queue <- webhookDelivery{event: event, body: body}:
+	default:
+		wd.log.Warnf("Webhook queue is full. Dropping %s event of order "+
+			"%s/%s", event, order.User.ShortLogID(), order.ID)
+	}
+}
+
+// post sends a single request to the webhook.
+func (wd *webhookDispatcher) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wd.cfg.URL,
+		bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(wd.cfg.Secret, body))
+	res, err := wd.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook replied with status %s", res.Status)
+	}
+	return nil
+}
+
+// deliver delivers the event, retrying with exponential backoff.
+func (wd *webhookDispatcher) deliver(ctx context.Context, d webhookDelivery) error {
+	backoff := wd.cfg.MinBackoff
+	for attempt := 1; ; attempt++ {
+		err := wd.post(ctx, d.body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= wd.cfg.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %v", attempt, err)
+		}
+		wd.log.Debugf("Attempt %d to deliver %s event to webhook failed: %v",
+			attempt, d.event, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > wd.cfg.MaxBackoff {
+			backoff = wd.cfg.MaxBackoff
+		}
+	}
+}
+
+// run delivers the queued events until the context is done.
+func (wd *webhookDispatcher) run(ctx context.Context) error {
+	for {
+		select {
+		case d := <-wd.queue:
+			if err := wd.deliver(ctx, d); ctx.Err() != nil {
+				return ctx.Err()
+			} else if err != nil {
+				wd.log.Errorf("Unable to deliver %s event to webhook: %v",
+					d.event, err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// emitWebhook sends the event about the order to the webhook, if one is
+// configured. The sent order includes the shipping address in plain text.
+//
+// This MUST be called with the store mutex held.
+func (s *Store) emitWebhook(event WebhookEvent, order *Order) {
+	if s.webhooks == nil || !s.webhooks.wants(event) {
+		return
+	}
+	orderCopy := *order
+	if err := s.loadOrderShipAddr(&orderCopy); err != nil {
+		s.log.Warnf("Unable to load shipping address for webhook: %v", err)
+	}
+	s.webhooks.enqueue(event, &orderCopy)
+}
@@ -0,0 +1,146 @@
+package simplestore
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/decred/slog"
+)
+
+// TestWebhookConfigDefaults tests the defaults of the webhook config.
+func TestWebhookConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     WebhookConfig
+		wantMin time.Duration
+		wantMax time.Duration
+	}{{
+		name:    "unset",
+		wantMin: time.Second,
+		wantMax: 5 * time.Minute,
+	}, {
+		name:    "only min backoff",
+		cfg:     WebhookConfig{MinBackoff: time.Minute},
+		wantMin: time.Minute,
+		wantMax: 5 * time.Minute,
+	}, {
+		name:    "min backoff above default max",
+		cfg:     WebhookConfig{MinBackoff: 10 * time.Minute},
+		wantMin: 10 * time.Minute,
+		wantMax: 10 * time.Minute,
+	}, {
+		name:    "max backoff below min",
+		cfg:     WebhookConfig{MinBackoff: time.Minute, MaxBackoff: time.Second},
+		wantMin: time.Minute,
+		wantMax: time.Minute,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg.withDefaults()
+			assert.DeepEqual(t, cfg.MinBackoff, tc.wantMin)
+			assert.DeepEqual(t, cfg.MaxBackoff, tc.wantMax)
+		})
+	}
+}
+
+// TestWebhookDelivery tests that events are sent signed to the webhook and
+// retried when the webhook does not reply with a 2xx status.
+func TestWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	const secret = "webhook secret"
+	type request struct {
+		body []byte
+		sig  string
+	}
+	reqChan := make(chan request, 10)
+	failures := 2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		reqChan <- request{body: body, sig: r.Header.Get(WebhookSignatureHeader)}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	wd := newWebhookDispatcher(WebhookConfig{
+		URL:        srv.URL,
+		Secret:     secret,
+		Events:     []WebhookEvent{WebhookOrderPlaced},
+		MinBackoff: 10 * time.Millisecond,
+	}, slog.Disabled)
+	assert.DeepEqual(t, wd.wants(WebhookOrderPlaced), true)
+	assert.DeepEqual(t, wd.wants(WebhookOrderPaid), false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- wd.run(ctx) }()
+
+	order := testLedgerOrder()
+	wd.enqueue(WebhookOrderPlaced, order)
+
+	// The event is sent until the webhook accepts it, always with the
+	// same body and a valid signature.
+	var first request
+	for i := 0; i < 3; i++ {
+		req := assert.ChanWritten(t, reqChan)
+		assert.DeepEqual(t, req.sig, SignWebhookPayload(secret, req.body))
+		if i == 0 {
+			first = req
+		}
+		assert.DeepEqual(t, req.body, first.body)
+	}
+	assert.ChanNotWritten(t, reqChan, 100*time.Millisecond)
+
+	var payload WebhookPayload
+	assert.NilErr(t, json.Unmarshal(first.body, &payload))
+	assert.DeepEqual(t, payload.Event, WebhookOrderPlaced)
+	assert.DeepEqual(t, payload.Order.ID, order.ID)
+	assert.DeepEqual(t, payload.Order.User, order.User)
+	if payload.ID == "" {
+		t.Fatal("webhook event does not have an id")
+	}
+
+	cancel()
+	assert.ErrorIs(t, assert.ChanWritten(t, runErr), context.Canceled)
+}
+
+// TestWebhookDeliveryGivesUp tests that events are dropped after the max
+// number of delivery attempts.
+func TestWebhookDeliveryGivesUp(t *testing.T) {
+	t.Parallel()
+
+	reqChan := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqChan <- struct{}{}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	wd := newWebhookDispatcher(WebhookConfig{
+		URL:         srv.URL,
+		MaxAttempts: 3,
+		MinBackoff:  10 * time.Millisecond,
+	}, slog.Disabled)
+	err := wd.deliver(context.Background(), webhookDelivery{
+		event: WebhookOrderPaid,
+		body:  []byte("{}"),
+	})
+	assert.NonNilErr(t, err)
+	for i := 0; i < 3; i++ {
+		assert.ChanWritten(t, reqChan)
+	}
+	assert.ChanNotWritten(t, reqChan, 50*time.Millisecond)
+}
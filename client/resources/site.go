@@ -0,0 +1,495 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/slog"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// frontMatterDelim is the line that starts and ends the front matter
+	// of site pages.
+	frontMatterDelim = "+++"
+
+	// siteIndexName is the base name of the pages that are served for
+	// the dir that contains them.
+	siteIndexName = "index"
+
+	// siteIndexTmpl is the name of the template, in the site root, used to
+	// render the generated index of dirs without an index page.
+	siteIndexTmpl = "_index.tmpl"
+
+	contentTypeMarkdown = "text/markdown"
+)
+
+// defaultSiteIndexTmpl is the template used to render the generated index of
+// dirs without an index page when the site does not define one.
+var defaultSiteIndexTmpl = template.Must(template.New("index").Parse(
+	`# {{ .Page.Title }}
+{{ range .Pages }}
+- [{{ .Title }}]({{ .Link }}){{ if not .Date.IsZero }} ({{ .Date.Format "2006-01-02" }}){{ end }}{{ with .Description }} - {{ . }}{{ end }}
+{{- else }}
+This page is empty.
+{{- end }}
+`))
+
+// siteFrontMatter is the metadata of a site page.
+type siteFrontMatter struct {
+	Title       string                 `toml:"title"`
+	Description string                 `toml:"description"`
+	Date        time.Time              `toml:"date"`
+	Draft       bool                   `toml:"draft"`
+	Meta        map[string]string      `toml:"meta"`
+	Params      map[string]interface{} `toml:"params"`
+}
+
+// parseFrontMatter splits the front matter from the content of a page. The
+// front matter is optional and is a TOML document between "+++" lines at the
+// start of the page.
+func parseFrontMatter(data []byte) (siteFrontMatter, []byte, error) {
+	var fm siteFrontMatter
+	start := []byte(frontMatterDelim + "\n")
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(data, start) {
+		return fm, data, nil
+	}
+	rest := data[len(start):]
+	end := bytes.Index(rest, []byte("\n"+frontMatterDelim+"\n"))
+	var content []byte
+	switch {
+	case end > -1:
+		content = rest[end+len(frontMatterDelim)+2:]
+	case bytes.HasSuffix(rest, []byte("\n"+frontMatterDelim)):
+		end = len(rest) - len(frontMatterDelim) - 1
+	default:
+		return fm, nil, errors.New("front matter is not terminated")
+	}
+	if err := toml.Unmarshal(rest[:end], &fm); err != nil {
+		return fm, nil, fmt.Errorf("invalid front matter: %v", err)
+	}
+	return fm, content, nil
+}
+
+// SitePage is a page of a site served by a DirectoryResource.
+type SitePage struct {
+	// Path is the path of the page, relative to the site.
+	Path []string
+
+	// Link is the absolute resource path of the page, including the
+	// prefix of the site.
+	Link string
+
+	// IsDir is true for the entries of dirs in the generated indexes.
+	IsDir bool
+
+	Title       string
+	Description string
+	Date        time.Time
+	Draft       bool
+
+	// Meta is added to the meta of the replies with the page.
+	Meta map[string]string
+
+	// Params are the free form parameters of the page.
+	Params map[string]interface{}
+
+	fname   string
+	dirPath []string
+	modTime time.Time
+	size    int64
+	content []byte
+	tmpl    *template.Template
+}
+
+// SiteContext is the data passed to the templates of a site.
+type SiteContext struct {
+	UID clientintf.UserID
+
+	// Path is the requested path, relative to the site.
+	Path []string
+
+	// Data is the data of the request.
+	Data []byte
+
+	// Page is the page being rendered.
+	Page *SitePage
+
+	// Pages are the pages and subdirs of the dir of the page, sorted from
+	// newest to oldest and then by title.
+	Pages []*SitePage
+}
+
+// DirectoryResource is a resource provider that serves a site from a dir of
+// markdown pages, templates and other files.
+//
+// Request paths are mapped to files of the dir:
+//
+//   - "a/b" is served from "a/b.md", "a/b.tmpl" or the file "a/b".
+//   - A dir is served from its "index.md" or "index.tmpl" page or, if it has
+//     neither, from a generated index of its pages and subdirs.
+//   - Files and dirs whose name starts with "_" or "." are not served.
+//
+// Pages (.md and .tmpl files) may start with a front matter, which is a TOML
+// document between "+++" lines, with the title, description, date, draft,
+// meta and params of the page. Drafts are neither served nor listed.
+//
+// The content of .tmpl pages is executed as a text/template with a
+// SiteContext. The generated indexes are rendered with the "_index.tmpl"
+// template of the site root, if it exists, or with a default template.
+//
+// Parsed pages are cached until their file changes.
+type DirectoryResource struct {
+	root   string
+	log    slog.Logger
+	prefix []string
+
+	mtx   sync.Mutex
+	cache map[string]*SitePage
+}
+
+// ServeDirectory returns a provider that serves the site in the root dir.
+func ServeDirectory(root string) *DirectoryResource {
+	return &DirectoryResource{
+		root:  root,
+		log:   slog.Disabled,
+		cache: make(map[string]*SitePage),
+	}
+}
+
+// SetLogger sets the logger of the provider.
+//
+// This must be called before the provider starts fulfilling requests.
+func (dr *DirectoryResource) SetLogger(log slog.Logger) {
+	dr.log = log
+}
+
+// SetPrefix sets the path the site is bound to in the router. The prefix is
+// stripped from request paths and added to the links of the generated indexes.
+//
+// This must be called before the provider starts fulfilling requests.
+func (dr *DirectoryResource) SetPrefix(prefix []string) {
+	dr.prefix = prefix
+}
+
+// link returns the absolute resource path of a path relative to the site.
+func (dr *DirectoryResource) link(path []string) string {
+	return "/" + strings.Join(append(dr.prefix[:len(dr.prefix):len(dr.prefix)], path...), "/")
+}
+
+// hidden returns true if the file or dir with the given name is not served.
+func hidden(name string) bool {
+	return strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")
+}
+
+// isPageFile returns true if the file is a page of the site.
+func isPageFile(fname string) bool {
+	ext := filepath.Ext(fname)
+	return ext == ".md" || ext == ".tmpl"
+}
+
+// loadPage loads the page in fname, using the cached page if the file did not
+// change.
+func (dr *DirectoryResource) loadPage(fname string) (*SitePage, error) {
+	fi, err := os.Stat(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	dr.mtx.Lock()
+	defer dr.mtx.Unlock()
+	if page := dr.cache[fname]; page != nil && page.modTime.Equal(fi.ModTime()) &&
+		page.size == fi.Size() {
+		return page, nil
+	}
+
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	fm, content, err := parseFrontMatter(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse page %s: %v", fname, err)
+	}
+
+	// Index pages are served for the path of their dir.
+	rel, err := filepath.Rel(dr.root, fname)
+	if err != nil {
+		return nil, err
+	}
+	rel = strings.TrimSuffix(filepath.ToSlash(rel), filepath.Ext(rel))
+	path := strings.Split(rel, "/")
+	dirPath := path[:len(path)-1]
+	if path[len(path)-1] == siteIndexName {
+		path = dirPath
+	}
+
+	page := &SitePage{
+		Path:        path,
+		Link:        dr.link(path),
+		Title:       fm.Title,
+		Description: fm.Description,
+		Date:        fm.Date,
+		Draft:       fm.Draft,
+		Meta:        fm.Meta,
+		Params:      fm.Params,
+		fname:       fname,
+		dirPath:     dirPath,
+		modTime:     fi.ModTime(),
+		size:        fi.Size(),
+		content:     content,
+	}
+	if page.Title == "" {
+		page.Title = strings.TrimSuffix(filepath.Base(fname), filepath.Ext(fname))
+	}
+	if filepath.Ext(fname) == ".tmpl" {
+		page.tmpl, err = template.New(filepath.Base(fname)).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse template %s: %v", fname, err)
+		}
+	}
+	dr.cache[fname] = page
+	return page, nil
+}
+
+// findPageFile returns the page file for the given filename without its
+// extension, or an empty string if there is no such page.
+func (dr *DirectoryResource) findPageFile(base string) string {
+	for _, ext := range []string{".md", ".tmpl"} {
+		fi, err := os.Stat(base + ext)
+		if err == nil && !fi.IsDir() {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// dirPages returns the pages and subdirs of the dir, excluding its index page
+// and drafts.
+func (dr *DirectoryResource) dirPages(dir string, path []string) ([]*SitePage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]*SitePage, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if hidden(name) {
+			continue
+		}
+		fname := filepath.Join(dir, name)
+		if entry.IsDir() {
+			entryPath := append(path[:len(path):len(path)], name)
+			page := &SitePage{
+				Path:  entryPath,
+				Link:  dr.link(entryPath),
+				IsDir: true,
+				Title: name,
+			}
+			if index := dr.findPageFile(filepath.Join(fname, siteIndexName)); index != "" {
+				p, err := dr.loadPage(index)
+				if err != nil {
+					dr.log.Warnf("Unable to load page %s: %v", index, err)
+				} else if p.Draft {
+					continue
+				} else {
+					page.Title, page.Description = p.Title, p.Description
+					page.Date = p.Date
+				}
+			}
+			pages = append(pages, page)
+			continue
+		}
+
+		if !isPageFile(name) {
+			continue
+		}
+		if strings.TrimSuffix(name, filepath.Ext(name)) == siteIndexName {
+			continue
+		}
+		page, err := dr.loadPage(fname)
+		if err != nil {
+			dr.log.Warnf("Unable to load page %s: %v", fname, err)
+			continue
+		}
+		if page.Draft {
+			continue
+		}
+		pages = append(pages, page)
+	}
+
+	sort.Slice(pages, func(i, j int) bool {
+		if !pages[i].Date.Equal(pages[j].Date) {
+			return pages[i].Date.After(pages[j].Date)
+		}
+		return pages[i].Title < pages[j].Title
+	})
+	return pages, nil
+}
+
+// renderPage renders a page of the site.
+func (dr *DirectoryResource) renderPage(uid clientintf.UserID, page *SitePage,
+	req *rpc.RMFetchResource, path []string) (*rpc.RMFetchResourceReply, error) {
+
+	if page.Draft {
+		return &rpc.RMFetchResourceReply{Status: rpc.ResourceStatusNotFound}, nil
+	}
+
+	meta := map[string]string{rpc.ResourceMetaContentType: contentTypeMarkdown}
+	for k, v := range page.Meta {
+		meta[k] = v
+	}
+
+	if page.tmpl == nil {
+		data := ProcessEmbeds(string(page.content), dr.root, dr.log)
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusOk,
+			Data:   []byte(data),
+			Meta:   meta,
+		}, nil
+	}
+
+	pages, err := dr.dirPages(filepath.Dir(page.fname), page.dirPath)
+	if err != nil {
+		return nil, err
+	}
+	tctx := &SiteContext{
+		UID:   uid,
+		Path:  path,
+		Data:  req.Data,
+		Page:  page,
+		Pages: pages,
+	}
+	var b bytes.Buffer
+	if err := page.tmpl.Execute(&b, tctx); err != nil {
+		return nil, fmt.Errorf("unable to render page %s: %v",
+			strescape.ResourcesPath(req.Path), err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusOk,
+		Data:   b.Bytes(),
+		Meta:   meta,
+	}, nil
+}
+
+// renderIndex renders the generated index of a dir without an index page.
+func (dr *DirectoryResource) renderIndex(uid clientintf.UserID, dir string,
+	req *rpc.RMFetchResource, path []string) (*rpc.RMFetchResourceReply, error) {
+
+	pages, err := dr.dirPages(dir, path)
+	if err != nil {
+		return nil, err
+	}
+	title := "Index"
+	if len(path) > 0 {
+		title = path[len(path)-1]
+	}
+	tctx := &SiteContext{
+		UID:   uid,
+		Path:  path,
+		Data:  req.Data,
+		Page:  &SitePage{Path: path, Link: dr.link(path), IsDir: true, Title: title},
+		Pages: pages,
+	}
+
+	tmpl := defaultSiteIndexTmpl
+	if custom, err := dr.loadPage(filepath.Join(dr.root, siteIndexTmpl)); err == nil {
+		tmpl = custom.tmpl
+	} else if !errors.Is(err, os.ErrNotExist) {
+		dr.log.Warnf("Unable to load site index template: %v", err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, tctx); err != nil {
+		return nil, fmt.Errorf("unable to render index of %s: %v",
+			strescape.ResourcesPath(req.Path), err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusOk,
+		Data:   b.Bytes(),
+		Meta:   map[string]string{rpc.ResourceMetaContentType: contentTypeMarkdown},
+	}, nil
+}
+
+// Fulfill is part of the Provider interface.
+func (dr *DirectoryResource) Fulfill(ctx context.Context, uid clientintf.UserID,
+	req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	notFound := &rpc.RMFetchResourceReply{Status: rpc.ResourceStatusNotFound}
+	if len(req.Path) < len(dr.prefix) {
+		return notFound, nil
+	}
+	path := make([]string, 0, len(req.Path)-len(dr.prefix))
+	for _, e := range req.Path[len(dr.prefix):] {
+		if e == "" {
+			continue
+		}
+		e = strescape.PathElement(e)
+		if hidden(e) {
+			return notFound, nil
+		}
+		path = append(path, e)
+	}
+
+	fname := filepath.Join(append([]string{dr.root}, path...)...)
+	fi, err := os.Stat(fname)
+	switch {
+	case err == nil && fi.IsDir():
+		if index := dr.findPageFile(filepath.Join(fname, siteIndexName)); index != "" {
+			page, err := dr.loadPage(index)
+			if err != nil {
+				return nil, err
+			}
+			return dr.renderPage(uid, page, req, path)
+		}
+		return dr.renderIndex(uid, fname, req, path)
+
+	case err == nil && isPageFile(fname):
+		page, err := dr.loadPage(fname)
+		if err != nil {
+			return nil, err
+		}
+		return dr.renderPage(uid, page, req, path)
+
+	case err == nil:
+		data, err := os.ReadFile(fname)
+		if err != nil {
+			return nil, err
+		}
+		var meta map[string]string
+		if ct := mime.TypeByExtension(filepath.Ext(fname)); ct != "" {
+			meta = map[string]string{rpc.ResourceMetaContentType: ct}
+		}
+		return &rpc.RMFetchResourceReply{
+			Status: rpc.ResourceStatusOk,
+			Data:   data,
+			Meta:   meta,
+		}, nil
+
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	if page := dr.findPageFile(fname); page != "" && len(path) > 0 {
+		p, err := dr.loadPage(page)
+		if err != nil {
+			return nil, err
+		}
+		return dr.renderPage(uid, p, req, path)
+	}
+	return notFound, nil
+}
@@ -0,0 +1,187 @@
+package resources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/internal/testutils"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// testSiteFiles are the files of the test site, keyed by their slash separated
+// path.
+var testSiteFiles = map[string]string{
+	"index.md": "# Home\n",
+	"about.md": "+++\ntitle = \"About Us\"\n[meta]\nx-page = \"about\"\n+++\n# About\n",
+	"contact.tmpl": "+++\ntitle = \"Contact\"\n+++\n" +
+		"{{ .Page.Title }}: {{ printf \"%s\" .Data }}",
+	"logo.png":          "png data",
+	"_private.md":       "private",
+	".hidden":           "hidden",
+	"_drafts/post.md":   "draft",
+	"blog/first.md":     "+++\ntitle = \"First\"\ndate = 2023-01-01T00:00:00Z\n+++\nfirst",
+	"blog/second.md":    "+++\ntitle = \"Second\"\ndate = 2023-02-01T00:00:00Z\ndescription = \"more\"\n+++\nsecond",
+	"blog/draft.md":     "+++\ntitle = \"Draft\"\ndraft = true\n+++\ndraft",
+	"blog/_notes.md":    "notes",
+	"docs/index.tmpl":   "{{ range .Pages }}{{ .Title }} {{ .Link }};{{ end }}",
+	"docs/guide.md":     "guide",
+	"docs/api/index.md": "+++\ntitle = \"API\"\n+++\napi",
+}
+
+// newTestSite returns a provider that serves the test site bound to the
+// "site" prefix.
+func newTestSite(t testing.TB) (*DirectoryResource, string) {
+	t.Helper()
+	root := testutils.TempTestDir(t, "site-")
+	for name, content := range testSiteFiles {
+		fname := filepath.Join(root, filepath.FromSlash(name))
+		assert.NilErr(t, os.MkdirAll(filepath.Dir(fname), 0o700))
+		assert.NilErr(t, os.WriteFile(fname, []byte(content), 0o600))
+	}
+	dr := ServeDirectory(root)
+	dr.SetPrefix([]string{"site"})
+	return dr, root
+}
+
+// fetchSite fetches the slash separated path from the site.
+func fetchSite(t testing.TB, dr *DirectoryResource, path string, data []byte) *rpc.RMFetchResourceReply {
+	t.Helper()
+	req := &rpc.RMFetchResource{Path: strings.Split(path, "/"), Data: data}
+	res, err := dr.Fulfill(context.Background(), clientintf.UserID{}, req)
+	assert.NilErr(t, err)
+	return res
+}
+
+// TestSitePaths tests the resolution of request paths to the files of a site.
+func TestSitePaths(t *testing.T) {
+	t.Parallel()
+
+	dr, _ := newTestSite(t)
+	tests := []struct {
+		path     string
+		wantData string
+		wantType string
+	}{{
+		path:     "site",
+		wantData: "# Home\n",
+		wantType: contentTypeMarkdown,
+	}, {
+		path:     "site/about",
+		wantData: "# About\n",
+		wantType: contentTypeMarkdown,
+	}, {
+		path:     "site/about.md",
+		wantData: "# About\n",
+		wantType: contentTypeMarkdown,
+	}, {
+		path:     "site/blog/first",
+		wantData: "first",
+		wantType: contentTypeMarkdown,
+	}, {
+		path:     "site/docs/api",
+		wantData: "api",
+		wantType: contentTypeMarkdown,
+	}, {
+		path:     "site/logo.png",
+		wantData: "png data",
+		wantType: "image/png",
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			res := fetchSite(t, dr, tc.path, nil)
+			assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+			assert.DeepEqual(t, string(res.Data), tc.wantData)
+			assert.DeepEqual(t, res.Meta[rpc.ResourceMetaContentType], tc.wantType)
+		})
+	}
+
+	// The meta of the front matter is added to the reply.
+	res := fetchSite(t, dr, "site/about", nil)
+	assert.DeepEqual(t, res.Meta["x-page"], "about")
+}
+
+// TestSiteHiddenFiles tests that hidden files, drafts and paths outside the
+// site are not served.
+func TestSiteHiddenFiles(t *testing.T) {
+	t.Parallel()
+
+	dr, root := newTestSite(t)
+	outside := filepath.Join(filepath.Dir(root), filepath.Base(root)+"-secret.md")
+	assert.NilErr(t, os.WriteFile(outside, []byte("secret"), 0o600))
+	t.Cleanup(func() { os.Remove(outside) })
+
+	for _, path := range []string{
+		"site/_private",
+		"site/_private.md",
+		"site/.hidden",
+		"site/_drafts/post",
+		"site/blog/_notes",
+		"site/blog/draft",
+		"site/missing",
+		"site/..",
+		"site/../" + filepath.Base(root) + "-secret",
+	} {
+		res := fetchSite(t, dr, path, nil)
+		if res.Status != rpc.ResourceStatusNotFound {
+			t.Fatalf("unexpected status %d fetching %q: %s", res.Status,
+				path, res.Data)
+		}
+	}
+}
+
+// TestSiteIndexes tests the indexes of dirs, both generated and with an index
+// template.
+func TestSiteIndexes(t *testing.T) {
+	t.Parallel()
+
+	dr, root := newTestSite(t)
+
+	// The generated index lists the pages from newest to oldest, without
+	// drafts and hidden pages.
+	res := fetchSite(t, dr, "site/blog", nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	want := "# blog\n\n" +
+		"- [Second](/site/blog/second) (2023-02-01) - more\n" +
+		"- [First](/site/blog/first) (2023-01-01)\n"
+	assert.DeepEqual(t, string(res.Data), want)
+
+	// Index templates are rendered with the pages and subdirs of their
+	// dir.
+	res = fetchSite(t, dr, "site/docs", nil)
+	assert.DeepEqual(t, string(res.Data), "API /site/docs/api;guide /site/docs/guide;")
+
+	// The generated indexes are rendered with the index template of the
+	// site, if there is one.
+	tmpl := "{{ .Page.Title }}:{{ range .Pages }} {{ .Title }}{{ end }}"
+	assert.NilErr(t, os.WriteFile(filepath.Join(root, siteIndexTmpl), []byte(tmpl), 0o600))
+	res = fetchSite(t, dr, "site/blog", nil)
+	assert.DeepEqual(t, string(res.Data), "blog: Second First")
+}
+
+// TestSiteFormSubmission tests that the data of requests is passed to the
+// templates of the pages they fetch.
+func TestSiteFormSubmission(t *testing.T) {
+	t.Parallel()
+
+	dr, root := newTestSite(t)
+	res := fetchSite(t, dr, "site/contact", []byte(`{"name":"bob"}`))
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	assert.DeepEqual(t, string(res.Data), `Contact: {"name":"bob"}`)
+
+	// Changed pages are reloaded.
+	fname := filepath.Join(root, "contact.tmpl")
+	newContent := "+++\ntitle = \"Contact Us\"\n+++\n{{ .Page.Title }} ({{ len .Data }} bytes)"
+	assert.NilErr(t, os.WriteFile(fname, []byte(newContent), 0o600))
+	future := time.Now().Add(time.Minute)
+	assert.NilErr(t, os.Chtimes(fname, future, future))
+	res = fetchSite(t, dr, "site/contact", []byte(`{}`))
+	assert.DeepEqual(t, string(res.Data), "Contact Us (2 bytes)")
+}
@@ -1,9 +1,12 @@
 package resources
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/companyzero/bisonrelay/client/clientintf"
 	"github.com/companyzero/bisonrelay/internal/strescape"
@@ -31,8 +34,17 @@ func (sr *StaticResource) Fulfill(ctx context.Context, uid clientintf.UserID,
 // FilesystemResource is a resource that returns data from a root dir in the
 // filesystem.
 type FilesystemResource struct {
-	root string
-	log  slog.Logger
+	root   string
+	log    slog.Logger
+	render RenderEngine
+}
+
+// PageContext is the data passed to the templates used to render pages of a
+// FilesystemResource.
+type PageContext struct {
+	UID  clientintf.UserID
+	Path []string
+	Data []byte
 }
 
 func NewFilesystemResource(root string, log slog.Logger) *FilesystemResource {
@@ -45,10 +57,32 @@ func NewFilesystemResource(root string, log slog.Logger) *FilesystemResource {
 	}
 }
 
+// SetRenderEngine sets the engine used to render pages. Requests for paths
+// (joined by "/") that have a template in the engine are rendered with it
+// instead of being read from the root dir.
+//
+// This must be called before the resource starts fulfilling requests.
+func (fr *FilesystemResource) SetRenderEngine(render RenderEngine) {
+	fr.render = render
+}
+
 // Fulfill is part of the Provider interface.
 func (fr *FilesystemResource) Fulfill(ctx context.Context, uid clientintf.UserID,
 	req *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
 
+	if name := strings.Join(req.Path, "/"); fr.render != nil && fr.render.Has(name) {
+		var b bytes.Buffer
+		tctx := &PageContext{UID: uid, Path: req.Path, Data: req.Data}
+		if err := fr.render.Render(&b, name, tctx); err != nil {
+			return nil, fmt.Errorf("unable to render page %s: %v",
+				strescape.ResourcesPath(req.Path), err)
+		}
+		return &rpc.RMFetchResourceReply{
+			Data:   b.Bytes(),
+			Status: rpc.ResourceStatusOk,
+		}, nil
+	}
+
 	escapedPath := make([]string, 0, 1+len(req.Path))
 	escapedPath = append(escapedPath, fr.root)
 	for _, e := range req.Path {
@@ -0,0 +1,759 @@
+// Package tickets is a resource provider that sells a fixed number of tickets
+// for events and issues signed ticket codes that are verified and redeemed by
+// the organizer at the door.
+package tickets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources"
+	"github.com/companyzero/bisonrelay/internal/jsonfile"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrlnd/lnrpc"
+	"github.com/decred/slog"
+	"github.com/pelletier/go-toml"
+)
+
+//go:embed template
+var defaultTemplates embed.FS
+
+const (
+	indexTmplFile    = "index.tmpl"
+	eventTmplFile    = "event.tmpl"
+	purchaseTmplFile = "purchase.tmpl"
+	mineTmplFile     = "mine.tmpl"
+
+	// eventsFile is the file, in the root dir, with the events on sale.
+	eventsFile = "events.toml"
+
+	// keyFile is the file, in the root dir, with the key used to sign
+	// ticket codes.
+	keyFile = "ticketkey"
+
+	purchasesDir = "purchases"
+
+	// invoiceValidity is how long the invoices of purchases are valid
+	// for. Tickets of purchases that are not paid in this time are
+	// released.
+	invoiceValidity = time.Hour
+
+	// maxTicketsPerPurchase is the max number of tickets bought at once.
+	maxTicketsPerPurchase = 10
+
+	// sigLen is the number of bytes of the signature in ticket codes.
+	sigLen = 10
+)
+
+var (
+	purchaseFnamePattern = jsonfile.MakeDecimalFilePattern("purchase-", ".json", false)
+
+	eventIDRegexp = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+	// ErrInvalidTicket is returned when verifying a code that is not a
+	// valid ticket code.
+	ErrInvalidTicket = errors.New("invalid ticket code")
+
+	// ErrTicketRedeemed is returned when redeeming a ticket that was
+	// already redeemed.
+	ErrTicketRedeemed = errors.New("ticket already redeemed")
+)
+
+// Event is an event with tickets on sale.
+type Event struct {
+	ID          string    `toml:"id"`
+	Title       string    `toml:"title"`
+	Description string    `toml:"description"`
+	Venue       string    `toml:"venue"`
+	Date        time.Time `toml:"date"`
+
+	// Price is the price of each ticket, in DCR.
+	Price float64 `toml:"price"`
+
+	// Capacity is the number of tickets for sale.
+	Capacity int `toml:"capacity"`
+
+	// Available is the number of tickets that are neither sold nor
+	// reserved by pending purchases. It is filled when the event is
+	// rendered.
+	Available int `toml:"-"`
+}
+
+type eventsFileData struct {
+	Events []*Event `toml:"events"`
+}
+
+// PurchaseStatus is the status of a purchase of tickets.
+type PurchaseStatus string
+
+const (
+	PurchasePending PurchaseStatus = "pending"
+	PurchasePaid    PurchaseStatus = "paid"
+)
+
+// Ticket is a ticket issued for a paid purchase.
+type Ticket struct {
+	Code       string     `json:"code"`
+	RedeemedTS *time.Time `json:"redeemed_ts,omitempty"`
+}
+
+// Purchase is a purchase of tickets of an event by a remote user.
+type Purchase struct {
+	ID        uint64            `json:"id"`
+	User      clientintf.UserID `json:"user"`
+	Event     string            `json:"event"`
+	Quantity  int               `json:"quantity"`
+	Amount    dcrutil.Amount    `json:"amount"`
+	Invoice   string            `json:"invoice"`
+	Status    PurchaseStatus    `json:"status"`
+	CreatedTS time.Time         `json:"created_ts"`
+	PaidTS    *time.Time        `json:"paid_ts,omitempty"`
+	Tickets   []Ticket          `json:"tickets,omitempty"`
+}
+
+// IsExpired returns true if the invoice of the purchase expired before it was
+// paid.
+func (p *Purchase) IsExpired() bool {
+	return p.Status == PurchasePending && time.Since(p.CreatedTS) > invoiceValidity
+}
+
+// TicketInfo is the result of verifying a ticket code.
+type TicketInfo struct {
+	Code       string
+	Event      *Event
+	Number     uint64
+	User       clientintf.UserID
+	PurchaseID uint64
+	RedeemedTS *time.Time
+}
+
+// Config is the configuration of a tickets provider.
+type Config struct {
+	// Root is the dir with the events file, the signing key and the
+	// purchases.
+	Root string
+
+	Log         slog.Logger
+	Client      *client.Client
+	LNPayClient *client.DcrlnPaymentClient
+
+	// Prefix is the path where the provider is bound in the resources
+	// router. Defaults to "tickets".
+	Prefix string
+
+	// RenderEngine, if set, is used to render the pages instead of the
+	// default templates. Templates that are not defined in the engine are
+	// rendered with the default templates.
+	RenderEngine resources.RenderEngine
+
+	// TicketsIssued is called when the tickets of a purchase are issued.
+	TicketsIssued func(p *Purchase)
+}
+
+// Provider is the resource provider that sells tickets.
+type Provider struct {
+	cfg    Config
+	log    slog.Logger
+	render resources.RenderEngine
+	key    []byte
+
+	mtx       sync.Mutex
+	events    map[string]*Event
+	purchases map[uint64]*Purchase
+
+	// issued is the number of tickets issued per event, which is also the
+	// number of the last ticket of the event.
+	issued map[string]uint64
+}
+
+// loadKey loads the key used to sign ticket codes, generating it if needed.
+func loadKey(fname string) ([]byte, error) {
+	b, err := os.ReadFile(fname)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(b)))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("invalid ticket key in %s", fname)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(fname, []byte(hex.EncodeToString(key)), 0o600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// loadEvents loads the events file.
+func loadEvents(fname string) (map[string]*Event, error) {
+	var data eventsFileData
+	b, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*Event{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := toml.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode events file: %v", err)
+	}
+	events := make(map[string]*Event, len(data.Events))
+	for _, e := range data.Events {
+		switch {
+		case !eventIDRegexp.MatchString(e.ID):
+			return nil, fmt.Errorf("event id %q is not valid (use "+
+				"only lowercase letters, digits, '-' and '_')", e.ID)
+		case events[e.ID] != nil:
+			return nil, fmt.Errorf("duplicate event id %q", e.ID)
+		case e.Capacity <= 0:
+			return nil, fmt.Errorf("event %q has no tickets for sale", e.ID)
+		case e.Price <= 0:
+			return nil, fmt.Errorf("event %q has an invalid price", e.ID)
+		}
+		events[e.ID] = e
+	}
+	return events, nil
+}
+
+// New creates a new tickets provider.
+func New(cfg Config) (*Provider, error) {
+	log := slog.Disabled
+	if cfg.Log != nil {
+		log = cfg.Log
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "tickets"
+	}
+	if err := os.MkdirAll(filepath.Join(cfg.Root, purchasesDir), 0o700); err != nil {
+		return nil, err
+	}
+
+	var render resources.RenderEngine
+	render, err := resources.ParseTextTemplatesFS(defaultTemplates, nil, "template/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RenderEngine != nil {
+		render = resources.FallbackEngine{cfg.RenderEngine, render}
+	}
+
+	key, err := loadKey(filepath.Join(cfg.Root, keyFile))
+	if err != nil {
+		return nil, err
+	}
+	events, err := loadEvents(filepath.Join(cfg.Root, eventsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		cfg:       cfg,
+		log:       log,
+		render:    render,
+		key:       key,
+		events:    events,
+		purchases: make(map[uint64]*Purchase),
+		issued:    make(map[string]uint64),
+	}
+	if err := p.loadPurchases(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) purchaseFname(id uint64) string {
+	return filepath.Join(p.cfg.Root, purchasesDir, purchaseFnamePattern.FilenameFor(id))
+}
+
+// loadPurchases loads all purchases and the number of tickets issued for each
+// event.
+func (p *Provider) loadPurchases() error {
+	dir := filepath.Join(p.cfg.Root, purchasesDir)
+	files, err := purchaseFnamePattern.MatchFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		pur := new(Purchase)
+		if err := jsonfile.Read(filepath.Join(dir, f.Filename), pur); err != nil {
+			p.log.Warnf("Unable to read purchase %s: %v", f.Filename, err)
+			continue
+		}
+		p.purchases[pur.ID] = pur
+		for _, t := range pur.Tickets {
+			_, n, _, err := splitCode(t.Code)
+			if err == nil && n > p.issued[pur.Event] {
+				p.issued[pur.Event] = n
+			}
+		}
+	}
+	return nil
+}
+
+// available returns the number of tickets of the event that are neither sold
+// nor reserved.
+//
+// This MUST be called with the mutex held.
+func (p *Provider) available(e *Event) int {
+	taken := 0
+	for _, pur := range p.purchases {
+		if pur.Event != e.ID {
+			continue
+		}
+		if pur.Status == PurchasePaid || !pur.IsExpired() {
+			taken += pur.Quantity
+		}
+	}
+	if taken > e.Capacity {
+		return 0
+	}
+	return e.Capacity - taken
+}
+
+// sign returns the signature of a ticket code.
+func (p *Provider) sign(event string, n uint64) string {
+	mac := hmac.New(sha256.New, p.key)
+	fmt.Fprintf(mac, "%s:%d", event, n)
+	return hex.EncodeToString(mac.Sum(nil)[:sigLen])
+}
+
+// splitCode splits a ticket code in the format "<event>:<number>:<sig>".
+func splitCode(code string) (string, uint64, string, error) {
+	parts := strings.Split(strings.TrimSpace(code), ":")
+	if len(parts) != 3 {
+		return "", 0, "", ErrInvalidTicket
+	}
+	n, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, "", ErrInvalidTicket
+	}
+	return parts[0], n, parts[2], nil
+}
+
+// newPurchase reserves tickets of the event and creates the invoice to pay
+// for them.
+func (p *Provider) newPurchase(ctx context.Context, uid clientintf.UserID,
+	eventID string, qty int) (*Purchase, string, error) {
+
+	if p.cfg.LNPayClient == nil {
+		return nil, "", fmt.Errorf("LN not setup")
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	e := p.events[eventID]
+	if e == nil {
+		return nil, "Event not found", nil
+	}
+	if avail := p.available(e); qty > avail {
+		if avail == 0 {
+			return nil, "The event is sold out", nil
+		}
+		return nil, fmt.Sprintf("Only %d tickets are available", avail), nil
+	}
+	price, err := dcrutil.NewAmount(e.Price)
+	if err != nil {
+		return nil, "", err
+	}
+	amount := price * dcrutil.Amount(qty)
+	invoice, err := p.cfg.LNPayClient.GetInvoice(ctx, int64(amount)*1000, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to generate invoice: %v", err)
+	}
+
+	fname, id, err := purchaseFnamePattern.NextFilename(filepath.Join(p.cfg.Root, purchasesDir))
+	if err != nil {
+		return nil, "", err
+	}
+	pur := &Purchase{
+		ID:        id,
+		User:      uid,
+		Event:     eventID,
+		Quantity:  qty,
+		Amount:    amount,
+		Invoice:   invoice,
+		Status:    PurchasePending,
+		CreatedTS: time.Now(),
+	}
+	if err := jsonfile.Write(fname, pur, p.log); err != nil {
+		return nil, "", err
+	}
+	p.purchases[id] = pur
+	p.log.Infof("User %s reserved %d tickets of event %s (purchase %d)",
+		uid.ShortLogID(), qty, eventID, id)
+	return pur, "", nil
+}
+
+// purchasePaid issues the tickets of the purchase with the invoice.
+func (p *Provider) purchasePaid(invoice string) {
+	p.mtx.Lock()
+	var pur *Purchase
+	for _, v := range p.purchases {
+		if v.Invoice == invoice && v.Status == PurchasePending {
+			pur = v
+			break
+		}
+	}
+	if pur == nil {
+		p.mtx.Unlock()
+		return
+	}
+
+	// Tickets are issued even if the invoice was paid after the purchase
+	// expired, because the buyer paid for them.
+	now := time.Now()
+	upd := *pur
+	upd.Status = PurchasePaid
+	upd.PaidTS = &now
+	upd.Tickets = make([]Ticket, 0, pur.Quantity)
+	n := p.issued[pur.Event]
+	for i := 0; i < pur.Quantity; i++ {
+		n++
+		code := fmt.Sprintf("%s:%d:%s", pur.Event, n, p.sign(pur.Event, n))
+		upd.Tickets = append(upd.Tickets, Ticket{Code: code})
+	}
+	if err := jsonfile.Write(p.purchaseFname(pur.ID), &upd, p.log); err != nil {
+		p.mtx.Unlock()
+		p.log.Errorf("Unable to save paid purchase %d: %v", pur.ID, err)
+		return
+	}
+	p.issued[pur.Event] = n
+	*pur = upd
+	title := pur.Event
+	if e := p.events[pur.Event]; e != nil {
+		title = e.Title
+	}
+	p.mtx.Unlock()
+
+	p.log.Infof("Issued %d tickets of event %s to user %s (purchase %d)",
+		upd.Quantity, upd.Event, upd.User.ShortLogID(), upd.ID)
+	if p.cfg.Client != nil {
+		var b strings.Builder
+		fmt.Fprintf(&b, "Thank you for your purchase! Your tickets for %q:\n",
+			title)
+		for _, t := range upd.Tickets {
+			fmt.Fprintf(&b, "%s\n", t.Code)
+		}
+		b.WriteString("Show the ticket codes at the door.")
+		if err := p.cfg.Client.PM(upd.User, b.String()); err != nil {
+			p.log.Warnf("Unable to send tickets of purchase %d to "+
+				"user %s: %v", upd.ID, upd.User.ShortLogID(), err)
+		}
+	}
+	if p.cfg.TicketsIssued != nil {
+		p.cfg.TicketsIssued(&upd)
+	}
+}
+
+// findTicket returns the purchase and index of the ticket with the code.
+//
+// This MUST be called with the mutex held.
+func (p *Provider) findTicket(code string) (*Purchase, int, error) {
+	event, n, sig, err := splitCode(code)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !hmac.Equal([]byte(sig), []byte(p.sign(event, n))) {
+		return nil, 0, ErrInvalidTicket
+	}
+	code = strings.TrimSpace(code)
+	for _, pur := range p.purchases {
+		if pur.Event != event {
+			continue
+		}
+		for i := range pur.Tickets {
+			if pur.Tickets[i].Code == code {
+				return pur, i, nil
+			}
+		}
+	}
+	return nil, 0, ErrInvalidTicket
+}
+
+func (p *Provider) ticketInfo(pur *Purchase, i int) *TicketInfo {
+	_, n, _, _ := splitCode(pur.Tickets[i].Code)
+	return &TicketInfo{
+		Code:       pur.Tickets[i].Code,
+		Event:      p.events[pur.Event],
+		Number:     n,
+		User:       pur.User,
+		PurchaseID: pur.ID,
+		RedeemedTS: pur.Tickets[i].RedeemedTS,
+	}
+}
+
+// VerifyTicket verifies the ticket code, without redeeming it.
+func (p *Provider) VerifyTicket(code string) (*TicketInfo, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	pur, i, err := p.findTicket(code)
+	if err != nil {
+		return nil, err
+	}
+	return p.ticketInfo(pur, i), nil
+}
+
+// RedeemTicket verifies the ticket code and marks the ticket as redeemed. It
+// returns ErrTicketRedeemed (along with the ticket info) if the ticket was
+// already redeemed.
+func (p *Provider) RedeemTicket(code string) (*TicketInfo, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	pur, i, err := p.findTicket(code)
+	if err != nil {
+		return nil, err
+	}
+	if pur.Tickets[i].RedeemedTS != nil {
+		return p.ticketInfo(pur, i), ErrTicketRedeemed
+	}
+
+	now := time.Now()
+	upd := *pur
+	upd.Tickets = append([]Ticket(nil), pur.Tickets...)
+	upd.Tickets[i].RedeemedTS = &now
+	if err := jsonfile.Write(p.purchaseFname(pur.ID), &upd, p.log); err != nil {
+		return nil, err
+	}
+	*pur = upd
+	p.log.Infof("Redeemed ticket %s", upd.Tickets[i].Code)
+	return p.ticketInfo(pur, i), nil
+}
+
+// Events returns the events on sale, sorted by date.
+func (p *Provider) Events() []Event {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	res := make([]Event, 0, len(p.events))
+	for _, e := range p.events {
+		ev := *e
+		ev.Available = p.available(e)
+		res = append(res, ev)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Date.Equal(res[j].Date) {
+			return res[i].Date.Before(res[j].Date)
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res
+}
+
+// Run watches the invoices of the purchases until the context is done.
+func (p *Provider) Run(ctx context.Context) error {
+	if p.cfg.LNPayClient == nil {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	stream, err := p.cfg.LNPayClient.LNRPC().SubscribeInvoices(ctx, &lnrpc.InvoiceSubscription{})
+	if err != nil {
+		return err
+	}
+
+	// Issue the tickets of purchases paid while the provider was not
+	// running.
+	p.mtx.Lock()
+	var invoices []string
+	for _, pur := range p.purchases {
+		if pur.Status == PurchasePending {
+			invoices = append(invoices, pur.Invoice)
+		}
+	}
+	p.mtx.Unlock()
+	for _, inv := range invoices {
+		if err := p.cfg.LNPayClient.IsInvoicePaid(ctx, 0, inv); err == nil {
+			p.purchasePaid(inv)
+		}
+	}
+
+	for {
+		inv, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if inv.State == lnrpc.Invoice_SETTLED {
+			p.purchasePaid(inv.PaymentRequest)
+		}
+	}
+}
+
+type indexContext struct {
+	Prefix string
+	Events []Event
+}
+
+type eventContext struct {
+	Event
+	Prefix string
+	Max    int
+}
+
+type purchaseContext struct {
+	*Purchase
+	Prefix string
+	Title  string
+}
+
+type mineContext struct {
+	Prefix    string
+	Purchases []purchaseContext
+}
+
+func (p *Provider) renderPage(tmplFile string, tmplCtx interface{}) (*rpc.RMFetchResourceReply, error) {
+	w := &bytes.Buffer{}
+	if err := p.render.Render(w, tmplFile, tmplCtx); err != nil {
+		return nil, fmt.Errorf("unable to execute tickets template: %v", err)
+	}
+	return &rpc.RMFetchResourceReply{
+		Data:   w.Bytes(),
+		Status: rpc.ResourceStatusOk,
+	}, nil
+}
+
+func badRequest(msg string) *rpc.RMFetchResourceReply {
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusBadRequest,
+		Data:   []byte(msg),
+	}
+}
+
+func notFound() *rpc.RMFetchResourceReply {
+	return &rpc.RMFetchResourceReply{
+		Status: rpc.ResourceStatusNotFound,
+	}
+}
+
+func (p *Provider) handleEvent(eventID string) (*rpc.RMFetchResourceReply, error) {
+	for _, e := range p.Events() {
+		if e.ID != eventID {
+			continue
+		}
+		max := e.Available
+		if max > maxTicketsPerPurchase {
+			max = maxTicketsPerPurchase
+		}
+		return p.renderPage(eventTmplFile, &eventContext{Event: e, Prefix: p.cfg.Prefix, Max: max})
+	}
+	return notFound(), nil
+}
+
+func (p *Provider) purchaseContext(pur *Purchase) purchaseContext {
+	title := pur.Event
+	if e := p.events[pur.Event]; e != nil {
+		title = e.Title
+	}
+	return purchaseContext{Purchase: pur, Prefix: p.cfg.Prefix, Title: title}
+}
+
+// handleBuy reserves the tickets requested in the form data.
+func (p *Provider) handleBuy(ctx context.Context, uid clientintf.UserID,
+	data []byte) (*rpc.RMFetchResourceReply, error) {
+
+	fd, err := resources.ParseFormData(data)
+	if err != nil {
+		return badRequest("request data not valid json"), nil
+	}
+	qty, err := fd.Int("qty")
+	if err != nil || qty < 1 || qty > maxTicketsPerPurchase {
+		return badRequest(fmt.Sprintf("The number of tickets must be "+
+			"between 1 and %d", maxTicketsPerPurchase)), nil
+	}
+
+	pur, msg, err := p.newPurchase(ctx, uid, fd.String("event"), int(qty))
+	if err != nil {
+		return nil, err
+	}
+	if msg != "" {
+		return badRequest(msg), nil
+	}
+	p.mtx.Lock()
+	tmplCtx := p.purchaseContext(pur)
+	p.mtx.Unlock()
+	return p.renderPage(purchaseTmplFile, &tmplCtx)
+}
+
+// handlePurchase shows a purchase of the user.
+func (p *Provider) handlePurchase(uid clientintf.UserID, idStr string) (*rpc.RMFetchResourceReply, error) {
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return badRequest("invalid purchase id"), nil
+	}
+	p.mtx.Lock()
+	pur := p.purchases[id]
+	if pur == nil || pur.User != uid {
+		p.mtx.Unlock()
+		return notFound(), nil
+	}
+	purCopy := *pur
+	tmplCtx := p.purchaseContext(&purCopy)
+	p.mtx.Unlock()
+	return p.renderPage(purchaseTmplFile, &tmplCtx)
+}
+
+// handleMine lists the paid purchases of the user.
+func (p *Provider) handleMine(uid clientintf.UserID) (*rpc.RMFetchResourceReply, error) {
+	tmplCtx := &mineContext{Prefix: p.cfg.Prefix}
+	p.mtx.Lock()
+	for _, pur := range p.purchases {
+		if pur.User == uid && pur.Status == PurchasePaid {
+			purCopy := *pur
+			tmplCtx.Purchases = append(tmplCtx.Purchases, p.purchaseContext(&purCopy))
+		}
+	}
+	p.mtx.Unlock()
+	sort.Slice(tmplCtx.Purchases, func(i, j int) bool {
+		return tmplCtx.Purchases[i].ID > tmplCtx.Purchases[j].ID
+	})
+	return p.renderPage(mineTmplFile, tmplCtx)
+}
+
+// Fulfill is part of the resources.Provider interface.
+func (p *Provider) Fulfill(ctx context.Context, uid clientintf.UserID,
+	request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
+
+	path := request.Path
+	if len(path) > 0 && path[0] == p.cfg.Prefix {
+		path = path[1:]
+	}
+	switch {
+	case len(path) == 0:
+		return p.renderPage(indexTmplFile, &indexContext{
+			Prefix: p.cfg.Prefix,
+			Events: p.Events(),
+		})
+	case path[0] == "event" && len(path) == 2:
+		return p.handleEvent(path[1])
+	case path[0] == "buy" && len(path) == 1:
+		return p.handleBuy(ctx, uid, request.Data)
+	case path[0] == "purchase" && len(path) == 2:
+		return p.handlePurchase(uid, path[1])
+	case path[0] == "mine" && len(path) == 1:
+		return p.handleMine(uid)
+	default:
+		return notFound(), nil
+	}
+}
@@ -0,0 +1,194 @@
+package tickets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/internal/testutils"
+)
+
+const testEvents = `
+[[events]]
+id = "concert"
+title = "Concert"
+price = 0.5
+capacity = 3
+
+[[events]]
+id = "play"
+title = "Play"
+price = 1.0
+capacity = 2
+`
+
+// newTestProvider returns a provider with the test events in a new root dir.
+func newTestProvider(t testing.TB) *Provider {
+	t.Helper()
+	root := testutils.TempTestDir(t, "tickets-")
+	err := os.WriteFile(filepath.Join(root, eventsFile), []byte(testEvents), 0o600)
+	assert.NilErr(t, err)
+	p, err := New(Config{Root: root})
+	assert.NilErr(t, err)
+	return p
+}
+
+// addPaidPurchase adds a pending purchase of the event and marks it as paid,
+// returning the tickets issued for it.
+func addPaidPurchase(t testing.TB, p *Provider, event string, qty int) []Ticket {
+	t.Helper()
+	p.mtx.Lock()
+	id := uint64(len(p.purchases) + 1)
+	invoice := fmt.Sprintf("invoice-%d", id)
+	p.purchases[id] = &Purchase{
+		ID:        id,
+		Event:     event,
+		Quantity:  qty,
+		Invoice:   invoice,
+		Status:    PurchasePending,
+		CreatedTS: time.Now(),
+	}
+	p.mtx.Unlock()
+
+	var issued *Purchase
+	p.cfg.TicketsIssued = func(pur *Purchase) { issued = pur }
+	p.purchasePaid(invoice)
+	if issued == nil {
+		t.Fatalf("tickets of purchase %d not issued", id)
+	}
+	assert.DeepEqual(t, issued.Status, PurchasePaid)
+	assert.DeepEqual(t, len(issued.Tickets), qty)
+	return issued.Tickets
+}
+
+// TestTicketCodes tests that the codes of issued tickets are numbered per
+// event and signed with the key of the provider.
+func TestTicketCodes(t *testing.T) {
+	p := newTestProvider(t)
+	concert := addPaidPurchase(t, p, "concert", 2)
+	play := addPaidPurchase(t, p, "play", 1)
+	concert = append(concert, addPaidPurchase(t, p, "concert", 1)...)
+
+	for i, tk := range concert {
+		event, n, sig, err := splitCode(tk.Code)
+		assert.NilErr(t, err)
+		assert.DeepEqual(t, event, "concert")
+		assert.DeepEqual(t, n, uint64(i+1))
+		assert.DeepEqual(t, sig, p.sign(event, n))
+		assert.DeepEqual(t, len(sig), sigLen*2)
+	}
+	_, n, _, err := splitCode(play[0].Code)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, n, uint64(1))
+
+	// Signatures depend on the event, the number and the key.
+	if p.sign("concert", 1) == p.sign("concert", 2) ||
+		p.sign("concert", 1) == p.sign("play", 1) {
+		t.Fatal("signatures of different tickets are equal")
+	}
+	other := newTestProvider(t)
+	if p.sign("concert", 1) == other.sign("concert", 1) {
+		t.Fatal("signatures with different keys are equal")
+	}
+
+	// Purchases are only paid once.
+	p.purchasePaid("invoice-1")
+	p.mtx.Lock()
+	assert.DeepEqual(t, p.issued["concert"], uint64(3))
+	p.mtx.Unlock()
+}
+
+// TestFindTicket tests that only the codes of issued tickets with valid
+// signatures are found.
+func TestFindTicket(t *testing.T) {
+	p := newTestProvider(t)
+	tickets := addPaidPurchase(t, p, "concert", 2)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for i, tk := range tickets {
+		pur, idx, err := p.findTicket(tk.Code)
+		assert.NilErr(t, err)
+		assert.DeepEqual(t, pur.ID, uint64(1))
+		assert.DeepEqual(t, idx, i)
+
+		// Surrounding spaces are ignored.
+		_, idx, err = p.findTicket(" " + tk.Code + "\n")
+		assert.NilErr(t, err)
+		assert.DeepEqual(t, idx, i)
+	}
+
+	tests := []struct {
+		name string
+		code string
+	}{
+		{name: "empty", code: ""},
+		{name: "missing sig", code: "concert:1"},
+		{name: "extra field", code: tickets[0].Code + ":1"},
+		{name: "invalid number", code: "concert:one:" + p.sign("concert", 1)},
+		{name: "forged sig", code: "concert:1:" + strings.Repeat("00", sigLen)},
+		{name: "sig of another ticket", code: "concert:1:" + p.sign("concert", 2)},
+		{name: "sig of another event", code: "play:1:" + p.sign("concert", 1)},
+
+		// Validly signed codes of tickets that were not issued.
+		{name: "not issued", code: "concert:3:" + p.sign("concert", 3)},
+		{name: "unknown event", code: "party:1:" + p.sign("party", 1)},
+	}
+	for _, tc := range tests {
+		_, _, err := p.findTicket(tc.code)
+		if err != ErrInvalidTicket {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+// TestRedeemTicket tests that tickets are redeemed only once and that the
+// redemption is persisted.
+func TestRedeemTicket(t *testing.T) {
+	p := newTestProvider(t)
+	tickets := addPaidPurchase(t, p, "concert", 2)
+	code := tickets[0].Code
+
+	info, err := p.VerifyTicket(code)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, info.Number, uint64(1))
+	assert.DeepEqual(t, info.Event.ID, "concert")
+	if info.RedeemedTS != nil {
+		t.Fatal("ticket redeemed before redeeming")
+	}
+
+	info, err = p.RedeemTicket(code)
+	assert.NilErr(t, err)
+	if info.RedeemedTS == nil {
+		t.Fatal("redeemed ticket without redeemed time")
+	}
+	redeemedTS := *info.RedeemedTS
+
+	// Redeeming again fails but returns the info of the ticket.
+	info, err = p.RedeemTicket(code)
+	assert.ErrorIs(t, err, ErrTicketRedeemed)
+	assert.DeepEqual(t, info.RedeemedTS.Equal(redeemedTS), true)
+
+	// Forged codes are not redeemed.
+	_, err = p.RedeemTicket("concert:2:" + strings.Repeat("ab", sigLen))
+	assert.ErrorIs(t, err, ErrInvalidTicket)
+
+	// The redemption and the issued tickets persist after the provider
+	// is reloaded.
+	p2, err := New(Config{Root: p.cfg.Root})
+	assert.NilErr(t, err)
+	_, err = p2.RedeemTicket(code)
+	assert.ErrorIs(t, err, ErrTicketRedeemed)
+	info, err = p2.RedeemTicket(tickets[1].Code)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, info.Number, uint64(2))
+	assert.DeepEqual(t, p2.issued["concert"], uint64(2))
+
+	// The key was reloaded, so codes signed by the old provider are
+	// still valid.
+	assert.DeepEqual(t, p2.sign("concert", 1), p.sign("concert", 1))
+}
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"golang.org/x/time/rate"
+)
+
+// ResourceRateLimit is a token bucket rate limit applied to the resource
+// requests of each remote user.
+type ResourceRateLimit struct {
+	// Rate is the number of requests per second that are refilled in the
+	// bucket. A zero rate means requests are not limited.
+	Rate float64
+
+	// Burst is the maximum number of requests that can be made at once.
+	Burst int
+}
+
+// ResourceRateLimits configures the rate limits applied to incoming resource
+// requests.
+type ResourceRateLimits struct {
+	// Default is the rate limit applied to all requests that do not match
+	// any of the path overrides.
+	Default ResourceRateLimit
+
+	// Paths overrides the default rate limit for requests with paths that
+	// start with the given (slash separated) prefixes. The longest
+	// matching prefix is used. Each prefix has its own bucket.
+	Paths map[string]ResourceRateLimit
+
+	// Allow is the list of users whose requests are not rate limited.
+	Allow []clientintf.UserID
+
+	// Deny is the list of users that are not allowed to make requests.
+	Deny []clientintf.UserID
+
+	// OnAbuse is called (if set) whenever a request is rejected due to
+	// the deny list or the rate limits. It is called synchronously, so it
+	// should not block.
+	OnAbuse func(ev ResourceAbuseEvent)
+}
+
+// ResourceAbuseReason is the reason a resource request was rejected.
+type ResourceAbuseReason string
+
+const (
+	// ResourceAbuseDenied means the user is in the deny list.
+	ResourceAbuseDenied ResourceAbuseReason = "denied"
+
+	// ResourceAbuseRateLimited means the user exceeded the rate limit.
+	ResourceAbuseRateLimited ResourceAbuseReason = "rate-limited"
+)
+
+// ResourceAbuseEvent describes a rejected resource request.
+type ResourceAbuseEvent struct {
+	UID    clientintf.UserID
+	Path   []string
+	Reason ResourceAbuseReason
+
+	// Count is the number of requests from the user that were rejected in
+	// a row, including this one. It is reset once a request from the user
+	// is allowed.
+	Count int
+}
+
+// resourceLimiterKey identifies the bucket of a remote user for a path prefix
+// and limit.
+type resourceLimiterKey struct {
+	uid    clientintf.UserID
+	prefix string
+	limit  ResourceRateLimit
+}
+
+// maxResourceLimiters is the number of tracked buckets above which the idle
+// buckets are dropped.
+const maxResourceLimiters = 4096
+
+// resourceRateLimiter limits the rate of resource requests of remote users and
+// rejects the requests of denied users.
+type resourceRateLimiter struct {
+	limits      ResourceRateLimits
+	maxLimiters int
+	allowed     map[clientintf.UserID]struct{}
+	denied      map[clientintf.UserID]struct{}
+
+	mtx      sync.Mutex
+	limiters map[resourceLimiterKey]*rate.Limiter
+	rejected map[clientintf.UserID]int
+}
+
+func newResourceRateLimiter(limits ResourceRateLimits) *resourceRateLimiter {
+	rl := &resourceRateLimiter{
+		limits:      limits,
+		maxLimiters: maxResourceLimiters,
+		allowed:     make(map[clientintf.UserID]struct{}, len(limits.Allow)),
+		denied:      make(map[clientintf.UserID]struct{}, len(limits.Deny)),
+		limiters:    make(map[resourceLimiterKey]*rate.Limiter),
+		rejected:    make(map[clientintf.UserID]int),
+	}
+	for _, uid := range limits.Allow {
+		rl.allowed[uid] = struct{}{}
+	}
+	for _, uid := range limits.Deny {
+		rl.denied[uid] = struct{}{}
+	}
+	return rl
+}
+
+// pruneLimiters drops the buckets that are full, which behave the same as new
+// buckets.
+//
+// This MUST be called with the mutex held.
+func (rl *resourceRateLimiter) pruneLimiters(now time.Time) {
+	for key, lim := range rl.limiters {
+		if lim.TokensAt(now) >= float64(lim.Burst()) {
+			delete(rl.limiters, key)
+		}
+	}
+}
+
+// limitFor returns the path prefix and limit that apply to the path. If
+// defaultLimit is not nil, it is used instead of the configured default limit.
+func (rl *resourceRateLimiter) limitFor(path []string, defaultLimit *ResourceRateLimit) (string, ResourceRateLimit) {
+	fullPath := strings.Join(path, "/")
+	var prefix string
+	limit := rl.limits.Default
+	if defaultLimit != nil {
+		limit = *defaultLimit
+	}
+	bestLen := -1
+	for p, l := range rl.limits.Paths {
+		p = strings.Trim(p, "/")
+		if p != "" && fullPath != p && !strings.HasPrefix(fullPath, p+"/") {
+			continue
+		}
+		if len(p) > bestLen {
+			bestLen = len(p)
+			prefix, limit = p, l
+		}
+	}
+	return prefix, limit
+}
+
+// allow returns whether a request of the user for the path is allowed. When
+// it is not, it also returns the time after which the request could be made.
+// The defaultLimit (if not nil) overrides the configured default limit for
+// this user (for example, due to their trust tier).
+func (rl *resourceRateLimiter) allow(uid clientintf.UserID, path []string,
+	defaultLimit *ResourceRateLimit, now time.Time) (bool, time.Duration) {
+
+	if _, ok := rl.allowed[uid]; ok {
+		return true, 0
+	}
+	prefix, limit := rl.limitFor(path, defaultLimit)
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+
+	rl.mtx.Lock()
+	key := resourceLimiterKey{uid: uid, prefix: prefix, limit: limit}
+	lim := rl.limiters[key]
+	if lim == nil {
+		if len(rl.limiters) >= rl.maxLimiters {
+			rl.pruneLimiters(now)
+		}
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		lim = rate.NewLimiter(rate.Limit(limit.Rate), burst)
+		rl.limiters[key] = lim
+	}
+	rl.mtx.Unlock()
+
+	r := lim.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// check returns the reason the request of the user for the path should be
+// rejected (if any) and, for rate limited requests, the time after which the
+// request could be made. The abuse callback is called for rejected requests.
+func (rl *resourceRateLimiter) check(uid clientintf.UserID, path []string,
+	defaultLimit *ResourceRateLimit, now time.Time) (ResourceAbuseReason, time.Duration) {
+
+	var reason ResourceAbuseReason
+	var delay time.Duration
+	if _, ok := rl.denied[uid]; ok {
+		reason = ResourceAbuseDenied
+	} else if ok, d := rl.allow(uid, path, defaultLimit, now); !ok {
+		reason, delay = ResourceAbuseRateLimited, d
+	}
+
+	rl.mtx.Lock()
+	if reason == "" {
+		delete(rl.rejected, uid)
+		rl.mtx.Unlock()
+		return "", 0
+	}
+	if _, ok := rl.rejected[uid]; !ok && len(rl.rejected) >= rl.maxLimiters {
+		// The counts are only informative, so they are reset
+		// instead of growing without bound.
+		rl.rejected = make(map[clientintf.UserID]int)
+	}
+	rl.rejected[uid] += 1
+	ev := ResourceAbuseEvent{
+		UID:    uid,
+		Path:   path,
+		Reason: reason,
+		Count:  rl.rejected[uid],
+	}
+	rl.mtx.Unlock()
+
+	if rl.limits.OnAbuse != nil {
+		rl.limits.OnAbuse(ev)
+	}
+	return reason, delay
+}
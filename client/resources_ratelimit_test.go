@@ -0,0 +1,164 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// TestResourceRateLimiter tests the resource rate limiter applies the default
+// and per-path limits to each remote user.
+func TestResourceRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	rl := newResourceRateLimiter(ResourceRateLimits{
+		Default: ResourceRateLimit{Rate: 1, Burst: 2},
+		Paths: map[string]ResourceRateLimit{
+			"/static/":     {},
+			"admin":        {Rate: 0.1, Burst: 1},
+			"admin/orders": {Rate: 10, Burst: 5},
+		},
+	})
+
+	uid1, uid2 := clientintf.UserID{1: 1}, clientintf.UserID{1: 2}
+	uid3 := clientintf.UserID{1: 3}
+	tierLimit := &ResourceRateLimit{Rate: 0.5, Burst: 1}
+	now := time.Now()
+	tests := []struct {
+		name      string
+		uid       clientintf.UserID
+		path      []string
+		override  *ResourceRateLimit
+		when      time.Time
+		wantOk    bool
+		wantDelay time.Duration
+	}{
+		{"default 1st", uid1, []string{"index"}, nil, now, true, 0},
+		{"default 2nd", uid1, []string{"index"}, nil, now, true, 0},
+		{"default over burst", uid1, []string{"index"}, nil, now, false, time.Second},
+		{"default other user", uid2, []string{"index"}, nil, now, true, 0},
+		{"default after refill", uid1, []string{"index"}, nil, now.Add(time.Second), true, 0},
+		{"unlimited path 1st", uid1, []string{"static", "a.png"}, nil, now, true, 0},
+		{"unlimited path 2nd", uid1, []string{"static", "a.png"}, nil, now, true, 0},
+		{"unlimited path 3rd", uid1, []string{"static", "a.png"}, nil, now, true, 0},
+		{"admin 1st", uid1, []string{"admin"}, nil, now, true, 0},
+		{"admin over burst", uid1, []string{"admin", "index"}, nil, now, false, 10 * time.Second},
+		{"longest prefix", uid1, []string{"admin", "orders", "1"}, nil, now, true, 0},
+		{"prefix matches full elements", uid1, []string{"administrator"}, nil, now, false, time.Second},
+		{"override 1st", uid3, []string{"index"}, tierLimit, now, true, 0},
+		{"override over burst", uid3, []string{"index"}, tierLimit, now, false, 2 * time.Second},
+		{"override does not apply to paths", uid3, []string{"admin"}, tierLimit, now, true, 0},
+	}
+
+	for _, tc := range tests {
+		ok, delay := rl.allow(tc.uid, tc.path, tc.override, tc.when)
+		if ok != tc.wantOk {
+			t.Fatalf("%s: unexpected allow: got %v, want %v", tc.name,
+				ok, tc.wantOk)
+		}
+		if delay != tc.wantDelay {
+			t.Fatalf("%s: unexpected delay: got %v, want %v", tc.name,
+				delay, tc.wantDelay)
+		}
+	}
+}
+
+// TestResourceRateLimiterPrune tests that idle buckets are dropped once the
+// limiter tracks too many buckets.
+func TestResourceRateLimiterPrune(t *testing.T) {
+	t.Parallel()
+
+	rl := newResourceRateLimiter(ResourceRateLimits{
+		Default: ResourceRateLimit{Rate: 1, Burst: 1},
+	})
+	rl.maxLimiters = 2
+
+	uid1, uid2 := clientintf.UserID{1: 1}, clientintf.UserID{1: 2}
+	uid3 := clientintf.UserID{1: 3}
+	now := time.Now()
+	rl.allow(uid1, []string{"index"}, nil, now)
+	rl.allow(uid2, []string{"index"}, nil, now.Add(500*time.Millisecond))
+	if len(rl.limiters) != 2 {
+		t.Fatalf("unexpected nb of limiters: got %d, want 2", len(rl.limiters))
+	}
+
+	// The bucket of uid1 is full again, so it is dropped to make room for
+	// the bucket of uid3, while the bucket of uid2 is kept.
+	ok, _ := rl.allow(uid3, []string{"index"}, nil, now.Add(time.Second))
+	if !ok {
+		t.Fatal("request of new user not allowed")
+	}
+	if len(rl.limiters) != 2 {
+		t.Fatalf("unexpected nb of limiters: got %d, want 2", len(rl.limiters))
+	}
+	if ok, _ := rl.allow(uid2, []string{"index"}, nil, now.Add(time.Second)); ok {
+		t.Fatal("limit of user with a kept bucket was reset")
+	}
+}
+
+// TestResourceRateLimiterCheck tests the deny and allow lists of the resource
+// rate limiter and that rejected requests are reported as abuse.
+func TestResourceRateLimiterCheck(t *testing.T) {
+	t.Parallel()
+
+	denied, allowed := clientintf.UserID{1: 1}, clientintf.UserID{1: 2}
+	other := clientintf.UserID{1: 3}
+	var events []ResourceAbuseEvent
+	rl := newResourceRateLimiter(ResourceRateLimits{
+		Default: ResourceRateLimit{Rate: 1, Burst: 1},
+		Allow:   []clientintf.UserID{allowed},
+		Deny:    []clientintf.UserID{denied},
+		OnAbuse: func(ev ResourceAbuseEvent) { events = append(events, ev) },
+	})
+
+	now := time.Now()
+	path := []string{"index"}
+	tests := []struct {
+		name       string
+		uid        clientintf.UserID
+		when       time.Time
+		wantReason ResourceAbuseReason
+		wantDelay  time.Duration
+		wantCount  int
+	}{
+		{"denied 1st", denied, now, ResourceAbuseDenied, 0, 1},
+		{"denied 2nd", denied, now.Add(time.Hour), ResourceAbuseDenied, 0, 2},
+		{"allowed 1st", allowed, now, "", 0, 0},
+		{"allowed bypasses limit", allowed, now, "", 0, 0},
+		{"allowed bypasses limit again", allowed, now, "", 0, 0},
+		{"other 1st", other, now, "", 0, 0},
+		{"other over burst", other, now, ResourceAbuseRateLimited, time.Second, 1},
+		{"other over burst again", other, now.Add(500 * time.Millisecond),
+			ResourceAbuseRateLimited, 500 * time.Millisecond, 2},
+		{"other after refill", other, now.Add(time.Second), "", 0, 0},
+		{"other count reset", other, now.Add(time.Second), ResourceAbuseRateLimited,
+			time.Second, 1},
+	}
+
+	for _, tc := range tests {
+		nbEvents := len(events)
+		reason, delay := rl.check(tc.uid, path, nil, tc.when)
+		if reason != tc.wantReason {
+			t.Fatalf("%s: unexpected reason: got %q, want %q", tc.name,
+				reason, tc.wantReason)
+		}
+		if delay != tc.wantDelay {
+			t.Fatalf("%s: unexpected delay: got %v, want %v", tc.name,
+				delay, tc.wantDelay)
+		}
+		if tc.wantReason == "" {
+			if len(events) != nbEvents {
+				t.Fatalf("%s: allowed request reported as abuse", tc.name)
+			}
+			continue
+		}
+		if len(events) != nbEvents+1 {
+			t.Fatalf("%s: rejected request not reported as abuse", tc.name)
+		}
+		ev := events[len(events)-1]
+		if ev.UID != tc.uid || ev.Reason != tc.wantReason || ev.Count != tc.wantCount {
+			t.Fatalf("%s: unexpected abuse event: %+v", tc.name, ev)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"strings"
+	"sync"
+
+	genericlist "github.com/bahlo/generic-list-go"
+)
+
+// ResourceConcurrency configures how many incoming resource requests are
+// fulfilled at once. Requests are fulfilled by a pool of workers, outside the
+// processing of other received messages, so that slow providers do not delay
+// unrelated messages.
+type ResourceConcurrency struct {
+	// Workers is the max number of requests fulfilled at once. Defaults
+	// to 8.
+	Workers int
+
+	// MaxQueued is the max number of requests waiting for a worker.
+	// Requests received while the queue is full are replied with a "too
+	// many requests" status. Defaults to 100.
+	MaxQueued int
+
+	// Paths limits the number of requests fulfilled at once for requests
+	// with paths that start with the given (slash separated) prefixes,
+	// which are usually bound to a single provider. The longest matching
+	// prefix is used. Requests waiting on the limit of a prefix do not
+	// hold up requests for other prefixes.
+	Paths map[string]int
+}
+
+// resourceJob is a resource request waiting to be fulfilled.
+type resourceJob struct {
+	prefix string
+	limit  int
+	run    func()
+}
+
+// resourceWorkers schedules the fulfillment of resource requests, limiting
+// the number of requests fulfilled at once globally and per path prefix.
+type resourceWorkers struct {
+	cfg ResourceConcurrency
+
+	mtx      sync.Mutex
+	queue    *genericlist.List[*resourceJob]
+	running  int
+	byPrefix map[string]int
+}
+
+func newResourceWorkers(cfg ResourceConcurrency) *resourceWorkers {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 8
+	}
+	if cfg.MaxQueued <= 0 {
+		cfg.MaxQueued = 100
+	}
+	return &resourceWorkers{
+		cfg:      cfg,
+		queue:    genericlist.New[*resourceJob](),
+		byPrefix: make(map[string]int),
+	}
+}
+
+// limitFor returns the path prefix and concurrency limit that apply to the
+// path. A zero limit means only the global limit applies.
+func (rw *resourceWorkers) limitFor(path []string) (string, int) {
+	fullPath := strings.Join(path, "/")
+	var prefix string
+	var limit int
+	bestLen := -1
+	for p, l := range rw.cfg.Paths {
+		p = strings.Trim(p, "/")
+		if p != "" && fullPath != p && !strings.HasPrefix(fullPath, p+"/") {
+			continue
+		}
+		if len(p) > bestLen {
+			bestLen = len(p)
+			prefix, limit = p, l
+		}
+	}
+	return prefix, limit
+}
+
+// submit queues fn to be run for a request for the given path. It returns
+// false if the queue is full, in which case fn is not run.
+func (rw *resourceWorkers) submit(path []string, fn func()) bool {
+	prefix, limit := rw.limitFor(path)
+	rw.mtx.Lock()
+	defer rw.mtx.Unlock()
+	if rw.queue.Len() >= rw.cfg.MaxQueued {
+		return false
+	}
+	rw.queue.PushBack(&resourceJob{prefix: prefix, limit: limit, run: fn})
+	rw.dispatch()
+	return true
+}
+
+// dispatch starts the queued jobs, in order, that are allowed to run.
+//
+// This MUST be called with the mutex held.
+func (rw *resourceWorkers) dispatch() {
+	for el := rw.queue.Front(); el != nil && rw.running < rw.cfg.Workers; {
+		job := el.Value
+		next := el.Next()
+		if job.limit > 0 && rw.byPrefix[job.prefix] >= job.limit {
+			el = next
+			continue
+		}
+
+		rw.queue.Remove(el)
+		rw.running += 1
+		rw.byPrefix[job.prefix] += 1
+		go rw.runJob(job)
+		el = next
+	}
+}
+
+// runJob runs the job and starts the next queued jobs once it is done.
+func (rw *resourceWorkers) runJob(job *resourceJob) {
+	job.run()
+
+	rw.mtx.Lock()
+	rw.running -= 1
+	rw.byPrefix[job.prefix] -= 1
+	if rw.byPrefix[job.prefix] == 0 {
+		delete(rw.byPrefix, job.prefix)
+	}
+	rw.dispatch()
+	rw.mtx.Unlock()
+}
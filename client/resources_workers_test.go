@@ -0,0 +1,69 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+// TestResourceWorkers tests the global and per prefix concurrency limits of
+// the resource workers.
+func TestResourceWorkers(t *testing.T) {
+	t.Parallel()
+
+	rw := newResourceWorkers(ResourceConcurrency{
+		Workers:   3,
+		MaxQueued: 3,
+		Paths:     map[string]int{"store": 1},
+	})
+
+	// Block the store prefix with one slow request.
+	slowDone := make(chan struct{})
+	release := make(chan struct{})
+	if !rw.submit([]string{"store", "index"}, func() {
+		<-release
+		close(slowDone)
+	}) {
+		t.Fatal("request not queued")
+	}
+
+	// A second store request waits for the first one.
+	storeDone := make(chan struct{})
+	rw.submit([]string{"store", "cart"}, func() { close(storeDone) })
+
+	// Requests for other paths are not held up by the store requests.
+	otherDone := make(chan struct{})
+	rw.submit([]string{"pages"}, func() { close(otherDone) })
+	select {
+	case <-otherDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request for other path did not run")
+	}
+	select {
+	case <-storeDone:
+		t.Fatal("store request ran above its limit")
+	default:
+	}
+
+	// Fill the queue. Only the waiting store request and the following
+	// requests count towards it.
+	block := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		if !rw.submit([]string{"store", "x"}, func() { <-block }) {
+			t.Fatalf("request %d not queued", i)
+		}
+	}
+	if rw.submit([]string{"store", "y"}, func() {}) {
+		t.Fatal("request queued above max queue size")
+	}
+
+	// Releasing the slow request runs the waiting store request.
+	close(release)
+	for _, c := range []chan struct{}{slowDone, storeDone} {
+		select {
+		case <-c:
+		case <-time.After(5 * time.Second):
+			t.Fatal("store requests did not run")
+		}
+	}
+	close(block)
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"github.com/companyzero/bisonrelay/client/internal/lowlevel"
+	"github.com/companyzero/bisonrelay/client/tracing"
 	"github.com/companyzero/bisonrelay/ratchet"
 )
 
@@ -47,6 +48,9 @@ type remoteUserRM struct {
 	sendRV    lowlevel.RVID
 	ru        *RemoteUser
 	payEvent  string
+
+	// span is the span of sending the RM, when tracing is enabled.
+	span *tracing.Span
 }
 
 // Assert remoteUserRM fulfills the outboundRM interface.
@@ -68,6 +72,11 @@ func (rm *remoteUserRM) String() string {
 	return rm.payloadT
 }
 
+// TraceSpan is part of the lowlevel.TracedOutboundRM interface.
+func (rm *remoteUserRM) TraceSpan() *tracing.Span {
+	return rm.span
+}
+
 func (rm *remoteUserRM) PaidForRM(amount, fees int64) {
 	go rm.ru.paidForRM(rm.payEvent, amount, fees)
 }
@@ -63,6 +63,50 @@ func (c *chatServer) SendFile(_ context.Context, req *types.SendFileRequest, _ *
 	return c.c.SendFile(user.ID(), req.Filename)
 }
 
+func (c *chatServer) SaveQuery(_ context.Context, req *types.SaveQueryRequest, _ *types.SaveQueryResponse) error {
+	if req.Query == nil {
+		return fmt.Errorf("query is nil")
+	}
+	return c.c.SaveQuery(req.Query.Name, req.Query.Kind, req.Query.Terms)
+}
+
+func (c *chatServer) ListSavedQueries(_ context.Context, _ *types.ListSavedQueriesRequest, res *types.ListSavedQueriesResponse) error {
+	queries, err := c.c.SavedQueries()
+	if err != nil {
+		return err
+	}
+	res.Queries = make([]*types.SavedQuery, len(queries))
+	for i, q := range queries {
+		res.Queries[i] = &types.SavedQuery{
+			Name:    q.Name,
+			Kind:    q.Kind,
+			Terms:   q.Terms,
+			Created: q.Created.Unix(),
+		}
+	}
+	return nil
+}
+
+func (c *chatServer) RunSavedQuery(_ context.Context, req *types.RunSavedQueryRequest, res *types.RunSavedQueryResponse) error {
+	results, err := c.c.RunSavedQuery(req.Name)
+	if err != nil {
+		return err
+	}
+	res.Results = make([]*types.SavedQueryResult, len(results))
+	for i, r := range results {
+		res.Results[i] = &types.SavedQueryResult{
+			Timestamp: r.Timestamp.Unix(),
+			Source:    r.Source,
+			Text:      r.Text,
+		}
+	}
+	return nil
+}
+
+func (c *chatServer) RemoveSavedQuery(_ context.Context, req *types.RemoveSavedQueryRequest, _ *types.RemoveSavedQueryResponse) error {
+	return c.c.RemoveSavedQuery(req.Name)
+}
+
 func (c *chatServer) PM(ctx context.Context, req *types.PMRequest, res *types.PMResponse) error {
 	if req.Msg == nil {
 		return fmt.Errorf("msg is nil")
@@ -75,6 +75,32 @@ func (p *paymentsServer) AckTipProgress(_ context.Context, req *types.AckRequest
 	return p.tipProgressStreams.ack(req.SequenceId)
 }
 
+func (p *paymentsServer) ListPendingPayments(_ context.Context, _ *types.ListPendingPaymentsRequest, res *types.ListPendingPaymentsResponse) error {
+	approvals := p.c.PendingPaymentApprovals()
+	res.Payments = make([]*types.PendingPayment, len(approvals))
+	for i, a := range approvals {
+		res.Payments[i] = &types.PendingPayment{
+			Id:           a.ID,
+			Category:     string(a.Category),
+			Invoice:      a.Invoice,
+			AmountMatoms: a.MAtoms,
+			Requested:    a.Requested.Unix(),
+		}
+		if a.UID != nil {
+			res.Payments[i].Uid = a.UID.Bytes()
+		}
+	}
+	return nil
+}
+
+func (p *paymentsServer) ApprovePayment(_ context.Context, req *types.ApprovePaymentRequest, _ *types.ApprovePaymentResponse) error {
+	return p.c.ApprovePayment(req.Id)
+}
+
+func (p *paymentsServer) DenyPayment(_ context.Context, req *types.DenyPaymentRequest, _ *types.DenyPaymentResponse) error {
+	return p.c.DenyPayment(req.Id, req.Reason)
+}
+
 func (p *paymentsServer) registerOfflineMessageStorageHandlers() {
 	nmgr := p.c.NotificationManager()
 	nmgr.RegisterSync(client.OnTipAttemptProgressNtfn(p.tipProgressNtfnHandler))
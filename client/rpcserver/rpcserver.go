@@ -16,6 +16,10 @@ import (
 type Config struct {
 	JSONRPCListeners []net.Listener
 	Log              slog.Logger
+
+	// RESTGateway enables the REST mapping of the services in the
+	// JSON-RPC listeners.
+	RESTGateway bool
 }
 
 // Server is an RPC server for a corresponding BR Client instance.
@@ -40,11 +44,15 @@ func (s *Server) Run(ctx context.Context) error {
 
 func New(cfg Config) *Server {
 	services := new(types.ServersMap)
-	jsonServer := jsonrpc.NewServer(
+	opts := []jsonrpc.ServerOption{
 		jsonrpc.WithServices(services),
 		jsonrpc.WithListeners(cfg.JSONRPCListeners),
 		jsonrpc.WithServerLog(cfg.Log),
-	)
+	}
+	if cfg.RESTGateway {
+		opts = append(opts, jsonrpc.WithRESTGateway())
+	}
+	jsonServer := jsonrpc.NewServer(opts...)
 	s := &Server{
 		services:   services,
 		jsonServer: jsonServer,
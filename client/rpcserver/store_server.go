@@ -0,0 +1,249 @@
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources/simplestore"
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/decred/slog"
+)
+
+type StoreServerCfg struct {
+	// Store is the simplestore managed by the service.
+	Store *simplestore.Store
+
+	// Log should be set to the app's logger.
+	Log slog.Logger
+
+	// RootReplayMsgLogs is the root dir where replaymsglogs are stored for
+	// supported message types.
+	RootReplayMsgLogs string
+}
+
+// storeServer is the server side implementation of the
+// [types.StoreService].
+type storeServer struct {
+	store *simplestore.Store
+	log   slog.Logger
+
+	eventStreams *serverStreams[*types.StoreEvent]
+}
+
+var _ types.StoreServiceServer = (*storeServer)(nil)
+
+// storeProduct converts a product to its clientrpc representation.
+func storeProduct(prod *simplestore.Product) *types.StoreProduct {
+	res := &types.StoreProduct{
+		Sku:         prod.SKU,
+		Title:       prod.Title,
+		Description: prod.Description,
+		Tags:        prod.Tags,
+		Price:       prod.Price.Float(),
+		Category:    prod.Category,
+		Shipping:    prod.Shipping,
+		DigitalFile: prod.DigitalFile,
+		Archived:    prod.Disabled,
+	}
+	if prod.Stock != nil {
+		res.HasStock = true
+		res.Stock = *prod.Stock
+	}
+	return res
+}
+
+// productUpdate converts a clientrpc product to a product update.
+func productUpdate(prod *types.StoreProduct) (*simplestore.ProductUpdate, error) {
+	if prod == nil {
+		return nil, fmt.Errorf("product not specified")
+	}
+	upd := &simplestore.ProductUpdate{
+		SKU:         prod.Sku,
+		Title:       prod.Title,
+		Description: prod.Description,
+		Tags:        prod.Tags,
+		Price:       simplestore.MoneyFromFloat(prod.Price),
+		Category:    prod.Category,
+		Shipping:    prod.Shipping,
+		DigitalFile: prod.DigitalFile,
+	}
+	if prod.HasStock {
+		stock := prod.Stock
+		upd.Stock = &stock
+	}
+	return upd, nil
+}
+
+// storeItems converts cart items to their clientrpc representation.
+func storeItems(cartItems []*simplestore.CartItem) []*types.StoreOrderItem {
+	items := make([]*types.StoreOrderItem, 0, len(cartItems))
+	for _, item := range cartItems {
+		items = append(items, &types.StoreOrderItem{
+			Sku:      item.Product.SKU,
+			Title:    item.Product.Title,
+			Quantity: item.Quantity,
+			Price:    item.Product.Price.Float(),
+		})
+	}
+	return items
+}
+
+// storeOrder converts an order to its clientrpc representation.
+func storeOrder(order *simplestore.Order) *types.StoreOrder {
+	return &types.StoreOrder{
+		Id:         uint32(order.ID),
+		User:       order.User[:],
+		Status:     string(order.Status),
+		PlacedTs:   order.PlacedTS.Unix(),
+		Currency:   order.Currency,
+		TotalCents: int64(order.Total()),
+		PayType:    string(order.PayType),
+		Items:      storeItems(order.Cart.Items),
+	}
+}
+
+func (s *storeServer) ListProducts(_ context.Context, req *types.ListProductsRequest, res *types.ListProductsResponse) error {
+	products, err := s.store.Products(req.IncludeArchived)
+	if err != nil {
+		return err
+	}
+	res.Products = make([]*types.StoreProduct, len(products))
+	for i, prod := range products {
+		res.Products[i] = storeProduct(prod)
+	}
+	return nil
+}
+
+func (s *storeServer) AddProduct(_ context.Context, req *types.AddProductRequest, res *types.AddProductResponse) error {
+	upd, err := productUpdate(req.Product)
+	if err != nil {
+		return err
+	}
+	prod, err := s.store.AddProduct(upd)
+	if err != nil {
+		return err
+	}
+	res.Product = storeProduct(prod)
+	return nil
+}
+
+func (s *storeServer) UpdateProduct(_ context.Context, req *types.UpdateProductRequest, res *types.UpdateProductResponse) error {
+	upd, err := productUpdate(req.Product)
+	if err != nil {
+		return err
+	}
+	prod, err := s.store.UpdateProduct(upd)
+	if err != nil {
+		return err
+	}
+	res.Product = storeProduct(prod)
+	return nil
+}
+
+func (s *storeServer) ListOrders(_ context.Context, req *types.ListOrdersRequest, res *types.ListOrdersResponse) error {
+	status := simplestore.OrderStatus(req.Status)
+	if status != "" && !status.IsValid() {
+		return fmt.Errorf("unknown order status %q", req.Status)
+	}
+	orders, err := s.store.Orders(status)
+	if err != nil {
+		return err
+	}
+	res.Orders = make([]*types.StoreOrder, len(orders))
+	for i, order := range orders {
+		res.Orders[i] = storeOrder(order)
+	}
+	return nil
+}
+
+func (s *storeServer) UpdateOrderStatus(_ context.Context, req *types.UpdateOrderStatusRequest, _ *types.UpdateOrderStatusResponse) error {
+	var uid clientintf.UserID
+	if err := uid.FromBytes(req.User); err != nil {
+		return err
+	}
+	err := s.store.UpdateOrderStatus(uid, simplestore.OrderID(req.Id),
+		simplestore.OrderStatus(req.Status))
+	if err != nil {
+		return err
+	}
+	s.log.Infof("Changed status of order %s/%d to %s", uid.ShortLogID(),
+		req.Id, req.Status)
+	return nil
+}
+
+func (s *storeServer) SalesTotals(_ context.Context, req *types.SalesTotalsRequest, res *types.SalesTotalsResponse) error {
+	var since time.Time
+	if req.Since > 0 {
+		since = time.Unix(req.Since, 0)
+	}
+	sales, err := s.store.Sales(since)
+	if err != nil {
+		return err
+	}
+	res.Count = uint32(sales.Count)
+	res.Totals = make([]*types.SalesTotal, 0, len(sales.Totals))
+	for currency, total := range sales.Totals {
+		res.Totals = append(res.Totals, &types.SalesTotal{
+			Currency:   currency,
+			TotalCents: int64(total),
+		})
+	}
+	sort.Slice(res.Totals, func(i, j int) bool {
+		return res.Totals[i].Currency < res.Totals[j].Currency
+	})
+	return nil
+}
+
+func (s *storeServer) StoreEvents(ctx context.Context, req *types.StoreEventsRequest, stream types.StoreService_StoreEventsServer) error {
+	return s.eventStreams.runStream(ctx, req.UnackedFrom, stream)
+}
+
+func (s *storeServer) AckStoreEvents(_ context.Context, req *types.AckRequest, _ *types.AckResponse) error {
+	return s.eventStreams.ack(req.SequenceId)
+}
+
+// activityHandler sends the activity events of the store to the event
+// streams.
+func (s *storeServer) activityHandler(act *simplestore.Activity) {
+	ntfn := &types.StoreEvent{
+		Type:      string(act.Type),
+		Timestamp: act.Timestamp.Unix(),
+		User:      act.User[:],
+		OldStatus: string(act.OldStatus),
+	}
+	if act.Cart != nil {
+		ntfn.CartItems = storeItems(act.Cart.Items)
+		ntfn.CartTotalCents = int64(act.Cart.Total())
+	}
+	if act.Order != nil {
+		ntfn.Order = storeOrder(act.Order)
+	}
+	s.eventStreams.send(ntfn)
+}
+
+// InitStoreService initializes and binds a StoreService server to the RPC
+// server.
+func (s *Server) InitStoreService(cfg StoreServerCfg) error {
+	if cfg.Store == nil {
+		return fmt.Errorf("store not specified")
+	}
+	log := slog.Disabled
+	if cfg.Log != nil {
+		log = cfg.Log
+	}
+	eventStreams, err := newServerStreams[*types.StoreEvent](cfg.RootReplayMsgLogs, "storeevents", log)
+	if err != nil {
+		return err
+	}
+	ss := &storeServer{
+		store:        cfg.Store,
+		log:          log,
+		eventStreams: eventStreams,
+	}
+	cfg.Store.RegisterActivityHandler(ss.activityHandler)
+	s.services.Bind("StoreService", types.StoreServiceDefn(), ss)
+	return nil
+}
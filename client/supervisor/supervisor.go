@@ -0,0 +1,193 @@
+// Package supervisor isolates panics in non-critical subsystems of the client
+// (resource providers, rate fetchers, store janitors, etc), so that a bug in
+// one of them does not take down the whole client.
+//
+// Subsystems run through the supervisor are restarted with an exponential
+// backoff after they panic and every panic is reported as an incident.
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/decred/slog"
+)
+
+// maxIncidents is the number of most recent incidents kept by the
+// supervisor.
+const maxIncidents = 100
+
+// PanicError is the error returned by Protect when the protected function
+// panics.
+type PanicError struct {
+	Subsystem string
+	Value     interface{}
+}
+
+func (err PanicError) Error() string {
+	return fmt.Sprintf("subsystem %s panicked: %v", err.Subsystem, err.Value)
+}
+
+// Incident is a panic recovered by the supervisor.
+type Incident struct {
+	// Subsystem is the name of the subsystem that panicked.
+	Subsystem string
+
+	// Time is when the panic happened.
+	Time time.Time
+
+	// Value is the value passed to panic() and Stack is the stack trace
+	// of the goroutine that panicked.
+	Value interface{}
+	Stack []byte
+
+	// Restarts is the number of times the subsystem was restarted after
+	// panicking (including this incident) and RestartIn is the delay until
+	// the next restart. Both are zero for incidents of protected calls,
+	// which are not restarted.
+	Restarts  int
+	RestartIn time.Duration
+}
+
+// Config is the configuration of a Supervisor.
+type Config struct {
+	// MinBackoff and MaxBackoff are the min and max delays to restart a
+	// subsystem after it panics. The delay doubles after every panic and
+	// is reset once the subsystem runs for longer than MaxBackoff.
+	// Default to 1 second and 5 minutes.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnIncident is called after every recovered panic.
+	OnIncident func(Incident)
+
+	// Log is the logger of the supervisor.
+	Log slog.Logger
+}
+
+// Supervisor runs subsystems, recovering from their panics.
+type Supervisor struct {
+	cfg Config
+	log slog.Logger
+
+	mtx       sync.Mutex
+	incidents []Incident
+}
+
+// New creates a new supervisor.
+func New(cfg Config) *Supervisor {
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = time.Second
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	log := slog.Disabled
+	if cfg.Log != nil {
+		log = cfg.Log
+	}
+	return &Supervisor{cfg: cfg, log: log}
+}
+
+// report records and notifies an incident.
+func (s *Supervisor) report(inc Incident) {
+	if inc.RestartIn > 0 {
+		s.log.Errorf("Subsystem %s panicked: %v. Restarting in %s\n%s",
+			inc.Subsystem, inc.Value, inc.RestartIn, inc.Stack)
+	} else {
+		s.log.Errorf("Subsystem %s panicked: %v\n%s", inc.Subsystem,
+			inc.Value, inc.Stack)
+	}
+
+	s.mtx.Lock()
+	if len(s.incidents) >= maxIncidents {
+		s.incidents = append(s.incidents[:0], s.incidents[1:]...)
+	}
+	s.incidents = append(s.incidents, inc)
+	s.mtx.Unlock()
+
+	if s.cfg.OnIncident != nil {
+		s.cfg.OnIncident(inc)
+	}
+}
+
+// Incidents returns the most recent incidents, oldest first.
+func (s *Supervisor) Incidents() []Incident {
+	s.mtx.Lock()
+	res := make([]Incident, len(s.incidents))
+	copy(res, s.incidents)
+	s.mtx.Unlock()
+	return res
+}
+
+// Protect calls fn, recovering from a panic in it. If fn panics, the incident
+// is reported and a PanicError is returned. Protected calls are not retried.
+func (s *Supervisor) Protect(name string, fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			s.report(Incident{
+				Subsystem: name,
+				Time:      time.Now(),
+				Value:     v,
+				Stack:     debug.Stack(),
+			})
+			err = PanicError{Subsystem: name, Value: v}
+		}
+	}()
+	return fn()
+}
+
+// runOnce runs fn, returning the recovered panic value (if any) and stack.
+func runOnce(ctx context.Context, fn func(context.Context) error) (panicked interface{}, stack []byte, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			panicked, stack = v, debug.Stack()
+		}
+	}()
+	return nil, nil, fn(ctx)
+}
+
+// Run runs the subsystem until it returns or the context is done. The
+// subsystem is restarted with an exponential backoff every time it panics.
+func (s *Supervisor) Run(ctx context.Context, name string, fn func(context.Context) error) error {
+	backoff := s.cfg.MinBackoff
+	var restarts int
+	for {
+		start := time.Now()
+		v, stack, err := runOnce(ctx, fn)
+		if v == nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Reset the backoff if the subsystem ran for a while before
+		// panicking.
+		if time.Since(start) > s.cfg.MaxBackoff {
+			backoff = s.cfg.MinBackoff
+		}
+		restarts += 1
+		s.report(Incident{
+			Subsystem: name,
+			Time:      time.Now(),
+			Value:     v,
+			Stack:     stack,
+			Restarts:  restarts,
+			RestartIn: backoff,
+		})
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > s.cfg.MaxBackoff {
+			backoff = s.cfg.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRunRestartsAfterPanic tests that subsystems that panic are restarted
+// and their panics reported.
+func TestRunRestartsAfterPanic(t *testing.T) {
+	incidents := make(chan Incident, 10)
+	s := New(Config{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+		OnIncident: func(inc Incident) { incidents <- inc },
+	})
+
+	var runs int
+	errDone := errors.New("done")
+	err := s.Run(context.Background(), "test", func(context.Context) error {
+		runs += 1
+		if runs < 3 {
+			panic("boom")
+		}
+		return errDone
+	})
+	if !errors.Is(err, errDone) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 3 {
+		t.Fatalf("unexpected number of runs: %d", runs)
+	}
+
+	for i := 1; i <= 2; i++ {
+		inc := <-incidents
+		if inc.Subsystem != "test" || inc.Value != "boom" || inc.Restarts != i {
+			t.Fatalf("unexpected incident %d: %+v", i, inc)
+		}
+		if len(inc.Stack) == 0 {
+			t.Fatal("incident without stack")
+		}
+	}
+	if got := len(s.Incidents()); got != 2 {
+		t.Fatalf("unexpected number of incidents: %d", got)
+	}
+}
+
+// TestRunStopsOnContextDone tests that a subsystem that keeps panicking is not
+// restarted once the context is done.
+func TestRunStopsOnContextDone(t *testing.T) {
+	s := New(Config{MinBackoff: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- s.Run(ctx, "test", func(context.Context) error {
+			panic("boom")
+		})
+	}()
+
+	// Wait until the panic is recorded, then cancel while waiting for
+	// the restart.
+	for len(s.Incidents()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for Run to return")
+	}
+}
+
+// TestProtect tests recovering from panics of protected calls.
+func TestProtect(t *testing.T) {
+	s := New(Config{})
+	err := s.Protect("test", func() error { panic("boom") })
+	var perr PanicError
+	if !errors.As(err, &perr) || perr.Value != "boom" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantErr := errors.New("test error")
+	if err := s.Protect("test", func() error { return wantErr }); err != wantErr {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(s.Incidents()); got != 1 {
+		t.Fatalf("unexpected number of incidents: %d", got)
+	}
+}
@@ -169,6 +169,13 @@ func (tal *tipAttemptsList) determineTipAttemptAction(ta *clientdb.TipUserAttemp
 		return actionExpire, expireDeadline
 	}
 
+	if ta.LastInvoice == "" && ta.PaymentAttempt != nil {
+		// Keysend payment in-flight, or interrupted by a restart, in
+		// which case its result is unknown. Requesting (and paying) a
+		// new invoice could pay the tip twice, so only wait to expire.
+		return actionExpire, expireDeadline
+	}
+
 	if ta.LastInvoice != "" {
 		if ta.PaymentAttempt == nil {
 			if ta.PaymentAttemptFailed == nil {
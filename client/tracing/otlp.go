@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultOTLPURL is the default URL of the traces endpoint of a local
+// OpenTelemetry collector, using the OTLP/HTTP protocol.
+const DefaultOTLPURL = "http://127.0.0.1:4318/v1/traces"
+
+// OTLP status codes.
+const (
+	otlpStatusUnset = 0
+	otlpStatusError = 2
+)
+
+// otlpSpanKindInternal is the kind of the exported spans.
+const otlpSpanKindInternal = 1
+
+// The following types are the JSON encoding of OTLP trace export requests.
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// OTLPExporter exports spans to an OpenTelemetry collector using the
+// OTLP/HTTP protocol with JSON encoding.
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	httpClient  *http.Client
+}
+
+// NewOTLPExporter returns an exporter that sends spans to the traces endpoint
+// at url (for example, DefaultOTLPURL). The spans are reported as coming from
+// the given service.
+func NewOTLPExporter(url, serviceName string) *OTLPExporter {
+	return &OTLPExporter{
+		url:         url,
+		serviceName: serviceName,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// encodeRequest encodes the spans as an OTLP export request.
+func (e *OTLPExporter) encodeRequest(spans []SpanData) ([]byte, error) {
+	otlpSpans := make([]otlpSpan, len(spans))
+	for i := range spans {
+		sd := &spans[i]
+		span := otlpSpan{
+			TraceID:           sd.TraceID.String(),
+			SpanID:            sd.SpanID.String(),
+			Name:              sd.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(sd.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(sd.End.UnixNano(), 10),
+			Status:            otlpStatus{Code: otlpStatusUnset},
+		}
+		if !sd.ParentID.IsEmpty() {
+			span.ParentSpanID = sd.ParentID.String()
+		}
+		for k, v := range sd.Attrs {
+			span.Attributes = append(span.Attributes, otlpKeyValue{
+				Key:   k,
+				Value: otlpValue{StringValue: v},
+			})
+		}
+		if sd.Err != "" {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: sd.Err}
+		}
+		otlpSpans[i] = span
+	}
+
+	req := otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{{
+					Key:   "service.name",
+					Value: otlpValue{StringValue: e.serviceName},
+				}},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/companyzero/bisonrelay/client"},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+	return json.Marshal(&req)
+}
+
+// Export is part of the Exporter interface.
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := e.encodeRequest(spans)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url,
+		bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("collector replied with status %s", res.Status)
+	}
+	return nil
+}
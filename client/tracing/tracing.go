@@ -0,0 +1,232 @@
+// Package tracing records spans of the work done by the client (for example,
+// the stages of sending a message) and exports them to a collector, to
+// diagnose latency issues.
+//
+// Spans are modeled after OpenTelemetry spans and can be exported to an
+// OpenTelemetry collector with OTLPExporter. All methods are safe to call on
+// nil Tracer and Span values, in which case they do nothing. This allows code
+// to be instrumented unconditionally, with tracing disabled by not creating a
+// Tracer.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/decred/slog"
+)
+
+// TraceID identifies a trace (a tree of spans).
+type TraceID [16]byte
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// SpanID identifies a span.
+type SpanID [8]byte
+
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+// IsEmpty returns true if the span id is not set.
+func (id SpanID) IsEmpty() bool { return id == SpanID{} }
+
+// SpanData is the data of a finished span.
+type SpanData struct {
+	TraceID  TraceID
+	SpanID   SpanID
+	ParentID SpanID
+	Name     string
+	Start    time.Time
+	End      time.Time
+	Attrs    map[string]string
+
+	// Err is the error the span finished with, if any.
+	Err string
+}
+
+// Duration returns the duration of the span.
+func (sd *SpanData) Duration() time.Duration {
+	return sd.End.Sub(sd.Start)
+}
+
+// Span is an in-progress span.
+type Span struct {
+	tracer *Tracer
+
+	mtx   sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SetAttr sets an attribute of the span.
+func (s *Span) SetAttr(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	if s.data.Attrs == nil {
+		s.data.Attrs = make(map[string]string)
+	}
+	s.data.Attrs[key] = value
+	s.mtx.Unlock()
+}
+
+// Child starts a new span that is a child of this span.
+func (s *Span) Child(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.tracer.start(name, s.data.TraceID, s.data.SpanID)
+}
+
+// End finishes the span, with the given error (which may be nil). Spans are
+// only exported once, so calls after the first one are ignored.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	if s.ended {
+		s.mtx.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	if err != nil {
+		s.data.Err = err.Error()
+	}
+	data := s.data
+	s.mtx.Unlock()
+	s.tracer.finished(data)
+}
+
+// Exporter exports finished spans to a collector.
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// Config is the configuration of a Tracer.
+type Config struct {
+	// Exporter exports the finished spans.
+	Exporter Exporter
+
+	// Log is used to log export errors.
+	Log slog.Logger
+
+	// BatchSize is the max number of spans exported at once. Defaults to
+	// 512.
+	BatchSize int
+
+	// FlushInterval is the max time finished spans wait before being
+	// exported. Defaults to 5 seconds.
+	FlushInterval time.Duration
+
+	// QueueSize is the max number of finished spans waiting to be
+	// exported. Spans finished while the queue is full are dropped.
+	// Defaults to 4096.
+	QueueSize int
+}
+
+// Tracer creates spans and exports them once they finish.
+type Tracer struct {
+	cfg   Config
+	log   slog.Logger
+	queue chan SpanData
+}
+
+// New creates a new tracer. Run must be called for the finished spans to be
+// exported.
+func New(cfg Config) *Tracer {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 512
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 4096
+	}
+	log := cfg.Log
+	if log == nil {
+		log = slog.Disabled
+	}
+	return &Tracer{
+		cfg:   cfg,
+		log:   log,
+		queue: make(chan SpanData, cfg.QueueSize),
+	}
+}
+
+// Start starts a new root span.
+func (t *Tracer) Start(name string) *Span {
+	if t == nil {
+		return nil
+	}
+	var traceID TraceID
+	if _, err := rand.Read(traceID[:]); err != nil {
+		t.log.Warnf("Unable to generate trace id: %v", err)
+		return nil
+	}
+	return t.start(name, traceID, SpanID{})
+}
+
+func (t *Tracer) start(name string, traceID TraceID, parentID SpanID) *Span {
+	var spanID SpanID
+	if _, err := rand.Read(spanID[:]); err != nil {
+		t.log.Warnf("Unable to generate span id: %v", err)
+		return nil
+	}
+	return &Span{
+		tracer: t,
+		data: SpanData{
+			TraceID:  traceID,
+			SpanID:   spanID,
+			ParentID: parentID,
+			Name:     name,
+			Start:    time.Now(),
+		},
+	}
+}
+
+// finished queues the finished span to be exported. It does not block.
+func (t *Tracer) finished(data SpanData) {
+	select {
+	case t.queue <- data:
+	default:
+		t.log.Debugf("Tracing queue is full. Dropping span %s", data.Name)
+	}
+}
+
+// export exports the batch of spans, logging any errors.
+func (t *Tracer) export(ctx context.Context, batch []SpanData) {
+	if len(batch) == 0 || t.cfg.Exporter == nil {
+		return
+	}
+	if err := t.cfg.Exporter.Export(ctx, batch); err != nil {
+		t.log.Warnf("Unable to export %d spans: %v", len(batch), err)
+	}
+}
+
+// Run exports the finished spans until the context is done.
+func (t *Tracer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.cfg.BatchSize)
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) < t.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		t.export(ctx, batch)
+		batch = make([]SpanData, 0, t.cfg.BatchSize)
+	}
+}
@@ -0,0 +1,135 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type chanExporter chan []SpanData
+
+func (ce chanExporter) Export(ctx context.Context, spans []SpanData) error {
+	ce <- spans
+	return nil
+}
+
+// TestNilTracer tests that spans of a nil tracer can be used.
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	span := tracer.Start("root")
+	span.SetAttr("k", "v")
+	child := span.Child("child")
+	child.End(nil)
+	span.End(errors.New("boom"))
+}
+
+// TestSpans tests that finished spans are exported with their parents.
+func TestSpans(t *testing.T) {
+	t.Parallel()
+
+	exporter := make(chanExporter, 1)
+	tracer := New(Config{
+		Exporter:      exporter,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracer.Run(ctx)
+
+	root := tracer.Start("root")
+	root.SetAttr("k", "v")
+	child := root.Child("child")
+	child.End(errors.New("boom"))
+	root.End(nil)
+	root.End(nil) // Ignored.
+
+	var spans []SpanData
+	select {
+	case spans = <-exporter:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for spans")
+	}
+	if len(spans) != 2 {
+		t.Fatalf("unexpected nb of spans: got %d, want 2", len(spans))
+	}
+	gotChild, gotRoot := spans[0], spans[1]
+	if gotChild.Name != "child" || gotRoot.Name != "root" {
+		t.Fatalf("unexpected span names %q and %q", gotChild.Name, gotRoot.Name)
+	}
+	if gotChild.TraceID != gotRoot.TraceID {
+		t.Fatalf("spans have different trace ids")
+	}
+	if gotChild.ParentID != gotRoot.SpanID || !gotRoot.ParentID.IsEmpty() {
+		t.Fatalf("unexpected span parents")
+	}
+	if gotChild.Err != "boom" || gotRoot.Err != "" {
+		t.Fatalf("unexpected span errors %q and %q", gotChild.Err, gotRoot.Err)
+	}
+	if gotRoot.Attrs["k"] != "v" {
+		t.Fatalf("unexpected root attributes %v", gotRoot.Attrs)
+	}
+}
+
+// TestOTLPExporter tests the requests sent by the OTLP exporter.
+func TestOTLPExporter(t *testing.T) {
+	t.Parallel()
+
+	reqs := make(chan otlpExportRequest, 1)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req otlpExportRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reqs <- req
+	}))
+	defer svr.Close()
+
+	start := time.Unix(1700000000, 0)
+	spans := []SpanData{{
+		TraceID:  TraceID{0: 1},
+		SpanID:   SpanID{0: 2},
+		ParentID: SpanID{0: 3},
+		Name:     "pay",
+		Start:    start,
+		End:      start.Add(time.Second),
+		Attrs:    map[string]string{"k": "v"},
+		Err:      "boom",
+	}}
+	e := NewOTLPExporter(svr.URL, "test")
+	if err := e.Export(context.Background(), spans); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-reqs
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected request %v", req)
+	}
+	if svc := req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue; svc != "test" {
+		t.Fatalf("unexpected service name %q", svc)
+	}
+	got := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	want := otlpSpan{
+		TraceID:           "01000000000000000000000000000000",
+		SpanID:            "0200000000000000",
+		ParentSpanID:      "0300000000000000",
+		Name:              "pay",
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: "1700000000000000000",
+		EndTimeUnixNano:   "1700000001000000000",
+		Attributes:        []otlpKeyValue{{Key: "k", Value: otlpValue{StringValue: "v"}}},
+		Status:            otlpStatus{Code: otlpStatusError, Message: "boom"},
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Fatalf("unexpected span: got %s, want %s", gotJSON, wantJSON)
+	}
+}
@@ -0,0 +1,153 @@
+package updates
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/internal/strescape"
+	"github.com/companyzero/bisonrelay/rpc"
+	"golang.org/x/exp/slices"
+)
+
+// maxManifestSize is the max size of a fetched release manifest.
+const maxManifestSize = 1 << 20 // 1MiB
+
+// Fetcher fetches the release manifest.
+type Fetcher interface {
+	FetchManifest(ctx context.Context) ([]byte, error)
+}
+
+// requireHTTPS returns an error if the URL is not an HTTPS URL.
+func requireHTTPS(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("URL %q is not an https URL", rawURL)
+	}
+	return nil
+}
+
+// httpGet fetches the URL, reading at most maxSize bytes of the response.
+func httpGet(ctx context.Context, c *http.Client, rawURL string, maxSize int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("%s replied with status %s", rawURL, res.Status)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(res.Body, maxSize), res.Body}, nil
+}
+
+// HTTPSFetcher fetches the release manifest from an HTTPS URL.
+type HTTPSFetcher struct {
+	// URL is the URL of the manifest. It must be an https URL.
+	URL string
+
+	// HTTPClient is the client used to fetch the manifest. Defaults to a
+	// client with a 30 second timeout.
+	HTTPClient *http.Client
+}
+
+// FetchManifest is part of the Fetcher interface.
+func (f *HTTPSFetcher) FetchManifest(ctx context.Context) ([]byte, error) {
+	if err := requireHTTPS(f.URL); err != nil {
+		return nil, err
+	}
+	c := f.HTTPClient
+	if c == nil {
+		c = &http.Client{Timeout: 30 * time.Second}
+	}
+	body, err := httpGet(ctx, c, f.URL, maxManifestSize)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// BRFetcher fetches the release manifest as a resource from a user of the BR
+// network.
+type BRFetcher struct {
+	// Client is the client used to fetch the manifest.
+	Client *client.Client
+
+	// UID is the user that serves the manifest. It must be a known
+	// user of the client.
+	UID clientintf.UserID
+
+	// Path is the path of the manifest resource.
+	Path []string
+
+	// Timeout is the max time to wait for the reply of the user. Defaults
+	// to 5 minutes.
+	Timeout time.Duration
+}
+
+// FetchManifest is part of the Fetcher interface.
+func (f *BRFetcher) FetchManifest(ctx context.Context) ([]byte, error) {
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Register the handler before requesting the resource, to ensure the
+	// reply is not missed.
+	replies := make(chan rpc.RMFetchResourceReply, 8)
+	reg := f.Client.NotificationManager().Register(client.OnResourceFetchedNtfn(
+		func(ru *client.RemoteUser, fr clientdb.FetchedResource, _ clientdb.PageSessionOverview) {
+			if fr.UID != f.UID || !slices.Equal(fr.Request.Path, f.Path) {
+				return
+			}
+			select {
+			case replies <- fr.Response:
+			default:
+			}
+		}))
+	defer reg.Unregister()
+
+	tag, err := f.Client.FetchResource(f.UID, f.Path, nil, 0, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		select {
+		case reply := <-replies:
+			if reply.Tag != tag {
+				// Reply to an earlier request that timed out.
+				continue
+			}
+			if reply.Status != rpc.ResourceStatusOk {
+				return nil, fmt.Errorf("user replied to %s with status %s",
+					strescape.ResourcesPath(f.Path), reply.Status)
+			}
+			if len(reply.Data) > maxManifestSize {
+				return nil, fmt.Errorf("manifest too large (%d bytes)",
+					len(reply.Data))
+			}
+			return reply.Data, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package updates
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidManifest is returned when a manifest cannot be decoded or is not
+// signed by enough of the trusted keys.
+var ErrInvalidManifest = errors.New("invalid release manifest")
+
+// manifestSignPrefix is prepended to the release before signing it, so that
+// signatures of release manifests cannot be reused in other contexts.
+const manifestSignPrefix = "bisonrelay release manifest\n"
+
+// Binary is a binary of a release for a specific platform.
+type Binary struct {
+	// OS and Arch are the GOOS and GOARCH of the platform of the binary.
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+
+	// Name is the file name of the binary.
+	Name string `json:"name"`
+
+	// URL is the HTTPS URL from where the binary is downloaded.
+	URL string `json:"url"`
+
+	// SHA256 is the hex encoded SHA256 hash of the binary.
+	SHA256 string `json:"sha256"`
+}
+
+// Release is the description of a released version.
+type Release struct {
+	Version  string    `json:"version"`
+	Date     time.Time `json:"date"`
+	Notes    string    `json:"notes,omitempty"`
+	Binaries []Binary  `json:"binaries,omitempty"`
+}
+
+// Binary returns the binary of the release for the given platform.
+func (r *Release) Binary(goos, goarch string) *Binary {
+	for i := range r.Binaries {
+		if r.Binaries[i].OS == goos && r.Binaries[i].Arch == goarch {
+			return &r.Binaries[i]
+		}
+	}
+	return nil
+}
+
+// Signature is a signature of a release manifest.
+type Signature struct {
+	// PubKey is the hex encoded ed25519 public key of the signer.
+	PubKey string `json:"pubkey"`
+
+	// Sig is the hex encoded signature.
+	Sig string `json:"sig"`
+}
+
+// Manifest is a signed release manifest. The release is kept as the raw JSON
+// that was signed.
+type Manifest struct {
+	Release    json.RawMessage `json:"release"`
+	Signatures []Signature     `json:"signatures"`
+}
+
+// signMsg returns the message signed in release manifests.
+func signMsg(release []byte) []byte {
+	msg := make([]byte, 0, len(manifestSignPrefix)+len(release))
+	msg = append(msg, manifestSignPrefix...)
+	return append(msg, release...)
+}
+
+// NewManifest creates a manifest for the release, without signatures.
+func NewManifest(release *Release) (*Manifest, error) {
+	raw, err := json.Marshal(release)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{Release: raw}, nil
+}
+
+// Sign adds a signature of the manifest made with the given key.
+func (m *Manifest) Sign(key ed25519.PrivateKey) {
+	pub := key.Public().(ed25519.PublicKey)
+	sig := ed25519.Sign(key, signMsg(m.Release))
+	m.Signatures = append(m.Signatures, Signature{
+		PubKey: hex.EncodeToString(pub),
+		Sig:    hex.EncodeToString(sig),
+	})
+}
+
+// Verify verifies that the manifest is signed by at least minSigs of the
+// trusted keys and returns the signed release.
+func (m *Manifest) Verify(trusted []ed25519.PublicKey, minSigs int) (*Release, error) {
+	if minSigs < 1 {
+		minSigs = 1
+	}
+	msg := signMsg(m.Release)
+	signers := make(map[string]bool, len(m.Signatures))
+	for _, s := range m.Signatures {
+		pub, err := hex.DecodeString(s.PubKey)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			continue
+		}
+		sig, err := hex.DecodeString(s.Sig)
+		if err != nil || len(sig) != ed25519.SignatureSize {
+			continue
+		}
+		for _, t := range trusted {
+			if t.Equal(ed25519.PublicKey(pub)) && ed25519.Verify(t, msg, sig) {
+				// Key by the decoded key, so that the same key
+				// encoded differently is not counted twice.
+				signers[hex.EncodeToString(pub)] = true
+				break
+			}
+		}
+	}
+	if len(signers) < minSigs {
+		return nil, fmt.Errorf("%w: signed by %d trusted keys (need %d)",
+			ErrInvalidManifest, len(signers), minSigs)
+	}
+
+	var release Release
+	if err := json.Unmarshal(m.Release, &release); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	if _, err := parseVersion(release.Version); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	return &release, nil
+}
+
+// DecodeManifest decodes a JSON encoded manifest.
+func DecodeManifest(b []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	return &m, nil
+}
+
+// ParsePubKey parses a hex encoded ed25519 public key.
+func ParsePubKey(s string) (ed25519.PublicKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key has wrong length %d", len(b))
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+var versionRE = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// version is a parsed semantic version.
+type version struct {
+	major, minor, patch uint64
+	preRelease          string
+}
+
+func parseVersion(s string) (version, error) {
+	m := versionRE.FindStringSubmatch(s)
+	if m == nil {
+		return version{}, fmt.Errorf("malformed version %q", s)
+	}
+	var v version
+	var err error
+	if v.major, err = strconv.ParseUint(m[1], 10, 32); err != nil {
+		return version{}, err
+	}
+	if v.minor, err = strconv.ParseUint(m[2], 10, 32); err != nil {
+		return version{}, err
+	}
+	if v.patch, err = strconv.ParseUint(m[3], 10, 32); err != nil {
+		return version{}, err
+	}
+	v.preRelease = m[4]
+	return v, nil
+}
+
+// newerThan returns true if v is a newer version than other. Pre-release
+// versions are older than the corresponding release version.
+func (v version) newerThan(other version) bool {
+	switch {
+	case v.major != other.major:
+		return v.major > other.major
+	case v.minor != other.minor:
+		return v.minor > other.minor
+	case v.patch != other.patch:
+		return v.patch > other.patch
+	case v.preRelease == other.preRelease:
+		return false
+	case v.preRelease == "":
+		return true
+	case other.preRelease == "":
+		return false
+	default:
+		return v.preRelease > other.preRelease
+	}
+}
+
+// IsNewer returns true if newVersion is newer than the current version.
+func IsNewer(current, newVersion string) (bool, error) {
+	cur, err := parseVersion(current)
+	if err != nil {
+		return false, err
+	}
+	v, err := parseVersion(newVersion)
+	if err != nil {
+		return false, err
+	}
+	return v.newerThan(cur), nil
+}
@@ -0,0 +1,240 @@
+// Package updates implements an opt-in checker for new releases of the client.
+//
+// Releases are described in a manifest, signed by one or more release keys,
+// that is fetched either over HTTPS or as a resource from a user of the BR
+// network. Manifests not signed by enough of the trusted keys are rejected.
+// When a new release is found, the user is notified and (optionally) the
+// binary for the local platform is downloaded and staged in a dir, to be
+// manually swapped by the user. The running binary is never replaced.
+package updates
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/decred/slog"
+)
+
+// maxBinarySize is the max size of a staged binary.
+const maxBinarySize = 512 << 20 // 512MiB
+
+// Config is the configuration of a Checker.
+type Config struct {
+	// Fetcher fetches the release manifest.
+	Fetcher Fetcher
+
+	// CurrentVersion is the version of the running binary.
+	CurrentVersion string
+
+	// TrustedKeys are the keys trusted to sign release manifests.
+	TrustedKeys []ed25519.PublicKey
+
+	// MinSignatures is the min number of trusted keys that must sign a
+	// manifest. Defaults to 1.
+	MinSignatures int
+
+	// Interval is the interval between checks. Defaults to 24 hours.
+	Interval time.Duration
+
+	// StageDir, if set, is the dir where binaries of new releases for the
+	// local platform are downloaded to.
+	StageDir string
+
+	// HTTPClient is the client used to download binaries. Defaults to a
+	// client with a 30 minute timeout.
+	HTTPClient *http.Client
+
+	// NewRelease is called when a release newer than the current version
+	// is found. stagedPath is the path to the staged binary (if any).
+	NewRelease func(release *Release, stagedPath string)
+
+	// Log is the logger of the checker.
+	Log slog.Logger
+}
+
+// Checker periodically checks for new releases.
+type Checker struct {
+	cfg Config
+	log slog.Logger
+
+	mtx      sync.Mutex
+	notified string
+}
+
+// New creates a new update checker.
+func New(cfg Config) (*Checker, error) {
+	if cfg.Fetcher == nil {
+		return nil, errors.New("manifest fetcher not specified")
+	}
+	if len(cfg.TrustedKeys) == 0 {
+		return nil, errors.New("no trusted release keys specified")
+	}
+	if _, err := parseVersion(cfg.CurrentVersion); err != nil {
+		return nil, err
+	}
+	if cfg.MinSignatures < 1 {
+		cfg.MinSignatures = 1
+	}
+	if cfg.MinSignatures > len(cfg.TrustedKeys) {
+		return nil, fmt.Errorf("min signatures (%d) is higher than the "+
+			"number of trusted keys (%d)", cfg.MinSignatures,
+			len(cfg.TrustedKeys))
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 30 * time.Minute}
+	}
+	log := slog.Disabled
+	if cfg.Log != nil {
+		log = cfg.Log
+	}
+	return &Checker{cfg: cfg, log: log}, nil
+}
+
+// Check fetches and verifies the release manifest. It returns the release if
+// it is newer than the current version or nil otherwise.
+func (c *Checker) Check(ctx context.Context) (*Release, error) {
+	b, err := c.cfg.Fetcher.FetchManifest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch release manifest: %w", err)
+	}
+	m, err := DecodeManifest(b)
+	if err != nil {
+		return nil, err
+	}
+	release, err := m.Verify(c.cfg.TrustedKeys, c.cfg.MinSignatures)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := IsNewer(c.cfg.CurrentVersion, release.Version)
+	if err != nil || !newer {
+		return nil, err
+	}
+	return release, nil
+}
+
+// Stage downloads the binary of the release for the local platform to the
+// stage dir and verifies its hash. It returns the path to the staged binary.
+func (c *Checker) Stage(ctx context.Context, release *Release) (string, error) {
+	if c.cfg.StageDir == "" {
+		return "", errors.New("stage dir not configured")
+	}
+	bin := release.Binary(runtime.GOOS, runtime.GOARCH)
+	if bin == nil {
+		return "", fmt.Errorf("release %s has no binary for %s/%s",
+			release.Version, runtime.GOOS, runtime.GOARCH)
+	}
+	if err := requireHTTPS(bin.URL); err != nil {
+		return "", err
+	}
+	wantHash, err := hex.DecodeString(bin.SHA256)
+	if err != nil || len(wantHash) != sha256.Size {
+		return "", fmt.Errorf("invalid hash of binary %q", bin.SHA256)
+	}
+	name := filepath.Base(filepath.Clean("/" + bin.Name))
+	if name == "/" || name == "." {
+		return "", fmt.Errorf("invalid binary name %q", bin.Name)
+	}
+
+	dir := filepath.Join(c.cfg.StageDir, release.Version)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dir, name)
+
+	body, err := httpGet(ctx, c.cfg.HTTPClient, bin.URL, maxBinarySize)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	// Download to a temp file and only move it to the final path once its
+	// hash is verified.
+	f, err := os.CreateTemp(dir, name+".tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpName := f.Name()
+	defer os.Remove(tmpName)
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to download binary: %w", err)
+	}
+	if gotHash := h.Sum(nil); !bytes.Equal(gotHash, wantHash) {
+		return "", fmt.Errorf("hash mismatch of downloaded binary: got %x, "+
+			"want %x", gotHash, wantHash)
+	}
+	if err := os.Chmod(tmpName, 0o700); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpName, dest); err != nil {
+		return "", err
+	}
+	c.log.Infof("Staged binary of release %s at %s", release.Version, dest)
+	return dest, nil
+}
+
+// checkAndNotify checks for a new release and, if found, stages its binary
+// and notifies it. Each release is only notified once.
+func (c *Checker) checkAndNotify(ctx context.Context) error {
+	release, err := c.Check(ctx)
+	if err != nil || release == nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	notified := c.notified == release.Version
+	c.notified = release.Version
+	c.mtx.Unlock()
+	if notified {
+		return nil
+	}
+
+	c.log.Infof("New release %s available (current version %s)",
+		release.Version, c.cfg.CurrentVersion)
+	var stagedPath string
+	if c.cfg.StageDir != "" {
+		stagedPath, err = c.Stage(ctx, release)
+		if err != nil {
+			c.log.Warnf("Unable to stage binary of release %s: %v",
+				release.Version, err)
+		}
+	}
+	if c.cfg.NewRelease != nil {
+		c.cfg.NewRelease(release, stagedPath)
+	}
+	return nil
+}
+
+// Run checks for new releases every configured interval until the context is
+// done.
+func (c *Checker) Run(ctx context.Context) error {
+	for {
+		if err := c.checkAndNotify(ctx); err != nil && ctx.Err() == nil {
+			c.log.Warnf("Unable to check for new releases: %v", err)
+		}
+		select {
+		case <-time.After(c.cfg.Interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
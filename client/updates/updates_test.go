@@ -0,0 +1,225 @@
+package updates
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type testFetcher []byte
+
+func (f testFetcher) FetchManifest(context.Context) ([]byte, error) {
+	return f, nil
+}
+
+func newTestKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub, priv
+}
+
+// signedManifest returns the encoded manifest of the release signed by the
+// given keys.
+func signedManifest(t *testing.T, release *Release, keys ...ed25519.PrivateKey) []byte {
+	t.Helper()
+	m, err := NewManifest(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		m.Sign(key)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestManifestVerify tests the verification of signed manifests.
+func TestManifestVerify(t *testing.T) {
+	pub1, priv1 := newTestKey(t)
+	pub2, priv2 := newTestKey(t)
+	_, priv3 := newTestKey(t)
+	trusted := []ed25519.PublicKey{pub1, pub2}
+	release := &Release{Version: "1.2.3"}
+
+	tests := []struct {
+		name    string
+		keys    []ed25519.PrivateKey
+		minSigs int
+		ok      bool
+	}{
+		{name: "one sig", keys: []ed25519.PrivateKey{priv1}, minSigs: 1, ok: true},
+		{name: "two sigs", keys: []ed25519.PrivateKey{priv1, priv2}, minSigs: 2, ok: true},
+		{name: "not enough sigs", keys: []ed25519.PrivateKey{priv1}, minSigs: 2},
+		{name: "repeated sig", keys: []ed25519.PrivateKey{priv1, priv1}, minSigs: 2},
+		{name: "untrusted key", keys: []ed25519.PrivateKey{priv3}, minSigs: 1},
+		{name: "no sigs", minSigs: 1},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := DecodeManifest(signedManifest(t, release, tc.keys...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := m.Verify(trusted, tc.minSigs)
+			if !tc.ok {
+				if !errors.Is(err, ErrInvalidManifest) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Version != release.Version {
+				t.Fatalf("unexpected version %q", got.Version)
+			}
+		})
+	}
+
+	// Tampering with the release invalidates the signature.
+	m, err := DecodeManifest(signedManifest(t, release, priv1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Release, _ = json.Marshal(&Release{Version: "9.9.9"})
+	if _, err := m.Verify(trusted, 1); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The same signature listed with the key encoded in different cases
+	// counts as a single signer.
+	m, err = DecodeManifest(signedManifest(t, release, priv1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	upper := m.Signatures[0]
+	upper.PubKey = strings.ToUpper(upper.PubKey)
+	m.Signatures = append(m.Signatures, upper)
+	if _, err := m.Verify(trusted, 2); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestIsNewer tests comparing versions.
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, version string
+		newer            bool
+	}{
+		{"0.1.9", "0.1.10", true},
+		{"0.1.9", "0.2.0", true},
+		{"0.1.9", "1.0.0", true},
+		{"0.1.9", "0.1.9", false},
+		{"0.1.9", "0.1.8", false},
+		{"0.1.9-pre", "0.1.9", true},
+		{"0.1.9", "0.1.9-pre", false},
+		{"0.1.9-pre.1", "0.1.9-pre.2", true},
+		{"v0.1.9", "0.1.10+build", true},
+	}
+	for _, tc := range tests {
+		got, err := IsNewer(tc.current, tc.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.newer {
+			t.Fatalf("IsNewer(%q, %q): got %v, want %v", tc.current,
+				tc.version, got, tc.newer)
+		}
+	}
+	if _, err := IsNewer("0.1.9", "latest"); err == nil {
+		t.Fatal("malformed version did not fail")
+	}
+}
+
+// TestCheckAndStage tests finding a new release and staging its binary.
+func TestCheckAndStage(t *testing.T) {
+	binData := []byte("new binary")
+	binHash := sha256.Sum256(binData)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(binData)
+	}))
+	defer srv.Close()
+
+	pub, priv := newTestKey(t)
+	release := &Release{
+		Version: "0.2.0",
+		Binaries: []Binary{{
+			OS:     runtime.GOOS,
+			Arch:   runtime.GOARCH,
+			Name:   "../brclient",
+			URL:    srv.URL + "/brclient",
+			SHA256: hex.EncodeToString(binHash[:]),
+		}},
+	}
+
+	var gotRelease *Release
+	var gotPath string
+	c, err := New(Config{
+		Fetcher:        testFetcher(signedManifest(t, release, priv)),
+		CurrentVersion: "0.1.9",
+		TrustedKeys:    []ed25519.PublicKey{pub},
+		StageDir:       t.TempDir(),
+		HTTPClient:     srv.Client(),
+		NewRelease: func(release *Release, stagedPath string) {
+			gotRelease, gotPath = release, stagedPath
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := c.checkAndNotify(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if gotRelease == nil || gotRelease.Version != "0.2.0" {
+		t.Fatalf("unexpected release %v", gotRelease)
+	}
+	staged, err := os.ReadFile(gotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(staged) != string(binData) {
+		t.Fatalf("unexpected staged binary %q", staged)
+	}
+
+	// Releases are only notified once.
+	gotRelease = nil
+	if err := c.checkAndNotify(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if gotRelease != nil {
+		t.Fatal("release notified twice")
+	}
+
+	// Binaries that do not match the hash are not staged.
+	release.Binaries[0].SHA256 = hex.EncodeToString(make([]byte, sha256.Size))
+	if _, err := c.Stage(ctx, release); err == nil {
+		t.Fatal("binary with wrong hash was staged")
+	}
+
+	// Older releases are ignored.
+	c.cfg.CurrentVersion = "0.2.0"
+	got, err := c.Check(ctx)
+	if err != nil || got != nil {
+		t.Fatalf("unexpected check result: %v %v", got, err)
+	}
+}
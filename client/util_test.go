@@ -94,8 +94,9 @@ func orFatal(t testing.TB, err error) {
 	}
 }
 
-func testDB(t testing.TB, id *zkidentity.FullIdentity, log slog.Logger) *clientdb.DB {
-	//t.Helper()
+// testDBConfig returns the config of a test DB in a new temp dir, which is
+// removed after the test (unless it fails).
+func testDBConfig(t testing.TB, id *zkidentity.FullIdentity, log slog.Logger) clientdb.Config {
 	name := ""
 	if id != nil && id.Public.Nick != "" {
 		name = "-" + id.Public.Nick
@@ -111,13 +112,17 @@ func testDB(t testing.TB, id *zkidentity.FullIdentity, log slog.Logger) *clientd
 			os.RemoveAll(tempDir)
 		}
 	})
-	cfg := clientdb.Config{
+	return clientdb.Config{
 		Root:          tempDir,
 		DownloadsRoot: filepath.Join(tempDir, "downloads"),
 		Logger:        log,
 		ChunkSize:     8,
 	}
-	db, err := clientdb.New(cfg)
+}
+
+func testDB(t testing.TB, id *zkidentity.FullIdentity, log slog.Logger) *clientdb.DB {
+	//t.Helper()
+	db, err := clientdb.New(testDBConfig(t, id, log))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
 )
 
 // ErrorCode is a JSON-RPC error code.
@@ -23,6 +25,22 @@ func (err ErrorCode) Error() string {
 		return "invalid parameters"
 	case ErrInternal:
 		return "internal error"
+	case ErrPeerOffline:
+		return "peer is offline"
+	case ErrInsufficientFunds:
+		return "insufficient funds"
+	case ErrRateLimited:
+		return "rate limited"
+	case ErrResourceNotFound:
+		return "resource not found"
+	case ErrUserNotFound:
+		return "user not found"
+	case ErrGCNotFound:
+		return "group chat not found"
+	case ErrUserBlocked:
+		return "user is blocked"
+	case ErrAlreadyExists:
+		return "already exists"
 	default:
 		return fmt.Sprintf("error code %d", int(err))
 	}
@@ -32,6 +50,17 @@ const (
 	// Application defined error codes.
 	ErrEOF = 10000
 
+	// Error codes of the kinds of errors returned by the client (see
+	// clientintf.ErrorKind).
+	ErrPeerOffline       = 10001
+	ErrInsufficientFunds = 10002
+	ErrRateLimited       = 10003
+	ErrResourceNotFound  = 10004
+	ErrUserNotFound      = 10005
+	ErrGCNotFound        = 10006
+	ErrUserBlocked       = 10007
+	ErrAlreadyExists     = 10008
+
 	// JSON-RPC defined error codes.
 	ErrParseError     = -32700
 	ErrInvalidRequest = -32600
@@ -67,6 +96,29 @@ func newError(code ErrorCode, msg string) *Error {
 	return &err
 }
 
+// errorKindCodes maps the kinds of errors returned by the client to their
+// error codes.
+var errorKindCodes = map[clientintf.ErrorKind]ErrorCode{
+	clientintf.ErrPeerOffline:       ErrPeerOffline,
+	clientintf.ErrInsufficientFunds: ErrInsufficientFunds,
+	clientintf.ErrRateLimited:       ErrRateLimited,
+	clientintf.ErrResourceNotFound:  ErrResourceNotFound,
+	clientintf.ErrUserNotFound:      ErrUserNotFound,
+	clientintf.ErrGCNotFound:        ErrGCNotFound,
+	clientintf.ErrUserBlocked:       ErrUserBlocked,
+	clientintf.ErrAlreadyExists:     ErrAlreadyExists,
+}
+
+// codeForError returns the error code for an error returned by a method
+// handler that is not a JSON-RPC error. Errors of one of the kinds of client
+// errors use the code of their kind, while other errors are internal errors.
+func codeForError(err error) ErrorCode {
+	if code, ok := errorKindCodes[clientintf.ErrorKindOf(err)]; ok {
+		return code
+	}
+	return ErrInternal
+}
+
 func outboundFromError(id interface{}, err error) outboundMsg {
 	res := outboundMsg{
 		ID:      id,
@@ -85,7 +137,7 @@ func outboundFromError(id interface{}, err error) outboundMsg {
 		case errors.Is(err, io.EOF):
 			res.Error = newError(ErrEOF, "EOF")
 		default:
-			res.Error = newError(ErrInternal, err.Error())
+			res.Error = newError(codeForError(err), err.Error())
 		}
 	}
 
@@ -0,0 +1,60 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/client/clientintf"
+)
+
+// TestOutboundFromErrorKinds tests that errors of the kinds of client errors
+// are sent with their corresponding error codes.
+func TestOutboundFromErrorKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode ErrorCode
+	}{{
+		name:     "plain error",
+		err:      errors.New("some error"),
+		wantCode: ErrInternal,
+	}, {
+		name:     "kind",
+		err:      clientintf.ErrUserNotFound,
+		wantCode: ErrUserNotFound,
+	}, {
+		name:     "wrapped kind",
+		err:      fmt.Errorf("wrapped: %w", clientintf.ErrRateLimited),
+		wantCode: ErrRateLimited,
+	}, {
+		name:     "kind error",
+		err:      clientintf.KindErrorf(clientintf.ErrGCNotFound, "gc %s not found", "xx"),
+		wantCode: ErrGCNotFound,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res := outboundFromError(1, tc.err)
+			if res.Error == nil {
+				t.Fatalf("nil error in outbound msg")
+			}
+			if res.Error.Code != tc.wantCode {
+				t.Fatalf("unexpected code: got %d, want %d",
+					res.Error.Code, tc.wantCode)
+			}
+			if res.Error.Message != tc.err.Error() {
+				t.Fatalf("unexpected message: got %q, want %q",
+					res.Error.Message, tc.err.Error())
+			}
+		})
+	}
+
+	// Every kind must have a code.
+	for _, kind := range clientintf.ErrorKinds {
+		if _, ok := errorKindCodes[kind]; !ok {
+			t.Fatalf("error kind %q has no error code", kind)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// restPrefix is the URL path prefix of REST requests. Methods are called as
+// /v1/<Service>/<Method>.
+const restPrefix = "/v1/"
+
+// maxRESTRequestSize is the max size of the body of REST requests.
+const maxRESTRequestSize = 16 * 1024 * 1024
+
+// restError is the body of the reply to failed REST requests.
+type restError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// writeRESTError writes an error reply to a REST request.
+func writeRESTError(w http.ResponseWriter, status int, code ErrorCode, msg string) {
+	if msg == "" {
+		msg = code.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(restError{Code: code, Message: msg})
+}
+
+// restErrorFromHandler converts an error returned by a method handler to the
+// code and message of a REST error reply.
+func restErrorFromHandler(err error) (ErrorCode, string) {
+	var jerr Error
+	var jerrPtr *Error
+	var code ErrorCode
+	switch {
+	case errors.As(err, &jerr):
+		return jerr.Code, jerr.Message
+	case errors.As(err, &jerrPtr):
+		return jerrPtr.Code, jerrPtr.Message
+	case errors.As(err, &code):
+		return code, code.Error()
+	default:
+		return codeForError(err), err.Error()
+	}
+}
+
+// restStatusForCode returns the HTTP status of the reply to a REST request
+// that failed with the given error code.
+func restStatusForCode(code ErrorCode) int {
+	switch code {
+	case ErrResourceNotFound, ErrUserNotFound, ErrGCNotFound:
+		return http.StatusNotFound
+	case ErrRateLimited:
+		return http.StatusTooManyRequests
+	case ErrUserBlocked:
+		return http.StatusForbidden
+	case ErrAlreadyExists:
+		return http.StatusConflict
+	case ErrInsufficientFunds:
+		return http.StatusPaymentRequired
+	case ErrPeerOffline:
+		return http.StatusServiceUnavailable
+	case ErrInvalidParams:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// restQueryToJSON converts the query parameters of a GET request to the JSON
+// encoding of a request message. "true" and "false" are converted to bools,
+// while other values are kept as strings (which protojson accepts for numeric
+// fields). Repeated parameters are converted to lists.
+func restQueryToJSON(r *http.Request) ([]byte, error) {
+	query := r.URL.Query()
+	m := make(map[string]interface{}, len(query))
+	convert := func(v string) interface{} {
+		switch v {
+		case "true":
+			return true
+		case "false":
+			return false
+		default:
+			return v
+		}
+	}
+	for k, vs := range query {
+		if len(vs) == 1 {
+			m[k] = convert(vs[0])
+			continue
+		}
+		l := make([]interface{}, len(vs))
+		for i, v := range vs {
+			l[i] = convert(v)
+		}
+		m[k] = l
+	}
+	return json.Marshal(m)
+}
+
+// sseStream is the server side of a stream sent as server-sent events.
+type sseStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (s *sseStream) writeEvent(event string, data []byte) error {
+	var b strings.Builder
+	if event != "" {
+		b.WriteString("event: " + event + "\n")
+	}
+	b.WriteString("data: ")
+	b.Write(data)
+	b.WriteString("\n\n")
+	if _, err := io.WriteString(s.w, b.String()); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sseStream) Send(m proto.Message) error {
+	data, err := marshalOpts.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.writeEvent("", data)
+}
+
+// handleRESTRequest handles a REST request. Unary methods reply with the JSON
+// encoding of the response message, while streaming methods reply with a
+// stream of server-sent events, one for each message of the stream.
+func (s *Server) handleRESTRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+		writeRESTError(w, http.StatusMethodNotAllowed, ErrInvalidRequest,
+			"only GET and POST requests are supported")
+		return
+	}
+
+	// Determine the service.
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, restPrefix), "/")
+	if len(parts) != 2 {
+		writeRESTError(w, http.StatusNotFound, ErrMethodNotFound,
+			"path is not /v1/<Service>/<Method>")
+		return
+	}
+	method := parts[0] + "." + parts[1]
+	_, svc, methodDefn, err := s.services.SvcForMethod(method)
+	if err != nil {
+		writeRESTError(w, http.StatusNotFound, ErrMethodNotFound, err.Error())
+		return
+	}
+
+	// Decode the request from the body (POST) or query (GET).
+	var data []byte
+	if r.Method == http.MethodPost {
+		data, err = io.ReadAll(io.LimitReader(r.Body, maxRESTRequestSize))
+	} else {
+		data, err = restQueryToJSON(r)
+	}
+	if err != nil {
+		writeRESTError(w, http.StatusBadRequest, ErrParseError, err.Error())
+		return
+	}
+	protoReq := methodDefn.NewRequest()
+	if len(data) > 0 {
+		if err := unmarshalOpts.Unmarshal(data, protoReq); err != nil {
+			writeRESTError(w, http.StatusBadRequest, ErrInvalidParams,
+				fmt.Sprintf("unable to decode params: %v", err))
+			return
+		}
+	}
+
+	ctx := r.Context()
+	if !methodDefn.IsStreaming {
+		res := methodDefn.NewResponse()
+		err := methodDefn.ServerHandler(svc, ctx, protoReq, res)
+		if err != nil {
+			s.log.Debugf("Error handling REST request %s: %v", method, err)
+			code, msg := restErrorFromHandler(err)
+			writeRESTError(w, restStatusForCode(code), code, msg)
+			return
+		}
+		resData, err := marshalOpts.Marshal(res)
+		if err != nil {
+			writeRESTError(w, http.StatusInternalServerError, ErrInternal, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(resData)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeRESTError(w, http.StatusInternalServerError, ErrInternal,
+			"streaming not supported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// The stream lasts until the handler returns or the client
+	// disconnects. The end of the stream is signalled by an "error" or
+	// "eof" event.
+	stream := &sseStream{w: w, flusher: flusher}
+	err = methodDefn.ServerStreamHandler(svc, ctx, protoReq, stream)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.log.Debugf("Error handling REST stream %s: %v", method, err)
+		code, msg := restErrorFromHandler(err)
+		data, _ := json.Marshal(restError{Code: code, Message: msg})
+		_ = stream.writeEvent("error", data)
+		return
+	}
+	_ = stream.writeEvent("eof", []byte("{}"))
+}
@@ -0,0 +1,139 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/decred/slog"
+)
+
+// newTestRESTServer returns an http test server that handles REST requests
+// to the test VersionService.
+func newTestRESTServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	services := &types.ServersMap{}
+	server := &testServerImpl{appName: "testapp"}
+	services.Bind("VersionService", types.VersionServiceDefn(), server)
+	s := NewServer(WithServices(services), WithServerLog(slog.Disabled),
+		WithRESTGateway())
+	mux := http.NewServeMux()
+	mux.HandleFunc(restPrefix, s.handleRESTRequest)
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// TestRESTUnaryRequest tests calling unary methods through the REST mapping.
+func TestRESTUnaryRequest(t *testing.T) {
+	ts := newTestRESTServer(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{{
+		name:       "POST request",
+		method:     http.MethodPost,
+		path:       "/v1/VersionService/Version",
+		body:       "{}",
+		wantStatus: http.StatusOK,
+		wantBody:   `{"appName":"testapp"}`,
+	}, {
+		name:       "GET request",
+		method:     http.MethodGet,
+		path:       "/v1/VersionService/Version",
+		wantStatus: http.StatusOK,
+		wantBody:   `{"appName":"testapp"}`,
+	}, {
+		name:       "unknown method",
+		method:     http.MethodPost,
+		path:       "/v1/VersionService/Unknown",
+		wantStatus: http.StatusNotFound,
+		wantBody:   `{"code":-32601,"message":"unknown method"}`,
+	}, {
+		name:       "invalid params",
+		method:     http.MethodPost,
+		path:       "/v1/VersionService/Version",
+		body:       `{"unknown":1}`,
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name:       "wrong http method",
+		method:     http.MethodPut,
+		path:       "/v1/VersionService/Version",
+		wantStatus: http.StatusMethodNotAllowed,
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, ts.URL+tc.path,
+				strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			if res.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status: got %d, want %d (body %s)",
+					res.StatusCode, tc.wantStatus, body)
+			}
+			gotBody := strings.TrimSpace(string(body))
+			if tc.wantBody != "" && gotBody != tc.wantBody {
+				t.Fatalf("unexpected body: got %s, want %s",
+					gotBody, tc.wantBody)
+			}
+		})
+	}
+}
+
+// TestRESTStream tests that streaming methods are sent as server-sent events.
+func TestRESTStream(t *testing.T) {
+	ts := newTestRESTServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		ts.URL+"/v1/VersionService/KeepaliveStream?interval=10", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	// Read a few events.
+	scanner := bufio.NewScanner(res.Body)
+	var events int
+	for events < 3 && scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, `data: {"timestamp":`) {
+			t.Fatalf("unexpected event line %q", line)
+		}
+		events++
+	}
+	if events != 3 {
+		t.Fatalf("unexpected nb of events: %d (err %v)", events, scanner.Err())
+	}
+}
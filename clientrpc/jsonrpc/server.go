@@ -53,6 +53,7 @@ type Server struct {
 	services  *types.ServersMap
 	listeners []net.Listener
 	log       slog.Logger
+	rest      bool
 }
 
 // Run the server, responding to requests until the context is closed.
@@ -64,6 +65,11 @@ func (s *Server) Run(ctx context.Context) error {
 	// Handler for POST JSON-RPC requests.
 	serveMux.HandleFunc("/", s.handlePostRequest)
 
+	// Handler for REST requests.
+	if s.rest {
+		serveMux.HandleFunc(restPrefix, s.handleRESTRequest)
+	}
+
 	// Handler for Websocket JSON-RPC requests.
 	serveMux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		upgrader := websocket.Upgrader{
@@ -158,6 +164,7 @@ type serverConfig struct {
 	services  *types.ServersMap
 	listeners []net.Listener
 	log       slog.Logger
+	rest      bool
 }
 
 // ServerOption defines an option when configuring a JSON-RPC server.
@@ -187,6 +194,17 @@ func WithServerLog(log slog.Logger) ServerOption {
 	}
 }
 
+// WithRESTGateway enables the REST mapping of the services. When enabled,
+// methods may also be called with GET or POST requests to
+// /v1/<Service>/<Method>, with the request encoded as JSON in the body (POST)
+// or in the query parameters (GET). Streaming methods reply with server-sent
+// events.
+func WithRESTGateway() ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.rest = true
+	}
+}
+
 // NewServer returns a new JSON-RPC server.
 //
 // This is usually only used inside Bison Relay clients.
@@ -201,5 +219,6 @@ func NewServer(options ...ServerOption) *Server {
 		services:  cfg.services,
 		listeners: cfg.listeners,
 		log:       cfg.log,
+		rest:      cfg.rest,
 	}
 }
@@ -2233,22 +2233,25 @@ func (*SendFileResponse) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{38}
 }
 
-// KickFromGCRequest is the request to kick an user from a GC.
-type KickFromGCRequest struct {
+// SavedQuery is a named query over the local data of the client.
+type SavedQuery struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// gc is the hex-encoded ID or alias of the target GC.
-	Gc string `protobuf:"bytes,1,opt,name=gc,proto3" json:"gc,omitempty"`
-	// user is the hex-encoded ID or nick of the target user.
-	User string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
-	// reason is an optional reason to send for the kick.
-	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// name is the unique name of the query.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// kind is the kind of data queried: messages (the logged PMs and GC
+	// messages) or orders (the orders of the simple store).
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// terms are the terms of the query, in the field=value format.
+	Terms []string `protobuf:"bytes,3,rep,name=terms,proto3" json:"terms,omitempty"`
+	// created is the unix timestamp of when the query was saved.
+	Created int64 `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`
 }
 
-func (x *KickFromGCRequest) Reset() {
-	*x = KickFromGCRequest{}
+func (x *SavedQuery) Reset() {
+	*x = SavedQuery{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2256,13 +2259,13 @@ func (x *KickFromGCRequest) Reset() {
 	}
 }
 
-func (x *KickFromGCRequest) String() string {
+func (x *SavedQuery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KickFromGCRequest) ProtoMessage() {}
+func (*SavedQuery) ProtoMessage() {}
 
-func (x *KickFromGCRequest) ProtoReflect() protoreflect.Message {
+func (x *SavedQuery) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2274,41 +2277,51 @@ func (x *KickFromGCRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KickFromGCRequest.ProtoReflect.Descriptor instead.
-func (*KickFromGCRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SavedQuery.ProtoReflect.Descriptor instead.
+func (*SavedQuery) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *KickFromGCRequest) GetGc() string {
+func (x *SavedQuery) GetName() string {
 	if x != nil {
-		return x.Gc
+		return x.Name
 	}
 	return ""
 }
 
-func (x *KickFromGCRequest) GetUser() string {
+func (x *SavedQuery) GetKind() string {
 	if x != nil {
-		return x.User
+		return x.Kind
 	}
 	return ""
 }
 
-func (x *KickFromGCRequest) GetReason() string {
+func (x *SavedQuery) GetTerms() []string {
 	if x != nil {
-		return x.Reason
+		return x.Terms
 	}
-	return ""
+	return nil
 }
 
-// KickFromGCResponse is the response to a kick request.
-type KickFromGCResponse struct {
+func (x *SavedQuery) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+// SaveQueryRequest is the request to save a named query.
+type SaveQueryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// query is the query to save. Its created field is ignored.
+	Query *SavedQuery `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 }
 
-func (x *KickFromGCResponse) Reset() {
-	*x = KickFromGCResponse{}
+func (x *SaveQueryRequest) Reset() {
+	*x = SaveQueryRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2316,13 +2329,13 @@ func (x *KickFromGCResponse) Reset() {
 	}
 }
 
-func (x *KickFromGCResponse) String() string {
+func (x *SaveQueryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KickFromGCResponse) ProtoMessage() {}
+func (*SaveQueryRequest) ProtoMessage() {}
 
-func (x *KickFromGCResponse) ProtoReflect() protoreflect.Message {
+func (x *SaveQueryRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2334,23 +2347,27 @@ func (x *KickFromGCResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KickFromGCResponse.ProtoReflect.Descriptor instead.
-func (*KickFromGCResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SaveQueryRequest.ProtoReflect.Descriptor instead.
+func (*SaveQueryRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{40}
 }
 
-// GetGCRequest is the request to get GC datails.
-type GetGCRequest struct {
+func (x *SaveQueryRequest) GetQuery() *SavedQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+// SaveQueryResponse is the response to saving a named query.
+type SaveQueryResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// gc is the hex-encoded ID or alias of the target GC.
-	Gc string `protobuf:"bytes,1,opt,name=gc,proto3" json:"gc,omitempty"`
 }
 
-func (x *GetGCRequest) Reset() {
-	*x = GetGCRequest{}
+func (x *SaveQueryResponse) Reset() {
+	*x = SaveQueryResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2358,13 +2375,13 @@ func (x *GetGCRequest) Reset() {
 	}
 }
 
-func (x *GetGCRequest) String() string {
+func (x *SaveQueryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetGCRequest) ProtoMessage() {}
+func (*SaveQueryResponse) ProtoMessage() {}
 
-func (x *GetGCRequest) ProtoReflect() protoreflect.Message {
+func (x *SaveQueryResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2376,30 +2393,20 @@ func (x *GetGCRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetGCRequest.ProtoReflect.Descriptor instead.
-func (*GetGCRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SaveQueryResponse.ProtoReflect.Descriptor instead.
+func (*SaveQueryResponse) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *GetGCRequest) GetGc() string {
-	if x != nil {
-		return x.Gc
-	}
-	return ""
-}
-
-// GetGCResponse is the response to a request to get GC details.
-type GetGCResponse struct {
+// ListSavedQueriesRequest is the request to list the saved queries.
+type ListSavedQueriesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// gc is the gc definition.
-	Gc *RMGroupList `protobuf:"bytes,1,opt,name=gc,proto3" json:"gc,omitempty"`
 }
 
-func (x *GetGCResponse) Reset() {
-	*x = GetGCResponse{}
+func (x *ListSavedQueriesRequest) Reset() {
+	*x = ListSavedQueriesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2407,13 +2414,13 @@ func (x *GetGCResponse) Reset() {
 	}
 }
 
-func (x *GetGCResponse) String() string {
+func (x *ListSavedQueriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetGCResponse) ProtoMessage() {}
+func (*ListSavedQueriesRequest) ProtoMessage() {}
 
-func (x *GetGCResponse) ProtoReflect() protoreflect.Message {
+func (x *ListSavedQueriesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2425,27 +2432,22 @@ func (x *GetGCResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetGCResponse.ProtoReflect.Descriptor instead.
-func (*GetGCResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListSavedQueriesRequest.ProtoReflect.Descriptor instead.
+func (*ListSavedQueriesRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *GetGCResponse) GetGc() *RMGroupList {
-	if x != nil {
-		return x.Gc
-	}
-	return nil
-}
-
-// ListGCsRequest is the request to list GC data.
-type ListGCsRequest struct {
+// ListSavedQueriesResponse lists the saved queries, sorted by name.
+type ListSavedQueriesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Queries []*SavedQuery `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
 }
 
-func (x *ListGCsRequest) Reset() {
-	*x = ListGCsRequest{}
+func (x *ListSavedQueriesResponse) Reset() {
+	*x = ListSavedQueriesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2453,13 +2455,13 @@ func (x *ListGCsRequest) Reset() {
 	}
 }
 
-func (x *ListGCsRequest) String() string {
+func (x *ListSavedQueriesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListGCsRequest) ProtoMessage() {}
+func (*ListSavedQueriesResponse) ProtoMessage() {}
 
-func (x *ListGCsRequest) ProtoReflect() protoreflect.Message {
+func (x *ListSavedQueriesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2471,23 +2473,30 @@ func (x *ListGCsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListGCsRequest.ProtoReflect.Descriptor instead.
-func (*ListGCsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListSavedQueriesResponse.ProtoReflect.Descriptor instead.
+func (*ListSavedQueriesResponse) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{43}
 }
 
-// ListGCsResponse is the response to a request to list GC data.
-type ListGCsResponse struct {
+func (x *ListSavedQueriesResponse) GetQueries() []*SavedQuery {
+	if x != nil {
+		return x.Queries
+	}
+	return nil
+}
+
+// RunSavedQueryRequest is the request to run a saved query.
+type RunSavedQueryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// gcs is the list of GCs for the local client.
-	Gcs []*ListGCsResponse_GCInfo `protobuf:"bytes,1,rep,name=gcs,proto3" json:"gcs,omitempty"`
+	// name is the name of the query.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 }
 
-func (x *ListGCsResponse) Reset() {
-	*x = ListGCsResponse{}
+func (x *RunSavedQueryRequest) Reset() {
+	*x = RunSavedQueryRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[44]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2495,13 +2504,13 @@ func (x *ListGCsResponse) Reset() {
 	}
 }
 
-func (x *ListGCsResponse) String() string {
+func (x *RunSavedQueryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListGCsResponse) ProtoMessage() {}
+func (*RunSavedQueryRequest) ProtoMessage() {}
 
-func (x *ListGCsResponse) ProtoReflect() protoreflect.Message {
+func (x *RunSavedQueryRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[44]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2513,32 +2522,35 @@ func (x *ListGCsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListGCsResponse.ProtoReflect.Descriptor instead.
-func (*ListGCsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RunSavedQueryRequest.ProtoReflect.Descriptor instead.
+func (*RunSavedQueryRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *ListGCsResponse) GetGcs() []*ListGCsResponse_GCInfo {
+func (x *RunSavedQueryRequest) GetName() string {
 	if x != nil {
-		return x.Gcs
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-// ReceivedGCInvitesRequest is the request to start receiving GC invite events.
-type ReceivedGCInvitesRequest struct {
+// SavedQueryResult is an item found by a saved query.
+type SavedQueryResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// unacked_from specifies to the server the sequence_id of the last received
-	// GC invite. Invites received by the server that have a higher sequence_id
-	// will be streamed back to the client.
-	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
+	// timestamp is the unix timestamp of the item.
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// source identifies where the item was found (for example, the user or
+	// GC of a message or the ID of an order).
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// text is the text of the item.
+	Text string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
 }
 
-func (x *ReceivedGCInvitesRequest) Reset() {
-	*x = ReceivedGCInvitesRequest{}
+func (x *SavedQueryResult) Reset() {
+	*x = SavedQueryResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2546,13 +2558,13 @@ func (x *ReceivedGCInvitesRequest) Reset() {
 	}
 }
 
-func (x *ReceivedGCInvitesRequest) String() string {
+func (x *SavedQueryResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReceivedGCInvitesRequest) ProtoMessage() {}
+func (*SavedQueryResult) ProtoMessage() {}
 
-func (x *ReceivedGCInvitesRequest) ProtoReflect() protoreflect.Message {
+func (x *SavedQueryResult) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2564,39 +2576,44 @@ func (x *ReceivedGCInvitesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReceivedGCInvitesRequest.ProtoReflect.Descriptor instead.
-func (*ReceivedGCInvitesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SavedQueryResult.ProtoReflect.Descriptor instead.
+func (*SavedQueryResult) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *ReceivedGCInvitesRequest) GetUnackedFrom() uint64 {
+func (x *SavedQueryResult) GetTimestamp() int64 {
 	if x != nil {
-		return x.UnackedFrom
+		return x.Timestamp
 	}
 	return 0
 }
 
-// ReceivedGCInvite is the event sent when an invitation to join a GC is received.
-type ReceivedGCInvite struct {
+func (x *SavedQueryResult) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *SavedQueryResult) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// RunSavedQueryResponse is the response to running a saved query.
+type RunSavedQueryResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// sequence_id is an opaque sequential ID.
-	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
-	// inviter_uid is the UID of the user that sent the invitation.
-	InviterUid []byte `protobuf:"bytes,2,opt,name=inviter_uid,json=inviterUid,proto3" json:"inviter_uid,omitempty"`
-	// inviter_nick is the nick of the user that sent the invitation.
-	InviterNick string `protobuf:"bytes,3,opt,name=inviter_nick,json=inviterNick,proto3" json:"inviter_nick,omitempty"`
-	// invite_id is the unique invite ID that must be spcecified when accepting
-	// the invitation.
-	InviteId uint64 `protobuf:"varint,4,opt,name=invite_id,json=inviteId,proto3" json:"invite_id,omitempty"`
-	// invite is the invite information.
-	Invite *RMGroupInvite `protobuf:"bytes,5,opt,name=invite,proto3" json:"invite,omitempty"`
+	// results are the items that match the query, from the most recent.
+	Results []*SavedQueryResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 }
 
-func (x *ReceivedGCInvite) Reset() {
-	*x = ReceivedGCInvite{}
+func (x *RunSavedQueryResponse) Reset() {
+	*x = RunSavedQueryResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[46]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2604,13 +2621,13 @@ func (x *ReceivedGCInvite) Reset() {
 	}
 }
 
-func (x *ReceivedGCInvite) String() string {
+func (x *RunSavedQueryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReceivedGCInvite) ProtoMessage() {}
+func (*RunSavedQueryResponse) ProtoMessage() {}
 
-func (x *ReceivedGCInvite) ProtoReflect() protoreflect.Message {
+func (x *RunSavedQueryResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[46]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2622,62 +2639,30 @@ func (x *ReceivedGCInvite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReceivedGCInvite.ProtoReflect.Descriptor instead.
-func (*ReceivedGCInvite) Descriptor() ([]byte, []int) {
+// Deprecated: Use RunSavedQueryResponse.ProtoReflect.Descriptor instead.
+func (*RunSavedQueryResponse) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *ReceivedGCInvite) GetSequenceId() uint64 {
-	if x != nil {
-		return x.SequenceId
-	}
-	return 0
-}
-
-func (x *ReceivedGCInvite) GetInviterUid() []byte {
-	if x != nil {
-		return x.InviterUid
-	}
-	return nil
-}
-
-func (x *ReceivedGCInvite) GetInviterNick() string {
-	if x != nil {
-		return x.InviterNick
-	}
-	return ""
-}
-
-func (x *ReceivedGCInvite) GetInviteId() uint64 {
+func (x *RunSavedQueryResponse) GetResults() []*SavedQueryResult {
 	if x != nil {
-		return x.InviteId
-	}
-	return 0
-}
-
-func (x *ReceivedGCInvite) GetInvite() *RMGroupInvite {
-	if x != nil {
-		return x.Invite
+		return x.Results
 	}
 	return nil
 }
 
-// UserAndNick groups users and nicks when used in lists.
-type UserAndNick struct {
+// RemoveSavedQueryRequest is the request to remove a saved query.
+type RemoveSavedQueryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// uid is the unique user ID.
-	Uid []byte `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
-	// nick is the local alias or nick of the user.
-	Nick string `protobuf:"bytes,2,opt,name=nick,proto3" json:"nick,omitempty"`
-	// known flags whether the local client is KX'd with this user.
-	Known bool `protobuf:"varint,3,opt,name=known,proto3" json:"known,omitempty"`
+	// name is the name of the query.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 }
 
-func (x *UserAndNick) Reset() {
-	*x = UserAndNick{}
+func (x *RemoveSavedQueryRequest) Reset() {
+	*x = RemoveSavedQueryRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[47]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2685,13 +2670,13 @@ func (x *UserAndNick) Reset() {
 	}
 }
 
-func (x *UserAndNick) String() string {
+func (x *RemoveSavedQueryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserAndNick) ProtoMessage() {}
+func (*RemoveSavedQueryRequest) ProtoMessage() {}
 
-func (x *UserAndNick) ProtoReflect() protoreflect.Message {
+func (x *RemoveSavedQueryRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[47]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2703,47 +2688,27 @@ func (x *UserAndNick) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserAndNick.ProtoReflect.Descriptor instead.
-func (*UserAndNick) Descriptor() ([]byte, []int) {
+// Deprecated: Use RemoveSavedQueryRequest.ProtoReflect.Descriptor instead.
+func (*RemoveSavedQueryRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *UserAndNick) GetUid() []byte {
-	if x != nil {
-		return x.Uid
-	}
-	return nil
-}
-
-func (x *UserAndNick) GetNick() string {
+func (x *RemoveSavedQueryRequest) GetName() string {
 	if x != nil {
-		return x.Nick
+		return x.Name
 	}
 	return ""
 }
 
-func (x *UserAndNick) GetKnown() bool {
-	if x != nil {
-		return x.Known
-	}
-	return false
-}
-
-// GCMembersAddedRequest is the request sent to create a stream that receives
-// GC members added events.
-type GCMembersAddedRequest struct {
+// RemoveSavedQueryResponse is the response to removing a saved query.
+type RemoveSavedQueryResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// unacked_from specifies to the server the sequence_id of the last received
-	// GC members added event. Events received by the server that have a higher
-	// sequence_id will be streamed back to the client.
-	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
 }
 
-func (x *GCMembersAddedRequest) Reset() {
-	*x = GCMembersAddedRequest{}
+func (x *RemoveSavedQueryResponse) Reset() {
+	*x = RemoveSavedQueryResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[48]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2751,13 +2716,13 @@ func (x *GCMembersAddedRequest) Reset() {
 	}
 }
 
-func (x *GCMembersAddedRequest) String() string {
+func (x *RemoveSavedQueryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GCMembersAddedRequest) ProtoMessage() {}
+func (*RemoveSavedQueryResponse) ProtoMessage() {}
 
-func (x *GCMembersAddedRequest) ProtoReflect() protoreflect.Message {
+func (x *RemoveSavedQueryResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[48]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2769,36 +2734,27 @@ func (x *GCMembersAddedRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GCMembersAddedRequest.ProtoReflect.Descriptor instead.
-func (*GCMembersAddedRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RemoveSavedQueryResponse.ProtoReflect.Descriptor instead.
+func (*RemoveSavedQueryResponse) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *GCMembersAddedRequest) GetUnackedFrom() uint64 {
-	if x != nil {
-		return x.UnackedFrom
-	}
-	return 0
-}
-
-// GCMembersAddedEvent are events received when a GC has new members.
-type GCMembersAddedEvent struct {
+// KickFromGCRequest is the request to kick an user from a GC.
+type KickFromGCRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// sequence_id is an opaque sequential ID.
-	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
-	// gc is the ID of the GC.
-	Gc []byte `protobuf:"bytes,2,opt,name=gc,proto3" json:"gc,omitempty"`
-	// gc_name is the local alias of the GC.
-	GcName string `protobuf:"bytes,3,opt,name=gc_name,json=gcName,proto3" json:"gc_name,omitempty"`
-	// users is the list of users added to the GC.
-	Users []*UserAndNick `protobuf:"bytes,4,rep,name=users,proto3" json:"users,omitempty"`
+	// gc is the hex-encoded ID or alias of the target GC.
+	Gc string `protobuf:"bytes,1,opt,name=gc,proto3" json:"gc,omitempty"`
+	// user is the hex-encoded ID or nick of the target user.
+	User string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	// reason is an optional reason to send for the kick.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-func (x *GCMembersAddedEvent) Reset() {
-	*x = GCMembersAddedEvent{}
+func (x *KickFromGCRequest) Reset() {
+	*x = KickFromGCRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[49]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2806,13 +2762,13 @@ func (x *GCMembersAddedEvent) Reset() {
 	}
 }
 
-func (x *GCMembersAddedEvent) String() string {
+func (x *KickFromGCRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GCMembersAddedEvent) ProtoMessage() {}
+func (*KickFromGCRequest) ProtoMessage() {}
 
-func (x *GCMembersAddedEvent) ProtoReflect() protoreflect.Message {
+func (x *KickFromGCRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[49]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2824,54 +2780,41 @@ func (x *GCMembersAddedEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GCMembersAddedEvent.ProtoReflect.Descriptor instead.
-func (*GCMembersAddedEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use KickFromGCRequest.ProtoReflect.Descriptor instead.
+func (*KickFromGCRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *GCMembersAddedEvent) GetSequenceId() uint64 {
-	if x != nil {
-		return x.SequenceId
-	}
-	return 0
-}
-
-func (x *GCMembersAddedEvent) GetGc() []byte {
+func (x *KickFromGCRequest) GetGc() string {
 	if x != nil {
 		return x.Gc
 	}
-	return nil
+	return ""
 }
 
-func (x *GCMembersAddedEvent) GetGcName() string {
+func (x *KickFromGCRequest) GetUser() string {
 	if x != nil {
-		return x.GcName
+		return x.User
 	}
 	return ""
 }
 
-func (x *GCMembersAddedEvent) GetUsers() []*UserAndNick {
+func (x *KickFromGCRequest) GetReason() string {
 	if x != nil {
-		return x.Users
+		return x.Reason
 	}
-	return nil
+	return ""
 }
 
-// GCMembersRemovedRequest is the request to create a stream to receive GC
-// members removed events.
-type GCMembersRemovedRequest struct {
+// KickFromGCResponse is the response to a kick request.
+type KickFromGCResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// unacked_from specifies to the server the sequence_id of the last received
-	// GC members removed event. Events received by the server that have a higher
-	// sequence_id will be streamed back to the client.
-	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
 }
 
-func (x *GCMembersRemovedRequest) Reset() {
-	*x = GCMembersRemovedRequest{}
+func (x *KickFromGCResponse) Reset() {
+	*x = KickFromGCResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[50]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2879,13 +2822,13 @@ func (x *GCMembersRemovedRequest) Reset() {
 	}
 }
 
-func (x *GCMembersRemovedRequest) String() string {
+func (x *KickFromGCResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GCMembersRemovedRequest) ProtoMessage() {}
+func (*KickFromGCResponse) ProtoMessage() {}
 
-func (x *GCMembersRemovedRequest) ProtoReflect() protoreflect.Message {
+func (x *KickFromGCResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[50]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2897,36 +2840,23 @@ func (x *GCMembersRemovedRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GCMembersRemovedRequest.ProtoReflect.Descriptor instead.
-func (*GCMembersRemovedRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use KickFromGCResponse.ProtoReflect.Descriptor instead.
+func (*KickFromGCResponse) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *GCMembersRemovedRequest) GetUnackedFrom() uint64 {
-	if x != nil {
-		return x.UnackedFrom
-	}
-	return 0
-}
-
-// GCMembersRemovedEvent is an event received when members are removed from a GC.
-type GCMembersRemovedEvent struct {
+// GetGCRequest is the request to get GC datails.
+type GetGCRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// sequence_id is an opaque sequential ID.
-	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
-	// gc is the ID of the GC.
-	Gc []byte `protobuf:"bytes,2,opt,name=gc,proto3" json:"gc,omitempty"`
-	// gc_name is the local alias of the GC.
-	GcName string `protobuf:"bytes,3,opt,name=gc_name,json=gcName,proto3" json:"gc_name,omitempty"`
-	// users is the list of users removed from the GC.
-	Users []*UserAndNick `protobuf:"bytes,4,rep,name=users,proto3" json:"users,omitempty"`
+	// gc is the hex-encoded ID or alias of the target GC.
+	Gc string `protobuf:"bytes,1,opt,name=gc,proto3" json:"gc,omitempty"`
 }
 
-func (x *GCMembersRemovedEvent) Reset() {
-	*x = GCMembersRemovedEvent{}
+func (x *GetGCRequest) Reset() {
+	*x = GetGCRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[51]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2934,13 +2864,13 @@ func (x *GCMembersRemovedEvent) Reset() {
 	}
 }
 
-func (x *GCMembersRemovedEvent) String() string {
+func (x *GetGCRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GCMembersRemovedEvent) ProtoMessage() {}
+func (*GetGCRequest) ProtoMessage() {}
 
-func (x *GCMembersRemovedEvent) ProtoReflect() protoreflect.Message {
+func (x *GetGCRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[51]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2952,54 +2882,30 @@ func (x *GCMembersRemovedEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GCMembersRemovedEvent.ProtoReflect.Descriptor instead.
-func (*GCMembersRemovedEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetGCRequest.ProtoReflect.Descriptor instead.
+func (*GetGCRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *GCMembersRemovedEvent) GetSequenceId() uint64 {
-	if x != nil {
-		return x.SequenceId
-	}
-	return 0
-}
-
-func (x *GCMembersRemovedEvent) GetGc() []byte {
+func (x *GetGCRequest) GetGc() string {
 	if x != nil {
 		return x.Gc
 	}
-	return nil
-}
-
-func (x *GCMembersRemovedEvent) GetGcName() string {
-	if x != nil {
-		return x.GcName
-	}
 	return ""
 }
 
-func (x *GCMembersRemovedEvent) GetUsers() []*UserAndNick {
-	if x != nil {
-		return x.Users
-	}
-	return nil
-}
-
-// JoinedGCsRequest is the request to create a stream that receives events about
-// GCs the local client has joined.
-type JoinedGCsRequest struct {
+// GetGCResponse is the response to a request to get GC details.
+type GetGCResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// unacked_from specifies to the server the sequence_id of the last received
-	// GC joined event. Events received by the server that have a higher
-	// sequence_id will be streamed back to the client.
-	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
+	// gc is the gc definition.
+	Gc *RMGroupList `protobuf:"bytes,1,opt,name=gc,proto3" json:"gc,omitempty"`
 }
 
-func (x *JoinedGCsRequest) Reset() {
-	*x = JoinedGCsRequest{}
+func (x *GetGCResponse) Reset() {
+	*x = GetGCResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[52]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3007,13 +2913,13 @@ func (x *JoinedGCsRequest) Reset() {
 	}
 }
 
-func (x *JoinedGCsRequest) String() string {
+func (x *GetGCResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JoinedGCsRequest) ProtoMessage() {}
+func (*GetGCResponse) ProtoMessage() {}
 
-func (x *JoinedGCsRequest) ProtoReflect() protoreflect.Message {
+func (x *GetGCResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[52]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3025,32 +2931,27 @@ func (x *JoinedGCsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JoinedGCsRequest.ProtoReflect.Descriptor instead.
-func (*JoinedGCsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetGCResponse.ProtoReflect.Descriptor instead.
+func (*GetGCResponse) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *JoinedGCsRequest) GetUnackedFrom() uint64 {
+func (x *GetGCResponse) GetGc() *RMGroupList {
 	if x != nil {
-		return x.UnackedFrom
+		return x.Gc
 	}
-	return 0
+	return nil
 }
 
-// JoinedGCEvent is the event received when the local client joins a GC.
-type JoinedGCEvent struct {
+// ListGCsRequest is the request to list GC data.
+type ListGCsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// sequence_id is an opaque sequential ID.
-	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
-	// gc is the GC definition.
-	Gc *RMGroupList `protobuf:"bytes,2,opt,name=gc,proto3" json:"gc,omitempty"`
 }
 
-func (x *JoinedGCEvent) Reset() {
-	*x = JoinedGCEvent{}
+func (x *ListGCsRequest) Reset() {
+	*x = ListGCsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[53]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3058,13 +2959,13 @@ func (x *JoinedGCEvent) Reset() {
 	}
 }
 
-func (x *JoinedGCEvent) String() string {
+func (x *ListGCsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JoinedGCEvent) ProtoMessage() {}
+func (*ListGCsRequest) ProtoMessage() {}
 
-func (x *JoinedGCEvent) ProtoReflect() protoreflect.Message {
+func (x *ListGCsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[53]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3076,40 +2977,23 @@ func (x *JoinedGCEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JoinedGCEvent.ProtoReflect.Descriptor instead.
-func (*JoinedGCEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListGCsRequest.ProtoReflect.Descriptor instead.
+func (*ListGCsRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *JoinedGCEvent) GetSequenceId() uint64 {
-	if x != nil {
-		return x.SequenceId
-	}
-	return 0
-}
-
-func (x *JoinedGCEvent) GetGc() *RMGroupList {
-	if x != nil {
-		return x.Gc
-	}
-	return nil
-}
-
-// TipProgressRequest is the request to create a stream that receives events
-// about the progress of TipUser requests.
-type TipProgressRequest struct {
+// ListGCsResponse is the response to a request to list GC data.
+type ListGCsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// unacked_from specifies to the server the sequence_id of the last received
-	// tip progress event. Events received by the server that have a higher
-	// sequence_id will be streamed back to the client.
-	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
+	// gcs is the list of GCs for the local client.
+	Gcs []*ListGCsResponse_GCInfo `protobuf:"bytes,1,rep,name=gcs,proto3" json:"gcs,omitempty"`
 }
 
-func (x *TipProgressRequest) Reset() {
-	*x = TipProgressRequest{}
+func (x *ListGCsResponse) Reset() {
+	*x = ListGCsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[54]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3117,13 +3001,13 @@ func (x *TipProgressRequest) Reset() {
 	}
 }
 
-func (x *TipProgressRequest) String() string {
+func (x *ListGCsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TipProgressRequest) ProtoMessage() {}
+func (*ListGCsResponse) ProtoMessage() {}
 
-func (x *TipProgressRequest) ProtoReflect() protoreflect.Message {
+func (x *ListGCsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[54]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3135,47 +3019,32 @@ func (x *TipProgressRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TipProgressRequest.ProtoReflect.Descriptor instead.
-func (*TipProgressRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListGCsResponse.ProtoReflect.Descriptor instead.
+func (*ListGCsResponse) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *TipProgressRequest) GetUnackedFrom() uint64 {
+func (x *ListGCsResponse) GetGcs() []*ListGCsResponse_GCInfo {
 	if x != nil {
-		return x.UnackedFrom
+		return x.Gcs
 	}
-	return 0
+	return nil
 }
 
-// TipProgressEvent details the progress of a TipUser request.
-type TipProgressEvent struct {
+// ReceivedGCInvitesRequest is the request to start receiving GC invite events.
+type ReceivedGCInvitesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// sequence_id is an opaque sequential ID.
-	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
-	// uid is the User ID of the TipUser target.
-	Uid []byte `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
-	// nick is the nick of the TipUser target.
-	Nick string `protobuf:"bytes,3,opt,name=nick,proto3" json:"nick,omitempty"`
-	// amount_matoms is the amount being tipped in milli-atoms.
-	AmountMatoms int64 `protobuf:"varint,4,opt,name=amount_matoms,json=amountMatoms,proto3" json:"amount_matoms,omitempty"`
-	// completed flags whether the TipUser request was successfully completed
-	// (i.e. if the tip was paid).
-	Completed bool `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`
-	// attempt is the attempt number.
-	Attempt int32 `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
-	// attempt_err is filled when the attempt to fetch an invoice or perform
-	// the payment for a received invoice failed.
-	AttemptErr string `protobuf:"bytes,7,opt,name=attempt_err,json=attemptErr,proto3" json:"attempt_err,omitempty"`
-	// will_retry flags whether a new attempt to request an invoice and perform
-	// a payment will be done or if no more attempts will happen.
-	WillRetry bool `protobuf:"varint,8,opt,name=will_retry,json=willRetry,proto3" json:"will_retry,omitempty"`
+	// unacked_from specifies to the server the sequence_id of the last received
+	// GC invite. Invites received by the server that have a higher sequence_id
+	// will be streamed back to the client.
+	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
 }
 
-func (x *TipProgressEvent) Reset() {
-	*x = TipProgressEvent{}
+func (x *ReceivedGCInvitesRequest) Reset() {
+	*x = ReceivedGCInvitesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[55]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3183,13 +3052,13 @@ func (x *TipProgressEvent) Reset() {
 	}
 }
 
-func (x *TipProgressEvent) String() string {
+func (x *ReceivedGCInvitesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TipProgressEvent) ProtoMessage() {}
+func (*ReceivedGCInvitesRequest) ProtoMessage() {}
 
-func (x *TipProgressEvent) ProtoReflect() protoreflect.Message {
+func (x *ReceivedGCInvitesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[55]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3201,77 +3070,39 @@ func (x *TipProgressEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TipProgressEvent.ProtoReflect.Descriptor instead.
-func (*TipProgressEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReceivedGCInvitesRequest.ProtoReflect.Descriptor instead.
+func (*ReceivedGCInvitesRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *TipProgressEvent) GetSequenceId() uint64 {
-	if x != nil {
-		return x.SequenceId
-	}
-	return 0
-}
-
-func (x *TipProgressEvent) GetUid() []byte {
-	if x != nil {
-		return x.Uid
-	}
-	return nil
-}
-
-func (x *TipProgressEvent) GetNick() string {
-	if x != nil {
-		return x.Nick
-	}
-	return ""
-}
-
-func (x *TipProgressEvent) GetAmountMatoms() int64 {
-	if x != nil {
-		return x.AmountMatoms
-	}
-	return 0
-}
-
-func (x *TipProgressEvent) GetCompleted() bool {
-	if x != nil {
-		return x.Completed
-	}
-	return false
-}
-
-func (x *TipProgressEvent) GetAttempt() int32 {
+func (x *ReceivedGCInvitesRequest) GetUnackedFrom() uint64 {
 	if x != nil {
-		return x.Attempt
+		return x.UnackedFrom
 	}
 	return 0
 }
 
-func (x *TipProgressEvent) GetAttemptErr() string {
-	if x != nil {
-		return x.AttemptErr
-	}
-	return ""
-}
-
-func (x *TipProgressEvent) GetWillRetry() bool {
-	if x != nil {
-		return x.WillRetry
-	}
-	return false
-}
-
-// ResourceRequestsStreamRequest is the request for a stream to receive resource
-// requests.
-type ResourceRequestsStreamRequest struct {
+// ReceivedGCInvite is the event sent when an invitation to join a GC is received.
+type ReceivedGCInvite struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// sequence_id is an opaque sequential ID.
+	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	// inviter_uid is the UID of the user that sent the invitation.
+	InviterUid []byte `protobuf:"bytes,2,opt,name=inviter_uid,json=inviterUid,proto3" json:"inviter_uid,omitempty"`
+	// inviter_nick is the nick of the user that sent the invitation.
+	InviterNick string `protobuf:"bytes,3,opt,name=inviter_nick,json=inviterNick,proto3" json:"inviter_nick,omitempty"`
+	// invite_id is the unique invite ID that must be spcecified when accepting
+	// the invitation.
+	InviteId uint64 `protobuf:"varint,4,opt,name=invite_id,json=inviteId,proto3" json:"invite_id,omitempty"`
+	// invite is the invite information.
+	Invite *RMGroupInvite `protobuf:"bytes,5,opt,name=invite,proto3" json:"invite,omitempty"`
 }
 
-func (x *ResourceRequestsStreamRequest) Reset() {
-	*x = ResourceRequestsStreamRequest{}
+func (x *ReceivedGCInvite) Reset() {
+	*x = ReceivedGCInvite{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[56]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3279,13 +3110,13 @@ func (x *ResourceRequestsStreamRequest) Reset() {
 	}
 }
 
-func (x *ResourceRequestsStreamRequest) String() string {
+func (x *ReceivedGCInvite) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResourceRequestsStreamRequest) ProtoMessage() {}
+func (*ReceivedGCInvite) ProtoMessage() {}
 
-func (x *ResourceRequestsStreamRequest) ProtoReflect() protoreflect.Message {
+func (x *ReceivedGCInvite) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[56]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3297,31 +3128,62 @@ func (x *ResourceRequestsStreamRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResourceRequestsStreamRequest.ProtoReflect.Descriptor instead.
-func (*ResourceRequestsStreamRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReceivedGCInvite.ProtoReflect.Descriptor instead.
+func (*ReceivedGCInvite) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{56}
 }
 
-// ResourceRequestsStreamResponse is the a request made by a remote client for
-// one of the local client's resources.
-type ResourceRequestsStreamResponse struct {
+func (x *ReceivedGCInvite) GetSequenceId() uint64 {
+	if x != nil {
+		return x.SequenceId
+	}
+	return 0
+}
+
+func (x *ReceivedGCInvite) GetInviterUid() []byte {
+	if x != nil {
+		return x.InviterUid
+	}
+	return nil
+}
+
+func (x *ReceivedGCInvite) GetInviterNick() string {
+	if x != nil {
+		return x.InviterNick
+	}
+	return ""
+}
+
+func (x *ReceivedGCInvite) GetInviteId() uint64 {
+	if x != nil {
+		return x.InviteId
+	}
+	return 0
+}
+
+func (x *ReceivedGCInvite) GetInvite() *RMGroupInvite {
+	if x != nil {
+		return x.Invite
+	}
+	return nil
+}
+
+// UserAndNick groups users and nicks when used in lists.
+type UserAndNick struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// id is an opaque ID to link requests and responses made through the clientrpc
-	// interface.
-	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	// uid is the User ID of the client that requested the resource.
-	Uid []byte `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
-	// nick is the nick of the client that requested the resource.
-	Nick string `protobuf:"bytes,3,opt,name=nick,proto3" json:"nick,omitempty"`
-	// request is the actual request made by the remote client.
-	Request *RMFetchResource `protobuf:"bytes,4,opt,name=request,proto3" json:"request,omitempty"`
+	// uid is the unique user ID.
+	Uid []byte `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	// nick is the local alias or nick of the user.
+	Nick string `protobuf:"bytes,2,opt,name=nick,proto3" json:"nick,omitempty"`
+	// known flags whether the local client is KX'd with this user.
+	Known bool `protobuf:"varint,3,opt,name=known,proto3" json:"known,omitempty"`
 }
 
-func (x *ResourceRequestsStreamResponse) Reset() {
-	*x = ResourceRequestsStreamResponse{}
+func (x *UserAndNick) Reset() {
+	*x = UserAndNick{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[57]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3329,13 +3191,13 @@ func (x *ResourceRequestsStreamResponse) Reset() {
 	}
 }
 
-func (x *ResourceRequestsStreamResponse) String() string {
+func (x *UserAndNick) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResourceRequestsStreamResponse) ProtoMessage() {}
+func (*UserAndNick) ProtoMessage() {}
 
-func (x *ResourceRequestsStreamResponse) ProtoReflect() protoreflect.Message {
+func (x *UserAndNick) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[57]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3347,55 +3209,47 @@ func (x *ResourceRequestsStreamResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResourceRequestsStreamResponse.ProtoReflect.Descriptor instead.
-func (*ResourceRequestsStreamResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UserAndNick.ProtoReflect.Descriptor instead.
+func (*UserAndNick) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *ResourceRequestsStreamResponse) GetId() uint64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *ResourceRequestsStreamResponse) GetUid() []byte {
+func (x *UserAndNick) GetUid() []byte {
 	if x != nil {
 		return x.Uid
 	}
 	return nil
 }
 
-func (x *ResourceRequestsStreamResponse) GetNick() string {
+func (x *UserAndNick) GetNick() string {
 	if x != nil {
 		return x.Nick
 	}
 	return ""
 }
 
-func (x *ResourceRequestsStreamResponse) GetRequest() *RMFetchResource {
+func (x *UserAndNick) GetKnown() bool {
 	if x != nil {
-		return x.Request
+		return x.Known
 	}
-	return nil
+	return false
 }
 
-// FulfillResourceRequest fulfills a remote request for a local client resource.
-type FulfillResourceRequest struct {
+// GCMembersAddedRequest is the request sent to create a stream that receives
+// GC members added events.
+type GCMembersAddedRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// id must be the corresponding request received by the clientrpc client.
-	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	// error_msg is an optional error message to fail the request with.
-	ErrorMsg string `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
-	// response is the actual resource that should be sent to the remote client.
-	Response *RMFetchResourceReply `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
+	// unacked_from specifies to the server the sequence_id of the last received
+	// GC members added event. Events received by the server that have a higher
+	// sequence_id will be streamed back to the client.
+	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
 }
 
-func (x *FulfillResourceRequest) Reset() {
-	*x = FulfillResourceRequest{}
+func (x *GCMembersAddedRequest) Reset() {
+	*x = GCMembersAddedRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[58]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3403,13 +3257,13 @@ func (x *FulfillResourceRequest) Reset() {
 	}
 }
 
-func (x *FulfillResourceRequest) String() string {
+func (x *GCMembersAddedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FulfillResourceRequest) ProtoMessage() {}
+func (*GCMembersAddedRequest) ProtoMessage() {}
 
-func (x *FulfillResourceRequest) ProtoReflect() protoreflect.Message {
+func (x *GCMembersAddedRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[58]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3421,42 +3275,36 @@ func (x *FulfillResourceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FulfillResourceRequest.ProtoReflect.Descriptor instead.
-func (*FulfillResourceRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GCMembersAddedRequest.ProtoReflect.Descriptor instead.
+func (*GCMembersAddedRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *FulfillResourceRequest) GetId() uint64 {
+func (x *GCMembersAddedRequest) GetUnackedFrom() uint64 {
 	if x != nil {
-		return x.Id
+		return x.UnackedFrom
 	}
 	return 0
 }
 
-func (x *FulfillResourceRequest) GetErrorMsg() string {
-	if x != nil {
-		return x.ErrorMsg
-	}
-	return ""
-}
-
-func (x *FulfillResourceRequest) GetResponse() *RMFetchResourceReply {
-	if x != nil {
-		return x.Response
-	}
-	return nil
-}
-
-// FulfillResourceRequestResponse is the response to a FulfillResourceResquest
-// call.
-type FulfillResourceRequestResponse struct {
+// GCMembersAddedEvent are events received when a GC has new members.
+type GCMembersAddedEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// sequence_id is an opaque sequential ID.
+	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	// gc is the ID of the GC.
+	Gc []byte `protobuf:"bytes,2,opt,name=gc,proto3" json:"gc,omitempty"`
+	// gc_name is the local alias of the GC.
+	GcName string `protobuf:"bytes,3,opt,name=gc_name,json=gcName,proto3" json:"gc_name,omitempty"`
+	// users is the list of users added to the GC.
+	Users []*UserAndNick `protobuf:"bytes,4,rep,name=users,proto3" json:"users,omitempty"`
 }
 
-func (x *FulfillResourceRequestResponse) Reset() {
-	*x = FulfillResourceRequestResponse{}
+func (x *GCMembersAddedEvent) Reset() {
+	*x = GCMembersAddedEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[59]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3464,13 +3312,13 @@ func (x *FulfillResourceRequestResponse) Reset() {
 	}
 }
 
-func (x *FulfillResourceRequestResponse) String() string {
+func (x *GCMembersAddedEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FulfillResourceRequestResponse) ProtoMessage() {}
+func (*GCMembersAddedEvent) ProtoMessage() {}
 
-func (x *FulfillResourceRequestResponse) ProtoReflect() protoreflect.Message {
+func (x *GCMembersAddedEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[59]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3482,25 +3330,54 @@ func (x *FulfillResourceRequestResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FulfillResourceRequestResponse.ProtoReflect.Descriptor instead.
-func (*FulfillResourceRequestResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GCMembersAddedEvent.ProtoReflect.Descriptor instead.
+func (*GCMembersAddedEvent) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{59}
 }
 
-// RMPrivateMessage is the network-level routed private message.
-type RMPrivateMessage struct {
+func (x *GCMembersAddedEvent) GetSequenceId() uint64 {
+	if x != nil {
+		return x.SequenceId
+	}
+	return 0
+}
+
+func (x *GCMembersAddedEvent) GetGc() []byte {
+	if x != nil {
+		return x.Gc
+	}
+	return nil
+}
+
+func (x *GCMembersAddedEvent) GetGcName() string {
+	if x != nil {
+		return x.GcName
+	}
+	return ""
+}
+
+func (x *GCMembersAddedEvent) GetUsers() []*UserAndNick {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// GCMembersRemovedRequest is the request to create a stream to receive GC
+// members removed events.
+type GCMembersRemovedRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// message is the private message payload.
-	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	// mode is the message mode.
-	Mode MessageMode `protobuf:"varint,2,opt,name=mode,proto3,enum=MessageMode" json:"mode,omitempty"`
+	// unacked_from specifies to the server the sequence_id of the last received
+	// GC members removed event. Events received by the server that have a higher
+	// sequence_id will be streamed back to the client.
+	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
 }
 
-func (x *RMPrivateMessage) Reset() {
-	*x = RMPrivateMessage{}
+func (x *GCMembersRemovedRequest) Reset() {
+	*x = GCMembersRemovedRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[60]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3508,13 +3385,13 @@ func (x *RMPrivateMessage) Reset() {
 	}
 }
 
-func (x *RMPrivateMessage) String() string {
+func (x *GCMembersRemovedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RMPrivateMessage) ProtoMessage() {}
+func (*GCMembersRemovedRequest) ProtoMessage() {}
 
-func (x *RMPrivateMessage) ProtoReflect() protoreflect.Message {
+func (x *GCMembersRemovedRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[60]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3526,44 +3403,36 @@ func (x *RMPrivateMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RMPrivateMessage.ProtoReflect.Descriptor instead.
-func (*RMPrivateMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use GCMembersRemovedRequest.ProtoReflect.Descriptor instead.
+func (*GCMembersRemovedRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *RMPrivateMessage) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
-}
-
-func (x *RMPrivateMessage) GetMode() MessageMode {
+func (x *GCMembersRemovedRequest) GetUnackedFrom() uint64 {
 	if x != nil {
-		return x.Mode
+		return x.UnackedFrom
 	}
-	return MessageMode_MESSAGE_MODE_NORMAL
+	return 0
 }
 
-// RMGroupMessage is the network-level routed group message.
-type RMGroupMessage struct {
+// GCMembersRemovedEvent is an event received when members are removed from a GC.
+type GCMembersRemovedEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// id is the group chat id where the message was sent.
-	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// generation is the internal generation of the group chat metadata when the
-	// sender sent this message.
-	Generation uint64 `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
-	// message is the textual content.
-	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	// mode is the mode of the message.
-	Mode MessageMode `protobuf:"varint,4,opt,name=mode,proto3,enum=MessageMode" json:"mode,omitempty"`
+	// sequence_id is an opaque sequential ID.
+	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	// gc is the ID of the GC.
+	Gc []byte `protobuf:"bytes,2,opt,name=gc,proto3" json:"gc,omitempty"`
+	// gc_name is the local alias of the GC.
+	GcName string `protobuf:"bytes,3,opt,name=gc_name,json=gcName,proto3" json:"gc_name,omitempty"`
+	// users is the list of users removed from the GC.
+	Users []*UserAndNick `protobuf:"bytes,4,rep,name=users,proto3" json:"users,omitempty"`
 }
 
-func (x *RMGroupMessage) Reset() {
-	*x = RMGroupMessage{}
+func (x *GCMembersRemovedEvent) Reset() {
+	*x = GCMembersRemovedEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[61]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3571,13 +3440,13 @@ func (x *RMGroupMessage) Reset() {
 	}
 }
 
-func (x *RMGroupMessage) String() string {
+func (x *GCMembersRemovedEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RMGroupMessage) ProtoMessage() {}
+func (*GCMembersRemovedEvent) ProtoMessage() {}
 
-func (x *RMGroupMessage) ProtoReflect() protoreflect.Message {
+func (x *GCMembersRemovedEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[61]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3589,53 +3458,54 @@ func (x *RMGroupMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RMGroupMessage.ProtoReflect.Descriptor instead.
-func (*RMGroupMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use GCMembersRemovedEvent.ProtoReflect.Descriptor instead.
+func (*GCMembersRemovedEvent) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *RMGroupMessage) GetId() []byte {
+func (x *GCMembersRemovedEvent) GetSequenceId() uint64 {
 	if x != nil {
-		return x.Id
+		return x.SequenceId
 	}
-	return nil
+	return 0
 }
 
-func (x *RMGroupMessage) GetGeneration() uint64 {
+func (x *GCMembersRemovedEvent) GetGc() []byte {
 	if x != nil {
-		return x.Generation
+		return x.Gc
 	}
-	return 0
+	return nil
 }
 
-func (x *RMGroupMessage) GetMessage() string {
+func (x *GCMembersRemovedEvent) GetGcName() string {
 	if x != nil {
-		return x.Message
+		return x.GcName
 	}
 	return ""
 }
 
-func (x *RMGroupMessage) GetMode() MessageMode {
+func (x *GCMembersRemovedEvent) GetUsers() []*UserAndNick {
 	if x != nil {
-		return x.Mode
+		return x.Users
 	}
-	return MessageMode_MESSAGE_MODE_NORMAL
+	return nil
 }
 
-// PostMetadata is the network-level post data.
-type PostMetadata struct {
+// JoinedGCsRequest is the request to create a stream that receives events about
+// GCs the local client has joined.
+type JoinedGCsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// version defines the available fields within attributes.
-	Version uint64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
-	// attributes defines the available post attributes.
-	Attributes map[string]string `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// unacked_from specifies to the server the sequence_id of the last received
+	// GC joined event. Events received by the server that have a higher
+	// sequence_id will be streamed back to the client.
+	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
 }
 
-func (x *PostMetadata) Reset() {
-	*x = PostMetadata{}
+func (x *JoinedGCsRequest) Reset() {
+	*x = JoinedGCsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[62]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3643,13 +3513,13 @@ func (x *PostMetadata) Reset() {
 	}
 }
 
-func (x *PostMetadata) String() string {
+func (x *JoinedGCsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PostMetadata) ProtoMessage() {}
+func (*JoinedGCsRequest) ProtoMessage() {}
 
-func (x *PostMetadata) ProtoReflect() protoreflect.Message {
+func (x *JoinedGCsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[62]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3661,43 +3531,32 @@ func (x *PostMetadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PostMetadata.ProtoReflect.Descriptor instead.
-func (*PostMetadata) Descriptor() ([]byte, []int) {
+// Deprecated: Use JoinedGCsRequest.ProtoReflect.Descriptor instead.
+func (*JoinedGCsRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *PostMetadata) GetVersion() uint64 {
+func (x *JoinedGCsRequest) GetUnackedFrom() uint64 {
 	if x != nil {
-		return x.Version
+		return x.UnackedFrom
 	}
 	return 0
 }
 
-func (x *PostMetadata) GetAttributes() map[string]string {
-	if x != nil {
-		return x.Attributes
-	}
-	return nil
-}
-
-// PostMetadataStatus is the network-level post status update data.
-type PostMetadataStatus struct {
+// JoinedGCEvent is the event received when the local client joins a GC.
+type JoinedGCEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// version defines the available fields within attributes.
-	Version uint64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
-	// from is the UID of the original status creator.
-	From string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
-	// link is the ID of the post.
-	Link string `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
-	// attributes is the list of post update attributes.
-	Attributes map[string]string `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// sequence_id is an opaque sequential ID.
+	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	// gc is the GC definition.
+	Gc *RMGroupList `protobuf:"bytes,2,opt,name=gc,proto3" json:"gc,omitempty"`
 }
 
-func (x *PostMetadataStatus) Reset() {
-	*x = PostMetadataStatus{}
+func (x *JoinedGCEvent) Reset() {
+	*x = JoinedGCEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[63]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3705,13 +3564,13 @@ func (x *PostMetadataStatus) Reset() {
 	}
 }
 
-func (x *PostMetadataStatus) String() string {
+func (x *JoinedGCEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PostMetadataStatus) ProtoMessage() {}
+func (*JoinedGCEvent) ProtoMessage() {}
 
-func (x *PostMetadataStatus) ProtoReflect() protoreflect.Message {
+func (x *JoinedGCEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[63]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3723,63 +3582,40 @@ func (x *PostMetadataStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PostMetadataStatus.ProtoReflect.Descriptor instead.
-func (*PostMetadataStatus) Descriptor() ([]byte, []int) {
+// Deprecated: Use JoinedGCEvent.ProtoReflect.Descriptor instead.
+func (*JoinedGCEvent) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *PostMetadataStatus) GetVersion() uint64 {
+func (x *JoinedGCEvent) GetSequenceId() uint64 {
 	if x != nil {
-		return x.Version
+		return x.SequenceId
 	}
 	return 0
 }
 
-func (x *PostMetadataStatus) GetFrom() string {
-	if x != nil {
-		return x.From
-	}
-	return ""
-}
-
-func (x *PostMetadataStatus) GetLink() string {
-	if x != nil {
-		return x.Link
-	}
-	return ""
-}
-
-func (x *PostMetadataStatus) GetAttributes() map[string]string {
+func (x *JoinedGCEvent) GetGc() *RMGroupList {
 	if x != nil {
-		return x.Attributes
+		return x.Gc
 	}
 	return nil
 }
 
-// PublicIdentity is the lowlevel public identity.
-type PublicIdentity struct {
+// TipProgressRequest is the request to create a stream that receives events
+// about the progress of TipUser requests.
+type TipProgressRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// name is the name of the user.
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	// nick is the short name/alias of the user.
-	Nick string `protobuf:"bytes,2,opt,name=nick,proto3" json:"nick,omitempty"`
-	// sig_key is the signature key used to authenticate messages from the user.
-	SigKey []byte `protobuf:"bytes,3,opt,name=sig_key,json=sigKey,proto3" json:"sig_key,omitempty"`
-	// key is the NTRU public key of the user.
-	Key []byte `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
-	// identity is the public ID of the user.
-	Identity []byte `protobuf:"bytes,5,opt,name=identity,proto3" json:"identity,omitempty"`
-	// digest is a hash of the user's public information.
-	Digest []byte `protobuf:"bytes,6,opt,name=digest,proto3" json:"digest,omitempty"`
-	// signature is a signature of the user's public information.
-	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	// unacked_from specifies to the server the sequence_id of the last received
+	// tip progress event. Events received by the server that have a higher
+	// sequence_id will be streamed back to the client.
+	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
 }
 
-func (x *PublicIdentity) Reset() {
-	*x = PublicIdentity{}
+func (x *TipProgressRequest) Reset() {
+	*x = TipProgressRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[64]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3787,13 +3623,13 @@ func (x *PublicIdentity) Reset() {
 	}
 }
 
-func (x *PublicIdentity) String() string {
+func (x *TipProgressRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PublicIdentity) ProtoMessage() {}
+func (*TipProgressRequest) ProtoMessage() {}
 
-func (x *PublicIdentity) ProtoReflect() protoreflect.Message {
+func (x *TipProgressRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[64]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3805,83 +3641,47 @@ func (x *PublicIdentity) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PublicIdentity.ProtoReflect.Descriptor instead.
-func (*PublicIdentity) Descriptor() ([]byte, []int) {
+// Deprecated: Use TipProgressRequest.ProtoReflect.Descriptor instead.
+func (*TipProgressRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *PublicIdentity) GetName() string {
+func (x *TipProgressRequest) GetUnackedFrom() uint64 {
 	if x != nil {
-		return x.Name
+		return x.UnackedFrom
 	}
-	return ""
-}
-
-func (x *PublicIdentity) GetNick() string {
-	if x != nil {
-		return x.Nick
-	}
-	return ""
-}
-
-func (x *PublicIdentity) GetSigKey() []byte {
-	if x != nil {
-		return x.SigKey
-	}
-	return nil
-}
-
-func (x *PublicIdentity) GetKey() []byte {
-	if x != nil {
-		return x.Key
-	}
-	return nil
-}
-
-func (x *PublicIdentity) GetIdentity() []byte {
-	if x != nil {
-		return x.Identity
-	}
-	return nil
-}
-
-func (x *PublicIdentity) GetDigest() []byte {
-	if x != nil {
-		return x.Digest
-	}
-	return nil
-}
-
-func (x *PublicIdentity) GetSignature() []byte {
-	if x != nil {
-		return x.Signature
-	}
-	return nil
+	return 0
 }
 
-// InviteFunds is the lowlevel information about funds added to an invite.
-type InviteFunds struct {
+// TipProgressEvent details the progress of a TipUser request.
+type TipProgressEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// tx is the hash of the transaction that includes the funds.
-	Tx string `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
-	// index is the output index of the transaction that includes the funds.
-	Index uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
-	// tree is the tree of the transaction that includes the funds.
-	Tree int32 `protobuf:"varint,3,opt,name=tree,proto3" json:"tree,omitempty"`
-	// private_key is the private key needed to redeem the funds in the transaction.
-	PrivateKey string `protobuf:"bytes,4,opt,name=private_key,proto3" json:"private_key,omitempty"`
-	// height_hint is a height at or before the transaction that includes the funds
-	// is mined in.
-	HeightHint uint32 `protobuf:"varint,5,opt,name=height_hint,json=heightHint,proto3" json:"height_hint,omitempty"`
-	// address is the public address that the funds were sent to.
-	Address string `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	// sequence_id is an opaque sequential ID.
+	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	// uid is the User ID of the TipUser target.
+	Uid []byte `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	// nick is the nick of the TipUser target.
+	Nick string `protobuf:"bytes,3,opt,name=nick,proto3" json:"nick,omitempty"`
+	// amount_matoms is the amount being tipped in milli-atoms.
+	AmountMatoms int64 `protobuf:"varint,4,opt,name=amount_matoms,json=amountMatoms,proto3" json:"amount_matoms,omitempty"`
+	// completed flags whether the TipUser request was successfully completed
+	// (i.e. if the tip was paid).
+	Completed bool `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`
+	// attempt is the attempt number.
+	Attempt int32 `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// attempt_err is filled when the attempt to fetch an invoice or perform
+	// the payment for a received invoice failed.
+	AttemptErr string `protobuf:"bytes,7,opt,name=attempt_err,json=attemptErr,proto3" json:"attempt_err,omitempty"`
+	// will_retry flags whether a new attempt to request an invoice and perform
+	// a payment will be done or if no more attempts will happen.
+	WillRetry bool `protobuf:"varint,8,opt,name=will_retry,json=willRetry,proto3" json:"will_retry,omitempty"`
 }
 
-func (x *InviteFunds) Reset() {
-	*x = InviteFunds{}
+func (x *TipProgressEvent) Reset() {
+	*x = TipProgressEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[65]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3889,13 +3689,13 @@ func (x *InviteFunds) Reset() {
 	}
 }
 
-func (x *InviteFunds) String() string {
+func (x *TipProgressEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InviteFunds) ProtoMessage() {}
+func (*TipProgressEvent) ProtoMessage() {}
 
-func (x *InviteFunds) ProtoReflect() protoreflect.Message {
+func (x *TipProgressEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[65]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3907,72 +3707,89 @@ func (x *InviteFunds) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InviteFunds.ProtoReflect.Descriptor instead.
-func (*InviteFunds) Descriptor() ([]byte, []int) {
+// Deprecated: Use TipProgressEvent.ProtoReflect.Descriptor instead.
+func (*TipProgressEvent) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *InviteFunds) GetTx() string {
+func (x *TipProgressEvent) GetSequenceId() uint64 {
 	if x != nil {
-		return x.Tx
+		return x.SequenceId
 	}
-	return ""
+	return 0
 }
 
-func (x *InviteFunds) GetIndex() uint32 {
+func (x *TipProgressEvent) GetUid() []byte {
 	if x != nil {
-		return x.Index
+		return x.Uid
 	}
-	return 0
+	return nil
 }
 
-func (x *InviteFunds) GetTree() int32 {
+func (x *TipProgressEvent) GetNick() string {
 	if x != nil {
-		return x.Tree
+		return x.Nick
+	}
+	return ""
+}
+
+func (x *TipProgressEvent) GetAmountMatoms() int64 {
+	if x != nil {
+		return x.AmountMatoms
 	}
 	return 0
 }
 
-func (x *InviteFunds) GetPrivateKey() string {
+func (x *TipProgressEvent) GetCompleted() bool {
 	if x != nil {
-		return x.PrivateKey
+		return x.Completed
 	}
-	return ""
+	return false
 }
 
-func (x *InviteFunds) GetHeightHint() uint32 {
+func (x *TipProgressEvent) GetAttempt() int32 {
 	if x != nil {
-		return x.HeightHint
+		return x.Attempt
 	}
 	return 0
 }
 
-func (x *InviteFunds) GetAddress() string {
+func (x *TipProgressEvent) GetAttemptErr() string {
 	if x != nil {
-		return x.Address
+		return x.AttemptErr
 	}
 	return ""
 }
 
-// OOBPublicIdentityInvite is an out-of-band invite to perform a KX.
-type OOBPublicIdentityInvite struct {
+func (x *TipProgressEvent) GetWillRetry() bool {
+	if x != nil {
+		return x.WillRetry
+	}
+	return false
+}
+
+// PendingPayment is an outgoing payment waiting for approval.
+type PendingPayment struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// public is the public identity of the inviter.
-	Public *PublicIdentity `protobuf:"bytes,1,opt,name=public,proto3" json:"public,omitempty"`
-	// initial_rendezvous is the initial random RV where key exchange will happen.
-	InitialRendezvous []byte `protobuf:"bytes,2,opt,name=initial_rendezvous,json=initialrendezvous,proto3" json:"initial_rendezvous,omitempty"`
-	// reset_rendezvous is the inviter's reset RV in case of ratchet reset actions.
-	ResetRendezvous []byte `protobuf:"bytes,3,opt,name=reset_rendezvous,json=resetrendezvous,proto3" json:"reset_rendezvous,omitempty"`
-	// funds are funds that may be included in the invite and are redeemable by
-	// the invitee.
-	Funds *InviteFunds `protobuf:"bytes,4,opt,name=funds,proto3" json:"funds,omitempty"`
-}
-
-func (x *OOBPublicIdentityInvite) Reset() {
-	*x = OOBPublicIdentityInvite{}
+	// id identifies the payment in the approve and deny calls.
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// category is the category of the payment (tip, download or store).
+	Category string `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	// uid is the ID of the user being paid, if known.
+	Uid []byte `protobuf:"bytes,3,opt,name=uid,proto3" json:"uid,omitempty"`
+	// invoice is the invoice being paid. It is empty for keysend payments.
+	Invoice string `protobuf:"bytes,4,opt,name=invoice,proto3" json:"invoice,omitempty"`
+	// amount_matoms is the amount being paid, in milliatoms.
+	AmountMatoms int64 `protobuf:"varint,5,opt,name=amount_matoms,json=amountMatoms,proto3" json:"amount_matoms,omitempty"`
+	// requested is the unix timestamp of when the payment was held.
+	Requested int64 `protobuf:"varint,6,opt,name=requested,proto3" json:"requested,omitempty"`
+}
+
+func (x *PendingPayment) Reset() {
+	*x = PendingPayment{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[66]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3980,13 +3797,13 @@ func (x *OOBPublicIdentityInvite) Reset() {
 	}
 }
 
-func (x *OOBPublicIdentityInvite) String() string {
+func (x *PendingPayment) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OOBPublicIdentityInvite) ProtoMessage() {}
+func (*PendingPayment) ProtoMessage() {}
 
-func (x *OOBPublicIdentityInvite) ProtoReflect() protoreflect.Message {
+func (x *PendingPayment) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[66]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3998,61 +3815,63 @@ func (x *OOBPublicIdentityInvite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OOBPublicIdentityInvite.ProtoReflect.Descriptor instead.
-func (*OOBPublicIdentityInvite) Descriptor() ([]byte, []int) {
+// Deprecated: Use PendingPayment.ProtoReflect.Descriptor instead.
+func (*PendingPayment) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *OOBPublicIdentityInvite) GetPublic() *PublicIdentity {
+func (x *PendingPayment) GetId() uint64 {
 	if x != nil {
-		return x.Public
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-func (x *OOBPublicIdentityInvite) GetInitialRendezvous() []byte {
+func (x *PendingPayment) GetCategory() string {
 	if x != nil {
-		return x.InitialRendezvous
+		return x.Category
 	}
-	return nil
+	return ""
 }
 
-func (x *OOBPublicIdentityInvite) GetResetRendezvous() []byte {
+func (x *PendingPayment) GetUid() []byte {
 	if x != nil {
-		return x.ResetRendezvous
+		return x.Uid
 	}
 	return nil
 }
 
-func (x *OOBPublicIdentityInvite) GetFunds() *InviteFunds {
+func (x *PendingPayment) GetInvoice() string {
 	if x != nil {
-		return x.Funds
+		return x.Invoice
 	}
-	return nil
+	return ""
 }
 
-// RMGroupInvite is the information about an invite to join a GC.
-type RMGroupInvite struct {
+func (x *PendingPayment) GetAmountMatoms() int64 {
+	if x != nil {
+		return x.AmountMatoms
+	}
+	return 0
+}
+
+func (x *PendingPayment) GetRequested() int64 {
+	if x != nil {
+		return x.Requested
+	}
+	return 0
+}
+
+// ListPendingPaymentsRequest is the request to list the payments waiting for
+// approval.
+type ListPendingPaymentsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// id is the ID of the GC.
-	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// name is the name of the GC.
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	// token is a random number that must be sent back by the invitee to the inviter.
-	Token uint64 `protobuf:"varint,3,opt,name=token,proto3" json:"token,omitempty"`
-	// description is a description of the GC.
-	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	// expires is a timestamp for when the invitation expires.
-	Expires int64 `protobuf:"varint,5,opt,name=expires,proto3" json:"expires,omitempty"`
-	// version is the version of the current definition of the GC.
-	Version uint32 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
 }
 
-func (x *RMGroupInvite) Reset() {
-	*x = RMGroupInvite{}
+func (x *ListPendingPaymentsRequest) Reset() {
+	*x = ListPendingPaymentsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_clientrpc_proto_msgTypes[67]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4060,13 +3879,13 @@ func (x *RMGroupInvite) Reset() {
 	}
 }
 
-func (x *RMGroupInvite) String() string {
+func (x *ListPendingPaymentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RMGroupInvite) ProtoMessage() {}
+func (*ListPendingPaymentsRequest) ProtoMessage() {}
 
-func (x *RMGroupInvite) ProtoReflect() protoreflect.Message {
+func (x *ListPendingPaymentsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_clientrpc_proto_msgTypes[67]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4078,92 +3897,87 @@ func (x *RMGroupInvite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RMGroupInvite.ProtoReflect.Descriptor instead.
-func (*RMGroupInvite) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListPendingPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingPaymentsRequest) Descriptor() ([]byte, []int) {
 	return file_clientrpc_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *RMGroupInvite) GetId() []byte {
-	if x != nil {
-		return x.Id
-	}
-	return nil
+// ListPendingPaymentsResponse lists the payments waiting for approval, from
+// the oldest one.
+type ListPendingPaymentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payments []*PendingPayment `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
 }
 
-func (x *RMGroupInvite) GetName() string {
-	if x != nil {
-		return x.Name
+func (x *ListPendingPaymentsResponse) Reset() {
+	*x = ListPendingPaymentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *RMGroupInvite) GetToken() uint64 {
-	if x != nil {
-		return x.Token
-	}
-	return 0
+func (x *ListPendingPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *RMGroupInvite) GetDescription() string {
-	if x != nil {
-		return x.Description
+func (*ListPendingPaymentsResponse) ProtoMessage() {}
+
+func (x *ListPendingPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *RMGroupInvite) GetExpires() int64 {
-	if x != nil {
-		return x.Expires
-	}
-	return 0
+// Deprecated: Use ListPendingPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *RMGroupInvite) GetVersion() uint32 {
+func (x *ListPendingPaymentsResponse) GetPayments() []*PendingPayment {
 	if x != nil {
-		return x.Version
+		return x.Payments
 	}
-	return 0
+	return nil
 }
 
-// RMGroupList is the full definition of a GC.
-type RMGroupList struct {
+// ApprovePaymentRequest is the request to approve a payment.
+type ApprovePaymentRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// id is the ID of the GC.
-	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// name is the name of the GC.
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	// generation is a monotonically increasing count of GC changes.
-	Generation uint64 `protobuf:"varint,3,opt,name=generation,proto3" json:"generation,omitempty"`
-	// timestamp is the timestamp for the last modification of the GC defintions.
-	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	// version is the GC rules version.
-	Version uint32 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
-	// members is the list of user IDs that are in the GC.
-	Members [][]byte `protobuf:"bytes,6,rep,name=members,proto3" json:"members,omitempty"`
-	// extra_admins is the list of user IDs that are additional admins of the GC.
-	ExtraAdmins [][]byte `protobuf:"bytes,7,rep,name=extra_admins,proto3" json:"extra_admins,omitempty"`
+	// id is the id of the payment.
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *RMGroupList) Reset() {
-	*x = RMGroupList{}
+func (x *ApprovePaymentRequest) Reset() {
+	*x = ApprovePaymentRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_clientrpc_proto_msgTypes[68]
+		mi := &file_clientrpc_proto_msgTypes[69]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RMGroupList) String() string {
+func (x *ApprovePaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RMGroupList) ProtoMessage() {}
+func (*ApprovePaymentRequest) ProtoMessage() {}
 
-func (x *RMGroupList) ProtoReflect() protoreflect.Message {
-	mi := &file_clientrpc_proto_msgTypes[68]
+func (x *ApprovePaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[69]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4174,97 +3988,86 @@ func (x *RMGroupList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RMGroupList.ProtoReflect.Descriptor instead.
-func (*RMGroupList) Descriptor() ([]byte, []int) {
-	return file_clientrpc_proto_rawDescGZIP(), []int{68}
+// Deprecated: Use ApprovePaymentRequest.ProtoReflect.Descriptor instead.
+func (*ApprovePaymentRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *RMGroupList) GetId() []byte {
+func (x *ApprovePaymentRequest) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
-	return nil
+	return 0
 }
 
-func (x *RMGroupList) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+// ApprovePaymentResponse is the response to approving a payment.
+type ApprovePaymentResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *RMGroupList) GetGeneration() uint64 {
-	if x != nil {
-		return x.Generation
+func (x *ApprovePaymentResponse) Reset() {
+	*x = ApprovePaymentResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *RMGroupList) GetTimestamp() int64 {
-	if x != nil {
-		return x.Timestamp
-	}
-	return 0
+func (x *ApprovePaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *RMGroupList) GetVersion() uint32 {
-	if x != nil {
-		return x.Version
-	}
-	return 0
-}
+func (*ApprovePaymentResponse) ProtoMessage() {}
 
-func (x *RMGroupList) GetMembers() [][]byte {
-	if x != nil {
-		return x.Members
+func (x *ApprovePaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *RMGroupList) GetExtraAdmins() [][]byte {
-	if x != nil {
-		return x.ExtraAdmins
-	}
-	return nil
+// Deprecated: Use ApprovePaymentResponse.ProtoReflect.Descriptor instead.
+func (*ApprovePaymentResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{70}
 }
 
-// RMFetchResource is the lowlevel request to fetch a resource.
-type RMFetchResource struct {
+// DenyPaymentRequest is the request to deny a payment.
+type DenyPaymentRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// path is the resource's path (already split into segments).
-	Path []string `protobuf:"bytes,1,rep,name=path,proto3" json:"path,omitempty"`
-	// meta is metadata associated with the request.
-	Meta map[string]string `protobuf:"bytes,2,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// tag is a unique tag that should be relayed back on the reply.
-	Tag uint64 `protobuf:"varint,3,opt,name=tag,proto3" json:"tag,omitempty"`
-	// data is raw request data.
-	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
-	// index is used in chunked/multipart requests.
-	Index uint32 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
-	// count is the total number of chunks in multipart requests.
-	Count uint32 `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"`
+	// id is the id of the payment.
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// reason is an optional reason recorded in the payment audit log.
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-func (x *RMFetchResource) Reset() {
-	*x = RMFetchResource{}
+func (x *DenyPaymentRequest) Reset() {
+	*x = DenyPaymentRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_clientrpc_proto_msgTypes[69]
+		mi := &file_clientrpc_proto_msgTypes[71]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RMFetchResource) String() string {
+func (x *DenyPaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RMFetchResource) ProtoMessage() {}
+func (*DenyPaymentRequest) ProtoMessage() {}
 
-func (x *RMFetchResource) ProtoReflect() protoreflect.Message {
-	mi := &file_clientrpc_proto_msgTypes[69]
+func (x *DenyPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[71]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4275,90 +4078,49 @@ func (x *RMFetchResource) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RMFetchResource.ProtoReflect.Descriptor instead.
-func (*RMFetchResource) Descriptor() ([]byte, []int) {
-	return file_clientrpc_proto_rawDescGZIP(), []int{69}
-}
-
-func (x *RMFetchResource) GetPath() []string {
-	if x != nil {
-		return x.Path
-	}
-	return nil
-}
-
-func (x *RMFetchResource) GetMeta() map[string]string {
-	if x != nil {
-		return x.Meta
-	}
-	return nil
-}
-
-func (x *RMFetchResource) GetTag() uint64 {
-	if x != nil {
-		return x.Tag
-	}
-	return 0
-}
-
-func (x *RMFetchResource) GetData() []byte {
-	if x != nil {
-		return x.Data
-	}
-	return nil
+// Deprecated: Use DenyPaymentRequest.ProtoReflect.Descriptor instead.
+func (*DenyPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *RMFetchResource) GetIndex() uint32 {
+func (x *DenyPaymentRequest) GetId() uint64 {
 	if x != nil {
-		return x.Index
+		return x.Id
 	}
 	return 0
 }
 
-func (x *RMFetchResource) GetCount() uint32 {
+func (x *DenyPaymentRequest) GetReason() string {
 	if x != nil {
-		return x.Count
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-// RMFetchResourceReply is the lowlevel resource response.
-type RMFetchResourceReply struct {
+// DenyPaymentResponse is the response to denying a payment.
+type DenyPaymentResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// tag is the same as the request tag.
-	Tag uint64 `protobuf:"varint,1,opt,name=tag,proto3" json:"tag,omitempty"`
-	// status is the general status of the response.
-	Status uint32 `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
-	// meta is additional response metadata.
-	Meta map[string]string `protobuf:"bytes,3,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// data is the raw response data.
-	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
-	// index is used in chunked/multipart responses.
-	Index uint32 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
-	// count is the total number of chunks in multipart responses.
-	Count uint32 `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"`
 }
 
-func (x *RMFetchResourceReply) Reset() {
-	*x = RMFetchResourceReply{}
+func (x *DenyPaymentResponse) Reset() {
+	*x = DenyPaymentResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_clientrpc_proto_msgTypes[70]
+		mi := &file_clientrpc_proto_msgTypes[72]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RMFetchResourceReply) String() string {
+func (x *DenyPaymentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RMFetchResourceReply) ProtoMessage() {}
+func (*DenyPaymentResponse) ProtoMessage() {}
 
-func (x *RMFetchResourceReply) ProtoReflect() protoreflect.Message {
-	mi := &file_clientrpc_proto_msgTypes[70]
+func (x *DenyPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[72]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4369,88 +4131,86 @@ func (x *RMFetchResourceReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RMFetchResourceReply.ProtoReflect.Descriptor instead.
-func (*RMFetchResourceReply) Descriptor() ([]byte, []int) {
-	return file_clientrpc_proto_rawDescGZIP(), []int{70}
+// Deprecated: Use DenyPaymentResponse.ProtoReflect.Descriptor instead.
+func (*DenyPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *RMFetchResourceReply) GetTag() uint64 {
-	if x != nil {
-		return x.Tag
-	}
-	return 0
+// ResourceRequestsStreamRequest is the request for a stream to receive resource
+// requests.
+type ResourceRequestsStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *RMFetchResourceReply) GetStatus() uint32 {
-	if x != nil {
-		return x.Status
+func (x *ResourceRequestsStreamRequest) Reset() {
+	*x = ResourceRequestsStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *RMFetchResourceReply) GetMeta() map[string]string {
-	if x != nil {
-		return x.Meta
-	}
-	return nil
+func (x *ResourceRequestsStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *RMFetchResourceReply) GetData() []byte {
-	if x != nil {
-		return x.Data
-	}
-	return nil
-}
+func (*ResourceRequestsStreamRequest) ProtoMessage() {}
 
-func (x *RMFetchResourceReply) GetIndex() uint32 {
-	if x != nil {
-		return x.Index
+func (x *ResourceRequestsStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *RMFetchResourceReply) GetCount() uint32 {
-	if x != nil {
-		return x.Count
-	}
-	return 0
+// Deprecated: Use ResourceRequestsStreamRequest.ProtoReflect.Descriptor instead.
+func (*ResourceRequestsStreamRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{73}
 }
 
-// GCInfo is the summary info for a GC.
-type ListGCsResponse_GCInfo struct {
+// ResourceRequestsStreamResponse is the a request made by a remote client for
+// one of the local client's resources.
+type ResourceRequestsStreamResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// id is the unique GC ID.
-	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// name is the local name/alias for the GC.
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	// version is the current version the GC definition is on.
-	Version uint32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
-	// timestamp is the timestamp of the last modification to the GC definition.
-	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	// nb_members is the total number of members in the GC.
-	NbMembers uint32 `protobuf:"varint,5,opt,name=nb_members,json=nbMembers,proto3" json:"nb_members,omitempty"`
+	// id is an opaque ID to link requests and responses made through the clientrpc
+	// interface.
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// uid is the User ID of the client that requested the resource.
+	Uid []byte `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	// nick is the nick of the client that requested the resource.
+	Nick string `protobuf:"bytes,3,opt,name=nick,proto3" json:"nick,omitempty"`
+	// request is the actual request made by the remote client.
+	Request *RMFetchResource `protobuf:"bytes,4,opt,name=request,proto3" json:"request,omitempty"`
 }
 
-func (x *ListGCsResponse_GCInfo) Reset() {
-	*x = ListGCsResponse_GCInfo{}
+func (x *ResourceRequestsStreamResponse) Reset() {
+	*x = ResourceRequestsStreamResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_clientrpc_proto_msgTypes[71]
+		mi := &file_clientrpc_proto_msgTypes[74]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListGCsResponse_GCInfo) String() string {
+func (x *ResourceRequestsStreamResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListGCsResponse_GCInfo) ProtoMessage() {}
+func (*ResourceRequestsStreamResponse) ProtoMessage() {}
 
-func (x *ListGCsResponse_GCInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_clientrpc_proto_msgTypes[71]
+func (x *ResourceRequestsStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[74]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4461,817 +4221,3765 @@ func (x *ListGCsResponse_GCInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListGCsResponse_GCInfo.ProtoReflect.Descriptor instead.
-func (*ListGCsResponse_GCInfo) Descriptor() ([]byte, []int) {
-	return file_clientrpc_proto_rawDescGZIP(), []int{44, 0}
-}
+// Deprecated: Use ResourceRequestsStreamResponse.ProtoReflect.Descriptor instead.
+func (*ResourceRequestsStreamResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{74}
+}
 
-func (x *ListGCsResponse_GCInfo) GetId() []byte {
+func (x *ResourceRequestsStreamResponse) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
+	return 0
+}
+
+func (x *ResourceRequestsStreamResponse) GetUid() []byte {
+	if x != nil {
+		return x.Uid
+	}
 	return nil
 }
 
-func (x *ListGCsResponse_GCInfo) GetName() string {
+func (x *ResourceRequestsStreamResponse) GetNick() string {
 	if x != nil {
-		return x.Name
+		return x.Nick
 	}
 	return ""
 }
 
-func (x *ListGCsResponse_GCInfo) GetVersion() uint32 {
+func (x *ResourceRequestsStreamResponse) GetRequest() *RMFetchResource {
 	if x != nil {
-		return x.Version
+		return x.Request
+	}
+	return nil
+}
+
+// FulfillResourceRequest fulfills a remote request for a local client resource.
+type FulfillResourceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id must be the corresponding request received by the clientrpc client.
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// error_msg is an optional error message to fail the request with.
+	ErrorMsg string `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	// response is the actual resource that should be sent to the remote client.
+	Response *RMFetchResourceReply `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
+}
+
+func (x *FulfillResourceRequest) Reset() {
+	*x = FulfillResourceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FulfillResourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FulfillResourceRequest) ProtoMessage() {}
+
+func (x *FulfillResourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FulfillResourceRequest.ProtoReflect.Descriptor instead.
+func (*FulfillResourceRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *FulfillResourceRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
 	}
 	return 0
 }
 
-func (x *ListGCsResponse_GCInfo) GetTimestamp() int64 {
+func (x *FulfillResourceRequest) GetErrorMsg() string {
 	if x != nil {
-		return x.Timestamp
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *FulfillResourceRequest) GetResponse() *RMFetchResourceReply {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+// FulfillResourceRequestResponse is the response to a FulfillResourceResquest
+// call.
+type FulfillResourceRequestResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *FulfillResourceRequestResponse) Reset() {
+	*x = FulfillResourceRequestResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FulfillResourceRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FulfillResourceRequestResponse) ProtoMessage() {}
+
+func (x *FulfillResourceRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FulfillResourceRequestResponse.ProtoReflect.Descriptor instead.
+func (*FulfillResourceRequestResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{76}
+}
+
+// StoreProduct is a product of the store.
+type StoreProduct struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// sku is the unique identifier of the product.
+	Sku string `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	// title is the title of the product.
+	Title string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// description is the description of the product.
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// tags are the tags of the product.
+	Tags []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	// price is the price of the product, in the currency of the store.
+	Price float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	// category is the slash separated path of the category of the product.
+	Category string `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	// shipping is true if the product needs to be shipped.
+	Shipping bool `protobuf:"varint,7,opt,name=shipping,proto3" json:"shipping,omitempty"`
+	// digital_file is the file delivered to buyers once their order is paid.
+	DigitalFile string `protobuf:"bytes,8,opt,name=digital_file,json=digitalFile,proto3" json:"digital_file,omitempty"`
+	// has_stock is true if the stock of the product is tracked. Products
+	// without tracked stock have unlimited stock.
+	HasStock bool `protobuf:"varint,9,opt,name=has_stock,json=hasStock,proto3" json:"has_stock,omitempty"`
+	// stock is the number of units available for sale, when has_stock is
+	// true. When adding a product, this is its initial stock. Updating a
+	// product does not change its stock.
+	Stock int64 `protobuf:"varint,10,opt,name=stock,proto3" json:"stock,omitempty"`
+	// archived is true if the product is disabled and not shown in the store.
+	Archived bool `protobuf:"varint,11,opt,name=archived,proto3" json:"archived,omitempty"`
+}
+
+func (x *StoreProduct) Reset() {
+	*x = StoreProduct{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[77]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoreProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreProduct) ProtoMessage() {}
+
+func (x *StoreProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[77]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreProduct.ProtoReflect.Descriptor instead.
+func (*StoreProduct) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *StoreProduct) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *StoreProduct) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *StoreProduct) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *StoreProduct) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *StoreProduct) GetPrice() float64 {
+	if x != nil {
+		return x.Price
 	}
 	return 0
 }
 
-func (x *ListGCsResponse_GCInfo) GetNbMembers() uint32 {
+func (x *StoreProduct) GetCategory() string {
 	if x != nil {
-		return x.NbMembers
+		return x.Category
+	}
+	return ""
+}
+
+func (x *StoreProduct) GetShipping() bool {
+	if x != nil {
+		return x.Shipping
+	}
+	return false
+}
+
+func (x *StoreProduct) GetDigitalFile() string {
+	if x != nil {
+		return x.DigitalFile
+	}
+	return ""
+}
+
+func (x *StoreProduct) GetHasStock() bool {
+	if x != nil {
+		return x.HasStock
+	}
+	return false
+}
+
+func (x *StoreProduct) GetStock() int64 {
+	if x != nil {
+		return x.Stock
 	}
 	return 0
 }
 
-var File_clientrpc_proto protoreflect.FileDescriptor
+func (x *StoreProduct) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
 
-var file_clientrpc_proto_rawDesc = []byte{
-	0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0x10, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x22, 0x6c, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x5f, 0x76, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x70,
-	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x6f, 0x5f, 0x72, 0x75,
-	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x6f, 0x52,
-	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x70, 0x70, 0x5f, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x70, 0x70, 0x4e, 0x61, 0x6d,
-	0x65, 0x22, 0x34, 0x0a, 0x16, 0x4b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74,
-	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x69,
-	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x69,
-	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x2e, 0x0a, 0x0e, 0x4b, 0x65, 0x65, 0x70, 0x61,
-	0x6c, 0x69, 0x76, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x2d, 0x0a, 0x0a, 0x41, 0x63, 0x6b, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
-	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75,
-	0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x0d, 0x0a, 0x0b, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x44, 0x0a, 0x09, 0x50, 0x4d, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x4d, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x0c, 0x0a, 0x0a, 0x50,
-	0x4d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x34, 0x0a, 0x0f, 0x50, 0x4d, 0x53,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c,
-	0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22,
-	0x9b, 0x01, 0x0a, 0x0a, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x4d, 0x12, 0x10,
-	0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x75, 0x69, 0x64,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x69, 0x63, 0x6b, 0x12, 0x23, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x11, 0x2e, 0x52, 0x4d, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
-	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x2e, 0x0a,
-	0x0a, 0x47, 0x43, 0x4d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67,
-	0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x67, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x6d,
-	0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x0d, 0x0a,
-	0x0b, 0x47, 0x43, 0x4d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x35, 0x0a, 0x10,
-	0x47, 0x43, 0x4d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46,
-	0x72, 0x6f, 0x6d, 0x22, 0xb7, 0x01, 0x0a, 0x0d, 0x47, 0x43, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x64, 0x4d, 0x73, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x19, 0x0a, 0x08, 0x67,
-	0x63, 0x5f, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67,
-	0x63, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x21, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
-	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x2d, 0x0a,
-	0x17, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x1a, 0x0a, 0x18,
-	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2f, 0x0a, 0x19, 0x55, 0x6e, 0x73, 0x75,
-	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x1c, 0x0a, 0x1a, 0x55, 0x6e, 0x73,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xbf, 0x01, 0x0a, 0x0b, 0x50, 0x6f, 0x73, 0x74,
-	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x61,
-	0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
-	0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x75, 0x74, 0x68,
-	0x6f, 0x72, 0x5f, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61,
-	0x75, 0x74, 0x68, 0x6f, 0x72, 0x4e, 0x69, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
-	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a,
-	0x0e, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x74, 0x73, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x54, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x22, 0x37, 0x0a, 0x12, 0x50, 0x6f, 0x73,
-	0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72,
-	0x6f, 0x6d, 0x22, 0x99, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50,
-	0x6f, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e,
-	0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f,
-	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x65,
-	0x72, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61,
-	0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x04, 0x70,
-	0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x50, 0x6f, 0x73, 0x74,
-	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x04, 0x70, 0x6f, 0x73, 0x74, 0x22, 0x3d,
-	0x0a, 0x18, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72,
-	0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e,
-	0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0xe5, 0x01,
-	0x0a, 0x12, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x6f, 0x73, 0x74, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65,
-	0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x65, 0x72,
-	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x6c, 0x61, 0x79,
-	0x65, 0x72, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a,
-	0x0b, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x2b,
-	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
-	0x2e, 0x50, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x6e, 0x69, 0x63, 0x6b, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x46, 0x72, 0x6f,
-	0x6d, 0x4e, 0x69, 0x63, 0x6b, 0x22, 0x66, 0x0a, 0x0e, 0x54, 0x69, 0x70, 0x55, 0x73, 0x65, 0x72,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x64,
-	0x63, 0x72, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
-	0x09, 0x64, 0x63, 0x72, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61,
-	0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x22, 0x11, 0x0a,
-	0x0f, 0x54, 0x69, 0x70, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x46, 0x0a, 0x10, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x4b, 0x58, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x6f, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x6f, 0x72,
-	0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x13, 0x0a, 0x11, 0x4d, 0x65, 0x64, 0x69,
-	0x61, 0x74, 0x65, 0x4b, 0x58, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x34, 0x0a,
-	0x0f, 0x4b, 0x58, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46,
-	0x72, 0x6f, 0x6d, 0x22, 0x83, 0x01, 0x0a, 0x0b, 0x4b, 0x58, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
-	0x74, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e,
-	0x63, 0x65, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x2d, 0x0a, 0x12, 0x69, 0x6e,
-	0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x52,
-	0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73, 0x22, 0x48, 0x0a, 0x15, 0x57, 0x72, 0x69,
-	0x74, 0x65, 0x4e, 0x65, 0x77, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
-	0x67, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x66, 0x75, 0x6e, 0x64, 0x41, 0x6d, 0x6f,
-	0x75, 0x6e, 0x74, 0x22, 0x8c, 0x01, 0x0a, 0x16, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x65, 0x77,
-	0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21,
-	0x0a, 0x0c, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65,
-	0x73, 0x12, 0x30, 0x0a, 0x06, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x18, 0x2e, 0x4f, 0x4f, 0x42, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x06, 0x69, 0x6e, 0x76,
-	0x69, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x5f, 0x6b, 0x65,
-	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4b,
-	0x65, 0x79, 0x22, 0x38, 0x0a, 0x13, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x49, 0x6e, 0x76, 0x69,
-	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x76,
-	0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0b, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x48, 0x0a, 0x14,
-	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x4f, 0x4f, 0x42, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
-	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x06,
-	0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x22, 0x37, 0x0a, 0x11, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65,
-	0x54, 0x6f, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67,
-	0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x67, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x75,
-	0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22,
-	0x14, 0x0a, 0x12, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x54, 0x6f, 0x47, 0x43, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x34, 0x0a, 0x15, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x47,
-	0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
-	0x0a, 0x09, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x08, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x49, 0x64, 0x22, 0x18, 0x0a, 0x16, 0x41,
-	0x63, 0x63, 0x65, 0x70, 0x74, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x41, 0x0a, 0x0f, 0x53, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08,
-	0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x12, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x64,
-	0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4f, 0x0a, 0x11,
-	0x4b, 0x69, 0x63, 0x6b, 0x46, 0x72, 0x6f, 0x6d, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x67,
-	0x63, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x14, 0x0a,
-	0x12, 0x4b, 0x69, 0x63, 0x6b, 0x46, 0x72, 0x6f, 0x6d, 0x47, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x1e, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x67, 0x63, 0x22, 0x2d, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x47, 0x43, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0c, 0x2e, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x02,
-	0x67, 0x63, 0x22, 0x10, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x43, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0xc2, 0x01, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x43, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x03, 0x67, 0x63, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x43, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x43, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03,
-	0x67, 0x63, 0x73, 0x1a, 0x83, 0x01, 0x0a, 0x06, 0x47, 0x43, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x62,
-	0x5f, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
-	0x6e, 0x62, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x22, 0x3d, 0x0a, 0x18, 0x52, 0x65, 0x63,
-	0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64,
-	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61,
-	0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0xbc, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x63,
-	0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x12, 0x1f, 0x0a,
-	0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1f,
-	0x0a, 0x0b, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x5f, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x55, 0x69, 0x64, 0x12,
-	0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x5f, 0x6e, 0x69, 0x63, 0x6b, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x4e, 0x69,
-	0x63, 0x6b, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x49, 0x64, 0x12,
-	0x26, 0x0a, 0x06, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52,
-	0x06, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x22, 0x49, 0x0a, 0x0b, 0x55, 0x73, 0x65, 0x72, 0x41,
-	0x6e, 0x64, 0x4e, 0x69, 0x63, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05,
-	0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6b, 0x6e, 0x6f,
-	0x77, 0x6e, 0x22, 0x3a, 0x0a, 0x15, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41,
-	0x64, 0x64, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75,
-	0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0x83,
-	0x01, 0x0a, 0x13, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x65,
-	0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e,
-	0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71,
-	0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x02, 0x67, 0x63, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x63, 0x5f, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x63, 0x4e, 0x61, 0x6d, 0x65,
-	0x12, 0x22, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x41, 0x6e, 0x64, 0x4e, 0x69, 0x63, 0x6b, 0x52, 0x05, 0x75,
-	0x73, 0x65, 0x72, 0x73, 0x22, 0x3c, 0x0a, 0x17, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
-	0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72,
-	0x6f, 0x6d, 0x22, 0x85, 0x01, 0x0a, 0x15, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73,
-	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b,
-	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x0e, 0x0a,
-	0x02, 0x67, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x67, 0x63, 0x12, 0x17, 0x0a,
-	0x07, 0x67, 0x63, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x67, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18,
-	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x41, 0x6e, 0x64, 0x4e,
-	0x69, 0x63, 0x6b, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0x35, 0x0a, 0x10, 0x4a, 0x6f,
-	0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
-	0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f,
-	0x6d, 0x22, 0x4e, 0x0a, 0x0d, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
-	0x65, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0c, 0x2e, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x02, 0x67,
-	0x63, 0x22, 0x37, 0x0a, 0x12, 0x54, 0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b,
-	0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75,
-	0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0xf6, 0x01, 0x0a, 0x10, 0x54,
-	0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
-	0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64,
-	0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x75,
-	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x5f, 0x6d, 0x61, 0x74, 0x6f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x61,
-	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x61, 0x74, 0x6f, 0x6d, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63,
-	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
-	0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74,
-	0x65, 0x6d, 0x70, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65,
-	0x6d, 0x70, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x5f, 0x65,
-	0x72, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70,
-	0x74, 0x45, 0x72, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6c, 0x6c, 0x5f, 0x72, 0x65, 0x74,
-	0x72, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x77, 0x69, 0x6c, 0x6c, 0x52, 0x65,
-	0x74, 0x72, 0x79, 0x22, 0x1f, 0x0a, 0x1d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0x82, 0x01, 0x0a, 0x1e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63,
-	0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x2a, 0x0a,
-	0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
-	0x2e, 0x52, 0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
-	0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x78, 0x0a, 0x16, 0x46, 0x75, 0x6c,
-	0x66, 0x69, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x73, 0x67,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73, 0x67,
-	0x12, 0x31, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x52, 0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x20, 0x0a, 0x1e, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x52, 0x65,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4e, 0x0a, 0x10, 0x52, 0x4d, 0x50, 0x72, 0x69, 0x76, 0x61,
-	0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x0c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52,
-	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x7c, 0x0a, 0x0e, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x67, 0x65, 0x6e,
-	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x20, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x0c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d,
-	0x6f, 0x64, 0x65, 0x22, 0xa6, 0x01, 0x0a, 0x0c, 0x50, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61,
-	0x64, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3d,
-	0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x1a, 0x3d, 0x0a,
-	0x0f, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xda, 0x01, 0x0a,
-	0x12, 0x50, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
-	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f,
-	0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x43, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
-	0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x50, 0x6f, 0x73, 0x74,
-	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x41,
-	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a,
-	0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x41, 0x74,
-	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb5, 0x01, 0x0a, 0x0e, 0x50, 0x75,
-	0x62, 0x6c, 0x69, 0x63, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x69, 0x63, 0x6b, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x67, 0x5f, 0x6b, 0x65, 0x79, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x69, 0x67, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x1a, 0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x64,
-	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67,
-	0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x22, 0xa4, 0x01, 0x0a, 0x0b, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x46, 0x75, 0x6e, 0x64,
-	0x73, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74,
-	0x78, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x72, 0x65, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x72, 0x65, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70,
-	0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x12, 0x1f, 0x0a,
-	0x0b, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x0a, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x48, 0x69, 0x6e, 0x74, 0x12, 0x18,
-	0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x17, 0x4f, 0x4f, 0x42,
-	0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x6e,
-	0x76, 0x69, 0x74, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x2d, 0x0a,
-	0x12, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76,
-	0x6f, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x69, 0x6e, 0x69, 0x74, 0x69,
-	0x61, 0x6c, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73, 0x12, 0x29, 0x0a, 0x10,
-	0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x65, 0x74, 0x72, 0x65, 0x6e,
-	0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73, 0x12, 0x22, 0x0a, 0x05, 0x66, 0x75, 0x6e, 0x64, 0x73,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x46,
-	0x75, 0x6e, 0x64, 0x73, 0x52, 0x05, 0x66, 0x75, 0x6e, 0x64, 0x73, 0x22, 0x9f, 0x01, 0x0a, 0x0d,
-	0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x70,
-	0x69, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x78, 0x70, 0x69,
-	0x72, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xc7, 0x01,
-	0x0a, 0x0b, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
-	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x6d,
-	0x62, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x65, 0x6d, 0x62,
-	0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x61, 0x64, 0x6d,
-	0x69, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61,
-	0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x22, 0xe0, 0x01, 0x0a, 0x0f, 0x52, 0x4d, 0x46, 0x65,
-	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70,
-	0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
-	0x2e, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x52, 0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e,
-	0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12,
-	0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x74, 0x61,
-	0x67, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x63,
-	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x1a, 0x37, 0x0a, 0x09, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
-	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
-	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xee, 0x01, 0x0a, 0x14, 0x52,
-	0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x33, 0x0a,
-	0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x52, 0x4d,
-	0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70,
-	0x6c, 0x79, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x6d, 0x65,
-	0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05,
-	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75,
-	0x6e, 0x74, 0x1a, 0x37, 0x0a, 0x09, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
-	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
-	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x3b, 0x0a, 0x0b, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x45,
-	0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4e, 0x4f, 0x52, 0x4d, 0x41,
-	0x4c, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x4d,
-	0x4f, 0x44, 0x45, 0x5f, 0x4d, 0x45, 0x10, 0x01, 0x32, 0x7d, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x73,
-	0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0f, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0f, 0x4b, 0x65, 0x65, 0x70,
-	0x61, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x17, 0x2e, 0x4b, 0x65,
-	0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x4b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x32, 0xc6, 0x04, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x02, 0x50, 0x4d, 0x12, 0x0a, 0x2e,
-	0x50, 0x4d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b, 0x2e, 0x50, 0x4d, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x08, 0x50, 0x4d, 0x53, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x12, 0x10, 0x2e, 0x50, 0x4d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50,
-	0x4d, 0x30, 0x01, 0x12, 0x2a, 0x0a, 0x0d, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x64, 0x50, 0x4d, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x20, 0x0a, 0x03, 0x47, 0x43, 0x4d, 0x12, 0x0b, 0x2e, 0x47, 0x43, 0x4d, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x47, 0x43, 0x4d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x30, 0x0a, 0x09, 0x47, 0x43, 0x4d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x11,
-	0x2e, 0x47, 0x43, 0x4d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x0e, 0x2e, 0x47, 0x43, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x4d, 0x73,
-	0x67, 0x30, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x64, 0x47, 0x43, 0x4d, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x32, 0x0a, 0x09, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x4b, 0x58, 0x12, 0x11, 0x2e,
-	0x4d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x4b, 0x58, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x12, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x4b, 0x58, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x08, 0x4b, 0x58, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x12, 0x10, 0x2e, 0x4b, 0x58, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x4b, 0x58, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64,
-	0x30, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x41, 0x63, 0x6b, 0x4b, 0x58, 0x43, 0x6f, 0x6d, 0x70, 0x6c,
-	0x65, 0x74, 0x65, 0x64, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x41, 0x0a, 0x0e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x65, 0x77, 0x49, 0x6e, 0x76, 0x69, 0x74,
-	0x65, 0x12, 0x16, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x65, 0x77, 0x49, 0x6e, 0x76, 0x69,
-	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x57, 0x72, 0x69, 0x74,
-	0x65, 0x4e, 0x65, 0x77, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x49, 0x6e, 0x76, 0x69,
-	0x74, 0x65, 0x12, 0x14, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x49, 0x6e, 0x76, 0x69, 0x74,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70,
-	0x74, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x2f, 0x0a, 0x08, 0x53, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x10, 0x2e, 0x53, 0x65,
-	0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e,
-	0x53, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x32, 0xc8, 0x05, 0x0a, 0x09, 0x47, 0x43, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x35,
-	0x0a, 0x0a, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x54, 0x6f, 0x47, 0x43, 0x12, 0x12, 0x2e, 0x49,
-	0x6e, 0x76, 0x69, 0x74, 0x65, 0x54, 0x6f, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x13, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x54, 0x6f, 0x47, 0x43, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x47,
-	0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x12, 0x16, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
-	0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x17, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x4b, 0x69, 0x63, 0x6b,
-	0x46, 0x72, 0x6f, 0x6d, 0x47, 0x43, 0x12, 0x12, 0x2e, 0x4b, 0x69, 0x63, 0x6b, 0x46, 0x72, 0x6f,
-	0x6d, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x4b, 0x69, 0x63,
-	0x6b, 0x46, 0x72, 0x6f, 0x6d, 0x47, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x26, 0x0a, 0x05, 0x47, 0x65, 0x74, 0x47, 0x43, 0x12, 0x0d, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x43,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x43, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12,
-	0x0f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x43, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x10, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x43, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x43, 0x0a, 0x11, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43,
-	0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x64, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x11, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43, 0x49,
-	0x6e, 0x76, 0x69, 0x74, 0x65, 0x30, 0x01, 0x12, 0x31, 0x0a, 0x14, 0x41, 0x63, 0x6b, 0x52, 0x65,
-	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x73, 0x12,
-	0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41,
-	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x4d, 0x65,
-	0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x65, 0x64, 0x12, 0x16, 0x2e, 0x47, 0x43, 0x4d,
-	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64,
-	0x64, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x41, 0x63,
-	0x6b, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x65, 0x64, 0x12, 0x0b, 0x2e,
-	0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0e, 0x4d, 0x65, 0x6d, 0x62,
-	0x65, 0x72, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x18, 0x2e, 0x47, 0x43, 0x4d,
-	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73,
-	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x2e,
-	0x0a, 0x11, 0x41, 0x63, 0x6b, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6d, 0x6f,
-	0x76, 0x65, 0x64, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30,
-	0x0a, 0x09, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x73, 0x12, 0x11, 0x2e, 0x4a, 0x6f,
-	0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e,
-	0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01,
-	0x12, 0x29, 0x0a, 0x0c, 0x41, 0x63, 0x6b, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x73,
-	0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e,
-	0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x84, 0x03, 0x0a, 0x0c,
-	0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x10,
-	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73,
-	0x12, 0x18, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f,
-	0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x53, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x12, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x55, 0x6e,
-	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x0b, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72,
-	0x65, 0x61, 0x6d, 0x12, 0x13, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61,
-	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69,
-	0x76, 0x65, 0x64, 0x50, 0x6f, 0x73, 0x74, 0x30, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x41, 0x63, 0x6b,
-	0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x6f, 0x73, 0x74, 0x12, 0x0b, 0x2e, 0x41,
-	0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x11, 0x50, 0x6f, 0x73, 0x74, 0x73,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x19, 0x2e, 0x50,
-	0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x64, 0x50, 0x6f, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x30, 0x01, 0x12, 0x32,
-	0x0a, 0x15, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x6f, 0x73,
-	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x32, 0xa5, 0x01, 0x0a, 0x0f, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x54, 0x69, 0x70, 0x55, 0x73, 0x65,
-	0x72, 0x12, 0x0f, 0x2e, 0x54, 0x69, 0x70, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x54, 0x69, 0x70, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0b, 0x54, 0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72,
-	0x65, 0x73, 0x73, 0x12, 0x13, 0x2e, 0x54, 0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x54, 0x69, 0x70, 0x50, 0x72,
-	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x2b, 0x0a,
-	0x0e, 0x41, 0x63, 0x6b, 0x54, 0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12,
-	0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41,
-	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xb3, 0x01, 0x0a, 0x10, 0x52,
-	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
-	0x53, 0x0a, 0x0e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61,
-	0x6d, 0x12, 0x1e, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1f, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x30, 0x01, 0x12, 0x4a, 0x0a, 0x0e, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x2e, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c,
-	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1f, 0x2e, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
-	0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x7a, 0x65, 0x72, 0x6f, 0x2f, 0x62, 0x69, 0x73, 0x63, 0x6f,
-	0x6e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x72, 0x70, 0x63,
-	0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+// ListProductsRequest is the request to list the products of the store.
+type ListProductsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// include_archived also lists the archived products.
+	IncludeArchived bool `protobuf:"varint,1,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
 }
 
-var (
-	file_clientrpc_proto_rawDescOnce sync.Once
-	file_clientrpc_proto_rawDescData = file_clientrpc_proto_rawDesc
-)
+func (x *ListProductsRequest) Reset() {
+	*x = ListProductsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[78]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_clientrpc_proto_rawDescGZIP() []byte {
-	file_clientrpc_proto_rawDescOnce.Do(func() {
-		file_clientrpc_proto_rawDescData = protoimpl.X.CompressGZIP(file_clientrpc_proto_rawDescData)
-	})
-	return file_clientrpc_proto_rawDescData
+func (x *ListProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_clientrpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_clientrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 76)
-var file_clientrpc_proto_goTypes = []interface{}{
-	(MessageMode)(0),                       // 0: MessageMode
-	(*VersionRequest)(nil),                 // 1: VersionRequest
-	(*VersionResponse)(nil),                // 2: VersionResponse
-	(*KeepaliveStreamRequest)(nil),         // 3: KeepaliveStreamRequest
-	(*KeepaliveEvent)(nil),                 // 4: KeepaliveEvent
-	(*AckRequest)(nil),                     // 5: AckRequest
-	(*AckResponse)(nil),                    // 6: AckResponse
-	(*PMRequest)(nil),                      // 7: PMRequest
-	(*PMResponse)(nil),                     // 8: PMResponse
-	(*PMStreamRequest)(nil),                // 9: PMStreamRequest
-	(*ReceivedPM)(nil),                     // 10: ReceivedPM
-	(*GCMRequest)(nil),                     // 11: GCMRequest
-	(*GCMResponse)(nil),                    // 12: GCMResponse
-	(*GCMStreamRequest)(nil),               // 13: GCMStreamRequest
-	(*GCReceivedMsg)(nil),                  // 14: GCReceivedMsg
-	(*SubscribeToPostsRequest)(nil),        // 15: SubscribeToPostsRequest
-	(*SubscribeToPostsResponse)(nil),       // 16: SubscribeToPostsResponse
-	(*UnsubscribeToPostsRequest)(nil),      // 17: UnsubscribeToPostsRequest
-	(*UnsubscribeToPostsResponse)(nil),     // 18: UnsubscribeToPostsResponse
-	(*PostSummary)(nil),                    // 19: PostSummary
-	(*PostsStreamRequest)(nil),             // 20: PostsStreamRequest
-	(*ReceivedPost)(nil),                   // 21: ReceivedPost
-	(*PostsStatusStreamRequest)(nil),       // 22: PostsStatusStreamRequest
-	(*ReceivedPostStatus)(nil),             // 23: ReceivedPostStatus
-	(*TipUserRequest)(nil),                 // 24: TipUserRequest
-	(*TipUserResponse)(nil),                // 25: TipUserResponse
-	(*MediateKXRequest)(nil),               // 26: MediateKXRequest
-	(*MediateKXResponse)(nil),              // 27: MediateKXResponse
-	(*KXStreamRequest)(nil),                // 28: KXStreamRequest
-	(*KXCompleted)(nil),                    // 29: KXCompleted
-	(*WriteNewInviteRequest)(nil),          // 30: WriteNewInviteRequest
-	(*WriteNewInviteResponse)(nil),         // 31: WriteNewInviteResponse
-	(*AcceptInviteRequest)(nil),            // 32: AcceptInviteRequest
-	(*AcceptInviteResponse)(nil),           // 33: AcceptInviteResponse
-	(*InviteToGCRequest)(nil),              // 34: InviteToGCRequest
-	(*InviteToGCResponse)(nil),             // 35: InviteToGCResponse
-	(*AcceptGCInviteRequest)(nil),          // 36: AcceptGCInviteRequest
-	(*AcceptGCInviteResponse)(nil),         // 37: AcceptGCInviteResponse
-	(*SendFileRequest)(nil),                // 38: SendFileRequest
-	(*SendFileResponse)(nil),               // 39: SendFileResponse
-	(*KickFromGCRequest)(nil),              // 40: KickFromGCRequest
-	(*KickFromGCResponse)(nil),             // 41: KickFromGCResponse
-	(*GetGCRequest)(nil),                   // 42: GetGCRequest
-	(*GetGCResponse)(nil),                  // 43: GetGCResponse
-	(*ListGCsRequest)(nil),                 // 44: ListGCsRequest
-	(*ListGCsResponse)(nil),                // 45: ListGCsResponse
-	(*ReceivedGCInvitesRequest)(nil),       // 46: ReceivedGCInvitesRequest
-	(*ReceivedGCInvite)(nil),               // 47: ReceivedGCInvite
-	(*UserAndNick)(nil),                    // 48: UserAndNick
-	(*GCMembersAddedRequest)(nil),          // 49: GCMembersAddedRequest
-	(*GCMembersAddedEvent)(nil),            // 50: GCMembersAddedEvent
-	(*GCMembersRemovedRequest)(nil),        // 51: GCMembersRemovedRequest
-	(*GCMembersRemovedEvent)(nil),          // 52: GCMembersRemovedEvent
-	(*JoinedGCsRequest)(nil),               // 53: JoinedGCsRequest
-	(*JoinedGCEvent)(nil),                  // 54: JoinedGCEvent
-	(*TipProgressRequest)(nil),             // 55: TipProgressRequest
-	(*TipProgressEvent)(nil),               // 56: TipProgressEvent
-	(*ResourceRequestsStreamRequest)(nil),  // 57: ResourceRequestsStreamRequest
-	(*ResourceRequestsStreamResponse)(nil), // 58: ResourceRequestsStreamResponse
-	(*FulfillResourceRequest)(nil),         // 59: FulfillResourceRequest
-	(*FulfillResourceRequestResponse)(nil), // 60: FulfillResourceRequestResponse
-	(*RMPrivateMessage)(nil),               // 61: RMPrivateMessage
-	(*RMGroupMessage)(nil),                 // 62: RMGroupMessage
-	(*PostMetadata)(nil),                   // 63: PostMetadata
-	(*PostMetadataStatus)(nil),             // 64: PostMetadataStatus
-	(*PublicIdentity)(nil),                 // 65: PublicIdentity
-	(*InviteFunds)(nil),                    // 66: InviteFunds
-	(*OOBPublicIdentityInvite)(nil),        // 67: OOBPublicIdentityInvite
-	(*RMGroupInvite)(nil),                  // 68: RMGroupInvite
-	(*RMGroupList)(nil),                    // 69: RMGroupList
-	(*RMFetchResource)(nil),                // 70: RMFetchResource
-	(*RMFetchResourceReply)(nil),           // 71: RMFetchResourceReply
-	(*ListGCsResponse_GCInfo)(nil),         // 72: ListGCsResponse.GCInfo
-	nil,                                    // 73: PostMetadata.AttributesEntry
-	nil,                                    // 74: PostMetadataStatus.AttributesEntry
-	nil,                                    // 75: RMFetchResource.MetaEntry
-	nil,                                    // 76: RMFetchResourceReply.MetaEntry
+func (*ListProductsRequest) ProtoMessage() {}
+
+func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[78]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_clientrpc_proto_depIdxs = []int32{
-	61, // 0: PMRequest.msg:type_name -> RMPrivateMessage
-	61, // 1: ReceivedPM.msg:type_name -> RMPrivateMessage
-	62, // 2: GCReceivedMsg.msg:type_name -> RMGroupMessage
-	19, // 3: ReceivedPost.summary:type_name -> PostSummary
-	63, // 4: ReceivedPost.post:type_name -> PostMetadata
-	64, // 5: ReceivedPostStatus.status:type_name -> PostMetadataStatus
-	67, // 6: WriteNewInviteResponse.invite:type_name -> OOBPublicIdentityInvite
-	67, // 7: AcceptInviteResponse.invite:type_name -> OOBPublicIdentityInvite
-	69, // 8: GetGCResponse.gc:type_name -> RMGroupList
-	72, // 9: ListGCsResponse.gcs:type_name -> ListGCsResponse.GCInfo
-	68, // 10: ReceivedGCInvite.invite:type_name -> RMGroupInvite
-	48, // 11: GCMembersAddedEvent.users:type_name -> UserAndNick
-	48, // 12: GCMembersRemovedEvent.users:type_name -> UserAndNick
-	69, // 13: JoinedGCEvent.gc:type_name -> RMGroupList
-	70, // 14: ResourceRequestsStreamResponse.request:type_name -> RMFetchResource
-	71, // 15: FulfillResourceRequest.response:type_name -> RMFetchResourceReply
-	0,  // 16: RMPrivateMessage.mode:type_name -> MessageMode
-	0,  // 17: RMGroupMessage.mode:type_name -> MessageMode
-	73, // 18: PostMetadata.attributes:type_name -> PostMetadata.AttributesEntry
-	74, // 19: PostMetadataStatus.attributes:type_name -> PostMetadataStatus.AttributesEntry
-	65, // 20: OOBPublicIdentityInvite.public:type_name -> PublicIdentity
-	66, // 21: OOBPublicIdentityInvite.funds:type_name -> InviteFunds
-	75, // 22: RMFetchResource.meta:type_name -> RMFetchResource.MetaEntry
-	76, // 23: RMFetchResourceReply.meta:type_name -> RMFetchResourceReply.MetaEntry
-	1,  // 24: VersionService.Version:input_type -> VersionRequest
-	3,  // 25: VersionService.KeepaliveStream:input_type -> KeepaliveStreamRequest
-	7,  // 26: ChatService.PM:input_type -> PMRequest
-	9,  // 27: ChatService.PMStream:input_type -> PMStreamRequest
-	5,  // 28: ChatService.AckReceivedPM:input_type -> AckRequest
-	11, // 29: ChatService.GCM:input_type -> GCMRequest
-	13, // 30: ChatService.GCMStream:input_type -> GCMStreamRequest
-	5,  // 31: ChatService.AckReceivedGCM:input_type -> AckRequest
-	26, // 32: ChatService.MediateKX:input_type -> MediateKXRequest
-	28, // 33: ChatService.KXStream:input_type -> KXStreamRequest
-	5,  // 34: ChatService.AckKXCompleted:input_type -> AckRequest
-	30, // 35: ChatService.WriteNewInvite:input_type -> WriteNewInviteRequest
-	32, // 36: ChatService.AcceptInvite:input_type -> AcceptInviteRequest
-	38, // 37: ChatService.SendFile:input_type -> SendFileRequest
-	34, // 38: GCService.InviteToGC:input_type -> InviteToGCRequest
-	36, // 39: GCService.AcceptGCInvite:input_type -> AcceptGCInviteRequest
-	40, // 40: GCService.KickFromGC:input_type -> KickFromGCRequest
-	42, // 41: GCService.GetGC:input_type -> GetGCRequest
-	44, // 42: GCService.List:input_type -> ListGCsRequest
-	46, // 43: GCService.ReceivedGCInvites:input_type -> ReceivedGCInvitesRequest
-	5,  // 44: GCService.AckReceivedGCInvites:input_type -> AckRequest
-	49, // 45: GCService.MembersAdded:input_type -> GCMembersAddedRequest
-	5,  // 46: GCService.AckMembersAdded:input_type -> AckRequest
-	51, // 47: GCService.MembersRemoved:input_type -> GCMembersRemovedRequest
-	5,  // 48: GCService.AckMembersRemoved:input_type -> AckRequest
-	53, // 49: GCService.JoinedGCs:input_type -> JoinedGCsRequest
-	5,  // 50: GCService.AckJoinedGCs:input_type -> AckRequest
-	15, // 51: PostsService.SubscribeToPosts:input_type -> SubscribeToPostsRequest
-	17, // 52: PostsService.UnsubscribeToPosts:input_type -> UnsubscribeToPostsRequest
-	20, // 53: PostsService.PostsStream:input_type -> PostsStreamRequest
-	5,  // 54: PostsService.AckReceivedPost:input_type -> AckRequest
-	22, // 55: PostsService.PostsStatusStream:input_type -> PostsStatusStreamRequest
-	5,  // 56: PostsService.AckReceivedPostStatus:input_type -> AckRequest
-	24, // 57: PaymentsService.TipUser:input_type -> TipUserRequest
-	55, // 58: PaymentsService.TipProgress:input_type -> TipProgressRequest
-	5,  // 59: PaymentsService.AckTipProgress:input_type -> AckRequest
-	57, // 60: ResourcesService.RequestsStream:input_type -> ResourceRequestsStreamRequest
-	59, // 61: ResourcesService.FulfillRequest:input_type -> FulfillResourceRequest
-	2,  // 62: VersionService.Version:output_type -> VersionResponse
-	4,  // 63: VersionService.KeepaliveStream:output_type -> KeepaliveEvent
-	8,  // 64: ChatService.PM:output_type -> PMResponse
-	10, // 65: ChatService.PMStream:output_type -> ReceivedPM
-	6,  // 66: ChatService.AckReceivedPM:output_type -> AckResponse
-	12, // 67: ChatService.GCM:output_type -> GCMResponse
-	14, // 68: ChatService.GCMStream:output_type -> GCReceivedMsg
-	6,  // 69: ChatService.AckReceivedGCM:output_type -> AckResponse
-	27, // 70: ChatService.MediateKX:output_type -> MediateKXResponse
-	29, // 71: ChatService.KXStream:output_type -> KXCompleted
-	6,  // 72: ChatService.AckKXCompleted:output_type -> AckResponse
-	31, // 73: ChatService.WriteNewInvite:output_type -> WriteNewInviteResponse
-	33, // 74: ChatService.AcceptInvite:output_type -> AcceptInviteResponse
-	39, // 75: ChatService.SendFile:output_type -> SendFileResponse
-	35, // 76: GCService.InviteToGC:output_type -> InviteToGCResponse
-	37, // 77: GCService.AcceptGCInvite:output_type -> AcceptGCInviteResponse
-	41, // 78: GCService.KickFromGC:output_type -> KickFromGCResponse
-	43, // 79: GCService.GetGC:output_type -> GetGCResponse
-	45, // 80: GCService.List:output_type -> ListGCsResponse
-	47, // 81: GCService.ReceivedGCInvites:output_type -> ReceivedGCInvite
-	6,  // 82: GCService.AckReceivedGCInvites:output_type -> AckResponse
-	50, // 83: GCService.MembersAdded:output_type -> GCMembersAddedEvent
-	6,  // 84: GCService.AckMembersAdded:output_type -> AckResponse
-	52, // 85: GCService.MembersRemoved:output_type -> GCMembersRemovedEvent
-	6,  // 86: GCService.AckMembersRemoved:output_type -> AckResponse
-	54, // 87: GCService.JoinedGCs:output_type -> JoinedGCEvent
-	6,  // 88: GCService.AckJoinedGCs:output_type -> AckResponse
-	16, // 89: PostsService.SubscribeToPosts:output_type -> SubscribeToPostsResponse
-	18, // 90: PostsService.UnsubscribeToPosts:output_type -> UnsubscribeToPostsResponse
-	21, // 91: PostsService.PostsStream:output_type -> ReceivedPost
-	6,  // 92: PostsService.AckReceivedPost:output_type -> AckResponse
-	23, // 93: PostsService.PostsStatusStream:output_type -> ReceivedPostStatus
-	6,  // 94: PostsService.AckReceivedPostStatus:output_type -> AckResponse
-	25, // 95: PaymentsService.TipUser:output_type -> TipUserResponse
-	56, // 96: PaymentsService.TipProgress:output_type -> TipProgressEvent
-	6,  // 97: PaymentsService.AckTipProgress:output_type -> AckResponse
-	58, // 98: ResourcesService.RequestsStream:output_type -> ResourceRequestsStreamResponse
-	60, // 99: ResourcesService.FulfillRequest:output_type -> FulfillResourceRequestResponse
-	62, // [62:100] is the sub-list for method output_type
-	24, // [24:62] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+
+// Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
+func (*ListProductsRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{78}
 }
 
-func init() { file_clientrpc_proto_init() }
-func file_clientrpc_proto_init() {
-	if File_clientrpc_proto != nil {
-		return
+func (x *ListProductsRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_clientrpc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VersionRequest); i {
+	return false
+}
+
+// ListProductsResponse is the list of products of the store.
+type ListProductsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// products are the products of the store, sorted by SKU.
+	Products []*StoreProduct `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+}
+
+func (x *ListProductsResponse) Reset() {
+	*x = ListProductsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[79]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsResponse) ProtoMessage() {}
+
+func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[79]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
+func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *ListProductsResponse) GetProducts() []*StoreProduct {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+// AddProductRequest is the request to add a product to the store.
+type AddProductRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// product is the product to add. Its SKU must not be used by an existing
+	// product.
+	Product *StoreProduct `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+}
+
+func (x *AddProductRequest) Reset() {
+	*x = AddProductRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[80]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductRequest) ProtoMessage() {}
+
+func (x *AddProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[80]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductRequest.ProtoReflect.Descriptor instead.
+func (*AddProductRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *AddProductRequest) GetProduct() *StoreProduct {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+// AddProductResponse is the response to an AddProduct call.
+type AddProductResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// product is the added product.
+	Product *StoreProduct `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+}
+
+func (x *AddProductResponse) Reset() {
+	*x = AddProductResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[81]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductResponse) ProtoMessage() {}
+
+func (x *AddProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[81]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductResponse.ProtoReflect.Descriptor instead.
+func (*AddProductResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *AddProductResponse) GetProduct() *StoreProduct {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+// UpdateProductRequest is the request to update a product of the store.
+type UpdateProductRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// product is the new definition of the product with the same SKU.
+	Product *StoreProduct `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[82]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[82]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *UpdateProductRequest) GetProduct() *StoreProduct {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+// UpdateProductResponse is the response to an UpdateProduct call.
+type UpdateProductResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// product is the updated product.
+	Product *StoreProduct `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+}
+
+func (x *UpdateProductResponse) Reset() {
+	*x = UpdateProductResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[83]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductResponse) ProtoMessage() {}
+
+func (x *UpdateProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[83]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductResponse.ProtoReflect.Descriptor instead.
+func (*UpdateProductResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *UpdateProductResponse) GetProduct() *StoreProduct {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+// StoreOrderItem is an item of an order.
+type StoreOrderItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// sku is the SKU of the ordered product.
+	Sku string `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	// title is the title of the ordered product.
+	Title string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// quantity is the number of ordered units.
+	Quantity uint32 `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// price is the unit price of the product when the order was placed.
+	Price float64 `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+}
+
+func (x *StoreOrderItem) Reset() {
+	*x = StoreOrderItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[84]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoreOrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreOrderItem) ProtoMessage() {}
+
+func (x *StoreOrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[84]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreOrderItem.ProtoReflect.Descriptor instead.
+func (*StoreOrderItem) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *StoreOrderItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *StoreOrderItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *StoreOrderItem) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *StoreOrderItem) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+// StoreOrder is an order placed in the store.
+type StoreOrder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id is the ID of the order. IDs are unique per user.
+	Id uint32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// user is the ID of the user that placed the order.
+	User []byte `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	// status is the status of the order.
+	Status string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// placed_ts is the unix timestamp of when the order was placed.
+	PlacedTs int64 `protobuf:"varint,4,opt,name=placed_ts,json=placedTs,proto3" json:"placed_ts,omitempty"`
+	// currency is the currency of the amounts of the order.
+	Currency string `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	// total_cents is the total amount of the order (including shipping), in
+	// cents.
+	TotalCents int64 `protobuf:"varint,6,opt,name=total_cents,json=totalCents,proto3" json:"total_cents,omitempty"`
+	// pay_type is how the order is paid.
+	PayType string `protobuf:"bytes,7,opt,name=pay_type,json=payType,proto3" json:"pay_type,omitempty"`
+	// items are the ordered items.
+	Items []*StoreOrderItem `protobuf:"bytes,8,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *StoreOrder) Reset() {
+	*x = StoreOrder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[85]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoreOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreOrder) ProtoMessage() {}
+
+func (x *StoreOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[85]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreOrder.ProtoReflect.Descriptor instead.
+func (*StoreOrder) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *StoreOrder) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StoreOrder) GetUser() []byte {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *StoreOrder) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StoreOrder) GetPlacedTs() int64 {
+	if x != nil {
+		return x.PlacedTs
+	}
+	return 0
+}
+
+func (x *StoreOrder) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *StoreOrder) GetTotalCents() int64 {
+	if x != nil {
+		return x.TotalCents
+	}
+	return 0
+}
+
+func (x *StoreOrder) GetPayType() string {
+	if x != nil {
+		return x.PayType
+	}
+	return ""
+}
+
+func (x *StoreOrder) GetItems() []*StoreOrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// ListOrdersRequest is the request to list the orders placed in the store.
+type ListOrdersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// status, if set, only lists the orders in this status.
+	Status string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[86]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[86]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ListOrdersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// ListOrdersResponse is the list of orders placed in the store.
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// orders are the orders, sorted by the time they were placed.
+	Orders []*StoreOrder `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[87]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[87]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*StoreOrder {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+// UpdateOrderStatusRequest is the request to change the status of an order.
+type UpdateOrderStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// user is the ID of the user that placed the order.
+	User []byte `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// id is the ID of the order.
+	Id uint32 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	// status is the new status of the order.
+	Status string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[88]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[88]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *UpdateOrderStatusRequest) GetUser() []byte {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UpdateOrderStatusRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateOrderStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// UpdateOrderStatusResponse is the response to an UpdateOrderStatus call.
+type UpdateOrderStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateOrderStatusResponse) Reset() {
+	*x = UpdateOrderStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[89]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateOrderStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusResponse) ProtoMessage() {}
+
+func (x *UpdateOrderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[89]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{89}
+}
+
+// SalesTotalsRequest is the request for the totals of the sales of the store.
+type SalesTotalsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// since, if set, only counts the orders placed after this unix timestamp.
+	Since int64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+}
+
+func (x *SalesTotalsRequest) Reset() {
+	*x = SalesTotalsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[90]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SalesTotalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SalesTotalsRequest) ProtoMessage() {}
+
+func (x *SalesTotalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[90]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SalesTotalsRequest.ProtoReflect.Descriptor instead.
+func (*SalesTotalsRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *SalesTotalsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+// SalesTotal is the total of sales in a currency.
+type SalesTotal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// currency is the currency of the sales.
+	Currency string `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	// total_cents is the total amount of sales, in cents.
+	TotalCents int64 `protobuf:"varint,2,opt,name=total_cents,json=totalCents,proto3" json:"total_cents,omitempty"`
+}
+
+func (x *SalesTotal) Reset() {
+	*x = SalesTotal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[91]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SalesTotal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SalesTotal) ProtoMessage() {}
+
+func (x *SalesTotal) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[91]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SalesTotal.ProtoReflect.Descriptor instead.
+func (*SalesTotal) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *SalesTotal) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SalesTotal) GetTotalCents() int64 {
+	if x != nil {
+		return x.TotalCents
+	}
+	return 0
+}
+
+// SalesTotalsResponse is the totals of the sales of the store.
+type SalesTotalsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// count is the number of orders counted as sales.
+	Count uint32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// totals are the totals in each currency, sorted by currency.
+	Totals []*SalesTotal `protobuf:"bytes,2,rep,name=totals,proto3" json:"totals,omitempty"`
+}
+
+func (x *SalesTotalsResponse) Reset() {
+	*x = SalesTotalsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[92]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SalesTotalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SalesTotalsResponse) ProtoMessage() {}
+
+func (x *SalesTotalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[92]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SalesTotalsResponse.ProtoReflect.Descriptor instead.
+func (*SalesTotalsResponse) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *SalesTotalsResponse) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SalesTotalsResponse) GetTotals() []*SalesTotal {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+// StoreEventsRequest is the request to start a stream of store events.
+type StoreEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// unacked_from specifies to the server the sequence_id of the last received
+	// store event. Events received by the server that have a higher
+	// sequence_id will be streamed back to the client.
+	UnackedFrom uint64 `protobuf:"varint,1,opt,name=unacked_from,json=unackedFrom,proto3" json:"unacked_from,omitempty"`
+}
+
+func (x *StoreEventsRequest) Reset() {
+	*x = StoreEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[93]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoreEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreEventsRequest) ProtoMessage() {}
+
+func (x *StoreEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[93]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreEventsRequest.ProtoReflect.Descriptor instead.
+func (*StoreEventsRequest) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *StoreEventsRequest) GetUnackedFrom() uint64 {
+	if x != nil {
+		return x.UnackedFrom
+	}
+	return 0
+}
+
+// StoreEvent is an activity event of the store.
+type StoreEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// sequence_id is an opaque sequential ID.
+	SequenceId uint64 `protobuf:"varint,1,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	// type is the type of the event: cart.updated, order.placed,
+	// order.paid or order.status.
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// timestamp is the unix timestamp of when the event was generated.
+	Timestamp int64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// user is the ID of the user whose cart or order is the subject of the
+	// event.
+	User []byte `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	// cart_items are the items of the updated cart, in cart.updated events.
+	// An empty list means the cart was cleared.
+	CartItems []*StoreOrderItem `protobuf:"bytes,5,rep,name=cart_items,json=cartItems,proto3" json:"cart_items,omitempty"`
+	// cart_total_cents is the total amount of the updated cart, in cents.
+	CartTotalCents int64 `protobuf:"varint,6,opt,name=cart_total_cents,json=cartTotalCents,proto3" json:"cart_total_cents,omitempty"`
+	// order is the order, in order events.
+	Order *StoreOrder `protobuf:"bytes,7,opt,name=order,proto3" json:"order,omitempty"`
+	// old_status is the status of the order before it changed, in
+	// order.paid and order.status events.
+	OldStatus string `protobuf:"bytes,8,opt,name=old_status,json=oldStatus,proto3" json:"old_status,omitempty"`
+}
+
+func (x *StoreEvent) Reset() {
+	*x = StoreEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[94]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoreEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreEvent) ProtoMessage() {}
+
+func (x *StoreEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[94]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreEvent.ProtoReflect.Descriptor instead.
+func (*StoreEvent) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *StoreEvent) GetSequenceId() uint64 {
+	if x != nil {
+		return x.SequenceId
+	}
+	return 0
+}
+
+func (x *StoreEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *StoreEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *StoreEvent) GetUser() []byte {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *StoreEvent) GetCartItems() []*StoreOrderItem {
+	if x != nil {
+		return x.CartItems
+	}
+	return nil
+}
+
+func (x *StoreEvent) GetCartTotalCents() int64 {
+	if x != nil {
+		return x.CartTotalCents
+	}
+	return 0
+}
+
+func (x *StoreEvent) GetOrder() *StoreOrder {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *StoreEvent) GetOldStatus() string {
+	if x != nil {
+		return x.OldStatus
+	}
+	return ""
+}
+
+// RMPrivateMessage is the network-level routed private message.
+type RMPrivateMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// message is the private message payload.
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// mode is the message mode.
+	Mode MessageMode `protobuf:"varint,2,opt,name=mode,proto3,enum=MessageMode" json:"mode,omitempty"`
+}
+
+func (x *RMPrivateMessage) Reset() {
+	*x = RMPrivateMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[95]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RMPrivateMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RMPrivateMessage) ProtoMessage() {}
+
+func (x *RMPrivateMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[95]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RMPrivateMessage.ProtoReflect.Descriptor instead.
+func (*RMPrivateMessage) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *RMPrivateMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RMPrivateMessage) GetMode() MessageMode {
+	if x != nil {
+		return x.Mode
+	}
+	return MessageMode_MESSAGE_MODE_NORMAL
+}
+
+// RMGroupMessage is the network-level routed group message.
+type RMGroupMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id is the group chat id where the message was sent.
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// generation is the internal generation of the group chat metadata when the
+	// sender sent this message.
+	Generation uint64 `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
+	// message is the textual content.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// mode is the mode of the message.
+	Mode MessageMode `protobuf:"varint,4,opt,name=mode,proto3,enum=MessageMode" json:"mode,omitempty"`
+}
+
+func (x *RMGroupMessage) Reset() {
+	*x = RMGroupMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[96]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RMGroupMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RMGroupMessage) ProtoMessage() {}
+
+func (x *RMGroupMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[96]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RMGroupMessage.ProtoReflect.Descriptor instead.
+func (*RMGroupMessage) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *RMGroupMessage) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *RMGroupMessage) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *RMGroupMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RMGroupMessage) GetMode() MessageMode {
+	if x != nil {
+		return x.Mode
+	}
+	return MessageMode_MESSAGE_MODE_NORMAL
+}
+
+// PostMetadata is the network-level post data.
+type PostMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// version defines the available fields within attributes.
+	Version uint64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// attributes defines the available post attributes.
+	Attributes map[string]string `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *PostMetadata) Reset() {
+	*x = PostMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[97]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PostMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostMetadata) ProtoMessage() {}
+
+func (x *PostMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[97]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostMetadata.ProtoReflect.Descriptor instead.
+func (*PostMetadata) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *PostMetadata) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PostMetadata) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+// PostMetadataStatus is the network-level post status update data.
+type PostMetadataStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// version defines the available fields within attributes.
+	Version uint64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// from is the UID of the original status creator.
+	From string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	// link is the ID of the post.
+	Link string `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
+	// attributes is the list of post update attributes.
+	Attributes map[string]string `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *PostMetadataStatus) Reset() {
+	*x = PostMetadataStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[98]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PostMetadataStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostMetadataStatus) ProtoMessage() {}
+
+func (x *PostMetadataStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[98]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostMetadataStatus.ProtoReflect.Descriptor instead.
+func (*PostMetadataStatus) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *PostMetadataStatus) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PostMetadataStatus) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *PostMetadataStatus) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *PostMetadataStatus) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+// PublicIdentity is the lowlevel public identity.
+type PublicIdentity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name is the name of the user.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// nick is the short name/alias of the user.
+	Nick string `protobuf:"bytes,2,opt,name=nick,proto3" json:"nick,omitempty"`
+	// sig_key is the signature key used to authenticate messages from the user.
+	SigKey []byte `protobuf:"bytes,3,opt,name=sig_key,json=sigKey,proto3" json:"sig_key,omitempty"`
+	// key is the NTRU public key of the user.
+	Key []byte `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	// identity is the public ID of the user.
+	Identity []byte `protobuf:"bytes,5,opt,name=identity,proto3" json:"identity,omitempty"`
+	// digest is a hash of the user's public information.
+	Digest []byte `protobuf:"bytes,6,opt,name=digest,proto3" json:"digest,omitempty"`
+	// signature is a signature of the user's public information.
+	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *PublicIdentity) Reset() {
+	*x = PublicIdentity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[99]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublicIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicIdentity) ProtoMessage() {}
+
+func (x *PublicIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[99]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicIdentity.ProtoReflect.Descriptor instead.
+func (*PublicIdentity) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *PublicIdentity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PublicIdentity) GetNick() string {
+	if x != nil {
+		return x.Nick
+	}
+	return ""
+}
+
+func (x *PublicIdentity) GetSigKey() []byte {
+	if x != nil {
+		return x.SigKey
+	}
+	return nil
+}
+
+func (x *PublicIdentity) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *PublicIdentity) GetIdentity() []byte {
+	if x != nil {
+		return x.Identity
+	}
+	return nil
+}
+
+func (x *PublicIdentity) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *PublicIdentity) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// InviteFunds is the lowlevel information about funds added to an invite.
+type InviteFunds struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// tx is the hash of the transaction that includes the funds.
+	Tx string `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	// index is the output index of the transaction that includes the funds.
+	Index uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	// tree is the tree of the transaction that includes the funds.
+	Tree int32 `protobuf:"varint,3,opt,name=tree,proto3" json:"tree,omitempty"`
+	// private_key is the private key needed to redeem the funds in the transaction.
+	PrivateKey string `protobuf:"bytes,4,opt,name=private_key,proto3" json:"private_key,omitempty"`
+	// height_hint is a height at or before the transaction that includes the funds
+	// is mined in.
+	HeightHint uint32 `protobuf:"varint,5,opt,name=height_hint,json=heightHint,proto3" json:"height_hint,omitempty"`
+	// address is the public address that the funds were sent to.
+	Address string `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *InviteFunds) Reset() {
+	*x = InviteFunds{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[100]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InviteFunds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteFunds) ProtoMessage() {}
+
+func (x *InviteFunds) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[100]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteFunds.ProtoReflect.Descriptor instead.
+func (*InviteFunds) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *InviteFunds) GetTx() string {
+	if x != nil {
+		return x.Tx
+	}
+	return ""
+}
+
+func (x *InviteFunds) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *InviteFunds) GetTree() int32 {
+	if x != nil {
+		return x.Tree
+	}
+	return 0
+}
+
+func (x *InviteFunds) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+func (x *InviteFunds) GetHeightHint() uint32 {
+	if x != nil {
+		return x.HeightHint
+	}
+	return 0
+}
+
+func (x *InviteFunds) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+// OOBPublicIdentityInvite is an out-of-band invite to perform a KX.
+type OOBPublicIdentityInvite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// public is the public identity of the inviter.
+	Public *PublicIdentity `protobuf:"bytes,1,opt,name=public,proto3" json:"public,omitempty"`
+	// initial_rendezvous is the initial random RV where key exchange will happen.
+	InitialRendezvous []byte `protobuf:"bytes,2,opt,name=initial_rendezvous,json=initialrendezvous,proto3" json:"initial_rendezvous,omitempty"`
+	// reset_rendezvous is the inviter's reset RV in case of ratchet reset actions.
+	ResetRendezvous []byte `protobuf:"bytes,3,opt,name=reset_rendezvous,json=resetrendezvous,proto3" json:"reset_rendezvous,omitempty"`
+	// funds are funds that may be included in the invite and are redeemable by
+	// the invitee.
+	Funds *InviteFunds `protobuf:"bytes,4,opt,name=funds,proto3" json:"funds,omitempty"`
+}
+
+func (x *OOBPublicIdentityInvite) Reset() {
+	*x = OOBPublicIdentityInvite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[101]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OOBPublicIdentityInvite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OOBPublicIdentityInvite) ProtoMessage() {}
+
+func (x *OOBPublicIdentityInvite) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[101]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OOBPublicIdentityInvite.ProtoReflect.Descriptor instead.
+func (*OOBPublicIdentityInvite) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *OOBPublicIdentityInvite) GetPublic() *PublicIdentity {
+	if x != nil {
+		return x.Public
+	}
+	return nil
+}
+
+func (x *OOBPublicIdentityInvite) GetInitialRendezvous() []byte {
+	if x != nil {
+		return x.InitialRendezvous
+	}
+	return nil
+}
+
+func (x *OOBPublicIdentityInvite) GetResetRendezvous() []byte {
+	if x != nil {
+		return x.ResetRendezvous
+	}
+	return nil
+}
+
+func (x *OOBPublicIdentityInvite) GetFunds() *InviteFunds {
+	if x != nil {
+		return x.Funds
+	}
+	return nil
+}
+
+// RMGroupInvite is the information about an invite to join a GC.
+type RMGroupInvite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id is the ID of the GC.
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// name is the name of the GC.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// token is a random number that must be sent back by the invitee to the inviter.
+	Token uint64 `protobuf:"varint,3,opt,name=token,proto3" json:"token,omitempty"`
+	// description is a description of the GC.
+	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// expires is a timestamp for when the invitation expires.
+	Expires int64 `protobuf:"varint,5,opt,name=expires,proto3" json:"expires,omitempty"`
+	// version is the version of the current definition of the GC.
+	Version uint32 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *RMGroupInvite) Reset() {
+	*x = RMGroupInvite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[102]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RMGroupInvite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RMGroupInvite) ProtoMessage() {}
+
+func (x *RMGroupInvite) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[102]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RMGroupInvite.ProtoReflect.Descriptor instead.
+func (*RMGroupInvite) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *RMGroupInvite) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *RMGroupInvite) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RMGroupInvite) GetToken() uint64 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+func (x *RMGroupInvite) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RMGroupInvite) GetExpires() int64 {
+	if x != nil {
+		return x.Expires
+	}
+	return 0
+}
+
+func (x *RMGroupInvite) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// RMGroupList is the full definition of a GC.
+type RMGroupList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id is the ID of the GC.
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// name is the name of the GC.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// generation is a monotonically increasing count of GC changes.
+	Generation uint64 `protobuf:"varint,3,opt,name=generation,proto3" json:"generation,omitempty"`
+	// timestamp is the timestamp for the last modification of the GC defintions.
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// version is the GC rules version.
+	Version uint32 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	// members is the list of user IDs that are in the GC.
+	Members [][]byte `protobuf:"bytes,6,rep,name=members,proto3" json:"members,omitempty"`
+	// extra_admins is the list of user IDs that are additional admins of the GC.
+	ExtraAdmins [][]byte `protobuf:"bytes,7,rep,name=extra_admins,proto3" json:"extra_admins,omitempty"`
+}
+
+func (x *RMGroupList) Reset() {
+	*x = RMGroupList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[103]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RMGroupList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RMGroupList) ProtoMessage() {}
+
+func (x *RMGroupList) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[103]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RMGroupList.ProtoReflect.Descriptor instead.
+func (*RMGroupList) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *RMGroupList) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *RMGroupList) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RMGroupList) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *RMGroupList) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *RMGroupList) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RMGroupList) GetMembers() [][]byte {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *RMGroupList) GetExtraAdmins() [][]byte {
+	if x != nil {
+		return x.ExtraAdmins
+	}
+	return nil
+}
+
+// RMFetchResource is the lowlevel request to fetch a resource.
+type RMFetchResource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// path is the resource's path (already split into segments).
+	Path []string `protobuf:"bytes,1,rep,name=path,proto3" json:"path,omitempty"`
+	// meta is metadata associated with the request.
+	Meta map[string]string `protobuf:"bytes,2,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// tag is a unique tag that should be relayed back on the reply.
+	Tag uint64 `protobuf:"varint,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	// data is raw request data.
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// index is used in chunked/multipart requests.
+	Index uint32 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	// count is the total number of chunks in multipart requests.
+	Count uint32 `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *RMFetchResource) Reset() {
+	*x = RMFetchResource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[104]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RMFetchResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RMFetchResource) ProtoMessage() {}
+
+func (x *RMFetchResource) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[104]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RMFetchResource.ProtoReflect.Descriptor instead.
+func (*RMFetchResource) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *RMFetchResource) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *RMFetchResource) GetMeta() map[string]string {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *RMFetchResource) GetTag() uint64 {
+	if x != nil {
+		return x.Tag
+	}
+	return 0
+}
+
+func (x *RMFetchResource) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *RMFetchResource) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *RMFetchResource) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// RMFetchResourceReply is the lowlevel resource response.
+type RMFetchResourceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// tag is the same as the request tag.
+	Tag uint64 `protobuf:"varint,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// status is the general status of the response.
+	Status uint32 `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	// meta is additional response metadata.
+	Meta map[string]string `protobuf:"bytes,3,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// data is the raw response data.
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// index is used in chunked/multipart responses.
+	Index uint32 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	// count is the total number of chunks in multipart responses.
+	Count uint32 `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *RMFetchResourceReply) Reset() {
+	*x = RMFetchResourceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[105]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RMFetchResourceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RMFetchResourceReply) ProtoMessage() {}
+
+func (x *RMFetchResourceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[105]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RMFetchResourceReply.ProtoReflect.Descriptor instead.
+func (*RMFetchResourceReply) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *RMFetchResourceReply) GetTag() uint64 {
+	if x != nil {
+		return x.Tag
+	}
+	return 0
+}
+
+func (x *RMFetchResourceReply) GetStatus() uint32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *RMFetchResourceReply) GetMeta() map[string]string {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *RMFetchResourceReply) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *RMFetchResourceReply) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *RMFetchResourceReply) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// GCInfo is the summary info for a GC.
+type ListGCsResponse_GCInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id is the unique GC ID.
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// name is the local name/alias for the GC.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// version is the current version the GC definition is on.
+	Version uint32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// timestamp is the timestamp of the last modification to the GC definition.
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// nb_members is the total number of members in the GC.
+	NbMembers uint32 `protobuf:"varint,5,opt,name=nb_members,json=nbMembers,proto3" json:"nb_members,omitempty"`
+}
+
+func (x *ListGCsResponse_GCInfo) Reset() {
+	*x = ListGCsResponse_GCInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_clientrpc_proto_msgTypes[106]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListGCsResponse_GCInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGCsResponse_GCInfo) ProtoMessage() {}
+
+func (x *ListGCsResponse_GCInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_clientrpc_proto_msgTypes[106]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGCsResponse_GCInfo.ProtoReflect.Descriptor instead.
+func (*ListGCsResponse_GCInfo) Descriptor() ([]byte, []int) {
+	return file_clientrpc_proto_rawDescGZIP(), []int{54, 0}
+}
+
+func (x *ListGCsResponse_GCInfo) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *ListGCsResponse_GCInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListGCsResponse_GCInfo) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ListGCsResponse_GCInfo) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ListGCsResponse_GCInfo) GetNbMembers() uint32 {
+	if x != nil {
+		return x.NbMembers
+	}
+	return 0
+}
+
+var File_clientrpc_proto protoreflect.FileDescriptor
+
+var file_clientrpc_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x10, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x6c, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x5f, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x70,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x6f, 0x5f, 0x72, 0x75,
+	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x6f, 0x52,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x70, 0x70, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x70, 0x70, 0x4e, 0x61, 0x6d,
+	0x65, 0x22, 0x34, 0x0a, 0x16, 0x4b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x2e, 0x0a, 0x0e, 0x4b, 0x65, 0x65, 0x70, 0x61,
+	0x6c, 0x69, 0x76, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x2d, 0x0a, 0x0a, 0x41, 0x63, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x0d, 0x0a, 0x0b, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x44, 0x0a, 0x09, 0x50, 0x4d, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x4d, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x0c, 0x0a, 0x0a, 0x50,
+	0x4d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x34, 0x0a, 0x0f, 0x50, 0x4d, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22,
+	0x9b, 0x01, 0x0a, 0x0a, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x4d, 0x12, 0x10,
+	0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x75, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x69, 0x63, 0x6b, 0x12, 0x23, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x52, 0x4d, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x2e, 0x0a,
+	0x0a, 0x47, 0x43, 0x4d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67,
+	0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x67, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x6d,
+	0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x0d, 0x0a,
+	0x0b, 0x47, 0x43, 0x4d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x35, 0x0a, 0x10,
+	0x47, 0x43, 0x4d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46,
+	0x72, 0x6f, 0x6d, 0x22, 0xb7, 0x01, 0x0a, 0x0d, 0x47, 0x43, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x64, 0x4d, 0x73, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x19, 0x0a, 0x08, 0x67,
+	0x63, 0x5f, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67,
+	0x63, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x21, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4d, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x2d, 0x0a,
+	0x17, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x1a, 0x0a, 0x18,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2f, 0x0a, 0x19, 0x55, 0x6e, 0x73, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x1c, 0x0a, 0x1a, 0x55, 0x6e, 0x73,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xbf, 0x01, 0x0a, 0x0b, 0x50, 0x6f, 0x73, 0x74,
+	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x61,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
+	0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x5f, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x4e, 0x69, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a,
+	0x0e, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x74, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x54, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x22, 0x37, 0x0a, 0x12, 0x50, 0x6f, 0x73,
+	0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72,
+	0x6f, 0x6d, 0x22, 0x99, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50,
+	0x6f, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x04, 0x70,
+	0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x50, 0x6f, 0x73, 0x74,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x04, 0x70, 0x6f, 0x73, 0x74, 0x22, 0x3d,
+	0x0a, 0x18, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e,
+	0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0xe5, 0x01,
+	0x0a, 0x12, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x6f, 0x73, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a,
+	0x0b, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x2b,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x50, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x6e, 0x69, 0x63, 0x6b, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x46, 0x72, 0x6f,
+	0x6d, 0x4e, 0x69, 0x63, 0x6b, 0x22, 0x66, 0x0a, 0x0e, 0x54, 0x69, 0x70, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x64,
+	0x63, 0x72, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x64, 0x63, 0x72, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61,
+	0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x22, 0x11, 0x0a,
+	0x0f, 0x54, 0x69, 0x70, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x46, 0x0a, 0x10, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x4b, 0x58, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x6f, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x6f, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x13, 0x0a, 0x11, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x74, 0x65, 0x4b, 0x58, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x34, 0x0a,
+	0x0f, 0x4b, 0x58, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46,
+	0x72, 0x6f, 0x6d, 0x22, 0x83, 0x01, 0x0a, 0x0b, 0x4b, 0x58, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x2d, 0x0a, 0x12, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x52,
+	0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73, 0x22, 0x48, 0x0a, 0x15, 0x57, 0x72, 0x69,
+	0x74, 0x65, 0x4e, 0x65, 0x77, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x67, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x66, 0x75, 0x6e, 0x64, 0x41, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0x8c, 0x01, 0x0a, 0x16, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x65, 0x77,
+	0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21,
+	0x0a, 0x0c, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x12, 0x30, 0x0a, 0x06, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x4f, 0x4f, 0x42, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x06, 0x69, 0x6e, 0x76,
+	0x69, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4b,
+	0x65, 0x79, 0x22, 0x38, 0x0a, 0x13, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x49, 0x6e, 0x76, 0x69,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x76,
+	0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0b, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x48, 0x0a, 0x14,
+	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x4f, 0x4f, 0x42, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x06,
+	0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x22, 0x37, 0x0a, 0x11, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65,
+	0x54, 0x6f, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67,
+	0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x67, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22,
+	0x14, 0x0a, 0x12, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x54, 0x6f, 0x47, 0x43, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x34, 0x0a, 0x15, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x47,
+	0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x49, 0x64, 0x22, 0x18, 0x0a, 0x16, 0x41,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x41, 0x0a, 0x0f, 0x53, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x12, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x64, 0x0a, 0x0a,
+	0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69,
+	0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x65, 0x72, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x65, 0x72, 0x6d, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x22, 0x35, 0x0a, 0x10, 0x53, 0x61, 0x76, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22, 0x13, 0x0a, 0x11, 0x53, 0x61, 0x76,
+	0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x19,
+	0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x41, 0x0a, 0x18, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x71, 0x75, 0x65, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x52, 0x07, 0x71, 0x75, 0x65, 0x72, 0x69, 0x65, 0x73, 0x22, 0x2a, 0x0a, 0x14,
+	0x52, 0x75, 0x6e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5c, 0x0a, 0x10, 0x53, 0x61, 0x76, 0x65,
+	0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x44, 0x0a, 0x15, 0x52, 0x75, 0x6e, 0x53, 0x61, 0x76,
+	0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2b, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x2d, 0x0a, 0x17,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4f, 0x0a, 0x11, 0x4b, 0x69, 0x63, 0x6b, 0x46,
+	0x72, 0x6f, 0x6d, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x67, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x67, 0x63, 0x12, 0x12, 0x0a, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x14, 0x0a, 0x12, 0x4b, 0x69, 0x63, 0x6b,
+	0x46, 0x72, 0x6f, 0x6d, 0x47, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e,
+	0x0a, 0x0c, 0x47, 0x65, 0x74, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x67, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x67, 0x63, 0x22, 0x2d,
+	0x0a, 0x0d, 0x47, 0x65, 0x74, 0x47, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1c, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x52, 0x4d,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x02, 0x67, 0x63, 0x22, 0x10, 0x0a,
+	0x0e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x43, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0xc2, 0x01, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x43, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x03, 0x67, 0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x43, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2e, 0x47, 0x43, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x67, 0x63, 0x73, 0x1a, 0x83,
+	0x01, 0x0a, 0x06, 0x47, 0x43, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x62, 0x5f, 0x6d, 0x65, 0x6d, 0x62,
+	0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6e, 0x62, 0x4d, 0x65, 0x6d,
+	0x62, 0x65, 0x72, 0x73, 0x22, 0x3d, 0x0a, 0x18, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
+	0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46,
+	0x72, 0x6f, 0x6d, 0x22, 0xbc, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
+	0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x76,
+	0x69, 0x74, 0x65, 0x72, 0x5f, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
+	0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x55, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e,
+	0x76, 0x69, 0x74, 0x65, 0x72, 0x5f, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x4e, 0x69, 0x63, 0x6b, 0x12, 0x1b, 0x0a,
+	0x09, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x06, 0x69, 0x6e,
+	0x76, 0x69, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x52, 0x4d, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x06, 0x69, 0x6e, 0x76, 0x69,
+	0x74, 0x65, 0x22, 0x49, 0x0a, 0x0b, 0x55, 0x73, 0x65, 0x72, 0x41, 0x6e, 0x64, 0x4e, 0x69, 0x63,
+	0x6b, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x6b, 0x6e, 0x6f, 0x77, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x22, 0x3a, 0x0a,
+	0x15, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x65, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65,
+	0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e,
+	0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0x83, 0x01, 0x0a, 0x13, 0x47, 0x43,
+	0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02,
+	0x67, 0x63, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x63, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x75,
+	0x73, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x41, 0x6e, 0x64, 0x4e, 0x69, 0x63, 0x6b, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22,
+	0x3c, 0x0a, 0x17, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e,
+	0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0x85, 0x01,
+	0x0a, 0x15, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x63, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x67, 0x63, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x63, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x63, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x22, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x41, 0x6e, 0x64, 0x4e, 0x69, 0x63, 0x6b, 0x52, 0x05,
+	0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0x35, 0x0a, 0x10, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x47,
+	0x43, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61,
+	0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0x4e, 0x0a, 0x0d,
+	0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1c,
+	0x0a, 0x02, 0x67, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x52, 0x4d, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x02, 0x67, 0x63, 0x22, 0x37, 0x0a, 0x12,
+	0x54, 0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66, 0x72,
+	0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65,
+	0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0xf6, 0x01, 0x0a, 0x10, 0x54, 0x69, 0x70, 0x50, 0x72, 0x6f,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0a, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x69, 0x63,
+	0x6b, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x61, 0x74, 0x6f,
+	0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x4d, 0x61, 0x74, 0x6f, 0x6d, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c,
+	0x65, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x5f, 0x65, 0x72, 0x72, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x45, 0x72, 0x72, 0x12,
+	0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6c, 0x6c, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x79, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x77, 0x69, 0x6c, 0x6c, 0x52, 0x65, 0x74, 0x72, 0x79, 0x22, 0xab,
+	0x01, 0x0a, 0x0e, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x61, 0x74, 0x6f, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x61, 0x74, 0x6f, 0x6d, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x22, 0x1c, 0x0a, 0x1a,
+	0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4a, 0x0a, 0x1b, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x08, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x50, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x27, 0x0a, 0x15, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76,
+	0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x18, 0x0a, 0x16, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3c, 0x0a, 0x12, 0x44, 0x65, 0x6e,
+	0x79, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x15, 0x0a, 0x13, 0x44, 0x65, 0x6e, 0x79, 0x50,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1f,
+	0x0a, 0x1d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x82, 0x01, 0x0a, 0x1e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x2a, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x52, 0x4d, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x78, 0x0a, 0x16, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x52,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b,
+	0x0a, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x31, 0x0a, 0x08, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x52, 0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x20,
+	0x0a, 0x1e, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0xac, 0x02, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x6b, 0x75, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x73, 0x6b, 0x75, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x79, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x68, 0x69, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x21, 0x0a,
+	0x0c, 0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x5f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x68, 0x61, 0x73, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x22,
+	0x40, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x5f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
+	0x64, 0x22, 0x41, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x08, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x73, 0x22, 0x3c, 0x0a, 0x11, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x07, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x22, 0x3d, 0x0a, 0x12, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x22, 0x3f, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x07, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x22, 0x40, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x07, 0x70,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x53,
+	0x74, 0x6f, 0x72, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x07, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x22, 0x6a, 0x0a, 0x0e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x6b, 0x75, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x6b, 0x75, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72,
+	0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x22, 0xe4, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x6c, 0x61, 0x63, 0x65, 0x64, 0x5f, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x54, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x43, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x61, 0x79, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x79, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x25, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x2b, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x4f,
+	0x72, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x39, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x06, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x22,
+	0x56, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x1b, 0x0a, 0x19, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2a, 0x0a, 0x12, 0x53, 0x61, 0x6c, 0x65, 0x73, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69,
+	0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65,
+	0x22, 0x49, 0x0a, 0x0a, 0x53, 0x61, 0x6c, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x5f, 0x63, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x50, 0x0a, 0x13, 0x53,
+	0x61, 0x6c, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x23, 0x0a, 0x06, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x53, 0x61, 0x6c, 0x65, 0x73,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x06, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x22, 0x37, 0x0a,
+	0x12, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x66,
+	0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x63, 0x6b,
+	0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0x8f, 0x02, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x0a,
+	0x63, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x74, 0x65,
+	0x6d, 0x52, 0x09, 0x63, 0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x28, 0x0a, 0x10,
+	0x63, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x63, 0x61, 0x72, 0x74, 0x54, 0x6f, 0x74, 0x61,
+	0x6c, 0x43, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6c, 0x64,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f,
+	0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x4e, 0x0a, 0x10, 0x52, 0x4d, 0x50, 0x72,
+	0x69, 0x76, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4d, 0x6f,
+	0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x7c, 0x0a, 0x0e, 0x52, 0x4d, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0xa6, 0x01, 0x0a, 0x0c, 0x50, 0x6f, 0x73, 0x74, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x3d, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73,
+	0x1a, 0x3d, 0x0a, 0x0f, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0xda, 0x01, 0x0a, 0x12, 0x50, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x66, 0x72, 0x6f, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x43, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x50,
+	0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x1a, 0x3d, 0x0a,
+	0x0f, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb5, 0x01, 0x0a,
+	0x0e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x69, 0x63, 0x6b, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x67, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x69, 0x67, 0x4b, 0x65, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x22, 0xa4, 0x01, 0x0a, 0x0b, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x46,
+	0x75, 0x6e, 0x64, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x74, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x72,
+	0x65, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x72, 0x65, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79,
+	0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x48, 0x69, 0x6e,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x17,
+	0x4f, 0x4f, 0x42, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x12, 0x2d, 0x0a, 0x12, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x6e, 0x64,
+	0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73, 0x12,
+	0x29, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76,
+	0x6f, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x65, 0x74,
+	0x72, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75, 0x73, 0x12, 0x22, 0x0a, 0x05, 0x66, 0x75,
+	0x6e, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x49, 0x6e, 0x76, 0x69,
+	0x74, 0x65, 0x46, 0x75, 0x6e, 0x64, 0x73, 0x52, 0x05, 0x66, 0x75, 0x6e, 0x64, 0x73, 0x22, 0x9f,
+	0x01, 0x0a, 0x0d, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0xc7, 0x01, 0x0a, 0x0b, 0x52, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x6d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x65, 0x78,
+	0x74, 0x72, 0x61, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x22, 0xe0, 0x01, 0x0a, 0x0f, 0x52,
+	0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x2e, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x52, 0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x6d, 0x65,
+	0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x03, 0x74, 0x61, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14,
+	0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x1a, 0x37, 0x0a, 0x09, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xee, 0x01,
+	0x0a, 0x14, 0x52, 0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x33, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x52, 0x4d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x1a, 0x37, 0x0a, 0x09, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x3b,
+	0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x17, 0x0a,
+	0x13, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4e, 0x4f,
+	0x52, 0x4d, 0x41, 0x4c, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47,
+	0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4d, 0x45, 0x10, 0x01, 0x32, 0x7d, 0x0a, 0x0e, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2c, 0x0a,
+	0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0f, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0f, 0x4b,
+	0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x17,
+	0x2e, 0x4b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x4b, 0x65, 0x65, 0x70, 0x61, 0x6c,
+	0x69, 0x76, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x32, 0xcc, 0x06, 0x0a, 0x0b, 0x43,
+	0x68, 0x61, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x02, 0x50, 0x4d,
+	0x12, 0x0a, 0x2e, 0x50, 0x4d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b, 0x2e, 0x50,
+	0x4d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x08, 0x50, 0x4d, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x10, 0x2e, 0x50, 0x4d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x64, 0x50, 0x4d, 0x30, 0x01, 0x12, 0x2a, 0x0a, 0x0d, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x4d, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x20, 0x0a, 0x03, 0x47, 0x43, 0x4d, 0x12, 0x0b, 0x2e, 0x47, 0x43, 0x4d, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x47, 0x43, 0x4d, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x09, 0x47, 0x43, 0x4d, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x12, 0x11, 0x2e, 0x47, 0x43, 0x4d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x47, 0x43, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x4d, 0x73, 0x67, 0x30, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43, 0x4d, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x4b, 0x58,
+	0x12, 0x11, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x4b, 0x58, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x4b, 0x58, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x08, 0x4b, 0x58, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x12, 0x10, 0x2e, 0x4b, 0x58, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x4b, 0x58, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x30, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x41, 0x63, 0x6b, 0x4b, 0x58, 0x43, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x65, 0x77, 0x49, 0x6e,
+	0x76, 0x69, 0x74, 0x65, 0x12, 0x16, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4e, 0x65, 0x77, 0x49,
+	0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x57,
+	0x72, 0x69, 0x74, 0x65, 0x4e, 0x65, 0x77, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x49,
+	0x6e, 0x76, 0x69, 0x74, 0x65, 0x12, 0x14, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x49, 0x6e,
+	0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x41, 0x63,
+	0x63, 0x65, 0x70, 0x74, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x53, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x10,
+	0x2e, 0x53, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x11, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x53, 0x61, 0x76, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x12, 0x11, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x18, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x61, 0x76, 0x65,
+	0x64, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3e, 0x0a, 0x0d, 0x52, 0x75, 0x6e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x12, 0x15, 0x2e, 0x52, 0x75, 0x6e, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x52, 0x75, 0x6e, 0x53, 0x61,
+	0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x47, 0x0a, 0x10, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x12, 0x18, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x61, 0x76,
+	0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x61, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xc8, 0x05, 0x0a, 0x09, 0x47, 0x43,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x49, 0x6e, 0x76, 0x69, 0x74,
+	0x65, 0x54, 0x6f, 0x47, 0x43, 0x12, 0x12, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x54, 0x6f,
+	0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x49, 0x6e, 0x76, 0x69,
+	0x74, 0x65, 0x54, 0x6f, 0x47, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41,
+	0x0a, 0x0e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65,
+	0x12, 0x16, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70,
+	0x74, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x35, 0x0a, 0x0a, 0x4b, 0x69, 0x63, 0x6b, 0x46, 0x72, 0x6f, 0x6d, 0x47, 0x43, 0x12,
+	0x12, 0x2e, 0x4b, 0x69, 0x63, 0x6b, 0x46, 0x72, 0x6f, 0x6d, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x4b, 0x69, 0x63, 0x6b, 0x46, 0x72, 0x6f, 0x6d, 0x47, 0x43,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x47, 0x65, 0x74, 0x47,
+	0x43, 0x12, 0x0d, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0e, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x29, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x0f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47,
+	0x43, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x47, 0x43, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x11, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x73,
+	0x12, 0x19, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43, 0x49, 0x6e, 0x76,
+	0x69, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x52, 0x65,
+	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x47, 0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x30, 0x01,
+	0x12, 0x31, 0x0a, 0x14, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x47,
+	0x43, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x73, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64,
+	0x64, 0x65, 0x64, 0x12, 0x16, 0x2e, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41,
+	0x64, 0x64, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x47, 0x43,
+	0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x41, 0x64, 0x64, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x30, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x41, 0x63, 0x6b, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x41, 0x64, 0x64, 0x65, 0x64, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x44, 0x0a, 0x0e, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x64, 0x12, 0x18, 0x2e, 0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x47, 0x43, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x2e, 0x0a, 0x11, 0x41, 0x63, 0x6b, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x0b, 0x2e, 0x41,
+	0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x09, 0x4a, 0x6f, 0x69, 0x6e, 0x65,
+	0x64, 0x47, 0x43, 0x73, 0x12, 0x11, 0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64,
+	0x47, 0x43, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x29, 0x0a, 0x0c, 0x41, 0x63, 0x6b,
+	0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x47, 0x43, 0x73, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x32, 0x84, 0x03, 0x0a, 0x0c, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x18, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54,
+	0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d,
+	0x0a, 0x12, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x50,
+	0x6f, 0x73, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x54, 0x6f, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1b, 0x2e, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f,
+	0x50, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a,
+	0x0b, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x13, 0x2e, 0x50,
+	0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0d, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x6f, 0x73, 0x74,
+	0x30, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x50, 0x6f, 0x73, 0x74, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x45, 0x0a, 0x11, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x19, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x73, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x13, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x6f, 0x73, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x30, 0x01, 0x12, 0x32, 0x0a, 0x15, 0x41, 0x63, 0x6b, 0x52, 0x65,
+	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x50, 0x6f, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e,
+	0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xf4, 0x02, 0x0a, 0x0f,
+	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x2c, 0x0a, 0x07, 0x54, 0x69, 0x70, 0x55, 0x73, 0x65, 0x72, 0x12, 0x0f, 0x2e, 0x54, 0x69, 0x70,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x54, 0x69,
+	0x70, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a,
+	0x0b, 0x54, 0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x13, 0x2e, 0x54,
+	0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x11, 0x2e, 0x54, 0x69, 0x70, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x41, 0x63, 0x6b, 0x54, 0x69, 0x70,
+	0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1b, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65,
+	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76,
+	0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x17, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x44, 0x65, 0x6e, 0x79,
+	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x13, 0x2e, 0x44, 0x65, 0x6e, 0x79, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x44,
+	0x65, 0x6e, 0x79, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x32, 0xb3, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x53, 0x0a, 0x0e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1e, 0x2e, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x4a, 0x0a, 0x0e,
+	0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17,
+	0x2e, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x46, 0x75, 0x6c, 0x66, 0x69, 0x6c,
+	0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xdf, 0x03, 0x0a, 0x0c, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x4c, 0x69, 0x73,
+	0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x12, 0x14, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x15, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x12, 0x12, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a,
+	0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12, 0x15,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a,
+	0x0a, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x12, 0x12, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x13, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x19, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x38, 0x0a, 0x0b, 0x53, 0x61, 0x6c, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x12,
+	0x13, 0x2e, 0x53, 0x61, 0x6c, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x53, 0x61, 0x6c, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61,
+	0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x0b, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x13, 0x2e, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b,
+	0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x2b, 0x0a,
+	0x0e, 0x41, 0x63, 0x6b, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x0b, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x41,
+	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79,
+	0x7a, 0x65, 0x72, 0x6f, 0x2f, 0x62, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x72, 0x65, 0x6c, 0x61, 0x79,
+	0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_clientrpc_proto_rawDescOnce sync.Once
+	file_clientrpc_proto_rawDescData = file_clientrpc_proto_rawDesc
+)
+
+func file_clientrpc_proto_rawDescGZIP() []byte {
+	file_clientrpc_proto_rawDescOnce.Do(func() {
+		file_clientrpc_proto_rawDescData = protoimpl.X.CompressGZIP(file_clientrpc_proto_rawDescData)
+	})
+	return file_clientrpc_proto_rawDescData
+}
+
+var file_clientrpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_clientrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 111)
+var file_clientrpc_proto_goTypes = []interface{}{
+	(MessageMode)(0),                       // 0: MessageMode
+	(*VersionRequest)(nil),                 // 1: VersionRequest
+	(*VersionResponse)(nil),                // 2: VersionResponse
+	(*KeepaliveStreamRequest)(nil),         // 3: KeepaliveStreamRequest
+	(*KeepaliveEvent)(nil),                 // 4: KeepaliveEvent
+	(*AckRequest)(nil),                     // 5: AckRequest
+	(*AckResponse)(nil),                    // 6: AckResponse
+	(*PMRequest)(nil),                      // 7: PMRequest
+	(*PMResponse)(nil),                     // 8: PMResponse
+	(*PMStreamRequest)(nil),                // 9: PMStreamRequest
+	(*ReceivedPM)(nil),                     // 10: ReceivedPM
+	(*GCMRequest)(nil),                     // 11: GCMRequest
+	(*GCMResponse)(nil),                    // 12: GCMResponse
+	(*GCMStreamRequest)(nil),               // 13: GCMStreamRequest
+	(*GCReceivedMsg)(nil),                  // 14: GCReceivedMsg
+	(*SubscribeToPostsRequest)(nil),        // 15: SubscribeToPostsRequest
+	(*SubscribeToPostsResponse)(nil),       // 16: SubscribeToPostsResponse
+	(*UnsubscribeToPostsRequest)(nil),      // 17: UnsubscribeToPostsRequest
+	(*UnsubscribeToPostsResponse)(nil),     // 18: UnsubscribeToPostsResponse
+	(*PostSummary)(nil),                    // 19: PostSummary
+	(*PostsStreamRequest)(nil),             // 20: PostsStreamRequest
+	(*ReceivedPost)(nil),                   // 21: ReceivedPost
+	(*PostsStatusStreamRequest)(nil),       // 22: PostsStatusStreamRequest
+	(*ReceivedPostStatus)(nil),             // 23: ReceivedPostStatus
+	(*TipUserRequest)(nil),                 // 24: TipUserRequest
+	(*TipUserResponse)(nil),                // 25: TipUserResponse
+	(*MediateKXRequest)(nil),               // 26: MediateKXRequest
+	(*MediateKXResponse)(nil),              // 27: MediateKXResponse
+	(*KXStreamRequest)(nil),                // 28: KXStreamRequest
+	(*KXCompleted)(nil),                    // 29: KXCompleted
+	(*WriteNewInviteRequest)(nil),          // 30: WriteNewInviteRequest
+	(*WriteNewInviteResponse)(nil),         // 31: WriteNewInviteResponse
+	(*AcceptInviteRequest)(nil),            // 32: AcceptInviteRequest
+	(*AcceptInviteResponse)(nil),           // 33: AcceptInviteResponse
+	(*InviteToGCRequest)(nil),              // 34: InviteToGCRequest
+	(*InviteToGCResponse)(nil),             // 35: InviteToGCResponse
+	(*AcceptGCInviteRequest)(nil),          // 36: AcceptGCInviteRequest
+	(*AcceptGCInviteResponse)(nil),         // 37: AcceptGCInviteResponse
+	(*SendFileRequest)(nil),                // 38: SendFileRequest
+	(*SendFileResponse)(nil),               // 39: SendFileResponse
+	(*SavedQuery)(nil),                     // 40: SavedQuery
+	(*SaveQueryRequest)(nil),               // 41: SaveQueryRequest
+	(*SaveQueryResponse)(nil),              // 42: SaveQueryResponse
+	(*ListSavedQueriesRequest)(nil),        // 43: ListSavedQueriesRequest
+	(*ListSavedQueriesResponse)(nil),       // 44: ListSavedQueriesResponse
+	(*RunSavedQueryRequest)(nil),           // 45: RunSavedQueryRequest
+	(*SavedQueryResult)(nil),               // 46: SavedQueryResult
+	(*RunSavedQueryResponse)(nil),          // 47: RunSavedQueryResponse
+	(*RemoveSavedQueryRequest)(nil),        // 48: RemoveSavedQueryRequest
+	(*RemoveSavedQueryResponse)(nil),       // 49: RemoveSavedQueryResponse
+	(*KickFromGCRequest)(nil),              // 50: KickFromGCRequest
+	(*KickFromGCResponse)(nil),             // 51: KickFromGCResponse
+	(*GetGCRequest)(nil),                   // 52: GetGCRequest
+	(*GetGCResponse)(nil),                  // 53: GetGCResponse
+	(*ListGCsRequest)(nil),                 // 54: ListGCsRequest
+	(*ListGCsResponse)(nil),                // 55: ListGCsResponse
+	(*ReceivedGCInvitesRequest)(nil),       // 56: ReceivedGCInvitesRequest
+	(*ReceivedGCInvite)(nil),               // 57: ReceivedGCInvite
+	(*UserAndNick)(nil),                    // 58: UserAndNick
+	(*GCMembersAddedRequest)(nil),          // 59: GCMembersAddedRequest
+	(*GCMembersAddedEvent)(nil),            // 60: GCMembersAddedEvent
+	(*GCMembersRemovedRequest)(nil),        // 61: GCMembersRemovedRequest
+	(*GCMembersRemovedEvent)(nil),          // 62: GCMembersRemovedEvent
+	(*JoinedGCsRequest)(nil),               // 63: JoinedGCsRequest
+	(*JoinedGCEvent)(nil),                  // 64: JoinedGCEvent
+	(*TipProgressRequest)(nil),             // 65: TipProgressRequest
+	(*TipProgressEvent)(nil),               // 66: TipProgressEvent
+	(*PendingPayment)(nil),                 // 67: PendingPayment
+	(*ListPendingPaymentsRequest)(nil),     // 68: ListPendingPaymentsRequest
+	(*ListPendingPaymentsResponse)(nil),    // 69: ListPendingPaymentsResponse
+	(*ApprovePaymentRequest)(nil),          // 70: ApprovePaymentRequest
+	(*ApprovePaymentResponse)(nil),         // 71: ApprovePaymentResponse
+	(*DenyPaymentRequest)(nil),             // 72: DenyPaymentRequest
+	(*DenyPaymentResponse)(nil),            // 73: DenyPaymentResponse
+	(*ResourceRequestsStreamRequest)(nil),  // 74: ResourceRequestsStreamRequest
+	(*ResourceRequestsStreamResponse)(nil), // 75: ResourceRequestsStreamResponse
+	(*FulfillResourceRequest)(nil),         // 76: FulfillResourceRequest
+	(*FulfillResourceRequestResponse)(nil), // 77: FulfillResourceRequestResponse
+	(*StoreProduct)(nil),                   // 78: StoreProduct
+	(*ListProductsRequest)(nil),            // 79: ListProductsRequest
+	(*ListProductsResponse)(nil),           // 80: ListProductsResponse
+	(*AddProductRequest)(nil),              // 81: AddProductRequest
+	(*AddProductResponse)(nil),             // 82: AddProductResponse
+	(*UpdateProductRequest)(nil),           // 83: UpdateProductRequest
+	(*UpdateProductResponse)(nil),          // 84: UpdateProductResponse
+	(*StoreOrderItem)(nil),                 // 85: StoreOrderItem
+	(*StoreOrder)(nil),                     // 86: StoreOrder
+	(*ListOrdersRequest)(nil),              // 87: ListOrdersRequest
+	(*ListOrdersResponse)(nil),             // 88: ListOrdersResponse
+	(*UpdateOrderStatusRequest)(nil),       // 89: UpdateOrderStatusRequest
+	(*UpdateOrderStatusResponse)(nil),      // 90: UpdateOrderStatusResponse
+	(*SalesTotalsRequest)(nil),             // 91: SalesTotalsRequest
+	(*SalesTotal)(nil),                     // 92: SalesTotal
+	(*SalesTotalsResponse)(nil),            // 93: SalesTotalsResponse
+	(*StoreEventsRequest)(nil),             // 94: StoreEventsRequest
+	(*StoreEvent)(nil),                     // 95: StoreEvent
+	(*RMPrivateMessage)(nil),               // 96: RMPrivateMessage
+	(*RMGroupMessage)(nil),                 // 97: RMGroupMessage
+	(*PostMetadata)(nil),                   // 98: PostMetadata
+	(*PostMetadataStatus)(nil),             // 99: PostMetadataStatus
+	(*PublicIdentity)(nil),                 // 100: PublicIdentity
+	(*InviteFunds)(nil),                    // 101: InviteFunds
+	(*OOBPublicIdentityInvite)(nil),        // 102: OOBPublicIdentityInvite
+	(*RMGroupInvite)(nil),                  // 103: RMGroupInvite
+	(*RMGroupList)(nil),                    // 104: RMGroupList
+	(*RMFetchResource)(nil),                // 105: RMFetchResource
+	(*RMFetchResourceReply)(nil),           // 106: RMFetchResourceReply
+	(*ListGCsResponse_GCInfo)(nil),         // 107: ListGCsResponse.GCInfo
+	nil,                                    // 108: PostMetadata.AttributesEntry
+	nil,                                    // 109: PostMetadataStatus.AttributesEntry
+	nil,                                    // 110: RMFetchResource.MetaEntry
+	nil,                                    // 111: RMFetchResourceReply.MetaEntry
+}
+var file_clientrpc_proto_depIdxs = []int32{
+	96,  // 0: PMRequest.msg:type_name -> RMPrivateMessage
+	96,  // 1: ReceivedPM.msg:type_name -> RMPrivateMessage
+	97,  // 2: GCReceivedMsg.msg:type_name -> RMGroupMessage
+	19,  // 3: ReceivedPost.summary:type_name -> PostSummary
+	98,  // 4: ReceivedPost.post:type_name -> PostMetadata
+	99,  // 5: ReceivedPostStatus.status:type_name -> PostMetadataStatus
+	102, // 6: WriteNewInviteResponse.invite:type_name -> OOBPublicIdentityInvite
+	102, // 7: AcceptInviteResponse.invite:type_name -> OOBPublicIdentityInvite
+	40,  // 8: SaveQueryRequest.query:type_name -> SavedQuery
+	40,  // 9: ListSavedQueriesResponse.queries:type_name -> SavedQuery
+	46,  // 10: RunSavedQueryResponse.results:type_name -> SavedQueryResult
+	104, // 11: GetGCResponse.gc:type_name -> RMGroupList
+	107, // 12: ListGCsResponse.gcs:type_name -> ListGCsResponse.GCInfo
+	103, // 13: ReceivedGCInvite.invite:type_name -> RMGroupInvite
+	58,  // 14: GCMembersAddedEvent.users:type_name -> UserAndNick
+	58,  // 15: GCMembersRemovedEvent.users:type_name -> UserAndNick
+	104, // 16: JoinedGCEvent.gc:type_name -> RMGroupList
+	67,  // 17: ListPendingPaymentsResponse.payments:type_name -> PendingPayment
+	105, // 18: ResourceRequestsStreamResponse.request:type_name -> RMFetchResource
+	106, // 19: FulfillResourceRequest.response:type_name -> RMFetchResourceReply
+	78,  // 20: ListProductsResponse.products:type_name -> StoreProduct
+	78,  // 21: AddProductRequest.product:type_name -> StoreProduct
+	78,  // 22: AddProductResponse.product:type_name -> StoreProduct
+	78,  // 23: UpdateProductRequest.product:type_name -> StoreProduct
+	78,  // 24: UpdateProductResponse.product:type_name -> StoreProduct
+	85,  // 25: StoreOrder.items:type_name -> StoreOrderItem
+	86,  // 26: ListOrdersResponse.orders:type_name -> StoreOrder
+	92,  // 27: SalesTotalsResponse.totals:type_name -> SalesTotal
+	85,  // 28: StoreEvent.cart_items:type_name -> StoreOrderItem
+	86,  // 29: StoreEvent.order:type_name -> StoreOrder
+	0,   // 30: RMPrivateMessage.mode:type_name -> MessageMode
+	0,   // 31: RMGroupMessage.mode:type_name -> MessageMode
+	108, // 32: PostMetadata.attributes:type_name -> PostMetadata.AttributesEntry
+	109, // 33: PostMetadataStatus.attributes:type_name -> PostMetadataStatus.AttributesEntry
+	100, // 34: OOBPublicIdentityInvite.public:type_name -> PublicIdentity
+	101, // 35: OOBPublicIdentityInvite.funds:type_name -> InviteFunds
+	110, // 36: RMFetchResource.meta:type_name -> RMFetchResource.MetaEntry
+	111, // 37: RMFetchResourceReply.meta:type_name -> RMFetchResourceReply.MetaEntry
+	1,   // 38: VersionService.Version:input_type -> VersionRequest
+	3,   // 39: VersionService.KeepaliveStream:input_type -> KeepaliveStreamRequest
+	7,   // 40: ChatService.PM:input_type -> PMRequest
+	9,   // 41: ChatService.PMStream:input_type -> PMStreamRequest
+	5,   // 42: ChatService.AckReceivedPM:input_type -> AckRequest
+	11,  // 43: ChatService.GCM:input_type -> GCMRequest
+	13,  // 44: ChatService.GCMStream:input_type -> GCMStreamRequest
+	5,   // 45: ChatService.AckReceivedGCM:input_type -> AckRequest
+	26,  // 46: ChatService.MediateKX:input_type -> MediateKXRequest
+	28,  // 47: ChatService.KXStream:input_type -> KXStreamRequest
+	5,   // 48: ChatService.AckKXCompleted:input_type -> AckRequest
+	30,  // 49: ChatService.WriteNewInvite:input_type -> WriteNewInviteRequest
+	32,  // 50: ChatService.AcceptInvite:input_type -> AcceptInviteRequest
+	38,  // 51: ChatService.SendFile:input_type -> SendFileRequest
+	41,  // 52: ChatService.SaveQuery:input_type -> SaveQueryRequest
+	43,  // 53: ChatService.ListSavedQueries:input_type -> ListSavedQueriesRequest
+	45,  // 54: ChatService.RunSavedQuery:input_type -> RunSavedQueryRequest
+	48,  // 55: ChatService.RemoveSavedQuery:input_type -> RemoveSavedQueryRequest
+	34,  // 56: GCService.InviteToGC:input_type -> InviteToGCRequest
+	36,  // 57: GCService.AcceptGCInvite:input_type -> AcceptGCInviteRequest
+	50,  // 58: GCService.KickFromGC:input_type -> KickFromGCRequest
+	52,  // 59: GCService.GetGC:input_type -> GetGCRequest
+	54,  // 60: GCService.List:input_type -> ListGCsRequest
+	56,  // 61: GCService.ReceivedGCInvites:input_type -> ReceivedGCInvitesRequest
+	5,   // 62: GCService.AckReceivedGCInvites:input_type -> AckRequest
+	59,  // 63: GCService.MembersAdded:input_type -> GCMembersAddedRequest
+	5,   // 64: GCService.AckMembersAdded:input_type -> AckRequest
+	61,  // 65: GCService.MembersRemoved:input_type -> GCMembersRemovedRequest
+	5,   // 66: GCService.AckMembersRemoved:input_type -> AckRequest
+	63,  // 67: GCService.JoinedGCs:input_type -> JoinedGCsRequest
+	5,   // 68: GCService.AckJoinedGCs:input_type -> AckRequest
+	15,  // 69: PostsService.SubscribeToPosts:input_type -> SubscribeToPostsRequest
+	17,  // 70: PostsService.UnsubscribeToPosts:input_type -> UnsubscribeToPostsRequest
+	20,  // 71: PostsService.PostsStream:input_type -> PostsStreamRequest
+	5,   // 72: PostsService.AckReceivedPost:input_type -> AckRequest
+	22,  // 73: PostsService.PostsStatusStream:input_type -> PostsStatusStreamRequest
+	5,   // 74: PostsService.AckReceivedPostStatus:input_type -> AckRequest
+	24,  // 75: PaymentsService.TipUser:input_type -> TipUserRequest
+	65,  // 76: PaymentsService.TipProgress:input_type -> TipProgressRequest
+	5,   // 77: PaymentsService.AckTipProgress:input_type -> AckRequest
+	68,  // 78: PaymentsService.ListPendingPayments:input_type -> ListPendingPaymentsRequest
+	70,  // 79: PaymentsService.ApprovePayment:input_type -> ApprovePaymentRequest
+	72,  // 80: PaymentsService.DenyPayment:input_type -> DenyPaymentRequest
+	74,  // 81: ResourcesService.RequestsStream:input_type -> ResourceRequestsStreamRequest
+	76,  // 82: ResourcesService.FulfillRequest:input_type -> FulfillResourceRequest
+	79,  // 83: StoreService.ListProducts:input_type -> ListProductsRequest
+	81,  // 84: StoreService.AddProduct:input_type -> AddProductRequest
+	83,  // 85: StoreService.UpdateProduct:input_type -> UpdateProductRequest
+	87,  // 86: StoreService.ListOrders:input_type -> ListOrdersRequest
+	89,  // 87: StoreService.UpdateOrderStatus:input_type -> UpdateOrderStatusRequest
+	91,  // 88: StoreService.SalesTotals:input_type -> SalesTotalsRequest
+	94,  // 89: StoreService.StoreEvents:input_type -> StoreEventsRequest
+	5,   // 90: StoreService.AckStoreEvents:input_type -> AckRequest
+	2,   // 91: VersionService.Version:output_type -> VersionResponse
+	4,   // 92: VersionService.KeepaliveStream:output_type -> KeepaliveEvent
+	8,   // 93: ChatService.PM:output_type -> PMResponse
+	10,  // 94: ChatService.PMStream:output_type -> ReceivedPM
+	6,   // 95: ChatService.AckReceivedPM:output_type -> AckResponse
+	12,  // 96: ChatService.GCM:output_type -> GCMResponse
+	14,  // 97: ChatService.GCMStream:output_type -> GCReceivedMsg
+	6,   // 98: ChatService.AckReceivedGCM:output_type -> AckResponse
+	27,  // 99: ChatService.MediateKX:output_type -> MediateKXResponse
+	29,  // 100: ChatService.KXStream:output_type -> KXCompleted
+	6,   // 101: ChatService.AckKXCompleted:output_type -> AckResponse
+	31,  // 102: ChatService.WriteNewInvite:output_type -> WriteNewInviteResponse
+	33,  // 103: ChatService.AcceptInvite:output_type -> AcceptInviteResponse
+	39,  // 104: ChatService.SendFile:output_type -> SendFileResponse
+	42,  // 105: ChatService.SaveQuery:output_type -> SaveQueryResponse
+	44,  // 106: ChatService.ListSavedQueries:output_type -> ListSavedQueriesResponse
+	47,  // 107: ChatService.RunSavedQuery:output_type -> RunSavedQueryResponse
+	49,  // 108: ChatService.RemoveSavedQuery:output_type -> RemoveSavedQueryResponse
+	35,  // 109: GCService.InviteToGC:output_type -> InviteToGCResponse
+	37,  // 110: GCService.AcceptGCInvite:output_type -> AcceptGCInviteResponse
+	51,  // 111: GCService.KickFromGC:output_type -> KickFromGCResponse
+	53,  // 112: GCService.GetGC:output_type -> GetGCResponse
+	55,  // 113: GCService.List:output_type -> ListGCsResponse
+	57,  // 114: GCService.ReceivedGCInvites:output_type -> ReceivedGCInvite
+	6,   // 115: GCService.AckReceivedGCInvites:output_type -> AckResponse
+	60,  // 116: GCService.MembersAdded:output_type -> GCMembersAddedEvent
+	6,   // 117: GCService.AckMembersAdded:output_type -> AckResponse
+	62,  // 118: GCService.MembersRemoved:output_type -> GCMembersRemovedEvent
+	6,   // 119: GCService.AckMembersRemoved:output_type -> AckResponse
+	64,  // 120: GCService.JoinedGCs:output_type -> JoinedGCEvent
+	6,   // 121: GCService.AckJoinedGCs:output_type -> AckResponse
+	16,  // 122: PostsService.SubscribeToPosts:output_type -> SubscribeToPostsResponse
+	18,  // 123: PostsService.UnsubscribeToPosts:output_type -> UnsubscribeToPostsResponse
+	21,  // 124: PostsService.PostsStream:output_type -> ReceivedPost
+	6,   // 125: PostsService.AckReceivedPost:output_type -> AckResponse
+	23,  // 126: PostsService.PostsStatusStream:output_type -> ReceivedPostStatus
+	6,   // 127: PostsService.AckReceivedPostStatus:output_type -> AckResponse
+	25,  // 128: PaymentsService.TipUser:output_type -> TipUserResponse
+	66,  // 129: PaymentsService.TipProgress:output_type -> TipProgressEvent
+	6,   // 130: PaymentsService.AckTipProgress:output_type -> AckResponse
+	69,  // 131: PaymentsService.ListPendingPayments:output_type -> ListPendingPaymentsResponse
+	71,  // 132: PaymentsService.ApprovePayment:output_type -> ApprovePaymentResponse
+	73,  // 133: PaymentsService.DenyPayment:output_type -> DenyPaymentResponse
+	75,  // 134: ResourcesService.RequestsStream:output_type -> ResourceRequestsStreamResponse
+	77,  // 135: ResourcesService.FulfillRequest:output_type -> FulfillResourceRequestResponse
+	80,  // 136: StoreService.ListProducts:output_type -> ListProductsResponse
+	82,  // 137: StoreService.AddProduct:output_type -> AddProductResponse
+	84,  // 138: StoreService.UpdateProduct:output_type -> UpdateProductResponse
+	88,  // 139: StoreService.ListOrders:output_type -> ListOrdersResponse
+	90,  // 140: StoreService.UpdateOrderStatus:output_type -> UpdateOrderStatusResponse
+	93,  // 141: StoreService.SalesTotals:output_type -> SalesTotalsResponse
+	95,  // 142: StoreService.StoreEvents:output_type -> StoreEvent
+	6,   // 143: StoreService.AckStoreEvents:output_type -> AckResponse
+	91,  // [91:144] is the sub-list for method output_type
+	38,  // [38:91] is the sub-list for method input_type
+	38,  // [38:38] is the sub-list for extension type_name
+	38,  // [38:38] is the sub-list for extension extendee
+	0,   // [0:38] is the sub-list for field type_name
+}
+
+func init() { file_clientrpc_proto_init() }
+func file_clientrpc_proto_init() {
+	if File_clientrpc_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_clientrpc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeepaliveStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeepaliveEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AckRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AckResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PMRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PMResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PMStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceivedPM); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCMRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCMResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCMStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCReceivedMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeToPostsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeToPostsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnsubscribeToPostsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnsubscribeToPostsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PostSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PostsStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceivedPost); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PostsStatusStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceivedPostStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TipUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TipUserResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediateKXRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediateKXResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KXStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KXCompleted); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WriteNewInviteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WriteNewInviteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5282,8 +7990,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VersionResponse); i {
+		file_clientrpc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceptInviteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5294,8 +8002,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KeepaliveStreamRequest); i {
+		file_clientrpc_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceptInviteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5306,8 +8014,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KeepaliveEvent); i {
+		file_clientrpc_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InviteToGCRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5318,8 +8026,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AckRequest); i {
+		file_clientrpc_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InviteToGCResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5330,8 +8038,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AckResponse); i {
+		file_clientrpc_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceptGCInviteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5342,8 +8050,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PMRequest); i {
+		file_clientrpc_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceptGCInviteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5354,8 +8062,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PMResponse); i {
+		file_clientrpc_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendFileRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5366,8 +8074,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PMStreamRequest); i {
+		file_clientrpc_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendFileResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5378,8 +8086,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReceivedPM); i {
+		file_clientrpc_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SavedQuery); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5390,8 +8098,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GCMRequest); i {
+		file_clientrpc_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SaveQueryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5402,8 +8110,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GCMResponse); i {
+		file_clientrpc_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SaveQueryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5414,8 +8122,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GCMStreamRequest); i {
+		file_clientrpc_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSavedQueriesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5426,8 +8134,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GCReceivedMsg); i {
+		file_clientrpc_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSavedQueriesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5438,8 +8146,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribeToPostsRequest); i {
+		file_clientrpc_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunSavedQueryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5450,8 +8158,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribeToPostsResponse); i {
+		file_clientrpc_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SavedQueryResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5462,8 +8170,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UnsubscribeToPostsRequest); i {
+		file_clientrpc_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunSavedQueryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5474,8 +8182,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UnsubscribeToPostsResponse); i {
+		file_clientrpc_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveSavedQueryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5486,8 +8194,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PostSummary); i {
+		file_clientrpc_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveSavedQueryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5498,8 +8206,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PostsStreamRequest); i {
+		file_clientrpc_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KickFromGCRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5510,8 +8218,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReceivedPost); i {
+		file_clientrpc_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KickFromGCResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5522,8 +8230,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PostsStatusStreamRequest); i {
+		file_clientrpc_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetGCRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5534,8 +8242,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReceivedPostStatus); i {
+		file_clientrpc_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetGCResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5546,8 +8254,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TipUserRequest); i {
+		file_clientrpc_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListGCsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5558,8 +8266,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TipUserResponse); i {
+		file_clientrpc_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListGCsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5570,8 +8278,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MediateKXRequest); i {
+		file_clientrpc_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceivedGCInvitesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5582,8 +8290,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MediateKXResponse); i {
+		file_clientrpc_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceivedGCInvite); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5594,8 +8302,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KXStreamRequest); i {
+		file_clientrpc_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserAndNick); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5606,8 +8314,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KXCompleted); i {
+		file_clientrpc_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCMembersAddedRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5618,8 +8326,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WriteNewInviteRequest); i {
+		file_clientrpc_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCMembersAddedEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5630,8 +8338,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WriteNewInviteResponse); i {
+		file_clientrpc_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCMembersRemovedRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5642,8 +8350,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AcceptInviteRequest); i {
+		file_clientrpc_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCMembersRemovedEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5654,8 +8362,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AcceptInviteResponse); i {
+		file_clientrpc_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JoinedGCsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5666,8 +8374,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InviteToGCRequest); i {
+		file_clientrpc_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JoinedGCEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5678,8 +8386,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InviteToGCResponse); i {
+		file_clientrpc_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TipProgressRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5690,8 +8398,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AcceptGCInviteRequest); i {
+		file_clientrpc_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TipProgressEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5702,8 +8410,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AcceptGCInviteResponse); i {
+		file_clientrpc_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PendingPayment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5714,8 +8422,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendFileRequest); i {
+		file_clientrpc_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPendingPaymentsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5726,8 +8434,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendFileResponse); i {
+		file_clientrpc_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPendingPaymentsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5738,8 +8446,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KickFromGCRequest); i {
+		file_clientrpc_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApprovePaymentRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5750,8 +8458,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KickFromGCResponse); i {
+		file_clientrpc_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApprovePaymentResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5762,8 +8470,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetGCRequest); i {
+		file_clientrpc_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DenyPaymentRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5774,8 +8482,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetGCResponse); i {
+		file_clientrpc_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DenyPaymentResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5786,8 +8494,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListGCsRequest); i {
+		file_clientrpc_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceRequestsStreamRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5798,8 +8506,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListGCsResponse); i {
+		file_clientrpc_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceRequestsStreamResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5810,8 +8518,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReceivedGCInvitesRequest); i {
+		file_clientrpc_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FulfillResourceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5822,8 +8530,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReceivedGCInvite); i {
+		file_clientrpc_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FulfillResourceRequestResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5834,8 +8542,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserAndNick); i {
+		file_clientrpc_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreProduct); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5846,8 +8554,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GCMembersAddedRequest); i {
+		file_clientrpc_proto_msgTypes[78].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProductsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5858,8 +8566,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GCMembersAddedEvent); i {
+		file_clientrpc_proto_msgTypes[79].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProductsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5870,8 +8578,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GCMembersRemovedRequest); i {
+		file_clientrpc_proto_msgTypes[80].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddProductRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5882,8 +8590,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GCMembersRemovedEvent); i {
+		file_clientrpc_proto_msgTypes[81].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddProductResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5894,8 +8602,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JoinedGCsRequest); i {
+		file_clientrpc_proto_msgTypes[82].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateProductRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5906,8 +8614,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JoinedGCEvent); i {
+		file_clientrpc_proto_msgTypes[83].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateProductResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5918,8 +8626,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TipProgressRequest); i {
+		file_clientrpc_proto_msgTypes[84].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreOrderItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5930,8 +8638,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TipProgressEvent); i {
+		file_clientrpc_proto_msgTypes[85].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreOrder); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5942,8 +8650,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResourceRequestsStreamRequest); i {
+		file_clientrpc_proto_msgTypes[86].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOrdersRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5954,8 +8662,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResourceRequestsStreamResponse); i {
+		file_clientrpc_proto_msgTypes[87].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOrdersResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5966,8 +8674,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FulfillResourceRequest); i {
+		file_clientrpc_proto_msgTypes[88].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateOrderStatusRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5978,8 +8686,8 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FulfillResourceRequestResponse); i {
+		file_clientrpc_proto_msgTypes[89].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateOrderStatusResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5990,7 +8698,67 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[90].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SalesTotalsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[91].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SalesTotal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[92].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SalesTotalsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[93].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[94].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_clientrpc_proto_msgTypes[95].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RMPrivateMessage); i {
 			case 0:
 				return &v.state
@@ -6002,7 +8770,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[96].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RMGroupMessage); i {
 			case 0:
 				return &v.state
@@ -6014,7 +8782,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[97].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PostMetadata); i {
 			case 0:
 				return &v.state
@@ -6026,7 +8794,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[98].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PostMetadataStatus); i {
 			case 0:
 				return &v.state
@@ -6038,7 +8806,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[99].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PublicIdentity); i {
 			case 0:
 				return &v.state
@@ -6050,7 +8818,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[100].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*InviteFunds); i {
 			case 0:
 				return &v.state
@@ -6062,7 +8830,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[101].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*OOBPublicIdentityInvite); i {
 			case 0:
 				return &v.state
@@ -6074,7 +8842,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[102].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RMGroupInvite); i {
 			case 0:
 				return &v.state
@@ -6086,7 +8854,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[103].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RMGroupList); i {
 			case 0:
 				return &v.state
@@ -6098,7 +8866,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[104].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RMFetchResource); i {
 			case 0:
 				return &v.state
@@ -6110,7 +8878,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[105].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RMFetchResourceReply); i {
 			case 0:
 				return &v.state
@@ -6122,7 +8890,7 @@ func file_clientrpc_proto_init() {
 				return nil
 			}
 		}
-		file_clientrpc_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+		file_clientrpc_proto_msgTypes[106].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListGCsResponse_GCInfo); i {
 			case 0:
 				return &v.state
@@ -6141,9 +8909,9 @@ func file_clientrpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_clientrpc_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   76,
+			NumMessages:   111,
 			NumExtensions: 0,
-			NumServices:   6,
+			NumServices:   7,
 		},
 		GoTypes:           file_clientrpc_proto_goTypes,
 		DependencyIndexes: file_clientrpc_proto_depIdxs,
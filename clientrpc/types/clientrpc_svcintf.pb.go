@@ -136,6 +136,15 @@ type ChatServiceClient interface {
 	AcceptInvite(ctx context.Context, in *AcceptInviteRequest, out *AcceptInviteResponse) error
 	// SendFile sends a file to a user.
 	SendFile(ctx context.Context, in *SendFileRequest, out *SendFileResponse) error
+	// SaveQuery saves a named query over the local messages or store orders,
+	// replacing any query with the same name.
+	SaveQuery(ctx context.Context, in *SaveQueryRequest, out *SaveQueryResponse) error
+	// ListSavedQueries lists the saved queries.
+	ListSavedQueries(ctx context.Context, in *ListSavedQueriesRequest, out *ListSavedQueriesResponse) error
+	// RunSavedQuery runs a saved query and returns the items that match it.
+	RunSavedQuery(ctx context.Context, in *RunSavedQueryRequest, out *RunSavedQueryResponse) error
+	// RemoveSavedQuery removes a saved query.
+	RemoveSavedQuery(ctx context.Context, in *RemoveSavedQueryRequest, out *RemoveSavedQueryResponse) error
 }
 
 type client_ChatService struct {
@@ -227,6 +236,26 @@ func (c *client_ChatService) SendFile(ctx context.Context, in *SendFileRequest,
 	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
 }
 
+func (c *client_ChatService) SaveQuery(ctx context.Context, in *SaveQueryRequest, out *SaveQueryResponse) error {
+	const method = "SaveQuery"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_ChatService) ListSavedQueries(ctx context.Context, in *ListSavedQueriesRequest, out *ListSavedQueriesResponse) error {
+	const method = "ListSavedQueries"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_ChatService) RunSavedQuery(ctx context.Context, in *RunSavedQueryRequest, out *RunSavedQueryResponse) error {
+	const method = "RunSavedQuery"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_ChatService) RemoveSavedQuery(ctx context.Context, in *RemoveSavedQueryRequest, out *RemoveSavedQueryResponse) error {
+	const method = "RemoveSavedQuery"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
 func NewChatServiceClient(c ClientConn) ChatServiceClient {
 	return &client_ChatService{c: c, defn: ChatServiceDefn()}
 }
@@ -264,6 +293,15 @@ type ChatServiceServer interface {
 	AcceptInvite(context.Context, *AcceptInviteRequest, *AcceptInviteResponse) error
 	// SendFile sends a file to a user.
 	SendFile(context.Context, *SendFileRequest, *SendFileResponse) error
+	// SaveQuery saves a named query over the local messages or store orders,
+	// replacing any query with the same name.
+	SaveQuery(context.Context, *SaveQueryRequest, *SaveQueryResponse) error
+	// ListSavedQueries lists the saved queries.
+	ListSavedQueries(context.Context, *ListSavedQueriesRequest, *ListSavedQueriesResponse) error
+	// RunSavedQuery runs a saved query and returns the items that match it.
+	RunSavedQuery(context.Context, *RunSavedQueryRequest, *RunSavedQueryResponse) error
+	// RemoveSavedQuery removes a saved query.
+	RemoveSavedQuery(context.Context, *RemoveSavedQueryRequest, *RemoveSavedQueryResponse) error
 }
 
 type ChatService_PMStreamServer interface {
@@ -462,6 +500,70 @@ func ChatServiceDefn() ServiceDefn {
 					return conn.Request(ctx, method, request, response)
 				},
 			},
+			"SaveQuery": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(SaveQueryRequest) },
+				NewResponse:  func() proto.Message { return new(SaveQueryResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(SaveQueryRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(SaveQueryResponse).ProtoReflect().Descriptor() },
+				Help:         "SaveQuery saves a named query over the local messages or store orders, replacing any query with the same name.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(ChatServiceServer).SaveQuery(ctx, request.(*SaveQueryRequest), response.(*SaveQueryResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "ChatService.SaveQuery"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"ListSavedQueries": {
+				IsStreaming: false,
+				NewRequest:  func() proto.Message { return new(ListSavedQueriesRequest) },
+				NewResponse: func() proto.Message { return new(ListSavedQueriesResponse) },
+				RequestDefn: func() protoreflect.MessageDescriptor { return new(ListSavedQueriesRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor {
+					return new(ListSavedQueriesResponse).ProtoReflect().Descriptor()
+				},
+				Help: "ListSavedQueries lists the saved queries.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(ChatServiceServer).ListSavedQueries(ctx, request.(*ListSavedQueriesRequest), response.(*ListSavedQueriesResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "ChatService.ListSavedQueries"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"RunSavedQuery": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(RunSavedQueryRequest) },
+				NewResponse:  func() proto.Message { return new(RunSavedQueryResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(RunSavedQueryRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(RunSavedQueryResponse).ProtoReflect().Descriptor() },
+				Help:         "RunSavedQuery runs a saved query and returns the items that match it.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(ChatServiceServer).RunSavedQuery(ctx, request.(*RunSavedQueryRequest), response.(*RunSavedQueryResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "ChatService.RunSavedQuery"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"RemoveSavedQuery": {
+				IsStreaming: false,
+				NewRequest:  func() proto.Message { return new(RemoveSavedQueryRequest) },
+				NewResponse: func() proto.Message { return new(RemoveSavedQueryResponse) },
+				RequestDefn: func() protoreflect.MessageDescriptor { return new(RemoveSavedQueryRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor {
+					return new(RemoveSavedQueryResponse).ProtoReflect().Descriptor()
+				},
+				Help: "RemoveSavedQuery removes a saved query.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(ChatServiceServer).RemoveSavedQuery(ctx, request.(*RemoveSavedQueryRequest), response.(*RemoveSavedQueryResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "ChatService.RemoveSavedQuery"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
 		},
 	}
 }
@@ -1086,6 +1188,13 @@ type PaymentsServiceClient interface {
 	// AckTipProgress acknowledges events received up to a given
 	// sequence_id have been processed.
 	AckTipProgress(ctx context.Context, in *AckRequest, out *AckResponse) error
+	// ListPendingPayments lists the outgoing payments waiting for approval, when
+	// the audit mode of outgoing payments is enabled.
+	ListPendingPayments(ctx context.Context, in *ListPendingPaymentsRequest, out *ListPendingPaymentsResponse) error
+	// ApprovePayment approves an outgoing payment waiting for approval.
+	ApprovePayment(ctx context.Context, in *ApprovePaymentRequest, out *ApprovePaymentResponse) error
+	// DenyPayment denies an outgoing payment waiting for approval.
+	DenyPayment(ctx context.Context, in *DenyPaymentRequest, out *DenyPaymentResponse) error
 }
 
 type client_PaymentsService struct {
@@ -1116,6 +1225,21 @@ func (c *client_PaymentsService) AckTipProgress(ctx context.Context, in *AckRequ
 	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
 }
 
+func (c *client_PaymentsService) ListPendingPayments(ctx context.Context, in *ListPendingPaymentsRequest, out *ListPendingPaymentsResponse) error {
+	const method = "ListPendingPayments"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_PaymentsService) ApprovePayment(ctx context.Context, in *ApprovePaymentRequest, out *ApprovePaymentResponse) error {
+	const method = "ApprovePayment"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_PaymentsService) DenyPayment(ctx context.Context, in *DenyPaymentRequest, out *DenyPaymentResponse) error {
+	const method = "DenyPayment"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
 func NewPaymentsServiceClient(c ClientConn) PaymentsServiceClient {
 	return &client_PaymentsService{c: c, defn: PaymentsServiceDefn()}
 }
@@ -1132,6 +1256,13 @@ type PaymentsServiceServer interface {
 	// AckTipProgress acknowledges events received up to a given
 	// sequence_id have been processed.
 	AckTipProgress(context.Context, *AckRequest, *AckResponse) error
+	// ListPendingPayments lists the outgoing payments waiting for approval, when
+	// the audit mode of outgoing payments is enabled.
+	ListPendingPayments(context.Context, *ListPendingPaymentsRequest, *ListPendingPaymentsResponse) error
+	// ApprovePayment approves an outgoing payment waiting for approval.
+	ApprovePayment(context.Context, *ApprovePaymentRequest, *ApprovePaymentResponse) error
+	// DenyPayment denies an outgoing payment waiting for approval.
+	DenyPayment(context.Context, *DenyPaymentRequest, *DenyPaymentResponse) error
 }
 
 type PaymentsService_TipProgressServer interface {
@@ -1187,6 +1318,55 @@ func PaymentsServiceDefn() ServiceDefn {
 					return conn.Request(ctx, method, request, response)
 				},
 			},
+			"ListPendingPayments": {
+				IsStreaming: false,
+				NewRequest:  func() proto.Message { return new(ListPendingPaymentsRequest) },
+				NewResponse: func() proto.Message { return new(ListPendingPaymentsResponse) },
+				RequestDefn: func() protoreflect.MessageDescriptor {
+					return new(ListPendingPaymentsRequest).ProtoReflect().Descriptor()
+				},
+				ResponseDefn: func() protoreflect.MessageDescriptor {
+					return new(ListPendingPaymentsResponse).ProtoReflect().Descriptor()
+				},
+				Help: "ListPendingPayments lists the outgoing payments waiting for approval, when the audit mode of outgoing payments is enabled.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(PaymentsServiceServer).ListPendingPayments(ctx, request.(*ListPendingPaymentsRequest), response.(*ListPendingPaymentsResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "PaymentsService.ListPendingPayments"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"ApprovePayment": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(ApprovePaymentRequest) },
+				NewResponse:  func() proto.Message { return new(ApprovePaymentResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(ApprovePaymentRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(ApprovePaymentResponse).ProtoReflect().Descriptor() },
+				Help:         "ApprovePayment approves an outgoing payment waiting for approval.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(PaymentsServiceServer).ApprovePayment(ctx, request.(*ApprovePaymentRequest), response.(*ApprovePaymentResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "PaymentsService.ApprovePayment"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"DenyPayment": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(DenyPaymentRequest) },
+				NewResponse:  func() proto.Message { return new(DenyPaymentResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(DenyPaymentRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(DenyPaymentResponse).ProtoReflect().Descriptor() },
+				Help:         "DenyPayment denies an outgoing payment waiting for approval.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(PaymentsServiceServer).DenyPayment(ctx, request.(*DenyPaymentRequest), response.(*DenyPaymentResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "PaymentsService.DenyPayment"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
 		},
 	}
 }
@@ -1287,6 +1467,245 @@ func ResourcesServiceDefn() ServiceDefn {
 	}
 }
 
+// StoreServiceClient is the client API for StoreService service.
+type StoreServiceClient interface {
+	// ListProducts lists the products of the store.
+	ListProducts(ctx context.Context, in *ListProductsRequest, out *ListProductsResponse) error
+	// AddProduct adds a new product to the store.
+	AddProduct(ctx context.Context, in *AddProductRequest, out *AddProductResponse) error
+	// UpdateProduct updates an existing product of the store.
+	UpdateProduct(ctx context.Context, in *UpdateProductRequest, out *UpdateProductResponse) error
+	// ListOrders lists the orders placed in the store.
+	ListOrders(ctx context.Context, in *ListOrdersRequest, out *ListOrdersResponse) error
+	// UpdateOrderStatus changes the status of an order. Only transitions
+	// allowed by the order lifecycle are accepted.
+	UpdateOrderStatus(ctx context.Context, in *UpdateOrderStatusRequest, out *UpdateOrderStatusResponse) error
+	// SalesTotals returns the totals of the sales of the store.
+	SalesTotals(ctx context.Context, in *SalesTotalsRequest, out *SalesTotalsResponse) error
+	// StoreEvents streams the activity events of the store: carts updated,
+	// orders placed, orders paid and order status changes.
+	StoreEvents(ctx context.Context, in *StoreEventsRequest) (StoreService_StoreEventsClient, error)
+	// AckStoreEvents acks to the server that store events up to a given
+	// sequence_id have been processed.
+	AckStoreEvents(ctx context.Context, in *AckRequest, out *AckResponse) error
+}
+
+type client_StoreService struct {
+	c    ClientConn
+	defn ServiceDefn
+}
+
+func (c *client_StoreService) ListProducts(ctx context.Context, in *ListProductsRequest, out *ListProductsResponse) error {
+	const method = "ListProducts"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_StoreService) AddProduct(ctx context.Context, in *AddProductRequest, out *AddProductResponse) error {
+	const method = "AddProduct"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_StoreService) UpdateProduct(ctx context.Context, in *UpdateProductRequest, out *UpdateProductResponse) error {
+	const method = "UpdateProduct"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_StoreService) ListOrders(ctx context.Context, in *ListOrdersRequest, out *ListOrdersResponse) error {
+	const method = "ListOrders"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_StoreService) UpdateOrderStatus(ctx context.Context, in *UpdateOrderStatusRequest, out *UpdateOrderStatusResponse) error {
+	const method = "UpdateOrderStatus"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func (c *client_StoreService) SalesTotals(ctx context.Context, in *SalesTotalsRequest, out *SalesTotalsResponse) error {
+	const method = "SalesTotals"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+type StoreService_StoreEventsClient interface {
+	Recv(*StoreEvent) error
+}
+
+func (c *client_StoreService) StoreEvents(ctx context.Context, in *StoreEventsRequest) (StoreService_StoreEventsClient, error) {
+	const method = "StoreEvents"
+	inner, err := c.defn.Methods[method].ClientStreamHandler(c.c, ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return streamerImpl[*StoreEvent]{c: inner}, nil
+}
+
+func (c *client_StoreService) AckStoreEvents(ctx context.Context, in *AckRequest, out *AckResponse) error {
+	const method = "AckStoreEvents"
+	return c.defn.Methods[method].ClientHandler(c.c, ctx, in, out)
+}
+
+func NewStoreServiceClient(c ClientConn) StoreServiceClient {
+	return &client_StoreService{c: c, defn: StoreServiceDefn()}
+}
+
+// StoreServiceServer is the server API for StoreService service.
+type StoreServiceServer interface {
+	// ListProducts lists the products of the store.
+	ListProducts(context.Context, *ListProductsRequest, *ListProductsResponse) error
+	// AddProduct adds a new product to the store.
+	AddProduct(context.Context, *AddProductRequest, *AddProductResponse) error
+	// UpdateProduct updates an existing product of the store.
+	UpdateProduct(context.Context, *UpdateProductRequest, *UpdateProductResponse) error
+	// ListOrders lists the orders placed in the store.
+	ListOrders(context.Context, *ListOrdersRequest, *ListOrdersResponse) error
+	// UpdateOrderStatus changes the status of an order. Only transitions
+	// allowed by the order lifecycle are accepted.
+	UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest, *UpdateOrderStatusResponse) error
+	// SalesTotals returns the totals of the sales of the store.
+	SalesTotals(context.Context, *SalesTotalsRequest, *SalesTotalsResponse) error
+	// StoreEvents streams the activity events of the store: carts updated,
+	// orders placed, orders paid and order status changes.
+	StoreEvents(context.Context, *StoreEventsRequest, StoreService_StoreEventsServer) error
+	// AckStoreEvents acks to the server that store events up to a given
+	// sequence_id have been processed.
+	AckStoreEvents(context.Context, *AckRequest, *AckResponse) error
+}
+
+type StoreService_StoreEventsServer interface {
+	Send(m *StoreEvent) error
+}
+
+func StoreServiceDefn() ServiceDefn {
+	return ServiceDefn{
+		Name: "StoreService",
+		Methods: map[string]MethodDefn{
+			"ListProducts": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(ListProductsRequest) },
+				NewResponse:  func() proto.Message { return new(ListProductsResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(ListProductsRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(ListProductsResponse).ProtoReflect().Descriptor() },
+				Help:         "ListProducts lists the products of the store.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(StoreServiceServer).ListProducts(ctx, request.(*ListProductsRequest), response.(*ListProductsResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "StoreService.ListProducts"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"AddProduct": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(AddProductRequest) },
+				NewResponse:  func() proto.Message { return new(AddProductResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(AddProductRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(AddProductResponse).ProtoReflect().Descriptor() },
+				Help:         "AddProduct adds a new product to the store.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(StoreServiceServer).AddProduct(ctx, request.(*AddProductRequest), response.(*AddProductResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "StoreService.AddProduct"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"UpdateProduct": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(UpdateProductRequest) },
+				NewResponse:  func() proto.Message { return new(UpdateProductResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(UpdateProductRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(UpdateProductResponse).ProtoReflect().Descriptor() },
+				Help:         "UpdateProduct updates an existing product of the store.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(StoreServiceServer).UpdateProduct(ctx, request.(*UpdateProductRequest), response.(*UpdateProductResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "StoreService.UpdateProduct"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"ListOrders": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(ListOrdersRequest) },
+				NewResponse:  func() proto.Message { return new(ListOrdersResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(ListOrdersRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(ListOrdersResponse).ProtoReflect().Descriptor() },
+				Help:         "ListOrders lists the orders placed in the store.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(StoreServiceServer).ListOrders(ctx, request.(*ListOrdersRequest), response.(*ListOrdersResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "StoreService.ListOrders"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"UpdateOrderStatus": {
+				IsStreaming: false,
+				NewRequest:  func() proto.Message { return new(UpdateOrderStatusRequest) },
+				NewResponse: func() proto.Message { return new(UpdateOrderStatusResponse) },
+				RequestDefn: func() protoreflect.MessageDescriptor {
+					return new(UpdateOrderStatusRequest).ProtoReflect().Descriptor()
+				},
+				ResponseDefn: func() protoreflect.MessageDescriptor {
+					return new(UpdateOrderStatusResponse).ProtoReflect().Descriptor()
+				},
+				Help: "UpdateOrderStatus changes the status of an order. Only transitions allowed by the order lifecycle are accepted.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(StoreServiceServer).UpdateOrderStatus(ctx, request.(*UpdateOrderStatusRequest), response.(*UpdateOrderStatusResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "StoreService.UpdateOrderStatus"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"SalesTotals": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(SalesTotalsRequest) },
+				NewResponse:  func() proto.Message { return new(SalesTotalsResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(SalesTotalsRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(SalesTotalsResponse).ProtoReflect().Descriptor() },
+				Help:         "SalesTotals returns the totals of the sales of the store.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(StoreServiceServer).SalesTotals(ctx, request.(*SalesTotalsRequest), response.(*SalesTotalsResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "StoreService.SalesTotals"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+			"StoreEvents": {
+				IsStreaming:  true,
+				NewRequest:   func() proto.Message { return new(StoreEventsRequest) },
+				NewResponse:  func() proto.Message { return new(StoreEvent) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(StoreEventsRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(StoreEvent).ProtoReflect().Descriptor() },
+				Help:         "StoreEvents streams the activity events of the store: carts updated, orders placed, orders paid and order status changes.",
+				ServerStreamHandler: func(x interface{}, ctx context.Context, request proto.Message, stream ServerStream) error {
+					return x.(StoreServiceServer).StoreEvents(ctx, request.(*StoreEventsRequest), streamerImpl[*StoreEvent]{s: stream})
+				},
+				ClientStreamHandler: func(conn ClientConn, ctx context.Context, request proto.Message) (ClientStream, error) {
+					method := "StoreService.StoreEvents"
+					return conn.Stream(ctx, method, request)
+				},
+			},
+			"AckStoreEvents": {
+				IsStreaming:  false,
+				NewRequest:   func() proto.Message { return new(AckRequest) },
+				NewResponse:  func() proto.Message { return new(AckResponse) },
+				RequestDefn:  func() protoreflect.MessageDescriptor { return new(AckRequest).ProtoReflect().Descriptor() },
+				ResponseDefn: func() protoreflect.MessageDescriptor { return new(AckResponse).ProtoReflect().Descriptor() },
+				Help:         "AckStoreEvents acks to the server that store events up to a given sequence_id have been processed.",
+				ServerHandler: func(x interface{}, ctx context.Context, request, response proto.Message) error {
+					return x.(StoreServiceServer).AckStoreEvents(ctx, request.(*AckRequest), response.(*AckResponse))
+				},
+				ClientHandler: func(conn ClientConn, ctx context.Context, request, response proto.Message) error {
+					method := "StoreService.AckStoreEvents"
+					return conn.Request(ctx, method, request, response)
+				},
+			},
+		},
+	}
+}
+
 var help_messages = map[string]map[string]string{
 	"VersionRequest": {
 		"@": "",
@@ -1472,6 +1891,48 @@ var help_messages = map[string]map[string]string{
 	"SendFileResponse": {
 		"@": "SendFileResponse is the response to sending a file to a user.",
 	},
+	"SavedQuery": {
+		"@":       "SavedQuery is a named query over the local data of the client.",
+		"name":    "name is the unique name of the query.",
+		"kind":    "kind is the kind of data queried: messages (the logged PMs and GC messages) or orders (the orders of the simple store).",
+		"terms":   "terms are the terms of the query, in the field=value format.",
+		"created": "created is the unix timestamp of when the query was saved.",
+	},
+	"SaveQueryRequest": {
+		"@":     "SaveQueryRequest is the request to save a named query.",
+		"query": "query is the query to save. Its created field is ignored.",
+	},
+	"SaveQueryResponse": {
+		"@": "SaveQueryResponse is the response to saving a named query.",
+	},
+	"ListSavedQueriesRequest": {
+		"@": "ListSavedQueriesRequest is the request to list the saved queries.",
+	},
+	"ListSavedQueriesResponse": {
+		"@":       "ListSavedQueriesResponse lists the saved queries, sorted by name.",
+		"queries": "",
+	},
+	"RunSavedQueryRequest": {
+		"@":    "RunSavedQueryRequest is the request to run a saved query.",
+		"name": "name is the name of the query.",
+	},
+	"SavedQueryResult": {
+		"@":         "SavedQueryResult is an item found by a saved query.",
+		"timestamp": "timestamp is the unix timestamp of the item.",
+		"source":    "source identifies where the item was found (for example, the user or GC of a message or the ID of an order).",
+		"text":      "text is the text of the item.",
+	},
+	"RunSavedQueryResponse": {
+		"@":       "RunSavedQueryResponse is the response to running a saved query.",
+		"results": "results are the items that match the query, from the most recent.",
+	},
+	"RemoveSavedQueryRequest": {
+		"@":    "RemoveSavedQueryRequest is the request to remove a saved query.",
+		"name": "name is the name of the query.",
+	},
+	"RemoveSavedQueryResponse": {
+		"@": "RemoveSavedQueryResponse is the response to removing a saved query.",
+	},
 	"KickFromGCRequest": {
 		"@":      "KickFromGCRequest is the request to kick an user from a GC.",
 		"gc":     "gc is the hex-encoded ID or alias of the target GC.",
@@ -1560,6 +2021,37 @@ var help_messages = map[string]map[string]string{
 		"attempt_err":   "attempt_err is filled when the attempt to fetch an invoice or perform the payment for a received invoice failed.",
 		"will_retry":    "will_retry flags whether a new attempt to request an invoice and perform a payment will be done or if no more attempts will happen.",
 	},
+	"PendingPayment": {
+		"@":             "PendingPayment is an outgoing payment waiting for approval.",
+		"id":            "id identifies the payment in the approve and deny calls.",
+		"category":      "category is the category of the payment (tip, download or store).",
+		"uid":           "uid is the ID of the user being paid, if known.",
+		"invoice":       "invoice is the invoice being paid. It is empty for keysend payments.",
+		"amount_matoms": "amount_matoms is the amount being paid, in milliatoms.",
+		"requested":     "requested is the unix timestamp of when the payment was held.",
+	},
+	"ListPendingPaymentsRequest": {
+		"@": "ListPendingPaymentsRequest is the request to list the payments waiting for approval.",
+	},
+	"ListPendingPaymentsResponse": {
+		"@":        "ListPendingPaymentsResponse lists the payments waiting for approval, from the oldest one.",
+		"payments": "",
+	},
+	"ApprovePaymentRequest": {
+		"@":  "ApprovePaymentRequest is the request to approve a payment.",
+		"id": "id is the id of the payment.",
+	},
+	"ApprovePaymentResponse": {
+		"@": "ApprovePaymentResponse is the response to approving a payment.",
+	},
+	"DenyPaymentRequest": {
+		"@":      "DenyPaymentRequest is the request to deny a payment.",
+		"id":     "id is the id of the payment.",
+		"reason": "reason is an optional reason recorded in the payment audit log.",
+	},
+	"DenyPaymentResponse": {
+		"@": "DenyPaymentResponse is the response to denying a payment.",
+	},
 	"ResourceRequestsStreamRequest": {
 		"@": "ResourceRequestsStreamRequest is the request for a stream to receive resource requests.",
 	},
@@ -1579,6 +2071,108 @@ var help_messages = map[string]map[string]string{
 	"FulfillResourceRequestResponse": {
 		"@": "FulfillResourceRequestResponse is the response to a FulfillResourceResquest call.",
 	},
+	"StoreProduct": {
+		"@":            "StoreProduct is a product of the store.",
+		"sku":          "sku is the unique identifier of the product.",
+		"title":        "title is the title of the product.",
+		"description":  "description is the description of the product.",
+		"tags":         "tags are the tags of the product.",
+		"price":        "price is the price of the product, in the currency of the store.",
+		"category":     "category is the slash separated path of the category of the product.",
+		"shipping":     "shipping is true if the product needs to be shipped.",
+		"digital_file": "digital_file is the file delivered to buyers once their order is paid.",
+		"has_stock":    "has_stock is true if the stock of the product is tracked. Products without tracked stock have unlimited stock.",
+		"stock":        "stock is the number of units available for sale, when has_stock is true. When adding a product, this is its initial stock. Updating a product does not change its stock.",
+		"archived":     "archived is true if the product is disabled and not shown in the store.",
+	},
+	"ListProductsRequest": {
+		"@":                "ListProductsRequest is the request to list the products of the store.",
+		"include_archived": "include_archived also lists the archived products.",
+	},
+	"ListProductsResponse": {
+		"@":        "ListProductsResponse is the list of products of the store.",
+		"products": "products are the products of the store, sorted by SKU.",
+	},
+	"AddProductRequest": {
+		"@":       "AddProductRequest is the request to add a product to the store.",
+		"product": "product is the product to add. Its SKU must not be used by an existing product.",
+	},
+	"AddProductResponse": {
+		"@":       "AddProductResponse is the response to an AddProduct call.",
+		"product": "product is the added product.",
+	},
+	"UpdateProductRequest": {
+		"@":       "UpdateProductRequest is the request to update a product of the store.",
+		"product": "product is the new definition of the product with the same SKU.",
+	},
+	"UpdateProductResponse": {
+		"@":       "UpdateProductResponse is the response to an UpdateProduct call.",
+		"product": "product is the updated product.",
+	},
+	"StoreOrderItem": {
+		"@":        "StoreOrderItem is an item of an order.",
+		"sku":      "sku is the SKU of the ordered product.",
+		"title":    "title is the title of the ordered product.",
+		"quantity": "quantity is the number of ordered units.",
+		"price":    "price is the unit price of the product when the order was placed.",
+	},
+	"StoreOrder": {
+		"@":           "StoreOrder is an order placed in the store.",
+		"id":          "id is the ID of the order. IDs are unique per user.",
+		"user":        "user is the ID of the user that placed the order.",
+		"status":      "status is the status of the order.",
+		"placed_ts":   "placed_ts is the unix timestamp of when the order was placed.",
+		"currency":    "currency is the currency of the amounts of the order.",
+		"total_cents": "total_cents is the total amount of the order (including shipping), in cents.",
+		"pay_type":    "pay_type is how the order is paid.",
+		"items":       "items are the ordered items.",
+	},
+	"ListOrdersRequest": {
+		"@":      "ListOrdersRequest is the request to list the orders placed in the store.",
+		"status": "status, if set, only lists the orders in this status.",
+	},
+	"ListOrdersResponse": {
+		"@":      "ListOrdersResponse is the list of orders placed in the store.",
+		"orders": "orders are the orders, sorted by the time they were placed.",
+	},
+	"UpdateOrderStatusRequest": {
+		"@":      "UpdateOrderStatusRequest is the request to change the status of an order.",
+		"user":   "user is the ID of the user that placed the order.",
+		"id":     "id is the ID of the order.",
+		"status": "status is the new status of the order.",
+	},
+	"UpdateOrderStatusResponse": {
+		"@": "UpdateOrderStatusResponse is the response to an UpdateOrderStatus call.",
+	},
+	"SalesTotalsRequest": {
+		"@":     "SalesTotalsRequest is the request for the totals of the sales of the store.",
+		"since": "since, if set, only counts the orders placed after this unix timestamp.",
+	},
+	"SalesTotal": {
+		"@":           "SalesTotal is the total of sales in a currency.",
+		"currency":    "currency is the currency of the sales.",
+		"total_cents": "total_cents is the total amount of sales, in cents.",
+	},
+	"SalesTotalsResponse": {
+		"@":      "SalesTotalsResponse is the totals of the sales of the store.",
+		"count":  "count is the number of orders counted as sales.",
+		"totals": "totals are the totals in each currency, sorted by currency.",
+	},
+	"StoreEventsRequest": {
+		"@":            "StoreEventsRequest is the request to start a stream of store events.",
+		"unacked_from": "unacked_from specifies to the server the sequence_id of the last received store event. Events received by the server that have a higher sequence_id will be streamed back to the client.",
+	},
+	"StoreEvent": {
+		"@":                "StoreEvent is an activity event of the store.",
+		"sequence_id":      "sequence_id is an opaque sequential ID.",
+		"type":             "type is the type of the event: cart.updated, order.placed, order.paid or order.status.",
+		"timestamp":        "timestamp is the unix timestamp of when the event was generated.",
+		"user":             "user is the ID of the user whose cart or order is the subject of the event.",
+		"cart_items":       "cart_items are the items of the updated cart, in cart.updated events. An empty list means the cart was cleared.",
+		"cart_total_cents": "cart_total_cents is the total amount of the updated cart, in cents.",
+		"order":            "order is the order, in order events.",
+		"old_status":       "old_status is the status of the order before it changed, in order.paid and order.status events.",
+	},
 	"RMPrivateMessage": {
 		"@":       "RMPrivateMessage is the network-level routed private message.",
 		"message": "message is the private message payload.",
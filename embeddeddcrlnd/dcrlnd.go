@@ -62,6 +62,15 @@ type Config struct {
 
 	// SyncFreeList sets the SyncFreeList flag in the DB.
 	SyncFreeList bool
+
+	// WatchtowerClient enables the watchtower client, which backs up
+	// revoked channel states to watchtowers registered through the
+	// wtclient RPC service.
+	WatchtowerClient bool
+
+	// AcceptKeysend enables receiving spontaneous (keysend) payments,
+	// such as tips sent without first requesting an invoice.
+	AcceptKeysend bool
 }
 
 // Dcrlnd is a running instance of an embedded dcrlnd instance.
@@ -363,7 +372,8 @@ func RunDcrlnd(ctx context.Context, cfg Config) (*Dcrlnd, error) {
 	conf.DB.Bolt.SyncFreelist = cfg.SyncFreeList
 	conf.DebugLevel = cfg.DebugLevel
 	conf.ProtocolOptions = &lncfg.ProtocolOptions{}
-	conf.WtClient = &lncfg.WtClient{}
+	conf.WtClient = &lncfg.WtClient{Active: cfg.WatchtowerClient}
+	conf.AcceptKeySend = cfg.AcceptKeysend
 	conf.SubRPCServers.WalletKitRPC = &walletrpc.Config{}
 	conf.SubRPCServers.AutopilotRPC = &autopilotrpc.Config{}
 	conf.SubRPCServers.ChainRPC = &chainrpc.Config{}
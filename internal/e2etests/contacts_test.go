@@ -0,0 +1,88 @@
+package e2etests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// TestMergeDuplicateContacts tests detecting and merging duplicate contacts.
+func TestMergeDuplicateContacts(t *testing.T) {
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice", withLogMsgs())
+	bob := ts.newClient("bob")
+
+	// Bob's second client has a new identity with the same nick.
+	bob2Cfg := ts.defaultNewClientCfg("bob2")
+	bob2ID, err := zkidentity.New("bob", "bob")
+	assert.NilErr(t, err)
+	bob2Cfg.id = bob2ID
+	bob2Cfg.idIniter = func(context.Context) (*zkidentity.FullIdentity, error) {
+		c := new(zkidentity.FullIdentity)
+		*c = *bob2ID
+		return c, nil
+	}
+	bob2 := ts.newClientWithCfg(bob2Cfg)
+	charlie := ts.newClient("charlie")
+
+	ts.kxUsers(alice, bob)
+	ts.kxUsers(alice, bob2)
+	ts.kxUsers(alice, charlie)
+	assertClientsCanPM(t, alice, bob)
+	assertClientsCanPM(t, alice, bob2)
+
+	// Alice sees Bob's entries as duplicates.
+	dups := alice.FindDuplicateContacts()
+	assert.DeepEqual(t, len(dups), 1)
+	assert.DeepEqual(t, dups[0].Reason, client.DuplicateSameName)
+	assert.DeepEqual(t, dups[0].UIDs, []client.UserID{bob.PublicID(), bob2.PublicID()})
+
+	// Alice administers a GC with the second identity.
+	gcID, err := alice.NewGroupChat("test gc")
+	assert.NilErr(t, err)
+	assertClientJoinsGC(t, gcID, alice, bob2)
+	assert.NilErr(t, alice.AddToGCBlockList(gcID, bob2.PublicID()))
+
+	// Alice merges the entries.
+	bob.acceptNextGCInvite(gcID)
+	err = alice.MergeContacts(bob.PublicID(), []client.UserID{bob2.PublicID()},
+		client.MergeContactsOpts{UpdateGCs: true, Remove: true})
+	assert.NilErr(t, err)
+
+	// The second entry was removed and its nick is an alias.
+	assert.DeepEqual(t, alice.UserExists(bob2.PublicID()), false)
+	merged, err := alice.MergedContacts(bob.PublicID())
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(merged), 1)
+	assert.DeepEqual(t, merged[0].UID, bob2.PublicID())
+	uid, err := alice.UIDByNick(merged[0].Nick)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, uid, bob.PublicID())
+	assert.DeepEqual(t, len(alice.FindDuplicateContacts()), 0)
+
+	// The history of both entries is in Bob's history.
+	history, _, err := alice.ReadUserHistoryMessages(bob.PublicID(), "", 100, 0)
+	assert.NilErr(t, err)
+	var fromBob, fromBob2 bool
+	for _, entry := range history {
+		fromBob = fromBob || entry.From == "bob"
+		fromBob2 = fromBob2 || entry.From == merged[0].Nick
+	}
+	if !fromBob || !fromBob2 {
+		t.Fatalf("history not merged: %v", history)
+	}
+
+	// Bob replaced the second identity in the GC and in its block list.
+	assertClientInGC(t, bob, gcID)
+	gc, err := alice.GetGC(gcID)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, gc.Members, []zkidentity.ShortID{alice.PublicID(), bob.PublicID()})
+	bl, err := alice.GetGCBlockList(gcID)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, bl.IsBlocked(bob.PublicID()), true)
+	assert.DeepEqual(t, bl.IsBlocked(bob2.PublicID()), false)
+}
@@ -31,6 +31,10 @@ type testScaffoldCfg struct {
 	skipNewServer bool
 	logScanner    io.Writer
 	rootDir       string
+
+	// maxPublishedResourceSize enables hosting published resources in
+	// the server.
+	maxPublishedResourceSize int
 }
 
 type testConn struct {
@@ -87,6 +91,28 @@ type clientCfg struct {
 	idIniter  func(context.Context) (*zkidentity.FullIdentity, error)
 	netDialer func(context.Context) (clientintf.Conn, *tls.ConnectionState, error)
 	pcIniter  func(loggerSubsysIniter) clientintf.PaymentClient
+
+	// logMsgs enables logging the messages (PMs and GC messages).
+	logMsgs bool
+
+	// tipUserKeysendFallback enables sending tips via keysend.
+	tipUserKeysendFallback bool
+
+	// liteSync enables the lite sync startup mode.
+	liteSync           bool
+	maxAutoFetchRMSize int
+
+	// maxBacklogFetchPerMinute throttles fetching the backlog during lite
+	// sync.
+	maxBacklogFetchPerMinute int
+
+	// ntfns, if set, is the notification manager of the client, so that
+	// handlers may be registered before the client starts running.
+	ntfns *client.NotificationManager
+
+	summarizer           client.Summarizer
+	summarizePostsMinLen int
+	summarizeGCMsgs      int
 }
 
 type newClientOpt func(*clientCfg)
@@ -97,6 +123,62 @@ func withPCIniter(pcIniter func(loggerSubsysIniter) clientintf.PaymentClient) ne
 	}
 }
 
+// withMockKeysendPayClient configures the client with a mock payment client
+// that supports keysend payments and enables sending tips via keysend.
+func withMockKeysendPayClient() newClientOpt {
+	return func(cfg *clientCfg) {
+		cfg.tipUserKeysendFallback = true
+		cfg.pcIniter = func(loggerSubsysIniter) clientintf.PaymentClient {
+			return &testutils.MockKeysendPayClient{
+				MockPayClient: &testutils.MockPayClient{},
+			}
+		}
+	}
+}
+
+// withLiteSync enables the lite sync startup mode with the given max size of
+// RMs automatically fetched.
+func withLiteSync(maxAutoFetchRMSize int) newClientOpt {
+	return func(cfg *clientCfg) {
+		cfg.liteSync = true
+		cfg.maxAutoFetchRMSize = maxAutoFetchRMSize
+	}
+}
+
+// withBacklogThrottle limits the number of deferred RMs fetched per minute
+// during lite sync.
+func withBacklogThrottle(maxPerMinute int) newClientOpt {
+	return func(cfg *clientCfg) {
+		cfg.maxBacklogFetchPerMinute = maxPerMinute
+	}
+}
+
+// withNotifications configures the client with the given notification
+// manager.
+func withNotifications(ntfns *client.NotificationManager) newClientOpt {
+	return func(cfg *clientCfg) {
+		cfg.ntfns = ntfns
+	}
+}
+
+// withLogMsgs enables logging the messages of the client.
+func withLogMsgs() newClientOpt {
+	return func(cfg *clientCfg) {
+		cfg.logMsgs = true
+	}
+}
+
+// withSummarizer configures the client with the given summarizer and enables
+// logging messages (so that GC backlogs can be summarized).
+func withSummarizer(s client.Summarizer, postsMinLen, gcMsgs int) newClientOpt {
+	return func(cfg *clientCfg) {
+		cfg.logMsgs = true
+		cfg.summarizer = s
+		cfg.summarizePostsMinLen = postsMinLen
+		cfg.summarizeGCMsgs = gcMsgs
+	}
+}
+
 func withSimnetEnvDcrlndPayClient(t testing.TB, alt bool) newClientOpt {
 	pcIniter := func(logBknd loggerSubsysIniter) clientintf.PaymentClient {
 		t.Helper()
@@ -132,6 +214,7 @@ type testClient struct {
 	cancel  func()
 	runC    chan error
 	mpc     *testutils.MockPayClient
+	kpc     *testutils.MockKeysendPayClient
 	nccfg   *clientCfg
 	cfg     *client.Config
 	log     slog.Logger
@@ -348,11 +431,18 @@ func (ts *testScaffold) newClientWithCfg(nccfg *clientCfg, opts ...newClientOpt)
 		Logger:        dbLog,
 		ChunkSize:     8,
 	}
+	if nccfg.logMsgs {
+		dbCfg.MsgsRoot = filepath.Join(rootDir, "logs")
+	}
 	db, err := clientdb.New(dbCfg)
 	assert.NilErr(ts.t, err)
 
 	pc := nccfg.pcIniter(logBknd)
 	mpc, _ := pc.(*testutils.MockPayClient)
+	kpc, _ := pc.(*testutils.MockKeysendPayClient)
+	if kpc != nil {
+		mpc = kpc.MockPayClient
+	}
 
 	cfg := client.Config{
 		ReconnectDelay: 500 * time.Millisecond,
@@ -370,6 +460,13 @@ func (ts *testScaffold) newClientWithCfg(nccfg *clientCfg, opts ...newClientOpt)
 		TipUserReRequestInvoiceDelay: time.Second,
 		TipUserMaxLifetime:           20 * time.Second,
 		TipUserPayRetryDelayFactor:   100 * time.Millisecond,
+		TipUserKeysendFallback:       nccfg.tipUserKeysendFallback,
+
+		LiteSync:           nccfg.liteSync,
+		MaxAutoFetchRMSize: nccfg.maxAutoFetchRMSize,
+		Notifications:      nccfg.ntfns,
+
+		MaxBacklogFetchPerMinute: nccfg.maxBacklogFetchPerMinute,
 
 		GCMQUpdtDelay:    100 * time.Millisecond,
 		GCMQMaxLifetime:  time.Second,
@@ -382,6 +479,10 @@ func (ts *testScaffold) newClientWithCfg(nccfg *clientCfg, opts ...newClientOpt)
 		AutoHandshakeInterval:       time.Second * 8,
 		AutoRemoveIdleUsersInterval: time.Second * 14,
 
+		Summarizer:             nccfg.summarizer,
+		SummarizePostsMinLen:   nccfg.summarizePostsMinLen,
+		SummarizeGCBacklogMsgs: nccfg.summarizeGCMsgs,
+
 		ResourcesProvider: resources.ProviderFunc(func(ctx context.Context,
 			uid clientintf.UserID,
 			request *rpc.RMFetchResource) (*rpc.RMFetchResourceReply, error) {
@@ -410,6 +511,7 @@ func (ts *testScaffold) newClientWithCfg(nccfg *clientCfg, opts ...newClientOpt)
 		runC:    make(chan error, 1),
 		db:      db,
 		mpc:     mpc,
+		kpc:     kpc,
 		cfg:     &cfg,
 		nccfg:   nccfg,
 		log:     logBknd("TEST"),
@@ -506,6 +608,7 @@ func (ts *testScaffold) newTestServer() {
 	cfg.InitSessTimeout = time.Second
 	cfg.DebugLevel = "debug"
 	cfg.LogStdOut = ts.tlb
+	cfg.MaxPublishedResourceSize = ts.cfg.maxPublishedResourceSize
 
 	s, err := server.NewServer(cfg)
 	if err != nil {
@@ -0,0 +1,52 @@
+package e2etests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/rpc"
+)
+
+// assertRemoteFeatures waits until c has received the features of target.
+func assertRemoteFeatures(t testing.TB, c, target *testClient) *rpc.RMFeatures {
+	t.Helper()
+	maxCheck := 1000
+	for i := 0; i < maxCheck; i++ {
+		feats, err := c.RemoteFeatures(target.PublicID())
+		assert.NilErr(t, err)
+		if feats != nil {
+			return feats
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timeout waiting for remote features")
+	return nil
+}
+
+// TestFeaturesExchange tests that clients exchange their supported features
+// after KX.
+func TestFeaturesExchange(t *testing.T) {
+	t.Parallel()
+
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob")
+	ts.kxUsers(alice, bob)
+	assertClientsCanPM(t, alice, bob)
+
+	// Both clients should learn about the features of the other one.
+	aliceFeats := assertRemoteFeatures(t, bob, alice)
+	bobFeats := assertRemoteFeatures(t, alice, bob)
+	assert.DeepEqual(t, aliceFeats.Version, rpc.RMFeaturesVersion)
+	assert.DeepEqual(t, bobFeats.Version, rpc.RMFeaturesVersion)
+	for _, cmd := range rpc.SupportedRMCommands() {
+		if !alice.UserSupportsCommand(bob.PublicID(), cmd) {
+			t.Fatalf("alice does not see bob supporting %q", cmd)
+		}
+		if !bob.UserSupportsCommand(alice.PublicID(), cmd) {
+			t.Fatalf("bob does not see alice supporting %q", cmd)
+		}
+	}
+}
@@ -790,6 +790,62 @@ func TestVersion1GCs(t *testing.T) {
 	assert.NonNilErr(t, err)
 }
 
+// TestReadOnlyGCs tests that only the admins and posters of read-only GCs may
+// send messages to them.
+func TestReadOnlyGCs(t *testing.T) {
+	t.Parallel()
+
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob")
+	charlie := ts.newClient("charlie")
+
+	ts.kxUsers(alice, bob)
+	ts.kxUsers(alice, charlie)
+	ts.kxUsers(bob, charlie)
+
+	gcID, err := alice.NewGroupChat("test gc")
+	assert.NilErr(t, err)
+	bob.acceptNextGCInvite(gcID)
+	assert.NilErr(t, alice.InviteToGroupChat(gcID, bob.PublicID()))
+	assertClientInGC(t, bob, gcID)
+	charlie.acceptNextGCInvite(gcID)
+	assert.NilErr(t, alice.InviteToGroupChat(gcID, charlie.PublicID()))
+	assertClientInGC(t, charlie, gcID)
+	assertClientSeesInGC(t, bob, gcID, charlie.PublicID())
+
+	// Alice makes the GC read-only, with Bob as poster.
+	readOnlyChan := make(chan bool, 1)
+	ntfnReg := charlie.handle(client.OnGCReadOnlyChangedNtfn(func(_ *client.RemoteUser, gc rpc.RMGroupList) {
+		readOnlyChan <- gc.ReadOnly
+	}))
+	err = alice.SetGCReadOnly(gcID, true, []zkidentity.ShortID{bob.PublicID()})
+	assert.NilErr(t, err)
+	assert.ChanWrittenWithVal(t, readOnlyChan, true)
+
+	// Charlie cannot post, while Alice (the admin) and Bob can.
+	err = charlie.GCMessage(gcID, "not allowed", 0, nil)
+	assert.ErrorIs(t, err, client.ErrGCReadOnly)
+	assertClientsCanSeeGCM(t, gcID, alice, bob, charlie)
+	assertClientsCanSeeGCM(t, gcID, bob, alice, charlie)
+
+	// Non-members cannot be posters.
+	err = alice.SetGCReadOnly(gcID, true, []zkidentity.ShortID{ts.newClient("dave").PublicID()})
+	assert.NonNilErr(t, err)
+
+	// Bob (not an admin) cannot change the GC.
+	err = bob.SetGCReadOnly(gcID, false, nil)
+	assert.NonNilErr(t, err)
+
+	// Alice makes the GC writable again. Everyone can post.
+	err = alice.SetGCReadOnly(gcID, false, nil)
+	assert.NilErr(t, err)
+	assert.ChanWrittenWithVal(t, readOnlyChan, false)
+	ntfnReg.Unregister()
+	assertClientsCanGCM(t, gcID, alice, bob, charlie)
+}
+
 // TestGCCrossedMediatedKX tests a scenario that could cause broken ratchets
 // when two users are added simultaneously to GCs where both will attempt
 // to KX with each other.
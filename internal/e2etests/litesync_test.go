@@ -0,0 +1,196 @@
+package e2etests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/rpc"
+	"golang.org/x/exp/slices"
+)
+
+// liteSyncTestMaxAutoFetch is the max size of RMs automatically fetched by
+// clients in lite sync tests.
+const liteSyncTestMaxAutoFetch = 256 * 1024
+
+// liteSyncNtfns returns a notification manager that writes the PMs and the
+// lite sync progress received by a client to the returned chans.
+func liteSyncNtfns() (*client.NotificationManager, chan string, chan client.SyncProgress) {
+	ntfns := client.NewNotificationManager()
+	pmChan := make(chan string, 10)
+	progressChan := make(chan client.SyncProgress, 10)
+	ntfns.RegisterSync(client.OnPMNtfn(func(_ *client.RemoteUser, pm rpc.RMPrivateMessage, _ time.Time) {
+		pmChan <- pm.Message
+	}))
+	ntfns.RegisterSync(client.OnSyncProgressNtfn(func(progress client.SyncProgress) {
+		progressChan <- progress
+	}))
+	return ntfns, pmChan, progressChan
+}
+
+// assertReceivesPMs asserts that exactly the given PMs are received, in any
+// order.
+func assertReceivesPMs(t testing.TB, pmChan chan string, msgs ...string) {
+	t.Helper()
+	var got []string
+	for range msgs {
+		got = append(got, assert.ChanWritten(t, pmChan))
+	}
+	slices.Sort(got)
+	want := slices.Clone(msgs)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Fatalf("unexpected PMs received (got %d, want %d)", len(got), len(want))
+	}
+	assert.ChanNotWritten(t, pmChan, time.Second)
+}
+
+// assertSyncProgress asserts that the sync progress notifications up to the
+// done stage are the given ones.
+func assertSyncProgress(t testing.TB, progressChan chan client.SyncProgress, want ...client.SyncProgress) {
+	t.Helper()
+	var got []client.SyncProgress
+	for {
+		progress := assert.ChanWritten(t, progressChan)
+		got = append(got, progress)
+		if progress.Stage == client.SyncStageDone {
+			break
+		}
+	}
+	assert.DeepEqual(t, got, want)
+}
+
+// sendLiteSyncTestPMs sends PMs from alice to the (offline) bob: a small one,
+// one larger than the size of RMs pushed during lite sync and one larger than
+// the max size of RMs automatically fetched.
+func sendLiteSyncTestPMs(t testing.TB, alice, bob *testClient) (small, large, huge string) {
+	t.Helper()
+	rnd := testRand(t)
+	small = "small message"
+	large = randomHex(rnd, 64*1024)
+	huge = randomHex(rnd, liteSyncTestMaxAutoFetch*3/2)
+	for _, msg := range []string{small, large, huge} {
+		assert.NilErr(t, alice.PM(bob.PublicID(), msg))
+	}
+	assertEmptyRMQ(t, alice)
+	return small, large, huge
+}
+
+// TestLiteSyncSkipsLargeRMs tests that, during lite sync, small RMs are
+// received first, larger RMs are fetched afterwards and RMs larger than the
+// max auto fetch size are skipped.
+func TestLiteSyncSkipsLargeRMs(t *testing.T) {
+	t.Parallel()
+
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob", withLiteSync(liteSyncTestMaxAutoFetch))
+	ts.kxUsers(alice, bob)
+
+	// Send the PMs while bob is offline, so that they are received during
+	// bob's lite sync.
+	ts.stopClient(bob)
+	small, large, _ := sendLiteSyncTestPMs(t, alice, bob)
+
+	ntfns, pmChan, progressChan := liteSyncNtfns()
+	ts.recreateStoppedClient(bob, withNotifications(ntfns))
+
+	// The huge PM is skipped: it is neither pushed nor fetched.
+	assertSyncProgress(t, progressChan,
+		client.SyncProgress{Stage: client.SyncStageRecent},
+		client.SyncProgress{Stage: client.SyncStageBacklog},
+		client.SyncProgress{Stage: client.SyncStageLarge, Total: 1},
+		client.SyncProgress{Stage: client.SyncStageLarge, Fetched: 1, Total: 1},
+		client.SyncProgress{Stage: client.SyncStageDone},
+	)
+	assertReceivesPMs(t, pmChan, small, large)
+}
+
+// TestLiteSyncResumes tests that RMs that were not fetched due to the client
+// stopping during lite sync are fetched on the next lite sync.
+func TestLiteSyncResumes(t *testing.T) {
+	t.Parallel()
+
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob", withLiteSync(liteSyncTestMaxAutoFetch))
+	ts.kxUsers(alice, bob)
+
+	ts.stopClient(bob)
+	small, large, _ := sendLiteSyncTestPMs(t, alice, bob)
+
+	// Interrupt the lite sync before the large PM is fetched.
+	ntfns, pmChan, _ := liteSyncNtfns()
+	interrupted, unblock := make(chan struct{}), make(chan struct{})
+	ntfns.RegisterSync(client.OnSyncProgressNtfn(func(progress client.SyncProgress) {
+		if progress.Stage == client.SyncStageLarge && progress.Fetched == 0 {
+			close(interrupted)
+			<-unblock
+		}
+	}))
+	bob = ts.recreateStoppedClient(bob, withNotifications(ntfns))
+	assert.ChanWritten(t, interrupted)
+	assertGoesOffline(t, bob)
+	close(unblock)
+	assertReceivesPMs(t, pmChan, small)
+	ts.stopClient(bob)
+
+	// The next lite sync fetches the large PM.
+	ntfns, pmChan, progressChan := liteSyncNtfns()
+	ts.recreateStoppedClient(bob, withNotifications(ntfns))
+	assertSyncProgress(t, progressChan,
+		client.SyncProgress{Stage: client.SyncStageRecent},
+		client.SyncProgress{Stage: client.SyncStageBacklog},
+		client.SyncProgress{Stage: client.SyncStageLarge, Total: 1},
+		client.SyncProgress{Stage: client.SyncStageLarge, Fetched: 1, Total: 1},
+		client.SyncProgress{Stage: client.SyncStageDone},
+	)
+	assertReceivesPMs(t, pmChan, large)
+}
+
+// TestLiteSyncBacklogThrottle tests that fetching the backlog during lite sync
+// is paused once the configured number of RMs per minute is fetched.
+func TestLiteSyncBacklogThrottle(t *testing.T) {
+	t.Parallel()
+
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	charlie := ts.newClient("charlie")
+	bob := ts.newClient("bob", withLiteSync(liteSyncTestMaxAutoFetch),
+		withBacklogThrottle(1))
+	ts.kxUsers(alice, bob)
+	ts.kxUsers(charlie, bob)
+
+	// Alice and Charlie send large PMs while bob is offline, so that they
+	// are fetched after the initial stage of bob's lite sync. Each PM is
+	// sent through a different ratchet, so both are deferred at once.
+	ts.stopClient(bob)
+	rnd := testRand(t)
+	for _, sender := range []*testClient{alice, charlie} {
+		assert.NilErr(t, sender.PM(bob.PublicID(), randomHex(rnd, 64*1024)))
+		assertEmptyRMQ(t, sender)
+	}
+
+	ntfns, pmChan, progressChan := liteSyncNtfns()
+	bob = ts.recreateStoppedClient(bob, withNotifications(ntfns))
+
+	// Only one of the PMs is fetched before fetching is throttled.
+	want := []client.SyncProgress{
+		{Stage: client.SyncStageRecent},
+		{Stage: client.SyncStageBacklog},
+		{Stage: client.SyncStageLarge, Total: 2},
+		{Stage: client.SyncStageLarge, Fetched: 1, Total: 2},
+		{Stage: client.SyncStageLarge, Fetched: 1, Total: 2, Throttled: true},
+	}
+	for _, progress := range want {
+		assert.DeepEqual(t, assert.ChanWritten(t, progressChan), progress)
+	}
+	assert.ChanNotWritten(t, progressChan, time.Second)
+	assert.ChanWritten(t, pmChan)
+	assert.ChanNotWritten(t, pmChan, time.Second)
+	assert.DeepEqual(t, bob.SyncProgress(), want[len(want)-1])
+}
@@ -4,11 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
 	"github.com/companyzero/bisonrelay/internal/assert"
 	"github.com/companyzero/bisonrelay/internal/testutils"
@@ -944,6 +946,97 @@ func TestTipUserRetriesRetriablePayFailure(t *testing.T) {
 	assert.ChanNotWritten(t, progressErrChan, resendDelay) // No more ntfns
 }
 
+// TestTipUserKeysendIgnoresLateInvoice tests that an invoice received while a
+// tip is being sent via keysend is not paid.
+func TestTipUserKeysendIgnoresLateInvoice(t *testing.T) {
+	t.Parallel()
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice", withMockKeysendPayClient())
+	bob := ts.newClient("bob")
+
+	ts.kxUsers(alice, bob)
+
+	const maxAttempts = 3
+	const bobNode = "bob's node"
+	payMAtoms := int64(4321000)
+
+	// Bob generates invoices that record his node on Alice, until he
+	// fails to generate them.
+	var mtx sync.Mutex
+	bobFailInvoices := false
+	bob.mpc.HookGetInvoice(func(amt int64, cb func(int64)) (string, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if bobFailInvoices {
+			return "", errors.New("unable to generate invoice")
+		}
+		return "custom invoice", nil
+	})
+	alice.mpc.HookDecodeInvoice(func(invoice string) (clientintf.DecodedInvoice, error) {
+		inv, err := alice.mpc.DefaultDecodeInvoice(invoice)
+		inv.MAtoms = payMAtoms
+		inv.DestNode = bobNode
+		return inv, err
+	})
+	payInvoiceChan := make(chan struct{}, 10)
+	alice.mpc.HookPayInvoice(func(string) (int64, error) {
+		payInvoiceChan <- struct{}{}
+		return 0, nil
+	})
+	keysendChan := make(chan string, 10)
+	keysendDoneChan := make(chan struct{})
+	alice.kpc.HookPayKeysend(func(node string, amt int64) (int64, error) {
+		keysendChan <- node
+		<-keysendDoneChan
+		return 0, nil
+	})
+	progressErrChan := make(chan error, 10)
+	alice.handle(client.OnTipAttemptProgressNtfn(func(ru *client.RemoteUser, amtMAtoms int64, completed bool, attempt int, attemptErr error, willRetry bool) {
+		if !willRetry {
+			progressErrChan <- attemptErr
+		}
+	}))
+
+	// Pay the first tip through an invoice, so that Alice learns Bob's
+	// node.
+	err := alice.TipUser(bob.PublicID(), float64(payMAtoms)/1e11, maxAttempts)
+	assert.NilErr(t, err)
+	assert.ChanWritten(t, payInvoiceChan)
+	assert.NilErrFromChan(t, progressErrChan)
+
+	// Send the second tip. Bob fails to generate the invoice, so Alice
+	// sends the tip via keysend on the next attempt.
+	mtx.Lock()
+	bobFailInvoices = true
+	mtx.Unlock()
+	err = alice.TipUser(bob.PublicID(), float64(payMAtoms)/1e11, maxAttempts)
+	assert.NilErr(t, err)
+	gotNode := assert.ChanWritten(t, keysendChan)
+	assert.DeepEqual(t, gotNode, bobNode)
+
+	// Bob sends an invoice for the tip while the keysend is in-flight.
+	// Alice does not pay it.
+	tas, err := alice.ListTipUserAttempts(bob.PublicID())
+	assert.NilErr(t, err)
+	var tag int32
+	for _, ta := range tas {
+		if ta.Completed == nil {
+			tag = ta.Tag
+		}
+	}
+	rm := rpc.RMInvoice{Tag: uint32(tag), Invoice: "late invoice"}
+	err = bob.testInterface().SendUserRM(alice.PublicID(), rm)
+	assert.NilErr(t, err)
+	assert.ChanNotWritten(t, payInvoiceChan, time.Second)
+
+	// The keysend completes the tip and the invoice is never paid.
+	close(keysendDoneChan)
+	assert.NilErrFromChan(t, progressErrChan)
+	assert.ChanNotWritten(t, payInvoiceChan, 2*time.Second)
+	assert.ChanNotWritten(t, keysendChan, time.Millisecond*100)
+}
+
 // TestRecvTipPersistsSuccess asserts that receiving tips are notified across
 // client restarts.
 func TestRecvTipPersistsSuccess(t *testing.T) {
@@ -1087,3 +1180,73 @@ func TestRecvTipExpired(t *testing.T) {
 	// Bob should not be attempting to track an expired invoice.
 	assert.ChanNotWritten(t, trackInvoiceChan, time.Second)
 }
+
+// TestTipLeaderboard asserts that tips received for posts are aggregated in
+// the tip leaderboard and that only tippers that consented to it are named in
+// the thank-you summary.
+func TestTipLeaderboard(t *testing.T) {
+	t.Parallel()
+
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob")
+	charlie := ts.newClient("charlie")
+	ts.kxUsers(alice, bob)
+	ts.kxUsers(charlie, bob)
+
+	startTime := time.Now()
+	assert.NilErr(t, bob.SetTipStatsConfig(clientdb.TipStatsConfig{Enabled: true}))
+	post, err := bob.CreatePost("Tipped post", "")
+	assert.NilErr(t, err)
+
+	bob.mpc.HookTrackInvoice(func(_ string, amt int64) (int64, error) {
+		return amt, nil
+	})
+	bobTipRecvChan := make(chan int64, 3)
+	bob.handle(client.OnTipReceivedNtfn(func(_ *client.RemoteUser, amt int64) {
+		bobTipRecvChan <- amt
+	}))
+
+	// Alice tips the post and consents to being named. Charlie tips Bob
+	// without consenting.
+	opts := client.TipOptions{Post: &post.ID, Public: true}
+	assert.NilErr(t, alice.TipUserWithOptions(bob.PublicID(), 0.2, 1, opts))
+	assert.ChanWrittenWithVal(t, bobTipRecvChan, int64(0.2*1e11))
+	assert.NilErr(t, charlie.TipUser(bob.PublicID(), 0.1, 1))
+	assert.ChanWrittenWithVal(t, bobTipRecvChan, int64(0.1*1e11))
+
+	lb, err := bob.TipLeaderboard(startTime, time.Time{})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, lb.Count, 2)
+	assert.DeepEqual(t, lb.MilliAtoms, int64(0.3*1e11))
+	assert.DeepEqual(t, len(lb.Tippers), 2)
+	assert.DeepEqual(t, lb.Tippers[0].UID, alice.PublicID())
+	assert.DeepEqual(t, lb.Tippers[0].Public, true)
+	assert.DeepEqual(t, lb.Tippers[1].UID, charlie.PublicID())
+	assert.DeepEqual(t, lb.Tippers[1].Public, false)
+	assert.DeepEqual(t, len(lb.Posts), 1)
+	assert.DeepEqual(t, lb.Posts[0].Post, post.ID)
+	assert.DeepEqual(t, lb.Posts[0].Title, "Tipped post")
+
+	// Only Alice is named in the summary.
+	summary := lb.ThankYouSummary(true)
+	if !strings.Contains(summary, "1. alice - 0.2 DCR") {
+		t.Fatalf("summary does not name alice: %s", summary)
+	}
+	if strings.Contains(summary, "charlie") {
+		t.Fatalf("summary names charlie: %s", summary)
+	}
+	summary = lb.ThankYouSummary(false)
+	if strings.Contains(summary, "alice") {
+		t.Fatalf("anonymized summary names alice: %s", summary)
+	}
+
+	// Tips are not recorded after disabling the stats.
+	assert.NilErr(t, bob.SetTipStatsConfig(clientdb.TipStatsConfig{}))
+	assert.NilErr(t, charlie.TipUser(bob.PublicID(), 0.1, 1))
+	assert.ChanWritten(t, bobTipRecvChan)
+	lb, err = bob.TipLeaderboard(startTime, time.Time{})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, lb.Count, 2)
+}
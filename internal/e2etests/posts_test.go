@@ -1,14 +1,20 @@
 package e2etests
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/companyzero/bisonrelay/client"
 	"github.com/companyzero/bisonrelay/client/clientdb"
 	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/postarchive"
 	"github.com/companyzero/bisonrelay/internal/assert"
 	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
 )
 
 func TestBasicPostFeatures(t *testing.T) {
@@ -110,6 +116,122 @@ func TestBasicPostFeatures(t *testing.T) {
 	assert.ErrorIs(t, err, client.ErrKXSearchNeeded{})
 }
 
+// TestExportImportPosts tests exporting the posts of a user to an archive and
+// importing them as posts of another user.
+func TestExportImportPosts(t *testing.T) {
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob")
+	charlie := ts.newClient("charlie")
+
+	charlieRecvPosts := make(chan rpc.PostMetadata, 2)
+	charlie.handle(client.OnPostRcvdNtfn(func(ru *client.RemoteUser, summary clientdb.PostSummary, pm rpc.PostMetadata) {
+		charlieRecvPosts <- pm
+	}))
+	charlieSubChanged := make(chan bool, 1)
+	charlie.handle(client.OnRemoteSubscriptionChangedNtfn(func(user *client.RemoteUser, subscribed bool) {
+		charlieSubChanged <- subscribed
+	}))
+
+	ts.kxUsers(alice, bob)
+	ts.kxUsers(bob, charlie)
+
+	// Alice creates two posts and Bob comments on one of them.
+	post1, err := alice.CreatePost("first post", "")
+	assert.NilErr(t, err)
+	_, err = alice.CreatePost("second post", "second descr")
+	assert.NilErr(t, err)
+	assert.NilErr(t, alice.CommentPost(alice.PublicID(), post1.ID, "alice comment", nil))
+
+	// Alice exports her posts.
+	var archive bytes.Buffer
+	n, err := alice.ExportPosts(&archive)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, n, 2)
+	a, err := postarchive.Read(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	assert.NilErr(t, err)
+	var comments []string
+	for _, p := range a.Posts {
+		for _, c := range p.Comments {
+			comments = append(comments, c.Comment)
+		}
+	}
+	assert.DeepEqual(t, comments, []string{"alice comment"})
+
+	// Charlie subscribes to Bob's posts.
+	assert.NilErr(t, charlie.SubscribeToPosts(bob.PublicID()))
+	assert.ChanWrittenWithVal(t, charlieSubChanged, true)
+
+	// Bob imports the posts. Charlie receives the republished posts.
+	summs, err := bob.ImportPosts(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(summs), 2)
+	gotBodies := make(map[string]string, 2)
+	for i := 0; i < 2; i++ {
+		pm := assert.ChanWritten(t, charlieRecvPosts)
+		assert.DeepEqual(t, pm.Attributes[rpc.RMPStatusFrom], bob.PublicID().String())
+		gotBodies[pm.Attributes[rpc.RMPMain]] = pm.Attributes[rpc.RMPDescription]
+	}
+	assert.DeepEqual(t, gotBodies, map[string]string{
+		"first post":  "",
+		"second post": "second descr",
+	})
+}
+
+// TestAnnouncePostsInGC tests that new posts are announced in the configured
+// GCs, skipping the members already subscribed to the posts and respecting the
+// min interval between announcements.
+func TestAnnouncePostsInGC(t *testing.T) {
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob")
+	charlie := ts.newClient("charlie")
+
+	ts.kxUsers(alice, bob)
+	ts.kxUsers(alice, charlie)
+	gcID, err := alice.NewGroupChat("test gc")
+	assert.NilErr(t, err)
+	assertClientJoinsGC(t, gcID, alice, bob)
+	assertClientJoinsGC(t, gcID, alice, charlie)
+
+	bobGCMs := make(chan string, 2)
+	bob.handle(client.OnGCMNtfn(func(_ *client.RemoteUser, msg rpc.RMGroupMessage, _ time.Time) {
+		bobGCMs <- msg.Message
+	}))
+	charlieGCMs := make(chan string, 2)
+	charlie.handle(client.OnGCMNtfn(func(_ *client.RemoteUser, msg rpc.RMGroupMessage, _ time.Time) {
+		charlieGCMs <- msg.Message
+	}))
+
+	// Charlie subscribes to Alice's posts, so Charlie does not need the
+	// announcements.
+	assertSubscribeToPosts(t, alice, charlie)
+
+	// Alice configures the GC to announce her posts and publishes one.
+	assert.NilErr(t, alice.SetPostAnnounceGCs([]zkidentity.ShortID{gcID}))
+	gcs, err := alice.PostAnnounceGCs()
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, gcs, []zkidentity.ShortID{gcID})
+	post, err := alice.CreatePost("Post title\nThe body of the post", "")
+	assert.NilErr(t, err)
+
+	// Bob receives the announcement, Charlie does not.
+	msg := assert.ChanWritten(t, bobGCMs)
+	if !strings.Contains(msg, "Post title") || !strings.Contains(msg, post.ID.String()) ||
+		!strings.Contains(msg, "The body of the post") {
+		t.Fatalf("unexpected announcement: %q", msg)
+	}
+	assert.ChanNotWritten(t, charlieGCMs, 250*time.Millisecond)
+
+	// A new post is not announced before the min interval between
+	// announcements elapses.
+	_, err = alice.CreatePost("second post", "")
+	assert.NilErr(t, err)
+	assert.ChanNotWritten(t, bobGCMs, 250*time.Millisecond)
+}
+
 // TestKXSearchFromPosts tests the KX search feature from posts.
 //
 // The test plan is the following: create a chain of 5 KXd users (A-E). Create
@@ -185,3 +307,72 @@ func TestKXSearchFromPosts(t *testing.T) {
 	assert.NilErr(t, bob.CommentPost(alice.PublicID(), alicePostID, bobComment, nil))
 	assert.ChanWrittenWithVal(t, eveRcvdStatus, bobComment)
 }
+
+// testSummarizer is a client.Summarizer that summarizes content as its title
+// and length.
+type testSummarizer struct {
+	reqs chan client.SummaryRequest
+}
+
+func (s *testSummarizer) Name() string { return "test" }
+
+func (s *testSummarizer) Summarize(ctx context.Context, req client.SummaryRequest) (string, error) {
+	s.reqs <- req
+	return fmt.Sprintf("%s of %d bytes", req.Title, len(req.Content)), nil
+}
+
+// TestSummaries tests that long posts and busy GC backlogs are automatically
+// summarized.
+func TestSummaries(t *testing.T) {
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	summarizer := &testSummarizer{reqs: make(chan client.SummaryRequest, 5)}
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob", withSummarizer(summarizer, 100, 3))
+	ts.kxUsers(alice, bob)
+
+	bobSummaries := make(chan client.ContentSummaryEvent, 5)
+	bob.handle(client.OnContentSummarizedNtfn(func(ev client.ContentSummaryEvent) {
+		bobSummaries <- ev
+	}))
+	assertSubscribeToPosts(t, alice, bob)
+
+	// A short post is not summarized.
+	_, err := alice.CreatePost("short post", "")
+	assert.NilErr(t, err)
+	assert.ChanNotWritten(t, summarizer.reqs, 250*time.Millisecond)
+
+	// A long post is summarized.
+	longPost := "Long post\n" + strings.Repeat("lorem ipsum ", 20)
+	post, err := alice.CreatePost(longPost, "")
+	assert.NilErr(t, err)
+	req := assert.ChanWritten(t, summarizer.reqs)
+	assert.DeepEqual(t, req.Kind, client.SummaryKindPost)
+	assert.DeepEqual(t, req.Content, longPost)
+	ev := assert.ChanWritten(t, bobSummaries)
+	assert.DeepEqual(t, ev.Post, post.ID)
+	wantSumm := fmt.Sprintf("Long post of %d bytes", len(longPost))
+	assert.DeepEqual(t, ev.Summary.Summary, wantSumm)
+	summ, err := bob.PostSummary(alice.PublicID(), post.ID)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, summ.Summary, wantSumm)
+	assert.DeepEqual(t, summ.Summarizer, "test")
+
+	// Bob's backlog of the GC is summarized after 3 messages.
+	gcID, err := alice.NewGroupChat("test gc")
+	assert.NilErr(t, err)
+	assertClientJoinsGC(t, gcID, alice, bob)
+	for i := 0; i < 3; i++ {
+		assert.NilErr(t, alice.GCMessage(gcID, fmt.Sprintf("msg %d", i),
+			rpc.MessageModeNormal, nil))
+	}
+	req = assert.ChanWritten(t, summarizer.reqs)
+	assert.DeepEqual(t, req.Kind, client.SummaryKindGCBacklog)
+	assert.DeepEqual(t, strings.Count(req.Content, "\n"), 3)
+	ev = assert.ChanWritten(t, bobSummaries)
+	assert.DeepEqual(t, ev.GC, gcID)
+	assert.DeepEqual(t, ev.Messages, 3)
+	gcSumm, err := bob.GCBacklogSummary(gcID)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, gcSumm.Summary, ev.Summary.Summary)
+}
@@ -1,11 +1,13 @@
 package e2etests
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/companyzero/bisonrelay/client"
 	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
 	"github.com/companyzero/bisonrelay/client/resources"
 	"github.com/companyzero/bisonrelay/internal/assert"
 	"github.com/companyzero/bisonrelay/rpc"
@@ -55,3 +57,79 @@ func TestFetchesFixedResource(t *testing.T) {
 	// Bob does not receive a reply.
 	assert.ChanNotWritten(t, chanResReply, time.Second)
 }
+
+// TestGuestView tests that a user that did not KX with the creator of a guest
+// view can fetch its pages using the guest view token.
+func TestGuestView(t *testing.T) {
+	tcfg := testScaffoldCfg{maxPublishedResourceSize: 1 << 16}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice")
+	bob := ts.newClient("bob")
+
+	// Setup Alice's resources. The index links to a product page and an
+	// admin page, which is not published.
+	indexData := []byte("# Store\n\n[Product](/product/1)\n[Admin](/admin)\n")
+	productData := []byte("# Product 1\n")
+	adminData := []byte("# Admin\n")
+	alice.modifyHandlers(func() {
+		r := resources.NewRouter()
+		r.BindExactPath([]string{"index.md"}, &resources.StaticResource{Data: indexData})
+		r.BindExactPath([]string{"product", "1"}, &resources.StaticResource{Data: productData})
+		r.BindExactPath([]string{"admin"}, &resources.StaticResource{Data: adminData})
+		alice.resourcesProvider = r
+	})
+
+	// Alice creates the guest view (once connected to the server).
+	var token *clientintf.GuestViewToken
+	var gv *clientdb.GuestView
+	var err error
+	for i := 0; i < 100; i++ {
+		token, gv, err = alice.CreateGuestView(ts.ctx, time.Hour, nil)
+		if err == nil || !strings.Contains(err.Error(), "not connected") {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(gv.Pages), 2)
+
+	// Bob decodes the token and fetches the pages without KXing with
+	// Alice.
+	enc, err := token.Encode()
+	assert.NilErr(t, err)
+	bobToken, err := clientintf.DecodeGuestViewToken(enc)
+	assert.NilErr(t, err)
+	var data []byte
+	for i := 0; i < 100; i++ {
+		data, err = bob.FetchGuestView(ts.ctx, bobToken, nil)
+		if err == nil || !strings.Contains(err.Error(), "not connected") {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, data, indexData)
+	data, err = bob.FetchGuestView(ts.ctx, bobToken, []string{"product", "1"})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, data, productData)
+	_, err = bob.FetchGuestView(ts.ctx, bobToken, []string{"admin"})
+	assert.NonNilErr(t, err)
+
+	// A token with a different key cannot decrypt the pages.
+	badToken := *bobToken
+	badToken.Key = new([32]byte)
+	_, err = bob.FetchGuestView(ts.ctx, &badToken, nil)
+	assert.NonNilErr(t, err)
+
+	// The server only accepts changes to published resources with newer
+	// timestamps, so wait before revoking the view.
+	time.Sleep(time.Second)
+
+	// After Alice revokes the view, Bob cannot fetch its pages anymore.
+	assert.NilErr(t, alice.RevokeGuestView(ts.ctx, token.ID))
+	_, err = bob.FetchGuestView(ts.ctx, bobToken, nil)
+	assert.NonNilErr(t, err)
+	views, err := alice.ListGuestViews()
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(views), 0)
+}
@@ -0,0 +1,1247 @@
+package e2etests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/companyzero/bisonrelay/client"
+	"github.com/companyzero/bisonrelay/client/clientdb"
+	"github.com/companyzero/bisonrelay/client/clientintf"
+	"github.com/companyzero/bisonrelay/client/resources"
+
+	"github.com/companyzero/bisonrelay/client/resources/simplestore"
+	"github.com/companyzero/bisonrelay/client/resources/simplestore/storetest"
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/internal/mdembeds"
+	"github.com/companyzero/bisonrelay/internal/testutils"
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrlnd/lnrpc"
+)
+
+// assertStoreReplyContains asserts the reply of the store contains the given
+// text.
+func assertStoreReplyContains(t testing.TB, reply, want string) {
+	t.Helper()
+	if !strings.Contains(reply, want) {
+		t.Fatalf("reply does not contain %q: %s", want, reply)
+	}
+}
+
+// TestSimpleStoreOrderFlow tests the flow of an order in a simple store, from
+// browsing the store to the completion of the paid order.
+func TestSimpleStoreOrderFlow(t *testing.T) {
+	t.Parallel()
+
+	h := storetest.New(t, simplestore.Config{})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	// Bob browses the store.
+	index := h.FetchPage(bob, "index.md", nil)
+	assertStoreReplyContains(t, index, "Test Book")
+	prod := h.FetchPage(bob, "product/book01", nil)
+	assertStoreReplyContains(t, prod, "Test Book")
+
+	// Bob adds the product to the cart.
+	h.AddToCart(bob, "book01", 2)
+	cart := h.FetchPage(bob, "cart", nil)
+	assertStoreReplyContains(t, cart, "Test Book")
+
+	// Bob places the order, which is quoted at the harness rate.
+	order := h.PlaceOrder(bob)
+	assert.DeepEqual(t, order.Status, simplestore.StatusPlaced)
+	assert.DeepEqual(t, order.ExchangeRate, storetest.DefaultRate)
+	wantTotal, _ := dcrutil.NewAmount(20 / storetest.DefaultRate)
+	assert.DeepEqual(t, order.TotalDCR(), wantTotal)
+	if order.Invoice == "" {
+		t.Fatalf("order has no invoice")
+	}
+
+	// The cart is cleared after the order is placed.
+	cart = h.FetchPage(bob, "cart", nil)
+	if strings.Contains(cart, "Test Book") {
+		t.Fatalf("cart was not cleared after placing the order")
+	}
+
+	// Bob pays the order. The store detects the payment and notifies Bob.
+	h.PayOrder(order)
+	paid := h.WaitOrderStatus(bob, order.ID, simplestore.StatusPaid)
+	assert.DeepEqual(t, paid.PaidAmount, wantTotal)
+	assertStoreReplyContains(t, h.WaitPM(bob), "identified as paid")
+
+	// Bob sees the order as paid.
+	status := h.FetchPage(bob, "order/"+order.ID.String(), nil)
+	assertStoreReplyContains(t, status, string(simplestore.StatusPaid))
+
+	// The admin ships and completes the order. Invalid transitions are
+	// rejected.
+	err := h.Store.UpdateOrderStatus(bob, order.ID, simplestore.StatusPlaced)
+	assert.NonNilErr(t, err)
+	err = h.Store.UpdateOrderStatus(bob, order.ID, simplestore.StatusShipped)
+	assert.NilErr(t, err)
+	h.WaitOrderStatus(bob, order.ID, simplestore.StatusShipped)
+	err = h.Store.UpdateOrderStatus(bob, order.ID, simplestore.StatusCompleted)
+	assert.NilErr(t, err)
+	h.WaitOrderStatus(bob, order.ID, simplestore.StatusCompleted)
+	err = h.Store.UpdateOrderStatus(bob, order.ID, simplestore.StatusCanceled)
+	assert.NonNilErr(t, err)
+}
+
+// TestSimpleStoreCancelUnpaidOrder tests that unpaid orders may be canceled by
+// the buyer and are not marked as paid afterwards.
+func TestSimpleStoreCancelUnpaidOrder(t *testing.T) {
+	t.Parallel()
+
+	h := storetest.New(t, simplestore.Config{})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	h.AddToCart(bob, "book01", 1)
+	order := h.PlaceOrder(bob)
+	h.FetchPage(bob, "order/"+order.ID.String()+"/cancel", nil)
+	h.WaitOrderStatus(bob, order.ID, simplestore.StatusCanceled)
+
+	// Paying the invoice of the canceled order does not change its status.
+	h.PayOrder(order)
+	order = h.WaitOrderStatus(bob, order.ID, simplestore.StatusCanceled)
+	assert.DeepEqual(t, order.PaidAmount, dcrutil.Amount(0))
+}
+
+// TestSimpleStorePayOnChain tests that switching an order to on-chain payment
+// cancels its LN invoice, so that it can't be paid twice.
+func TestSimpleStorePayOnChain(t *testing.T) {
+	t.Parallel()
+
+	h := storetest.New(t, simplestore.Config{})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	h.AddToCart(bob, "book01", 1)
+	order := h.PlaceOrder(bob)
+	lnInvoice := order.Invoice
+	reply := h.FetchPage(bob, "orderpayonchain/"+order.ID.String(), nil)
+	assertStoreReplyContains(t, reply, "On-Chain Payment")
+
+	// The order is now waiting for the on-chain payment.
+	switched := h.Order(bob, order.ID)
+	assert.DeepEqual(t, switched.PayType, simplestore.PayTypeOnChain)
+	if switched.Invoice == lnInvoice {
+		t.Fatalf("order still has the LN invoice")
+	}
+
+	// The LN invoice can no longer be paid.
+	assert.NonNilErr(t, h.LN.PayInvoice(lnInvoice))
+	time.Sleep(100 * time.Millisecond)
+	order = h.Order(bob, order.ID)
+	assert.DeepEqual(t, order.Status, simplestore.StatusPlaced)
+	assert.DeepEqual(t, order.PaidAmount, dcrutil.Amount(0))
+
+	// The order can't be switched again.
+	res := h.Fetch(bob, "orderpayonchain/"+order.ID.String(), nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+}
+
+// TestSimpleStoreQuoteExpiry tests that the receipt of an expired order embeds
+// the validity of its quote, and that fetching its refresh path requotes the
+// order.
+func TestSimpleStoreQuoteExpiry(t *testing.T) {
+	t.Parallel()
+
+	h := storetest.New(t, simplestore.Config{QuoteValidity: 500 * time.Millisecond})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	h.AddToCart(bob, "book01", 1)
+	order := h.PlaceOrder(bob)
+	h.WaitOrderStatus(bob, order.ID, simplestore.StatusExpired)
+
+	// The receipt of the expired order embeds its expired quote.
+	var quote *mdembeds.EmbeddedArgs
+	mdembeds.ReplaceEmbeds(h.WaitPM(bob), func(args mdembeds.EmbeddedArgs) string {
+		quote = &args
+		return ""
+	})
+	if quote == nil {
+		t.Fatalf("receipt does not embed the quote of the order")
+	}
+	assert.DeepEqual(t, quote.ExpiresTS, order.ExpiresTS.Unix())
+	assert.BoolIs(t, quote.QuoteExpired(time.Now()), true)
+
+	// Refreshing the quote places the order again, with a new quote.
+	page := h.FetchPage(bob, quote.Refresh, nil)
+	assertStoreReplyContains(t, page, "Order Requoted")
+	assertStoreReplyContains(t, page, "--embed[")
+	requoted := h.WaitOrderStatus(bob, order.ID, simplestore.StatusPlaced)
+	if !requoted.ExpiresTS.After(order.ExpiresTS) {
+		t.Fatalf("requoted order did not get a new quote")
+	}
+}
+
+// TestSimpleStoreExperiments tests that users are served a stable variant of
+// the template of an experiment and that the orders they place are attributed
+// to the variant.
+func TestSimpleStoreExperiments(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("").Parse(`{{ define "index_b.tmpl" }}Variant B{{ end }}`))
+	h := storetest.New(t, simplestore.Config{
+		RenderEngine: resources.NewTextTemplateEngine(tmpl),
+		Experiments: []simplestore.Experiment{{
+			Name:     "index-layout",
+			Template: "index.tmpl",
+			Variants: []string{"index.tmpl", "index_b.tmpl"},
+		}},
+	})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+
+	// Users are split between the variants. Each user is always served
+	// the same variant.
+	const nbUsers = 20
+	var usersB []clientintf.UserID
+	for i := 0; i < nbUsers; i++ {
+		uid := h.Client.AddUser("user" + string(rune('a'+i)))
+		isB := strings.Contains(h.FetchPage(uid, "index.md", nil), "Variant B")
+		for j := 0; j < 3; j++ {
+			again := strings.Contains(h.FetchPage(uid, "index.md", nil), "Variant B")
+			assert.BoolIs(t, again, isB)
+		}
+		if isB {
+			usersB = append(usersB, uid)
+		}
+	}
+	if len(usersB) == 0 || len(usersB) == nbUsers {
+		t.Fatalf("all users were served the same variant")
+	}
+
+	// A user served the second variant places and pays an order.
+	buyer := usersB[0]
+	h.AddToCart(buyer, "book01", 1)
+	order := h.PlaceOrder(buyer)
+	assert.DeepEqual(t, order.Experiments, map[string]string{"index-layout": "index_b.tmpl"})
+	h.PayOrder(order)
+	h.WaitOrderStatus(buyer, order.ID, simplestore.StatusPaid)
+
+	stats, err := h.Store.ExperimentStats()
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(stats), 1)
+	variants := stats[0].Variants
+	assert.DeepEqual(t, variants[0].Viewers, nbUsers-len(usersB))
+	assert.DeepEqual(t, variants[0].Orders, 0)
+	assert.DeepEqual(t, variants[1].Viewers, len(usersB))
+	assert.DeepEqual(t, variants[1].Orders, 1)
+	assert.DeepEqual(t, variants[1].Sales, 1)
+	assert.DeepEqual(t, variants[1].Conversion(), 1/float64(len(usersB)))
+
+	// The admin sees the stats of the experiment.
+	page := h.FetchPage(h.Client.PublicID(), "admin/experiments", nil)
+	assertStoreReplyContains(t, page, "index_b.tmpl: ")
+	assertStoreReplyContains(t, page, "1 paid")
+}
+
+// TestSimpleStoreSavedQuery tests running saved queries over the orders of the
+// store.
+func TestSimpleStoreSavedQuery(t *testing.T) {
+	t.Parallel()
+
+	h := storetest.New(t, simplestore.Config{})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "tv01",
+		Title: "Test TV",
+		Price: simplestore.MoneyFromFloat(200),
+	})
+	bob := h.Client.AddUser("bob")
+
+	h.AddToCart(bob, "book01", 1)
+	small := h.PlaceOrder(bob)
+	h.AddToCart(bob, "tv01", 1)
+	big := h.PlaceOrder(bob)
+	h.PayOrder(small)
+	h.WaitOrderStatus(bob, small.ID, simplestore.StatusPaid)
+
+	// Invalid queries are rejected.
+	assert.NonNilErr(t, h.Store.ValidateQuery([]string{"mintotal=abc"}))
+	assert.NonNilErr(t, h.Store.ValidateQuery([]string{"days=x"}))
+	assert.NilErr(t, h.Store.ValidateQuery([]string{"status=placed", "mintotal=100", "days=30"}))
+
+	// Only the unpaid order over $100 is found.
+	res, err := h.Store.RunQuery([]string{"status=placed,confirmed", "mintotal=100", "days=30"})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(res), 1)
+	assert.DeepEqual(t, res[0].Source, "order "+big.ID.String())
+
+	res, err = h.Store.RunQuery([]string{"days=30"})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(res), 2)
+
+	// Totals are only compared to orders in the same currency.
+	res, err = h.Store.RunQuery([]string{"currency=eur", "mintotal=1"})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(res), 0)
+	res, err = h.Store.RunQuery([]string{"currency=usd", "mintotal=1"})
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(res), 2)
+}
+
+// TestSimpleStoreSchemaMigration tests that documents saved with older schema
+// versions are migrated when the store starts and that the store refuses to
+// load data saved by a newer version.
+func TestSimpleStoreSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, dir := range []string{"products", "carts"} {
+		assert.NilErr(t, os.Mkdir(filepath.Join(root, dir), 0o700))
+	}
+
+	// A cart saved before schema versions and Money amounts.
+	var uid clientintf.UserID
+	uid[0] = 0x01
+	cartFname := filepath.Join(root, "carts", uid.String())
+	legacyCart := `{"items":[{"product":{"title":"Test Book","sku":"book01",` +
+		`"price":10},"quantity":2}],"discount_cents":150}`
+	assert.NilErr(t, os.WriteFile(cartFname, []byte(legacyCart), 0o600))
+
+	_, err := simplestore.New(simplestore.Config{Root: root})
+	assert.NilErr(t, err)
+
+	// The cart was saved again with the current schema.
+	data, err := os.ReadFile(cartFname)
+	assert.NilErr(t, err)
+	assertStoreReplyContains(t, string(data), `"schema_version":1`)
+	var cart simplestore.Cart
+	assert.NilErr(t, json.Unmarshal(data, &cart))
+	assert.DeepEqual(t, cart.Discount, simplestore.MoneyFromFloat(1.5))
+	assert.DeepEqual(t, cart.Total(), simplestore.MoneyFromFloat(18.5))
+
+	// Documents saved by a newer version are not decoded.
+	newer := `{"items":[],"schema_version":99}`
+	err = json.Unmarshal([]byte(newer), &cart)
+	assert.ErrorIs(t, err, simplestore.ErrNewerSchema)
+
+	// Stores saved by a newer version are not loaded.
+	schema := `{"carts":99,"orders":1,"products":1}`
+	assert.NilErr(t, os.WriteFile(filepath.Join(root, "schema.json"), []byte(schema), 0o600))
+	_, err = simplestore.New(simplestore.Config{Root: root})
+	assert.ErrorIs(t, err, simplestore.ErrNewerSchema)
+}
+
+// waitAdminPM waits for the next private message sent by the store to one of
+// the admins. Messages sent to other users are discarded.
+func waitAdminPM(t testing.TB, h *storetest.Harness, admins ...clientintf.UserID) storetest.PM {
+	t.Helper()
+	timeout := time.After(30 * time.Second)
+	for {
+		select {
+		case pm := <-h.Client.PMs():
+			for _, admin := range admins {
+				if pm.To == admin {
+					return pm
+				}
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for PM to admins")
+			return storetest.PM{}
+		}
+	}
+}
+
+// TestSimpleStoreAdminRouting tests that placed orders are assigned to the
+// admins of the store, that unacknowledged orders are reassigned without
+// notifying the same admin twice and that routing admins are limited to
+// viewing and acknowledging orders.
+func TestSimpleStoreAdminRouting(t *testing.T) {
+	t.Parallel()
+
+	c := storetest.NewClient()
+	alice := c.AddUser("alice")
+	carol := c.AddUser("carol")
+	h := storetest.NewWithClient(t, simplestore.Config{
+		AdminRouting: simplestore.AdminRouting{
+			Admins:     []clientintf.UserID{alice, carol},
+			AckTimeout: 200 * time.Millisecond,
+		},
+	}, c)
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	// The order is assigned to the first admin, then reassigned to the
+	// second one after it is not acknowledged. Each admin is notified once.
+	h.AddToCart(bob, "book01", 1)
+	order := h.PlaceOrder(bob)
+	pm := waitAdminPM(t, h, alice, carol)
+	assert.DeepEqual(t, pm.To, alice)
+	assertStoreReplyContains(t, pm.Msg, order.ID.String())
+	pm = waitAdminPM(t, h, alice, carol)
+	assert.DeepEqual(t, pm.To, carol)
+
+	// The order keeps being reassigned between the admins, but they are
+	// not notified again.
+	deadline := time.After(time.Second)
+	assignedTo := make(map[clientintf.UserID]bool)
+	for done := false; !done; {
+		select {
+		case pm := <-h.Client.PMs():
+			if pm.To == alice || pm.To == carol {
+				t.Fatalf("admin notified again: %s", pm.Msg)
+			}
+		case <-time.After(50 * time.Millisecond):
+			if admin := h.Order(bob, order.ID).AssignedAdmin; admin != nil {
+				assignedTo[*admin] = true
+			}
+		case <-deadline:
+			done = true
+		}
+	}
+	assert.DeepEqual(t, assignedTo[alice], true)
+	assert.DeepEqual(t, assignedTo[carol], true)
+	order = h.Order(bob, order.ID)
+	assert.DeepEqual(t, order.NotifiedAdmins, []clientintf.UserID{alice, carol})
+
+	// Routing admins may view the order, but not change it or access the
+	// rest of the admin section.
+	orderPath := "admin/order/" + bob.String() + "/" + order.ID.String()
+	assertStoreReplyContains(t, h.FetchPage(alice, orderPath, nil), order.ID.String())
+	for _, path := range []string{
+		"admin/orderstatusto/" + bob.String() + "/" + order.ID.String() + "/canceled",
+		"admin/orderrefund/" + bob.String() + "/" + order.ID.String(),
+		"admin/products",
+		"admin/customers",
+	} {
+		res := h.Fetch(alice, path, nil)
+		assert.DeepEqual(t, res.Status, rpc.ResourceStatusForbidden)
+	}
+}
+
+// TestSimpleStoreAdminAck tests that routing admins may only acknowledge the
+// orders assigned to them.
+func TestSimpleStoreAdminAck(t *testing.T) {
+	t.Parallel()
+
+	c := storetest.NewClient()
+	alice := c.AddUser("alice")
+	carol := c.AddUser("carol")
+	h := storetest.NewWithClient(t, simplestore.Config{
+		AdminRouting: simplestore.AdminRouting{
+			Admins: []clientintf.UserID{alice, carol},
+		},
+	}, c)
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	h.AddToCart(bob, "book01", 1)
+	order := h.PlaceOrder(bob)
+	assert.DeepEqual(t, waitAdminPM(t, h, alice, carol).To, alice)
+
+	// Carol was not assigned the order, so she can't acknowledge it.
+	ackPath := "admin/orderack/" + bob.String() + "/" + order.ID.String()
+	res := h.Fetch(carol, ackPath, nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusForbidden)
+	if h.Order(bob, order.ID).AckedTS != nil {
+		t.Fatalf("order acknowledged by unassigned admin")
+	}
+
+	// Alice acknowledges the order.
+	h.FetchPage(alice, ackPath, nil)
+	order = h.Order(bob, order.ID)
+	if order.AckedTS == nil {
+		t.Fatalf("order not acknowledged")
+	}
+	assert.DeepEqual(t, *order.AckedBy, alice)
+}
+
+// TestSimpleStoreTipOrderExpiry tests that orders paid with tips that are not
+// paid before their quote expires are expired and return their items to the
+// stock.
+func TestSimpleStoreTipOrderExpiry(t *testing.T) {
+	t.Parallel()
+
+	products := `
+[[products]]
+title = "Donation"
+sku = "donation"
+price = 1.0
+paywhatyouwant = true
+stock = 1
+`
+	h := storetest.NewWithProducts(t, simplestore.Config{
+		QuoteValidity: 500 * time.Millisecond,
+	}, storetest.NewClient(), products)
+	bob := h.Client.AddUser("bob")
+
+	h.FetchPage(bob, "addToCart", map[string]interface{}{
+		"sku": "donation", "qty": 1, "amount": "5",
+	})
+	order := h.PlaceOrder(bob)
+	assert.DeepEqual(t, order.PayType, simplestore.PayTypeTip)
+	assert.DeepEqual(t, *h.Product("donation").Stock, int64(0))
+
+	h.WaitOrderStatus(bob, order.ID, simplestore.StatusExpired)
+	assert.DeepEqual(t, *h.Product("donation").Stock, int64(1))
+}
+
+// storeLNPayClient is a payment client that pays and looks up the invoices of
+// the fake LN wallet of a simple store.
+type storeLNPayClient struct {
+	*testutils.MockPayClient
+	ln *storetest.LNClient
+}
+
+func (pc *storeLNPayClient) lookupInvoice(ctx context.Context, hash []byte) (*lnrpc.Invoice, error) {
+	inv, err := pc.ln.LNRPC().LookupInvoice(ctx, &lnrpc.PaymentHash{RHash: hash})
+	if err != nil {
+		return nil, err
+	}
+	if inv.State != lnrpc.Invoice_SETTLED {
+		return nil, fmt.Errorf("invoice %x not settled", hash)
+	}
+	return inv, nil
+}
+
+func (pc *storeLNPayClient) DecodeInvoice(ctx context.Context, invoice string) (clientintf.DecodedInvoice, error) {
+	payReq, err := pc.ln.LNRPC().DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: invoice})
+	if err != nil {
+		return clientintf.DecodedInvoice{}, err
+	}
+	return clientintf.DecodedInvoice{
+		ID:         storetest.PaymentHash(invoice),
+		MAtoms:     payReq.NumMAtoms,
+		ExpiryTime: time.Now().Add(time.Hour),
+	}, nil
+}
+
+func (pc *storeLNPayClient) PaymentPreimage(ctx context.Context, invoice string) ([]byte, error) {
+	inv, err := pc.lookupInvoice(ctx, storetest.PaymentHash(invoice))
+	if err != nil {
+		return nil, err
+	}
+	return inv.RPreimage, nil
+}
+
+func (pc *storeLNPayClient) SettledInvoiceMAtoms(ctx context.Context, hash []byte) (int64, error) {
+	inv, err := pc.lookupInvoice(ctx, hash)
+	if err != nil {
+		return 0, err
+	}
+	return inv.AmtPaidMAtoms, nil
+}
+
+// TestSimpleStoreOrderPaymentProof tests that the buyer of an order sends the
+// proof of payment of the order invoice to the store, which records it after
+// verifying it against the settled invoice.
+func TestSimpleStoreOrderPaymentProof(t *testing.T) {
+	t.Parallel()
+
+	ln := storetest.NewLNClient()
+	pcIniter := func(loggerSubsysIniter) clientintf.PaymentClient {
+		return &storeLNPayClient{MockPayClient: &testutils.MockPayClient{}, ln: ln}
+	}
+	tcfg := testScaffoldCfg{}
+	ts := newTestScaffold(t, tcfg)
+	alice := ts.newClient("alice", withPCIniter(pcIniter))
+	bob := ts.newClient("bob", withPCIniter(pcIniter))
+	ts.kxUsers(alice, bob)
+
+	// Alice runs a store.
+	root := t.TempDir()
+	products := `
+[[products]]
+title = "Test Book"
+sku = "book01"
+price = 10.0
+`
+	prodDir := filepath.Join(root, "products")
+	assert.NilErr(t, os.Mkdir(prodDir, 0o700))
+	assert.NilErr(t, os.WriteFile(filepath.Join(prodDir, "products.toml"),
+		[]byte(products), 0o600))
+	store, err := simplestore.New(simplestore.Config{
+		Root:         root,
+		Client:       alice.Client,
+		LNPayClient:  ln,
+		RateProvider: storetest.FixedRate(storetest.DefaultRate),
+		PayType:      simplestore.PayTypeLN,
+		Log:          alice.log,
+	})
+	assert.NilErr(t, err)
+	go func() { _ = store.Run(ts.ctx) }()
+	alice.modifyHandlers(func() {
+		alice.resourcesProvider = store
+	})
+
+	fetchedChan := make(chan clientdb.FetchedResource, 1)
+	bob.handle(client.OnResourceFetchedNtfn(func(user *client.RemoteUser,
+		fr clientdb.FetchedResource, sess clientdb.PageSessionOverview) {
+		fetchedChan <- fr
+	}))
+	fetch := func(path string, data interface{}) clientdb.FetchedResource {
+		t.Helper()
+		var rawData json.RawMessage
+		if data != nil {
+			rawData, err = json.Marshal(data)
+			assert.NilErr(t, err)
+		}
+		_, err := bob.FetchResource(alice.PublicID(), []string{path}, nil,
+			0, 0, rawData)
+		assert.NilErr(t, err)
+		return assert.ChanWritten(t, fetchedChan)
+	}
+
+	// Bob places two orders.
+	placeOrder := func() *rpc.OrderSummary {
+		t.Helper()
+		fetch("addToCart", map[string]interface{}{"sku": "book01", "qty": 1})
+		fr := fetch("placeOrder", nil)
+		assert.DeepEqual(t, fr.OrderSummaryStatus, clientdb.ResourceSignatureValid)
+		return fr.OrderSummary
+	}
+	summary := placeOrder()
+	otherSummary := placeOrder()
+
+	assertOrderProof := func(summary *rpc.OrderSummary, want bool) {
+		t.Helper()
+		var id simplestore.OrderID
+		assert.NilErr(t, id.FromString(summary.OrderID))
+		bobID := bob.PublicID()
+		for i := 0; i < 100; i++ {
+			orders, err := store.QueryOrders(simplestore.OrderFilter{User: &bobID})
+			assert.NilErr(t, err)
+			for _, order := range orders {
+				if order.ID != id {
+					continue
+				}
+				if (order.PaymentProof != nil) == want {
+					return
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatalf("payment proof of order %s recorded != %v",
+			summary.OrderID, want)
+	}
+
+	// Bob pays the first order and sends the proof of payment, which is
+	// recorded by the store.
+	assert.NilErr(t, ln.PayInvoice(summary.Invoice))
+	proof, err := bob.SendOrderPaymentProof(alice.PublicID(), summary)
+	assert.NilErr(t, err)
+	assertOrderProof(summary, true)
+
+	// A proof of the payment of the first order in the context of the
+	// second order is not recorded.
+	wrongSummary := *otherSummary
+	wrongSummary.Invoice = summary.Invoice
+	_, err = bob.SendOrderPaymentProof(alice.PublicID(), &wrongSummary)
+	assert.NilErr(t, err)
+	time.Sleep(time.Second)
+	assertOrderProof(otherSummary, false)
+
+	// The recorded proof is the one sent by Bob.
+	var id simplestore.OrderID
+	assert.NilErr(t, id.FromString(summary.OrderID))
+	bobID := bob.PublicID()
+	orders, err := store.QueryOrders(simplestore.OrderFilter{User: &bobID})
+	assert.NilErr(t, err)
+	for _, order := range orders {
+		if order.ID == id {
+			assert.DeepEqual(t, order.PaymentProof.PaymentHash, proof.PaymentHash)
+		}
+	}
+}
+
+// TestSimpleStoreEscrow tests that high-value orders are paid in escrow to the
+// escrow account and are only completed once both the buyer and an admin
+// release the escrow, which is blocked while the buyer disputes the order.
+func TestSimpleStoreEscrow(t *testing.T) {
+	t.Parallel()
+
+	c := storetest.NewClient()
+	alice := c.AddUser("alice")
+	h := storetest.NewWithClient(t, simplestore.Config{
+		AdminRoles: map[clientintf.UserID]simplestore.AdminRole{
+			alice: simplestore.RoleOrderManager,
+		},
+		Escrow: simplestore.EscrowConfig{
+			MinTotal: 50,
+			Account:  "escrow",
+		},
+	}, c)
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	// Orders below the min total are not paid in escrow.
+	h.AddToCart(bob, "book01", 1)
+	order := h.PlaceOrder(bob)
+	assert.DeepEqual(t, order.IsEscrow(), false)
+	assert.DeepEqual(t, order.PayType, simplestore.PayTypeLN)
+
+	// Orders above the min total are paid on-chain, to an address of the
+	// escrow account.
+	h.AddToCart(bob, "book01", 6)
+	order = h.PlaceOrder(bob)
+	assert.DeepEqual(t, order.IsEscrow(), true)
+	assert.DeepEqual(t, order.PayType, simplestore.PayTypeOnChain)
+	acct, ok := c.AddrAccount(order.Invoice)
+	assert.DeepEqual(t, ok, true)
+	assert.DeepEqual(t, acct, "escrow")
+
+	releasePath := "order/" + order.ID.String() + "/escrowrelease"
+	disputePath := "order/" + order.ID.String() + "/escrowdispute"
+	adminPath := "admin/orderescrow/" + bob.String() + "/" + order.ID.String()
+
+	// The escrow of unpaid orders can't be released.
+	res := h.Fetch(bob, releasePath, nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+
+	h.PayOrder(order)
+	h.WaitOrderStatus(bob, order.ID, simplestore.StatusPaid)
+	assertStoreReplyContains(t, h.WaitPM(bob), "identified as paid")
+
+	// While the buyer disputes the order, neither the buyer nor the admin
+	// may release the escrow.
+	res = h.Fetch(bob, disputePath, map[string]string{"reason": "not received"})
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusOk)
+	order = h.Order(bob, order.ID)
+	assert.DeepEqual(t, order.Escrow.Disputed, true)
+	assert.DeepEqual(t, order.Escrow.DisputeReason, "not received")
+	res = h.Fetch(bob, releasePath, nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+	res = h.Fetch(alice, adminPath+"/release", nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+	_, err := h.Store.ReleaseEscrow(bob, order.ID)
+	assert.ErrorIs(t, err, simplestore.ErrInvalidEscrowAction)
+
+	// The admin resolves the dispute, which allows the escrow to be
+	// released again.
+	h.FetchPage(alice, adminPath+"/resolve", map[string]string{"note": "found it"})
+	order = h.Order(bob, order.ID)
+	assert.DeepEqual(t, order.Escrow.Disputed, false)
+	assert.DeepEqual(t, *order.Escrow.DisputeResolvedBy, alice)
+	assertStoreReplyContains(t, h.WaitPM(bob), "found it")
+
+	// The order is only completed after both the buyer and the admin
+	// release the escrow.
+	h.FetchPage(bob, releasePath, nil)
+	order = h.Order(bob, order.ID)
+	assert.DeepEqual(t, order.Status, simplestore.StatusPaid)
+	if order.Escrow.BuyerReleasedTS == nil {
+		t.Fatalf("buyer release was not recorded")
+	}
+	res = h.Fetch(bob, releasePath, nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+	h.FetchPage(alice, adminPath+"/release", nil)
+	order = h.WaitOrderStatus(bob, order.ID, simplestore.StatusCompleted)
+	assert.DeepEqual(t, *order.Escrow.SellerReleasedBy, alice)
+	assert.DeepEqual(t, order.Escrow.Released(), true)
+}
+
+// TestSimpleStoreRefunds tests that only paid orders may be refunded, up to
+// their paid amount, and that pushed refunds are sent to the buyer.
+func TestSimpleStoreRefunds(t *testing.T) {
+	t.Parallel()
+
+	c := storetest.NewClient()
+	alice := c.AddUser("alice")
+	h := storetest.NewWithClient(t, simplestore.Config{
+		AdminRoles: map[clientintf.UserID]simplestore.AdminRole{
+			alice: simplestore.RoleOrderManager,
+		},
+	}, c)
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	// Unpaid orders can't be refunded.
+	h.AddToCart(bob, "book01", 1)
+	order := h.PlaceOrder(bob)
+	_, err := h.Store.RefundOrder(bob, order.ID, dcrutil.Amount(1e7), "", false)
+	assert.ErrorIs(t, err, simplestore.ErrInvalidRefund)
+
+	// Paid orders can't be canceled by the buyer.
+	h.PayOrder(order)
+	order = h.WaitOrderStatus(bob, order.ID, simplestore.StatusPaid)
+	assertStoreReplyContains(t, h.WaitPM(bob), "identified as paid")
+	res := h.Fetch(bob, "order/"+order.ID.String()+"/cancel", nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+	assert.DeepEqual(t, order.Refundable(), order.PaidAmount)
+
+	// The admin refunds part of the order, which is sent to the buyer.
+	refundPath := "admin/orderrefund/" + bob.String() + "/" + order.ID.String()
+	h.FetchPage(alice, refundPath, map[string]string{
+		"amount": "0.2",
+		"note":   "damaged box",
+		"push":   "yes",
+	})
+	assert.DeepEqual(t, h.Client.Tips(), []storetest.Tip{{To: bob, DCRAmount: 0.2}})
+	pm := h.WaitPM(bob)
+	assertStoreReplyContains(t, pm, "refund of 0.2 DCR")
+	assertStoreReplyContains(t, pm, "damaged box")
+	order = h.Order(bob, order.ID)
+	assert.DeepEqual(t, len(order.Refunds), 1)
+	assert.DeepEqual(t, *order.Refunds[0].By, alice)
+	assert.DeepEqual(t, order.Refunds[0].Pushed, true)
+	assert.DeepEqual(t, order.Refunded(), dcrutil.Amount(2e7))
+
+	// Refunds larger than the remaining paid amount are rejected.
+	res = h.Fetch(alice, refundPath, map[string]string{"amount": "0.4"})
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+	_, err = h.Store.RefundOrder(bob, order.ID, dcrutil.Amount(4e7), "", false)
+	assert.ErrorIs(t, err, simplestore.ErrInvalidRefund)
+
+	// The rest of the order is refunded out of band.
+	order, err = h.Store.RefundOrder(bob, order.ID, order.Refundable(), "", false)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, order.Refundable(), dcrutil.Amount(0))
+	assert.DeepEqual(t, order.Refunded(), order.PaidAmount)
+	assert.DeepEqual(t, order.Refunds[1].Pushed, false)
+	assert.DeepEqual(t, len(h.Client.Tips()), 1)
+}
+
+// setSubscriptionPaidUntil changes the end of the paid period of the single
+// subscription of the user in the root of the store.
+func setSubscriptionPaidUntil(t testing.TB, h *storetest.Harness, uid clientintf.UserID, paidUntil time.Time) {
+	t.Helper()
+	fnames, err := filepath.Glob(filepath.Join(h.Root, "subscriptions", uid.String(), "*.json"))
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(fnames), 1)
+	data, err := os.ReadFile(fnames[0])
+	assert.NilErr(t, err)
+	var sub map[string]json.RawMessage
+	assert.NilErr(t, json.Unmarshal(data, &sub))
+	sub["paid_until"], err = json.Marshal(paidUntil)
+	assert.NilErr(t, err)
+	data, err = json.Marshal(sub)
+	assert.NilErr(t, err)
+	assert.NilErr(t, os.WriteFile(fnames[0], data, 0o600))
+}
+
+// TestSimpleStoreSubscriptions tests that paying for a subscription product
+// starts a subscription, that renewal orders are placed before the end of the
+// paid period and extend it when paid, and that subscriptions whose renewal is
+// not paid lapse after the grace period.
+func TestSimpleStoreSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	products := `
+[[products]]
+title = "Newsletter"
+sku = "news01"
+price = 5.0
+subscription = "weekly"
+`
+	h := storetest.NewWithProducts(t, simplestore.Config{}, storetest.NewClient(), products)
+	bob := h.Client.AddUser("bob")
+
+	loadSub := func() *simplestore.Subscription {
+		t.Helper()
+		subs, err := h.Store.Subscriptions(bob)
+		assert.NilErr(t, err)
+		assert.DeepEqual(t, len(subs), 1)
+		return subs[0]
+	}
+	hasSub := func() bool {
+		t.Helper()
+		ok, err := h.Store.HasSubscription(bob, "news01")
+		assert.NilErr(t, err)
+		return ok
+	}
+
+	// Paying for the product starts the subscription.
+	h.AddToCart(bob, "news01", 1)
+	order := h.PlaceOrder(bob)
+	h.PayOrder(order)
+	h.WaitOrderStatus(bob, order.ID, simplestore.StatusPaid)
+	assertStoreReplyContains(t, h.WaitPM(bob), "identified as paid")
+	sub := loadSub()
+	assert.DeepEqual(t, sub.Status, simplestore.SubscriptionStatusActive)
+	assert.DeepEqual(t, sub.Orders, []simplestore.OrderID{order.ID})
+	assert.DeepEqual(t, hasSub(), true)
+
+	// Close to the end of the paid period, the renewal order is placed and
+	// sent to the subscriber.
+	paidUntil := time.Now().Add(time.Hour).Truncate(time.Second)
+	setSubscriptionPaidUntil(t, h, bob, paidUntil)
+	h.Restart()
+	assertStoreReplyContains(t, h.WaitPM(bob), "renews on")
+	sub = loadSub()
+	if sub.RenewalOrderID == 0 {
+		t.Fatalf("renewal order was not placed")
+	}
+	renewal := h.Order(bob, sub.RenewalOrderID)
+	assert.DeepEqual(t, renewal.SubscriptionID, sub.ID)
+	assert.DeepEqual(t, renewal.Status, simplestore.StatusPlaced)
+	assert.DeepEqual(t, renewal.Total(), simplestore.MoneyFromFloat(5))
+
+	// Paying the renewal order extends the paid period.
+	h.PayOrder(renewal)
+	h.WaitOrderStatus(bob, renewal.ID, simplestore.StatusPaid)
+	assertStoreReplyContains(t, h.WaitPM(bob), "identified as paid")
+	sub = loadSub()
+	if !sub.PaidUntil.Equal(paidUntil.AddDate(0, 0, 7)) {
+		t.Fatalf("unexpected paid period end %s", sub.PaidUntil)
+	}
+	assert.DeepEqual(t, sub.Orders, []simplestore.OrderID{order.ID, renewal.ID})
+	assert.DeepEqual(t, sub.RenewalOrderID, simplestore.OrderID(0))
+
+	// A renewal order is placed again after the paid period ends, but it
+	// is not paid. The subscription remains active during the grace
+	// period, but no longer grants access to the product.
+	setSubscriptionPaidUntil(t, h, bob, time.Now().Add(-time.Hour))
+	h.Restart()
+	assertStoreReplyContains(t, h.WaitPM(bob), "renews on")
+	sub = loadSub()
+	unpaid := sub.RenewalOrderID
+	if unpaid == 0 {
+		t.Fatalf("renewal order was not placed")
+	}
+	assert.DeepEqual(t, sub.Status, simplestore.SubscriptionStatusActive)
+	assert.DeepEqual(t, hasSub(), false)
+
+	// After the grace period, the subscription lapses and its renewal
+	// order is canceled.
+	setSubscriptionPaidUntil(t, h, bob, time.Now().AddDate(0, 0, -8))
+	h.Restart()
+	assertStoreReplyContains(t, h.WaitPM(bob), "lapsed")
+	sub = loadSub()
+	assert.DeepEqual(t, sub.Status, simplestore.SubscriptionStatusLapsed)
+	assert.DeepEqual(t, sub.RenewalOrderID, simplestore.OrderID(0))
+	assert.DeepEqual(t, h.Order(bob, unpaid).Status, simplestore.StatusCanceled)
+	assert.DeepEqual(t, hasSub(), false)
+}
+
+// TestSimpleStoreCouponUses tests that the orders placed with a coupon are
+// counted and that the coupon can't be used after its max number of uses, even
+// if it was applied to a cart before that.
+func TestSimpleStoreCouponUses(t *testing.T) {
+	t.Parallel()
+
+	h := storetest.New(t, simplestore.Config{})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	promos := `[{"code":"SAVE10","type":"percent","amount":10,"max_uses":2},` +
+		`{"code":"MUGS","type":"fixed","amount":2,"skus":["mug01"]}]`
+	assert.NilErr(t, os.WriteFile(filepath.Join(h.Root, "promotions.json"),
+		[]byte(promos), 0o600))
+	usesFname := filepath.Join(h.Root, "promotionuses.json")
+	assertUses := func(want int) {
+		t.Helper()
+		var uses map[string]int
+		data, err := os.ReadFile(usesFname)
+		assert.NilErr(t, err)
+		assert.NilErr(t, json.Unmarshal(data, &uses))
+		assert.DeepEqual(t, uses["SAVE10"], want)
+	}
+
+	// Promotions for products not in the cart do not apply.
+	bob := h.Client.AddUser("bob")
+	h.AddToCart(bob, "book01", 1)
+	reply := h.FetchPage(bob, "applyCoupon", map[string]string{"code": "mugs"})
+	assertStoreReplyContains(t, reply, "does not apply")
+
+	// Three users apply the coupon to their carts before any of them
+	// places an order. Codes are case insensitive.
+	users := []clientintf.UserID{bob, h.Client.AddUser("carol"), h.Client.AddUser("dave")}
+	for i, uid := range users {
+		if i > 0 {
+			h.AddToCart(uid, "book01", 1)
+		}
+		reply := h.FetchPage(uid, "applyCoupon", map[string]string{"code": "save10"})
+		assertStoreReplyContains(t, reply, `Applied coupon "SAVE10"`)
+	}
+
+	// Each placed order is counted as a use of the coupon.
+	for i, uid := range users[:2] {
+		order := h.PlaceOrder(uid)
+		assert.DeepEqual(t, order.Cart.Coupon, "SAVE10")
+		assert.DeepEqual(t, order.Total(), simplestore.MoneyFromFloat(9))
+		assertUses(i + 1)
+	}
+
+	// The coupon is no longer available, so the last order is rejected
+	// and the coupon is removed from the cart.
+	res := h.Fetch(users[2], "placeOrder", nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+	assertStoreReplyContains(t, string(res.Data), "no longer available")
+	assertUses(2)
+	order := h.PlaceOrder(users[2])
+	assert.DeepEqual(t, order.Cart.Coupon, "")
+	assert.DeepEqual(t, order.Total(), simplestore.MoneyFromFloat(10))
+
+	// The uses are kept across restarts.
+	h.Restart()
+	h.AddToCart(bob, "book01", 1)
+	reply = h.FetchPage(bob, "applyCoupon", map[string]string{"code": "SAVE10"})
+	assertStoreReplyContains(t, reply, "no longer available")
+	assertUses(2)
+}
+
+// testShipAddr returns a complete shipping address in the region.
+func testShipAddr(country, state string) *simplestore.ShippingAddress {
+	return &simplestore.ShippingAddress{
+		Name:        "Bob",
+		Address1:    "1 Main St",
+		City:        "Springfield",
+		State:       state,
+		PostalCode:  "12345",
+		CountryCode: country,
+	}
+}
+
+// placeOrderShippedTo places the order of the cart of the user, shipped to the
+// address. It returns the reply of the store and the placed order.
+func placeOrderShippedTo(t testing.TB, h *storetest.Harness, uid clientintf.UserID,
+	addr *simplestore.ShippingAddress) (string, *simplestore.Order) {
+
+	t.Helper()
+	reply := h.FetchPage(uid, "placeOrder", addr)
+	return reply, h.Orders(uid)[0]
+}
+
+// TestSimpleStoreTaxes tests that orders are charged the taxes of the region
+// of their shipping address and that the taxes are itemized in the receipts.
+func TestSimpleStoreTaxes(t *testing.T) {
+	t.Parallel()
+
+	h := storetest.New(t, simplestore.Config{
+		ShipCharge: 5,
+		Tax: simplestore.TaxConfig{
+			Name:        "VAT",
+			Rate:        5,
+			Regions:     map[string]float64{"US": 8, "us-ca": 10},
+			TaxShipping: true,
+		},
+	})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:      "book01",
+		Title:    "Test Book",
+		Price:    simplestore.MoneyFromFloat(10),
+		Shipping: true,
+	})
+
+	// Each order is placed by a different user and shipped to a region
+	// with a different rate.
+	tests := []struct {
+		addr     *simplestore.ShippingAddress
+		wantRate float64
+		wantTax  float64
+	}{{
+		addr:     testShipAddr("US", "CA"),
+		wantRate: 10,
+		wantTax:  1.5,
+	}, {
+		addr:     testShipAddr("us", "NY"),
+		wantRate: 8,
+		wantTax:  1.2,
+	}, {
+		addr:     testShipAddr("DE", "Berlin"),
+		wantRate: 5,
+		wantTax:  0.75,
+	}}
+
+	for _, tc := range tests {
+		uid := h.Client.AddUser("bob")
+		h.AddToCart(uid, "book01", 1)
+		reply, order := placeOrderShippedTo(t, h, uid, tc.addr)
+
+		// The items and the shipping charge are taxed.
+		wantTax := simplestore.MoneyFromFloat(tc.wantTax)
+		assert.DeepEqual(t, order.Taxes, simplestore.TaxLines{{
+			Name:   "VAT",
+			Rate:   tc.wantRate,
+			Amount: wantTax,
+		}})
+		wantTotal := simplestore.MoneyFromFloat(15) + wantTax
+		assert.DeepEqual(t, order.Total(), wantTotal)
+
+		// The tax is itemized in the order reply and in the receipt
+		// sent when the order is paid.
+		line := order.Taxes[0].Label() + ": " + order.FormatAmount(wantTax)
+		assertStoreReplyContains(t, reply, line)
+		h.PayOrder(order)
+		h.WaitOrderStatus(uid, order.ID, simplestore.StatusPaid)
+		assertStoreReplyContains(t, h.WaitPM(uid), order.Taxes[0].Label())
+	}
+}
+
+// TestSimpleStoreTaxCalc tests that the taxes of orders are calculated by the
+// tax calculator of the config, when set.
+func TestSimpleStoreTaxCalc(t *testing.T) {
+	t.Parallel()
+
+	h := storetest.New(t, simplestore.Config{
+		Tax: simplestore.TaxConfig{
+			Rate: 5,
+			Calc: func(order *simplestore.Order) simplestore.TaxLines {
+				return simplestore.TaxLines{
+					{Name: "State", Amount: simplestore.MoneyFromFloat(1)},
+					{Name: "City", Amount: simplestore.MoneyFromFloat(0.25)},
+				}
+			},
+		},
+	})
+	h.AddProduct(&simplestore.ProductUpdate{
+		SKU:   "book01",
+		Title: "Test Book",
+		Price: simplestore.MoneyFromFloat(10),
+	})
+	bob := h.Client.AddUser("bob")
+
+	h.AddToCart(bob, "book01", 1)
+	order := h.PlaceOrder(bob)
+	assert.DeepEqual(t, len(order.Taxes), 2)
+	assert.DeepEqual(t, order.Taxes.Total(), simplestore.MoneyFromFloat(1.25))
+	assert.DeepEqual(t, order.Total(), simplestore.MoneyFromFloat(11.25))
+}
+
+// TestSimpleStoreShippingMethods tests that buyers may select the shipping
+// methods of the store that ship their order to their address and that orders
+// are charged the price of the selected method.
+func TestSimpleStoreShippingMethods(t *testing.T) {
+	t.Parallel()
+
+	products := `
+[[products]]
+title = "Test Book"
+sku = "book01"
+price = 10.0
+shipping = true
+weight = 1.0
+
+[[products]]
+title = "Anvil"
+sku = "anvil01"
+price = 50.0
+shipping = true
+weight = 30.0
+`
+	methods := `
+[[Methods]]
+ID = "ground"
+Name = "Ground"
+FreeOver = 100.0
+# Tiers are sorted by max weight when loaded.
+Tiers = [
+  { MaxWeight = 20.0, Price = 9.0 },
+  { MaxWeight = 5.0, Price = 4.0 },
+]
+
+[[Methods]]
+ID = "express"
+Name = "Express"
+Price = 15.0
+Regions = ["US"]
+`
+	h := storetest.NewWithProducts(t, simplestore.Config{
+		ShippingMethods: []simplestore.ShippingMethod{{
+			ID:    "config",
+			Name:  "Config Method",
+			Price: simplestore.MoneyFromFloat(1),
+		}},
+	}, storetest.NewClient(), products)
+
+	// The methods of the shipping file take precedence over the ones of
+	// the config.
+	assert.NilErr(t, os.WriteFile(filepath.Join(h.Root, "shipping.toml"),
+		[]byte(methods), 0o600))
+	h.Restart()
+
+	// The buyer sees the methods that ship to the address, with the first
+	// one selected, and selects another one.
+	bob := h.Client.AddUser("bob")
+	h.AddToCart(bob, "book01", 1)
+	checkout := h.FetchPage(bob, "shippingInfo", testShipAddr("US", "CA"))
+	assertStoreReplyContains(t, checkout, "**Ground** - $4.00 USD (selected)")
+	assertStoreReplyContains(t, checkout, "[Express](/shippingMethod/express) - $15.00 USD")
+	if strings.Contains(checkout, "Config Method") {
+		t.Fatalf("checkout includes the shipping method of the config")
+	}
+	checkout = h.FetchPage(bob, "shippingMethod/express", nil)
+	assertStoreReplyContains(t, checkout, "**Express** - $15.00 USD (selected)")
+	order := h.PlaceOrder(bob)
+	assert.DeepEqual(t, order.ShipMethod, "Express")
+	assert.DeepEqual(t, order.ShipCharge, simplestore.MoneyFromFloat(15))
+	assert.DeepEqual(t, order.Total(), simplestore.MoneyFromFloat(25))
+
+	// Methods restricted to other regions can't be selected.
+	carol := h.Client.AddUser("carol")
+	h.AddToCart(carol, "book01", 6)
+	checkout = h.FetchPage(carol, "shippingInfo", testShipAddr("DE", "Berlin"))
+	if strings.Contains(checkout, "Express") {
+		t.Fatalf("checkout includes a method that does not ship to the address")
+	}
+	res := h.Fetch(carol, "shippingMethod/express", nil)
+	assert.DeepEqual(t, res.Status, rpc.ResourceStatusBadRequest)
+
+	// Tiers are charged by the weight of the order.
+	order = h.PlaceOrder(carol)
+	assert.DeepEqual(t, order.ShipMethod, "Ground")
+	assert.DeepEqual(t, order.ShipCharge, simplestore.MoneyFromFloat(9))
+
+	// Shipping is free over the threshold of the method.
+	dave := h.Client.AddUser("dave")
+	h.AddToCart(dave, "book01", 10)
+	_, order = placeOrderShippedTo(t, h, dave, testShipAddr("DE", "Berlin"))
+	assert.DeepEqual(t, order.ShipMethod, "Ground")
+	assert.DeepEqual(t, order.ShipCharge, simplestore.Money(0))
+
+	// Orders heavier than the tiers of a method are only shipped by the
+	// other methods.
+	eve := h.Client.AddUser("eve")
+	h.AddToCart(eve, "anvil01", 1)
+	_, order = placeOrderShippedTo(t, h, eve, testShipAddr("US", "NY"))
+	assert.DeepEqual(t, order.ShipMethod, "Express")
+	nOrders := len(h.Orders(eve))
+	h.AddToCart(eve, "anvil01", 1)
+	reply := h.FetchPage(eve, "placeOrder", testShipAddr("DE", "Berlin"))
+	assertStoreReplyContains(t, reply, "No shipping method ships this order")
+	assert.DeepEqual(t, len(h.Orders(eve)), nOrders)
+}
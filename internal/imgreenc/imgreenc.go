@@ -0,0 +1,239 @@
+// Package imgreenc downscales and re-encodes images before they are sent to
+// remote users, in order to reduce their size (and therefore the relay fees
+// paid to send them) and to strip metadata that could leak private info.
+package imgreenc
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// DefaultJPEGQuality is the quality used to encode JPEG images when one is
+// not specified in the config.
+const DefaultJPEGQuality = 85
+
+// Config configures the re-encoding of images.
+type Config struct {
+	// MaxWidth and MaxHeight are the max dimensions of images. Larger
+	// images are downscaled to fit, keeping their aspect ratio. Zero means
+	// no limit.
+	MaxWidth  int
+	MaxHeight int
+
+	// MaxSize is the size (in bytes) above which images are re-encoded.
+	// The re-encoded image is only used if it is smaller than the
+	// original one. Zero means no limit.
+	MaxSize int
+
+	// JPEGQuality is the quality (1-100) of re-encoded JPEG images.
+	// Defaults to DefaultJPEGQuality.
+	JPEGQuality int
+
+	// StripMetadata causes images with metadata (EXIF in JPEG images,
+	// text and EXIF chunks in PNG images) to be re-encoded without it,
+	// even if they are within the size limits.
+	StripMetadata bool
+}
+
+// IsZero returns true if the config does not cause any image to be
+// re-encoded.
+func (cfg *Config) IsZero() bool {
+	return cfg.MaxWidth <= 0 && cfg.MaxHeight <= 0 && cfg.MaxSize <= 0 &&
+		!cfg.StripMetadata
+}
+
+// fitDims returns the dimensions that fit w x h in the max dimensions of the
+// config, keeping the aspect ratio.
+func (cfg *Config) fitDims(w, h int) (int, int) {
+	if cfg.MaxWidth > 0 && w > cfg.MaxWidth {
+		h = max(1, h*cfg.MaxWidth/w)
+		w = cfg.MaxWidth
+	}
+	if cfg.MaxHeight > 0 && h > cfg.MaxHeight {
+		w = max(1, w*cfg.MaxHeight/h)
+		h = cfg.MaxHeight
+	}
+	return w, h
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Reencode re-encodes the image in data according to the config. It returns
+// the (possibly) re-encoded image, its mime type and whether it was changed.
+//
+// Data that is not a JPEG or PNG image, or that is within all limits of the
+// config, is returned unchanged (with an empty mime type if it is not an
+// image). Animated images (GIFs) are not supported, as re-encoding them would
+// drop their animation.
+func Reencode(data []byte, cfg Config) ([]byte, string, bool, error) {
+	imgCfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		// Not an image (or not a supported one).
+		return data, "", false, nil
+	}
+
+	var hasMeta bool
+	var orientation int
+	var mimeType string
+	switch format {
+	case "jpeg":
+		mimeType = "image/jpeg"
+		hasMeta, orientation = jpegEXIF(data)
+	case "png":
+		mimeType = "image/png"
+		hasMeta = pngHasMetadata(data)
+	default:
+		return data, "", false, nil
+	}
+
+	// Images that are displayed rotated have their dimensions swapped.
+	w, h := imgCfg.Width, imgCfg.Height
+	if orientation >= 5 {
+		w, h = h, w
+	}
+	newW, newH := cfg.fitDims(w, h)
+	resize := newW != w || newH != h
+	strip := cfg.StripMetadata && hasMeta
+	tooLarge := cfg.MaxSize > 0 && len(data) > cfg.MaxSize
+	if !resize && !strip && !tooLarge {
+		return data, mimeType, false, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", false, err
+	}
+	img := toRGBA(src)
+	if orientation > 1 {
+		img = orient(img, orientation)
+	}
+	if resize {
+		img = downscale(img, newW, newH)
+	}
+
+	quality := cfg.JPEGQuality
+	if quality <= 0 || quality > 100 {
+		quality = DefaultJPEGQuality
+	}
+	out := new(bytes.Buffer)
+	if format == "png" {
+		err = png.Encode(out, img)
+
+		// Opaque images still above the max size are converted to
+		// JPEG.
+		if err == nil && cfg.MaxSize > 0 && out.Len() > cfg.MaxSize && img.Opaque() {
+			out.Reset()
+			mimeType = "image/jpeg"
+			err = jpeg.Encode(out, img, &jpeg.Options{Quality: quality})
+		}
+	} else {
+		err = jpeg.Encode(out, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	// Re-encoding only due to the size is pointless if it did not make the
+	// image smaller.
+	if !resize && !strip && out.Len() >= len(data) {
+		if format == "png" {
+			mimeType = "image/png"
+		}
+		return data, mimeType, false, nil
+	}
+	return out.Bytes(), mimeType, true, nil
+}
+
+// toRGBA converts the image to an RGBA image with its origin at (0, 0).
+func toRGBA(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(img, img.Bounds(), src, b.Min, draw.Src)
+	return img
+}
+
+// downscale downscales the image to w x h by averaging the source pixels
+// covered by each destination pixel.
+func downscale(src *image.RGBA, w, h int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for dy := 0; dy < h; dy++ {
+		sy0, sy1 := dy*sh/h, (dy+1)*sh/h
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for dx := 0; dx < w; dx++ {
+			sx0, sx1 := dx*sw/w, (dx+1)*sw/w
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+			var r, g, b, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				i := src.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint32(src.Pix[i])
+					g += uint32(src.Pix[i+1])
+					b += uint32(src.Pix[i+2])
+					a += uint32(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+			j := dst.PixOffset(dx, dy)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// orient transforms the image according to its EXIF orientation, such that
+// it is displayed correctly once the EXIF metadata is removed.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	// srcXY returns the source coordinates of the destination pixel.
+	var srcXY func(dx, dy int) (int, int)
+	switch orientation {
+	case 2: // Flip horizontal.
+		srcXY = func(dx, dy int) (int, int) { return w - 1 - dx, dy }
+	case 3: // Rotate 180.
+		srcXY = func(dx, dy int) (int, int) { return w - 1 - dx, h - 1 - dy }
+	case 4: // Flip vertical.
+		srcXY = func(dx, dy int) (int, int) { return dx, h - 1 - dy }
+	case 5: // Transpose.
+		srcXY = func(dx, dy int) (int, int) { return dy, dx }
+	case 6: // Rotate 90 clockwise.
+		srcXY = func(dx, dy int) (int, int) { return dy, h - 1 - dx }
+	case 7: // Transverse.
+		srcXY = func(dx, dy int) (int, int) { return w - 1 - dy, h - 1 - dx }
+	case 8: // Rotate 90 counter-clockwise.
+		srcXY = func(dx, dy int) (int, int) { return w - 1 - dy, dx }
+	default:
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		for dx := 0; dx < dw; dx++ {
+			sx, sy := srcXY(dx, dy)
+			i, j := src.PixOffset(sx, sy), dst.PixOffset(dx, dy)
+			copy(dst.Pix[j:j+4], src.Pix[i:i+4])
+		}
+	}
+	return dst
+}
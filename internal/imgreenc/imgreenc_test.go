@@ -0,0 +1,171 @@
+package imgreenc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+// testImage returns a w x h image where the top left 10x10 block is red and
+// every other pixel is blue.
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{B: 255, A: 255}
+			if x < 10 && y < 10 {
+				c = color.RGBA{R: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	b := new(bytes.Buffer)
+	if err := jpeg.Encode(b, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// withEXIF inserts an EXIF segment with the given orientation after the SOI
+// marker of the JPEG image.
+func withEXIF(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08") // Header, IFD at offset 8.
+	tiff = append(tiff, 0, 1)                    // One IFD entry.
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry, 0x0112) // Orientation.
+	binary.BigEndian.PutUint16(entry[2:], 3)  // SHORT.
+	binary.BigEndian.PutUint32(entry[4:], 1)  // Count.
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0) // No next IFD.
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	seg := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	seg = append(seg, payload...)
+
+	res := append([]byte{}, data[:2]...)
+	res = append(res, seg...)
+	return append(res, data[2:]...)
+}
+
+// TestReencodeDownscales tests that images larger than the max dimensions
+// are downscaled, keeping their aspect ratio.
+func TestReencodeDownscales(t *testing.T) {
+	data := encodeJPEG(t, testImage(200, 100))
+	cfg := Config{MaxWidth: 50, MaxHeight: 50}
+	res, mimeType, changed, err := Reencode(data, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Fatalf("image was not re-encoded")
+	}
+	if mimeType != "image/jpeg" {
+		t.Fatalf("unexpected mime type %q", mimeType)
+	}
+	imgCfg, err := jpeg.DecodeConfig(bytes.NewReader(res))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imgCfg.Width != 50 || imgCfg.Height != 25 {
+		t.Fatalf("unexpected dimensions %dx%d", imgCfg.Width, imgCfg.Height)
+	}
+}
+
+// TestReencodeUnchanged tests that images within the limits and data that is
+// not an image are not re-encoded.
+func TestReencodeUnchanged(t *testing.T) {
+	cfg := Config{MaxWidth: 500, MaxHeight: 500, StripMetadata: true}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"small jpeg", encodeJPEG(t, testImage(20, 20))},
+		{"not an image", []byte("some text")},
+	}
+	for _, tc := range tests {
+		res, _, changed, err := Reencode(tc.data, cfg)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if changed || !bytes.Equal(res, tc.data) {
+			t.Fatalf("%s: data was changed", tc.name)
+		}
+	}
+}
+
+// TestReencodeStripsEXIF tests that EXIF metadata is stripped and that the
+// image is rotated according to its orientation.
+func TestReencodeStripsEXIF(t *testing.T) {
+	data := withEXIF(encodeJPEG(t, testImage(40, 20)), 6)
+	if hasEXIF, orientation := jpegEXIF(data); !hasEXIF || orientation != 6 {
+		t.Fatalf("unexpected EXIF in test data: %v %d", hasEXIF, orientation)
+	}
+
+	res, _, changed, err := Reencode(data, Config{StripMetadata: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Fatalf("image was not re-encoded")
+	}
+	if hasEXIF, _ := jpegEXIF(res); hasEXIF {
+		t.Fatalf("EXIF metadata was not stripped")
+	}
+
+	// Rotated 90 degrees clockwise, the red top left block is now the top
+	// right block.
+	img, err := jpeg.Decode(bytes.NewReader(res))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 40 {
+		t.Fatalf("unexpected dimensions %dx%d", b.Dx(), b.Dy())
+	}
+	if r, _, _, _ := img.At(15, 5).RGBA(); r < 0x8000 {
+		t.Fatalf("top right block is not red")
+	}
+	if r, _, _, _ := img.At(5, 5).RGBA(); r > 0x8000 {
+		t.Fatalf("top left block is red")
+	}
+}
+
+// TestReencodeLargePNG tests that opaque PNG images above the max size are
+// converted to JPEG.
+func TestReencodeLargePNG(t *testing.T) {
+	// Noise does not compress well in PNG images.
+	img := testImage(300, 300)
+	rnd := rand.New(rand.NewSource(1))
+	for i := range img.Pix {
+		if i%4 != 3 {
+			img.Pix[i] = uint8(rnd.Intn(256))
+		}
+	}
+	b := new(bytes.Buffer)
+	if err := png.Encode(b, img); err != nil {
+		t.Fatal(err)
+	}
+	data := b.Bytes()
+
+	res, mimeType, changed, err := Reencode(data, Config{MaxSize: len(data) - 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || mimeType != "image/jpeg" {
+		t.Fatalf("unexpected result: changed %v, mime type %q", changed, mimeType)
+	}
+	if len(res) >= len(data) {
+		t.Fatalf("re-encoded image is not smaller")
+	}
+}
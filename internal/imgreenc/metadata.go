@@ -0,0 +1,94 @@
+package imgreenc
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// jpegEXIF returns whether the JPEG image has EXIF metadata and the
+// orientation tag of the metadata (zero if not specified).
+func jpegEXIF(data []byte) (bool, int) {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xd8 {
+		return false, 0
+	}
+
+	// Walk the segments until the start of the image data.
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xff {
+			return false, 0
+		}
+		marker := data[i+1]
+		if marker == 0xd9 || marker == 0xda { // EOI or SOS.
+			return false, 0
+		}
+		segLen := int(binary.BigEndian.Uint16(data[i+2:]))
+		if segLen < 2 || i+2+segLen > len(data) {
+			return false, 0
+		}
+		payload := data[i+4 : i+2+segLen]
+		if marker == 0xe1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			return true, tiffOrientation(payload[6:])
+		}
+		i += 2 + segLen
+	}
+	return false, 0
+}
+
+// tiffOrientation returns the orientation tag of the first IFD of the TIFF
+// structure of EXIF metadata, or zero if it is not specified.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var bo binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return 0
+	}
+	if bo.Uint16(tiff[2:]) != 42 {
+		return 0
+	}
+
+	const orientationTag = 0x0112
+	ifd := int(bo.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	n := int(bo.Uint16(tiff[ifd:]))
+	for i := 0; i < n; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if bo.Uint16(tiff[entry:]) != orientationTag {
+			continue
+		}
+		o := int(bo.Uint16(tiff[entry+8:]))
+		if o < 1 || o > 8 {
+			return 0
+		}
+		return o
+	}
+	return 0
+}
+
+// pngHasMetadata returns true if the PNG image has text or EXIF chunks.
+func pngHasMetadata(data []byte) bool {
+	const sigLen = 8
+	for i := sigLen; i+8 <= len(data); {
+		chunkLen := int(binary.BigEndian.Uint32(data[i:]))
+		switch string(data[i+4 : i+8]) {
+		case "tEXt", "zTXt", "iTXt", "eXIf":
+			return true
+		case "IEND":
+			return false
+		}
+		// Length, type, data and CRC.
+		i += 12 + chunkLen
+	}
+	return false
+}
@@ -0,0 +1,334 @@
+package jsonfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/slog"
+)
+
+// journalExt is the extension of the journal files.
+const journalExt = ".journal"
+
+// syncDir fsyncs the given dir, such that renames and removals of files inside
+// it are persisted.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		// Windows does not support fsync on dirs.
+		return nil
+	}
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeFileSync writes the data to the file and fsyncs it.
+func writeFileSync(fname string, data []byte) error {
+	f, err := os.OpenFile(fname, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// journalOp is a single operation of a journaled batch.
+type journalOp struct {
+	// Filename is the final filename of the operation.
+	Filename string `json:"filename"`
+
+	// TempFilename is the temp file with the new contents of Filename. It
+	// is empty when the operation removes Filename.
+	TempFilename string `json:"temp_filename,omitempty"`
+}
+
+// journalEntry is the contents of a journal file.
+type journalEntry struct {
+	Ops       []journalOp `json:"ops"`
+	Committed bool        `json:"committed"`
+}
+
+// apply applies the operations of a committed entry. It is idempotent, so
+// that it may be called again if a previous apply was interrupted.
+func (entry *journalEntry) apply() error {
+	dirs := make(map[string]struct{}, len(entry.Ops))
+	for _, op := range entry.Ops {
+		var err error
+		if op.TempFilename == "" {
+			err = RemoveIfExists(op.Filename)
+			if err == nil && !Exists(filepath.Dir(op.Filename)) {
+				// Removing a file of a missing dir is a no-op,
+				// so there is no dir to sync.
+				continue
+			}
+		} else {
+			err = os.Rename(op.TempFilename, op.Filename)
+			if os.IsNotExist(err) && Exists(op.Filename) {
+				// Already renamed before.
+				err = nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+		dirs[filepath.Dir(op.Filename)] = struct{}{}
+	}
+	for dir := range dirs {
+		if err := syncDir(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discard removes the temp files of an uncommitted entry.
+func (entry *journalEntry) discard() error {
+	for _, op := range entry.Ops {
+		if op.TempFilename == "" {
+			continue
+		}
+		if err := RemoveIfExists(op.TempFilename); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Journal performs crash-safe writes of json files. Writes are first recorded
+// in a write-ahead journal file, such that a write (or batch of writes) that
+// was interrupted (for example, due to a power loss) is either fully applied
+// or fully discarded by Recover().
+//
+// Files written through a Journal should not be concurrently written by other
+// means.
+type Journal struct {
+	dir string
+	log slog.Logger
+
+	mtx    sync.Mutex
+	lastID int64
+}
+
+// OpenJournal opens the journal stored in the given dir, creating the dir if
+// needed, and recovers any interrupted writes.
+func OpenJournal(dir string, log slog.Logger) (*Journal, error) {
+	if log == nil {
+		log = slog.Disabled
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create journal dir: %w", err)
+	}
+	j := &Journal{dir: dir, log: log}
+	if _, err := j.Recover(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Recover scans the journal dir for interrupted writes. Writes that were
+// committed are applied and writes that were not committed are discarded.
+// Returns the number of recovered journal files.
+//
+// This should only be called when no batches are being committed.
+func (j *Journal) Recover() (int, error) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != journalExt {
+			continue
+		}
+		fname := filepath.Join(j.dir, e.Name())
+		var entry journalEntry
+		if err := Read(fname, &entry); err != nil {
+			// The journal file is only renamed into place after
+			// being fully written, so this is not expected.
+			return n, fmt.Errorf("unable to read journal file %s: %w",
+				fname, err)
+		}
+		if entry.Committed {
+			j.log.Infof("Applying interrupted journaled write %s "+
+				"(%d files)", e.Name(), len(entry.Ops))
+			err = entry.apply()
+		} else {
+			j.log.Infof("Discarding uncommitted journaled write %s "+
+				"(%d files)", e.Name(), len(entry.Ops))
+			err = entry.discard()
+		}
+		if err != nil {
+			return n, fmt.Errorf("unable to recover journal file %s: %w",
+				fname, err)
+		}
+		if err := os.Remove(fname); err != nil {
+			return n, err
+		}
+		n += 1
+	}
+	if n > 0 {
+		if err := syncDir(j.dir); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// nextJournalFname returns the name of the next journal file.
+func (j *Journal) nextJournalFname() string {
+	j.mtx.Lock()
+	id := time.Now().UnixNano()
+	if id <= j.lastID {
+		id = j.lastID + 1
+	}
+	j.lastID = id
+	j.mtx.Unlock()
+	return filepath.Join(j.dir, fmt.Sprintf("%020d%s", id, journalExt))
+}
+
+// writeEntry writes the journal entry to the journal file.
+func (j *Journal) writeEntry(fname string, entry *journalEntry) error {
+	if err := Write(fname, entry, j.log); err != nil {
+		return err
+	}
+	return syncDir(j.dir)
+}
+
+// Write writes a single json file through the journal.
+func (j *Journal) Write(fname string, data interface{}) error {
+	b := j.NewBatch()
+	b.Write(fname, data)
+	return b.Commit()
+}
+
+// NewBatch starts a new batch of writes that are committed atomically.
+func (j *Journal) NewBatch() *Batch {
+	return &Batch{j: j}
+}
+
+type batchOp struct {
+	fname  string
+	data   interface{}
+	remove bool
+}
+
+// Batch is a set of file writes and removals that are committed atomically:
+// after a crash, either all or none of them are applied.
+type Batch struct {
+	j   *Journal
+	ops []batchOp
+}
+
+// Write adds the writing of data as the json contents of fname to the batch.
+// The data is encoded when the batch is committed.
+func (b *Batch) Write(fname string, data interface{}) {
+	b.ops = append(b.ops, batchOp{fname: fname, data: data})
+}
+
+// Remove adds the removal of fname to the batch. It is not an error if the
+// file does not exist.
+func (b *Batch) Remove(fname string) {
+	b.ops = append(b.ops, batchOp{fname: fname, remove: true})
+}
+
+// Commit commits the batch.
+func (b *Batch) Commit() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+
+	// Encode all data before touching the filesystem.
+	contents := make([][]byte, len(b.ops))
+	for i, op := range b.ops {
+		if op.remove {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(op.data); err != nil {
+			return fmt.Errorf("unable to encode json contents of %s: %w",
+				op.fname, err)
+		}
+		contents[i] = buf.Bytes()
+	}
+
+	jfname := b.j.nextJournalFname()
+	jid := strings.TrimSuffix(filepath.Base(jfname), journalExt)
+	entry := &journalEntry{Ops: make([]journalOp, len(b.ops))}
+	for i, op := range b.ops {
+		entry.Ops[i].Filename = op.fname
+		if !op.remove {
+			dir, base := filepath.Split(op.fname)
+			entry.Ops[i].TempFilename = filepath.Join(dir,
+				fmt.Sprintf(".%s.%s.new", base, jid))
+		}
+	}
+
+	// Record the intent to write the temp files, so that they are removed
+	// if a crash happens before the batch is committed.
+	if err := b.j.writeEntry(jfname, entry); err != nil {
+		return fmt.Errorf("unable to write journal file: %w", err)
+	}
+
+	// Write the temp files.
+	var err error
+	for i, op := range entry.Ops {
+		if op.TempFilename == "" {
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(op.Filename), 0o700); err != nil {
+			break
+		}
+		if err = writeFileSync(op.TempFilename, contents[i]); err != nil {
+			break
+		}
+	}
+
+	// Commit point.
+	if err == nil {
+		entry.Committed = true
+		err = b.j.writeEntry(jfname, entry)
+	}
+	if err != nil {
+		if discardErr := entry.discard(); discardErr != nil {
+			b.j.log.Warnf("Unable to discard temp files of journal "+
+				"file %s: %v", jfname, discardErr)
+		} else if remErr := os.Remove(jfname); remErr != nil {
+			b.j.log.Warnf("Unable to remove journal file %s: %v",
+				jfname, remErr)
+		}
+		return fmt.Errorf("unable to commit batch: %w", err)
+	}
+
+	// Apply the batch. From this point on, the batch is committed and a
+	// failure to apply it is fixed by a future Recover().
+	if err := entry.apply(); err != nil {
+		return fmt.Errorf("unable to apply committed batch: %w", err)
+	}
+	if err := os.Remove(jfname); err != nil {
+		return fmt.Errorf("unable to remove journal file: %w", err)
+	}
+	return syncDir(b.j.dir)
+}
@@ -0,0 +1,98 @@
+package jsonfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/internal/assert"
+	"github.com/companyzero/bisonrelay/internal/testutils"
+)
+
+type journalTestData struct {
+	V int `json:"v"`
+}
+
+// TestJournalBatch tests committing a batch of writes and removals, including
+// removals of files that do not exist.
+func TestJournalBatch(t *testing.T) {
+	dir := testutils.TempTestDir(t, "journal-")
+	j, err := OpenJournal(filepath.Join(dir, "journal"), nil)
+	assert.NilErr(t, err)
+
+	fnameA := filepath.Join(dir, "a.json")
+	fnameB := filepath.Join(dir, "sub", "b.json")
+	fnameC := filepath.Join(dir, "c.json")
+	assert.NilErr(t, Write(fnameC, journalTestData{V: 3}, nil))
+
+	b := j.NewBatch()
+	b.Write(fnameA, journalTestData{V: 1})
+	b.Write(fnameB, journalTestData{V: 2})
+	b.Remove(fnameC)
+	b.Remove(filepath.Join(dir, "missing", "d.json"))
+	assert.NilErr(t, b.Commit())
+
+	var got journalTestData
+	assert.NilErr(t, Read(fnameA, &got))
+	assert.DeepEqual(t, got.V, 1)
+	assert.NilErr(t, Read(fnameB, &got))
+	assert.DeepEqual(t, got.V, 2)
+	assert.ErrorIs(t, Read(fnameC, &got), ErrNotFound)
+
+	// No leftover journal or temp files.
+	entries, err := os.ReadDir(j.dir)
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(entries), 0)
+	temps, err := filepath.Glob(filepath.Join(dir, "*", ".*.new"))
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, len(temps), 0)
+}
+
+// TestJournalRecover tests that interrupted batches are applied or discarded
+// during recovery, depending on whether they were committed.
+func TestJournalRecover(t *testing.T) {
+	dir := testutils.TempTestDir(t, "journal-")
+	jdir := filepath.Join(dir, "journal")
+	j, err := OpenJournal(jdir, nil)
+	assert.NilErr(t, err)
+
+	fnameA := filepath.Join(dir, "a.json")
+	fnameB := filepath.Join(dir, "b.json")
+	assert.NilErr(t, Write(fnameA, journalTestData{V: 1}, nil))
+	assert.NilErr(t, Write(fnameB, journalTestData{V: 1}, nil))
+
+	// Simulate a crash after a batch was committed but before it was
+	// applied and a crash before a second batch was committed.
+	tempA := filepath.Join(dir, ".a.json.1.new")
+	tempB := filepath.Join(dir, ".b.json.2.new")
+	assert.NilErr(t, writeFileSync(tempA, []byte(`{"v":10}`)))
+	assert.NilErr(t, writeFileSync(tempB, []byte(`{"v":20}`)))
+	committed := journalEntry{
+		Ops:       []journalOp{{Filename: fnameA, TempFilename: tempA}},
+		Committed: true,
+	}
+	uncommitted := journalEntry{
+		Ops: []journalOp{{Filename: fnameB, TempFilename: tempB}},
+	}
+	assert.NilErr(t, Write(filepath.Join(jdir, "1"+journalExt), committed, nil))
+	assert.NilErr(t, Write(filepath.Join(jdir, "2"+journalExt), uncommitted, nil))
+
+	n, err := j.Recover()
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, n, 2)
+
+	var got journalTestData
+	assert.NilErr(t, Read(fnameA, &got))
+	assert.DeepEqual(t, got.V, 10)
+	assert.NilErr(t, Read(fnameB, &got))
+	assert.DeepEqual(t, got.V, 1)
+	if _, err := os.Stat(tempB); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("uncommitted temp file was not removed: %v", err)
+	}
+
+	// Recovering again is a no-op.
+	n, err = j.Recover()
+	assert.NilErr(t, err)
+	assert.DeepEqual(t, n, 0)
+}
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/companyzero/bisonrelay/client/clientintf"
 	"github.com/companyzero/bisonrelay/zkidentity"
@@ -25,6 +26,10 @@ type EmbeddedArgs struct {
 	Size     uint64
 	Cost     uint64
 
+	// quote validity (of quotes sent by stores)
+	ExpiresTS int64
+	Refresh   string
+
 	// processed locally
 	LocalFilename string
 
@@ -55,6 +60,12 @@ func (args EmbeddedArgs) String() string {
 	if args.Cost > 0 {
 		parts = append(parts, "cost="+strconv.FormatUint(args.Cost, 10))
 	}
+	if args.ExpiresTS > 0 {
+		parts = append(parts, "expires="+strconv.FormatInt(args.ExpiresTS, 10))
+	}
+	if args.Refresh != "" {
+		parts = append(parts, "refresh="+url.PathEscape(args.Refresh))
+	}
 	if args.Data != nil {
 		parts = append(parts, "data="+base64.StdEncoding.EncodeToString(args.Data))
 	}
@@ -62,6 +73,17 @@ func (args EmbeddedArgs) String() string {
 	return "--embed[" + strings.Join(parts, ",") + "]--"
 }
 
+// IsQuote returns true if the embed is the validity of a quote (for example,
+// of the payment details of an order sent by a store).
+func (args EmbeddedArgs) IsQuote() bool {
+	return args.ExpiresTS > 0
+}
+
+// QuoteExpired returns true if the embed is a quote that expired before now.
+func (args EmbeddedArgs) QuoteExpired(now time.Time) bool {
+	return args.IsQuote() && !now.Before(time.Unix(args.ExpiresTS, 0))
+}
+
 var embedRegexp = regexp.MustCompile(`--embed\[.*?\]--`)
 
 // FindAllStringIndex returns a slice with start and end positions for all
@@ -115,6 +137,11 @@ func ParseEmbedArgs(rawEmbedStr string) EmbeddedArgs {
 			args.Size, _ = strconv.ParseUint(v, 10, 64)
 		case "cost":
 			args.Cost, _ = strconv.ParseUint(v, 10, 64)
+		case "expires":
+			args.ExpiresTS, _ = strconv.ParseInt(v, 10, 64)
+		case "refresh":
+			// Ignore the error and leave refresh empty.
+			args.Refresh, _ = url.PathUnescape(v)
 		case "localfilename":
 			args.LocalFilename = v
 		}
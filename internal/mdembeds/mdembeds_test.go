@@ -3,6 +3,7 @@ package mdembeds
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/companyzero/bisonrelay/zkidentity"
 )
@@ -51,6 +52,11 @@ func TestReplaceEmbeds(t *testing.T) {
 		src:      "start --embed[alt=alt,download=broken]-- end",
 		wantArgs: []EmbeddedArgs{{Alt: "alt"}},
 		wantDst:  "start xxx end",
+	}, {
+		name:     "quote validity",
+		src:      "start --embed[alt=order%2012,expires=1700000000,refresh=%2Forder%2F12%2Frequote]-- end",
+		wantArgs: []EmbeddedArgs{{Alt: "order 12", ExpiresTS: 1700000000, Refresh: "/order/12/requote"}},
+		wantDst:  "start xxx end",
 	}}
 
 	for _, tc := range tests {
@@ -80,3 +86,30 @@ func TestReplaceEmbeds(t *testing.T) {
 		})
 	}
 }
+
+// TestQuoteEmbed tests encoding and decoding the validity of quotes.
+func TestQuoteEmbed(t *testing.T) {
+	expires := time.Unix(1700000000, 0)
+	args := EmbeddedArgs{
+		Alt:       "order",
+		ExpiresTS: expires.Unix(),
+		Refresh:   "/order/12/requote",
+	}
+	got := ParseEmbedArgs(args.String())
+	if !reflect.DeepEqual(got, args) {
+		t.Fatalf("unexpected args: got %#v, want %#v", got, args)
+	}
+
+	if !got.IsQuote() {
+		t.Fatalf("embed is not a quote")
+	}
+	if got.QuoteExpired(expires.Add(-time.Second)) {
+		t.Fatalf("quote expired before its expiration")
+	}
+	if !got.QuoteExpired(expires) {
+		t.Fatalf("quote not expired at its expiration")
+	}
+	if (EmbeddedArgs{Alt: "alt"}).QuoteExpired(expires) {
+		t.Fatalf("embed without validity is expired")
+	}
+}
@@ -21,6 +21,7 @@ type MockPayClient struct {
 	getInvoice     func(int64, func(int64)) (string, error)
 	decodeInvoice  func(string) (clientintf.DecodedInvoice, error)
 	trackInvoice   func(string, int64) (int64, error)
+	estimateFee    func(string, int64) (int64, error)
 }
 
 func (pc *MockPayClient) PayScheme() string {
@@ -136,3 +137,55 @@ func (pc *MockPayClient) TrackInvoice(ctx context.Context, inv string, minMAtoms
 	}
 	return 0, nil
 }
+
+func (pc *MockPayClient) HookEstimatePaymentFee(hook func(string, int64) (int64, error)) {
+	pc.mtx.Lock()
+	pc.estimateFee = hook
+	pc.mtx.Unlock()
+}
+
+func (pc *MockPayClient) EstimatePaymentFee(_ context.Context, invoice string, amount int64) (int64, error) {
+	pc.mtx.Lock()
+	hook := pc.estimateFee
+	pc.mtx.Unlock()
+	if hook != nil {
+		return hook(invoice, amount)
+	}
+	return 0, nil
+}
+
+// MockKeysendPayClient is a MockPayClient that also fulfills the
+// [clientintf.KeysendPaymentClient] interface. It is used for tests.
+type MockKeysendPayClient struct {
+	*MockPayClient
+
+	mtx        sync.Mutex
+	payKeysend func(string, int64) (int64, error)
+}
+
+func (pc *MockKeysendPayClient) HookPayKeysend(hook func(string, int64) (int64, error)) {
+	pc.mtx.Lock()
+	pc.payKeysend = hook
+	pc.mtx.Unlock()
+}
+
+func (pc *MockKeysendPayClient) PayKeysend(_ context.Context, destNode string,
+	amountMAtoms, _ int64, _ map[uint64][]byte) (int64, error) {
+
+	pc.mtx.Lock()
+	hook := pc.payKeysend
+	pc.mtx.Unlock()
+	if hook != nil {
+		return hook(destNode, amountMAtoms)
+	}
+	return 0, nil
+}
+
+// TrackKeysendPayments blocks until the context is canceled, because the mock
+// client does not receive keysend payments.
+func (pc *MockKeysendPayClient) TrackKeysendPayments(ctx context.Context,
+	_ func(clientintf.ReceivedKeysend)) error {
+
+	<-ctx.Done()
+	return ctx.Err()
+}
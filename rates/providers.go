@@ -0,0 +1,182 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Quote is the price of DCR and BTC in a fiat currency.
+type Quote struct {
+	DCRPrice float64
+	BTCPrice float64
+}
+
+// Provider is a backend that fetches exchange rates.
+type Provider interface {
+	// Name is the name of the provider, used in logs.
+	Name() string
+
+	// Fetch fetches the current quotes in the given currencies. Currencies
+	// not supported by the provider are omitted from the result.
+	Fetch(ctx context.Context, c *http.Client, currencies []string) (map[string]Quote, error)
+}
+
+// DefaultProviders returns the providers used when none are specified in the
+// config, in order of preference.
+func DefaultProviders() []Provider {
+	return []Provider{DcrData{}, Bittrex{}, CoinGecko{}}
+}
+
+// getRaw fetches the contents of the given URL.
+func getRaw(ctx context.Context, c *http.Client, exchangeAPI string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		exchangeAPI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new http request: %v", err)
+	}
+	req.Header.Del("User-Agent")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rate: %v", err)
+	}
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rate response: %v", err)
+	}
+	return b, nil
+}
+
+// hasCurrency returns true if the currency is in the list.
+func hasCurrency(currencies []string, currency string) bool {
+	for _, c := range currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// DcrData fetches USD rates from dcrdata.
+type DcrData struct{}
+
+func (DcrData) Name() string { return "dcrdata" }
+
+func (DcrData) Fetch(ctx context.Context, c *http.Client, currencies []string) (map[string]Quote, error) {
+	if !hasCurrency(currencies, USD) {
+		return nil, nil
+	}
+
+	dcrDataExchange := struct {
+		DCRPrice float64 `json:"dcrPrice"`
+		BTCPrice float64 `json:"btcPrice"`
+	}{}
+
+	const apiURL = "https://explorer.dcrdata.org/api/exchangerate"
+	b, err := getRaw(ctx, c, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &dcrDataExchange); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rate: %v", err)
+	}
+
+	return map[string]Quote{USD: {
+		DCRPrice: dcrDataExchange.DCRPrice,
+		BTCPrice: dcrDataExchange.BTCPrice,
+	}}, nil
+}
+
+// Bittrex fetches USD rates from bittrex.
+type Bittrex struct{}
+
+func (Bittrex) Name() string { return "bittrex" }
+
+func (Bittrex) Fetch(ctx context.Context, c *http.Client, currencies []string) (map[string]Quote, error) {
+	if !hasCurrency(currencies, USD) {
+		return nil, nil
+	}
+
+	bittrexExchange := struct {
+		LastTradeRate string `json:"lastTradeRate"`
+	}{}
+
+	const dcrAPI = "https://api.bittrex.com/v3/markets/DCR-USD/ticker"
+	b, err := getRaw(ctx, c, dcrAPI)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &bittrexExchange); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rate: %w", err)
+	}
+	dcrPrice, err := strconv.ParseFloat(bittrexExchange.LastTradeRate, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rate: %w", err)
+	}
+
+	const btcAPI = "https://api.bittrex.com/v3/markets/BTC-USDT/ticker"
+	b, err = getRaw(ctx, c, btcAPI)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &bittrexExchange); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rate: %v", err)
+	}
+	btcPrice, err := strconv.ParseFloat(bittrexExchange.LastTradeRate, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rate: %w", err)
+	}
+
+	return map[string]Quote{USD: {DCRPrice: dcrPrice, BTCPrice: btcPrice}}, nil
+}
+
+// CoinGecko fetches rates in all supported currencies from coingecko.
+type CoinGecko struct{}
+
+func (CoinGecko) Name() string { return "coingecko" }
+
+func (CoinGecko) Fetch(ctx context.Context, c *http.Client, currencies []string) (map[string]Quote, error) {
+	var vs []string
+	for _, cur := range currencies {
+		if IsSupportedCurrency(cur) {
+			vs = append(vs, strings.ToLower(cur))
+		}
+	}
+	if len(vs) == 0 {
+		return nil, nil
+	}
+
+	apiURL := "https://api.coingecko.com/api/v3/simple/price?ids=decred,bitcoin&vs_currencies=" +
+		url.QueryEscape(strings.Join(vs, ","))
+	b, err := getRaw(ctx, c, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	var prices struct {
+		Decred  map[string]float64 `json:"decred"`
+		Bitcoin map[string]float64 `json:"bitcoin"`
+	}
+	if err = json.Unmarshal(b, &prices); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rate: %v", err)
+	}
+
+	res := make(map[string]Quote, len(vs))
+	for _, cur := range vs {
+		dcrPrice, ok := prices.Decred[cur]
+		if !ok || dcrPrice <= 0 {
+			continue
+		}
+		res[strings.ToUpper(cur)] = Quote{
+			DCRPrice: dcrPrice,
+			BTCPrice: prices.Bitcoin[cur],
+		}
+	}
+	return res, nil
+}
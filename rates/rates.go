@@ -2,34 +2,148 @@ package rates
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/decred/slog"
 )
 
+// Supported fiat currencies.
+const (
+	USD = "USD"
+	EUR = "EUR"
+	GBP = "GBP"
+	BRL = "BRL"
+)
+
+var currencySymbols = map[string]string{
+	USD: "$",
+	EUR: "€",
+	GBP: "£",
+	BRL: "R$",
+}
+
+// IsSupportedCurrency returns true if the currency is one of the supported
+// fiat currencies.
+func IsSupportedCurrency(currency string) bool {
+	_, ok := currencySymbols[currency]
+	return ok
+}
+
+// CurrencySymbol returns the symbol of the currency, or an empty string if
+// the currency is not supported.
+func CurrencySymbol(currency string) string {
+	return currencySymbols[currency]
+}
+
+// DefaultMaxAge is the age after which fetched rates are considered stale,
+// when one is not specified in the config.
+const DefaultMaxAge = time.Hour
+
+var (
+	// ErrUnknownCurrency is returned when no rate was fetched for a
+	// currency.
+	ErrUnknownCurrency = errors.New("no exchange rate for currency")
+
+	// ErrStaleRate is returned when the last rate fetched for a currency
+	// is older than the max age of rates.
+	ErrStaleRate = errors.New("stale exchange rate")
+)
+
 type Config struct {
 	HTTPClient *http.Client
 	Log        slog.Logger
+
+	// Providers are the backends used to fetch rates, in order of
+	// preference. Defaults to DefaultProviders().
+	Providers []Provider
+
+	// Currencies are the fiat currencies to fetch rates for. USD rates are
+	// always fetched.
+	Currencies []string
+
+	// MaxAge is the age after which fetched rates are considered stale.
+	// Defaults to DefaultMaxAge.
+	MaxAge time.Duration
+}
+
+// Rate is the last rate fetched for a currency.
+type Rate struct {
+	Quote
+	Source  string
+	Updated time.Time
 }
 
 type Rates struct {
 	cfg Config
 
-	mtx      sync.Mutex
-	dcrPrice float64
-	btcPrice float64
+	mtx   sync.Mutex
+	rates map[string]Rate
 }
 
 func New(cfg Config) *Rates {
+	if len(cfg.Providers) == 0 {
+		cfg.Providers = DefaultProviders()
+	}
+	currencies := []string{USD}
+	for _, cur := range cfg.Currencies {
+		cur = strings.ToUpper(cur)
+		if !hasCurrency(currencies, cur) {
+			currencies = append(currencies, cur)
+		}
+	}
+	cfg.Currencies = currencies
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = DefaultMaxAge
+	}
 	return &Rates{
-		cfg: cfg,
+		cfg:   cfg,
+		rates: make(map[string]Rate),
+	}
+}
+
+// fetch fetches the rates of all currencies, trying each provider in turn
+// for the currencies not fetched from the previous ones.
+func (r *Rates) fetch(ctx context.Context, requestTimeout time.Duration) error {
+	missing := r.cfg.Currencies
+	var errs []string
+	for _, p := range r.cfg.Providers {
+		rctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		quotes, err := p.Fetch(rctx, r.cfg.HTTPClient, missing)
+		cancel()
+		if err != nil {
+			r.cfg.Log.Debugf("Unable to fetch rate from %s: %v", p.Name(), err)
+			errs = append(errs, fmt.Sprintf("%s: %v", p.Name(), err))
+			continue
+		}
+
+		now := time.Now()
+		var stillMissing []string
+		r.mtx.Lock()
+		for _, cur := range missing {
+			q, ok := quotes[cur]
+			if !ok || q.DCRPrice <= 0 {
+				stillMissing = append(stillMissing, cur)
+				continue
+			}
+			r.rates[cur] = Rate{Quote: q, Source: p.Name(), Updated: now}
+			r.cfg.Log.Infof("Current %s exchange rate: DCR:%0.2f BTC:%0.2f %s",
+				p.Name(), q.DCRPrice, q.BTCPrice, cur)
+		}
+		r.mtx.Unlock()
+		missing = stillMissing
+		if len(missing) == 0 {
+			return nil
+		}
+	}
+	if len(errs) == 0 {
+		errs = append(errs, "no provider for currencies "+strings.Join(missing, ", "))
 	}
+	return errors.New(strings.Join(errs, "; "))
 }
 
 func (r *Rates) Run(ctx context.Context) {
@@ -43,7 +157,6 @@ func (r *Rates) Run(ctx context.Context) {
 
 	var failedTries int
 
-	var err error
 	for {
 		select {
 		case <-ctx.Done():
@@ -51,35 +164,19 @@ func (r *Rates) Run(ctx context.Context) {
 		case <-t.C:
 			t.Stop()
 
-			// Try from dcrdata.
-			rctx, cancel := context.WithTimeout(ctx, requestTimeout)
-			if err = r.dcrData(rctx); err == nil {
-				cancel()
-				failedTries = 0
-				t.Reset(longTimeout)
-				continue
-			}
-			cancel()
-			r.cfg.Log.Debugf("Unable to fetch rate from dcrdata: %v", err)
-
-			// Try from bittrex.
-			rctx, cancel = context.WithTimeout(ctx, requestTimeout)
-			if err = r.bittrex(rctx); err == nil {
-				cancel()
+			err := r.fetch(ctx, requestTimeout)
+			if err == nil {
 				failedTries = 0
 				t.Reset(longTimeout)
 				continue
 			}
-			cancel()
-			r.cfg.Log.Debugf("Unable to fetch rate from bittrex: %v", err)
 
 			// Only log these at a higher warning level once after
 			// the rate has been successfully fetched. This prevents
 			// spam in the UI.
 			failedTries++
 			if failedTries == triesBeforeErr {
-				r.cfg.Log.Warnf("Unable to fetch rate from dcrdata: %v", err)
-				r.cfg.Log.Warnf("Unable to fetch rate from bittrex: %v", err)
+				r.cfg.Log.Warnf("Unable to fetch rates: %v", err)
 				r.cfg.Log.Errorf("Unable to fetch recent exchange rate. Will keep retrying.")
 			}
 			t.Reset(shortTimeout)
@@ -90,107 +187,57 @@ func (r *Rates) Run(ctx context.Context) {
 // Get returns the last fetched USD/DCR and USD/BTC prices.
 func (r *Rates) Get() (float64, float64) {
 	r.mtx.Lock()
-	dcrPrice, btcPrice := r.dcrPrice, r.btcPrice
+	rate := r.rates[USD]
 	r.mtx.Unlock()
 
-	return dcrPrice, btcPrice
+	return rate.DCRPrice, rate.BTCPrice
 }
 
-// Set manually sets the USD/DCR and USD/BTC prices.
+// Set manually sets the USD/DCR and USD/BTC prices. Manually set prices never
+// become stale.
 func (r *Rates) Set(dcrPrice, btcPrice float64) {
-	r.cfg.Log.Infof("Setting manual exchange rate: DCR:%0.2f BTC:%0.2f",
-		dcrPrice, btcPrice)
-
-	r.mtx.Lock()
-	r.dcrPrice = dcrPrice
-	r.btcPrice = btcPrice
-	r.mtx.Unlock()
+	r.SetCurrency(USD, dcrPrice, btcPrice)
 }
 
-func (r *Rates) dcrData(ctx context.Context) error {
-	dcrDataExchange := struct {
-		DCRPrice float64 `json:"dcrPrice"`
-		BTCPrice float64 `json:"btcPrice"`
-	}{}
-
-	const apiURL = "https://explorer.dcrdata.org/api/exchangerate"
-	b, err := r.getRaw(ctx, apiURL)
-	if err != nil {
-		return err
-	}
-	if err = json.Unmarshal(b, &dcrDataExchange); err != nil {
-		return fmt.Errorf("failed to decode exchange rate: %v", err)
-	}
-
-	r.cfg.Log.Infof("Current dcrdata exchange rate: DCR:%0.2f BTC:%0.2f",
-		dcrDataExchange.DCRPrice, dcrDataExchange.BTCPrice)
+// SetCurrency manually sets the DCR and BTC prices in the given currency.
+// Manually set prices never become stale.
+func (r *Rates) SetCurrency(currency string, dcrPrice, btcPrice float64) {
+	r.cfg.Log.Infof("Setting manual exchange rate: DCR:%0.2f BTC:%0.2f %s",
+		dcrPrice, btcPrice, currency)
 
 	r.mtx.Lock()
-	r.dcrPrice = dcrDataExchange.DCRPrice
-	r.btcPrice = dcrDataExchange.BTCPrice
+	r.rates[currency] = Rate{
+		Quote:   Quote{DCRPrice: dcrPrice, BTCPrice: btcPrice},
+		Source:  "manual",
+		Updated: time.Now(),
+	}
 	r.mtx.Unlock()
-
-	return nil
 }
 
-func (r *Rates) bittrex(ctx context.Context) error {
-	bittrexExchange := struct {
-		LastTradeRate string `json:"lastTradeRate"`
-	}{}
-
-	const dcrAPI = "https://api.bittrex.com/v3/markets/DCR-USD/ticker"
-	b, err := r.getRaw(ctx, dcrAPI)
-	if err != nil {
-		return err
-	}
-	if err = json.Unmarshal(b, &bittrexExchange); err != nil {
-		return fmt.Errorf("failed to decode exchange rate: %w", err)
-	}
-	dcrPrice, err := strconv.ParseFloat(bittrexExchange.LastTradeRate, 64)
-	if err != nil {
-		return fmt.Errorf("failed to parse exchange rate: %w", err)
-	}
-
-	const btcAPI = "https://api.bittrex.com/v3/markets/BTC-USDT/ticker"
-	b, err = r.getRaw(ctx, btcAPI)
-	if err != nil {
-		return err
-	}
-	if err = json.Unmarshal(b, &bittrexExchange); err != nil {
-		return fmt.Errorf("failed to decode exchange rate: %v", err)
-	}
-	btcPrice, err := strconv.ParseFloat(bittrexExchange.LastTradeRate, 64)
-	if err != nil {
-		return fmt.Errorf("failed to parse exchange rate: %w", err)
-	}
-
-	r.cfg.Log.Infof("Current bittrex exchange rate: DCR:%0.2f BTC:%0.2f",
-		dcrPrice, btcPrice)
-
+// Rate returns the last rate fetched for the currency. It returns
+// ErrStaleRate (along with the rate) if the rate is older than the max age of
+// rates.
+func (r *Rates) Rate(currency string) (Rate, error) {
 	r.mtx.Lock()
-	r.dcrPrice = dcrPrice
-	r.btcPrice = btcPrice
+	rate, ok := r.rates[currency]
 	r.mtx.Unlock()
 
-	return nil
-}
-
-func (r *Rates) getRaw(ctx context.Context, exchangeAPI string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
-		exchangeAPI, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new http request: %v", err)
+	switch {
+	case !ok || rate.DCRPrice <= 0:
+		return rate, fmt.Errorf("%w %s", ErrUnknownCurrency, currency)
+	case rate.Source != "manual" && time.Since(rate.Updated) > r.cfg.MaxAge:
+		return rate, fmt.Errorf("%w: %s rate last updated at %s",
+			ErrStaleRate, currency, rate.Updated.Format(time.RFC3339))
 	}
-	req.Header.Del("User-Agent")
+	return rate, nil
+}
 
-	resp, err := r.cfg.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get exchange rate: %v", err)
-	}
-	b, err := io.ReadAll(resp.Body)
-	resp.Body.Close()
+// DCRPrice returns the price of one DCR in the currency. It fails if the rate
+// of the currency is unknown or stale.
+func (r *Rates) DCRPrice(currency string) (float64, error) {
+	rate, err := r.Rate(currency)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read exchange rate response: %v", err)
+		return 0, err
 	}
-	return b, nil
+	return rate.DCRPrice, nil
 }
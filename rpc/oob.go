@@ -23,6 +23,11 @@ type InviteFunds struct {
 	PrivateKey string `json:"private_key"`
 	HeightHint uint32 `json:"height_hint"`
 	Address    string `json:"address"`
+
+	// Amount is the amount (in atoms) sent to the funds address. This is
+	// only informative: the actual amount is only known when the funds
+	// are redeemed. Invites created by older clients do not set it.
+	Amount int64 `json:"amount,omitempty"`
 }
 
 // OOBPublicIdentityInvite is an unencrypted OOB command which contains all
@@ -0,0 +1,95 @@
+package rpc
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// PublishedResource is a public resource (for example, the index page of a
+// store) that a user published to the server, so that the server may serve it
+// to other users while its owner is offline. The resource is signed by its
+// owner, which allows fetchers to verify its authenticity.
+//
+// OwnerIdentity is the public identity of the owner. It allows the server to
+// verify that SigKey is the signing key of Owner, such that users cannot
+// publish resources under the ID of other users.
+type PublishedResource struct {
+	Owner         zkidentity.ShortID                   `json:"owner"`
+	OwnerIdentity zkidentity.PublicIdentity            `json:"owner_identity"`
+	SigKey        zkidentity.FixedSizeEd25519PublicKey `json:"sig_key"`
+	Path          []string                             `json:"path"`
+	Data          []byte                               `json:"data"`
+	Timestamp     int64                                `json:"timestamp"`
+	Signature     zkidentity.FixedSizeSignature        `json:"signature"`
+}
+
+// PathKey returns the path of the resource as a single string.
+func (r *PublishedResource) PathKey() string {
+	return PublishedResourcePathKey(r.Path)
+}
+
+// SignedHash returns the hash of the resource that is signed by its owner.
+func (r *PublishedResource) SignedHash() []byte {
+	h := sha256.New()
+	h.Write(r.Owner[:])
+	h.Write(r.SigKey[:])
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(len(r.Path)))
+	h.Write(b[:])
+	for _, p := range r.Path {
+		binary.BigEndian.PutUint64(b[:], uint64(len(p)))
+		h.Write(b[:])
+		h.Write([]byte(p))
+	}
+	binary.BigEndian.PutUint64(b[:], uint64(r.Timestamp))
+	h.Write(b[:])
+	h.Write(r.Data)
+	return h.Sum(nil)
+}
+
+// VerifySignature returns true if the resource is signed by its SigKey. Note
+// that this does not verify that SigKey is the signing key of the Owner, which
+// requires knowing the public identity of the owner.
+func (r *PublishedResource) VerifySignature() bool {
+	return ed25519.Verify(r.SigKey[:], r.SignedHash(), r.Signature[:])
+}
+
+// VerifyOwner returns true if OwnerIdentity is a valid identity for Owner and
+// SigKey is its signing key.
+func (r *PublishedResource) VerifyOwner() bool {
+	id := &r.OwnerIdentity
+	return id.Identity == r.Owner && id.SigKey == r.SigKey &&
+		id.Verify() && id.VerifyIdentity()
+}
+
+// PublishedResourcePathKey returns the given resource path as a single string.
+func PublishedResourcePathKey(path []string) string {
+	return strings.Join(path, "/")
+}
+
+// PublishResource asks the server to host the resource, replacing any
+// previously published resource with the same owner and path. A resource with
+// empty Data removes the previously published resource.
+type PublishResource struct {
+	Resource PublishedResource
+}
+
+type PublishResourceReply struct {
+	Error string
+}
+
+// FetchPublishedResource fetches a resource published to the server by the
+// given owner.
+type FetchPublishedResource struct {
+	Owner zkidentity.ShortID
+	Path  []string
+}
+
+type FetchPublishedResourceReply struct {
+	Resource *PublishedResource
+	Error    string
+}
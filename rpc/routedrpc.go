@@ -9,11 +9,13 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/companyzero/bisonrelay/ratchet"
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/klauspost/compress/zstd"
 )
 
 // Header that describes the payload that follows.
@@ -29,8 +31,48 @@ const (
 
 	// Use NoCompression by default
 	RMDefaultCompressionLevel = zlib.NoCompression
+
+	// RMZstdCompressionThreshold is the minimum size of an encoded RM
+	// payload for it to be compressed with zstd (when the remote client
+	// supports it). Smaller payloads do not benefit from it.
+	RMZstdCompressionThreshold = 1024
+
+	// rmZstdWindowSize is the window size used when compressing RMs with
+	// zstd. Decoders reject frames with a window larger than the max
+	// decompressed RM size, so this must be a power of two lower than
+	// maxRMDecompressSize.
+	rmZstdWindowSize = 1 << 20
+)
+
+// zstdMagic is the magic number that starts every zstd frame. Zlib streams
+// never start with this sequence, so it is used to discriminate between the
+// two compression formats when decomposing RMs.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// RMCapabilities is a set of flags that a client advertises to remote clients
+// in the header of the RMs it sends, indicating which optional features it
+// supports.
+type RMCapabilities uint64
+
+const (
+	// RMCapZstdCompression indicates the client can decompose zstd
+	// compressed RMs.
+	RMCapZstdCompression RMCapabilities = 1 << iota
+
+	// RMCapFeatures indicates the client supports exchanging its
+	// features with RMFeatures.
+	RMCapFeatures
 )
 
+// SupportedRMCapabilities are the capabilities supported by this version of
+// the package.
+const SupportedRMCapabilities = RMCapZstdCompression | RMCapFeatures
+
+// Has returns true if all of the flags in caps are set.
+func (c RMCapabilities) Has(caps RMCapabilities) bool {
+	return c&caps == caps
+}
+
 type RMHeader struct {
 	Version   uint64 `json:"version"`
 	Timestamp int64  `json:"timestamp"`
@@ -38,6 +80,9 @@ type RMHeader struct {
 	Tag       uint32 `json:"tag"`
 
 	Signature zkidentity.FixedSizeSignature `json:"signature,omitempty"`
+
+	// Capabilities are the capabilities supported by the sender of the RM.
+	Capabilities RMCapabilities `json:"caps,omitempty"`
 }
 
 // Private message to other client
@@ -51,6 +96,39 @@ const (
 type RMPrivateMessage struct {
 	Mode    uint32 `json:"mode"`
 	Message string `json:"message"`
+
+	// Nonce is a random value chosen by the sender, which makes the ID of
+	// the message unique. It is zero in messages sent by clients that do
+	// not set message IDs.
+	Nonce uint64 `json:"nonce,omitempty"`
+}
+
+// msgIDHash returns the hash of the fields of a message, used as the message
+// ID.
+func msgIDHash(kind string, nonce uint64, fields ...[]byte) zkidentity.ShortID {
+	h := blake256.New()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], nonce)
+	h.Write([]byte(kind))
+	h.Write(b[:])
+	for _, f := range fields {
+		binary.LittleEndian.PutUint64(b[:], uint64(len(f)))
+		h.Write(b[:])
+		h.Write(f)
+	}
+	var id zkidentity.ShortID
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+// MsgID returns the ID of the PM sent by from to the given user. The ID is
+// deterministic: a resent copy of the PM has the same ID, so that receivers
+// may process the PM only once.
+func (pm *RMPrivateMessage) MsgID(from, to zkidentity.ShortID) zkidentity.ShortID {
+	var mode [4]byte
+	binary.LittleEndian.PutUint32(mode[:], pm.Mode)
+	return msgIDHash(RMCPrivateMessage, pm.Nonce, from[:], to[:], mode[:],
+		[]byte(pm.Message))
 }
 
 type RMBlock struct {
@@ -128,6 +206,14 @@ type RMGetInvoice struct {
 	PayScheme  string
 	MilliAtoms uint64
 	Tag        uint32
+
+	// Post is the post being tipped, when the invoice is requested to tip
+	// a post.
+	Post *zkidentity.ShortID `json:"post,omitempty"`
+
+	// Public is set when the tipper consents to being named in the
+	// acknowledgments of tips published by the receiver.
+	Public bool `json:"public,omitempty"`
 }
 
 const RMCInvoice = "invoice"
@@ -138,6 +224,199 @@ type RMInvoice struct {
 	Error   *string `json:"error,omitempty"`
 }
 
+// KeysendTipMemoRecordType is the type of the LN custom record used to attach
+// a KeysendTipMemo to tips sent via keysend.
+const KeysendTipMemoRecordType uint64 = 0x62720001
+
+// KeysendTipMemo is attached to tips sent via keysend (that is, without first
+// requesting an invoice from the receiver) in order to link the payment to
+// the identity of the sender.
+type KeysendTipMemo struct {
+	From       zkidentity.ShortID            `json:"from"`
+	To         zkidentity.ShortID            `json:"to"`
+	MilliAtoms uint64                        `json:"matoms"`
+	Timestamp  int64                         `json:"ts"`
+	Signature  zkidentity.FixedSizeSignature `json:"sig"`
+}
+
+// SignedHash returns the hash of the memo that is signed by the sender.
+func (m *KeysendTipMemo) SignedHash() []byte {
+	h := sha256.New()
+	h.Write(m.From[:])
+	h.Write(m.To[:])
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], m.MilliAtoms)
+	h.Write(b[:])
+	binary.BigEndian.PutUint64(b[:], uint64(m.Timestamp))
+	h.Write(b[:])
+	return h.Sum(nil)
+}
+
+// PaymentProofContextTip is the context of payment proofs for tips.
+const PaymentProofContextTip = "tip"
+
+// paymentProofContextOrderPrefix is the prefix of the context of payment
+// proofs for orders.
+const paymentProofContextOrderPrefix = "order:"
+
+// PaymentProofOrderContext returns the context of payment proofs for the
+// payment of the order with the given id placed by buyer in a store.
+func PaymentProofOrderContext(buyer zkidentity.ShortID, orderID string) string {
+	return paymentProofContextOrderPrefix + buyer.String() + "/" + orderID
+}
+
+// ParsePaymentProofOrderContext parses the context of a payment proof created
+// with PaymentProofOrderContext. It returns false if the context is not the
+// context of the payment of an order.
+func ParsePaymentProofOrderContext(context string) (zkidentity.ShortID, string, bool) {
+	var buyer zkidentity.ShortID
+	if !strings.HasPrefix(context, paymentProofContextOrderPrefix) {
+		return buyer, "", false
+	}
+	context = strings.TrimPrefix(context, paymentProofContextOrderPrefix)
+	i := strings.Index(context, "/")
+	if i < 0 || i == len(context)-1 {
+		return buyer, "", false
+	}
+	if err := buyer.FromString(context[:i]); err != nil {
+		return buyer, "", false
+	}
+	return buyer, context[i+1:], true
+}
+
+// PaymentProof is a proof, signed by the payer, that an LN payment was made to
+// the payee in a given context (for example, a tip or the payment of an order
+// placed in a store). The preimage of the payment proves the payment was
+// completed, while the signature links the payment to the identity of the
+// payer.
+type PaymentProof struct {
+	Payer       zkidentity.ShortID            `json:"payer"`
+	Payee       zkidentity.ShortID            `json:"payee"`
+	PaymentHash []byte                        `json:"payment_hash"`
+	Preimage    []byte                        `json:"preimage"`
+	MilliAtoms  uint64                        `json:"matoms"`
+	Context     string                        `json:"context"`
+	Timestamp   int64                         `json:"ts"`
+	Signature   zkidentity.FixedSizeSignature `json:"sig"`
+}
+
+// SignedHash returns the hash of the proof that is signed by the payer. Each
+// field is prefixed by its length, so that bytes may not be moved between
+// adjacent fields without changing the hash.
+func (p *PaymentProof) SignedHash() []byte {
+	h := sha256.New()
+	var b [8]byte
+	writeField := func(data []byte) {
+		binary.BigEndian.PutUint64(b[:], uint64(len(data)))
+		h.Write(b[:])
+		h.Write(data)
+	}
+	writeUint64 := func(v uint64) {
+		var vb [8]byte
+		binary.BigEndian.PutUint64(vb[:], v)
+		writeField(vb[:])
+	}
+	writeField(p.Payer[:])
+	writeField(p.Payee[:])
+	writeField(p.PaymentHash)
+	writeField(p.Preimage)
+	writeUint64(p.MilliAtoms)
+	writeField([]byte(p.Context))
+	writeUint64(uint64(p.Timestamp))
+	return h.Sum(nil)
+}
+
+// VerifyPreimage returns true if the preimage of the proof corresponds to its
+// payment hash.
+func (p *PaymentProof) VerifyPreimage() bool {
+	hash := sha256.Sum256(p.Preimage)
+	return len(p.PaymentHash) == len(hash) && bytes.Equal(hash[:], p.PaymentHash)
+}
+
+const RMCPaymentProof = "paymentproof"
+
+// RMPaymentProof is sent by the payer of an invoice to the payee, after the
+// payment is completed.
+type RMPaymentProof struct {
+	Proof PaymentProof `json:"proof"`
+}
+
+const RMCFeatures = "features"
+
+// RMFeaturesVersion is the current version of the RMFeatures message.
+const RMFeaturesVersion = 1
+
+// RMFeatures is sent by clients to advertise the features they support to a
+// remote client. This allows new features to degrade gracefully when talking
+// to older clients.
+type RMFeatures struct {
+	Version uint64 `json:"version"`
+
+	// Commands are the RM commands the client can handle.
+	Commands []string `json:"commands"`
+
+	// MaxMsgSize is the max size of messages the client accepts.
+	MaxMsgSize uint64 `json:"max_msg_size"`
+
+	// Extensions are optional (application defined) extensions supported
+	// by the client, along with their versions.
+	Extensions map[string]uint64 `json:"extensions,omitempty"`
+
+	// WantReply is set when the sender wants the remote client to reply
+	// with its own features.
+	WantReply bool `json:"want_reply,omitempty"`
+}
+
+// HasCommand returns true if the features include the given command.
+func (f *RMFeatures) HasCommand(cmd string) bool {
+	for _, c := range f.Commands {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtensionVersion returns the version of the given extension or zero if the
+// extension is not supported.
+func (f *RMFeatures) ExtensionVersion(ext string) uint64 {
+	return f.Extensions[ext]
+}
+
+// BaselineRMCommands are the RM commands supported by every client, including
+// the ones that predate the exchange of features.
+var BaselineRMCommands = []string{
+	RMCPrivateMessage, RMCBlock, RMCInvite, RMCMediateIdentity,
+	RMCTransitiveReset, RMCTransitiveResetReply, RMCGetInvoice,
+	RMCInvoice, RMCTransitiveMessage, RMCTransitiveMessageReply,
+	RMCTransitiveMessageForward, RMCKXSearch, RMCKXSearchReply,
+	RMCKXSuggestion, RMCHandshakeSYN, RMCHandshakeSYNACK,
+	RMCHandshakeACK, RMCGroupInvite, RMCGroupJoin, RMCGroupPart,
+	RMCGroupKill, RMCGroupKick, RMCGroupUpgradeVersion,
+	RMGCGroupUpdateAdmins, RMCGroupList, RMCFTList, RMCFTListReply,
+	RMCFTGet, RMCFTGetReply, RMCFTGetChunk, RMCFTGetChunkReply,
+	RMCFTPayForChunk, RMCFTSendFile, RMCGroupMessage, RMCUser,
+	RMCUserReply, RMCListPosts, RMCListPostsReply, RMCGetPost,
+	RMCPostShare, RMCPostsSubscribe, RMCPostsSubscribeReply,
+	RMCPostsUnsubscribe, RMCPostsUnsubscribeReply, RMCPostGet,
+	RMCPostGetReply, RMCPostStatus, RMCPostStatusReply,
+	RMCFetchResource, RMCFetchResourceReply,
+}
+
+// newerRMCommands are the RM commands added after the exchange of features.
+// Remote users only support them if they advertise them in their features.
+var newerRMCommands = []string{
+	RMCFeatures, RMCPaymentProof,
+}
+
+// SupportedRMCommands returns the RM commands supported by this version of the
+// package.
+func SupportedRMCommands() []string {
+	cmds := make([]string, 0, len(BaselineRMCommands)+len(newerRMCommands))
+	cmds = append(cmds, BaselineRMCommands...)
+	return append(cmds, newerRMCommands...)
+}
+
 const RMCKXSuggestion = "kxsuggestion"
 
 type RMKXSuggestion struct {
@@ -180,11 +459,90 @@ func (rs ResourceStatus) String() string {
 }
 
 const (
-	ResourceStatusOk         = 200
-	ResourceStatusBadRequest = 400
-	ResourceStatusNotFound   = 404
+	ResourceStatusOk              = 200
+	ResourceStatusBadRequest      = 400
+	ResourceStatusForbidden       = 403
+	ResourceStatusNotFound        = 404
+	ResourceStatusTooManyRequests = 429
 )
 
+// ResourceMetaRetryAfter is the meta field of replies with status
+// ResourceStatusTooManyRequests with the number of seconds after which the
+// request may be retried.
+const ResourceMetaRetryAfter = "retry-after"
+
+// ResourceMetaContentType is the meta field of replies with the MIME type of
+// the data of the reply. Replies without it are markdown pages.
+//
+// Requests set it to ResourceContentTypeForm when their data is a submitted
+// form.
+const ResourceMetaContentType = "content-type"
+
+// ResourceContentTypeForm is the content type of requests with the fields of
+// a submitted form as their data, encoded as a JSON object that maps the name
+// of each field to its value.
+const ResourceContentTypeForm = "application/x-brform+json"
+
+// ResourceMetaSignature is the meta field of replies with the hex-encoded
+// signature, by the identity key of the replying user, of the message
+// returned by ResourceReplySignMsg. It allows the requesting user to verify
+// the reply was not altered after being created.
+const ResourceMetaSignature = "signature"
+
+// ResourceMetaOrderSummary is the meta field of replies to requests that place
+// or show an order in a store, with the JSON-encoded OrderSummary of the
+// order.
+const ResourceMetaOrderSummary = "order-summary"
+
+// ResourceMetaOrderSummarySig is the meta field of replies with an order
+// summary, with the hex-encoded signature, by the identity key of the store
+// owner, of the message returned by OrderSummarySignMsg.
+const ResourceMetaOrderSummarySig = "order-summary-sig"
+
+// OrderSummary is the summary of an order placed in a store. It binds the
+// payment details of the order to the order and its buyer, so that buyers can
+// verify the invoice or address shown in a page was created by the store for
+// their order.
+type OrderSummary struct {
+	Buyer      zkidentity.ShortID `json:"buyer"`
+	OrderID    string             `json:"order_id"`
+	TotalAtoms int64              `json:"total_atoms"`
+	PayType    string             `json:"pay_type"`
+	Invoice    string             `json:"invoice"`
+	ExpiresTS  int64              `json:"expires_ts,omitempty"`
+}
+
+// OrderSummarySignMsg returns the message that is signed to create the
+// signature of the JSON-encoded order summary.
+func OrderSummarySignMsg(summaryJSON string) []byte {
+	h := sha256.New()
+	h.Write([]byte("brordersummary-v1"))
+	h.Write([]byte(summaryJSON))
+	return h.Sum(nil)
+}
+
+// ResourceReplySignMsg returns the message that is signed to create the
+// signature of a reply to a request for the given path. The signature covers
+// the path, status, content type and data of the reply.
+func ResourceReplySignMsg(path []string, reply *RMFetchResourceReply) []byte {
+	h := sha256.New()
+	writeField := func(b []byte) {
+		var l [4]byte
+		binary.BigEndian.PutUint32(l[:], uint32(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+	writeField([]byte("brresourcereply-v1"))
+	writeField([]byte(strconv.Itoa(len(path))))
+	for _, p := range path {
+		writeField([]byte(p))
+	}
+	writeField([]byte(reply.Status.String()))
+	writeField([]byte(reply.Meta[ResourceMetaContentType]))
+	writeField(reply.Data)
+	return h.Sum(nil)
+}
+
 const RMCFetchResource = "fetchresource"
 
 type RMFetchResource struct {
@@ -217,13 +575,36 @@ type RMHandshakeSYN struct{}
 type RMHandshakeSYNACK struct{}
 type RMHandshakeACK struct{}
 
+// RMComposeOpts are the options used when composing an RM.
+type RMComposeOpts struct {
+	// ZlibLevel is the zlib compression level used when the RM is not
+	// compressed with zstd.
+	ZlibLevel int
+
+	// Zstd indicates the remote client supports zstd compressed RMs. When
+	// set, payloads larger than RMZstdCompressionThreshold are compressed
+	// with zstd instead of zlib.
+	Zstd bool
+
+	// Capabilities are advertised to the remote client in the RM header.
+	Capabilities RMCapabilities
+}
+
 // ComposeCompressedRM creates a blobified message that has a header and a
 // payload that can then be encrypted and transmitted to the other side. The
 // contents are zlib compressed with the specified level.
 func ComposeCompressedRM(from *zkidentity.FullIdentity, rm interface{}, zlibLevel int) ([]byte, error) {
+	return ComposeRMWithOpts(from, rm, RMComposeOpts{ZlibLevel: zlibLevel})
+}
+
+// ComposeRMWithOpts creates a blobified message that has a header and a
+// payload that can then be encrypted and transmitted to the other side. The
+// contents are compressed according to the passed options.
+func ComposeRMWithOpts(from *zkidentity.FullIdentity, rm interface{}, opts RMComposeOpts) ([]byte, error) {
 	h := RMHeader{
-		Version:   RMHeaderVersion,
-		Timestamp: time.Now().Unix(),
+		Version:      RMHeaderVersion,
+		Timestamp:    time.Now().Unix(),
+		Capabilities: opts.Capabilities,
 	}
 	switch rm.(type) {
 	case RMPrivateMessage:
@@ -271,6 +652,9 @@ func ComposeCompressedRM(from *zkidentity.FullIdentity, rm interface{}, zlibLeve
 	case RMKXSuggestion:
 		h.Command = RMCKXSuggestion
 
+	case RMPaymentProof:
+		h.Command = RMCPaymentProof
+
 	// Handshake
 	case RMHandshakeSYN:
 		h.Command = RMCHandshakeSYN
@@ -383,6 +767,9 @@ func ComposeCompressedRM(from *zkidentity.FullIdentity, rm interface{}, zlibLeve
 	case RMFetchResourceReply:
 		h.Command = RMCFetchResourceReply
 
+	case RMFeatures:
+		h.Command = RMCFeatures
+
 	// Purely transitive commands
 
 	default:
@@ -401,7 +788,12 @@ func ComposeCompressedRM(from *zkidentity.FullIdentity, rm interface{}, zlibLeve
 	// Create payload
 	// Create header, note that the encoder appends a '\n'
 	mb := &bytes.Buffer{}
-	w, err := zlib.NewWriterLevel(mb, zlibLevel)
+	var w io.WriteCloser
+	if opts.Zstd && len(payload) >= RMZstdCompressionThreshold {
+		w, err = zstd.NewWriter(mb, zstd.WithWindowSize(rmZstdWindowSize))
+	} else {
+		w, err = zlib.NewWriterLevel(mb, opts.ZlibLevel)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -436,20 +828,52 @@ func ComposeRM(from *zkidentity.FullIdentity, rm interface{}) ([]byte, error) {
 	return ComposeCompressedRM(from, rm, RMDefaultCompressionLevel)
 }
 
-func DecomposeRM(id *zkidentity.PublicIdentity, mb []byte) (*RMHeader, interface{}, error) {
-	// Decompress everything
+// decompressRM decompresses the given composed RM. Both zstd and zlib
+// compressed RMs are supported.
+func decompressRM(mb []byte) ([]byte, error) {
+	if bytes.HasPrefix(mb, zstdMagic) {
+		zr, err := zstd.NewReader(bytes.NewReader(mb),
+			zstd.WithDecoderMaxMemory(maxRMDecompressSize),
+			zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		// The zstd decoder only returns io.EOF on the read after the
+		// last decompressed data, so allow reading one byte past the
+		// max size to differentiate max sized RMs from larger ones.
+		lr := &limitedReader{R: zr, N: maxRMDecompressSize + 1}
+		all, err := io.ReadAll(lr)
+		zr.Close()
+		if err == nil && len(all) > maxRMDecompressSize {
+			err = errLimitedReaderExhausted
+		}
+		if err != nil {
+			return nil, fmt.Errorf("zstd read err: %w", err)
+		}
+		return all, nil
+	}
+
 	cr, err := zlib.NewReader(bytes.NewReader(mb))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	lr := &limitedReader{R: cr, N: maxRMDecompressSize}
 	all, err := io.ReadAll(lr)
 	closeErr := cr.Close()
 	if err != nil {
-		return nil, nil, fmt.Errorf("zlib read err: %w", err)
+		return nil, fmt.Errorf("zlib read err: %w", err)
 	}
 	if closeErr != nil {
-		return nil, nil, fmt.Errorf("zlib close err: %w", closeErr)
+		return nil, fmt.Errorf("zlib close err: %w", closeErr)
+	}
+	return all, nil
+}
+
+func DecomposeRM(id *zkidentity.PublicIdentity, mb []byte) (*RMHeader, interface{}, error) {
+	// Decompress everything
+	all, err := decompressRM(mb)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	var h RMHeader
@@ -546,6 +970,11 @@ func DecomposeRM(id *zkidentity.PublicIdentity, mb []byte) (*RMHeader, interface
 		err = pmd.Decode(&kxsg)
 		payload = kxsg
 
+	case RMCPaymentProof:
+		var proof RMPaymentProof
+		err = pmd.Decode(&proof)
+		payload = proof
+
 	// Handshake
 	case RMCHandshakeSYN:
 		var hshk RMHandshakeSYN
@@ -732,6 +1161,11 @@ func DecomposeRM(id *zkidentity.PublicIdentity, mb []byte) (*RMHeader, interface
 		err = pmd.Decode(&fetchResReply)
 		payload = fetchResReply
 
+	case RMCFeatures:
+		var features RMFeatures
+		err = pmd.Decode(&features)
+		payload = features
+
 	// Purely transitive commands
 
 	default:
@@ -881,6 +1315,38 @@ type RMGroupList struct {
 	// ExtraAdmins are additional admins. Members[0] is still considered
 	// an admin in version 1 GCs.
 	ExtraAdmins []zkidentity.ShortID `json:"extra_admins"`
+
+	// ReadOnly is set on announcement GCs, where only the admins and the
+	// Posters may send messages. Messages from other members are dropped
+	// by the receiving members.
+	ReadOnly bool `json:"read_only,omitempty"`
+
+	// Posters are the members that may send messages in a read-only GC,
+	// in addition to the admins.
+	Posters []zkidentity.ShortID `json:"posters,omitempty"`
+}
+
+// CanPost returns true if the member may send messages to the GC.
+func (gl *RMGroupList) CanPost(uid zkidentity.ShortID) bool {
+	if !gl.ReadOnly {
+		return true
+	}
+	if len(gl.Members) > 0 && gl.Members[0] == uid {
+		return true
+	}
+	if gl.Version > 0 {
+		for _, id := range gl.ExtraAdmins {
+			if id == uid {
+				return true
+			}
+		}
+	}
+	for _, id := range gl.Posters {
+		if id == uid {
+			return true
+		}
+	}
+	return false
 }
 
 const RMCGroupList = "grouplist"
@@ -891,6 +1357,22 @@ type RMGroupMessage struct {
 	Generation uint64             `json:"generation"` // Generation used
 	Message    string             `json:"message"`    // Actual message
 	Mode       MessageMode        `json:"mode"`       // 0 regular mode, 1 /me
+
+	// Nonce is a random value chosen by the sender, which makes the ID of
+	// the message unique. It is zero in messages sent by clients that do
+	// not set message IDs.
+	Nonce uint64 `json:"nonce,omitempty"`
+}
+
+// MsgID returns the ID of the GC message sent by from. The ID is the same for
+// all members of the GC and for resent copies of the message, so that
+// receivers may process the message only once.
+func (gcm *RMGroupMessage) MsgID(from zkidentity.ShortID) zkidentity.ShortID {
+	var gen, mode [8]byte
+	binary.LittleEndian.PutUint64(gen[:], gcm.Generation)
+	binary.LittleEndian.PutUint64(mode[:], uint64(gcm.Mode))
+	return msgIDHash(RMCGroupMessage, gcm.Nonce, from[:], gcm.ID[:],
+		gen[:], mode[:], []byte(gcm.Message))
 }
 
 const RMCGroupMessage = "groupmessage"
@@ -1227,6 +1709,13 @@ func (pm *PostMetadataStatus) Hash() [32]byte {
 	return b
 }
 
+// MsgID returns the ID of the status update (for example, a comment). As with
+// the IDs of PMs and GC messages, it is deterministic, and is the hash of the
+// status update.
+func (pm *PostMetadataStatus) MsgID() zkidentity.ShortID {
+	return pm.Hash()
+}
+
 const PostMetadataStatusVersion = 1
 
 // IsPostStatus returns true when the map of attributes (possibly) corresponds
@@ -3,9 +3,16 @@ package rpc
 import (
 	"bytes"
 	"compress/zlib"
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/klauspost/compress/zstd"
 )
 
 //func TestComposeRM(t *testing.T) {
@@ -299,6 +306,138 @@ func TestDecomposeLimitsZlibDeflate(t *testing.T) {
 	}
 }
 
+func TestDecomposeLimitsZstd(t *testing.T) {
+	// Figure out the max valid size when we prepend the blob with a valid
+	// RM message.
+	validRM := `{"command":"pm"}` + "\n{}" // Valid header and message.
+	maxSize := maxRMDecompressSize - len(validRM)
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr error
+	}{{
+		name:    "max size decompression",
+		size:    maxSize,
+		wantErr: nil,
+	}, {
+		name:    "one past max size decompression",
+		size:    maxSize + 1,
+		wantErr: errLimitedReaderExhausted,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Generate a small compressed stream that decompresses to a large
+			// message.
+			mb := &bytes.Buffer{}
+			w, err := zstd.NewWriter(mb, zstd.WithEncoderLevel(zstd.SpeedBestCompression),
+				zstd.WithWindowSize(rmZstdWindowSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := w.Write([]byte(validRM)); err != nil {
+				t.Fatal(err)
+			}
+			padding := make([]byte, tc.size)
+			if _, err := w.Write(padding); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			bts := mb.Bytes()
+
+			// Sanity check the generated message is small.
+			if len(bts) > 10*1024 {
+				t.Fatalf("Sanity check failed: compressed message is too large: %d",
+					len(bts))
+			}
+
+			// Attempt to decompress it.
+			_, _, err = DecomposeRM(nil, bts)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestComposeZstdRM asserts that RMs are compressed with zstd only when
+// requested and when larger than the compression threshold, and that both
+// formats can be decomposed.
+func TestComposeZstdRM(t *testing.T) {
+	id, err := zkidentity.New("Alice McMoo", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		msg      string
+		zstd     bool
+		wantZstd bool
+	}{{
+		name:     "small msg without zstd",
+		msg:      "hello",
+		zstd:     false,
+		wantZstd: false,
+	}, {
+		name:     "small msg with zstd",
+		msg:      "hello",
+		zstd:     true,
+		wantZstd: false,
+	}, {
+		name:     "large msg without zstd",
+		msg:      strings.Repeat("hello", RMZstdCompressionThreshold),
+		zstd:     false,
+		wantZstd: false,
+	}, {
+		name:     "large msg with zstd",
+		msg:      strings.Repeat("hello", RMZstdCompressionThreshold),
+		zstd:     true,
+		wantZstd: true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := RMComposeOpts{
+				ZlibLevel:    zlib.BestSpeed,
+				Zstd:         tc.zstd,
+				Capabilities: SupportedRMCapabilities,
+			}
+			rm := RMPrivateMessage{Message: tc.msg}
+			blob, err := ComposeRMWithOpts(id, rm, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotZstd := bytes.HasPrefix(blob, zstdMagic)
+			if gotZstd != tc.wantZstd {
+				t.Fatalf("unexpected zstd compression: got %v, want %v",
+					gotZstd, tc.wantZstd)
+			}
+
+			h, payload, err := DecomposeRM(&id.Public, blob)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !h.Capabilities.Has(RMCapZstdCompression) {
+				t.Fatalf("header does not have zstd capability")
+			}
+			gotPM, ok := payload.(RMPrivateMessage)
+			if !ok {
+				t.Fatalf("unexpected payload type %T", payload)
+			}
+			if gotPM.Message != tc.msg {
+				t.Fatalf("unexpected message: got %q, want %q",
+					gotPM.Message, tc.msg)
+			}
+		})
+	}
+}
+
 func decodeHex32(s string) [32]byte {
 	var res [32]byte
 	n, err := hex.Decode(res[:], []byte(s))
@@ -370,3 +509,248 @@ func TestMetadataStatusHash(t *testing.T) {
 		})
 	}
 }
+
+// TestKeysendTipMemoSignature tests that signatures of keysend tip memos can
+// be verified and that modified memos fail verification.
+func TestKeysendTipMemoSignature(t *testing.T) {
+	alice, err := zkidentity.New("Alice McMalice", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := zkidentity.New("Bob Bobberino", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memo := KeysendTipMemo{
+		From:       alice.Public.Identity,
+		To:         bob.Public.Identity,
+		MilliAtoms: 1000,
+		Timestamp:  1700000000,
+	}
+	memo.Signature = alice.SignMessage(memo.SignedHash())
+	if !alice.Public.VerifyMessage(memo.SignedHash(), memo.Signature) {
+		t.Fatal("signature of memo does not verify")
+	}
+	if bob.Public.VerifyMessage(memo.SignedHash(), memo.Signature) {
+		t.Fatal("signature of memo verified with wrong identity")
+	}
+
+	memo.MilliAtoms += 1
+	if alice.Public.VerifyMessage(memo.SignedHash(), memo.Signature) {
+		t.Fatal("signature of modified memo verified")
+	}
+}
+
+// TestPaymentProofVerify tests that payment proofs can be verified and that
+// modified proofs fail verification.
+func TestPaymentProofVerify(t *testing.T) {
+	alice, err := zkidentity.New("Alice McMalice", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := zkidentity.New("Bob Bobberino", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preimage := []byte("01234567890123456789012345678901")
+	hash := sha256.Sum256(preimage)
+	proof := PaymentProof{
+		Payer:       alice.Public.Identity,
+		Payee:       bob.Public.Identity,
+		PaymentHash: hash[:],
+		Preimage:    preimage,
+		MilliAtoms:  1000,
+		Context:     PaymentProofContextTip,
+		Timestamp:   1700000000,
+	}
+	proof.Signature = alice.SignMessage(proof.SignedHash())
+	if !proof.VerifyPreimage() {
+		t.Fatal("preimage of proof does not verify")
+	}
+	if !alice.Public.VerifyMessage(proof.SignedHash(), proof.Signature) {
+		t.Fatal("signature of proof does not verify")
+	}
+	if bob.Public.VerifyMessage(proof.SignedHash(), proof.Signature) {
+		t.Fatal("signature of proof verified with wrong identity")
+	}
+
+	proof.Context = "order:1"
+	if alice.Public.VerifyMessage(proof.SignedHash(), proof.Signature) {
+		t.Fatal("signature of modified proof verified")
+	}
+
+	// Moving bytes between adjacent fields changes the signed hash.
+	shifted := proof
+	shifted.PaymentHash = append(append([]byte{}, proof.PaymentHash...),
+		proof.Preimage[0])
+	shifted.Preimage = proof.Preimage[1:]
+	if bytes.Equal(shifted.SignedHash(), proof.SignedHash()) {
+		t.Fatal("signed hash of proof with shifted fields did not change")
+	}
+
+	proof.Preimage = []byte("wrong preimage")
+	if proof.VerifyPreimage() {
+		t.Fatal("wrong preimage verified")
+	}
+}
+
+// TestPaymentProofOrderContext tests creating and parsing the context of
+// order payment proofs.
+func TestPaymentProofOrderContext(t *testing.T) {
+	var buyer zkidentity.ShortID
+	buyer[0] = 0x01
+	buyer[31] = 0xff
+
+	ctx := PaymentProofOrderContext(buyer, "00000012")
+	gotBuyer, gotOrder, ok := ParsePaymentProofOrderContext(ctx)
+	if !ok {
+		t.Fatalf("unable to parse context %q", ctx)
+	}
+	if gotBuyer != buyer {
+		t.Fatalf("unexpected buyer: got %s, want %s", gotBuyer, buyer)
+	}
+	if gotOrder != "00000012" {
+		t.Fatalf("unexpected order id: got %q, want %q", gotOrder, "00000012")
+	}
+
+	invalid := []string{
+		PaymentProofContextTip,
+		"order:",
+		"order:" + buyer.String(),
+		"order:" + buyer.String() + "/",
+		"order:xyz/00000012",
+		"tip:" + buyer.String() + "/00000012",
+	}
+	for _, ctx := range invalid {
+		if _, _, ok := ParsePaymentProofOrderContext(ctx); ok {
+			t.Fatalf("parsed invalid context %q", ctx)
+		}
+	}
+}
+
+// TestFeaturesRM tests composing and decomposing features messages.
+func TestFeaturesRM(t *testing.T) {
+	id, err := zkidentity.New("Alice McMoo", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rm := RMFeatures{
+		Version:    RMFeaturesVersion,
+		Commands:   SupportedRMCommands(),
+		MaxMsgSize: MaxMsgSize,
+		Extensions: map[string]uint64{"reactions": 2},
+		WantReply:  true,
+	}
+	blob, err := ComposeRM(id, rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, payload, err := DecomposeRM(&id.Public, blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Command != RMCFeatures {
+		t.Fatalf("unexpected command: got %q, want %q", h.Command,
+			RMCFeatures)
+	}
+	got, ok := payload.(RMFeatures)
+	if !ok {
+		t.Fatalf("unexpected payload type %T", payload)
+	}
+	if !reflect.DeepEqual(got, rm) {
+		t.Fatalf("unexpected features: got %v, want %v", got, rm)
+	}
+	if !got.HasCommand(RMCFeatures) || !got.HasCommand(RMCPrivateMessage) {
+		t.Fatalf("features do not have expected commands")
+	}
+	if got.HasCommand("unknown") {
+		t.Fatalf("features have unexpected command")
+	}
+
+	// Commands added after the features exchange are not in the baseline.
+	baseline := RMFeatures{Commands: BaselineRMCommands}
+	for _, cmd := range []string{RMCFeatures, RMCPaymentProof} {
+		if !got.HasCommand(cmd) {
+			t.Fatalf("features do not have command %q", cmd)
+		}
+		if baseline.HasCommand(cmd) {
+			t.Fatalf("baseline has unexpected command %q", cmd)
+		}
+	}
+	if v := got.ExtensionVersion("reactions"); v != 2 {
+		t.Fatalf("unexpected extension version: got %d, want 2", v)
+	}
+	if v := got.ExtensionVersion("unknown"); v != 0 {
+		t.Fatalf("unexpected extension version: got %d, want 0", v)
+	}
+}
+
+// TestMsgIDs tests that the IDs of PMs and GC messages are deterministic and
+// that they change when any of the fields of the message change.
+func TestMsgIDs(t *testing.T) {
+	from := zkidentity.ShortID{0: 0x01}
+	to := zkidentity.ShortID{0: 0x02}
+	gcID := zkidentity.ShortID{0: 0x03}
+
+	pm := RMPrivateMessage{Message: "hello", Nonce: 0x0102030405060708}
+	gcm := RMGroupMessage{ID: gcID, Generation: 1, Message: "hello",
+		Nonce: 0x0102030405060708}
+
+	// The IDs must not change across versions of the software.
+	wantPMID := "ce656dc6171b02a5606f928cece6709cf022be3f3993b9d67d9fc614b71f051a"
+	wantGCMID := "687123d561b1c3edc0e9ba04387d7ac320d0bb5a513f4437f250f7082a403f10"
+	if got := pm.MsgID(from, to); got.String() != wantPMID {
+		t.Fatalf("unexpected PM id: got %s, want %s", got, wantPMID)
+	}
+	if got := gcm.MsgID(from); got.String() != wantGCMID {
+		t.Fatalf("unexpected GCM id: got %s, want %s", got, wantGCMID)
+	}
+
+	// A decoded copy of the message has the same ID.
+	var pmCopy RMPrivateMessage
+	b, err := json.Marshal(pm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &pmCopy); err != nil {
+		t.Fatal(err)
+	}
+	if pmCopy.MsgID(from, to) != pm.MsgID(from, to) {
+		t.Fatalf("decoded PM has a different id")
+	}
+
+	// Changing any field changes the ID.
+	pmIDs := map[zkidentity.ShortID]string{pm.MsgID(from, to): "original"}
+	addPMID := func(name string, id zkidentity.ShortID) {
+		t.Helper()
+		if other, ok := pmIDs[id]; ok {
+			t.Fatalf("PM id of %q equal to the id of %q", name, other)
+		}
+		pmIDs[id] = name
+	}
+	addPMID("swapped users", pm.MsgID(to, from))
+	addPMID("other nonce", (&RMPrivateMessage{Message: pm.Message, Nonce: 1}).MsgID(from, to))
+	addPMID("other msg", (&RMPrivateMessage{Message: "hello!", Nonce: pm.Nonce}).MsgID(from, to))
+	addPMID("other mode", (&RMPrivateMessage{Message: pm.Message, Nonce: pm.Nonce,
+		Mode: RMPrivateMessageModeMe}).MsgID(from, to))
+
+	gcmIDs := map[zkidentity.ShortID]string{gcm.MsgID(from): "original"}
+	addGCMID := func(name string, f func(gcm *RMGroupMessage), from zkidentity.ShortID) {
+		t.Helper()
+		other := gcm
+		f(&other)
+		id := other.MsgID(from)
+		if prev, ok := gcmIDs[id]; ok {
+			t.Fatalf("GCM id of %q equal to the id of %q", name, prev)
+		}
+		gcmIDs[id] = name
+	}
+	addGCMID("other sender", func(*RMGroupMessage) {}, to)
+	addGCMID("other gc", func(gcm *RMGroupMessage) { gcm.ID = to }, from)
+	addGCMID("other generation", func(gcm *RMGroupMessage) { gcm.Generation = 2 }, from)
+	addGCMID("other msg", func(gcm *RMGroupMessage) { gcm.Message = "hello!" }, from)
+	addGCMID("other nonce", func(gcm *RMGroupMessage) { gcm.Nonce = 1 }, from)
+}
@@ -43,11 +43,26 @@ const (
 	TaggedCmdRouteMessage      = "routemessage"
 	TaggedCmdRouteMessageReply = "routemessagereply"
 
+	TaggedCmdRouteMessageBatch      = "routemessagebatch"
+	TaggedCmdRouteMessageBatchReply = "routemessagebatchreply"
+
 	TaggedCmdSubscribeRoutedMessages      = "subscriberoutedmessages"
 	TaggedCmdSubscribeRoutedMessagesReply = "subscriberoutedmessagesreply"
 
 	TaggedCmdPushRoutedMessage = "pushroutedmessage"
 
+	TaggedCmdFetchDeferredRoutedMessages      = "fetchdeferredroutedmessages"
+	TaggedCmdFetchDeferredRoutedMessagesReply = "fetchdeferredroutedmessagesreply"
+
+	TaggedCmdRegisterWakeup      = "registerwakeup"
+	TaggedCmdRegisterWakeupReply = "registerwakeupreply"
+
+	TaggedCmdPublishResource      = "publishresource"
+	TaggedCmdPublishResourceReply = "publishresourcereply"
+
+	TaggedCmdFetchPublishedResource      = "fetchpublishedresource"
+	TaggedCmdFetchPublishedResourceReply = "fetchpublishedresourcereply"
+
 	// misc
 	MessageModeNormal MessageMode = 0
 	MessageModeMe     MessageMode = 1
@@ -108,10 +123,66 @@ type RouteMessageReply struct {
 	NextInvoice string
 }
 
+// BatchedRouteMessage is an individual RM pushed as part of a
+// RouteMessageBatch.
+type BatchedRouteMessage struct {
+	Rendezvous ratchet.RVPoint
+	Message    []byte
+}
+
+// RouteMessageBatch pushes multiple (usually small) RMs to the server in a
+// single command. A single payment covers the push of the entire batch. The
+// batch may only be sent to servers that advertise support for it through the
+// PropMaxBatchedRMs property.
+type RouteMessageBatch struct {
+	PaidInvoiceID []byte
+	Messages      []BatchedRouteMessage
+}
+
+// RouteMessageBatchReply is the reply to a RouteMessageBatch. Error is set if
+// the entire batch failed. Otherwise, Errors has one entry for each message of
+// the batch (in the same order), which is empty if that message was stored
+// successfully.
+type RouteMessageBatchReply struct {
+	Error       string
+	Errors      []string
+	NextInvoice string
+}
+
+// BatchPayloadSize returns the total payload size of all messages in the
+// batch. This is the size used to calculate the push payment for the batch.
+func (rmb *RouteMessageBatch) BatchPayloadSize() int {
+	var size int
+	for i := range rmb.Messages {
+		size += len(rmb.Messages[i].Message)
+	}
+	return size
+}
+
+// SubscriptionFilter is a filter applied by the server before pushing RMs
+// stored on subscribed RVs to a client.
+type SubscriptionFilter struct {
+	// MaxPushSize is the maximum size of an RM that is automatically
+	// pushed to the client. Larger RMs are announced to the client with a
+	// deferred PushRoutedMessage and are only sent after the client
+	// explicitly requests them. Zero means no limit.
+	MaxPushSize int
+
+	// MinTimestamp is the unix timestamp (in seconds) before which stored
+	// RMs are deferred instead of automatically pushed. Zero means no
+	// limit.
+	MinTimestamp int64 `json:"mints,omitempty"`
+}
+
 type SubscribeRoutedMessages struct {
 	AddRendezvous []ratchet.RVPoint // Add to subscribed RVs
 	DelRendezvous []ratchet.RVPoint // Del from subscribed RVs
 	MarkPaid      []ratchet.RVPoint // Mark paid but do not subscribe
+
+	// Filter, if specified, replaces the filter applied to the session's
+	// subscriptions. Only servers that advertise the PropSubFilters
+	// property support filters.
+	Filter *SubscriptionFilter `json:",omitempty"`
 }
 
 type SubscribeRoutedMessagesReply struct {
@@ -124,6 +195,43 @@ type PushRoutedMessage struct {
 	RV        ratchet.RVPoint
 	Timestamp int64
 	Error     string
+
+	// Deferred is set when the payload was not pushed due to the session's
+	// subscription filter. In this case, Payload is empty and Size is the
+	// size of the stored payload, which may be requested with a
+	// FetchDeferredRoutedMessages command.
+	Deferred bool `json:",omitempty"`
+	Size     int  `json:",omitempty"`
+}
+
+// FetchDeferredRoutedMessages requests the server to push the full payload of
+// RMs that were previously deferred due to the session's subscription filter.
+type FetchDeferredRoutedMessages struct {
+	RVs []ratchet.RVPoint
+}
+
+type FetchDeferredRoutedMessagesReply struct {
+	Error string
+}
+
+// RegisterWakeup registers a push gateway that the server notifies when RMs are
+// stored on RVs the session was subscribed to, after the session goes
+// offline. Only an opaque token is sent to the gateway (never any content or
+// RV). The gateway must be one of those advertised by the server in the
+// PropPushGateways property. An empty Token removes the registration.
+type RegisterWakeup struct {
+	Gateway string
+	Token   string
+}
+
+type RegisterWakeupReply struct {
+	Error string
+}
+
+// WakeupNotification is the body of the HTTP POST request sent by the server to
+// a push gateway.
+type WakeupNotification struct {
+	Token string `json:"token"`
 }
 
 // Acknowledge is sent to acknowledge commands and Error is set if the command
@@ -217,6 +325,31 @@ const (
 	// for them.
 	PropMaxPushInvoices        = "maxpushinvoices"
 	PropMaxPushInvoicesDefault = 8
+
+	// PropMaxBatchedRMs is the maximum number of RMs that may be pushed in
+	// a single RouteMessageBatch command. Servers that do not send this
+	// property (or send it with a value lower than 2) do not support
+	// batched pushes.
+	PropMaxBatchedRMs        = "maxbatchedrms"
+	PropMaxBatchedRMsDefault = 16
+
+	// PropSubFilters is set by servers that support subscription filters
+	// and fetching of deferred RMs.
+	PropSubFilters        = "subfilters"
+	PropSubFiltersDefault = "1"
+
+	// PropPushGateways is a comma separated list of push gateway URLs
+	// that clients may register to be notified (via RegisterWakeup) when
+	// new RMs are stored for them while they are offline. An empty value
+	// means the server does not support wakeup notifications.
+	PropPushGateways = "pushgateways"
+
+	// PropMaxPublishedResourceSize is the max size of the resources users
+	// may publish to the server (via PublishResource) to be served to
+	// other users while they are offline. Servers that do not send this
+	// property (or send it with a value of zero) do not host published
+	// resources.
+	PropMaxPublishedResourceSize = "maxpublishedresourcesize"
 )
 
 var (
@@ -274,6 +407,26 @@ var (
 		Value:    "",
 		Required: false,
 	}
+	DefaultPropMaxBatchedRMs = ServerProperty{
+		Key:      PropMaxBatchedRMs,
+		Value:    strconv.Itoa(PropMaxBatchedRMsDefault),
+		Required: false,
+	}
+	DefaultPropSubFilters = ServerProperty{
+		Key:      PropSubFilters,
+		Value:    PropSubFiltersDefault,
+		Required: false,
+	}
+	DefaultPropPushGateways = ServerProperty{
+		Key:      PropPushGateways,
+		Value:    "",
+		Required: false,
+	}
+	DefaultPropMaxPublishedResourceSize = ServerProperty{
+		Key:      PropMaxPublishedResourceSize,
+		Value:    "0",
+		Required: false,
+	}
 
 	// All properties must exist in this array.
 	SupportedServerProperties = []ServerProperty{
@@ -291,6 +444,10 @@ var (
 
 		// optional
 		DefaultPropServerLNNode,
+		DefaultPropMaxBatchedRMs,
+		DefaultPropSubFilters,
+		DefaultPropPushGateways,
+		DefaultPropMaxPublishedResourceSize,
 	}
 )
 
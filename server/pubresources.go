@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// maxPublishedResourceClockSkew is the max time a published resource may be
+// timestamped in the future.
+const maxPublishedResourceClockSkew = 10 * time.Minute
+
+// publishedResources are the resources published by a single owner.
+type publishedResources struct {
+	// sigKey is the signing key of the owner. Only resources signed with
+	// this key are accepted for the owner, until all of its resources
+	// expire.
+	sigKey    zkidentity.FixedSizeEd25519PublicKey
+	resources map[string]*publishedResource
+}
+
+type publishedResource struct {
+	res     *rpc.PublishedResource
+	expires time.Time
+}
+
+// removeExpired removes the expired resources of the owner. It returns the
+// number and total size of the removed resources.
+func (pr *publishedResources) removeExpired(now time.Time) (int, int) {
+	var count, size int
+	for k, r := range pr.resources {
+		if now.After(r.expires) {
+			delete(pr.resources, k)
+			count++
+			size += len(r.res.Data)
+		}
+	}
+	return count, size
+}
+
+// handlePublishResource handles a client request to host a public resource.
+func (z *ZKS) handlePublishResource(ctx context.Context, msg rpc.Message,
+	r rpc.PublishResource, sc *sessionContext) error {
+
+	var payload rpc.PublishResourceReply
+	if err := z.publishResource(&r.Resource); err != nil {
+		payload.Error = err.Error()
+	} else if len(r.Resource.Data) == 0 {
+		sc.log.Debugf("Removed published resource %q of %s",
+			r.Resource.PathKey(), r.Resource.Owner)
+	} else {
+		sc.log.Debugf("Published resource %q of %s (%d bytes)",
+			r.Resource.PathKey(), r.Resource.Owner, len(r.Resource.Data))
+	}
+
+	sc.writer <- &RPCWrapper{
+		Message: rpc.Message{
+			Command: rpc.TaggedCmdPublishResourceReply,
+			Tag:     msg.Tag,
+		},
+		Payload: payload,
+	}
+	return nil
+}
+
+// publishResource validates and stores (or removes) the published resource.
+func (z *ZKS) publishResource(res *rpc.PublishedResource) error {
+	maxSize := z.settings.MaxPublishedResourceSize
+	switch {
+	case maxSize <= 0:
+		return fmt.Errorf("server does not host published resources")
+	case len(res.Data) > maxSize:
+		return fmt.Errorf("resource too large (%d > %d)", len(res.Data),
+			maxSize)
+	case len(res.Path) == 0:
+		return fmt.Errorf("resource path is empty")
+	case !res.VerifyOwner():
+		return fmt.Errorf("resource not signed by the identity of its owner")
+	case !res.VerifySignature():
+		return fmt.Errorf("invalid resource signature")
+	}
+	now := z.now()
+	if time.Unix(res.Timestamp, 0).After(now.Add(maxPublishedResourceClockSkew)) {
+		return fmt.Errorf("resource timestamp is in the future")
+	}
+
+	key := res.PathKey()
+	z.pubResMtx.Lock()
+	defer z.pubResMtx.Unlock()
+
+	owner := z.pubResources[res.Owner]
+	if owner != nil {
+		_, size := owner.removeExpired(now)
+		z.pubResSize -= size
+		if len(owner.resources) == 0 {
+			owner = nil
+		}
+	}
+	if owner != nil && owner.sigKey != res.SigKey {
+		return fmt.Errorf("resource signed by a different key than the " +
+			"previously published resources")
+	}
+	if owner != nil {
+		if old := owner.resources[key]; old != nil && old.res.Timestamp >= res.Timestamp {
+			return fmt.Errorf("resource is older than the published one")
+		}
+	}
+
+	var oldSize int
+	if owner != nil && owner.resources[key] != nil {
+		oldSize = len(owner.resources[key].res.Data)
+	}
+
+	if len(res.Data) == 0 {
+		if owner != nil {
+			delete(owner.resources, key)
+			z.pubResSize -= oldSize
+		}
+		return nil
+	}
+
+	newTotal := z.pubResSize - oldSize + len(res.Data)
+	if newTotal > z.settings.MaxTotalPublishedResourcesSize {
+		return fmt.Errorf("server storage for published resources is full")
+	}
+
+	if owner == nil {
+		owner = &publishedResources{
+			sigKey:    res.SigKey,
+			resources: make(map[string]*publishedResource),
+		}
+		z.pubResources[res.Owner] = owner
+	}
+	if _, ok := owner.resources[key]; !ok &&
+		len(owner.resources) >= z.settings.MaxPublishedResources {
+		return fmt.Errorf("too many published resources (max %d)",
+			z.settings.MaxPublishedResources)
+	}
+	owner.resources[key] = &publishedResource{
+		res:     res,
+		expires: now.Add(z.settings.PublishedResourceLifetime),
+	}
+	z.pubResSize = newTotal
+	return nil
+}
+
+// handleFetchPublishedResource handles a client request to fetch a resource
+// published by another user.
+func (z *ZKS) handleFetchPublishedResource(ctx context.Context, msg rpc.Message,
+	r rpc.FetchPublishedResource, sc *sessionContext) error {
+
+	var payload rpc.FetchPublishedResourceReply
+	now := z.now()
+	key := rpc.PublishedResourcePathKey(r.Path)
+	z.pubResMtx.Lock()
+	if owner := z.pubResources[r.Owner]; owner != nil {
+		if pr := owner.resources[key]; pr != nil && !now.After(pr.expires) {
+			payload.Resource = pr.res
+		}
+	}
+	z.pubResMtx.Unlock()
+	if payload.Resource == nil {
+		payload.Error = "resource not found"
+	}
+
+	sc.writer <- &RPCWrapper{
+		Message: rpc.Message{
+			Command: rpc.TaggedCmdFetchPublishedResourceReply,
+			Tag:     msg.Tag,
+		},
+		Payload: payload,
+	}
+	return nil
+}
+
+// pubResourcesExpirationLoop periodically removes expired published
+// resources.
+func (z *ZKS) pubResourcesExpirationLoop(ctx context.Context) error {
+	for {
+		select {
+		case <-time.After(time.Hour):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		now := z.now()
+		var count int
+		z.pubResMtx.Lock()
+		for id, owner := range z.pubResources {
+			n, size := owner.removeExpired(now)
+			count += n
+			z.pubResSize -= size
+			if len(owner.resources) == 0 {
+				delete(z.pubResources, id)
+			}
+		}
+		z.pubResMtx.Unlock()
+		if count > 0 {
+			z.log.Debugf("Expired %d published resources", count)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/rpc"
+	"github.com/companyzero/bisonrelay/zkidentity"
+)
+
+// TestPublishResource tests the validation of resources published to the
+// server.
+func TestPublishResource(t *testing.T) {
+	svr := newTestServer(t)
+	svr.settings.MaxPublishedResourceSize = 10
+	svr.settings.MaxPublishedResources = 2
+	svr.settings.MaxTotalPublishedResourcesSize = 100
+
+	alice := zkidentity.MustNew("alice", "alice")
+	mallory := zkidentity.MustNew("mallory", "mallory")
+	ts := time.Now().Unix()
+	newRes := func(signer *zkidentity.FullIdentity, path string, data string) *rpc.PublishedResource {
+		res := &rpc.PublishedResource{
+			Owner:         alice.Public.Identity,
+			OwnerIdentity: signer.Public,
+			SigKey:        signer.Public.SigKey,
+			Path:          []string{path},
+			Data:          []byte(data),
+			Timestamp:     ts,
+		}
+		res.Signature = signer.SignMessage(res.SignedHash())
+		ts++
+		return res
+	}
+	assertPublished := func(path string, want string) {
+		t.Helper()
+		owner := svr.pubResources[alice.Public.Identity]
+		var got string
+		if owner != nil && owner.resources[path] != nil {
+			got = string(owner.resources[path].res.Data)
+		}
+		if got != want {
+			t.Fatalf("unexpected data of %q: got %q, want %q", path,
+				got, want)
+		}
+	}
+
+	if err := svr.publishResource(newRes(alice, "index", "hello")); err != nil {
+		t.Fatal(err)
+	}
+	assertPublished("index", "hello")
+
+	// Replacing with a newer version works.
+	newer := newRes(alice, "index", "hello2")
+	if err := svr.publishResource(newer); err != nil {
+		t.Fatal(err)
+	}
+	assertPublished("index", "hello2")
+
+	// Replaying an older version fails.
+	older := newRes(alice, "index", "old")
+	older.Timestamp = newer.Timestamp - 10
+	older.Signature = alice.SignMessage(older.SignedHash())
+	if err := svr.publishResource(older); err == nil {
+		t.Fatal("expected error publishing older resource")
+	}
+
+	// Invalid signatures, resources signed by other keys and resources
+	// that are too large are rejected.
+	invalidSig := newRes(alice, "index", "bad")
+	invalidSig.Data = []byte("changed")
+
+	// Mallory attempts to publish under alice's ID, either with alice's
+	// identity or with a tampered copy of it with mallory's key.
+	forgedID := newRes(mallory, "index", "forged")
+	forgedID.OwnerIdentity = alice.Public
+	forgedID.Signature = mallory.SignMessage(forgedID.SignedHash())
+	tamperedID := newRes(mallory, "index", "forged")
+	tamperedID.OwnerIdentity = alice.Public
+	tamperedID.OwnerIdentity.SigKey = mallory.Public.SigKey
+	tamperedID.Signature = mallory.SignMessage(tamperedID.SignedHash())
+	tests := []*rpc.PublishedResource{
+		invalidSig,
+		newRes(mallory, "index", "forged"),
+		forgedID,
+		tamperedID,
+		newRes(alice, "index", "01234567890"),
+	}
+	for i, res := range tests {
+		if err := svr.publishResource(res); err == nil {
+			t.Fatalf("%d: expected error publishing resource", i)
+		}
+		assertPublished("index", "hello2")
+	}
+
+	// The number of resources is limited.
+	if err := svr.publishResource(newRes(alice, "other", "x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := svr.publishResource(newRes(alice, "third", "x")); err == nil {
+		t.Fatal("expected error publishing too many resources")
+	}
+
+	// Publishing empty data removes the resource.
+	if err := svr.publishResource(newRes(alice, "index", "")); err != nil {
+		t.Fatal(err)
+	}
+	assertPublished("index", "")
+	if svr.pubResSize != 1 {
+		t.Fatalf("unexpected total size: got %d, want 1", svr.pubResSize)
+	}
+}
+
+// TestPublishResourceTotalSize tests that the total size of the resources
+// published by all users is limited.
+func TestPublishResourceTotalSize(t *testing.T) {
+	svr := newTestServer(t)
+	svr.settings.MaxPublishedResourceSize = 10
+	svr.settings.MaxPublishedResources = 10
+	svr.settings.MaxTotalPublishedResourcesSize = 25
+
+	ts := time.Now().Unix()
+	publish := func(id *zkidentity.FullIdentity, path string, size int) error {
+		res := &rpc.PublishedResource{
+			Owner:         id.Public.Identity,
+			OwnerIdentity: id.Public,
+			SigKey:        id.Public.SigKey,
+			Path:          []string{path},
+			Data:          make([]byte, size),
+			Timestamp:     ts,
+		}
+		res.Signature = id.SignMessage(res.SignedHash())
+		ts++
+		return svr.publishResource(res)
+	}
+
+	alice := zkidentity.MustNew("alice", "alice")
+	bob := zkidentity.MustNew("bob", "bob")
+	if err := publish(alice, "a", 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := publish(bob, "a", 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := publish(bob, "b", 10); err == nil {
+		t.Fatal("expected error publishing over the total size")
+	}
+
+	// Replacing a resource only accounts for the size difference.
+	if err := publish(alice, "a", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := publish(bob, "b", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := publish(alice, "b", 10); err == nil {
+		t.Fatal("expected error publishing over the total size")
+	}
+
+	// Removed resources free up space.
+	if err := publish(bob, "a", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := publish(alice, "b", 10); err != nil {
+		t.Fatal(err)
+	}
+	if svr.pubResSize != 20 {
+		t.Fatalf("unexpected total size: got %d, want 20", svr.pubResSize)
+	}
+}
@@ -21,10 +21,17 @@ func (z *ZKS) maybePushRM(r rpc.RouteMessage) {
 	z.stats.rmsRecv.add(1)
 
 	z.Lock() // XXX LOOOL
-	if sc, ok := z.subscribers[r.Rendezvous]; ok {
+	sc, ok := z.subscribers[r.Rendezvous]
+	if ok {
 		sc.msgC <- r.Rendezvous
 	}
 	z.Unlock()
+
+	// Nobody online is waiting for this RV. Wake up the client that
+	// last subscribed to it, if it registered a push gateway.
+	if !ok && len(z.settings.PushGateways) > 0 {
+		go z.maybeWakeup(r.Rendezvous)
+	}
 }
 
 func (z *ZKS) handleRouteMessage(ctx context.Context, writer chan *RPCWrapper,
@@ -107,6 +114,117 @@ func (z *ZKS) handleRouteMessage(ctx context.Context, writer chan *RPCWrapper,
 	return nil
 }
 
+func (z *ZKS) handleRouteMessageBatch(ctx context.Context, writer chan *RPCWrapper,
+	msg rpc.Message, r rpc.RouteMessageBatch, sc *sessionContext) error {
+
+	sc.log.Tracef("handleRouteMessageBatch tag %v (%d msgs)", msg.Tag,
+		len(r.Messages))
+
+	// always reply from here on out (provided non fatal error)
+	reply := RPCWrapper{
+		Message: rpc.Message{
+			Command: rpc.TaggedCmdRouteMessageBatchReply,
+			Tag:     msg.Tag,
+		},
+	}
+
+	// Validate the batch before verifying its payment, so that an invalid
+	// batch does not redeem the payment.
+	var emptyRV ratchet.RVPoint
+	var batchErr error
+	switch {
+	case z.settings.MaxBatchedRMs < 2:
+		batchErr = fmt.Errorf("batched RMs are not supported")
+	case len(r.Messages) == 0:
+		batchErr = fmt.Errorf("empty batch")
+	case len(r.Messages) > z.settings.MaxBatchedRMs:
+		batchErr = fmt.Errorf("too many RMs in batch (%d > %d)",
+			len(r.Messages), z.settings.MaxBatchedRMs)
+	case r.BatchPayloadSize() > rpc.MaxMsgSize:
+		batchErr = fmt.Errorf("batch payload too large (%d > %d)",
+			r.BatchPayloadSize(), rpc.MaxMsgSize)
+	}
+	for i := 0; batchErr == nil && i < len(r.Messages); i++ {
+		if r.Messages[i].Rendezvous == emptyRV {
+			batchErr = fmt.Errorf("empty rendezvous in batch item %d", i)
+		}
+	}
+	if batchErr != nil {
+		reply.Payload = rpc.RouteMessageBatchReply{
+			Error: batchErr.Error(),
+		}
+		writer <- &reply
+		sc.log.Warnf("handleRouteMessageBatch tag %v: %v", msg.Tag, batchErr)
+		return nil
+	}
+
+	err := z.isRMBatchPaid(ctx, &r, sc)
+	if err != nil {
+		// Reply with a generic invoice error.
+		reply.Payload = rpc.RouteMessageBatchReply{
+			Error: rpc.ErrRMInvoicePayment.Error(),
+		}
+		writer <- &reply
+		sc.log.Errorf("handleRouteMessageBatch isRMBatchPaid: %v", err)
+		return nil
+	}
+
+	payload := rpc.RouteMessageBatchReply{
+		Errors: make([]string, len(r.Messages)),
+	}
+
+	// Generate the next invoice that needs to be paid, if needed.
+	switch z.settings.PayScheme {
+	case rpc.PaySchemeFree:
+		// Send a dummy invoice to avoid having the client re-request it.
+		payload.NextInvoice = "free invoice"
+
+	case rpc.PaySchemeDCRLN:
+		var invoiceID string
+		invoiceAction := rpc.InvoiceActionPush
+		payload.NextInvoice, invoiceID, err = z.generateNextLNInvoice(ctx, sc, invoiceAction)
+		if err != nil {
+			sc.log.Errorf("handleRouteMessageBatch generate invoice %v", err)
+		} else {
+			sc.log.Debugf("Generated invoice for action %q pay scheme %q: %s",
+				invoiceAction, z.settings.PayScheme, invoiceID)
+		}
+
+	default:
+		// Shouldn't happen unless it's in-development.
+		return fmt.Errorf("unimplemented payment scheme %s", z.settings.PayScheme)
+	}
+
+	// Store each individual message on disk.
+	now := time.Now()
+	for i := range r.Messages {
+		bm := &r.Messages[i]
+		err = z.db.StorePayload(z.dbCtx, bm.Rendezvous, bm.Message, now)
+		if errors.Is(err, serverdb.ErrAlreadyStoredRV) {
+			sc.log.Warnf("Attempt to store already stored RV %s", bm.Rendezvous)
+		} else if err != nil {
+			payload.Errors[i] = err.Error()
+			z.log.Warnf("handleRouteMessageBatch tag %v item %d: %v",
+				msg.Tag, i, err)
+		} else {
+			sc.log.Debugf("Stored %d bytes at RV %s (batched)",
+				len(bm.Message), bm.Rendezvous)
+
+			// Deliver notification if there's an online session
+			// expecting it.
+			go z.maybePushRM(rpc.RouteMessage{
+				Rendezvous: bm.Rendezvous,
+				Message:    bm.Message,
+			})
+		}
+	}
+
+	// Send reply.
+	reply.Payload = payload
+	writer <- &reply
+	return nil
+}
+
 func (z *ZKS) handleSubscribeRoutedMessages(ctx context.Context, msg rpc.Message,
 	r rpc.SubscribeRoutedMessages, sc *sessionContext) error {
 
@@ -177,3 +295,32 @@ func (z *ZKS) handleSubscribeRoutedMessages(ctx context.Context, msg rpc.Message
 
 	return nil
 }
+
+// handleFetchDeferredRoutedMessages handles a client request to push RMs that
+// were previously deferred due to the session's subscription filter.
+func (z *ZKS) handleFetchDeferredRoutedMessages(ctx context.Context, msg rpc.Message,
+	r rpc.FetchDeferredRoutedMessages, sc *sessionContext) error {
+
+	var payload rpc.FetchDeferredRoutedMessagesReply
+	if len(r.RVs) > 0 {
+		// Ask sessionSubscribe() to push the full payloads.
+		select {
+		case sc.msgFetchC <- r.RVs:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	} else {
+		payload.Error = "no RVs specified"
+	}
+
+	// Reply.
+	sc.writer <- &RPCWrapper{
+		Message: rpc.Message{
+			Command: rpc.TaggedCmdFetchDeferredRoutedMessagesReply,
+			Tag:     msg.Tag,
+		},
+		Payload: payload,
+	}
+
+	return nil
+}
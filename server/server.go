@@ -16,6 +16,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -79,6 +80,17 @@ type ZKS struct {
 	lnRpc      lnrpc.LightningClient
 	lnInvoices invoicesrpc.InvoicesClient
 	lnNode     string
+
+	// Push gateway wakeups.
+	wakeupMtx    sync.Mutex
+	wakeups      map[ratchet.RVPoint]*wakeupTarget
+	wakeupClient *http.Client
+
+	// Published resources, indexed by owner. pubResSize is the total size
+	// of the published resources.
+	pubResMtx    sync.Mutex
+	pubResources map[zkidentity.ShortID]*publishedResources
+	pubResSize   int
 }
 
 // BoundAddrs returns the addresses the server is bound to listen to.
@@ -144,6 +156,12 @@ func (z *ZKS) welcome(kx *session.KX) error {
 			properties[k].Value = strconv.FormatInt(int64(z.settings.PushPaymentLifetime), 10)
 		case rpc.PropMaxPushInvoices:
 			properties[k].Value = strconv.FormatInt(int64(z.settings.MaxPushInvoices), 10)
+		case rpc.PropPushGateways:
+			properties[k].Value = strings.Join(z.settings.PushGateways, ",")
+		case rpc.PropMaxBatchedRMs:
+			properties[k].Value = strconv.FormatInt(int64(z.settings.MaxBatchedRMs), 10)
+		case rpc.PropMaxPublishedResourceSize:
+			properties[k].Value = strconv.FormatInt(int64(z.settings.MaxPublishedResourceSize), 10)
 		}
 	}
 
@@ -394,6 +412,16 @@ func (z *ZKS) Run(ctx context.Context) error {
 	// Run the expiration loop.
 	g.Go(func() error { return z.expirationLoop(ctx) })
 
+	// Run the loop that expires push gateway wakeups.
+	if len(z.settings.PushGateways) > 0 {
+		g.Go(func() error { return z.wakeupExpirationLoop(gctx) })
+	}
+
+	// Run the loop that expires published resources.
+	if z.settings.MaxPublishedResourceSize > 0 {
+		g.Go(func() error { return z.pubResourcesExpirationLoop(gctx) })
+	}
+
 	// Listen for connections.
 	for i := range listeners {
 		l := listeners[i]
@@ -449,6 +477,11 @@ func NewServer(cfg *settings.Settings) (*ZKS, error) {
 		pingLimit:   rpc.PingLimit,
 		dbCtx:       dbCtx,
 		dbCtxCancel: dbCtxCancel,
+
+		wakeups:      make(map[ratchet.RVPoint]*wakeupTarget),
+		wakeupClient: &http.Client{Timeout: wakeupTimeout},
+
+		pubResources: make(map[zkidentity.ShortID]*publishedResources),
 	}
 
 	// Init db.
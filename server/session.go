@@ -29,15 +29,20 @@ type sessionContext struct {
 	log      slog.Logger
 
 	// subscriptions
-	msgC    chan ratchet.RVPoint
-	msgSetC chan rpc.SubscribeRoutedMessages
-	msgAckC chan ratchet.RVPoint
+	msgC      chan ratchet.RVPoint
+	msgSetC   chan rpc.SubscribeRoutedMessages
+	msgAckC   chan ratchet.RVPoint
+	msgFetchC chan []ratchet.RVPoint
 
 	// protected
 	sync.Mutex
 	tagMessage      []*RPCWrapper
 	lnPayReqHashSub []byte
 	lnPushHashes    map[[32]byte]time.Time
+
+	// wakeup is the push gateway registration to use once the session
+	// goes offline.
+	wakeup *rpc.RegisterWakeup
 }
 
 func (z *ZKS) sessionWriter(ctx context.Context, sc *sessionContext) error {
@@ -94,6 +99,10 @@ func (z *ZKS) sessionSubscribe(ctx context.Context, sc *sessionContext) error {
 	// operations and lock contention for memory consumption.
 	sessSubs := make(map[ratchet.RVPoint]struct{})
 
+	// filter is the subscription filter applied to pushed RMs, as
+	// requested by the client.
+	var filter rpc.SubscriptionFilter
+
 	defer func() {
 		// Remove all of this session's subscriptions.
 		z.Lock()
@@ -101,6 +110,7 @@ func (z *ZKS) sessionSubscribe(ctx context.Context, sc *sessionContext) error {
 			delete(z.subscribers, rv)
 		}
 		z.Unlock()
+		z.registerOfflineWakeups(sc, sessSubs)
 		sc.log.Tracef("subscribers quit: %v", sessSubs)
 	}()
 
@@ -108,12 +118,22 @@ loop:
 	for {
 		var rvsToCheck []ratchet.RVPoint
 
+		// skipFilter is set when the client explicitly requested the
+		// RVs to be pushed.
+		var skipFilter bool
+
 		select {
 		case <-ctx.Done():
 			break loop
 
 		case s := <-sc.msgSetC:
 			rvsToCheck = s.AddRendezvous
+			if s.Filter != nil {
+				filter = *s.Filter
+				sc.log.Debugf("subscribers filter set to max "+
+					"push size %d, min timestamp %d",
+					filter.MaxPushSize, filter.MinTimestamp)
+			}
 
 			z.Lock()
 			// Remove subscriptions that were deleted.
@@ -144,6 +164,12 @@ loop:
 			}
 			z.Unlock()
 
+			// The client is online for these RVs, so stop any
+			// pending wakeups for them.
+			if len(z.settings.PushGateways) > 0 {
+				z.removeWakeups(rvsToCheck)
+			}
+
 			sc.log.Tracef("subscribers added %v deleted %v",
 				s.AddRendezvous, s.DelRendezvous)
 
@@ -153,6 +179,17 @@ loop:
 			sc.log.Tracef("subscribers read: %v", rv)
 			rvsToCheck = []ratchet.RVPoint{rv}
 
+		case rvs := <-sc.msgFetchC:
+			sc.log.Tracef("subscribers fetch deferred: %v", rvs)
+
+			// Only push RVs this session is subscribed to.
+			for _, rv := range rvs {
+				if _, ok := sessSubs[rv]; ok {
+					rvsToCheck = append(rvsToCheck, rv)
+				}
+			}
+			skipFilter = true
+
 		case rv := <-sc.msgAckC:
 			sc.log.Tracef("subscribers ackd: %v", rv)
 
@@ -186,16 +223,30 @@ loop:
 				sc.log.Errorf("could not obtain tag: %v", err)
 				continue
 			}
+			prm := rpc.PushRoutedMessage{
+				Payload:   msgPayload.Payload,
+				RV:        rv,
+				Timestamp: msgPayload.InsertTime.Unix(),
+			}
+
+			// Defer pushing payloads larger or older than allowed
+			// by the session's filter until the client requests
+			// them.
+			size := len(msgPayload.Payload)
+			tooLarge := filter.MaxPushSize > 0 && size > filter.MaxPushSize
+			tooOld := filter.MinTimestamp > 0 && prm.Timestamp < filter.MinTimestamp
+			if !skipFilter && (tooLarge || tooOld) {
+				prm.Payload = nil
+				prm.Deferred = true
+				prm.Size = size
+			}
+
 			reply := RPCWrapper{
 				Message: rpc.Message{
 					Command: rpc.TaggedCmdPushRoutedMessage,
 					Tag:     tag,
 				},
-				Payload: rpc.PushRoutedMessage{
-					Payload:   msgPayload.Payload,
-					RV:        rv,
-					Timestamp: msgPayload.InsertTime.Unix(),
-				},
+				Payload: prm,
 			}
 
 			sc.Lock()
@@ -208,9 +259,14 @@ loop:
 			sc.Unlock()
 
 			// And send
-			sc.log.Debugf("Pushing %d bytes to client at RV %s",
-				len(msgPayload.Payload), rv)
-			z.stats.rmsSent.add(1)
+			if prm.Deferred {
+				sc.log.Debugf("Pushing deferred RM of %d bytes to "+
+					"client at RV %s", size, rv)
+			} else {
+				sc.log.Debugf("Pushing %d bytes to client at RV %s",
+					size, rv)
+				z.stats.rmsSent.add(1)
+			}
 
 			sc.writer <- &reply
 		}
@@ -360,8 +416,9 @@ func (z *ZKS) sessionReader(ctx context.Context, sc *sessionContext) error {
 			}
 
 			// If the message being ack'd is a PushRM, the client
-			// has processed the message. Delete from disk.
-			if prm, ok := m.Payload.(rpc.PushRoutedMessage); ok {
+			// has processed the message. Delete from disk. Deferred
+			// RMs are kept until the client fetches them.
+			if prm, ok := m.Payload.(rpc.PushRoutedMessage); ok && !prm.Deferred {
 				go func() {
 					select {
 					case sc.msgAckC <- prm.RV:
@@ -386,6 +443,19 @@ func (z *ZKS) sessionReader(ctx context.Context, sc *sessionContext) error {
 				return fmt.Errorf("handleRouteMessage: %v", err)
 			}
 
+		case rpc.TaggedCmdRouteMessageBatch:
+			sc.log.Tracef("TaggedCmdRouteMessageBatch")
+
+			var r rpc.RouteMessageBatch
+			err = z.unmarshal(dec, &r)
+			if err != nil {
+				return fmt.Errorf("unmarshal RouteMessageBatch failed")
+			}
+			err = z.handleRouteMessageBatch(ctx, sc.writer, message, r, sc)
+			if err != nil {
+				return fmt.Errorf("handleRouteMessageBatch: %v", err)
+			}
+
 		case rpc.TaggedCmdSubscribeRoutedMessages:
 			sc.log.Tracef("TaggedCmdSubscribeRoutedMessages")
 
@@ -401,6 +471,66 @@ func (z *ZKS) sessionReader(ctx context.Context, sc *sessionContext) error {
 				return fmt.Errorf("handleSubscribeRoutedMessages: %v", err)
 			}
 
+		case rpc.TaggedCmdRegisterWakeup:
+			sc.log.Tracef("TaggedCmdRegisterWakeup")
+
+			var r rpc.RegisterWakeup
+			err = z.unmarshal(dec, &r)
+			if err != nil {
+				return fmt.Errorf("unmarshal RegisterWakeup failed: %v",
+					err)
+			}
+
+			err = z.handleRegisterWakeup(ctx, message, r, sc)
+			if err != nil {
+				return fmt.Errorf("handleRegisterWakeup: %v", err)
+			}
+
+		case rpc.TaggedCmdPublishResource:
+			sc.log.Tracef("TaggedCmdPublishResource")
+
+			var r rpc.PublishResource
+			err = z.unmarshal(dec, &r)
+			if err != nil {
+				return fmt.Errorf("unmarshal PublishResource failed: %v",
+					err)
+			}
+
+			err = z.handlePublishResource(ctx, message, r, sc)
+			if err != nil {
+				return fmt.Errorf("handlePublishResource: %v", err)
+			}
+
+		case rpc.TaggedCmdFetchPublishedResource:
+			sc.log.Tracef("TaggedCmdFetchPublishedResource")
+
+			var r rpc.FetchPublishedResource
+			err = z.unmarshal(dec, &r)
+			if err != nil {
+				return fmt.Errorf("unmarshal FetchPublishedResource "+
+					"failed: %v", err)
+			}
+
+			err = z.handleFetchPublishedResource(ctx, message, r, sc)
+			if err != nil {
+				return fmt.Errorf("handleFetchPublishedResource: %v", err)
+			}
+
+		case rpc.TaggedCmdFetchDeferredRoutedMessages:
+			sc.log.Tracef("TaggedCmdFetchDeferredRoutedMessages")
+
+			var r rpc.FetchDeferredRoutedMessages
+			err = z.unmarshal(dec, &r)
+			if err != nil {
+				return fmt.Errorf("unmarshal "+
+					"FetchDeferredRoutedMessages failed: %v", err)
+			}
+
+			err = z.handleFetchDeferredRoutedMessages(ctx, message, r, sc)
+			if err != nil {
+				return fmt.Errorf("handleFetchDeferredRoutedMessages: %v", err)
+			}
+
 		case rpc.TaggedCmdGetInvoice:
 			sc.log.Tracef("TaggedCmdGetInvoice")
 
@@ -442,6 +572,8 @@ func (z *ZKS) runNewSession(ctx context.Context, conn net.Conn, kx *session.KX)
 		msgC:    make(chan ratchet.RVPoint, 2), // To allow write in go func itself
 		msgAckC: make(chan ratchet.RVPoint),
 
+		msgFetchC: make(chan []ratchet.RVPoint),
+
 		lnPushHashes: make(map[[32]byte]time.Time),
 	}
 
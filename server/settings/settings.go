@@ -47,6 +47,32 @@ type Settings struct {
 	MilliAtomsPerSub    uint64
 	PushPaymentLifetime int // how long a payment to a push is valid
 	MaxPushInvoices     int
+	MaxBatchedRMs       int // max number of RMs pushed in a single batch
+
+	// PushGateways are the URLs of push gateways clients may register to
+	// be notified of new RMs while offline.
+	PushGateways []string
+
+	// WakeupLifetime is how long after a session goes offline its
+	// registered push gateway is notified of new RMs.
+	WakeupLifetime time.Duration
+
+	// MaxPublishedResourceSize is the max size of resources users may
+	// publish to be served while they are offline. Zero disables hosting
+	// published resources.
+	MaxPublishedResourceSize int
+
+	// MaxPublishedResources is the max number of resources each user may
+	// publish.
+	MaxPublishedResources int
+
+	// MaxTotalPublishedResourcesSize is the max total size of the
+	// resources published by all users.
+	MaxTotalPublishedResourcesSize int
+
+	// PublishedResourceLifetime is how long published resources are
+	// hosted after being published.
+	PublishedResourceLifetime time.Duration
 
 	// log section
 	LogFile    string // log filename
@@ -95,6 +121,12 @@ func New() *Settings {
 		MilliAtomsPerSub:    rpc.PropSubPaymentRateDefault,
 		PushPaymentLifetime: rpc.PropPushPaymentLifetimeDefault,
 		MaxPushInvoices:     rpc.PropMaxPushInvoicesDefault,
+		MaxBatchedRMs:       rpc.PropMaxBatchedRMsDefault,
+		WakeupLifetime:      time.Hour * 24 * time.Duration(rpc.PropExpirationDaysDefault),
+
+		MaxPublishedResources:          16,
+		MaxTotalPublishedResourcesSize: 256 * 1024 * 1024,
+		PublishedResourceLifetime:      time.Hour * 24 * time.Duration(rpc.PropExpirationDaysDefault),
 
 		// log
 		LogFile:    "~/.brserver/brserver.log",
@@ -260,6 +292,51 @@ func (s *Settings) Load(filename string) error {
 	}
 	s.MaxPushInvoices = maxPushInvoices
 
+	maxBatchedRMs := rpc.PropMaxBatchedRMsDefault
+	err = iniInt(cfg, &maxBatchedRMs, "policy", "maxbatchedrms")
+	if err != nil && !errors.Is(err, errIniNotFound) {
+		return err
+	}
+	s.MaxBatchedRMs = maxBatchedRMs
+
+	rawPushGateways, ok := cfg.Get("policy", "pushgateways")
+	if ok && rawPushGateways != "" {
+		pushGateways := strings.Split(rawPushGateways, ",")
+		for i := range pushGateways {
+			pushGateways[i] = strings.TrimSpace(pushGateways[i])
+		}
+		s.PushGateways = pushGateways
+	}
+
+	wakeupLifetimeHours := int(s.WakeupLifetime / time.Hour)
+	err = iniInt(cfg, &wakeupLifetimeHours, "policy", "wakeuplifetimehours")
+	if err != nil && !errors.Is(err, errIniNotFound) {
+		return err
+	}
+	s.WakeupLifetime = time.Duration(wakeupLifetimeHours) * time.Hour
+
+	err = iniInt(cfg, &s.MaxPublishedResourceSize, "policy", "maxpublishedresourcesize")
+	if err != nil && !errors.Is(err, errIniNotFound) {
+		return err
+	}
+
+	err = iniInt(cfg, &s.MaxPublishedResources, "policy", "maxpublishedresources")
+	if err != nil && !errors.Is(err, errIniNotFound) {
+		return err
+	}
+
+	err = iniInt(cfg, &s.MaxTotalPublishedResourcesSize, "policy", "maxtotalpublishedresourcessize")
+	if err != nil && !errors.Is(err, errIniNotFound) {
+		return err
+	}
+
+	pubResLifetimeHours := int(s.PublishedResourceLifetime / time.Hour)
+	err = iniInt(cfg, &pubResLifetimeHours, "policy", "publishedresourcelifetimehours")
+	if err != nil && !errors.Is(err, errIniNotFound) {
+		return err
+	}
+	s.PublishedResourceLifetime = time.Duration(pubResLifetimeHours) * time.Hour
+
 	return nil
 }
 
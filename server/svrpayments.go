@@ -217,12 +217,24 @@ func (z *ZKS) handleGetInvoice(ctx context.Context, sc *sessionContext,
 // isRMPaid returns whether the received routed message was paid for. Returns
 // nil if it is paid, or an error if not.
 func (z *ZKS) isRMPaid(ctx context.Context, rm *rpc.RouteMessage, sc *sessionContext) error {
+	return z.isPushPaid(ctx, rm.PaidInvoiceID, int64(len(rm.Message)), sc)
+}
+
+// isRMBatchPaid returns whether the received batch of routed messages was paid
+// for. A single payment covers the total size of all messages in the batch.
+func (z *ZKS) isRMBatchPaid(ctx context.Context, rmb *rpc.RouteMessageBatch, sc *sessionContext) error {
+	return z.isPushPaid(ctx, rmb.PaidInvoiceID, int64(rmb.BatchPayloadSize()), sc)
+}
+
+// isPushPaid returns whether the invoice identified by paidInvoiceID was paid
+// with enough funds to push msgLen bytes. Returns nil if it is paid, or an
+// error if not.
+func (z *ZKS) isPushPaid(ctx context.Context, paidInvoiceID []byte, msgLen int64, sc *sessionContext) error {
 	switch z.settings.PayScheme {
 	case rpc.PaySchemeFree:
 		return nil
 
 	case rpc.PaySchemeDCRLN:
-		msgLen := int64(len(rm.Message))
 		wantMAtoms := msgLen * int64(z.settings.MilliAtomsPerByte)
 
 		// Enforce the minimum payment policy.
@@ -240,7 +252,6 @@ func (z *ZKS) isRMPaid(ctx context.Context, rm *rpc.RouteMessage, sc *sessionCon
 		// there is a single outstanding invoice, use that one.
 		//
 		// TODO: remove in the future once all clients have updated.
-		paidInvoiceID := rm.PaidInvoiceID
 		if paidInvoiceID == nil {
 			sc.Lock()
 			if len(sc.lnPushHashes) == 1 {
@@ -328,7 +339,7 @@ func (z *ZKS) isRMPaid(ctx context.Context, rm *rpc.RouteMessage, sc *sessionCon
 
 		return err
 	default:
-		return fmt.Errorf("unimplemented isNextRMPaid for scheme %s",
+		return fmt.Errorf("unimplemented isPushPaid for scheme %s",
 			z.settings.PayScheme)
 	}
 }
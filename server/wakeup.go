@@ -0,0 +1,180 @@
+// Copyright (c) 2023 Company 0, LLC.
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/companyzero/bisonrelay/ratchet"
+	"github.com/companyzero/bisonrelay/rpc"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// wakeupTimeout is the max time to wait for a push gateway to reply to
+	// a wakeup notification.
+	wakeupTimeout = 10 * time.Second
+
+	// minWakeupInterval is the min interval between notifications sent to
+	// the same target. This avoids spamming the gateway when multiple RMs
+	// are received in a short time.
+	minWakeupInterval = time.Minute
+
+	// maxWakeupTokenLen is the max length of a wakeup token.
+	maxWakeupTokenLen = 1024
+)
+
+// wakeupTarget is a push gateway registration of a session that went offline.
+type wakeupTarget struct {
+	gateway string
+	token   string
+	expires time.Time
+
+	// lastNotified is protected by ZKS.wakeupMtx.
+	lastNotified time.Time
+}
+
+// handleRegisterWakeup handles a client request to register a push gateway to
+// be notified of new RMs after the session goes offline.
+func (z *ZKS) handleRegisterWakeup(ctx context.Context, msg rpc.Message,
+	r rpc.RegisterWakeup, sc *sessionContext) error {
+
+	var payload rpc.RegisterWakeupReply
+	switch {
+	case r.Token == "":
+		sc.Lock()
+		sc.wakeup = nil
+		sc.Unlock()
+		sc.log.Debugf("Removed wakeup registration")
+
+	case !slices.Contains(z.settings.PushGateways, r.Gateway):
+		payload.Error = fmt.Sprintf("unsupported push gateway %q", r.Gateway)
+
+	case len(r.Token) > maxWakeupTokenLen:
+		payload.Error = fmt.Sprintf("wakeup token too long (%d > %d)",
+			len(r.Token), maxWakeupTokenLen)
+
+	default:
+		sc.Lock()
+		sc.wakeup = &rpc.RegisterWakeup{Gateway: r.Gateway, Token: r.Token}
+		sc.Unlock()
+		sc.log.Debugf("Registered wakeup with gateway %s", r.Gateway)
+	}
+
+	sc.writer <- &RPCWrapper{
+		Message: rpc.Message{
+			Command: rpc.TaggedCmdRegisterWakeupReply,
+			Tag:     msg.Tag,
+		},
+		Payload: payload,
+	}
+	return nil
+}
+
+// registerOfflineWakeups is called when a session is closing, to register
+// that new RMs on the session's subscribed RVs should be notified to the
+// session's push gateway.
+func (z *ZKS) registerOfflineWakeups(sc *sessionContext, rvs map[ratchet.RVPoint]struct{}) {
+	sc.Lock()
+	reg := sc.wakeup
+	sc.Unlock()
+	if reg == nil || len(rvs) == 0 {
+		return
+	}
+
+	target := &wakeupTarget{
+		gateway: reg.Gateway,
+		token:   reg.Token,
+		expires: time.Now().Add(z.settings.WakeupLifetime),
+	}
+	z.wakeupMtx.Lock()
+	for rv := range rvs {
+		z.wakeups[rv] = target
+	}
+	z.wakeupMtx.Unlock()
+	sc.log.Debugf("Registered %d RVs for offline wakeup", len(rvs))
+}
+
+// removeWakeups removes the wakeup registration of the given RVs. This is
+// called when a new session subscribes to the RVs.
+func (z *ZKS) removeWakeups(rvs []ratchet.RVPoint) {
+	z.wakeupMtx.Lock()
+	for _, rv := range rvs {
+		delete(z.wakeups, rv)
+	}
+	z.wakeupMtx.Unlock()
+}
+
+// maybeWakeup notifies the push gateway registered for the given RV (if there
+// is one) that a new RM was stored. This is called when there is no online
+// session subscribed to the RV.
+func (z *ZKS) maybeWakeup(rv ratchet.RVPoint) {
+	now := time.Now()
+	z.wakeupMtx.Lock()
+	target, ok := z.wakeups[rv]
+	if ok && now.After(target.expires) {
+		delete(z.wakeups, rv)
+		ok = false
+	}
+	if ok && now.Sub(target.lastNotified) < minWakeupInterval {
+		ok = false
+	}
+	if ok {
+		target.lastNotified = now
+	}
+	z.wakeupMtx.Unlock()
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(rpc.WakeupNotification{Token: target.token})
+	if err != nil {
+		z.log.Errorf("Unable to encode wakeup notification: %v", err)
+		return
+	}
+
+	res, err := z.wakeupClient.Post(target.gateway, "application/json",
+		bytes.NewReader(body))
+	if err != nil {
+		z.log.Warnf("Unable to send wakeup to gateway %s: %v",
+			target.gateway, err)
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		z.log.Warnf("Push gateway %s replied to wakeup with status %d",
+			target.gateway, res.StatusCode)
+	}
+}
+
+// wakeupExpirationLoop periodically removes expired wakeup registrations.
+func (z *ZKS) wakeupExpirationLoop(ctx context.Context) error {
+	for {
+		select {
+		case <-time.After(time.Hour):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		now := time.Now()
+		var count int
+		z.wakeupMtx.Lock()
+		for rv, target := range z.wakeups {
+			if now.After(target.expires) {
+				delete(z.wakeups, rv)
+				count++
+			}
+		}
+		z.wakeupMtx.Unlock()
+		if count > 0 {
+			z.log.Debugf("Expired %d wakeup registrations", count)
+		}
+	}
+}